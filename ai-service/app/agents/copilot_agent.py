@@ -162,6 +162,9 @@ class CopilotAgent:
             """Async wrapper that executes the tool with context."""
             try:
                 result = await tool.run(context, **kwargs)
+                if result.confirmation_required:
+                    # Kept for the chat response, so the pending action stores the tool parameters
+                    context.pending_confirmation = result.confirmation_details
                 return self._format_tool_result(result)
             except Exception as e:
                 logger.error(f"Tool execution error: {tool.name} - {e}")
@@ -292,6 +295,7 @@ class CopilotAgent:
                     self.agent.memory.put(msg)
 
             # Get response from agent
+            self.tool_context.pending_confirmation = None
             response = await self.agent.achat(message)
 
             return {
@@ -336,12 +340,19 @@ class CopilotAgent:
             response: Agent response object.
 
         Returns:
-            Confirmation details if required, None otherwise.
+            Confirmation details if required, None otherwise. The details of
+            the tool that requested confirmation are returned under "details".
         """
+        pending = self.tool_context.pending_confirmation
+        if pending:
+            return {
+                "required": True,
+                "message": pending.get("message") or "Esta acao requer confirmacao.",
+                "details": pending,
+            }
+
         response_text = str(response)
         if "CONFIRMACAO_NECESSARIA" in response_text:
-            # Parse confirmation details from response
-            # This is a simplified implementation
             return {
                 "required": True,
                 "message": "Esta acao requer confirmacao.",
@@ -5,6 +5,7 @@ A simplified implementation that bypasses llama_index compatibility issues
 with Python 3.14 by using the OpenAI client directly.
 """
 
+import json
 import logging
 from typing import Any, List, Optional
 from uuid import UUID
@@ -13,6 +14,8 @@ from openai import AsyncOpenAI
 
 from app.config import get_settings
 from app.middleware.tenant import RequestContext
+from app.tools.base import BaseTool, ToolContext
+from app.tools.occurrence_tools import UpdateOccurrenceStatusTool
 
 
 logger = logging.getLogger(__name__)
@@ -59,6 +62,25 @@ DIRETRIZES IMPORTANTES:
 Como posso ajuda-lo hoje?"""
 
 
+# Function definition of the status update, proposed to the user for confirmation
+UPDATE_STATUS_FUNCTION = {
+    "type": "function",
+    "function": {
+        "name": UpdateOccurrenceStatusTool.name,
+        "description": "Propoe a alteracao do status de uma ocorrencia. A alteracao so e executada apos confirmacao do usuario.",
+        "parameters": {
+            "type": "object",
+            "properties": {
+                "occurrence_id": {"type": "string", "description": "ID da ocorrencia"},
+                "new_status": {"type": "string", "enum": UpdateOccurrenceStatusTool.VALID_STATUSES},
+                "observacao": {"type": "string", "description": "Observacao sobre a mudanca"},
+            },
+            "required": ["occurrence_id", "new_status"],
+        },
+    },
+}
+
+
 class SimpleAgent:
     """
     Simple AI Agent using OpenAI directly.
@@ -83,6 +105,7 @@ class SimpleAgent:
         self._request_ctx = request_ctx
         self._conversation_id = conversation_id
         self._client: Optional[AsyncOpenAI] = None
+        self._status_tool: BaseTool = UpdateOccurrenceStatusTool()
 
     @property
     def client(self) -> AsyncOpenAI:
@@ -153,16 +176,27 @@ class SimpleAgent:
             # Add current user message
             messages.append({"role": "user", "content": message})
 
+            # Only offer the status update to roles allowed to run it
+            tools = None
+            if self._status_tool.has_permission(self._request_ctx.role):
+                tools = [UPDATE_STATUS_FUNCTION]
+
             # Call OpenAI API
             response = await self.client.chat.completions.create(
                 model=self._settings.ai_model or "gpt-4o-mini",
                 messages=messages,
                 temperature=0.1,
                 max_tokens=2000,
+                **({"tools": tools} if tools else {}),
             )
 
+            response_message = response.choices[0].message
+            for tool_call in response_message.tool_calls or []:
+                if tool_call.function.name == self._status_tool.name:
+                    return await self._propose_status_update(tool_call.function.arguments)
+
             # Extract response text
-            response_text = response.choices[0].message.content or ""
+            response_text = response_message.content or ""
 
             return {
                 "response": response_text,
@@ -178,6 +212,49 @@ class SimpleAgent:
                 "tool_calls": [],
             }
 
+    async def _propose_status_update(self, arguments: str) -> dict:
+        """
+        Turn a status update call of the model into a confirmation request.
+
+        The tool is run without confirmation, so it only returns the
+        confirmation details with its tool name and parameters.
+
+        Args:
+            arguments: JSON arguments of the function call.
+
+        Returns:
+            Dictionary with response and the confirmation required.
+        """
+        try:
+            params = json.loads(arguments or "{}")
+        except json.JSONDecodeError:
+            params = {}
+
+        context = ToolContext(
+            request_ctx=self._request_ctx,
+            conversation_id=self._conversation_id,
+        )
+        result = await self._status_tool.run(context, **params)
+        tool_call = {"tool_name": self._status_tool.name, "arguments": params}
+
+        if not result.confirmation_required:
+            return {
+                "response": result.message or "",
+                "tool_calls": [tool_call],
+                "confirmation_required": None,
+            }
+
+        details = result.confirmation_details or {}
+        return {
+            "response": details.get("message") or result.message or "",
+            "tool_calls": [tool_call],
+            "confirmation_required": {
+                "required": True,
+                "message": details.get("message") or "Esta acao requer confirmacao.",
+                "details": details,
+            },
+        }
+
 
 def create_simple_agent(
     request_ctx: RequestContext,
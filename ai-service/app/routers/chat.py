@@ -5,6 +5,7 @@ Provides endpoints for interacting with the AI assistant:
 - POST /api/v1/ai/chat - Send message to agent
 - POST /api/v1/ai/chat/stream - SSE streaming response
 - POST /api/v1/ai/confirm/{action_id} - Confirm pending action
+- GET /api/v1/ai/actions/{action_id} - Get a pending action for re-validation
 - GET /api/v1/ai/conversations - List user's conversations
 - GET /api/v1/ai/conversations/{session_id} - Get conversation messages
 - DELETE /api/v1/ai/conversations/{session_id} - Clear conversation
@@ -90,6 +91,16 @@ class ConfirmResponse(BaseModel):
     message: str
 
 
+class PendingActionResponse(BaseModel):
+    """Response body for a proposed action, used by the backend to re-validate it before confirmation."""
+
+    action_id: str
+    tool_name: Optional[str] = None
+    input_params: dict = Field(default_factory=dict)
+    status: str
+    created_at: str
+
+
 class ConversationSummary(BaseModel):
     """Summary of a conversation session."""
 
@@ -204,26 +215,29 @@ async def send_chat_message(
 
         # Check for confirmation requirements
         confirmation_required = None
-        if agent_confirmation or _requires_confirmation(response_text, request.message):
-            action_id = str(uuid4())
-            confirmation_required = ConfirmationRequired(
-                action_id=action_id,
-                action_type="update_occurrence_status",
-                description=agent_confirmation.get("message") if agent_confirmation else "Esta acao requer sua confirmacao",
-                details={"original_message": request.message},
-            )
+        if agent_confirmation:
+            # Store the tool parameters so the backend can re-validate the action on confirmation
+            action_details = agent_confirmation.get("details") or {}
+            tool_name, action_params = _pending_action_params(action_details)
 
-            # Create pending action audit log
-            await audit_repo.create_tool_execution_log(
+            # Create pending action audit log; its ID is the action ID the user confirms
+            pending_action = await audit_repo.create_tool_execution_log(
                 tenant_id=tenant_id,
                 user_id=user_id,
                 conversation_id=user_message.id,
-                tool_name="update_occurrence_status",
-                input_params={"message": request.message},
+                tool_name=tool_name,
+                input_params={"message": request.message, **action_params},
                 status=ActionStatus.PENDING,
                 severity=Severity.WARN,
             )
 
+            confirmation_required = ConfirmationRequired(
+                action_id=str(pending_action.id),
+                action_type=tool_name,
+                description=agent_confirmation.get("message") or "Esta acao requer sua confirmacao",
+                details={"original_message": request.message, **action_details},
+            )
+
         # Store assistant response
         assistant_message = await conv_repo.create_assistant_message(
             tenant_id=tenant_id,
@@ -498,6 +512,63 @@ async def confirm_action(
     )
 
 
+@router.get("/actions/{action_id}", response_model=PendingActionResponse)
+async def get_pending_action(
+    action_id: str,
+    request_ctx: RequestContext = Depends(get_request_context),
+    db: AsyncSession = Depends(get_db_session),
+) -> PendingActionResponse:
+    """
+    Get an action proposed to the user.
+
+    The backend reads it before forwarding a confirmation so the action is
+    re-validated against current permissions and state.
+
+    Args:
+        action_id: UUID of the proposed action.
+        request_ctx: Request context with user and tenant info.
+        db: Database session.
+
+    Returns:
+        The stored action.
+    """
+    try:
+        action_uuid = UUID(action_id)
+    except ValueError:
+        raise HTTPException(
+            status_code=status.HTTP_400_BAD_REQUEST,
+            detail={"error": "invalid_action_id", "message": "ID da acao invalido"},
+        )
+
+    tenant_id = UUID(request_ctx.effective_tenant_id)
+
+    audit_repo = AuditLogRepository(db)
+    action = await audit_repo.get_by_id(action_uuid, tenant_id)
+
+    if not action:
+        raise HTTPException(
+            status_code=status.HTTP_404_NOT_FOUND,
+            detail={"error": "action_not_found", "message": "Acao nao encontrada"},
+        )
+
+    if str(action.user_id) != str(UUID(request_ctx.user_id)):
+        raise HTTPException(
+            status_code=status.HTTP_403_FORBIDDEN,
+            detail={
+                "error": "unauthorized",
+                "message": "Voce nao tem permissao para acessar esta acao",
+            },
+        )
+
+    return PendingActionResponse(
+        action_id=str(action.id),
+        tool_name=action.tool_name,
+        input_params=action.input_params or {},
+        status=action.status,
+        created_at=action.created_at.isoformat() if action.created_at else "",
+    )
+
+
 @router.get("/conversations", response_model=ConversationsListResponse)
 async def list_conversations(
     limit: int = 20,
@@ -690,28 +761,21 @@ async def _process_with_agent(
         }
 
 
-def _requires_confirmation(response_text: str, original_message: str) -> bool:
+def _pending_action_params(action_details: dict) -> tuple[str, dict]:
     """
-    Check if the action requires human confirmation.
+    Get the tool name and parameters of a proposed action.
+
+    The tool parameters are stored at the top level of the action input
+    params, where the backend reads them to re-validate the action.
 
     Args:
-        response_text: AI response text.
-        original_message: Original user message.
+        action_details: Confirmation details of the tool, with tool_name and parameters.
 
     Returns:
-        True if confirmation is required.
+        Tuple of tool name and input params of the pending action.
     """
-    # Keywords that trigger confirmation requirement
-    confirmation_keywords = [
-        "atualizar status",
-        "alterar status",
-        "mudar status",
-        "update status",
-        "confirmar",
-    ]
-
-    message_lower = original_message.lower()
-    return any(keyword in message_lower for keyword in confirmation_keywords)
+    tool_name = action_details.get("tool_name") or "update_occurrence_status"
+    return tool_name, dict(action_details.get("parameters") or {})
 
 
 async def _execute_confirmed_action(
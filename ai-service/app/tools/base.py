@@ -136,11 +136,14 @@ class ToolContext:
         request_ctx: The request context with user and tenant info.
         conversation_id: Optional conversation message ID for audit.
         confirmation_received: Whether user has confirmed the action.
+        pending_confirmation: Confirmation details of the last tool call that
+            requested confirmation, including the tool name and parameters.
     """
 
     request_ctx: RequestContext
     conversation_id: Optional[UUID] = None
     confirmation_received: bool = False
+    pending_confirmation: Optional[dict] = None
 
     @property
     def user_id(self) -> str:
@@ -328,3 +328,42 @@ class TestHumanInTheLoopFlow:
             assert result.confirmation_required is False
             assert result.data["total"] == 2
             assert mock_api.called
+
+    @pytest.mark.asyncio
+    async def test_simple_agent_status_update_returns_tool_parameters(self):
+        """
+        Test that a status update proposed by the chat agent carries the tool parameters.
+
+        The pending action stores these parameters, and the backend reads
+        them to re-validate the action when the user confirms it.
+        """
+        import json
+        from types import SimpleNamespace
+
+        from app.agents.simple_agent import SimpleAgent
+
+        occurrence_id = str(uuid4())
+        tool_call = SimpleNamespace(
+            function=SimpleNamespace(
+                name="update_occurrence_status",
+                arguments=json.dumps({"occurrence_id": occurrence_id, "new_status": "em_andamento"}),
+            ),
+        )
+        completion = SimpleNamespace(
+            choices=[SimpleNamespace(message=SimpleNamespace(content=None, tool_calls=[tool_call]))],
+        )
+
+        agent = SimpleAgent(request_ctx=self._create_request_context(role="operador"))
+        agent._client = MagicMock()
+        agent._client.chat.completions.create = AsyncMock(return_value=completion)
+
+        result = await agent.chat("Iniciar a ocorrencia")
+
+        confirmation = result["confirmation_required"]
+        assert confirmation["required"] is True
+        assert confirmation["details"]["tool_name"] == "update_occurrence_status"
+        assert confirmation["details"]["parameters"] == {
+            "occurrence_id": occurrence_id,
+            "new_status": "em_andamento",
+        }
+        assert result["tool_calls"][0]["tool_name"] == "update_occurrence_status"
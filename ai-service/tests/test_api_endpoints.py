@@ -119,6 +119,19 @@ class TestConfirmationEndpoint:
         self.tenant_id = str(uuid4())
         self.action_id = str(uuid4())
 
+    @pytest.fixture(autouse=True)
+    def db_session_override(self):
+        """Replace the database session, since the repositories are mocked."""
+        from app.database import get_db_session
+        from app.main import app
+
+        async def override_db_session():
+            yield AsyncMock()
+
+        app.dependency_overrides[get_db_session] = override_db_session
+        yield
+        app.dependency_overrides.pop(get_db_session, None)
+
     def _create_token(
         self,
         user_id: str = None,
@@ -174,6 +187,7 @@ class TestConfirmationEndpoint:
         # Create a mock pending action
         mock_pending_action = MagicMock()
         mock_pending_action.id = UUID(self.action_id)
+        mock_pending_action.user_id = UUID(self.user_id)
         mock_pending_action.tool_name = "update_occurrence_status"
         mock_pending_action.input_params = {"occurrence_id": str(uuid4())}
         mock_pending_action.status = "pending"
@@ -190,6 +204,127 @@ class TestConfirmationEndpoint:
         # Should process the request (may return different status based on action state)
         assert response.status_code in [200, 404, 500]
 
+    @patch("app.routers.chat.AuditLogRepository")
+    def test_get_pending_action_rejects_other_users(self, mock_audit_repo):
+        """
+        Test that a user cannot read an action proposed to another user.
+
+        The backend reads the action before confirmation, so it must only be
+        visible to the user who received the proposal.
+        """
+        from app.main import app
+
+        client = TestClient(app)
+        token = self._create_token()
+
+        mock_audit_repo_instance = AsyncMock()
+        mock_audit_repo.return_value = mock_audit_repo_instance
+
+        mock_pending_action = MagicMock()
+        mock_pending_action.id = UUID(self.action_id)
+        mock_pending_action.user_id = uuid4()
+        mock_pending_action.tool_name = "update_occurrence_status"
+        mock_pending_action.input_params = {"occurrence_id": str(uuid4())}
+        mock_pending_action.status = "pending"
+
+        mock_audit_repo_instance.get_by_id.return_value = mock_pending_action
+
+        response = client.get(
+            f"/api/v1/ai/actions/{self.action_id}",
+            headers={"Authorization": f"Bearer {token}"},
+        )
+
+        assert response.status_code == 403
+
+    @patch("app.agents.simple_agent.AsyncOpenAI")
+    @patch("app.routers.chat.ConversationRepository")
+    @patch("app.routers.chat.AuditLogRepository")
+    def test_confirmed_status_update_keeps_tool_parameters(
+        self,
+        mock_audit_repo,
+        mock_conv_repo,
+        mock_openai,
+    ):
+        """
+        Test that a status update proposed in chat can be confirmed with its parameters.
+
+        The pending action must store the occurrence_id and new_status of the
+        tool call at the top level of its input params, since the backend
+        rejects a confirmation whose action has no valid parameters.
+        """
+        import json
+        from types import SimpleNamespace
+
+        from app.main import app
+
+        client = TestClient(app)
+        token = self._create_token()
+        occurrence_id = str(uuid4())
+
+        tool_call = SimpleNamespace(
+            function=SimpleNamespace(
+                name="update_occurrence_status",
+                arguments=json.dumps({"occurrence_id": occurrence_id, "new_status": "em_andamento"}),
+            ),
+        )
+        completion = SimpleNamespace(
+            choices=[SimpleNamespace(message=SimpleNamespace(content=None, tool_calls=[tool_call]))],
+        )
+        mock_openai.return_value.chat.completions.create = AsyncMock(return_value=completion)
+
+        mock_conv_repo_instance = AsyncMock()
+        mock_conv_repo.return_value = mock_conv_repo_instance
+        mock_conv_repo_instance.create_user_message.return_value = MagicMock(id=uuid4())
+        mock_conv_repo_instance.create_assistant_message.return_value = MagicMock(id=uuid4())
+        mock_conv_repo_instance.get_recent_context.return_value = []
+
+        mock_audit_repo_instance = AsyncMock()
+        mock_audit_repo.return_value = mock_audit_repo_instance
+        mock_audit_repo_instance.create_query_log.return_value = MagicMock(id=uuid4())
+        mock_audit_repo_instance.create_tool_execution_log.return_value = MagicMock(id=UUID(self.action_id))
+
+        response = client.post(
+            "/api/v1/ai/chat",
+            json={"message": "Iniciar a ocorrencia"},
+            headers={"Authorization": f"Bearer {token}"},
+        )
+
+        assert response.status_code == 200
+        assert response.json()["confirmation_required"]["action_id"] == self.action_id
+
+        stored = mock_audit_repo_instance.create_tool_execution_log.call_args.kwargs
+        assert stored["tool_name"] == "update_occurrence_status"
+        assert stored["input_params"]["occurrence_id"] == occurrence_id
+        assert stored["input_params"]["new_status"] == "em_andamento"
+
+        # The backend reads the action back and confirms it
+        mock_pending_action = MagicMock()
+        mock_pending_action.id = UUID(self.action_id)
+        mock_pending_action.user_id = UUID(self.user_id)
+        mock_pending_action.tool_name = stored["tool_name"]
+        mock_pending_action.input_params = stored["input_params"]
+        mock_pending_action.status = "pending"
+        mock_pending_action.created_at = datetime.now(timezone.utc)
+        mock_audit_repo_instance.get_by_id.return_value = mock_pending_action
+
+        response = client.get(
+            f"/api/v1/ai/actions/{self.action_id}",
+            headers={"Authorization": f"Bearer {token}"},
+        )
+
+        assert response.status_code == 200
+        assert response.json()["input_params"]["occurrence_id"] == occurrence_id
+        assert response.json()["input_params"]["new_status"] == "em_andamento"
+
+        response = client.post(
+            f"/api/v1/ai/confirm/{self.action_id}",
+            json={"confirmed": True},
+            headers={"Authorization": f"Bearer {token}"},
+        )
+
+        assert response.status_code == 200
+        assert response.json()["confirmed"] is True
+
 
 class TestConversationHistoryEndpoint:
     """Test conversation history retrieval endpoints."""
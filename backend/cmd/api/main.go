@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -24,10 +25,17 @@ import (
 	"github.com/sidot/backend/internal/services"
 	"github.com/sidot/backend/internal/services/audit"
 	"github.com/sidot/backend/internal/services/auth"
+	"github.com/sidot/backend/internal/services/escalation"
+	"github.com/sidot/backend/internal/services/expiry"
+	"github.com/sidot/backend/internal/services/geocoding"
 	"github.com/sidot/backend/internal/services/health"
 	"github.com/sidot/backend/internal/services/listener"
+	"github.com/sidot/backend/internal/services/metricscache"
 	"github.com/sidot/backend/internal/services/notification"
+	"github.com/sidot/backend/internal/services/outbox"
 	"github.com/sidot/backend/internal/services/report"
+	"github.com/sidot/backend/internal/services/shift"
+	"github.com/sidot/backend/internal/services/storage"
 	"github.com/sidot/backend/internal/services/triagem"
 )
 
@@ -38,6 +46,11 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Shift times of hospitals without a timezone are in the system timezone
+	if err := models.SetDefaultTimezone(cfg.SystemTimezone); err != nil {
+		log.Fatalf("Failed to set system timezone: %v", err)
+	}
+
 	// Set Gin mode based on environment
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -55,6 +68,29 @@ func main() {
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(5 * time.Minute)
 
+	// Bound the analytics and audit read queries so they cannot tie up the pool
+	repository.SetStatementTimeout(cfg.DBStatementTimeout)
+
+	// Optional read replica for the reporting and metrics queries, so they do not compete with writes
+	var replicaDB *sql.DB
+	if cfg.ReplicaDatabaseURL != "" {
+		replicaDB, err = sql.Open("postgres", cfg.ReplicaDatabaseURL)
+		if err != nil {
+			log.Fatalf("Failed to connect to replica database: %v", err)
+		}
+		defer replicaDB.Close()
+
+		replicaDB.SetMaxOpenConns(10)
+		replicaDB.SetMaxIdleConns(2)
+		replicaDB.SetConnMaxLifetime(5 * time.Minute)
+
+		if err := replicaDB.Ping(); err != nil {
+			log.Printf("Warning: Replica database ping failed: %v", err)
+		}
+		log.Println("Reporting and metrics queries routed to the read replica")
+	}
+	reportingDB := repository.ReportingDB(db, replicaDB)
+
 	// Test database connection
 	if err := db.Ping(); err != nil {
 		log.Printf("Warning: Database ping failed: %v", err)
@@ -103,24 +139,95 @@ func main() {
 	hospitalRepo := repository.NewHospitalRepository(db)
 	occurrenceRepo := repository.NewOccurrenceRepository(db)
 	occurrenceHistoryRepo := repository.NewOccurrenceHistoryRepository(db)
+	obitoRepo := repository.NewObitoRepository(db)
 	triagemRuleRepo := repository.NewTriagemRuleRepository(db, redisClient)
-	indicatorsRepo := repository.NewIndicatorsRepository(db)
+	indicatorsRepo := repository.NewIndicatorsRepository(reportingDB)
 	auditLogRepo := repository.NewAuditLogRepository(db)
 	shiftRepo := repository.NewShiftRepository(db)
 	pushSubRepo := repository.NewPushSubscriptionRepository(db)
+	notificationPrefsRepo := repository.NewUserNotificationPreferencesRepository(db)
+	listPresetRepo := repository.NewListPresetRepository(db)
+	loginHistoryRepo := repository.NewLoginHistoryRepository(db)
+	tenantRepo := repository.NewTenantRepository(db)
 
 	// Initialize admin repositories
 	adminTenantRepo := repository.NewAdminTenantRepository(db)
 	adminUserRepo := repository.NewAdminUserRepository(db)
 	adminHospitalRepo := repository.NewAdminHospitalRepository(db)
-	adminTriagemRepo := repository.NewAdminTriagemTemplateRepository(db)
+	adminTriagemRepo := repository.NewAdminTriagemTemplateRepository(db, redisClient)
 	adminSettingsRepo := repository.NewAdminSettingsRepository(db, encryptionService)
 
+	// Load occurrence data encryption from system settings (plaintext when absent)
+	if encryptionService != nil {
+		repository.SetOccurrenceDataEncryptionService(encryptionService)
+	}
+	if setting, err := adminSettingsRepo.GetSettingByKey(context.Background(), models.SettingKeyOccurrenceDataEncryption); err == nil {
+		if config, err := setting.GetOccurrenceDataEncryptionConfig(); err != nil {
+			log.Printf("Warning: Invalid occurrence_data_encryption setting, storing occurrence data as plaintext: %v", err)
+		} else if err := repository.SetOccurrenceDataEncryptionEnabled(config.Enabled); err != nil {
+			log.Printf("Warning: %v, storing occurrence data as plaintext", err)
+		} else if config.Enabled {
+			log.Println("[EncryptionService] Occurrence data encryption enabled")
+		}
+	}
+
 	// Initialize auth service
 	authService := auth.NewAuthService(jwtService, userRepo, redisClient)
+	authService.SetLoginRecorder(loginHistoryRepo)
+	if encryptionService != nil {
+		authService.SetMFAStore(repository.NewUserMFARepository(db, encryptionService))
+	}
 
-	// Initialize impersonation service
-	impersonateService := auth.NewImpersonationService(jwtService, userRepo, auditLogRepo)
+	// Load password policy from system settings (defaults apply when absent)
+	if setting, err := adminSettingsRepo.GetSettingByKey(context.Background(), models.SettingKeyPasswordPolicy); err == nil {
+		if policy, err := setting.GetPasswordPolicyConfig(); err == nil {
+			authService.SetPasswordPolicy(*policy)
+			log.Printf("[Auth] Password policy loaded (min_length=%d)", policy.MinLength)
+		} else {
+			log.Printf("Warning: Invalid password_policy setting, using defaults: %v", err)
+		}
+	}
+
+	// Load token durations by role from system settings (global durations apply when absent)
+	if setting, err := adminSettingsRepo.GetSettingByKey(context.Background(), models.SettingKeyTokenDurations); err == nil {
+		if config, err := setting.GetTokenDurationsConfig(); err == nil {
+			authService.SetTokenDurations(*config)
+			log.Printf("[Auth] Token durations loaded for %d roles", len(config.Roles))
+		} else {
+			log.Printf("Warning: Invalid token_durations setting, using global durations: %v", err)
+		}
+	}
+
+	// Initialize audit service (alerts are enabled once the email service exists)
+	auditService := audit.NewAuditService(auditLogRepo)
+	handlers.SetAuditService(auditService)
+
+	// Initialize impersonation service (recorded through the audit service so it can alert)
+	impersonateService := auth.NewImpersonationService(jwtService, userRepo, auditService)
+	impersonateService.SetSessionStore(auth.NewRedisImpersonationSessionStore(redisClient))
+
+	// Load MFA policy from system settings (no role requires MFA when absent)
+	if setting, err := adminSettingsRepo.GetSettingByKey(context.Background(), models.SettingKeyMFAPolicy); err == nil {
+		if policy, err := setting.GetMFAPolicyConfig(); err != nil {
+			log.Printf("Warning: Invalid mfa_policy setting, MFA is not required for any role: %v", err)
+		} else if len(policy.RequiredRoles) > 0 && !authService.MFAAvailable() {
+			log.Printf("Warning: mfa_policy requires MFA but no encryption key is configured, MFA is not required")
+		} else {
+			auth.SetActiveMFAPolicy(*policy)
+			log.Printf("[Auth] MFA policy loaded (required_roles=%v)", policy.RequiredRoles)
+		}
+	}
+
+	// Load impersonation policy from system settings (default duration applies when absent)
+	if setting, err := adminSettingsRepo.GetSettingByKey(context.Background(), models.SettingKeyImpersonationPolicy); err == nil {
+		if policy, err := setting.GetImpersonationPolicyConfig(); err == nil {
+			auth.SetActiveImpersonationPolicy(*policy)
+			log.Printf("[Auth] Impersonation policy loaded (duration=%dmin, max=%dmin)", policy.DurationMinutes, models.ImpersonationMaxDurationMinutes)
+		} else {
+			log.Printf("Warning: Invalid impersonation_policy setting, using defaults: %v", err)
+		}
+	}
+	middleware.SetImpersonationRevocationChecker(impersonateService)
 
 	// Initialize auth handler and set global handler
 	authHandler := handlers.NewAuthHandler(authService)
@@ -132,41 +239,87 @@ func main() {
 	handlers.SetOccurrenceRepository(occurrenceRepo)
 	handlers.SetOccurrenceHistoryRepository(occurrenceHistoryRepo)
 	handlers.SetTriagemRuleRepository(triagemRuleRepo)
-	handlers.SetMetricsOccurrenceRepository(occurrenceRepo)
+	handlers.SetObitoRepository(obitoRepo)
+	handlers.SetMetricsOccurrenceRepository(repository.NewOccurrenceRepository(reportingDB))
 	handlers.SetIndicatorsRepository(indicatorsRepo)
 	handlers.SetAuditLogRepository(auditLogRepo)
+	handlers.SetIneligibilityRepository(repository.NewObitoIneligibilityRepository(db))
+
+	// Initialize cache of dashboard metrics and indicators (disabled without Redis)
+	metricsCache := metricscache.NewCache(redisClient)
+	metricsCache.SetTTL(cfg.MetricsCacheTTL)
+	handlers.SetMetricsCache(metricsCache)
 
 	// Set admin repositories for handlers
 	handlers.SetAdminTenantRepository(adminTenantRepo)
+
+	// Initialize tenant asset storage (logos and favicons)
+	assetStorage, err := storage.New(&storage.Config{
+		Driver:         cfg.StorageDriver,
+		LocalDir:       cfg.StorageLocalDir,
+		LocalPublicURL: cfg.StorageLocalURL,
+		S3: storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PublicURL:       cfg.S3PublicURL,
+			UsePathStyle:    cfg.S3UsePathStyle,
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize asset storage: %v", err)
+	}
+	handlers.SetTenantAssetStorage(assetStorage)
+	log.Printf("[Storage] Tenant assets stored with %s driver", cfg.StorageDriver)
 	handlers.SetAdminUserRepository(adminUserRepo)
 	handlers.SetAdminHospitalRepository(adminHospitalRepo)
+	handlers.SetAdminOccurrenceRepository(repository.NewAdminOccurrenceRepository(db))
+	handlers.SetAdminEmailDeliveryRepository(repository.NewEmailDeliveryRepository(db))
 	handlers.SetImpersonateService(impersonateService)
 	handlers.SetAdminTriagemTemplateRepository(adminTriagemRepo)
 	handlers.SetAdminSettingsRepository(adminSettingsRepo)
 	handlers.SetAdminAuditLogDB(db)
 	handlers.SetTenantThemeDB(db)
 
-	// Initialize audit service
-	auditService := audit.NewAuditService(auditLogRepo)
-	handlers.SetAuditService(auditService)
-
 	// Initialize report service
-	reportService := report.NewReportService(db)
+	reportService := report.NewReportServiceWithReplica(db, replicaDB)
 	handlers.SetReportService(reportService)
 
 	// Initialize shift handler
 	shiftHandler := handlers.NewShiftHandler(shiftRepo, userRepo)
+	shiftHandler.SetHospitalRepository(hospitalRepo)
+	shiftTemplateHandler := handlers.NewShiftTemplateHandler(repository.NewShiftTemplateRepository(db), shiftRepo, userRepo)
 
 	// Initialize map handler for geographic dashboard
 	mapHandler := handlers.NewMapHandler(hospitalRepo, occurrenceRepo, shiftRepo)
+	mapHandler.SetUrgencyThresholds(adminSettingsRepo)
+	// Geocoding fallback for hospitals with an address but no coordinates, resolved in the
+	// background so map requests never wait on the geocoding API
+	var coordinateResolver *geocoding.CoordinateResolver
+	if cfg.GeocodingAPIURL != "" {
+		geocoder := geocoding.NewCachedGeocoder(geocoding.NewHTTPGeocoder(&geocoding.HTTPGeocoderConfig{
+			BaseURL:   cfg.GeocodingAPIURL,
+			APIKey:    cfg.GeocodingAPIKey,
+			UserAgent: "SIDOT/1.0",
+		}), redisClient)
+		coordinateResolver = geocoding.NewCoordinateResolver(hospitalRepo, geocoder)
+		coordinateResolver.SetCheckInterval(cfg.GeocodingInterval)
+		log.Printf("[Geocoding] Hospital geocoding fallback enabled (URL: %s)", cfg.GeocodingAPIURL)
+	}
 
 	// Initialize Push Notification Service
 	pushConfig := &notification.PushConfig{
 		ServerKey: cfg.FCMServerKey,
 	}
 	pushService := notification.NewPushService(pushConfig)
+	pushService.SetSubscriptionPruner(pushSubRepo)
 	handlers.SetPushService(pushService)
 	handlers.SetPushSubscriptionRepository(pushSubRepo)
+	handlers.SetNotificationPreferencesRepository(notificationPrefsRepo)
+	handlers.SetListPresetRepository(listPresetRepo)
+	handlers.SetLoginHistoryRepository(loginHistoryRepo)
 
 	if cfg.IsFCMConfigured() {
 		log.Println("[PushService] FCM push notifications enabled")
@@ -179,12 +332,25 @@ func main() {
 	// TODO: Load PEP API keys from database or configuration
 	// For now, use empty map - can be configured via hospital settings
 	handlers.SetPEPAPIKeys(make(map[string]uuid.UUID))
+	handlers.SetPEPHospitalLookup(adminHospitalRepo)
+	pepCertFingerprints, err := middleware.ParsePEPCertFingerprints(cfg.PEPClientCerts)
+	if err != nil {
+		log.Fatalf("Invalid PEP_CLIENT_CERTS: %v", err)
+	}
+	if len(pepCertFingerprints) > 0 {
+		log.Printf("[PEP] Client certificate authentication enabled for %d agent(s)", len(pepCertFingerprints))
+	}
 	log.Println("[PEP] PEP integration endpoint initialized")
 
 	// Initialize SSE Hub for real-time notifications
 	sseHub := notification.NewSSEHub(redisClient, db)
 	handlers.SetGlobalSSEHub(sseHub)
 
+	// Live map updates published on the map topic of the SSE hub
+	mapUpdatePublisher := notification.NewMapUpdatePublisher(db, sseHub)
+	mapUpdatePublisher.SetUrgencyThresholds(adminSettingsRepo)
+	handlers.SetMapUpdatePublisher(mapUpdatePublisher)
+
 	// Initialize AI Service Client
 	aiServiceClient := integration.NewAIServiceClient(integration.DefaultAIServiceConfig())
 	handlers.SetAIServiceClient(aiServiceClient)
@@ -197,11 +363,64 @@ func main() {
 		SMTPUser:     cfg.SMTPUser,
 		SMTPPassword: cfg.SMTPPassword,
 		SMTPFrom:     cfg.SMTPFrom,
+
+		UseTLS:             cfg.SMTPUseTLS,
+		RequireSTARTTLS:    cfg.SMTPRequireSTARTTLS,
+		InsecureSkipVerify: cfg.SMTPInsecureSkipVerify,
+
+		PoolSize:        cfg.SMTPPoolSize,
+		PoolIdleTimeout: cfg.SMTPPoolIdleTimeout,
+		PoolMaxMessages: cfg.SMTPPoolMaxMessages,
+	}
+	if cfg.SMTPCACert != "" {
+		rootCAs, err := notification.LoadCACertPool(cfg.SMTPCACert)
+		if err != nil {
+			log.Fatalf("Failed to load SMTP_CA_CERT: %v", err)
+		}
+		emailConfig.RootCAs = rootCAs
+	}
+	if cfg.SMTPInsecureSkipVerify {
+		log.Printf("[Email] WARNING: SMTP certificate verification is disabled (SMTP_INSECURE_SKIP_VERIFY)")
 	}
 	emailService := notification.NewEmailService(emailConfig)
 
+	// Onboarding emails link to the dashboard (verify-email page for verification links, login page for temporary passwords)
+	authService.SetEmailVerification(userRepo, emailService, cfg.DashboardURL+"/verify-email")
+	authService.SetWelcomeEmail(userRepo, emailService, cfg.DashboardURL+"/login")
+
+	// Load email verification from system settings (new users are verified when absent)
+	if setting, err := adminSettingsRepo.GetSettingByKey(context.Background(), models.SettingKeyEmailVerification); err == nil {
+		if config, err := setting.GetEmailVerificationConfig(); err != nil {
+			log.Printf("Warning: Invalid email_verification setting, emails are not verified: %v", err)
+		} else if config.Enabled && !authService.EmailVerificationAvailable() {
+			log.Printf("Warning: email_verification is enabled but SMTP or Redis is not configured, emails are not verified")
+		} else {
+			auth.SetActiveEmailVerificationPolicy(*config)
+			log.Printf("[Auth] Email verification loaded (enabled=%v, grace=%dh)", config.Enabled, config.GraceHours)
+		}
+	}
+
+	// Email admins about critical audit events (impersonation, bans, rule deletion by default)
+	if cfg.AdminAlertEmail != "" {
+		auditAlertRules := audit.DefaultAlertRules
+		if len(cfg.AuditAlertRules) > 0 {
+			auditAlertRules, err = audit.ParseAlertRules(cfg.AuditAlertRules)
+			if err != nil {
+				log.Fatalf("Failed to parse AUDIT_ALERT_RULES: %v", err)
+			}
+		}
+		auditService.SetAlerts(emailService, audit.AlertConfig{
+			Recipients:   []string{cfg.AdminAlertEmail},
+			Rules:        auditAlertRules,
+			Cooldown:     cfg.AuditAlertCooldown,
+			DashboardURL: cfg.DashboardURL,
+		})
+	}
+
 	// Initialize Email Queue Worker
 	emailQueueWorker := notification.NewEmailQueueWorker(redisClient, emailService, db)
+	emailQueueWorker.SetDedupWindow(cfg.EmailDedupWindow)
+	handlers.SetGlobalEmailQueueWorker(emailQueueWorker)
 
 	// Initialize and start obito listener
 	obitoListener := listener.NewObitoListener(db, redisClient, cfg.ListenerPollInterval)
@@ -209,6 +428,7 @@ func main() {
 
 	// Initialize and start triagem motor
 	triagemMotor := triagem.NewTriagemMotor(db, redisClient)
+	triagemMotor.SetAutoAssigner(triagem.NewAutoAssigner(db, adminSettingsRepo))
 	handlers.SetGlobalTriagemMotor(triagemMotor)
 
 	// Initialize Health Monitor Service
@@ -218,48 +438,116 @@ func main() {
 	healthMonitor.SetTriagemMotor(triagemMotor)
 	healthMonitor.SetCheckInterval(cfg.HealthCheckInterval)
 	healthMonitor.SetCooldownPeriod(time.Duration(cfg.AlertCooldownMinutes) * time.Minute)
+
+	// Load per-component latency thresholds from system settings (defaults apply when absent)
+	if setting, err := adminSettingsRepo.GetSettingByKey(context.Background(), models.SettingKeyHealthLatencyThresholds); err == nil {
+		if thresholds, err := setting.GetHealthLatencyThresholdsConfig(); err == nil {
+			healthMonitor.SetLatencyThresholds(thresholds)
+			log.Printf("[HealthMonitor] Latency thresholds loaded for %d component(s)", len(thresholds))
+		} else {
+			log.Printf("Warning: Invalid health_latency_thresholds setting, using defaults: %v", err)
+		}
+	}
 	handlers.SetGlobalHealthMonitor(healthMonitor)
 
-	// Set callback for new occurrences to trigger SSE notifications
-	triagemMotor.SetOnOccurrenceCreated(func(ctx context.Context, occurrence *models.Occurrence, hospitalNome string) {
-		// Publish SSE event for dashboard notifications
-		if err := sseHub.PublishNewOccurrence(ctx, occurrence, hospitalNome); err != nil {
-			log.Printf("Warning: Failed to publish SSE event: %v", err)
+	// Initialize Shift Coverage Alert Service
+	coverageAlertService := shift.NewCoverageAlertService(db, redisClient, emailService)
+	coverageAlertService.SetCheckInterval(cfg.CoverageAlertInterval)
+	coverageAlertService.SetLookahead(cfg.CoverageAlertLookahead)
+	coverageAlertService.SetDashboardURL(cfg.DashboardURL)
+
+	// Initialize SLA escalation monitor (pending occurrences close to window expiry)
+	slaMonitor := escalation.NewSLAMonitor(db, redisClient, emailService, sseHub)
+	slaMonitor.SetCheckInterval(cfg.SLAEscalationInterval)
+	slaMonitor.SetThreshold(cfg.SLAEscalationThreshold)
+	slaMonitor.SetDashboardURL(cfg.DashboardURL)
+
+	// Initialize occurrence expiry job (pending occurrences past their capture window)
+	occurrenceExpiryJob := expiry.NewOccurrenceExpiryJob(db)
+	occurrenceExpiryJob.SetCheckInterval(cfg.OccurrenceExpiryInterval)
+	occurrenceExpiryJob.SetMetricsCache(metricsCache)
+
+	// Initialize audit log retention job (archives entries past retention, then deletes them).
+	// Archives are written under a directory or S3 bucket of their own, never the public uploads.
+	archiveStorage, err := storage.New(&storage.Config{
+		Driver:         cfg.AuditArchiveDriver,
+		LocalDir:       cfg.AuditArchiveDir,
+		LocalPublicURL: cfg.AuditArchiveDir,
+		S3: storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.AuditArchiveS3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			UsePathStyle:    cfg.S3UsePathStyle,
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize audit archive storage: %v", err)
+	}
+	auditRetentionJob := audit.NewRetentionJob(db, archiveStorage)
+	auditRetentionJob.SetRetention(cfg.AuditLogRetention)
+	auditRetentionJob.SetCheckInterval(cfg.AuditArchiveInterval)
+	auditRetentionJob.SetPrefix(cfg.AuditArchivePrefix)
+
+	// Initialize SMS Service and Queue Worker
+	smsService := notification.NewSMSService(&notification.SMSConfig{
+		AccountSID:      cfg.TwilioAccountSID,
+		AuthToken:       cfg.TwilioAuthToken,
+		FromPhoneNumber: cfg.TwilioPhoneNumber,
+	})
+	smsQueueWorker := notification.NewSMSQueueWorker(redisClient, smsService, db)
+
+	// Initialize occurrence notifier (email/SMS/push filtered by user notification preferences)
+	occurrenceNotifier := notification.NewOccurrenceNotifier(userRepo, notificationPrefsRepo)
+	occurrenceNotifier.SetDashboardURL(cfg.DashboardURL)
+	occurrenceNotifier.SetUrgencyThresholds(adminSettingsRepo)
+	if emailService.IsConfigured() {
+		occurrenceNotifier.SetEmailQueue(emailQueueWorker)
+	}
+	if smsService.IsConfigured() {
+		occurrenceNotifier.SetSMSQueue(smsQueueWorker)
+	}
+	if pushService.IsConfigured() {
+		occurrenceNotifier.SetPush(pushService, pushSubRepo)
+	}
+
+	// Relay occurrence events from the transactional outbox to SSE and notifications.
+	// Occurrences are written together with their outbox event, so none is lost if the process stops in between.
+	outboxRelay := outbox.NewRelay(db)
+	outboxRelay.SetSentRetention(cfg.OutboxSentRetention)
+	outboxRelay.Handle(models.OutboxEventOccurrenceCreated, func(ctx context.Context, event *models.OutboxEvent) error {
+		var payload models.OccurrenceCreatedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
 		}
 
-		// Queue email notifications for operators if email service is configured
-		if emailService.IsConfigured() {
-			// Get operators to notify (you could filter by hospital if needed)
-			operators, err := userRepo.ListByRole(ctx, "operador")
-			if err != nil {
-				log.Printf("Warning: Failed to get operators for email notification: %v", err)
-				return
-			}
+		occurrence, err := occurrenceRepo.GetByID(ctx, payload.OccurrenceID)
+		if err != nil {
+			return err
+		}
 
-			// Get occurrence details for email
-			var completeData models.OccurrenceCompleteData
-			if err := json.Unmarshal(occurrence.DadosCompletos, &completeData); err != nil {
-				log.Printf("Warning: Failed to parse occurrence data for email: %v", err)
-				return
-			}
+		// New occurrences change the dashboard metrics
+		metricsCache.Invalidate(ctx)
 
-			emailData := &notification.ObitoNotificationData{
-				HospitalNome:  hospitalNome,
-				Setor:         completeData.Setor,
-				HoraObito:     occurrence.DataObito,
-				TempoRestante: occurrence.FormatTimeRemaining(),
-				OccurrenceID:  occurrence.ID.String(),
-				Prioridade:    occurrence.ScorePriorizacao,
-				DashboardURL:  "http://localhost:3000/dashboard", // Configure via env
-			}
+		hospitalNome := "Hospital Desconhecido"
+		if hospital, err := hospitalRepo.GetByID(ctx, occurrence.HospitalID); err == nil {
+			hospitalNome = hospital.Nome
+		}
 
-			for _, operator := range operators {
-				userID := operator.ID
-				if err := emailQueueWorker.EnqueueEmail(ctx, occurrence.ID, operator.Email, &userID, emailData); err != nil {
-					log.Printf("Warning: Failed to queue email for %s: %v", operator.Email, err)
-				}
-			}
+		// Publish SSE event for dashboard notifications; a failure leaves the event for retry
+		if err := sseHub.PublishNewOccurrence(ctx, occurrence, hospitalNome); err != nil {
+			return fmt.Errorf("failed to publish SSE event: %w", err)
 		}
+
+		// Update the hospital marker of the live map; a failure is caught up by its periodic check
+		if err := mapUpdatePublisher.PublishOccurrenceCreated(ctx, occurrence); err != nil {
+			log.Printf("[MapUpdates] Failed to publish new occurrence %s: %v", occurrence.ID, err)
+		}
+
+		// Queue email/SMS/push notifications according to each operator's preferences
+		occurrenceNotifier.NotifyNewOccurrence(ctx, occurrence, hospitalNome)
+		return nil
 	})
 
 	// Create context for background services
@@ -282,20 +570,85 @@ func main() {
 		log.Printf("Warning: Failed to start email queue worker: %v", err)
 	}
 
+	if smsService.IsConfigured() {
+		if err := smsQueueWorker.Start(ctx); err != nil {
+			log.Printf("Warning: Failed to start SMS queue worker: %v", err)
+		}
+	}
+
 	// Start health monitor service
 	if err := healthMonitor.Start(ctx); err != nil {
 		log.Printf("Warning: Failed to start health monitor: %v", err)
 	}
 	log.Println("[HealthMonitor] Health monitor service initialized")
 
+	// Start shift coverage alert service
+	if err := coverageAlertService.Start(ctx); err != nil {
+		log.Printf("Warning: Failed to start coverage alert service: %v", err)
+	}
+
+	// Start live map update publisher
+	if err := mapUpdatePublisher.Start(ctx); err != nil {
+		log.Printf("Warning: Failed to start map update publisher: %v", err)
+	}
+
+	// Start SLA escalation monitor
+	if err := slaMonitor.Start(ctx); err != nil {
+		log.Printf("Warning: Failed to start SLA escalation monitor: %v", err)
+	}
+
+	// Start hospital coordinate resolver (only with GEOCODING_API_URL)
+	if coordinateResolver != nil {
+		if err := coordinateResolver.Start(ctx); err != nil {
+			log.Printf("Warning: Failed to start hospital coordinate resolver: %v", err)
+		}
+	}
+
+	// Start occurrence expiry job
+	if err := occurrenceExpiryJob.Start(ctx); err != nil {
+		log.Printf("Warning: Failed to start occurrence expiry job: %v", err)
+	}
+
+	// Start audit log retention job (disabled with AUDIT_LOG_RETENTION=0)
+	if cfg.AuditLogRetention > 0 {
+		if err := auditRetentionJob.Start(ctx); err != nil {
+			log.Printf("Warning: Failed to start audit log retention job: %v", err)
+		}
+	}
+
+	// Start outbox relay
+	if err := outboxRelay.Start(ctx); err != nil {
+		log.Printf("Warning: Failed to start outbox relay: %v", err)
+	}
+
 	// Initialize router
 	router := gin.Default()
 
 	// Apply global middleware
-	router.Use(middleware.CORS(cfg.CORSOrigins))
+	router.Use(middleware.TenantCORS(cfg.CORSOrigins, tenantRepo))
 	router.Use(middleware.RequestID())
 	router.Use(middleware.Logger())
 	router.Use(middleware.SetJWTService(jwtService))
+	router.Use(middleware.BodyLimit(middleware.BodyLimitConfig{
+		MaxBytes:     int64(cfg.MaxRequestBodyBytes),
+		MaxJSONDepth: cfg.MaxJSONDepth,
+		RouteLimits: map[string]int64{
+			"/api/v1/admin/tenants/:id/assets": int64(cfg.MaxUploadBodyBytes),
+			"/api/v1/ai/documents/index":       int64(cfg.MaxUploadBodyBytes),
+		},
+	}))
+	router.Use(middleware.Timeout(middleware.TimeoutConfig{
+		Timeout: cfg.RequestTimeout,
+		GroupTimeouts: map[string]time.Duration{
+			// Streams stay open for the whole session
+			"/api/v1/notifications/stream": 0,
+			"/api/v1/notifications/ws":     0,
+			"/api/v1/map/stream":           0,
+			"/api/v1/ai/chat/stream":       0,
+			"/api/v1/reports":              cfg.LongRequestTimeout,
+			"/api/v1/ai":                   cfg.LongRequestTimeout,
+		},
+	}))
 
 	// Health check endpoint (basic)
 	router.GET("/health", func(c *gin.Context) {
@@ -305,6 +658,13 @@ func main() {
 		})
 	})
 
+	// Liveness and readiness probes
+	router.GET("/healthz", handlers.Liveness)
+	router.GET("/readyz", handlers.Readiness)
+
+	// Prometheus scrape endpoint
+	router.GET("/metrics", handlers.PrometheusMetrics(cfg.MetricsToken))
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
@@ -316,10 +676,19 @@ func main() {
 			authRoutes.POST("/refresh", handlers.RefreshToken)
 			authRoutes.POST("/logout", handlers.Logout)
 			authRoutes.GET("/me", middleware.AuthRequired(), handlers.Me)
+			authRoutes.POST("/mfa/verify", middleware.LoginRateLimit(redisClient, cfg.LoginRateLimit), handlers.VerifyMFA)
+			authRoutes.POST("/mfa/enroll", middleware.AuthRequired(), handlers.EnrollMFA)
+			authRoutes.POST("/mfa/enroll/confirm", middleware.AuthRequired(), handlers.ConfirmMFAEnrollment)
+			authRoutes.GET("/verify-email", handlers.VerifyEmail)
+			authRoutes.GET("/sessions", middleware.AuthRequired(), handlers.ListSessions)
+			authRoutes.DELETE("/sessions", middleware.AuthRequired(), handlers.RevokeAllSessions)
+			authRoutes.DELETE("/sessions/:jti", middleware.AuthRequired(), handlers.RevokeSession)
 		}
 
-		// SSE stream with query param authentication (for EventSource - no auth header support)
+		// SSE stream and WebSocket with query param authentication (EventSource/WebSocket - no auth header support)
 		v1.GET("/notifications/stream", handlers.NotificationStream)
+		v1.GET("/notifications/ws", handlers.NotificationWebSocket)
+		v1.GET("/map/stream", handlers.MapStream)
 
 		// Public health summary endpoint (for load balancers)
 		v1.GET("/health/summary", handlers.HealthSummary)
@@ -344,10 +713,18 @@ func main() {
 			users := protected.Group("/users")
 			{
 				users.GET("", middleware.RequireRole("admin"), handlers.ListUsers)
+				users.GET("/me/notification-preferences", handlers.GetMyNotificationPreferences)
+				users.PUT("/me/notification-preferences", handlers.UpdateMyNotificationPreferences)
+				users.GET("/me/occurrence-presets", handlers.ListMyOccurrencePresets)
+				users.PUT("/me/occurrence-presets", handlers.SaveMyOccurrencePreset)
+				users.DELETE("/me/occurrence-presets/:id", handlers.DeleteMyOccurrencePreset)
 				users.GET("/:id", handlers.GetUser)
 				users.POST("", middleware.RequireRole("admin"), handlers.CreateUser)
 				users.PATCH("/:id", handlers.UpdateUser)
 				users.DELETE("/:id", middleware.RequireRole("admin"), handlers.DeleteUser)
+				users.POST("/:id/restore", middleware.RequireRole("admin"), handlers.RestoreUser)
+				users.POST("/:id/resend-welcome", middleware.RequireRole("admin"), handlers.ResendWelcome)
+				users.GET("/:id/logins", middleware.RequireRole("admin"), handlers.ListUserLogins)
 			}
 
 			// Occurrences
@@ -356,8 +733,10 @@ func main() {
 				occurrences.GET("", handlers.ListOccurrences)
 				occurrences.GET("/:id", handlers.GetOccurrence)
 				occurrences.GET("/:id/history", handlers.GetOccurrenceHistory)
+				occurrences.GET("/:id/pdf", handlers.GetOccurrencePDF)
 				occurrences.PATCH("/:id/status", handlers.UpdateOccurrenceStatus)
 				occurrences.POST("/:id/outcome", handlers.RegisterOutcome)
+				occurrences.POST("/:id/assign", handlers.AssignOccurrence)
 			}
 
 			// Triagem Rules
@@ -365,6 +744,7 @@ func main() {
 			{
 				rules.GET("", middleware.RequireRole("gestor", "admin"), handlers.ListTriagemRules)
 				rules.POST("", middleware.RequireRole("gestor", "admin"), handlers.CreateTriagemRule)
+				rules.POST("/simulate", middleware.RequireRole("gestor", "admin"), handlers.SimulateTriagemRules)
 				rules.PATCH("/:id", middleware.RequireRole("gestor", "admin"), handlers.UpdateTriagemRule)
 				rules.DELETE("/:id", middleware.RequireRole("gestor", "admin"), handlers.DeleteTriagemRule)
 			}
@@ -376,6 +756,7 @@ func main() {
 			// Health checks (protected - for detailed info)
 			protected.GET("/health/listener", handlers.ListenerHealth)
 			protected.GET("/health/sse", handlers.SSEHealth)
+			protected.POST("/health/check", middleware.RequireRole("admin"), handlers.TriggerHealthCheck)
 
 			// Shifts (plantoes)
 			shifts := protected.Group("/shifts")
@@ -385,12 +766,24 @@ func main() {
 				shifts.PUT("/:id", middleware.RequireRole("admin", "gestor"), shiftHandler.Update)
 				shifts.DELETE("/:id", middleware.RequireRole("admin", "gestor"), shiftHandler.Delete)
 				shifts.GET("/me", shiftHandler.GetMyShifts)
+				shifts.GET("/on-duty", shiftHandler.GetOnDuty)
+			}
+
+			// Shift templates (modelos de escala)
+			shiftTemplates := protected.Group("/shift-templates")
+			shiftTemplates.Use(middleware.RequireRole("admin", "gestor"))
+			{
+				shiftTemplates.POST("", shiftTemplateHandler.Create)
+				shiftTemplates.GET("", shiftTemplateHandler.List)
 			}
 
 			// Hospital-specific shift routes
 			protected.GET("/hospitals/:id/shifts", shiftHandler.ListByHospital)
+			protected.POST("/hospitals/:id/shifts/import", middleware.RequireRole("admin", "gestor"), shiftHandler.ImportShifts)
+			protected.POST("/hospitals/:id/shift-templates/:templateId/apply", middleware.RequireRole("admin", "gestor"), shiftTemplateHandler.Apply)
 			protected.GET("/hospitals/:id/shifts/today", shiftHandler.GetTodayShifts)
 			protected.GET("/hospitals/:id/shifts/coverage", shiftHandler.GetCoverageGaps)
+			protected.GET("/hospitals/:id/shifts/schedule", shiftHandler.GetSchedule)
 
 			// Map routes (Dashboard Geografico)
 			mapRoutes := protected.Group("/map")
@@ -400,6 +793,7 @@ func main() {
 
 			// Audit Logs
 			protected.GET("/audit-logs", middleware.RequireRole("admin", "gestor"), handlers.ListAuditLogs)
+			protected.GET("/ineligibilities", middleware.RequireRole("admin", "gestor"), handlers.ListIneligibilities)
 			protected.GET("/occurrences/:id/timeline", handlers.GetOccurrenceTimeline)
 
 			// Reports
@@ -407,6 +801,7 @@ func main() {
 			{
 				reports.GET("/csv", middleware.RequireRole("admin", "gestor"), handlers.ExportCSV)
 				reports.GET("/pdf", middleware.RequireRole("admin", "gestor"), handlers.ExportPDF)
+				reports.GET("/causa-mortis", middleware.RequireRole("admin", "gestor"), handlers.GetCausaMortisReport)
 			}
 
 			// Push Notifications
@@ -437,6 +832,7 @@ func main() {
 				// Conversation management endpoints
 				ai.GET("/conversations", handlers.AIListConversations)
 				ai.GET("/conversations/:session_id", handlers.AIGetConversation)
+				ai.GET("/conversations/:session_id/export", handlers.AIExportConversation)
 				ai.DELETE("/conversations/:session_id", handlers.AIDeleteConversation)
 
 				// AI service health check
@@ -472,8 +868,13 @@ func main() {
 				adminTenants.POST("", handlers.AdminCreateTenant)
 				adminTenants.PUT("/:id", handlers.AdminUpdateTenant)
 				adminTenants.PUT("/:id/theme", handlers.AdminUpdateThemeConfig)
+				adminTenants.GET("/:id/theme/versions", handlers.AdminListThemeVersions)
+				adminTenants.POST("/:id/theme/rollback/:version", handlers.AdminRollbackThemeConfig)
 				adminTenants.PUT("/:id/toggle", handlers.AdminToggleTenantActive)
 				adminTenants.POST("/:id/assets", handlers.AdminUploadTenantAssets)
+				adminTenants.POST("/:id/reassign-hospitals", handlers.AdminReassignTenantHospitals)
+				adminTenants.GET("/:id/export", handlers.AdminExportTenant)
+				adminTenants.DELETE("/:id/purge", handlers.AdminPurgeTenant)
 			}
 
 			// User Management (Task Group 4 - Implemented)
@@ -487,6 +888,13 @@ func main() {
 				adminUsers.POST("/:id/reset-password", handlers.AdminResetPassword)
 			}
 
+			// Impersonation Sessions
+			adminImpersonations := admin.Group("/impersonations")
+			{
+				adminImpersonations.GET("", handlers.AdminListImpersonations)
+				adminImpersonations.DELETE("/:jti", handlers.AdminRevokeImpersonation)
+			}
+
 			// Hospital Management (Task Group 4 - Implemented)
 			adminHospitals := admin.Group("/hospitals")
 			{
@@ -494,8 +902,16 @@ func main() {
 				adminHospitals.GET("/:id", handlers.AdminGetHospital)
 				adminHospitals.PUT("/:id", handlers.AdminUpdateHospital)
 				adminHospitals.PUT("/:id/reassign", handlers.AdminReassignHospitalTenant)
+				adminHospitals.DELETE("/:id", handlers.AdminDeleteHospital)
+				adminHospitals.POST("/:id/restore", handlers.AdminRestoreHospital)
 			}
 
+			// Cross-tenant occurrence search
+			admin.GET("/occurrences", handlers.AdminSearchOccurrences)
+
+			// Delivery status of queued emails
+			admin.GET("/email-deliveries", handlers.AdminListEmailDeliveries)
+
 			// Triagem Rule Templates (Task Group 5 - Implemented)
 			adminTriagemTemplates := admin.Group("/triagem-templates")
 			{
@@ -521,11 +937,17 @@ func main() {
 			admin.GET("/logs/export", handlers.AdminExportAuditLogs)
 		}
 
-		// PEP Integration (API Key authentication, not user auth)
+		// PEP Integration (API key or client certificate authentication, not user auth)
 		pep := v1.Group("/pep")
+		pep.Use(middleware.PEPClientCert(middleware.PEPCertConfig{
+			Fingerprints:      pepCertFingerprints,
+			FingerprintHeader: cfg.PEPClientCertHeader,
+		}))
 		{
 			pep.POST("/eventos", handlers.ReceivePEPEvent)
 			pep.GET("/status", handlers.GetPEPStatus)
+			pep.GET("/ping", handlers.PingPEP)
+			pep.POST("/validate-mapping", handlers.ValidatePEPMapping)
 		}
 	}
 
@@ -556,12 +978,24 @@ func main() {
 	// Cancel background services context
 	cancelBackground()
 
-	// Stop background services
+	// Stop background services, producers first. The triagem motor and the email worker stop
+	// taking new work, then finish the messages and emails in flight within their drain timeout.
 	obitoListener.Stop()
 	triagemMotor.Stop()
 	sseHub.Stop()
 	emailQueueWorker.Stop()
+	emailService.Close()
+	smsQueueWorker.Stop()
 	healthMonitor.Stop()
+	coverageAlertService.Stop()
+	mapUpdatePublisher.Stop()
+	slaMonitor.Stop()
+	occurrenceExpiryJob.Stop()
+	if coordinateResolver != nil {
+		coordinateResolver.Stop()
+	}
+	auditRetentionJob.Stop()
+	outboxRelay.Stop()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
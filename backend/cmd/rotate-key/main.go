@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	_ "github.com/lib/pq"
+	"github.com/sidot/backend/config"
+	"github.com/sidot/backend/internal/repository"
+	"github.com/sidot/backend/internal/services"
+)
+
+// rotate-key re-encrypts all encrypted system settings, occurrence dados_completos and MFA secrets
+// with a new encryption key.
+//
+// Usage:
+//
+//	ENCRYPTION_KEY_OLD=<old> ENCRYPTION_KEY_NEW=<new> go run ./cmd/rotate-key -old-version 1 -new-version 2
+//
+// Rollout: deploy with ENCRYPTION_KEY=<new>, ENCRYPTION_KEY_VERSION=2 and
+// ENCRYPTION_PREVIOUS_KEYS=1:<old> so values of both versions decrypt, run this command,
+// then drop the old key from ENCRYPTION_PREVIOUS_KEYS once no data depends on it.
+func main() {
+	var (
+		oldKey     = flag.String("old-key", "", "Current encryption key (base64 or raw 32 bytes), defaults to $ENCRYPTION_KEY_OLD")
+		oldVersion = flag.Int("old-version", services.LegacyKeyVersion, "Version of the current encryption key")
+		newKey     = flag.String("new-key", "", "New encryption key (base64 or raw 32 bytes), defaults to $ENCRYPTION_KEY_NEW")
+		newVersion = flag.Int("new-version", 0, "Version of the new encryption key (defaults to old-version + 1)")
+		previous   = flag.String("previous-keys", "", "Other retired keys still present in the data, as comma-separated version:key pairs")
+		dryRun     = flag.Bool("dry-run", false, "Decrypt and re-encrypt without writing changes")
+	)
+	flag.Parse()
+
+	if *oldKey == "" {
+		*oldKey = os.Getenv("ENCRYPTION_KEY_OLD")
+	}
+	if *newKey == "" {
+		*newKey = os.Getenv("ENCRYPTION_KEY_NEW")
+	}
+	if *oldKey == "" || *newKey == "" {
+		log.Fatal("Both the old and the new encryption keys are required")
+	}
+	if *newVersion == 0 {
+		*newVersion = *oldVersion + 1
+	}
+	if *newVersion == *oldVersion {
+		log.Fatal("The new key version must differ from the old key version")
+	}
+
+	svc, err := buildKeyring(*oldKey, *oldVersion, *newKey, *newVersion, *previous)
+	if err != nil {
+		log.Fatalf("Invalid encryption keys: %v", err)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Connect to database
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	repo := repository.NewAdminSettingsRepository(db, svc)
+	result, err := repo.RotateEncryptionKey(context.Background(), svc, *dryRun)
+	if err != nil {
+		log.Fatalf("Key rotation failed, no values were changed: %v", err)
+	}
+
+	mode := "Rotated"
+	if *dryRun {
+		mode = "Dry run: would rotate"
+	}
+	log.Printf("%s %d settings to key version %d: %s", mode, len(result.Rotated), result.KeyVersion, strings.Join(result.Rotated, ", "))
+	for _, column := range []string{"occurrences.dados_completos", "users.mfa_secret"} {
+		log.Printf("%s %d rows of %s", mode, result.RotatedRows[column], column)
+	}
+	if len(result.Skipped) > 0 {
+		log.Printf("Skipped %d settings (already at v%d or not encrypted): %s", len(result.Skipped), result.KeyVersion, strings.Join(result.Skipped, ", "))
+	}
+}
+
+// buildKeyring creates a service that encrypts with the new key and decrypts with the old and retired keys
+func buildKeyring(oldKeyStr string, oldVersion int, newKeyStr string, newVersion int, previous string) (*services.EncryptionService, error) {
+	newKey, err := services.ParseEncryptionKey(newKeyStr)
+	if err != nil {
+		return nil, err
+	}
+	oldKey, err := services.ParseEncryptionKey(oldKeyStr)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, err := services.NewEncryptionServiceWithVersion(newVersion, newKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := svc.AddPreviousKey(oldVersion, oldKey); err != nil {
+		return nil, err
+	}
+
+	if previous != "" {
+		for _, entry := range strings.Split(previous, ",") {
+			if err := svc.AddPreviousKeyEntry(entry); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return svc, nil
+}
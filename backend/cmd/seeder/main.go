@@ -281,7 +281,7 @@ func seedUsers(ctx context.Context, db *sql.DB) error {
 		_, err := db.ExecContext(ctx, `
 			INSERT INTO users (id, email, password_hash, nome, role, ativo, created_at, updated_at)
 			VALUES ($1, $2, $3, $4, $5, true, NOW(), NOW())
-			ON CONFLICT (email) DO UPDATE SET
+			ON CONFLICT (email) WHERE tenant_id IS NULL DO UPDATE SET
 				password_hash = EXCLUDED.password_hash,
 				nome = EXCLUDED.nome,
 				role = EXCLUDED.role,
@@ -13,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
 )
 
 // adminAuditLogDB is used to access the database for audit log queries
@@ -23,14 +24,29 @@ func SetAdminAuditLogDB(db *sql.DB) {
 	adminAuditLogDB = db
 }
 
+const (
+	// AdminAuditLogExportMaxRows caps the number of entries in a single CSV export
+	AdminAuditLogExportMaxRows = 10000
+
+	// adminAuditLogExportBatchSize is the page size used while iterating the export
+	adminAuditLogExportBatchSize = 500
+)
+
+// adminAuditLogPageFunc fetches up to limit entries strictly after the cursor (nil starts from the newest)
+// and returns the cursor to resume from, nil when there are no more entries
+type adminAuditLogPageFunc func(ctx context.Context, filter *AdminAuditLogFilter, after *models.AuditLogCursor, limit int) ([]AdminAuditLogResponse, *models.AuditLogCursor, error)
+
 // AdminAuditLogFilter represents filters for admin audit log queries (no tenant restriction)
 type AdminAuditLogFilter struct {
 	TenantID     *uuid.UUID       `form:"tenant_id"`
 	DataInicio   *time.Time       `form:"data_inicio" time_format:"2006-01-02"`
 	DataFim      *time.Time       `form:"data_fim" time_format:"2006-01-02"`
 	UsuarioID    *uuid.UUID       `form:"usuario_id"`
+	Actor        *string          `form:"actor"` // user id or part of the actor name
 	Acao         *string          `form:"acao"`
 	EntidadeTipo *string          `form:"entidade_tipo"`
+	EntidadeID   *string          `form:"entidade_id"`
+	IPAddress    *string          `form:"ip_address"`
 	Severity     *models.Severity `form:"severity"`
 	Page         int              `form:"page"`
 	PageSize     int              `form:"page_size"`
@@ -94,6 +110,11 @@ func AdminListAuditLogs(c *gin.Context) {
 		filter.TenantID = &tenantID
 	}
 
+	if filter.IPAddress != nil && *filter.IPAddress != "" && !isValidAuditLogIP(*filter.IPAddress) {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid ip_address format")
+		return
+	}
+
 	// Parse usuario_id from query if provided
 	if userIDStr := c.Query("usuario_id"); userIDStr != "" {
 		userID, err := uuid.Parse(userIDStr)
@@ -104,6 +125,26 @@ func AdminListAuditLogs(c *gin.Context) {
 		filter.UsuarioID = &userID
 	}
 
+	// Cursor pagination: used when the cursor param is present (empty starts from the newest entry)
+	if cursor, useCursor := c.GetQuery("cursor"); useCursor {
+		after, ok := parseAdminAuditLogCursor(c, cursor)
+		if !ok {
+			return
+		}
+
+		logs, next, err := queryAdminAuditLogsAfterCursor(c.Request.Context(), adminAuditLogDB, &filter, after, filter.PageSize)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to query audit logs",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, newAuditLogCursorResponse(logs, filter.PageSize, next))
+		return
+	}
+
 	// Build query
 	logs, total, err := queryAdminAuditLogs(c.Request.Context(), adminAuditLogDB, &filter)
 	if err != nil {
@@ -122,11 +163,15 @@ func AdminListAuditLogs(c *gin.Context) {
 		"page":        filter.Page,
 		"per_page":    filter.PageSize,
 		"total_pages": totalPages,
+		"links":       models.NewPaginationLinks(c.Request.URL, "page_size", filter.Page, filter.PageSize, totalPages),
 	})
 }
 
 // AdminExportAuditLogs exports audit logs as CSV
 // GET /api/v1/admin/logs/export
+// The export is iterated with keyset pagination, so entries inserted while it runs do not shift or
+// duplicate rows. When more than AdminAuditLogExportMaxRows entries match, the X-Next-Cursor header
+// holds the cursor param to export the next part.
 func AdminExportAuditLogs(c *gin.Context) {
 	if adminAuditLogDB == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "database not configured"})
@@ -142,10 +187,6 @@ func AdminExportAuditLogs(c *gin.Context) {
 		return
 	}
 
-	// For export, we want all records matching the filter (with reasonable limit)
-	filter.Page = 1
-	filter.PageSize = 10000 // Max 10k records for export
-
 	// Parse tenant_id from query if provided
 	if tenantIDStr := c.Query("tenant_id"); tenantIDStr != "" {
 		tenantID, err := uuid.Parse(tenantIDStr)
@@ -156,7 +197,21 @@ func AdminExportAuditLogs(c *gin.Context) {
 		filter.TenantID = &tenantID
 	}
 
-	logs, _, err := queryAdminAuditLogs(c.Request.Context(), adminAuditLogDB, &filter)
+	if filter.IPAddress != nil && *filter.IPAddress != "" && !isValidAuditLogIP(*filter.IPAddress) {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid ip_address format")
+		return
+	}
+
+	after, ok := parseAdminAuditLogCursor(c, c.Query("cursor"))
+	if !ok {
+		return
+	}
+
+	fetch := func(ctx context.Context, filter *AdminAuditLogFilter, after *models.AuditLogCursor, limit int) ([]AdminAuditLogResponse, *models.AuditLogCursor, error) {
+		return queryAdminAuditLogsAfterCursor(ctx, adminAuditLogDB, filter, after, limit)
+	}
+
+	logs, next, err := collectAdminAuditLogExport(c.Request.Context(), &filter, after, AdminAuditLogExportMaxRows, adminAuditLogExportBatchSize, fetch)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "failed to query audit logs",
@@ -169,6 +224,9 @@ func AdminExportAuditLogs(c *gin.Context) {
 	filename := fmt.Sprintf("audit_logs_%s.csv", time.Now().Format("20060102_150405"))
 	c.Header("Content-Type", "text/csv")
 	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	if next != nil {
+		c.Header("X-Next-Cursor", next.Encode())
+	}
 
 	// Write CSV
 	writer := csv.NewWriter(c.Writer)
@@ -236,8 +294,154 @@ func AdminExportAuditLogs(c *gin.Context) {
 	}
 }
 
+// parseAdminAuditLogCursor decodes the cursor param, writing a 400 response when it is invalid
+// An empty cursor starts from the newest entry
+func parseAdminAuditLogCursor(c *gin.Context, cursor string) (*models.AuditLogCursor, bool) {
+	if cursor == "" {
+		return nil, true
+	}
+
+	after, err := models.ParseAuditLogCursor(cursor)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+		return nil, false
+	}
+	return after, true
+}
+
+// collectAdminAuditLogExport iterates audit logs in batches from the cursor until maxRows entries are read
+// Returns the cursor to resume from when entries remain, nil when the export is complete
+func collectAdminAuditLogExport(ctx context.Context, filter *AdminAuditLogFilter, after *models.AuditLogCursor, maxRows, batchSize int, fetch adminAuditLogPageFunc) ([]AdminAuditLogResponse, *models.AuditLogCursor, error) {
+	var logs []AdminAuditLogResponse
+	cursor := after
+
+	for len(logs) < maxRows {
+		limit := batchSize
+		if remaining := maxRows - len(logs); remaining < limit {
+			limit = remaining
+		}
+
+		page, next, err := fetch(ctx, filter, cursor, limit)
+		if err != nil {
+			return nil, nil, err
+		}
+		logs = append(logs, page...)
+
+		if next == nil {
+			return logs, nil, nil
+		}
+		cursor = next
+	}
+
+	return logs, cursor, nil
+}
+
 // queryAdminAuditLogs executes the audit log query with tenant info
 func queryAdminAuditLogs(ctx context.Context, db *sql.DB, filter *AdminAuditLogFilter) ([]AdminAuditLogResponse, int, error) {
+	conditions, args := adminAuditLogConditions(filter)
+	argIdx := len(args) + 1
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Count total
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM audit_logs al %s`, whereClause)
+	var total int
+	if err := db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	// Calculate offset
+	offset := (filter.Page - 1) * filter.PageSize
+
+	// Main query with tenant and hospital join
+	query := fmt.Sprintf(`
+		%s
+		%s
+		ORDER BY al.timestamp DESC, al.id DESC
+		LIMIT $%d OFFSET $%d
+	`, adminAuditLogSelect, whereClause, argIdx, argIdx+1)
+
+	args = append(args, filter.PageSize, offset)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs, err := scanAdminAuditLogs(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
+
+// queryAdminAuditLogsAfterCursor executes the audit log query with keyset pagination
+// Returns up to limit entries strictly after the cursor (nil starts from the newest entry) and
+// the cursor of the last entry, which is nil when there are no more entries
+func queryAdminAuditLogsAfterCursor(ctx context.Context, db *sql.DB, filter *AdminAuditLogFilter, after *models.AuditLogCursor, limit int) ([]AdminAuditLogResponse, *models.AuditLogCursor, error) {
+	conditions, args := adminAuditLogConditions(filter)
+	argIdx := len(args) + 1
+
+	if after != nil {
+		conditions = append(conditions, fmt.Sprintf("(al.timestamp, al.id) < ($%d, $%d)", argIdx, argIdx+1))
+		args = append(args, after.Timestamp, after.ID)
+		argIdx += 2
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	query := fmt.Sprintf(`
+		%s
+		%s
+		ORDER BY al.timestamp DESC, al.id DESC
+		LIMIT $%d
+	`, adminAuditLogSelect, whereClause, argIdx)
+
+	args = append(args, limit+1)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs, err := scanAdminAuditLogs(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(logs) <= limit {
+		return logs, nil, nil
+	}
+
+	logs = logs[:limit]
+	last := logs[len(logs)-1]
+	return logs, &models.AuditLogCursor{Timestamp: last.Timestamp, ID: last.ID}, nil
+}
+
+// adminAuditLogSelect selects audit log columns joined with tenant and hospital info
+const adminAuditLogSelect = `
+		SELECT
+			al.id, al.tenant_id, t.name, t.slug,
+			al.timestamp, al.usuario_id, al.actor_name, al.acao,
+			al.entidade_tipo, al.entidade_id, al.hospital_id,
+			h.nome AS hospital_nome, al.severity, al.detalhes,
+			al.ip_address, al.user_agent
+		FROM audit_logs al
+		LEFT JOIN tenants t ON al.tenant_id = t.id
+		LEFT JOIN hospitals h ON al.hospital_id = h.id`
+
+// adminAuditLogConditions builds the WHERE conditions and arguments for admin audit log filters
+func adminAuditLogConditions(filter *AdminAuditLogFilter) ([]string, []interface{}) {
 	// Build WHERE clause - note: no tenant_id restriction by default
 	var conditions []string
 	var args []interface{}
@@ -269,6 +473,20 @@ func queryAdminAuditLogs(ctx context.Context, db *sql.DB, filter *AdminAuditLogF
 		argIdx++
 	}
 
+	if filter.Actor != nil {
+		uid, name := parseAuditLogActor(*filter.Actor)
+		if uid != nil {
+			conditions = append(conditions, fmt.Sprintf("al.usuario_id = $%d", argIdx))
+			args = append(args, *uid)
+			argIdx++
+		}
+		if name != nil {
+			conditions = append(conditions, fmt.Sprintf("al.actor_name ILIKE $%d", argIdx))
+			args = append(args, "%"+repository.EscapeLikePattern(*name)+"%")
+			argIdx++
+		}
+	}
+
 	if filter.Acao != nil && *filter.Acao != "" {
 		conditions = append(conditions, fmt.Sprintf("al.acao = $%d", argIdx))
 		args = append(args, *filter.Acao)
@@ -281,51 +499,29 @@ func queryAdminAuditLogs(ctx context.Context, db *sql.DB, filter *AdminAuditLogF
 		argIdx++
 	}
 
-	if filter.Severity != nil && *filter.Severity != "" {
-		conditions = append(conditions, fmt.Sprintf("al.severity = $%d", argIdx))
-		args = append(args, *filter.Severity)
+	if filter.EntidadeID != nil && *filter.EntidadeID != "" {
+		conditions = append(conditions, fmt.Sprintf("al.entidade_id = $%d", argIdx))
+		args = append(args, *filter.EntidadeID)
 		argIdx++
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	if filter.IPAddress != nil && *filter.IPAddress != "" {
+		conditions = append(conditions, fmt.Sprintf("al.ip_address = $%d", argIdx))
+		args = append(args, *filter.IPAddress)
+		argIdx++
 	}
 
-	// Count total
-	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM audit_logs al %s`, whereClause)
-	var total int
-	if err := db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	if filter.Severity != nil && *filter.Severity != "" {
+		conditions = append(conditions, fmt.Sprintf("al.severity = $%d", argIdx))
+		args = append(args, *filter.Severity)
+		argIdx++
 	}
 
-	// Calculate offset
-	offset := (filter.Page - 1) * filter.PageSize
-
-	// Main query with tenant and hospital join
-	query := fmt.Sprintf(`
-		SELECT
-			al.id, al.tenant_id, t.name, t.slug,
-			al.timestamp, al.usuario_id, al.actor_name, al.acao,
-			al.entidade_tipo, al.entidade_id, al.hospital_id,
-			h.nome AS hospital_nome, al.severity, al.detalhes,
-			al.ip_address, al.user_agent
-		FROM audit_logs al
-		LEFT JOIN tenants t ON al.tenant_id = t.id
-		LEFT JOIN hospitals h ON al.hospital_id = h.id
-		%s
-		ORDER BY al.timestamp DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIdx, argIdx+1)
-
-	args = append(args, filter.PageSize, offset)
-
-	rows, err := db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query audit logs: %w", err)
-	}
-	defer rows.Close()
+	return conditions, args
+}
 
+// scanAdminAuditLogs scans rows selected with adminAuditLogSelect
+func scanAdminAuditLogs(rows *sql.Rows) ([]AdminAuditLogResponse, error) {
 	var logs []AdminAuditLogResponse
 	for rows.Next() {
 		var log AdminAuditLogResponse
@@ -342,7 +538,7 @@ func queryAdminAuditLogs(ctx context.Context, db *sql.DB, filter *AdminAuditLogF
 			&ipAddress, &userAgent,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan audit log: %w", err)
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
 		}
 
 		if tenantID.Valid {
@@ -380,8 +576,8 @@ func queryAdminAuditLogs(ctx context.Context, db *sql.DB, filter *AdminAuditLogF
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("error iterating audit logs: %w", err)
+		return nil, fmt.Errorf("error iterating audit logs: %w", err)
 	}
 
-	return logs, total, nil
+	return logs, nil
 }
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// memoryAuditLogTable emulates the audit_logs keyset query over an in-memory table
+type memoryAuditLogTable struct {
+	entries []AdminAuditLogResponse
+
+	// onFetch runs after each page is read, to simulate concurrent inserts
+	onFetch func()
+}
+
+// precedes reports whether a comes before b in (timestamp DESC, id DESC) order
+func precedes(a, b AdminAuditLogResponse) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.After(b.Timestamp)
+	}
+	return bytes.Compare(a.ID[:], b.ID[:]) > 0
+}
+
+func (t *memoryAuditLogTable) insert(timestamp time.Time) AdminAuditLogResponse {
+	entry := AdminAuditLogResponse{ID: uuid.New(), Timestamp: timestamp, Acao: "regra.update"}
+	t.entries = append(t.entries, entry)
+	return entry
+}
+
+func (t *memoryAuditLogTable) fetch(ctx context.Context, filter *AdminAuditLogFilter, after *models.AuditLogCursor, limit int) ([]AdminAuditLogResponse, *models.AuditLogCursor, error) {
+	sorted := append([]AdminAuditLogResponse(nil), t.entries...)
+	sort.Slice(sorted, func(i, j int) bool { return precedes(sorted[i], sorted[j]) })
+
+	var page []AdminAuditLogResponse
+	for _, entry := range sorted {
+		if after != nil && !precedes(AdminAuditLogResponse{ID: after.ID, Timestamp: after.Timestamp}, entry) {
+			continue
+		}
+		page = append(page, entry)
+		if len(page) == limit+1 {
+			break
+		}
+	}
+
+	if t.onFetch != nil {
+		t.onFetch()
+	}
+
+	if len(page) <= limit {
+		return page, nil, nil
+	}
+	page = page[:limit]
+	last := page[len(page)-1]
+	return page, &models.AuditLogCursor{Timestamp: last.Timestamp, ID: last.ID}, nil
+}
+
+// TestCollectAdminAuditLogExportStableWithConcurrentInserts tests that rows inserted mid-scan
+// neither duplicate nor skip existing rows
+func TestCollectAdminAuditLogExportStableWithConcurrentInserts(t *testing.T) {
+	table := &memoryAuditLogTable{}
+	base := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	expected := map[uuid.UUID]bool{}
+	for i := 0; i < 25; i++ {
+		// Pairs of entries share a timestamp, so ordering depends on the id tie-break
+		entry := table.insert(base.Add(-time.Duration(i/2) * time.Minute))
+		expected[entry.ID] = true
+	}
+
+	// New audit events are always appended at the head of the log
+	inserted := 0
+	table.onFetch = func() {
+		inserted++
+		table.insert(base.Add(time.Duration(inserted) * time.Second))
+	}
+
+	logs, next, err := collectAdminAuditLogExport(context.Background(), &AdminAuditLogFilter{}, nil, 100, 4, table.fetch)
+	if err != nil {
+		t.Fatalf("collectAdminAuditLogExport returned error: %v", err)
+	}
+	if next != nil {
+		t.Errorf("Expected export to be complete, got next cursor %s", next.Encode())
+	}
+	if inserted < 2 {
+		t.Fatalf("Expected rows to be inserted during the scan, got %d", inserted)
+	}
+
+	if len(logs) != len(expected) {
+		t.Fatalf("Expected %d rows, got %d", len(expected), len(logs))
+	}
+	seen := map[uuid.UUID]bool{}
+	for i, log := range logs {
+		if !expected[log.ID] {
+			t.Errorf("Row %s inserted after the scan started should not be exported", log.ID)
+		}
+		if seen[log.ID] {
+			t.Errorf("Row %s exported twice", log.ID)
+		}
+		seen[log.ID] = true
+		if i > 0 && !precedes(logs[i-1], log) {
+			t.Errorf("Rows %d and %d are out of order", i-1, i)
+		}
+	}
+}
+
+// TestCollectAdminAuditLogExportResumesFromCursor tests that a capped export returns a cursor
+// that continues exactly where it stopped
+func TestCollectAdminAuditLogExportResumesFromCursor(t *testing.T) {
+	table := &memoryAuditLogTable{}
+	base := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		table.insert(base.Add(-time.Duration(i) * time.Second))
+	}
+
+	first, next, err := collectAdminAuditLogExport(context.Background(), &AdminAuditLogFilter{}, nil, 6, 4, table.fetch)
+	if err != nil {
+		t.Fatalf("collectAdminAuditLogExport returned error: %v", err)
+	}
+	if len(first) != 6 || next == nil {
+		t.Fatalf("Expected 6 rows and a next cursor, got %d rows and cursor %v", len(first), next)
+	}
+
+	// New entries at the head of the log do not affect the next part
+	table.insert(base.Add(time.Second))
+
+	cursor, err := models.ParseAuditLogCursor(next.Encode())
+	if err != nil {
+		t.Fatalf("Failed to parse next cursor: %v", err)
+	}
+	second, next, err := collectAdminAuditLogExport(context.Background(), &AdminAuditLogFilter{}, cursor, 6, 4, table.fetch)
+	if err != nil {
+		t.Fatalf("collectAdminAuditLogExport returned error: %v", err)
+	}
+	if len(second) != 4 || next != nil {
+		t.Fatalf("Expected the remaining 4 rows, got %d rows and cursor %v", len(second), next)
+	}
+	if !precedes(first[len(first)-1], second[0]) {
+		t.Error("Expected the second part to start right after the first")
+	}
+}
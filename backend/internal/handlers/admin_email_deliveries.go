@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// adminEmailDeliveryLister lists the delivery records of queued emails
+// (implemented by repository.EmailDeliveryRepository)
+type adminEmailDeliveryLister interface {
+	List(ctx context.Context, filters models.EmailDeliveryFilters) ([]models.EmailDelivery, int, error)
+}
+
+var adminEmailDeliveryRepo adminEmailDeliveryLister
+
+// SetAdminEmailDeliveryRepository sets the email delivery repository for handlers
+func SetAdminEmailDeliveryRepository(repo *repository.EmailDeliveryRepository) {
+	if repo == nil {
+		adminEmailDeliveryRepo = nil
+		return
+	}
+	adminEmailDeliveryRepo = repo
+}
+
+// AdminListEmailDeliveries returns the delivery status of queued emails, most recently updated first,
+// filtered by status, occurrence_id or recipient
+// GET /api/v1/admin/email-deliveries
+func AdminListEmailDeliveries(c *gin.Context) {
+	if adminEmailDeliveryRepo == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "email delivery repository not configured")
+		return
+	}
+
+	filters := models.EmailDeliveryFilters{Page: 1, PageSize: 20, Recipient: c.Query("recipient")}
+
+	if status := c.Query("status"); status != "" {
+		s := models.EmailDeliveryStatus(status)
+		if !s.IsValid() {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid status (queued, sent or failed)")
+			return
+		}
+		filters.Status = &s
+	}
+
+	if occurrenceIDStr := c.Query("occurrence_id"); occurrenceIDStr != "" {
+		occurrenceID, err := uuid.Parse(occurrenceIDStr)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid occurrence_id format")
+			return
+		}
+		filters.OccurrenceID = &occurrenceID
+	}
+
+	if page := c.Query("page"); page != "" {
+		p, err := strconv.Atoi(page)
+		if err != nil || p < 1 {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid page number")
+			return
+		}
+		filters.Page = p
+	}
+
+	if pageSize := c.Query("page_size"); pageSize != "" {
+		ps, err := strconv.Atoi(pageSize)
+		if err != nil || ps < 1 || ps > 100 {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid page_size (1-100)")
+			return
+		}
+		filters.PageSize = ps
+	}
+
+	deliveries, totalItems, err := adminEmailDeliveryRepo.List(c.Request.Context(), filters)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to list email deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewPaginatedResponse(deliveries, filters.Page, filters.PageSize, totalItems))
+}
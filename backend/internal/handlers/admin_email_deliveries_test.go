@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// mockEmailDeliveryLister returns fixed deliveries and records the filters it received
+type mockEmailDeliveryLister struct {
+	deliveries []models.EmailDelivery
+	filters    *models.EmailDeliveryFilters
+}
+
+func (m *mockEmailDeliveryLister) List(ctx context.Context, filters models.EmailDeliveryFilters) ([]models.EmailDelivery, int, error) {
+	m.filters = &filters
+	return m.deliveries, len(m.deliveries), nil
+}
+
+// withEmailDeliveryLister installs a lister for the duration of a test
+func withEmailDeliveryLister(t *testing.T, lister adminEmailDeliveryLister) {
+	t.Helper()
+	previous := adminEmailDeliveryRepo
+	adminEmailDeliveryRepo = lister
+	t.Cleanup(func() { adminEmailDeliveryRepo = previous })
+}
+
+// TestAdminListEmailDeliveries tests that failed deliveries are listed with their error
+func TestAdminListEmailDeliveries(t *testing.T) {
+	occurrenceID := uuid.New()
+	lastError := "failed to send email: 550 mailbox unavailable"
+	lister := &mockEmailDeliveryLister{deliveries: []models.EmailDelivery{{
+		ID:           uuid.New(),
+		OccurrenceID: &occurrenceID,
+		Recipient:    "operador@sidot.gov.br",
+		Status:       models.EmailDeliveryFailed,
+		Attempts:     3,
+		LastError:    &lastError,
+	}}}
+	withEmailDeliveryLister(t, lister)
+
+	router := setupTestRouter()
+	router.Use(mockAuthMiddleware(uuid.New().String(), "admin"))
+	router.GET("/api/v1/admin/email-deliveries", AdminListEmailDeliveries)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/email-deliveries?status=failed&occurrence_id="+occurrenceID.String()+"&page_size=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data       []models.EmailDelivery `json:"data"`
+		TotalItems int                    `json:"total_items"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data) != 1 || response.Data[0].LastError == nil || *response.Data[0].LastError != lastError {
+		t.Errorf("Expected the failed delivery with its error, got %s", w.Body.String())
+	}
+	if response.TotalItems != 1 {
+		t.Errorf("Expected total_items 1, got %d", response.TotalItems)
+	}
+
+	if lister.filters == nil || lister.filters.Status == nil || *lister.filters.Status != models.EmailDeliveryFailed {
+		t.Fatalf("Expected the status filter to be passed, got %+v", lister.filters)
+	}
+	if lister.filters.OccurrenceID == nil || *lister.filters.OccurrenceID != occurrenceID || lister.filters.PageSize != 10 {
+		t.Errorf("Expected occurrence and page size filters, got %+v", lister.filters)
+	}
+}
+
+// TestAdminListEmailDeliveriesInvalidFilters tests that invalid filters are rejected
+func TestAdminListEmailDeliveriesInvalidFilters(t *testing.T) {
+	withEmailDeliveryLister(t, &mockEmailDeliveryLister{})
+
+	router := setupTestRouter()
+	router.Use(mockAuthMiddleware(uuid.New().String(), "admin"))
+	router.GET("/api/v1/admin/email-deliveries", AdminListEmailDeliveries)
+
+	for _, query := range []string{"status=bounced", "occurrence_id=abc", "page=0", "page_size=500"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/email-deliveries?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
@@ -130,6 +130,10 @@ func AdminUpdateHospital(c *gin.Context) {
 		})
 		return
 	}
+	if err := models.ValidateTimezone(input.Timezone); err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeValidationFailed, "validation failed", err.Error())
+		return
+	}
 
 	// Get existing hospital for audit
 	existingHospital, err := adminHospitalRepo.GetHospitalByID(c.Request.Context(), id)
@@ -161,14 +165,7 @@ func AdminUpdateHospital(c *gin.Context) {
 		detalhes := map[string]interface{}{
 			"hospital_id":   id.String(),
 			"hospital_nome": existingHospital.Nome,
-		}
-		if input.Nome != nil {
-			detalhes["nome_anterior"] = existingHospital.Nome
-			detalhes["nome_novo"] = *input.Nome
-		}
-		if input.Ativo != nil {
-			detalhes["ativo_anterior"] = existingHospital.Ativo
-			detalhes["ativo_novo"] = *input.Ativo
+			"alteracoes":    audit.Diff(existingHospital, hospital),
 		}
 
 		auditService.LogEventWithUser(
@@ -250,7 +247,7 @@ func AdminReassignHospitalTenant(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "hospital not found"})
 			return
 		}
-		if err.Error() == "target tenant not found" {
+		if errors.Is(err, repository.ErrAdminTargetTenantNotFound) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "target tenant not found"})
 			return
 		}
@@ -291,3 +288,201 @@ func AdminReassignHospitalTenant(c *gin.Context) {
 		"hospital": hospital.ToResponse(),
 	})
 }
+
+// AdminReassignTenantHospitals moves all hospitals of a tenant (and optionally its users) to another tenant
+// POST /api/v1/admin/tenants/:id/reassign-hospitals
+func AdminReassignTenantHospitals(c *gin.Context) {
+	if adminHospitalRepo == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin hospital repository not configured")
+		return
+	}
+
+	idParam := c.Param("id")
+	sourceTenantID, err := uuid.Parse(idParam)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid tenant ID format")
+		return
+	}
+
+	var input models.AdminReassignTenantHospitalsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "invalid request body", err.Error())
+		return
+	}
+
+	// Validate input
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeValidationFailed, "validation failed", err.Error())
+		return
+	}
+
+	reassignment, err := adminHospitalRepo.ReassignTenantHospitals(c.Request.Context(), sourceTenantID, input.TenantID, input.MoveUsers)
+	if err != nil {
+		respondDomainError(c, err, "failed to reassign hospitals")
+		return
+	}
+
+	// Log one audit event per moved hospital
+	if auditService != nil {
+		userID, actorName := audit.GetUserInfoFromContext(c)
+		ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+		for _, hospital := range reassignment.Hospitals {
+			detalhes := map[string]interface{}{
+				"hospital_id":        hospital.ID.String(),
+				"hospital_nome":      hospital.Nome,
+				"tenant_id_anterior": sourceTenantID.String(),
+				"tenant_id_novo":     input.TenantID.String(),
+				"reassign_tenant":    true,
+				"move_users":         input.MoveUsers,
+			}
+
+			auditService.LogEventWithUser(
+				c.Request.Context(),
+				userID,
+				actorName,
+				auth.ActionHospitalReassign,
+				"Hospital",
+				hospital.ID.String(),
+				nil,
+				models.SeverityWarn,
+				detalhes,
+				ipAddress,
+				userAgent,
+			)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "hospitals reassigned successfully",
+		"hospitals":   reassignment.Hospitals,
+		"moved_users": reassignment.MovedUsers,
+	})
+}
+
+// AdminDeleteHospital soft-deletes a hospital (cross-tenant)
+// DELETE /api/v1/admin/hospitals/:id
+func AdminDeleteHospital(c *gin.Context) {
+	if adminHospitalRepo == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin hospital repository not configured"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hospital ID format"})
+		return
+	}
+
+	// Get existing hospital for audit
+	existingHospital, err := adminHospitalRepo.GetHospitalByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAdminHospitalNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hospital not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get hospital"})
+		return
+	}
+
+	if err := adminHospitalRepo.SoftDeleteHospital(c.Request.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrAdminHospitalNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hospital not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete hospital"})
+		return
+	}
+
+	// Log audit event
+	if auditService != nil {
+		userID, actorName := audit.GetUserInfoFromContext(c)
+		ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+		auditService.LogEventWithUser(
+			c.Request.Context(),
+			userID,
+			actorName,
+			auth.ActionHospitalDelete,
+			"Hospital",
+			id.String(),
+			&id,
+			models.SeverityWarn,
+			map[string]interface{}{
+				"hospital_id":     id.String(),
+				"hospital_nome":   existingHospital.Nome,
+				"hospital_codigo": existingHospital.Codigo,
+				"tenant_id":       existingHospital.TenantID.String(),
+				"ativo_anterior":  existingHospital.Ativo,
+				"action":          "soft_delete",
+			},
+			ipAddress,
+			userAgent,
+		)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "hospital deleted successfully"})
+}
+
+// AdminRestoreHospital restores a soft-deleted hospital (cross-tenant)
+// POST /api/v1/admin/hospitals/:id/restore
+func AdminRestoreHospital(c *gin.Context) {
+	if adminHospitalRepo == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin hospital repository not configured"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hospital ID format"})
+		return
+	}
+
+	hospital, err := adminHospitalRepo.RestoreHospital(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAdminHospitalNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "deleted hospital not found"})
+			return
+		}
+		if errors.Is(err, repository.ErrAdminHospitalCodigoInUse) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore hospital"})
+		return
+	}
+
+	// Log audit event
+	if auditService != nil {
+		userID, actorName := audit.GetUserInfoFromContext(c)
+		ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+		auditService.LogEventWithUser(
+			c.Request.Context(),
+			userID,
+			actorName,
+			auth.ActionHospitalRestore,
+			"Hospital",
+			id.String(),
+			&id,
+			models.SeverityWarn,
+			map[string]interface{}{
+				"hospital_id":     id.String(),
+				"hospital_nome":   hospital.Nome,
+				"hospital_codigo": hospital.Codigo,
+				"tenant_id":       hospital.TenantID.String(),
+				"action":          "restore",
+			},
+			ipAddress,
+			userAgent,
+		)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "hospital restored successfully",
+		"hospital": hospital.ToResponse(),
+	})
+}
@@ -2,10 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
 )
 
 // TestAdminReassignHospitalInputValidation tests the AdminReassignHospitalInput structure
@@ -325,6 +329,28 @@ func TestHospitalReassignTenantScenarios(t *testing.T) {
 	})
 }
 
+// TestAdminReassignTenantHospitalsSameTenant tests that moving the hospitals of a tenant to itself is rejected
+func TestAdminReassignTenantHospitalsSameTenant(t *testing.T) {
+	previous := adminHospitalRepo
+	SetAdminHospitalRepository(repository.NewAdminHospitalRepository(nil))
+	t.Cleanup(func() { adminHospitalRepo = previous })
+
+	router := setupTestRouter()
+	router.Use(mockAuthMiddleware(uuid.New().String(), "admin"))
+	router.POST("/api/v1/admin/tenants/:id/reassign-hospitals", AdminReassignTenantHospitals)
+
+	tenantID := uuid.New()
+	body := `{"tenant_id": "` + tenantID.String() + `", "move_users": true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/tenants/"+tenantID.String()+"/reassign-hospitals", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for the same tenant, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+	}
+}
+
 // TestHospitalListParams tests the admin hospital list parameters
 func TestHospitalListParams(t *testing.T) {
 	tenantID := uuid.New()
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// adminOccurrenceSearcher searches occurrences across tenants
+// (implemented by repository.AdminOccurrenceRepository)
+type adminOccurrenceSearcher interface {
+	Search(ctx context.Context, filters models.AdminOccurrenceFilters) ([]models.OccurrenceWithTenant, int, error)
+}
+
+var adminOccurrenceRepo adminOccurrenceSearcher
+
+// SetAdminOccurrenceRepository sets the admin occurrence repository for handlers
+func SetAdminOccurrenceRepository(repo *repository.AdminOccurrenceRepository) {
+	if repo == nil {
+		adminOccurrenceRepo = nil
+		return
+	}
+	adminOccurrenceRepo = repo
+}
+
+// AdminSearchOccurrences returns occurrences across all tenants with pagination and filters
+// GET /api/v1/admin/occurrences
+func AdminSearchOccurrences(c *gin.Context) {
+	if adminOccurrenceRepo == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin occurrence repository not configured"})
+		return
+	}
+
+	listFilters, ok := parseOccurrenceListFilters(c, c.Request.URL.Query())
+	if !ok {
+		return
+	}
+	filters := models.AdminOccurrenceFilters{OccurrenceListFilters: listFilters}
+
+	if tenantIDStr := c.Query("tenant_id"); tenantIDStr != "" {
+		tenantID, err := uuid.Parse(tenantIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tenant_id format"})
+			return
+		}
+		filters.TenantID = &tenantID
+	}
+
+	if filters.HospitalID != nil {
+		if _, err := uuid.Parse(*filters.HospitalID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hospital_id format"})
+			return
+		}
+	}
+
+	scoreParams := []struct {
+		name   string
+		target **int
+	}{{"score_min", &filters.ScoreMin}, {"score_max", &filters.ScoreMax}}
+	for _, param := range scoreParams {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		score, err := strconv.Atoi(value)
+		if err != nil || score < 0 || score > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param.name + " (0-100)"})
+			return
+		}
+		*param.target = &score
+	}
+	if filters.ScoreMin != nil && filters.ScoreMax != nil && *filters.ScoreMin > *filters.ScoreMax {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "score_min must not be greater than score_max"})
+		return
+	}
+
+	occurrences, totalItems, err := adminOccurrenceRepo.Search(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search occurrences"})
+		return
+	}
+
+	// Convert to list response format (with masked names)
+	response := make([]models.AdminOccurrenceListResponse, 0, len(occurrences))
+	for _, o := range occurrences {
+		response = append(response, o.ToAdminListResponse())
+	}
+
+	c.JSON(http.StatusOK, models.NewPaginatedResponse(response, filters.Page, filters.PageSize, totalItems).WithLinks(c.Request.URL, "page_size"))
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// mockAdminOccurrenceSearcher returns fixed occurrences and records the filters it received
+type mockAdminOccurrenceSearcher struct {
+	occurrences []models.OccurrenceWithTenant
+	filters     *models.AdminOccurrenceFilters
+}
+
+func (m *mockAdminOccurrenceSearcher) Search(ctx context.Context, filters models.AdminOccurrenceFilters) ([]models.OccurrenceWithTenant, int, error) {
+	m.filters = &filters
+	return m.occurrences, len(m.occurrences), nil
+}
+
+// withAdminOccurrenceSearcher installs a searcher for the duration of a test
+func withAdminOccurrenceSearcher(t *testing.T, searcher adminOccurrenceSearcher) {
+	t.Helper()
+	previous := adminOccurrenceRepo
+	adminOccurrenceRepo = searcher
+	t.Cleanup(func() { adminOccurrenceRepo = previous })
+}
+
+// TestAdminSearchOccurrencesSpansTenants tests that the admin search returns occurrences from several tenants with their names
+func TestAdminSearchOccurrencesSpansTenants(t *testing.T) {
+	tenantA, tenantB := uuid.New(), uuid.New()
+	nameA, nameB := "Secretaria Goias", "Secretaria Bahia"
+
+	occA := createTestOccurrence(models.StatusPendente, uuid.New())
+	occA.TenantID = tenantA
+	occB := createTestOccurrence(models.StatusEmAndamento, uuid.New())
+	occB.TenantID = tenantB
+
+	searcher := &mockAdminOccurrenceSearcher{occurrences: []models.OccurrenceWithTenant{
+		{Occurrence: occA, TenantName: &nameA},
+		{Occurrence: occB, TenantName: &nameB},
+	}}
+	withAdminOccurrenceSearcher(t, searcher)
+
+	router := setupTestRouter()
+	router.Use(mockAuthMiddleware(uuid.New().String(), "admin"))
+	router.GET("/api/v1/admin/occurrences", AdminSearchOccurrences)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/occurrences?score_min=50&page_size=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data       []models.AdminOccurrenceListResponse `json:"data"`
+		TotalItems int                                  `json:"total_items"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if response.TotalItems != 2 || len(response.Data) != 2 {
+		t.Fatalf("Expected 2 occurrences, got %d (total %d)", len(response.Data), response.TotalItems)
+	}
+
+	names := map[uuid.UUID]string{}
+	for _, occ := range response.Data {
+		if occ.TenantName == nil {
+			t.Fatalf("Expected tenant_name for occurrence %s", occ.ID)
+		}
+		names[occ.TenantID] = *occ.TenantName
+		if occ.HospitalNome == "" {
+			t.Errorf("Expected hospital_nome for occurrence %s", occ.ID)
+		}
+	}
+	if names[tenantA] != nameA || names[tenantB] != nameB {
+		t.Errorf("Expected occurrences from both tenants with their names, got %v", names)
+	}
+
+	if searcher.filters == nil || searcher.filters.TenantID != nil {
+		t.Errorf("Expected no tenant filter, got %+v", searcher.filters)
+	}
+	if searcher.filters.ScoreMin == nil || *searcher.filters.ScoreMin != 50 || searcher.filters.PageSize != 10 {
+		t.Errorf("Expected score_min=50 and page_size=10, got %+v", searcher.filters)
+	}
+}
+
+// TestAdminSearchOccurrencesInvalidFilters tests that invalid admin filters are rejected
+func TestAdminSearchOccurrencesInvalidFilters(t *testing.T) {
+	withAdminOccurrenceSearcher(t, &mockAdminOccurrenceSearcher{})
+
+	router := setupTestRouter()
+	router.Use(mockAuthMiddleware(uuid.New().String(), "admin"))
+	router.GET("/api/v1/admin/occurrences", AdminSearchOccurrences)
+
+	for _, query := range []string{
+		"tenant_id=not-a-uuid",
+		"hospital_id=not-a-uuid",
+		"score_min=120",
+		"score_min=80&score_max=20",
+		"sort=nome_paciente",
+		"sort=score:up",
+		"sort=created_at%3BDROP+TABLE+occurrences",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/occurrences?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+// TestAdminSearchOccurrencesSort tests that the sort options map to their columns, defaulting to score descending
+func TestAdminSearchOccurrencesSort(t *testing.T) {
+	searcher := &mockAdminOccurrenceSearcher{}
+	withAdminOccurrenceSearcher(t, searcher)
+
+	router := setupTestRouter()
+	router.Use(mockAuthMiddleware(uuid.New().String(), "admin"))
+	router.GET("/api/v1/admin/occurrences", AdminSearchOccurrences)
+
+	tests := []struct {
+		query     string
+		sortBy    string
+		sortOrder string
+	}{
+		{"", "score_priorizacao", "desc"},
+		{"sort=tempo_restante:asc", "janela_expira_em", "asc"},
+		{"sort=data_obito", "data_obito", "desc"},
+		{"sort=created_at&sort_order=asc", "created_at", "asc"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/occurrences?"+tt.query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status %d, got %d: %s", tt.query, http.StatusOK, w.Code, w.Body.String())
+		}
+		if searcher.filters.SortBy != tt.sortBy || searcher.filters.SortOrder != tt.sortOrder {
+			t.Errorf("%s: expected %s %s, got %s %s", tt.query, tt.sortBy, tt.sortOrder, searcher.filters.SortBy, searcher.filters.SortOrder)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/sidot/backend/internal/models"
 	"github.com/sidot/backend/internal/repository"
 	"github.com/sidot/backend/internal/services/audit"
+	"github.com/sidot/backend/internal/services/auth"
 )
 
 var adminSettingsRepo *repository.AdminSettingsRepository
@@ -111,6 +112,135 @@ func AdminUpsertSetting(c *gin.Context) {
 		return
 	}
 
+	// Password policy must be well-formed before it is stored
+	var passwordPolicy *models.PasswordPolicyConfig
+	if key == models.SettingKeyPasswordPolicy {
+		probe := models.SystemSetting{Value: input.Value}
+		policy, err := probe.GetPasswordPolicyConfig()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid password policy",
+				"details": err.Error(),
+			})
+			return
+		}
+		passwordPolicy = policy
+	}
+
+	// Impersonation policy must be well-formed before it is stored
+	var impersonationPolicy *models.ImpersonationPolicyConfig
+	if key == models.SettingKeyImpersonationPolicy {
+		probe := models.SystemSetting{Value: input.Value}
+		policy, err := probe.GetImpersonationPolicyConfig()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid impersonation policy",
+				"details": err.Error(),
+			})
+			return
+		}
+		impersonationPolicy = policy
+	}
+
+	// MFA policy must be well-formed, and MFA available, before roles are required to use it
+	var mfaPolicy *models.MFAPolicyConfig
+	if key == models.SettingKeyMFAPolicy {
+		probe := models.SystemSetting{Value: input.Value}
+		policy, err := probe.GetMFAPolicyConfig()
+		if err != nil {
+			respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid mfa policy", err.Error())
+			return
+		}
+		if len(policy.RequiredRoles) > 0 && (globalAuthHandler == nil || !globalAuthHandler.authService.MFAAvailable()) {
+			respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid mfa policy", auth.ErrMFAUnavailable.Error())
+			return
+		}
+		mfaPolicy = policy
+	}
+
+	// Email verification can only be enabled when the verification emails can be sent
+	var emailVerification *models.EmailVerificationConfig
+	if key == models.SettingKeyEmailVerification {
+		probe := models.SystemSetting{Value: input.Value}
+		config, err := probe.GetEmailVerificationConfig()
+		if err != nil {
+			respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid email verification", err.Error())
+			return
+		}
+		if config.Enabled && (globalAuthHandler == nil || !globalAuthHandler.authService.EmailVerificationAvailable()) {
+			respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid email verification", auth.ErrEmailVerificationUnavailable.Error())
+			return
+		}
+		emailVerification = config
+	}
+
+	// Token durations must be well-formed before they are stored
+	var tokenDurations *models.TokenDurationsConfig
+	if key == models.SettingKeyTokenDurations {
+		probe := models.SystemSetting{Value: input.Value}
+		config, err := probe.GetTokenDurationsConfig()
+		if err != nil {
+			respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid token durations", err.Error())
+			return
+		}
+		tokenDurations = config
+	}
+
+	// Latency thresholds must be well-formed before they are stored
+	var latencyThresholds models.HealthLatencyThresholdsConfig
+	if key == models.SettingKeyHealthLatencyThresholds {
+		probe := models.SystemSetting{Value: input.Value}
+		thresholds, err := probe.GetHealthLatencyThresholdsConfig()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid health latency thresholds",
+				"details": err.Error(),
+			})
+			return
+		}
+		latencyThresholds = thresholds
+	}
+
+	// Auto-assignment policy must be well-formed before it is stored (it is read per occurrence)
+	if key == models.SettingKeyOccurrenceAutoAssign {
+		probe := models.SystemSetting{Value: input.Value}
+		if _, err := probe.GetOccurrenceAutoAssignConfig(); err != nil {
+			respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid occurrence auto-assignment policy", err.Error())
+			return
+		}
+	}
+
+	// Urgency thresholds must be well-formed before they are stored (they are read per map request)
+	if key == models.SettingKeyUrgencyThresholds {
+		probe := models.SystemSetting{Value: input.Value}
+		if _, err := probe.GetUrgencyThresholdsConfig(); err != nil {
+			respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid urgency thresholds", err.Error())
+			return
+		}
+	}
+
+	// Occurrence data encryption can only be enabled when an encryption key is configured
+	var dataEncryption *models.OccurrenceDataEncryptionConfig
+	if key == models.SettingKeyOccurrenceDataEncryption {
+		probe := models.SystemSetting{Value: input.Value}
+		config, err := probe.GetOccurrenceDataEncryptionConfig()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid occurrence data encryption setting",
+				"details": err.Error(),
+			})
+			return
+		}
+		if config.Enabled && !repository.OccurrenceDataEncryptionAvailable() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid occurrence data encryption setting",
+				"details": repository.ErrOccurrenceDataEncryptionUnavailable.Error(),
+			})
+			return
+		}
+		dataEncryption = config
+	}
+
 	// Check if this is a create or update for audit logging
 	isCreate := false
 	_, err := adminSettingsRepo.GetSettingByKey(c.Request.Context(), key)
@@ -127,6 +257,41 @@ func AdminUpsertSetting(c *gin.Context) {
 		return
 	}
 
+	// Apply the new password policy without requiring a restart
+	if passwordPolicy != nil {
+		auth.SetActivePasswordPolicy(*passwordPolicy)
+	}
+
+	// Apply the impersonation policy to new impersonation tokens (durations above the maximum are clamped)
+	if impersonationPolicy != nil {
+		auth.SetActiveImpersonationPolicy(*impersonationPolicy)
+	}
+
+	// Apply the MFA policy from the next login
+	if mfaPolicy != nil {
+		auth.SetActiveMFAPolicy(*mfaPolicy)
+	}
+
+	// Apply the email verification to the users created and the logins from now on
+	if emailVerification != nil {
+		auth.SetActiveEmailVerificationPolicy(*emailVerification)
+	}
+
+	// Apply the token durations to new tokens (tokens already issued keep their expiration)
+	if tokenDurations != nil && globalAuthHandler != nil {
+		globalAuthHandler.authService.SetTokenDurations(*tokenDurations)
+	}
+
+	// Apply latency thresholds from the next health check
+	if latencyThresholds != nil && globalHealthMonitor != nil {
+		globalHealthMonitor.SetLatencyThresholds(latencyThresholds)
+	}
+
+	// Apply occurrence data encryption to new occurrences without requiring a restart
+	if dataEncryption != nil {
+		repository.SetOccurrenceDataEncryptionEnabled(dataEncryption.Enabled)
+	}
+
 	// Log audit event
 	if auditService != nil {
 		userID, actorName := audit.GetUserInfoFromContext(c)
@@ -200,6 +365,41 @@ func AdminDeleteSetting(c *gin.Context) {
 		return
 	}
 
+	// Removing the password policy restores the default rules
+	if key == models.SettingKeyPasswordPolicy {
+		auth.SetActivePasswordPolicy(models.DefaultPasswordPolicyConfig())
+	}
+
+	// Removing the impersonation policy restores the default duration
+	if key == models.SettingKeyImpersonationPolicy {
+		auth.SetActiveImpersonationPolicy(models.DefaultImpersonationPolicyConfig())
+	}
+
+	// Removing the MFA policy stops requiring MFA for any role (users that enabled it keep it)
+	if key == models.SettingKeyMFAPolicy {
+		auth.SetActiveMFAPolicy(models.MFAPolicyConfig{})
+	}
+
+	// Removing the email verification stops requiring it (unverified users can sign in again)
+	if key == models.SettingKeyEmailVerification {
+		auth.SetActiveEmailVerificationPolicy(models.EmailVerificationConfig{})
+	}
+
+	// Removing the token durations restores the global durations for every role
+	if key == models.SettingKeyTokenDurations && globalAuthHandler != nil {
+		globalAuthHandler.authService.SetTokenDurations(models.TokenDurationsConfig{})
+	}
+
+	// Removing the latency thresholds restores the default thresholds for every component
+	if key == models.SettingKeyHealthLatencyThresholds && globalHealthMonitor != nil {
+		globalHealthMonitor.SetLatencyThresholds(nil)
+	}
+
+	// Removing the encryption setting stops encrypting new occurrences (encrypted rows stay readable)
+	if key == models.SettingKeyOccurrenceDataEncryption {
+		repository.SetOccurrenceDataEncryptionEnabled(false)
+	}
+
 	// Log audit event
 	if auditService != nil {
 		userID, actorName := audit.GetUserInfoFromContext(c)
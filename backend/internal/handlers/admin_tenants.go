@@ -2,18 +2,19 @@ package handlers
 
 import (
 	"errors"
-	"io"
+	"fmt"
+	"log"
 	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
 	"github.com/sidot/backend/internal/models"
 	"github.com/sidot/backend/internal/repository"
+	"github.com/sidot/backend/internal/services/audit"
 )
 
 var adminTenantRepo *repository.AdminTenantRepository
@@ -27,22 +28,19 @@ func SetAdminTenantRepository(repo *repository.AdminTenantRepository) {
 // GET /api/v1/admin/tenants
 func AdminListTenants(c *gin.Context) {
 	if adminTenantRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin tenant repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin tenant repository not configured")
 		return
 	}
 
 	var params repository.AdminTenantListParams
 	if err := c.ShouldBindQuery(&params); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid query parameters",
-			"details": err.Error(),
-		})
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid query parameters", err.Error())
 		return
 	}
 
 	result, err := adminTenantRepo.ListAllTenants(c.Request.Context(), &params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list tenants"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to list tenants")
 		return
 	}
 
@@ -58,6 +56,7 @@ func AdminListTenants(c *gin.Context) {
 		"page":        result.Page,
 		"per_page":    result.PerPage,
 		"total_pages": result.TotalPages,
+		"links":       models.NewPaginationLinks(c.Request.URL, "per_page", result.Page, result.PerPage, result.TotalPages),
 	})
 }
 
@@ -65,24 +64,20 @@ func AdminListTenants(c *gin.Context) {
 // GET /api/v1/admin/tenants/:id
 func AdminGetTenant(c *gin.Context) {
 	if adminTenantRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin tenant repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin tenant repository not configured")
 		return
 	}
 
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tenant ID format"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid tenant ID format")
 		return
 	}
 
 	tenant, err := adminTenantRepo.GetTenantByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, repository.ErrAdminTenantNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "tenant not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get tenant"})
+		respondDomainError(c, err, "failed to get tenant")
 		return
 	}
 
@@ -93,40 +88,26 @@ func AdminGetTenant(c *gin.Context) {
 // POST /api/v1/admin/tenants
 func AdminCreateTenant(c *gin.Context) {
 	if adminTenantRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin tenant repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin tenant repository not configured")
 		return
 	}
 
 	var input models.CreateTenantInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "invalid request body", err.Error())
 		return
 	}
 
 	// Validate input
 	validate := validator.New()
 	if err := validate.Struct(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation failed",
-			"details": err.Error(),
-		})
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeValidationFailed, "validation failed", err.Error())
 		return
 	}
 
 	tenant, err := adminTenantRepo.CreateTenant(c.Request.Context(), &input)
 	if err != nil {
-		if errors.Is(err, repository.ErrAdminTenantSlugExists) {
-			c.JSON(http.StatusConflict, gin.H{"error": "tenant with this slug already exists"})
-			return
-		}
-		if errors.Is(err, models.ErrInvalidTenantSlug) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create tenant"})
+		respondDomainError(c, err, "failed to create tenant")
 		return
 	}
 
@@ -137,119 +118,300 @@ func AdminCreateTenant(c *gin.Context) {
 // PUT /api/v1/admin/tenants/:id
 func AdminUpdateTenant(c *gin.Context) {
 	if adminTenantRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin tenant repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin tenant repository not configured")
 		return
 	}
 
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tenant ID format"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid tenant ID format")
 		return
 	}
 
 	var input models.UpdateTenantInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "invalid request body", err.Error())
 		return
 	}
 
 	// Validate input
 	validate := validator.New()
 	if err := validate.Struct(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation failed",
-			"details": err.Error(),
-		})
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeValidationFailed, "validation failed", err.Error())
+		return
+	}
+
+	existingTenant, err := adminTenantRepo.GetTenantByID(c.Request.Context(), id)
+	if err != nil {
+		respondDomainError(c, err, "failed to get tenant")
 		return
 	}
 
 	tenant, err := adminTenantRepo.UpdateTenant(c.Request.Context(), id, &input)
 	if err != nil {
-		if errors.Is(err, repository.ErrAdminTenantNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "tenant not found"})
-			return
-		}
-		if errors.Is(err, repository.ErrAdminTenantSlugExists) {
-			c.JSON(http.StatusConflict, gin.H{"error": "tenant with this slug already exists"})
-			return
-		}
-		if errors.Is(err, models.ErrInvalidTenantSlug) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update tenant"})
+		respondDomainError(c, err, "failed to update tenant")
 		return
 	}
 
+	// Log audit event
+	if auditService != nil {
+		userID, actorName := audit.GetUserInfoFromContext(c)
+		ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+		auditService.LogEventWithUser(
+			c.Request.Context(),
+			userID,
+			actorName,
+			models.ActionTenantUpdate,
+			models.EntityTypeTenant,
+			id.String(),
+			nil,
+			models.SeverityInfo,
+			map[string]interface{}{
+				"tenant_nome": existingTenant.Name,
+				"alteracoes":  audit.Diff(&existingTenant.Tenant, tenant),
+			},
+			ipAddress,
+			userAgent,
+		)
+	}
+
 	c.JSON(http.StatusOK, tenant.ToResponse())
 }
 
+// AdminExportTenant streams all data of a tenant as a zip archive of JSON files (LGPD data portability)
+// GET /api/v1/admin/tenants/:id/export
+func AdminExportTenant(c *gin.Context) {
+	if adminTenantRepo == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin tenant repository not configured")
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid tenant ID format")
+		return
+	}
+
+	tenant, err := adminTenantRepo.GetTenantByID(c.Request.Context(), id)
+	if err != nil {
+		respondDomainError(c, err, "failed to get tenant")
+		return
+	}
+
+	// Log audit event before streaming, since the response cannot report later failures
+	if auditService != nil {
+		userID, actorName := audit.GetUserInfoFromContext(c)
+		ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+		auditService.LogEventWithUser(
+			c.Request.Context(),
+			userID,
+			actorName,
+			models.ActionTenantExport,
+			models.EntityTypeTenant,
+			id.String(),
+			nil,
+			models.SeverityWarn,
+			map[string]interface{}{
+				"tenant_nome": tenant.Name,
+			},
+			ipAddress,
+			userAgent,
+		)
+	}
+
+	filename := fmt.Sprintf("tenant_%s_%s.zip", tenant.Slug, time.Now().Format("20060102_150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Status(http.StatusOK)
+
+	if err := adminTenantRepo.ExportTenantData(c.Request.Context(), id, c.Writer); err != nil {
+		// The archive is left incomplete, so the client fails to open it
+		log.Printf("[Admin] Error exporting tenant %s: %v", id, err)
+	}
+}
+
+// AdminPurgeTenant permanently deletes all data of an inactive tenant, confirmed by its slug
+// DELETE /api/v1/admin/tenants/:id/purge
+func AdminPurgeTenant(c *gin.Context) {
+	if adminTenantRepo == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin tenant repository not configured")
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid tenant ID format")
+		return
+	}
+
+	var input models.PurgeTenantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "invalid request body", err.Error())
+		return
+	}
+
+	// Validate input
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeValidationFailed, "validation failed", err.Error())
+		return
+	}
+
+	result, err := adminTenantRepo.PurgeTenantData(c.Request.Context(), id, input.ConfirmationToken)
+	if err != nil {
+		respondDomainError(c, err, "failed to purge tenant")
+		return
+	}
+
+	// Log audit event
+	if auditService != nil {
+		userID, actorName := audit.GetUserInfoFromContext(c)
+		ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+		auditService.LogEventWithUser(
+			c.Request.Context(),
+			userID,
+			actorName,
+			models.ActionTenantPurge,
+			models.EntityTypeTenant,
+			id.String(),
+			nil,
+			models.SeverityCritical,
+			map[string]interface{}{
+				"tenant_slug":         input.ConfirmationToken,
+				"registros_removidos": result.Tables,
+			},
+			ipAddress,
+			userAgent,
+		)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "tenant data purged successfully",
+		"tables":  result.Tables,
+	})
+}
+
 // AdminUpdateThemeConfig updates a tenant's theme configuration
 // PUT /api/v1/admin/tenants/:id/theme
 func AdminUpdateThemeConfig(c *gin.Context) {
 	if adminTenantRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin tenant repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin tenant repository not configured")
 		return
 	}
 
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tenant ID format"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid tenant ID format")
 		return
 	}
 
 	var input models.UpdateThemeConfigInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "invalid request body", err.Error())
 		return
 	}
 
-	tenant, err := adminTenantRepo.UpdateThemeConfig(c.Request.Context(), id, input.ThemeConfig)
+	tenant, err := adminTenantRepo.UpdateThemeConfig(c.Request.Context(), id, input.ThemeConfig, themeChangeAuthor(c))
 	if err != nil {
-		if errors.Is(err, repository.ErrAdminTenantNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "tenant not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "failed to update theme config",
-			"details": err.Error(),
-		})
+		respondDomainError(c, err, "failed to update theme config")
+		return
+	}
+
+	c.JSON(http.StatusOK, tenant.ToResponse())
+}
+
+// AdminListThemeVersions returns the archived theme configs of a tenant, newest first
+// GET /api/v1/admin/tenants/:id/theme/versions
+func AdminListThemeVersions(c *gin.Context) {
+	if adminTenantRepo == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin tenant repository not configured")
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid tenant ID format")
+		return
+	}
+
+	versions, err := adminTenantRepo.ListThemeVersions(c.Request.Context(), id)
+	if err != nil {
+		respondDomainError(c, err, "failed to list theme versions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// AdminRollbackThemeConfig restores an archived theme config of a tenant
+// POST /api/v1/admin/tenants/:id/theme/rollback/:version
+func AdminRollbackThemeConfig(c *gin.Context) {
+	if adminTenantRepo == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin tenant repository not configured")
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid tenant ID format")
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid theme version")
+		return
+	}
+
+	tenant, err := adminTenantRepo.RollbackThemeConfig(c.Request.Context(), id, version, themeChangeAuthor(c))
+	if err != nil {
+		respondDomainError(c, err, "failed to roll back theme config")
 		return
 	}
 
 	c.JSON(http.StatusOK, tenant.ToResponse())
 }
 
+// themeChangeAuthor returns the ID of the authenticated user, recorded on archived theme versions
+func themeChangeAuthor(c *gin.Context) *uuid.UUID {
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		return nil
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil
+	}
+	return &userID
+}
+
 // AdminToggleTenantActive toggles a tenant's is_active status
 // PUT /api/v1/admin/tenants/:id/toggle
 func AdminToggleTenantActive(c *gin.Context) {
 	if adminTenantRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin tenant repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin tenant repository not configured")
 		return
 	}
 
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tenant ID format"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid tenant ID format")
 		return
 	}
 
 	tenant, err := adminTenantRepo.ToggleTenantActive(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, repository.ErrAdminTenantNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "tenant not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to toggle tenant status"})
+		respondDomainError(c, err, "failed to toggle tenant status")
 		return
 	}
 
@@ -264,123 +426,81 @@ func AdminToggleTenantActive(c *gin.Context) {
 // POST /api/v1/admin/tenants/:id/assets
 func AdminUploadTenantAssets(c *gin.Context) {
 	if adminTenantRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin tenant repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin tenant repository not configured")
+		return
+	}
+	if tenantAssetStorage == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "asset storage not configured")
 		return
 	}
 
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tenant ID format"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid tenant ID format")
 		return
 	}
 
 	// Verify tenant exists
 	_, err = adminTenantRepo.GetTenantByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, repository.ErrAdminTenantNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "tenant not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify tenant"})
+		respondDomainError(c, err, "failed to verify tenant")
 		return
 	}
 
 	// Parse multipart form
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, logoAssetRules.MaxBytes+faviconAssetRules.MaxBytes+(1<<20))
 	if err := c.Request.ParseMultipartForm(10 << 20); err != nil { // 10 MB max
-		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to parse form data"})
-		return
-	}
-
-	var logoURL, faviconURL *string
-
-	// Create upload directory if it doesn't exist
-	uploadDir := filepath.Join("uploads", "tenants", id.String())
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create upload directory"})
-		return
-	}
-
-	// Handle logo upload
-	logoFile, logoHeader, err := c.Request.FormFile("logo")
-	if err == nil {
-		defer logoFile.Close()
-
-		// Validate file type
-		if !isValidImageType(logoHeader.Header.Get("Content-Type")) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "logo must be an image file (PNG, JPG, SVG, WebP)"})
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondError(c, http.StatusRequestEntityTooLarge, ErrCodePayloadTooLarge, "request body too large")
 			return
 		}
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "failed to parse form data")
+		return
+	}
 
-		// Generate unique filename
-		ext := filepath.Ext(logoHeader.Filename)
-		filename := "logo_" + time.Now().Format("20060102150405") + ext
-		filePath := filepath.Join(uploadDir, filename)
-
-		// Save file
-		out, err := os.Create(filePath)
+	// Validate every upload before storing any of them
+	assets := map[string]*tenantAsset{}
+	for _, rules := range []tenantAssetRules{logoAssetRules, faviconAssetRules} {
+		file, header, err := c.Request.FormFile(rules.Name)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save logo"})
-			return
+			continue
 		}
-		defer out.Close()
-
-		if _, err := io.Copy(out, logoFile); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to write logo file"})
+		asset, err := readTenantAsset(file, header.Size, rules)
+		file.Close()
+		if err != nil {
+			respondDomainError(c, err, "failed to read uploaded asset")
 			return
 		}
-
-		url := "/" + filePath
-		logoURL = &url
+		assets[rules.Name] = asset
 	}
 
-	// Handle favicon upload
-	faviconFile, faviconHeader, err := c.Request.FormFile("favicon")
-	if err == nil {
-		defer faviconFile.Close()
-
-		// Validate file type
-		if !isValidFaviconType(faviconHeader.Header.Get("Content-Type")) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "favicon must be an image file (PNG, ICO, SVG)"})
-			return
-		}
-
-		// Generate unique filename
-		ext := filepath.Ext(faviconHeader.Filename)
-		filename := "favicon_" + time.Now().Format("20060102150405") + ext
-		filePath := filepath.Join(uploadDir, filename)
-
-		// Save file
-		out, err := os.Create(filePath)
+	var logoURL, faviconURL *string
+	for field, asset := range assets {
+		key := fmt.Sprintf("tenants/%s/%s_%s%s", id, field, time.Now().Format("20060102150405"), asset.Extension)
+		url, err := tenantAssetStorage.Put(c.Request.Context(), key, asset.Data, asset.ContentType)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save favicon"})
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to store "+field)
 			return
 		}
-		defer out.Close()
-
-		if _, err := io.Copy(out, faviconFile); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to write favicon file"})
-			return
+		if field == "logo" {
+			logoURL = &url
+		} else {
+			faviconURL = &url
 		}
-
-		url := "/" + filePath
-		faviconURL = &url
 	}
 
 	// Check if at least one file was uploaded
 	if logoURL == nil && faviconURL == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "no files uploaded; provide 'logo' and/or 'favicon' files"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "no files uploaded; provide 'logo' and/or 'favicon' files")
 		return
 	}
 
 	// Update tenant with new asset URLs
 	tenant, err := adminTenantRepo.UpdateAssets(c.Request.Context(), id, logoURL, faviconURL)
 	if err != nil {
-		if errors.Is(err, repository.ErrAdminTenantNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "tenant not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update tenant assets"})
+		respondDomainError(c, err, "failed to update tenant assets")
 		return
 	}
 
@@ -390,53 +510,17 @@ func AdminUploadTenantAssets(c *gin.Context) {
 	})
 }
 
-// isValidImageType checks if the content type is a valid image type for logo
-func isValidImageType(contentType string) bool {
-	validTypes := []string{
-		"image/png",
-		"image/jpeg",
-		"image/jpg",
-		"image/svg+xml",
-		"image/webp",
-	}
-	ct := strings.ToLower(contentType)
-	for _, vt := range validTypes {
-		if ct == vt {
-			return true
-		}
-	}
-	return false
-}
-
-// isValidFaviconType checks if the content type is a valid favicon type
-func isValidFaviconType(contentType string) bool {
-	validTypes := []string{
-		"image/png",
-		"image/x-icon",
-		"image/vnd.microsoft.icon",
-		"image/svg+xml",
-		"image/ico",
-	}
-	ct := strings.ToLower(contentType)
-	for _, vt := range validTypes {
-		if ct == vt {
-			return true
-		}
-	}
-	return false
-}
-
 // AdminDashboardMetrics returns global metrics for the admin dashboard
 // GET /api/v1/admin/metrics
 func AdminDashboardMetrics(c *gin.Context) {
 	if adminTenantRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin tenant repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "admin tenant repository not configured")
 		return
 	}
 
 	metrics, err := adminTenantRepo.GetDashboardMetrics(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get dashboard metrics"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to get dashboard metrics")
 		return
 	}
 
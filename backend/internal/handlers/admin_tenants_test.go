@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -272,6 +274,85 @@ func TestThemeConfigValidation(t *testing.T) {
 	}
 }
 
+// TestThemeConfigValidationLimits tests that oversized or malformed theme configs are rejected
+func TestThemeConfigValidationLimits(t *testing.T) {
+	base := func() models.ThemeConfig {
+		config := models.DefaultThemeConfig()
+		config.Layout.Sidebar = []models.SidebarItem{{Label: "Dashboard", Icon: "LayoutDashboard", Link: "/dashboard"}}
+		return config
+	}
+
+	oversizedSidebar := base()
+	oversizedSidebar.Layout.Sidebar = make([]models.SidebarItem, models.MaxThemeSidebarItems+1)
+	for i := range oversizedSidebar.Layout.Sidebar {
+		oversizedSidebar.Layout.Sidebar[i] = models.SidebarItem{Label: "Item", Icon: "Home", Link: "/item"}
+	}
+
+	oversizedWidgets := base()
+	oversizedWidgets.Layout.DashboardWidgets = make([]models.DashboardWidget, models.MaxThemeDashboardWidgets+1)
+	for i := range oversizedWidgets.Layout.DashboardWidgets {
+		oversizedWidgets.Layout.DashboardWidgets[i] = models.DashboardWidget{Type: "stats_card", Visible: true, Order: i}
+	}
+
+	longLabel := base()
+	longLabel.Layout.Sidebar[0].Label = strings.Repeat("a", models.MaxThemeLabelLength+1)
+
+	badIcon := base()
+	badIcon.Layout.Sidebar[0].Icon = "<script>"
+
+	largeWidgetConfig := base()
+	largeWidgetConfig.Layout.DashboardWidgets = []models.DashboardWidget{
+		{Type: "stats_card", Config: map[string]interface{}{"blob": strings.Repeat("x", models.MaxThemeWidgetConfigBytes)}},
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*models.ThemeConfig)
+		config *models.ThemeConfig
+	}{
+		{name: "Oversized sidebar", config: &oversizedSidebar},
+		{name: "Oversized dashboard widgets", config: &oversizedWidgets},
+		{name: "Label too long", config: &longLabel},
+		{name: "Icon with invalid characters", config: &badIcon},
+		{name: "Widget config too large", config: &largeWidgetConfig},
+		{name: "Primary color without hash", mutate: func(c *models.ThemeConfig) { c.Theme.Colors.Primary = "2563eb" }},
+		{name: "Primary color named", mutate: func(c *models.ThemeConfig) { c.Theme.Colors.Primary = "blue" }},
+		{name: "Secondary color with invalid digits", mutate: func(c *models.ThemeConfig) { c.Theme.Colors.Secondary = "#GGGGGG" }},
+		{name: "Accent color wrong length", mutate: func(c *models.ThemeConfig) { c.Theme.Colors.Accent = "#12345" }},
+		{name: "Background color injection", mutate: func(c *models.ThemeConfig) { c.Theme.Colors.Background = "#fff; color: red" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			if config == nil {
+				c := base()
+				tt.mutate(&c)
+				config = &c
+			}
+
+			err := models.ValidateThemeConfig(config)
+			if !errors.Is(err, models.ErrInvalidThemeConfig) {
+				t.Errorf("ValidateThemeConfig() error = %v, want ErrInvalidThemeConfig", err)
+			}
+		})
+	}
+
+	t.Run("Limits and short hex colors are accepted", func(t *testing.T) {
+		config := base()
+		config.Theme.Colors.Primary = "#FFF"
+		config.Theme.Colors.Secondary = "#33C1FF"
+		config.Layout.Sidebar = make([]models.SidebarItem, models.MaxThemeSidebarItems)
+		for i := range config.Layout.Sidebar {
+			config.Layout.Sidebar[i] = models.SidebarItem{Label: strings.Repeat("a", models.MaxThemeLabelLength), Icon: "file-text", Link: "/item"}
+		}
+
+		if err := models.ValidateThemeConfig(&config); err != nil {
+			t.Errorf("ValidateThemeConfig() error = %v, want nil", err)
+		}
+	})
+}
+
 // TestDefaultThemeConfig tests the default theme config generation
 func TestDefaultThemeConfig(t *testing.T) {
 	config := models.DefaultThemeConfig()
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -14,9 +15,22 @@ import (
 
 var adminTriagemRepo *repository.AdminTriagemTemplateRepository
 
+// triagemTemplateCloner clones triagem templates into tenant rules
+// (implemented by repository.AdminTriagemTemplateRepository)
+type triagemTemplateCloner interface {
+	CloneToTenant(ctx context.Context, templateID uuid.UUID, tenantIDs []uuid.UUID) (*repository.CloneResult, error)
+}
+
+var adminTriagemCloner triagemTemplateCloner
+
 // SetAdminTriagemTemplateRepository sets the admin triagem template repository for handlers
 func SetAdminTriagemTemplateRepository(repo *repository.AdminTriagemTemplateRepository) {
 	adminTriagemRepo = repo
+	if repo == nil {
+		adminTriagemCloner = nil
+		return
+	}
+	adminTriagemCloner = repo
 }
 
 // AdminListTriagemTemplates returns all triagem rule templates with pagination
@@ -234,9 +248,10 @@ func AdminUpdateTriagemTemplate(c *gin.Context) {
 }
 
 // AdminCloneTriagemTemplate clones a template to one or more tenants
+// Tenants are cloned independently and the response reports the outcome for each one
 // POST /api/v1/admin/triagem-templates/:id/clone
 func AdminCloneTriagemTemplate(c *gin.Context) {
-	if adminTriagemRepo == nil {
+	if adminTriagemCloner == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "admin triagem template repository not configured"})
 		return
 	}
@@ -266,7 +281,7 @@ func AdminCloneTriagemTemplate(c *gin.Context) {
 		return
 	}
 
-	result, err := adminTriagemRepo.CloneToTenant(c.Request.Context(), id, input.TenantIDs)
+	result, err := adminTriagemCloner.CloneToTenant(c.Request.Context(), id, input.TenantIDs)
 	if err != nil {
 		if errors.Is(err, repository.ErrAdminTriagemTemplateNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "triagem template not found"})
@@ -279,40 +294,49 @@ func AdminCloneTriagemTemplate(c *gin.Context) {
 		return
 	}
 
-	// Log audit event
+	// Log one audit event per tenant that received the template
 	if auditService != nil {
 		userID, actorName := audit.GetUserInfoFromContext(c)
 		ipAddress, userAgent := audit.ExtractRequestInfo(c)
 
-		tenantIDStrs := make([]string, 0, len(result.ClonedToTenants))
-		for _, tid := range result.ClonedToTenants {
-			tenantIDStrs = append(tenantIDStrs, tid.String())
+		for _, tenantResult := range result.Results {
+			if !tenantResult.Success {
+				continue
+			}
+			auditService.LogEventWithUser(
+				c.Request.Context(),
+				userID,
+				actorName,
+				"admin.triagem_template.clone",
+				"TriagemRuleTemplate",
+				id.String(),
+				nil,
+				models.SeverityInfo,
+				map[string]interface{}{
+					"tenant_id": tenantResult.TenantID.String(),
+					"rule_id":   tenantResult.RuleID.String(),
+				},
+				ipAddress,
+				userAgent,
+			)
 		}
+	}
 
-		auditService.LogEventWithUser(
-			c.Request.Context(),
-			userID,
-			actorName,
-			"admin.triagem_template.clone",
-			"TriagemRuleTemplate",
-			id.String(),
-			nil,
-			models.SeverityInfo,
-			map[string]interface{}{
-				"cloned_to_tenants": tenantIDStrs,
-				"success_count":     result.SuccessCount,
-			},
-			ipAddress,
-			userAgent,
-		)
+	message := "template cloned successfully"
+	if result.FailureCount > 0 && result.SuccessCount > 0 {
+		message = "template cloned to some tenants"
+	} else if result.FailureCount > 0 {
+		message = "template could not be cloned to any tenant"
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":           "template cloned successfully",
+		"message":           message,
 		"template_id":       result.TemplateID,
 		"cloned_to_tenants": result.ClonedToTenants,
 		"success_count":     result.SuccessCount,
 		"failed_tenants":    result.FailedTenants,
+		"failure_count":     result.FailureCount,
+		"results":           result.Results,
 	})
 }
 
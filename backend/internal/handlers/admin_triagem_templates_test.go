@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// mockTriagemTemplateCloner fails for the configured tenants and succeeds for the rest
+type mockTriagemTemplateCloner struct {
+	failures map[uuid.UUID]string
+}
+
+func (m *mockTriagemTemplateCloner) CloneToTenant(ctx context.Context, templateID uuid.UUID, tenantIDs []uuid.UUID) (*repository.CloneResult, error) {
+	result := &repository.CloneResult{TemplateID: templateID}
+	for _, tenantID := range tenantIDs {
+		if reason, ok := m.failures[tenantID]; ok {
+			result.FailedTenants = append(result.FailedTenants, tenantID)
+			result.FailureCount++
+			result.Results = append(result.Results, repository.CloneTenantResult{TenantID: tenantID, Error: reason})
+			continue
+		}
+		ruleID := uuid.New()
+		result.ClonedToTenants = append(result.ClonedToTenants, tenantID)
+		result.SuccessCount++
+		result.Results = append(result.Results, repository.CloneTenantResult{TenantID: tenantID, Success: true, RuleID: &ruleID})
+	}
+	return result, nil
+}
+
+// TestAdminCloneTriagemTemplatePartialFailure tests that the response reports each tenant when one of them fails
+func TestAdminCloneTriagemTemplatePartialFailure(t *testing.T) {
+	ok1, failing, ok2 := uuid.New(), uuid.New(), uuid.New()
+
+	previous := adminTriagemCloner
+	adminTriagemCloner = &mockTriagemTemplateCloner{failures: map[uuid.UUID]string{failing: "tenant not found"}}
+	t.Cleanup(func() { adminTriagemCloner = previous })
+
+	router := setupTestRouter()
+	router.Use(mockAuthMiddleware(uuid.New().String(), "admin"))
+	router.POST("/api/v1/admin/triagem-templates/:id/clone", AdminCloneTriagemTemplate)
+
+	body, _ := json.Marshal(map[string]interface{}{"tenant_ids": []uuid.UUID{ok1, failing, ok2}})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/triagem-templates/"+uuid.New().String()+"/clone", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Message      string                         `json:"message"`
+		SuccessCount int                            `json:"success_count"`
+		FailureCount int                            `json:"failure_count"`
+		Results      []repository.CloneTenantResult `json:"results"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if response.SuccessCount != 2 || response.FailureCount != 1 || response.Message != "template cloned to some tenants" {
+		t.Errorf("Unexpected summary: %+v", response)
+	}
+	if len(response.Results) != 3 {
+		t.Fatalf("Expected 3 per-tenant results, got %d", len(response.Results))
+	}
+	for _, r := range response.Results {
+		if r.TenantID == failing {
+			if r.Success || r.Error != "tenant not found" {
+				t.Errorf("Expected failing tenant to report its error, got %+v", r)
+			}
+		} else if !r.Success || r.RuleID == nil {
+			t.Errorf("Expected tenant %s to succeed, got %+v", r.TenantID, r)
+		}
+	}
+}
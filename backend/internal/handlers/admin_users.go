@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -76,6 +78,7 @@ func AdminListUsers(c *gin.Context) {
 		"page":        result.Page,
 		"per_page":    result.PerPage,
 		"total_pages": result.TotalPages,
+		"links":       models.NewPaginationLinks(c.Request.URL, "per_page", result.Page, result.PerPage, result.TotalPages),
 	})
 }
 
@@ -141,6 +144,26 @@ func AdminImpersonateUser(c *gin.Context) {
 		return
 	}
 
+	// A reason is required and recorded in the audit log
+	var input models.AdminImpersonateUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	input.Reason = strings.TrimSpace(input.Reason)
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	// Extract request info for audit
 	ipAddress, userAgent := audit.ExtractRequestInfo(c)
 
@@ -149,10 +172,16 @@ func AdminImpersonateUser(c *gin.Context) {
 		c.Request.Context(),
 		adminUserID,
 		targetUserID,
+		input.Reason,
+		time.Duration(input.DurationMinutes)*time.Minute,
 		ipAddress,
 		userAgent,
 	)
 	if err != nil {
+		if errors.Is(err, auth.ErrImpersonationReasonRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "validation failed", "details": err.Error()})
+			return
+		}
 		if errors.Is(err, auth.ErrUserNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "target user not found"})
 			return
@@ -164,6 +193,7 @@ func AdminImpersonateUser(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message":      "impersonation token generated",
 		"access_token": result.AccessToken,
+		"jti":          result.TokenID,
 		"expires_at":   result.ExpiresAt,
 		"expires_in":   result.ExpiresIn,
 		"user": gin.H{
@@ -175,6 +205,70 @@ func AdminImpersonateUser(c *gin.Context) {
 	})
 }
 
+// AdminListImpersonations returns the active impersonation sessions
+// GET /api/v1/admin/impersonations
+func AdminListImpersonations(c *gin.Context) {
+	if impersonateService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "impersonation service not configured"})
+		return
+	}
+
+	sessions, err := impersonateService.ListActiveSessions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list impersonation sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  sessions,
+		"total": len(sessions),
+	})
+}
+
+// AdminRevokeImpersonation ends an impersonation session immediately
+// DELETE /api/v1/admin/impersonations/:jti
+func AdminRevokeImpersonation(c *gin.Context) {
+	if impersonateService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "impersonation service not configured"})
+		return
+	}
+
+	tokenID := c.Param("jti")
+	if _, err := uuid.Parse(tokenID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid jti format"})
+		return
+	}
+
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	adminUserID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid admin user ID"})
+		return
+	}
+
+	ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+	session, err := impersonateService.RevokeSession(c.Request.Context(), adminUserID, tokenID, ipAddress, userAgent)
+	if err != nil {
+		if errors.Is(err, auth.ErrImpersonationSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "impersonation session not found or already ended"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke impersonation session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "impersonation session revoked",
+		"session": session,
+	})
+}
+
 // AdminUpdateUserRole updates a user's role and/or super admin status
 // PUT /api/v1/admin/users/:id/role
 func AdminUpdateUserRole(c *gin.Context) {
@@ -239,14 +333,7 @@ func AdminUpdateUserRole(c *gin.Context) {
 		detalhes := map[string]interface{}{
 			"target_user_id":    id.String(),
 			"target_user_email": existingUser.Email,
-		}
-		if input.Role != nil {
-			detalhes["role_anterior"] = existingUser.Role
-			detalhes["role_novo"] = *input.Role
-		}
-		if input.IsSuperAdmin != nil {
-			detalhes["is_super_admin_anterior"] = existingUser.IsSuperAdmin
-			detalhes["is_super_admin_novo"] = *input.IsSuperAdmin
+			"alteracoes":        audit.Diff(existingUser, user),
 		}
 
 		auditService.LogEventWithUser(
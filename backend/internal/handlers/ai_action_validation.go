@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/integration"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// aiActionRoles mirrors the AI service permission matrix for tools that require confirmation.
+// Tools missing here are admin-only, as in the AI service.
+var aiActionRoles = map[string][]string{
+	"update_occurrence_status": {"admin", "gestor", "operador"},
+	"send_team_notification":   {"admin", "gestor"},
+	"generate_report":          {"admin", "gestor"},
+}
+
+// aiActionOccurrenceReader reads the occurrences targeted by AI actions (implemented by repository.OccurrenceRepository)
+type aiActionOccurrenceReader interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Occurrence, error)
+}
+
+var aiActionOccurrences aiActionOccurrenceReader
+
+// aiActionError is a validation failure with the HTTP status to respond with
+type aiActionError struct {
+	status  int
+	message string
+	details string
+}
+
+// validateAIAction re-checks a proposed action against the caller's current permissions and the
+// current state of its target, instead of trusting what was stored when the action was proposed
+func validateAIAction(ctx context.Context, claims *middleware.UserClaims, action *integration.PendingAction) *aiActionError {
+	if action.Status != integration.PendingActionStatus {
+		return &aiActionError{http.StatusConflict, "action is no longer pending", fmt.Sprintf("action status is %s", action.Status)}
+	}
+
+	allowedRoles, ok := aiActionRoles[action.ToolName]
+	if !ok {
+		allowedRoles = []string{"admin"}
+	}
+	if !containsRole(allowedRoles, claims.Role) {
+		return &aiActionError{http.StatusForbidden, "insufficient permissions for this action", fmt.Sprintf("role %s cannot perform %s", claims.Role, action.ToolName)}
+	}
+
+	switch action.ToolName {
+	case "update_occurrence_status":
+		return validateOccurrenceStatusAction(ctx, claims, action)
+	default:
+		return nil
+	}
+}
+
+// validateOccurrenceStatusAction checks that the occurrence still exists, is visible to the caller,
+// has not changed since the action was proposed and still allows the proposed status
+func validateOccurrenceStatusAction(ctx context.Context, claims *middleware.UserClaims, action *integration.PendingAction) *aiActionError {
+	if aiActionOccurrences == nil {
+		return &aiActionError{http.StatusInternalServerError, "occurrence repository not configured", ""}
+	}
+
+	occurrenceParam, _ := action.InputParams["occurrence_id"].(string)
+	occurrenceID, err := uuid.Parse(occurrenceParam)
+	if err != nil {
+		return &aiActionError{http.StatusBadRequest, "invalid action parameters", "occurrence_id must be a valid UUID"}
+	}
+	statusParam, _ := action.InputParams["new_status"].(string)
+	newStatus := models.OccurrenceStatus(strings.ToUpper(statusParam))
+	if !newStatus.IsValid() {
+		return &aiActionError{http.StatusBadRequest, "invalid action parameters", fmt.Sprintf("invalid status %q", statusParam)}
+	}
+
+	occurrence, err := aiActionOccurrences.GetByID(ctx, occurrenceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOccurrenceNotFound) {
+			return &aiActionError{http.StatusConflict, "occurrence no longer exists", occurrenceID.String()}
+		}
+		return &aiActionError{http.StatusInternalServerError, "failed to validate action", err.Error()}
+	}
+
+	if !canAccessOccurrence(claims, occurrence) {
+		return &aiActionError{http.StatusForbidden, "access denied to this occurrence", ""}
+	}
+
+	if occurrence.UpdatedAt.After(action.CreatedAt.Time) {
+		return &aiActionError{http.StatusConflict, "occurrence changed since the action was proposed", fmt.Sprintf("current status is %s", occurrence.Status)}
+	}
+
+	if !occurrence.Status.CanTransitionTo(newStatus) {
+		return &aiActionError{http.StatusConflict, "status transition no longer allowed", fmt.Sprintf("%s -> %s", occurrence.Status, newStatus)}
+	}
+
+	return nil
+}
+
+// revalidateAIAction loads the pending action and validates it for the caller, writing the error
+// response and returning false when the action must not be executed
+func revalidateAIAction(c *gin.Context, actionID string, opts *integration.AIRequestOptions) bool {
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return false
+	}
+
+	action, err := aiServiceClient.GetPendingAction(c.Request.Context(), actionID, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, integration.ErrActionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "action not found"})
+		case errors.Is(err, integration.ErrActionForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": "you can only confirm your own actions"})
+		default:
+			respondAIServiceError(c, err)
+		}
+		return false
+	}
+
+	if verr := validateAIAction(c.Request.Context(), claims, action); verr != nil {
+		c.JSON(verr.status, gin.H{
+			"error":   verr.message,
+			"details": verr.details,
+		})
+		return false
+	}
+
+	return true
+}
+
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
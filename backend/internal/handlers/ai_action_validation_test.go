@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/integration"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// fakeAIActionOccurrences serves occurrences from memory for AI action validation
+type fakeAIActionOccurrences map[uuid.UUID]*models.Occurrence
+
+func (f fakeAIActionOccurrences) GetByID(ctx context.Context, id uuid.UUID) (*models.Occurrence, error) {
+	if occurrence, ok := f[id]; ok {
+		return occurrence, nil
+	}
+	return nil, repository.ErrOccurrenceNotFound
+}
+
+// aiActionTestSetup holds the mock AI service for confirmation tests
+type aiActionTestSetup struct {
+	confirmForwarded bool
+	hospitalID       uuid.UUID
+	occurrenceID     uuid.UUID
+}
+
+// newAIActionTest starts a mock AI service holding one pending status change, proposed at proposedAt,
+// for an occurrence last updated at updatedAt
+func newAIActionTest(t *testing.T, proposedAt, updatedAt time.Time) *aiActionTestSetup {
+	t.Helper()
+
+	setup := &aiActionTestSetup{hospitalID: uuid.New(), occurrenceID: uuid.New()}
+
+	mockAIService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			setup.confirmForwarded = true
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "message": "Acao executada com sucesso"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"action_id":    "action-1",
+			"tool_name":    "update_occurrence_status",
+			"input_params": map[string]interface{}{"message": "iniciar a ocorrencia", "occurrence_id": setup.occurrenceID.String(), "new_status": "em_andamento"},
+			"status":       "pending",
+			// The AI service stores naive timestamps
+			"created_at": proposedAt.UTC().Format("2006-01-02T15:04:05.999999"),
+		})
+	}))
+	t.Cleanup(mockAIService.Close)
+
+	SetAIServiceClient(integration.NewAIServiceClient(&integration.AIServiceConfig{
+		BaseURL: mockAIService.URL,
+		Timeout: 5 * time.Second,
+	}))
+	t.Cleanup(func() { SetAIServiceClient(nil) })
+
+	previous := aiActionOccurrences
+	aiActionOccurrences = fakeAIActionOccurrences{
+		setup.occurrenceID: {
+			ID:         setup.occurrenceID,
+			HospitalID: setup.hospitalID,
+			Status:     models.StatusPendente,
+			UpdatedAt:  updatedAt,
+		},
+	}
+	t.Cleanup(func() { aiActionOccurrences = previous })
+
+	return setup
+}
+
+// confirm posts a confirmation as a user with the given role at the occurrence's hospital
+func (s *aiActionTestSetup) confirm(role string, confirmed bool) *httptest.ResponseRecorder {
+	router := setupAIProxyTestRouter()
+	router.POST("/api/v1/ai/confirm/:action_id", func(c *gin.Context) {
+		c.Set("user_claims", &middleware.UserClaims{
+			UserID:     uuid.New().String(),
+			Role:       role,
+			HospitalID: s.hospitalID.String(),
+		})
+		AIConfirmAction(c)
+	})
+
+	body := `{"confirmed": false}`
+	if confirmed {
+		body = `{"confirmed": true}`
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/ai/confirm/action-1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestAIConfirmActionRevalidatesBeforeExecution verifies that a current, permitted action is forwarded
+func TestAIConfirmActionRevalidatesBeforeExecution(t *testing.T) {
+	setup := newAIActionTest(t, time.Now(), time.Now().Add(-time.Hour))
+
+	w := setup.confirm("operador", true)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !setup.confirmForwarded {
+		t.Error("Expected the confirmation to be forwarded to the AI service")
+	}
+}
+
+// TestAIConfirmActionStale verifies that an action whose target changed after the proposal returns 409
+func TestAIConfirmActionStale(t *testing.T) {
+	proposedAt := time.Now().Add(-10 * time.Minute)
+	setup := newAIActionTest(t, proposedAt, proposedAt.Add(5*time.Minute))
+
+	w := setup.confirm("gestor", true)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if setup.confirmForwarded {
+		t.Error("Expected a stale action not to be executed")
+	}
+}
+
+// TestAIConfirmActionUnauthorizedRole verifies that a role without permission for the tool gets 403
+func TestAIConfirmActionUnauthorizedRole(t *testing.T) {
+	setup := newAIActionTest(t, time.Now(), time.Now().Add(-time.Hour))
+
+	w := setup.confirm("medico", true)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %d: %s", w.Code, w.Body.String())
+	}
+	if setup.confirmForwarded {
+		t.Error("Expected an unauthorized action not to be executed")
+	}
+}
+
+// TestAIConfirmActionRejectionSkipsValidation verifies that cancelling an action does not need revalidation
+func TestAIConfirmActionRejectionSkipsValidation(t *testing.T) {
+	proposedAt := time.Now().Add(-10 * time.Minute)
+	setup := newAIActionTest(t, proposedAt, proposedAt.Add(5*time.Minute))
+
+	w := setup.confirm("medico", false)
+
+	if w.Code != http.StatusOK || !setup.confirmForwarded {
+		t.Errorf("Expected the rejection to be forwarded, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// TestValidateAIAction covers the state checks of a proposed occurrence status change
+func TestValidateAIAction(t *testing.T) {
+	hospitalID, occurrenceID := uuid.New(), uuid.New()
+	proposedAt := time.Now()
+
+	previous := aiActionOccurrences
+	aiActionOccurrences = fakeAIActionOccurrences{
+		occurrenceID: {ID: occurrenceID, HospitalID: hospitalID, Status: models.StatusConcluida, UpdatedAt: proposedAt.Add(-time.Hour)},
+	}
+	defer func() { aiActionOccurrences = previous }()
+
+	claims := &middleware.UserClaims{Role: "gestor", HospitalID: hospitalID.String()}
+	action := func(status, occurrence, newStatus string) *integration.PendingAction {
+		return &integration.PendingAction{
+			ToolName:    "update_occurrence_status",
+			Status:      status,
+			InputParams: map[string]interface{}{"occurrence_id": occurrence, "new_status": newStatus},
+			CreatedAt:   integration.ServiceTime{Time: proposedAt},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		claims *middleware.UserClaims
+		action *integration.PendingAction
+		status int
+	}{
+		{"already processed", claims, action("success", occurrenceID.String(), "cancelada"), http.StatusConflict},
+		{"occurrence deleted", claims, action("pending", uuid.New().String(), "cancelada"), http.StatusConflict},
+		{"terminal status", claims, action("pending", occurrenceID.String(), "cancelada"), http.StatusConflict},
+		{"invalid parameters", claims, action("pending", "not-a-uuid", "cancelada"), http.StatusBadRequest},
+		{"other hospital", &middleware.UserClaims{Role: "gestor", HospitalID: uuid.New().String()}, action("pending", occurrenceID.String(), "cancelada"), http.StatusForbidden},
+		{"unknown tool is admin only", claims, &integration.PendingAction{ToolName: "delete_everything", Status: "pending"}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAIAction(context.Background(), tt.claims, tt.action)
+			if err == nil || err.status != tt.status {
+				t.Errorf("Expected status %d, got %+v", tt.status, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidot/backend/internal/integration"
+)
+
+// aiConversationPageSize is the page size used when walking conversations and messages for an export
+const aiConversationPageSize = 100
+
+// aiConversationRoleLabels are the speaker labels used in Markdown exports
+var aiConversationRoleLabels = map[string]string{
+	"user":      "Usuário",
+	"assistant": "Assistente",
+	"system":    "Sistema",
+}
+
+// AIConversationExport is the JSON export of a conversation
+type AIConversationExport struct {
+	SessionID  string                            `json:"session_id"`
+	ExportedAt time.Time                         `json:"exported_at"`
+	Messages   []integration.ConversationMessage `json:"messages"`
+}
+
+// AIExportConversation handles GET /api/v1/ai/conversations/:session_id/export
+// Downloads one of the authenticated user's conversations as Markdown (default) or JSON (?format=json)
+func AIExportConversation(c *gin.Context) {
+	if aiServiceClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "AI service not configured",
+		})
+		return
+	}
+
+	sessionID := c.Param("session_id")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "session_id is required",
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", "markdown")
+	if format != "markdown" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid format",
+			"details": "format must be markdown or json",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	opts := extractAuthAndTenant(c)
+
+	// The AI service scopes messages by tenant only, so ownership is checked against the user's own sessions
+	owned, err := userOwnsConversation(ctx, sessionID, opts)
+	if err != nil {
+		respondAIServiceError(c, err)
+		return
+	}
+	if !owned {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "you can only export your own conversations",
+		})
+		return
+	}
+
+	messages, err := fetchConversationMessages(ctx, sessionID, opts)
+	if err != nil {
+		respondAIServiceError(c, err)
+		return
+	}
+
+	export := &AIConversationExport{
+		SessionID:  sessionID,
+		ExportedAt: time.Now().UTC(),
+		Messages:   messages,
+	}
+
+	var (
+		data        []byte
+		contentType string
+		extension   string
+	)
+	if format == "json" {
+		data, err = json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "failed to export conversation",
+				"details": err.Error(),
+			})
+			return
+		}
+		contentType, extension = "application/json; charset=utf-8", "json"
+	} else {
+		data = []byte(renderConversationMarkdown(export))
+		contentType, extension = "text/markdown; charset=utf-8", "md"
+	}
+
+	filename := fmt.Sprintf("conversa_%s.%s", sessionID, extension)
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// userOwnsConversation reports whether the session is among the conversations the AI service lists for the user
+func userOwnsConversation(ctx context.Context, sessionID string, opts *integration.AIRequestOptions) (bool, error) {
+	for offset := 0; ; offset += aiConversationPageSize {
+		page, err := aiServiceClient.ListConversations(ctx, aiConversationPageSize, offset, opts)
+		if err != nil {
+			return false, err
+		}
+		for _, conversation := range page.Conversations {
+			if conversation.SessionID == sessionID {
+				return true, nil
+			}
+		}
+		if len(page.Conversations) < aiConversationPageSize {
+			return false, nil
+		}
+	}
+}
+
+// fetchConversationMessages reads every message of the conversation, oldest first
+func fetchConversationMessages(ctx context.Context, sessionID string, opts *integration.AIRequestOptions) ([]integration.ConversationMessage, error) {
+	messages := []integration.ConversationMessage{}
+	for offset := 0; ; offset += aiConversationPageSize {
+		page, err := aiServiceClient.GetConversationMessages(ctx, sessionID, aiConversationPageSize, offset, opts)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, page.Messages...)
+		if len(page.Messages) < aiConversationPageSize {
+			return messages, nil
+		}
+	}
+}
+
+// renderConversationMarkdown renders the conversation as a Markdown transcript
+func renderConversationMarkdown(export *AIConversationExport) string {
+	var b strings.Builder
+
+	b.WriteString("# Conversa com o assistente de IA\n\n")
+	fmt.Fprintf(&b, "- Sessão: `%s`\n", export.SessionID)
+	fmt.Fprintf(&b, "- Exportada em: %s UTC\n", export.ExportedAt.UTC().Format("02/01/2006 15:04"))
+	fmt.Fprintf(&b, "- Mensagens: %d\n", len(export.Messages))
+
+	for _, msg := range export.Messages {
+		label, ok := aiConversationRoleLabels[msg.Role]
+		if !ok {
+			label = msg.Role
+		}
+
+		b.WriteString("\n---\n\n")
+		if msg.CreatedAt.IsZero() {
+			fmt.Fprintf(&b, "### %s\n\n", label)
+		} else {
+			fmt.Fprintf(&b, "### %s · %s UTC\n\n", label, msg.CreatedAt.UTC().Format("02/01/2006 15:04"))
+		}
+		b.WriteString(strings.TrimSpace(msg.Content))
+		b.WriteString("\n")
+
+		if len(msg.ToolCalls) > 0 {
+			names := make([]string, 0, len(msg.ToolCalls))
+			for _, call := range msg.ToolCalls {
+				names = append(names, "`"+call.Name+"`")
+			}
+			fmt.Fprintf(&b, "\n_Ferramentas utilizadas: %s_\n", strings.Join(names, ", "))
+		}
+	}
+
+	return b.String()
+}
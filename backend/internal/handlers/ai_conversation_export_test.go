@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidot/backend/internal/integration"
+)
+
+const (
+	aliceSessionID = "3f0c6f5e-1111-4a1e-9c1a-000000000001"
+	bobSessionID   = "3f0c6f5e-2222-4a1e-9c1a-000000000002"
+)
+
+// conversationsMockAIService mimics the AI service conversation endpoints: sessions are listed per user
+// (identified by the bearer token) while messages are scoped by tenant only
+type conversationsMockAIService struct {
+	*httptest.Server
+	messagesRequested bool
+	lastListQuery     string
+}
+
+func newConversationsMockAIService(t *testing.T) *conversationsMockAIService {
+	t.Helper()
+
+	sessions := map[string][]map[string]interface{}{
+		"Bearer alice-token": {{"session_id": aliceSessionID, "last_message_at": "2026-10-14T12:05:00+00:00", "message_count": 2}},
+		"Bearer bob-token":   {{"session_id": bobSessionID, "last_message_at": "2026-10-14T11:00:00+00:00", "message_count": 1}},
+	}
+
+	mock := &conversationsMockAIService{}
+	mock.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/api/v1/ai/conversations" {
+			mock.lastListQuery = r.URL.RawQuery
+			userSessions := sessions[r.Header.Get("Authorization")]
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"conversations": userSessions,
+				"total":         len(userSessions),
+			})
+			return
+		}
+
+		mock.messagesRequested = true
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"session_id": strings.TrimPrefix(r.URL.Path, "/api/v1/ai/conversations/"),
+			"messages": []map[string]interface{}{
+				{"id": "m1", "role": "user", "content": "Quantas ocorrencias pendentes?", "created_at": "2026-10-14T12:00:00.123456"},
+				{"id": "m2", "role": "assistant", "content": "Existem 3 ocorrencias pendentes.", "created_at": "2026-10-14T12:05:00+00:00",
+					"tool_calls": map[string]interface{}{"calls": []map[string]interface{}{{"name": "list_occurrences", "parameters": map[string]interface{}{}}}}},
+			},
+		})
+	}))
+	t.Cleanup(mock.Close)
+
+	SetAIServiceClient(integration.NewAIServiceClient(&integration.AIServiceConfig{
+		BaseURL: mock.URL,
+		Timeout: 5 * time.Second,
+	}))
+	t.Cleanup(func() { SetAIServiceClient(nil) })
+
+	return mock
+}
+
+// serveConversationRequest performs a request against the conversation endpoints as the given user token
+func serveConversationRequest(path, token string) *httptest.ResponseRecorder {
+	router := setupAIProxyTestRouter()
+	router.GET("/api/v1/ai/conversations", AIListConversations)
+	router.GET("/api/v1/ai/conversations/:session_id/export", AIExportConversation)
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestAIExportConversationForbiddenForOtherUser verifies that a user cannot export another user's session
+func TestAIExportConversationForbiddenForOtherUser(t *testing.T) {
+	mock := newConversationsMockAIService(t)
+
+	w := serveConversationRequest("/api/v1/ai/conversations/"+aliceSessionID+"/export", "bob-token")
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %d: %s", w.Code, w.Body.String())
+	}
+	if mock.messagesRequested {
+		t.Error("Expected the messages of another user's session not to be fetched")
+	}
+}
+
+// TestAIExportConversationMarkdown verifies the default Markdown download of the user's own conversation
+func TestAIExportConversationMarkdown(t *testing.T) {
+	newConversationsMockAIService(t)
+
+	w := serveConversationRequest("/api/v1/ai/conversations/"+aliceSessionID+"/export", "alice-token")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/markdown") {
+		t.Errorf("Expected Markdown content type, got %q", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="conversa_`+aliceSessionID+`.md"` {
+		t.Errorf("Unexpected Content-Disposition %q", cd)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{
+		"- Sessão: `" + aliceSessionID + "`",
+		"### Usuário · 14/10/2026 12:00 UTC\n\nQuantas ocorrencias pendentes?",
+		"### Assistente · 14/10/2026 12:05 UTC\n\nExistem 3 ocorrencias pendentes.",
+		"_Ferramentas utilizadas: `list_occurrences`_",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected export to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+// TestAIExportConversationJSON verifies the JSON download and format validation
+func TestAIExportConversationJSON(t *testing.T) {
+	newConversationsMockAIService(t)
+
+	w := serveConversationRequest("/api/v1/ai/conversations/"+bobSessionID+"/export?format=json", "bob-token")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var export AIConversationExport
+	if err := json.Unmarshal(w.Body.Bytes(), &export); err != nil {
+		t.Fatalf("Failed to decode export: %v", err)
+	}
+	if export.SessionID != bobSessionID || len(export.Messages) != 2 || len(export.Messages[1].ToolCalls) != 1 {
+		t.Errorf("Unexpected export %+v", export)
+	}
+
+	w = serveConversationRequest("/api/v1/ai/conversations/"+bobSessionID+"/export?format=pdf", "bob-token")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unsupported format, got %d", w.Code)
+	}
+}
+
+// TestAIListConversationsPagination verifies that page and per_page are translated for the AI service
+func TestAIListConversationsPagination(t *testing.T) {
+	mock := newConversationsMockAIService(t)
+
+	w := serveConversationRequest("/api/v1/ai/conversations?page=3&per_page=10", "alice-token")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if mock.lastListQuery != "limit=11&offset=20" {
+		t.Errorf("Expected limit=11&offset=20, got %q", mock.lastListQuery)
+	}
+
+	var response struct {
+		Data []integration.ConversationSummary `json:"data"`
+		Meta gin.H                             `json:"meta"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if len(response.Data) != 1 || response.Data[0].SessionID != aliceSessionID {
+		t.Errorf("Unexpected conversations %+v", response.Data)
+	}
+	if response.Meta["page"] != float64(3) || response.Meta["per_page"] != float64(10) || response.Meta["has_more"] != false {
+		t.Errorf("Unexpected meta %+v", response.Meta)
+	}
+}
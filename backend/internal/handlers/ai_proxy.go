@@ -1,13 +1,15 @@
 package handlers
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -66,6 +68,29 @@ func extractAuthAndTenant(c *gin.Context) *integration.AIRequestOptions {
 	return opts
 }
 
+// respondAIServiceError writes the error response for a failed AI service call.
+// While the circuit breaker is open the user gets a friendly message instead of an upstream error.
+func respondAIServiceError(c *gin.Context, err error) {
+	if errors.Is(err, integration.ErrCircuitOpen) {
+		retryAfter := int(math.Ceil(aiServiceClient.BreakerRetryAfter().Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":       "AI service temporarily unavailable",
+			"message":     "O assistente de IA está temporariamente indisponível. Tente novamente em alguns instantes.",
+			"retry_after": retryAfter,
+		})
+		return
+	}
+
+	c.JSON(http.StatusBadGateway, gin.H{
+		"error":   "failed to communicate with AI service",
+		"details": err.Error(),
+	})
+}
+
 // AIChatRequest represents the request body for AI chat
 type AIChatRequest struct {
 	Message   string `json:"message" binding:"required"`
@@ -96,10 +121,7 @@ func AIChat(c *gin.Context) {
 	// Forward the request to the AI service
 	resp, err := aiServiceClient.SendChatMessage(c.Request.Context(), req.Message, req.SessionID, opts)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"error":   "failed to communicate with AI service",
-			"details": err.Error(),
-		})
+		respondAIServiceError(c, err)
 		return
 	}
 
@@ -131,8 +153,13 @@ func AIChatStream(c *gin.Context) {
 	aiServiceURL := aiServiceClient.GetBaseURL()
 	upstreamURL := aiServiceURL + "/api/v1/ai/chat/stream"
 
+	// The upstream request lives only as long as the client request: a client disconnect,
+	// or the handler giving up on a failed write, cancels the proxied stream
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
 	reqBody, _ := json.Marshal(req)
-	upstreamReq, err := http.NewRequestWithContext(c.Request.Context(), http.MethodPost, upstreamURL, strings.NewReader(string(reqBody)))
+	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, upstreamURL, strings.NewReader(string(reqBody)))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "failed to create upstream request",
@@ -149,18 +176,10 @@ func AIChatStream(c *gin.Context) {
 		upstreamReq.Header.Set("X-Tenant-Context", opts.TenantContext)
 	}
 
-	// Create a client with no timeout for streaming
-	streamClient := &http.Client{
-		Timeout: 0, // No timeout for streaming
-	}
-
 	// Make the request to the AI service
-	upstreamResp, err := streamClient.Do(upstreamReq)
+	upstreamResp, err := aiServiceClient.Stream(upstreamReq)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"error":   "failed to connect to AI service",
-			"details": err.Error(),
-		})
+		respondAIServiceError(c, err)
 		return
 	}
 	defer upstreamResp.Body.Close()
@@ -182,29 +201,31 @@ func AIChatStream(c *gin.Context) {
 	c.Writer.Header().Set("X-Accel-Buffering", "no") // Disable nginx buffering
 
 	// Proxy the SSE stream from the AI service to the client
-	reader := bufio.NewReader(upstreamResp.Body)
-	for {
-		select {
-		case <-c.Request.Context().Done():
-			return
-		default:
-		}
+	c.Writer.WriteHeader(http.StatusOK)
+	c.Writer.Flush()
+	proxyStream(ctx, c.Writer, upstreamResp.Body)
+}
 
-		line, err := reader.ReadBytes('\n')
-		if err != nil {
-			if err == io.EOF {
+// proxyStream copies the upstream stream to the client, flushing each chunk as soon as it is read
+// so tokens reach the client without buffering. It returns when the upstream ends, the context is
+// cancelled or the client can no longer be written to.
+func proxyStream(ctx context.Context, w gin.ResponseWriter, upstream io.Reader) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := upstream.Read(buf)
+		if n > 0 {
+			if _, writeErr := w.Write(buf[:n]); writeErr != nil {
 				return
 			}
-			// Log error but continue - could be temporary
-			continue
+			w.Flush()
 		}
-
-		// Write the line to the client
-		_, writeErr := c.Writer.Write(line)
-		if writeErr != nil {
+		if err != nil {
+			// io.EOF ends the stream normally; any other error means the upstream or the client went away
+			return
+		}
+		if ctx.Err() != nil {
 			return
 		}
-		c.Writer.Flush()
 	}
 }
 
@@ -214,7 +235,7 @@ type AIConfirmActionRequest struct {
 }
 
 // AIConfirmAction handles POST /api/v1/ai/confirm/:action_id
-// Confirms or rejects a pending AI action
+// Confirms or rejects a pending AI action; confirmations are re-validated against current permissions and state
 func AIConfirmAction(c *gin.Context) {
 	if aiServiceClient == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
@@ -242,20 +263,27 @@ func AIConfirmAction(c *gin.Context) {
 
 	opts := extractAuthAndTenant(c)
 
+	// Rejecting an action never executes anything; confirming one is re-validated first
+	if req.Confirmed && !revalidateAIAction(c, actionID, opts) {
+		return
+	}
+
 	resp, err := aiServiceClient.ConfirmAction(c.Request.Context(), actionID, req.Confirmed, opts)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"error":   "failed to communicate with AI service",
-			"details": err.Error(),
-		})
+		respondAIServiceError(c, err)
 		return
 	}
 
+	// Confirmed actions (e.g. update_occurrence_status) change occurrences directly in the AI service
+	if req.Confirmed && resp.Success {
+		metricsCache.Invalidate(c.Request.Context())
+	}
+
 	c.JSON(http.StatusOK, resp)
 }
 
 // AIListConversations handles GET /api/v1/ai/conversations
-// Lists all conversations for the authenticated user
+// Lists the authenticated user's conversations, paginated with page and per_page
 func AIListConversations(c *gin.Context) {
 	if aiServiceClient == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
@@ -264,18 +292,44 @@ func AIListConversations(c *gin.Context) {
 		return
 	}
 
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+	if perPage < 1 {
+		perPage = 20
+	}
+
 	opts := extractAuthAndTenant(c)
 
-	resp, err := aiServiceClient.ListConversations(c.Request.Context(), opts)
+	// The AI service does not count all sessions, so fetch one extra to know whether there is a next page
+	resp, err := aiServiceClient.ListConversations(c.Request.Context(), perPage+1, (page-1)*perPage, opts)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"error":   "failed to communicate with AI service",
-			"details": err.Error(),
-		})
+		respondAIServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, resp)
+	conversations := resp.Conversations
+	hasMore := len(conversations) > perPage
+	if hasMore {
+		conversations = conversations[:perPage]
+	}
+	if conversations == nil {
+		conversations = []integration.ConversationSummary{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": conversations,
+		"meta": gin.H{
+			"page":     page,
+			"per_page": perPage,
+			"has_more": hasMore,
+		},
+	})
 }
 
 // AIGetConversation handles GET /api/v1/ai/conversations/:session_id
@@ -300,10 +354,7 @@ func AIGetConversation(c *gin.Context) {
 
 	resp, err := aiServiceClient.GetConversation(c.Request.Context(), sessionID, opts)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"error":   "failed to communicate with AI service",
-			"details": err.Error(),
-		})
+		respondAIServiceError(c, err)
 		return
 	}
 
@@ -332,10 +383,7 @@ func AIDeleteConversation(c *gin.Context) {
 
 	err := aiServiceClient.DeleteConversation(c.Request.Context(), sessionID, opts)
 	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"error":   "failed to communicate with AI service",
-			"details": err.Error(),
-		})
+		respondAIServiceError(c, err)
 		return
 	}
 
@@ -362,17 +410,19 @@ func AIHealth(c *gin.Context) {
 	resp, err := aiServiceClient.Health(ctx)
 	if err != nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"status":    "unhealthy",
-			"error":     fmt.Sprintf("AI service health check failed: %v", err),
-			"timestamp": time.Now().UTC().Format(time.RFC3339),
+			"status":          "unhealthy",
+			"error":           fmt.Sprintf("AI service health check failed: %v", err),
+			"circuit_breaker": aiServiceClient.BreakerStatus(),
+			"timestamp":       time.Now().UTC().Format(time.RFC3339),
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"status":     resp.Status,
-		"ai_service": resp,
-		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+		"status":          resp.Status,
+		"ai_service":      resp,
+		"circuit_breaker": aiServiceClient.BreakerStatus(),
+		"timestamp":       time.Now().UTC().Format(time.RFC3339),
 	})
 }
 
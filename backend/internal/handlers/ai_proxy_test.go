@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sidot/backend/internal/integration"
@@ -259,3 +262,158 @@ func TestAIProxyServiceUnavailable(t *testing.T) {
 		t.Errorf("Expected 'AI service not configured' error, got %v", response["error"])
 	}
 }
+
+// TestAIProxyCircuitBreaker verifies that a hanging AI service opens the circuit breaker,
+// and that a successful probe after the cooldown closes it again
+func TestAIProxyCircuitBreaker(t *testing.T) {
+	var healthy atomic.Bool
+	var hits atomic.Int32
+	mockAIService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !healthy.Load() {
+			// Simulate a hung service: respond only after the client timeout
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response":   "Test response",
+			"session_id": "test-session",
+			"status":     "healthy",
+		})
+	}))
+	defer mockAIService.Close()
+
+	client := integration.NewAIServiceClient(&integration.AIServiceConfig{
+		BaseURL:          mockAIService.URL,
+		Timeout:          50 * time.Millisecond,
+		MaxRetries:       0,
+		FailureThreshold: 2,
+		BreakerCooldown:  300 * time.Millisecond,
+	})
+	SetAIServiceClient(client)
+	defer SetAIServiceClient(nil)
+
+	router := setupAIProxyTestRouter()
+	router.POST("/api/v1/ai/chat", func(c *gin.Context) {
+		mockAIUserClaims(c, "user-123", "tenant-456", "operador", false)
+		mockAITenantContext(c, "tenant-456", false)
+		AIChat(c)
+	})
+	router.GET("/api/v1/ai/health", AIHealth)
+
+	chat := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/ai/chat", strings.NewReader(`{"message": "Hello AI"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	breakerState := func() string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/ai/health", nil))
+		var response struct {
+			CircuitBreaker integration.CircuitBreakerStatus `json:"circuit_breaker"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return string(response.CircuitBreaker.State)
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := chat(); w.Code != http.StatusBadGateway {
+			t.Fatalf("Expected timeout %d to return 502, got %d", i+1, w.Code)
+		}
+	}
+
+	hitsBeforeOpen := hits.Load()
+	w := chat()
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 while the circuit is open, got %d", w.Code)
+	}
+	if hits.Load() != hitsBeforeOpen {
+		t.Error("Expected the open circuit to short-circuit without calling the AI service")
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("Expected a Retry-After header while the circuit is open")
+	}
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response["message"] == nil || response["message"] == "" {
+		t.Error("Expected a friendly message while the circuit is open")
+	}
+	if state := breakerState(); state != string(integration.CircuitOpen) {
+		t.Errorf("Expected AIHealth to report an open circuit, got %q", state)
+	}
+
+	// After the cooldown the service has recovered and the probe closes the circuit
+	healthy.Store(true)
+	time.Sleep(350 * time.Millisecond)
+
+	if w := chat(); w.Code != http.StatusOK {
+		t.Fatalf("Expected the probe to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if state := breakerState(); state != string(integration.CircuitClosed) {
+		t.Errorf("Expected AIHealth to report a closed circuit, got %q", state)
+	}
+}
+
+// TestAIChatStreamCancelsUpstreamOnClientDisconnect verifies that chunks are flushed as they arrive
+// and that a client disconnecting mid-stream cancels the upstream request
+func TestAIChatStreamCancelsUpstreamOnClientDisconnect(t *testing.T) {
+	upstreamCancelled := make(chan struct{})
+	mockAIService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("data: {\"type\":\"token\",\"content\":\"Ola\"}\n\n"))
+		w.(http.Flusher).Flush()
+
+		// Slow upstream: keep the stream open until the proxy cancels it
+		select {
+		case <-r.Context().Done():
+			close(upstreamCancelled)
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer mockAIService.Close()
+
+	SetAIServiceClient(integration.NewAIServiceClient(&integration.AIServiceConfig{
+		BaseURL: mockAIService.URL,
+		Timeout: 5 * time.Second,
+	}))
+	defer SetAIServiceClient(nil)
+
+	router := setupAIProxyTestRouter()
+	router.POST("/api/v1/ai/chat/stream", func(c *gin.Context) {
+		mockAIUserClaims(c, "user-123", "tenant-456", "operador", false)
+		mockAITenantContext(c, "tenant-456", false)
+		AIChatStream(c)
+	})
+	proxy := httptest.NewServer(router)
+	defer proxy.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, proxy.URL+"/api/v1/ai/chat/stream", strings.NewReader(`{"message": "Hello AI"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Stream request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// The first event must arrive while the upstream is still streaming
+	buf := make([]byte, 256)
+	n, err := resp.Body.Read(buf)
+	if err != nil || !strings.Contains(string(buf[:n]), `"content":"Ola"`) {
+		t.Fatalf("Expected the first chunk to be flushed, got %q (err: %v)", buf[:n], err)
+	}
+
+	// Client disconnects mid-stream
+	cancel()
+
+	select {
+	case <-upstreamCancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the upstream request to be cancelled after the client disconnected")
+	}
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -35,6 +37,7 @@ func GetAuditService() *audit.AuditService {
 
 // ListAuditLogs returns audit logs with pagination and filters
 // GET /api/v1/audit-logs
+// Pagination: page/page_size (offset) or cursor/page_size (keyset, returns next_cursor)
 // Access: Admin (all logs), Gestor (only their hospital's logs)
 // Operador: Access DENIED
 func ListAuditLogs(c *gin.Context) {
@@ -101,6 +104,24 @@ func ListAuditLogs(c *gin.Context) {
 		filters.UsuarioID = &uid
 	}
 
+	// Actor filter - a user id or part of the actor name
+	if actor := c.Query("actor"); actor != "" {
+		uid, name := parseAuditLogActor(actor)
+		if uid != nil {
+			filters.UsuarioID = uid
+		}
+		filters.ActorName = name
+	}
+
+	// IP address filter
+	if ipAddress := c.Query("ip_address"); ipAddress != "" {
+		if !isValidAuditLogIP(ipAddress) {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid ip_address format")
+			return
+		}
+		filters.IPAddress = &ipAddress
+	}
+
 	// Acao filter
 	if acao := c.Query("acao"); acao != "" {
 		filters.Acao = &acao
@@ -178,6 +199,27 @@ func ListAuditLogs(c *gin.Context) {
 		filters.PageSize = ps
 	}
 
+	// Cursor pagination: used when the cursor param is present (empty starts from the newest entry)
+	if cursor, useCursor := c.GetQuery("cursor"); useCursor {
+		if cursor != "" {
+			parsed, err := models.ParseAuditLogCursor(cursor)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+				return
+			}
+			filters.Cursor = parsed
+		}
+
+		logs, next, err := auditLogRepo.ListWithHospitalNamesAfterCursor(c.Request.Context(), filters)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list audit logs"})
+			return
+		}
+
+		c.JSON(http.StatusOK, newAuditLogCursorResponse(logs, filters.PageSize, next))
+		return
+	}
+
 	// Query with hospital names for display
 	logs, totalItems, err := auditLogRepo.ListWithHospitalNames(c.Request.Context(), filters)
 	if err != nil {
@@ -185,10 +227,22 @@ func ListAuditLogs(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.NewPaginatedResponse(logs, filters.Page, filters.PageSize, totalItems))
+	c.JSON(http.StatusOK, models.NewPaginatedResponse(logs, filters.Page, filters.PageSize, totalItems).WithLinks(c.Request.URL, "page_size"))
+}
+
+// newAuditLogCursorResponse builds a cursor-paginated response; next is nil on the last page
+func newAuditLogCursorResponse(data interface{}, pageSize int, next *models.AuditLogCursor) models.CursorPaginatedResponse {
+	response := models.CursorPaginatedResponse{Data: data, PageSize: pageSize}
+	if next != nil {
+		token := next.Encode()
+		response.NextCursor = &token
+		response.HasNext = true
+	}
+	return response
 }
 
 // GetOccurrenceTimeline returns audit logs for a specific occurrence (timeline view)
+// and the time spent in each status
 // GET /api/v1/occurrences/:id/timeline
 // Access: Admin (all), Gestor (same hospital), Operador (their occurrences)
 func GetOccurrenceTimeline(c *gin.Context) {
@@ -224,30 +278,11 @@ func GetOccurrenceTimeline(c *gin.Context) {
 		}
 
 		// Access control based on role
-		switch claims.Role {
-		case "admin":
-			// Admin can view any occurrence timeline
-		case "gestor":
-			// Gestor can only view occurrences from their hospital
-			if claims.HospitalID == "" || claims.HospitalID != occurrence.HospitalID.String() {
-				c.JSON(http.StatusForbidden, gin.H{
-					"error":   "access denied",
-					"message": "you can only view occurrences from your hospital",
-				})
-				return
-			}
-		case "operador":
-			// Operador can view occurrences from their hospital
-			// Note: Could add additional restriction to only their assigned occurrences
-			if claims.HospitalID == "" || claims.HospitalID != occurrence.HospitalID.String() {
-				c.JSON(http.StatusForbidden, gin.H{
-					"error":   "access denied",
-					"message": "you can only view occurrences from your hospital",
-				})
-				return
-			}
-		default:
-			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		if !canAccessOccurrence(claims, occurrence) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "access denied",
+				"message": "you can only view occurrences from your hospital",
+			})
 			return
 		}
 	}
@@ -265,8 +300,38 @@ func GetOccurrenceTimeline(c *gin.Context) {
 		response = append(response, log.ToResponse())
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	result := gin.H{
 		"data":  response,
 		"total": len(response),
-	})
+	}
+
+	// Time spent in each status, computed from the status history
+	if occurrenceHistoryRepo != nil {
+		histories, err := occurrenceHistoryRepo.GetByOccurrenceID(c.Request.Context(), occurrenceID)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to get occurrence history")
+			return
+		}
+		result["status_timeline"] = buildOccurrenceStatusTimeline(histories, time.Now())
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// parseAuditLogActor interprets the actor filter: a UUID matches the user id,
+// anything else a part of the actor name
+func parseAuditLogActor(actor string) (*uuid.UUID, *string) {
+	actor = strings.TrimSpace(actor)
+	if uid, err := uuid.Parse(actor); err == nil {
+		return &uid, nil
+	}
+	if actor == "" {
+		return nil, nil
+	}
+	return nil, &actor
+}
+
+// isValidAuditLogIP reports whether the ip_address filter is an IPv4 or IPv6 address
+func isValidAuditLogIP(ipAddress string) bool {
+	return net.ParseIP(ipAddress) != nil
 }
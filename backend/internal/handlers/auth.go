@@ -27,6 +27,8 @@ func NewAuthHandler(authService *auth.AuthService) *AuthHandler {
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=1"`
+	// TenantID picks the account when the email is registered in several tenants
+	TenantID string `json:"tenant_id,omitempty" binding:"omitempty,uuid"`
 }
 
 // RefreshRequest represents the refresh token request body
@@ -34,6 +36,17 @@ type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+// MFAVerifyRequest represents the second step of a login with MFA
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MFAConfirmRequest represents the confirmation of an MFA enrollment
+type MFAConfirmRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
 // LogoutRequest represents the logout request body
 type LogoutRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
@@ -54,7 +67,12 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	// Extract request info for audit
 	ipAddress, userAgent := audit.ExtractRequestInfo(c)
 
-	result, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
+	ctx := c.Request.Context()
+	if req.TenantID != "" {
+		ctx = middleware.WithTenantContext(ctx, req.TenantID, false)
+	}
+
+	result, err := h.authService.LoginFrom(ctx, req.Email, req.Password, loginSource(ipAddress, userAgent))
 	if err != nil {
 		// Log failed login attempt
 		if auditService != nil {
@@ -82,6 +100,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{
 				"error": "user account is inactive",
 			})
+		case auth.ErrEmailNotVerified:
+			respondError(c, http.StatusForbidden, "EMAIL_NOT_VERIFIED", "email has not been verified, use the link sent to your email")
+		case auth.ErrTenantRequired:
+			respondError(c, http.StatusConflict, ErrCodeConflict, "email is registered in more than one tenant, inform tenant_id")
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": "authentication failed",
@@ -90,8 +112,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Log successful login
-	if auditService != nil {
+	// Log successful login (logins waiting for the MFA code are logged by VerifyMFA)
+	if auditService != nil && !result.MFARequired {
 		var userID *uuid.UUID
 		if result.User != nil {
 			userID = &result.User.ID
@@ -113,6 +135,276 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// VerifyMFA completes a login that requires MFA, exchanging the MFA token and a TOTP code for the token pair
+// POST /api/v1/auth/mfa/verify
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "invalid request body", err.Error())
+		return
+	}
+
+	ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+	result, err := h.authService.VerifyMFA(c.Request.Context(), req.MFAToken, req.Code, loginSource(ipAddress, userAgent))
+	if err != nil {
+		if auditService != nil && (err == auth.ErrInvalidMFACode || err == auth.ErrTooManyMFAAttempts) {
+			auditService.LogAuthEvent(
+				c.Request.Context(),
+				models.ActionAuthLoginFailed,
+				nil,
+				"",
+				models.SeverityWarn,
+				ipAddress,
+				userAgent,
+				map[string]interface{}{
+					"reason": err.Error(),
+				},
+			)
+		}
+
+		switch err {
+		case auth.ErrExpiredToken:
+			respondError(c, http.StatusUnauthorized, "TOKEN_EXPIRED", "mfa token has expired, sign in again")
+		case auth.ErrInvalidToken, auth.ErrInvalidClaims, auth.ErrUserNotFound:
+			respondError(c, http.StatusUnauthorized, "INVALID_TOKEN", "invalid mfa token")
+		case auth.ErrMFAChallengeUsed:
+			respondError(c, http.StatusUnauthorized, "INVALID_TOKEN", "mfa token has already been used, sign in again")
+		case auth.ErrInvalidMFACode, auth.ErrMFANotEnrolled:
+			respondError(c, http.StatusUnauthorized, "INVALID_MFA_CODE", "invalid mfa code")
+		case auth.ErrTooManyMFAAttempts:
+			respondError(c, http.StatusTooManyRequests, "RATE_LIMITED", "too many mfa attempts, try again later")
+		case auth.ErrUserInactive:
+			respondError(c, http.StatusForbidden, ErrCodeForbidden, "user account is inactive")
+		case auth.ErrMFAUnavailable:
+			respondError(c, http.StatusServiceUnavailable, ErrCodeUnavailable, "mfa is not available")
+		default:
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "mfa verification failed")
+		}
+		return
+	}
+
+	if auditService != nil {
+		auditService.LogAuthEvent(
+			c.Request.Context(),
+			models.ActionAuthLogin,
+			&result.User.ID,
+			result.User.Email,
+			models.SeverityInfo,
+			ipAddress,
+			userAgent,
+			map[string]interface{}{
+				"email": result.User.Email,
+				"mfa":   true,
+			},
+		)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// EnrollMFA starts the MFA enrollment of the current user
+// The returned secret is added to an authenticator app (provisioning_uri as a QR code) and confirmed with a code
+// POST /api/v1/auth/mfa/enroll
+func (h *AuthHandler) EnrollMFA(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.authService.EnrollMFA(c.Request.Context(), userID)
+	if err != nil {
+		switch err {
+		case auth.ErrMFAAlreadyEnabled:
+			respondError(c, http.StatusConflict, ErrCodeConflict, "mfa is already enabled")
+		case auth.ErrMFAUnavailable:
+			respondError(c, http.StatusServiceUnavailable, ErrCodeUnavailable, "mfa is not available")
+		case auth.ErrUserNotFound:
+			respondError(c, http.StatusNotFound, ErrCodeNotFound, "user not found")
+		default:
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to start mfa enrollment")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmMFAEnrollment enables MFA for the current user with a code of the enrolled secret
+// POST /api/v1/auth/mfa/enroll/confirm
+func (h *AuthHandler) ConfirmMFAEnrollment(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req MFAConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "invalid request body", err.Error())
+		return
+	}
+
+	if err := h.authService.ConfirmMFAEnrollment(c.Request.Context(), userID, req.Code); err != nil {
+		switch err {
+		case auth.ErrInvalidMFACode:
+			respondError(c, http.StatusBadRequest, "INVALID_MFA_CODE", "invalid mfa code")
+		case auth.ErrMFANotEnrolled:
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "mfa enrollment has not been started")
+		case auth.ErrTooManyMFAAttempts:
+			respondError(c, http.StatusTooManyRequests, "RATE_LIMITED", "too many mfa attempts, try again later")
+		case auth.ErrMFAAlreadyEnabled:
+			respondError(c, http.StatusConflict, ErrCodeConflict, "mfa is already enabled")
+		case auth.ErrMFAUnavailable:
+			respondError(c, http.StatusServiceUnavailable, ErrCodeUnavailable, "mfa is not available")
+		default:
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to confirm mfa enrollment")
+		}
+		return
+	}
+
+	if auditService != nil {
+		claims, _ := middleware.GetUserClaims(c)
+		ipAddress, userAgent := audit.ExtractRequestInfo(c)
+		auditService.LogAuthEvent(
+			c.Request.Context(),
+			models.ActionAuthMFAEnabled,
+			&userID,
+			claims.Email,
+			models.SeverityInfo,
+			ipAddress,
+			userAgent,
+			nil,
+		)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "mfa enabled",
+	})
+}
+
+// ListSessions returns the active sessions of the current user
+// GET /api/v1/auth/sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(c.Request.Context(), userID.String())
+	if err != nil {
+		respondSessionError(c, err, "failed to list sessions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  sessions,
+		"total": len(sessions),
+	})
+}
+
+// RevokeSession signs the current user out of one session, by the jti of its refresh token
+// DELETE /api/v1/auth/sessions/:jti
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	jti := c.Param("jti")
+	if err := h.authService.RevokeSession(c.Request.Context(), userID.String(), jti); err != nil {
+		respondSessionError(c, err, "failed to revoke session")
+		return
+	}
+
+	h.logSessionsRevoked(c, userID, map[string]interface{}{"jti": jti})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "session revoked",
+	})
+}
+
+// RevokeAllSessions signs the current user out of every session ("logout everywhere")
+// DELETE /api/v1/auth/sessions
+func (h *AuthHandler) RevokeAllSessions(c *gin.Context) {
+	userID, ok := authenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.authService.RevokeAllSessions(c.Request.Context(), userID.String())
+	if err != nil {
+		respondSessionError(c, err, "failed to revoke sessions")
+		return
+	}
+
+	h.logSessionsRevoked(c, userID, map[string]interface{}{"all": true, "revoked": revoked})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "sessions revoked",
+		"revoked": revoked,
+	})
+}
+
+// logSessionsRevoked logs the revocation of sessions of the current user as a logout
+func (h *AuthHandler) logSessionsRevoked(c *gin.Context, userID uuid.UUID, details map[string]interface{}) {
+	if auditService == nil {
+		return
+	}
+
+	claims, _ := middleware.GetUserClaims(c)
+	ipAddress, userAgent := audit.ExtractRequestInfo(c)
+	auditService.LogAuthEvent(
+		c.Request.Context(),
+		models.ActionAuthLogout,
+		&userID,
+		claims.Email,
+		models.SeverityInfo,
+		ipAddress,
+		userAgent,
+		details,
+	)
+}
+
+// respondSessionError writes the response of a failed session operation
+func respondSessionError(c *gin.Context, err error, message string) {
+	switch err {
+	case auth.ErrSessionNotFound:
+		respondError(c, http.StatusNotFound, ErrCodeNotFound, "session not found")
+	case auth.ErrSessionsUnavailable:
+		respondError(c, http.StatusServiceUnavailable, ErrCodeUnavailable, "sessions are not available")
+	default:
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, message)
+	}
+}
+
+// loginSource returns the source of a login from the request info
+func loginSource(ipAddress, userAgent *string) auth.LoginSource {
+	source := auth.LoginSource{}
+	if ipAddress != nil {
+		source.IPAddress = *ipAddress
+	}
+	if userAgent != nil {
+		source.UserAgent = *userAgent
+	}
+	return source
+}
+
+// authenticatedUserID returns the ID of the authenticated user; on failure it writes the error response
+func authenticatedUserID(c *gin.Context) (uuid.UUID, bool) {
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "authentication required")
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "authentication required")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
 // RefreshToken handles token refresh
 // POST /api/v1/auth/refresh
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
@@ -147,6 +439,8 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{
 				"error": "user account is inactive",
 			})
+		case auth.ErrEmailNotVerified:
+			respondError(c, http.StatusForbidden, "EMAIL_NOT_VERIFIED", "email has not been verified")
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": "token refresh failed",
@@ -227,6 +521,27 @@ func (h *AuthHandler) Me(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+// VerifyEmail confirms the email of a user with the token of its verification link
+// GET /api/v1/auth/verify-email?token=...
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	err := h.authService.VerifyEmail(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		switch err {
+		case auth.ErrInvalidVerificationToken, auth.ErrUserNotFound:
+			respondError(c, http.StatusBadRequest, "INVALID_TOKEN", "invalid or expired verification link")
+		case auth.ErrEmailVerificationUnavailable:
+			respondError(c, http.StatusServiceUnavailable, ErrCodeUnavailable, "email verification is not available")
+		default:
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "email verification failed")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "email verified",
+	})
+}
+
 // Global handlers using singleton pattern for backwards compatibility with existing routes
 
 var globalAuthHandler *AuthHandler
@@ -269,6 +584,69 @@ func Logout(c *gin.Context) {
 	globalAuthHandler.Logout(c)
 }
 
+// VerifyMFA is the global handler for the MFA step of a login
+func VerifyMFA(c *gin.Context) {
+	if globalAuthHandler == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "auth handler not configured")
+		return
+	}
+	globalAuthHandler.VerifyMFA(c)
+}
+
+// EnrollMFA is the global handler for starting an MFA enrollment
+func EnrollMFA(c *gin.Context) {
+	if globalAuthHandler == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "auth handler not configured")
+		return
+	}
+	globalAuthHandler.EnrollMFA(c)
+}
+
+// ConfirmMFAEnrollment is the global handler for confirming an MFA enrollment
+func ConfirmMFAEnrollment(c *gin.Context) {
+	if globalAuthHandler == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "auth handler not configured")
+		return
+	}
+	globalAuthHandler.ConfirmMFAEnrollment(c)
+}
+
+// VerifyEmail is the global handler for email verification links
+func VerifyEmail(c *gin.Context) {
+	if globalAuthHandler == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "auth handler not configured")
+		return
+	}
+	globalAuthHandler.VerifyEmail(c)
+}
+
+// ListSessions is the global handler for listing the sessions of the current user
+func ListSessions(c *gin.Context) {
+	if globalAuthHandler == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "auth handler not configured")
+		return
+	}
+	globalAuthHandler.ListSessions(c)
+}
+
+// RevokeSession is the global handler for revoking a session of the current user
+func RevokeSession(c *gin.Context) {
+	if globalAuthHandler == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "auth handler not configured")
+		return
+	}
+	globalAuthHandler.RevokeSession(c)
+}
+
+// RevokeAllSessions is the global handler for revoking every session of the current user
+func RevokeAllSessions(c *gin.Context) {
+	if globalAuthHandler == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "auth handler not configured")
+		return
+	}
+	globalAuthHandler.RevokeAllSessions(c)
+}
+
 // Me is the global handler for getting current user
 func Me(c *gin.Context) {
 	if globalAuthHandler == nil {
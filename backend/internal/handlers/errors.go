@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+// Generic error codes, used when an error has no domain-specific code
+const (
+	ErrCodeInvalidRequest     = "INVALID_REQUEST"
+	ErrCodeInvalidRequestBody = "INVALID_REQUEST_BODY"
+	ErrCodeValidationFailed   = "VALIDATION_FAILED"
+	ErrCodeUnauthorized       = "UNAUTHORIZED"
+	ErrCodeForbidden          = "FORBIDDEN"
+	ErrCodeNotFound           = "NOT_FOUND"
+	ErrCodeConflict           = "CONFLICT"
+	ErrCodePayloadTooLarge    = "PAYLOAD_TOO_LARGE"
+	ErrCodeInternal           = "INTERNAL_ERROR"
+	ErrCodeUnavailable        = "SERVICE_UNAVAILABLE"
+)
+
+// ErrorResponse is the standard error envelope of the API, shared with the middleware responses
+type ErrorResponse = middleware.ErrorResponse
+
+// domainError maps a domain error to its HTTP status and stable error code.
+// An empty message responds with the error text.
+type domainError struct {
+	err     error
+	status  int
+	code    string
+	message string
+}
+
+// domainErrors lists the domain errors with a stable code, checked in order with errors.Is
+var domainErrors = []domainError{
+	// Users
+	{auth.ErrUserNotFound, http.StatusNotFound, "USER_NOT_FOUND", "user not found"},
+	{repository.ErrAdminUserNotFound, http.StatusNotFound, "USER_NOT_FOUND", "user not found"},
+	{repository.ErrUserExists, http.StatusConflict, "USER_EXISTS", "user with this email already exists"},
+	{auth.ErrUserInactive, http.StatusConflict, "USER_INACTIVE", "user account is inactive"},
+	{auth.ErrWelcomeUnavailable, http.StatusServiceUnavailable, "WELCOME_EMAIL_UNAVAILABLE", "welcome email is not available"},
+
+	// Shifts
+	{models.ErrShiftNotFound, http.StatusNotFound, "SHIFT_NOT_FOUND", "Escala não encontrada"},
+	{models.ErrShiftExists, http.StatusConflict, "SHIFT_EXISTS", "Já existe uma escala para este operador neste horário"},
+	{models.ErrShiftOverlap, http.StatusConflict, "SHIFT_OVERLAP", "Escala sobrepõe outra escala do mesmo operador"},
+	{models.ErrInvalidDayOfWeek, http.StatusBadRequest, "INVALID_DAY_OF_WEEK", "Dia da semana inválido"},
+	{models.ErrInvalidStartTime, http.StatusBadRequest, "INVALID_START_TIME", "Horário de início inválido (use formato HH:MM)"},
+	{models.ErrInvalidEndTime, http.StatusBadRequest, "INVALID_END_TIME", "Horário de fim inválido (use formato HH:MM)"},
+	{models.ErrInvalidScheduleSlotMinutes, http.StatusBadRequest, "INVALID_SLOT_MINUTES", "Tamanho de intervalo inválido (divisor de 24h, entre 15 e 720 minutos)"},
+	{models.ErrShiftTemplateNotFound, http.StatusNotFound, "SHIFT_TEMPLATE_NOT_FOUND", "Modelo de escala não encontrado"},
+	{models.ErrShiftTemplateNoSlots, http.StatusBadRequest, "INVALID_SHIFT_TEMPLATE", "Modelo de escala deve ter ao menos um turno"},
+	{models.ErrShiftTemplateTooManySlots, http.StatusBadRequest, "INVALID_SHIFT_TEMPLATE", "Modelo de escala tem turnos demais"},
+	{models.ErrShiftTemplateEmptySlot, http.StatusBadRequest, "INVALID_SHIFT_TEMPLATE", "Turno do modelo deve ter início diferente do fim"},
+	{models.ErrShiftTemplateOperatorCount, http.StatusBadRequest, "INVALID_SHIFT_TEMPLATE_OPERATORS", "Informe um operador para cada turno do modelo"},
+
+	// Tenants
+	{repository.ErrAdminTenantNotFound, http.StatusNotFound, "TENANT_NOT_FOUND", "tenant not found"},
+	{models.ErrTenantNotFound, http.StatusNotFound, "TENANT_NOT_FOUND", "tenant not found"},
+	{repository.ErrAdminTenantSlugExists, http.StatusConflict, "TENANT_SLUG_EXISTS", "tenant with this slug already exists"},
+	{models.ErrTenantSlugExists, http.StatusConflict, "TENANT_SLUG_EXISTS", "tenant with this slug already exists"},
+	{repository.ErrThemeVersionNotFound, http.StatusNotFound, "THEME_VERSION_NOT_FOUND", "theme version not found"},
+	{repository.ErrAdminTenantActive, http.StatusConflict, "TENANT_ACTIVE", "tenant must be deactivated before its data is purged"},
+	{repository.ErrAdminPurgeConfirmation, http.StatusBadRequest, "INVALID_CONFIRMATION_TOKEN", "confirmation token does not match the tenant slug"},
+	{models.ErrInvalidTenantSlug, http.StatusBadRequest, "INVALID_TENANT_SLUG", ""},
+	{models.ErrInvalidThemeConfig, http.StatusBadRequest, "INVALID_THEME_CONFIG", ""},
+	{models.ErrInvalidAllowedOrigin, http.StatusBadRequest, "INVALID_ALLOWED_ORIGIN", ""},
+	{errAssetTooLarge, http.StatusRequestEntityTooLarge, "ASSET_TOO_LARGE", ""},
+	{errAssetTypeNotAllowed, http.StatusBadRequest, "ASSET_TYPE_NOT_ALLOWED", ""},
+	{errAssetInvalidImage, http.StatusBadRequest, "INVALID_ASSET_IMAGE", ""},
+
+	// Hospitals and occurrences
+	{repository.ErrHospitalNotFound, http.StatusNotFound, "HOSPITAL_NOT_FOUND", "hospital not found"},
+	{repository.ErrAdminHospitalNotFound, http.StatusNotFound, "HOSPITAL_NOT_FOUND", "hospital not found"},
+	{repository.ErrHospitalExists, http.StatusConflict, "HOSPITAL_EXISTS", "hospital with this code already exists"},
+	{repository.ErrAdminHospitalCodigoInUse, http.StatusConflict, "HOSPITAL_EXISTS", "hospital codigo is already in use by another hospital"},
+	{repository.ErrAdminReassignSameTenant, http.StatusBadRequest, "REASSIGN_SAME_TENANT", "hospitals are already in the target tenant"},
+	{repository.ErrAdminTargetTenantNotFound, http.StatusBadRequest, "TARGET_TENANT_NOT_FOUND", "target tenant not found"},
+	{repository.ErrAdminReassignConflict, http.StatusConflict, "REASSIGN_CONFLICT", "hospitals or users conflict with the target tenant"},
+	{repository.ErrOccurrenceNotFound, http.StatusNotFound, "OCCURRENCE_NOT_FOUND", "occurrence not found"},
+
+	// List presets
+	{models.ErrListPresetNotFound, http.StatusNotFound, "LIST_PRESET_NOT_FOUND", "Filtro salvo não encontrado"},
+	{models.ErrListPresetNameRequired, http.StatusBadRequest, "INVALID_LIST_PRESET", "Informe o nome do filtro"},
+	{models.ErrListPresetNameTooLong, http.StatusBadRequest, "INVALID_LIST_PRESET", "Nome do filtro muito longo"},
+	{models.ErrListPresetUnknownParam, http.StatusBadRequest, "INVALID_LIST_PRESET", ""},
+
+	// Database
+	{repository.ErrStatementTimeout, http.StatusServiceUnavailable, "QUERY_TIMEOUT", "query took too long, narrow the filters and try again"},
+}
+
+// respondError aborts the request with the standard error envelope
+func respondError(c *gin.Context, status int, code, message string) {
+	respondErrorDetails(c, status, code, message, "")
+}
+
+// respondErrorDetails aborts the request with the standard error envelope including details
+func respondErrorDetails(c *gin.Context, status int, code, message, details string) {
+	middleware.AbortWithError(c, status, code, message, details)
+}
+
+// respondDomainError responds with the status and code mapped to a known domain error,
+// or 500 INTERNAL_ERROR with fallbackMessage for any other error (its text is not exposed)
+func respondDomainError(c *gin.Context, err error, fallbackMessage string) {
+	if mapped, ok := lookupDomainError(err); ok {
+		message := mapped.message
+		if message == "" {
+			message = err.Error()
+		}
+		respondError(c, mapped.status, mapped.code, message)
+		return
+	}
+	respondError(c, http.StatusInternalServerError, ErrCodeInternal, fallbackMessage)
+}
+
+// lookupDomainError finds the mapping of a domain error
+func lookupDomainError(err error) (domainError, bool) {
+	for _, mapped := range domainErrors {
+		if errors.Is(err, mapped.err) {
+			return mapped, true
+		}
+	}
+	return domainError{}, false
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeErrorResponse decodes the error envelope of a response
+func decodeErrorResponse(t *testing.T, w *httptest.ResponseRecorder) ErrorResponse {
+	t.Helper()
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestErrorEnvelopeFromHandlers(t *testing.T) {
+	handler := NewShiftHandler(nil, nil)
+
+	router := setupTestRouter()
+	router.Use(middleware.RequestID())
+	router.POST("/shifts", handler.Create)
+	router.GET("/shifts/:id", handler.GetByID)
+
+	t.Run("unauthenticated request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/shifts", nil)
+		req.Header.Set("X-Request-ID", "req-123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		response := decodeErrorResponse(t, w)
+		assert.Equal(t, ErrCodeUnauthorized, response.Code)
+		assert.Equal(t, "Não autorizado", response.Message)
+		assert.Equal(t, response.Message, response.Error)
+		assert.Equal(t, "req-123", response.RequestID)
+	})
+
+	t.Run("invalid path parameter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/shifts/not-a-uuid", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		response := decodeErrorResponse(t, w)
+		assert.Equal(t, ErrCodeInvalidRequest, response.Code)
+		assert.NotEmpty(t, response.RequestID, "request ID generated by the middleware")
+	})
+}
+
+func TestRespondDomainError(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedStatus  int
+		expectedCode    string
+		expectedMessage string
+	}{
+		{"tenant not found", repository.ErrAdminTenantNotFound, http.StatusNotFound, "TENANT_NOT_FOUND", "tenant not found"},
+		{"user exists", repository.ErrUserExists, http.StatusConflict, "USER_EXISTS", "user with this email already exists"},
+		{"wrapped shift not found", fmt.Errorf("get shift: %w", models.ErrShiftNotFound), http.StatusNotFound, "SHIFT_NOT_FOUND", "Escala não encontrada"},
+		{"validation error uses its text", fmt.Errorf("%w: primary color is required", models.ErrInvalidThemeConfig), http.StatusBadRequest, "INVALID_THEME_CONFIG", "invalid theme config: primary color is required"},
+		{"unknown error is not exposed", errors.New("pq: connection refused"), http.StatusInternalServerError, ErrCodeInternal, "failed to load"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupTestRouter()
+			router.GET("/test", func(c *gin.Context) {
+				respondDomainError(c, tt.err, "failed to load")
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			response := decodeErrorResponse(t, w)
+			assert.Equal(t, tt.expectedCode, response.Code)
+			assert.Equal(t, tt.expectedMessage, response.Message)
+			assert.Equal(t, tt.expectedMessage, response.Error)
+		})
+	}
+}
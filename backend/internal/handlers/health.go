@@ -27,9 +27,32 @@ func SetGlobalTriagemMotor(m *triagem.TriagemMotor) {
 	globalTriagemMotor = m
 }
 
+// healthChecker runs on-demand health checks (implemented by health.HealthMonitorService)
+type healthChecker interface {
+	CheckNow(ctx context.Context) *health.HealthSummary
+}
+
+// onDemandHealthChecker runs the checks for TriggerHealthCheck
+var onDemandHealthChecker healthChecker
+
+// readinessChecker runs the readiness checks (implemented by health.HealthMonitorService)
+type readinessChecker interface {
+	CheckReadiness(ctx context.Context) *health.HealthSummary
+}
+
+// globalReadinessChecker runs the checks for Readiness
+var globalReadinessChecker readinessChecker
+
 // SetGlobalHealthMonitor sets the global health monitor instance
 func SetGlobalHealthMonitor(m *health.HealthMonitorService) {
 	globalHealthMonitor = m
+	if m == nil {
+		onDemandHealthChecker = nil
+		globalReadinessChecker = nil
+		return
+	}
+	onDemandHealthChecker = m
+	globalReadinessChecker = m
 }
 
 // GetGlobalListener returns the global listener instance
@@ -90,6 +113,9 @@ type ComponentStatusJSON struct {
 	LatencyMs int64  `json:"latency_ms"`
 	LastCheck string `json:"last_check"`
 	Message   string `json:"message,omitempty"`
+
+	// Pool is the connection pool usage of the database component
+	Pool *health.DBPoolStats `json:"pool,omitempty"`
 }
 
 // ListenerHealth returns the health status of the obito listener
@@ -163,33 +189,13 @@ func HealthSummary(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	// If health monitor is available, use its cached summary
+	// If health monitor is available, use its cached summary (checking now only before the first check has run)
 	if globalHealthMonitor != nil {
-		summary := globalHealthMonitor.GetHealthSummary(ctx)
-
-		response := HealthSummaryResponse{
-			Status:     string(summary.Status),
-			Timestamp:  summary.Timestamp.UTC().Format(time.RFC3339),
-			Components: make(map[string]ComponentStatusJSON),
-		}
-
-		for key, comp := range summary.Components {
-			response.Components[key] = ComponentStatusJSON{
-				Name:      comp.Name,
-				Status:    string(comp.Status),
-				LatencyMs: comp.LatencyMs,
-				LastCheck: comp.LastCheck.UTC().Format(time.RFC3339),
-				Message:   comp.Message,
-			}
+		summary := globalHealthMonitor.GetLastSummary()
+		if summary == nil {
+			summary = globalHealthMonitor.GetHealthSummary(ctx)
 		}
-
-		// Determine status code
-		statusCode := http.StatusOK
-		if summary.Status == health.StatusDown {
-			statusCode = http.StatusServiceUnavailable
-		}
-
-		c.JSON(statusCode, response)
+		c.JSON(healthSummaryStatusCode(summary), newHealthSummaryResponse(summary))
 		return
 	}
 
@@ -217,3 +223,74 @@ func HealthSummary(c *gin.Context) {
 
 	c.JSON(http.StatusServiceUnavailable, response)
 }
+
+// Liveness reports that the process is up, without checking any dependency
+// GET /healthz (public)
+func Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "alive",
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// Readiness checks that the database and Redis are reachable and the background services are running
+// Responds 503 when any of them is down, so the instance is taken out of rotation without being restarted
+// GET /readyz (public)
+func Readiness(c *gin.Context) {
+	if globalReadinessChecker == nil {
+		respondError(c, http.StatusServiceUnavailable, ErrCodeUnavailable, "health monitor not initialized")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	summary := globalReadinessChecker.CheckReadiness(ctx)
+	c.JSON(healthSummaryStatusCode(summary), newHealthSummaryResponse(summary))
+}
+
+// TriggerHealthCheck runs all health checks now and returns fresh results, also refreshing the cached summary
+// POST /api/v1/health/check (admin)
+func TriggerHealthCheck(c *gin.Context) {
+	if onDemandHealthChecker == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "health monitor not initialized"})
+		return
+	}
+
+	// Checks time out individually (health.DefaultTimeout); this bounds the whole run
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	summary := onDemandHealthChecker.CheckNow(ctx)
+	c.JSON(healthSummaryStatusCode(summary), newHealthSummaryResponse(summary))
+}
+
+// newHealthSummaryResponse converts a health summary to its JSON response
+func newHealthSummaryResponse(summary *health.HealthSummary) HealthSummaryResponse {
+	response := HealthSummaryResponse{
+		Status:     string(summary.Status),
+		Timestamp:  summary.Timestamp.UTC().Format(time.RFC3339),
+		Components: make(map[string]ComponentStatusJSON),
+	}
+
+	for key, comp := range summary.Components {
+		response.Components[key] = ComponentStatusJSON{
+			Name:      comp.Name,
+			Status:    string(comp.Status),
+			LatencyMs: comp.LatencyMs,
+			LastCheck: comp.LastCheck.UTC().Format(time.RFC3339),
+			Message:   comp.Message,
+			Pool:      comp.Pool,
+		}
+	}
+
+	return response
+}
+
+// healthSummaryStatusCode returns 503 when the system is down
+func healthSummaryStatusCode(summary *health.HealthSummary) int {
+	if summary.Status == health.StatusDown {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
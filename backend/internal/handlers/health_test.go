@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sidot/backend/internal/services/health"
+)
+
+// fakeHealthChecker returns a freshly timestamped summary on each check, replacing the cached one like the monitor does
+type fakeHealthChecker struct {
+	cached *health.HealthSummary
+}
+
+func (f *fakeHealthChecker) CheckNow(ctx context.Context) *health.HealthSummary {
+	now := time.Now()
+	f.cached = &health.HealthSummary{
+		Status:    health.StatusUp,
+		Timestamp: now,
+		Components: map[string]health.ComponentStatus{
+			"redis": {Name: "Redis", Status: health.StatusUp, LastCheck: now},
+		},
+	}
+	return f.cached
+}
+
+// TestTriggerHealthCheckReturnsFreshResults tests that an on-demand check returns results newer than the cached summary
+func TestTriggerHealthCheckReturnsFreshResults(t *testing.T) {
+	cachedAt := time.Now().Add(-10 * time.Minute)
+	checker := &fakeHealthChecker{cached: &health.HealthSummary{
+		Status:    health.StatusDown,
+		Timestamp: cachedAt,
+		Components: map[string]health.ComponentStatus{
+			"redis": {Name: "Redis", Status: health.StatusDown, LastCheck: cachedAt},
+		},
+	}}
+
+	previous := onDemandHealthChecker
+	onDemandHealthChecker = checker
+	defer func() { onDemandHealthChecker = previous }()
+
+	router := setupTestRouter()
+	router.POST("/health/check", mockAuthMiddleware("user-id", "admin"), TriggerHealthCheck)
+
+	req := httptest.NewRequest(http.MethodPost, "/health/check", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response HealthSummaryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, response.Timestamp)
+	if err != nil {
+		t.Fatalf("Invalid timestamp %q: %v", response.Timestamp, err)
+	}
+	if !timestamp.After(cachedAt) || time.Since(timestamp) > time.Minute {
+		t.Errorf("Expected a fresh timestamp (cached at %s), got %s", cachedAt.UTC().Format(time.RFC3339), response.Timestamp)
+	}
+	if response.Status != string(health.StatusUp) || response.Components["redis"].Status != string(health.StatusUp) {
+		t.Errorf("Expected the fresh results, got %+v", response)
+	}
+}
+
+// TestTriggerHealthCheckWithoutMonitor tests that the endpoint reports an unavailable monitor
+func TestTriggerHealthCheckWithoutMonitor(t *testing.T) {
+	previous := onDemandHealthChecker
+	onDemandHealthChecker = nil
+	defer func() { onDemandHealthChecker = previous }()
+
+	router := setupTestRouter()
+	router.POST("/health/check", TriggerHealthCheck)
+
+	req := httptest.NewRequest(http.MethodPost, "/health/check", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", w.Code)
+	}
+}
+
+// fakeReadinessChecker returns a readiness summary with the given redis status
+type fakeReadinessChecker struct {
+	redis health.ServiceStatus
+}
+
+func (f *fakeReadinessChecker) CheckReadiness(ctx context.Context) *health.HealthSummary {
+	now := time.Now()
+	return &health.HealthSummary{
+		Status:    f.redis,
+		Timestamp: now,
+		Components: map[string]health.ComponentStatus{
+			"database": {Name: "PostgreSQL", Status: health.StatusUp, LastCheck: now},
+			"redis":    {Name: "Redis", Status: f.redis, LastCheck: now},
+		},
+	}
+}
+
+// serveReadiness calls /readyz with the given readiness checker
+func serveReadiness(t *testing.T, checker readinessChecker) *httptest.ResponseRecorder {
+	t.Helper()
+
+	previous := globalReadinessChecker
+	globalReadinessChecker = checker
+	defer func() { globalReadinessChecker = previous }()
+
+	router := setupTestRouter()
+	router.GET("/readyz", Readiness)
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestReadinessDependencyDown tests that readiness responds 503 when a dependency is down
+func TestReadinessDependencyDown(t *testing.T) {
+	w := serveReadiness(t, &fakeReadinessChecker{redis: health.StatusDown})
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status 503, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response HealthSummaryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Components["redis"].Status != string(health.StatusDown) {
+		t.Errorf("Expected redis to be reported down, got %+v", response.Components["redis"])
+	}
+}
+
+// TestReadinessDependenciesUp tests that readiness responds 200 when dependencies are up or degraded
+func TestReadinessDependenciesUp(t *testing.T) {
+	for _, status := range []health.ServiceStatus{health.StatusUp, health.StatusDegraded} {
+		w := serveReadiness(t, &fakeReadinessChecker{redis: status})
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 with redis %s, got %d", status, w.Code)
+		}
+	}
+}
+
+// TestReadinessWithoutMonitor tests that the instance is not ready before the monitor is initialized
+func TestReadinessWithoutMonitor(t *testing.T) {
+	w := serveReadiness(t, nil)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", w.Code)
+	}
+}
+
+// TestLiveness tests that liveness responds 200 without checking dependencies
+func TestLiveness(t *testing.T) {
+	router := setupTestRouter()
+	router.GET("/healthz", Liveness)
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
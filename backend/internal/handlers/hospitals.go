@@ -99,6 +99,10 @@ func CreateHospital(c *gin.Context) {
 		})
 		return
 	}
+	if err := models.ValidateTimezone(input.Timezone); err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeValidationFailed, "validation failed", err.Error())
+		return
+	}
 
 	hospital, err := hospitalRepo.Create(c.Request.Context(), &input)
 	if err != nil {
@@ -146,6 +150,10 @@ func UpdateHospital(c *gin.Context) {
 		})
 		return
 	}
+	if err := models.ValidateTimezone(input.Timezone); err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeValidationFailed, "validation failed", err.Error())
+		return
+	}
 
 	hospital, err := hospitalRepo.Update(c.Request.Context(), id, &input)
 	if err != nil {
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
 	"github.com/sidot/backend/internal/repository"
 )
 
@@ -21,6 +25,8 @@ func SetIndicatorsRepository(repo *repository.IndicatorsRepository) {
 //
 // Query params:
 // - hospital_id (optional, UUID): Filter by hospital (ignored for operador role)
+// - group_by (optional, hospital|day|week): Adds a breakdown of the last 30 days with conversion rates per group
+// - date_from, date_to (optional, YYYY-MM-DD): Days of the detected obitos of the conversion funnel (default: last 30 days)
 //
 // Permissions:
 // - admin/gestor: Can view all data or filter by hospital
@@ -40,6 +46,20 @@ func GetIndicators(c *gin.Context) {
 		return
 	}
 
+	groupBy := models.IndicatorsGroupBy(c.Query("group_by"))
+	if groupBy != "" && !groupBy.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid group_by - must be hospital, day or week",
+		})
+		return
+	}
+
+	funnelFrom, funnelTo, err := parseFunnelRange(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+		return
+	}
+
 	// Determine hospital_id filter based on role
 	var hospitalID *uuid.UUID
 
@@ -74,8 +94,16 @@ func GetIndicators(c *gin.Context) {
 		}
 	}
 
-	// Get all indicators
-	metrics, err := indicatorsRepo.GetAllIndicators(ctx, hospitalID)
+	cacheParams := "hospital_id=&group_by=" + string(groupBy)
+	if hospitalID != nil {
+		cacheParams = "hospital_id=" + hospitalID.String() + "&group_by=" + string(groupBy)
+	}
+	cacheParams += "&date_from=" + funnelFrom.Format(funnelDateLayout) + "&date_to=" + funnelTo.Format(funnelDateLayout)
+
+	var metrics models.IndicatorsMetrics
+	_, err = metricsCache.Fetch(ctx, "indicators", cacheParams, &metrics, func() (interface{}, error) {
+		return computeIndicators(ctx, hospitalID, groupBy, funnelFrom, funnelTo)
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "failed to fetch indicators",
@@ -86,3 +114,57 @@ func GetIndicators(c *gin.Context) {
 
 	c.JSON(http.StatusOK, metrics)
 }
+
+// funnelDateLayout is the format of the date range of the conversion funnel
+const funnelDateLayout = "2006-01-02"
+
+// parseFunnelRange parses the date_from and date_to of the conversion funnel, defaulting to the last 30 days
+func parseFunnelRange(c *gin.Context) (time.Time, time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	dateFrom, dateTo := today.AddDate(0, 0, -29), today
+
+	if param := c.Query("date_from"); param != "" {
+		t, err := time.Parse(funnelDateLayout, param)
+		if err != nil {
+			return dateFrom, dateTo, fmt.Errorf("date_from must be in YYYY-MM-DD format")
+		}
+		dateFrom = t
+	}
+	if param := c.Query("date_to"); param != "" {
+		t, err := time.Parse(funnelDateLayout, param)
+		if err != nil {
+			return dateFrom, dateTo, fmt.Errorf("date_to must be in YYYY-MM-DD format")
+		}
+		dateTo = t
+	}
+	if dateTo.Before(dateFrom) {
+		return dateFrom, dateTo, fmt.Errorf("date_from must not be after date_to")
+	}
+
+	return dateFrom, dateTo, nil
+}
+
+// computeIndicators queries the indicators and the conversion funnel, with the breakdown when groupBy is set
+func computeIndicators(ctx context.Context, hospitalID *uuid.UUID, groupBy models.IndicatorsGroupBy, funnelFrom, funnelTo time.Time) (*models.IndicatorsMetrics, error) {
+	metrics, err := indicatorsRepo.GetAllIndicators(ctx, hospitalID)
+	if err != nil {
+		return nil, err
+	}
+
+	funnel, err := indicatorsRepo.GetConversionFunnel(ctx, funnelFrom, funnelTo, hospitalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch conversion funnel: %w", err)
+	}
+	metrics.Funil = funnel
+
+	if groupBy != "" {
+		breakdown, err := indicatorsRepo.GetBreakdown(ctx, groupBy, hospitalID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch indicators breakdown: %w", err)
+		}
+		metrics.Breakdown = breakdown
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// ineligibilityLister lists the ineligibility records of the triagem
+// (implemented by repository.ObitoIneligibilityRepository)
+type ineligibilityLister interface {
+	List(ctx context.Context, filters models.ObitoIneligibilityFilters) ([]models.ObitoIneligibility, int, error)
+}
+
+var ineligibilityRepo ineligibilityLister
+
+// SetIneligibilityRepository sets the obito ineligibility repository for handlers
+func SetIneligibilityRepository(repo *repository.ObitoIneligibilityRepository) {
+	if repo == nil {
+		ineligibilityRepo = nil
+		return
+	}
+	ineligibilityRepo = repo
+}
+
+// ListIneligibilities returns the obitos found ineligible by the triagem, newest first, with their reasons
+// and evaluated rules, filtered by motivo (partial match), date_from/date_to (YYYY-MM-DD) or hospital_id
+// GET /api/v1/ineligibilities
+func ListIneligibilities(c *gin.Context) {
+	if ineligibilityRepo == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "ineligibility repository not configured")
+		return
+	}
+
+	filters := models.ObitoIneligibilityFilters{Page: 1, PageSize: 20, Motivo: c.Query("motivo")}
+
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		t, err := time.Parse("2006-01-02", dateFrom)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "date_from must be in YYYY-MM-DD format")
+			return
+		}
+		filters.DateFrom = &t
+	}
+
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		t, err := time.Parse("2006-01-02", dateTo)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "date_to must be in YYYY-MM-DD format")
+			return
+		}
+		// Set to end of day
+		t = t.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+		filters.DateTo = &t
+	}
+
+	if hospitalIDStr := c.Query("hospital_id"); hospitalIDStr != "" {
+		hospitalID, err := uuid.Parse(hospitalIDStr)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid hospital_id format")
+			return
+		}
+		filters.HospitalID = &hospitalID
+	}
+
+	if page := c.Query("page"); page != "" {
+		p, err := strconv.Atoi(page)
+		if err != nil || p < 1 {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid page number")
+			return
+		}
+		filters.Page = p
+	}
+
+	if pageSize := c.Query("page_size"); pageSize != "" {
+		ps, err := strconv.Atoi(pageSize)
+		if err != nil || ps < 1 || ps > 100 {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid page_size (1-100)")
+			return
+		}
+		filters.PageSize = ps
+	}
+
+	ineligibilities, totalItems, err := ineligibilityRepo.List(c.Request.Context(), filters)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to list ineligibilities")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewPaginatedResponse(ineligibilities, filters.Page, filters.PageSize, totalItems).WithLinks(c.Request.URL, "page_size"))
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// listPresetStore stores the list presets of the users (implemented by repository.ListPresetRepository)
+type listPresetStore interface {
+	Save(ctx context.Context, userID uuid.UUID, list string, input *models.SaveListPresetInput) (*models.ListPreset, error)
+	GetByName(ctx context.Context, userID uuid.UUID, list, nome string) (*models.ListPreset, error)
+	List(ctx context.Context, userID uuid.UUID, list string) ([]models.ListPreset, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+var listPresets listPresetStore
+
+// SetListPresetRepository sets the list preset repository for handlers
+func SetListPresetRepository(repo *repository.ListPresetRepository) {
+	listPresets = repo
+}
+
+// ListMyOccurrencePresets returns the occurrence list presets of the current user
+// GET /api/v1/users/me/occurrence-presets
+func ListMyOccurrencePresets(c *gin.Context) {
+	userID, ok := listPresetUserID(c)
+	if !ok {
+		return
+	}
+
+	presets, err := listPresets.List(c.Request.Context(), userID, models.ListPresetOccurrences)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Erro ao buscar filtros salvos")
+		return
+	}
+
+	c.JSON(http.StatusOK, presets)
+}
+
+// SaveMyOccurrencePreset saves an occurrence list preset of the current user, replacing the one of the same name
+// The params are checked like the query parameters of the occurrence list
+// PUT /api/v1/users/me/occurrence-presets
+func SaveMyOccurrencePreset(c *gin.Context) {
+	userID, ok := listPresetUserID(c)
+	if !ok {
+		return
+	}
+
+	var input models.SaveListPresetInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, err.Error())
+		return
+	}
+
+	if err := input.Validate(models.OccurrenceListPresetParams); err != nil {
+		respondDomainError(c, err, "Filtro salvo inválido")
+		return
+	}
+
+	if _, err := occurrenceListFiltersFromQuery(listPresetQuery(input.Params)); err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeValidationFailed, "Filtro salvo inválido", err.Error())
+		return
+	}
+
+	preset, err := listPresets.Save(c.Request.Context(), userID, models.ListPresetOccurrences, &input)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Erro ao salvar filtro")
+		return
+	}
+
+	c.JSON(http.StatusOK, preset)
+}
+
+// DeleteMyOccurrencePreset deletes an occurrence list preset of the current user
+// DELETE /api/v1/users/me/occurrence-presets/:id
+func DeleteMyOccurrencePreset(c *gin.Context) {
+	userID, ok := listPresetUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "ID do filtro inválido")
+		return
+	}
+
+	if err := listPresets.Delete(c.Request.Context(), userID, id); err != nil {
+		respondDomainError(c, err, "Erro ao remover filtro")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// expandOccurrenceListPreset adds the params of the named preset of the current user to the query,
+// keeping the parameters already in it. On failure it writes the error response.
+func expandOccurrenceListPreset(c *gin.Context, nome string, query url.Values) bool {
+	userID, ok := listPresetUserID(c)
+	if !ok {
+		return false
+	}
+
+	preset, err := listPresets.GetByName(c.Request.Context(), userID, models.ListPresetOccurrences, nome)
+	if err != nil {
+		respondDomainError(c, err, "Erro ao buscar filtro salvo")
+		return false
+	}
+
+	for key, value := range preset.Params {
+		if !query.Has(key) {
+			query.Set(key, value)
+		}
+	}
+	return true
+}
+
+// listPresetUserID returns the ID of the current user; on failure it writes the error response
+func listPresetUserID(c *gin.Context) (uuid.UUID, bool) {
+	if listPresets == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "list preset repository not configured")
+		return uuid.Nil, false
+	}
+
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Não autorizado")
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Não autorizado")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// listPresetQuery converts the params of a preset into query parameters
+func listPresetQuery(params map[string]string) url.Values {
+	query := url.Values{}
+	for key, value := range params {
+		query.Set(key, value)
+	}
+	return query
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// fakeListPresetStore keeps the presets in memory by user, list and name
+type fakeListPresetStore struct {
+	presets map[string]*models.ListPreset
+}
+
+func (f *fakeListPresetStore) key(userID uuid.UUID, list, nome string) string {
+	return userID.String() + "/" + list + "/" + nome
+}
+
+func (f *fakeListPresetStore) Save(ctx context.Context, userID uuid.UUID, list string, input *models.SaveListPresetInput) (*models.ListPreset, error) {
+	preset := &models.ListPreset{ID: uuid.New(), UserID: userID, List: list, Nome: input.Nome, Params: input.Params, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	f.presets[f.key(userID, list, input.Nome)] = preset
+	return preset, nil
+}
+
+func (f *fakeListPresetStore) GetByName(ctx context.Context, userID uuid.UUID, list, nome string) (*models.ListPreset, error) {
+	if preset, ok := f.presets[f.key(userID, list, nome)]; ok {
+		return preset, nil
+	}
+	return nil, models.ErrListPresetNotFound
+}
+
+func (f *fakeListPresetStore) List(ctx context.Context, userID uuid.UUID, list string) ([]models.ListPreset, error) {
+	presets := []models.ListPreset{}
+	for _, preset := range f.presets {
+		if preset.UserID == userID && preset.List == list {
+			presets = append(presets, *preset)
+		}
+	}
+	return presets, nil
+}
+
+func (f *fakeListPresetStore) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	for key, preset := range f.presets {
+		if preset.UserID == userID && preset.ID == id {
+			delete(f.presets, key)
+			return nil
+		}
+	}
+	return models.ErrListPresetNotFound
+}
+
+// fakeOccurrenceLister filters the occurrences by status and sorts them by score
+type fakeOccurrenceLister struct {
+	occurrences []models.Occurrence
+}
+
+func (f *fakeOccurrenceLister) List(ctx context.Context, filters models.OccurrenceListFilters) ([]models.Occurrence, int, error) {
+	var result []models.Occurrence
+	for _, o := range f.occurrences {
+		if filters.Status == nil || o.Status == *filters.Status {
+			result = append(result, o)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if filters.SortOrder == "asc" {
+			return result[i].ScorePriorizacao < result[j].ScorePriorizacao
+		}
+		return result[i].ScorePriorizacao > result[j].ScorePriorizacao
+	})
+	return result, len(result), nil
+}
+
+// setupListPresetRouter installs the fakes for the duration of a test and routes the preset and list endpoints
+func setupListPresetRouter(t *testing.T, userID string) (*gin.Engine, *fakeListPresetStore) {
+	t.Helper()
+
+	store := &fakeListPresetStore{presets: map[string]*models.ListPreset{}}
+	hospitalID := uuid.New()
+	lister := &fakeOccurrenceLister{}
+	for i, status := range []models.OccurrenceStatus{models.StatusPendente, models.StatusEmAndamento, models.StatusPendente, models.StatusPendente} {
+		occurrence := createTestOccurrence(status, hospitalID)
+		occurrence.ScorePriorizacao = 40 + i*10
+		lister.occurrences = append(lister.occurrences, occurrence)
+	}
+
+	previousPresets, previousLists := listPresets, occurrenceLists
+	listPresets, occurrenceLists = store, lister
+	t.Cleanup(func() { listPresets, occurrenceLists = previousPresets, previousLists })
+
+	router := setupTestRouter()
+	router.Use(mockAuthMiddleware(userID, "operador"))
+	router.GET("/api/v1/occurrences", ListOccurrences)
+	router.GET("/api/v1/users/me/occurrence-presets", ListMyOccurrencePresets)
+	router.PUT("/api/v1/users/me/occurrence-presets", SaveMyOccurrencePreset)
+	router.DELETE("/api/v1/users/me/occurrence-presets/:id", DeleteMyOccurrencePreset)
+	return router, store
+}
+
+func serveListPresetRequest(router *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// listedOccurrenceIDs returns the IDs of an occurrence list response, in order
+func listedOccurrenceIDs(t *testing.T, w *httptest.ResponseRecorder) []uuid.UUID {
+	t.Helper()
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response struct {
+		Data []models.OccurrenceListResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	ids := make([]uuid.UUID, len(response.Data))
+	for i, o := range response.Data {
+		ids[i] = o.ID
+	}
+	return ids
+}
+
+// TestOccurrencePresetAppliesSavedFilters tests that a saved preset lists the same occurrences as its parameters
+func TestOccurrencePresetAppliesSavedFilters(t *testing.T) {
+	userID := uuid.New().String()
+	router, store := setupListPresetRouter(t, userID)
+
+	w := serveListPresetRequest(router, http.MethodPut, "/api/v1/users/me/occurrence-presets", models.SaveListPresetInput{
+		Nome:   "Pendentes",
+		Params: map[string]string{"status": "PENDENTE", "sort": "score:asc"},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if _, err := store.GetByName(context.Background(), uuid.MustParse(userID), models.ListPresetOccurrences, "Pendentes"); err != nil {
+		t.Fatalf("Expected the preset to be saved for the user: %v", err)
+	}
+
+	expected := listedOccurrenceIDs(t, serveListPresetRequest(router, http.MethodGet, "/api/v1/occurrences?status=PENDENTE&sort=score:asc", nil))
+	got := listedOccurrenceIDs(t, serveListPresetRequest(router, http.MethodGet, "/api/v1/occurrences?preset=Pendentes", nil))
+
+	if len(expected) != 3 || len(got) != len(expected) {
+		t.Fatalf("Expected the 3 pending occurrences, got %v (explicit filters %v)", got, expected)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Position %d: expected occurrence %s, got %s", i, expected[i], got[i])
+		}
+	}
+
+	// Parameters of the request take precedence over the preset
+	overridden := listedOccurrenceIDs(t, serveListPresetRequest(router, http.MethodGet, "/api/v1/occurrences?preset=Pendentes&sort=score:desc", nil))
+	if len(overridden) != 3 || overridden[0] != expected[2] {
+		t.Errorf("Expected the request sort to reverse the preset order, got %v", overridden)
+	}
+}
+
+// TestOccurrencePresetScopedToUser tests that presets of other users are not found
+func TestOccurrencePresetScopedToUser(t *testing.T) {
+	router, store := setupListPresetRouter(t, uuid.New().String())
+
+	store.Save(context.Background(), uuid.New(), models.ListPresetOccurrences, &models.SaveListPresetInput{
+		Nome:   "Pendentes",
+		Params: map[string]string{"status": "PENDENTE"},
+	})
+
+	w := serveListPresetRequest(router, http.MethodGet, "/api/v1/occurrences?preset=Pendentes", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+
+	w = serveListPresetRequest(router, http.MethodGet, "/api/v1/users/me/occurrence-presets", nil)
+	var presets []models.ListPreset
+	if err := json.Unmarshal(w.Body.Bytes(), &presets); err != nil || len(presets) != 0 {
+		t.Errorf("Expected no presets for the user, got %s", w.Body.String())
+	}
+}
+
+// TestSaveOccurrencePresetRejectsInvalidParams tests that presets are checked like the list query parameters
+func TestSaveOccurrencePresetRejectsInvalidParams(t *testing.T) {
+	router, store := setupListPresetRouter(t, uuid.New().String())
+
+	for name, input := range map[string]models.SaveListPresetInput{
+		"no name":         {Nome: " ", Params: map[string]string{"status": "PENDENTE"}},
+		"unknown param":   {Nome: "Pagina 2", Params: map[string]string{"page": "2"}},
+		"invalid status":  {Nome: "Status", Params: map[string]string{"status": "ABERTA"}},
+		"invalid sort":    {Nome: "Nome", Params: map[string]string{"sort": "nome_paciente"}},
+		"invalid date_to": {Nome: "Datas", Params: map[string]string{"date_to": "ontem"}},
+	} {
+		w := serveListPresetRequest(router, http.MethodPut, "/api/v1/users/me/occurrence-presets", input)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", name, http.StatusBadRequest, w.Code)
+		}
+	}
+	if len(store.presets) != 0 {
+		t.Errorf("Expected no preset to be saved, got %d", len(store.presets))
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+const (
+	defaultLoginHistoryLimit = 20
+	maxLoginHistoryLimit     = 100
+)
+
+// loginHistoryReader reads the login history of the users (implemented by repository.LoginHistoryRepository)
+type loginHistoryReader interface {
+	ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]models.LoginHistoryEntry, error)
+}
+
+var loginHistory loginHistoryReader
+
+// SetLoginHistoryRepository sets the login history repository for handlers
+func SetLoginHistoryRepository(repo *repository.LoginHistoryRepository) {
+	loginHistory = repo
+}
+
+// ListUserLogins returns the most recent logins of a user, newest first (admin only)
+// GET /api/v1/users/:id/logins?limit=20
+func ListUserLogins(c *gin.Context) {
+	if loginHistory == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "login history repository not configured")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid user ID format")
+		return
+	}
+
+	limit := defaultLoginHistoryLimit
+	if value := c.Query("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLoginHistoryLimit {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "limit must be between 1 and 100")
+			return
+		}
+	}
+
+	entries, err := loginHistory.ListByUser(c.Request.Context(), id, limit)
+	if err != nil {
+		respondDomainError(c, err, "failed to list user logins")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": entries})
+}
@@ -2,19 +2,75 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/sidot/backend/internal/models"
 	"github.com/sidot/backend/internal/repository"
+	"github.com/sidot/backend/internal/services/geo"
+	"github.com/sidot/backend/internal/services/notification"
 )
 
+const (
+	// DefaultMapClusterZoom e o zoom assumido quando cluster=true sem zoom informado
+	DefaultMapClusterZoom = 10
+	// MaxMapZoom e o maior nivel de zoom aceito
+	MaxMapZoom = 20
+	// MapClusterMaxZoom e o zoom a partir do qual os hospitais sao retornados individualmente
+	MapClusterMaxZoom = 15
+)
+
+// mapUpdateNotifier publishes live map changes of the occurrence and shift flows
+// (implemented by notification.MapUpdatePublisher)
+type mapUpdateNotifier interface {
+	PublishOccurrenceResolved(ctx context.Context, occurrence *models.Occurrence) error
+	PublishShiftChange(ctx context.Context, hospitalID uuid.UUID) error
+}
+
+var mapUpdates mapUpdateNotifier
+
+// SetMapUpdatePublisher enables the live map updates of the occurrence and shift handlers
+func SetMapUpdatePublisher(publisher *notification.MapUpdatePublisher) {
+	if publisher != nil {
+		mapUpdates = publisher
+	}
+}
+
+// publishMapOccurrenceResolved publica no mapa ao vivo uma ocorrencia que deixou de estar ativa
+func publishMapOccurrenceResolved(ctx context.Context, occurrence *models.Occurrence) {
+	if mapUpdates == nil {
+		return
+	}
+	if err := mapUpdates.PublishOccurrenceResolved(ctx, occurrence); err != nil {
+		log.Printf("[Map] Failed to publish resolved occurrence %s: %v", occurrence.ID, err)
+	}
+}
+
+// publishMapShiftChange publica no mapa ao vivo o operador de plantao de um hospital cujas escalas mudaram
+func publishMapShiftChange(ctx context.Context, hospitalID uuid.UUID) {
+	if mapUpdates == nil {
+		return
+	}
+	if err := mapUpdates.PublishShiftChange(ctx, hospitalID); err != nil {
+		log.Printf("[Map] Failed to publish shift change of hospital %s: %v", hospitalID, err)
+	}
+}
+
 // MapHandler handles map-related HTTP requests
 type MapHandler struct {
 	hospitalRepo   *repository.HospitalRepository
 	occurrenceRepo *repository.OccurrenceRepository
 	shiftRepo      *repository.ShiftRepository
+
+	// Optional tenant urgency bands; the built-in bands apply without them
+	urgencyThresholds notification.UrgencyThresholdsSource
 }
 
 // NewMapHandler creates a new map handler
@@ -30,11 +86,48 @@ func NewMapHandler(
 	}
 }
 
+// SetUrgencyThresholds sets the tenant urgency bands used to classify the occurrences of the map
+func (h *MapHandler) SetUrgencyThresholds(source notification.UrgencyThresholdsSource) {
+	h.urgencyThresholds = source
+}
+
 // GetMapHospitals returns all active hospitals with coordinates and their occurrences for map rendering
 // GET /api/v1/map/hospitals
+// Optional: ?cluster=true&zoom=N groups nearby hospitals into clusters for low zoom levels
+// Optional: ?min_urgency=green|yellow|red returns only hospitals whose max urgency is at least that level
+// Optional: ?from_lat=&from_lng= adds the distance from the reference point and sorts hospitals nearest-first
 func (h *MapHandler) GetMapHospitals(c *gin.Context) {
 	ctx := c.Request.Context()
 
+	cluster := c.Query("cluster") == "true"
+	zoom := DefaultMapClusterZoom
+	if zoomParam := c.Query("zoom"); zoomParam != "" {
+		z, err := strconv.Atoi(zoomParam)
+		if err != nil || z < 0 || z > MaxMapZoom {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Zoom invalido (0-%d)", MaxMapZoom)})
+			return
+		}
+		zoom = z
+	}
+
+	// Filtro opcional de urgencia minima (ex.: salas de triagem exibem apenas hospitais em vermelho)
+	var minUrgency models.UrgencyLevel
+	if urgencyParam := c.Query("min_urgency"); urgencyParam != "" {
+		level, ok := models.ParseUrgencyLevel(urgencyParam)
+		if !ok {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "min_urgency invalido (green, yellow, red)")
+			return
+		}
+		minUrgency = level
+	}
+
+	// Ponto de referencia opcional (ex.: transporte de cornea): distancia ate cada hospital, do mais proximo ao mais distante
+	from, hasFrom, ok := parseMapReferencePoint(c)
+	if !ok {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "Coordenadas de referencia invalidas (from_lat entre -90 e 90, from_lng entre -180 e 180)")
+		return
+	}
+
 	// Buscar hospitais ativos com coordenadas
 	hospitals, err := h.hospitalRepo.GetActiveHospitalsWithCoordinates(ctx)
 	if err != nil {
@@ -43,7 +136,7 @@ func (h *MapHandler) GetMapHospitals(c *gin.Context) {
 	}
 
 	now := time.Now()
-	dayOfWeek := int(now.Weekday())
+	urgencyThresholds := notification.LoadUrgencyThresholds(ctx, h.urgencyThresholds, log.Default())
 
 	// Construir resposta com dados agregados para cada hospital
 	var mapHospitals []models.MapHospitalResponse
@@ -56,6 +149,9 @@ func (h *MapHandler) GetMapHospitals(c *gin.Context) {
 			continue // Pular hospital em caso de erro
 		}
 
+		// Faixas de urgencia do tenant do hospital
+		thresholds := urgencyThresholds.For(hospital.TenantID)
+
 		// Construir resposta do hospital
 		hospitalResp := models.MapHospitalResponse{
 			ID:               hospital.ID,
@@ -64,17 +160,18 @@ func (h *MapHandler) GetMapHospitals(c *gin.Context) {
 			Latitude:         *hospital.Latitude,
 			Longitude:        *hospital.Longitude,
 			Ativo:            hospital.Ativo,
-			UrgenciaMaxima:   models.CalculateMaxUrgency(activeOccurrences),
+			UrgenciaMaxima:   models.CalculateMaxUrgency(activeOccurrences, thresholds),
 			OcorrenciasCount: len(activeOccurrences),
 		}
 
 		// Adicionar ocorrencias formatadas para o mapa
 		for _, occ := range activeOccurrences {
-			hospitalResp.Ocorrencias = append(hospitalResp.Ocorrencias, occ.ToMapOccurrenceResponse())
+			hospitalResp.Ocorrencias = append(hospitalResp.Ocorrencias, occ.ToMapOccurrenceResponse(thresholds))
 		}
 
-		// Buscar operador de plantao atual
-		activeShifts, err := h.shiftRepo.GetActiveShifts(ctx, hospital.ID, dayOfWeek, now)
+		// Buscar operador de plantao atual, no fuso horario do hospital
+		local := now.In(hospital.Location())
+		activeShifts, err := h.shiftRepo.GetActiveShifts(ctx, hospital.ID, int(local.Weekday()), local)
 		if err == nil && len(activeShifts) > 0 {
 			// Usar o primeiro operador ativo encontrado
 			shift := activeShifts[0]
@@ -90,12 +187,128 @@ func (h *MapHandler) GetMapHospitals(c *gin.Context) {
 		mapHospitals = append(mapHospitals, hospitalResp)
 	}
 
-	c.JSON(http.StatusOK, models.MapDataResponse{
+	if minUrgency != "" {
+		mapHospitals = filterMapHospitalsByUrgency(mapHospitals, minUrgency)
+	}
+	if hasFrom {
+		sortMapHospitalsByDistance(mapHospitals, from)
+	}
+
+	response := models.MapDataResponse{
 		Hospitals: mapHospitals,
 		Total:     len(mapHospitals),
+	}
+
+	// Agrupar hospitais proximos em zoom baixo; em zoom alto o detalhe por hospital e mantido
+	if cluster && zoom < MapClusterMaxZoom {
+		response.Clusters = clusterMapHospitals(mapHospitals, zoom)
+		response.Hospitals = nil
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// mapClusterCellSize retorna o tamanho da celula da grade (em graus) para o zoom
+// Corresponde a largura de um tile do mapa nesse zoom (360 / 2^zoom)
+func mapClusterCellSize(zoom int) float64 {
+	return 360.0 / math.Pow(2, float64(zoom))
+}
+
+// parseMapReferencePoint le o ponto de referencia de from_lat e from_lng
+// Retorna hasFrom=false sem os parametros e ok=false quando estao incompletos ou fora dos limites
+func parseMapReferencePoint(c *gin.Context) (from geo.Point, hasFrom bool, ok bool) {
+	latParam, lngParam := c.Query("from_lat"), c.Query("from_lng")
+	if latParam == "" && lngParam == "" {
+		return geo.Point{}, false, true
+	}
+
+	lat, errLat := strconv.ParseFloat(latParam, 64)
+	lng, errLng := strconv.ParseFloat(lngParam, 64)
+	from = geo.Point{Lat: lat, Lng: lng}
+	if errLat != nil || errLng != nil || !from.IsValid() {
+		return geo.Point{}, false, false
+	}
+	return from, true, true
+}
+
+// sortMapHospitalsByDistance preenche a distancia em linha reta e o tempo estimado ate cada hospital
+// e ordena do mais proximo ao mais distante
+func sortMapHospitalsByDistance(hospitals []models.MapHospitalResponse, from geo.Point) {
+	for i := range hospitals {
+		distance := geo.DistanceKm(from, geo.Point{Lat: hospitals[i].Latitude, Lng: hospitals[i].Longitude})
+		eta := int(math.Round(geo.EstimateTravelTime(distance, geo.DefaultAverageSpeedKmh).Minutes()))
+		distance = math.Round(distance*100) / 100
+		hospitals[i].DistanciaKm = &distance
+		hospitals[i].TempoEstimadoMinutos = &eta
+	}
+
+	sort.SliceStable(hospitals, func(i, j int) bool {
+		return *hospitals[i].DistanciaKm < *hospitals[j].DistanciaKm
 	})
 }
 
+// filterMapHospitalsByUrgency mantem apenas os hospitais com urgencia maxima igual ou acima de min
+// Hospitais sem ocorrencias ativas (urgencia none) nunca passam no filtro
+func filterMapHospitalsByUrgency(hospitals []models.MapHospitalResponse, min models.UrgencyLevel) []models.MapHospitalResponse {
+	var filtered []models.MapHospitalResponse
+	for _, hospital := range hospitals {
+		if hospital.UrgenciaMaxima.IsAtLeast(min) {
+			filtered = append(filtered, hospital)
+		}
+	}
+	return filtered
+}
+
+// clusterMapHospitals agrupa hospitais por celula de uma grade dependente do zoom
+// O centroide e a media das coordenadas; contagens e urgencia maxima sao agregadas
+func clusterMapHospitals(hospitals []models.MapHospitalResponse, zoom int) []models.MapClusterResponse {
+	cellSize := mapClusterCellSize(zoom)
+
+	clusters := []models.MapClusterResponse{}
+	index := make(map[string]int)
+
+	for i := range hospitals {
+		hospital := hospitals[i]
+		row := int(math.Floor(hospital.Latitude / cellSize))
+		col := int(math.Floor(hospital.Longitude / cellSize))
+		key := fmt.Sprintf("%d:%d:%d", zoom, row, col)
+
+		pos, exists := index[key]
+		if !exists {
+			clusters = append(clusters, models.MapClusterResponse{
+				ID:             key,
+				HospitalIDs:    []uuid.UUID{},
+				UrgenciaMaxima: models.UrgencyNone,
+			})
+			pos = len(clusters) - 1
+			index[key] = pos
+		}
+
+		c := &clusters[pos]
+		// Media incremental das coordenadas
+		c.Latitude += (hospital.Latitude - c.Latitude) / float64(c.HospitalCount+1)
+		c.Longitude += (hospital.Longitude - c.Longitude) / float64(c.HospitalCount+1)
+		c.HospitalCount++
+		c.HospitalIDs = append(c.HospitalIDs, hospital.ID)
+		c.OcorrenciasCount += hospital.OcorrenciasCount
+		c.UrgenciaMaxima = models.MaxUrgencyLevel(c.UrgenciaMaxima, hospital.UrgenciaMaxima)
+	}
+
+	// Clusters com um unico hospital levam o detalhe completo
+	for i := range clusters {
+		if clusters[i].HospitalCount == 1 {
+			for j := range hospitals {
+				if hospitals[j].ID == clusters[i].HospitalIDs[0] {
+					clusters[i].Hospital = &hospitals[j]
+					break
+				}
+			}
+		}
+	}
+
+	return clusters
+}
+
 // getActiveOccurrencesByHospital busca ocorrencias ativas (PENDENTE e EM_ANDAMENTO) para um hospital
 func (h *MapHandler) getActiveOccurrencesByHospital(ctx context.Context, hospitalID string) ([]models.Occurrence, error) {
 	// Filtrar por status PENDENTE e EM_ANDAMENTO
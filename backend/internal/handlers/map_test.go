@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services/geo"
 )
 
 // MockMapRepository simula o repositorio do mapa para testes
@@ -339,7 +341,7 @@ func TestMapEndpointCalculatesMaxUrgency(t *testing.T) {
 			for _, h := range hospitals {
 				occurrences, _ := mockRepo.GetActiveOccurrencesByHospitalID(ctx, h.ID)
 
-				urgencia := models.CalculateMaxUrgency(occurrences)
+				urgencia := models.CalculateMaxUrgency(occurrences, models.DefaultUrgencyThresholds())
 
 				if urgencia != tc.expectedUrgency {
 					t.Errorf("Esperado urgencia %s, recebido %s", tc.expectedUrgency, urgencia)
@@ -436,6 +438,7 @@ func TestMapEndpointFiltersOccurrencesByActiveStatus(t *testing.T) {
 		models.StatusRecusada,
 		models.StatusCancelada,
 		models.StatusConcluida,
+		models.StatusExpirada,
 	}
 
 	for _, status := range statuses {
@@ -457,3 +460,217 @@ func TestMapEndpointFiltersOccurrencesByActiveStatus(t *testing.T) {
 		}
 	}
 }
+
+// Test: Testar que hospitais proximos sao agrupados em zoom baixo e separados em zoom alto
+func TestMapClusteringMergesCloseHospitalsAtLowZoom(t *testing.T) {
+	hospitals := []models.MapHospitalResponse{
+		{
+			ID:               uuid.New(),
+			Nome:             "Hospital Geral de Goiania",
+			Latitude:         -16.6868,
+			Longitude:        -49.2648,
+			UrgenciaMaxima:   models.UrgencyYellow,
+			OcorrenciasCount: 2,
+		},
+		{
+			ID:               uuid.New(),
+			Nome:             "Hospital de Urgencias",
+			Latitude:         -16.7200,
+			Longitude:        -49.3000,
+			UrgenciaMaxima:   models.UrgencyRed,
+			OcorrenciasCount: 1,
+		},
+	}
+
+	// Zoom baixo: os dois hospitais (~5km) caem na mesma celula
+	clusters := clusterMapHospitals(hospitals, 8)
+	if len(clusters) != 1 {
+		t.Fatalf("Esperado 1 cluster no zoom 8, recebido %d", len(clusters))
+	}
+
+	cluster := clusters[0]
+	if cluster.HospitalCount != 2 || len(cluster.HospitalIDs) != 2 {
+		t.Errorf("Esperado 2 hospitais no cluster, recebido %d", cluster.HospitalCount)
+	}
+	if cluster.OcorrenciasCount != 3 {
+		t.Errorf("Esperado 3 ocorrencias agregadas, recebido %d", cluster.OcorrenciasCount)
+	}
+	if cluster.UrgenciaMaxima != models.UrgencyRed {
+		t.Errorf("Esperado urgencia maxima red, recebido %s", cluster.UrgenciaMaxima)
+	}
+	if cluster.Hospital != nil {
+		t.Error("Cluster com varios hospitais nao deve trazer detalhe individual")
+	}
+
+	expectedLat := (-16.6868 + -16.7200) / 2
+	expectedLng := (-49.2648 + -49.3000) / 2
+	if diff := cluster.Latitude - expectedLat; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Esperado centroide lat %f, recebido %f", expectedLat, cluster.Latitude)
+	}
+	if diff := cluster.Longitude - expectedLng; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Esperado centroide lng %f, recebido %f", expectedLng, cluster.Longitude)
+	}
+
+	// Zoom alto: cada hospital em seu proprio cluster, com detalhe completo
+	clusters = clusterMapHospitals(hospitals, 14)
+	if len(clusters) != 2 {
+		t.Fatalf("Esperado 2 clusters no zoom 14, recebido %d", len(clusters))
+	}
+	for _, c := range clusters {
+		if c.HospitalCount != 1 || c.Hospital == nil {
+			t.Errorf("Esperado cluster individual com detalhe do hospital, recebido %+v", c)
+			continue
+		}
+		if c.Latitude != c.Hospital.Latitude || c.Longitude != c.Hospital.Longitude {
+			t.Errorf("Centroide de cluster individual deve ser a posicao do hospital")
+		}
+	}
+}
+
+// Test: Testar que o tamanho da celula diminui com o zoom
+func TestMapClusterCellSizeShrinksWithZoom(t *testing.T) {
+	if mapClusterCellSize(0) != 360 {
+		t.Errorf("Esperado celula de 360 graus no zoom 0, recebido %f", mapClusterCellSize(0))
+	}
+	for zoom := 1; zoom <= MaxMapZoom; zoom++ {
+		if mapClusterCellSize(zoom) >= mapClusterCellSize(zoom-1) {
+			t.Errorf("Celula do zoom %d deveria ser menor que a do zoom %d", zoom, zoom-1)
+		}
+	}
+}
+
+// Test: Testar que min_urgency=red retorna apenas hospitais com urgencia vermelha, mantendo o plantao
+func TestMapFilterByUrgencyKeepsOnlyRedHospitals(t *testing.T) {
+	mockRepo := NewMockMapRepository()
+
+	cases := []struct {
+		nome           string
+		hoursRemaining int
+	}{
+		{"Hospital Verde", 5},
+		{"Hospital Amarelo", 3},
+		{"Hospital Vermelho", 1},
+		{"Hospital Sem Ocorrencias", 0},
+	}
+	for i, tc := range cases {
+		hospital := createTestHospitalWithCoordinates(tc.nome, fmt.Sprintf("H%d", i), -16.6868, -49.2648, true)
+		mockRepo.AddHospital(hospital)
+		if tc.hoursRemaining > 0 {
+			mockRepo.AddOccurrence(createTestOccurrenceForMap(hospital.ID, models.StatusPendente, tc.hoursRemaining))
+		}
+		mockRepo.AddShift(createTestTodayShift(hospital.ID, uuid.New(), "Operador "+tc.nome, true))
+	}
+
+	// Montar as respostas como GetMapHospitals
+	ctx := context.Background()
+	hospitals, _ := mockRepo.GetActiveHospitalsWithCoordinates(ctx)
+	var mapHospitals []models.MapHospitalResponse
+	for _, h := range hospitals {
+		occurrences, _ := mockRepo.GetActiveOccurrencesByHospitalID(ctx, h.ID)
+		resp := models.MapHospitalResponse{
+			ID:               h.ID,
+			Nome:             h.Nome,
+			UrgenciaMaxima:   models.CalculateMaxUrgency(occurrences, models.DefaultUrgencyThresholds()),
+			OcorrenciasCount: len(occurrences),
+		}
+		if shift, _ := mockRepo.GetCurrentOperatorByHospitalID(ctx, h.ID); shift != nil && shift.User != nil {
+			resp.OperadorPlantao = &models.MapOperatorResponse{ID: shift.User.ID, Nome: shift.User.Nome, UserID: shift.UserID}
+		}
+		mapHospitals = append(mapHospitals, resp)
+	}
+
+	red := filterMapHospitalsByUrgency(mapHospitals, models.UrgencyRed)
+	if len(red) != 1 {
+		t.Fatalf("Esperado 1 hospital com min_urgency=red, recebido %d", len(red))
+	}
+	if red[0].Nome != "Hospital Vermelho" || red[0].UrgenciaMaxima != models.UrgencyRed {
+		t.Errorf("Esperado Hospital Vermelho, recebido %s (%s)", red[0].Nome, red[0].UrgenciaMaxima)
+	}
+	if red[0].OperadorPlantao == nil || red[0].OperadorPlantao.Nome != "Operador Hospital Vermelho" {
+		t.Errorf("Esperado operador de plantao mantido, recebido %+v", red[0].OperadorPlantao)
+	}
+
+	if yellow := filterMapHospitalsByUrgency(mapHospitals, models.UrgencyYellow); len(yellow) != 2 {
+		t.Errorf("Esperado 2 hospitais com min_urgency=yellow, recebido %d", len(yellow))
+	}
+	if green := filterMapHospitalsByUrgency(mapHospitals, models.UrgencyGreen); len(green) != 3 {
+		t.Errorf("Esperado 3 hospitais com min_urgency=green (sem ocorrencias excluido), recebido %d", len(green))
+	}
+}
+
+// Test: Testar validacao do parametro min_urgency
+func TestParseUrgencyLevel(t *testing.T) {
+	for _, value := range []string{"green", "yellow", "red"} {
+		if level, ok := models.ParseUrgencyLevel(value); !ok || string(level) != value {
+			t.Errorf("Esperado %q valido, recebido %q %v", value, level, ok)
+		}
+	}
+	for _, value := range []string{"none", "RED", "critico", ""} {
+		if _, ok := models.ParseUrgencyLevel(value); ok {
+			t.Errorf("Esperado %q invalido", value)
+		}
+	}
+}
+
+// Test: Testar que hospitais sao ordenados do mais proximo ao mais distante do ponto de referencia
+func TestMapSortHospitalsByDistance(t *testing.T) {
+	hospitals := []models.MapHospitalResponse{
+		{ID: uuid.New(), Nome: "Hospital Brasilia", Latitude: -15.7939, Longitude: -47.8828},
+		{ID: uuid.New(), Nome: "Hospital Goiania", Latitude: -16.6869, Longitude: -49.2648},
+		{ID: uuid.New(), Nome: "Hospital Anapolis", Latitude: -16.3281, Longitude: -48.9530},
+	}
+
+	// Referencia no centro de Goiania
+	sortMapHospitalsByDistance(hospitals, geo.Point{Lat: -16.6869, Lng: -49.2648})
+
+	expected := []string{"Hospital Goiania", "Hospital Anapolis", "Hospital Brasilia"}
+	for i, nome := range expected {
+		if hospitals[i].Nome != nome {
+			t.Errorf("Posicao %d: esperado %s, recebido %s", i, nome, hospitals[i].Nome)
+		}
+		if hospitals[i].DistanciaKm == nil || hospitals[i].TempoEstimadoMinutos == nil {
+			t.Fatalf("Esperado distancia e tempo estimado em %s", hospitals[i].Nome)
+		}
+	}
+
+	if *hospitals[0].DistanciaKm != 0 || *hospitals[0].TempoEstimadoMinutos != 0 {
+		t.Errorf("Esperado distancia 0 no ponto de referencia, recebido %.2f km", *hospitals[0].DistanciaKm)
+	}
+	if d := *hospitals[2].DistanciaKm; d < 176 || d > 180 {
+		t.Errorf("Esperado ~178 km ate Brasilia, recebido %.2f km", d)
+	}
+	// 178 km a 60 km/h
+	if eta := *hospitals[2].TempoEstimadoMinutos; eta < 176 || eta > 180 {
+		t.Errorf("Esperado ~178 minutos ate Brasilia, recebido %d", eta)
+	}
+}
+
+// Test: Testar validacao dos parametros from_lat e from_lng
+func TestMapParseReferencePoint(t *testing.T) {
+	tests := []struct {
+		query   string
+		hasFrom bool
+		ok      bool
+	}{
+		{"", false, true},
+		{"from_lat=-16.6869&from_lng=-49.2648", true, true},
+		{"from_lat=-16.6869", false, false},
+		{"from_lng=-49.2648", false, false},
+		{"from_lat=abc&from_lng=-49.2648", false, false},
+		{"from_lat=-91&from_lng=-49.2648", false, false},
+		{"from_lat=-16.6869&from_lng=181", false, false},
+	}
+
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/map/hospitals?"+tt.query, nil)
+
+		from, hasFrom, ok := parseMapReferencePoint(c)
+		if hasFrom != tt.hasFrom || ok != tt.ok {
+			t.Errorf("%q: esperado hasFrom=%v ok=%v, recebido %v %v", tt.query, tt.hasFrom, tt.ok, hasFrom, ok)
+		}
+		if hasFrom && (from.Lat != -16.6869 || from.Lng != -49.2648) {
+			t.Errorf("%q: ponto inesperado %+v", tt.query, from)
+		}
+	}
+}
@@ -1,23 +1,37 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sidot/backend/internal/models"
 	"github.com/sidot/backend/internal/repository"
+	"github.com/sidot/backend/internal/services/metricscache"
 )
 
-var metricsOccurrenceRepo *repository.OccurrenceRepository
+var (
+	metricsOccurrenceRepo *repository.OccurrenceRepository
+	metricsCache          *metricscache.Cache
+)
 
 // SetMetricsOccurrenceRepository sets the occurrence repository for metrics handler
 func SetMetricsOccurrenceRepository(repo *repository.OccurrenceRepository) {
 	metricsOccurrenceRepo = repo
 }
 
+// SetMetricsCache sets the cache of dashboard metrics and indicators (nil disables caching)
+func SetMetricsCache(cache *metricscache.Cache) {
+	metricsCache = cache
+}
+
 // GetDashboardMetrics returns dashboard metrics
 // GET /api/v1/metrics/dashboard
+//
+// Query params:
+// - compare (optional, "previous"): Adds the change of each period-based metric vs the previous period
+// - period (optional, day|week, default day): Period used by compare
 func GetDashboardMetrics(c *gin.Context) {
 	if metricsOccurrenceRepo == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "occurrence repository not configured"})
@@ -26,6 +40,39 @@ func GetDashboardMetrics(c *gin.Context) {
 
 	ctx := c.Request.Context()
 
+	compare := c.Query("compare")
+	if compare != "" && compare != "previous" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid compare - must be previous"})
+		return
+	}
+
+	period := models.ComparisonPeriodDay
+	if p := c.Query("period"); p != "" {
+		period = models.ComparisonPeriod(p)
+		if !period.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid period - must be day or week"})
+			return
+		}
+	}
+
+	var response models.MetricsResponse
+	params := "compare=" + compare + "&period=" + string(period)
+	_, err := metricsCache.Fetch(ctx, "dashboard", params, &response, func() (interface{}, error) {
+		return computeDashboardMetrics(ctx, compare == "previous", period)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to compare metrics with the previous period",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// computeDashboardMetrics queries the dashboard metrics, compared with the previous period when compare is set
+func computeDashboardMetrics(ctx context.Context, compare bool, period models.ComparisonPeriod) (*models.MetricsResponse, error) {
 	// Get today's eligible deaths count
 	obitosPotenciais, err := metricsOccurrenceRepo.GetTodayEligibleCount(ctx)
 	if err != nil {
@@ -62,5 +109,16 @@ func GetDashboardMetrics(c *gin.Context) {
 		UltimaAtualizacao:      time.Now(),
 	}
 
-	c.JSON(http.StatusOK, metrics.ToResponse())
+	response := metrics.ToResponse()
+
+	if compare {
+		currentStart, previousStart := period.Bounds(metrics.UltimaAtualizacao)
+		current, previous, err := metricsOccurrenceRepo.GetPeriodComparison(ctx, currentStart, previousStart, metrics.UltimaAtualizacao)
+		if err != nil {
+			return nil, err
+		}
+		response.Comparacao = models.NewMetricsComparison(period, currentStart, previousStart, current, previous)
+	}
+
+	return &response, nil
 }
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+var notificationPrefsRepo *repository.UserNotificationPreferencesRepository
+
+// SetNotificationPreferencesRepository sets the notification preferences repository for handlers
+func SetNotificationPreferencesRepository(repo *repository.UserNotificationPreferencesRepository) {
+	notificationPrefsRepo = repo
+}
+
+// GetMyNotificationPreferences returns the current user's notification preferences
+// Defaults are created on first access
+// GET /api/v1/users/me/notification-preferences
+func GetMyNotificationPreferences(c *gin.Context) {
+	prefs, ok := ensureMyNotificationPreferences(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs.ToResponse())
+}
+
+// UpdateMyNotificationPreferences updates the current user's notification preferences
+// PUT /api/v1/users/me/notification-preferences
+func UpdateMyNotificationPreferences(c *gin.Context) {
+	var input models.UpdateNotificationPreferencesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	prefs, ok := ensureMyNotificationPreferences(c)
+	if !ok {
+		return
+	}
+
+	updated, err := notificationPrefsRepo.Update(c.Request.Context(), prefs.UserID, &input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated.ToResponse())
+}
+
+// ensureMyNotificationPreferences loads (or creates with defaults) the current user's preferences
+// On failure it writes the error response
+func ensureMyNotificationPreferences(c *gin.Context) (*models.UserNotificationPreferences, bool) {
+	if notificationPrefsRepo == nil || userRepo == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "notification preferences not configured"})
+		return nil, false
+	}
+
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return nil, false
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user ID in token"})
+		return nil, false
+	}
+
+	user, err := userRepo.GetModelByID(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+		return nil, false
+	}
+
+	prefs, err := notificationPrefsRepo.EnsureExists(c.Request.Context(), userID, user.CanReceiveSMSNotifications())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get notification preferences"})
+		return nil, false
+	}
+
+	return prefs, true
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+	"github.com/sidot/backend/internal/services/audit"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+// occurrenceAssignmentRepository reads and assigns occurrences (implemented by repository.OccurrenceRepository)
+type occurrenceAssignmentRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Occurrence, error)
+	Assign(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+}
+
+// occurrenceAssigneeReader loads candidate assignees with their hospitals (implemented by repository.UserRepository)
+type occurrenceAssigneeReader interface {
+	GetModelByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+}
+
+// occurrenceHistoryWriter records occurrence history entries (implemented by repository.OccurrenceHistoryRepository)
+type occurrenceHistoryWriter interface {
+	Create(ctx context.Context, input *models.CreateHistoryInput) (*models.OccurrenceHistory, error)
+}
+
+var (
+	occurrenceAssignments       occurrenceAssignmentRepository
+	occurrenceAssignees         occurrenceAssigneeReader
+	occurrenceAssignmentHistory occurrenceHistoryWriter
+)
+
+// AssignOccurrence assigns an occurrence to an operator of its hospital
+// POST /api/v1/occurrences/:id/assign
+// Access: Admin (all), Gestor (same hospital), Operador (same hospital, only to themselves)
+func AssignOccurrence(c *gin.Context) {
+	if occurrenceAssignments == nil || occurrenceAssignees == nil || occurrenceAssignmentHistory == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "repositories not configured"})
+		return
+	}
+
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid occurrence ID format"})
+		return
+	}
+
+	var input models.AssignOccurrenceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	occurrence, err := occurrenceAssignments.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrOccurrenceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "occurrence not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get occurrence"})
+		return
+	}
+
+	if !canAccessOccurrence(claims, occurrence) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "access denied",
+			"message": "you can only assign occurrences from your hospital",
+		})
+		return
+	}
+
+	if claims.Role == string(models.RoleOperador) && claims.UserID != input.UserID.String() {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "access denied",
+			"message": "operators can only assign occurrences to themselves",
+		})
+		return
+	}
+
+	if !occurrence.Status.CanBeAssigned() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":          "occurrence can only be assigned in PENDENTE or EM_ANDAMENTO status",
+			"current_status": occurrence.Status,
+		})
+		return
+	}
+
+	assignee, err := occurrenceAssignees.GetModelByID(c.Request.Context(), input.UserID)
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "assignee not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get assignee"})
+		return
+	}
+
+	if reason := assigneeIneligibility(assignee, occurrence.HospitalID); reason != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid assignee",
+			"details": reason,
+		})
+		return
+	}
+
+	if err := occurrenceAssignments.Assign(c.Request.Context(), id, assignee.ID); err != nil {
+		if errors.Is(err, repository.ErrOccurrenceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "occurrence not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to assign occurrence"})
+		return
+	}
+
+	// Cached dashboard metrics and indicators include the assignment of occurrences
+	metricsCache.Invalidate(c.Request.Context())
+
+	var userID *uuid.UUID
+	if uid, err := uuid.Parse(claims.UserID); err == nil {
+		userID = &uid
+	}
+
+	observacoes := fmt.Sprintf("Atribuida a %s", assignee.Nome)
+	if input.Observacoes != nil && *input.Observacoes != "" {
+		observacoes = fmt.Sprintf("%s: %s", observacoes, *input.Observacoes)
+	}
+
+	_, err = occurrenceAssignmentHistory.Create(c.Request.Context(), &models.CreateHistoryInput{
+		OccurrenceID: id,
+		UserID:       userID,
+		Acao:         models.ActionOccurrenceAssigned,
+		Observacoes:  &observacoes,
+	})
+	if err != nil {
+		// Log error but don't fail the request
+		_ = err
+	}
+
+	// Log audit event for the assignment
+	if auditService != nil {
+		userIDForAudit, actorName := audit.GetUserInfoFromContext(c)
+		ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+		auditService.LogEventWithUser(
+			c.Request.Context(),
+			userIDForAudit,
+			actorName,
+			models.ActionOcorrenciaAtribuir,
+			"Ocorrencia",
+			id.String(),
+			&occurrence.HospitalID,
+			models.SeverityInfo,
+			map[string]interface{}{
+				"assigned_user_id":          assignee.ID,
+				"previous_assigned_user_id": occurrence.AssignedUserID,
+			},
+			ipAddress,
+			userAgent,
+		)
+	}
+
+	occurrence.AssignedUserID = &assignee.ID
+	occurrence.AssignedUser = &models.OccurrenceAssignee{ID: assignee.ID, Nome: assignee.Nome}
+	c.JSON(http.StatusOK, occurrence.ToDetailResponse())
+}
+
+// assigneeIneligibility returns why a user cannot be assigned occurrences of the hospital, or "" if they can
+func assigneeIneligibility(user *models.User, hospitalID uuid.UUID) string {
+	if !user.Ativo {
+		return "assignee is inactive"
+	}
+	if user.Role != models.RoleOperador {
+		return "assignee must be an operador"
+	}
+	for _, h := range user.Hospitals {
+		if h.ID == hospitalID {
+			return ""
+		}
+	}
+	return "assignee has no access to the occurrence hospital"
+}
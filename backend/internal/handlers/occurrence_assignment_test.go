@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+// fakeAssignmentStore serves occurrences, users and history from memory for assignment tests
+type fakeAssignmentStore struct {
+	occurrences map[uuid.UUID]*models.Occurrence
+	users       map[uuid.UUID]*models.User
+	assigned    map[uuid.UUID]uuid.UUID
+	history     []models.CreateHistoryInput
+}
+
+func (f *fakeAssignmentStore) GetByID(ctx context.Context, id uuid.UUID) (*models.Occurrence, error) {
+	if o, ok := f.occurrences[id]; ok {
+		copied := *o
+		return &copied, nil
+	}
+	return nil, repository.ErrOccurrenceNotFound
+}
+
+func (f *fakeAssignmentStore) Assign(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	if _, ok := f.occurrences[id]; !ok {
+		return repository.ErrOccurrenceNotFound
+	}
+	f.assigned[id] = userID
+	return nil
+}
+
+func (f *fakeAssignmentStore) GetModelByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	if u, ok := f.users[id]; ok {
+		return u, nil
+	}
+	return nil, auth.ErrUserNotFound
+}
+
+func (f *fakeAssignmentStore) Create(ctx context.Context, input *models.CreateHistoryInput) (*models.OccurrenceHistory, error) {
+	f.history = append(f.history, *input)
+	return &models.OccurrenceHistory{ID: uuid.New(), OccurrenceID: input.OccurrenceID, Acao: input.Acao}, nil
+}
+
+// assignmentTestSetup holds an occurrence of one hospital and operators with and without access to it
+type assignmentTestSetup struct {
+	store             *fakeAssignmentStore
+	hospitalID        uuid.UUID
+	occurrenceID      uuid.UUID
+	operatorID        uuid.UUID
+	foreignOperatorID uuid.UUID
+	gestorID          string
+}
+
+func newAssignmentTest(t *testing.T, status models.OccurrenceStatus) *assignmentTestSetup {
+	t.Helper()
+
+	hospitalID := uuid.New()
+	s := &assignmentTestSetup{
+		hospitalID:        hospitalID,
+		occurrenceID:      uuid.New(),
+		operatorID:        uuid.New(),
+		foreignOperatorID: uuid.New(),
+		gestorID:          uuid.New().String(),
+	}
+	s.store = &fakeAssignmentStore{
+		occurrences: map[uuid.UUID]*models.Occurrence{
+			s.occurrenceID: {ID: s.occurrenceID, HospitalID: hospitalID, Status: status},
+		},
+		users: map[uuid.UUID]*models.User{
+			s.operatorID: {
+				ID: s.operatorID, Nome: "Ana Operadora", Role: models.RoleOperador, Ativo: true,
+				Hospitals: []models.Hospital{{ID: hospitalID}},
+			},
+			s.foreignOperatorID: {
+				ID: s.foreignOperatorID, Nome: "Bruno Operador", Role: models.RoleOperador, Ativo: true,
+				Hospitals: []models.Hospital{{ID: uuid.New()}},
+			},
+		},
+		assigned: map[uuid.UUID]uuid.UUID{},
+	}
+
+	prevOcc, prevUsers, prevHist := occurrenceAssignments, occurrenceAssignees, occurrenceAssignmentHistory
+	occurrenceAssignments, occurrenceAssignees, occurrenceAssignmentHistory = s.store, s.store, s.store
+	t.Cleanup(func() {
+		occurrenceAssignments, occurrenceAssignees, occurrenceAssignmentHistory = prevOcc, prevUsers, prevHist
+	})
+
+	return s
+}
+
+// assign posts an assignment of the occurrence to userID as a caller with the given id and role at the hospital
+func (s *assignmentTestSetup) assign(callerID, role string, userID uuid.UUID) *httptest.ResponseRecorder {
+	router := setupTestRouter()
+	router.POST("/api/v1/occurrences/:id/assign", func(c *gin.Context) {
+		c.Set("user_claims", &middleware.UserClaims{
+			UserID:     callerID,
+			Role:       role,
+			HospitalID: s.hospitalID.String(),
+		})
+		AssignOccurrence(c)
+	})
+
+	body := fmt.Sprintf(`{"user_id": %q}`, userID.String())
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/occurrences/"+s.occurrenceID.String()+"/assign", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestAssignOccurrence verifies that an operator of the hospital is assigned and the assignment recorded in history
+func TestAssignOccurrence(t *testing.T) {
+	s := newAssignmentTest(t, models.StatusPendente)
+
+	w := s.assign(s.gestorID, "gestor", s.operatorID)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if s.store.assigned[s.occurrenceID] != s.operatorID {
+		t.Error("Expected the occurrence to be assigned to the operator")
+	}
+	if len(s.store.history) != 1 || s.store.history[0].Acao != models.ActionOccurrenceAssigned {
+		t.Errorf("Expected one %q history entry, got %+v", models.ActionOccurrenceAssigned, s.store.history)
+	}
+
+	var response models.OccurrenceDetailResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.AssignedUser == nil || response.AssignedUser.ID != s.operatorID || response.AssignedUser.Nome != "Ana Operadora" {
+		t.Errorf("Expected the assignee in the response, got %+v", response.AssignedUser)
+	}
+}
+
+// TestAssignOccurrenceRejectsUserWithoutHospitalAccess verifies that an operator of another hospital cannot be assigned
+func TestAssignOccurrenceRejectsUserWithoutHospitalAccess(t *testing.T) {
+	s := newAssignmentTest(t, models.StatusPendente)
+
+	w := s.assign(s.gestorID, "gestor", s.foreignOperatorID)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "no access to the occurrence hospital") {
+		t.Errorf("Expected the hospital access error, got %s", w.Body.String())
+	}
+	if _, ok := s.store.assigned[s.occurrenceID]; ok {
+		t.Error("Expected the occurrence not to be assigned")
+	}
+	if len(s.store.history) != 0 {
+		t.Error("Expected no history entry for a rejected assignment")
+	}
+}
+
+// TestAssignOccurrenceValidation covers the status, role and assignee checks of an assignment
+func TestAssignOccurrenceValidation(t *testing.T) {
+	t.Run("terminal status", func(t *testing.T) {
+		s := newAssignmentTest(t, models.StatusAceita)
+		if w := s.assign(s.gestorID, "gestor", s.operatorID); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("assignee is not an operador", func(t *testing.T) {
+		s := newAssignmentTest(t, models.StatusEmAndamento)
+		s.store.users[s.operatorID].Role = models.RoleGestor
+		if w := s.assign(s.gestorID, "gestor", s.operatorID); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("unknown assignee", func(t *testing.T) {
+		s := newAssignmentTest(t, models.StatusPendente)
+		if w := s.assign(s.gestorID, "gestor", uuid.New()); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("operador assigning someone else", func(t *testing.T) {
+		s := newAssignmentTest(t, models.StatusPendente)
+		if w := s.assign(uuid.New().String(), "operador", s.operatorID); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("operador assigning themselves", func(t *testing.T) {
+		s := newAssignmentTest(t, models.StatusPendente)
+		if w := s.assign(s.operatorID.String(), "operador", s.operatorID); w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
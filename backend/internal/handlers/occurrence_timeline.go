@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sidot/backend/internal/models"
+)
+
+// OccurrenceStatusPeriod is the time an occurrence spent in one status
+type OccurrenceStatusPeriod struct {
+	Status          models.OccurrenceStatus `json:"status"`
+	Inicio          time.Time               `json:"inicio"`
+	Fim             *time.Time              `json:"fim,omitempty"` // nil while it is the current status
+	DuracaoSegundos int64                   `json:"duracao_segundos"`
+}
+
+// OccurrenceStatusTimeline is the status history of an occurrence laid out as consecutive periods
+type OccurrenceStatusTimeline struct {
+	Periodos       []OccurrenceStatusPeriod          `json:"periodos"`
+	TempoPorStatus map[models.OccurrenceStatus]int64 `json:"tempo_por_status_segundos"`
+	// TempoTotalSegundos runs from creation to the terminal status, nil while the occurrence is open
+	TempoTotalSegundos *int64 `json:"tempo_total_segundos,omitempty"`
+}
+
+// buildOccurrenceStatusTimeline computes the periods between consecutive status changes of the history.
+// The current status of an open occurrence lasts until now; a terminal status ends the timeline.
+func buildOccurrenceStatusTimeline(histories []models.OccurrenceHistory, now time.Time) OccurrenceStatusTimeline {
+	changes := make([]models.OccurrenceHistory, 0, len(histories))
+	for _, h := range histories {
+		if h.StatusNovo != nil {
+			changes = append(changes, h)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].CreatedAt.Before(changes[j].CreatedAt) })
+
+	timeline := OccurrenceStatusTimeline{
+		Periodos:       []OccurrenceStatusPeriod{},
+		TempoPorStatus: make(map[models.OccurrenceStatus]int64),
+	}
+
+	for _, change := range changes {
+		status := *change.StatusNovo
+		if n := len(timeline.Periodos); n > 0 {
+			last := &timeline.Periodos[n-1]
+			if last.Status == status || last.Status.IsTerminal() {
+				continue
+			}
+			end := change.CreatedAt
+			last.Fim = &end
+		}
+		timeline.Periodos = append(timeline.Periodos, OccurrenceStatusPeriod{Status: status, Inicio: change.CreatedAt})
+	}
+
+	if len(timeline.Periodos) == 0 {
+		return timeline
+	}
+
+	last := &timeline.Periodos[len(timeline.Periodos)-1]
+	if last.Status.IsTerminal() {
+		end := last.Inicio
+		last.Fim = &end
+		total := int64(end.Sub(timeline.Periodos[0].Inicio) / time.Second)
+		timeline.TempoTotalSegundos = &total
+	}
+
+	for i := range timeline.Periodos {
+		period := &timeline.Periodos[i]
+		end := now
+		if period.Fim != nil {
+			end = *period.Fim
+		}
+		period.DuracaoSegundos = int64(end.Sub(period.Inicio) / time.Second)
+		timeline.TempoPorStatus[period.Status] += period.DuracaoSegundos
+	}
+
+	return timeline
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sidot/backend/internal/models"
+)
+
+// historyEntry builds a status change history entry at the given time
+func historyEntry(at time.Time, anterior *models.OccurrenceStatus, novo models.OccurrenceStatus) models.OccurrenceHistory {
+	return models.OccurrenceHistory{Acao: models.ActionStatusChanged, StatusAnterior: anterior, StatusNovo: &novo, CreatedAt: at}
+}
+
+func statusPtr(status models.OccurrenceStatus) *models.OccurrenceStatus {
+	return &status
+}
+
+// TestBuildOccurrenceStatusTimelineConcluded tests the durations of a concluded occurrence,
+// with the history in the descending order returned by the repository
+func TestBuildOccurrenceStatusTimelineConcluded(t *testing.T) {
+	created := time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC)
+	note := "Familia contactada"
+	histories := []models.OccurrenceHistory{
+		historyEntry(created.Add(3*time.Hour), statusPtr(models.StatusAceita), models.StatusConcluida),
+		{Acao: "Observacao adicionada", Observacoes: &note, CreatedAt: created.Add(2 * time.Hour)},
+		historyEntry(created.Add(90*time.Minute), statusPtr(models.StatusEmAndamento), models.StatusAceita),
+		historyEntry(created.Add(30*time.Minute), statusPtr(models.StatusPendente), models.StatusEmAndamento),
+		historyEntry(created, nil, models.StatusPendente),
+	}
+
+	timeline := buildOccurrenceStatusTimeline(histories, created.Add(24*time.Hour))
+
+	expected := []struct {
+		status   models.OccurrenceStatus
+		duration int64
+	}{
+		{models.StatusPendente, 1800},
+		{models.StatusEmAndamento, 3600},
+		{models.StatusAceita, 5400},
+		{models.StatusConcluida, 0},
+	}
+	if len(timeline.Periodos) != len(expected) {
+		t.Fatalf("Expected %d periods, got %d", len(expected), len(timeline.Periodos))
+	}
+	for i, want := range expected {
+		period := timeline.Periodos[i]
+		if period.Status != want.status || period.DuracaoSegundos != want.duration {
+			t.Errorf("Period %d: expected %s for %ds, got %s for %ds", i, want.status, want.duration, period.Status, period.DuracaoSegundos)
+		}
+		if period.Fim == nil {
+			t.Errorf("Period %d: expected an end", i)
+		}
+	}
+
+	if timeline.TempoTotalSegundos == nil || *timeline.TempoTotalSegundos != 3*3600 {
+		t.Errorf("Expected a total of %d seconds, got %v", 3*3600, timeline.TempoTotalSegundos)
+	}
+	if timeline.TempoPorStatus[models.StatusAceita] != 5400 {
+		t.Errorf("Expected 5400 seconds in ACEITA, got %d", timeline.TempoPorStatus[models.StatusAceita])
+	}
+}
+
+// TestBuildOccurrenceStatusTimelineOpen tests that the current status of an open occurrence lasts until now
+func TestBuildOccurrenceStatusTimelineOpen(t *testing.T) {
+	created := time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC)
+	histories := []models.OccurrenceHistory{
+		historyEntry(created, nil, models.StatusPendente),
+		historyEntry(created.Add(10*time.Minute), statusPtr(models.StatusPendente), models.StatusEmAndamento),
+		historyEntry(created.Add(20*time.Minute), statusPtr(models.StatusEmAndamento), models.StatusPendente),
+	}
+
+	timeline := buildOccurrenceStatusTimeline(histories, created.Add(time.Hour))
+
+	if len(timeline.Periodos) != 3 {
+		t.Fatalf("Expected 3 periods, got %d", len(timeline.Periodos))
+	}
+	current := timeline.Periodos[2]
+	if current.Fim != nil || current.DuracaoSegundos != 40*60 {
+		t.Errorf("Expected the current period to be open for 2400s, got fim %v and %ds", current.Fim, current.DuracaoSegundos)
+	}
+	if timeline.TempoPorStatus[models.StatusPendente] != 50*60 {
+		t.Errorf("Expected both PENDENTE periods summed to 3000s, got %d", timeline.TempoPorStatus[models.StatusPendente])
+	}
+	if timeline.TempoTotalSegundos != nil {
+		t.Errorf("Expected no total for an open occurrence, got %d", *timeline.TempoTotalSegundos)
+	}
+}
+
+// TestBuildOccurrenceStatusTimelineEmpty tests a history without status changes
+func TestBuildOccurrenceStatusTimelineEmpty(t *testing.T) {
+	timeline := buildOccurrenceStatusTimeline([]models.OccurrenceHistory{{Acao: models.ActionNotificationSent}}, time.Now())
+
+	if len(timeline.Periodos) != 0 || len(timeline.TempoPorStatus) != 0 || timeline.TempoTotalSegundos != nil {
+		t.Errorf("Expected an empty timeline, got %+v", timeline)
+	}
+}
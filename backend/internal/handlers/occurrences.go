@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -15,69 +20,135 @@ import (
 	"github.com/sidot/backend/internal/services/audit"
 )
 
+// maxOccurrenceSearchLength is the maximum length of the occurrence name search
+const maxOccurrenceSearchLength = 100
+
+// occurrenceLister lists the occurrences of the tenant (implemented by repository.OccurrenceRepository)
+type occurrenceLister interface {
+	List(ctx context.Context, filters models.OccurrenceListFilters) ([]models.Occurrence, int, error)
+}
+
 var (
 	occurrenceRepo        *repository.OccurrenceRepository
 	occurrenceHistoryRepo *repository.OccurrenceHistoryRepository
+	occurrenceLists       occurrenceLister
 )
 
 // SetOccurrenceRepository sets the occurrence repository for handlers
 func SetOccurrenceRepository(repo *repository.OccurrenceRepository) {
 	occurrenceRepo = repo
+	if repo != nil {
+		occurrenceLists = repo
+		aiActionOccurrences = repo
+		occurrenceAssignments = repo
+	}
 }
 
 // SetOccurrenceHistoryRepository sets the occurrence history repository for handlers
 func SetOccurrenceHistoryRepository(repo *repository.OccurrenceHistoryRepository) {
 	occurrenceHistoryRepo = repo
+	if repo != nil {
+		occurrenceAssignmentHistory = repo
+	}
 }
 
 // ListOccurrences returns occurrences with pagination and filters
+// preset=<nome> expands to the filters of a saved preset of the user; parameters in the request take precedence
 // GET /api/v1/occurrences
 func ListOccurrences(c *gin.Context) {
-	if occurrenceRepo == nil {
+	if occurrenceLists == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "occurrence repository not configured"})
 		return
 	}
 
-	// Parse filters from query parameters
+	query := c.Request.URL.Query()
+	if nome := query.Get("preset"); nome != "" {
+		if !expandOccurrenceListPreset(c, nome, query) {
+			return
+		}
+	}
+
+	filters, ok := parseOccurrenceListFilters(c, query)
+	if !ok {
+		return
+	}
+
+	// Name search: operadores only match the masked name, gestor/admin also match the full name
+	if q := strings.TrimSpace(query.Get("q")); q != "" {
+		if utf8.RuneCountInString(q) > maxOccurrenceSearchLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("q must have at most %d characters", maxOccurrenceSearchLength)})
+			return
+		}
+		filters.Search = &q
+		if claims, ok := middleware.GetUserClaims(c); ok {
+			filters.SearchFullName = claims.Role == string(models.RoleGestor) || claims.Role == string(models.RoleAdmin)
+		}
+	}
+
+	occurrences, totalItems, err := occurrenceLists.List(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list occurrences"})
+		return
+	}
+
+	// Convert to list response format (with masked names)
+	response := make([]models.OccurrenceListResponse, 0, len(occurrences))
+	for _, o := range occurrences {
+		response = append(response, o.ToListResponse())
+	}
+
+	c.JSON(http.StatusOK, models.NewPaginatedResponse(response, filters.Page, filters.PageSize, totalItems).WithLinks(c.Request.URL, "page_size"))
+}
+
+// parseOccurrenceListFilters parses the occurrence list query parameters
+// On invalid input it writes a 400 response and returns false
+func parseOccurrenceListFilters(c *gin.Context, query url.Values) (models.OccurrenceListFilters, bool) {
+	filters, err := occurrenceListFiltersFromQuery(query)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
+		return filters, false
+	}
+	return filters, true
+}
+
+// occurrenceListFiltersFromQuery builds the occurrence list filters from query parameters
+func occurrenceListFiltersFromQuery(query url.Values) (models.OccurrenceListFilters, error) {
 	filters := models.DefaultFilters()
 
 	// Status filter
-	if status := c.Query("status"); status != "" {
+	if status := query.Get("status"); status != "" {
 		s := models.OccurrenceStatus(status)
 		if !s.IsValid() {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status filter"})
-			return
+			return filters, errors.New("invalid status filter")
 		}
 		filters.Status = &s
 	}
 
 	// Hospital filter
-	if hospitalID := c.Query("hospital_id"); hospitalID != "" {
+	if hospitalID := query.Get("hospital_id"); hospitalID != "" {
 		filters.HospitalID = &hospitalID
 	}
 
 	// Date filters
-	if dateFrom := c.Query("date_from"); dateFrom != "" {
+	if dateFrom := query.Get("date_from"); dateFrom != "" {
 		t, err := time.Parse(time.RFC3339, dateFrom)
 		if err != nil {
 			// Try date-only format
 			t, err = time.Parse("2006-01-02", dateFrom)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date_from format, use RFC3339 or YYYY-MM-DD"})
-				return
+				return filters, errors.New("invalid date_from format, use RFC3339 or YYYY-MM-DD")
 			}
 		}
 		filters.DateFrom = &t
 	}
 
-	if dateTo := c.Query("date_to"); dateTo != "" {
+	if dateTo := query.Get("date_to"); dateTo != "" {
 		t, err := time.Parse(time.RFC3339, dateTo)
 		if err != nil {
 			// Try date-only format and set to end of day
 			t, err = time.Parse("2006-01-02", dateTo)
 			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date_to format, use RFC3339 or YYYY-MM-DD"})
-				return
+				return filters, errors.New("invalid date_to format, use RFC3339 or YYYY-MM-DD")
 			}
 			t = t.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
 		}
@@ -85,50 +156,48 @@ func ListOccurrences(c *gin.Context) {
 	}
 
 	// Pagination
-	if page := c.Query("page"); page != "" {
+	if page := query.Get("page"); page != "" {
 		p, err := strconv.Atoi(page)
 		if err != nil || p < 1 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page number"})
-			return
+			return filters, errors.New("invalid page number")
 		}
 		filters.Page = p
 	}
 
-	if pageSize := c.Query("page_size"); pageSize != "" {
+	if pageSize := query.Get("page_size"); pageSize != "" {
 		ps, err := strconv.Atoi(pageSize)
 		if err != nil || ps < 1 || ps > 100 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page_size (1-100)"})
-			return
+			return filters, errors.New("invalid page_size (1-100)")
 		}
 		filters.PageSize = ps
 	}
 
 	// Sorting
-	if sortBy := c.Query("sort_by"); sortBy != "" {
+	if sortBy := query.Get("sort_by"); sortBy != "" {
 		filters.SortBy = sortBy
 	}
 
-	if sortOrder := c.Query("sort_order"); sortOrder != "" {
+	if sortOrder := query.Get("sort_order"); sortOrder != "" {
 		if sortOrder != "asc" && sortOrder != "desc" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort_order (asc or desc)"})
-			return
+			return filters, errors.New("invalid sort_order (asc or desc)")
 		}
 		filters.SortOrder = sortOrder
 	}
 
-	occurrences, totalItems, err := occurrenceRepo.List(c.Request.Context(), filters)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list occurrences"})
-		return
-	}
-
-	// Convert to list response format (with masked names)
-	response := make([]models.OccurrenceListResponse, 0, len(occurrences))
-	for _, o := range occurrences {
-		response = append(response, o.ToListResponse())
+	// sort=<field> or sort=<field>:<asc|desc>, mapped to an allowlisted column
+	if sort := query.Get("sort"); sort != "" {
+		field, order, _ := strings.Cut(sort, ":")
+		column, ok := models.OccurrenceSortFields[field]
+		if !ok || (order != "" && order != "asc" && order != "desc") {
+			return filters, errors.New("invalid sort (score, data_obito, created_at or tempo_restante, optionally followed by :asc or :desc)")
+		}
+		filters.SortBy = column
+		if order != "" {
+			filters.SortOrder = order
+		}
 	}
 
-	c.JSON(http.StatusOK, models.NewPaginatedResponse(response, filters.Page, filters.PageSize, totalItems))
+	return filters, nil
 }
 
 // GetOccurrence returns occurrence details with full data (unmasked name)
@@ -225,6 +294,109 @@ func GetOccurrenceHistory(c *gin.Context) {
 	})
 }
 
+// GetOccurrencePDF returns a printable case sheet for a single occurrence
+// GET /api/v1/occurrences/:id/pdf
+// Access: Admin (all), Gestor and Operador (same hospital)
+func GetOccurrencePDF(c *gin.Context) {
+	if occurrenceRepo == nil || occurrenceHistoryRepo == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "repositories not configured"})
+		return
+	}
+	if reportService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "report service not configured"})
+		return
+	}
+
+	claims, ok := middleware.GetUserClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid occurrence ID format"})
+		return
+	}
+
+	occurrence, err := occurrenceRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrOccurrenceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "occurrence not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get occurrence"})
+		return
+	}
+
+	if !canAccessOccurrence(claims, occurrence) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "access denied",
+			"message": "you can only view occurrences from your hospital",
+		})
+		return
+	}
+
+	histories, err := occurrenceHistoryRepo.GetByOccurrenceID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get occurrence history"})
+		return
+	}
+
+	pdfBytes, err := reportService.GenerateOccurrencePDF(occurrence, histories)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to generate occurrence PDF",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Log export of complete data for LGPD audit
+	if auditService != nil {
+		userID, actorName := audit.GetUserInfoFromContext(c)
+		ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+		auditService.LogEventWithUser(
+			c.Request.Context(),
+			userID,
+			actorName,
+			models.ActionOcorrenciaExportarPDF,
+			"Ocorrencia",
+			id.String(),
+			&occurrence.HospitalID,
+			models.SeverityInfo,
+			nil, // No sensitive details
+			ipAddress,
+			userAgent,
+		)
+	}
+
+	filename := fmt.Sprintf("ocorrencia_%s.pdf", id.String())
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	c.Header("Content-Length", fmt.Sprintf("%d", len(pdfBytes)))
+	c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
+	c.Header("Pragma", "no-cache")
+	c.Header("Expires", "0")
+
+	c.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
+
+// canAccessOccurrence applies the role-based hospital access rules for an occurrence
+// Admin can access any occurrence; gestor and operador only those from their hospital
+func canAccessOccurrence(claims *middleware.UserClaims, occurrence *models.Occurrence) bool {
+	switch claims.Role {
+	case "admin":
+		return true
+	case "gestor", "operador":
+		return claims.HospitalID != "" && claims.HospitalID == occurrence.HospitalID.String()
+	default:
+		return false
+	}
+}
+
 // UpdateOccurrenceStatus updates the status of an occurrence
 // PATCH /api/v1/occurrences/:id/status
 func UpdateOccurrenceStatus(c *gin.Context) {
@@ -301,6 +473,16 @@ func UpdateOccurrenceStatus(c *gin.Context) {
 		return
 	}
 
+	// Cached dashboard metrics count occurrences by status
+	metricsCache.Invalidate(c.Request.Context())
+
+	// Leaving the active statuses removes the occurrence from the map
+	if occurrence.Status.IsActive() && !input.Status.IsActive() {
+		resolved := *occurrence
+		resolved.Status = input.Status
+		publishMapOccurrenceResolved(c.Request.Context(), &resolved)
+	}
+
 	// Get user claims for history
 	claims, _ := middleware.GetUserClaims(c)
 	var userID *uuid.UUID
@@ -488,6 +670,9 @@ func RegisterOutcome(c *gin.Context) {
 		return
 	}
 
+	// Cached indicators count captures by outcome
+	metricsCache.Invalidate(c.Request.Context())
+
 	// Log audit event for outcome registration
 	if auditService != nil {
 		userIDForAudit, actorName := audit.GetUserInfoFromContext(c)
@@ -3,34 +3,140 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
 )
 
-// PEPEventInput represents the event received from PEP agents
+// PEP event schema versions accepted by ReceivePEPEvent. Older versions are upconverted
+// to PEPEventInput; a payload without schema_version comes from an agent predating it (v1).
+const (
+	PEPEventSchemaVersion    = 2 // current version
+	PEPEventMinSchemaVersion = 1 // oldest version still accepted
+)
+
+// ErrUnsupportedPEPSchemaVersion is returned for a schema_version the server does not know
+var ErrUnsupportedPEPSchemaVersion = errors.New("unsupported PEP event schema version")
+
+// PEPEventInput represents the event received from PEP agents (schema v2)
 type PEPEventInput struct {
-	HospitalIDOrigem  string `json:"hospital_id_origem"`          // ID from source PEP system
+	SchemaVersion     int    `json:"schema_version"`
+	HospitalIDOrigem  string `json:"hospital_id_origem"`             // ID from source PEP system
 	HospitalID        string `json:"hospital_id" binding:"required"` // SIDOT hospital UUID
+	NomePaciente      string `json:"nome_paciente"`                  // required unless identificacao_desconhecida
+	DataObito         string `json:"data_obito" binding:"required"`
+	CausaMortis       string `json:"causa_mortis" binding:"required"`
+	DataNascimento    string `json:"data_nascimento,omitempty"`
+	Idade             int    `json:"idade"`
+	CNS               string `json:"cns,omitempty"`        // Cartão Nacional de Saúde
+	CPFMasked         string `json:"cpf_masked,omitempty"` // Already masked CPF
+	Setor             string `json:"setor,omitempty"`
+	Leito             string `json:"leito,omitempty"`
+	Prontuario        string `json:"prontuario,omitempty"`
+	TimestampDeteccao string `json:"timestamp_deteccao,omitempty"`
+
+	// IdentificacaoDesconhecida flags a patient without identification (v2)
+	IdentificacaoDesconhecida bool `json:"identificacao_desconhecida"`
+}
+
+// PEPEventInputV1 is the event sent by agents before schema versioning: the patient name is
+// always required and the unknown identification flag is not supported
+type PEPEventInputV1 struct {
+	HospitalIDOrigem  string `json:"hospital_id_origem"`
+	HospitalID        string `json:"hospital_id" binding:"required"`
 	NomePaciente      string `json:"nome_paciente" binding:"required"`
 	DataObito         string `json:"data_obito" binding:"required"`
 	CausaMortis       string `json:"causa_mortis" binding:"required"`
 	DataNascimento    string `json:"data_nascimento,omitempty"`
 	Idade             int    `json:"idade"`
-	CNS               string `json:"cns,omitempty"`               // Cartão Nacional de Saúde
-	CPFMasked         string `json:"cpf_masked,omitempty"`        // Already masked CPF
+	CNS               string `json:"cns,omitempty"`
+	CPFMasked         string `json:"cpf_masked,omitempty"`
 	Setor             string `json:"setor,omitempty"`
 	Leito             string `json:"leito,omitempty"`
 	Prontuario        string `json:"prontuario,omitempty"`
 	TimestampDeteccao string `json:"timestamp_deteccao,omitempty"`
 }
 
+// Upconvert converts a v1 event to the current schema
+func (v1 PEPEventInputV1) Upconvert() PEPEventInput {
+	return PEPEventInput{
+		SchemaVersion:     PEPEventSchemaVersion,
+		HospitalIDOrigem:  v1.HospitalIDOrigem,
+		HospitalID:        v1.HospitalID,
+		NomePaciente:      v1.NomePaciente,
+		DataObito:         v1.DataObito,
+		CausaMortis:       v1.CausaMortis,
+		DataNascimento:    v1.DataNascimento,
+		Idade:             v1.Idade,
+		CNS:               v1.CNS,
+		CPFMasked:         v1.CPFMasked,
+		Setor:             v1.Setor,
+		Leito:             v1.Leito,
+		Prontuario:        v1.Prontuario,
+		TimestampDeteccao: v1.TimestampDeteccao,
+	}
+}
+
+// decodePEPEvent decodes an event of any supported schema version into the current schema
+func decodePEPEvent(body []byte) (*PEPEventInput, error) {
+	var envelope struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+
+	version := PEPEventMinSchemaVersion
+	if envelope.SchemaVersion != nil {
+		version = *envelope.SchemaVersion
+	}
+
+	switch version {
+	case 1:
+		var v1 PEPEventInputV1
+		if err := json.Unmarshal(body, &v1); err != nil {
+			return nil, err
+		}
+		if err := binding.Validator.ValidateStruct(&v1); err != nil {
+			return nil, err
+		}
+		input := v1.Upconvert()
+		return &input, nil
+	case 2:
+		var input PEPEventInput
+		if err := json.Unmarshal(body, &input); err != nil {
+			return nil, err
+		}
+		if err := binding.Validator.ValidateStruct(&input); err != nil {
+			return nil, err
+		}
+		if input.NomePaciente == "" && !input.IdentificacaoDesconhecida {
+			return nil, errors.New("nome_paciente is required unless identificacao_desconhecida is true")
+		}
+		return &input, nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedPEPSchemaVersion, version)
+	}
+}
+
+// PEPHospitalLookup resolves the hospital of an API key across tenants
+// (implemented by repository.AdminHospitalRepository)
+type PEPHospitalLookup interface {
+	GetHospitalByID(ctx context.Context, id uuid.UUID) (*models.HospitalWithTenant, error)
+}
+
 var (
-	pepRedisClient *redis.Client
-	pepAPIKeys     map[string]uuid.UUID // API Key -> Hospital UUID mapping
+	pepRedisClient    *redis.Client
+	pepAPIKeys        map[string]uuid.UUID // API Key -> Hospital UUID mapping
+	pepHospitalLookup PEPHospitalLookup
 )
 
 // SetPEPRedisClient sets the Redis client for PEP handlers
@@ -38,14 +144,26 @@ func SetPEPRedisClient(client *redis.Client) {
 	pepRedisClient = client
 }
 
+// SetPEPHospitalLookup sets the hospital lookup used by the PEP ping endpoint
+func SetPEPHospitalLookup(lookup PEPHospitalLookup) {
+	pepHospitalLookup = lookup
+}
+
 // SetPEPAPIKeys sets the API keys for PEP authentication
 // This should be loaded from hospital configurations
 func SetPEPAPIKeys(keys map[string]uuid.UUID) {
 	pepAPIKeys = keys
 }
 
-// ValidatePEPAPIKey validates the API key from the request
+// ValidatePEPAPIKey authenticates a PEP agent, by the client certificate resolved by the
+// PEPClientCert middleware or else by the API key of the request
 func ValidatePEPAPIKey(c *gin.Context) (*uuid.UUID, bool) {
+	if value, ok := c.Get(middleware.PEPCertHospitalKey); ok {
+		if hospitalID, ok := value.(uuid.UUID); ok {
+			return &hospitalID, true
+		}
+	}
+
 	apiKey := c.GetHeader("X-API-Key")
 	if apiKey == "" {
 		return nil, false
@@ -68,36 +186,36 @@ func ReceivePEPEvent(c *gin.Context) {
 	// Validate API Key
 	hospitalID, valid := ValidatePEPAPIKey(c)
 	if !valid {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "invalid or missing API key",
-			"code":  "INVALID_API_KEY",
-		})
+		respondError(c, http.StatusUnauthorized, "INVALID_API_KEY", "invalid or missing API key")
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "failed to read request body")
 		return
 	}
 
-	var input PEPEventInput
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
+	input, err := decodePEPEvent(body)
+	if errors.Is(err, ErrUnsupportedPEPSchemaVersion) {
+		respondErrorDetails(c, http.StatusBadRequest, "UNSUPPORTED_SCHEMA_VERSION", err.Error(),
+			fmt.Sprintf("supported schema versions: %d to %d", PEPEventMinSchemaVersion, PEPEventSchemaVersion))
+		return
+	}
+	if err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "invalid request body", err.Error())
 		return
 	}
 
 	// Validate hospital ID matches API key
 	inputHospitalID, err := uuid.Parse(input.HospitalID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid hospital_id format",
-		})
+		respondError(c, http.StatusBadRequest, ErrCodeValidationFailed, "invalid hospital_id format")
 		return
 	}
 
 	if *hospitalID != inputHospitalID {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": "hospital_id does not match API key",
-			"code":  "HOSPITAL_MISMATCH",
-		})
+		respondError(c, http.StatusForbidden, "HOSPITAL_MISMATCH", "hospital_id does not match API key")
 		return
 	}
 
@@ -112,21 +230,22 @@ func ReceivePEPEvent(c *gin.Context) {
 
 	// Create event for Redis Stream (compatible with ObitoEvent)
 	event := map[string]interface{}{
-		"obito_id":                 eventID,
-		"hospital_id":              input.HospitalID,
-		"timestamp_deteccao":       timestamp,
-		"nome_paciente":            input.NomePaciente,
-		"data_obito":               input.DataObito,
-		"causa_mortis":             input.CausaMortis,
-		"setor":                    input.Setor,
-		"leito":                    input.Leito,
-		"idade":                    input.Idade,
-		"identificacao_desconhecida": false,
-		"source":                   "pep",           // Mark as coming from PEP agent
-		"hospital_id_origem":       input.HospitalIDOrigem,
-		"cns":                      input.CNS,
-		"cpf_masked":               input.CPFMasked,
-		"prontuario":               input.Prontuario,
+		"obito_id":                   eventID,
+		"hospital_id":                input.HospitalID,
+		"timestamp_deteccao":         timestamp,
+		"nome_paciente":              input.NomePaciente,
+		"data_obito":                 input.DataObito,
+		"causa_mortis":               input.CausaMortis,
+		"setor":                      input.Setor,
+		"leito":                      input.Leito,
+		"idade":                      input.Idade,
+		"identificacao_desconhecida": input.IdentificacaoDesconhecida,
+		"source":                     "pep", // Mark as coming from PEP agent
+		"hospital_id_origem":         input.HospitalIDOrigem,
+		"cns":                        input.CNS,
+		"cpf_masked":                 input.CPFMasked,
+		"prontuario":                 input.Prontuario,
+		"schema_version":             input.SchemaVersion,
 	}
 
 	// Add data_nascimento if provided
@@ -139,9 +258,7 @@ func ReceivePEPEvent(c *gin.Context) {
 		ctx := context.Background()
 		eventJSON, err := json.Marshal(event)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "failed to serialize event",
-			})
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to serialize event")
 			return
 		}
 
@@ -153,9 +270,7 @@ func ReceivePEPEvent(c *gin.Context) {
 		}).Result()
 
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "failed to publish event to stream",
-			})
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to publish event to stream")
 			return
 		}
 	}
@@ -172,7 +287,7 @@ func GetPEPStatus(c *gin.Context) {
 	configured := pepRedisClient != nil && len(pepAPIKeys) > 0
 
 	c.JSON(http.StatusOK, gin.H{
-		"configured":     configured,
+		"configured":      configured,
 		"hospitals_count": len(pepAPIKeys),
 		"message": func() string {
 			if configured {
@@ -182,3 +297,58 @@ func GetPEPStatus(c *gin.Context) {
 		}(),
 	})
 }
+
+// PEPPingResponse tells an agent which hospital its API key resolves to
+type PEPPingResponse struct {
+	Status             string    `json:"status"`
+	HospitalID         uuid.UUID `json:"hospital_id"`
+	HospitalNome       string    `json:"hospital_nome"`
+	HospitalCodigo     string    `json:"hospital_codigo"`
+	TenantID           uuid.UUID `json:"tenant_id"`
+	TenantName         string    `json:"tenant_name,omitempty"`
+	TenantSlug         string    `json:"tenant_slug,omitempty"`
+	ServerTime         time.Time `json:"server_time"`
+	EventSchemaVersion int       `json:"event_schema_version"`
+	// MinEventSchemaVersion is the oldest event schema still accepted
+	MinEventSchemaVersion int `json:"min_event_schema_version"`
+}
+
+// PingPEP lets an agent verify connectivity and its API key before going live
+// GET /api/v1/pep/ping
+func PingPEP(c *gin.Context) {
+	hospitalID, valid := ValidatePEPAPIKey(c)
+	if !valid {
+		respondError(c, http.StatusUnauthorized, "INVALID_API_KEY", "invalid or missing API key")
+		return
+	}
+
+	if pepHospitalLookup == nil {
+		respondError(c, http.StatusServiceUnavailable, ErrCodeUnavailable, "hospital lookup not configured")
+		return
+	}
+
+	hospital, err := pepHospitalLookup.GetHospitalByID(c.Request.Context(), *hospitalID)
+	if err != nil {
+		respondDomainError(c, err, "failed to get hospital")
+		return
+	}
+
+	response := PEPPingResponse{
+		Status:                "ok",
+		HospitalID:            hospital.ID,
+		HospitalNome:          hospital.Nome,
+		HospitalCodigo:        hospital.Codigo,
+		TenantID:              hospital.TenantID,
+		ServerTime:            time.Now().UTC(),
+		EventSchemaVersion:    PEPEventSchemaVersion,
+		MinEventSchemaVersion: PEPEventMinSchemaVersion,
+	}
+	if hospital.TenantName != nil {
+		response.TenantName = *hospital.TenantName
+	}
+	if hospital.TenantSlug != nil {
+		response.TenantSlug = *hospital.TenantSlug
+	}
+
+	c.JSON(http.StatusOK, response)
+}
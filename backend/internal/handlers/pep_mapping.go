@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidot/backend/pkg/pepmapping"
+)
+
+// PEPMappingValidationResponse is the result of validating a PEP agent mapping
+type PEPMappingValidationResponse struct {
+	Valid    bool               `json:"valid"`
+	Errors   []pepmapping.Issue `json:"errors"`
+	Warnings []pepmapping.Issue `json:"warnings"`
+}
+
+// ValidatePEPMapping checks a PEP agent mapping against the server rules, so a hospital
+// can verify its mapping.yaml before deploying the agent. The body is the YAML (or JSON)
+// mapping file, or its mapping section alone. Invalid mappings still answer 200.
+// POST /api/v1/pep/validate-mapping
+func ValidatePEPMapping(c *gin.Context) {
+	if _, valid := ValidatePEPAPIKey(c); !valid {
+		respondError(c, http.StatusUnauthorized, "INVALID_API_KEY", "invalid or missing API key")
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondError(c, http.StatusRequestEntityTooLarge, ErrCodePayloadTooLarge, "request body too large")
+			return
+		}
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "failed to read request body")
+		return
+	}
+	if strings.TrimSpace(string(body)) == "" {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "mapping is required")
+		return
+	}
+
+	mapping, err := pepmapping.Parse(body)
+	if err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, "INVALID_MAPPING", "mapping is not valid YAML or JSON", err.Error())
+		return
+	}
+
+	issues := pepmapping.Validate(*mapping)
+	errs := pepmapping.Errors(issues)
+	c.JSON(http.StatusOK, PEPMappingValidationResponse{
+		Valid:    len(errs) == 0,
+		Errors:   errs,
+		Warnings: pepmapping.Warnings(issues),
+	})
+}
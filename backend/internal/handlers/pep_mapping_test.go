@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePEPMapping(t *testing.T) {
+	SetPEPAPIKeys(map[string]uuid.UUID{"test-key": uuid.New()})
+	defer SetPEPAPIKeys(nil)
+
+	router := setupTestRouter()
+	router.POST("/pep/validate-mapping", ValidatePEPMapping)
+
+	post := func(apiKey, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/pep/validate-mapping", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/yaml")
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("requires an API key", func(t *testing.T) {
+		w := post("", "mapping: {}")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "INVALID_API_KEY", decodeErrorResponse(t, w).Code)
+	})
+
+	t.Run("mapping missing the death date", func(t *testing.T) {
+		w := post("test-key", `
+mapping:
+  source_table: TASY.TB_PACIENTE_OBITO
+  fields:
+    id: CD_PACIENTE_OBITO
+    nome_paciente: NM_PACIENTE
+    causa_mortis: DS_CAUSA_MORTIS
+    idade: NR_IDADE
+    cpf: NR_CPF
+`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response PEPMappingValidationResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.False(t, response.Valid)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, "fields.data_obito", response.Errors[0].Field)
+		assert.Empty(t, response.Warnings)
+	})
+
+	t.Run("valid JSON mapping", func(t *testing.T) {
+		w := post("test-key", `{"source_table":"OBITOS","fields":{"id":"ID","nome_paciente":"NOME","data_obito":"DT","causa_mortis":"CAUSA","idade":"IDADE"}}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response PEPMappingValidationResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Valid)
+		assert.Empty(t, response.Errors)
+		assert.Len(t, response.Warnings, 1, "no patient identification")
+	})
+
+	t.Run("unparseable body", func(t *testing.T) {
+		w := post("test-key", "mapping: [unclosed")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_MAPPING", decodeErrorResponse(t, w).Code)
+	})
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePEPHospitalLookup returns the hospitals it holds
+type fakePEPHospitalLookup struct {
+	hospitals map[uuid.UUID]*models.HospitalWithTenant
+}
+
+func (f *fakePEPHospitalLookup) GetHospitalByID(ctx context.Context, id uuid.UUID) (*models.HospitalWithTenant, error) {
+	if hospital, ok := f.hospitals[id]; ok {
+		return hospital, nil
+	}
+	return nil, repository.ErrAdminHospitalNotFound
+}
+
+func TestPingPEP(t *testing.T) {
+	tenantName, tenantSlug := "Central Goias", "goias"
+	hospital := &models.HospitalWithTenant{
+		ID:         uuid.New(),
+		TenantID:   uuid.New(),
+		Nome:       "Hospital Geral",
+		Codigo:     "HGG",
+		TenantName: &tenantName,
+		TenantSlug: &tenantSlug,
+	}
+	SetPEPAPIKeys(map[string]uuid.UUID{"valid-key": hospital.ID, "orphan-key": uuid.New()})
+	SetPEPHospitalLookup(&fakePEPHospitalLookup{hospitals: map[uuid.UUID]*models.HospitalWithTenant{hospital.ID: hospital}})
+	defer func() {
+		SetPEPAPIKeys(nil)
+		SetPEPHospitalLookup(nil)
+	}()
+
+	router := setupTestRouter()
+	router.GET("/pep/ping", PingPEP)
+
+	ping := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/pep/ping", nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("valid key returns the hospital", func(t *testing.T) {
+		w := ping("valid-key")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response PEPPingResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "ok", response.Status)
+		assert.Equal(t, hospital.ID, response.HospitalID)
+		assert.Equal(t, "Hospital Geral", response.HospitalNome)
+		assert.Equal(t, hospital.TenantID, response.TenantID)
+		assert.Equal(t, "goias", response.TenantSlug)
+		assert.Equal(t, PEPEventSchemaVersion, response.EventSchemaVersion)
+		assert.False(t, response.ServerTime.IsZero())
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		w := ping("wrong-key")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "INVALID_API_KEY", decodeErrorResponse(t, w).Code)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, ping("").Code)
+	})
+
+	t.Run("client certificate without API key", func(t *testing.T) {
+		certRouter := setupTestRouter()
+		certRouter.GET("/pep/ping", func(c *gin.Context) {
+			c.Set(middleware.PEPCertHospitalKey, hospital.ID) // set by middleware.PEPClientCert
+			PingPEP(c)
+		})
+		w := httptest.NewRecorder()
+		certRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pep/ping", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), hospital.ID.String())
+	})
+
+	t.Run("key of a removed hospital", func(t *testing.T) {
+		w := ping("orphan-key")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "HOSPITAL_NOT_FOUND", decodeErrorResponse(t, w).Code)
+	})
+}
+
+func TestReceivePEPEventSchemaVersions(t *testing.T) {
+	hospitalID := uuid.New()
+	SetPEPAPIKeys(map[string]uuid.UUID{"valid-key": hospitalID})
+	defer SetPEPAPIKeys(nil)
+
+	router := setupTestRouter()
+	router.POST("/pep/eventos", ReceivePEPEvent)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/pep/eventos", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", "valid-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	v1 := `{"hospital_id":"` + hospitalID.String() + `","nome_paciente":"Joao Silva","data_obito":"2026-10-14T10:00:00Z","causa_mortis":"PCR","idade":60,"identificacao_desconhecida":true}`
+	v2Unknown := `{"schema_version":2,"hospital_id":"` + hospitalID.String() + `","data_obito":"2026-10-14T10:00:00Z","causa_mortis":"PCR","idade":60,"identificacao_desconhecida":true}`
+
+	t.Run("accepts v1 without schema_version", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, post(v1).Code)
+	})
+
+	t.Run("upconverts v1", func(t *testing.T) {
+		input, err := decodePEPEvent([]byte(v1))
+		require.NoError(t, err)
+		assert.Equal(t, PEPEventSchemaVersion, input.SchemaVersion)
+		assert.Equal(t, "Joao Silva", input.NomePaciente)
+		assert.False(t, input.IdentificacaoDesconhecida, "v1 does not support the unknown identification flag")
+	})
+
+	t.Run("v1 still requires the patient name", func(t *testing.T) {
+		w := post(`{"schema_version":1,"hospital_id":"` + hospitalID.String() + `","data_obito":"2026-10-14T10:00:00Z","causa_mortis":"PCR"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, ErrCodeInvalidRequestBody, decodeErrorResponse(t, w).Code)
+	})
+
+	t.Run("accepts v2 unknown patient without name", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, post(v2Unknown).Code)
+
+		input, err := decodePEPEvent([]byte(v2Unknown))
+		require.NoError(t, err)
+		assert.True(t, input.IdentificacaoDesconhecida)
+	})
+
+	t.Run("v2 requires the name of an identified patient", func(t *testing.T) {
+		w := post(`{"schema_version":2,"hospital_id":"` + hospitalID.String() + `","data_obito":"2026-10-14T10:00:00Z","causa_mortis":"PCR"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("rejects an unknown future version", func(t *testing.T) {
+		w := post(`{"schema_version":999,"hospital_id":"` + hospitalID.String() + `","nome_paciente":"Joao","data_obito":"2026-10-14T10:00:00Z","causa_mortis":"PCR"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		response := decodeErrorResponse(t, w)
+		assert.Equal(t, "UNSUPPORTED_SCHEMA_VERSION", response.Code)
+		assert.Contains(t, response.Message, "999")
+		assert.Equal(t, "supported schema versions: 1 to 2", response.Details)
+	})
+}
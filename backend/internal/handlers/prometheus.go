@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidot/backend/internal/services/health"
+	"github.com/sidot/backend/internal/services/notification"
+)
+
+// prometheusContentType is the Prometheus text exposition format content type
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var globalEmailQueueWorker *notification.EmailQueueWorker
+
+// SetGlobalEmailQueueWorker sets the global email queue worker instance for metrics
+func SetGlobalEmailQueueWorker(w *notification.EmailQueueWorker) {
+	globalEmailQueueWorker = w
+}
+
+// metricsSnapshot holds the values exposed at /metrics; nil fields are components that are not initialized
+type metricsSnapshot struct {
+	Triagem         *TriagemMotorDetails
+	EmailQueueDepth *int64
+	SSEClients      *int
+	Components      map[string]health.ComponentStatus
+}
+
+// PrometheusMetrics serves operational counters in the Prometheus text exposition format
+// When token is set, scrapers must send it as "Authorization: Bearer <token>"
+func PrometheusMetrics(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token != "" {
+			provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid metrics token"})
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+		defer cancel()
+
+		var buf bytes.Buffer
+		writePrometheusMetrics(&buf, collectMetricsSnapshot(ctx))
+		c.Data(http.StatusOK, prometheusContentType, buf.Bytes())
+	}
+}
+
+// collectMetricsSnapshot reads the current counters from the global service instances
+func collectMetricsSnapshot(ctx context.Context) *metricsSnapshot {
+	snapshot := &metricsSnapshot{}
+
+	if globalTriagemMotor != nil {
+		stats := globalTriagemMotor.GetStats()
+		snapshot.Triagem = &TriagemMotorDetails{
+			Running:          stats["running"].(bool),
+			TotalProcessados: stats["total_processados"].(int64),
+			TotalElegiveis:   stats["total_elegiveis"].(int64),
+			TotalInelegiveis: stats["total_inelegiveis"].(int64),
+			Errors:           stats["errors"].(int64),
+		}
+	}
+
+	if globalEmailQueueWorker != nil {
+		if depth, err := globalEmailQueueWorker.GetQueueLength(ctx); err == nil {
+			snapshot.EmailQueueDepth = &depth
+		}
+	}
+
+	if globalSSEHub != nil {
+		clients := globalSSEHub.GetClientCount()
+		snapshot.SSEClients = &clients
+	}
+
+	// Use the monitor's last check instead of probing every component on each scrape
+	if globalHealthMonitor != nil {
+		if summary := globalHealthMonitor.GetLastSummary(); summary != nil {
+			snapshot.Components = summary.Components
+		}
+	}
+
+	return snapshot
+}
+
+// writePrometheusMetrics writes the snapshot in the Prometheus text exposition format
+func writePrometheusMetrics(w io.Writer, s *metricsSnapshot) {
+	if s.Triagem != nil {
+		writeMetric(w, "sidot_triagem_occurrences_processed_total", "counter", "Obitos evaluated by the triagem motor.", s.Triagem.TotalProcessados)
+		writeMetric(w, "sidot_triagem_occurrences_eligible_total", "counter", "Obitos evaluated as eligible for donation.", s.Triagem.TotalElegiveis)
+		writeMetric(w, "sidot_triagem_occurrences_ineligible_total", "counter", "Obitos evaluated as ineligible for donation.", s.Triagem.TotalInelegiveis)
+		writeMetric(w, "sidot_triagem_errors_total", "counter", "Errors while consuming or evaluating obitos.", s.Triagem.Errors)
+		writeMetric(w, "sidot_triagem_motor_running", "gauge", "Whether the triagem motor is running (1) or stopped (0).", boolGauge(s.Triagem.Running))
+	}
+
+	if s.EmailQueueDepth != nil {
+		writeMetric(w, "sidot_email_queue_depth", "gauge", "Emails waiting in the notification queue.", *s.EmailQueueDepth)
+	}
+
+	if s.SSEClients != nil {
+		writeMetric(w, "sidot_sse_connected_clients", "gauge", "Clients connected to the realtime notification stream.", int64(*s.SSEClients))
+	}
+
+	if len(s.Components) > 0 {
+		keys := make([]string, 0, len(s.Components))
+		for key := range s.Components {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		fmt.Fprintln(w, "# HELP sidot_component_up Whether a component was up or degraded (1) or down (0) at the last health check.")
+		fmt.Fprintln(w, "# TYPE sidot_component_up gauge")
+		for _, key := range keys {
+			fmt.Fprintf(w, "sidot_component_up{component=\"%s\"} %d\n", escapeLabelValue(key), boolGauge(s.Components[key].Status != health.StatusDown))
+		}
+	}
+}
+
+// writeMetric writes a single unlabelled metric with its HELP and TYPE lines
+func writeMetric(w io.Writer, name, metricType, help string, value int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", name, help, name, metricType, name, value)
+}
+
+// escapeLabelValue escapes a label value as required by the exposition format
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func boolGauge(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/sidot/backend/internal/services/health"
+	"github.com/sidot/backend/internal/services/notification"
+	"github.com/sidot/backend/internal/services/triagem"
+)
+
+var (
+	promSampleLine = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\]|\\.)*"(?:,[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\]|\\.)*")*\})? -?[0-9]+(?:\.[0-9]+)?$`)
+	promTypeLine   = regexp.MustCompile(`^# TYPE ([a-zA-Z_:][a-zA-Z0-9_:]*) (counter|gauge)$`)
+)
+
+// parsePrometheusText parses exposition-format output and returns the samples per metric name,
+// failing on malformed lines or samples without a TYPE declaration
+func parsePrometheusText(t *testing.T, body string) map[string][]string {
+	t.Helper()
+
+	types := make(map[string]string)
+	samples := make(map[string][]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "# HELP "):
+			continue
+		case strings.HasPrefix(line, "# TYPE "):
+			match := promTypeLine.FindStringSubmatch(line)
+			if match == nil {
+				t.Fatalf("Malformed TYPE line %q", line)
+			}
+			types[match[1]] = match[2]
+		default:
+			match := promSampleLine.FindStringSubmatch(line)
+			if match == nil {
+				t.Fatalf("Malformed sample line %q", line)
+			}
+			if _, ok := types[match[1]]; !ok {
+				t.Fatalf("Sample %q has no TYPE declaration", line)
+			}
+			samples[match[1]] = append(samples[match[1]], line)
+		}
+	}
+	return samples
+}
+
+// TestWritePrometheusMetrics tests that every metric is written in the exposition format
+func TestWritePrometheusMetrics(t *testing.T) {
+	depth := int64(3)
+	clients := 7
+	snapshot := &metricsSnapshot{
+		Triagem: &TriagemMotorDetails{
+			Running:          true,
+			TotalProcessados: 42,
+			TotalElegiveis:   30,
+			TotalInelegiveis: 12,
+			Errors:           1,
+		},
+		EmailQueueDepth: &depth,
+		SSEClients:      &clients,
+		Components: map[string]health.ComponentStatus{
+			"redis":    {Name: "Redis", Status: health.StatusDown},
+			"database": {Name: "Database", Status: health.StatusUp},
+			"sse_hub":  {Name: "SSE Hub", Status: health.StatusDegraded},
+		},
+	}
+
+	var buf bytes.Buffer
+	writePrometheusMetrics(&buf, snapshot)
+	samples := parsePrometheusText(t, buf.String())
+
+	expected := map[string]string{
+		"sidot_triagem_occurrences_processed_total":  "sidot_triagem_occurrences_processed_total 42",
+		"sidot_triagem_occurrences_eligible_total":   "sidot_triagem_occurrences_eligible_total 30",
+		"sidot_triagem_occurrences_ineligible_total": "sidot_triagem_occurrences_ineligible_total 12",
+		"sidot_triagem_errors_total":                 "sidot_triagem_errors_total 1",
+		"sidot_triagem_motor_running":                "sidot_triagem_motor_running 1",
+		"sidot_email_queue_depth":                    "sidot_email_queue_depth 3",
+		"sidot_sse_connected_clients":                "sidot_sse_connected_clients 7",
+	}
+	for name, line := range expected {
+		if len(samples[name]) != 1 || samples[name][0] != line {
+			t.Errorf("Expected %q, got %v", line, samples[name])
+		}
+	}
+
+	components := samples["sidot_component_up"]
+	want := []string{
+		`sidot_component_up{component="database"} 1`,
+		`sidot_component_up{component="redis"} 0`,
+		`sidot_component_up{component="sse_hub"} 1`,
+	}
+	if strings.Join(components, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected component gauges:\n%s", strings.Join(components, "\n"))
+	}
+}
+
+// TestWritePrometheusMetricsOmitsUninitializedComponents tests that metrics are skipped for services that are not running
+func TestWritePrometheusMetricsOmitsUninitializedComponents(t *testing.T) {
+	var buf bytes.Buffer
+	writePrometheusMetrics(&buf, &metricsSnapshot{})
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output, got %q", buf.String())
+	}
+}
+
+// TestPrometheusMetricsEndpoint tests the scrape endpoint, including the optional bearer token
+func TestPrometheusMetricsEndpoint(t *testing.T) {
+	previousMotor, previousHub := globalTriagemMotor, globalSSEHub
+	globalTriagemMotor = triagem.NewTriagemMotor(nil, nil)
+	globalSSEHub = notification.NewSSEHub(nil, nil)
+	defer func() { globalTriagemMotor, globalSSEHub = previousMotor, previousHub }()
+
+	router := setupTestRouter()
+	router.GET("/metrics", PrometheusMetrics("scrape-secret"))
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"without token", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer wrong", http.StatusUnauthorized},
+		{"valid token", "Bearer scrape-secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			if ct := w.Header().Get("Content-Type"); ct != prometheusContentType {
+				t.Errorf("Expected content type %q, got %q", prometheusContentType, ct)
+			}
+			samples := parsePrometheusText(t, w.Body.String())
+			for _, name := range []string{"sidot_triagem_occurrences_processed_total", "sidot_triagem_errors_total", "sidot_sse_connected_clients"} {
+				if len(samples[name]) == 0 {
+					t.Errorf("Expected metric %s in output", name)
+				}
+			}
+		})
+	}
+}
@@ -127,6 +127,29 @@ func ExportPDF(c *gin.Context) {
 	}
 }
 
+// GetCausaMortisReport handles GET /api/v1/reports/causa-mortis
+// Returns the eligibility rate and most common exclusion reason per normalized cause of death
+func GetCausaMortisReport(c *gin.Context) {
+	if reportService == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "report service not configured")
+		return
+	}
+
+	filters, err := parseReportFilters(c)
+	if err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid filter parameters", err.Error())
+		return
+	}
+
+	result, err := reportService.CausaMortisReport(c.Request.Context(), filters)
+	if err != nil {
+		respondErrorDetails(c, http.StatusInternalServerError, ErrCodeInternal, "failed to generate causa mortis report", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // parseReportFilters parses and validates query parameters for report filters
 func parseReportFilters(c *gin.Context) (models.ReportFilters, error) {
 	var filters models.ReportFilters
@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+// MaxShiftImportRows caps the number of shifts in a single CSV import
+const MaxShiftImportRows = 500
+
+// shiftImportColumns are the required columns of a shift import CSV
+var shiftImportColumns = []string{"day_of_week", "start_time", "end_time", "operator_email"}
+
+// shiftBatchCreator creates shifts in one transaction (implemented by ShiftRepository)
+type shiftBatchCreator interface {
+	CreateBatch(ctx context.Context, inputs []models.CreateShiftInput) ([]*models.Shift, []error, error)
+}
+
+// shiftOperatorDirectory resolves operators by email with their hospitals (implemented by UserRepository)
+type shiftOperatorDirectory interface {
+	GetByEmail(ctx context.Context, email string) (*auth.User, error)
+	GetModelByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+}
+
+// ShiftImportRowResult is the outcome of one CSV row
+type ShiftImportRowResult struct {
+	Line    int                   `json:"line"`
+	Status  string                `json:"status"` // "created" or "rejected"
+	Shift   *models.ShiftResponse `json:"shift,omitempty"`
+	Code    string                `json:"code,omitempty"`
+	Error   string                `json:"error,omitempty"`
+	Details string                `json:"details,omitempty"`
+}
+
+// ShiftImportResponse is the result of a shift import
+type ShiftImportResponse struct {
+	Created  int                    `json:"created"`
+	Rejected int                    `json:"rejected"`
+	Rows     []ShiftImportRowResult `json:"rows"`
+}
+
+// shiftImportRecord holds the fields of a CSV row, ordered as shiftImportColumns
+type shiftImportRecord struct {
+	line   int
+	fields []string
+}
+
+// shiftImportRow is a parsed CSV row waiting to be created
+type shiftImportRow struct {
+	result *ShiftImportRowResult
+	input  models.CreateShiftInput
+}
+
+// ImportShifts creates the weekly shifts of a hospital from a CSV
+// @Summary Import shifts from CSV
+// @Description Create shifts from a CSV with day_of_week, start_time, end_time and operator_email columns,
+// @Description sent as the request body or as the "file" multipart field. Rows with an unknown operator,
+// @Description invalid times or overlapping another shift are rejected and the rest are imported.
+// @Tags shifts
+// @Accept text/csv
+// @Produce json
+// @Param id path string true "Hospital ID"
+// @Success 200 {object} ShiftImportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/v1/hospitals/{id}/shifts/import [post]
+func (h *ShiftHandler) ImportShifts(c *gin.Context) {
+	claims, exists := middleware.GetUserClaims(c)
+	if !exists {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Não autorizado")
+		return
+	}
+
+	if claims.Role != string(models.RoleAdmin) && claims.Role != string(models.RoleGestor) {
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "Sem permissão para importar escalas")
+		return
+	}
+
+	hospitalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "ID do hospital inválido")
+		return
+	}
+
+	// Gestor can only import shifts for their hospital
+	if claims.Role == string(models.RoleGestor) && claims.HospitalID != "" {
+		claimHospitalID, err := uuid.Parse(claims.HospitalID)
+		if err == nil && hospitalID != claimHospitalID {
+			respondError(c, http.StatusForbidden, ErrCodeForbidden, "Gestores só podem importar escalas do próprio hospital")
+			return
+		}
+	}
+
+	body, err := shiftImportBody(c)
+	if err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "Arquivo CSV inválido", err.Error())
+		return
+	}
+	defer body.Close()
+
+	records, err := readShiftImportCSV(body)
+	if err != nil {
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "Arquivo CSV inválido", err.Error())
+		return
+	}
+
+	response := ShiftImportResponse{Rows: make([]ShiftImportRowResult, len(records))}
+	var pending []shiftImportRow
+	operators := make(map[string]*models.User)
+
+	for i, record := range records {
+		result := &response.Rows[i]
+		result.Line = record.line
+
+		input, err := h.parseShiftImportRecord(c.Request.Context(), hospitalID, record.fields, operators)
+		if err != nil {
+			rejectShiftImportRow(result, err)
+			continue
+		}
+		pending = append(pending, shiftImportRow{result: result, input: *input})
+	}
+
+	if len(pending) > 0 {
+		inputs := make([]models.CreateShiftInput, len(pending))
+		for i, row := range pending {
+			inputs[i] = row.input
+		}
+
+		created, rowErrs, err := h.shiftImporter.CreateBatch(c.Request.Context(), inputs)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Erro ao importar escalas")
+			return
+		}
+		for i, row := range pending {
+			if rowErrs[i] != nil {
+				rejectShiftImportRow(row.result, rowErrs[i])
+				continue
+			}
+			shift := created[i].ToResponse()
+			row.result.Status = "created"
+			row.result.Shift = &shift
+		}
+	}
+
+	for _, row := range response.Rows {
+		if row.Status == "created" {
+			response.Created++
+		} else {
+			response.Rejected++
+		}
+	}
+	if response.Created > 0 {
+		publishMapShiftChange(c.Request.Context(), hospitalID)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// shiftImportBody returns the CSV, from the "file" multipart field or the request body
+func shiftImportBody(c *gin.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		return c.Request.Body, nil
+	}
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("missing file field: %w", err)
+	}
+	return file, nil
+}
+
+// readShiftImportCSV reads the rows of a shift import, ordering their fields as shiftImportColumns
+func readShiftImportCSV(r io.Reader) ([]shiftImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("empty file")
+		}
+		return nil, err
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, column := range header {
+		positions[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))] = i
+	}
+	indexes := make([]int, len(shiftImportColumns))
+	for i, column := range shiftImportColumns {
+		index, ok := positions[column]
+		if !ok {
+			return nil, fmt.Errorf("missing column %s", column)
+		}
+		indexes[i] = index
+	}
+
+	var records []shiftImportRecord
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == MaxShiftImportRows {
+			return nil, fmt.Errorf("more than %d rows", MaxShiftImportRows)
+		}
+
+		fields := make([]string, len(indexes))
+		for i, index := range indexes {
+			if index < len(record) {
+				fields[i] = strings.TrimSpace(record[index])
+			}
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, shiftImportRecord{line: line, fields: fields})
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("no shifts to import")
+	}
+	return records, nil
+}
+
+// shiftOperatorError is returned for a row whose operator cannot work at the hospital
+type shiftOperatorError struct {
+	reason string
+}
+
+func (e *shiftOperatorError) Error() string {
+	return e.reason
+}
+
+// parseShiftImportRecord converts a CSV row into a shift of the hospital, resolving its operator
+func (h *ShiftHandler) parseShiftImportRecord(ctx context.Context, hospitalID uuid.UUID, record []string, operators map[string]*models.User) (*models.CreateShiftInput, error) {
+	day, err := strconv.Atoi(record[0])
+	if err != nil {
+		return nil, models.ErrInvalidDayOfWeek
+	}
+
+	input := &models.CreateShiftInput{
+		HospitalID: hospitalID,
+		DayOfWeek:  models.DayOfWeek(day),
+		StartTime:  models.ShiftTime(record[1]),
+		EndTime:    models.ShiftTime(record[2]),
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	email := record[3]
+	operator, ok := operators[email]
+	if !ok {
+		authUser, err := h.operators.GetByEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		operator, err = h.operators.GetModelByID(ctx, authUser.ID)
+		if err != nil {
+			return nil, err
+		}
+		operators[email] = operator
+	}
+
+	if reason := assigneeIneligibility(operator, hospitalID); reason != "" {
+		return nil, &shiftOperatorError{reason: reason}
+	}
+
+	input.UserID = operator.ID
+	return input, nil
+}
+
+// rejectShiftImportRow records why a row was not imported
+func rejectShiftImportRow(result *ShiftImportRowResult, err error) {
+	result.Status = "rejected"
+
+	var invalidOperator *shiftOperatorError
+	switch {
+	case errors.Is(err, auth.ErrUserNotFound):
+		result.Code = "OPERATOR_NOT_FOUND"
+		result.Error = "Operador não encontrado"
+	case errors.As(err, &invalidOperator):
+		result.Code = "INVALID_OPERATOR"
+		result.Error = "Operador não pode atuar neste hospital"
+		result.Details = invalidOperator.reason
+	default:
+		if mapped, ok := lookupDomainError(err); ok {
+			result.Code = mapped.code
+			result.Error = mapped.message
+			result.Details = err.Error()
+			return
+		}
+		result.Code = ErrCodeInternal
+		result.Error = "Erro ao importar escala"
+	}
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+// mockShiftImportRepository simulates ShiftRepository.CreateBatch and the user lookups of the import
+type mockShiftImportRepository struct {
+	users   map[string]*models.User
+	shifts  []models.Shift
+	batches int
+}
+
+func (r *mockShiftImportRepository) GetByEmail(ctx context.Context, email string) (*auth.User, error) {
+	user, ok := r.users[email]
+	if !ok {
+		return nil, auth.ErrUserNotFound
+	}
+	return &auth.User{ID: user.ID, Email: user.Email}, nil
+}
+
+func (r *mockShiftImportRepository) GetModelByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	for _, user := range r.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, auth.ErrUserNotFound
+}
+
+func (r *mockShiftImportRepository) CreateBatch(ctx context.Context, inputs []models.CreateShiftInput) ([]*models.Shift, []error, error) {
+	r.batches++
+	created := make([]*models.Shift, len(inputs))
+	rowErrs := make([]error, len(inputs))
+	for i, input := range inputs {
+		shift := models.Shift{ID: uuid.New(), HospitalID: input.HospitalID, UserID: input.UserID, DayOfWeek: input.DayOfWeek, StartTime: input.StartTime, EndTime: input.EndTime}
+		for _, existing := range r.shifts {
+			if existing.UserID == shift.UserID && shift.Overlaps(&existing) {
+				rowErrs[i] = &models.ShiftOverlapError{Existing: existing}
+				break
+			}
+		}
+		if rowErrs[i] == nil {
+			r.shifts = append(r.shifts, shift)
+			created[i] = &shift
+		}
+	}
+	return created, rowErrs, nil
+}
+
+// requestShiftImport posts a CSV to the import endpoint of a hospital
+func requestShiftImport(t *testing.T, repo *mockShiftImportRepository, claims *middleware.UserClaims, hospitalID uuid.UUID, csv string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	handler := &ShiftHandler{shiftImporter: repo, operators: repo}
+
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_claims", claims)
+		c.Next()
+	})
+	router.POST("/api/v1/hospitals/:id/shifts/import", handler.ImportShifts)
+
+	req, _ := http.NewRequest("POST", "/api/v1/hospitals/"+hospitalID.String()+"/shifts/import", strings.NewReader(csv))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func shiftImportOperator(email string, hospitalID uuid.UUID) *models.User {
+	return &models.User{
+		ID:        uuid.New(),
+		Email:     email,
+		Nome:      "Operador " + email,
+		Role:      models.RoleOperador,
+		Ativo:     true,
+		Hospitals: []models.Hospital{{ID: hospitalID}},
+	}
+}
+
+// TestImportShiftsRejectsOverlapAndImportsTheRest tests that an overlapping row is rejected while the other rows import
+func TestImportShiftsRejectsOverlapAndImportsTheRest(t *testing.T) {
+	hospitalID := uuid.New()
+	ana := shiftImportOperator("ana@hospital.com", hospitalID)
+	bruno := shiftImportOperator("bruno@hospital.com", hospitalID)
+	outsider := shiftImportOperator("carla@outro.com", uuid.New())
+	repo := &mockShiftImportRepository{users: map[string]*models.User{
+		ana.Email: ana, bruno.Email: bruno, outsider.Email: outsider,
+	}}
+
+	csv := "day_of_week,start_time,end_time,operator_email\n" +
+		"1,07:00,19:00,ana@hospital.com\n" +
+		"1,19:00,07:00,bruno@hospital.com\n" +
+		"1,13:00,20:00,ana@hospital.com\n" + // overlaps Ana's Monday day shift
+		"2,07:00,19:00,ana@hospital.com\n" +
+		"3,25:00,19:00,bruno@hospital.com\n" +
+		"4,07:00,19:00,ninguem@hospital.com\n" +
+		"4,07:00,19:00,carla@outro.com\n"
+	admin := &middleware.UserClaims{UserID: uuid.New().String(), Role: "admin"}
+
+	w := requestShiftImport(t, repo, admin, hospitalID, csv)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Esperado status 200, recebido %d: %s", w.Code, w.Body.String())
+	}
+
+	var response ShiftImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Erro ao decodificar resposta: %v", err)
+	}
+
+	expected := []struct {
+		line   int
+		status string
+		code   string
+	}{
+		{2, "created", ""},
+		{3, "created", ""},
+		{4, "rejected", "SHIFT_OVERLAP"},
+		{5, "created", ""},
+		{6, "rejected", "INVALID_START_TIME"},
+		{7, "rejected", "OPERATOR_NOT_FOUND"},
+		{8, "rejected", "INVALID_OPERATOR"},
+	}
+	if len(response.Rows) != len(expected) {
+		t.Fatalf("Esperado %d linhas, recebido %d", len(expected), len(response.Rows))
+	}
+	for i, want := range expected {
+		row := response.Rows[i]
+		if row.Line != want.line || row.Status != want.status || row.Code != want.code {
+			t.Errorf("Linha %d: esperado %s/%q, recebido linha %d %s/%q (%s)", want.line, want.status, want.code, row.Line, row.Status, row.Code, row.Error)
+		}
+	}
+
+	if response.Created != 3 || response.Rejected != 4 {
+		t.Errorf("Esperado 3 criadas e 4 rejeitadas, recebido %d e %d", response.Created, response.Rejected)
+	}
+	if repo.batches != 1 || len(repo.shifts) != 3 {
+		t.Errorf("Esperado um lote com 3 escalas, recebido %d lotes e %d escalas", repo.batches, len(repo.shifts))
+	}
+	if response.Rows[0].Shift == nil || response.Rows[0].Shift.UserID != ana.ID || response.Rows[0].Shift.HospitalID != hospitalID {
+		t.Errorf("Escala criada inesperada: %+v", response.Rows[0].Shift)
+	}
+}
+
+// TestImportShiftsValidatesRequest tests the permission and file checks of the import
+func TestImportShiftsValidatesRequest(t *testing.T) {
+	hospitalID := uuid.New()
+	repo := &mockShiftImportRepository{users: map[string]*models.User{}}
+	validCSV := "day_of_week,start_time,end_time,operator_email\n1,07:00,19:00,ana@hospital.com\n"
+
+	tests := []struct {
+		name   string
+		claims *middleware.UserClaims
+		csv    string
+		status int
+	}{
+		{"gestor of another hospital", &middleware.UserClaims{Role: "gestor", HospitalID: uuid.New().String()}, validCSV, http.StatusForbidden},
+		{"operador", &middleware.UserClaims{Role: "operador", HospitalID: hospitalID.String()}, validCSV, http.StatusForbidden},
+		{"missing column", &middleware.UserClaims{Role: "admin"}, "day_of_week,start_time,end_time\n1,07:00,19:00\n", http.StatusBadRequest},
+		{"no rows", &middleware.UserClaims{Role: "admin"}, "day_of_week,start_time,end_time,operator_email\n", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := requestShiftImport(t, repo, tt.claims, hospitalID, tt.csv)
+			if w.Code != tt.status {
+				t.Errorf("Esperado status %d, recebido %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+	if repo.batches != 0 {
+		t.Errorf("Esperado nenhum lote criado, recebido %d", repo.batches)
+	}
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// shiftTemplateStore stores shift templates (implemented by ShiftTemplateRepository)
+type shiftTemplateStore interface {
+	Create(ctx context.Context, input *models.CreateShiftTemplateInput) (*models.ShiftTemplate, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ShiftTemplate, error)
+	List(ctx context.Context) ([]models.ShiftTemplate, error)
+}
+
+// shiftSetCreator creates a set of shifts, all or none (implemented by ShiftRepository)
+type shiftSetCreator interface {
+	CreateAll(ctx context.Context, inputs []models.CreateShiftInput) ([]*models.Shift, error)
+}
+
+// shiftTemplateOperatorSource loads the operators a template is applied to (implemented by UserRepository)
+type shiftTemplateOperatorSource interface {
+	GetModelByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+}
+
+// ShiftTemplateHandler handles shift template HTTP requests
+type ShiftTemplateHandler struct {
+	templates shiftTemplateStore
+	shifts    shiftSetCreator
+	operators shiftTemplateOperatorSource
+}
+
+// NewShiftTemplateHandler creates a new shift template handler
+func NewShiftTemplateHandler(templateRepo *repository.ShiftTemplateRepository, shiftRepo *repository.ShiftRepository, userRepo *repository.UserRepository) *ShiftTemplateHandler {
+	return &ShiftTemplateHandler{
+		templates: templateRepo,
+		shifts:    shiftRepo,
+		operators: userRepo,
+	}
+}
+
+// ApplyShiftTemplateResponse is the result of applying a shift template to a hospital
+type ApplyShiftTemplateResponse struct {
+	TemplateID uuid.UUID              `json:"template_id"`
+	HospitalID uuid.UUID              `json:"hospital_id"`
+	Total      int                    `json:"total"`
+	Shifts     []models.ShiftResponse `json:"shifts"`
+}
+
+// Create creates a shift template
+// @Summary Create a shift template
+// @Description Create a weekly rotation whose slots repeat on the given days, or on all seven days
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Param template body models.CreateShiftTemplateInput true "Template data"
+// @Success 201 {object} models.ShiftTemplate
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/shift-templates [post]
+func (h *ShiftTemplateHandler) Create(c *gin.Context) {
+	var input models.CreateShiftTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, err.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		respondDomainError(c, err, "Modelo de escala inválido")
+		return
+	}
+
+	template, err := h.templates.Create(c.Request.Context(), &input)
+	if err != nil {
+		respondDomainError(c, err, "Erro ao criar modelo de escala")
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// List lists the shift templates
+// @Summary List shift templates
+// @Description List the shift templates of the tenant and the global ones
+// @Tags shifts
+// @Produce json
+// @Success 200 {array} models.ShiftTemplate
+// @Router /api/v1/shift-templates [get]
+func (h *ShiftTemplateHandler) List(c *gin.Context) {
+	templates, err := h.templates.List(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Erro ao buscar modelos de escala")
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// Apply creates the shifts of a template at a hospital
+// @Summary Apply a shift template to a hospital
+// @Description Create the shifts of every slot of the template for the week, assigning each slot to the
+// @Description operator at the same position. No shift is created when any of them overlaps an existing one.
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Param id path string true "Hospital ID"
+// @Param templateId path string true "Template ID"
+// @Param input body models.ApplyShiftTemplateInput true "Operators of the slots"
+// @Success 201 {object} ApplyShiftTemplateResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/v1/hospitals/{id}/shift-templates/{templateId}/apply [post]
+func (h *ShiftTemplateHandler) Apply(c *gin.Context) {
+	claims, exists := middleware.GetUserClaims(c)
+	if !exists {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Não autorizado")
+		return
+	}
+
+	if claims.Role != string(models.RoleAdmin) && claims.Role != string(models.RoleGestor) {
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "Sem permissão para criar escalas")
+		return
+	}
+
+	hospitalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "ID do hospital inválido")
+		return
+	}
+
+	// Gestor can only apply templates to their hospital
+	if claims.Role == string(models.RoleGestor) && claims.HospitalID != "" {
+		claimHospitalID, err := uuid.Parse(claims.HospitalID)
+		if err == nil && hospitalID != claimHospitalID {
+			respondError(c, http.StatusForbidden, ErrCodeForbidden, "Gestores só podem criar escalas do próprio hospital")
+			return
+		}
+	}
+
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "ID do modelo de escala inválido")
+		return
+	}
+
+	var input models.ApplyShiftTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	template, err := h.templates.GetByID(ctx, templateID)
+	if err != nil {
+		respondDomainError(c, err, "Erro ao buscar modelo de escala")
+		return
+	}
+
+	inputs, err := template.Expand(hospitalID, input.Operators)
+	if err != nil {
+		respondDomainError(c, err, "Erro ao aplicar modelo de escala")
+		return
+	}
+
+	checked := make(map[uuid.UUID]bool, len(input.Operators))
+	for _, operatorID := range input.Operators {
+		if checked[operatorID] {
+			continue
+		}
+		checked[operatorID] = true
+
+		operator, err := h.operators.GetModelByID(ctx, operatorID)
+		if err != nil {
+			respondDomainError(c, err, "Erro ao buscar operador")
+			return
+		}
+		if reason := assigneeIneligibility(operator, hospitalID); reason != "" {
+			respondErrorDetails(c, http.StatusBadRequest, "INVALID_OPERATOR", "Operador não pode atuar neste hospital", reason)
+			return
+		}
+	}
+
+	shifts, err := h.shifts.CreateAll(ctx, inputs)
+	if err != nil {
+		// The details name the conflicting shift
+		if mapped, ok := lookupDomainError(err); ok {
+			respondErrorDetails(c, mapped.status, mapped.code, mapped.message, err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Erro ao aplicar modelo de escala")
+		return
+	}
+	publishMapShiftChange(ctx, hospitalID)
+
+	response := ApplyShiftTemplateResponse{
+		TemplateID: template.ID,
+		HospitalID: hospitalID,
+		Total:      len(shifts),
+		Shifts:     make([]models.ShiftResponse, len(shifts)),
+	}
+	for i, shift := range shifts {
+		response.Shifts[i] = shift.ToResponse()
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
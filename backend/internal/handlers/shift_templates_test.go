@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+// mockShiftTemplateRepository simulates the template, shift and user repositories used to apply templates
+type mockShiftTemplateRepository struct {
+	templates map[uuid.UUID]*models.ShiftTemplate
+	users     map[uuid.UUID]*models.User
+	shifts    []models.Shift
+}
+
+func (r *mockShiftTemplateRepository) Create(ctx context.Context, input *models.CreateShiftTemplateInput) (*models.ShiftTemplate, error) {
+	template := &models.ShiftTemplate{ID: uuid.New(), Nome: input.Nome, Descricao: input.Descricao, Slots: input.Slots}
+	r.templates[template.ID] = template
+	return template, nil
+}
+
+func (r *mockShiftTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ShiftTemplate, error) {
+	template, ok := r.templates[id]
+	if !ok {
+		return nil, models.ErrShiftTemplateNotFound
+	}
+	return template, nil
+}
+
+func (r *mockShiftTemplateRepository) List(ctx context.Context) ([]models.ShiftTemplate, error) {
+	var templates []models.ShiftTemplate
+	for _, template := range r.templates {
+		templates = append(templates, *template)
+	}
+	return templates, nil
+}
+
+func (r *mockShiftTemplateRepository) GetModelByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, auth.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// CreateAll creates every shift or none, like ShiftRepository.CreateAll
+func (r *mockShiftTemplateRepository) CreateAll(ctx context.Context, inputs []models.CreateShiftInput) ([]*models.Shift, error) {
+	pending := append([]models.Shift(nil), r.shifts...)
+	var created []*models.Shift
+	for _, input := range inputs {
+		shift := models.Shift{ID: uuid.New(), HospitalID: input.HospitalID, UserID: input.UserID, DayOfWeek: input.DayOfWeek, StartTime: input.StartTime, EndTime: input.EndTime}
+		for _, existing := range pending {
+			if existing.UserID == shift.UserID && shift.Overlaps(&existing) {
+				return nil, &models.ShiftOverlapError{Existing: existing}
+			}
+		}
+		pending = append(pending, shift)
+		created = append(created, &shift)
+	}
+	r.shifts = pending
+	return created, nil
+}
+
+// requestApplyShiftTemplate applies a template to a hospital as admin
+func requestApplyShiftTemplate(t *testing.T, repo *mockShiftTemplateRepository, hospitalID, templateID uuid.UUID, operators []uuid.UUID) *httptest.ResponseRecorder {
+	t.Helper()
+
+	handler := &ShiftTemplateHandler{templates: repo, shifts: repo, operators: repo}
+
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_claims", &middleware.UserClaims{UserID: uuid.New().String(), Role: "admin"})
+		c.Next()
+	})
+	router.POST("/api/v1/hospitals/:id/shift-templates/:templateId/apply", handler.Apply)
+
+	body, _ := json.Marshal(models.ApplyShiftTemplateInput{Operators: operators})
+	req, _ := http.NewRequest("POST", "/api/v1/hospitals/"+hospitalID.String()+"/shift-templates/"+templateID.String()+"/apply", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// newDayNightTemplateRepository returns a repository with a 12h day/night template and two operators of the hospital
+func newDayNightTemplateRepository(hospitalID uuid.UUID) (*mockShiftTemplateRepository, *models.ShiftTemplate, *models.User, *models.User) {
+	template := &models.ShiftTemplate{
+		ID:   uuid.New(),
+		Nome: "Plantao 12h diurno/noturno",
+		Slots: []models.ShiftTemplateSlot{
+			{StartTime: "07:00", EndTime: "19:00"},
+			{StartTime: "19:00", EndTime: "07:00"},
+		},
+	}
+	ana := shiftImportOperator("ana@hospital.com", hospitalID)
+	bruno := shiftImportOperator("bruno@hospital.com", hospitalID)
+	repo := &mockShiftTemplateRepository{
+		templates: map[uuid.UUID]*models.ShiftTemplate{template.ID: template},
+		users:     map[uuid.UUID]*models.User{ana.ID: ana, bruno.ID: bruno},
+	}
+	return repo, template, ana, bruno
+}
+
+// TestApplyShiftTemplateCreatesWeek tests that applying a day/night template creates both shifts on every day
+func TestApplyShiftTemplateCreatesWeek(t *testing.T) {
+	hospitalID := uuid.New()
+	repo, template, ana, bruno := newDayNightTemplateRepository(hospitalID)
+
+	w := requestApplyShiftTemplate(t, repo, hospitalID, template.ID, []uuid.UUID{ana.ID, bruno.ID})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Esperado status 201, recebido %d: %s", w.Code, w.Body.String())
+	}
+
+	var response ApplyShiftTemplateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Erro ao decodificar resposta: %v", err)
+	}
+	if response.Total != 14 || len(response.Shifts) != 14 || len(repo.shifts) != 14 {
+		t.Fatalf("Esperado 14 escalas, recebido %d (%d gravadas)", response.Total, len(repo.shifts))
+	}
+
+	expected := map[uuid.UUID]models.ShiftTemplateSlot{ana.ID: template.Slots[0], bruno.ID: template.Slots[1]}
+	days := make(map[uuid.UUID]map[models.DayOfWeek]bool)
+	for _, shift := range response.Shifts {
+		slot := expected[shift.UserID]
+		if shift.HospitalID != hospitalID || shift.StartTime != slot.StartTime || shift.EndTime != slot.EndTime {
+			t.Errorf("Escala inesperada: %+v", shift)
+		}
+		if days[shift.UserID] == nil {
+			days[shift.UserID] = make(map[models.DayOfWeek]bool)
+		}
+		days[shift.UserID][shift.DayOfWeek] = true
+	}
+	if len(days[ana.ID]) != 7 || len(days[bruno.ID]) != 7 {
+		t.Errorf("Esperado os sete dias para cada operador, recebido %d e %d", len(days[ana.ID]), len(days[bruno.ID]))
+	}
+	if !response.Shifts[7].IsNight {
+		t.Errorf("Esperado turno noturno para o segundo operador")
+	}
+}
+
+// TestApplyShiftTemplateRejectsOverlap tests that no shift is created when one overlaps an existing shift
+func TestApplyShiftTemplateRejectsOverlap(t *testing.T) {
+	hospitalID := uuid.New()
+	repo, template, ana, bruno := newDayNightTemplateRepository(hospitalID)
+	repo.shifts = []models.Shift{{ID: uuid.New(), HospitalID: uuid.New(), UserID: bruno.ID, DayOfWeek: models.Wednesday, StartTime: "20:00", EndTime: "23:00"}}
+
+	w := requestApplyShiftTemplate(t, repo, hospitalID, template.ID, []uuid.UUID{ana.ID, bruno.ID})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Esperado status 409, recebido %d: %s", w.Code, w.Body.String())
+	}
+
+	var response ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Erro ao decodificar resposta: %v", err)
+	}
+	if response.Code != "SHIFT_OVERLAP" || response.Details == "" {
+		t.Errorf("Esperado SHIFT_OVERLAP com detalhes, recebido %+v", response)
+	}
+	if len(repo.shifts) != 1 {
+		t.Errorf("Esperado nenhuma escala criada, recebido %d escalas", len(repo.shifts))
+	}
+}
+
+// TestApplyShiftTemplateValidatesRequest tests the template, operator count and operator eligibility checks
+func TestApplyShiftTemplateValidatesRequest(t *testing.T) {
+	hospitalID := uuid.New()
+	repo, template, ana, _ := newDayNightTemplateRepository(hospitalID)
+	outsider := shiftImportOperator("carla@outro.com", uuid.New())
+	repo.users[outsider.ID] = outsider
+
+	tests := []struct {
+		name       string
+		templateID uuid.UUID
+		operators  []uuid.UUID
+		status     int
+		code       string
+	}{
+		{"unknown template", uuid.New(), []uuid.UUID{ana.ID, ana.ID}, http.StatusNotFound, "SHIFT_TEMPLATE_NOT_FOUND"},
+		{"missing operator", template.ID, []uuid.UUID{ana.ID}, http.StatusBadRequest, "INVALID_SHIFT_TEMPLATE_OPERATORS"},
+		{"unknown operator", template.ID, []uuid.UUID{ana.ID, uuid.New()}, http.StatusNotFound, "USER_NOT_FOUND"},
+		{"operator of another hospital", template.ID, []uuid.UUID{ana.ID, outsider.ID}, http.StatusBadRequest, "INVALID_OPERATOR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := requestApplyShiftTemplate(t, repo, hospitalID, tt.templateID, tt.operators)
+			var response ErrorResponse
+			_ = json.Unmarshal(w.Body.Bytes(), &response)
+			if w.Code != tt.status || response.Code != tt.code {
+				t.Errorf("Esperado %d/%s, recebido %d/%s", tt.status, tt.code, w.Code, response.Code)
+			}
+		})
+	}
+	if len(repo.shifts) != 0 {
+		t.Errorf("Esperado nenhuma escala criada, recebido %d", len(repo.shifts))
+	}
+}
@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -10,20 +14,49 @@ import (
 	"github.com/sidot/backend/internal/repository"
 )
 
+// activeShiftFinder finds the shifts active at a given moment (implemented by ShiftRepository)
+type activeShiftFinder interface {
+	GetActiveShifts(ctx context.Context, hospitalID uuid.UUID, dayOfWeek int, currentTime time.Time) ([]models.Shift, error)
+}
+
+// onDutyHospitalSource provides the hospitals considered for the on-duty view (implemented by HospitalRepository)
+type onDutyHospitalSource interface {
+	GetActiveHospitals(ctx context.Context) ([]models.Hospital, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Hospital, error)
+}
+
 // ShiftHandler handles shift-related HTTP requests
 type ShiftHandler struct {
 	shiftRepo *repository.ShiftRepository
 	userRepo  *repository.UserRepository
+
+	// CSV import
+	shiftImporter shiftBatchCreator
+	operators     shiftOperatorDirectory
+
+	// On-duty lookup
+	activeShifts activeShiftFinder
+	hospitals    onDutyHospitalSource
+	now          func() time.Time
 }
 
 // NewShiftHandler creates a new shift handler
 func NewShiftHandler(shiftRepo *repository.ShiftRepository, userRepo *repository.UserRepository) *ShiftHandler {
 	return &ShiftHandler{
-		shiftRepo: shiftRepo,
-		userRepo:  userRepo,
+		shiftRepo:     shiftRepo,
+		userRepo:      userRepo,
+		shiftImporter: shiftRepo,
+		operators:     userRepo,
+		activeShifts:  shiftRepo,
+		now:           time.Now,
 	}
 }
 
+// SetHospitalRepository sets the hospital repository used by the on-duty endpoint
+func (h *ShiftHandler) SetHospitalRepository(repo *repository.HospitalRepository) {
+	h.hospitals = repo
+}
+
 // Create creates a new shift
 // @Summary Create a new shift
 // @Description Create a new shift schedule
@@ -40,18 +73,18 @@ func (h *ShiftHandler) Create(c *gin.Context) {
 	// Check permission
 	claims, exists := middleware.GetUserClaims(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Não autorizado")
 		return
 	}
 
 	if claims.Role != string(models.RoleAdmin) && claims.Role != string(models.RoleGestor) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Sem permissão para criar escalas"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "Sem permissão para criar escalas")
 		return
 	}
 
 	var input models.CreateShiftInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, err.Error())
 		return
 	}
 
@@ -59,33 +92,17 @@ func (h *ShiftHandler) Create(c *gin.Context) {
 	if claims.Role == string(models.RoleGestor) && claims.HospitalID != "" {
 		claimHospitalID, err := uuid.Parse(claims.HospitalID)
 		if err == nil && input.HospitalID != claimHospitalID {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Gestores só podem criar escalas do próprio hospital"})
+			respondError(c, http.StatusForbidden, ErrCodeForbidden, "Gestores só podem criar escalas do próprio hospital")
 			return
 		}
 	}
 
 	shift, err := h.shiftRepo.Create(c.Request.Context(), &input)
 	if err != nil {
-		if err == models.ErrShiftExists {
-			c.JSON(http.StatusConflict, gin.H{"error": "Já existe uma escala para este operador neste horário"})
-			return
-		}
-		if err == models.ErrInvalidDayOfWeek {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Dia da semana inválido"})
-			return
-		}
-		if err == models.ErrInvalidStartTime {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Horário de início inválido (use formato HH:MM)"})
-			return
-		}
-		if err == models.ErrInvalidEndTime {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Horário de fim inválido (use formato HH:MM)"})
-			return
-		}
-		// Log the actual error for debugging
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar escala: " + err.Error()})
+		respondDomainError(c, err, "Erro ao criar escala")
 		return
 	}
+	publishMapShiftChange(c.Request.Context(), shift.HospitalID)
 
 	c.JSON(http.StatusCreated, shift.ToResponse())
 }
@@ -103,17 +120,13 @@ func (h *ShiftHandler) GetByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "ID inválido")
 		return
 	}
 
 	shift, err := h.shiftRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
-		if err == models.ErrShiftNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Escala não encontrada"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar escala"})
+		respondDomainError(c, err, "Erro ao buscar escala")
 		return
 	}
 
@@ -136,19 +149,19 @@ func (h *ShiftHandler) GetByID(c *gin.Context) {
 func (h *ShiftHandler) Update(c *gin.Context) {
 	claims, exists := middleware.GetUserClaims(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Não autorizado")
 		return
 	}
 
 	if claims.Role != string(models.RoleAdmin) && claims.Role != string(models.RoleGestor) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Sem permissão para editar escalas"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "Sem permissão para editar escalas")
 		return
 	}
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "ID inválido")
 		return
 	}
 
@@ -156,17 +169,13 @@ func (h *ShiftHandler) Update(c *gin.Context) {
 	if claims.Role == string(models.RoleGestor) {
 		existingShift, err := h.shiftRepo.GetByID(c.Request.Context(), id)
 		if err != nil {
-			if err == models.ErrShiftNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Escala não encontrada"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar escala"})
+			respondDomainError(c, err, "Erro ao verificar escala")
 			return
 		}
 		if claims.HospitalID != "" {
 			claimHospitalID, _ := uuid.Parse(claims.HospitalID)
 			if existingShift.HospitalID != claimHospitalID {
-				c.JSON(http.StatusForbidden, gin.H{"error": "Gestores só podem editar escalas do próprio hospital"})
+				respondError(c, http.StatusForbidden, ErrCodeForbidden, "Gestores só podem editar escalas do próprio hospital")
 				return
 			}
 		}
@@ -174,23 +183,16 @@ func (h *ShiftHandler) Update(c *gin.Context) {
 
 	var input models.UpdateShiftInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, err.Error())
 		return
 	}
 
 	shift, err := h.shiftRepo.Update(c.Request.Context(), id, &input)
 	if err != nil {
-		if err == models.ErrShiftNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Escala não encontrada"})
-			return
-		}
-		if err == models.ErrShiftExists {
-			c.JSON(http.StatusConflict, gin.H{"error": "Conflito de horário com outra escala"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar escala"})
+		respondDomainError(c, err, "Erro ao atualizar escala")
 		return
 	}
+	publishMapShiftChange(c.Request.Context(), shift.HospitalID)
 
 	c.JSON(http.StatusOK, shift.ToResponse())
 }
@@ -207,51 +209,43 @@ func (h *ShiftHandler) Update(c *gin.Context) {
 func (h *ShiftHandler) Delete(c *gin.Context) {
 	claims, exists := middleware.GetUserClaims(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Não autorizado")
 		return
 	}
 
 	if claims.Role != string(models.RoleAdmin) && claims.Role != string(models.RoleGestor) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Sem permissão para excluir escalas"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "Sem permissão para excluir escalas")
 		return
 	}
 
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "ID inválido")
+		return
+	}
+
+	existingShift, err := h.shiftRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondDomainError(c, err, "Erro ao verificar escala")
 		return
 	}
 
 	// Check if gestor owns the shift's hospital
-	if claims.Role == string(models.RoleGestor) {
-		existingShift, err := h.shiftRepo.GetByID(c.Request.Context(), id)
-		if err != nil {
-			if err == models.ErrShiftNotFound {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Escala não encontrada"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar escala"})
+	if claims.Role == string(models.RoleGestor) && claims.HospitalID != "" {
+		claimHospitalID, _ := uuid.Parse(claims.HospitalID)
+		if existingShift.HospitalID != claimHospitalID {
+			respondError(c, http.StatusForbidden, ErrCodeForbidden, "Gestores só podem excluir escalas do próprio hospital")
 			return
 		}
-		if claims.HospitalID != "" {
-			claimHospitalID, _ := uuid.Parse(claims.HospitalID)
-			if existingShift.HospitalID != claimHospitalID {
-				c.JSON(http.StatusForbidden, gin.H{"error": "Gestores só podem excluir escalas do próprio hospital"})
-				return
-			}
-		}
 	}
 
 	err = h.shiftRepo.Delete(c.Request.Context(), id)
 	if err != nil {
-		if err == models.ErrShiftNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Escala não encontrada"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir escala"})
+		respondDomainError(c, err, "Erro ao excluir escala")
 		return
 	}
+	publishMapShiftChange(c.Request.Context(), existingShift.HospitalID)
 
 	c.Status(http.StatusNoContent)
 }
@@ -268,13 +262,13 @@ func (h *ShiftHandler) ListByHospital(c *gin.Context) {
 	hospitalIDStr := c.Param("id")
 	hospitalID, err := uuid.Parse(hospitalIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do hospital inválido"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "ID do hospital inválido")
 		return
 	}
 
 	shifts, err := h.shiftRepo.ListByHospitalID(c.Request.Context(), hospitalID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar escalas"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Erro ao buscar escalas")
 		return
 	}
 
@@ -296,19 +290,19 @@ func (h *ShiftHandler) ListByHospital(c *gin.Context) {
 func (h *ShiftHandler) GetMyShifts(c *gin.Context) {
 	claims, exists := middleware.GetUserClaims(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Não autorizado")
 		return
 	}
 
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "ID de usuário inválido"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "ID de usuário inválido")
 		return
 	}
 
 	shifts, err := h.shiftRepo.GetShiftsByUserID(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar escalas"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Erro ao buscar escalas")
 		return
 	}
 
@@ -332,13 +326,13 @@ func (h *ShiftHandler) GetTodayShifts(c *gin.Context) {
 	hospitalIDStr := c.Param("id")
 	hospitalID, err := uuid.Parse(hospitalIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do hospital inválido"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "ID do hospital inválido")
 		return
 	}
 
 	shifts, err := h.shiftRepo.GetTodayShifts(c.Request.Context(), hospitalID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar escalas de hoje"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Erro ao buscar escalas de hoje")
 		return
 	}
 
@@ -362,15 +356,130 @@ func (h *ShiftHandler) GetCoverageGaps(c *gin.Context) {
 	hospitalIDStr := c.Param("id")
 	hospitalID, err := uuid.Parse(hospitalIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do hospital inválido"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "ID do hospital inválido")
 		return
 	}
 
 	analysis, err := h.shiftRepo.GetCoverageGaps(c.Request.Context(), hospitalID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao analisar cobertura"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Erro ao analisar cobertura")
 		return
 	}
 
 	c.JSON(http.StatusOK, analysis)
 }
+
+// GetSchedule returns the weekly schedule grid for a hospital
+// @Summary Get weekly schedule grid
+// @Description Get the 7-day grid of a hospital with the operators covering each slot and the gaps marked
+// @Tags shifts
+// @Produce json
+// @Param id path string true "Hospital ID"
+// @Param slot_minutes query int false "Slot size in minutes, dividing 24h (default 60)"
+// @Success 200 {object} models.WeeklySchedule
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/hospitals/{id}/shifts/schedule [get]
+func (h *ShiftHandler) GetSchedule(c *gin.Context) {
+	hospitalID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "ID do hospital inválido")
+		return
+	}
+
+	slotMinutes := models.DefaultScheduleSlotMinutes
+	if value := c.Query("slot_minutes"); value != "" {
+		slotMinutes, err = strconv.Atoi(value)
+		if err != nil {
+			respondDomainError(c, models.ErrInvalidScheduleSlotMinutes, "")
+			return
+		}
+	}
+	if err := models.ValidateScheduleSlotMinutes(slotMinutes); err != nil {
+		respondDomainError(c, err, "")
+		return
+	}
+
+	schedule, err := h.shiftRepo.GetWeeklySchedule(c.Request.Context(), hospitalID, slotMinutes)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Erro ao montar grade de escalas")
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// GetOnDuty returns the operator currently on duty for each hospital the caller can access
+// @Summary Get operators on duty
+// @Description For each accessible hospital, get the operator whose shift is active right now (null if nobody is on duty)
+// @Tags shifts
+// @Produce json
+// @Success 200 {object} object
+// @Router /api/v1/shifts/on-duty [get]
+func (h *ShiftHandler) GetOnDuty(c *gin.Context) {
+	if h.hospitals == nil || h.activeShifts == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Repositórios não configurados")
+		return
+	}
+
+	claims, exists := middleware.GetUserClaims(c)
+	if !exists {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "Não autorizado")
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	hospitals, err := h.accessibleHospitals(ctx, claims)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Erro ao buscar hospitais")
+		return
+	}
+
+	now := h.now()
+
+	responses := make([]models.OnDutyResponse, 0, len(hospitals))
+	for _, hospital := range hospitals {
+		// Shift times are wall-clock times of the hospital's timezone
+		local := now.In(hospital.Location())
+		activeShifts, err := h.activeShifts.GetActiveShifts(ctx, hospital.ID, int(local.Weekday()), local)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "Erro ao buscar escalas ativas")
+			return
+		}
+
+		responses = append(responses, models.NewOnDutyResponse(hospital, activeShifts))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":      responses,
+		"total":     len(responses),
+		"timestamp": now,
+	})
+}
+
+// accessibleHospitals returns the hospitals visible to the caller
+// Admin sees every active hospital of the tenant; gestor and operador see only their own hospital
+func (h *ShiftHandler) accessibleHospitals(ctx context.Context, claims *middleware.UserClaims) ([]models.Hospital, error) {
+	if claims.Role == string(models.RoleAdmin) {
+		return h.hospitals.GetActiveHospitals(ctx)
+	}
+
+	if claims.HospitalID == "" {
+		return []models.Hospital{}, nil
+	}
+
+	hospitalID, err := uuid.Parse(claims.HospitalID)
+	if err != nil {
+		return []models.Hospital{}, nil
+	}
+
+	hospital, err := h.hospitals.GetByID(ctx, hospitalID)
+	if err != nil {
+		if errors.Is(err, repository.ErrHospitalNotFound) {
+			return []models.Hospital{}, nil
+		}
+		return nil, err
+	}
+
+	return []models.Hospital{*hospital}, nil
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// mockOnDutyRepository simulates the shift and hospital repositories for the on-duty endpoint
+// GetActiveShifts mirrors the SQL rules of ShiftRepository.GetActiveShifts
+type mockOnDutyRepository struct {
+	hospitals []models.Hospital
+	shifts    []models.Shift
+}
+
+func (r *mockOnDutyRepository) GetActiveHospitals(ctx context.Context) ([]models.Hospital, error) {
+	return r.hospitals, nil
+}
+
+func (r *mockOnDutyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Hospital, error) {
+	for i := range r.hospitals {
+		if r.hospitals[i].ID == id {
+			return &r.hospitals[i], nil
+		}
+	}
+	return nil, repository.ErrHospitalNotFound
+}
+
+func (r *mockOnDutyRepository) GetActiveShifts(ctx context.Context, hospitalID uuid.UUID, dayOfWeek int, currentTime time.Time) ([]models.Shift, error) {
+	previousDay := (dayOfWeek + 6) % 7
+	checkMinutes := currentTime.Hour()*60 + currentTime.Minute()
+
+	var result []models.Shift
+	for _, s := range r.shifts {
+		if s.HospitalID != hospitalID {
+			continue
+		}
+		startMinutes := s.StartTime.Hour()*60 + s.StartTime.Minute()
+		endMinutes := s.EndTime.Hour()*60 + s.EndTime.Minute()
+
+		switch {
+		case int(s.DayOfWeek) == dayOfWeek && !s.IsNightShift() && s.ContainsTime(currentTime):
+			result = append(result, s)
+		case int(s.DayOfWeek) == dayOfWeek && s.IsNightShift() && checkMinutes >= startMinutes:
+			result = append(result, s)
+		case int(s.DayOfWeek) == previousDay && s.IsNightShift() && checkMinutes < endMinutes:
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+// onDutyTestShift creates a shift with a loaded operator
+func onDutyTestShift(hospitalID uuid.UUID, day models.DayOfWeek, start, end models.ShiftTime, nome string) models.Shift {
+	userID := uuid.New()
+	return models.Shift{
+		ID:         uuid.New(),
+		HospitalID: hospitalID,
+		UserID:     userID,
+		DayOfWeek:  day,
+		StartTime:  start,
+		EndTime:    end,
+		User: &models.User{
+			ID:    userID,
+			Nome:  nome,
+			Email: "operador@test.com",
+			Role:  models.RoleOperador,
+			Ativo: true,
+		},
+	}
+}
+
+// requestOnDuty calls GET /api/v1/shifts/on-duty at a fixed moment and decodes the response
+func requestOnDuty(t *testing.T, repo *mockOnDutyRepository, claims *middleware.UserClaims, now time.Time) []models.OnDutyResponse {
+	t.Helper()
+
+	handler := &ShiftHandler{
+		activeShifts: repo,
+		hospitals:    repo,
+		now:          func() time.Time { return now },
+	}
+
+	router := setupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_claims", claims)
+		c.Next()
+	})
+	router.GET("/api/v1/shifts/on-duty", handler.GetOnDuty)
+
+	req, _ := http.NewRequest("GET", "/api/v1/shifts/on-duty", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Esperado status 200, recebido %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Data  []models.OnDutyResponse `json:"data"`
+		Total int                     `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Erro ao decodificar resposta: %v", err)
+	}
+	if response.Total != len(response.Data) {
+		t.Errorf("Total %d diferente da quantidade de itens %d", response.Total, len(response.Data))
+	}
+	return response.Data
+}
+
+// TestGetOnDutyResolvesDayAndNightShifts tests on-duty resolution for a day shift and a night shift spanning midnight
+func TestGetOnDutyResolvesDayAndNightShifts(t *testing.T) {
+	dayHospital := models.Hospital{ID: uuid.New(), Nome: "Hospital Dia", Ativo: true}
+	nightHospital := models.Hospital{ID: uuid.New(), Nome: "Hospital Noite", Ativo: true}
+	emptyHospital := models.Hospital{ID: uuid.New(), Nome: "Hospital Sem Escala", Ativo: true}
+
+	repo := &mockOnDutyRepository{
+		hospitals: []models.Hospital{dayHospital, nightHospital, emptyHospital},
+		shifts: []models.Shift{
+			onDutyTestShift(dayHospital.ID, models.Tuesday, "07:00", "19:00", "Operador Diurno"),
+			onDutyTestShift(nightHospital.ID, models.Monday, "19:00", "07:00", "Operador Noturno"),
+		},
+	}
+	admin := &middleware.UserClaims{UserID: uuid.New().String(), Role: "admin"}
+
+	// Tuesday 2026-10-13 at 10:00: day shift active, Monday night shift already ended
+	morning := time.Date(2026, 10, 13, 10, 0, 0, 0, time.Local)
+	data := requestOnDuty(t, repo, admin, morning)
+	if len(data) != 3 {
+		t.Fatalf("Esperado 3 hospitais, recebido %d", len(data))
+	}
+	if data[0].Operador == nil || data[0].Operador.Nome != "Operador Diurno" {
+		t.Errorf("Esperado Operador Diurno de plantao, recebido %v", data[0].Operador)
+	}
+	if data[1].Operador != nil {
+		t.Errorf("Esperado nenhum operador no Hospital Noite, recebido %s", data[1].Operador.Nome)
+	}
+	if data[2].Operador != nil || data[2].Shift != nil {
+		t.Error("Esperado operador nulo para hospital sem escala")
+	}
+
+	// Tuesday 2026-10-13 at 02:00: Monday night shift spans midnight and is still active
+	earlyMorning := time.Date(2026, 10, 13, 2, 0, 0, 0, time.Local)
+	data = requestOnDuty(t, repo, admin, earlyMorning)
+	if data[0].Operador != nil {
+		t.Errorf("Esperado nenhum operador no Hospital Dia, recebido %s", data[0].Operador.Nome)
+	}
+	if data[1].Operador == nil || data[1].Operador.Nome != "Operador Noturno" {
+		t.Errorf("Esperado Operador Noturno de plantao, recebido %v", data[1].Operador)
+	}
+}
+
+// TestGetOnDutyUsesHospitalTimezone tests that shifts are resolved in the hospital's local time, not server time
+func TestGetOnDutyUsesHospitalTimezone(t *testing.T) {
+	timezone := "America/Sao_Paulo"
+	hospital := models.Hospital{ID: uuid.New(), Nome: "Hospital Sao Paulo", Ativo: true, Timezone: &timezone}
+
+	repo := &mockOnDutyRepository{
+		hospitals: []models.Hospital{hospital},
+		shifts: []models.Shift{
+			onDutyTestShift(hospital.ID, models.Wednesday, "00:00", "06:00", "Operador Madrugada"),
+			onDutyTestShift(hospital.ID, models.Tuesday, "13:00", "23:00", "Operador Tarde"),
+		},
+	}
+	admin := &middleware.UserClaims{UserID: uuid.New().String(), Role: "admin"}
+
+	// Wednesday 2026-10-14 at 01:00 UTC is still Tuesday 22:00 in Sao Paulo (UTC-3)
+	data := requestOnDuty(t, repo, admin, time.Date(2026, 10, 14, 1, 0, 0, 0, time.UTC))
+	if len(data) != 1 {
+		t.Fatalf("Esperado 1 hospital, recebido %d", len(data))
+	}
+	if data[0].Operador == nil || data[0].Operador.Nome != "Operador Tarde" {
+		t.Errorf("Esperado Operador Tarde de plantao no horario local, recebido %v", data[0].Operador)
+	}
+}
+
+// TestGetOnDutyExplicitNull tests that hospitals without anyone on duty serialize operador as null
+func TestGetOnDutyExplicitNull(t *testing.T) {
+	resp := models.NewOnDutyResponse(models.Hospital{ID: uuid.New(), Nome: "Hospital"}, nil)
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("Erro ao serializar: %v", err)
+	}
+
+	var raw map[string]interface{}
+	json.Unmarshal(body, &raw)
+
+	value, present := raw["operador"]
+	if !present || value != nil {
+		t.Errorf("Esperado campo operador presente e nulo, recebido %v (presente=%v)", value, present)
+	}
+}
+
+// TestGetOnDutyScopedToOwnHospital tests that non-admin users only see their own hospital
+func TestGetOnDutyScopedToOwnHospital(t *testing.T) {
+	own := models.Hospital{ID: uuid.New(), Nome: "Hospital Proprio", Ativo: true}
+	other := models.Hospital{ID: uuid.New(), Nome: "Outro Hospital", Ativo: true}
+
+	repo := &mockOnDutyRepository{
+		hospitals: []models.Hospital{own, other},
+		shifts: []models.Shift{
+			onDutyTestShift(other.ID, models.Tuesday, "07:00", "19:00", "Operador Outro"),
+		},
+	}
+	operador := &middleware.UserClaims{UserID: uuid.New().String(), Role: "operador", HospitalID: own.ID.String()}
+
+	data := requestOnDuty(t, repo, operador, time.Date(2026, 10, 13, 10, 0, 0, 0, time.Local))
+	if len(data) != 1 {
+		t.Fatalf("Esperado 1 hospital, recebido %d", len(data))
+	}
+	if data[0].HospitalID != own.ID {
+		t.Errorf("Esperado hospital proprio, recebido %s", data[0].HospitalNome)
+	}
+	if data[0].Operador != nil {
+		t.Error("Esperado operador nulo no hospital proprio")
+	}
+}
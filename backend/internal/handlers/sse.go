@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
 	"github.com/sidot/backend/internal/services/auth"
 	"github.com/sidot/backend/internal/services/notification"
 )
@@ -30,51 +31,25 @@ func GetGlobalSSEHub() *notification.SSEHub {
 // NotificationStream handles SSE connections for real-time notifications
 // GET /api/v1/notifications/stream
 func NotificationStream(c *gin.Context) {
+	serveEventStream(c, "")
+}
+
+// MapStream handles SSE connections for live map updates (map_update events)
+// GET /api/v1/map/stream
+func MapStream(c *gin.Context) {
+	serveEventStream(c, models.SSETopicMap)
+}
+
+// serveEventStream streams the hub events of a topic to an SSE connection
+func serveEventStream(c *gin.Context, topic string) {
 	if globalSSEHub == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "SSE service not available"})
 		return
 	}
 
-	// Get user claims from context (set by auth middleware)
-	claims, ok := middleware.GetUserClaims(c)
+	claims, ok := streamClaims(c)
 	if !ok {
-		// Try to authenticate via query param token for SSE
-		token := c.Query("token")
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
-			return
-		}
-
-		// Validate token from query param
-		jwtService, jwtOk := middleware.GetJWTService(c)
-		if !jwtOk {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "authentication service not configured"})
-			return
-		}
-
-		tokenClaims, err := jwtService.ValidateAccessToken(token)
-		if err != nil {
-			switch err {
-			case auth.ErrExpiredToken:
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"error": "token has expired",
-					"code":  "TOKEN_EXPIRED",
-				})
-			default:
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"error": "invalid token",
-					"code":  "INVALID_TOKEN",
-				})
-			}
-			return
-		}
-
-		claims = &middleware.UserClaims{
-			UserID:     tokenClaims.UserID,
-			Email:      tokenClaims.Email,
-			Role:       tokenClaims.Role,
-			HospitalID: tokenClaims.HospitalID,
-		}
+		return
 	}
 
 	// Set headers for SSE
@@ -85,7 +60,8 @@ func NotificationStream(c *gin.Context) {
 	c.Writer.Header().Set("X-Accel-Buffering", "no") // Disable buffering in nginx
 
 	// Create SSE client
-	client := notification.NewSSEClient(claims.UserID, claims.Role)
+	client := notification.NewTopicSSEClient(claims.UserID, claims.Role, topic)
+	client.SetTenant(claims.TenantID, claims.IsSuperAdmin)
 	globalSSEHub.RegisterClient(client)
 
 	// Ensure cleanup on disconnect
@@ -129,6 +105,59 @@ func NotificationStream(c *gin.Context) {
 	}
 }
 
+// streamClaims resolves the user for a real-time stream connection, either from the
+// auth middleware or from the "token" query param. On failure it writes the error response.
+func streamClaims(c *gin.Context) (*middleware.UserClaims, bool) {
+	// Get user claims from context (set by auth middleware)
+	claims, ok := middleware.GetUserClaims(c)
+	if ok {
+		return claims, true
+	}
+
+	// Try to authenticate via query param token (EventSource and WebSocket cannot set headers)
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		return nil, false
+	}
+
+	// Validate token from query param
+	jwtService, jwtOk := middleware.GetJWTService(c)
+	if !jwtOk {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "authentication service not configured"})
+		return nil, false
+	}
+
+	tokenClaims, err := jwtService.ValidateAccessToken(token)
+	if err != nil {
+		switch err {
+		case auth.ErrExpiredToken:
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "token has expired",
+				"code":  "TOKEN_EXPIRED",
+			})
+		default:
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "invalid token",
+				"code":  "INVALID_TOKEN",
+			})
+		}
+		return nil, false
+	}
+
+	revoked, err := middleware.ImpersonationRevoked(c.Request.Context(), tokenClaims)
+	if err != nil {
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "unable to verify impersonation session")
+		return nil, false
+	}
+	if revoked {
+		respondError(c, http.StatusUnauthorized, "IMPERSONATION_REVOKED", "impersonation session has been revoked")
+		return nil, false
+	}
+
+	return middleware.NewUserClaims(tokenClaims), true
+}
+
 // sendSSEEvent writes an SSE event to the response writer
 func sendSSEEvent(w io.Writer, eventType string, data interface{}) error {
 	jsonData, err := json.Marshal(data)
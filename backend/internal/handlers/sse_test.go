@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+// revokedImpersonations is an ImpersonationRevocationChecker backed by a set of token IDs
+type revokedImpersonations map[string]bool
+
+func (r revokedImpersonations) IsImpersonationRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return r[tokenID], nil
+}
+
+// TestStreamClaimsRejectsRevokedImpersonation tests that a revoked impersonation token in the query
+// param cannot open a real-time stream
+func TestStreamClaimsRejectsRevokedImpersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jwtService, err := auth.NewJWTService("test-access-secret-key-32-chars!", "test-refresh-secret-key-32chars!", 15*time.Minute, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create JWT service: %v", err)
+	}
+
+	revoked := revokedImpersonations{}
+	middleware.SetImpersonationRevocationChecker(revoked)
+	t.Cleanup(func() { middleware.SetImpersonationRevocationChecker(nil) })
+
+	tenantID := uuid.New().String()
+	token, tokenID, _, err := jwtService.GenerateImpersonationToken(uuid.New().String(), "operador@sidot.gov.br", "operador", "", tenantID, false, uuid.New().String(), time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to generate impersonation token: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(middleware.SetJWTService(jwtService))
+	router.GET("/stream", func(c *gin.Context) {
+		claims, ok := streamClaims(c)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"tenant_id": claims.TenantID, "impersonated_by": claims.ImpersonatedBy})
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream?token="+token, nil))
+		return w
+	}
+
+	if w := request(); w.Code != http.StatusOK {
+		t.Fatalf("Expected an active impersonation token to open the stream, got %d", w.Code)
+	}
+
+	revoked[tokenID] = true
+	w := request()
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a revoked impersonation token, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "IMPERSONATION_REVOKED") {
+		t.Errorf("Expected IMPERSONATION_REVOKED, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sidot/backend/internal/services/storage"
+)
+
+var (
+	// errAssetTooLarge is returned when an uploaded asset exceeds its size limit
+	errAssetTooLarge = errors.New("file too large")
+
+	// errAssetTypeNotAllowed is returned when an uploaded asset is not one of the allowed image types
+	errAssetTypeNotAllowed = errors.New("file type not allowed")
+
+	// errAssetInvalidImage is returned when an uploaded asset cannot be decoded or has invalid dimensions
+	errAssetInvalidImage = errors.New("invalid image")
+)
+
+// Asset content types accepted for tenant logos and favicons
+const (
+	assetTypePNG = "image/png"
+	assetTypeSVG = "image/svg+xml"
+	assetTypeICO = "image/x-icon"
+)
+
+// assetExtensions maps accepted content types to the stored file extension
+var assetExtensions = map[string]string{
+	assetTypePNG: ".png",
+	assetTypeSVG: ".svg",
+	assetTypeICO: ".ico",
+}
+
+// tenantAssetRules holds the limits applied to one kind of tenant asset
+type tenantAssetRules struct {
+	Name         string
+	MaxBytes     int64
+	AllowedTypes []string
+	MinDimension int
+	MaxDimension int
+}
+
+var (
+	logoAssetRules = tenantAssetRules{
+		Name:         "logo",
+		MaxBytes:     2 << 20, // 2 MB
+		AllowedTypes: []string{assetTypePNG, assetTypeSVG},
+		MinDimension: 16,
+		MaxDimension: 2048,
+	}
+
+	faviconAssetRules = tenantAssetRules{
+		Name:         "favicon",
+		MaxBytes:     256 << 10, // 256 KB
+		AllowedTypes: []string{assetTypePNG, assetTypeSVG, assetTypeICO},
+		MinDimension: 16,
+		MaxDimension: 512,
+	}
+)
+
+// tenantAssetStorage stores uploaded tenant assets
+var tenantAssetStorage storage.Storage
+
+// SetTenantAssetStorage sets the storage used for tenant logo and favicon uploads
+func SetTenantAssetStorage(s storage.Storage) {
+	tenantAssetStorage = s
+}
+
+// tenantAsset is an uploaded asset that passed validation
+type tenantAsset struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+}
+
+// readTenantAsset reads an uploaded file of the declared size and validates its size, content type and dimensions
+// The content type is detected from the file contents; the client-provided header is not trusted
+func readTenantAsset(file io.Reader, size int64, rules tenantAssetRules) (*tenantAsset, error) {
+	if size > rules.MaxBytes {
+		return nil, fmt.Errorf("%w: %s must be at most %d KB", errAssetTooLarge, rules.Name, rules.MaxBytes>>10)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, rules.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > rules.MaxBytes {
+		return nil, fmt.Errorf("%w: %s must be at most %d KB", errAssetTooLarge, rules.Name, rules.MaxBytes>>10)
+	}
+
+	contentType := detectAssetType(data)
+	allowed := false
+	for _, t := range rules.AllowedTypes {
+		if contentType == t {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("%w: %s must be one of %s", errAssetTypeNotAllowed, rules.Name, describeAssetTypes(rules.AllowedTypes))
+	}
+
+	if err := validateAssetImage(data, contentType, rules); err != nil {
+		return nil, err
+	}
+
+	return &tenantAsset{
+		Data:        data,
+		ContentType: contentType,
+		Extension:   assetExtensions[contentType],
+	}, nil
+}
+
+// detectAssetType returns the content type of an asset from its contents
+func detectAssetType(data []byte) string {
+	switch detected := http.DetectContentType(data); {
+	case detected == assetTypePNG:
+		return assetTypePNG
+	case detected == "image/x-icon" || detected == "image/vnd.microsoft.icon":
+		return assetTypeICO
+	case strings.HasPrefix(detected, "text/xml") || strings.HasPrefix(detected, "text/plain"):
+		if bytes.Contains(data, []byte("<svg")) {
+			return assetTypeSVG
+		}
+		return detected
+	default:
+		return detected
+	}
+}
+
+// describeAssetTypes formats allowed content types for error messages (e.g. "PNG, SVG")
+func describeAssetTypes(types []string) string {
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, strings.ToUpper(strings.TrimPrefix(assetExtensions[t], ".")))
+	}
+	return strings.Join(names, ", ")
+}
+
+// validateAssetImage checks that an asset decodes as its content type and has acceptable dimensions
+func validateAssetImage(data []byte, contentType string, rules tenantAssetRules) error {
+	var width, height int
+
+	switch contentType {
+	case assetTypePNG:
+		config, err := png.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("%w: %s is not a valid PNG", errAssetInvalidImage, rules.Name)
+		}
+		width, height = config.Width, config.Height
+	case assetTypeICO:
+		w, h, err := icoDimensions(data)
+		if err != nil {
+			return fmt.Errorf("%w: %s is not a valid ICO", errAssetInvalidImage, rules.Name)
+		}
+		width, height = w, h
+	case assetTypeSVG:
+		// Vector images have no pixel size; check they are safe to serve instead
+		if err := validateSVG(data); err != nil {
+			return fmt.Errorf("%w: %s %v", errAssetInvalidImage, rules.Name, err)
+		}
+		return nil
+	}
+
+	if width < rules.MinDimension || height < rules.MinDimension || width > rules.MaxDimension || height > rules.MaxDimension {
+		return fmt.Errorf("%w: %s must be between %dx%d and %dx%d pixels, got %dx%d", errAssetInvalidImage,
+			rules.Name, rules.MinDimension, rules.MinDimension, rules.MaxDimension, rules.MaxDimension, width, height)
+	}
+	return nil
+}
+
+// icoDimensions returns the size of the first image in an ICO file
+// (ICONDIR header followed by ICONDIRENTRY records; a width or height byte of 0 means 256)
+func icoDimensions(data []byte) (int, int, error) {
+	if len(data) < 22 || binary.LittleEndian.Uint16(data[0:2]) != 0 || binary.LittleEndian.Uint16(data[2:4]) != 1 ||
+		binary.LittleEndian.Uint16(data[4:6]) == 0 {
+		return 0, 0, errors.New("invalid ICO header")
+	}
+
+	width, height := int(data[6]), int(data[7])
+	if width == 0 {
+		width = 256
+	}
+	if height == 0 {
+		height = 256
+	}
+	return width, height, nil
+}
+
+// SVG namespaces accepted in tenant assets
+const (
+	svgNamespace   = "http://www.w3.org/2000/svg"
+	xlinkNamespace = "http://www.w3.org/1999/xlink"
+	xmlNamespace   = "http://www.w3.org/XML/1998/namespace"
+)
+
+// svgAllowedElements lists the SVG elements accepted in tenant assets: shapes, text, gradients,
+// clipping and filters. Anything else (script, style, foreignObject, a, image, animate, set, ...) is rejected.
+var svgAllowedElements = toSet(
+	"svg", "g", "defs", "symbol", "use", "title", "desc",
+	"path", "rect", "circle", "ellipse", "line", "polyline", "polygon",
+	"text", "tspan", "textPath",
+	"linearGradient", "radialGradient", "stop", "pattern", "clipPath", "mask", "marker",
+	"filter", "feBlend", "feColorMatrix", "feComposite", "feDropShadow", "feFlood", "feGaussianBlur",
+	"feMerge", "feMergeNode", "feMorphology", "feOffset",
+)
+
+// svgAllowedAttributes lists the presentation and geometry attributes accepted in tenant assets.
+// Event handlers and attributes that load or animate content are not listed.
+var svgAllowedAttributes = toSet(
+	"id", "class", "style", "version", "width", "height", "viewBox", "preserveAspectRatio",
+	"x", "y", "x1", "y1", "x2", "y2", "cx", "cy", "r", "rx", "ry", "fx", "fy", "fr", "dx", "dy",
+	"d", "points", "pathLength", "transform", "rotate", "textLength", "lengthAdjust", "offset",
+	"fill", "fill-opacity", "fill-rule", "stroke", "stroke-width", "stroke-linecap", "stroke-linejoin",
+	"stroke-miterlimit", "stroke-dasharray", "stroke-dashoffset", "stroke-opacity", "opacity", "color",
+	"display", "visibility", "overflow", "clip-path", "clip-rule", "mask", "filter", "paint-order",
+	"vector-effect", "shape-rendering", "text-rendering", "image-rendering", "mix-blend-mode", "isolation",
+	"font-family", "font-size", "font-style", "font-weight", "font-variant", "letter-spacing", "word-spacing",
+	"text-anchor", "text-decoration", "dominant-baseline", "alignment-baseline", "baseline-shift",
+	"writing-mode", "direction", "stop-color", "stop-opacity", "flood-color", "flood-opacity",
+	"gradientUnits", "gradientTransform", "spreadMethod", "patternUnits", "patternContentUnits",
+	"patternTransform", "clipPathUnits", "maskUnits", "maskContentUnits", "filterUnits", "primitiveUnits",
+	"color-interpolation-filters", "markerWidth", "markerHeight", "markerUnits", "refX", "refY", "orient",
+	"marker-start", "marker-mid", "marker-end", "href",
+	"in", "in2", "result", "stdDeviation", "mode", "operator", "k1", "k2", "k3", "k4", "values", "type", "radius",
+)
+
+// toSet builds a lookup set from a list of names
+func toSet(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// validateSVG checks that an SVG document has an <svg> root and only allowlisted elements and
+// attributes, since assets are served from the application's own origin. References (href, url())
+// may only point inside the document.
+func validateSVG(data []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	sawRoot := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.New("is not a valid SVG document")
+		}
+
+		switch t := token.(type) {
+		case xml.Directive:
+			// DOCTYPE declarations can define entities
+			return errors.New("must not contain a DOCTYPE or other declarations")
+		case xml.ProcInst:
+			// Only the XML declaration; xml-stylesheet would load external styles
+			if t.Target != "xml" {
+				return fmt.Errorf("must not contain <?%s?> instructions", t.Target)
+			}
+		case xml.StartElement:
+			if !sawRoot {
+				if t.Name.Local != "svg" {
+					return errors.New("must have an <svg> root element")
+				}
+				sawRoot = true
+			}
+			if err := validateSVGElement(t); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !sawRoot {
+		return errors.New("must have an <svg> root element")
+	}
+	return nil
+}
+
+// validateSVGElement checks one element and its attributes against the allowlists
+func validateSVGElement(element xml.StartElement) error {
+	if (element.Name.Space != svgNamespace && element.Name.Space != "") || !svgAllowedElements[element.Name.Local] {
+		return fmt.Errorf("must not contain <%s> elements", element.Name.Local)
+	}
+
+	for _, attr := range element.Attr {
+		switch {
+		case attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns"):
+			// Namespace declarations; the elements that use them are checked themselves
+			continue
+		case attr.Name.Space == xmlNamespace && (attr.Name.Local == "space" || attr.Name.Local == "lang"):
+			continue
+		case attr.Name.Space == xlinkNamespace && attr.Name.Local == "href",
+			attr.Name.Space == "" && svgAllowedAttributes[attr.Name.Local]:
+		default:
+			return fmt.Errorf("must not contain the %s attribute", attr.Name.Local)
+		}
+
+		if err := validateSVGReferences(attr.Name.Local, attr.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSVGReferences checks that an attribute value only references fragments of the document:
+// href must be "#id", and url(...) in paint, clip, mask, filter and style values must be url(#id)
+func validateSVGReferences(name, value string) error {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if name == "href" && !strings.HasPrefix(value, "#") {
+		return errors.New("must only link to elements of the same document")
+	}
+	if strings.Contains(value, "javascript:") || strings.Contains(value, "@import") || strings.Contains(value, "expression(") {
+		return fmt.Errorf("must not contain active content in the %s attribute", name)
+	}
+
+	for rest := value; ; {
+		i := strings.Index(rest, "url(")
+		if i < 0 {
+			break
+		}
+		rest = strings.TrimLeft(rest[i+len("url("):], ` '"`)
+		if !strings.HasPrefix(rest, "#") {
+			return fmt.Errorf("must only reference elements of the same document in the %s attribute", name)
+		}
+	}
+	return nil
+}
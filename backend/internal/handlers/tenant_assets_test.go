@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// testPNG encodes a blank PNG of the given size
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// testICO builds a minimal ICO header with one entry of the given size (0 means 256)
+func testICO(width, height byte) []byte {
+	data := make([]byte, 22)
+	data[2] = 1 // type: icon
+	data[4] = 1 // one image
+	data[6], data[7] = width, height
+	return data
+}
+
+// TestReadTenantAssetAcceptsValidImages tests that PNG, ICO and SVG assets within limits are accepted
+func TestReadTenantAssetAcceptsValidImages(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		rules       tenantAssetRules
+		contentType string
+		extension   string
+	}{
+		{"PNG logo", testPNG(t, 200, 80), logoAssetRules, assetTypePNG, ".png"},
+		{"SVG logo", []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><rect width="10" height="10" fill="#2563eb"/></svg>`), logoAssetRules, assetTypeSVG, ".svg"},
+		{"SVG logo with gradient and local reference", []byte(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 10 10"><defs><linearGradient id="g"><stop offset="0" stop-color="#fff"/></linearGradient><circle id="c" r="4"/></defs><use xlink:href="#c" fill="url(#g)" style="stroke: url('#g')"/></svg>`), logoAssetRules, assetTypeSVG, ".svg"},
+		{"ICO favicon", testICO(32, 32), faviconAssetRules, assetTypeICO, ".ico"},
+		{"PNG favicon", testPNG(t, 64, 64), faviconAssetRules, assetTypePNG, ".png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asset, err := readTenantAsset(bytes.NewReader(tt.data), int64(len(tt.data)), tt.rules)
+			if err != nil {
+				t.Fatalf("readTenantAsset() error = %v", err)
+			}
+			if asset.ContentType != tt.contentType || asset.Extension != tt.extension {
+				t.Errorf("Expected %s (%s), got %s (%s)", tt.contentType, tt.extension, asset.ContentType, asset.Extension)
+			}
+		})
+	}
+}
+
+// TestReadTenantAssetRejectsOversizedFile tests that files above the size limit are rejected
+func TestReadTenantAssetRejectsOversizedFile(t *testing.T) {
+	data := append(testPNG(t, 32, 32), make([]byte, faviconAssetRules.MaxBytes)...)
+
+	// Declared size over the limit
+	if _, err := readTenantAsset(bytes.NewReader(data), int64(len(data)), faviconAssetRules); !errors.Is(err, errAssetTooLarge) {
+		t.Errorf("Expected errAssetTooLarge for declared size, got %v", err)
+	}
+
+	// Declared size understated by the client; the content itself is still bounded
+	if _, err := readTenantAsset(bytes.NewReader(data), 1024, faviconAssetRules); !errors.Is(err, errAssetTooLarge) {
+		t.Errorf("Expected errAssetTooLarge for actual size, got %v", err)
+	}
+}
+
+// TestReadTenantAssetRejectsDisallowedContentType tests that non-image and unsupported image types are rejected
+// regardless of the file name or Content-Type header the client sends
+func TestReadTenantAssetRejectsDisallowedContentType(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []byte
+		rules tenantAssetRules
+	}{
+		{"JPEG logo", []byte("\xFF\xD8\xFF\xE0\x00\x10JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00"), logoAssetRules},
+		{"GIF favicon", []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;"), faviconAssetRules},
+		{"HTML logo", []byte("<html><body><script>alert(1)</script></body></html>"), logoAssetRules},
+		{"ICO logo", testICO(32, 32), logoAssetRules},
+		{"Plain text favicon", []byte("not an image"), faviconAssetRules},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readTenantAsset(bytes.NewReader(tt.data), int64(len(tt.data)), tt.rules)
+			if !errors.Is(err, errAssetTypeNotAllowed) {
+				t.Errorf("Expected errAssetTypeNotAllowed, got %v", err)
+			}
+		})
+	}
+}
+
+// TestReadTenantAssetRejectsInvalidImages tests dimension checks and SVG content outside the allowlist
+func TestReadTenantAssetRejectsInvalidImages(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []byte
+		rules tenantAssetRules
+	}{
+		{"PNG logo too wide", testPNG(t, logoAssetRules.MaxDimension+1, 100), logoAssetRules},
+		{"PNG favicon too small", testPNG(t, 8, 8), faviconAssetRules},
+		{"ICO favicon too small", testICO(8, 8), faviconAssetRules},
+		{"Truncated PNG", testPNG(t, 32, 32)[:20], logoAssetRules},
+		{"SVG with script", []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`), logoAssetRules},
+		{"SVG with event handler", []byte(`<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"></svg>`), logoAssetRules},
+		{"SVG with javascript link", []byte(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><a xlink:href="javascript:alert(1)"><text>x</text></a></svg>`), faviconAssetRules},
+		{"SVG with animation", []byte(`<svg xmlns="http://www.w3.org/2000/svg"><rect width="10" height="10"><animate attributeName="fill" to="red"/></rect></svg>`), logoAssetRules},
+		{"SVG with set on href", []byte(`<svg xmlns="http://www.w3.org/2000/svg"><use href="#a"><set attributeName="href" to="javascript:alert(1)"/></use></svg>`), logoAssetRules},
+		{"SVG with style element", []byte(`<svg xmlns="http://www.w3.org/2000/svg"><style>@import url(https://evil.example/x.css);</style></svg>`), logoAssetRules},
+		{"SVG with stylesheet instruction", []byte(`<?xml-stylesheet href="https://evil.example/x.css"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`), logoAssetRules},
+		{"SVG with DOCTYPE", []byte(`<!DOCTYPE svg [<!ENTITY x "y">]><svg xmlns="http://www.w3.org/2000/svg"></svg>`), logoAssetRules},
+		{"SVG with external paint", []byte(`<svg xmlns="http://www.w3.org/2000/svg"><rect width="10" height="10" fill="url(https://evil.example/p.svg#g)"/></svg>`), logoAssetRules},
+		{"SVG with external href", []byte(`<svg xmlns="http://www.w3.org/2000/svg"><use href="https://evil.example/s.svg#a"/></svg>`), logoAssetRules},
+		{"SVG with foreign namespace element", []byte(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:h="http://www.w3.org/1999/xhtml"><h:iframe/></svg>`), logoAssetRules},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readTenantAsset(bytes.NewReader(tt.data), int64(len(tt.data)), tt.rules)
+			if !errors.Is(err, errAssetInvalidImage) && !errors.Is(err, errAssetTypeNotAllowed) {
+				t.Errorf("Expected the image to be rejected, got %v", err)
+			}
+		})
+	}
+}
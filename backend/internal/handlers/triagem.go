@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -13,13 +16,71 @@ import (
 	"github.com/sidot/backend/internal/services/audit"
 )
 
-var triagemRuleRepo *repository.TriagemRuleRepository
+// triagemRuleStore persists triagem rules (implemented by repository.TriagemRuleRepository)
+type triagemRuleStore interface {
+	List(ctx context.Context) ([]models.TriagemRule, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.TriagemRule, error)
+	Create(ctx context.Context, input *models.CreateTriagemRuleInput) (*models.TriagemRule, error)
+	Update(ctx context.Context, id uuid.UUID, input *models.UpdateTriagemRuleInput) (*models.TriagemRule, error)
+	SoftDelete(ctx context.Context, id uuid.UUID) error
+}
+
+// ruleAuditLogger records audit events for rule changes (implemented by audit.AuditService)
+type ruleAuditLogger interface {
+	LogEventWithUser(ctx context.Context, usuarioID *uuid.UUID, actorName string, acao string, entidadeTipo string, entidadeID string, hospitalID *uuid.UUID, severity models.Severity, detalhes map[string]interface{}, ipAddress *string, userAgent *string) error
+}
+
+var triagemRuleRepo triagemRuleStore
+
+// triagemRuleAuditLogger overrides the global audit service for rule changes (used in tests)
+var triagemRuleAuditLogger ruleAuditLogger
 
 // SetTriagemRuleRepository sets the triagem rule repository for handlers
 func SetTriagemRuleRepository(repo *repository.TriagemRuleRepository) {
+	if repo == nil {
+		triagemRuleRepo = nil
+		return
+	}
 	triagemRuleRepo = repo
 }
 
+// logTriagemRuleAudit records a rule change with the actor, request origin and hospital of the caller
+func logTriagemRuleAudit(c *gin.Context, acao string, ruleID uuid.UUID, severity models.Severity, detalhes map[string]interface{}) {
+	logger := triagemRuleAuditLogger
+	if logger == nil {
+		if auditService == nil {
+			return
+		}
+		logger = auditService
+	}
+
+	userID, actorName := audit.GetUserInfoFromContext(c)
+	ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+	// Get hospital_id from context if available
+	var hospitalID *uuid.UUID
+	claims, _ := middleware.GetUserClaims(c)
+	if claims != nil && claims.HospitalID != "" {
+		if hid, err := uuid.Parse(claims.HospitalID); err == nil {
+			hospitalID = &hid
+		}
+	}
+
+	logger.LogEventWithUser(
+		c.Request.Context(),
+		userID,
+		actorName,
+		acao,
+		"Regra",
+		ruleID.String(),
+		hospitalID,
+		severity,
+		detalhes,
+		ipAddress,
+		userAgent,
+	)
+}
+
 // ListTriagemRules returns all triagem rules
 // GET /api/v1/triagem-rules
 func ListTriagemRules(c *gin.Context) {
@@ -75,40 +136,24 @@ func CreateTriagemRule(c *gin.Context) {
 
 	rule, err := triagemRuleRepo.Create(c.Request.Context(), &input)
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidRuleConfig) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid rule configuration",
+				"details": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create triagem rule"})
 		return
 	}
 
 	// Log audit event for rule creation
-	if auditService != nil {
-		userID, actorName := audit.GetUserInfoFromContext(c)
-		ipAddress, userAgent := audit.ExtractRequestInfo(c)
-
-		// Get hospital_id from context if available
-		var hospitalID *uuid.UUID
-		claims, _ := middleware.GetUserClaims(c)
-		if claims != nil && claims.HospitalID != "" {
-			hid, _ := uuid.Parse(claims.HospitalID)
-			hospitalID = &hid
-		}
-
-		auditService.LogEventWithUser(
-			c.Request.Context(),
-			userID,
-			actorName,
-			models.ActionRegraCreate,
-			"Regra",
-			rule.ID.String(),
-			hospitalID,
-			models.SeverityInfo,
-			map[string]interface{}{
-				"nome":       rule.Nome,
-				"prioridade": rule.Prioridade,
-			},
-			ipAddress,
-			userAgent,
-		)
-	}
+	logTriagemRuleAudit(c, models.ActionRegraCreate, rule.ID, models.SeverityInfo, map[string]interface{}{
+		"nome":        rule.Nome,
+		"prioridade":  rule.Prioridade,
+		"ativo":       rule.Ativo,
+		"regras_nova": rule.Regras,
+	})
 
 	c.JSON(http.StatusCreated, rule.ToResponse())
 }
@@ -164,53 +209,25 @@ func UpdateTriagemRule(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "triagem rule not found"})
 			return
 		}
+		if errors.Is(err, models.ErrInvalidRuleConfig) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid rule configuration",
+				"details": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update triagem rule"})
 		return
 	}
 
 	// Log audit event for rule update (CRITICAL severity as per spec)
-	if auditService != nil {
-		userID, actorName := audit.GetUserInfoFromContext(c)
-		ipAddress, userAgent := audit.ExtractRequestInfo(c)
-
-		// Get hospital_id from context if available
-		var hospitalID *uuid.UUID
-		claims, _ := middleware.GetUserClaims(c)
-		if claims != nil && claims.HospitalID != "" {
-			hid, _ := uuid.Parse(claims.HospitalID)
-			hospitalID = &hid
-		}
-
-		// Build details with changes
-		detalhes := map[string]interface{}{
-			"nome_anterior": oldRule.Nome,
-			"nome_novo":     rule.Nome,
-		}
-
-		// Track specific changes that are critical
-		if input.Regras != nil {
-			detalhes["regras_alteradas"] = true
-		}
-		if input.Ativo != nil {
-			detalhes["ativo_anterior"] = oldRule.Ativo
-			detalhes["ativo_novo"] = rule.Ativo
-		}
-
-		auditService.LogEventWithUser(
-			c.Request.Context(),
-			userID,
-			actorName,
-			models.ActionRegraUpdate,
-			"Regra",
-			rule.ID.String(),
-			hospitalID,
-			models.SeverityCritical,
-			detalhes,
-			ipAddress,
-			userAgent,
-		)
+	detalhes := map[string]interface{}{
+		"nome":       rule.Nome,
+		"alteracoes": audit.Diff(oldRule, rule),
 	}
 
+	logTriagemRuleAudit(c, models.ActionRegraUpdate, rule.ID, models.SeverityCritical, detalhes)
+
 	c.JSON(http.StatusOK, rule.ToResponse())
 }
 
@@ -252,34 +269,101 @@ func DeleteTriagemRule(c *gin.Context) {
 	}
 
 	// Log audit event for rule deletion
-	if auditService != nil {
-		userID, actorName := audit.GetUserInfoFromContext(c)
-		ipAddress, userAgent := audit.ExtractRequestInfo(c)
-
-		var hospitalID *uuid.UUID
-		claims, _ := middleware.GetUserClaims(c)
-		if claims != nil && claims.HospitalID != "" {
-			hid, _ := uuid.Parse(claims.HospitalID)
-			hospitalID = &hid
+	logTriagemRuleAudit(c, models.ActionRegraDelete, rule.ID, models.SeverityWarn, map[string]interface{}{
+		"nome":            rule.Nome,
+		"action":          "soft_delete",
+		"regras_anterior": rule.Regras,
+	})
+
+	c.Status(http.StatusNoContent)
+}
+
+const (
+	// MaxTriagemSimulationDays limits the date range of a triagem dry-run
+	MaxTriagemSimulationDays = 90
+
+	// MaxTriagemSimulationObitos limits how many obitos a dry-run evaluates
+	MaxTriagemSimulationObitos = 1000
+)
+
+var obitoRepo *repository.ObitoRepository
+
+// SetObitoRepository sets the obito repository for handlers
+func SetObitoRepository(repo *repository.ObitoRepository) {
+	obitoRepo = repo
+}
+
+// SimulateTriagemRules runs a candidate rule set against recent obitos without persisting anything
+// POST /api/v1/triagem-rules/simulate
+func SimulateTriagemRules(c *gin.Context) {
+	if obitoRepo == nil || globalTriagemMotor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "triagem simulation not available"})
+		return
+	}
+
+	var input models.SimulateTriagemRulesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	dateFrom, err := time.ParseInLocation("2006-01-02", input.DateFrom, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date_from format, expected YYYY-MM-DD"})
+		return
+	}
+	dateTo, err := time.ParseInLocation("2006-01-02", input.DateTo, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date_to format, expected YYYY-MM-DD"})
+		return
+	}
+	if dateTo.Before(dateFrom) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date_to must not be before date_from"})
+		return
+	}
+	// date_to is inclusive
+	dateTo = dateTo.AddDate(0, 0, 1)
+	if dateTo.Sub(dateFrom) > MaxTriagemSimulationDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("date range must not exceed %d days", MaxTriagemSimulationDays)})
+		return
+	}
+
+	rules := input.ToRules()
+	for _, rule := range rules {
+		if _, err := models.ValidateRuleConfig(rule.Regras); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid rule configuration",
+				"details": fmt.Sprintf("%s: %v", rule.Nome, err),
+			})
+			return
 		}
+	}
 
-		auditService.LogEventWithUser(
-			c.Request.Context(),
-			userID,
-			actorName,
-			models.ActionRegraUpdate, // Using update action since it's a soft delete
-			"Regra",
-			rule.ID.String(),
-			hospitalID,
-			models.SeverityCritical,
-			map[string]interface{}{
-				"nome":   rule.Nome,
-				"action": "soft_delete",
-			},
-			ipAddress,
-			userAgent,
-		)
+	obitos, err := obitoRepo.ListByDeathDateRange(c.Request.Context(), dateFrom, dateTo, MaxTriagemSimulationObitos)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load obitos"})
+		return
 	}
 
-	c.Status(http.StatusNoContent)
+	result := globalTriagemMotor.Simulate(obitos, rules)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":      result.Results,
+		"summary":   result.Summary,
+		"date_from": input.DateFrom,
+		"date_to":   input.DateTo,
+		"truncated": len(obitos) >= MaxTriagemSimulationObitos,
+	})
 }
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+	"github.com/sidot/backend/internal/services/audit"
+)
+
+// mockTriagemRuleStore keeps triagem rules in memory
+type mockTriagemRuleStore struct {
+	rules map[uuid.UUID]*models.TriagemRule
+}
+
+func (s *mockTriagemRuleStore) List(ctx context.Context) ([]models.TriagemRule, error) {
+	rules := make([]models.TriagemRule, 0, len(s.rules))
+	for _, rule := range s.rules {
+		rules = append(rules, *rule)
+	}
+	return rules, nil
+}
+
+func (s *mockTriagemRuleStore) GetByID(ctx context.Context, id uuid.UUID) (*models.TriagemRule, error) {
+	rule, ok := s.rules[id]
+	if !ok {
+		return nil, repository.ErrTriagemRuleNotFound
+	}
+	copied := *rule
+	return &copied, nil
+}
+
+func (s *mockTriagemRuleStore) Create(ctx context.Context, input *models.CreateTriagemRuleInput) (*models.TriagemRule, error) {
+	if _, err := models.ValidateRuleConfig(input.Regras); err != nil {
+		return nil, err
+	}
+	rule := &models.TriagemRule{ID: uuid.New(), Nome: input.Nome, Regras: input.Regras, Ativo: true, Prioridade: 50}
+	s.rules[rule.ID] = rule
+	copied := *rule
+	return &copied, nil
+}
+
+func (s *mockTriagemRuleStore) Update(ctx context.Context, id uuid.UUID, input *models.UpdateTriagemRuleInput) (*models.TriagemRule, error) {
+	rule, ok := s.rules[id]
+	if !ok {
+		return nil, repository.ErrTriagemRuleNotFound
+	}
+	if input.Regras != nil {
+		rule.Regras = input.Regras
+	}
+	if input.Ativo != nil {
+		rule.Ativo = *input.Ativo
+	}
+	copied := *rule
+	return &copied, nil
+}
+
+func (s *mockTriagemRuleStore) SoftDelete(ctx context.Context, id uuid.UUID) error {
+	if _, ok := s.rules[id]; !ok {
+		return repository.ErrTriagemRuleNotFound
+	}
+	delete(s.rules, id)
+	return nil
+}
+
+// recordedAuditEvent is an audit event captured by recordingAuditLogger
+type recordedAuditEvent struct {
+	usuarioID *uuid.UUID
+	acao      string
+	entityID  string
+	severity  models.Severity
+	detalhes  map[string]interface{}
+	ipAddress *string
+}
+
+// recordingAuditLogger records audit events in memory
+type recordingAuditLogger struct {
+	events []recordedAuditEvent
+}
+
+func (l *recordingAuditLogger) LogEventWithUser(ctx context.Context, usuarioID *uuid.UUID, actorName string, acao string, entidadeTipo string, entidadeID string, hospitalID *uuid.UUID, severity models.Severity, detalhes map[string]interface{}, ipAddress *string, userAgent *string) error {
+	l.events = append(l.events, recordedAuditEvent{
+		usuarioID: usuarioID,
+		acao:      acao,
+		entityID:  entidadeID,
+		severity:  severity,
+		detalhes:  detalhes,
+		ipAddress: ipAddress,
+	})
+	return nil
+}
+
+// setupTriagemRuleAuditTest installs an in-memory rule store and audit logger
+func setupTriagemRuleAuditTest(t *testing.T) (*mockTriagemRuleStore, *recordingAuditLogger) {
+	store := &mockTriagemRuleStore{rules: map[uuid.UUID]*models.TriagemRule{}}
+	logger := &recordingAuditLogger{}
+
+	previousRepo, previousLogger := triagemRuleRepo, triagemRuleAuditLogger
+	triagemRuleRepo, triagemRuleAuditLogger = store, logger
+	t.Cleanup(func() {
+		triagemRuleRepo, triagemRuleAuditLogger = previousRepo, previousLogger
+	})
+
+	return store, logger
+}
+
+// regrasJSON returns the compact JSON of a regras value stored in audit details
+func regrasJSON(t *testing.T, value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Failed to marshal regras: %v", err)
+	}
+	return string(data)
+}
+
+// TestCreateTriagemRuleRejectsInvalidConfig tests that a malformed rule config is rejected before it is stored
+// The repository has no database, so reaching the insert would fail with a 500
+func TestCreateTriagemRuleRejectsInvalidConfig(t *testing.T) {
+	previousRepo := triagemRuleRepo
+	SetTriagemRuleRepository(repository.NewTriagemRuleRepository(nil, nil))
+	defer func() { triagemRuleRepo = previousRepo }()
+
+	router := setupTestRouter()
+	router.Use(mockAuthMiddleware(uuid.New().String(), "admin"))
+	router.POST("/api/v1/triagem-rules", CreateTriagemRule)
+
+	body := `{"nome": "Causas Excludentes", "regras": {"tipo": "causas_excludentes", "valor": 42, "acao": "rejeitar"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/triagem-rules", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if response["error"] != "invalid rule configuration" {
+		t.Errorf("Expected invalid rule configuration error, got %q", response["error"])
+	}
+	if !strings.Contains(response["details"], "causas_excludentes must be a non-empty array of strings") {
+		t.Errorf("Expected details to describe the expected schema, got %q", response["details"])
+	}
+}
+
+// TestTriagemRuleChangesAreAudited tests that create, update and delete each record an audit entry with the regras diff
+func TestTriagemRuleChangesAreAudited(t *testing.T) {
+	_, logger := setupTriagemRuleAuditTest(t)
+	userID := uuid.New()
+
+	router := setupTestRouter()
+	router.Use(mockAuthMiddleware(userID.String(), "admin"))
+	router.POST("/api/v1/triagem-rules", CreateTriagemRule)
+	router.PATCH("/api/v1/triagem-rules/:id", UpdateTriagemRule)
+	router.DELETE("/api/v1/triagem-rules/:id", DeleteTriagemRule)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "10.0.0.7:5000"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/api/v1/triagem-rules", `{"nome": "Idade Maxima", "regras": {"tipo": "idade_maxima", "valor": 80, "acao": "rejeitar"}}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created models.TriagemRuleResponse
+	json.Unmarshal(w.Body.Bytes(), &created)
+
+	w = send(http.MethodPatch, "/api/v1/triagem-rules/"+created.ID.String(), `{"regras": {"tipo": "idade_maxima", "valor": 70, "acao": "rejeitar"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = send(http.MethodDelete, "/api/v1/triagem-rules/"+created.ID.String(), "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if len(logger.events) != 3 {
+		t.Fatalf("Expected 3 audit events, got %d", len(logger.events))
+	}
+
+	expected := []struct {
+		acao     string
+		severity models.Severity
+	}{
+		{models.ActionRegraCreate, models.SeverityInfo},
+		{models.ActionRegraUpdate, models.SeverityCritical},
+		{models.ActionRegraDelete, models.SeverityWarn},
+	}
+	for i, event := range logger.events {
+		if event.acao != expected[i].acao || event.severity != expected[i].severity {
+			t.Errorf("Event %d: expected %s/%s, got %s/%s", i, expected[i].acao, expected[i].severity, event.acao, event.severity)
+		}
+		if event.entityID != created.ID.String() {
+			t.Errorf("Event %d: expected entity %s, got %s", i, created.ID, event.entityID)
+		}
+		if event.usuarioID == nil || *event.usuarioID != userID {
+			t.Errorf("Event %d: expected actor %s, got %v", i, userID, event.usuarioID)
+		}
+		if event.ipAddress == nil || *event.ipAddress != "10.0.0.7" {
+			t.Errorf("Event %d: expected IP 10.0.0.7, got %v", i, event.ipAddress)
+		}
+	}
+
+	changes, ok := logger.events[1].detalhes["alteracoes"].(audit.Changes)
+	if !ok {
+		t.Fatalf("Expected the update to record alteracoes, got %v", logger.events[1].detalhes)
+	}
+	if got := regrasJSON(t, changes["regras"].Anterior); got != `{"tipo":"idade_maxima","valor":80,"acao":"rejeitar"}` {
+		t.Errorf("Unexpected previous regras: %s", got)
+	}
+	if got := regrasJSON(t, changes["regras"].Novo); got != `{"tipo":"idade_maxima","valor":70,"acao":"rejeitar"}` {
+		t.Errorf("Unexpected new regras: %s", got)
+	}
+	if _, changed := changes["nome"]; changed || len(changes) != 1 {
+		t.Errorf("Expected only regras to change, got %v", changes)
+	}
+
+	if got := regrasJSON(t, logger.events[2].detalhes["regras_anterior"]); got != `{"tipo":"idade_maxima","valor":70,"acao":"rejeitar"}` {
+		t.Errorf("Expected delete to capture the last regras, got %s", got)
+	}
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"errors"
+	"log"
 	"net/http"
 	"strconv"
 
@@ -17,16 +19,27 @@ import (
 
 var userRepo *repository.UserRepository
 
+// welcomeRecipientReader loads the users of the current tenant (implemented by repository.UserRepository)
+type welcomeRecipientReader interface {
+	GetTenantModelByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+}
+
+var welcomeRecipients welcomeRecipientReader
+
 // SetUserRepository sets the user repository for handlers
 func SetUserRepository(repo *repository.UserRepository) {
 	userRepo = repo
+	if repo != nil {
+		occurrenceAssignees = repo
+		welcomeRecipients = repo
+	}
 }
 
 // ListUsers returns users with pagination, search, and filtering (admin only)
 // GET /api/v1/users
 func ListUsers(c *gin.Context) {
 	if userRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "user repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "user repository not configured")
 		return
 	}
 
@@ -53,7 +66,7 @@ func ListUsers(c *gin.Context) {
 
 	result, err := userRepo.ListWithPagination(c.Request.Context(), params)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list users"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to list users")
 		return
 	}
 
@@ -71,6 +84,7 @@ func ListUsers(c *gin.Context) {
 			"total":       result.Total,
 			"total_pages": result.TotalPages,
 		},
+		"links": models.NewPaginationLinks(c.Request.URL, "per_page", result.Page, result.PerPage, result.TotalPages),
 	})
 }
 
@@ -78,37 +92,33 @@ func ListUsers(c *gin.Context) {
 // GET /api/v1/users/:id
 func GetUser(c *gin.Context) {
 	if userRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "user repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "user repository not configured")
 		return
 	}
 
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID format"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid user ID format")
 		return
 	}
 
 	// Get current user claims
 	claims, ok := middleware.GetUserClaims(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "authentication required")
 		return
 	}
 
 	// Check authorization: admin can view any user, others can only view themselves
 	if claims.Role != "admin" && claims.UserID != id.String() {
-		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "insufficient permissions")
 		return
 	}
 
 	user, err := userRepo.GetModelByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, auth.ErrUserNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+		respondDomainError(c, err, "failed to get user")
 		return
 	}
 
@@ -119,62 +129,58 @@ func GetUser(c *gin.Context) {
 // POST /api/v1/users
 func CreateUser(c *gin.Context) {
 	if userRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "user repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "user repository not configured")
 		return
 	}
 
 	var input models.CreateUserInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "invalid request body", err.Error())
 		return
 	}
 
 	// Validate input
 	validate := validator.New()
 	if err := validate.Struct(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation failed",
-			"details": err.Error(),
-		})
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeValidationFailed, "validation failed", err.Error())
 		return
 	}
 
 	// Validate password strength
 	if err := auth.ValidatePasswordStrength(input.Password); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
 		return
 	}
 
 	// Validate mobile phone if provided
 	if input.MobilePhone != nil && !models.ValidateMobilePhone(*input.MobilePhone) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid mobile phone format, must be in E.164 format (e.g., +5511999999999)",
-		})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid mobile phone format, must be in E.164 format (e.g., +5511999999999)")
 		return
 	}
 
 	// Hash password
 	passwordHash, err := auth.HashPassword(input.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process password"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to process password")
 		return
 	}
 
+	// New users confirm their email before signing in when email verification is enabled
+	verification := globalAuthHandler != nil && auth.ActiveEmailVerificationPolicy().Enabled
+	input.RequireEmailVerification = verification
+
 	user, err := userRepo.CreateUser(c.Request.Context(), &input, passwordHash)
 	if err != nil {
-		if errors.Is(err, repository.ErrUserExists) {
-			c.JSON(http.StatusConflict, gin.H{"error": "user with this email already exists"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create user"})
+		respondDomainError(c, err, "failed to create user")
 		return
 	}
 
+	if verification {
+		if err := globalAuthHandler.authService.SendEmailVerification(c.Request.Context(), user); err != nil {
+			log.Printf("[Users] Failed to send the verification email of user %s: %v", user.ID, err)
+		}
+	}
+
 	// Log audit event for user creation
 	if auditService != nil {
 		userID, actorName := audit.GetUserInfoFromContext(c)
@@ -205,65 +211,53 @@ func CreateUser(c *gin.Context) {
 // PATCH /api/v1/users/:id
 func UpdateUser(c *gin.Context) {
 	if userRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "user repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "user repository not configured")
 		return
 	}
 
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID format"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid user ID format")
 		return
 	}
 
 	// Get current user claims
 	claims, ok := middleware.GetUserClaims(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "authentication required")
 		return
 	}
 
 	// Only admin can update users through this endpoint
 	if claims.Role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "only admins can manage users"})
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "only admins can manage users")
 		return
 	}
 
 	// Get existing user for audit comparison
 	existingUser, err := userRepo.GetModelByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, auth.ErrUserNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+		respondDomainError(c, err, "failed to get user")
 		return
 	}
 
 	var input models.UpdateUserInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "invalid request body", err.Error())
 		return
 	}
 
 	// Validate input
 	validate := validator.New()
 	if err := validate.Struct(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation failed",
-			"details": err.Error(),
-		})
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeValidationFailed, "validation failed", err.Error())
 		return
 	}
 
 	// Validate mobile phone if provided
 	if input.MobilePhone != nil && !models.ValidateMobilePhone(*input.MobilePhone) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid mobile phone format, must be in E.164 format (e.g., +5511999999999)",
-		})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid mobile phone format, must be in E.164 format (e.g., +5511999999999)")
 		return
 	}
 
@@ -271,15 +265,13 @@ func UpdateUser(c *gin.Context) {
 	var passwordHash *string
 	if input.Password != nil {
 		if err := auth.ValidatePasswordStrength(*input.Password); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
 			return
 		}
 
 		hash, err := auth.HashPassword(*input.Password)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process password"})
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to process password")
 			return
 		}
 		passwordHash = &hash
@@ -287,15 +279,7 @@ func UpdateUser(c *gin.Context) {
 
 	user, err := userRepo.UpdateUser(c.Request.Context(), id, &input, passwordHash)
 	if err != nil {
-		if errors.Is(err, auth.ErrUserNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-			return
-		}
-		if errors.Is(err, repository.ErrUserExists) {
-			c.JSON(http.StatusConflict, gin.H{"error": "user with this email already exists"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update user"})
+		respondDomainError(c, err, "failed to update user")
 		return
 	}
 
@@ -304,14 +288,8 @@ func UpdateUser(c *gin.Context) {
 		userIDForAudit, actorName := audit.GetUserInfoFromContext(c)
 		ipAddress, userAgent := audit.ExtractRequestInfo(c)
 
-		detalhes := map[string]interface{}{}
-		if input.Nome != nil {
-			detalhes["nome_anterior"] = existingUser.Nome
-			detalhes["nome_novo"] = *input.Nome
-		}
-		if input.Role != nil {
-			detalhes["role_anterior"] = existingUser.Role
-			detalhes["role_novo"] = *input.Role
+		detalhes := map[string]interface{}{
+			"alteracoes": audit.Diff(existingUser, user),
 		}
 
 		auditService.LogEventWithUser(
@@ -332,63 +310,60 @@ func UpdateUser(c *gin.Context) {
 	c.JSON(http.StatusOK, user.ToResponse())
 }
 
-// DeleteUser deactivates a user (admin only)
+// DeleteUser soft-deletes a user (admin only)
+// The user leaves the listings and cannot sign in; audit logs and occurrences keep referencing it
 // DELETE /api/v1/users/:id
 func DeleteUser(c *gin.Context) {
 	if userRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "user repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "user repository not configured")
 		return
 	}
 
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID format"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid user ID format")
 		return
 	}
 
 	// Get current user claims
 	claims, ok := middleware.GetUserClaims(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "authentication required")
 		return
 	}
 
 	// Prevent self-deletion
 	if claims.UserID == id.String() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot deactivate your own account"})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "cannot delete your own account")
 		return
 	}
 
-	// Get user info for audit before deactivation
-	userToDeactivate, _ := userRepo.GetModelByID(c.Request.Context(), id)
+	// Get user info for audit before deletion
+	userToDelete, _ := userRepo.GetModelByID(c.Request.Context(), id)
 
-	err = userRepo.DeactivateUser(c.Request.Context(), id)
+	err = userRepo.SoftDelete(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, auth.ErrUserNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to deactivate user"})
+		respondDomainError(c, err, "failed to delete user")
 		return
 	}
 
-	// Log audit event for user deactivation (WARN severity)
+	// Log audit event for user deletion (WARN severity)
 	if auditService != nil {
 		userIDForAudit, actorName := audit.GetUserInfoFromContext(c)
 		ipAddress, userAgent := audit.ExtractRequestInfo(c)
 
 		detalhes := map[string]interface{}{}
-		if userToDeactivate != nil {
-			detalhes["email"] = userToDeactivate.Email
-			detalhes["role"] = userToDeactivate.Role
+		if userToDelete != nil {
+			detalhes["email"] = userToDelete.Email
+			detalhes["role"] = userToDelete.Role
 		}
 
 		auditService.LogEventWithUser(
 			c.Request.Context(),
 			userIDForAudit,
 			actorName,
-			models.ActionUsuarioDesativar,
+			models.ActionUsuarioExcluir,
 			"Usuario",
 			id.String(),
 			nil, // no hospital_id for user operations
@@ -399,37 +374,143 @@ func DeleteUser(c *gin.Context) {
 		)
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "user deactivated successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "user deleted successfully"})
+}
+
+// RestoreUser restores a soft-deleted user and reactivates it (admin only)
+// POST /api/v1/users/:id/restore
+func RestoreUser(c *gin.Context) {
+	if userRepo == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "user repository not configured")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid user ID format")
+		return
+	}
+
+	user, err := userRepo.Restore(c.Request.Context(), id)
+	if err != nil {
+		respondDomainError(c, err, "failed to restore user")
+		return
+	}
+
+	if auditService != nil {
+		userIDForAudit, actorName := audit.GetUserInfoFromContext(c)
+		ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+		auditService.LogEventWithUser(
+			c.Request.Context(),
+			userIDForAudit,
+			actorName,
+			models.ActionUsuarioRestaurar,
+			"Usuario",
+			id.String(),
+			nil, // no hospital_id for user operations
+			models.SeverityInfo,
+			map[string]interface{}{"email": user.Email, "role": user.Role},
+			ipAddress,
+			userAgent,
+		)
+	}
+
+	c.JSON(http.StatusOK, user.ToResponse())
+}
+
+// ResendWelcome resends the onboarding email of a user (admin only), at most once per auth.WelcomeResendInterval
+// Users that have not verified their email get a new verification link, the others a new temporary password
+// Only users of the admin's tenant can be targeted, and super admins only by another super admin
+// POST /api/v1/users/:id/resend-welcome
+func ResendWelcome(c *gin.Context) {
+	if welcomeRecipients == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "user repository not configured")
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "invalid user ID format")
+		return
+	}
+
+	user, err := welcomeRecipients.GetTenantModelByID(c.Request.Context(), id)
+	if err != nil {
+		respondDomainError(c, err, "failed to get user")
+		return
+	}
+	if user.IsSuperAdmin && !middleware.IsSuperAdminFromGinContext(c) {
+		respondError(c, http.StatusForbidden, ErrCodeForbidden, "only a super admin can resend the welcome email of a super admin")
+		return
+	}
+
+	if globalAuthHandler == nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "auth handler not configured")
+		return
+	}
+
+	resend, err := globalAuthHandler.authService.ResendWelcome(c.Request.Context(), user)
+	if err != nil {
+		var rateLimited *auth.WelcomeRateLimitError
+		if errors.As(err, &rateLimited) {
+			c.Header("Retry-After", strconv.Itoa(int(rateLimited.RetryAfter.Seconds())))
+			respondError(c, http.StatusTooManyRequests, "RATE_LIMITED", rateLimited.Error())
+			return
+		}
+		respondDomainError(c, err, "failed to resend welcome email")
+		return
+	}
+
+	if auditService != nil {
+		userIDForAudit, actorName := audit.GetUserInfoFromContext(c)
+		ipAddress, userAgent := audit.ExtractRequestInfo(c)
+
+		auditService.LogEventWithUser(
+			c.Request.Context(),
+			userIDForAudit,
+			actorName,
+			models.ActionUsuarioReenvio,
+			"Usuario",
+			id.String(),
+			nil, // no hospital_id for user operations
+			models.SeverityInfo,
+			map[string]interface{}{"email": user.Email, "kind": resend.Kind},
+			ipAddress,
+			userAgent,
+		)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "welcome email sent",
+		"kind":    resend.Kind,
+	})
 }
 
 // GetCurrentUser returns the currently authenticated user's profile
 // GET /api/v1/users/me
 func GetCurrentUser(c *gin.Context) {
 	if userRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "user repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "user repository not configured")
 		return
 	}
 
 	// Get current user claims
 	claims, ok := middleware.GetUserClaims(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "authentication required")
 		return
 	}
 
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user ID in token"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "invalid user ID in token")
 		return
 	}
 
 	user, err := userRepo.GetModelByID(c.Request.Context(), userID)
 	if err != nil {
-		if errors.Is(err, auth.ErrUserNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+		respondDomainError(c, err, "failed to get user")
 		return
 	}
 
@@ -440,39 +521,33 @@ func GetCurrentUser(c *gin.Context) {
 // PATCH /api/v1/users/me
 func UpdateCurrentUser(c *gin.Context) {
 	if userRepo == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "user repository not configured"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "user repository not configured")
 		return
 	}
 
 	// Get current user claims
 	claims, ok := middleware.GetUserClaims(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "authentication required"})
+		respondError(c, http.StatusUnauthorized, ErrCodeUnauthorized, "authentication required")
 		return
 	}
 
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user ID in token"})
+		respondError(c, http.StatusInternalServerError, ErrCodeInternal, "invalid user ID in token")
 		return
 	}
 
 	var input models.UpdateProfileInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid request body",
-			"details": err.Error(),
-		})
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeInvalidRequestBody, "invalid request body", err.Error())
 		return
 	}
 
 	// Validate input
 	validate := validator.New()
 	if err := validate.Struct(input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation failed",
-			"details": err.Error(),
-		})
+		respondErrorDetails(c, http.StatusBadRequest, ErrCodeValidationFailed, "validation failed", err.Error())
 		return
 	}
 
@@ -481,42 +556,32 @@ func UpdateCurrentUser(c *gin.Context) {
 	if input.NewPassword != nil {
 		// Current password is required to change password
 		if input.CurrentPassword == nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "current_password is required to change password",
-			})
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "current_password is required to change password")
 			return
 		}
 
 		// Verify current password
 		user, err := userRepo.GetModelByID(c.Request.Context(), userID)
 		if err != nil {
-			if errors.Is(err, auth.ErrUserNotFound) {
-				c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+			respondDomainError(c, err, "failed to get user")
 			return
 		}
 
 		if err := auth.CheckPasswordHash(*input.CurrentPassword, user.PasswordHash); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "current password is incorrect",
-			})
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, "current password is incorrect")
 			return
 		}
 
 		// Validate new password strength
 		if err := auth.ValidatePasswordStrength(*input.NewPassword); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, err.Error())
 			return
 		}
 
 		// Hash new password
 		hash, err := auth.HashPassword(*input.NewPassword)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process password"})
+			respondError(c, http.StatusInternalServerError, ErrCodeInternal, "failed to process password")
 			return
 		}
 		newPasswordHash = &hash
@@ -524,11 +589,7 @@ func UpdateCurrentUser(c *gin.Context) {
 
 	user, err := userRepo.UpdateProfile(c.Request.Context(), userID, &input, newPasswordHash)
 	if err != nil {
-		if errors.Is(err, auth.ErrUserNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update profile"})
+		respondDomainError(c, err, "failed to update profile")
 		return
 	}
 
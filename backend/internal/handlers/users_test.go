@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+// tenantWelcomeRecipients serves users from memory, scoped to the tenant of the context like the repository
+type tenantWelcomeRecipients struct {
+	users map[uuid.UUID]*models.User
+}
+
+func (r *tenantWelcomeRecipients) GetTenantModelByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, auth.ErrUserNotFound
+	}
+	tenantID, _, err := middleware.GetTenantFromContext(ctx)
+	if err == nil && tenantID != "" && (user.TenantID == nil || user.TenantID.String() != tenantID) {
+		return nil, auth.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// resendWelcomeAs posts a welcome resend of userID as an admin of tenantID
+func resendWelcomeAs(tenantID uuid.UUID, isSuperAdmin bool, userID uuid.UUID) *httptest.ResponseRecorder {
+	router := setupTestRouter()
+	router.POST("/api/v1/users/:id/resend-welcome", func(c *gin.Context) {
+		c.Set("tenant_context", &middleware.TenantContext{
+			TenantID:          tenantID.String(),
+			IsSuperAdmin:      isSuperAdmin,
+			EffectiveTenantID: tenantID.String(),
+		})
+		c.Request = c.Request.WithContext(middleware.WithTenantContext(c.Request.Context(), tenantID.String(), isSuperAdmin))
+		ResendWelcome(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/"+userID.String()+"/resend-welcome", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestResendWelcomeTenantIsolation verifies that an admin cannot resend the welcome email, and so reset the
+// password, of a user of another tenant or of a super admin
+func TestResendWelcomeTenantIsolation(t *testing.T) {
+	tenantA, tenantB := uuid.New(), uuid.New()
+	foreignUser := &models.User{ID: uuid.New(), TenantID: &tenantB, Role: models.RoleOperador, Ativo: true}
+	superAdmin := &models.User{ID: uuid.New(), TenantID: &tenantA, Role: models.RoleAdmin, IsSuperAdmin: true, Ativo: true}
+
+	prev := welcomeRecipients
+	welcomeRecipients = &tenantWelcomeRecipients{users: map[uuid.UUID]*models.User{
+		foreignUser.ID: foreignUser,
+		superAdmin.ID:  superAdmin,
+	}}
+	t.Cleanup(func() { welcomeRecipients = prev })
+
+	t.Run("user of another tenant", func(t *testing.T) {
+		if w := resendWelcomeAs(tenantA, false, foreignUser.ID); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("super admin targeted by a tenant admin", func(t *testing.T) {
+		if w := resendWelcomeAs(tenantA, false, superAdmin.ID); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
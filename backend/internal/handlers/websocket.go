@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/services/notification"
+	"golang.org/x/net/websocket"
+)
+
+// WSWriteTimeout limits how long a single WebSocket write may block
+const WSWriteTimeout = 10 * time.Second
+
+// wsConnectedMessage is the first message sent after a WebSocket connection is accepted
+type wsConnectedMessage struct {
+	Type      string `json:"type"`
+	ClientID  string `json:"client_id"`
+	Timestamp string `json:"timestamp"`
+}
+
+// NotificationWebSocket handles WebSocket connections for real-time notifications
+// Events come from the same SSE hub and use the same payload as the SSE stream
+// GET /api/v1/notifications/ws
+func NotificationWebSocket(c *gin.Context) {
+	if globalSSEHub == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "WebSocket service not available"})
+		return
+	}
+
+	claims, ok := streamClaims(c)
+	if !ok {
+		return
+	}
+
+	server := websocket.Server{
+		Handshake: acceptAnyOrigin,
+		Handler: func(ws *websocket.Conn) {
+			serveNotificationWebSocket(ws, globalSSEHub, claims)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// acceptAnyOrigin mirrors the SSE stream CORS policy (Access-Control-Allow-Origin: *)
+// Authentication is enforced by the token, so browsers and native clients without Origin are accepted
+func acceptAnyOrigin(config *websocket.Config, req *http.Request) error {
+	if origin := req.Header.Get("Origin"); origin != "" {
+		if parsed, err := websocket.Origin(config, req); err == nil {
+			config.Origin = parsed
+		}
+	}
+	return nil
+}
+
+// serveNotificationWebSocket registers the connection with the hub and relays events until it closes
+func serveNotificationWebSocket(ws *websocket.Conn, hub *notification.SSEHub, claims *middleware.UserClaims) {
+	defer ws.Close()
+
+	client := notification.NewWebSocketClient(claims.UserID, claims.Role)
+	client.SetTenant(claims.TenantID, claims.IsSuperAdmin)
+	hub.RegisterClient(client)
+	defer hub.UnregisterClient(client.ID)
+
+	if err := sendWSMessage(ws, wsConnectedMessage{
+		Type:      "connected",
+		ClientID:  client.ID,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		return
+	}
+
+	// Incoming messages are ignored; a read error means the peer went away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard []byte
+		for {
+			if err := websocket.Message.Receive(ws, &discard); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+
+		case <-client.Done:
+			return
+
+		case event := <-client.Channel:
+			if event == nil {
+				continue
+			}
+
+			if err := sendWSMessage(ws, event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sendWSMessage writes a JSON text frame with a write deadline
+func sendWSMessage(ws *websocket.Conn, message interface{}) error {
+	ws.SetWriteDeadline(time.Now().Add(WSWriteTimeout))
+	return websocket.JSON.Send(ws, message)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services/notification"
+	"golang.org/x/net/websocket"
+)
+
+// TestNotificationWebSocketReceivesNewOccurrence tests that a WebSocket client receives events published to the SSE hub
+func TestNotificationWebSocketReceivesNewOccurrence(t *testing.T) {
+	hub := notification.NewSSEHub(nil, nil)
+	previousHub := globalSSEHub
+	SetGlobalSSEHub(hub)
+	defer SetGlobalSSEHub(previousHub)
+
+	tenantID := uuid.New()
+	router := setupTestRouter()
+	router.GET("/api/v1/notifications/ws", func(c *gin.Context) {
+		c.Set("user_claims", &middleware.UserClaims{
+			UserID:   uuid.New().String(),
+			Role:     "operador",
+			TenantID: tenantID.String(),
+		})
+		NotificationWebSocket(c)
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/notifications/ws"
+	ws, err := websocket.Dial(wsURL, "", "http://localhost/")
+	if err != nil {
+		t.Fatalf("Failed to connect WebSocket: %v", err)
+	}
+	defer ws.Close()
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var connected map[string]interface{}
+	if err := websocket.JSON.Receive(ws, &connected); err != nil {
+		t.Fatalf("Failed to read connected message: %v", err)
+	}
+	if connected["type"] != "connected" || connected["client_id"] == "" {
+		t.Fatalf("Unexpected connected message: %v", connected)
+	}
+
+	if counts := hub.GetClientCountByTransport(); counts[notification.TransportWebSocket] != 1 {
+		t.Errorf("Expected 1 WebSocket client registered, got %d", counts[notification.TransportWebSocket])
+	}
+
+	dadosCompletos, _ := json.Marshal(models.OccurrenceCompleteData{Setor: "UTI"})
+	occurrence := &models.Occurrence{
+		ID:             uuid.New(),
+		TenantID:       tenantID,
+		DataObito:      time.Now().Add(-time.Hour),
+		JanelaExpiraEm: time.Now().Add(5 * time.Hour),
+		DadosCompletos: dadosCompletos,
+	}
+	event := models.NewOccurrenceSSEEvent(occurrence, "Hospital Teste")
+	if err := hub.PublishEvent(context.Background(), &event); err != nil {
+		t.Fatalf("PublishEvent returned error: %v", err)
+	}
+
+	var received models.SSEEvent
+	if err := websocket.JSON.Receive(ws, &received); err != nil {
+		t.Fatalf("Failed to read event: %v", err)
+	}
+	if received.Type != "new_occurrence" {
+		t.Errorf("Expected event type new_occurrence, got %s", received.Type)
+	}
+	if received.OccurrenceID != occurrence.ID {
+		t.Errorf("Expected occurrence %s, got %s", occurrence.ID, received.OccurrenceID)
+	}
+	if received.HospitalNome != "Hospital Teste" || received.Setor != "UTI" {
+		t.Errorf("Unexpected event payload: %+v", received)
+	}
+}
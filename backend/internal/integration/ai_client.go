@@ -4,35 +4,45 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 )
 
 // AIServiceClient handles HTTP communication with the Python AI service
 type AIServiceClient struct {
-	baseURL    string
-	httpClient *http.Client
-	maxRetries int
+	baseURL      string
+	httpClient   *http.Client
+	streamClient *http.Client
+	maxRetries   int
+	breaker      *circuitBreaker
 }
 
 // AIServiceConfig holds configuration for the AI service client
 type AIServiceConfig struct {
 	BaseURL       string
-	Timeout       time.Duration
+	Timeout       time.Duration // per-request timeout; for streams it bounds the wait for the response headers
 	MaxRetries    int
 	RetryInterval time.Duration
+	// FailureThreshold is the number of consecutive failed requests that opens the circuit breaker
+	FailureThreshold int
+	// BreakerCooldown is how long the open circuit rejects requests before probing the service again
+	BreakerCooldown time.Duration
 }
 
 // DefaultAIServiceConfig returns default configuration for the AI service
 func DefaultAIServiceConfig() *AIServiceConfig {
 	return &AIServiceConfig{
-		BaseURL:       getAIServiceURL(),
-		Timeout:       30 * time.Second,
-		MaxRetries:    3,
-		RetryInterval: 1 * time.Second,
+		BaseURL:          getAIServiceURL(),
+		Timeout:          getAIServiceSecondsEnv("AI_SERVICE_TIMEOUT_SECONDS", 30*time.Second),
+		MaxRetries:       3,
+		RetryInterval:    1 * time.Second,
+		FailureThreshold: getAIServiceIntEnv("AI_SERVICE_BREAKER_THRESHOLD", 5),
+		BreakerCooldown:  getAIServiceSecondsEnv("AI_SERVICE_BREAKER_COOLDOWN_SECONDS", 30*time.Second),
 	}
 }
 
@@ -45,18 +55,39 @@ func getAIServiceURL() string {
 	return url
 }
 
+// getAIServiceIntEnv retrieves a positive integer from environment, falling back to the default
+func getAIServiceIntEnv(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// getAIServiceSecondsEnv retrieves a duration in seconds from environment, falling back to the default
+func getAIServiceSecondsEnv(key string, defaultValue time.Duration) time.Duration {
+	return time.Duration(getAIServiceIntEnv(key, int(defaultValue/time.Second))) * time.Second
+}
+
 // NewAIServiceClient creates a new AI service client with the given configuration
 func NewAIServiceClient(config *AIServiceConfig) *AIServiceClient {
 	if config == nil {
 		config = DefaultAIServiceConfig()
 	}
 
+	// Streams can legitimately run longer than the request timeout, so only the wait for headers is bounded
+	streamTransport := http.DefaultTransport.(*http.Transport).Clone()
+	streamTransport.ResponseHeaderTimeout = config.Timeout
+
 	return &AIServiceClient{
 		baseURL: config.BaseURL,
 		httpClient: &http.Client{
 			Timeout: config.Timeout,
 		},
+		streamClient: &http.Client{
+			Transport: streamTransport,
+		},
 		maxRetries: config.MaxRetries,
+		breaker:    newCircuitBreaker(config.FailureThreshold, config.BreakerCooldown),
 	}
 }
 
@@ -109,19 +140,86 @@ type ConfirmActionResponse struct {
 	Result  map[string]interface{} `json:"result,omitempty"`
 }
 
-// ConversationListResponse represents a list of conversations
+// ServiceTime is a timestamp written by the AI service.
+// Its models store naive timestamps, so values without a zone offset are read as UTC.
+type ServiceTime struct {
+	time.Time
+}
+
+// UnmarshalJSON decodes an ISO 8601 timestamp with or without zone offset; empty values are the zero time
+func (t *ServiceTime) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		parsed, err = time.ParseInLocation("2006-01-02T15:04:05.999999999", value, time.UTC)
+		if err != nil {
+			return fmt.Errorf("invalid AI service timestamp %q: %w", value, err)
+		}
+	}
+	t.Time = parsed
+	return nil
+}
+
+// PendingAction represents an action proposed by the AI agent that awaits user confirmation
+type PendingAction struct {
+	ActionID    string                 `json:"action_id"`
+	ToolName    string                 `json:"tool_name"`
+	InputParams map[string]interface{} `json:"input_params"`
+	Status      string                 `json:"status"`
+	CreatedAt   ServiceTime            `json:"created_at"`
+}
+
+// PendingActionStatus is the status of an action that has not been confirmed or cancelled yet
+const PendingActionStatus = "pending"
+
+var (
+	// ErrActionNotFound is returned when the AI service has no action with the given ID in the tenant
+	ErrActionNotFound = errors.New("AI action not found")
+	// ErrActionForbidden is returned when the action was proposed to another user
+	ErrActionForbidden = errors.New("AI action belongs to another user")
+)
+
+// ConversationListResponse represents a page of the user's conversations
 type ConversationListResponse struct {
-	Data  []ConversationSummary `json:"data"`
-	Total int                   `json:"total"`
+	Conversations []ConversationSummary `json:"conversations"`
+	Total         int                   `json:"total"` // number of conversations in this page
 }
 
 // ConversationSummary represents a summary of a conversation
 type ConversationSummary struct {
-	SessionID   string    `json:"session_id"`
-	LastMessage string    `json:"last_message"`
-	MessageCount int      `json:"message_count"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	SessionID     string `json:"session_id"`
+	LastMessageAt string `json:"last_message_at"`
+	MessageCount  int    `json:"message_count"`
+}
+
+// MessageToolCalls holds the tool calls of a stored message.
+// The AI service stores them as {"calls": [...]}, so both that and a plain list are accepted.
+type MessageToolCalls []ToolCall
+
+// UnmarshalJSON decodes either a list of tool calls or the {"calls": [...]} envelope
+func (m *MessageToolCalls) UnmarshalJSON(data []byte) error {
+	var calls []ToolCall
+	if err := json.Unmarshal(data, &calls); err == nil {
+		*m = calls
+		return nil
+	}
+
+	var envelope struct {
+		Calls []ToolCall `json:"calls"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+	*m = envelope.Calls
+	return nil
 }
 
 // ConversationMessage represents a single message in a conversation
@@ -129,9 +227,9 @@ type ConversationMessage struct {
 	ID        string                 `json:"id"`
 	Role      string                 `json:"role"`
 	Content   string                 `json:"content"`
-	ToolCalls []ToolCall             `json:"tool_calls,omitempty"`
+	ToolCalls MessageToolCalls       `json:"tool_calls,omitempty"`
 	Metadata  map[string]interface{} `json:"metadata,omitempty"`
-	CreatedAt time.Time              `json:"created_at"`
+	CreatedAt ServiceTime            `json:"created_at"`
 }
 
 // ConversationDetailResponse represents the details of a conversation
@@ -146,8 +244,32 @@ type HealthResponse struct {
 	Timestamp string `json:"timestamp"`
 }
 
-// doRequest executes an HTTP request with retry logic
+// doRequest executes an HTTP request with retry logic, guarded by the circuit breaker
 func (c *AIServiceClient) doRequest(ctx context.Context, method, path string, body interface{}, opts *AIRequestOptions) (*http.Response, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendWithRetry(ctx, method, path, body, opts)
+	c.recordOutcome(ctx, resp, err)
+	return resp, err
+}
+
+// recordOutcome updates the circuit breaker with the result of a request.
+// Requests cancelled by the caller say nothing about the service and are not counted.
+func (c *AIServiceClient) recordOutcome(ctx context.Context, resp *http.Response, err error) {
+	switch {
+	case err == nil && resp.StatusCode < 500:
+		c.breaker.recordSuccess()
+	case errors.Is(ctx.Err(), context.Canceled):
+		c.breaker.release()
+	default:
+		c.breaker.recordFailure()
+	}
+}
+
+// sendWithRetry sends the request, retrying on transport errors and 5xx responses
+func (c *AIServiceClient) sendWithRetry(ctx context.Context, method, path string, body interface{}, opts *AIRequestOptions) (*http.Response, error) {
 	var bodyReader io.Reader
 	if body != nil {
 		jsonData, err := json.Marshal(body)
@@ -291,9 +413,38 @@ func (c *AIServiceClient) ConfirmAction(ctx context.Context, actionID string, co
 	return &confirmResp, nil
 }
 
-// ListConversations lists all conversations for the authenticated user
-func (c *AIServiceClient) ListConversations(ctx context.Context, opts *AIRequestOptions) (*ConversationListResponse, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, "/api/v1/ai/conversations", nil, opts)
+// GetPendingAction retrieves an action proposed to the authenticated user
+func (c *AIServiceClient) GetPendingAction(ctx context.Context, actionID string, opts *AIRequestOptions) (*PendingAction, error) {
+	path := fmt.Sprintf("/api/v1/ai/actions/%s", actionID)
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrActionNotFound
+	case http.StatusForbidden:
+		return nil, ErrActionForbidden
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("get action failed: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var action PendingAction
+	if err := json.NewDecoder(resp.Body).Decode(&action); err != nil {
+		return nil, fmt.Errorf("failed to decode action response: %w", err)
+	}
+
+	return &action, nil
+}
+
+// ListConversations lists a page of conversations for the authenticated user, most recent first
+func (c *AIServiceClient) ListConversations(ctx context.Context, limit, offset int, opts *AIRequestOptions) (*ConversationListResponse, error) {
+	path := fmt.Sprintf("/api/v1/ai/conversations?limit=%d&offset=%d", limit, offset)
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -314,7 +465,15 @@ func (c *AIServiceClient) ListConversations(ctx context.Context, opts *AIRequest
 
 // GetConversation retrieves a specific conversation by session ID
 func (c *AIServiceClient) GetConversation(ctx context.Context, sessionID string, opts *AIRequestOptions) (*ConversationDetailResponse, error) {
-	path := fmt.Sprintf("/api/v1/ai/conversations/%s", sessionID)
+	return c.getConversation(ctx, fmt.Sprintf("/api/v1/ai/conversations/%s", sessionID), opts)
+}
+
+// GetConversationMessages retrieves a page of messages of a conversation, oldest first
+func (c *AIServiceClient) GetConversationMessages(ctx context.Context, sessionID string, limit, offset int, opts *AIRequestOptions) (*ConversationDetailResponse, error) {
+	return c.getConversation(ctx, fmt.Sprintf("/api/v1/ai/conversations/%s?limit=%d&offset=%d", sessionID, limit, offset), opts)
+}
+
+func (c *AIServiceClient) getConversation(ctx context.Context, path string, opts *AIRequestOptions) (*ConversationDetailResponse, error) {
 	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, opts)
 	if err != nil {
 		return nil, err
@@ -351,6 +510,28 @@ func (c *AIServiceClient) DeleteConversation(ctx context.Context, sessionID stri
 	return nil
 }
 
+// Stream sends a streaming request to the AI service, guarded by the circuit breaker.
+// The caller owns the response body.
+func (c *AIServiceClient) Stream(req *http.Request) (*http.Response, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.streamClient.Do(req)
+	c.recordOutcome(req.Context(), resp, err)
+	return resp, err
+}
+
+// BreakerStatus returns the current state of the circuit breaker
+func (c *AIServiceClient) BreakerStatus() CircuitBreakerStatus {
+	return c.breaker.status()
+}
+
+// BreakerRetryAfter returns how long until the open circuit breaker probes the service again
+func (c *AIServiceClient) BreakerRetryAfter() time.Duration {
+	return c.breaker.retryAfter()
+}
+
 // GetBaseURL returns the base URL of the AI service
 func (c *AIServiceClient) GetBaseURL() string {
 	return c.baseURL
@@ -0,0 +1,155 @@
+package integration
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the AI service while the circuit breaker is open
+var ErrCircuitOpen = errors.New("AI service circuit breaker is open")
+
+// CircuitState represents the state of the AI service circuit breaker
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// CircuitBreakerStatus is a snapshot of the circuit breaker for health reporting
+type CircuitBreakerStatus struct {
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	FailureThreshold    int          `json:"failure_threshold"`
+	CooldownSeconds     int          `json:"cooldown_seconds"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	RetryAfterSeconds   int          `json:"retry_after_seconds,omitempty"`
+}
+
+// circuitBreaker stops calling the AI service after consecutive failures.
+// Once the cooldown elapses it lets a single probe request through (half-open):
+// a success closes the circuit, a failure opens it for another cooldown.
+type circuitBreaker struct {
+	mu            sync.Mutex
+	threshold     int
+	cooldown      time.Duration
+	state         CircuitState
+	failures      int
+	openedAt      time.Time
+	probeInFlight bool
+	now           func() time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     CircuitClosed,
+		now:       time.Now,
+	}
+}
+
+// allow reports whether a request may be sent, moving an expired open circuit to half-open
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		b.probeInFlight = true
+		return nil
+	case CircuitHalfOpen:
+		// Only one probe at a time while recovery is being checked
+		if b.probeInFlight {
+			return ErrCircuitOpen
+		}
+		b.probeInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// recordSuccess closes the circuit and resets the failure count
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+	b.probeInFlight = false
+}
+
+// recordFailure counts a failed request, opening the circuit at the threshold or when a probe fails
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probeInFlight = false
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// release frees the probe slot when a request ended without telling anything about the service
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probeInFlight = false
+}
+
+// retryAfter returns how long until the open circuit lets a probe through
+func (b *circuitBreaker) retryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != CircuitOpen {
+		return 0
+	}
+	remaining := b.cooldown - b.now().Sub(b.openedAt)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// status returns a snapshot of the breaker
+func (b *circuitBreaker) status() CircuitBreakerStatus {
+	retryAfter := b.retryAfter()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := CircuitBreakerStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		FailureThreshold:    b.threshold,
+		CooldownSeconds:     int(b.cooldown.Seconds()),
+	}
+	if b.state != CircuitClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	if retryAfter > 0 {
+		status.RetryAfterSeconds = int(retryAfter.Round(time.Second).Seconds())
+		if status.RetryAfterSeconds == 0 {
+			status.RetryAfterSeconds = 1
+		}
+	}
+	return status
+}
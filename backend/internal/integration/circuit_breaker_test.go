@@ -0,0 +1,82 @@
+package integration
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestBreaker returns a breaker driven by a controllable clock
+func newTestBreaker(threshold int, cooldown time.Duration) (*circuitBreaker, *time.Time) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(threshold, cooldown)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+// TestCircuitBreakerOpensAfterConsecutiveFailures tests that the threshold of consecutive failures opens the circuit
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		if err := b.allow(); err != nil {
+			t.Fatalf("Request %d rejected before the threshold: %v", i+1, err)
+		}
+		b.recordFailure()
+	}
+
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen after 3 failures, got %v", err)
+	}
+	if status := b.status(); status.State != CircuitOpen || status.ConsecutiveFailures != 3 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+}
+
+// TestCircuitBreakerSuccessResetsFailures tests that only consecutive failures count towards the threshold
+func TestCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	b, _ := newTestBreaker(2, time.Minute)
+
+	b.recordFailure()
+	b.recordSuccess()
+	b.recordFailure()
+
+	if err := b.allow(); err != nil {
+		t.Errorf("Expected the circuit to stay closed, got %v", err)
+	}
+}
+
+// TestCircuitBreakerHalfOpenProbe tests that after the cooldown a single probe is allowed,
+// closing the circuit on success and reopening it on failure
+func TestCircuitBreakerHalfOpenProbe(t *testing.T) {
+	b, now := newTestBreaker(1, time.Minute)
+	b.recordFailure()
+
+	*now = now.Add(59 * time.Second)
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected the circuit to stay open during the cooldown, got %v", err)
+	}
+
+	*now = now.Add(2 * time.Second)
+	if err := b.allow(); err != nil {
+		t.Fatalf("Expected a probe after the cooldown, got %v", err)
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected a concurrent request to be rejected while probing, got %v", err)
+	}
+
+	b.recordFailure()
+	if status := b.status(); status.State != CircuitOpen || status.RetryAfterSeconds != 60 {
+		t.Fatalf("Expected a failed probe to reopen the circuit for a full cooldown, got %+v", status)
+	}
+
+	*now = now.Add(time.Minute)
+	if err := b.allow(); err != nil {
+		t.Fatalf("Expected another probe after the cooldown, got %v", err)
+	}
+	b.recordSuccess()
+
+	if status := b.status(); status.State != CircuitClosed || status.ConsecutiveFailures != 0 || status.OpenedAt != nil {
+		t.Errorf("Expected a successful probe to close the circuit, got %+v", status)
+	}
+}
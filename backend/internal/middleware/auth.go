@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -16,6 +17,51 @@ type UserClaims struct {
 	HospitalID   string `json:"hospital_id,omitempty"`
 	TenantID     string `json:"tenant_id,omitempty"`
 	IsSuperAdmin bool   `json:"is_super_admin,omitempty"`
+
+	// Set only when the request uses an impersonation token
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+	TokenID        string `json:"-"`
+}
+
+// ImpersonationRevocationChecker reports whether an impersonation token was revoked
+// (implemented by auth.ImpersonationService)
+type ImpersonationRevocationChecker interface {
+	IsImpersonationRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// impersonationRevocations is checked by AuthRequired for every impersonation token
+var impersonationRevocations ImpersonationRevocationChecker
+
+// SetImpersonationRevocationChecker sets the checker used to reject revoked impersonation tokens
+func SetImpersonationRevocationChecker(checker ImpersonationRevocationChecker) {
+	impersonationRevocations = checker
+}
+
+// ImpersonationRevoked reports whether the token is an impersonation token revoked by a super admin
+// Regular tokens are never reported as revoked. An error means the revocation could not be checked,
+// in which case the impersonation must be refused.
+func ImpersonationRevoked(ctx context.Context, claims *auth.Claims) (bool, error) {
+	if !claims.IsImpersonation || impersonationRevocations == nil {
+		return false, nil
+	}
+	return impersonationRevocations.IsImpersonationRevoked(ctx, claims.ID)
+}
+
+// NewUserClaims returns the user claims of a validated access token
+func NewUserClaims(claims *auth.Claims) *UserClaims {
+	userClaims := &UserClaims{
+		UserID:       claims.UserID,
+		Email:        claims.Email,
+		Role:         claims.Role,
+		HospitalID:   claims.HospitalID,
+		TenantID:     claims.TenantID,
+		IsSuperAdmin: claims.IsSuperAdmin,
+		TokenID:      claims.ID,
+	}
+	if claims.IsImpersonation {
+		userClaims.ImpersonatedBy = claims.OriginalAdminID
+	}
+	return userClaims
 }
 
 // contextKey is the key used to store JWT service in context
@@ -99,16 +145,20 @@ func AuthRequired() gin.HandlerFunc {
 			return
 		}
 
-		// Set user claims in context (including tenant info)
-		userClaims := &UserClaims{
-			UserID:       claims.UserID,
-			Email:        claims.Email,
-			Role:         claims.Role,
-			HospitalID:   claims.HospitalID,
-			TenantID:     claims.TenantID,
-			IsSuperAdmin: claims.IsSuperAdmin,
+		// Impersonation tokens can be revoked by a super admin before they expire
+		// If revocation cannot be checked, the impersonation is refused
+		revoked, err := ImpersonationRevoked(c.Request.Context(), claims)
+		if err != nil {
+			AbortWithError(c, http.StatusUnauthorized, "UNAUTHORIZED", "unable to verify impersonation session", "")
+			return
+		}
+		if revoked {
+			AbortWithError(c, http.StatusUnauthorized, "IMPERSONATION_REVOKED", "impersonation session has been revoked", "")
+			return
 		}
-		c.Set("user_claims", userClaims)
+
+		// Set user claims in context (including tenant info)
+		c.Set("user_claims", NewUserClaims(claims))
 
 		c.Next()
 	}
@@ -204,16 +254,14 @@ func OptionalAuth() gin.HandlerFunc {
 			return
 		}
 
+		// A revoked impersonation token, or one whose revocation cannot be checked, is ignored
+		if revoked, err := ImpersonationRevoked(c.Request.Context(), claims); err != nil || revoked {
+			c.Next()
+			return
+		}
+
 		// Set user claims in context (including tenant info)
-		userClaims := &UserClaims{
-			UserID:       claims.UserID,
-			Email:        claims.Email,
-			Role:         claims.Role,
-			HospitalID:   claims.HospitalID,
-			TenantID:     claims.TenantID,
-			IsSuperAdmin: claims.IsSuperAdmin,
-		}
-		c.Set("user_claims", userClaims)
+		c.Set("user_claims", NewUserClaims(claims))
 
 		c.Next()
 	}
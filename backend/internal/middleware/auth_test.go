@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/services/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revokedTokens is an ImpersonationRevocationChecker backed by a set of token IDs
+type revokedTokens map[string]bool
+
+func (r revokedTokens) IsImpersonationRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return r[tokenID], nil
+}
+
+func TestAuthRequiredRejectsRevokedImpersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jwtService, err := auth.NewJWTService("test-access-secret-key-32-chars!", "test-refresh-secret-key-32chars!", 15*time.Minute, time.Hour)
+	require.NoError(t, err)
+
+	revoked := revokedTokens{}
+	SetImpersonationRevocationChecker(revoked)
+	t.Cleanup(func() { SetImpersonationRevocationChecker(nil) })
+
+	router := gin.New()
+	router.Use(SetJWTService(jwtService))
+	router.Use(AuthRequired())
+	router.GET("/protected", func(c *gin.Context) {
+		claims, _ := GetUserClaims(c)
+		c.JSON(http.StatusOK, gin.H{"impersonated_by": claims.ImpersonatedBy})
+	})
+
+	request := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	adminID := uuid.New().String()
+	token, tokenID, _, err := jwtService.GenerateImpersonationToken(uuid.New().String(), "operador@sidot.gov.br", "operador", "", "", false, adminID, time.Hour)
+	require.NoError(t, err)
+
+	t.Run("should accept an active impersonation token", func(t *testing.T) {
+		w := request(token)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]string
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, adminID, response["impersonated_by"])
+	})
+
+	t.Run("should reject a revoked impersonation token", func(t *testing.T) {
+		revoked[tokenID] = true
+
+		w := request(token)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]string
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "IMPERSONATION_REVOKED", response["code"])
+	})
+
+	t.Run("should not check regular tokens against the revocation set", func(t *testing.T) {
+		regular, err := jwtService.GenerateAccessTokenWithTenant(uuid.New().String(), "gestor@sidot.gov.br", "gestor", "", "", false)
+		require.NoError(t, err)
+		claims, err := jwtService.ValidateAccessToken(regular)
+		require.NoError(t, err)
+		revoked[claims.ID] = true
+
+		assert.Equal(t, http.StatusOK, request(regular).Code)
+	})
+}
+
+func TestOptionalAuthIgnoresRevokedImpersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jwtService, err := auth.NewJWTService("test-access-secret-key-32-chars!", "test-refresh-secret-key-32chars!", 15*time.Minute, time.Hour)
+	require.NoError(t, err)
+
+	revoked := revokedTokens{}
+	SetImpersonationRevocationChecker(revoked)
+	t.Cleanup(func() { SetImpersonationRevocationChecker(nil) })
+
+	router := gin.New()
+	router.Use(SetJWTService(jwtService))
+	router.Use(OptionalAuth())
+	router.GET("/public", func(c *gin.Context) {
+		claims, ok := GetUserClaims(c)
+		if !ok {
+			c.JSON(http.StatusOK, gin.H{"authenticated": "false"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"authenticated": "true", "impersonated_by": claims.ImpersonatedBy})
+	})
+
+	adminID := uuid.New().String()
+	token, tokenID, _, err := jwtService.GenerateImpersonationToken(uuid.New().String(), "operador@sidot.gov.br", "operador", "", "", false, adminID, time.Hour)
+	require.NoError(t, err)
+
+	request := func() map[string]string {
+		req := httptest.NewRequest(http.MethodGet, "/public", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]string
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response
+	}
+
+	response := request()
+	assert.Equal(t, "true", response["authenticated"])
+	assert.Equal(t, adminID, response["impersonated_by"])
+
+	revoked[tokenID] = true
+	assert.Equal(t, "false", request()["authenticated"])
+}
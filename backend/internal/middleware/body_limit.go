@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimitConfig configures the request body limits
+type BodyLimitConfig struct {
+	MaxBytes     int64            // limit for routes without an override
+	MaxJSONDepth int              // maximum nesting of objects and arrays in JSON bodies (0 disables the check)
+	RouteLimits  map[string]int64 // limits by route path (as registered, e.g. "/api/v1/admin/tenants/:id/assets")
+}
+
+// BodyLimit returns a middleware that rejects request bodies larger than the route limit with 413
+// and bodies nested deeper than MaxJSONDepth with 400.
+// Every body but multipart uploads is buffered (up to the limit) and checked before the handler binds it,
+// whatever its content type, since JSON binding decodes any body; multipart uploads are capped while the
+// handler reads them.
+func BodyLimit(cfg BodyLimitConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		limit := cfg.MaxBytes
+		if routeLimit, ok := cfg.RouteLimits[c.FullPath()]; ok {
+			limit = routeLimit
+		}
+
+		if c.Request.ContentLength > limit {
+			abortBodyTooLarge(c, limit)
+			return
+		}
+
+		if isMultipartContentType(c.ContentType()) {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+			c.Next()
+			return
+		}
+
+		// Read one byte past the limit to detect bodies without Content-Length (chunked) that exceed it
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+		if err != nil {
+			AbortWithError(c, http.StatusBadRequest, "INVALID_REQUEST_BODY", "failed to read request body", err.Error())
+			return
+		}
+		if int64(len(body)) > limit {
+			abortBodyTooLarge(c, limit)
+			return
+		}
+
+		if cfg.MaxJSONDepth > 0 && jsonDepthExceeds(body, cfg.MaxJSONDepth) {
+			AbortWithError(c, http.StatusBadRequest, "REQUEST_BODY_TOO_DEEP", "request body nested too deeply",
+				fmt.Sprintf("maximum JSON nesting depth is %d", cfg.MaxJSONDepth))
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+// abortBodyTooLarge aborts the request with 413
+func abortBodyTooLarge(c *gin.Context, limit int64) {
+	AbortWithError(c, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", "request body too large",
+		fmt.Sprintf("maximum size is %d bytes", limit))
+}
+
+// isMultipartContentType checks if the content type is multipart (e.g. multipart/form-data uploads)
+func isMultipartContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, "multipart/")
+}
+
+// jsonDepthExceeds reports whether objects and arrays in data nest deeper than maxDepth.
+// It only scans brackets outside strings; malformed JSON is left for the handler to reject.
+func jsonDepthExceeds(data []byte, maxDepth int) bool {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for _, b := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case b == '\\':
+				escaped = true
+			case b == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch b {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return true
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return false
+}
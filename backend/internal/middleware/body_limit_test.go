@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBodyLimitRouter(cfg BodyLimitConfig) *gin.Engine {
+	router := gin.New()
+	router.Use(BodyLimit(cfg))
+	echo := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	}
+	router.POST("/test", echo)
+	router.POST("/upload/:id", echo)
+	return router
+}
+
+func postBody(router *gin.Engine, path, contentType string, body io.Reader) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// chunkedReader hides the body length, so the request has no Content-Length
+type chunkedReader struct {
+	io.Reader
+}
+
+func TestBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newBodyLimitRouter(BodyLimitConfig{
+		MaxBytes:     64,
+		MaxJSONDepth: 3,
+		RouteLimits:  map[string]int64{"/upload/:id": 1024},
+	})
+
+	t.Run("should pass a normal JSON request", func(t *testing.T) {
+		w := postBody(router, "/test", "application/json", strings.NewReader(`{"nome":"teste","itens":[1,2]}`))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"nome":"teste","itens":[1,2]}`, w.Body.String())
+	})
+
+	t.Run("should reject an oversized body", func(t *testing.T) {
+		body := `{"nome":"` + strings.Repeat("a", 100) + `"}`
+		w := postBody(router, "/test", "application/json", strings.NewReader(body))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), "request body too large")
+		assert.Contains(t, w.Body.String(), `"code":"PAYLOAD_TOO_LARGE"`)
+	})
+
+	t.Run("should reject an oversized body without content length", func(t *testing.T) {
+		body := `{"nome":"` + strings.Repeat("a", 100) + `"}`
+		w := postBody(router, "/test", "application/json", chunkedReader{strings.NewReader(body)})
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("should reject deeply nested JSON", func(t *testing.T) {
+		w := postBody(router, "/test", "application/json", strings.NewReader(`{"a":{"b":{"c":{"d":1}}}}`))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "nested too deeply")
+		assert.Contains(t, w.Body.String(), `"code":"REQUEST_BODY_TOO_DEEP"`)
+	})
+
+	t.Run("should reject deeply nested bodies of any content type", func(t *testing.T) {
+		w := postBody(router, "/test", "text/plain", strings.NewReader(`{"a":{"b":{"c":{"d":1}}}}`))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "nested too deeply")
+	})
+
+	t.Run("should ignore brackets inside strings", func(t *testing.T) {
+		w := postBody(router, "/test", "application/json", strings.NewReader(`{"a":"[[[[{{{{\"]]]]"}`))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("should apply the route override", func(t *testing.T) {
+		body := bytes.Repeat([]byte("x"), 512)
+		w := postBody(router, "/upload/1", "application/octet-stream", bytes.NewReader(body))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		body = bytes.Repeat([]byte("x"), 2048)
+		w = postBody(router, "/upload/1", "application/octet-stream", bytes.NewReader(body))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("should cap multipart bodies while they are read", func(t *testing.T) {
+		body := bytes.Repeat([]byte("x"), 100)
+		w := postBody(router, "/test", "multipart/form-data; boundary=limite", chunkedReader{bytes.NewReader(body)})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "request body too large")
+	})
+}
@@ -1,14 +1,47 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"log"
+	"net"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sidot/backend/internal/models"
 )
 
+// tenantOriginsCacheTTL is how long the CORS origins resolved for a tenant slug are reused,
+// so changes to a tenant allowed_origins take effect within this interval
+const tenantOriginsCacheTTL = time.Minute
+
+// tenantOriginsCacheMaxEntries bounds the cached slugs, as the Host header is client controlled
+const tenantOriginsCacheMaxEntries = 1000
+
+// TenantOriginsResolver resolves the CORS origins of an active tenant by slug
+// (implemented by repository.TenantRepository)
+type TenantOriginsResolver interface {
+	GetAllowedOriginsBySlug(ctx context.Context, slug string) ([]string, error)
+}
+
 // CORS returns a middleware that handles Cross-Origin Resource Sharing
 func CORS(allowedOrigins []string) gin.HandlerFunc {
+	return TenantCORS(allowedOrigins, nil)
+}
+
+// TenantCORS returns a CORS middleware that also allows the origins registered for the tenant of the request.
+// The tenant is resolved from the slug subdomain of the request host (e.g. "ses-go" for ses-go.api.example.com),
+// since CORS runs before authentication. Requests without a known tenant fall back to the global list,
+// which stays allowed for every tenant. A nil resolver allows only the global list.
+func TenantCORS(allowedOrigins []string, resolver TenantOriginsResolver) gin.HandlerFunc {
+	var tenantOrigins *tenantOriginsCache
+	if resolver != nil {
+		tenantOrigins = newTenantOriginsCache(resolver, tenantOriginsCacheTTL)
+	}
+
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 
@@ -21,6 +54,12 @@ func CORS(allowedOrigins []string) gin.HandlerFunc {
 			}
 		}
 
+		if !allowed && origin != "" && tenantOrigins != nil {
+			if slug := TenantSlugFromHost(c.Request.Host); slug != "" {
+				allowed = originsContains(tenantOrigins.get(c.Request.Context(), slug), origin)
+			}
+		}
+
 		if allowed {
 			c.Header("Access-Control-Allow-Origin", origin)
 			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
@@ -28,6 +67,8 @@ func CORS(allowedOrigins []string) gin.HandlerFunc {
 			c.Header("Access-Control-Allow-Credentials", "true")
 			c.Header("Access-Control-Max-Age", "86400")
 		}
+		// The response depends on the Origin, so shared caches must not reuse it across origins
+		c.Header("Vary", "Origin")
 
 		// Handle preflight requests
 		if c.Request.Method == http.MethodOptions {
@@ -39,6 +80,23 @@ func CORS(allowedOrigins []string) gin.HandlerFunc {
 	}
 }
 
+// TenantSlugFromHost returns the tenant slug of a host, its first label when the host has a subdomain
+// ("ses-go.api.example.com:8080" -> "ses-go"), or "" for hosts without one such as localhost or IPs
+func TenantSlugFromHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" || net.ParseIP(host) != nil {
+		return ""
+	}
+
+	labels := strings.Split(strings.ToLower(host), ".")
+	if len(labels) < 3 {
+		return ""
+	}
+	return labels[0]
+}
+
 // originsContains checks if the origin is in the allowed list
 func originsContains(origins []string, origin string) bool {
 	for _, o := range origins {
@@ -48,3 +106,57 @@ func originsContains(origins []string, origin string) bool {
 	}
 	return false
 }
+
+// tenantOriginsCache caches the origins resolved per tenant slug, including unknown slugs,
+// so CORS does not query the database on every request
+type tenantOriginsCache struct {
+	resolver TenantOriginsResolver
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]tenantOriginsEntry
+}
+
+type tenantOriginsEntry struct {
+	origins   []string
+	expiresAt time.Time
+}
+
+func newTenantOriginsCache(resolver TenantOriginsResolver, ttl time.Duration) *tenantOriginsCache {
+	return &tenantOriginsCache{
+		resolver: resolver,
+		ttl:      ttl,
+		entries:  make(map[string]tenantOriginsEntry),
+	}
+}
+
+// get returns the origins of the tenant slug, resolving them when not cached or expired.
+// Resolver failures other than an unknown tenant are not cached, so the next request retries.
+func (c *tenantOriginsCache) get(ctx context.Context, slug string) []string {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[slug]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.origins
+	}
+
+	origins, err := c.resolver.GetAllowedOriginsBySlug(ctx, slug)
+	if err != nil {
+		if !errors.Is(err, models.ErrTenantNotFound) {
+			log.Printf("[CORS] Failed to resolve origins of tenant %s: %v", slug, err)
+			return nil
+		}
+		origins = nil
+	}
+
+	c.mu.Lock()
+	if len(c.entries) >= tenantOriginsCacheMaxEntries {
+		c.entries = make(map[string]tenantOriginsEntry)
+	}
+	c.entries[slug] = tenantOriginsEntry{origins: origins, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+
+	return origins
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sidot/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeOriginsResolver resolves tenant origins from a map and counts lookups
+type fakeOriginsResolver struct {
+	origins map[string][]string
+	err     error
+	calls   int
+}
+
+func (f *fakeOriginsResolver) GetAllowedOriginsBySlug(ctx context.Context, slug string) ([]string, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	origins, ok := f.origins[slug]
+	if !ok {
+		return nil, models.ErrTenantNotFound
+	}
+	return origins, nil
+}
+
+func newCORSRouter(global []string, resolver TenantOriginsResolver) *gin.Engine {
+	router := gin.New()
+	router.Use(TenantCORS(global, resolver))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func corsRequest(router *gin.Engine, method, host, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/test", nil)
+	req.Host = host
+	req.Header.Set("Origin", origin)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTenantCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	global := []string{"https://app.sidot.com.br"}
+	resolver := &fakeOriginsResolver{origins: map[string][]string{
+		"tenant-a": {"https://painel.tenant-a.gov.br"},
+		"tenant-b": {"https://painel.tenant-b.gov.br"},
+	}}
+	router := newCORSRouter(global, resolver)
+
+	t.Run("should allow an origin of the request tenant", func(t *testing.T) {
+		w := corsRequest(router, http.MethodGet, "tenant-a.api.sidot.com.br", "https://painel.tenant-a.gov.br")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "https://painel.tenant-a.gov.br", w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("should reject an origin of another tenant", func(t *testing.T) {
+		w := corsRequest(router, http.MethodOptions, "tenant-b.api.sidot.com.br", "https://painel.tenant-a.gov.br")
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("should allow global origins for every tenant", func(t *testing.T) {
+		for _, host := range []string{"tenant-a.api.sidot.com.br", "tenant-b.api.sidot.com.br"} {
+			w := corsRequest(router, http.MethodGet, host, "https://app.sidot.com.br")
+			assert.Equal(t, "https://app.sidot.com.br", w.Header().Get("Access-Control-Allow-Origin"), host)
+		}
+	})
+
+	t.Run("should fall back to global origins without a tenant subdomain", func(t *testing.T) {
+		w := corsRequest(router, http.MethodGet, "localhost:8080", "https://painel.tenant-a.gov.br")
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+		w = corsRequest(router, http.MethodGet, "unknown.api.sidot.com.br", "https://painel.tenant-a.gov.br")
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestTenantCORSCachesOrigins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	resolver := &fakeOriginsResolver{origins: map[string][]string{
+		"tenant-a": {"https://painel.tenant-a.gov.br"},
+	}}
+	router := newCORSRouter(nil, resolver)
+
+	for i := 0; i < 3; i++ {
+		corsRequest(router, http.MethodGet, "tenant-a.api.sidot.com.br", "https://painel.tenant-a.gov.br")
+		corsRequest(router, http.MethodGet, "unknown.api.sidot.com.br", "https://painel.tenant-a.gov.br")
+	}
+
+	assert.Equal(t, 2, resolver.calls, "known and unknown slugs should be resolved once")
+}
+
+func TestTenantCORSResolverError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	resolver := &fakeOriginsResolver{err: errors.New("connection refused")}
+	router := newCORSRouter([]string{"https://app.sidot.com.br"}, resolver)
+
+	w := corsRequest(router, http.MethodGet, "tenant-a.api.sidot.com.br", "https://painel.tenant-a.gov.br")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	// Failures are not cached
+	corsRequest(router, http.MethodGet, "tenant-a.api.sidot.com.br", "https://painel.tenant-a.gov.br")
+	assert.Equal(t, 2, resolver.calls)
+}
+
+func TestTenantSlugFromHost(t *testing.T) {
+	testCases := []struct {
+		host string
+		want string
+	}{
+		{"ses-go.api.sidot.com.br", "ses-go"},
+		{"SES-GO.api.sidot.com.br:443", "ses-go"},
+		{"ses-pe.sidot.com", "ses-pe"},
+		{"sidot.com", ""},
+		{"localhost:8080", ""},
+		{"10.0.0.1:8080", ""},
+		{"[::1]:8080", ""},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.host, func(t *testing.T) {
+			assert.Equal(t, tc.want, TenantSlugFromHost(tc.host))
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// ErrorResponse is the standard error envelope of the API
+type ErrorResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   string `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// Error repeats Message for clients that still read the former {"error": "..."} shape
+	Error string `json:"error"`
+}
+
+// AbortWithError aborts the request with the standard error envelope, including the ID set by RequestID
+func AbortWithError(c *gin.Context, status int, code, message, details string) {
+	c.AbortWithStatusJSON(status, ErrorResponse{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: c.GetString("request_id"),
+		Error:     message,
+	})
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PEPCertHospitalKey is the context key of the hospital authenticated by client certificate
+const PEPCertHospitalKey = "pep_cert_hospital_id"
+
+// PEPCertConfig configures the client-certificate (mTLS) authentication of PEP agents
+type PEPCertConfig struct {
+	// Fingerprints maps the SHA-256 fingerprint of an agent certificate to its hospital
+	Fingerprints map[string]uuid.UUID
+	// FingerprintHeader is set by a TLS-terminating proxy with the fingerprint of the client
+	// certificate it verified. When empty only certificates of a TLS connection to this server
+	// are trusted; when set, the proxy must overwrite any value sent by the client.
+	FingerprintHeader string
+}
+
+// PEPClientCert returns a middleware that authenticates PEP agents by client certificate.
+// A known certificate sets PEPCertHospitalKey and an unknown one is rejected with 401;
+// requests without a certificate continue to API-key authentication.
+func PEPClientCert(cfg PEPCertConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(cfg.Fingerprints) == 0 {
+			c.Next()
+			return
+		}
+
+		fingerprint := ""
+		if c.Request.TLS != nil && len(c.Request.TLS.PeerCertificates) > 0 {
+			fingerprint = CertificateFingerprint(c.Request.TLS.PeerCertificates[0])
+		} else if cfg.FingerprintHeader != "" {
+			fingerprint = NormalizeFingerprint(c.GetHeader(cfg.FingerprintHeader))
+		}
+		if fingerprint == "" {
+			c.Next()
+			return
+		}
+
+		hospitalID, ok := cfg.Fingerprints[fingerprint]
+		if !ok {
+			AbortWithError(c, http.StatusUnauthorized, "INVALID_CLIENT_CERT", "client certificate is not registered", "")
+			return
+		}
+
+		c.Set(PEPCertHospitalKey, hospitalID)
+		c.Next()
+	}
+}
+
+// CertificateFingerprint returns the SHA-256 fingerprint of a certificate in lowercase hex
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeFingerprint lowercases a fingerprint and removes its separators,
+// so "AB:CD:..." (openssl output) and "abcd..." are the same
+func NormalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(strings.TrimSpace(fingerprint)))
+}
+
+// ParsePEPCertFingerprints parses "fingerprint=hospital_uuid" entries
+func ParsePEPCertFingerprints(entries []string) (map[string]uuid.UUID, error) {
+	fingerprints := make(map[string]uuid.UUID)
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fingerprint, hospital, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid certificate entry %q: expected fingerprint=hospital_id", entry)
+		}
+		fingerprint = NormalizeFingerprint(fingerprint)
+		if decoded, err := hex.DecodeString(fingerprint); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("invalid certificate fingerprint %q: expected a SHA-256 hex digest", fingerprint)
+		}
+		hospitalID, err := uuid.Parse(strings.TrimSpace(hospital))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hospital id for certificate %s: %w", fingerprint, err)
+		}
+		fingerprints[fingerprint] = hospitalID
+	}
+	return fingerprints, nil
+}
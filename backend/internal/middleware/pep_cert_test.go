@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCertificate creates a self-signed agent certificate
+func newTestCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "pep-agent"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestPEPClientCert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cert := newTestCertificate(t)
+	hospitalID := uuid.New()
+	fingerprints, err := ParsePEPCertFingerprints([]string{CertificateFingerprint(cert) + "=" + hospitalID.String()})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(PEPClientCert(PEPCertConfig{Fingerprints: fingerprints, FingerprintHeader: "X-Client-Cert-Sha256"}))
+	router.GET("/pep/ping", func(c *gin.Context) {
+		value, ok := c.Get(PEPCertHospitalKey)
+		if !ok {
+			c.String(http.StatusOK, "api-key")
+			return
+		}
+		c.String(http.StatusOK, value.(uuid.UUID).String())
+	})
+
+	t.Run("should resolve the hospital of a TLS client certificate", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/pep/ping", nil)
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, hospitalID.String(), w.Body.String())
+	})
+
+	t.Run("should resolve the fingerprint forwarded by the proxy", func(t *testing.T) {
+		// openssl prints the fingerprint in uppercase with colons
+		var pairs []string
+		fingerprint := strings.ToUpper(CertificateFingerprint(cert))
+		for i := 0; i < len(fingerprint); i += 2 {
+			pairs = append(pairs, fingerprint[i:i+2])
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/pep/ping", nil)
+		req.Header.Set("X-Client-Cert-Sha256", strings.Join(pairs, ":"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, hospitalID.String(), w.Body.String())
+	})
+
+	t.Run("should reject an unknown certificate", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/pep/ping", nil)
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{newTestCertificate(t)}}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_CLIENT_CERT")
+	})
+
+	t.Run("should fall back to API key without a certificate", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pep/ping", nil))
+
+		assert.Equal(t, "api-key", w.Body.String())
+	})
+}
+
+func TestParsePEPCertFingerprints(t *testing.T) {
+	fingerprint := strings.Repeat("ab", 32)
+	hospitalID := uuid.New()
+
+	fingerprints, err := ParsePEPCertFingerprints([]string{" AB:" + strings.Repeat("ab", 31) + " = " + hospitalID.String(), ""})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uuid.UUID{fingerprint: hospitalID}, fingerprints)
+
+	_, err = ParsePEPCertFingerprints([]string{fingerprint})
+	assert.Error(t, err, "missing hospital")
+	_, err = ParsePEPCertFingerprints([]string{"abcd=" + hospitalID.String()})
+	assert.Error(t, err, "short fingerprint")
+	_, err = ParsePEPCertFingerprints([]string{fingerprint + "=not-a-uuid"})
+	assert.Error(t, err)
+}
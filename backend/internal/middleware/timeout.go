@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutConfig configures the request timeouts
+type TimeoutConfig struct {
+	Timeout time.Duration // deadline of routes without an override
+	// GroupTimeouts overrides the deadline by route prefix (the longest matching prefix wins);
+	// a zero duration disables it, e.g. for SSE and WebSocket streams
+	GroupTimeouts map[string]time.Duration
+}
+
+// Timeout returns a middleware that gives each request context a deadline, so database calls
+// made with c.Request.Context() are cancelled when it expires. A request that exceeds its deadline
+// before writing a response gets 503; whatever the handler writes afterwards is discarded.
+func Timeout(cfg TimeoutConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := cfg.timeoutFor(c.FullPath())
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		writer := &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		if writer.timedOut() {
+			AbortWithError(c, http.StatusServiceUnavailable, "REQUEST_TIMEOUT", "request timed out",
+				"the request exceeded its deadline of "+timeout.String())
+		}
+	}
+}
+
+// timeoutFor returns the deadline of a route path
+func (cfg TimeoutConfig) timeoutFor(path string) time.Duration {
+	timeout := cfg.Timeout
+	longest := -1
+	for prefix, groupTimeout := range cfg.GroupTimeouts {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			timeout = groupTimeout
+			longest = len(prefix)
+		}
+	}
+	return timeout
+}
+
+// timeoutWriter discards the response once the request deadline has passed without a response,
+// so the middleware can reply 503 instead of the error the handler got from its cancelled calls
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx context.Context
+}
+
+// timedOut reports whether the deadline passed before anything was written
+func (w *timeoutWriter) timedOut() bool {
+	return errors.Is(w.ctx.Err(), context.DeadlineExceeded) && !w.ResponseWriter.Written()
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if w.timedOut() {
+		return 0, w.ctx.Err()
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if w.timedOut() {
+		return 0, w.ctx.Err()
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.timedOut() {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
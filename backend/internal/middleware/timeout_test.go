@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var handlerErr error
+	router := gin.New()
+	router.Use(Timeout(TimeoutConfig{
+		Timeout: 50 * time.Millisecond,
+		GroupTimeouts: map[string]time.Duration{
+			"/stream":  0,
+			"/reports": time.Second,
+		},
+	}))
+
+	// slow simulates a query that honours the request context, failing with the context error
+	slow := func(c *gin.Context) {
+		select {
+		case <-time.After(2 * time.Second):
+			c.JSON(http.StatusOK, gin.H{"status": "done"})
+		case <-c.Request.Context().Done():
+			handlerErr = c.Request.Context().Err()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to query"})
+		}
+	}
+	router.GET("/slow", slow)
+	router.GET("/fast", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/stream/events", func(c *gin.Context) {
+		_, hasDeadline := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"has_deadline": hasDeadline})
+	})
+	router.GET("/reports/csv", func(c *gin.Context) {
+		time.Sleep(100 * time.Millisecond)
+		c.JSON(http.StatusOK, gin.H{"err": c.Request.Context().Err() != nil})
+	})
+
+	t.Run("should cancel a slow handler at the deadline", func(t *testing.T) {
+		started := time.Now()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), "REQUEST_TIMEOUT")
+		assert.NotContains(t, w.Body.String(), "failed to query")
+		assert.ErrorIs(t, handlerErr, context.DeadlineExceeded)
+		assert.Less(t, time.Since(started), time.Second)
+	})
+
+	t.Run("should pass a request within the deadline", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+	})
+
+	t.Run("should not set a deadline on excluded groups", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream/events", nil))
+
+		assert.JSONEq(t, `{"has_deadline":false}`, w.Body.String())
+	})
+
+	t.Run("should apply the group override", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reports/csv", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"err":false}`, w.Body.String())
+	})
+}
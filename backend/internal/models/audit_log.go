@@ -1,7 +1,10 @@
 package models
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -76,13 +79,66 @@ type AuditLogFilter struct {
 	DataInicio   *time.Time `json:"data_inicio,omitempty"`
 	DataFim      *time.Time `json:"data_fim,omitempty"`
 	UsuarioID    *uuid.UUID `json:"usuario_id,omitempty"`
+	ActorName    *string    `json:"actor_name,omitempty"` // case-insensitive partial match
 	Acao         *string    `json:"acao,omitempty"`
 	EntidadeTipo *string    `json:"entidade_tipo,omitempty"`
 	EntidadeID   *string    `json:"entidade_id,omitempty"`
 	Severity     *Severity  `json:"severity,omitempty"`
 	HospitalID   *uuid.UUID `json:"hospital_id,omitempty"`
+	IPAddress    *string    `json:"ip_address,omitempty"`
 	Page         int        `json:"page"`
 	PageSize     int        `json:"page_size"`
+
+	// Cursor switches to keyset pagination: entries strictly after it are returned and Page is ignored
+	Cursor *AuditLogCursor `json:"-"`
+}
+
+// ErrInvalidAuditLogCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidAuditLogCursor = errors.New("invalid audit log cursor")
+
+// AuditLogCursor marks a position in the audit log, which is iterated by (timestamp DESC, id DESC)
+// Unlike offsets, a cursor stays valid while new entries are inserted at the head of the log
+type AuditLogCursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
+}
+
+// Encode returns the opaque next_cursor token for the cursor
+func (c *AuditLogCursor) Encode() string {
+	raw := c.Timestamp.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseAuditLogCursor decodes a token produced by AuditLogCursor.Encode
+func ParseAuditLogCursor(token string) (*AuditLogCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidAuditLogCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidAuditLogCursor
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, ErrInvalidAuditLogCursor
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, ErrInvalidAuditLogCursor
+	}
+
+	return &AuditLogCursor{Timestamp: timestamp, ID: id}, nil
+}
+
+// CursorPaginatedResponse represents a keyset-paginated API response
+type CursorPaginatedResponse struct {
+	Data       interface{} `json:"data"`
+	PageSize   int         `json:"page_size"`
+	NextCursor *string     `json:"next_cursor"`
+	HasNext    bool        `json:"has_next"`
 }
 
 // DefaultAuditLogFilters returns default filters for audit logs
@@ -136,6 +192,7 @@ const (
 	ActionAuthLogin       = "auth.login"
 	ActionAuthLogout      = "auth.logout"
 	ActionAuthLoginFailed = "auth.login_failed"
+	ActionAuthMFAEnabled  = "auth.mfa_enabled"
 
 	// Triagem rule actions
 	ActionRegraCreate = "regra.create"
@@ -147,17 +204,24 @@ const (
 	ActionOcorrenciaAceitar      = "ocorrencia.aceitar"
 	ActionOcorrenciaRecusar      = "ocorrencia.recusar"
 	ActionOcorrenciaStatusChange = "ocorrencia.status_change"
+	ActionOcorrenciaExportarPDF  = "ocorrencia.exportar_pdf"
+	ActionOcorrenciaAtribuir     = "ocorrencia.atribuir"
 	ActionTriagemRejeicao        = "triagem.rejeicao"
 
 	// User actions
 	ActionUsuarioCreate    = "usuario.create"
 	ActionUsuarioUpdate    = "usuario.update"
 	ActionUsuarioDesativar = "usuario.desativar"
+	ActionUsuarioExcluir   = "usuario.excluir"
+	ActionUsuarioRestaurar = "usuario.restaurar"
+	ActionUsuarioReenvio   = "usuario.reenviar_boas_vindas"
 
 	// Tenant actions
 	ActionTenantCreate        = "tenant.create"
 	ActionTenantUpdate        = "tenant.update"
 	ActionTenantContextSwitch = "tenant.context_switch"
+	ActionTenantExport        = "tenant.export"
+	ActionTenantPurge         = "tenant.purge"
 )
 
 // SIDOTBotActor is the name used for system actions
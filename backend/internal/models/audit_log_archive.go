@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLogArchive records an export of audit log entries removed after the retention period
+type AuditLogArchive struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	Location      string    `json:"location" db:"location"`
+	Entries       int       `json:"entries" db:"entries"`
+	FromTimestamp time.Time `json:"from_timestamp" db:"from_timestamp"`
+	ToTimestamp   time.Time `json:"to_timestamp" db:"to_timestamp"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAuditLogCursorRoundTrip(t *testing.T) {
+	cursor := &AuditLogCursor{
+		Timestamp: time.Date(2026, 10, 14, 9, 30, 15, 123456000, time.FixedZone("BRT", -3*3600)),
+		ID:        uuid.New(),
+	}
+
+	parsed, err := ParseAuditLogCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("ParseAuditLogCursor returned error: %v", err)
+	}
+	if !parsed.Timestamp.Equal(cursor.Timestamp) || parsed.ID != cursor.ID {
+		t.Errorf("Expected %v/%s, got %v/%s", cursor.Timestamp, cursor.ID, parsed.Timestamp, parsed.ID)
+	}
+}
+
+func TestParseAuditLogCursorInvalid(t *testing.T) {
+	for _, token := range []string{"not base64!", "bm8tc2VwYXJhdG9y", "MjAyNi0xMC0xNHxub3QtYS11dWlk"} {
+		if _, err := ParseAuditLogCursor(token); err != ErrInvalidAuditLogCursor {
+			t.Errorf("ParseAuditLogCursor(%q) error = %v, expected ErrInvalidAuditLogCursor", token, err)
+		}
+	}
+}
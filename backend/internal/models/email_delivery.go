@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailDeliveryStatus represents the delivery status of a queued email
+type EmailDeliveryStatus string
+
+const (
+	EmailDeliveryQueued EmailDeliveryStatus = "queued" // waiting in the queue
+	EmailDeliverySent   EmailDeliveryStatus = "sent"
+	EmailDeliveryFailed EmailDeliveryStatus = "failed" // last attempt failed, retried while NextRetryAt is set
+)
+
+// IsValid checks if the status is a valid email delivery status
+func (s EmailDeliveryStatus) IsValid() bool {
+	switch s {
+	case EmailDeliveryQueued, EmailDeliverySent, EmailDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// EmailDelivery records the delivery of one queued email
+type EmailDelivery struct {
+	ID            uuid.UUID           `json:"id" db:"id"` // email queue item ID
+	OccurrenceID  *uuid.UUID          `json:"occurrence_id,omitempty" db:"occurrence_id"`
+	UserID        *uuid.UUID          `json:"user_id,omitempty" db:"user_id"`
+	Recipient     string              `json:"recipient" db:"recipient"`
+	Status        EmailDeliveryStatus `json:"status" db:"status"`
+	Attempts      int                 `json:"attempts" db:"attempts"`
+	LastError     *string             `json:"last_error,omitempty" db:"last_error"`
+	LastAttemptAt *time.Time          `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	NextRetryAt   *time.Time          `json:"next_retry_at,omitempty" db:"next_retry_at"`
+	CreatedAt     time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at" db:"updated_at"`
+}
+
+// EmailDeliveryFilters filters the email delivery list
+type EmailDeliveryFilters struct {
+	Status       *EmailDeliveryStatus
+	OccurrenceID *uuid.UUID
+	Recipient    string // exact address, case-insensitive
+	Page         int
+	PageSize     int
+}
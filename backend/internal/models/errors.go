@@ -9,4 +9,5 @@ var (
 	ErrInvalidEndTime   = errors.New("end_time must be in HH:MM format")
 	ErrShiftNotFound    = errors.New("shift not found")
 	ErrShiftExists      = errors.New("shift already exists for this user on this day at this time")
+	ErrShiftOverlap     = errors.New("shift overlaps an existing shift for this user")
 )
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,35 +10,41 @@ import (
 
 // Hospital represents a hospital integrated with SIDOT
 type Hospital struct {
-	ID            uuid.UUID       `json:"id" db:"id"`
-	TenantID      uuid.UUID       `json:"tenant_id" db:"tenant_id"`
-	Nome          string          `json:"nome" db:"nome" validate:"required,min=2,max=255"`
-	Codigo        string          `json:"codigo" db:"codigo" validate:"required,min=2,max=50"`
-	Endereco      *string         `json:"endereco,omitempty" db:"endereco"`
-	Telefone      *string         `json:"telefone,omitempty" db:"telefone" validate:"omitempty,max=20"`
-	Latitude      *float64        `json:"latitude,omitempty" db:"latitude"`
-	Longitude     *float64        `json:"longitude,omitempty" db:"longitude"`
-	ConfigConexao json.RawMessage `json:"config_conexao,omitempty" db:"config_conexao"`
-	Ativo         bool            `json:"ativo" db:"ativo"`
-	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
-	DeletedAt     *time.Time      `json:"deleted_at,omitempty" db:"deleted_at"`
+	ID              uuid.UUID       `json:"id" db:"id"`
+	TenantID        uuid.UUID       `json:"tenant_id" db:"tenant_id"`
+	Nome            string          `json:"nome" db:"nome" validate:"required,min=2,max=255"`
+	Codigo          string          `json:"codigo" db:"codigo" validate:"required,min=2,max=50"`
+	Endereco        *string         `json:"endereco,omitempty" db:"endereco"`
+	Telefone        *string         `json:"telefone,omitempty" db:"telefone" validate:"omitempty,max=20"`
+	Email           *string         `json:"email,omitempty" db:"email" validate:"omitempty,email,max=255"`
+	EscalationEmail *string         `json:"escalation_email,omitempty" db:"escalation_email" validate:"omitempty,email,max=255"`
+	Latitude        *float64        `json:"latitude,omitempty" db:"latitude"`
+	Longitude       *float64        `json:"longitude,omitempty" db:"longitude"`
+	ConfigConexao   json.RawMessage `json:"config_conexao,omitempty" db:"config_conexao"`
+	Timezone        *string         `json:"timezone,omitempty" db:"timezone"`
+	Ativo           bool            `json:"ativo" db:"ativo"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+	DeletedAt       *time.Time      `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 // HospitalWithTenant extends Hospital with tenant information for admin views
 type HospitalWithTenant struct {
-	ID            uuid.UUID       `json:"id" db:"id"`
-	TenantID      uuid.UUID       `json:"tenant_id" db:"tenant_id"`
-	Nome          string          `json:"nome" db:"nome"`
-	Codigo        string          `json:"codigo" db:"codigo"`
-	Endereco      *string         `json:"endereco,omitempty" db:"endereco"`
-	Telefone      *string         `json:"telefone,omitempty" db:"telefone"`
-	Latitude      *float64        `json:"latitude,omitempty" db:"latitude"`
-	Longitude     *float64        `json:"longitude,omitempty" db:"longitude"`
-	ConfigConexao json.RawMessage `json:"config_conexao,omitempty" db:"config_conexao"`
-	Ativo         bool            `json:"ativo" db:"ativo"`
-	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID       `json:"id" db:"id"`
+	TenantID        uuid.UUID       `json:"tenant_id" db:"tenant_id"`
+	Nome            string          `json:"nome" db:"nome"`
+	Codigo          string          `json:"codigo" db:"codigo"`
+	Endereco        *string         `json:"endereco,omitempty" db:"endereco"`
+	Telefone        *string         `json:"telefone,omitempty" db:"telefone"`
+	Email           *string         `json:"email,omitempty" db:"email"`
+	EscalationEmail *string         `json:"escalation_email,omitempty" db:"escalation_email"`
+	Latitude        *float64        `json:"latitude,omitempty" db:"latitude"`
+	Longitude       *float64        `json:"longitude,omitempty" db:"longitude"`
+	ConfigConexao   json.RawMessage `json:"config_conexao,omitempty" db:"config_conexao"`
+	Ativo           bool            `json:"ativo" db:"ativo"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+	DeletedAt       *time.Time      `json:"deleted_at,omitempty" db:"deleted_at"`
 
 	// Tenant info (populated by admin queries)
 	TenantName *string `json:"tenant_name,omitempty" db:"tenant_name"`
@@ -47,37 +54,43 @@ type HospitalWithTenant struct {
 // ToResponse converts HospitalWithTenant to HospitalWithTenantResponse
 func (h *HospitalWithTenant) ToResponse() HospitalWithTenantResponse {
 	return HospitalWithTenantResponse{
-		ID:         h.ID,
-		TenantID:   h.TenantID,
-		Nome:       h.Nome,
-		Codigo:     h.Codigo,
-		Endereco:   h.Endereco,
-		Telefone:   h.Telefone,
-		Latitude:   h.Latitude,
-		Longitude:  h.Longitude,
-		Ativo:      h.Ativo,
-		CreatedAt:  h.CreatedAt,
-		UpdatedAt:  h.UpdatedAt,
-		TenantName: h.TenantName,
-		TenantSlug: h.TenantSlug,
+		ID:              h.ID,
+		TenantID:        h.TenantID,
+		Nome:            h.Nome,
+		Codigo:          h.Codigo,
+		Endereco:        h.Endereco,
+		Telefone:        h.Telefone,
+		Email:           h.Email,
+		EscalationEmail: h.EscalationEmail,
+		Latitude:        h.Latitude,
+		Longitude:       h.Longitude,
+		Ativo:           h.Ativo,
+		CreatedAt:       h.CreatedAt,
+		UpdatedAt:       h.UpdatedAt,
+		DeletedAt:       h.DeletedAt,
+		TenantName:      h.TenantName,
+		TenantSlug:      h.TenantSlug,
 	}
 }
 
 // HospitalWithTenantResponse represents the API response for a hospital with tenant info
 type HospitalWithTenantResponse struct {
-	ID         uuid.UUID `json:"id"`
-	TenantID   uuid.UUID `json:"tenant_id"`
-	Nome       string    `json:"nome"`
-	Codigo     string    `json:"codigo"`
-	Endereco   *string   `json:"endereco,omitempty"`
-	Telefone   *string   `json:"telefone,omitempty"`
-	Latitude   *float64  `json:"latitude,omitempty"`
-	Longitude  *float64  `json:"longitude,omitempty"`
-	Ativo      bool      `json:"ativo"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	TenantName *string   `json:"tenant_name,omitempty"`
-	TenantSlug *string   `json:"tenant_slug,omitempty"`
+	ID              uuid.UUID  `json:"id"`
+	TenantID        uuid.UUID  `json:"tenant_id"`
+	Nome            string     `json:"nome"`
+	Codigo          string     `json:"codigo"`
+	Endereco        *string    `json:"endereco,omitempty"`
+	Telefone        *string    `json:"telefone,omitempty"`
+	Email           *string    `json:"email,omitempty"`
+	EscalationEmail *string    `json:"escalation_email,omitempty"`
+	Latitude        *float64   `json:"latitude,omitempty"`
+	Longitude       *float64   `json:"longitude,omitempty"`
+	Ativo           bool       `json:"ativo"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+	TenantName      *string    `json:"tenant_name,omitempty"`
+	TenantSlug      *string    `json:"tenant_slug,omitempty"`
 }
 
 // AdminReassignHospitalInput represents input for reassigning a hospital to a different tenant
@@ -85,6 +98,12 @@ type AdminReassignHospitalInput struct {
 	TenantID uuid.UUID `json:"tenant_id" validate:"required"`
 }
 
+// AdminReassignTenantHospitalsInput represents input for moving all hospitals of a tenant to another tenant
+type AdminReassignTenantHospitalsInput struct {
+	TenantID  uuid.UUID `json:"tenant_id" validate:"required"`
+	MoveUsers bool      `json:"move_users"`
+}
+
 // HospitalConfig represents the connection configuration for a hospital
 type HospitalConfig struct {
 	Tipo         string `json:"tipo,omitempty"`          // "simulado", "hl7", "fhir"
@@ -96,55 +115,67 @@ type HospitalConfig struct {
 
 // CreateHospitalInput represents input for creating a hospital
 type CreateHospitalInput struct {
-	Nome          string          `json:"nome" validate:"required,min=2,max=255"`
-	Codigo        string          `json:"codigo" validate:"required,min=2,max=50,alphanum"`
-	Endereco      string          `json:"endereco" validate:"required,max=500"`
-	Telefone      *string         `json:"telefone,omitempty" validate:"omitempty,max=20"`
-	Latitude      float64         `json:"latitude" validate:"required,min=-90,max=90"`
-	Longitude     float64         `json:"longitude" validate:"required,min=-180,max=180"`
-	ConfigConexao json.RawMessage `json:"config_conexao,omitempty"`
-	Ativo         *bool           `json:"ativo,omitempty"`
+	Nome            string          `json:"nome" validate:"required,min=2,max=255"`
+	Codigo          string          `json:"codigo" validate:"required,min=2,max=50,alphanum"`
+	Endereco        string          `json:"endereco" validate:"required,max=500"`
+	Telefone        *string         `json:"telefone,omitempty" validate:"omitempty,max=20"`
+	Email           *string         `json:"email,omitempty" validate:"omitempty,email,max=255"`
+	EscalationEmail *string         `json:"escalation_email,omitempty" validate:"omitempty,email,max=255"`
+	Latitude        float64         `json:"latitude" validate:"required,min=-90,max=90"`
+	Longitude       float64         `json:"longitude" validate:"required,min=-180,max=180"`
+	ConfigConexao   json.RawMessage `json:"config_conexao,omitempty"`
+	Timezone        *string         `json:"timezone,omitempty" validate:"omitempty,max=64"`
+	Ativo           *bool           `json:"ativo,omitempty"`
 }
 
 // UpdateHospitalInput represents input for updating a hospital
 type UpdateHospitalInput struct {
-	Nome          *string         `json:"nome,omitempty" validate:"omitempty,min=2,max=255"`
-	Codigo        *string         `json:"codigo,omitempty" validate:"omitempty,min=2,max=50,alphanum"`
-	Endereco      *string         `json:"endereco,omitempty" validate:"omitempty,max=500"`
-	Telefone      *string         `json:"telefone,omitempty" validate:"omitempty,max=20"`
-	Latitude      *float64        `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
-	Longitude     *float64        `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
-	ConfigConexao json.RawMessage `json:"config_conexao,omitempty"`
-	Ativo         *bool           `json:"ativo,omitempty"`
+	Nome            *string         `json:"nome,omitempty" validate:"omitempty,min=2,max=255"`
+	Codigo          *string         `json:"codigo,omitempty" validate:"omitempty,min=2,max=50,alphanum"`
+	Endereco        *string         `json:"endereco,omitempty" validate:"omitempty,max=500"`
+	Telefone        *string         `json:"telefone,omitempty" validate:"omitempty,max=20"`
+	Email           *string         `json:"email,omitempty" validate:"omitempty,email,max=255"`
+	EscalationEmail *string         `json:"escalation_email,omitempty" validate:"omitempty,email,max=255"`
+	Latitude        *float64        `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
+	Longitude       *float64        `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
+	ConfigConexao   json.RawMessage `json:"config_conexao,omitempty"`
+	Timezone        *string         `json:"timezone,omitempty" validate:"omitempty,max=64"`
+	Ativo           *bool           `json:"ativo,omitempty"`
 }
 
 // HospitalResponse represents the API response for a hospital
 type HospitalResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Nome      string    `json:"nome"`
-	Codigo    string    `json:"codigo"`
-	Endereco  *string   `json:"endereco,omitempty"`
-	Telefone  *string   `json:"telefone,omitempty"`
-	Latitude  *float64  `json:"latitude,omitempty"`
-	Longitude *float64  `json:"longitude,omitempty"`
-	Ativo     bool      `json:"ativo"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              uuid.UUID `json:"id"`
+	Nome            string    `json:"nome"`
+	Codigo          string    `json:"codigo"`
+	Endereco        *string   `json:"endereco,omitempty"`
+	Telefone        *string   `json:"telefone,omitempty"`
+	Email           *string   `json:"email,omitempty"`
+	EscalationEmail *string   `json:"escalation_email,omitempty"`
+	Latitude        *float64  `json:"latitude,omitempty"`
+	Longitude       *float64  `json:"longitude,omitempty"`
+	Timezone        string    `json:"timezone"`
+	Ativo           bool      `json:"ativo"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // ToResponse converts Hospital to HospitalResponse
 func (h *Hospital) ToResponse() HospitalResponse {
 	return HospitalResponse{
-		ID:        h.ID,
-		Nome:      h.Nome,
-		Codigo:    h.Codigo,
-		Endereco:  h.Endereco,
-		Telefone:  h.Telefone,
-		Latitude:  h.Latitude,
-		Longitude: h.Longitude,
-		Ativo:     h.Ativo,
-		CreatedAt: h.CreatedAt,
-		UpdatedAt: h.UpdatedAt,
+		ID:              h.ID,
+		Nome:            h.Nome,
+		Codigo:          h.Codigo,
+		Endereco:        h.Endereco,
+		Telefone:        h.Telefone,
+		Email:           h.Email,
+		EscalationEmail: h.EscalationEmail,
+		Latitude:        h.Latitude,
+		Longitude:       h.Longitude,
+		Timezone:        h.Location().String(),
+		Ativo:           h.Ativo,
+		CreatedAt:       h.CreatedAt,
+		UpdatedAt:       h.UpdatedAt,
 	}
 }
 
@@ -157,3 +188,54 @@ func (h *Hospital) IsActive() bool {
 func (h *Hospital) HasCoordinates() bool {
 	return h.Latitude != nil && h.Longitude != nil
 }
+
+// ErrInvalidTimezone is returned when a timezone is not a known IANA name
+var ErrInvalidTimezone = errors.New("timezone must be an IANA name such as America/Sao_Paulo")
+
+// defaultLocation is the timezone of hospitals without one (SYSTEM_TIMEZONE)
+var defaultLocation = time.Local
+
+// SetDefaultTimezone sets the timezone used for hospitals without one
+func SetDefaultTimezone(name string) error {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return ErrInvalidTimezone
+	}
+	defaultLocation = loc
+	return nil
+}
+
+// DefaultLocation returns the timezone used for hospitals without one
+func DefaultLocation() *time.Location {
+	return defaultLocation
+}
+
+// ValidateTimezone checks that the timezone of a hospital input is a known IANA name
+// An empty name is valid and resets the hospital to the system timezone
+func ValidateTimezone(timezone *string) error {
+	if timezone == nil || *timezone == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(*timezone); err != nil {
+		return ErrInvalidTimezone
+	}
+	return nil
+}
+
+// HospitalLocation returns the location of a hospital timezone, or the system timezone
+// when it is not set or unknown
+func HospitalLocation(timezone *string) *time.Location {
+	if timezone == nil || *timezone == "" {
+		return defaultLocation
+	}
+	loc, err := time.LoadLocation(*timezone)
+	if err != nil {
+		return defaultLocation
+	}
+	return loc
+}
+
+// Location returns the timezone shift times of the hospital are expressed in
+func (h *Hospital) Location() *time.Location {
+	return HospitalLocation(h.Timezone)
+}
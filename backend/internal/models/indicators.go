@@ -56,6 +56,91 @@ type IndicatorsMetrics struct {
 	Series30Dias            Series30Dias     `json:"series_30_dias"`
 	RankingHospitais        RankingHospitais `json:"ranking_hospitais"`
 	UltimaAtualizacao       time.Time        `json:"ultima_atualizacao"`
+	Breakdown               *IndicatorsBreakdown `json:"breakdown,omitempty"`
+	Funil                   *ConversionFunnel    `json:"funil,omitempty"`
+}
+
+// IndicatorsGroupBy is the grouping of the indicators breakdown
+type IndicatorsGroupBy string
+
+const (
+	IndicatorsGroupByHospital IndicatorsGroupBy = "hospital"
+	IndicatorsGroupByDay      IndicatorsGroupBy = "day"
+	IndicatorsGroupByWeek     IndicatorsGroupBy = "week"
+)
+
+// IsValid checks if the grouping is supported
+func (g IndicatorsGroupBy) IsValid() bool {
+	return g == IndicatorsGroupByHospital || g == IndicatorsGroupByDay || g == IndicatorsGroupByWeek
+}
+
+// BreakdownItem represents the conversion funnel (eligible -> accepted -> captured) of one hospital or time bucket
+type BreakdownItem struct {
+	Chave         string     `json:"chave"` // Hospital ID, or bucket start date in YYYY-MM-DD format
+	HospitalID    *uuid.UUID `json:"hospital_id,omitempty"`
+	Nome          string     `json:"nome,omitempty"`
+	Elegiveis     int        `json:"elegiveis"`
+	Aceitas       int        `json:"aceitas"`
+	Captadas      int        `json:"captadas"`
+	TaxaAceitacao float64    `json:"taxa_aceitacao"` // Aceitas / Elegiveis (%)
+	TaxaCaptacao  float64    `json:"taxa_captacao"`  // Captadas / Aceitas (%)
+	TaxaConversao float64    `json:"taxa_conversao"` // Captadas / Elegiveis (%)
+}
+
+// IndicatorsBreakdown represents the indicators of the last 30 days grouped by hospital, day or week
+type IndicatorsBreakdown struct {
+	GroupBy IndicatorsGroupBy `json:"group_by"`
+	Itens   []BreakdownItem   `json:"itens"`
+}
+
+// CalculateRates fills the conversion rates of the item from its counts
+func (b *BreakdownItem) CalculateRates() {
+	b.TaxaAceitacao = ratePercent(b.Aceitas, b.Elegiveis)
+	b.TaxaCaptacao = ratePercent(b.Captadas, b.Aceitas)
+	b.TaxaConversao = ratePercent(b.Captadas, b.Elegiveis)
+}
+
+// FunnelStages counts the donation conversion funnel: detected obitos -> eligible occurrences -> accepted -> captured
+type FunnelStages struct {
+	ObitosDetectados  int     `json:"obitos_detectados"`
+	Elegiveis         int     `json:"elegiveis"`
+	Aceitas           int     `json:"aceitas"`
+	Captadas          int     `json:"captadas"`
+	TaxaElegibilidade float64 `json:"taxa_elegibilidade"` // Elegiveis / ObitosDetectados (%)
+	TaxaAceitacao     float64 `json:"taxa_aceitacao"`     // Aceitas / Elegiveis (%)
+	TaxaCaptacao      float64 `json:"taxa_captacao"`      // Captadas / Aceitas (%)
+	TaxaConversao     float64 `json:"taxa_conversao"`     // Captadas / ObitosDetectados (%)
+}
+
+// CalculateRates fills the conversion rates of each stage from the counts
+func (f *FunnelStages) CalculateRates() {
+	f.TaxaElegibilidade = ratePercent(f.Elegiveis, f.ObitosDetectados)
+	f.TaxaAceitacao = ratePercent(f.Aceitas, f.Elegiveis)
+	f.TaxaCaptacao = ratePercent(f.Captadas, f.Aceitas)
+	f.TaxaConversao = ratePercent(f.Captadas, f.ObitosDetectados)
+}
+
+// HospitalFunnel is the conversion funnel of one hospital
+type HospitalFunnel struct {
+	HospitalID uuid.UUID `json:"hospital_id"`
+	Nome       string    `json:"nome"`
+	FunnelStages
+}
+
+// ConversionFunnel is the conversion funnel of the obitos detected within a date range, in total and per hospital
+type ConversionFunnel struct {
+	DataInicio string           `json:"data_inicio"` // YYYY-MM-DD
+	DataFim    string           `json:"data_fim"`    // YYYY-MM-DD, inclusive
+	Total      FunnelStages     `json:"total"`
+	Hospitais  []HospitalFunnel `json:"hospitais"`
+}
+
+// ratePercent returns part / total as a percentage, or 0 when total is 0
+func ratePercent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
 }
 
 // Threshold constants for visual indicators
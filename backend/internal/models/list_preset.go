@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// List preset errors
+var (
+	ErrListPresetNotFound     = errors.New("list preset not found")
+	ErrListPresetNameRequired = errors.New("list preset name is required")
+	ErrListPresetNameTooLong  = fmt.Errorf("list preset name must have at most %d characters", MaxListPresetNameLength)
+	ErrListPresetUnknownParam = errors.New("list preset has a parameter the list does not accept")
+)
+
+// MaxListPresetNameLength caps the length of a list preset name
+const MaxListPresetNameLength = 100
+
+// ListPresetOccurrences is the list of the occurrence presets
+const ListPresetOccurrences = "occurrences"
+
+// OccurrenceListPresetParams are the occurrence list query parameters a preset can store
+// Pagination other than the page size is left to the request
+var OccurrenceListPresetParams = map[string]bool{
+	"status":      true,
+	"hospital_id": true,
+	"date_from":   true,
+	"date_to":     true,
+	"q":           true,
+	"sort":        true,
+	"sort_by":     true,
+	"sort_order":  true,
+	"page_size":   true,
+}
+
+// ListPreset is a named set of filter and sort query parameters of a list, saved by a user
+type ListPreset struct {
+	ID        uuid.UUID         `json:"id"`
+	UserID    uuid.UUID         `json:"user_id"`
+	List      string            `json:"list"`
+	Nome      string            `json:"nome"`
+	Params    map[string]string `json:"params"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// SaveListPresetInput is the input to save a list preset, replacing the preset of the same name
+type SaveListPresetInput struct {
+	Nome   string            `json:"nome"`
+	Params map[string]string `json:"params"`
+}
+
+// Validate validates the name and checks the params against the parameters of the list
+func (i *SaveListPresetInput) Validate(allowed map[string]bool) error {
+	i.Nome = strings.TrimSpace(i.Nome)
+	if i.Nome == "" {
+		return ErrListPresetNameRequired
+	}
+	if len([]rune(i.Nome)) > MaxListPresetNameLength {
+		return ErrListPresetNameTooLong
+	}
+	for key := range i.Params {
+		if !allowed[key] {
+			return fmt.Errorf("%w: %s", ErrListPresetUnknownParam, key)
+		}
+	}
+	return nil
+}
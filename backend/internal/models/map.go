@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,6 +35,9 @@ type MapHospitalResponse struct {
 	OcorrenciasCount int                   `json:"ocorrencias_count"`
 	Ocorrencias      []MapOccurrenceResponse `json:"ocorrencias,omitempty"`
 	OperadorPlantao  *MapOperatorResponse  `json:"operador_plantao,omitempty"`
+	// Distancia em linha reta ate o ponto de referencia (from_lat/from_lng) e tempo estimado de transporte
+	DistanciaKm          *float64 `json:"distancia_km,omitempty"`
+	TempoEstimadoMinutos *int     `json:"tempo_estimado_minutos,omitempty"`
 }
 
 // MapOccurrenceResponse representa os dados de uma ocorrencia para o mapa
@@ -54,47 +58,90 @@ type MapOperatorResponse struct {
 	UserID uuid.UUID `json:"user_id"`
 }
 
+// MapClusterResponse representa um agrupamento de hospitais proximos no mapa
+// Clusters com um unico hospital trazem o detalhe completo em Hospital
+type MapClusterResponse struct {
+	ID               string               `json:"id"`
+	Latitude         float64              `json:"latitude"`
+	Longitude        float64              `json:"longitude"`
+	HospitalCount    int                  `json:"hospital_count"`
+	HospitalIDs      []uuid.UUID          `json:"hospital_ids"`
+	OcorrenciasCount int                  `json:"ocorrencias_count"`
+	UrgenciaMaxima   UrgencyLevel         `json:"urgencia_maxima"`
+	Hospital         *MapHospitalResponse `json:"hospital,omitempty"`
+}
+
 // MapDataResponse representa a resposta completa do endpoint do mapa
 type MapDataResponse struct {
 	Hospitals []MapHospitalResponse `json:"hospitals"`
 	Total     int                   `json:"total"`
+	Clusters  []MapClusterResponse  `json:"clusters,omitempty"`
+}
+
+// Faixas de urgencia padrao: <2h vermelho, 2-4h amarelo, >4h verde
+const (
+	DefaultUrgencyRedMinutes    = 120
+	DefaultUrgencyYellowMinutes = 240
+)
+
+// UrgencyThresholds define as faixas de tempo restante de cada nivel de urgencia
+// Abaixo de RedMinutes e vermelho, abaixo de YellowMinutes amarelo, e a partir dai verde
+type UrgencyThresholds struct {
+	RedMinutes    int `json:"red_minutes"`
+	YellowMinutes int `json:"yellow_minutes"`
+}
+
+// DefaultUrgencyThresholds retorna as faixas aplicadas sem configuracao do tenant
+func DefaultUrgencyThresholds() UrgencyThresholds {
+	return UrgencyThresholds{
+		RedMinutes:    DefaultUrgencyRedMinutes,
+		YellowMinutes: DefaultUrgencyYellowMinutes,
+	}
+}
+
+// Validate valida que 0 < red_minutes < yellow_minutes
+func (t UrgencyThresholds) Validate() error {
+	if t.RedMinutes <= 0 || t.YellowMinutes <= t.RedMinutes {
+		return errors.New("urgency thresholds must have 0 < red_minutes < yellow_minutes")
+	}
+	return nil
 }
 
 // CalculateUrgencyLevel calcula o nivel de urgencia baseado no tempo restante em minutos
-// >4h (>240min) = verde, 2-4h (120-240min) = amarelo, <2h (<120min) = vermelho
-func CalculateUrgencyLevel(tempoRestanteMinutos int) UrgencyLevel {
+// Com as faixas padrao: >4h (>240min) = verde, 2-4h (120-240min) = amarelo, <2h (<120min) = vermelho
+func CalculateUrgencyLevel(tempoRestanteMinutos int, thresholds UrgencyThresholds) UrgencyLevel {
 	if tempoRestanteMinutos <= 0 {
 		return UrgencyRed
 	}
-	if tempoRestanteMinutos < 120 { // < 2 horas
+	if tempoRestanteMinutos < thresholds.RedMinutes {
 		return UrgencyRed
 	}
-	if tempoRestanteMinutos < 240 { // 2-4 horas
+	if tempoRestanteMinutos < thresholds.YellowMinutes {
 		return UrgencyYellow
 	}
-	return UrgencyGreen // > 4 horas
+	return UrgencyGreen
 }
 
 // CalculateUrgencyFromExpiration calcula o nivel de urgencia baseado na data de expiracao
-func CalculateUrgencyFromExpiration(janelaExpiraEm time.Time) UrgencyLevel {
+func CalculateUrgencyFromExpiration(janelaExpiraEm time.Time, thresholds UrgencyThresholds) UrgencyLevel {
 	remaining := janelaExpiraEm.Sub(time.Now())
 	if remaining <= 0 {
 		return UrgencyRed
 	}
 	minutes := int(remaining.Minutes())
-	return CalculateUrgencyLevel(minutes)
+	return CalculateUrgencyLevel(minutes, thresholds)
 }
 
 // CalculateMaxUrgency calcula a urgencia maxima de uma lista de ocorrencias
 // Retorna o nivel mais critico (vermelho > amarelo > verde > none)
-func CalculateMaxUrgency(occurrences []Occurrence) UrgencyLevel {
+func CalculateMaxUrgency(occurrences []Occurrence, thresholds UrgencyThresholds) UrgencyLevel {
 	if len(occurrences) == 0 {
 		return UrgencyNone
 	}
 
 	maxUrgency := UrgencyGreen
 	for _, o := range occurrences {
-		urgency := CalculateUrgencyFromExpiration(o.JanelaExpiraEm)
+		urgency := CalculateUrgencyFromExpiration(o.JanelaExpiraEm, thresholds)
 
 		// Prioridade: Red > Yellow > Green
 		if urgency == UrgencyRed {
@@ -108,8 +155,45 @@ func CalculateMaxUrgency(occurrences []Occurrence) UrgencyLevel {
 	return maxUrgency
 }
 
-// ToMapOccurrenceResponse converte uma Occurrence para MapOccurrenceResponse
-func (o *Occurrence) ToMapOccurrenceResponse() MapOccurrenceResponse {
+// urgencyRank retorna a ordem de criticidade do nivel de urgencia (maior = mais critico)
+func urgencyRank(level UrgencyLevel) int {
+	switch level {
+	case UrgencyRed:
+		return 3
+	case UrgencyYellow:
+		return 2
+	case UrgencyGreen:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// MaxUrgencyLevel retorna o nivel mais critico entre dois niveis de urgencia
+func MaxUrgencyLevel(a, b UrgencyLevel) UrgencyLevel {
+	if urgencyRank(b) > urgencyRank(a) {
+		return b
+	}
+	return a
+}
+
+// ParseUrgencyLevel converte o filtro de urgencia (green, yellow, red) em UrgencyLevel
+func ParseUrgencyLevel(value string) (UrgencyLevel, bool) {
+	switch level := UrgencyLevel(value); level {
+	case UrgencyGreen, UrgencyYellow, UrgencyRed:
+		return level, true
+	default:
+		return "", false
+	}
+}
+
+// IsAtLeast verifica se o nivel e tao ou mais critico que min
+func (l UrgencyLevel) IsAtLeast(min UrgencyLevel) bool {
+	return urgencyRank(l) >= urgencyRank(min)
+}
+
+// ToMapOccurrenceResponse converte uma Occurrence para MapOccurrenceResponse, com a urgencia pelas faixas informadas
+func (o *Occurrence) ToMapOccurrenceResponse(thresholds UrgencyThresholds) MapOccurrenceResponse {
 	// Calcular tempo restante
 	remaining := o.TimeRemaining()
 	minutesRemaining := int(remaining.Minutes())
@@ -128,7 +212,7 @@ func (o *Occurrence) ToMapOccurrenceResponse() MapOccurrenceResponse {
 		TempoRestante:         o.FormatTimeRemaining(),
 		TempoRestanteMinutos:  minutesRemaining,
 		Status:                o.Status,
-		Urgencia:              CalculateUrgencyLevel(minutesRemaining),
+		Urgencia:              CalculateUrgencyLevel(minutesRemaining, thresholds),
 	}
 }
 
@@ -163,3 +247,28 @@ func GetUrgencyColor(level UrgencyLevel) string {
 		return "#6b7280"
 	}
 }
+
+// MapUpdateChange identifica o que mudou em um hospital do mapa
+type MapUpdateChange string
+
+const (
+	// MapChangeOccurrenceCreated indica uma nova ocorrencia ativa no hospital
+	MapChangeOccurrenceCreated MapUpdateChange = "occurrence_created"
+	// MapChangeOccurrenceResolved indica que uma ocorrencia deixou de estar ativa (aceita, recusada, expirada...)
+	MapChangeOccurrenceResolved MapUpdateChange = "occurrence_resolved"
+	// MapChangeUrgency indica mudanca da urgencia maxima com o passar do tempo
+	MapChangeUrgency MapUpdateChange = "urgency_changed"
+	// MapChangeOperator indica mudanca do operador de plantao
+	MapChangeOperator MapUpdateChange = "operator_changed"
+)
+
+// MapUpdate e o delta de um hospital do mapa publicado em tempo real
+// Traz o estado atual do marcador, para o frontend atualizar sem consultar o endpoint do mapa
+type MapUpdate struct {
+	Change           MapUpdateChange        `json:"change"`
+	HospitalID       uuid.UUID              `json:"hospital_id"`
+	UrgenciaMaxima   UrgencyLevel           `json:"urgencia_maxima"`
+	OcorrenciasCount int                    `json:"ocorrencias_count"`
+	OperadorPlantao  *MapOperatorResponse   `json:"operador_plantao,omitempty"`
+	Ocorrencia       *MapOccurrenceResponse `json:"ocorrencia,omitempty"`
+}
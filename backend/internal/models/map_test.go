@@ -0,0 +1,100 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestCalculateUrgencyLevelDefaultBands tests the built-in bands: <2h red, 2-4h yellow, >4h green
+func TestCalculateUrgencyLevelDefaultBands(t *testing.T) {
+	thresholds := DefaultUrgencyThresholds()
+	tests := []struct {
+		minutes  int
+		expected UrgencyLevel
+	}{
+		{-5, UrgencyRed},
+		{0, UrgencyRed},
+		{119, UrgencyRed},
+		{120, UrgencyYellow},
+		{239, UrgencyYellow},
+		{240, UrgencyGreen},
+		{600, UrgencyGreen},
+	}
+
+	for _, tt := range tests {
+		if got := CalculateUrgencyLevel(tt.minutes, thresholds); got != tt.expected {
+			t.Errorf("%d minutes: expected %s, got %s", tt.minutes, tt.expected, got)
+		}
+	}
+}
+
+// TestCalculateMaxUrgencyCustomThresholdsReclassify tests that a tighter tenant band reclassifies an occurrence
+func TestCalculateMaxUrgencyCustomThresholdsReclassify(t *testing.T) {
+	// 90 minutes left: red with the built-in bands
+	occurrences := []Occurrence{{ID: uuid.New(), JanelaExpiraEm: time.Now().Add(90 * time.Minute)}}
+	if got := CalculateMaxUrgency(occurrences, DefaultUrgencyThresholds()); got != UrgencyRed {
+		t.Fatalf("Expected red with the default bands, got %s", got)
+	}
+
+	// Shorter windows: red below 30 minutes, yellow below 60 minutes
+	tight := UrgencyThresholds{RedMinutes: 30, YellowMinutes: 60}
+	if got := CalculateMaxUrgency(occurrences, tight); got != UrgencyGreen {
+		t.Errorf("Expected green with the tight bands, got %s", got)
+	}
+
+	occurrences = append(occurrences, Occurrence{ID: uuid.New(), JanelaExpiraEm: time.Now().Add(45 * time.Minute)})
+	if got := CalculateMaxUrgency(occurrences, tight); got != UrgencyYellow {
+		t.Errorf("Expected yellow with the tight bands, got %s", got)
+	}
+
+	if got := CalculateMaxUrgency(nil, tight); got != UrgencyNone {
+		t.Errorf("Expected none without occurrences, got %s", got)
+	}
+}
+
+// TestUrgencyThresholdsConfigFor tests the per-tenant bands and their fallbacks
+func TestUrgencyThresholdsConfigFor(t *testing.T) {
+	tenantA := uuid.New()
+	tenantB := uuid.New()
+	value := `{"default": {"red_minutes": 60, "yellow_minutes": 180}, "tenants": {"` + tenantA.String() + `": {"red_minutes": 30, "yellow_minutes": 60}}}`
+
+	setting := SystemSetting{Key: SettingKeyUrgencyThresholds, Value: json.RawMessage(value)}
+	config, err := setting.GetUrgencyThresholdsConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := config.For(tenantA); got != (UrgencyThresholds{RedMinutes: 30, YellowMinutes: 60}) {
+		t.Errorf("Expected tenant bands, got %+v", got)
+	}
+	if got := config.For(tenantB); got != (UrgencyThresholds{RedMinutes: 60, YellowMinutes: 180}) {
+		t.Errorf("Expected default bands of the setting, got %+v", got)
+	}
+
+	var missing *UrgencyThresholdsConfig
+	if got := missing.For(tenantA); got != DefaultUrgencyThresholds() {
+		t.Errorf("Expected built-in bands without a setting, got %+v", got)
+	}
+	if got := (&UrgencyThresholdsConfig{}).For(tenantA); got != DefaultUrgencyThresholds() {
+		t.Errorf("Expected built-in bands without a default, got %+v", got)
+	}
+}
+
+// TestUrgencyThresholdsConfigValidate tests that red_minutes must be positive and below yellow_minutes
+func TestUrgencyThresholdsConfigValidate(t *testing.T) {
+	invalid := []string{
+		`{"default": {"red_minutes": 0, "yellow_minutes": 60}}`,
+		`{"default": {"red_minutes": 60, "yellow_minutes": 60}}`,
+		`{"tenants": {"` + uuid.New().String() + `": {"red_minutes": 120, "yellow_minutes": 30}}}`,
+		`{"tenants": {"not-a-uuid": {"red_minutes": 30, "yellow_minutes": 60}}}`,
+	}
+	for _, value := range invalid {
+		setting := SystemSetting{Value: json.RawMessage(value)}
+		if _, err := setting.GetUrgencyThresholdsConfig(); err == nil {
+			t.Errorf("Expected error for %s", value)
+		}
+	}
+}
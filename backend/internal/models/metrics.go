@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -55,6 +57,9 @@ type MetricsResponse struct {
 	OccurrencesPendentes           int       `json:"occurrences_pendentes"`
 	OccurrencesEmAndamento         int       `json:"occurrences_em_andamento"`
 	UltimaAtualizacao              time.Time `json:"ultima_atualizacao"`
+
+	// Comparison with the previous period, present when requested with compare=previous
+	Comparacao *MetricsComparison `json:"comparacao,omitempty"`
 }
 
 // ToResponse converts DashboardMetrics to MetricsResponse
@@ -70,6 +75,89 @@ func (m *DashboardMetrics) ToResponse() MetricsResponse {
 	}
 }
 
+// ComparisonPeriod is the period used to compare dashboard metrics with the previous one
+type ComparisonPeriod string
+
+const (
+	ComparisonPeriodDay  ComparisonPeriod = "day"
+	ComparisonPeriodWeek ComparisonPeriod = "week"
+)
+
+// IsValid checks if the comparison period is supported
+func (p ComparisonPeriod) IsValid() bool {
+	return p == ComparisonPeriodDay || p == ComparisonPeriodWeek
+}
+
+// Bounds returns the start of the current period (today, or the current week starting on Monday)
+// and the start of the previous one. The previous period is compared over the same elapsed time,
+// so a partial current period is never compared with a complete previous one.
+func (p ComparisonPeriod) Bounds(now time.Time) (currentStart, previousStart time.Time) {
+	currentStart = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if p == ComparisonPeriodWeek {
+		daysSinceMonday := (int(currentStart.Weekday()) + 6) % 7
+		currentStart = currentStart.AddDate(0, 0, -daysSinceMonday)
+		return currentStart, currentStart.AddDate(0, 0, -7)
+	}
+	return currentStart, currentStart.AddDate(0, 0, -1)
+}
+
+// PeriodMetrics represents the period-based dashboard metrics of one period
+type PeriodMetrics struct {
+	ObitosElegiveis       int     `json:"obitos_elegiveis"`
+	TempoMedioNotificacao float64 `json:"tempo_medio_notificacao"` // in seconds
+	CorneasPotenciais     int     `json:"corneas_potenciais"`
+}
+
+// MetricDelta represents a metric in the current and previous periods
+type MetricDelta struct {
+	Atual    float64 `json:"atual"`
+	Anterior float64 `json:"anterior"`
+	// VariacaoPercentual is the change from the previous period in percent, or null when the previous period is empty
+	VariacaoPercentual *float64 `json:"variacao_percentual"`
+}
+
+// NewMetricDelta creates a metric delta from the current and previous values
+func NewMetricDelta(atual, anterior float64) MetricDelta {
+	return MetricDelta{
+		Atual:              atual,
+		Anterior:           anterior,
+		VariacaoPercentual: PercentChange(atual, anterior),
+	}
+}
+
+// PercentChange returns the change from previous to current in percent,
+// or nil when previous is zero and the change is undefined
+func PercentChange(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := (current - previous) / previous * 100
+	return &change
+}
+
+// MetricsComparison represents the dashboard metrics of the current period compared with the previous one.
+// Pending and in-progress counts are point-in-time values and are not compared.
+type MetricsComparison struct {
+	Periodo               ComparisonPeriod `json:"periodo"`
+	InicioAtual           time.Time        `json:"inicio_atual"`
+	InicioAnterior        time.Time        `json:"inicio_anterior"`
+	ObitosElegiveis       MetricDelta      `json:"obitos_elegiveis"`
+	TempoMedioNotificacao MetricDelta      `json:"tempo_medio_notificacao"`
+	CorneasPotenciais     MetricDelta      `json:"corneas_potenciais"`
+}
+
+// NewMetricsComparison compares the metrics of the current period with those of the previous one
+func NewMetricsComparison(period ComparisonPeriod, currentStart, previousStart time.Time, current, previous PeriodMetrics) *MetricsComparison {
+	return &MetricsComparison{
+		Periodo:               period,
+		InicioAtual:           currentStart,
+		InicioAnterior:        previousStart,
+		ObitosElegiveis:       NewMetricDelta(float64(current.ObitosElegiveis), float64(previous.ObitosElegiveis)),
+		TempoMedioNotificacao: NewMetricDelta(current.TempoMedioNotificacao, previous.TempoMedioNotificacao),
+		CorneasPotenciais:     NewMetricDelta(float64(current.CorneasPotenciais), float64(previous.CorneasPotenciais)),
+	}
+}
+
 // OccurrenceListFilters represents filters for listing occurrences
 type OccurrenceListFilters struct {
 	Status     *OccurrenceStatus `json:"status,omitempty"`
@@ -80,6 +168,20 @@ type OccurrenceListFilters struct {
 	PageSize   int               `json:"page_size"`
 	SortBy     string            `json:"sort_by"`
 	SortOrder  string            `json:"sort_order"`
+
+	// Search is a patient name prefix, matched against the masked name
+	Search *string `json:"q,omitempty"`
+	// SearchFullName also matches Search against the full name in plaintext dados_completos (gestor/admin only);
+	// encrypted rows only match by masked name
+	SearchFullName bool `json:"-"`
+}
+
+// OccurrenceSortFields maps the sort options of the occurrence lists to the sorted columns
+var OccurrenceSortFields = map[string]string{
+	"score":          "score_priorizacao",
+	"data_obito":     "data_obito",
+	"created_at":     "created_at",
+	"tempo_restante": "janela_expira_em",
 }
 
 // DefaultFilters returns default filter values
@@ -87,7 +189,7 @@ func DefaultFilters() OccurrenceListFilters {
 	return OccurrenceListFilters{
 		Page:      1,
 		PageSize:  20,
-		SortBy:    "created_at",
+		SortBy:    "score_priorizacao",
 		SortOrder: "desc",
 	}
 }
@@ -101,6 +203,52 @@ type PaginatedResponse struct {
 	TotalPages int         `json:"total_pages"`
 	HasNext    bool        `json:"has_next"`
 	HasPrev    bool        `json:"has_prev"`
+
+	// Links navigate to the other pages; set with WithLinks
+	Links *PaginationLinks `json:"links,omitempty"`
+}
+
+// PaginationLinks are the navigation links of a paginated list, relative to the API host
+// Prev and Next are null on the first and last page
+type PaginationLinks struct {
+	First string  `json:"first"`
+	Prev  *string `json:"prev"`
+	Next  *string `json:"next"`
+	Last  string  `json:"last"`
+}
+
+// NewPaginationLinks builds the links of a page from the request URL, keeping its other query
+// parameters and setting page and sizeParam (the page size parameter of the endpoint)
+func NewPaginationLinks(requestURL *url.URL, sizeParam string, page, pageSize, totalPages int) PaginationLinks {
+	if totalPages < 1 {
+		totalPages = 1
+	}
+
+	link := func(p int) string {
+		query := requestURL.Query()
+		query.Set("page", strconv.Itoa(p))
+		query.Set(sizeParam, strconv.Itoa(pageSize))
+		return requestURL.Path + "?" + query.Encode()
+	}
+
+	links := PaginationLinks{First: link(1), Last: link(totalPages)}
+	if page > 1 {
+		// A page past the end goes back to the last one
+		prev := link(min(page-1, totalPages))
+		links.Prev = &prev
+	}
+	if page < totalPages {
+		next := link(page + 1)
+		links.Next = &next
+	}
+	return links
+}
+
+// WithLinks returns the response with the navigation links built from the request URL
+func (r PaginatedResponse) WithLinks(requestURL *url.URL, sizeParam string) PaginatedResponse {
+	links := NewPaginationLinks(requestURL, sizeParam, r.Page, r.PageSize, r.TotalPages)
+	r.Links = &links
+	return r
 }
 
 // NewPaginatedResponse creates a new paginated response
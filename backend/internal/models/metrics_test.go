@@ -0,0 +1,152 @@
+package models
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+// TestPercentChange tests the percentage change between periods
+func TestPercentChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  float64
+		previous float64
+		expected float64
+	}{
+		{"increase", 15, 10, 50},
+		{"decrease", 5, 20, -75},
+		{"no change", 8, 8, 0},
+		{"drop to zero", 0, 4, -100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := PercentChange(tt.current, tt.previous)
+			if change == nil {
+				t.Fatalf("Expected a change, got nil")
+			}
+			if *change != tt.expected {
+				t.Errorf("PercentChange(%v, %v) = %v, expected %v", tt.current, tt.previous, *change, tt.expected)
+			}
+		})
+	}
+}
+
+// TestPercentChangeEmptyPrevious tests that an empty previous period has no defined change
+func TestPercentChangeEmptyPrevious(t *testing.T) {
+	if change := PercentChange(12, 0); change != nil {
+		t.Errorf("Expected nil change for an empty previous period, got %v", *change)
+	}
+	if change := PercentChange(0, 0); change != nil {
+		t.Errorf("Expected nil change when both periods are empty, got %v", *change)
+	}
+}
+
+// TestNewMetricsComparison tests the deltas of each compared metric
+func TestNewMetricsComparison(t *testing.T) {
+	current := PeriodMetrics{ObitosElegiveis: 6, TempoMedioNotificacao: 30, CorneasPotenciais: 12}
+	previous := PeriodMetrics{ObitosElegiveis: 4, TempoMedioNotificacao: 0, CorneasPotenciais: 8}
+
+	comparison := NewMetricsComparison(ComparisonPeriodWeek, time.Now(), time.Now(), current, previous)
+
+	if comparison.Periodo != ComparisonPeriodWeek {
+		t.Errorf("Expected week period, got %s", comparison.Periodo)
+	}
+	if comparison.ObitosElegiveis.Atual != 6 || comparison.ObitosElegiveis.Anterior != 4 {
+		t.Errorf("Unexpected eligible values: %+v", comparison.ObitosElegiveis)
+	}
+	if v := comparison.ObitosElegiveis.VariacaoPercentual; v == nil || *v != 50 {
+		t.Errorf("Expected +50%% eligible, got %v", v)
+	}
+	if v := comparison.CorneasPotenciais.VariacaoPercentual; v == nil || *v != 50 {
+		t.Errorf("Expected +50%% corneas, got %v", v)
+	}
+	if comparison.TempoMedioNotificacao.VariacaoPercentual != nil {
+		t.Error("Expected no notification time change when the previous period has no notifications")
+	}
+}
+
+// TestComparisonPeriodBounds tests the start of the current and previous periods
+func TestComparisonPeriodBounds(t *testing.T) {
+	// Thursday
+	now := time.Date(2026, 10, 15, 14, 30, 0, 0, time.UTC)
+
+	currentStart, previousStart := ComparisonPeriodDay.Bounds(now)
+	if !currentStart.Equal(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)) ||
+		!previousStart.Equal(time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected day bounds: %s, %s", currentStart, previousStart)
+	}
+
+	currentStart, previousStart = ComparisonPeriodWeek.Bounds(now)
+	if !currentStart.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)) ||
+		!previousStart.Equal(time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected week bounds: %s, %s", currentStart, previousStart)
+	}
+
+	// Sunday belongs to the week started on the previous Monday
+	currentStart, _ = ComparisonPeriodWeek.Bounds(time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC))
+	if !currentStart.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected Sunday to belong to the week started Monday, got %s", currentStart)
+	}
+}
+
+// TestNewPaginationLinks tests the navigation links at the first, middle and last page
+func TestNewPaginationLinks(t *testing.T) {
+	requestURL, _ := url.Parse("/api/v1/occurrences?status=PENDENTE&page=2&page_size=20")
+	link := func(page string) string {
+		return "/api/v1/occurrences?page=" + page + "&page_size=20&status=PENDENTE"
+	}
+
+	tests := []struct {
+		name string
+		page int
+		prev string
+		next string
+	}{
+		{"first page", 1, "", link("2")},
+		{"middle page", 2, link("1"), link("3")},
+		{"last page", 3, link("2"), ""},
+		{"past the last page", 7, link("3"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := NewPaginationLinks(requestURL, "page_size", tt.page, 20, 3)
+
+			if links.First != link("1") || links.Last != link("3") {
+				t.Errorf("Expected first %q and last %q, got %q and %q", link("1"), link("3"), links.First, links.Last)
+			}
+			if got := linkOrEmpty(links.Prev); got != tt.prev {
+				t.Errorf("Expected prev %q, got %q", tt.prev, got)
+			}
+			if got := linkOrEmpty(links.Next); got != tt.next {
+				t.Errorf("Expected next %q, got %q", tt.next, got)
+			}
+		})
+	}
+}
+
+// TestPaginatedResponseWithLinksEmpty tests that an empty list links to a single page
+func TestPaginatedResponseWithLinksEmpty(t *testing.T) {
+	requestURL, _ := url.Parse("/api/v1/users")
+
+	response := NewPaginatedResponse([]string{}, 1, 10, 0).WithLinks(requestURL, "per_page")
+
+	if response.Links == nil {
+		t.Fatal("Expected links to be set")
+	}
+	if response.Links.First != "/api/v1/users?page=1&per_page=10" || response.Links.Last != response.Links.First {
+		t.Errorf("Expected first and last to be page 1, got %q and %q", response.Links.First, response.Links.Last)
+	}
+	if response.Links.Prev != nil || response.Links.Next != nil {
+		t.Errorf("Expected no prev or next link, got %v and %v", response.Links.Prev, response.Links.Next)
+	}
+}
+
+func linkOrEmpty(link *string) string {
+	if link == nil {
+		return ""
+	}
+	return *link
+}
@@ -88,6 +88,7 @@ func TestOccurrenceStatusValidation(t *testing.T) {
 		{"valid RECUSADA", StatusRecusada, true},
 		{"valid CANCELADA", StatusCancelada, true},
 		{"valid CONCLUIDA", StatusConcluida, true},
+		{"valid EXPIRADA", StatusExpirada, true},
 		{"invalid empty status", OccurrenceStatus(""), false},
 		{"invalid lowercase status", OccurrenceStatus("pendente"), false},
 		{"invalid unknown status", OccurrenceStatus("UNKNOWN"), false},
@@ -130,6 +131,7 @@ func TestOccurrenceStatusTransitions(t *testing.T) {
 		// Terminal states - no transitions allowed
 		{"CONCLUIDA to any (invalid)", StatusConcluida, StatusPendente, false},
 		{"CANCELADA to any (invalid)", StatusCancelada, StatusPendente, false},
+		{"EXPIRADA to any (invalid)", StatusExpirada, StatusPendente, false},
 	}
 
 	for _, tt := range tests {
@@ -321,3 +323,46 @@ func TestHospitalToResponse(t *testing.T) {
 		t.Errorf("Ativo mismatch: got %v, expected %v", response.Ativo, hospital.Ativo)
 	}
 }
+
+// Test adicional: Fuso horario do hospital, com fallback para o fuso do sistema
+func TestHospitalLocation(t *testing.T) {
+	previous := DefaultLocation()
+	defer func() { defaultLocation = previous }()
+
+	if err := SetDefaultTimezone("America/Sao_Paulo"); err != nil {
+		t.Fatalf("Failed to set default timezone: %v", err)
+	}
+	if err := SetDefaultTimezone("Mars/Olympus"); err != ErrInvalidTimezone {
+		t.Errorf("Expected ErrInvalidTimezone, got %v", err)
+	}
+
+	manaus := "America/Manaus"
+	unknown := "Mars/Olympus"
+	empty := ""
+	tests := []struct {
+		name     string
+		timezone *string
+		expected string
+	}{
+		{"hospital timezone", &manaus, "America/Manaus"},
+		{"not set", nil, "America/Sao_Paulo"},
+		{"empty", &empty, "America/Sao_Paulo"},
+		{"unknown", &unknown, "America/Sao_Paulo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hospital := Hospital{Timezone: tt.timezone}
+			if got := hospital.Location().String(); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+
+	if ValidateTimezone(&manaus) != nil || ValidateTimezone(&empty) != nil || ValidateTimezone(nil) != nil {
+		t.Error("Expected known and empty timezones to be valid")
+	}
+	if ValidateTimezone(&unknown) != ErrInvalidTimezone {
+		t.Error("Expected an unknown timezone to be rejected")
+	}
+}
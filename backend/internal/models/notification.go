@@ -14,6 +14,8 @@ const (
 	ChannelDashboard NotificationChannel = "dashboard"
 	ChannelEmail     NotificationChannel = "email"
 	ChannelSMS       NotificationChannel = "sms"
+	// ChannelPush is used for delivery preferences only; push deliveries are not recorded in notifications
+	ChannelPush NotificationChannel = "push"
 )
 
 // ValidChannels contains all valid notification channels
@@ -129,9 +131,14 @@ type SSEEvent struct {
 	DataObito    time.Time `json:"data_obito"`
 	TempoRestante string   `json:"tempo_restante"`
 	CreatedAt    time.Time `json:"created_at"`
+	// Topic routes the event to the streams subscribed to it; empty is the notification stream
+	Topic string     `json:"topic,omitempty"`
+	Map   *MapUpdate `json:"map,omitempty"`
+	// TenantID restricts the event to the clients of the tenant (and super admins); empty reaches every client
+	TenantID string `json:"tenant_id,omitempty"`
 }
 
-// NewOccurrenceSSEEvent creates a new SSE event for a new occurrence
+// NewOccurrenceSSEEvent creates a new SSE event for a new occurrence, delivered only to the clients of its tenant
 func NewOccurrenceSSEEvent(occurrence *Occurrence, hospitalNome string) SSEEvent {
 	setor := ""
 	var data OccurrenceCompleteData
@@ -147,9 +154,44 @@ func NewOccurrenceSSEEvent(occurrence *Occurrence, hospitalNome string) SSEEvent
 		DataObito:     occurrence.DataObito,
 		TempoRestante: occurrence.FormatTimeRemaining(),
 		CreatedAt:     time.Now(),
+		TenantID:      occurrence.TenantID.String(),
 	}
 }
 
+// SSEEventTypeEscalation is published when a pending occurrence is about to exceed its capture window
+const SSEEventTypeEscalation = "escalation"
+
+// NewEscalationSSEEvent creates an SSE event escalating a pending occurrence close to window expiry
+func NewEscalationSSEEvent(occurrence *Occurrence, hospitalNome string) SSEEvent {
+	event := NewOccurrenceSSEEvent(occurrence, hospitalNome)
+	event.Type = SSEEventTypeEscalation
+	return event
+}
+
+// SSETopicMap is the topic of the live map stream
+const SSETopicMap = "map"
+
+// SSEEventTypeMapUpdate is published when a marker of the map changes
+const SSEEventTypeMapUpdate = "map_update"
+
+// NewMapUpdateSSEEvent creates an SSE event of the map topic, delivered only to the clients of the hospital's tenant
+func NewMapUpdateSSEEvent(update *MapUpdate, hospitalNome string, tenantID uuid.UUID) SSEEvent {
+	event := SSEEvent{
+		Type:         SSEEventTypeMapUpdate,
+		HospitalNome: hospitalNome,
+		CreatedAt:    time.Now(),
+		Topic:        SSETopicMap,
+		Map:          update,
+		TenantID:     tenantID.String(),
+	}
+	if update.Ocorrencia != nil {
+		event.OccurrenceID = update.Ocorrencia.ID
+		event.Setor = update.Ocorrencia.Setor
+		event.TempoRestante = update.Ocorrencia.TempoRestante
+	}
+	return event
+}
+
 // SSE Event Types for AI Assistant
 const (
 	// SSEEventTypeAIResponseChunk represents a chunk of AI response text
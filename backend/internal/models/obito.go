@@ -53,14 +53,24 @@ func (o *ObitoSimulado) CalculateAge() int {
 
 // IsWithinWindow checks if the death is within the 6-hour capture window
 func (o *ObitoSimulado) IsWithinWindow(windowHours int) bool {
+	return o.IsWithinWindowAt(windowHours, time.Now())
+}
+
+// IsWithinWindowAt checks if the death was within the capture window at the given moment
+func (o *ObitoSimulado) IsWithinWindowAt(windowHours int, at time.Time) bool {
 	deadline := o.DataObito.Add(time.Duration(windowHours) * time.Hour)
-	return time.Now().Before(deadline)
+	return at.Before(deadline)
 }
 
 // TimeRemaining returns the time remaining in the capture window
 func (o *ObitoSimulado) TimeRemaining(windowHours int) time.Duration {
+	return o.TimeRemainingAt(windowHours, time.Now())
+}
+
+// TimeRemainingAt returns the time remaining in the capture window at the given moment
+func (o *ObitoSimulado) TimeRemainingAt(windowHours int, at time.Time) time.Duration {
 	deadline := o.DataObito.Add(time.Duration(windowHours) * time.Hour)
-	remaining := deadline.Sub(time.Now())
+	remaining := deadline.Sub(at)
 
 	if remaining < 0 {
 		return 0
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ObitoIneligibility records why the triagem found an obito ineligible
+type ObitoIneligibility struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	ObitoID         uuid.UUID `json:"obito_id" db:"obito_id"`
+	HospitalID      uuid.UUID `json:"hospital_id" db:"hospital_id"`
+	TenantID        uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	Motivos         []string  `json:"motivos" db:"motivos"`
+	RegrasAvaliadas []string  `json:"regras_avaliadas" db:"regras_avaliadas"`
+	RegraRejeicao   *string   `json:"regra_rejeicao,omitempty" db:"regra_rejeicao"` // First rule that found the obito ineligible
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// ObitoIneligibilityFilters filters the ineligibility list
+type ObitoIneligibilityFilters struct {
+	Motivo     string // case-insensitive partial match of any reason
+	DateFrom   *time.Time
+	DateTo     *time.Time
+	HospitalID *uuid.UUID
+	Page       int
+	PageSize   int
+}
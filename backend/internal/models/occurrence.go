@@ -18,6 +18,7 @@ const (
 	StatusRecusada    OccurrenceStatus = "RECUSADA"
 	StatusCancelada   OccurrenceStatus = "CANCELADA"
 	StatusConcluida   OccurrenceStatus = "CONCLUIDA"
+	StatusExpirada    OccurrenceStatus = "EXPIRADA"
 )
 
 // ValidStatuses contains all valid occurrence statuses
@@ -28,6 +29,7 @@ var ValidStatuses = []OccurrenceStatus{
 	StatusRecusada,
 	StatusCancelada,
 	StatusConcluida,
+	StatusExpirada,
 }
 
 // StatusTransitions defines valid status transitions
@@ -38,6 +40,7 @@ var StatusTransitions = map[OccurrenceStatus][]OccurrenceStatus{
 	StatusRecusada:    {StatusConcluida, StatusCancelada},
 	StatusCancelada:   {}, // Terminal state
 	StatusConcluida:   {}, // Terminal state
+	StatusExpirada:    {}, // Terminal state, set only by the expiry job
 }
 
 // IsValid checks if the status is a valid occurrence status
@@ -73,7 +76,17 @@ func (s OccurrenceStatus) String() string {
 
 // IsTerminal returns true if this is a terminal status
 func (s OccurrenceStatus) IsTerminal() bool {
-	return s == StatusConcluida || s == StatusCancelada
+	return s == StatusConcluida || s == StatusCancelada || s == StatusExpirada
+}
+
+// IsActive returns true if occurrences in this status are still open (PENDENTE or EM_ANDAMENTO)
+func (s OccurrenceStatus) IsActive() bool {
+	return s == StatusPendente || s == StatusEmAndamento
+}
+
+// CanBeAssigned returns true if an occurrence in this status can be assigned to an operator
+func (s OccurrenceStatus) CanBeAssigned() bool {
+	return s == StatusPendente || s == StatusEmAndamento
 }
 
 // Occurrence represents an eligible death occurrence
@@ -82,7 +95,7 @@ type Occurrence struct {
 	TenantID              uuid.UUID        `json:"tenant_id" db:"tenant_id"`
 	ObitoID               uuid.UUID        `json:"obito_id" db:"obito_id" validate:"required"`
 	HospitalID            uuid.UUID        `json:"hospital_id" db:"hospital_id" validate:"required"`
-	Status                OccurrenceStatus `json:"status" db:"status" validate:"required,oneof=PENDENTE EM_ANDAMENTO ACEITA RECUSADA CANCELADA CONCLUIDA"`
+	Status                OccurrenceStatus `json:"status" db:"status" validate:"required,oneof=PENDENTE EM_ANDAMENTO ACEITA RECUSADA CANCELADA CONCLUIDA EXPIRADA"`
 	ScorePriorizacao      int              `json:"score_priorizacao" db:"score_priorizacao"`
 	NomePacienteMascarado string           `json:"nome_paciente_mascarado" db:"nome_paciente_mascarado" validate:"required"`
 	DadosCompletos        json.RawMessage  `json:"-" db:"dados_completos"` // Hidden by default (LGPD)
@@ -91,10 +104,19 @@ type Occurrence struct {
 	NotificadoEm          *time.Time       `json:"notificado_em,omitempty" db:"notificado_em"`
 	DataObito             time.Time        `json:"data_obito" db:"data_obito"`
 	JanelaExpiraEm        time.Time        `json:"janela_expira_em" db:"janela_expira_em"`
+	AssignedUserID        *uuid.UUID       `json:"assigned_user_id,omitempty" db:"assigned_user_id"`
+	Flags                 []string         `json:"flags" db:"flags"` // Warnings of the triagem rules with the "flag" action
 
 	// Related data (populated by queries)
-	Hospital *Hospital      `json:"hospital,omitempty" db:"-"`
-	Obito    *ObitoSimulado `json:"-" db:"-"` // Hidden by default (LGPD)
+	Hospital     *Hospital           `json:"hospital,omitempty" db:"-"`
+	AssignedUser *OccurrenceAssignee `json:"assigned_user,omitempty" db:"-"`
+	Obito        *ObitoSimulado      `json:"-" db:"-"` // Hidden by default (LGPD)
+}
+
+// OccurrenceAssignee is the operator an occurrence is assigned to
+type OccurrenceAssignee struct {
+	ID   uuid.UUID `json:"id"`
+	Nome string    `json:"nome"`
 }
 
 // OccurrenceCompleteData represents the complete data stored in dados_completos
@@ -120,14 +142,21 @@ type CreateOccurrenceInput struct {
 	NomePacienteMascarado string          `json:"nome_paciente_mascarado" validate:"required"`
 	DadosCompletos        json.RawMessage `json:"dados_completos" validate:"required"`
 	DataObito             time.Time       `json:"data_obito" validate:"required"`
+	Flags                 []string        `json:"flags,omitempty"`
 }
 
 // UpdateStatusInput represents input for updating occurrence status
 type UpdateStatusInput struct {
-	Status      OccurrenceStatus `json:"status" validate:"required,oneof=PENDENTE EM_ANDAMENTO ACEITA RECUSADA CANCELADA CONCLUIDA"`
+	Status      OccurrenceStatus `json:"status" validate:"required,oneof=PENDENTE EM_ANDAMENTO ACEITA RECUSADA CANCELADA CONCLUIDA EXPIRADA"`
 	Observacoes *string          `json:"observacoes,omitempty" validate:"omitempty,max=1000"`
 }
 
+// AssignOccurrenceInput represents input for assigning an occurrence to an operator
+type AssignOccurrenceInput struct {
+	UserID      uuid.UUID `json:"user_id" validate:"required"`
+	Observacoes *string   `json:"observacoes,omitempty" validate:"omitempty,max=1000"`
+}
+
 // RegisterOutcomeInput represents input for registering an occurrence outcome
 type RegisterOutcomeInput struct {
 	Desfecho    OutcomeType `json:"desfecho" validate:"required,oneof=sucesso_captacao familia_recusou contraindicacao_medica tempo_excedido outro"`
@@ -136,18 +165,20 @@ type RegisterOutcomeInput struct {
 
 // OccurrenceListResponse represents the API response for listing occurrences
 type OccurrenceListResponse struct {
-	ID                    uuid.UUID         `json:"id"`
-	HospitalID            uuid.UUID         `json:"hospital_id"`
-	Hospital              *HospitalResponse `json:"hospital,omitempty"`
-	Status                OccurrenceStatus  `json:"status"`
-	ScorePriorizacao      int               `json:"score_priorizacao"`
-	NomePacienteMascarado string            `json:"nome_paciente_mascarado"`
-	CreatedAt             time.Time         `json:"created_at"`
-	NotificadoEm          *time.Time        `json:"notificado_em,omitempty"`
-	DataObito             time.Time         `json:"data_obito"`
-	JanelaExpiraEm        time.Time         `json:"janela_expira_em"`
-	TempoRestante         string            `json:"tempo_restante"`
-	Setor                 string            `json:"setor,omitempty"`
+	ID                    uuid.UUID           `json:"id"`
+	HospitalID            uuid.UUID           `json:"hospital_id"`
+	Hospital              *HospitalResponse   `json:"hospital,omitempty"`
+	Status                OccurrenceStatus    `json:"status"`
+	ScorePriorizacao      int                 `json:"score_priorizacao"`
+	NomePacienteMascarado string              `json:"nome_paciente_mascarado"`
+	CreatedAt             time.Time           `json:"created_at"`
+	NotificadoEm          *time.Time          `json:"notificado_em,omitempty"`
+	DataObito             time.Time           `json:"data_obito"`
+	JanelaExpiraEm        time.Time           `json:"janela_expira_em"`
+	TempoRestante         string              `json:"tempo_restante"`
+	Setor                 string              `json:"setor,omitempty"`
+	Flags                 []string            `json:"flags"`
+	AssignedUser          *OccurrenceAssignee `json:"assigned_user,omitempty"`
 }
 
 // OccurrenceDetailResponse represents the API response for occurrence details (includes unmasked data)
@@ -165,6 +196,43 @@ type OccurrenceDetailResponse struct {
 	DataObito             time.Time               `json:"data_obito"`
 	JanelaExpiraEm        time.Time               `json:"janela_expira_em"`
 	TempoRestante         string                  `json:"tempo_restante"`
+	Flags                 []string                `json:"flags"`
+	AssignedUser          *OccurrenceAssignee     `json:"assigned_user,omitempty"`
+}
+
+// AdminOccurrenceFilters extends OccurrenceListFilters for the cross-tenant occurrence search
+type AdminOccurrenceFilters struct {
+	OccurrenceListFilters
+	TenantID *uuid.UUID `json:"tenant_id,omitempty"`
+	ScoreMin *int       `json:"score_min,omitempty"`
+	ScoreMax *int       `json:"score_max,omitempty"`
+}
+
+// OccurrenceWithTenant is an occurrence with its tenant name (populated by admin queries)
+type OccurrenceWithTenant struct {
+	Occurrence
+	TenantName *string `json:"tenant_name,omitempty"`
+}
+
+// AdminOccurrenceListResponse represents an occurrence in the cross-tenant admin search
+type AdminOccurrenceListResponse struct {
+	OccurrenceListResponse
+	TenantID     uuid.UUID `json:"tenant_id"`
+	TenantName   *string   `json:"tenant_name,omitempty"`
+	HospitalNome string    `json:"hospital_nome,omitempty"`
+}
+
+// ToAdminListResponse converts OccurrenceWithTenant to AdminOccurrenceListResponse (masked names)
+func (o *OccurrenceWithTenant) ToAdminListResponse() AdminOccurrenceListResponse {
+	resp := AdminOccurrenceListResponse{
+		OccurrenceListResponse: o.ToListResponse(),
+		TenantID:               o.TenantID,
+		TenantName:             o.TenantName,
+	}
+	if o.Hospital != nil {
+		resp.HospitalNome = o.Hospital.Nome
+	}
+	return resp
 }
 
 // ToListResponse converts Occurrence to OccurrenceListResponse
@@ -180,6 +248,8 @@ func (o *Occurrence) ToListResponse() OccurrenceListResponse {
 		DataObito:             o.DataObito,
 		JanelaExpiraEm:        o.JanelaExpiraEm,
 		TempoRestante:         o.FormatTimeRemaining(),
+		Flags:                 o.flagsOrEmpty(),
+		AssignedUser:          o.AssignedUser,
 	}
 
 	if o.Hospital != nil {
@@ -210,6 +280,8 @@ func (o *Occurrence) ToDetailResponse() OccurrenceDetailResponse {
 		DataObito:             o.DataObito,
 		JanelaExpiraEm:        o.JanelaExpiraEm,
 		TempoRestante:         o.FormatTimeRemaining(),
+		Flags:                 o.flagsOrEmpty(),
+		AssignedUser:          o.AssignedUser,
 	}
 
 	if o.Hospital != nil {
@@ -226,6 +298,14 @@ func (o *Occurrence) ToDetailResponse() OccurrenceDetailResponse {
 	return resp
 }
 
+// flagsOrEmpty returns the flags of the occurrence, never nil, so responses always carry a flags array
+func (o *Occurrence) flagsOrEmpty() []string {
+	if o.Flags == nil {
+		return []string{}
+	}
+	return o.Flags
+}
+
 // TimeRemaining returns the time remaining in the capture window
 func (o *Occurrence) TimeRemaining() time.Duration {
 	remaining := o.JanelaExpiraEm.Sub(time.Now())
@@ -136,6 +136,7 @@ const (
 	ActionOccurrenceRefused     = "Ocorrencia recusada"
 	ActionOccurrenceCanceled    = "Ocorrencia cancelada"
 	ActionOccurrenceConcluded   = "Ocorrencia concluida"
+	ActionOccurrenceExpired     = "Ocorrencia expirada"
 	ActionOutcomeRegistered     = "Desfecho registrado"
 	ActionNotificationSent      = "Notificacao enviada"
 )
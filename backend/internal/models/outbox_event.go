@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Outbox event types
+const (
+	// OutboxEventOccurrenceCreated is written when the triagem motor creates an occurrence
+	OutboxEventOccurrenceCreated = "occurrence.created"
+)
+
+// OutboxEvent is a domain event stored in events_outbox until the relay delivers it
+type OutboxEvent struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	EventType   string          `json:"event_type" db:"event_type"`
+	AggregateID uuid.UUID       `json:"aggregate_id" db:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	LastError   *string         `json:"last_error,omitempty" db:"last_error"`
+	AvailableAt time.Time       `json:"available_at" db:"available_at"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	SentAt      *time.Time      `json:"sent_at,omitempty" db:"sent_at"`
+	DeadAt      *time.Time      `json:"dead_at,omitempty" db:"dead_at"` // Set when delivery gave up (dead-letter)
+}
+
+// OccurrenceCreatedPayload is the payload of an occurrence.created event.
+// Patient data is not copied into the outbox; handlers load the occurrence by ID.
+type OccurrenceCreatedPayload struct {
+	OccurrenceID uuid.UUID `json:"occurrence_id"`
+	HospitalID   uuid.UUID `json:"hospital_id"`
+}
+
+// NewOccurrenceCreatedEvent builds the outbox event for a newly created occurrence
+func NewOccurrenceCreatedEvent(occurrence *Occurrence) (*OutboxEvent, error) {
+	payload, err := json.Marshal(OccurrenceCreatedPayload{
+		OccurrenceID: occurrence.ID,
+		HospitalID:   occurrence.HospitalID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxEvent{
+		ID:          uuid.New(),
+		EventType:   OutboxEventOccurrenceCreated,
+		AggregateID: occurrence.ID,
+		Payload:     payload,
+		AvailableAt: occurrence.CreatedAt,
+		CreatedAt:   occurrence.CreatedAt,
+	}, nil
+}
@@ -36,6 +36,23 @@ type ReportOccurrenceRow struct {
 	Desfecho               *string    `json:"desfecho,omitempty"`
 }
 
+// CausaMortisStats represents the triagem eligibility of the obitos with one normalized cause of death
+type CausaMortisStats struct {
+	CausaMortis             string  `json:"causa_mortis"` // Lowercased, trimmed and with single spaces
+	TotalObitos             int     `json:"total_obitos"`
+	Elegiveis               int     `json:"elegiveis"`
+	Inelegiveis             int     `json:"inelegiveis"`
+	TaxaElegibilidade       float64 `json:"taxa_elegibilidade"` // percentage
+	MotivoExclusaoFrequente *string `json:"motivo_exclusao_frequente,omitempty"`
+}
+
+// CausaMortisReport represents the eligibility per cause of death of the obitos triaged in a period
+type CausaMortisReport struct {
+	Causas        []CausaMortisStats `json:"causas"`
+	PeriodoInicio *time.Time         `json:"periodo_inicio,omitempty"`
+	PeriodoFim    *time.Time         `json:"periodo_fim,omitempty"`
+}
+
 // ReportAuditLog represents an audit log entry for report exports
 type ReportAuditLog struct {
 	ID           uuid.UUID      `json:"id" db:"id"`
@@ -80,3 +97,41 @@ func IsValidDesfecho(desfecho string) bool {
 	}
 	return false
 }
+
+// ScoreBreakdown splits an occurrence priority score into its components
+type ScoreBreakdown struct {
+	Setor          string `json:"setor"`
+	PontuacaoSetor int    `json:"pontuacao_setor"`
+	BonusUrgencia  int    `json:"bonus_urgencia"`
+	Total          int    `json:"total"`
+}
+
+// NewScoreBreakdown derives the sector and urgency components of a stored priority score
+// The triagem motor scores by sector first and adds an urgency bonus capped at 100
+func NewScoreBreakdown(setor string, total int) ScoreBreakdown {
+	base := 50 // Motor default when sector is unknown
+	if setor != "" {
+		base = GetSectorScore(setor)
+	}
+
+	bonus := total - base
+	if bonus < 0 {
+		bonus = 0
+	}
+
+	return ScoreBreakdown{
+		Setor:          setor,
+		PontuacaoSetor: base,
+		BonusUrgencia:  bonus,
+		Total:          total,
+	}
+}
+
+// OccurrenceCaseSheet holds the data rendered in a single-occurrence PDF case sheet
+type OccurrenceCaseSheet struct {
+	Occurrence     *Occurrence
+	DadosCompletos *OccurrenceCompleteData
+	History        []OccurrenceHistory
+	Outcome        *OccurrenceHistory
+	Score          ScoreBreakdown
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -147,6 +148,62 @@ func (s *Shift) ContainsTime(checkTime time.Time) bool {
 	return checkMinutes >= startMinutes && checkMinutes < endMinutes
 }
 
+// NextDay returns the following day of the week (Saturday wraps to Sunday)
+func (d DayOfWeek) NextDay() DayOfWeek {
+	return (d + 1) % 7
+}
+
+// coversInstant checks if the shift covers the given day of week and time of day
+// A night shift covers its own day from StartTime and the next day until EndTime
+func (s *Shift) coversInstant(day DayOfWeek, checkTime time.Time) bool {
+	if !s.ContainsTime(checkTime) {
+		return false
+	}
+
+	if !s.IsNightShift() {
+		return s.DayOfWeek == day
+	}
+
+	checkMinutes := checkTime.Hour()*60 + checkTime.Minute()
+	startMinutes := s.StartTime.Hour()*60 + s.StartTime.Minute()
+
+	if checkMinutes >= startMinutes {
+		return s.DayOfWeek == day
+	}
+	return s.DayOfWeek.NextDay() == day
+}
+
+// Overlaps checks if two shifts share any time in the weekly schedule
+// Night shifts are considered to extend into the early morning of the next day
+func (s *Shift) Overlaps(other *Shift) bool {
+	if !s.StartTime.IsValid() || !other.StartTime.IsValid() {
+		return false
+	}
+
+	ref := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	sStart, _ := s.StartTime.ToTime(ref)
+	otherStart, _ := other.StartTime.ToTime(ref)
+
+	// Two intervals overlap if and only if one contains the start of the other
+	return s.coversInstant(other.DayOfWeek, otherStart) || other.coversInstant(s.DayOfWeek, sStart)
+}
+
+// ShiftOverlapError describes a conflict with an existing shift for the same operator
+type ShiftOverlapError struct {
+	Existing Shift
+}
+
+// Error returns a description of the conflicting shift
+func (e *ShiftOverlapError) Error() string {
+	return fmt.Sprintf("shift overlaps existing shift on %s from %s to %s",
+		e.Existing.DayOfWeek.String(), e.Existing.StartTime, e.Existing.EndTime)
+}
+
+// Unwrap allows errors.Is(err, ErrShiftOverlap)
+func (e *ShiftOverlapError) Unwrap() error {
+	return ErrShiftOverlap
+}
+
 // CreateShiftInput represents input for creating a shift
 type CreateShiftInput struct {
 	HospitalID uuid.UUID `json:"hospital_id" validate:"required"`
@@ -246,6 +303,36 @@ type CoverageAnalysis struct {
 	HasGaps     bool          `json:"has_gaps"`
 }
 
+// OnDutyResponse represents who is on duty right now at a hospital
+// Operador and Shift are null when nobody is scheduled
+type OnDutyResponse struct {
+	HospitalID   uuid.UUID      `json:"hospital_id"`
+	HospitalNome string         `json:"hospital_nome"`
+	Operador     *UserResponse  `json:"operador"`
+	Shift        *ShiftResponse `json:"shift"`
+}
+
+// NewOnDutyResponse builds the on-duty entry for a hospital from its active shifts
+// The first active shift with a loaded user is used, matching the notification routing order
+func NewOnDutyResponse(hospital Hospital, activeShifts []Shift) OnDutyResponse {
+	resp := OnDutyResponse{
+		HospitalID:   hospital.ID,
+		HospitalNome: hospital.Nome,
+	}
+
+	for _, s := range activeShifts {
+		if s.User == nil {
+			continue
+		}
+		shiftResp := s.ToResponse()
+		resp.Shift = &shiftResp
+		resp.Operador = shiftResp.User
+		break
+	}
+
+	return resp
+}
+
 // TodayShift represents a shift scheduled for today with user details
 type TodayShift struct {
 	Shift
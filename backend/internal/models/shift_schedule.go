@@ -0,0 +1,150 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidScheduleSlotMinutes is returned when the slot size does not evenly divide a day
+var ErrInvalidScheduleSlotMinutes = fmt.Errorf("slot_minutes must divide a day evenly and be between %d and %d", MinScheduleSlotMinutes, MaxScheduleSlotMinutes)
+
+const (
+	// DefaultScheduleSlotMinutes is the slot size of the weekly schedule when none is given
+	DefaultScheduleSlotMinutes = 60
+	// MinScheduleSlotMinutes is the smallest slot size of the weekly schedule
+	MinScheduleSlotMinutes = 15
+	// MaxScheduleSlotMinutes is the largest slot size of the weekly schedule
+	MaxScheduleSlotMinutes = 720
+
+	minutesPerDay  = 24 * 60
+	minutesPerWeek = 7 * minutesPerDay
+)
+
+// ScheduleOperator is an operator covering a slot of the weekly schedule
+type ScheduleOperator struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Nome    string    `json:"nome"`
+	ShiftID uuid.UUID `json:"shift_id"`
+}
+
+// ScheduleSlot is a time slot of a day in the weekly schedule
+// A slot without operators is a coverage gap
+type ScheduleSlot struct {
+	StartTime string             `json:"start_time"`
+	EndTime   string             `json:"end_time"`
+	Operators []ScheduleOperator `json:"operators"`
+	Gap       bool               `json:"gap"`
+}
+
+// ScheduleDay holds the slots of a day of the weekly schedule
+type ScheduleDay struct {
+	DayOfWeek DayOfWeek      `json:"day_of_week"`
+	DayName   string         `json:"day_name"`
+	Slots     []ScheduleSlot `json:"slots"`
+}
+
+// WeeklySchedule is the 7-day grid of who covers each slot at a hospital
+type WeeklySchedule struct {
+	HospitalID  uuid.UUID     `json:"hospital_id"`
+	SlotMinutes int           `json:"slot_minutes"`
+	TotalShifts int           `json:"total_shifts"`
+	GapSlots    int           `json:"gap_slots"`
+	HasGaps     bool          `json:"has_gaps"`
+	Days        []ScheduleDay `json:"days"`
+}
+
+// ValidateScheduleSlotMinutes checks that the slot size evenly divides a day
+func ValidateScheduleSlotMinutes(slotMinutes int) error {
+	if slotMinutes < MinScheduleSlotMinutes || slotMinutes > MaxScheduleSlotMinutes || minutesPerDay%slotMinutes != 0 {
+		return ErrInvalidScheduleSlotMinutes
+	}
+	return nil
+}
+
+// weekInterval returns the start and end of the shift in minutes since Sunday 00:00
+// A night shift ends on the next day, and Saturday night ends past the end of the week
+func (s *Shift) weekInterval() (int, int) {
+	start := s.StartTime.Hour()*60 + s.StartTime.Minute()
+	end := s.EndTime.Hour()*60 + s.EndTime.Minute()
+	if s.IsNightShift() {
+		end += minutesPerDay
+	}
+	offset := int(s.DayOfWeek) * minutesPerDay
+	return offset + start, offset + end
+}
+
+// coversWeekRange checks if the shift covers any part of [from, to), in minutes since Sunday 00:00
+func (s *Shift) coversWeekRange(from, to int) bool {
+	start, end := s.weekInterval()
+	// A shift running past Saturday midnight also covers the start of the week
+	for _, offset := range []int{0, -minutesPerWeek} {
+		if start+offset < to && from < end+offset {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildWeeklySchedule lays out the shifts of a hospital on a 7-day grid of slotMinutes slots
+// Night shifts cover the evening slots of their day and the early slots of the next day
+func BuildWeeklySchedule(hospitalID uuid.UUID, shifts []Shift, slotMinutes int) (*WeeklySchedule, error) {
+	if err := ValidateScheduleSlotMinutes(slotMinutes); err != nil {
+		return nil, err
+	}
+
+	schedule := &WeeklySchedule{
+		HospitalID:  hospitalID,
+		SlotMinutes: slotMinutes,
+		TotalShifts: len(shifts),
+		Days:        make([]ScheduleDay, 0, 7),
+	}
+
+	for day := Sunday; day <= Saturday; day++ {
+		scheduleDay := ScheduleDay{
+			DayOfWeek: day,
+			DayName:   day.String(),
+			Slots:     make([]ScheduleSlot, 0, minutesPerDay/slotMinutes),
+		}
+
+		for minute := 0; minute < minutesPerDay; minute += slotMinutes {
+			from := int(day)*minutesPerDay + minute
+			slot := ScheduleSlot{
+				StartTime: formatScheduleMinute(minute),
+				EndTime:   formatScheduleMinute(minute + slotMinutes),
+				Operators: []ScheduleOperator{},
+			}
+
+			for i := range shifts {
+				if !shifts[i].coversWeekRange(from, from+slotMinutes) {
+					continue
+				}
+				operator := ScheduleOperator{UserID: shifts[i].UserID, ShiftID: shifts[i].ID}
+				if shifts[i].User != nil {
+					operator.Nome = shifts[i].User.Nome
+				}
+				slot.Operators = append(slot.Operators, operator)
+			}
+
+			if len(slot.Operators) == 0 {
+				slot.Gap = true
+				schedule.GapSlots++
+				schedule.HasGaps = true
+			}
+			scheduleDay.Slots = append(scheduleDay.Slots, slot)
+		}
+
+		schedule.Days = append(schedule.Days, scheduleDay)
+	}
+
+	return schedule, nil
+}
+
+// formatScheduleMinute formats minutes since midnight as HH:MM, with the end of the day as 23:59
+// like the coverage gaps
+func formatScheduleMinute(minute int) string {
+	if minute >= minutesPerDay {
+		return "23:59"
+	}
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
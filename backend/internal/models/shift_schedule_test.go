@@ -0,0 +1,128 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// scheduleTestShift creates a shift with a loaded operator for the weekly schedule tests
+func scheduleTestShift(day DayOfWeek, start, end ShiftTime, nome string) Shift {
+	userID := uuid.New()
+	return Shift{
+		ID:        uuid.New(),
+		UserID:    userID,
+		DayOfWeek: day,
+		StartTime: start,
+		EndTime:   end,
+		User:      &User{ID: userID, Nome: nome},
+	}
+}
+
+// scheduleSlotOperators returns the names covering the slot of the day starting at startTime
+func scheduleSlotOperators(t *testing.T, schedule *WeeklySchedule, day DayOfWeek, startTime string) []string {
+	t.Helper()
+	for _, slot := range schedule.Days[day].Slots {
+		if slot.StartTime == startTime {
+			names := make([]string, len(slot.Operators))
+			for i, operator := range slot.Operators {
+				names[i] = operator.Nome
+			}
+			return names
+		}
+	}
+	t.Fatalf("Slot %s not found on %s", startTime, day)
+	return nil
+}
+
+// TestBuildWeeklyScheduleNightShift tests that a night shift covers the evening of its day and the early slots of the next day
+func TestBuildWeeklyScheduleNightShift(t *testing.T) {
+	shifts := []Shift{
+		scheduleTestShift(Monday, "19:00", "07:00", "Noturno"),
+		scheduleTestShift(Tuesday, "07:00", "19:00", "Diurno"),
+	}
+
+	schedule, err := BuildWeeklySchedule(uuid.New(), shifts, 60)
+	if err != nil {
+		t.Fatalf("Failed to build schedule: %v", err)
+	}
+
+	if len(schedule.Days) != 7 {
+		t.Fatalf("Expected 7 days, got %d", len(schedule.Days))
+	}
+	for _, day := range schedule.Days {
+		if len(day.Slots) != 24 {
+			t.Fatalf("Expected 24 slots on %s, got %d", day.DayName, len(day.Slots))
+		}
+	}
+
+	tests := []struct {
+		day       DayOfWeek
+		startTime string
+		expected  string
+	}{
+		{Monday, "18:00", ""},
+		{Monday, "19:00", "Noturno"},
+		{Monday, "23:00", "Noturno"},
+		{Tuesday, "00:00", "Noturno"},
+		{Tuesday, "06:00", "Noturno"},
+		{Tuesday, "07:00", "Diurno"},
+		{Tuesday, "19:00", ""},
+		{Wednesday, "00:00", ""},
+	}
+	for _, tt := range tests {
+		names := scheduleSlotOperators(t, schedule, tt.day, tt.startTime)
+		if tt.expected == "" && len(names) != 0 {
+			t.Errorf("%s %s: expected a gap, got %v", tt.day, tt.startTime, names)
+		}
+		if tt.expected != "" && (len(names) != 1 || names[0] != tt.expected) {
+			t.Errorf("%s %s: expected %s, got %v", tt.day, tt.startTime, tt.expected, names)
+		}
+	}
+
+	if !schedule.HasGaps || schedule.GapSlots != 7*24-24 {
+		t.Errorf("Expected %d gap slots, got %d", 7*24-24, schedule.GapSlots)
+	}
+	if !schedule.Days[Monday].Slots[18].Gap || schedule.Days[Monday].Slots[19].Gap {
+		t.Errorf("Expected gaps to be marked only on uncovered slots")
+	}
+	last := schedule.Days[Monday].Slots[23]
+	if last.StartTime != "23:00" || last.EndTime != "23:59" {
+		t.Errorf("Expected the last slot from 23:00 to 23:59, got %s-%s", last.StartTime, last.EndTime)
+	}
+}
+
+// TestBuildWeeklyScheduleSaturdayNight tests that a Saturday night shift wraps into Sunday morning
+func TestBuildWeeklyScheduleSaturdayNight(t *testing.T) {
+	schedule, err := BuildWeeklySchedule(uuid.New(), []Shift{scheduleTestShift(Saturday, "22:00", "06:00", "Sabado")}, 30)
+	if err != nil {
+		t.Fatalf("Failed to build schedule: %v", err)
+	}
+
+	if len(schedule.Days[Sunday].Slots) != 48 {
+		t.Fatalf("Expected 48 slots of 30 minutes, got %d", len(schedule.Days[Sunday].Slots))
+	}
+	if names := scheduleSlotOperators(t, schedule, Sunday, "05:30"); len(names) != 1 {
+		t.Errorf("Expected the Saturday night shift on Sunday 05:30, got %v", names)
+	}
+	if names := scheduleSlotOperators(t, schedule, Sunday, "06:00"); len(names) != 0 {
+		t.Errorf("Expected a gap on Sunday 06:00, got %v", names)
+	}
+	if names := scheduleSlotOperators(t, schedule, Saturday, "22:00"); len(names) != 1 {
+		t.Errorf("Expected the shift on Saturday 22:00, got %v", names)
+	}
+}
+
+// TestValidateScheduleSlotMinutes tests the accepted slot sizes
+func TestValidateScheduleSlotMinutes(t *testing.T) {
+	for _, minutes := range []int{15, 30, 60, 120, 240, 720} {
+		if err := ValidateScheduleSlotMinutes(minutes); err != nil {
+			t.Errorf("Expected %d to be valid, got %v", minutes, err)
+		}
+	}
+	for _, minutes := range []int{0, 10, 50, 1440, -60} {
+		if err := ValidateScheduleSlotMinutes(minutes); err != ErrInvalidScheduleSlotMinutes {
+			t.Errorf("Expected %d to be rejected, got %v", minutes, err)
+		}
+	}
+}
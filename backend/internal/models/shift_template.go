@@ -0,0 +1,119 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shift template errors
+var (
+	ErrShiftTemplateNotFound      = errors.New("shift template not found")
+	ErrShiftTemplateNoSlots       = errors.New("shift template must have at least one slot")
+	ErrShiftTemplateTooManySlots  = fmt.Errorf("shift template must have at most %d slots", MaxShiftTemplateSlots)
+	ErrShiftTemplateEmptySlot     = errors.New("shift template slot must not start and end at the same time")
+	ErrShiftTemplateOperatorCount = errors.New("one operator is required for each slot of the shift template")
+)
+
+// MaxShiftTemplateSlots caps the number of slots in a shift template
+const MaxShiftTemplateSlots = 24
+
+// ShiftTemplateSlot is a recurring shift of a template, worked by one operator
+// A slot with no days repeats on all seven days of the week
+type ShiftTemplateSlot struct {
+	StartTime ShiftTime   `json:"start_time"`
+	EndTime   ShiftTime   `json:"end_time"`
+	Days      []DayOfWeek `json:"days,omitempty"`
+}
+
+// WeekDays returns the days the slot repeats on
+func (s ShiftTemplateSlot) WeekDays() []DayOfWeek {
+	if len(s.Days) > 0 {
+		return s.Days
+	}
+	return []DayOfWeek{Sunday, Monday, Tuesday, Wednesday, Thursday, Friday, Saturday}
+}
+
+// Validate validates the times and days of the slot
+func (s ShiftTemplateSlot) Validate() error {
+	if !s.StartTime.IsValid() {
+		return ErrInvalidStartTime
+	}
+	if !s.EndTime.IsValid() {
+		return ErrInvalidEndTime
+	}
+	if s.StartTime == s.EndTime {
+		return ErrShiftTemplateEmptySlot
+	}
+	for _, day := range s.Days {
+		if !day.IsValid() {
+			return ErrInvalidDayOfWeek
+		}
+	}
+	return nil
+}
+
+// ShiftTemplate is a reusable weekly rotation (e.g., "12h day/night") applied to hospitals
+// A template without tenant is available to every tenant
+type ShiftTemplate struct {
+	ID        uuid.UUID           `json:"id" db:"id"`
+	TenantID  *uuid.UUID          `json:"tenant_id,omitempty" db:"tenant_id"`
+	Nome      string              `json:"nome" db:"nome"`
+	Descricao *string             `json:"descricao,omitempty" db:"descricao"`
+	Slots     []ShiftTemplateSlot `json:"slots" db:"slots"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at" db:"updated_at"`
+}
+
+// Expand returns the shifts of the template at the hospital, assigning each slot to the
+// operator at the same position
+func (t *ShiftTemplate) Expand(hospitalID uuid.UUID, operators []uuid.UUID) ([]CreateShiftInput, error) {
+	if len(operators) != len(t.Slots) {
+		return nil, ErrShiftTemplateOperatorCount
+	}
+
+	var inputs []CreateShiftInput
+	for i, slot := range t.Slots {
+		for _, day := range slot.WeekDays() {
+			inputs = append(inputs, CreateShiftInput{
+				HospitalID: hospitalID,
+				UserID:     operators[i],
+				DayOfWeek:  day,
+				StartTime:  slot.StartTime,
+				EndTime:    slot.EndTime,
+			})
+		}
+	}
+	return inputs, nil
+}
+
+// CreateShiftTemplateInput represents input for creating a shift template
+type CreateShiftTemplateInput struct {
+	Nome      string              `json:"nome" validate:"required,min=2,max=255"`
+	Descricao *string             `json:"descricao,omitempty" validate:"omitempty,max=1000"`
+	Slots     []ShiftTemplateSlot `json:"slots" validate:"required"`
+}
+
+// Validate validates the slots of the CreateShiftTemplateInput
+func (i *CreateShiftTemplateInput) Validate() error {
+	if len(i.Slots) == 0 {
+		return ErrShiftTemplateNoSlots
+	}
+	if len(i.Slots) > MaxShiftTemplateSlots {
+		return ErrShiftTemplateTooManySlots
+	}
+	for _, slot := range i.Slots {
+		if err := slot.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyShiftTemplateInput represents input for applying a shift template to a hospital
+type ApplyShiftTemplateInput struct {
+	// Operators lists the operator of each slot, in the order of the template slots
+	Operators []uuid.UUID `json:"operators" validate:"required"`
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestShiftTemplateExpandDayNightRotation tests that a 12h day/night rotation expands into the 14 shifts of the week
+func TestShiftTemplateExpandDayNightRotation(t *testing.T) {
+	template := &ShiftTemplate{
+		ID:   uuid.New(),
+		Nome: "Plantao 12h diurno/noturno",
+		Slots: []ShiftTemplateSlot{
+			{StartTime: "07:00", EndTime: "19:00"},
+			{StartTime: "19:00", EndTime: "07:00"},
+		},
+	}
+	hospitalID, day, night := uuid.New(), uuid.New(), uuid.New()
+
+	inputs, err := template.Expand(hospitalID, []uuid.UUID{day, night})
+	if err != nil {
+		t.Fatalf("Failed to expand template: %v", err)
+	}
+
+	if len(inputs) != 14 {
+		t.Fatalf("Expected 14 shifts, got %d", len(inputs))
+	}
+
+	seen := make(map[DayOfWeek]map[uuid.UUID]bool)
+	for _, input := range inputs {
+		if input.HospitalID != hospitalID {
+			t.Errorf("Expected hospital %s, got %s", hospitalID, input.HospitalID)
+		}
+		if err := input.Validate(); err != nil {
+			t.Errorf("Expected a valid shift, got %v", err)
+		}
+		switch input.UserID {
+		case day:
+			if input.StartTime != "07:00" || input.EndTime != "19:00" {
+				t.Errorf("Expected the day operator from 07:00 to 19:00, got %s-%s", input.StartTime, input.EndTime)
+			}
+		case night:
+			if input.StartTime != "19:00" || input.EndTime != "07:00" {
+				t.Errorf("Expected the night operator from 19:00 to 07:00, got %s-%s", input.StartTime, input.EndTime)
+			}
+		default:
+			t.Errorf("Unexpected operator %s", input.UserID)
+		}
+		if seen[input.DayOfWeek] == nil {
+			seen[input.DayOfWeek] = make(map[uuid.UUID]bool)
+		}
+		seen[input.DayOfWeek][input.UserID] = true
+	}
+
+	for d := Sunday; d <= Saturday; d++ {
+		if !seen[d][day] || !seen[d][night] {
+			t.Errorf("Expected a day and a night shift on %s, got %v", d, seen[d])
+		}
+	}
+}
+
+// TestShiftTemplateExpandDays tests that a slot with days only repeats on those days
+func TestShiftTemplateExpandDays(t *testing.T) {
+	template := &ShiftTemplate{Slots: []ShiftTemplateSlot{
+		{StartTime: "08:00", EndTime: "14:00", Days: []DayOfWeek{Saturday, Sunday}},
+	}}
+
+	inputs, err := template.Expand(uuid.New(), []uuid.UUID{uuid.New()})
+	if err != nil {
+		t.Fatalf("Failed to expand template: %v", err)
+	}
+	if len(inputs) != 2 || inputs[0].DayOfWeek != Saturday || inputs[1].DayOfWeek != Sunday {
+		t.Errorf("Expected shifts on Saturday and Sunday, got %+v", inputs)
+	}
+
+	if _, err := template.Expand(uuid.New(), nil); !errors.Is(err, ErrShiftTemplateOperatorCount) {
+		t.Errorf("Expected ErrShiftTemplateOperatorCount, got %v", err)
+	}
+}
+
+// TestCreateShiftTemplateInputValidate tests the validation of template slots
+func TestCreateShiftTemplateInputValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		slots    []ShiftTemplateSlot
+		expected error
+	}{
+		{"valid", []ShiftTemplateSlot{{StartTime: "07:00", EndTime: "19:00"}}, nil},
+		{"no slots", nil, ErrShiftTemplateNoSlots},
+		{"invalid start", []ShiftTemplateSlot{{StartTime: "7h", EndTime: "19:00"}}, ErrInvalidStartTime},
+		{"invalid end", []ShiftTemplateSlot{{StartTime: "07:00", EndTime: "24:00"}}, ErrInvalidEndTime},
+		{"empty slot", []ShiftTemplateSlot{{StartTime: "07:00", EndTime: "07:00"}}, ErrShiftTemplateEmptySlot},
+		{"invalid day", []ShiftTemplateSlot{{StartTime: "07:00", EndTime: "19:00", Days: []DayOfWeek{7}}}, ErrInvalidDayOfWeek},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := CreateShiftTemplateInput{Nome: "Modelo", Slots: tt.slots}
+			if err := input.Validate(); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -321,3 +321,67 @@ func TestUserCanManageShifts(t *testing.T) {
 		})
 	}
 }
+
+// TestShiftOverlaps tests overlap detection between shifts of the same operator
+func TestShiftOverlaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        Shift
+		b        Shift
+		expected bool
+	}{
+		{
+			name:     "Day shifts overlapping on the same day",
+			a:        Shift{DayOfWeek: Monday, StartTime: "07:00", EndTime: "13:00"},
+			b:        Shift{DayOfWeek: Monday, StartTime: "12:00", EndTime: "19:00"},
+			expected: true,
+		},
+		{
+			name:     "Day shift contained in another",
+			a:        Shift{DayOfWeek: Monday, StartTime: "07:00", EndTime: "19:00"},
+			b:        Shift{DayOfWeek: Monday, StartTime: "10:00", EndTime: "11:00"},
+			expected: true,
+		},
+		{
+			name:     "Adjacent day and night shifts do not overlap",
+			a:        Shift{DayOfWeek: Monday, StartTime: "07:00", EndTime: "19:00"},
+			b:        Shift{DayOfWeek: Monday, StartTime: "19:00", EndTime: "07:00"},
+			expected: false,
+		},
+		{
+			name:     "Same hours on different days do not overlap",
+			a:        Shift{DayOfWeek: Monday, StartTime: "07:00", EndTime: "19:00"},
+			b:        Shift{DayOfWeek: Tuesday, StartTime: "07:00", EndTime: "19:00"},
+			expected: false,
+		},
+		{
+			name:     "Night shift overlaps early morning of the next day",
+			a:        Shift{DayOfWeek: Monday, StartTime: "19:00", EndTime: "07:00"},
+			b:        Shift{DayOfWeek: Tuesday, StartTime: "06:00", EndTime: "12:00"},
+			expected: true,
+		},
+		{
+			name:     "Night shift does not overlap early morning of its own day",
+			a:        Shift{DayOfWeek: Monday, StartTime: "19:00", EndTime: "07:00"},
+			b:        Shift{DayOfWeek: Monday, StartTime: "05:00", EndTime: "08:00"},
+			expected: false,
+		},
+		{
+			name:     "Saturday night shift wraps into Sunday",
+			a:        Shift{DayOfWeek: Saturday, StartTime: "22:00", EndTime: "06:00"},
+			b:        Shift{DayOfWeek: Sunday, StartTime: "00:00", EndTime: "04:00"},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Overlaps(&tt.b); got != tt.expected {
+				t.Errorf("a.Overlaps(b) = %v, expected %v", got, tt.expected)
+			}
+			if got := tt.b.Overlaps(&tt.a); got != tt.expected {
+				t.Errorf("b.Overlaps(a) = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,6 +25,27 @@ const (
 	SettingKeySMTPConfig   = "smtp_config"
 	SettingKeyTwilioConfig = "twilio_config"
 	SettingKeyFCMConfig    = "fcm_config"
+
+	SettingKeyPasswordPolicy = "password_policy"
+
+	SettingKeyImpersonationPolicy = "impersonation_policy"
+
+	SettingKeyMFAPolicy = "mfa_policy"
+
+	SettingKeyTokenDurations = "token_durations"
+
+	SettingKeyEmailVerification = "email_verification"
+
+	SettingKeyOccurrenceDataEncryption = "occurrence_data_encryption"
+
+	// SettingKeyEncryptionKeyVersion records the key version encrypted settings were last rotated to
+	SettingKeyEncryptionKeyVersion = "encryption_key_version"
+
+	SettingKeyHealthLatencyThresholds = "health_latency_thresholds"
+
+	SettingKeyOccurrenceAutoAssign = "occurrence_auto_assign"
+
+	SettingKeyUrgencyThresholds = "urgency_thresholds"
 )
 
 // SMTPConfig represents the SMTP configuration for email sending
@@ -48,6 +70,232 @@ type FCMConfig struct {
 	ServerKey string `json:"server_key"`
 }
 
+// PasswordPolicyConfig represents the password strength rules enforced on user passwords
+type PasswordPolicyConfig struct {
+	MinLength     int  `json:"min_length"`
+	RequireUpper  bool `json:"require_upper"`
+	RequireDigit  bool `json:"require_digit"`
+	RequireSymbol bool `json:"require_symbol"`
+}
+
+// ImpersonationPolicyConfig controls impersonation tokens issued to super admins
+type ImpersonationPolicyConfig struct {
+	DurationMinutes int `json:"duration_minutes"`
+}
+
+// MFAPolicyConfig lists the roles whose users must sign in with a TOTP code
+type MFAPolicyConfig struct {
+	RequiredRoles []UserRole `json:"required_roles"`
+}
+
+// EmailVerificationConfig controls whether newly created users must confirm their email before signing in
+// Unverified users can still sign in during the grace period after their creation
+type EmailVerificationConfig struct {
+	Enabled    bool `json:"enabled"`
+	GraceHours int  `json:"grace_hours"`
+}
+
+// TokenDurationsConfig overrides the access and refresh token durations by role
+// Omitted roles and zero values keep the global durations
+type TokenDurationsConfig struct {
+	Roles map[UserRole]RoleTokenDurations `json:"roles"`
+}
+
+// RoleTokenDurations are the token durations of a role
+type RoleTokenDurations struct {
+	AccessMinutes int `json:"access_minutes,omitempty"`
+	RefreshHours  int `json:"refresh_hours,omitempty"`
+}
+
+// OccurrenceDataEncryptionConfig controls encryption at rest of occurrence dados_completos
+// Only new occurrences are encrypted; existing plaintext rows remain readable
+type OccurrenceDataEncryptionConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// OccurrenceAutoAssignConfig assigns new occurrences to the operator on duty at their hospital
+// The policy applies to every hospital of the listed tenants and to the listed hospitals
+type OccurrenceAutoAssignConfig struct {
+	TenantIDs   []uuid.UUID `json:"tenant_ids,omitempty"`
+	HospitalIDs []uuid.UUID `json:"hospital_ids,omitempty"`
+}
+
+// AppliesTo reports whether new occurrences of the hospital are auto-assigned
+func (c *OccurrenceAutoAssignConfig) AppliesTo(tenantID, hospitalID uuid.UUID) bool {
+	for _, id := range c.TenantIDs {
+		if id == tenantID {
+			return true
+		}
+	}
+	for _, id := range c.HospitalIDs {
+		if id == hospitalID {
+			return true
+		}
+	}
+	return false
+}
+
+// UrgencyThresholdsConfig sets the time bands of the occurrence urgency levels
+// Tenants without an entry use Default, or the built-in bands when Default is omitted
+type UrgencyThresholdsConfig struct {
+	Default *UrgencyThresholds              `json:"default,omitempty"`
+	Tenants map[uuid.UUID]UrgencyThresholds `json:"tenants,omitempty"`
+}
+
+// For returns the urgency thresholds of a tenant
+func (c *UrgencyThresholdsConfig) For(tenantID uuid.UUID) UrgencyThresholds {
+	if c == nil {
+		return DefaultUrgencyThresholds()
+	}
+	if thresholds, ok := c.Tenants[tenantID]; ok {
+		return thresholds
+	}
+	if c.Default != nil {
+		return *c.Default
+	}
+	return DefaultUrgencyThresholds()
+}
+
+// Validate validates the default and every tenant's thresholds
+func (c *UrgencyThresholdsConfig) Validate() error {
+	if c.Default != nil {
+		if err := c.Default.Validate(); err != nil {
+			return err
+		}
+	}
+	for tenantID, thresholds := range c.Tenants {
+		if err := thresholds.Validate(); err != nil {
+			return fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+	}
+	return nil
+}
+
+// EncryptionKeyVersionConfig is the key-version tag written by a key rotation
+type EncryptionKeyVersionConfig struct {
+	Version   int       `json:"version"`
+	RotatedAt time.Time `json:"rotated_at"`
+}
+
+// LatencyThreshold sets the latencies at which a health-checked component is degraded and down
+// Latencies below OKMs are up, below DegradedMs degraded, and anything slower is down
+type LatencyThreshold struct {
+	OKMs       int64 `json:"ok_ms"`
+	DegradedMs int64 `json:"degraded_ms"`
+}
+
+// HealthLatencyThresholdsConfig maps health component keys (e.g. "database", "redis") to their latency thresholds
+// Components without an entry use the health monitor defaults
+type HealthLatencyThresholdsConfig map[string]LatencyThreshold
+
+// Validate validates every component threshold
+func (c HealthLatencyThresholdsConfig) Validate() error {
+	for component, threshold := range c {
+		if component == "" {
+			return errors.New("health latency thresholds must have a component key")
+		}
+		if threshold.OKMs <= 0 || threshold.DegradedMs <= threshold.OKMs {
+			return fmt.Errorf("health latency thresholds for %s must have 0 < ok_ms < degraded_ms", component)
+		}
+	}
+	return nil
+}
+
+// Password length bounds accepted by a password policy (bcrypt limit is 72 bytes)
+const (
+	PasswordPolicyMinLengthFloor   = 8
+	PasswordPolicyMinLengthCeiling = 72
+)
+
+// DefaultPasswordPolicyConfig returns the policy applied when no password_policy setting exists
+func DefaultPasswordPolicyConfig() PasswordPolicyConfig {
+	return PasswordPolicyConfig{
+		MinLength: PasswordPolicyMinLengthFloor,
+	}
+}
+
+// Validate validates the password policy bounds
+func (p *PasswordPolicyConfig) Validate() error {
+	if p.MinLength < PasswordPolicyMinLengthFloor || p.MinLength > PasswordPolicyMinLengthCeiling {
+		return errors.New("password policy min_length must be between 8 and 72")
+	}
+	return nil
+}
+
+// Validate validates the roles of the MFA policy
+func (p *MFAPolicyConfig) Validate() error {
+	for _, role := range p.RequiredRoles {
+		if !role.IsValid() {
+			return fmt.Errorf("mfa policy has invalid role %q", role)
+		}
+	}
+	return nil
+}
+
+// RequiresRole reports whether users of the role must sign in with a TOTP code
+func (p MFAPolicyConfig) RequiresRole(role string) bool {
+	for _, required := range p.RequiredRoles {
+		if string(required) == role {
+			return true
+		}
+	}
+	return false
+}
+
+// EmailVerificationMaxGraceHours is the longest grace period of unverified users
+const EmailVerificationMaxGraceHours = 30 * 24
+
+// Validate validates the grace period of the email verification
+func (c *EmailVerificationConfig) Validate() error {
+	if c.GraceHours < 0 || c.GraceHours > EmailVerificationMaxGraceHours {
+		return fmt.Errorf("email verification grace_hours must be between 0 and %d", EmailVerificationMaxGraceHours)
+	}
+	return nil
+}
+
+// Token duration bounds of the role overrides
+const (
+	TokenAccessMaxMinutes = 24 * 60
+	TokenRefreshMaxHours  = 90 * 24
+)
+
+// Validate validates the roles and bounds of the token duration overrides
+func (c *TokenDurationsConfig) Validate() error {
+	for role, durations := range c.Roles {
+		if !role.IsValid() {
+			return fmt.Errorf("token durations have invalid role %q", role)
+		}
+		if durations.AccessMinutes < 0 || durations.AccessMinutes > TokenAccessMaxMinutes {
+			return fmt.Errorf("token durations access_minutes of %s must be between 0 and %d", role, TokenAccessMaxMinutes)
+		}
+		if durations.RefreshHours < 0 || durations.RefreshHours > TokenRefreshMaxHours {
+			return fmt.Errorf("token durations refresh_hours of %s must be between 0 and %d", role, TokenRefreshMaxHours)
+		}
+	}
+	return nil
+}
+
+// Impersonation duration bounds; longer durations are clamped to the maximum
+const (
+	ImpersonationDefaultDurationMinutes = 60
+	ImpersonationMaxDurationMinutes     = 240
+)
+
+// DefaultImpersonationPolicyConfig returns the policy applied when no impersonation_policy setting exists
+func DefaultImpersonationPolicyConfig() ImpersonationPolicyConfig {
+	return ImpersonationPolicyConfig{
+		DurationMinutes: ImpersonationDefaultDurationMinutes,
+	}
+}
+
+// Validate validates the impersonation policy
+func (p *ImpersonationPolicyConfig) Validate() error {
+	if p.DurationMinutes < 1 {
+		return errors.New("impersonation policy duration_minutes must be at least 1")
+	}
+	return nil
+}
+
 // SystemSetting represents a global system configuration
 type SystemSetting struct {
 	ID          uuid.UUID       `json:"id" db:"id"`
@@ -206,6 +454,110 @@ func (s *SystemSetting) GetFCMConfig() (*FCMConfig, error) {
 	return &config, nil
 }
 
+// GetPasswordPolicyConfig parses the value as PasswordPolicyConfig
+// Omitted fields keep the default policy values
+func (s *SystemSetting) GetPasswordPolicyConfig() (*PasswordPolicyConfig, error) {
+	config := DefaultPasswordPolicyConfig()
+	if err := json.Unmarshal(s.Value, &config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetImpersonationPolicyConfig parses the value as ImpersonationPolicyConfig
+// Omitted fields keep the default policy values
+func (s *SystemSetting) GetImpersonationPolicyConfig() (*ImpersonationPolicyConfig, error) {
+	config := DefaultImpersonationPolicyConfig()
+	if err := json.Unmarshal(s.Value, &config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetMFAPolicyConfig parses the value as MFAPolicyConfig
+func (s *SystemSetting) GetMFAPolicyConfig() (*MFAPolicyConfig, error) {
+	var config MFAPolicyConfig
+	if err := json.Unmarshal(s.Value, &config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetEmailVerificationConfig parses the value as EmailVerificationConfig
+func (s *SystemSetting) GetEmailVerificationConfig() (*EmailVerificationConfig, error) {
+	var config EmailVerificationConfig
+	if err := json.Unmarshal(s.Value, &config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetTokenDurationsConfig parses the value as TokenDurationsConfig
+func (s *SystemSetting) GetTokenDurationsConfig() (*TokenDurationsConfig, error) {
+	var config TokenDurationsConfig
+	if err := json.Unmarshal(s.Value, &config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetHealthLatencyThresholdsConfig parses the value as HealthLatencyThresholdsConfig
+func (s *SystemSetting) GetHealthLatencyThresholdsConfig() (HealthLatencyThresholdsConfig, error) {
+	var config HealthLatencyThresholdsConfig
+	if err := json.Unmarshal(s.Value, &config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// GetOccurrenceDataEncryptionConfig parses the value as OccurrenceDataEncryptionConfig
+func (s *SystemSetting) GetOccurrenceDataEncryptionConfig() (*OccurrenceDataEncryptionConfig, error) {
+	var config OccurrenceDataEncryptionConfig
+	if err := json.Unmarshal(s.Value, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetOccurrenceAutoAssignConfig parses the value as OccurrenceAutoAssignConfig
+func (s *SystemSetting) GetOccurrenceAutoAssignConfig() (*OccurrenceAutoAssignConfig, error) {
+	var config OccurrenceAutoAssignConfig
+	if err := json.Unmarshal(s.Value, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// GetUrgencyThresholdsConfig parses the value as UrgencyThresholdsConfig
+func (s *SystemSetting) GetUrgencyThresholdsConfig() (*UrgencyThresholdsConfig, error) {
+	var config UrgencyThresholdsConfig
+	if err := json.Unmarshal(s.Value, &config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
 // SetValue sets the value from a struct
 func (s *SystemSetting) SetValue(value interface{}) error {
 	data, err := json.Marshal(value)
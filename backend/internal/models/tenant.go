@@ -3,6 +3,8 @@ package models
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
 	"regexp"
 	"time"
 
@@ -22,8 +24,35 @@ var (
 	// ErrTenantInactive is returned when trying to access an inactive tenant
 	ErrTenantInactive = errors.New("tenant is inactive")
 
+	// ErrInvalidThemeConfig is returned when a theme configuration fails validation
+	ErrInvalidThemeConfig = errors.New("invalid theme config")
+
+	// ErrInvalidAllowedOrigin is returned when a tenant allowed CORS origin is invalid
+	ErrInvalidAllowedOrigin = errors.New("invalid allowed origin: must be an http(s) scheme and host without path")
+
 	// slugRegex validates tenant slugs: alphanumeric with hyphens, no leading/trailing hyphens
 	slugRegex = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+	// hexColorRegex validates theme colors: #RGB or #RRGGBB
+	hexColorRegex = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+	// iconNameRegex validates sidebar icon names (Lucide names such as "LayoutDashboard" or "file-text")
+	iconNameRegex = regexp.MustCompile(`^[A-Za-z0-9-]{1,64}$`)
+)
+
+// MaxAllowedOrigins bounds the CORS origins a tenant can register
+const MaxAllowedOrigins = 20
+
+// Theme config limits, keeping tenant theme_config payloads bounded
+const (
+	MaxThemeSidebarItems      = 50
+	MaxThemeSidebarItemRoles  = 10
+	MaxThemeDashboardWidgets  = 30
+	MaxThemeLabelLength       = 100
+	MaxThemeLinkLength        = 500
+	MaxThemeIconLength        = 64
+	MaxThemeFontLength        = 100
+	MaxThemeWidgetConfigBytes = 4096
 )
 
 // ThemeColors represents the color configuration for a tenant's UI theme
@@ -121,8 +150,10 @@ type Tenant struct {
 	IsActive    bool            `json:"is_active" db:"is_active"`
 	LogoURL     *string         `json:"logo_url,omitempty" db:"logo_url"`
 	FaviconURL  *string         `json:"favicon_url,omitempty" db:"favicon_url"`
-	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+	// AllowedOrigins are the CORS origins of the tenant dashboard domains, allowed besides the global CORS_ORIGINS
+	AllowedOrigins []string  `json:"allowed_origins" db:"allowed_origins"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // CreateTenantInput represents input for creating a tenant
@@ -141,6 +172,13 @@ type UpdateTenantInput struct {
 	IsActive   *bool   `json:"is_active,omitempty"`
 	LogoURL    *string `json:"logo_url,omitempty"`
 	FaviconURL *string `json:"favicon_url,omitempty"`
+	// AllowedOrigins replaces the tenant CORS origins when present; an empty list clears them
+	AllowedOrigins []string `json:"allowed_origins,omitempty"`
+}
+
+// PurgeTenantInput confirms the purge of the data of a tenant with its slug
+type PurgeTenantInput struct {
+	ConfirmationToken string `json:"confirmation_token" validate:"required"`
 }
 
 // UpdateThemeConfigInput represents input for updating tenant theme configuration
@@ -148,6 +186,17 @@ type UpdateThemeConfigInput struct {
 	ThemeConfig ThemeConfig `json:"theme_config" validate:"required"`
 }
 
+// TenantThemeVersion is a prior theme configuration of a tenant, archived when it was replaced
+type TenantThemeVersion struct {
+	ID            uuid.UUID       `json:"id" db:"id"`
+	TenantID      uuid.UUID       `json:"tenant_id" db:"tenant_id"`
+	Version       int             `json:"version" db:"version"`
+	ThemeConfig   json.RawMessage `json:"theme_config" db:"theme_config"`
+	CreatedBy     *uuid.UUID      `json:"created_by,omitempty" db:"created_by"`
+	CreatedByNome *string         `json:"created_by_nome,omitempty"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
+
 // TenantResponse represents the API response for a tenant
 type TenantResponse struct {
 	ID          uuid.UUID       `json:"id"`
@@ -157,8 +206,10 @@ type TenantResponse struct {
 	IsActive    bool            `json:"is_active"`
 	LogoURL     *string         `json:"logo_url,omitempty"`
 	FaviconURL  *string         `json:"favicon_url,omitempty"`
-	CreatedAt   time.Time       `json:"created_at"`
-	UpdatedAt   time.Time       `json:"updated_at"`
+	// AllowedOrigins are the CORS origins of the tenant dashboard domains
+	AllowedOrigins []string  `json:"allowed_origins"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 // TenantWithMetrics represents a tenant with additional metrics for admin views
@@ -211,8 +262,10 @@ func (t *Tenant) ToResponse() TenantResponse {
 		IsActive:    t.IsActive,
 		LogoURL:     t.LogoURL,
 		FaviconURL:  t.FaviconURL,
-		CreatedAt:   t.CreatedAt,
-		UpdatedAt:   t.UpdatedAt,
+		// Never null in the response, so clients can render an empty list
+		AllowedOrigins: append([]string{}, t.AllowedOrigins...),
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
 	}
 }
 
@@ -256,6 +309,12 @@ func (i *CreateTenantInput) Validate() error {
 		return errors.New("tenant name must be between 2 and 255 characters")
 	}
 
+	if i.ThemeConfig != nil {
+		if err := ValidateThemeConfig(i.ThemeConfig); err != nil {
+			return err
+		}
+	}
+
 	return ValidateSlug(i.Slug)
 }
 
@@ -266,44 +325,131 @@ func (i *UpdateTenantInput) Validate() error {
 	}
 
 	if i.Slug != nil {
-		return ValidateSlug(*i.Slug)
+		if err := ValidateSlug(*i.Slug); err != nil {
+			return err
+		}
+	}
+
+	if i.AllowedOrigins != nil {
+		return ValidateAllowedOrigins(i.AllowedOrigins)
+	}
+
+	return nil
+}
+
+// ValidateAllowedOrigins validates tenant CORS origins: each must be a bare http(s) origin
+// such as "https://painel.ses-go.gov.br", since browsers send the Origin header without path
+func ValidateAllowedOrigins(origins []string) error {
+	if len(origins) > MaxAllowedOrigins {
+		return fmt.Errorf("%w: at most %d origins", ErrInvalidAllowedOrigin, MaxAllowedOrigins)
+	}
+
+	for _, origin := range origins {
+		parsed, err := url.Parse(origin)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+			parsed.Path != "" || parsed.RawQuery != "" || parsed.Fragment != "" || parsed.User != nil {
+			return fmt.Errorf("%w: %q", ErrInvalidAllowedOrigin, origin)
+		}
 	}
 
 	return nil
 }
 
 // ValidateThemeConfig validates the theme configuration structure
+// Besides required fields it bounds list sizes and string lengths, so the stored JSONB stays small
 func ValidateThemeConfig(config *ThemeConfig) error {
 	if config == nil {
-		return errors.New("theme_config cannot be nil")
+		return fmt.Errorf("%w: theme_config cannot be nil", ErrInvalidThemeConfig)
 	}
 
 	// Validate colors
 	if config.Theme.Colors.Primary == "" {
-		return errors.New("theme primary color is required")
+		return fmt.Errorf("%w: theme primary color is required", ErrInvalidThemeConfig)
+	}
+	colors := []struct {
+		name  string
+		value string
+	}{
+		{"primary", config.Theme.Colors.Primary},
+		{"secondary", config.Theme.Colors.Secondary},
+		{"background", config.Theme.Colors.Background},
+		{"foreground", config.Theme.Colors.Foreground},
+		{"muted", config.Theme.Colors.Muted},
+		{"accent", config.Theme.Colors.Accent},
+	}
+	for _, color := range colors {
+		if color.value != "" && !hexColorRegex.MatchString(color.value) {
+			return fmt.Errorf("%w: theme %s color %q must be a hex color (#RGB or #RRGGBB)", ErrInvalidThemeConfig, color.name, truncateForError(color.value))
+		}
 	}
 
 	// Validate fonts
 	if config.Theme.Fonts.Body == "" {
-		return errors.New("theme body font is required")
+		return fmt.Errorf("%w: theme body font is required", ErrInvalidThemeConfig)
+	}
+	if len(config.Theme.Fonts.Body) > MaxThemeFontLength || len(config.Theme.Fonts.Heading) > MaxThemeFontLength {
+		return fmt.Errorf("%w: theme font names must be at most %d characters", ErrInvalidThemeConfig, MaxThemeFontLength)
 	}
 
 	// Validate sidebar items
+	if len(config.Layout.Sidebar) > MaxThemeSidebarItems {
+		return fmt.Errorf("%w: sidebar has %d items, maximum is %d", ErrInvalidThemeConfig, len(config.Layout.Sidebar), MaxThemeSidebarItems)
+	}
 	for i, item := range config.Layout.Sidebar {
 		if item.Label == "" {
-			return errors.New("sidebar item label is required at index " + string(rune('0'+i)))
+			return fmt.Errorf("%w: sidebar item label is required at index %d", ErrInvalidThemeConfig, i)
+		}
+		if len(item.Label) > MaxThemeLabelLength {
+			return fmt.Errorf("%w: sidebar item label at index %d exceeds %d characters", ErrInvalidThemeConfig, i, MaxThemeLabelLength)
 		}
 		if item.Link == "" {
-			return errors.New("sidebar item link is required at index " + string(rune('0'+i)))
+			return fmt.Errorf("%w: sidebar item link is required at index %d", ErrInvalidThemeConfig, i)
+		}
+		if len(item.Link) > MaxThemeLinkLength {
+			return fmt.Errorf("%w: sidebar item link at index %d exceeds %d characters", ErrInvalidThemeConfig, i, MaxThemeLinkLength)
+		}
+		if item.Icon != "" && !iconNameRegex.MatchString(item.Icon) {
+			return fmt.Errorf("%w: sidebar item icon at index %d must be 1-%d letters, digits or hyphens", ErrInvalidThemeConfig, i, MaxThemeIconLength)
+		}
+		if len(item.Roles) > MaxThemeSidebarItemRoles {
+			return fmt.Errorf("%w: sidebar item at index %d has more than %d roles", ErrInvalidThemeConfig, i, MaxThemeSidebarItemRoles)
+		}
+		for _, role := range item.Roles {
+			if len(role) > MaxThemeLabelLength {
+				return fmt.Errorf("%w: sidebar item role at index %d exceeds %d characters", ErrInvalidThemeConfig, i, MaxThemeLabelLength)
+			}
 		}
 	}
 
 	// Validate dashboard widgets
+	if len(config.Layout.DashboardWidgets) > MaxThemeDashboardWidgets {
+		return fmt.Errorf("%w: dashboard has %d widgets, maximum is %d", ErrInvalidThemeConfig, len(config.Layout.DashboardWidgets), MaxThemeDashboardWidgets)
+	}
 	for i, widget := range config.Layout.DashboardWidgets {
 		if widget.Type == "" {
-			return errors.New("dashboard widget type is required at index " + string(rune('0'+i)))
+			return fmt.Errorf("%w: dashboard widget type is required at index %d", ErrInvalidThemeConfig, i)
+		}
+		if len(widget.Type) > MaxThemeLabelLength {
+			return fmt.Errorf("%w: dashboard widget type at index %d exceeds %d characters", ErrInvalidThemeConfig, i, MaxThemeLabelLength)
+		}
+		if len(widget.Config) > 0 {
+			data, err := json.Marshal(widget.Config)
+			if err != nil {
+				return fmt.Errorf("%w: dashboard widget config at index %d is not valid JSON", ErrInvalidThemeConfig, i)
+			}
+			if len(data) > MaxThemeWidgetConfigBytes {
+				return fmt.Errorf("%w: dashboard widget config at index %d exceeds %d bytes", ErrInvalidThemeConfig, i, MaxThemeWidgetConfigBytes)
+			}
 		}
 	}
 
 	return nil
 }
+
+// truncateForError shortens a user-provided value before it is echoed in an error message
+func truncateForError(value string) string {
+	if len(value) > 20 {
+		return value[:20] + "..."
+	}
+	return value
+}
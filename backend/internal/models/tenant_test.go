@@ -124,3 +124,33 @@ func TestTenant_ToResponse(t *testing.T) {
 	assert.Equal(t, tenant.CreatedAt, resp.CreatedAt)
 	assert.Equal(t, tenant.UpdatedAt, resp.UpdatedAt)
 }
+
+func TestValidateAllowedOrigins(t *testing.T) {
+	testCases := []struct {
+		name    string
+		origins []string
+		wantErr bool
+	}{
+		{"empty list", []string{}, false},
+		{"https origin", []string{"https://painel.ses-go.gov.br"}, false},
+		{"origin with port", []string{"http://localhost:3000"}, false},
+
+		{"wildcard", []string{"*"}, true},
+		{"missing scheme", []string{"painel.ses-go.gov.br"}, true},
+		{"unsupported scheme", []string{"ftp://painel.ses-go.gov.br"}, true},
+		{"with path", []string{"https://painel.ses-go.gov.br/dashboard"}, true},
+		{"trailing slash", []string{"https://painel.ses-go.gov.br/"}, true},
+		{"too many", make([]string, MaxAllowedOrigins+1), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateAllowedOrigins(tc.origins)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAllowedOrigin)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
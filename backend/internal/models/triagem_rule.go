@@ -1,12 +1,21 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrInvalidRuleConfig is returned when a rule's regras does not match the schema for its tipo
+var ErrInvalidRuleConfig = errors.New("invalid rule configuration")
+
 // TriagemRule represents a triagem rule configuration
 type TriagemRule struct {
 	ID         uuid.UUID       `json:"id" db:"id"`
@@ -37,6 +46,7 @@ const (
 	RuleActionRejeitar   RuleAction = "rejeitar"
 	RuleActionPriorizar  RuleAction = "priorizar"
 	RuleActionAlertar    RuleAction = "alertar"
+	RuleActionFlag       RuleAction = "flag" // Keeps the obito eligible and attaches a warning to its occurrence
 )
 
 // RuleConfig represents the configuration of a single rule
@@ -125,6 +135,50 @@ func (r *TriagemRule) ToResponse() TriagemRuleResponse {
 	}
 }
 
+// Checksum fingerprints the rule fields copied from a template when it is cloned
+// Regras is normalized first, so the value does not change when JSONB reformats it
+func (r *TriagemRule) Checksum() (string, error) {
+	return triagemRuleChecksum(r.Nome, r.Descricao, r.Regras, r.Ativo, r.Prioridade)
+}
+
+// ModifiedSinceClone reports whether the rule differs from its state when cloned from a template
+// It returns nil when the clone-time checksum is unknown (rules linked to a template by the 034 migration)
+func (r *TriagemRule) ModifiedSinceClone(cloneChecksum *string) (*bool, error) {
+	if cloneChecksum == nil {
+		return nil, nil
+	}
+	current, err := r.Checksum()
+	if err != nil {
+		return nil, err
+	}
+	modified := current != *cloneChecksum
+	return &modified, nil
+}
+
+// triagemRuleChecksum returns the hex SHA-256 of a rule's cloned fields
+func triagemRuleChecksum(nome string, descricao *string, regras json.RawMessage, ativo bool, prioridade int) (string, error) {
+	var normalized interface{}
+	if len(regras) > 0 {
+		if err := json.Unmarshal(regras, &normalized); err != nil {
+			return "", err
+		}
+	}
+
+	data, err := json.Marshal(struct {
+		Nome       string      `json:"nome"`
+		Descricao  *string     `json:"descricao"`
+		Regras     interface{} `json:"regras"`
+		Ativo      bool        `json:"ativo"`
+		Prioridade int         `json:"prioridade"`
+	}{nome, descricao, normalized, ativo, prioridade})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // ParseRuleConfig parses the rule configuration from JSON
 func (r *TriagemRule) ParseRuleConfig() (*RuleConfig, error) {
 	var config RuleConfig
@@ -134,6 +188,126 @@ func (r *TriagemRule) ParseRuleConfig() (*RuleConfig, error) {
 	return &config, nil
 }
 
+// ValidateRuleConfig parses a rule configuration from JSON and validates it against the schema for its tipo
+func ValidateRuleConfig(regras json.RawMessage) (*RuleConfig, error) {
+	var config RuleConfig
+	if err := json.Unmarshal(regras, &config); err != nil {
+		return nil, fmt.Errorf("%w: regras must be a JSON object: %v", ErrInvalidRuleConfig, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// IsValid checks if the rule type is known by the triagem motor
+func (t RuleType) IsValid() bool {
+	switch t {
+	case RuleTypeIdadeMaxima, RuleTypeCausasExcludentes, RuleTypeJanelaHoras,
+		RuleTypeIdentificacaoDesconhecida, RuleTypeSetorPriorizacao:
+		return true
+	}
+	return false
+}
+
+// IsValid checks if the rule action is valid
+func (a RuleAction) IsValid() bool {
+	switch a {
+	case RuleActionRejeitar, RuleActionPriorizar, RuleActionAlertar, RuleActionFlag:
+		return true
+	}
+	return false
+}
+
+// Validate checks that valor has the shape the triagem motor expects for the rule tipo
+func (c *RuleConfig) Validate() error {
+	if c.Tipo == "" {
+		return fmt.Errorf("%w: tipo is required", ErrInvalidRuleConfig)
+	}
+	if !c.Tipo.IsValid() {
+		return fmt.Errorf("%w: unknown tipo %q", ErrInvalidRuleConfig, c.Tipo)
+	}
+	if !c.Acao.IsValid() {
+		return fmt.Errorf("%w: acao must be one of rejeitar, priorizar, alertar, flag", ErrInvalidRuleConfig)
+	}
+
+	switch c.Tipo {
+	case RuleTypeIdadeMaxima, RuleTypeJanelaHoras:
+		if n, ok := c.Valor.(float64); !ok || n <= 0 || n != math.Trunc(n) {
+			return fmt.Errorf("%w: valor for %s must be a positive integer", ErrInvalidRuleConfig, c.Tipo)
+		}
+
+	case RuleTypeIdentificacaoDesconhecida:
+		if _, ok := c.Valor.(bool); !ok {
+			return fmt.Errorf("%w: valor for %s must be a boolean", ErrInvalidRuleConfig, c.Tipo)
+		}
+
+	case RuleTypeCausasExcludentes:
+		causas, ok := c.Valor.([]interface{})
+		if !ok || len(causas) == 0 {
+			return fmt.Errorf("%w: valor for %s must be a non-empty array of strings", ErrInvalidRuleConfig, c.Tipo)
+		}
+		for i, causa := range causas {
+			if s, ok := causa.(string); !ok || s == "" {
+				return fmt.Errorf("%w: valor[%d] for %s must be a non-empty string", ErrInvalidRuleConfig, i, c.Tipo)
+			}
+		}
+
+	case RuleTypeSetorPriorizacao:
+		setores, ok := c.Valor.(map[string]interface{})
+		if !ok || len(setores) == 0 {
+			return fmt.Errorf("%w: valor for %s must be a non-empty object of sector scores", ErrInvalidRuleConfig, c.Tipo)
+		}
+		for setor, score := range setores {
+			if n, ok := score.(float64); !ok || n < 0 || n > 100 || n != math.Trunc(n) {
+				return fmt.Errorf("%w: score for sector %q must be an integer between 0 and 100", ErrInvalidRuleConfig, setor)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SimulateTriagemRulesInput represents a dry-run request for a candidate rule set
+// Dates use the YYYY-MM-DD format; date_to is inclusive
+type SimulateTriagemRulesInput struct {
+	Rules    []CreateTriagemRuleInput `json:"rules" validate:"required,min=1,max=50,dive"`
+	DateFrom string                   `json:"date_from" validate:"required"`
+	DateTo   string                   `json:"date_to" validate:"required"`
+}
+
+// ToRules converts the candidate rules to in-memory rules, ordered like the active rule set
+// (prioridade DESC, nome ASC) and with the same defaults applied on creation
+func (i *SimulateTriagemRulesInput) ToRules() []TriagemRule {
+	rules := make([]TriagemRule, 0, len(i.Rules))
+	for _, input := range i.Rules {
+		rule := TriagemRule{
+			ID:         uuid.New(),
+			Nome:       input.Nome,
+			Descricao:  input.Descricao,
+			Regras:     input.Regras,
+			Ativo:      true,
+			Prioridade: 50,
+		}
+		if input.Ativo != nil {
+			rule.Ativo = *input.Ativo
+		}
+		if input.Prioridade != nil {
+			rule.Prioridade = *input.Prioridade
+		}
+		rules = append(rules, rule)
+	}
+
+	sort.SliceStable(rules, func(a, b int) bool {
+		if rules[a].Prioridade != rules[b].Prioridade {
+			return rules[a].Prioridade > rules[b].Prioridade
+		}
+		return rules[a].Nome < rules[b].Nome
+	})
+
+	return rules
+}
+
 // Default sector scores for prioritization
 var DefaultSectorScores = map[string]int{
 	"UTI":         100,
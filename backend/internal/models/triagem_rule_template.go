@@ -54,7 +54,7 @@ func (t TriagemRuleTemplateType) String() string {
 // TriagemRuleTemplateCondition represents the condition configuration for a rule template
 type TriagemRuleTemplateCondition struct {
 	Valor interface{} `json:"valor"`
-	Acao  string      `json:"acao"` // "rejeitar", "priorizar", "alertar"
+	Acao  string      `json:"acao"` // "rejeitar", "priorizar", "alertar", "flag"
 }
 
 // TriagemRuleTemplate represents a master triagem rule template (global, not tied to any tenant)
@@ -183,6 +183,11 @@ func (t *TriagemRuleTemplateWithUsage) ToWithUsageResponse() TriagemRuleTemplate
 	}
 }
 
+// CloneChecksum returns the checksum of the rule produced by cloning this template (see TriagemRule.Checksum)
+func (t *TriagemRuleTemplate) CloneChecksum() (string, error) {
+	return triagemRuleChecksum(t.Nome, t.Descricao, t.Condicao, t.Ativo, t.Prioridade)
+}
+
 // GetCondition parses and returns the condition
 func (t *TriagemRuleTemplate) GetCondition() (*TriagemRuleTemplateCondition, error) {
 	var condition TriagemRuleTemplateCondition
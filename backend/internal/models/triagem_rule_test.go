@@ -0,0 +1,129 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestValidateRuleConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		regras  string
+		wantErr bool
+	}{
+		{"valid idade_maxima", `{"tipo": "idade_maxima", "valor": 80, "acao": "rejeitar"}`, false},
+		{"valid janela_horas", `{"tipo": "janela_horas", "valor": 6, "acao": "rejeitar"}`, false},
+		{"valid identificacao_desconhecida", `{"tipo": "identificacao_desconhecida", "valor": true, "acao": "rejeitar"}`, false},
+		{"valid causas_excludentes", `{"tipo": "causas_excludentes", "valor": ["sepse", "hiv"], "acao": "rejeitar"}`, false},
+		{"valid setor_priorizacao", `{"tipo": "setor_priorizacao", "valor": {"UTI": 100, "Enfermaria": 50}, "acao": "priorizar"}`, false},
+		{"causas_excludentes with number", `{"tipo": "causas_excludentes", "valor": 42, "acao": "rejeitar"}`, true},
+		{"causas_excludentes empty", `{"tipo": "causas_excludentes", "valor": [], "acao": "rejeitar"}`, true},
+		{"causas_excludentes with non-string item", `{"tipo": "causas_excludentes", "valor": ["sepse", 1], "acao": "rejeitar"}`, true},
+		{"idade_maxima negative", `{"tipo": "idade_maxima", "valor": -1, "acao": "rejeitar"}`, true},
+		{"idade_maxima as string", `{"tipo": "idade_maxima", "valor": "80", "acao": "rejeitar"}`, true},
+		{"janela_horas fractional", `{"tipo": "janela_horas", "valor": 6.5, "acao": "rejeitar"}`, true},
+		{"identificacao_desconhecida as string", `{"tipo": "identificacao_desconhecida", "valor": "sim", "acao": "rejeitar"}`, true},
+		{"setor_priorizacao score above 100", `{"tipo": "setor_priorizacao", "valor": {"UTI": 150}, "acao": "priorizar"}`, true},
+		{"missing tipo", `{"valor": 80, "acao": "rejeitar"}`, true},
+		{"unknown tipo", `{"tipo": "peso_maximo", "valor": 80, "acao": "rejeitar"}`, true},
+		{"invalid acao", `{"tipo": "idade_maxima", "valor": 80, "acao": "ignorar"}`, true},
+		{"not an object", `[1, 2, 3]`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateRuleConfig(json.RawMessage(tt.regras))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateRuleConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRuleConfig) {
+				t.Errorf("Expected error to wrap ErrInvalidRuleConfig, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateRuleConfigDescribesProblem(t *testing.T) {
+	_, err := ValidateRuleConfig(json.RawMessage(`{"tipo": "causas_excludentes", "valor": 42, "acao": "rejeitar"}`))
+	if err == nil {
+		t.Fatal("Expected causas_excludentes with a numeric valor to be rejected")
+	}
+
+	expected := "invalid rule configuration: valor for causas_excludentes must be a non-empty array of strings"
+	if err.Error() != expected {
+		t.Errorf("Expected error %q, got %q", expected, err.Error())
+	}
+}
+
+func TestTriagemRuleModifiedSinceClone(t *testing.T) {
+	descricao := "Rejeita doadores acima de 80 anos"
+	template := TriagemRuleTemplate{
+		Nome:       "Idade maxima",
+		Condicao:   json.RawMessage(`{"tipo": "idade_maxima", "valor": 80, "acao": "rejeitar"}`),
+		Descricao:  &descricao,
+		Ativo:      true,
+		Prioridade: 10,
+	}
+	checksum, err := template.CloneChecksum()
+	if err != nil {
+		t.Fatalf("CloneChecksum() error = %v", err)
+	}
+
+	clone := func() TriagemRule {
+		return TriagemRule{
+			Nome:       template.Nome,
+			Descricao:  template.Descricao,
+			Regras:     template.Condicao,
+			Ativo:      template.Ativo,
+			Prioridade: template.Prioridade,
+		}
+	}
+	otherDescricao := "Outra descricao"
+
+	tests := []struct {
+		name     string
+		edit     func(r *TriagemRule)
+		modified bool
+	}{
+		{"unchanged clone", func(r *TriagemRule) {}, false},
+		{"regras reformatted by JSONB", func(r *TriagemRule) {
+			r.Regras = json.RawMessage(`{"acao":"rejeitar","tipo":"idade_maxima","valor":80}`)
+		}, false},
+		{"regras valor changed", func(r *TriagemRule) {
+			r.Regras = json.RawMessage(`{"tipo": "idade_maxima", "valor": 75, "acao": "rejeitar"}`)
+		}, true},
+		{"nome changed", func(r *TriagemRule) { r.Nome = "Idade maxima (local)" }, true},
+		{"descricao changed", func(r *TriagemRule) { r.Descricao = &otherDescricao }, true},
+		{"descricao removed", func(r *TriagemRule) { r.Descricao = nil }, true},
+		{"deactivated", func(r *TriagemRule) { r.Ativo = false }, true},
+		{"prioridade changed", func(r *TriagemRule) { r.Prioridade = 20 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := clone()
+			tt.edit(&rule)
+
+			modified, err := rule.ModifiedSinceClone(&checksum)
+			if err != nil {
+				t.Fatalf("ModifiedSinceClone() error = %v", err)
+			}
+			if modified == nil || *modified != tt.modified {
+				t.Errorf("Expected modified = %v, got %v", tt.modified, modified)
+			}
+		})
+	}
+}
+
+func TestTriagemRuleModifiedSinceCloneUnknownChecksum(t *testing.T) {
+	rule := TriagemRule{Nome: "Idade maxima", Regras: json.RawMessage(`{"tipo": "idade_maxima", "valor": 80, "acao": "rejeitar"}`)}
+
+	modified, err := rule.ModifiedSinceClone(nil)
+	if err != nil {
+		t.Fatalf("ModifiedSinceClone() error = %v", err)
+	}
+	if modified != nil {
+		t.Errorf("Expected nil when the clone checksum is unknown, got %v", *modified)
+	}
+}
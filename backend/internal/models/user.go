@@ -2,6 +2,7 @@ package models
 
 import (
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -37,6 +38,38 @@ func (r UserRole) String() string {
 	return string(r)
 }
 
+// Locales supported for user-facing messages such as notification emails
+const (
+	LocalePtBR = "pt-BR"
+	LocaleEn   = "en"
+)
+
+// DefaultLocale is the locale of users without a preference
+const DefaultLocale = LocalePtBR
+
+// ValidLocales contains all supported locales
+var ValidLocales = []string{LocalePtBR, LocaleEn}
+
+// NormalizeLocale returns the supported locale matching locale, ignoring case and
+// falling back to its language ("en-US" is en, "pt" is pt-BR), or DefaultLocale
+func NormalizeLocale(locale string) string {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	for _, valid := range ValidLocales {
+		if strings.EqualFold(locale, valid) {
+			return valid
+		}
+	}
+
+	language, _, _ := strings.Cut(locale, "-")
+	for _, valid := range ValidLocales {
+		validLanguage, _, _ := strings.Cut(valid, "-")
+		if strings.EqualFold(language, validLanguage) {
+			return valid
+		}
+	}
+	return DefaultLocale
+}
+
 // User represents a system user
 type User struct {
 	ID                 uuid.UUID  `json:"id" db:"id"`
@@ -48,9 +81,13 @@ type User struct {
 	IsSuperAdmin       bool       `json:"is_super_admin" db:"is_super_admin"`
 	MobilePhone        *string    `json:"mobile_phone,omitempty" db:"mobile_phone"`
 	EmailNotifications bool       `json:"email_notifications" db:"email_notifications"`
+	Locale             string     `json:"locale" db:"locale"`
 	Ativo              bool       `json:"ativo" db:"ativo"`
+	EmailVerified      bool       `json:"email_verified" db:"email_verified"`
 	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt          *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	LastLoginAt        *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
 
 	// Related data (populated by queries) - N:N relationship with hospitals
 	Hospitals []Hospital `json:"hospitals,omitempty" db:"-"`
@@ -128,6 +165,13 @@ type AdminUpdateUserRoleInput struct {
 	IsSuperAdmin *bool     `json:"is_super_admin,omitempty"`
 }
 
+// AdminImpersonateUserInput represents input for impersonating a user (super admin)
+// DurationMinutes is optional; it defaults to the impersonation_policy setting and is clamped to the maximum
+type AdminImpersonateUserInput struct {
+	Reason          string `json:"reason" validate:"required,max=500"`
+	DurationMinutes int    `json:"duration_minutes,omitempty" validate:"omitempty,min=1"`
+}
+
 // AdminBanUserInput represents input for banning/unbanning a user
 type AdminBanUserInput struct {
 	Banned    bool    `json:"banned"`
@@ -143,6 +187,10 @@ type CreateUserInput struct {
 	HospitalIDs        []uuid.UUID `json:"hospital_ids,omitempty"`
 	MobilePhone        *string     `json:"mobile_phone,omitempty"`
 	EmailNotifications *bool       `json:"email_notifications,omitempty"`
+	Locale             *string     `json:"locale,omitempty" validate:"omitempty,oneof=pt-BR en"`
+
+	// RequireEmailVerification creates the user with an unverified email (set from the email_verification setting)
+	RequireEmailVerification bool `json:"-"`
 }
 
 // UpdateUserInput represents input for updating a user (admin only)
@@ -153,12 +201,14 @@ type UpdateUserInput struct {
 	HospitalIDs        []uuid.UUID `json:"hospital_ids,omitempty"`
 	MobilePhone        *string     `json:"mobile_phone,omitempty"`
 	EmailNotifications *bool       `json:"email_notifications,omitempty"`
+	Locale             *string     `json:"locale,omitempty" validate:"omitempty,oneof=pt-BR en"`
 	Ativo              *bool       `json:"ativo,omitempty"`
 }
 
 // UpdateProfileInput represents input for updating own profile
 type UpdateProfileInput struct {
 	Nome            *string `json:"nome,omitempty" validate:"omitempty,min=2,max=255"`
+	Locale          *string `json:"locale,omitempty" validate:"omitempty,oneof=pt-BR en"`
 	CurrentPassword *string `json:"current_password,omitempty" validate:"omitempty,min=8,max=72"`
 	NewPassword     *string `json:"new_password,omitempty" validate:"omitempty,min=8,max=72"`
 }
@@ -174,9 +224,13 @@ type UserResponse struct {
 	Hospitals          []HospitalResponse `json:"hospitals"`
 	MobilePhone        *string            `json:"mobile_phone,omitempty"`
 	EmailNotifications bool               `json:"email_notifications"`
+	Locale             string             `json:"locale"`
 	Ativo              bool               `json:"ativo"`
+	EmailVerified      bool               `json:"email_verified"`
 	CreatedAt          time.Time          `json:"created_at"`
 	UpdatedAt          time.Time          `json:"updated_at"`
+	DeletedAt          *time.Time         `json:"deleted_at,omitempty"`
+	LastLoginAt        *time.Time         `json:"last_login_at,omitempty"`
 }
 
 // ToResponse converts User to UserResponse
@@ -191,9 +245,13 @@ func (u *User) ToResponse() UserResponse {
 		Hospitals:          make([]HospitalResponse, 0, len(u.Hospitals)),
 		MobilePhone:        u.MobilePhone,
 		EmailNotifications: u.EmailNotifications,
+		Locale:             u.PreferredLocale(),
 		Ativo:              u.Ativo,
+		EmailVerified:      u.EmailVerified,
 		CreatedAt:          u.CreatedAt,
 		UpdatedAt:          u.UpdatedAt,
+		DeletedAt:          u.DeletedAt,
+		LastLoginAt:        u.LastLoginAt,
 	}
 
 	for _, h := range u.Hospitals {
@@ -280,6 +338,11 @@ func (u *User) CanReceiveEmailNotifications() bool {
 	return u.Ativo && u.EmailNotifications
 }
 
+// PreferredLocale returns the locale of messages sent to the user, DefaultLocale when unset
+func (u *User) PreferredLocale() string {
+	return NormalizeLocale(u.Locale)
+}
+
 // CanManageShifts returns true if the user can create, update, or delete shifts
 // Admin can manage all shifts, Gestor can manage shifts for their hospital
 func (u *User) CanManageShifts() bool {
@@ -324,3 +387,12 @@ type UserListResult struct {
 	PerPage    int    `json:"per_page"`
 	TotalPages int    `json:"total_pages"`
 }
+
+// LoginHistoryEntry represents a successful login of a user
+type LoginHistoryEntry struct {
+	ID         uuid.UUID `json:"id"`
+	UserID     uuid.UUID `json:"user_id"`
+	IPAddress  *string   `json:"ip_address,omitempty"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	LoggedInAt time.Time `json:"logged_in_at"`
+}
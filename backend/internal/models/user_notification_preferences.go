@@ -1,20 +1,31 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Notification preferences validation errors
+var (
+	ErrInvalidQuietHoursStart = errors.New("quiet_hours_start must be in HH:MM format")
+	ErrInvalidQuietHoursEnd   = errors.New("quiet_hours_end must be in HH:MM format")
+	ErrIncompleteQuietHours   = errors.New("quiet_hours_start and quiet_hours_end must be set together")
+)
+
 // UserNotificationPreferences represents user preferences for notification channels
 type UserNotificationPreferences struct {
-	ID               uuid.UUID `json:"id" db:"id"`
-	UserID           uuid.UUID `json:"user_id" db:"user_id" validate:"required"`
-	SMSEnabled       bool      `json:"sms_enabled" db:"sms_enabled"`
-	EmailEnabled     bool      `json:"email_enabled" db:"email_enabled"`
-	DashboardEnabled bool      `json:"dashboard_enabled" db:"dashboard_enabled"`
-	CreatedAt        time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+	ID               uuid.UUID  `json:"id" db:"id"`
+	UserID           uuid.UUID  `json:"user_id" db:"user_id" validate:"required"`
+	SMSEnabled       bool       `json:"sms_enabled" db:"sms_enabled"`
+	EmailEnabled     bool       `json:"email_enabled" db:"email_enabled"`
+	PushEnabled      bool       `json:"push_enabled" db:"push_enabled"`
+	DashboardEnabled bool       `json:"dashboard_enabled" db:"dashboard_enabled"`
+	QuietHoursStart  *ShiftTime `json:"quiet_hours_start,omitempty" db:"quiet_hours_start"`
+	QuietHoursEnd    *ShiftTime `json:"quiet_hours_end,omitempty" db:"quiet_hours_end"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 // CreateNotificationPreferencesInput represents input for creating notification preferences
@@ -27,20 +38,50 @@ type CreateNotificationPreferencesInput struct {
 
 // UpdateNotificationPreferencesInput represents input for updating notification preferences
 // Note: DashboardEnabled is not included because it cannot be changed
+// Quiet hours are cleared by sending both fields as empty strings
 type UpdateNotificationPreferencesInput struct {
-	SMSEnabled   *bool `json:"sms_enabled,omitempty"`
-	EmailEnabled *bool `json:"email_enabled,omitempty"`
+	SMSEnabled      *bool      `json:"sms_enabled,omitempty"`
+	EmailEnabled    *bool      `json:"email_enabled,omitempty"`
+	PushEnabled     *bool      `json:"push_enabled,omitempty"`
+	QuietHoursStart *ShiftTime `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd   *ShiftTime `json:"quiet_hours_end,omitempty"`
+}
+
+// Validate validates the update input
+func (i *UpdateNotificationPreferencesInput) Validate() error {
+	if (i.QuietHoursStart == nil) != (i.QuietHoursEnd == nil) {
+		return ErrIncompleteQuietHours
+	}
+	if i.QuietHoursStart == nil {
+		return nil
+	}
+	if (*i.QuietHoursStart == "") != (*i.QuietHoursEnd == "") {
+		return ErrIncompleteQuietHours
+	}
+	if *i.QuietHoursStart == "" {
+		return nil
+	}
+	if !i.QuietHoursStart.IsValid() {
+		return ErrInvalidQuietHoursStart
+	}
+	if !i.QuietHoursEnd.IsValid() {
+		return ErrInvalidQuietHoursEnd
+	}
+	return nil
 }
 
 // NotificationPreferencesResponse represents the API response for notification preferences
 type NotificationPreferencesResponse struct {
-	ID               uuid.UUID `json:"id"`
-	UserID           uuid.UUID `json:"user_id"`
-	SMSEnabled       bool      `json:"sms_enabled"`
-	EmailEnabled     bool      `json:"email_enabled"`
-	DashboardEnabled bool      `json:"dashboard_enabled"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	ID               uuid.UUID  `json:"id"`
+	UserID           uuid.UUID  `json:"user_id"`
+	SMSEnabled       bool       `json:"sms_enabled"`
+	EmailEnabled     bool       `json:"email_enabled"`
+	PushEnabled      bool       `json:"push_enabled"`
+	DashboardEnabled bool       `json:"dashboard_enabled"`
+	QuietHoursStart  *ShiftTime `json:"quiet_hours_start"`
+	QuietHoursEnd    *ShiftTime `json:"quiet_hours_end"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 // ToResponse converts UserNotificationPreferences to NotificationPreferencesResponse
@@ -50,7 +91,10 @@ func (p *UserNotificationPreferences) ToResponse() NotificationPreferencesRespon
 		UserID:           p.UserID,
 		SMSEnabled:       p.SMSEnabled,
 		EmailEnabled:     p.EmailEnabled,
+		PushEnabled:      p.PushEnabled,
 		DashboardEnabled: p.DashboardEnabled,
+		QuietHoursStart:  p.QuietHoursStart,
+		QuietHoursEnd:    p.QuietHoursEnd,
 		CreatedAt:        p.CreatedAt,
 		UpdatedAt:        p.UpdatedAt,
 	}
@@ -63,8 +107,56 @@ func DefaultPreferences(userID uuid.UUID, hasMobilePhone bool) *UserNotification
 		UserID:           userID,
 		SMSEnabled:       hasMobilePhone, // Default to true only if user has mobile phone
 		EmailEnabled:     true,           // Default to true
+		PushEnabled:      true,           // Default to true
 		DashboardEnabled: true,           // Always true, not editable
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
 	}
 }
+
+// ChannelEnabled returns true if the user accepts notifications on the given channel
+// The dashboard channel is always enabled
+func (p *UserNotificationPreferences) ChannelEnabled(channel NotificationChannel) bool {
+	switch channel {
+	case ChannelEmail:
+		return p.EmailEnabled
+	case ChannelSMS:
+		return p.SMSEnabled
+	case ChannelPush:
+		return p.PushEnabled
+	default:
+		return true
+	}
+}
+
+// InQuietHours returns true if t falls within the user's quiet hours
+// Quiet hours where the end is earlier than the start span midnight (e.g. 22:00-07:00)
+func (p *UserNotificationPreferences) InQuietHours(t time.Time) bool {
+	if p.QuietHoursStart == nil || p.QuietHoursEnd == nil || !p.QuietHoursStart.IsValid() || !p.QuietHoursEnd.IsValid() {
+		return false
+	}
+
+	start := p.QuietHoursStart.Hour()*60 + p.QuietHoursStart.Minute()
+	end := p.QuietHoursEnd.Hour()*60 + p.QuietHoursEnd.Minute()
+	current := t.Hour()*60 + t.Minute()
+
+	if start == end {
+		return false
+	}
+	if start < end {
+		return current >= start && current < end
+	}
+	return current >= start || current < end
+}
+
+// AllowsNotification returns true if a notification may be sent on the channel at time t
+// Critical notifications bypass quiet hours but still respect disabled channels
+func (p *UserNotificationPreferences) AllowsNotification(channel NotificationChannel, critical bool, t time.Time) bool {
+	if !p.ChannelEnabled(channel) {
+		return false
+	}
+	if channel == ChannelDashboard || critical {
+		return true
+	}
+	return !p.InQuietHours(t)
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -101,3 +102,86 @@ func TestUpdateNotificationPreferencesInput_NoDashboardEnabled(t *testing.T) {
 	// Note: DashboardEnabled is intentionally not in UpdateNotificationPreferencesInput
 	// This test documents that design decision
 }
+
+// Test 5: Test quiet hours, including a window spanning midnight
+func TestUserNotificationPreferences_InQuietHours(t *testing.T) {
+	start, end := ShiftTime("22:00"), ShiftTime("07:00")
+	prefs := &UserNotificationPreferences{QuietHoursStart: &start, QuietHoursEnd: &end}
+
+	tests := []struct {
+		hour, minute int
+		expected     bool
+	}{
+		{21, 59, false},
+		{22, 0, true},
+		{3, 30, true},
+		{6, 59, true},
+		{7, 0, false},
+		{12, 0, false},
+	}
+
+	for _, tc := range tests {
+		at := time.Date(2026, 10, 14, tc.hour, tc.minute, 0, 0, time.Local)
+		if got := prefs.InQuietHours(at); got != tc.expected {
+			t.Errorf("InQuietHours(%02d:%02d) = %v, expected %v", tc.hour, tc.minute, got, tc.expected)
+		}
+	}
+
+	if (&UserNotificationPreferences{}).InQuietHours(time.Date(2026, 10, 14, 23, 0, 0, 0, time.Local)) {
+		t.Error("Expected no quiet hours when none are configured")
+	}
+}
+
+// Test 6: Test critical notifications bypass quiet hours but not disabled channels
+func TestUserNotificationPreferences_AllowsNotification(t *testing.T) {
+	start, end := ShiftTime("22:00"), ShiftTime("07:00")
+	prefs := &UserNotificationPreferences{
+		EmailEnabled:     true,
+		SMSEnabled:       false,
+		PushEnabled:      true,
+		DashboardEnabled: true,
+		QuietHoursStart:  &start,
+		QuietHoursEnd:    &end,
+	}
+	night := time.Date(2026, 10, 14, 23, 0, 0, 0, time.Local)
+
+	if prefs.AllowsNotification(ChannelEmail, false, night) {
+		t.Error("Expected non-critical email to be suppressed during quiet hours")
+	}
+	if !prefs.AllowsNotification(ChannelEmail, true, night) {
+		t.Error("Expected critical email to bypass quiet hours")
+	}
+	if prefs.AllowsNotification(ChannelSMS, true, night) {
+		t.Error("Expected disabled SMS channel to block even critical notifications")
+	}
+	if !prefs.AllowsNotification(ChannelDashboard, false, night) {
+		t.Error("Expected dashboard notifications to ignore quiet hours")
+	}
+}
+
+// Test 7: Test quiet hours validation on update
+func TestUpdateNotificationPreferencesInput_Validate(t *testing.T) {
+	valid, invalid, empty := ShiftTime("22:00"), ShiftTime("25:00"), ShiftTime("")
+
+	tests := []struct {
+		name     string
+		input    UpdateNotificationPreferencesInput
+		expected error
+	}{
+		{"no quiet hours", UpdateNotificationPreferencesInput{}, nil},
+		{"valid quiet hours", UpdateNotificationPreferencesInput{QuietHoursStart: &valid, QuietHoursEnd: &valid}, nil},
+		{"clear quiet hours", UpdateNotificationPreferencesInput{QuietHoursStart: &empty, QuietHoursEnd: &empty}, nil},
+		{"only start", UpdateNotificationPreferencesInput{QuietHoursStart: &valid}, ErrIncompleteQuietHours},
+		{"start cleared only", UpdateNotificationPreferencesInput{QuietHoursStart: &empty, QuietHoursEnd: &valid}, ErrIncompleteQuietHours},
+		{"invalid start", UpdateNotificationPreferencesInput{QuietHoursStart: &invalid, QuietHoursEnd: &valid}, ErrInvalidQuietHoursStart},
+		{"invalid end", UpdateNotificationPreferencesInput{QuietHoursStart: &valid, QuietHoursEnd: &invalid}, ErrInvalidQuietHoursEnd},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.input.Validate(); err != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, err)
+			}
+		})
+	}
+}
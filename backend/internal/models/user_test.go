@@ -127,3 +127,22 @@ func TestUserInputStructsHaveMobilePhone(t *testing.T) {
 		t.Error("UpdateUserInput.MobilePhone not set correctly")
 	}
 }
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := map[string]string{
+		"pt-BR": LocalePtBR,
+		"pt_br": LocalePtBR,
+		"pt":    LocalePtBR,
+		"en":    LocaleEn,
+		"en-US": LocaleEn,
+		"EN":    LocaleEn,
+		"":      DefaultLocale,
+		"es":    DefaultLocale,
+	}
+
+	for locale, expected := range tests {
+		if got := NormalizeLocale(locale); got != expected {
+			t.Errorf("NormalizeLocale(%q) = %q, expected %q", locale, got, expected)
+		}
+	}
+}
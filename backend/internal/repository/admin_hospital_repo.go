@@ -11,12 +11,25 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sidot/backend/internal/models"
 )
 
 var (
 	// ErrAdminHospitalNotFound is returned when a hospital is not found
 	ErrAdminHospitalNotFound = errors.New("hospital not found")
+
+	// ErrAdminHospitalCodigoInUse is returned when restoring a hospital whose codigo is used by another hospital
+	ErrAdminHospitalCodigoInUse = errors.New("hospital codigo is already in use by another hospital")
+
+	// ErrAdminTargetTenantNotFound is returned when hospitals are reassigned to a tenant that does not exist
+	ErrAdminTargetTenantNotFound = errors.New("target tenant not found")
+
+	// ErrAdminReassignSameTenant is returned when the hospitals of a tenant are reassigned to the same tenant
+	ErrAdminReassignSameTenant = errors.New("source and target tenants must differ")
+
+	// ErrAdminReassignConflict is returned when a moved hospital or user conflicts with one of the target tenant
+	ErrAdminReassignConflict = errors.New("reassignment conflicts with data of the target tenant")
 )
 
 // AdminHospitalListParams contains parameters for listing hospitals in admin view
@@ -24,7 +37,7 @@ type AdminHospitalListParams struct {
 	Page     int        `form:"page"`
 	PerPage  int        `form:"per_page"`
 	Search   string     `form:"search"`
-	Status   string     `form:"status"` // "all", "active", "inactive"
+	Status   string     `form:"status"` // "all", "active", "inactive", "deleted"
 	TenantID *uuid.UUID `form:"tenant_id"`
 }
 
@@ -68,8 +81,12 @@ func (r *AdminHospitalRepository) ListAllHospitals(ctx context.Context, params *
 	var args []interface{}
 	argIndex := 1
 
-	// Always exclude soft-deleted hospitals
-	conditions = append(conditions, "h.deleted_at IS NULL")
+	// Soft-deleted hospitals are only listed when explicitly requested (to be restored)
+	if params.Status == "deleted" {
+		conditions = append(conditions, "h.deleted_at IS NOT NULL")
+	} else {
+		conditions = append(conditions, "h.deleted_at IS NULL")
+	}
 
 	// Optional tenant filter
 	if params.TenantID != nil {
@@ -116,8 +133,8 @@ func (r *AdminHospitalRepository) ListAllHospitals(ctx context.Context, params *
 	query := fmt.Sprintf(`
 		SELECT
 			h.id, h.tenant_id, h.nome, h.codigo, h.endereco, h.telefone,
-			h.latitude, h.longitude, h.config_conexao, h.ativo,
-			h.created_at, h.updated_at,
+			h.email, h.escalation_email, h.latitude, h.longitude, h.config_conexao, h.ativo,
+			h.created_at, h.updated_at, h.deleted_at,
 			t.name as tenant_name, t.slug as tenant_slug
 		FROM hospitals h
 		LEFT JOIN tenants t ON h.tenant_id = t.id
@@ -137,8 +154,9 @@ func (r *AdminHospitalRepository) ListAllHospitals(ctx context.Context, params *
 	var hospitals []models.HospitalWithTenant
 	for rows.Next() {
 		var h models.HospitalWithTenant
-		var endereco, telefone, configConexao, tenantName, tenantSlug sql.NullString
+		var endereco, telefone, email, escalationEmail, configConexao, tenantName, tenantSlug sql.NullString
 		var latitude, longitude sql.NullFloat64
+		var deletedAt sql.NullTime
 
 		err := rows.Scan(
 			&h.ID,
@@ -147,12 +165,15 @@ func (r *AdminHospitalRepository) ListAllHospitals(ctx context.Context, params *
 			&h.Codigo,
 			&endereco,
 			&telefone,
+			&email,
+			&escalationEmail,
 			&latitude,
 			&longitude,
 			&configConexao,
 			&h.Ativo,
 			&h.CreatedAt,
 			&h.UpdatedAt,
+			&deletedAt,
 			&tenantName,
 			&tenantSlug,
 		)
@@ -166,6 +187,12 @@ func (r *AdminHospitalRepository) ListAllHospitals(ctx context.Context, params *
 		if telefone.Valid {
 			h.Telefone = &telefone.String
 		}
+		if email.Valid {
+			h.Email = &email.String
+		}
+		if escalationEmail.Valid {
+			h.EscalationEmail = &escalationEmail.String
+		}
 		if latitude.Valid {
 			h.Latitude = &latitude.Float64
 		}
@@ -181,6 +208,9 @@ func (r *AdminHospitalRepository) ListAllHospitals(ctx context.Context, params *
 		if tenantSlug.Valid {
 			h.TenantSlug = &tenantSlug.String
 		}
+		if deletedAt.Valid {
+			h.DeletedAt = &deletedAt.Time
+		}
 
 		hospitals = append(hospitals, h)
 	}
@@ -203,7 +233,7 @@ func (r *AdminHospitalRepository) GetHospitalByID(ctx context.Context, id uuid.U
 	query := `
 		SELECT
 			h.id, h.tenant_id, h.nome, h.codigo, h.endereco, h.telefone,
-			h.latitude, h.longitude, h.config_conexao, h.ativo,
+			h.email, h.escalation_email, h.latitude, h.longitude, h.config_conexao, h.ativo,
 			h.created_at, h.updated_at,
 			t.name as tenant_name, t.slug as tenant_slug
 		FROM hospitals h
@@ -212,7 +242,7 @@ func (r *AdminHospitalRepository) GetHospitalByID(ctx context.Context, id uuid.U
 	`
 
 	var h models.HospitalWithTenant
-	var endereco, telefone, configConexao, tenantName, tenantSlug sql.NullString
+	var endereco, telefone, email, escalationEmail, configConexao, tenantName, tenantSlug sql.NullString
 	var latitude, longitude sql.NullFloat64
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
@@ -222,6 +252,8 @@ func (r *AdminHospitalRepository) GetHospitalByID(ctx context.Context, id uuid.U
 		&h.Codigo,
 		&endereco,
 		&telefone,
+		&email,
+		&escalationEmail,
 		&latitude,
 		&longitude,
 		&configConexao,
@@ -245,6 +277,12 @@ func (r *AdminHospitalRepository) GetHospitalByID(ctx context.Context, id uuid.U
 	if telefone.Valid {
 		h.Telefone = &telefone.String
 	}
+	if email.Valid {
+		h.Email = &email.String
+	}
+	if escalationEmail.Valid {
+		h.EscalationEmail = &escalationEmail.String
+	}
 	if latitude.Valid {
 		h.Latitude = &latitude.Float64
 	}
@@ -285,6 +323,19 @@ func (r *AdminHospitalRepository) UpdateHospital(ctx context.Context, id uuid.UU
 	if input.Telefone != nil {
 		hospital.Telefone = input.Telefone
 	}
+	if input.Email != nil {
+		// An empty email removes the contact
+		hospital.Email = input.Email
+		if *input.Email == "" {
+			hospital.Email = nil
+		}
+	}
+	if input.EscalationEmail != nil {
+		hospital.EscalationEmail = input.EscalationEmail
+		if *input.EscalationEmail == "" {
+			hospital.EscalationEmail = nil
+		}
+	}
 	if input.Latitude != nil {
 		hospital.Latitude = input.Latitude
 	}
@@ -300,10 +351,13 @@ func (r *AdminHospitalRepository) UpdateHospital(ctx context.Context, id uuid.UU
 
 	hospital.UpdatedAt = time.Now()
 
+	// The timezone is only changed when sent; an empty one resets it to the system timezone
 	query := `
 		UPDATE hospitals
 		SET nome = $1, codigo = $2, endereco = $3, telefone = $4,
-		    latitude = $5, longitude = $6, config_conexao = $7, ativo = $8, updated_at = $9
+		    latitude = $5, longitude = $6, config_conexao = $7, ativo = $8, updated_at = $9,
+		    timezone = CASE WHEN $11::text IS NULL THEN timezone ELSE NULLIF($11, '') END,
+		    email = $12, escalation_email = $13
 		WHERE id = $10 AND deleted_at IS NULL
 	`
 
@@ -323,6 +377,9 @@ func (r *AdminHospitalRepository) UpdateHospital(ctx context.Context, id uuid.UU
 		hospital.Ativo,
 		hospital.UpdatedAt,
 		id,
+		input.Timezone,
+		hospital.Email,
+		hospital.EscalationEmail,
 	)
 	if err != nil {
 		return nil, err
@@ -354,7 +411,7 @@ func (r *AdminHospitalRepository) ReassignHospitalTenant(ctx context.Context, ho
 		return nil, err
 	}
 	if !tenantExists {
-		return nil, errors.New("target tenant not found")
+		return nil, ErrAdminTargetTenantNotFound
 	}
 
 	// Begin transaction
@@ -400,3 +457,180 @@ func (r *AdminHospitalRepository) ReassignHospitalTenant(ctx context.Context, ho
 
 	return r.GetHospitalByID(ctx, hospitalID)
 }
+
+// ReassignedHospital identifies a hospital moved by ReassignTenantHospitals
+type ReassignedHospital struct {
+	ID   uuid.UUID `json:"id"`
+	Nome string    `json:"nome"`
+}
+
+// TenantHospitalsReassignment is the result of moving the hospitals of a tenant
+type TenantHospitalsReassignment struct {
+	Hospitals  []ReassignedHospital `json:"hospitals"`
+	MovedUsers int64                `json:"moved_users"`
+}
+
+// hospitalDataReassignStatements move the records of the moved hospitals ($2) to the target tenant ($1),
+// so a later purge of the source tenant does not delete them
+var hospitalDataReassignStatements = []string{
+	`UPDATE occurrences SET tenant_id = $1 WHERE hospital_id = ANY($2)`,
+	`UPDATE obitos_simulados SET tenant_id = $1 WHERE hospital_id = ANY($2)`,
+	`UPDATE obito_ineligibilities SET tenant_id = $1 WHERE hospital_id = ANY($2)`,
+	`UPDATE shifts SET tenant_id = $1 WHERE hospital_id = ANY($2)`,
+	`UPDATE notifications SET tenant_id = $1 WHERE occurrence_id IN (SELECT id FROM occurrences WHERE hospital_id = ANY($2))`,
+}
+
+// ReassignTenantHospitals moves every hospital of a tenant, including soft-deleted ones, to another tenant
+// in one transaction, with their occurrences, obitos, ineligibilities, shifts and notifications.
+// With moveUsers the users of the source tenant (except super admins) move as well.
+// Returns ErrAdminReassignConflict when a moved row violates a unique constraint of the target tenant.
+func (r *AdminHospitalRepository) ReassignTenantHospitals(ctx context.Context, sourceTenantID, targetTenantID uuid.UUID, moveUsers bool) (*TenantHospitalsReassignment, error) {
+	if sourceTenantID == targetTenantID {
+		return nil, ErrAdminReassignSameTenant
+	}
+
+	var sourceExists, targetExists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			EXISTS(SELECT 1 FROM tenants WHERE id = $1),
+			EXISTS(SELECT 1 FROM tenants WHERE id = $2)
+	`, sourceTenantID, targetTenantID).Scan(&sourceExists, &targetExists)
+	if err != nil {
+		return nil, err
+	}
+	if !sourceExists {
+		return nil, ErrAdminTenantNotFound
+	}
+	if !targetExists {
+		return nil, ErrAdminTargetTenantNotFound
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE hospitals
+		SET tenant_id = $1, updated_at = $2
+		WHERE tenant_id = $3
+		RETURNING id, nome
+	`, targetTenantID, time.Now(), sourceTenantID)
+	if err != nil {
+		return nil, reassignError(err)
+	}
+
+	reassignment := &TenantHospitalsReassignment{Hospitals: []ReassignedHospital{}}
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var h ReassignedHospital
+		if err := rows.Scan(&h.ID, &h.Nome); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		reassignment.Hospitals = append(reassignment.Hospitals, h)
+		ids = append(ids, h.ID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, reassignError(err)
+	}
+
+	// Update user_hospitals associations to new tenant
+	_, err = tx.ExecContext(ctx, `
+		UPDATE user_hospitals
+		SET tenant_id = $1
+		WHERE hospital_id = ANY($2)
+	`, targetTenantID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, statement := range hospitalDataReassignStatements {
+		if _, err := tx.ExecContext(ctx, statement, targetTenantID, pq.Array(ids)); err != nil {
+			return nil, reassignError(err)
+		}
+	}
+
+	if moveUsers {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE users
+			SET tenant_id = $1, updated_at = $2
+			WHERE tenant_id = $3 AND is_super_admin = false
+		`, targetTenantID, time.Now(), sourceTenantID)
+		if err != nil {
+			return nil, reassignError(err)
+		}
+		if reassignment.MovedUsers, err = result.RowsAffected(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return reassignment, nil
+}
+
+// reassignError maps a unique violation of a reassignment to ErrAdminReassignConflict
+func reassignError(err error) error {
+	if isUniqueViolation(err) {
+		return ErrAdminReassignConflict
+	}
+	return err
+}
+
+// SoftDeleteHospital marks a hospital as deleted (cross-tenant)
+// The hospital is deactivated and hidden from every listing until restored; its previous
+// ativo flag is kept in ativo_before_delete for RestoreHospital
+func (r *AdminHospitalRepository) SoftDeleteHospital(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE hospitals
+		SET deleted_at = $1, ativo_before_delete = ativo, ativo = false, updated_at = $1
+		WHERE id = $2 AND deleted_at IS NULL
+	`, now, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrAdminHospitalNotFound
+	}
+
+	return nil
+}
+
+// RestoreHospital clears the deletion of a soft-deleted hospital and brings back the ativo flag it
+// had before the deletion (cross-tenant). Hospitals deleted before that flag was recorded are reactivated.
+// Returns ErrAdminHospitalNotFound when no deleted hospital has this ID and ErrAdminHospitalCodigoInUse
+// when another hospital took its codigo in the meantime.
+func (r *AdminHospitalRepository) RestoreHospital(ctx context.Context, id uuid.UUID) (*models.HospitalWithTenant, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE hospitals
+		SET deleted_at = NULL, ativo = COALESCE(ativo_before_delete, true), ativo_before_delete = NULL, updated_at = $1
+		WHERE id = $2 AND deleted_at IS NOT NULL
+	`, time.Now(), id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAdminHospitalCodigoInUse
+		}
+		return nil, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, ErrAdminHospitalNotFound
+	}
+
+	return r.GetHospitalByID(ctx, id)
+}
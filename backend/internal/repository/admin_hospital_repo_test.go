@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+)
+
+// openTestDB connects to TEST_DATABASE_URL (a migrated database), skipping the test when it is not set
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping database test")
+	}
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		t.Fatalf("Failed to ping database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// insertTestHospital creates a hospital with coordinates for tenantID and removes it after the test
+func insertTestHospital(t *testing.T, db *sql.DB, tenantID uuid.UUID, codigo string) uuid.UUID {
+	t.Helper()
+
+	id := uuid.New()
+	_, err := db.Exec(`
+		INSERT INTO hospitals (id, tenant_id, nome, codigo, latitude, longitude, ativo)
+		VALUES ($1, $2, $3, $4, -16.68, -49.25, true)
+	`, id, tenantID, "Hospital "+codigo, codigo)
+	if err != nil {
+		t.Fatalf("Failed to insert hospital: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM hospitals WHERE id = $1`, id) })
+	return id
+}
+
+// containsHospital reports whether a hospital listing function returns id
+func containsHospital(t *testing.T, id uuid.UUID, list func() ([]uuid.UUID, error)) bool {
+	t.Helper()
+
+	ids, err := list()
+	if err != nil {
+		t.Fatalf("Failed to list hospitals: %v", err)
+	}
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// TestAdminSoftDeleteAndRestoreHospital tests that soft-deleted hospitals are hidden from listings until restored
+func TestAdminSoftDeleteAndRestoreHospital(t *testing.T) {
+	db := openTestDB(t)
+
+	tenantID := uuid.New()
+	if _, err := db.Exec(`INSERT INTO tenants (id, name, slug) VALUES ($1, $2, $3)`, tenantID, "Tenant Teste", "tenant-"+tenantID.String()[:8]); err != nil {
+		t.Fatalf("Failed to insert tenant: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM tenants WHERE id = $1`, tenantID) })
+
+	codigo := "SOFT-" + uuid.New().String()[:8]
+	hospitalID := insertTestHospital(t, db, tenantID, codigo)
+
+	adminRepo := NewAdminHospitalRepository(db)
+	hospitalRepo := NewHospitalRepository(db)
+	ctx := context.Background()
+	tenantCtx := middleware.WithTenantContext(ctx, tenantID.String(), false)
+
+	listings := map[string]func() ([]uuid.UUID, error){
+		"tenant list": func() ([]uuid.UUID, error) {
+			hospitals, err := hospitalRepo.List(tenantCtx)
+			ids := make([]uuid.UUID, 0, len(hospitals))
+			for _, h := range hospitals {
+				ids = append(ids, h.ID)
+			}
+			return ids, err
+		},
+		"map hospitals": func() ([]uuid.UUID, error) {
+			hospitals, err := hospitalRepo.GetActiveHospitalsWithCoordinates(tenantCtx)
+			ids := make([]uuid.UUID, 0, len(hospitals))
+			for _, h := range hospitals {
+				ids = append(ids, h.ID)
+			}
+			return ids, err
+		},
+		"admin list": func() ([]uuid.UUID, error) {
+			result, err := adminRepo.ListAllHospitals(ctx, &AdminHospitalListParams{TenantID: &tenantID})
+			if err != nil {
+				return nil, err
+			}
+			ids := make([]uuid.UUID, 0, len(result.Hospitals))
+			for _, h := range result.Hospitals {
+				ids = append(ids, h.ID)
+			}
+			return ids, nil
+		},
+	}
+
+	if err := adminRepo.SoftDeleteHospital(ctx, hospitalID); err != nil {
+		t.Fatalf("SoftDeleteHospital returned error: %v", err)
+	}
+	for name, list := range listings {
+		if containsHospital(t, hospitalID, list) {
+			t.Errorf("Expected soft-deleted hospital to be excluded from %s", name)
+		}
+	}
+	if _, err := adminRepo.GetHospitalByID(ctx, hospitalID); !errors.Is(err, ErrAdminHospitalNotFound) {
+		t.Errorf("Expected ErrAdminHospitalNotFound for a deleted hospital, got %v", err)
+	}
+	if err := adminRepo.SoftDeleteHospital(ctx, hospitalID); !errors.Is(err, ErrAdminHospitalNotFound) {
+		t.Errorf("Expected deleting twice to return ErrAdminHospitalNotFound, got %v", err)
+	}
+
+	deleted, err := adminRepo.ListAllHospitals(ctx, &AdminHospitalListParams{TenantID: &tenantID, Status: "deleted"})
+	if err != nil {
+		t.Fatalf("ListAllHospitals returned error: %v", err)
+	}
+	if len(deleted.Hospitals) != 1 || deleted.Hospitals[0].ID != hospitalID || deleted.Hospitals[0].DeletedAt == nil {
+		t.Errorf("Expected the deleted hospital in the deleted listing, got %+v", deleted.Hospitals)
+	}
+
+	restored, err := adminRepo.RestoreHospital(ctx, hospitalID)
+	if err != nil {
+		t.Fatalf("RestoreHospital returned error: %v", err)
+	}
+	if !restored.Ativo || restored.DeletedAt != nil {
+		t.Errorf("Expected restored hospital to be active and not deleted, got %+v", restored)
+	}
+	for name, list := range listings {
+		if !containsHospital(t, hospitalID, list) {
+			t.Errorf("Expected restored hospital to reappear in %s", name)
+		}
+	}
+	if _, err := adminRepo.RestoreHospital(ctx, hospitalID); !errors.Is(err, ErrAdminHospitalNotFound) {
+		t.Errorf("Expected restoring a hospital that is not deleted to return ErrAdminHospitalNotFound, got %v", err)
+	}
+
+	// An inactive hospital stays inactive after a delete and restore
+	if _, err := db.Exec(`UPDATE hospitals SET ativo = false WHERE id = $1`, hospitalID); err != nil {
+		t.Fatalf("Failed to deactivate hospital: %v", err)
+	}
+	if err := adminRepo.SoftDeleteHospital(ctx, hospitalID); err != nil {
+		t.Fatalf("SoftDeleteHospital returned error: %v", err)
+	}
+	restored, err = adminRepo.RestoreHospital(ctx, hospitalID)
+	if err != nil {
+		t.Fatalf("RestoreHospital returned error: %v", err)
+	}
+	if restored.Ativo || restored.DeletedAt != nil {
+		t.Errorf("Expected restored hospital to keep ativo = false, got %+v", restored)
+	}
+
+	// A codigo taken while the hospital was deleted blocks the restore
+	if err := adminRepo.SoftDeleteHospital(ctx, hospitalID); err != nil {
+		t.Fatalf("SoftDeleteHospital returned error: %v", err)
+	}
+	insertTestHospital(t, db, tenantID, codigo)
+	if _, err := adminRepo.RestoreHospital(ctx, hospitalID); !errors.Is(err, ErrAdminHospitalCodigoInUse) {
+		t.Errorf("Expected ErrAdminHospitalCodigoInUse, got %v", err)
+	}
+}
+
+// TestHospitalContactsPersist tests that the contact and escalation emails are stored, updated and cleared
+func TestHospitalContactsPersist(t *testing.T) {
+	db := openTestDB(t)
+	tenantID := insertTestTenant(t, db)
+
+	hospitalRepo := NewHospitalRepository(db)
+	adminRepo := NewAdminHospitalRepository(db)
+	ctx := middleware.WithTenantContext(context.Background(), tenantID.String(), false)
+
+	email := "contato@hospital.test"
+	escalationEmail := "plantao@hospital.test"
+	created, err := hospitalRepo.Create(ctx, &models.CreateHospitalInput{
+		Nome:            "Hospital Contatos",
+		Codigo:          "CONT" + uuid.New().String()[:8],
+		Endereco:        "Rua Teste, 1",
+		Latitude:        -16.68,
+		Longitude:       -49.25,
+		Email:           &email,
+		EscalationEmail: &escalationEmail,
+	})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM hospitals WHERE id = $1`, created.ID) })
+
+	hospital, err := hospitalRepo.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if hospital.Email == nil || *hospital.Email != email || hospital.EscalationEmail == nil || *hospital.EscalationEmail != escalationEmail {
+		t.Errorf("Expected the contacts to persist, got %v %v", hospital.Email, hospital.EscalationEmail)
+	}
+
+	// An empty escalation email removes it and keeps the contact email
+	empty := ""
+	if _, err := hospitalRepo.Update(ctx, created.ID, &models.UpdateHospitalInput{EscalationEmail: &empty}); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	admin, err := adminRepo.GetHospitalByID(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("GetHospitalByID returned error: %v", err)
+	}
+	if admin.EscalationEmail != nil || admin.Email == nil || *admin.Email != email {
+		t.Errorf("Expected only the escalation email to be cleared, got %v %v", admin.Email, admin.EscalationEmail)
+	}
+
+	updated := "escalacao@hospital.test"
+	if _, err := adminRepo.UpdateHospital(context.Background(), created.ID, &models.UpdateHospitalInput{EscalationEmail: &updated}); err != nil {
+		t.Fatalf("UpdateHospital returned error: %v", err)
+	}
+	hospital, err = hospitalRepo.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if hospital.EscalationEmail == nil || *hospital.EscalationEmail != updated {
+		t.Errorf("Expected the escalation email updated by the admin, got %v", hospital.EscalationEmail)
+	}
+}
+
+// TestAdminReassignTenantHospitals tests that every hospital of a tenant, and optionally its users, moves to the target tenant
+func TestAdminReassignTenantHospitals(t *testing.T) {
+	db := openTestDB(t)
+	sourceTenantID := insertTestTenant(t, db)
+	targetTenantID := insertTestTenant(t, db)
+
+	first := insertTestHospital(t, db, sourceTenantID, "MOVE"+uuid.New().String()[:8])
+	second := insertTestHospital(t, db, sourceTenantID, "MOVE"+uuid.New().String()[:8])
+	kept := insertTestHospital(t, db, targetTenantID, "KEEP"+uuid.New().String()[:8])
+
+	userID := uuid.New()
+	_, err := db.Exec(`
+		INSERT INTO users (id, email, password_hash, nome, role, tenant_id, ativo)
+		VALUES ($1, $2, 'hash', 'Operador Fusao', 'operador', $3, true)
+	`, userID, "fusao-"+userID.String()[:8]+"@sidot.test", sourceTenantID)
+	if err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM users WHERE id = $1`, userID) })
+
+	repo := NewAdminHospitalRepository(db)
+	ctx := context.Background()
+
+	if _, err := repo.ReassignTenantHospitals(ctx, sourceTenantID, sourceTenantID, true); !errors.Is(err, ErrAdminReassignSameTenant) {
+		t.Errorf("Expected ErrAdminReassignSameTenant, got %v", err)
+	}
+	if _, err := repo.ReassignTenantHospitals(ctx, sourceTenantID, uuid.New(), true); !errors.Is(err, ErrAdminTargetTenantNotFound) {
+		t.Errorf("Expected ErrAdminTargetTenantNotFound, got %v", err)
+	}
+
+	reassignment, err := repo.ReassignTenantHospitals(ctx, sourceTenantID, targetTenantID, true)
+	if err != nil {
+		t.Fatalf("ReassignTenantHospitals returned error: %v", err)
+	}
+	if len(reassignment.Hospitals) != 2 || reassignment.MovedUsers != 1 {
+		t.Errorf("Expected 2 hospitals and 1 user moved, got %+v", reassignment)
+	}
+
+	result, err := repo.ListAllHospitals(ctx, &AdminHospitalListParams{TenantID: &targetTenantID})
+	if err != nil {
+		t.Fatalf("ListAllHospitals returned error: %v", err)
+	}
+	moved := map[uuid.UUID]bool{}
+	for _, h := range result.Hospitals {
+		moved[h.ID] = true
+	}
+	if !moved[first] || !moved[second] || !moved[kept] {
+		t.Errorf("Expected every hospital in the target tenant, got %v", moved)
+	}
+
+	var userTenantID uuid.UUID
+	if err := db.QueryRow(`SELECT tenant_id FROM users WHERE id = $1`, userID).Scan(&userTenantID); err != nil {
+		t.Fatalf("Failed to read user tenant: %v", err)
+	}
+	if userTenantID != targetTenantID {
+		t.Errorf("Expected the user to move to the target tenant, got %s", userTenantID)
+	}
+
+	// The source tenant has nothing left to move
+	reassignment, err = repo.ReassignTenantHospitals(ctx, sourceTenantID, targetTenantID, false)
+	if err != nil {
+		t.Fatalf("ReassignTenantHospitals returned error: %v", err)
+	}
+	if len(reassignment.Hospitals) != 0 || reassignment.MovedUsers != 0 {
+		t.Errorf("Expected nothing moved on the second call, got %+v", reassignment)
+	}
+}
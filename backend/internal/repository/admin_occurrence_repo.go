@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sidot/backend/internal/models"
+)
+
+// AdminOccurrenceRepository handles admin-level occurrence data access (cross-tenant)
+type AdminOccurrenceRepository struct {
+	db *sql.DB
+}
+
+// NewAdminOccurrenceRepository creates a new admin occurrence repository
+func NewAdminOccurrenceRepository(db *sql.DB) *AdminOccurrenceRepository {
+	return &AdminOccurrenceRepository{db: db}
+}
+
+// Search returns occurrences across all tenants with pagination and filters
+// Tenant filtering is not applied; filters.TenantID narrows the search to one tenant.
+func (r *AdminOccurrenceRepository) Search(ctx context.Context, filters models.AdminOccurrenceFilters) ([]models.OccurrenceWithTenant, int, error) {
+	where := "WHERE 1=1"
+	args := []interface{}{}
+	argIndex := 1
+
+	if filters.TenantID != nil {
+		where += fmt.Sprintf(" AND o.tenant_id = $%d", argIndex)
+		args = append(args, *filters.TenantID)
+		argIndex++
+	}
+
+	if filters.Status != nil && *filters.Status != "" {
+		where += fmt.Sprintf(" AND o.status = $%d", argIndex)
+		args = append(args, *filters.Status)
+		argIndex++
+	}
+
+	if filters.HospitalID != nil && *filters.HospitalID != "" {
+		where += fmt.Sprintf(" AND o.hospital_id = $%d", argIndex)
+		args = append(args, *filters.HospitalID)
+		argIndex++
+	}
+
+	if filters.DateFrom != nil {
+		where += fmt.Sprintf(" AND o.created_at >= $%d", argIndex)
+		args = append(args, *filters.DateFrom)
+		argIndex++
+	}
+
+	if filters.DateTo != nil {
+		where += fmt.Sprintf(" AND o.created_at <= $%d", argIndex)
+		args = append(args, *filters.DateTo)
+		argIndex++
+	}
+
+	if filters.ScoreMin != nil {
+		where += fmt.Sprintf(" AND o.score_priorizacao >= $%d", argIndex)
+		args = append(args, *filters.ScoreMin)
+		argIndex++
+	}
+
+	if filters.ScoreMax != nil {
+		where += fmt.Sprintf(" AND o.score_priorizacao <= $%d", argIndex)
+		args = append(args, *filters.ScoreMax)
+		argIndex++
+	}
+
+	// Count total items
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM occurrences o %s", where)
+	var totalItems int
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	orderBy := "o.created_at DESC"
+	if occurrenceSortColumns[filters.SortBy] {
+		order := "DESC"
+		if filters.SortOrder == "asc" {
+			order = "ASC"
+		}
+		orderBy = fmt.Sprintf("o.%s %s", filters.SortBy, order)
+	}
+
+	offset := (filters.Page - 1) * filters.PageSize
+
+	query := fmt.Sprintf(`
+		SELECT
+			o.id, o.tenant_id, o.obito_id, o.hospital_id, o.status, o.score_priorizacao,
+			o.nome_paciente_mascarado, o.dados_completos, o.created_at, o.updated_at,
+			o.notificado_em, o.data_obito, o.janela_expira_em,
+			h.id, h.nome, h.codigo, h.endereco, h.ativo,
+			t.name, o.flags
+		FROM occurrences o
+		LEFT JOIN hospitals h ON o.hospital_id = h.id
+		LEFT JOIN tenants t ON o.tenant_id = t.id
+		%s
+		ORDER BY %s, o.id
+		LIMIT %d OFFSET %d
+	`, where, orderBy, filters.PageSize, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var occurrences []models.OccurrenceWithTenant
+	for rows.Next() {
+		var o models.OccurrenceWithTenant
+		var h models.Hospital
+		var notificadoEm sql.NullTime
+		var dadosCompletos string
+		var hEndereco, tenantName sql.NullString
+
+		err := rows.Scan(
+			&o.ID, &o.TenantID, &o.ObitoID, &o.HospitalID, &o.Status, &o.ScorePriorizacao,
+			&o.NomePacienteMascarado, &dadosCompletos, &o.CreatedAt, &o.UpdatedAt,
+			&notificadoEm, &o.DataObito, &o.JanelaExpiraEm,
+			&h.ID, &h.Nome, &h.Codigo, &hEndereco, &h.Ativo,
+			&tenantName, pq.Array(&o.Flags),
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		o.DadosCompletos, err = OpenOccurrenceData(json.RawMessage(dadosCompletos))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read dados_completos of occurrence %s: %w", o.ID, err)
+		}
+
+		if notificadoEm.Valid {
+			o.NotificadoEm = &notificadoEm.Time
+		}
+		if hEndereco.Valid {
+			h.Endereco = &hEndereco.String
+		}
+		o.Hospital = &h
+		if tenantName.Valid {
+			o.TenantName = &tenantName.String
+		}
+
+		occurrences = append(occurrences, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return occurrences, totalItems, nil
+}
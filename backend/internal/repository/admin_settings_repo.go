@@ -121,6 +121,23 @@ func (r *AdminSettingsRepository) GetSettingByKey(ctx context.Context, key strin
 	return &s, nil
 }
 
+// GetUrgencyThresholdsConfig returns the urgency_thresholds setting, or an empty config
+// (built-in bands for every tenant) when it is not set
+func (r *AdminSettingsRepository) GetUrgencyThresholdsConfig(ctx context.Context) (*models.UrgencyThresholdsConfig, error) {
+	setting, err := r.GetSettingByKey(ctx, models.SettingKeyUrgencyThresholds)
+	if err != nil {
+		if errors.Is(err, ErrAdminSettingNotFound) {
+			return &models.UrgencyThresholdsConfig{}, nil
+		}
+		return nil, err
+	}
+	config, err := setting.GetUrgencyThresholdsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid urgency thresholds: %w", err)
+	}
+	return config, nil
+}
+
 // UpsertSetting creates or updates a system setting
 // If is_encrypted is true and encryption service is available, the value is encrypted before storage
 func (r *AdminSettingsRepository) UpsertSetting(ctx context.Context, input *models.CreateSystemSettingInput) (*models.SystemSetting, error) {
@@ -359,6 +376,243 @@ func (r *AdminSettingsRepository) GetDecryptedSetting(ctx context.Context, key s
 	return setting, nil
 }
 
+// KeyRotationResult summarizes a key rotation of encrypted system settings and data columns
+type KeyRotationResult struct {
+	KeyVersion int      `json:"key_version"`
+	Rotated    []string `json:"rotated"`
+	Skipped    []string `json:"skipped"`
+
+	// RotatedRows counts the re-encrypted rows of each encrypted data column (table.column)
+	RotatedRows map[string]int `json:"rotated_rows"`
+}
+
+// encryptedDataColumn is a data column encrypted with the settings key, rotated with the settings
+type encryptedDataColumn struct {
+	name   string
+	query  string
+	update string
+	rotate func(value string, svc *services.EncryptionService) (string, bool, error)
+}
+
+// encryptedDataColumns lists the columns whose values come from EncryptionService.EncryptValue
+var encryptedDataColumns = []encryptedDataColumn{
+	{
+		name:   "occurrences.dados_completos",
+		query:  `SELECT id, dados_completos::text FROM occurrences WHERE dados_completos->>'_enc' IS NOT NULL FOR UPDATE`,
+		update: `UPDATE occurrences SET dados_completos = $1::jsonb WHERE id = $2`,
+		rotate: RotateOccurrenceDataValue,
+	},
+	{
+		name:   "users.mfa_secret",
+		query:  `SELECT id, mfa_secret FROM users WHERE mfa_secret IS NOT NULL FOR UPDATE`,
+		update: `UPDATE users SET mfa_secret = $1 WHERE id = $2`,
+		rotate: RotateCiphertext,
+	},
+}
+
+// RotateEncryptionKey re-encrypts every encrypted system setting, occurrence dados_completos and
+// MFA secret with the current key of svc
+// svc must also hold the previous keys of the stored values (see EncryptionService.AddPreviousKey).
+// Everything is rotated in a single transaction together with the encryption_key_version tag,
+// so a failure on any value leaves every value untouched, and once it commits no value depends
+// on a previous key.
+func (r *AdminSettingsRepository) RotateEncryptionKey(ctx context.Context, svc *services.EncryptionService, dryRun bool) (*KeyRotationResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT key, value
+		FROM system_settings
+		WHERE is_encrypted = true AND key <> $1
+		ORDER BY key ASC
+		FOR UPDATE
+	`, models.SettingKeyEncryptionKeyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query encrypted settings: %w", err)
+	}
+
+	var settings []models.SystemSetting
+	for rows.Next() {
+		var s models.SystemSetting
+		var value string
+		if err := rows.Scan(&s.Key, &value); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan setting: %w", err)
+		}
+		s.Value = json.RawMessage(value)
+		s.IsEncrypted = true
+		settings = append(settings, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating settings: %w", err)
+	}
+
+	rotated, result, err := RotateSettingValues(settings, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	result.RotatedRows = make(map[string]int, len(encryptedDataColumns))
+	for _, column := range encryptedDataColumns {
+		if result.RotatedRows[column.name], err = rotateDataColumn(ctx, tx, column, svc, dryRun); err != nil {
+			return nil, err
+		}
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	now := time.Now()
+	for _, setting := range rotated {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE system_settings SET value = $1, updated_at = $2 WHERE key = $3
+		`, string(setting.Value), now, setting.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update setting %s: %w", setting.Key, err)
+		}
+	}
+
+	tag, _ := json.Marshal(models.EncryptionKeyVersionConfig{Version: svc.KeyVersion(), RotatedAt: now})
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO system_settings (id, key, value, description, is_encrypted, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, false, $5, $5)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at
+	`, uuid.New(), models.SettingKeyEncryptionKeyVersion, string(tag), "Versao da chave de criptografia das configuracoes", now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write key version tag: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// rotateDataColumn re-encrypts the values of an encrypted data column that are not at the current key version
+// and returns how many rows were (or, in a dry run, would be) updated
+func rotateDataColumn(ctx context.Context, tx *sql.Tx, column encryptedDataColumn, svc *services.EncryptionService, dryRun bool) (int, error) {
+	rows, err := tx.QueryContext(ctx, column.query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", column.name, err)
+	}
+
+	type rotatedValue struct {
+		id    uuid.UUID
+		value string
+	}
+	var rotated []rotatedValue
+	for rows.Next() {
+		var id uuid.UUID
+		var value string
+		if err := rows.Scan(&id, &value); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan %s: %w", column.name, err)
+		}
+		reencrypted, changed, err := column.rotate(value, svc)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("%s of %s: %w", column.name, id, err)
+		}
+		if changed {
+			rotated = append(rotated, rotatedValue{id: id, value: reencrypted})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating %s: %w", column.name, err)
+	}
+
+	if !dryRun {
+		for _, r := range rotated {
+			if _, err := tx.ExecContext(ctx, column.update, r.value, r.id); err != nil {
+				return 0, fmt.Errorf("failed to update %s of %s: %w", column.name, r.id, err)
+			}
+		}
+	}
+	return len(rotated), nil
+}
+
+// RotateCiphertext re-encrypts a value from EncryptValue with the current key of svc
+// Reports false, with the value unchanged, when it is already at the current key version
+func RotateCiphertext(ciphertext string, svc *services.EncryptionService) (string, bool, error) {
+	version, err := services.CiphertextKeyVersion(ciphertext)
+	if err != nil {
+		return "", false, err
+	}
+	if version == svc.KeyVersion() {
+		return ciphertext, false, nil
+	}
+
+	plaintext, err := svc.DecryptValue(ciphertext)
+	if err != nil {
+		return "", false, err
+	}
+	reencrypted, err := svc.EncryptValue(plaintext)
+	if err != nil {
+		return "", false, err
+	}
+	return reencrypted, true, nil
+}
+
+// RotateOccurrenceDataValue re-encrypts a stored dados_completos envelope with the current key of svc
+// Plaintext values and envelopes already at the current key version are reported unchanged
+func RotateOccurrenceDataValue(stored string, svc *services.EncryptionService) (string, bool, error) {
+	var envelope encryptedOccurrenceData
+	if err := json.Unmarshal([]byte(stored), &envelope); err != nil || envelope.Algorithm != OccurrenceDataEncryptionAlgorithm {
+		return stored, false, nil
+	}
+
+	ciphertext, changed, err := RotateCiphertext(envelope.Ciphertext, svc)
+	if err != nil || !changed {
+		return stored, false, err
+	}
+
+	envelope.Ciphertext = ciphertext
+	value, err := json.Marshal(envelope)
+	if err != nil {
+		return "", false, err
+	}
+	return string(value), true, nil
+}
+
+// RotateSettingValues re-encrypts encrypted setting values with the current key of svc
+// Returns only the settings whose value changed. Values already at the current key version and
+// values that were never encrypted (not a JSON string) are skipped. Any value that cannot be
+// decrypted, including one with an unknown key version, aborts the whole batch.
+func RotateSettingValues(settings []models.SystemSetting, svc *services.EncryptionService) ([]models.SystemSetting, *KeyRotationResult, error) {
+	result := &KeyRotationResult{KeyVersion: svc.KeyVersion(), Rotated: []string{}, Skipped: []string{}}
+	var rotated []models.SystemSetting
+
+	for _, setting := range settings {
+		var ciphertext string
+		if err := json.Unmarshal(setting.Value, &ciphertext); err != nil {
+			result.Skipped = append(result.Skipped, setting.Key)
+			continue
+		}
+
+		reencrypted, changed, err := RotateCiphertext(ciphertext, svc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("setting %s: %w", setting.Key, err)
+		}
+		if !changed {
+			result.Skipped = append(result.Skipped, setting.Key)
+			continue
+		}
+
+		value, _ := json.Marshal(reencrypted)
+		setting.Value = value
+		rotated = append(rotated, setting)
+		result.Rotated = append(result.Rotated, setting.Key)
+	}
+
+	return rotated, result, nil
+}
+
 // implode joins strings with a separator
 func implode(arr []string, sep string) string {
 	result := ""
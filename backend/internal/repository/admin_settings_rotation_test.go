@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services"
+)
+
+const (
+	rotationTestOldKey = "0123456789abcdef0123456789abcdef"
+	rotationTestNewKey = "fedcba9876543210fedcba9876543210"
+)
+
+// rotationTestKeyring returns the v1 service and a v2 service that can still decrypt v1 values
+func rotationTestKeyring(t *testing.T) (*services.EncryptionService, *services.EncryptionService) {
+	t.Helper()
+
+	oldSvc, err := services.NewEncryptionServiceWithKey([]byte(rotationTestOldKey))
+	if err != nil {
+		t.Fatalf("Failed to create old encryption service: %v", err)
+	}
+	newSvc, err := services.NewEncryptionServiceWithVersion(2, []byte(rotationTestNewKey))
+	if err != nil {
+		t.Fatalf("Failed to create new encryption service: %v", err)
+	}
+	if err := newSvc.AddPreviousKey(services.LegacyKeyVersion, []byte(rotationTestOldKey)); err != nil {
+		t.Fatalf("Failed to add previous key: %v", err)
+	}
+	return oldSvc, newSvc
+}
+
+// encryptedSetting builds an encrypted setting as stored by the repository
+func encryptedSetting(t *testing.T, key, ciphertext string) models.SystemSetting {
+	t.Helper()
+	value, _ := json.Marshal(ciphertext)
+	return models.SystemSetting{Key: key, Value: value, IsEncrypted: true}
+}
+
+// TestRotateSettingValuesBatch tests rotating a batch of settings stored with mixed key versions
+func TestRotateSettingValuesBatch(t *testing.T) {
+	oldSvc, newSvc := rotationTestKeyring(t)
+
+	// Values written before key versions existed have no prefix
+	legacyRaw, err := oldSvc.EncryptBytes([]byte(`{"api_key":"legacy"}`))
+	if err != nil {
+		t.Fatalf("Failed to encrypt legacy value: %v", err)
+	}
+	v1, _ := oldSvc.EncryptValue(`{"api_key":"v1"}`)
+	v2, _ := newSvc.EncryptValue(`{"api_key":"v2"}`)
+
+	settings := []models.SystemSetting{
+		encryptedSetting(t, "legacy_setting", base64.StdEncoding.EncodeToString(legacyRaw)),
+		encryptedSetting(t, "v1_setting", v1),
+		encryptedSetting(t, "v2_setting", v2),
+		{Key: "placeholder_setting", Value: json.RawMessage(`{"server_key": ""}`), IsEncrypted: true},
+	}
+
+	rotated, result, err := RotateSettingValues(settings, newSvc)
+	if err != nil {
+		t.Fatalf("RotateSettingValues returned error: %v", err)
+	}
+
+	if result.KeyVersion != 2 {
+		t.Errorf("Expected key version 2, got %d", result.KeyVersion)
+	}
+	if len(result.Rotated) != 2 || result.Rotated[0] != "legacy_setting" || result.Rotated[1] != "v1_setting" {
+		t.Errorf("Expected legacy_setting and v1_setting to be rotated, got %v", result.Rotated)
+	}
+	if len(result.Skipped) != 2 || result.Skipped[0] != "v2_setting" || result.Skipped[1] != "placeholder_setting" {
+		t.Errorf("Expected v2_setting and placeholder_setting to be skipped, got %v", result.Skipped)
+	}
+
+	expected := map[string]string{
+		"legacy_setting": `{"api_key":"legacy"}`,
+		"v1_setting":     `{"api_key":"v1"}`,
+	}
+	for _, setting := range rotated {
+		var ciphertext string
+		if err := json.Unmarshal(setting.Value, &ciphertext); err != nil {
+			t.Fatalf("Rotated value of %s is not a JSON string: %v", setting.Key, err)
+		}
+
+		version, err := services.CiphertextKeyVersion(ciphertext)
+		if err != nil || version != 2 {
+			t.Errorf("Expected %s to be tagged with v2, got %d (%v)", setting.Key, version, err)
+		}
+		plaintext, err := newSvc.DecryptValue(ciphertext)
+		if err != nil {
+			t.Fatalf("Failed to decrypt rotated %s: %v", setting.Key, err)
+		}
+		if plaintext != expected[setting.Key] {
+			t.Errorf("Expected %s to decrypt to %s, got %s", setting.Key, expected[setting.Key], plaintext)
+		}
+
+		// The new key alone must be enough once rotation is complete
+		newOnly, _ := services.NewEncryptionServiceWithVersion(2, []byte(rotationTestNewKey))
+		if _, err := newOnly.DecryptValue(ciphertext); err != nil {
+			t.Errorf("Expected %s to decrypt without the old key: %v", setting.Key, err)
+		}
+	}
+}
+
+// TestRotateSettingValuesRejectsUnknownVersion tests that a value with an unknown key version aborts the batch
+func TestRotateSettingValuesRejectsUnknownVersion(t *testing.T) {
+	oldSvc, newSvc := rotationTestKeyring(t)
+
+	v1, _ := oldSvc.EncryptValue(`{"api_key":"v1"}`)
+	v9, _ := services.NewEncryptionServiceWithVersion(9, []byte(rotationTestOldKey))
+	unknown, _ := v9.EncryptValue(`{"api_key":"v9"}`)
+
+	tests := []struct {
+		name       string
+		ciphertext string
+	}{
+		{"version without key", unknown},
+		{"malformed version", "vx:" + v1[len("v1:"):]},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := []models.SystemSetting{
+				encryptedSetting(t, "v1_setting", v1),
+				encryptedSetting(t, "unknown_setting", tc.ciphertext),
+			}
+
+			rotated, result, err := RotateSettingValues(settings, newSvc)
+			if !errors.Is(err, services.ErrUnknownKeyVersion) {
+				t.Fatalf("Expected ErrUnknownKeyVersion, got %v", err)
+			}
+			if rotated != nil || result != nil {
+				t.Error("Expected no partial rotation when a value has an unknown version")
+			}
+		})
+	}
+
+	if _, err := newSvc.DecryptValue(unknown); !errors.Is(err, services.ErrUnknownKeyVersion) {
+		t.Errorf("Expected DecryptValue to reject v9, got %v", err)
+	}
+}
+
+// TestRotateOccurrenceDataValue tests rotating the encrypted dados_completos envelope of an occurrence
+func TestRotateOccurrenceDataValue(t *testing.T) {
+	oldSvc, newSvc := rotationTestKeyring(t)
+
+	v1, _ := oldSvc.EncryptValue(`{"nome_paciente":"Maria Souza"}`)
+	stored, _ := json.Marshal(encryptedOccurrenceData{Algorithm: OccurrenceDataEncryptionAlgorithm, Ciphertext: v1})
+
+	rotated, changed, err := RotateOccurrenceDataValue(string(stored), newSvc)
+	if err != nil || !changed {
+		t.Fatalf("Expected the v1 envelope to be rotated, got %v (%v)", changed, err)
+	}
+
+	var envelope encryptedOccurrenceData
+	if err := json.Unmarshal([]byte(rotated), &envelope); err != nil || envelope.Algorithm != OccurrenceDataEncryptionAlgorithm {
+		t.Fatalf("Expected a dados_completos envelope, got %s (%v)", rotated, err)
+	}
+	newOnly, _ := services.NewEncryptionServiceWithVersion(2, []byte(rotationTestNewKey))
+	plaintext, err := newOnly.DecryptValue(envelope.Ciphertext)
+	if err != nil || plaintext != `{"nome_paciente":"Maria Souza"}` {
+		t.Errorf("Expected the rotated envelope to decrypt with the new key only, got %s (%v)", plaintext, err)
+	}
+
+	// Rotated envelopes and plaintext values are left as they are
+	for _, value := range []string{rotated, `{"nome_paciente":"Joao Lima"}`} {
+		if unchanged, changed, err := RotateOccurrenceDataValue(value, newSvc); err != nil || changed || unchanged != value {
+			t.Errorf("Expected %s to be unchanged, got %s, %v (%v)", value, unchanged, changed, err)
+		}
+	}
+
+	// MFA secrets are stored as plain ciphertexts
+	secret, _ := oldSvc.EncryptValue("JBSWY3DPEHPK3PXP")
+	if rotatedSecret, changed, err := RotateCiphertext(secret, newSvc); err != nil || !changed {
+		t.Errorf("Expected the v1 secret to be rotated, got %v (%v)", changed, err)
+	} else if plaintext, err := newOnly.DecryptValue(rotatedSecret); err != nil || plaintext != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Expected the rotated secret to decrypt with the new key only, got %s (%v)", plaintext, err)
+	}
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// tenantExportEntry is one JSON file of a tenant export, read as one JSON object per row
+type tenantExportEntry struct {
+	name  string
+	query string
+	// transform rewrites each row before it is written, when set
+	transform func(row json.RawMessage) (json.RawMessage, error)
+}
+
+// tenantExportEntries lists the files of a tenant export; every query takes the tenant ID as $1
+// Password hashes and MFA secrets of the users are never exported
+var tenantExportEntries = []tenantExportEntry{
+	{
+		name:  "tenant.json",
+		query: `SELECT to_jsonb(t)::text FROM tenants t WHERE t.id = $1`,
+	},
+	{
+		name: "users.json",
+		query: `
+			SELECT (to_jsonb(u) - 'password_hash' - 'mfa_secret')::text
+			FROM users u
+			WHERE u.tenant_id = $1
+			ORDER BY u.created_at, u.id
+		`,
+	},
+	{
+		name: "user_hospitals.json",
+		query: `
+			SELECT to_jsonb(uh)::text
+			FROM user_hospitals uh
+			WHERE uh.tenant_id = $1
+			ORDER BY uh.user_id, uh.hospital_id
+		`,
+	},
+	{
+		name: "hospitals.json",
+		query: `
+			SELECT to_jsonb(h)::text
+			FROM hospitals h
+			WHERE h.tenant_id = $1
+			ORDER BY h.created_at, h.id
+		`,
+	},
+	{
+		name: "occurrences.json",
+		query: `
+			SELECT to_jsonb(o)::text
+			FROM occurrences o
+			WHERE o.tenant_id = $1
+			ORDER BY o.created_at, o.id
+		`,
+		transform: openExportedOccurrence,
+	},
+	{
+		name: "occurrence_history.json",
+		query: `
+			SELECT to_jsonb(oh)::text
+			FROM occurrence_history oh
+			JOIN occurrences o ON o.id = oh.occurrence_id
+			WHERE o.tenant_id = $1
+			ORDER BY oh.created_at, oh.id
+		`,
+	},
+	{
+		name: "obito_ineligibilities.json",
+		query: `
+			SELECT to_jsonb(oi)::text
+			FROM obito_ineligibilities oi
+			WHERE oi.tenant_id = $1
+			ORDER BY oi.created_at, oi.id
+		`,
+	},
+	{
+		name: "triagem_rules.json",
+		query: `
+			SELECT to_jsonb(tr)::text
+			FROM triagem_rules tr
+			WHERE tr.tenant_id = $1
+			ORDER BY tr.created_at, tr.id
+		`,
+	},
+	{
+		name: "audit_logs.json",
+		query: `
+			SELECT to_jsonb(al)::text
+			FROM audit_logs al
+			WHERE al.tenant_id = $1
+			ORDER BY al.timestamp, al.id
+		`,
+	},
+}
+
+// ExportTenantData writes every record of a tenant to w as a zip archive with one JSON array per table
+// Rows are streamed from the database into the archive, so the export is never held in memory
+func (r *AdminTenantRepository) ExportTenantData(ctx context.Context, tenantID uuid.UUID, w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	for _, entry := range tenantExportEntries {
+		file, err := archive.Create(entry.name)
+		if err != nil {
+			return err
+		}
+		if err := r.writeTenantExportEntry(ctx, tenantID, entry, file); err != nil {
+			return fmt.Errorf("failed to export %s: %w", entry.name, err)
+		}
+	}
+
+	return archive.Close()
+}
+
+// writeTenantExportEntry writes the rows of one export entry as a JSON array
+func (r *AdminTenantRepository) writeTenantExportEntry(ctx context.Context, tenantID uuid.UUID, entry tenantExportEntry, w io.Writer) error {
+	rows, err := r.db.QueryContext(ctx, entry.query, tenantID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	first := true
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return err
+		}
+
+		data := json.RawMessage(row)
+		if entry.transform != nil {
+			if data, err = entry.transform(data); err != nil {
+				return err
+			}
+		}
+
+		separator := ",\n"
+		if first {
+			separator = "\n"
+			first = false
+		}
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n]\n")
+	return err
+}
+
+// openExportedOccurrence decrypts the dados_completos of an exported occurrence
+func openExportedOccurrence(row json.RawMessage) (json.RawMessage, error) {
+	var occurrence map[string]json.RawMessage
+	if err := json.Unmarshal(row, &occurrence); err != nil {
+		return nil, err
+	}
+
+	dados, ok := occurrence["dados_completos"]
+	if !ok {
+		return row, nil
+	}
+	opened, err := OpenOccurrenceData(dados)
+	if err != nil {
+		return nil, err
+	}
+	occurrence["dados_completos"] = opened
+
+	return json.Marshal(occurrence)
+}
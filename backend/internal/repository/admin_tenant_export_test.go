@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// readTenantExport returns the rows of every JSON file of an export archive by file name
+func readTenantExport(t *testing.T, data []byte) map[string][]map[string]interface{} {
+	t.Helper()
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to open export archive: %v", err)
+	}
+
+	entries := make(map[string][]map[string]interface{})
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", file.Name, err)
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file.Name, err)
+		}
+
+		var rows []map[string]interface{}
+		if err := json.Unmarshal(content, &rows); err != nil {
+			t.Fatalf("Expected %s to be a JSON array: %v", file.Name, err)
+		}
+		entries[file.Name] = rows
+	}
+	return entries
+}
+
+// TestAdminExportTenantData tests that the export contains every table of the seeded tenant and nothing of others
+func TestAdminExportTenantData(t *testing.T) {
+	db := openTestDB(t)
+
+	tenantID := insertTestTenant(t, db)
+	otherTenantID := insertTestTenant(t, db)
+	suffix := uuid.New().String()[:8]
+	hospitalID := insertTestHospital(t, db, tenantID, "EXP"+suffix)
+	insertTestHospital(t, db, otherTenantID, "OTH"+suffix)
+	userID := insertTestUser(t, db, tenantID, "Operador Exportado")
+	insertTestUser(t, db, otherTenantID, "Operador Outro Tenant")
+	occurrenceID := insertTestOccurrence(t, db, tenantID, hospitalID, models.StatusConcluida, time.Now().Add(-time.Hour), true, true)
+
+	ruleID := uuid.New()
+	if _, err := db.Exec(`INSERT INTO triagem_rules (id, tenant_id, nome, regras) VALUES ($1, $2, 'Regra Exportada', '{}')`, ruleID, tenantID); err != nil {
+		t.Fatalf("Failed to insert triagem rule: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM triagem_rules WHERE id = $1`, ruleID) })
+
+	auditEntityID := "export-" + suffix
+	_, err := db.Exec(`
+		INSERT INTO audit_logs (tenant_id, actor_name, acao, entidade_tipo, entidade_id, severity)
+		VALUES ($1, 'Admin', $2, $3, $4, 'INFO')
+	`, tenantID, models.ActionTenantUpdate, models.EntityTypeTenant, auditEntityID)
+	if err != nil {
+		t.Fatalf("Failed to insert audit log: %v", err)
+	}
+	t.Cleanup(func() { deleteTestAuditLogs(db, auditEntityID) })
+
+	var buf bytes.Buffer
+	if err := NewAdminTenantRepository(db).ExportTenantData(context.Background(), tenantID, &buf); err != nil {
+		t.Fatalf("ExportTenantData returned error: %v", err)
+	}
+	entries := readTenantExport(t, buf.Bytes())
+
+	for _, entry := range tenantExportEntries {
+		if _, ok := entries[entry.name]; !ok {
+			t.Errorf("Expected %s in the archive", entry.name)
+		}
+	}
+
+	expected := map[string]int{
+		"tenant.json":             1,
+		"users.json":              1,
+		"hospitals.json":          1,
+		"occurrences.json":        1,
+		"occurrence_history.json": 2,
+		"triagem_rules.json":      1,
+		"audit_logs.json":         1,
+	}
+	for name, count := range expected {
+		if len(entries[name]) != count {
+			t.Errorf("Expected %d rows in %s, got %d", count, name, len(entries[name]))
+		}
+	}
+
+	if users := entries["users.json"]; len(users) == 1 {
+		if users[0]["id"] != userID.String() {
+			t.Errorf("Expected the user of the tenant, got %v", users[0]["id"])
+		}
+		if _, ok := users[0]["password_hash"]; ok {
+			t.Error("Expected the password hash to be left out of the export")
+		}
+		if _, ok := users[0]["mfa_secret"]; ok {
+			t.Error("Expected the MFA secret to be left out of the export")
+		}
+	}
+	if occurrences := entries["occurrences.json"]; len(occurrences) == 1 && occurrences[0]["id"] != occurrenceID.String() {
+		t.Errorf("Expected the occurrence of the tenant, got %v", occurrences[0]["id"])
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TenantPurgeResult counts the rows removed from each table by a tenant purge
+type TenantPurgeResult struct {
+	Tables map[string]int64 `json:"tables"`
+}
+
+// tenantPurgeStatements deletes the data of a tenant ($1) in foreign key order, like the seeder clearSeedData.
+// Super admins are kept, and tables owned by users (sessions, presets, login history, push subscriptions)
+// are removed by their ON DELETE CASCADE. Audit log entries that reference a removed user or hospital are
+// deleted first by tenantAuditPurgeQuery, since the audit triggers forbid the ON DELETE SET NULL update.
+var tenantPurgeStatements = []struct {
+	table string
+	query string
+}{
+	{"notifications", `DELETE FROM notifications WHERE tenant_id = $1`},
+	{"email_deliveries", `
+		DELETE FROM email_deliveries
+		WHERE occurrence_id IN (SELECT id FROM occurrences WHERE tenant_id = $1)
+		   OR user_id IN (SELECT id FROM users WHERE tenant_id = $1 AND is_super_admin = false)
+	`},
+	{"events_outbox", `DELETE FROM events_outbox WHERE aggregate_id IN (SELECT id FROM occurrences WHERE tenant_id = $1)`},
+	{"occurrence_history", `DELETE FROM occurrence_history WHERE occurrence_id IN (SELECT id FROM occurrences WHERE tenant_id = $1)`},
+	{"occurrences", `DELETE FROM occurrences WHERE tenant_id = $1`},
+	{"obito_ineligibilities", `DELETE FROM obito_ineligibilities WHERE tenant_id = $1`},
+	{"obitos_simulados", `DELETE FROM obitos_simulados WHERE tenant_id = $1`},
+	{"triagem_rules", `DELETE FROM triagem_rules WHERE tenant_id = $1`},
+	{"shifts", `DELETE FROM shifts WHERE tenant_id = $1`},
+	{"shift_templates", `DELETE FROM shift_templates WHERE tenant_id = $1`},
+	{"user_hospitals", `DELETE FROM user_hospitals WHERE tenant_id = $1`},
+	{"users", `DELETE FROM users WHERE tenant_id = $1 AND is_super_admin = false`},
+	{"hospitals", `DELETE FROM hospitals WHERE tenant_id = $1`},
+}
+
+// tenantAuditPurgeQuery deletes the audit log entries of the tenant ($1) and those referencing its users
+// or hospitals. audit_logs only accepts deletes from the archiver role functions (migration 054).
+const tenantAuditPurgeQuery = `SELECT purge_tenant_audit_logs($1)`
+
+// PurgeTenantData permanently deletes every record of an inactive tenant in one transaction.
+// The confirmation token must be the tenant slug. The tenant itself is kept, empty and inactive.
+func (r *AdminTenantRepository) PurgeTenantData(ctx context.Context, tenantID uuid.UUID, confirmation string) (*TenantPurgeResult, error) {
+	tenant, err := r.getTenantByIDBasic(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.IsActive {
+		return nil, ErrAdminTenantActive
+	}
+	if confirmation != tenant.Slug {
+		return nil, ErrAdminPurgeConfirmation
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &TenantPurgeResult{Tables: make(map[string]int64, len(tenantPurgeStatements)+1)}
+
+	// Audit entries go first, while the users and hospitals they reference still exist
+	var auditLogs int64
+	if err := tx.QueryRowContext(ctx, tenantAuditPurgeQuery, tenantID).Scan(&auditLogs); err != nil {
+		return nil, err
+	}
+	result.Tables["audit_logs"] = auditLogs
+
+	for _, statement := range tenantPurgeStatements {
+		res, err := tx.ExecContext(ctx, statement.query, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if result.Tables[statement.table], err = res.RowsAffected(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
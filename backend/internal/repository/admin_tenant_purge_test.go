@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// countTenantRows returns the rows left in each purged table for the tenant
+func countTenantRows(t *testing.T, db *sql.DB, tenantID uuid.UUID) map[string]int {
+	t.Helper()
+
+	queries := map[string]string{
+		"users":              `SELECT COUNT(*) FROM users WHERE tenant_id = $1`,
+		"hospitals":          `SELECT COUNT(*) FROM hospitals WHERE tenant_id = $1`,
+		"occurrences":        `SELECT COUNT(*) FROM occurrences WHERE tenant_id = $1`,
+		"obitos_simulados":   `SELECT COUNT(*) FROM obitos_simulados WHERE tenant_id = $1`,
+		"occurrence_history": `SELECT COUNT(*) FROM occurrence_history oh JOIN occurrences o ON o.id = oh.occurrence_id WHERE o.tenant_id = $1`,
+		"triagem_rules":      `SELECT COUNT(*) FROM triagem_rules WHERE tenant_id = $1`,
+		"user_hospitals":     `SELECT COUNT(*) FROM user_hospitals WHERE tenant_id = $1`,
+		"audit_logs":         `SELECT COUNT(*) FROM audit_logs WHERE tenant_id = $1`,
+	}
+
+	counts := make(map[string]int, len(queries))
+	for table, query := range queries {
+		var count int
+		if err := db.QueryRow(query, tenantID).Scan(&count); err != nil {
+			t.Fatalf("Failed to count %s: %v", table, err)
+		}
+		counts[table] = count
+	}
+	return counts
+}
+
+// seedPurgeTenant creates a hospital, an operator linked to it, an occurrence with history,
+// a triagem rule and an audit log entry for the tenant
+func seedPurgeTenant(t *testing.T, db *sql.DB, tenantID uuid.UUID, codigo string) {
+	t.Helper()
+
+	hospitalID := insertTestHospital(t, db, tenantID, codigo)
+	userID := insertTestUser(t, db, tenantID, "Operador "+codigo)
+	if _, err := db.Exec(`INSERT INTO user_hospitals (user_id, hospital_id, tenant_id) VALUES ($1, $2, $3)`, userID, hospitalID, tenantID); err != nil {
+		t.Fatalf("Failed to link user to hospital: %v", err)
+	}
+	insertTestOccurrence(t, db, tenantID, hospitalID, models.StatusConcluida, time.Now().Add(-time.Hour), true, true)
+
+	if _, err := db.Exec(`INSERT INTO triagem_rules (tenant_id, nome, regras) VALUES ($1, $2, '{}')`, tenantID, "Regra "+codigo); err != nil {
+		t.Fatalf("Failed to insert triagem rule: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM triagem_rules WHERE tenant_id = $1`, tenantID) })
+
+	_, err := db.Exec(`
+		INSERT INTO audit_logs (tenant_id, usuario_id, hospital_id, actor_name, acao, entidade_tipo, entidade_id, severity)
+		VALUES ($1, $2, $3, 'Operador', $4, 'Occurrence', $5, 'INFO')
+	`, tenantID, userID, hospitalID, models.ActionOccurrenceAccepted, "purge-"+codigo)
+	if err != nil {
+		t.Fatalf("Failed to insert audit log: %v", err)
+	}
+	t.Cleanup(func() { deleteTestAuditLogs(db, "purge-"+codigo) })
+}
+
+// TestAdminPurgeTenantData tests that the purge requires an inactive tenant and its slug, then removes every row
+// of the tenant and nothing of other tenants
+func TestAdminPurgeTenantData(t *testing.T) {
+	db := openTestDB(t)
+
+	tenantID := insertTestTenant(t, db)
+	otherTenantID := insertTestTenant(t, db)
+	suffix := uuid.New().String()[:8]
+	seedPurgeTenant(t, db, tenantID, "PRG"+suffix)
+	seedPurgeTenant(t, db, otherTenantID, "KPT"+suffix)
+
+	repo := NewAdminTenantRepository(db)
+	ctx := context.Background()
+
+	var slug string
+	if err := db.QueryRow(`SELECT slug FROM tenants WHERE id = $1`, tenantID).Scan(&slug); err != nil {
+		t.Fatalf("Failed to read tenant slug: %v", err)
+	}
+
+	if _, err := repo.PurgeTenantData(ctx, tenantID, slug); !errors.Is(err, ErrAdminTenantActive) {
+		t.Fatalf("Expected ErrAdminTenantActive for an active tenant, got %v", err)
+	}
+	if _, err := db.Exec(`UPDATE tenants SET is_active = false WHERE id = $1`, tenantID); err != nil {
+		t.Fatalf("Failed to deactivate tenant: %v", err)
+	}
+
+	if _, err := repo.PurgeTenantData(ctx, tenantID, "outro-slug"); !errors.Is(err, ErrAdminPurgeConfirmation) {
+		t.Fatalf("Expected ErrAdminPurgeConfirmation for a wrong token, got %v", err)
+	}
+	if counts := countTenantRows(t, db, tenantID); counts["occurrences"] != 1 || counts["users"] != 1 {
+		t.Fatalf("Expected nothing removed by a rejected purge, got %v", counts)
+	}
+	if _, err := repo.PurgeTenantData(ctx, uuid.New(), slug); !errors.Is(err, ErrAdminTenantNotFound) {
+		t.Errorf("Expected ErrAdminTenantNotFound, got %v", err)
+	}
+
+	result, err := repo.PurgeTenantData(ctx, tenantID, slug)
+	if err != nil {
+		t.Fatalf("PurgeTenantData returned error: %v", err)
+	}
+	if result.Tables["occurrence_history"] != 2 || result.Tables["users"] != 1 || result.Tables["hospitals"] != 1 {
+		t.Errorf("Unexpected purge counts %v", result.Tables)
+	}
+
+	for table, count := range countTenantRows(t, db, tenantID) {
+		if count != 0 {
+			t.Errorf("Expected no %s left for the purged tenant, got %d", table, count)
+		}
+	}
+	for table, count := range countTenantRows(t, db, otherTenantID) {
+		if count == 0 {
+			t.Errorf("Expected the %s of the other tenant to be kept", table)
+		}
+	}
+
+	var tenantExists bool
+	if err := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM tenants WHERE id = $1)`, tenantID).Scan(&tenantExists); err != nil || !tenantExists {
+		t.Errorf("Expected the purged tenant to be kept, got %v (%v)", tenantExists, err)
+	}
+}
+
+// TestAdminPurgeTenantAfterReassign tests that the data of the hospitals moved by a reassignment is kept when
+// the source tenant is purged, and that a reassignment conflicting with the target tenant moves nothing
+func TestAdminPurgeTenantAfterReassign(t *testing.T) {
+	db := openTestDB(t)
+
+	sourceTenantID := insertTestTenant(t, db)
+	targetTenantID := insertTestTenant(t, db)
+	suffix := uuid.New().String()[:8]
+	seedPurgeTenant(t, db, sourceTenantID, "RSG"+suffix)
+
+	var hospitalID, userID uuid.UUID
+	if err := db.QueryRow(`SELECT hospital_id, user_id FROM user_hospitals WHERE tenant_id = $1`, sourceTenantID).Scan(&hospitalID, &userID); err != nil {
+		t.Fatalf("Failed to read seeded hospital: %v", err)
+	}
+	obitoID := insertIneligibleObito(t, db, sourceTenantID, hospitalID)
+	if _, err := db.Exec(`
+		INSERT INTO obito_ineligibilities (obito_id, hospital_id, tenant_id, motivos)
+		VALUES ($1, $2, $3, '{"Idade acima do limite"}')
+	`, obitoID, hospitalID, sourceTenantID); err != nil {
+		t.Fatalf("Failed to insert ineligibility: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO shifts (hospital_id, user_id, tenant_id, day_of_week, start_time, end_time)
+		VALUES ($1, $2, $3, 1, '07:00', '19:00')
+	`, hospitalID, userID, sourceTenantID); err != nil {
+		t.Fatalf("Failed to insert shift: %v", err)
+	}
+
+	repo := NewAdminHospitalRepository(db)
+	ctx := context.Background()
+
+	// A user of the target tenant with the same email blocks the move of the users
+	var email string
+	if err := db.QueryRow(`SELECT email FROM users WHERE id = $1`, userID).Scan(&email); err != nil {
+		t.Fatalf("Failed to read user email: %v", err)
+	}
+	duplicateID := insertTestUser(t, db, targetTenantID, "Operador Duplicado")
+	if _, err := db.Exec(`UPDATE users SET email = $1 WHERE id = $2`, email, duplicateID); err != nil {
+		t.Fatalf("Failed to duplicate email: %v", err)
+	}
+	if _, err := repo.ReassignTenantHospitals(ctx, sourceTenantID, targetTenantID, true); !errors.Is(err, ErrAdminReassignConflict) {
+		t.Fatalf("Expected ErrAdminReassignConflict, got %v", err)
+	}
+	if counts := countTenantRows(t, db, sourceTenantID); counts["hospitals"] != 1 || counts["occurrences"] != 1 {
+		t.Fatalf("Expected nothing moved by a conflicting reassignment, got %v", counts)
+	}
+	if _, err := db.Exec(`DELETE FROM users WHERE id = $1`, duplicateID); err != nil {
+		t.Fatalf("Failed to delete duplicate user: %v", err)
+	}
+
+	if _, err := repo.ReassignTenantHospitals(ctx, sourceTenantID, targetTenantID, true); err != nil {
+		t.Fatalf("ReassignTenantHospitals returned error: %v", err)
+	}
+
+	var slug string
+	if err := db.QueryRow(`UPDATE tenants SET is_active = false WHERE id = $1 RETURNING slug`, sourceTenantID).Scan(&slug); err != nil {
+		t.Fatalf("Failed to deactivate tenant: %v", err)
+	}
+	result, err := NewAdminTenantRepository(db).PurgeTenantData(ctx, sourceTenantID, slug)
+	if err != nil {
+		t.Fatalf("PurgeTenantData returned error: %v", err)
+	}
+	for _, table := range []string{"occurrences", "obitos_simulados", "obito_ineligibilities", "shifts", "hospitals", "users"} {
+		if result.Tables[table] != 0 {
+			t.Errorf("Expected no %s of the moved hospital purged, got %d", table, result.Tables[table])
+		}
+	}
+
+	counts := countTenantRows(t, db, targetTenantID)
+	if counts["hospitals"] != 1 || counts["occurrences"] != 1 || counts["obitos_simulados"] != 2 || counts["users"] != 1 || counts["user_hospitals"] != 1 {
+		t.Errorf("Expected the moved data in the target tenant, got %v", counts)
+	}
+
+	var shifts, ineligibilities int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM shifts WHERE tenant_id = $1`, targetTenantID).Scan(&shifts); err != nil {
+		t.Fatalf("Failed to count shifts: %v", err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM obito_ineligibilities WHERE tenant_id = $1`, targetTenantID).Scan(&ineligibilities); err != nil {
+		t.Fatalf("Failed to count ineligibilities: %v", err)
+	}
+	if shifts != 1 || ineligibilities != 1 {
+		t.Errorf("Expected the shift and the ineligibility in the target tenant, got %d and %d", shifts, ineligibilities)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sidot/backend/internal/models"
 )
 
@@ -20,6 +21,15 @@ var (
 
 	// ErrAdminTenantSlugExists is returned when a tenant slug already exists
 	ErrAdminTenantSlugExists = errors.New("tenant with this slug already exists")
+
+	// ErrThemeVersionNotFound is returned when a tenant has no archived theme config with the requested version
+	ErrThemeVersionNotFound = errors.New("theme version not found")
+
+	// ErrAdminTenantActive is returned when the data of a tenant that is still active is purged
+	ErrAdminTenantActive = errors.New("tenant must be deactivated before its data is purged")
+
+	// ErrAdminPurgeConfirmation is returned when the purge confirmation token does not match the tenant slug
+	ErrAdminPurgeConfirmation = errors.New("confirmation token does not match the tenant slug")
 )
 
 // AdminTenantListParams contains parameters for listing tenants in admin view
@@ -107,7 +117,7 @@ func (r *AdminTenantRepository) ListAllTenants(ctx context.Context, params *Admi
 	// Get tenants with metrics
 	query := fmt.Sprintf(`
 		SELECT
-			t.id, t.name, t.slug, t.theme_config, t.is_active, t.logo_url, t.favicon_url, t.created_at, t.updated_at,
+			t.id, t.name, t.slug, t.theme_config, t.is_active, t.logo_url, t.favicon_url, t.allowed_origins, t.created_at, t.updated_at,
 			COALESCE((SELECT COUNT(*) FROM users u WHERE u.tenant_id = t.id), 0) AS user_count,
 			COALESCE((SELECT COUNT(*) FROM hospitals h WHERE h.tenant_id = t.id AND h.deleted_at IS NULL), 0) AS hospital_count,
 			COALESCE((SELECT COUNT(*) FROM occurrences o WHERE o.tenant_id = t.id), 0) AS occurrence_count
@@ -139,6 +149,7 @@ func (r *AdminTenantRepository) ListAllTenants(ctx context.Context, params *Admi
 			&isActive,
 			&logoURL,
 			&faviconURL,
+			pq.Array(&t.AllowedOrigins),
 			&t.CreatedAt,
 			&t.UpdatedAt,
 			&t.UserCount,
@@ -184,7 +195,7 @@ func (r *AdminTenantRepository) ListAllTenants(ctx context.Context, params *Admi
 func (r *AdminTenantRepository) GetTenantByID(ctx context.Context, id uuid.UUID) (*models.TenantWithMetrics, error) {
 	query := `
 		SELECT
-			t.id, t.name, t.slug, t.theme_config, t.is_active, t.logo_url, t.favicon_url, t.created_at, t.updated_at,
+			t.id, t.name, t.slug, t.theme_config, t.is_active, t.logo_url, t.favicon_url, t.allowed_origins, t.created_at, t.updated_at,
 			COALESCE((SELECT COUNT(*) FROM users u WHERE u.tenant_id = t.id), 0) AS user_count,
 			COALESCE((SELECT COUNT(*) FROM hospitals h WHERE h.tenant_id = t.id AND h.deleted_at IS NULL), 0) AS hospital_count,
 			COALESCE((SELECT COUNT(*) FROM occurrences o WHERE o.tenant_id = t.id), 0) AS occurrence_count
@@ -204,6 +215,7 @@ func (r *AdminTenantRepository) GetTenantByID(ctx context.Context, id uuid.UUID)
 		&isActive,
 		&logoURL,
 		&faviconURL,
+		pq.Array(&t.AllowedOrigins),
 		&t.CreatedAt,
 		&t.UpdatedAt,
 		&t.UserCount,
@@ -300,7 +312,7 @@ func (r *AdminTenantRepository) CreateTenant(ctx context.Context, input *models.
 	return tenant, nil
 }
 
-// UpdateTenant updates a tenant's basic info (name, slug, logo, favicon)
+// UpdateTenant updates a tenant's basic info (name, slug, logo, favicon, allowed CORS origins)
 func (r *AdminTenantRepository) UpdateTenant(ctx context.Context, id uuid.UUID, input *models.UpdateTenantInput) (*models.Tenant, error) {
 	// Validate input
 	if err := input.Validate(); err != nil {
@@ -338,12 +350,19 @@ func (r *AdminTenantRepository) UpdateTenant(ctx context.Context, id uuid.UUID,
 	if input.FaviconURL != nil {
 		tenant.FaviconURL = input.FaviconURL
 	}
+	if input.AllowedOrigins != nil {
+		tenant.AllowedOrigins = input.AllowedOrigins
+	}
+	if tenant.AllowedOrigins == nil {
+		// The column is NOT NULL; a nil slice would be written as NULL
+		tenant.AllowedOrigins = []string{}
+	}
 	tenant.UpdatedAt = time.Now()
 
 	query := `
 		UPDATE tenants
-		SET name = $1, slug = $2, is_active = $3, logo_url = $4, favicon_url = $5, updated_at = $6
-		WHERE id = $7
+		SET name = $1, slug = $2, is_active = $3, logo_url = $4, favicon_url = $5, allowed_origins = $6, updated_at = $7
+		WHERE id = $8
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
@@ -352,6 +371,7 @@ func (r *AdminTenantRepository) UpdateTenant(ctx context.Context, id uuid.UUID,
 		tenant.IsActive,
 		tenant.LogoURL,
 		tenant.FaviconURL,
+		pq.Array(tenant.AllowedOrigins),
 		tenant.UpdatedAt,
 		id,
 	)
@@ -371,7 +391,8 @@ func (r *AdminTenantRepository) UpdateTenant(ctx context.Context, id uuid.UUID,
 }
 
 // UpdateThemeConfig updates the tenant's theme_config JSONB field
-func (r *AdminTenantRepository) UpdateThemeConfig(ctx context.Context, id uuid.UUID, themeConfig models.ThemeConfig) (*models.Tenant, error) {
+// The config being replaced is archived in tenant_theme_versions so it can be rolled back
+func (r *AdminTenantRepository) UpdateThemeConfig(ctx context.Context, id uuid.UUID, themeConfig models.ThemeConfig, authorID *uuid.UUID) (*models.Tenant, error) {
 	// Validate theme config
 	if err := models.ValidateThemeConfig(&themeConfig); err != nil {
 		return nil, err
@@ -383,18 +404,139 @@ func (r *AdminTenantRepository) UpdateThemeConfig(ctx context.Context, id uuid.U
 		return nil, err
 	}
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tenant, err := replaceThemeConfig(ctx, tx, id, string(themeConfigJSON), authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return tenant, nil
+}
+
+// ListThemeVersions returns the archived theme configs of a tenant, newest first
+func (r *AdminTenantRepository) ListThemeVersions(ctx context.Context, tenantID uuid.UUID) ([]models.TenantThemeVersion, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM tenants WHERE id = $1)", tenantID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrAdminTenantNotFound
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT v.id, v.tenant_id, v.version, v.theme_config, v.created_by, u.nome, v.created_at
+		FROM tenant_theme_versions v
+		LEFT JOIN users u ON u.id = v.created_by
+		WHERE v.tenant_id = $1
+		ORDER BY v.version DESC
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []models.TenantThemeVersion{}
+	for rows.Next() {
+		var v models.TenantThemeVersion
+		var themeConfigStr string
+		var createdBy uuid.NullUUID
+		var createdByNome sql.NullString
+
+		if err := rows.Scan(&v.ID, &v.TenantID, &v.Version, &themeConfigStr, &createdBy, &createdByNome, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		v.ThemeConfig = json.RawMessage(themeConfigStr)
+		if createdBy.Valid {
+			v.CreatedBy = &createdBy.UUID
+		}
+		if createdByNome.Valid {
+			v.CreatedByNome = &createdByNome.String
+		}
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
+
+// RollbackThemeConfig restores an archived theme config of a tenant
+// The current config is archived first, so a rollback can itself be rolled back
+func (r *AdminTenantRepository) RollbackThemeConfig(ctx context.Context, tenantID uuid.UUID, version int, authorID *uuid.UUID) (*models.Tenant, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var themeConfigStr string
+	err = tx.QueryRowContext(ctx, `
+		SELECT theme_config FROM tenant_theme_versions
+		WHERE tenant_id = $1 AND version = $2
+	`, tenantID, version).Scan(&themeConfigStr)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrThemeVersionNotFound
+		}
+		return nil, err
+	}
+
+	tenant, err := replaceThemeConfig(ctx, tx, tenantID, themeConfigStr, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return tenant, nil
+}
+
+// replaceThemeConfig archives the tenant's current theme_config as the next version and replaces it
+// The tenant row is locked so concurrent changes get sequential version numbers
+func replaceThemeConfig(ctx context.Context, tx *sql.Tx, id uuid.UUID, themeConfigJSON string, authorID *uuid.UUID) (*models.Tenant, error) {
+	var current sql.NullString
+	err := tx.QueryRowContext(ctx, "SELECT theme_config FROM tenants WHERE id = $1 FOR UPDATE", id).Scan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAdminTenantNotFound
+		}
+		return nil, err
+	}
+
+	if current.Valid {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO tenant_theme_versions (tenant_id, version, theme_config, created_by, created_at)
+			SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4
+			FROM tenant_theme_versions
+			WHERE tenant_id = $1
+		`, id, current.String, authorID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	query := `
 		UPDATE tenants
 		SET theme_config = $1, updated_at = $2
 		WHERE id = $3
-		RETURNING id, name, slug, theme_config, is_active, logo_url, favicon_url, created_at, updated_at
+		RETURNING id, name, slug, theme_config, is_active, logo_url, favicon_url, allowed_origins, created_at, updated_at
 	`
 
 	var t models.Tenant
 	var themeConfigStr, logoURL, faviconURL sql.NullString
 	var isActive sql.NullBool
 
-	err = r.db.QueryRowContext(ctx, query, string(themeConfigJSON), time.Now(), id).Scan(
+	err = tx.QueryRowContext(ctx, query, themeConfigJSON, time.Now(), id).Scan(
 		&t.ID,
 		&t.Name,
 		&t.Slug,
@@ -402,6 +544,7 @@ func (r *AdminTenantRepository) UpdateThemeConfig(ctx context.Context, id uuid.U
 		&isActive,
 		&logoURL,
 		&faviconURL,
+		pq.Array(&t.AllowedOrigins),
 		&t.CreatedAt,
 		&t.UpdatedAt,
 	)
@@ -437,7 +580,7 @@ func (r *AdminTenantRepository) ToggleTenantActive(ctx context.Context, id uuid.
 		UPDATE tenants
 		SET is_active = NOT COALESCE(is_active, true), updated_at = $1
 		WHERE id = $2
-		RETURNING id, name, slug, theme_config, is_active, logo_url, favicon_url, created_at, updated_at
+		RETURNING id, name, slug, theme_config, is_active, logo_url, favicon_url, allowed_origins, created_at, updated_at
 	`
 
 	var t models.Tenant
@@ -452,6 +595,7 @@ func (r *AdminTenantRepository) ToggleTenantActive(ctx context.Context, id uuid.
 		&isActive,
 		&logoURL,
 		&faviconURL,
+		pq.Array(&t.AllowedOrigins),
 		&t.CreatedAt,
 		&t.UpdatedAt,
 	)
@@ -514,7 +658,7 @@ func (r *AdminTenantRepository) UpdateAssets(ctx context.Context, id uuid.UUID,
 		UPDATE tenants
 		SET %s
 		WHERE id = $%d
-		RETURNING id, name, slug, theme_config, is_active, logo_url, favicon_url, created_at, updated_at
+		RETURNING id, name, slug, theme_config, is_active, logo_url, favicon_url, allowed_origins, created_at, updated_at
 	`, strings.Join(setClauses, ", "), argIndex)
 
 	var t models.Tenant
@@ -529,6 +673,7 @@ func (r *AdminTenantRepository) UpdateAssets(ctx context.Context, id uuid.UUID,
 		&isActive,
 		&logo,
 		&favicon,
+		pq.Array(&t.AllowedOrigins),
 		&t.CreatedAt,
 		&t.UpdatedAt,
 	)
@@ -574,7 +719,7 @@ func (r *AdminTenantRepository) ExistsBySlug(ctx context.Context, slug string) (
 // getTenantByIDBasic is a helper to get a tenant without metrics
 func (r *AdminTenantRepository) getTenantByIDBasic(ctx context.Context, id uuid.UUID) (*models.Tenant, error) {
 	query := `
-		SELECT id, name, slug, theme_config, is_active, logo_url, favicon_url, created_at, updated_at
+		SELECT id, name, slug, theme_config, is_active, logo_url, favicon_url, allowed_origins, created_at, updated_at
 		FROM tenants
 		WHERE id = $1
 	`
@@ -591,6 +736,7 @@ func (r *AdminTenantRepository) getTenantByIDBasic(ctx context.Context, id uuid.
 		&isActive,
 		&logoURL,
 		&faviconURL,
+		pq.Array(&t.AllowedOrigins),
 		&t.CreatedAt,
 		&t.UpdatedAt,
 	)
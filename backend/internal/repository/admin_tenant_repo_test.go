@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// themeConfigWithPrimary returns a theme config with a custom sidebar and widget to cover nested fields
+func themeConfigWithPrimary(primary string) models.ThemeConfig {
+	config := models.DefaultThemeConfig()
+	config.Theme.Colors.Primary = primary
+	config.Layout.Sidebar = []models.SidebarItem{
+		{Label: "Dashboard", Icon: "home", Link: "/dashboard", Roles: []string{"operador", "gestor"}, Order: 1},
+	}
+	config.Layout.DashboardWidgets = []models.DashboardWidget{
+		{Type: "stats", Visible: true, Order: 1, Config: map[string]interface{}{"period": "7d", "limit": float64(5)}},
+	}
+	return config
+}
+
+// parseThemeConfig decodes a stored theme config
+func parseThemeConfig(t *testing.T, data json.RawMessage) models.ThemeConfig {
+	t.Helper()
+
+	var config models.ThemeConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("Failed to parse theme config %s: %v", data, err)
+	}
+	return config
+}
+
+// TestAdminThemeConfigRollback tests that theme changes are archived and a rollback restores a prior config exactly
+func TestAdminThemeConfigRollback(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewAdminTenantRepository(db)
+	ctx := context.Background()
+
+	original := themeConfigWithPrimary("#111111")
+	tenant, err := repo.CreateTenant(ctx, &models.CreateTenantInput{
+		Name:        "Tenant Tema",
+		Slug:        "tema-" + uuid.New().String()[:8],
+		ThemeConfig: &original,
+	})
+	if err != nil {
+		t.Fatalf("CreateTenant returned error: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM tenants WHERE id = $1`, tenant.ID) })
+
+	second := themeConfigWithPrimary("#222222")
+	second.Layout.Topbar.ShowTenantLogo = false
+	third := themeConfigWithPrimary("#333333")
+	for _, config := range []models.ThemeConfig{second, third} {
+		if _, err := repo.UpdateThemeConfig(ctx, tenant.ID, config, nil); err != nil {
+			t.Fatalf("UpdateThemeConfig returned error: %v", err)
+		}
+	}
+
+	versions, err := repo.ListThemeVersions(ctx, tenant.ID)
+	if err != nil {
+		t.Fatalf("ListThemeVersions returned error: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != 2 || versions[1].Version != 1 {
+		t.Fatalf("Expected versions 2 and 1, got %+v", versions)
+	}
+	if got := parseThemeConfig(t, versions[1].ThemeConfig); !reflect.DeepEqual(got, original) {
+		t.Errorf("Expected version 1 to hold the original config, got %+v", got)
+	}
+
+	restored, err := repo.RollbackThemeConfig(ctx, tenant.ID, 2, nil)
+	if err != nil {
+		t.Fatalf("RollbackThemeConfig returned error: %v", err)
+	}
+	if got := parseThemeConfig(t, restored.ThemeConfig); !reflect.DeepEqual(got, second) {
+		t.Errorf("Expected rollback to restore %+v, got %+v", second, got)
+	}
+
+	// The config replaced by the rollback is archived as a new version
+	versions, err = repo.ListThemeVersions(ctx, tenant.ID)
+	if err != nil {
+		t.Fatalf("ListThemeVersions returned error: %v", err)
+	}
+	if len(versions) != 3 || versions[0].Version != 3 {
+		t.Fatalf("Expected 3 versions after rollback, got %+v", versions)
+	}
+	if got := parseThemeConfig(t, versions[0].ThemeConfig); !reflect.DeepEqual(got, third) {
+		t.Errorf("Expected version 3 to hold the config replaced by the rollback, got %+v", got)
+	}
+
+	if _, err := repo.RollbackThemeConfig(ctx, tenant.ID, 99, nil); !errors.Is(err, ErrThemeVersionNotFound) {
+		t.Errorf("Expected ErrThemeVersionNotFound, got %v", err)
+	}
+	if _, err := repo.ListThemeVersions(ctx, uuid.New()); !errors.Is(err, ErrAdminTenantNotFound) {
+		t.Errorf("Expected ErrAdminTenantNotFound for an unknown tenant, got %v", err)
+	}
+}
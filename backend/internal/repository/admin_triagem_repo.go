@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/sidot/backend/internal/models"
 )
 
@@ -22,11 +23,16 @@ var (
 // AdminTriagemTemplateRepository handles admin-level triagem template data access
 type AdminTriagemTemplateRepository struct {
 	db *sql.DB
+	// rules invalidates the triagem rules cache of every instance when templates are cloned into tenants
+	rules *TriagemRuleRepository
 }
 
 // NewAdminTriagemTemplateRepository creates a new admin triagem template repository
-func NewAdminTriagemTemplateRepository(db *sql.DB) *AdminTriagemTemplateRepository {
-	return &AdminTriagemTemplateRepository{db: db}
+func NewAdminTriagemTemplateRepository(db *sql.DB, redisClient *redis.Client) *AdminTriagemTemplateRepository {
+	return &AdminTriagemTemplateRepository{
+		db:    db,
+		rules: NewTriagemRuleRepository(db, redisClient),
+	}
 }
 
 // AdminTriagemTemplateListParams contains parameters for listing templates
@@ -47,27 +53,50 @@ type AdminTriagemTemplateListResult struct {
 	TotalPages int                                   `json:"total_pages"`
 }
 
-// TemplateUsage represents which tenants use similar rules to a template
+// TemplateUsage represents a tenant with rules cloned from a template
 type TemplateUsage struct {
-	TenantID   uuid.UUID `json:"tenant_id"`
-	TenantName string    `json:"tenant_name"`
-	TenantSlug string    `json:"tenant_slug"`
-	RuleCount  int       `json:"rule_count"`
+	TenantID      uuid.UUID     `json:"tenant_id"`
+	TenantName    string        `json:"tenant_name"`
+	TenantSlug    string        `json:"tenant_slug"`
+	RuleCount     int           `json:"rule_count"`
+	ModifiedCount int           `json:"modified_count"`
+	Rules         []DerivedRule `json:"rules"`
+}
+
+// DerivedRule is a tenant rule cloned from a template
+type DerivedRule struct {
+	RuleID uuid.UUID `json:"rule_id"`
+	Nome   string    `json:"nome"`
+	Ativo  bool      `json:"ativo"`
+	// Modified is nil when the rule predates clone tracking and its original state is unknown
+	Modified  *bool     `json:"modified"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // TemplateUsageResult contains the usage information for a template
 type TemplateUsageResult struct {
-	TemplateID  uuid.UUID       `json:"template_id"`
-	TotalTenants int            `json:"total_tenants"`
-	Tenants     []TemplateUsage `json:"tenants"`
+	TemplateID   uuid.UUID       `json:"template_id"`
+	TotalTenants int             `json:"total_tenants"`
+	Tenants      []TemplateUsage `json:"tenants"`
 }
 
 // CloneResult contains the result of cloning a template to tenants
 type CloneResult struct {
-	TemplateID    uuid.UUID   `json:"template_id"`
-	ClonedToTenants []uuid.UUID `json:"cloned_to_tenants"`
-	SuccessCount  int         `json:"success_count"`
-	FailedTenants []uuid.UUID `json:"failed_tenants,omitempty"`
+	TemplateID      uuid.UUID           `json:"template_id"`
+	ClonedToTenants []uuid.UUID         `json:"cloned_to_tenants"`
+	SuccessCount    int                 `json:"success_count"`
+	FailedTenants   []uuid.UUID         `json:"failed_tenants,omitempty"`
+	FailureCount    int                 `json:"failure_count"`
+	Results         []CloneTenantResult `json:"results"`
+}
+
+// CloneTenantResult is the outcome of cloning a template to a single tenant
+type CloneTenantResult struct {
+	TenantID uuid.UUID  `json:"tenant_id"`
+	Success  bool       `json:"success"`
+	RuleID   *uuid.UUID `json:"rule_id,omitempty"`
+	Error    string     `json:"error,omitempty"`
 }
 
 // ListTemplates returns all triagem rule templates with optional filters and pagination
@@ -131,7 +160,7 @@ func (r *AdminTriagemTemplateRepository) ListTemplates(ctx context.Context, para
 			COALESCE((
 				SELECT COUNT(DISTINCT tr.tenant_id)
 				FROM triagem_rules tr
-				WHERE tr.nome = t.nome
+				WHERE tr.source_template_id = t.id
 			), 0) AS tenant_count
 		FROM triagem_rule_templates t
 		%s
@@ -198,7 +227,7 @@ func (r *AdminTriagemTemplateRepository) GetTemplateByID(ctx context.Context, id
 			COALESCE((
 				SELECT COUNT(DISTINCT tr.tenant_id)
 				FROM triagem_rules tr
-				WHERE tr.nome = t.nome
+				WHERE tr.source_template_id = t.id
 			), 0) AS tenant_count
 		FROM triagem_rule_templates t
 		WHERE t.id = $1
@@ -376,6 +405,8 @@ func (r *AdminTriagemTemplateRepository) UpdateTemplate(ctx context.Context, id
 }
 
 // CloneToTenant copies a template to one or more tenant's triagem_rules table
+// Each tenant is cloned independently: a failure is reported in the tenant's result and the rest continue.
+// When any tenant received the rule, the triagem rules cache is invalidated so the motor applies it right away.
 func (r *AdminTriagemTemplateRepository) CloneToTenant(ctx context.Context, templateID uuid.UUID, tenantIDs []uuid.UUID) (*CloneResult, error) {
 	// Get the template
 	template, err := r.GetTemplateByID(ctx, templateID)
@@ -383,93 +414,212 @@ func (r *AdminTriagemTemplateRepository) CloneToTenant(ctx context.Context, temp
 		return nil, err
 	}
 
+	result := cloneToTenants(ctx, templateID, tenantIDs, func(ctx context.Context, tenantID uuid.UUID) (uuid.UUID, error) {
+		return r.cloneTemplateToTenant(ctx, &template.TriagemRuleTemplate, tenantID)
+	})
+
+	if result.SuccessCount > 0 {
+		r.rules.InvalidateCache(ctx)
+	}
+
+	return result, nil
+}
+
+// cloneToTenants runs clone for every tenant and collects a per-tenant result
+func cloneToTenants(ctx context.Context, templateID uuid.UUID, tenantIDs []uuid.UUID, clone func(ctx context.Context, tenantID uuid.UUID) (uuid.UUID, error)) *CloneResult {
 	result := &CloneResult{
 		TemplateID:      templateID,
 		ClonedToTenants: make([]uuid.UUID, 0),
 		FailedTenants:   make([]uuid.UUID, 0),
+		Results:         make([]CloneTenantResult, 0, len(tenantIDs)),
 	}
 
-	// Clone to each tenant
 	for _, tenantID := range tenantIDs {
-		query := `
-			INSERT INTO triagem_rules (id, tenant_id, nome, descricao, regras, ativo, prioridade, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		`
-
-		_, err := r.db.ExecContext(ctx, query,
-			uuid.New(),
-			tenantID,
-			template.Nome,
-			template.Descricao,
-			string(template.Condicao),
-			template.Ativo,
-			template.Prioridade,
-			time.Now(),
-			time.Now(),
-		)
+		tenantResult := CloneTenantResult{TenantID: tenantID}
 
+		ruleID, err := clone(ctx, tenantID)
 		if err != nil {
+			tenantResult.Error = cloneFailureReason(err)
 			result.FailedTenants = append(result.FailedTenants, tenantID)
+			result.FailureCount++
 		} else {
+			tenantResult.Success = true
+			tenantResult.RuleID = &ruleID
 			result.ClonedToTenants = append(result.ClonedToTenants, tenantID)
 			result.SuccessCount++
 		}
+
+		result.Results = append(result.Results, tenantResult)
 	}
 
-	return result, nil
+	return result
+}
+
+// cloneFailureReason describes why cloning to a tenant failed, without exposing database details
+func cloneFailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrAdminTenantNotFound):
+		return "tenant not found"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "request cancelled before the tenant was processed"
+	case isUniqueViolation(err):
+		return "tenant already has a conflicting triagem rule"
+	default:
+		return "failed to create triagem rule"
+	}
+}
+
+// cloneTemplateToTenant inserts a template as a triagem rule of a tenant and returns the new rule ID
+func (r *AdminTriagemTemplateRepository) cloneTemplateToTenant(ctx context.Context, template *models.TriagemRuleTemplate, tenantID uuid.UUID) (uuid.UUID, error) {
+	var tenantExists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM tenants WHERE id = $1)", tenantID).Scan(&tenantExists)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !tenantExists {
+		return uuid.Nil, ErrAdminTenantNotFound
+	}
+
+	checksum, err := template.CloneChecksum()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	query := `
+		INSERT INTO triagem_rules (id, tenant_id, nome, descricao, regras, ativo, prioridade, source_template_id, source_template_checksum, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	ruleID := uuid.New()
+	_, err = r.db.ExecContext(ctx, query,
+		ruleID,
+		tenantID,
+		template.Nome,
+		template.Descricao,
+		string(template.Condicao),
+		template.Ativo,
+		template.Prioridade,
+		template.ID,
+		checksum,
+		time.Now(),
+		time.Now(),
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return ruleID, nil
 }
 
-// GetTemplateUsage returns which tenants have rules similar to a template
+// GetTemplateUsage returns the tenants with rules cloned from a template and whether each rule was modified since
 func (r *AdminTriagemTemplateRepository) GetTemplateUsage(ctx context.Context, templateID uuid.UUID) (*TemplateUsageResult, error) {
 	// Get template first
-	template, err := r.GetTemplateByID(ctx, templateID)
-	if err != nil {
+	if _, err := r.GetTemplateByID(ctx, templateID); err != nil {
 		return nil, err
 	}
 
-	// Find tenants with rules matching this template's nome
 	query := `
 		SELECT
-			t.id AS tenant_id,
-			t.name AS tenant_name,
-			t.slug AS tenant_slug,
-			COUNT(tr.id) AS rule_count
-		FROM tenants t
-		INNER JOIN triagem_rules tr ON tr.tenant_id = t.id
-		WHERE tr.nome = $1
-		GROUP BY t.id, t.name, t.slug
-		ORDER BY t.name ASC
+			t.id, t.name, t.slug,
+			tr.id, tr.nome, tr.descricao, tr.regras, tr.ativo, tr.prioridade,
+			tr.source_template_checksum, tr.created_at, tr.updated_at
+		FROM triagem_rules tr
+		INNER JOIN tenants t ON t.id = tr.tenant_id
+		WHERE tr.source_template_id = $1
+		ORDER BY t.name ASC, tr.created_at ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, template.Nome)
+	rows, err := r.db.QueryContext(ctx, query, templateID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query template usage: %w", err)
 	}
 	defer rows.Close()
 
-	result := &TemplateUsageResult{
-		TemplateID: templateID,
-		Tenants:    make([]TemplateUsage, 0),
-	}
-
+	var derived []derivedRuleRow
 	for rows.Next() {
-		var usage TemplateUsage
+		var row derivedRuleRow
+		var descricao, checksum sql.NullString
+		var regras string
 		err := rows.Scan(
-			&usage.TenantID,
-			&usage.TenantName,
-			&usage.TenantSlug,
-			&usage.RuleCount,
+			&row.TenantID,
+			&row.TenantName,
+			&row.TenantSlug,
+			&row.Rule.ID,
+			&row.Rule.Nome,
+			&descricao,
+			&regras,
+			&row.Rule.Ativo,
+			&row.Rule.Prioridade,
+			&checksum,
+			&row.Rule.CreatedAt,
+			&row.Rule.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan usage: %w", err)
 		}
-		result.Tenants = append(result.Tenants, usage)
+		row.Rule.Regras = json.RawMessage(regras)
+		if descricao.Valid {
+			row.Rule.Descricao = &descricao.String
+		}
+		if checksum.Valid {
+			row.CloneChecksum = &checksum.String
+		}
+		derived = append(derived, row)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating usage: %w", err)
 	}
 
+	return buildTemplateUsage(templateID, derived)
+}
+
+// derivedRuleRow is a rule cloned from a template, with its tenant and clone-time checksum
+type derivedRuleRow struct {
+	TenantID      uuid.UUID
+	TenantName    string
+	TenantSlug    string
+	Rule          models.TriagemRule
+	CloneChecksum *string
+}
+
+// buildTemplateUsage groups derived rules by tenant (rows must be ordered by tenant) and detects modified rules
+func buildTemplateUsage(templateID uuid.UUID, rows []derivedRuleRow) (*TemplateUsageResult, error) {
+	result := &TemplateUsageResult{
+		TemplateID: templateID,
+		Tenants:    make([]TemplateUsage, 0),
+	}
+
+	for _, row := range rows {
+		modified, err := row.Rule.ModifiedSinceClone(row.CloneChecksum)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check rule %s: %w", row.Rule.ID, err)
+		}
+
+		if n := len(result.Tenants); n == 0 || result.Tenants[n-1].TenantID != row.TenantID {
+			result.Tenants = append(result.Tenants, TemplateUsage{
+				TenantID:   row.TenantID,
+				TenantName: row.TenantName,
+				TenantSlug: row.TenantSlug,
+				Rules:      make([]DerivedRule, 0, 1),
+			})
+		}
+		usage := &result.Tenants[len(result.Tenants)-1]
+
+		usage.Rules = append(usage.Rules, DerivedRule{
+			RuleID:    row.Rule.ID,
+			Nome:      row.Rule.Nome,
+			Ativo:     row.Rule.Ativo,
+			Modified:  modified,
+			CreatedAt: row.Rule.CreatedAt,
+			UpdatedAt: row.Rule.UpdatedAt,
+		})
+		usage.RuleCount++
+		if modified != nil && *modified {
+			usage.ModifiedCount++
+		}
+	}
+
 	result.TotalTenants = len(result.Tenants)
 	return result, nil
 }
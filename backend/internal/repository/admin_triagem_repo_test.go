@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/sidot/backend/internal/models"
+)
+
+// TestCloneToTenantsContinuesAfterFailure tests that one tenant failing does not stop the others from being cloned
+func TestCloneToTenantsContinuesAfterFailure(t *testing.T) {
+	templateID := uuid.New()
+	ok1, missing, conflict, ok2 := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	tenantIDs := []uuid.UUID{ok1, missing, conflict, ok2}
+
+	var attempted []uuid.UUID
+	result := cloneToTenants(context.Background(), templateID, tenantIDs, func(ctx context.Context, tenantID uuid.UUID) (uuid.UUID, error) {
+		attempted = append(attempted, tenantID)
+		switch tenantID {
+		case missing:
+			return uuid.Nil, ErrAdminTenantNotFound
+		case conflict:
+			return uuid.Nil, fmt.Errorf("pq: duplicate key value violates unique constraint (SQLSTATE 23505)")
+		}
+		return uuid.New(), nil
+	})
+
+	if len(attempted) != len(tenantIDs) {
+		t.Fatalf("Expected every tenant to be attempted, got %d of %d", len(attempted), len(tenantIDs))
+	}
+	if result.TemplateID != templateID || result.SuccessCount != 2 || result.FailureCount != 2 {
+		t.Errorf("Expected 2 successes and 2 failures, got %+v", result)
+	}
+	if len(result.ClonedToTenants) != 2 || result.ClonedToTenants[0] != ok1 || result.ClonedToTenants[1] != ok2 {
+		t.Errorf("Unexpected cloned tenants %v", result.ClonedToTenants)
+	}
+	if len(result.FailedTenants) != 2 || result.FailedTenants[0] != missing || result.FailedTenants[1] != conflict {
+		t.Errorf("Unexpected failed tenants %v", result.FailedTenants)
+	}
+
+	if len(result.Results) != len(tenantIDs) {
+		t.Fatalf("Expected one result per tenant, got %d", len(result.Results))
+	}
+	expected := map[uuid.UUID]string{
+		missing:  "tenant not found",
+		conflict: "tenant already has a conflicting triagem rule",
+	}
+	for i, tenantResult := range result.Results {
+		if tenantResult.TenantID != tenantIDs[i] {
+			t.Errorf("Result %d: expected tenant %s, got %s", i, tenantIDs[i], tenantResult.TenantID)
+		}
+		reason, shouldFail := expected[tenantResult.TenantID]
+		if shouldFail {
+			if tenantResult.Success || tenantResult.RuleID != nil || tenantResult.Error != reason {
+				t.Errorf("Result %d: expected failure %q, got %+v", i, reason, tenantResult)
+			}
+		} else if !tenantResult.Success || tenantResult.RuleID == nil || tenantResult.Error != "" {
+			t.Errorf("Result %d: expected success with a rule ID, got %+v", i, tenantResult)
+		}
+	}
+}
+
+// TestCloneFailureReasonHidesDatabaseErrors tests that unexpected errors are reported without database details
+func TestCloneFailureReasonHidesDatabaseErrors(t *testing.T) {
+	if reason := cloneFailureReason(errors.New(`pq: insert or update on table "triagem_rules" violates foreign key constraint`)); reason != "failed to create triagem rule" {
+		t.Errorf("Unexpected reason %q", reason)
+	}
+	if reason := cloneFailureReason(fmt.Errorf("query: %w", context.DeadlineExceeded)); reason != "request cancelled before the tenant was processed" {
+		t.Errorf("Unexpected reason %q", reason)
+	}
+}
+
+// TestBuildTemplateUsageDetectsModifiedRules tests that derived rules are grouped by tenant and edited rules are flagged
+func TestBuildTemplateUsageDetectsModifiedRules(t *testing.T) {
+	template := models.TriagemRuleTemplate{
+		ID:         uuid.New(),
+		Nome:       "Janela de 6 horas",
+		Condicao:   json.RawMessage(`{"tipo": "janela_horas", "valor": 6, "acao": "rejeitar"}`),
+		Ativo:      true,
+		Prioridade: 50,
+	}
+	checksum, err := template.CloneChecksum()
+	if err != nil {
+		t.Fatalf("CloneChecksum() error = %v", err)
+	}
+
+	cloned := func() models.TriagemRule {
+		return models.TriagemRule{
+			ID:         uuid.New(),
+			Nome:       template.Nome,
+			Regras:     json.RawMessage(`{"acao": "rejeitar", "tipo": "janela_horas", "valor": 6}`),
+			Ativo:      template.Ativo,
+			Prioridade: template.Prioridade,
+		}
+	}
+	edited := cloned()
+	edited.Regras = json.RawMessage(`{"tipo": "janela_horas", "valor": 12, "acao": "rejeitar"}`)
+	untracked := cloned()
+
+	tenantA, tenantB := uuid.New(), uuid.New()
+	rows := []derivedRuleRow{
+		{TenantID: tenantA, TenantName: "Central GO", Rule: cloned(), CloneChecksum: &checksum},
+		{TenantID: tenantA, TenantName: "Central GO", Rule: edited, CloneChecksum: &checksum},
+		{TenantID: tenantB, TenantName: "Central SP", Rule: untracked},
+	}
+
+	result, err := buildTemplateUsage(template.ID, rows)
+	if err != nil {
+		t.Fatalf("buildTemplateUsage() error = %v", err)
+	}
+
+	if result.TotalTenants != 2 || len(result.Tenants) != 2 {
+		t.Fatalf("Expected 2 tenants, got %d", result.TotalTenants)
+	}
+
+	a := result.Tenants[0]
+	if a.TenantID != tenantA || a.RuleCount != 2 || a.ModifiedCount != 1 {
+		t.Errorf("Expected tenant A with 2 rules and 1 modified, got %+v", a)
+	}
+	if a.Rules[0].Modified == nil || *a.Rules[0].Modified {
+		t.Errorf("Expected unchanged clone not to be flagged as modified")
+	}
+	if a.Rules[1].Modified == nil || !*a.Rules[1].Modified || a.Rules[1].RuleID != edited.ID {
+		t.Errorf("Expected edited rule to be flagged as modified")
+	}
+
+	b := result.Tenants[1]
+	if b.TenantID != tenantB || b.RuleCount != 1 || b.ModifiedCount != 0 || b.Rules[0].Modified != nil {
+		t.Errorf("Expected tenant B with 1 rule of unknown modification state, got %+v", b)
+	}
+}
+
+// TestBuildTemplateUsageEmpty tests that a template without derived rules reports no tenants
+func TestBuildTemplateUsageEmpty(t *testing.T) {
+	result, err := buildTemplateUsage(uuid.New(), nil)
+	if err != nil {
+		t.Fatalf("buildTemplateUsage() error = %v", err)
+	}
+	if result.TotalTenants != 0 || result.Tenants == nil || len(result.Tenants) != 0 {
+		t.Errorf("Expected an empty, non-nil tenant list, got %+v", result)
+	}
+}
@@ -63,8 +63,8 @@ func (r *AdminUserRepository) ListAllUsers(ctx context.Context, params *AdminUse
 		params.Status = "all"
 	}
 
-	// Build WHERE clause
-	var conditions []string
+	// Build WHERE clause, excluding soft-deleted users
+	conditions := []string{"u.deleted_at IS NULL"}
 	var args []interface{}
 	argIndex := 1
 
@@ -98,10 +98,7 @@ func (r *AdminUserRepository) ListAllUsers(ctx context.Context, params *AdminUse
 		argIndex++
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
 
 	// Count total
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM users u %s`, whereClause)
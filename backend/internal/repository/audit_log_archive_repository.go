@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sidot/backend/internal/models"
+)
+
+// AuditLogArchiveRepository reads audit log entries past retention and removes them once archived
+type AuditLogArchiveRepository struct {
+	db *sql.DB
+}
+
+// NewAuditLogArchiveRepository creates a new audit log archive repository
+func NewAuditLogArchiveRepository(db *sql.DB) *AuditLogArchiveRepository {
+	return &AuditLogArchiveRepository{db: db}
+}
+
+// ListBefore returns up to limit audit log entries older than cutoff, oldest first, across all tenants
+func (r *AuditLogArchiveRepository) ListBefore(ctx context.Context, cutoff time.Time, limit int) ([]models.AuditLog, error) {
+	query := `
+		SELECT
+			id, tenant_id, timestamp, usuario_id, actor_name, acao,
+			entidade_tipo, entidade_id, hospital_id, severity,
+			detalhes, ip_address, user_agent
+		FROM audit_logs
+		WHERE timestamp < $1
+		ORDER BY timestamp ASC, id ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []models.AuditLog
+	for rows.Next() {
+		var entry models.AuditLog
+		var tenantID, usuarioID, hospitalID, detalhes, ipAddress, userAgent sql.NullString
+
+		err := rows.Scan(
+			&entry.ID, &tenantID, &entry.Timestamp, &usuarioID, &entry.ActorName, &entry.Acao,
+			&entry.EntidadeTipo, &entry.EntidadeID, &hospitalID, &entry.Severity,
+			&detalhes, &ipAddress, &userAgent,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+
+		entry.TenantID = archiveNullUUID(tenantID)
+		entry.UsuarioID = archiveNullUUID(usuarioID)
+		entry.HospitalID = archiveNullUUID(hospitalID)
+		if detalhes.Valid {
+			entry.Detalhes = json.RawMessage(detalhes.String)
+		}
+		if ipAddress.Valid {
+			entry.IPAddress = &ipAddress.String
+		}
+		if userAgent.Valid {
+			entry.UserAgent = &userAgent.String
+		}
+
+		logs = append(logs, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit logs: %w", err)
+	}
+
+	return logs, nil
+}
+
+// DeleteArchived deletes the archived audit log entries older than cutoff and records the archive in one transaction
+func (r *AuditLogArchiveRepository) DeleteArchived(ctx context.Context, archive *models.AuditLogArchive, ids []uuid.UUID, cutoff time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// audit_logs only accepts deletes from the archiver role functions (migration 054), which also enforce the cutoff
+	var deleted int64
+	if err := tx.QueryRowContext(ctx, `SELECT archive_audit_logs($1, $2)`, pq.Array(ids), cutoff).Scan(&deleted); err != nil {
+		return fmt.Errorf("failed to delete archived audit logs: %w", err)
+	}
+	if int(deleted) != len(ids) {
+		return fmt.Errorf("expected to delete %d archived audit logs, deleted %d", len(ids), deleted)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO audit_log_archives (id, location, entries, from_timestamp, to_timestamp)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`, archive.ID, archive.Location, archive.Entries, archive.FromTimestamp, archive.ToTimestamp).Scan(&archive.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record audit log archive: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// archiveNullUUID parses a nullable UUID column, nil when NULL or invalid
+func archiveNullUUID(value sql.NullString) *uuid.UUID {
+	if !value.Valid {
+		return nil
+	}
+	id, err := uuid.Parse(value.String)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
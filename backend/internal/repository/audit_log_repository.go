@@ -82,70 +82,15 @@ func (r *AuditLogRepository) List(ctx context.Context, filters *models.AuditLogF
 		filters = models.DefaultAuditLogFilters()
 	}
 
-	// Build WHERE conditions
-	var conditions []string
-	var args []interface{}
-	argIdx := 1
-
-	if filters.DataInicio != nil {
-		conditions = append(conditions, fmt.Sprintf("al.timestamp >= $%d", argIdx))
-		args = append(args, *filters.DataInicio)
-		argIdx++
-	}
-
-	if filters.DataFim != nil {
-		conditions = append(conditions, fmt.Sprintf("al.timestamp <= $%d", argIdx))
-		args = append(args, *filters.DataFim)
-		argIdx++
-	}
-
-	if filters.UsuarioID != nil {
-		conditions = append(conditions, fmt.Sprintf("al.usuario_id = $%d", argIdx))
-		args = append(args, *filters.UsuarioID)
-		argIdx++
-	}
-
-	if filters.Acao != nil && *filters.Acao != "" {
-		conditions = append(conditions, fmt.Sprintf("al.acao = $%d", argIdx))
-		args = append(args, *filters.Acao)
-		argIdx++
-	}
-
-	if filters.EntidadeTipo != nil && *filters.EntidadeTipo != "" {
-		conditions = append(conditions, fmt.Sprintf("al.entidade_tipo = $%d", argIdx))
-		args = append(args, *filters.EntidadeTipo)
-		argIdx++
-	}
-
-	if filters.EntidadeID != nil && *filters.EntidadeID != "" {
-		conditions = append(conditions, fmt.Sprintf("al.entidade_id = $%d", argIdx))
-		args = append(args, *filters.EntidadeID)
-		argIdx++
-	}
-
-	if filters.Severity != nil {
-		conditions = append(conditions, fmt.Sprintf("al.severity = $%d", argIdx))
-		args = append(args, *filters.Severity)
-		argIdx++
-	}
-
-	if filters.HospitalID != nil {
-		conditions = append(conditions, fmt.Sprintf("al.hospital_id = $%d", argIdx))
-		args = append(args, *filters.HospitalID)
-		argIdx++
-	}
+	conditions, args := auditLogConditions(filters)
+	argIdx := len(args) + 1
 
 	whereClause := ""
 	if len(conditions) > 0 {
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	// Count total items
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM audit_logs al %s`, whereClause)
-	var totalItems int
-	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalItems); err != nil {
-		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
-	}
 
 	// Calculate offset
 	offset := (filters.Page - 1) * filters.PageSize
@@ -162,61 +107,25 @@ func (r *AuditLogRepository) List(ctx context.Context, filters *models.AuditLogF
 		LIMIT $%d OFFSET $%d
 	`, whereClause, argIdx, argIdx+1)
 
-	args = append(args, filters.PageSize, offset)
-
-	rows, err := r.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query audit logs: %w", err)
-	}
-	defer rows.Close()
-
+	var totalItems int
 	var logs []models.AuditLog
-	for rows.Next() {
-		var log models.AuditLog
-		var usuarioID, hospitalID sql.NullString
-		var detalhes sql.NullString
-		var ipAddress, userAgent sql.NullString
-
-		err := rows.Scan(
-			&log.ID, &log.Timestamp, &usuarioID, &log.ActorName, &log.Acao,
-			&log.EntidadeTipo, &log.EntidadeID, &hospitalID, &log.Severity,
-			&detalhes, &ipAddress, &userAgent,
-		)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan audit log: %w", err)
-		}
-
-		if usuarioID.Valid {
-			uid, err := uuid.Parse(usuarioID.String)
-			if err == nil {
-				log.UsuarioID = &uid
-			}
-		}
-
-		if hospitalID.Valid {
-			hid, err := uuid.Parse(hospitalID.String)
-			if err == nil {
-				log.HospitalID = &hid
-			}
+	err := withStatementTimeout(ctx, r.db, func(q readQueryer) error {
+		// Count total items
+		if err := q.QueryRowContext(ctx, countQuery, args...).Scan(&totalItems); err != nil {
+			return fmt.Errorf("failed to count audit logs: %w", err)
 		}
 
-		if detalhes.Valid {
-			log.Detalhes = json.RawMessage(detalhes.String)
-		}
-
-		if ipAddress.Valid {
-			log.IPAddress = &ipAddress.String
-		}
-
-		if userAgent.Valid {
-			log.UserAgent = &userAgent.String
+		rows, err := q.QueryContext(ctx, query, append(args, filters.PageSize, offset)...)
+		if err != nil {
+			return fmt.Errorf("failed to query audit logs: %w", err)
 		}
+		defer rows.Close()
 
-		logs = append(logs, log)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("error iterating audit logs: %w", err)
+		logs, err = scanAuditLogs(rows)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 
 	return logs, totalItems, nil
@@ -234,70 +143,143 @@ func (r *AuditLogRepository) GetByEntityID(ctx context.Context, entidadeTipo str
 		ORDER BY al.timestamp ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, entidadeTipo, entidadeID)
+	var logs []models.AuditLog
+	err := withStatementTimeout(ctx, r.db, func(q readQueryer) error {
+		rows, err := q.QueryContext(ctx, query, entidadeTipo, entidadeID)
+		if err != nil {
+			return fmt.Errorf("failed to query audit logs by entity: %w", err)
+		}
+		defer rows.Close()
+
+		logs, err = scanAuditLogs(rows)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to query audit logs by entity: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	var logs []models.AuditLog
-	for rows.Next() {
-		var log models.AuditLog
-		var usuarioID, hospitalID sql.NullString
-		var detalhes sql.NullString
-		var ipAddress, userAgent sql.NullString
+	return logs, nil
+}
 
-		err := rows.Scan(
-			&log.ID, &log.Timestamp, &usuarioID, &log.ActorName, &log.Acao,
-			&log.EntidadeTipo, &log.EntidadeID, &hospitalID, &log.Severity,
-			&detalhes, &ipAddress, &userAgent,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan audit log: %w", err)
-		}
+// ListWithHospitalNames retrieves audit logs with hospital names for display
+func (r *AuditLogRepository) ListWithHospitalNames(ctx context.Context, filters *models.AuditLogFilter) ([]models.AuditLogResponse, int, error) {
+	if filters == nil {
+		filters = models.DefaultAuditLogFilters()
+	}
 
-		if usuarioID.Valid {
-			uid, err := uuid.Parse(usuarioID.String)
-			if err == nil {
-				log.UsuarioID = &uid
-			}
-		}
+	conditions, args := auditLogConditions(filters)
+	argIdx := len(args) + 1
 
-		if hospitalID.Valid {
-			hid, err := uuid.Parse(hospitalID.String)
-			if err == nil {
-				log.HospitalID = &hid
-			}
-		}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
 
-		if detalhes.Valid {
-			log.Detalhes = json.RawMessage(detalhes.String)
-		}
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM audit_logs al %s`, whereClause)
 
-		if ipAddress.Valid {
-			log.IPAddress = &ipAddress.String
-		}
+	// Calculate offset
+	offset := (filters.Page - 1) * filters.PageSize
 
-		if userAgent.Valid {
-			log.UserAgent = &userAgent.String
+	// Main query with hospital join and pagination
+	query := fmt.Sprintf(`
+		%s
+		%s
+		ORDER BY al.timestamp DESC, al.id DESC
+		LIMIT $%d OFFSET $%d
+	`, auditLogWithHospitalSelect, whereClause, argIdx, argIdx+1)
+
+	var totalItems int
+	var logs []models.AuditLogResponse
+	err := withStatementTimeout(ctx, r.db, func(q readQueryer) error {
+		// Count total items
+		if err := q.QueryRowContext(ctx, countQuery, args...).Scan(&totalItems); err != nil {
+			return fmt.Errorf("failed to count audit logs: %w", err)
 		}
 
-		logs = append(logs, log)
-	}
+		rows, err := q.QueryContext(ctx, query, append(args, filters.PageSize, offset)...)
+		if err != nil {
+			return fmt.Errorf("failed to query audit logs: %w", err)
+		}
+		defer rows.Close()
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating audit logs: %w", err)
+		logs, err = scanAuditLogResponses(rows)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return logs, nil
+	return logs, totalItems, nil
 }
 
-// ListWithHospitalNames retrieves audit logs with hospital names for display
-func (r *AuditLogRepository) ListWithHospitalNames(ctx context.Context, filters *models.AuditLogFilter) ([]models.AuditLogResponse, int, error) {
+// ListWithHospitalNamesAfterCursor retrieves a page of audit logs using keyset pagination
+// Entries strictly after filters.Cursor (or from the newest entry when nil) are returned, ordered by
+// (timestamp DESC, id DESC). The returned cursor points at the last entry and is nil on the last page.
+func (r *AuditLogRepository) ListWithHospitalNamesAfterCursor(ctx context.Context, filters *models.AuditLogFilter) ([]models.AuditLogResponse, *models.AuditLogCursor, error) {
 	if filters == nil {
 		filters = models.DefaultAuditLogFilters()
 	}
 
+	conditions, args := auditLogConditions(filters)
+	argIdx := len(args) + 1
+
+	if filters.Cursor != nil {
+		conditions = append(conditions, fmt.Sprintf("(al.timestamp, al.id) < ($%d, $%d)", argIdx, argIdx+1))
+		args = append(args, filters.Cursor.Timestamp, filters.Cursor.ID)
+		argIdx += 2
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	query := fmt.Sprintf(`
+		%s
+		%s
+		ORDER BY al.timestamp DESC, al.id DESC
+		LIMIT $%d
+	`, auditLogWithHospitalSelect, whereClause, argIdx)
+
+	args = append(args, filters.PageSize+1)
+
+	var logs []models.AuditLogResponse
+	err := withStatementTimeout(ctx, r.db, func(q readQueryer) error {
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to query audit logs: %w", err)
+		}
+		defer rows.Close()
+
+		logs, err = scanAuditLogResponses(rows)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(logs) <= filters.PageSize {
+		return logs, nil, nil
+	}
+
+	logs = logs[:filters.PageSize]
+	last := logs[len(logs)-1]
+	return logs, &models.AuditLogCursor{Timestamp: last.Timestamp, ID: last.ID}, nil
+}
+
+// auditLogWithHospitalSelect selects audit log columns joined with the hospital name
+const auditLogWithHospitalSelect = `
+		SELECT
+			al.id, al.timestamp, al.usuario_id, al.actor_name, al.acao,
+			al.entidade_tipo, al.entidade_id, al.hospital_id, al.severity,
+			al.detalhes, al.ip_address, al.user_agent,
+			h.nome as hospital_nome
+		FROM audit_logs al
+		LEFT JOIN hospitals h ON al.hospital_id = h.id`
+
+// auditLogConditions builds the WHERE conditions and arguments for audit log filters
+func auditLogConditions(filters *models.AuditLogFilter) ([]string, []interface{}) {
 	// Build WHERE conditions
 	var conditions []string
 	var args []interface{}
@@ -321,6 +303,12 @@ func (r *AuditLogRepository) ListWithHospitalNames(ctx context.Context, filters
 		argIdx++
 	}
 
+	if filters.ActorName != nil && *filters.ActorName != "" {
+		conditions = append(conditions, fmt.Sprintf("al.actor_name ILIKE $%d", argIdx))
+		args = append(args, "%"+EscapeLikePattern(*filters.ActorName)+"%")
+		argIdx++
+	}
+
 	if filters.Acao != nil && *filters.Acao != "" {
 		conditions = append(conditions, fmt.Sprintf("al.acao = $%d", argIdx))
 		args = append(args, *filters.Acao)
@@ -351,43 +339,71 @@ func (r *AuditLogRepository) ListWithHospitalNames(ctx context.Context, filters
 		argIdx++
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	if filters.IPAddress != nil && *filters.IPAddress != "" {
+		conditions = append(conditions, fmt.Sprintf("al.ip_address = $%d", argIdx))
+		args = append(args, *filters.IPAddress)
+		argIdx++
 	}
 
-	// Count total items
-	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM audit_logs al %s`, whereClause)
-	var totalItems int
-	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalItems); err != nil {
-		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
-	}
+	return conditions, args
+}
 
-	// Calculate offset
-	offset := (filters.Page - 1) * filters.PageSize
+// scanAuditLogs scans rows of audit log columns
+func scanAuditLogs(rows *sql.Rows) ([]models.AuditLog, error) {
+	var logs []models.AuditLog
+	for rows.Next() {
+		var log models.AuditLog
+		var usuarioID, hospitalID sql.NullString
+		var detalhes sql.NullString
+		var ipAddress, userAgent sql.NullString
 
-	// Main query with hospital join and pagination
-	query := fmt.Sprintf(`
-		SELECT
-			al.id, al.timestamp, al.usuario_id, al.actor_name, al.acao,
-			al.entidade_tipo, al.entidade_id, al.hospital_id, al.severity,
-			al.detalhes, al.ip_address, al.user_agent,
-			h.nome as hospital_nome
-		FROM audit_logs al
-		LEFT JOIN hospitals h ON al.hospital_id = h.id
-		%s
-		ORDER BY al.timestamp DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIdx, argIdx+1)
+		err := rows.Scan(
+			&log.ID, &log.Timestamp, &usuarioID, &log.ActorName, &log.Acao,
+			&log.EntidadeTipo, &log.EntidadeID, &hospitalID, &log.Severity,
+			&detalhes, &ipAddress, &userAgent,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+
+		if usuarioID.Valid {
+			uid, err := uuid.Parse(usuarioID.String)
+			if err == nil {
+				log.UsuarioID = &uid
+			}
+		}
+
+		if hospitalID.Valid {
+			hid, err := uuid.Parse(hospitalID.String)
+			if err == nil {
+				log.HospitalID = &hid
+			}
+		}
 
-	args = append(args, filters.PageSize, offset)
+		if detalhes.Valid {
+			log.Detalhes = json.RawMessage(detalhes.String)
+		}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query audit logs: %w", err)
+		if ipAddress.Valid {
+			log.IPAddress = &ipAddress.String
+		}
+
+		if userAgent.Valid {
+			log.UserAgent = &userAgent.String
+		}
+
+		logs = append(logs, log)
 	}
-	defer rows.Close()
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit logs: %w", err)
+	}
+
+	return logs, nil
+}
+
+// scanAuditLogResponses scans rows selected with auditLogWithHospitalSelect
+func scanAuditLogResponses(rows *sql.Rows) ([]models.AuditLogResponse, error) {
 	var logs []models.AuditLogResponse
 	for rows.Next() {
 		var log models.AuditLogResponse
@@ -401,7 +417,7 @@ func (r *AuditLogRepository) ListWithHospitalNames(ctx context.Context, filters
 			&detalhes, &ipAddress, &userAgent, &hospitalNome,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan audit log: %w", err)
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
 		}
 
 		if usuarioID.Valid {
@@ -438,8 +454,8 @@ func (r *AuditLogRepository) ListWithHospitalNames(ctx context.Context, filters
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("error iterating audit logs: %w", err)
+		return nil, fmt.Errorf("error iterating audit logs: %w", err)
 	}
 
-	return logs, totalItems, nil
+	return logs, nil
 }
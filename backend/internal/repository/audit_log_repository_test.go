@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// TestAuditLogConditionsNumbersArgs verifies that every filter becomes one AND-ed condition with its own placeholder
+func TestAuditLogConditionsNumbersArgs(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	userID := uuid.New()
+	actor, acao, tipo, ip := "ana", "update", "Hospital", "10.0.0.1"
+	severity := models.SeverityWarn
+
+	conditions, args := auditLogConditions(&models.AuditLogFilter{
+		DataInicio:   &from,
+		DataFim:      &to,
+		UsuarioID:    &userID,
+		ActorName:    &actor,
+		Acao:         &acao,
+		EntidadeTipo: &tipo,
+		Severity:     &severity,
+		IPAddress:    &ip,
+	})
+
+	expected := []string{
+		"al.timestamp >= $1",
+		"al.timestamp <= $2",
+		"al.usuario_id = $3",
+		"al.actor_name ILIKE $4",
+		"al.acao = $5",
+		"al.entidade_tipo = $6",
+		"al.severity = $7",
+		"al.ip_address = $8",
+	}
+	if strings.Join(conditions, " AND ") != strings.Join(expected, " AND ") {
+		t.Fatalf("Expected conditions %v, got %v", expected, conditions)
+	}
+	if len(args) != len(expected) {
+		t.Fatalf("Expected %d args, got %d", len(expected), len(args))
+	}
+	if args[3] != "%ana%" || args[7] != ip {
+		t.Errorf("Unexpected actor or IP args: %v, %v", args[3], args[7])
+	}
+}
+
+// TestAuditLogConditionsEscapesActorName verifies that the actor name cannot inject LIKE wildcards
+func TestAuditLogConditionsEscapesActorName(t *testing.T) {
+	actor := "50%_a"
+	_, args := auditLogConditions(&models.AuditLogFilter{ActorName: &actor})
+
+	if len(args) != 1 || args[0] != `%50\%\_a%` {
+		t.Errorf("Expected an escaped contains pattern, got %v", args)
+	}
+}
+
+// TestAuditLogConditionsSkipsEmptyFilters verifies that empty strings do not filter
+func TestAuditLogConditionsSkipsEmptyFilters(t *testing.T) {
+	empty := ""
+	conditions, args := auditLogConditions(&models.AuditLogFilter{ActorName: &empty, IPAddress: &empty, Acao: &empty})
+
+	if len(conditions) != 0 || len(args) != 0 {
+		t.Errorf("Expected no conditions, got %v", conditions)
+	}
+}
+
+// insertTestAuditLog inserts an audit log entry with an explicit timestamp
+func insertTestAuditLog(t *testing.T, db *sql.DB, entry models.AuditLog) uuid.UUID {
+	t.Helper()
+
+	id := uuid.New()
+	_, err := db.Exec(`
+		INSERT INTO audit_logs (id, timestamp, actor_name, acao, entidade_tipo, entidade_id, severity, ip_address)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, id, entry.Timestamp, entry.ActorName, entry.Acao, entry.EntidadeTipo, entry.EntidadeID, entry.Severity, entry.IPAddress)
+	if err != nil {
+		t.Fatalf("Failed to insert audit log: %v", err)
+	}
+	return id
+}
+
+// deleteTestAuditLogs removes the entries of an entity through the archival function, the only way past the WORM trigger
+func deleteTestAuditLogs(db *sql.DB, entidadeID string) {
+	db.Exec(`SELECT archive_audit_logs(ARRAY(SELECT id FROM audit_logs WHERE entidade_id = $1), now())`, entidadeID)
+}
+
+// TestAuditLogListFilters verifies that each filter narrows the results and that combined filters are AND-ed
+func TestAuditLogListFilters(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewAuditLogRepository(db)
+
+	// Every entry shares a unique entity id, which scopes the queries to this test
+	entidadeID := "audit-filter-" + uuid.NewString()
+	t.Cleanup(func() { deleteTestAuditLogs(db, entidadeID) })
+
+	base := time.Now().UTC().Truncate(time.Second)
+	ip1, ip2 := "10.20.30.1", "10.20.30.2"
+	anaCreate := insertTestAuditLog(t, db, models.AuditLog{
+		Timestamp: base.Add(-72 * time.Hour), ActorName: "Ana Souza", Acao: "occurrence.create",
+		EntidadeTipo: "Ocorrencia", EntidadeID: entidadeID, Severity: models.SeverityInfo, IPAddress: &ip1,
+	})
+	brunoUpdate := insertTestAuditLog(t, db, models.AuditLog{
+		Timestamp: base.Add(-48 * time.Hour), ActorName: "Bruno Lima", Acao: "occurrence.update",
+		EntidadeTipo: "Ocorrencia", EntidadeID: entidadeID, Severity: models.SeverityWarn, IPAddress: &ip2,
+	})
+	anaDelete := insertTestAuditLog(t, db, models.AuditLog{
+		Timestamp: base.Add(-24 * time.Hour), ActorName: "Ana Souza", Acao: "hospital.delete",
+		EntidadeTipo: "Hospital", EntidadeID: entidadeID, Severity: models.SeverityCritical, IPAddress: &ip2,
+	})
+
+	str := func(value string) *string { return &value }
+	severity := func(value models.Severity) *models.Severity { return &value }
+	at := func(value time.Time) *time.Time { return &value }
+
+	tests := []struct {
+		name     string
+		filter   models.AuditLogFilter
+		expected []uuid.UUID
+	}{
+		{"no extra filter", models.AuditLogFilter{}, []uuid.UUID{anaDelete, brunoUpdate, anaCreate}},
+		{"actor name, case-insensitive partial", models.AuditLogFilter{ActorName: str("ana sou")}, []uuid.UUID{anaDelete, anaCreate}},
+		{"entity type", models.AuditLogFilter{EntidadeTipo: str("Hospital")}, []uuid.UUID{anaDelete}},
+		{"action", models.AuditLogFilter{Acao: str("occurrence.update")}, []uuid.UUID{brunoUpdate}},
+		{"severity", models.AuditLogFilter{Severity: severity(models.SeverityInfo)}, []uuid.UUID{anaCreate}},
+		{"IP address", models.AuditLogFilter{IPAddress: str(ip2)}, []uuid.UUID{anaDelete, brunoUpdate}},
+		{"date range", models.AuditLogFilter{DataInicio: at(base.Add(-60 * time.Hour)), DataFim: at(base.Add(-36 * time.Hour))}, []uuid.UUID{brunoUpdate}},
+		{"actor and IP address", models.AuditLogFilter{ActorName: str("Ana"), IPAddress: str(ip2)}, []uuid.UUID{anaDelete}},
+		{"entity type and action with no match", models.AuditLogFilter{EntidadeTipo: str("Hospital"), Acao: str("occurrence.create")}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			filter.EntidadeID = &entidadeID
+			filter.Page = 1
+			filter.PageSize = 50
+
+			logs, total, err := repo.ListWithHospitalNames(context.Background(), &filter)
+			if err != nil {
+				t.Fatalf("Failed to list audit logs: %v", err)
+			}
+			if total != len(tt.expected) || len(logs) != len(tt.expected) {
+				t.Fatalf("Expected %d entries, got %d (total %d)", len(tt.expected), len(logs), total)
+			}
+			for i, id := range tt.expected {
+				if logs[i].ID != id {
+					t.Errorf("Expected entry %d to be %s, got %s", i, id, logs[i].ID)
+				}
+			}
+		})
+	}
+}
+
+// TestAuditLogDeleteOnlyThroughArchiverFunction verifies that neither the old session setting nor the archiver
+// role lifts the WORM trigger and that the archival function only deletes the given entries older than its cutoff
+func TestAuditLogDeleteOnlyThroughArchiverFunction(t *testing.T) {
+	db := openTestDB(t)
+
+	entidadeID := "audit-worm-" + uuid.NewString()
+	t.Cleanup(func() { deleteTestAuditLogs(db, entidadeID) })
+	id := insertTestAuditLog(t, db, models.AuditLog{
+		Timestamp:    time.Now().UTC(),
+		ActorName:    "Ana",
+		Acao:         "user.create",
+		EntidadeTipo: "User",
+		EntidadeID:   entidadeID,
+		Severity:     models.SeverityInfo,
+	})
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	if _, err := tx.Exec(`SET LOCAL sidot.audit_archive = 'on'`); err != nil {
+		t.Fatalf("Failed to set session setting: %v", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM audit_logs WHERE id = $1`, id); err == nil {
+		t.Error("Expected a direct delete to be refused by the WORM trigger")
+	}
+	tx.Rollback()
+
+	// The migration role is no longer a member of the archiver role (a superuser can still switch to any role)
+	var superuser bool
+	if err := db.QueryRow(`SELECT rolsuper FROM pg_roles WHERE rolname = current_user`).Scan(&superuser); err != nil {
+		t.Fatalf("Failed to read role attributes: %v", err)
+	}
+	if !superuser {
+		tx, err = db.Begin()
+		if err != nil {
+			t.Fatalf("Failed to begin transaction: %v", err)
+		}
+		if _, err := tx.Exec(`SET LOCAL ROLE sidot_audit_archiver`); err == nil {
+			t.Error("Expected switching to the archiver role to be refused")
+		}
+		tx.Rollback()
+	}
+
+	// Entries newer than the cutoff are kept, and a cutoff in the future is refused
+	var deleted int64
+	if err := db.QueryRow(`SELECT archive_audit_logs(ARRAY[$1::uuid], now() - interval '1 day')`, id).Scan(&deleted); err != nil {
+		t.Fatalf("archive_audit_logs returned error: %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("Expected the entry newer than the cutoff to be kept, got %d deleted", deleted)
+	}
+	if _, err := db.Exec(`SELECT archive_audit_logs(ARRAY[$1::uuid], now() + interval '1 day')`, id); err == nil {
+		t.Error("Expected a cutoff in the future to be refused")
+	}
+
+	if err := db.QueryRow(`SELECT archive_audit_logs(ARRAY[$1::uuid], now())`, id).Scan(&deleted); err != nil {
+		t.Fatalf("archive_audit_logs returned error: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 entry deleted by archive_audit_logs, got %d", deleted)
+	}
+}
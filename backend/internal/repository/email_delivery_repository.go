@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sidot/backend/internal/models"
+)
+
+// EmailDeliveryRepository handles the delivery records of queued emails
+type EmailDeliveryRepository struct {
+	db *sql.DB
+}
+
+// NewEmailDeliveryRepository creates a new email delivery repository
+func NewEmailDeliveryRepository(db *sql.DB) *EmailDeliveryRepository {
+	return &EmailDeliveryRepository{db: db}
+}
+
+// Save creates the delivery record or updates its status and attempt details
+func (r *EmailDeliveryRepository) Save(ctx context.Context, delivery *models.EmailDelivery) error {
+	query := `
+		INSERT INTO email_deliveries (id, occurrence_id, user_id, recipient, status, attempts, last_error, last_attempt_at, next_retry_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			attempts = EXCLUDED.attempts,
+			last_error = EXCLUDED.last_error,
+			last_attempt_at = EXCLUDED.last_attempt_at,
+			next_retry_at = EXCLUDED.next_retry_at,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.ID,
+		delivery.OccurrenceID,
+		delivery.UserID,
+		delivery.Recipient,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.LastError,
+		delivery.LastAttemptAt,
+		delivery.NextRetryAt,
+	)
+	return err
+}
+
+// List returns delivery records, most recently updated first, with the total matching the filters
+func (r *EmailDeliveryRepository) List(ctx context.Context, filters models.EmailDeliveryFilters) ([]models.EmailDelivery, int, error) {
+	where := "WHERE 1=1"
+	args := []interface{}{}
+	argIndex := 1
+
+	if filters.Status != nil {
+		where += fmt.Sprintf(" AND status = $%d", argIndex)
+		args = append(args, *filters.Status)
+		argIndex++
+	}
+
+	if filters.OccurrenceID != nil {
+		where += fmt.Sprintf(" AND occurrence_id = $%d", argIndex)
+		args = append(args, *filters.OccurrenceID)
+		argIndex++
+	}
+
+	if filters.Recipient != "" {
+		where += fmt.Sprintf(" AND LOWER(recipient) = LOWER($%d)", argIndex)
+		args = append(args, filters.Recipient)
+		argIndex++
+	}
+
+	var totalItems int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM email_deliveries "+where, args...).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filters.Page - 1) * filters.PageSize
+	query := fmt.Sprintf(`
+		SELECT id, occurrence_id, user_id, recipient, status, attempts, last_error, last_attempt_at, next_retry_at, created_at, updated_at
+		FROM email_deliveries
+		%s
+		ORDER BY updated_at DESC, id
+		LIMIT %d OFFSET %d
+	`, where, filters.PageSize, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.EmailDelivery{}
+	for rows.Next() {
+		var d models.EmailDelivery
+		if err := rows.Scan(
+			&d.ID, &d.OccurrenceID, &d.UserID, &d.Recipient, &d.Status, &d.Attempts,
+			&d.LastError, &d.LastAttemptAt, &d.NextRetryAt, &d.CreatedAt, &d.UpdatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, totalItems, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// outboxExecer is satisfied by *sql.DB and *sql.Tx, so events can be written inside the caller's transaction
+type outboxExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// EventsOutboxRepository handles events_outbox data access
+type EventsOutboxRepository struct {
+	db *sql.DB
+}
+
+// NewEventsOutboxRepository creates a new events outbox repository
+func NewEventsOutboxRepository(db *sql.DB) *EventsOutboxRepository {
+	return &EventsOutboxRepository{db: db}
+}
+
+// insertOutboxEvent writes an event with the given executor; pass the transaction of the change the event describes
+func insertOutboxEvent(ctx context.Context, exec outboxExecer, event *models.OutboxEvent) error {
+	query := `
+		INSERT INTO events_outbox (id, event_type, aggregate_id, payload, available_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := exec.ExecContext(ctx, query,
+		event.ID,
+		event.EventType,
+		event.AggregateID,
+		string(event.Payload),
+		event.AvailableAt,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox event: %w", err)
+	}
+	return nil
+}
+
+// ClaimPending leases up to limit due events for delivery and returns them, oldest first.
+// Claimed events become available again when the lease expires, so an event whose relay crashed
+// before MarkSent is delivered again (at-least-once). SKIP LOCKED lets several instances relay concurrently.
+func (r *EventsOutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	query := `
+		UPDATE events_outbox
+		SET available_at = NOW() + make_interval(secs => $2), attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM events_outbox
+			WHERE sent_at IS NULL AND dead_at IS NULL AND available_at <= NOW()
+			ORDER BY created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, aggregate_id, payload, attempts, last_error, available_at, created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var e models.OutboxEvent
+		var payload string
+		var lastError sql.NullString
+
+		if err := rows.Scan(&e.ID, &e.EventType, &e.AggregateID, &payload, &e.Attempts, &lastError, &e.AvailableAt, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		e.Payload = []byte(payload)
+		if lastError.Valid {
+			e.LastError = &lastError.String
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not preserve the subquery order
+	sort.Slice(events, func(i, j int) bool { return events[i].CreatedAt.Before(events[j].CreatedAt) })
+	return events, nil
+}
+
+// MarkSent records the delivery of an event
+func (r *EventsOutboxRepository) MarkSent(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE events_outbox SET sent_at = NOW(), last_error = NULL WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event as sent: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery and schedules the next attempt
+func (r *EventsOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, deliveryErr string, retryAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE events_outbox SET last_error = $2, available_at = $3 WHERE id = $1 AND sent_at IS NULL`, id, deliveryErr, retryAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event as failed: %w", err)
+	}
+	return nil
+}
+
+// MarkDead moves an event that exhausted its attempts to dead-letter, so the relay no longer claims it
+func (r *EventsOutboxRepository) MarkDead(ctx context.Context, id uuid.UUID, deliveryErr string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE events_outbox SET last_error = $2, dead_at = NOW() WHERE id = $1 AND sent_at IS NULL`, id, deliveryErr)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event as dead: %w", err)
+	}
+	return nil
+}
+
+// DeleteSentBefore removes events sent before the cutoff, returning how many were deleted
+func (r *EventsOutboxRepository) DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM events_outbox WHERE sent_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sent outbox events: %w", err)
+	}
+	return result.RowsAffected()
+}
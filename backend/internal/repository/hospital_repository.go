@@ -31,7 +31,7 @@ func (r *HospitalRepository) List(ctx context.Context) ([]models.Hospital, error
 	tf := NewTenantFilter(ctx)
 
 	query := `
-		SELECT id, nome, codigo, endereco, telefone, latitude, longitude, config_conexao, ativo, created_at, updated_at, deleted_at
+		SELECT id, nome, codigo, endereco, telefone, email, escalation_email, latitude, longitude, config_conexao, timezone, ativo, created_at, updated_at, deleted_at
 		FROM hospitals
 		WHERE deleted_at IS NULL` + tf.AndClause() + `
 		ORDER BY nome ASC
@@ -46,7 +46,7 @@ func (r *HospitalRepository) List(ctx context.Context) ([]models.Hospital, error
 	var hospitals []models.Hospital
 	for rows.Next() {
 		var h models.Hospital
-		var endereco, telefone, configConexao sql.NullString
+		var endereco, telefone, email, escalationEmail, configConexao, timezone sql.NullString
 		var latitude, longitude sql.NullFloat64
 		var deletedAt sql.NullTime
 
@@ -56,9 +56,12 @@ func (r *HospitalRepository) List(ctx context.Context) ([]models.Hospital, error
 			&h.Codigo,
 			&endereco,
 			&telefone,
+			&email,
+			&escalationEmail,
 			&latitude,
 			&longitude,
 			&configConexao,
+			&timezone,
 			&h.Ativo,
 			&h.CreatedAt,
 			&h.UpdatedAt,
@@ -74,6 +77,12 @@ func (r *HospitalRepository) List(ctx context.Context) ([]models.Hospital, error
 		if telefone.Valid {
 			h.Telefone = &telefone.String
 		}
+		if email.Valid {
+			h.Email = &email.String
+		}
+		if escalationEmail.Valid {
+			h.EscalationEmail = &escalationEmail.String
+		}
 		if latitude.Valid {
 			h.Latitude = &latitude.Float64
 		}
@@ -83,6 +92,9 @@ func (r *HospitalRepository) List(ctx context.Context) ([]models.Hospital, error
 		if configConexao.Valid {
 			h.ConfigConexao = json.RawMessage(configConexao.String)
 		}
+		if timezone.Valid {
+			h.Timezone = &timezone.String
+		}
 		if deletedAt.Valid {
 			h.DeletedAt = &deletedAt.Time
 		}
@@ -102,25 +114,29 @@ func (r *HospitalRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 	tf := NewTenantFilter(ctx)
 
 	query := `
-		SELECT id, nome, codigo, endereco, telefone, latitude, longitude, config_conexao, ativo, created_at, updated_at, deleted_at
+		SELECT id, tenant_id, nome, codigo, endereco, telefone, email, escalation_email, latitude, longitude, config_conexao, timezone, ativo, created_at, updated_at, deleted_at
 		FROM hospitals
 		WHERE id = $1 AND deleted_at IS NULL` + tf.AndClause() + `
 	`
 
 	var h models.Hospital
-	var endereco, telefone, configConexao sql.NullString
+	var endereco, telefone, email, escalationEmail, configConexao, timezone sql.NullString
 	var latitude, longitude sql.NullFloat64
 	var deletedAt sql.NullTime
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&h.ID,
+		&h.TenantID,
 		&h.Nome,
 		&h.Codigo,
 		&endereco,
 		&telefone,
+		&email,
+		&escalationEmail,
 		&latitude,
 		&longitude,
 		&configConexao,
+		&timezone,
 		&h.Ativo,
 		&h.CreatedAt,
 		&h.UpdatedAt,
@@ -140,6 +156,12 @@ func (r *HospitalRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 	if telefone.Valid {
 		h.Telefone = &telefone.String
 	}
+	if email.Valid {
+		h.Email = &email.String
+	}
+	if escalationEmail.Valid {
+		h.EscalationEmail = &escalationEmail.String
+	}
 	if latitude.Valid {
 		h.Latitude = &latitude.Float64
 	}
@@ -149,6 +171,9 @@ func (r *HospitalRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 	if configConexao.Valid {
 		h.ConfigConexao = json.RawMessage(configConexao.String)
 	}
+	if timezone.Valid {
+		h.Timezone = &timezone.String
+	}
 	if deletedAt.Valid {
 		h.DeletedAt = &deletedAt.Time
 	}
@@ -161,13 +186,13 @@ func (r *HospitalRepository) GetByCodigo(ctx context.Context, codigo string) (*m
 	tf := NewTenantFilter(ctx)
 
 	query := `
-		SELECT id, nome, codigo, endereco, telefone, latitude, longitude, config_conexao, ativo, created_at, updated_at, deleted_at
+		SELECT id, nome, codigo, endereco, telefone, email, escalation_email, latitude, longitude, config_conexao, timezone, ativo, created_at, updated_at, deleted_at
 		FROM hospitals
 		WHERE codigo = $1 AND deleted_at IS NULL` + tf.AndClause() + `
 	`
 
 	var h models.Hospital
-	var endereco, telefone, configConexao sql.NullString
+	var endereco, telefone, email, escalationEmail, configConexao, timezone sql.NullString
 	var latitude, longitude sql.NullFloat64
 	var deletedAt sql.NullTime
 
@@ -177,9 +202,12 @@ func (r *HospitalRepository) GetByCodigo(ctx context.Context, codigo string) (*m
 		&h.Codigo,
 		&endereco,
 		&telefone,
+		&email,
+		&escalationEmail,
 		&latitude,
 		&longitude,
 		&configConexao,
+		&timezone,
 		&h.Ativo,
 		&h.CreatedAt,
 		&h.UpdatedAt,
@@ -199,6 +227,12 @@ func (r *HospitalRepository) GetByCodigo(ctx context.Context, codigo string) (*m
 	if telefone.Valid {
 		h.Telefone = &telefone.String
 	}
+	if email.Valid {
+		h.Email = &email.String
+	}
+	if escalationEmail.Valid {
+		h.EscalationEmail = &escalationEmail.String
+	}
 	if latitude.Valid {
 		h.Latitude = &latitude.Float64
 	}
@@ -208,6 +242,9 @@ func (r *HospitalRepository) GetByCodigo(ctx context.Context, codigo string) (*m
 	if configConexao.Valid {
 		h.ConfigConexao = json.RawMessage(configConexao.String)
 	}
+	if timezone.Valid {
+		h.Timezone = &timezone.String
+	}
 	if deletedAt.Valid {
 		h.DeletedAt = &deletedAt.Time
 	}
@@ -260,13 +297,23 @@ func (r *HospitalRepository) Create(ctx context.Context, input *models.CreateHos
 		UpdatedAt: time.Now(),
 	}
 
+	if input.Email != nil && *input.Email != "" {
+		hospital.Email = input.Email
+	}
+	if input.EscalationEmail != nil && *input.EscalationEmail != "" {
+		hospital.EscalationEmail = input.EscalationEmail
+	}
+
 	if input.ConfigConexao != nil {
 		hospital.ConfigConexao = input.ConfigConexao
 	}
+	if input.Timezone != nil && *input.Timezone != "" {
+		hospital.Timezone = input.Timezone
+	}
 
 	query := `
-		INSERT INTO hospitals (id, tenant_id, nome, codigo, endereco, telefone, latitude, longitude, config_conexao, ativo, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO hospitals (id, tenant_id, nome, codigo, endereco, telefone, email, escalation_email, latitude, longitude, config_conexao, timezone, ativo, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
 	var configJSON interface{}
@@ -281,9 +328,12 @@ func (r *HospitalRepository) Create(ctx context.Context, input *models.CreateHos
 		hospital.Codigo,
 		hospital.Endereco,
 		hospital.Telefone,
+		hospital.Email,
+		hospital.EscalationEmail,
 		hospital.Latitude,
 		hospital.Longitude,
 		configJSON,
+		hospital.Timezone,
 		hospital.Ativo,
 		hospital.CreatedAt,
 		hospital.UpdatedAt,
@@ -327,6 +377,19 @@ func (r *HospitalRepository) Update(ctx context.Context, id uuid.UUID, input *mo
 	if input.Telefone != nil {
 		hospital.Telefone = input.Telefone
 	}
+	if input.Email != nil {
+		// An empty email removes the contact
+		hospital.Email = input.Email
+		if *input.Email == "" {
+			hospital.Email = nil
+		}
+	}
+	if input.EscalationEmail != nil {
+		hospital.EscalationEmail = input.EscalationEmail
+		if *input.EscalationEmail == "" {
+			hospital.EscalationEmail = nil
+		}
+	}
 	if input.Latitude != nil {
 		hospital.Latitude = input.Latitude
 	}
@@ -336,6 +399,13 @@ func (r *HospitalRepository) Update(ctx context.Context, id uuid.UUID, input *mo
 	if input.ConfigConexao != nil {
 		hospital.ConfigConexao = input.ConfigConexao
 	}
+	if input.Timezone != nil {
+		// An empty timezone resets the hospital to the system timezone
+		hospital.Timezone = input.Timezone
+		if *input.Timezone == "" {
+			hospital.Timezone = nil
+		}
+	}
 	if input.Ativo != nil {
 		hospital.Ativo = *input.Ativo
 	}
@@ -344,8 +414,9 @@ func (r *HospitalRepository) Update(ctx context.Context, id uuid.UUID, input *mo
 
 	query := `
 		UPDATE hospitals
-		SET nome = $1, codigo = $2, endereco = $3, telefone = $4, latitude = $5, longitude = $6, config_conexao = $7, ativo = $8, updated_at = $9
-		WHERE id = $10 AND deleted_at IS NULL` + tf.AndClause() + `
+		SET nome = $1, codigo = $2, endereco = $3, telefone = $4, email = $5, escalation_email = $6, latitude = $7, longitude = $8,
+		    config_conexao = $9, timezone = $10, ativo = $11, updated_at = $12
+		WHERE id = $13 AND deleted_at IS NULL` + tf.AndClause() + `
 	`
 
 	var configJSON interface{}
@@ -358,9 +429,12 @@ func (r *HospitalRepository) Update(ctx context.Context, id uuid.UUID, input *mo
 		hospital.Codigo,
 		hospital.Endereco,
 		hospital.Telefone,
+		hospital.Email,
+		hospital.EscalationEmail,
 		hospital.Latitude,
 		hospital.Longitude,
 		configJSON,
+		hospital.Timezone,
 		hospital.Ativo,
 		hospital.UpdatedAt,
 		id,
@@ -427,7 +501,7 @@ func (r *HospitalRepository) GetActiveHospitals(ctx context.Context) ([]models.H
 	tf := NewTenantFilter(ctx)
 
 	query := `
-		SELECT id, nome, codigo, endereco, telefone, latitude, longitude, config_conexao, ativo, created_at, updated_at
+		SELECT id, nome, codigo, endereco, telefone, email, escalation_email, latitude, longitude, config_conexao, timezone, ativo, created_at, updated_at
 		FROM hospitals
 		WHERE deleted_at IS NULL AND ativo = true` + tf.AndClause() + `
 		ORDER BY nome ASC
@@ -442,7 +516,7 @@ func (r *HospitalRepository) GetActiveHospitals(ctx context.Context) ([]models.H
 	var hospitals []models.Hospital
 	for rows.Next() {
 		var h models.Hospital
-		var endereco, telefone, configConexao sql.NullString
+		var endereco, telefone, email, escalationEmail, configConexao, timezone sql.NullString
 		var latitude, longitude sql.NullFloat64
 
 		err := rows.Scan(
@@ -451,9 +525,12 @@ func (r *HospitalRepository) GetActiveHospitals(ctx context.Context) ([]models.H
 			&h.Codigo,
 			&endereco,
 			&telefone,
+			&email,
+			&escalationEmail,
 			&latitude,
 			&longitude,
 			&configConexao,
+			&timezone,
 			&h.Ativo,
 			&h.CreatedAt,
 			&h.UpdatedAt,
@@ -468,6 +545,12 @@ func (r *HospitalRepository) GetActiveHospitals(ctx context.Context) ([]models.H
 		if telefone.Valid {
 			h.Telefone = &telefone.String
 		}
+		if email.Valid {
+			h.Email = &email.String
+		}
+		if escalationEmail.Valid {
+			h.EscalationEmail = &escalationEmail.String
+		}
 		if latitude.Valid {
 			h.Latitude = &latitude.Float64
 		}
@@ -477,6 +560,9 @@ func (r *HospitalRepository) GetActiveHospitals(ctx context.Context) ([]models.H
 		if configConexao.Valid {
 			h.ConfigConexao = json.RawMessage(configConexao.String)
 		}
+		if timezone.Valid {
+			h.Timezone = &timezone.String
+		}
 
 		hospitals = append(hospitals, h)
 	}
@@ -490,7 +576,7 @@ func (r *HospitalRepository) GetActiveHospitalsWithCoordinates(ctx context.Conte
 	tf := NewTenantFilter(ctx)
 
 	query := `
-		SELECT id, nome, codigo, endereco, telefone, latitude, longitude, config_conexao, ativo, created_at, updated_at
+		SELECT id, tenant_id, nome, codigo, endereco, telefone, email, escalation_email, latitude, longitude, config_conexao, timezone, ativo, created_at, updated_at
 		FROM hospitals
 		WHERE deleted_at IS NULL
 		  AND ativo = true
@@ -508,18 +594,22 @@ func (r *HospitalRepository) GetActiveHospitalsWithCoordinates(ctx context.Conte
 	var hospitals []models.Hospital
 	for rows.Next() {
 		var h models.Hospital
-		var endereco, telefone, configConexao sql.NullString
+		var endereco, telefone, email, escalationEmail, configConexao, timezone sql.NullString
 		var latitude, longitude sql.NullFloat64
 
 		err := rows.Scan(
 			&h.ID,
+			&h.TenantID,
 			&h.Nome,
 			&h.Codigo,
 			&endereco,
 			&telefone,
+			&email,
+			&escalationEmail,
 			&latitude,
 			&longitude,
 			&configConexao,
+			&timezone,
 			&h.Ativo,
 			&h.CreatedAt,
 			&h.UpdatedAt,
@@ -534,6 +624,12 @@ func (r *HospitalRepository) GetActiveHospitalsWithCoordinates(ctx context.Conte
 		if telefone.Valid {
 			h.Telefone = &telefone.String
 		}
+		if email.Valid {
+			h.Email = &email.String
+		}
+		if escalationEmail.Valid {
+			h.EscalationEmail = &escalationEmail.String
+		}
 		if latitude.Valid {
 			h.Latitude = &latitude.Float64
 		}
@@ -543,9 +639,84 @@ func (r *HospitalRepository) GetActiveHospitalsWithCoordinates(ctx context.Conte
 		if configConexao.Valid {
 			h.ConfigConexao = json.RawMessage(configConexao.String)
 		}
+		if timezone.Valid {
+			h.Timezone = &timezone.String
+		}
+
+		hospitals = append(hospitals, h)
+	}
+
+	return hospitals, nil
+}
+
+// GetActiveHospitalsMissingCoordinates returns active hospitals that have an address but no coordinates for the current tenant
+// Used by the geocoding fallback of the map feature
+func (r *HospitalRepository) GetActiveHospitalsMissingCoordinates(ctx context.Context) ([]models.Hospital, error) {
+	tf := NewTenantFilter(ctx)
+
+	query := `
+		SELECT id, nome, codigo, endereco
+		FROM hospitals
+		WHERE deleted_at IS NULL
+		  AND ativo = true
+		  AND (latitude IS NULL OR longitude IS NULL)
+		  AND endereco IS NOT NULL
+		  AND endereco <> ''` + tf.AndClause() + `
+		ORDER BY nome ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hospitals []models.Hospital
+	for rows.Next() {
+		var h models.Hospital
+		var endereco sql.NullString
+
+		if err := rows.Scan(&h.ID, &h.Nome, &h.Codigo, &endereco); err != nil {
+			return nil, err
+		}
+
+		if endereco.Valid {
+			h.Endereco = &endereco.String
+		}
+		h.Ativo = true
 
 		hospitals = append(hospitals, h)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return hospitals, nil
 }
+
+// UpdateCoordinates persists the latitude and longitude of a hospital for the current tenant
+func (r *HospitalRepository) UpdateCoordinates(ctx context.Context, id uuid.UUID, latitude, longitude float64) error {
+	tf := NewTenantFilter(ctx)
+
+	query := `
+		UPDATE hospitals
+		SET latitude = $1, longitude = $2, updated_at = $3
+		WHERE id = $4 AND deleted_at IS NULL` + tf.AndClause() + `
+	`
+
+	result, err := r.db.ExecContext(ctx, query, latitude, longitude, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrHospitalNotFound
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -38,16 +39,6 @@ func (r *IndicatorsRepository) CalculateTaxaConversao(ctx context.Context, hospi
 		SELECT COUNT(*) FROM occurrences
 		WHERE status = 'CONCLUIDA'` + whereHospital
 
-	var totalNotificacoes int
-	err := r.db.QueryRowContext(ctx, totalQuery, args...).Scan(&totalNotificacoes)
-	if err != nil {
-		return 0, err
-	}
-
-	if totalNotificacoes == 0 {
-		return 0, nil
-	}
-
 	// Count successful captures
 	captacoesQuery := `
 		SELECT COUNT(DISTINCT o.id) FROM occurrences o
@@ -55,12 +46,21 @@ func (r *IndicatorsRepository) CalculateTaxaConversao(ctx context.Context, hospi
 		WHERE o.status = 'CONCLUIDA'
 		AND oh.desfecho = 'sucesso_captacao'` + whereHospital
 
-	var captacoes int
-	err = r.db.QueryRowContext(ctx, captacoesQuery, args...).Scan(&captacoes)
+	var totalNotificacoes, captacoes int
+	err := withStatementTimeout(ctx, r.db, func(q readQueryer) error {
+		if err := q.QueryRowContext(ctx, totalQuery, args...).Scan(&totalNotificacoes); err != nil || totalNotificacoes == 0 {
+			return err
+		}
+		return q.QueryRowContext(ctx, captacoesQuery, args...).Scan(&captacoes)
+	})
 	if err != nil {
 		return 0, err
 	}
 
+	if totalNotificacoes == 0 {
+		return 0, nil
+	}
+
 	// Calculate rate
 	taxa := (float64(captacoes) / float64(totalNotificacoes)) * 100
 	return taxa, nil
@@ -86,7 +86,9 @@ func (r *IndicatorsRepository) CalculateLatenciaSistema(ctx context.Context, hos
 		AND created_at >= NOW() - INTERVAL '30 days'` + whereHospital
 
 	var avgLatencia float64
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&avgLatencia)
+	err := withStatementTimeout(ctx, r.db, func(q readQueryer) error {
+		return q.QueryRowContext(ctx, query, args...).Scan(&avgLatencia)
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -117,7 +119,9 @@ func (r *IndicatorsRepository) CalculateTempoRespostaOperacional(ctx context.Con
 		AND o.created_at >= NOW() - INTERVAL '30 days'` + whereHospital
 
 	var avgTempo float64
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&avgTempo)
+	err := withStatementTimeout(ctx, r.db, func(q readQueryer) error {
+		return q.QueryRowContext(ctx, query, args...).Scan(&avgTempo)
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -172,23 +176,28 @@ func (r *IndicatorsRepository) GetSeries30Dias(ctx context.Context, hospitalID *
 		ORDER BY ds.data ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
 	var series []SeriesDataPoint
-	for rows.Next() {
-		var dp SeriesDataPoint
-		err := rows.Scan(&dp.Data, &dp.ObitosTotais, &dp.Captados)
+	err := withStatementTimeout(ctx, r.db, func(q readQueryer) error {
+		rows, err := q.QueryContext(ctx, query, args...)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		series = append(series, dp)
+		defer rows.Close()
+
+		for rows.Next() {
+			var dp SeriesDataPoint
+			if err := rows.Scan(&dp.Data, &dp.ObitosTotais, &dp.Captados); err != nil {
+				return err
+			}
+			series = append(series, dp)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return series, rows.Err()
+	return series, nil
 }
 
 // RankingItem represents a hospital in the ranking
@@ -214,23 +223,189 @@ func (r *IndicatorsRepository) GetRankingHospitais(ctx context.Context, limit in
 		LIMIT $1
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	var ranking []RankingItem
+	err := withStatementTimeout(ctx, r.db, func(q readQueryer) error {
+		rows, err := q.QueryContext(ctx, query, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item RankingItem
+			if err := rows.Scan(&item.HospitalID, &item.Nome, &item.Captacoes); err != nil {
+				return err
+			}
+			ranking = append(ranking, item)
+		}
+		return rows.Err()
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var ranking []RankingItem
-	for rows.Next() {
-		var item RankingItem
-		err := rows.Scan(&item.HospitalID, &item.Nome, &item.Captacoes)
+	return ranking, nil
+}
+
+// breakdownFunnelColumns counts the conversion funnel of the occurrences "o" of a group:
+// every occurrence is eligible, accepted once it reached ACEITA and captured with a sucesso_captacao outcome
+const breakdownFunnelColumns = `
+			COUNT(o.id) as elegiveis,
+			COUNT(o.id) FILTER (WHERE o.status = 'ACEITA' OR EXISTS (
+				SELECT 1 FROM occurrence_history oh
+				WHERE oh.occurrence_id = o.id AND oh.status_novo = 'ACEITA'
+			)) as aceitas,
+			COUNT(o.id) FILTER (WHERE EXISTS (
+				SELECT 1 FROM occurrence_history oh
+				WHERE oh.occurrence_id = o.id AND oh.desfecho = 'sucesso_captacao'
+			)) as captadas`
+
+// GetBreakdown returns the conversion funnel of the last 30 days grouped by hospital, day or week
+// for the current tenant. Time buckets without occurrences are included with zero counts.
+func (r *IndicatorsRepository) GetBreakdown(ctx context.Context, groupBy models.IndicatorsGroupBy, hospitalID *uuid.UUID) (*models.IndicatorsBreakdown, error) {
+	if !groupBy.IsValid() {
+		return nil, fmt.Errorf("invalid indicators grouping: %q", groupBy)
+	}
+
+	tf := NewTenantFilter(ctx)
+
+	args := []interface{}{}
+	where := "o.created_at >= CURRENT_DATE - INTERVAL '29 days'" + tf.AndClauseWithAlias("o")
+
+	if hospitalID != nil {
+		where += " AND o.hospital_id = $" + itoa(len(args)+1)
+		args = append(args, *hospitalID)
+	}
+
+	var query string
+	if groupBy == models.IndicatorsGroupByHospital {
+		query = `
+		SELECT
+			h.id::text, h.nome,` + breakdownFunnelColumns + `
+		FROM occurrences o
+		INNER JOIN hospitals h ON o.hospital_id = h.id
+		WHERE ` + where + `
+		GROUP BY h.id, h.nome
+		ORDER BY elegiveis DESC, h.nome ASC
+	`
+	} else {
+		// groupBy is validated above, so the unit is one of the fixed values "day" or "week"
+		unit := string(groupBy)
+		query = `
+		WITH buckets AS (
+			SELECT generate_series(
+				date_trunc('` + unit + `', CURRENT_DATE - INTERVAL '29 days'),
+				date_trunc('` + unit + `', CURRENT_DATE),
+				'1 ` + unit + `'::interval
+			)::date AS inicio
+		),
+		funnel AS (
+			SELECT
+				date_trunc('` + unit + `', o.created_at)::date AS inicio,` + breakdownFunnelColumns + `
+			FROM occurrences o
+			WHERE ` + where + `
+			GROUP BY 1
+		)
+		SELECT
+			to_char(b.inicio, 'YYYY-MM-DD'), '',
+			COALESCE(f.elegiveis, 0), COALESCE(f.aceitas, 0), COALESCE(f.captadas, 0)
+		FROM buckets b
+		LEFT JOIN funnel f ON b.inicio = f.inicio
+		ORDER BY b.inicio ASC
+	`
+	}
+
+	breakdown := &models.IndicatorsBreakdown{GroupBy: groupBy, Itens: []models.BreakdownItem{}}
+	err := withStatementTimeout(ctx, r.db, func(q readQueryer) error {
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item models.BreakdownItem
+			if err := rows.Scan(&item.Chave, &item.Nome, &item.Elegiveis, &item.Aceitas, &item.Captadas); err != nil {
+				return err
+			}
+			if groupBy == models.IndicatorsGroupByHospital {
+				if id, err := uuid.Parse(item.Chave); err == nil {
+					item.HospitalID = &id
+				}
+			}
+			item.CalculateRates()
+			breakdown.Itens = append(breakdown.Itens, item)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return breakdown, nil
+}
+
+// GetConversionFunnel returns the conversion funnel of the obitos detected (data_obito) from dateFrom to dateTo,
+// both days inclusive, per hospital and in total for the current tenant. Each obito is eligible once it
+// became an occurrence, and the occurrence stages are counted as in the breakdown.
+func (r *IndicatorsRepository) GetConversionFunnel(ctx context.Context, dateFrom, dateTo time.Time, hospitalID *uuid.UUID) (*models.ConversionFunnel, error) {
+	tf := NewTenantFilter(ctx)
+
+	// Dates are passed as text so the range follows the days of the database time zone
+	from, to := dateFrom.Format("2006-01-02"), dateTo.Format("2006-01-02")
+	args := []interface{}{from, to}
+	where := "ob.data_obito >= $1::date AND ob.data_obito < $2::date + 1" + tf.AndClauseWithAlias("ob")
+
+	if hospitalID != nil {
+		where += " AND ob.hospital_id = $" + itoa(len(args)+1)
+		args = append(args, *hospitalID)
+	}
+
+	query := `
+		SELECT
+			h.id, h.nome,
+			COUNT(ob.id) as obitos_detectados,` + breakdownFunnelColumns + `
+		FROM obitos_simulados ob
+		INNER JOIN hospitals h ON ob.hospital_id = h.id
+		LEFT JOIN occurrences o ON o.obito_id = ob.id
+		WHERE ` + where + `
+		GROUP BY h.id, h.nome
+		ORDER BY obitos_detectados DESC, h.nome ASC
+	`
+
+	funnel := &models.ConversionFunnel{
+		DataInicio: from,
+		DataFim:    to,
+		Hospitais:  []models.HospitalFunnel{},
+	}
+	err := withStatementTimeout(ctx, r.db, func(q readQueryer) error {
+		rows, err := q.QueryContext(ctx, query, args...)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var item models.HospitalFunnel
+			if err := rows.Scan(&item.HospitalID, &item.Nome, &item.ObitosDetectados, &item.Elegiveis, &item.Aceitas, &item.Captadas); err != nil {
+				return err
+			}
+			item.CalculateRates()
+			funnel.Hospitais = append(funnel.Hospitais, item)
+
+			funnel.Total.ObitosDetectados += item.ObitosDetectados
+			funnel.Total.Elegiveis += item.Elegiveis
+			funnel.Total.Aceitas += item.Aceitas
+			funnel.Total.Captadas += item.Captadas
 		}
-		ranking = append(ranking, item)
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
+	funnel.Total.CalculateRates()
 
-	return ranking, rows.Err()
+	return funnel, nil
 }
 
 // GetAllIndicators fetches all indicators data in optimized queries
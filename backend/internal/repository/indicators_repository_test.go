@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+)
+
+// insertTestTenant creates a tenant and removes it after the test
+func insertTestTenant(t *testing.T, db *sql.DB) uuid.UUID {
+	t.Helper()
+
+	id := uuid.New()
+	if _, err := db.Exec(`INSERT INTO tenants (id, name, slug) VALUES ($1, $2, $3)`, id, "Tenant Teste", "tenant-"+id.String()[:8]); err != nil {
+		t.Fatalf("Failed to insert tenant: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM tenants WHERE id = $1`, id) })
+	return id
+}
+
+// insertTestOccurrence creates an occurrence (and its obito) created at createdAt with the given status,
+// recording ACEITA and sucesso_captacao history entries when requested, and removes it after the test
+func insertTestOccurrence(t *testing.T, db *sql.DB, tenantID, hospitalID uuid.UUID, status models.OccurrenceStatus, createdAt time.Time, accepted, captured bool) uuid.UUID {
+	t.Helper()
+
+	obitoID := uuid.New()
+	_, err := db.Exec(`
+		INSERT INTO obitos_simulados (id, hospital_id, tenant_id, nome_paciente, data_nascimento, data_obito, causa_mortis)
+		VALUES ($1, $2, $3, 'Paciente Teste', '1960-01-01', $4, 'Causa teste')
+	`, obitoID, hospitalID, tenantID, createdAt)
+	if err != nil {
+		t.Fatalf("Failed to insert obito: %v", err)
+	}
+
+	occurrenceID := uuid.New()
+	_, err = db.Exec(`
+		INSERT INTO occurrences (id, obito_id, hospital_id, tenant_id, status, nome_paciente_mascarado,
+			dados_completos, data_obito, janela_expira_em, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, 'Pa****** Te***', '{}', $6, $7, $6, $6)
+	`, occurrenceID, obitoID, hospitalID, tenantID, status, createdAt, createdAt.Add(6*time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to insert occurrence: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec(`DELETE FROM occurrences WHERE id = $1`, occurrenceID)
+		db.Exec(`DELETE FROM obitos_simulados WHERE id = $1`, obitoID)
+	})
+
+	if accepted {
+		_, err = db.Exec(`
+			INSERT INTO occurrence_history (occurrence_id, acao, status_anterior, status_novo)
+			VALUES ($1, $2, 'EM_ANDAMENTO', 'ACEITA')
+		`, occurrenceID, models.ActionOccurrenceAccepted)
+		if err != nil {
+			t.Fatalf("Failed to insert acceptance history: %v", err)
+		}
+	}
+	if captured {
+		_, err = db.Exec(`
+			INSERT INTO occurrence_history (occurrence_id, acao, status_anterior, status_novo, desfecho)
+			VALUES ($1, $2, 'ACEITA', 'CONCLUIDA', 'sucesso_captacao')
+		`, occurrenceID, models.ActionOutcomeRegistered)
+		if err != nil {
+			t.Fatalf("Failed to insert outcome history: %v", err)
+		}
+	}
+	return occurrenceID
+}
+
+// breakdownItemByKey returns the item of the breakdown with the given key
+func breakdownItemByKey(breakdown *models.IndicatorsBreakdown, key string) (models.BreakdownItem, bool) {
+	for _, item := range breakdown.Itens {
+		if item.Chave == key {
+			return item, true
+		}
+	}
+	return models.BreakdownItem{}, false
+}
+
+// assertFunnel checks the funnel counts of a breakdown item
+func assertFunnel(t *testing.T, name string, item models.BreakdownItem, elegiveis, aceitas, captadas int) {
+	t.Helper()
+
+	if item.Elegiveis != elegiveis || item.Aceitas != aceitas || item.Captadas != captadas {
+		t.Errorf("%s: expected %d/%d/%d eligible/accepted/captured, got %d/%d/%d",
+			name, elegiveis, aceitas, captadas, item.Elegiveis, item.Aceitas, item.Captadas)
+	}
+}
+
+// TestIndicatorsBreakdownGrouping tests the per-hospital and per-day funnels over seeded occurrences,
+// and that occurrences of other tenants are not counted
+func TestIndicatorsBreakdownGrouping(t *testing.T) {
+	db := openTestDB(t)
+
+	tenantID := insertTestTenant(t, db)
+	otherTenantID := insertTestTenant(t, db)
+	suffix := uuid.New().String()[:8]
+	hospitalA := insertTestHospital(t, db, tenantID, "IND-A-"+suffix)
+	hospitalB := insertTestHospital(t, db, tenantID, "IND-B-"+suffix)
+	otherHospital := insertTestHospital(t, db, otherTenantID, "IND-C-"+suffix)
+
+	var now time.Time
+	var today, twoDaysAgo string
+	err := db.QueryRow(`
+		SELECT NOW(), to_char(CURRENT_DATE, 'YYYY-MM-DD'), to_char((NOW() - INTERVAL '2 days')::date, 'YYYY-MM-DD')
+	`).Scan(&now, &today, &twoDaysAgo)
+	if err != nil {
+		t.Fatalf("Failed to read database clock: %v", err)
+	}
+
+	// Hospital A, today: eligible only, accepted, and accepted then captured
+	insertTestOccurrence(t, db, tenantID, hospitalA, models.StatusPendente, now, false, false)
+	insertTestOccurrence(t, db, tenantID, hospitalA, models.StatusAceita, now, true, false)
+	insertTestOccurrence(t, db, tenantID, hospitalA, models.StatusConcluida, now, true, true)
+	// Hospital B, two days ago: refused
+	insertTestOccurrence(t, db, tenantID, hospitalB, models.StatusRecusada, now.Add(-48*time.Hour), false, false)
+	// Other tenant, today: captured, must not be counted
+	insertTestOccurrence(t, db, otherTenantID, otherHospital, models.StatusConcluida, now, true, true)
+
+	repo := NewIndicatorsRepository(db)
+	ctx := middleware.WithTenantContext(context.Background(), tenantID.String(), false)
+
+	t.Run("by hospital", func(t *testing.T) {
+		breakdown, err := repo.GetBreakdown(ctx, models.IndicatorsGroupByHospital, nil)
+		if err != nil {
+			t.Fatalf("GetBreakdown returned error: %v", err)
+		}
+		if len(breakdown.Itens) != 2 {
+			t.Fatalf("Expected 2 hospitals, got %+v", breakdown.Itens)
+		}
+
+		itemA, _ := breakdownItemByKey(breakdown, hospitalA.String())
+		assertFunnel(t, "hospital A", itemA, 3, 2, 1)
+		if itemA.HospitalID == nil || *itemA.HospitalID != hospitalA || itemA.Nome == "" {
+			t.Errorf("Expected hospital A id and name, got %+v", itemA)
+		}
+		if itemA.TaxaConversao < 33.3 || itemA.TaxaConversao > 33.4 || itemA.TaxaCaptacao != 50 {
+			t.Errorf("Unexpected rates for hospital A: %+v", itemA)
+		}
+
+		itemB, _ := breakdownItemByKey(breakdown, hospitalB.String())
+		assertFunnel(t, "hospital B", itemB, 1, 0, 0)
+
+		if _, ok := breakdownItemByKey(breakdown, otherHospital.String()); ok {
+			t.Error("Expected the other tenant hospital to be excluded")
+		}
+	})
+
+	t.Run("by day", func(t *testing.T) {
+		breakdown, err := repo.GetBreakdown(ctx, models.IndicatorsGroupByDay, nil)
+		if err != nil {
+			t.Fatalf("GetBreakdown returned error: %v", err)
+		}
+		if len(breakdown.Itens) != 30 {
+			t.Fatalf("Expected 30 daily buckets, got %d", len(breakdown.Itens))
+		}
+
+		itemToday, _ := breakdownItemByKey(breakdown, today)
+		assertFunnel(t, "today", itemToday, 3, 2, 1)
+		itemTwoDaysAgo, _ := breakdownItemByKey(breakdown, twoDaysAgo)
+		assertFunnel(t, "two days ago", itemTwoDaysAgo, 1, 0, 0)
+	})
+
+	t.Run("by week with hospital filter", func(t *testing.T) {
+		breakdown, err := repo.GetBreakdown(ctx, models.IndicatorsGroupByWeek, &hospitalA)
+		if err != nil {
+			t.Fatalf("GetBreakdown returned error: %v", err)
+		}
+
+		total := models.BreakdownItem{}
+		for _, item := range breakdown.Itens {
+			total.Elegiveis += item.Elegiveis
+			total.Aceitas += item.Aceitas
+			total.Captadas += item.Captadas
+		}
+		assertFunnel(t, "weeks of hospital A", total, 3, 2, 1)
+	})
+
+	t.Run("invalid grouping", func(t *testing.T) {
+		if _, err := repo.GetBreakdown(ctx, models.IndicatorsGroupBy("month"), nil); err == nil {
+			t.Error("Expected an error for an unsupported grouping")
+		}
+	})
+}
+
+// insertTestObito creates an obito detected at dataObito that did not become an occurrence
+func insertTestObito(t *testing.T, db *sql.DB, tenantID, hospitalID uuid.UUID, dataObito time.Time) {
+	t.Helper()
+
+	obitoID := uuid.New()
+	_, err := db.Exec(`
+		INSERT INTO obitos_simulados (id, hospital_id, tenant_id, nome_paciente, data_nascimento, data_obito, causa_mortis)
+		VALUES ($1, $2, $3, 'Paciente Inelegivel', '1930-01-01', $4, 'Causa teste')
+	`, obitoID, hospitalID, tenantID, dataObito)
+	if err != nil {
+		t.Fatalf("Failed to insert obito: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM obitos_simulados WHERE id = $1`, obitoID) })
+}
+
+// assertFunnelStages checks the stage counts of a conversion funnel
+func assertFunnelStages(t *testing.T, name string, stages models.FunnelStages, obitos, elegiveis, aceitas, captadas int) {
+	t.Helper()
+
+	if stages.ObitosDetectados != obitos || stages.Elegiveis != elegiveis || stages.Aceitas != aceitas || stages.Captadas != captadas {
+		t.Errorf("%s: expected %d/%d/%d/%d detected/eligible/accepted/captured, got %d/%d/%d/%d",
+			name, obitos, elegiveis, aceitas, captadas,
+			stages.ObitosDetectados, stages.Elegiveis, stages.Aceitas, stages.Captadas)
+	}
+}
+
+// TestIndicatorsConversionFunnel tests the stage counts and conversion rates of the funnel over seeded obitos,
+// per hospital and in total, within the date range and for the current tenant only
+func TestIndicatorsConversionFunnel(t *testing.T) {
+	db := openTestDB(t)
+
+	tenantID := insertTestTenant(t, db)
+	otherTenantID := insertTestTenant(t, db)
+	suffix := uuid.New().String()[:8]
+	hospitalA := insertTestHospital(t, db, tenantID, "FUN-A-"+suffix)
+	hospitalB := insertTestHospital(t, db, tenantID, "FUN-B-"+suffix)
+	otherHospital := insertTestHospital(t, db, otherTenantID, "FUN-C-"+suffix)
+
+	var now, today time.Time
+	if err := db.QueryRow(`SELECT NOW(), CURRENT_DATE`).Scan(&now, &today); err != nil {
+		t.Fatalf("Failed to read database clock: %v", err)
+	}
+
+	// Hospital A: 2 ineligible obitos, then eligible only, accepted, and accepted then captured
+	insertTestObito(t, db, tenantID, hospitalA, now)
+	insertTestObito(t, db, tenantID, hospitalA, now.Add(-24*time.Hour))
+	insertTestOccurrence(t, db, tenantID, hospitalA, models.StatusRecusada, now, false, false)
+	insertTestOccurrence(t, db, tenantID, hospitalA, models.StatusAceita, now, true, false)
+	insertTestOccurrence(t, db, tenantID, hospitalA, models.StatusConcluida, now, true, true)
+	// Hospital B: accepted then captured, and one captured before the range
+	insertTestOccurrence(t, db, tenantID, hospitalB, models.StatusConcluida, now.Add(-48*time.Hour), true, true)
+	insertTestOccurrence(t, db, tenantID, hospitalB, models.StatusConcluida, now.AddDate(0, 0, -20), true, true)
+	// Other tenant: captured, must not be counted
+	insertTestOccurrence(t, db, otherTenantID, otherHospital, models.StatusConcluida, now, true, true)
+
+	repo := NewIndicatorsRepository(db)
+	ctx := middleware.WithTenantContext(context.Background(), tenantID.String(), false)
+
+	funnel, err := repo.GetConversionFunnel(ctx, today.AddDate(0, 0, -7), today, nil)
+	if err != nil {
+		t.Fatalf("GetConversionFunnel returned error: %v", err)
+	}
+	if funnel.DataInicio != today.AddDate(0, 0, -7).Format("2006-01-02") || funnel.DataFim != today.Format("2006-01-02") {
+		t.Errorf("Unexpected funnel range %s - %s", funnel.DataInicio, funnel.DataFim)
+	}
+	if len(funnel.Hospitais) != 2 {
+		t.Fatalf("Expected 2 hospitals, got %+v", funnel.Hospitais)
+	}
+
+	// Hospitals are ordered by detected obitos
+	itemA, itemB := funnel.Hospitais[0], funnel.Hospitais[1]
+	if itemA.HospitalID != hospitalA || itemB.HospitalID != hospitalB {
+		t.Fatalf("Expected hospital A then B, got %s then %s", itemA.HospitalID, itemB.HospitalID)
+	}
+	assertFunnelStages(t, "hospital A", itemA.FunnelStages, 5, 3, 2, 1)
+	if itemA.TaxaElegibilidade != 60 || itemA.TaxaCaptacao != 50 || itemA.TaxaConversao != 20 {
+		t.Errorf("Unexpected rates for hospital A: %+v", itemA.FunnelStages)
+	}
+	if itemA.TaxaAceitacao < 66.6 || itemA.TaxaAceitacao > 66.7 {
+		t.Errorf("Expected a 66.7%% acceptance rate for hospital A, got %v", itemA.TaxaAceitacao)
+	}
+	assertFunnelStages(t, "hospital B", itemB.FunnelStages, 1, 1, 1, 1)
+	if itemB.TaxaConversao != 100 {
+		t.Errorf("Expected a 100%% conversion rate for hospital B, got %v", itemB.TaxaConversao)
+	}
+
+	assertFunnelStages(t, "total", funnel.Total, 6, 4, 3, 2)
+	if funnel.Total.TaxaAceitacao != 75 || funnel.Total.TaxaConversao < 33.3 || funnel.Total.TaxaConversao > 33.4 {
+		t.Errorf("Unexpected total rates: %+v", funnel.Total)
+	}
+
+	t.Run("hospital filter and range", func(t *testing.T) {
+		funnel, err := repo.GetConversionFunnel(ctx, today.AddDate(0, 0, -30), today.AddDate(0, 0, -1), &hospitalB)
+		if err != nil {
+			t.Fatalf("GetConversionFunnel returned error: %v", err)
+		}
+		assertFunnelStages(t, "hospital B over 30 days", funnel.Total, 2, 2, 2, 2)
+		if len(funnel.Hospitais) != 1 || funnel.Hospitais[0].HospitalID != hospitalB {
+			t.Errorf("Expected only hospital B, got %+v", funnel.Hospitais)
+		}
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+		funnel, err := repo.GetConversionFunnel(ctx, today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), nil)
+		if err != nil {
+			t.Fatalf("GetConversionFunnel returned error: %v", err)
+		}
+		assertFunnelStages(t, "future range", funnel.Total, 0, 0, 0, 0)
+		if len(funnel.Hospitais) != 0 || funnel.Total.TaxaConversao != 0 {
+			t.Errorf("Expected an empty funnel, got %+v", funnel)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// ListPresetRepository handles the list presets of the users
+type ListPresetRepository struct {
+	db *sql.DB
+}
+
+// NewListPresetRepository creates a new list preset repository
+func NewListPresetRepository(db *sql.DB) *ListPresetRepository {
+	return &ListPresetRepository{db: db}
+}
+
+// Save creates the preset of the user for the list, replacing the params of the preset of the same name
+func (r *ListPresetRepository) Save(ctx context.Context, userID uuid.UUID, list string, input *models.SaveListPresetInput) (*models.ListPreset, error) {
+	params := input.Params
+	if params == nil {
+		params = map[string]string{}
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO user_list_presets (id, user_id, list, nome, params, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (user_id, list, nome) DO UPDATE SET params = EXCLUDED.params, updated_at = NOW()
+		RETURNING id, user_id, list, nome, params, created_at, updated_at
+	`
+	return scanListPreset(r.db.QueryRowContext(ctx, query, uuid.New(), userID, list, input.Nome, data))
+}
+
+// GetByName retrieves the preset of the user for the list by name
+func (r *ListPresetRepository) GetByName(ctx context.Context, userID uuid.UUID, list, nome string) (*models.ListPreset, error) {
+	query := `
+		SELECT id, user_id, list, nome, params, created_at, updated_at
+		FROM user_list_presets
+		WHERE user_id = $1 AND list = $2 AND nome = $3
+	`
+	preset, err := scanListPreset(r.db.QueryRowContext(ctx, query, userID, list, nome))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrListPresetNotFound
+	}
+	return preset, err
+}
+
+// List returns the presets of the user for the list, ordered by name
+func (r *ListPresetRepository) List(ctx context.Context, userID uuid.UUID, list string) ([]models.ListPreset, error) {
+	query := `
+		SELECT id, user_id, list, nome, params, created_at, updated_at
+		FROM user_list_presets
+		WHERE user_id = $1 AND list = $2
+		ORDER BY nome ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, list)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	presets := []models.ListPreset{}
+	for rows.Next() {
+		preset, err := scanListPreset(rows)
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, *preset)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return presets, nil
+}
+
+// Delete deletes a preset of the user
+func (r *ListPresetRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM user_list_presets WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.ErrListPresetNotFound
+	}
+	return nil
+}
+
+// listPresetScanner is implemented by *sql.Row and *sql.Rows
+type listPresetScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanListPreset scans a list preset row
+func scanListPreset(row listPresetScanner) (*models.ListPreset, error) {
+	var preset models.ListPreset
+	var params []byte
+
+	if err := row.Scan(
+		&preset.ID,
+		&preset.UserID,
+		&preset.List,
+		&preset.Nome,
+		&params,
+		&preset.CreatedAt,
+		&preset.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(params, &preset.Params); err != nil {
+		return nil, fmt.Errorf("invalid list preset params: %w", err)
+	}
+
+	return &preset, nil
+}
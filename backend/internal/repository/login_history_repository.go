@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+// LoginHistoryRepository handles the last login and login history of the users
+type LoginHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewLoginHistoryRepository creates a new login history repository
+func NewLoginHistoryRepository(db *sql.DB) *LoginHistoryRepository {
+	return &LoginHistoryRepository{db: db}
+}
+
+// RecordLogin updates the last login of the user and appends the login to its history
+func (r *LoginHistoryRepository) RecordLogin(ctx context.Context, userID uuid.UUID, source auth.LoginSource) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET last_login_at = $1 WHERE id = $2`, now, userID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO login_history (id, user_id, ip_address, user_agent, logged_in_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5)
+	`, uuid.New(), userID, source.IPAddress, source.UserAgent, now)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListByUser returns the most recent logins of a user of the current tenant, newest first
+func (r *LoginHistoryRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit int) ([]models.LoginHistoryEntry, error) {
+	tf := NewTenantFilter(ctx)
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1`+tf.AndClause()+`)`, userID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, auth.ErrUserNotFound
+	}
+
+	query := `
+		SELECT id, user_id, ip_address, user_agent, logged_in_at
+		FROM login_history
+		WHERE user_id = $1
+		ORDER BY logged_in_at DESC
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.LoginHistoryEntry{}
+	for rows.Next() {
+		var entry models.LoginHistoryEntry
+		var ipAddress, userAgent sql.NullString
+
+		if err := rows.Scan(&entry.ID, &entry.UserID, &ipAddress, &userAgent, &entry.LoggedInAt); err != nil {
+			return nil, err
+		}
+
+		if ipAddress.Valid {
+			entry.IPAddress = &ipAddress.String
+		}
+		if userAgent.Valid {
+			entry.UserAgent = &userAgent.String
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+// TestRecordLoginUpdatesLastLoginAndHistory tests that a recorded login updates last_login_at and appends a history row
+func TestRecordLoginUpdatesLastLoginAndHistory(t *testing.T) {
+	db := openTestDB(t)
+
+	tenantID := insertTestTenant(t, db)
+	userID := insertTestUser(t, db, tenantID, "Operador Login")
+	ctx := middleware.WithTenantContext(context.Background(), tenantID.String(), false)
+
+	users := NewUserRepository(db)
+	before, err := users.GetModelByID(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetModelByID returned error: %v", err)
+	}
+	if before.LastLoginAt != nil {
+		t.Fatalf("Expected no last login for a new user, got %v", before.LastLoginAt)
+	}
+
+	repo := NewLoginHistoryRepository(db)
+	if err := repo.RecordLogin(ctx, userID, auth.LoginSource{IPAddress: "10.0.0.7", UserAgent: "Mozilla/5.0"}); err != nil {
+		t.Fatalf("RecordLogin returned error: %v", err)
+	}
+	if err := repo.RecordLogin(ctx, userID, auth.LoginSource{}); err != nil {
+		t.Fatalf("RecordLogin returned error: %v", err)
+	}
+
+	after, err := users.GetModelByID(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetModelByID returned error: %v", err)
+	}
+	if after.LastLoginAt == nil {
+		t.Fatal("Expected last_login_at to be set after the login")
+	}
+
+	entries, err := repo.ListByUser(ctx, userID, 10)
+	if err != nil {
+		t.Fatalf("ListByUser returned error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 logins in the history, got %d", len(entries))
+	}
+	if entries[0].IPAddress != nil || entries[0].UserAgent != nil {
+		t.Errorf("Expected the newest login first, without source, got %+v", entries[0])
+	}
+	if entries[1].IPAddress == nil || *entries[1].IPAddress != "10.0.0.7" || entries[1].UserAgent == nil || *entries[1].UserAgent != "Mozilla/5.0" {
+		t.Errorf("Expected the first login with its IP and user agent, got %+v", entries[1])
+	}
+	if !entries[0].LoggedInAt.Equal(*after.LastLoginAt) {
+		t.Errorf("Expected last_login_at %v to match the newest login %v", after.LastLoginAt, entries[0].LoggedInAt)
+	}
+
+	otherTenant := middleware.WithTenantContext(context.Background(), insertTestTenant(t, db).String(), false)
+	if _, err := repo.ListByUser(otherTenant, userID, 10); !errors.Is(err, auth.ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound for a user of another tenant, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sidot/backend/internal/models"
+)
+
+// ObitoIneligibilityRepository handles the ineligibility records of the triagem
+type ObitoIneligibilityRepository struct {
+	db *sql.DB
+}
+
+// NewObitoIneligibilityRepository creates a new obito ineligibility repository
+func NewObitoIneligibilityRepository(db *sql.DB) *ObitoIneligibilityRepository {
+	return &ObitoIneligibilityRepository{db: db}
+}
+
+// Create records the reasons, evaluated rules and rejecting rule of an ineligible obito,
+// in the hospital and tenant of the obito. An empty regraRejeicao is stored as NULL.
+func (r *ObitoIneligibilityRepository) Create(ctx context.Context, obitoID uuid.UUID, motivos, regrasAvaliadas []string, regraRejeicao string) (*models.ObitoIneligibility, error) {
+	if motivos == nil {
+		motivos = []string{}
+	}
+	if regrasAvaliadas == nil {
+		regrasAvaliadas = []string{}
+	}
+
+	query := `
+		INSERT INTO obito_ineligibilities (obito_id, hospital_id, tenant_id, motivos, regras_avaliadas, regra_rejeicao)
+		SELECT id, hospital_id, tenant_id, $2, $3, NULLIF($4, '')
+		FROM obitos_simulados
+		WHERE id = $1
+		RETURNING id, hospital_id, tenant_id, created_at
+	`
+
+	ineligibility := &models.ObitoIneligibility{
+		ObitoID:         obitoID,
+		Motivos:         motivos,
+		RegrasAvaliadas: regrasAvaliadas,
+	}
+	if regraRejeicao != "" {
+		ineligibility.RegraRejeicao = &regraRejeicao
+	}
+	err := r.db.QueryRowContext(ctx, query, obitoID, pq.Array(motivos), pq.Array(regrasAvaliadas), regraRejeicao).Scan(
+		&ineligibility.ID,
+		&ineligibility.HospitalID,
+		&ineligibility.TenantID,
+		&ineligibility.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrObitoNotFound
+		}
+		return nil, err
+	}
+
+	return ineligibility, nil
+}
+
+// List returns the ineligibility records of the current tenant, newest first, with the total matching the filters
+func (r *ObitoIneligibilityRepository) List(ctx context.Context, filters models.ObitoIneligibilityFilters) ([]models.ObitoIneligibility, int, error) {
+	where := "WHERE 1=1" + NewTenantFilter(ctx).AndClauseWithAlias("oi")
+	args := []interface{}{}
+	argIndex := 1
+
+	if filters.Motivo != "" {
+		where += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM unnest(oi.motivos) AS motivo WHERE motivo ILIKE $%d)", argIndex)
+		args = append(args, "%"+EscapeLikePattern(filters.Motivo)+"%")
+		argIndex++
+	}
+
+	if filters.DateFrom != nil {
+		where += fmt.Sprintf(" AND oi.created_at >= $%d", argIndex)
+		args = append(args, *filters.DateFrom)
+		argIndex++
+	}
+
+	if filters.DateTo != nil {
+		where += fmt.Sprintf(" AND oi.created_at <= $%d", argIndex)
+		args = append(args, *filters.DateTo)
+		argIndex++
+	}
+
+	if filters.HospitalID != nil {
+		where += fmt.Sprintf(" AND oi.hospital_id = $%d", argIndex)
+		args = append(args, *filters.HospitalID)
+		argIndex++
+	}
+
+	offset := (filters.Page - 1) * filters.PageSize
+	query := fmt.Sprintf(`
+		SELECT oi.id, oi.obito_id, oi.hospital_id, oi.tenant_id, oi.motivos, oi.regras_avaliadas, oi.regra_rejeicao, oi.created_at
+		FROM obito_ineligibilities oi
+		%s
+		ORDER BY oi.created_at DESC, oi.id
+		LIMIT %d OFFSET %d
+	`, where, filters.PageSize, offset)
+
+	var totalItems int
+	ineligibilities := []models.ObitoIneligibility{}
+	err := withStatementTimeout(ctx, r.db, func(q readQueryer) error {
+		if err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM obito_ineligibilities oi "+where, args...).Scan(&totalItems); err != nil {
+			return err
+		}
+
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var i models.ObitoIneligibility
+			if err := rows.Scan(
+				&i.ID, &i.ObitoID, &i.HospitalID, &i.TenantID,
+				pq.Array(&i.Motivos), pq.Array(&i.RegrasAvaliadas), &i.RegraRejeicao, &i.CreatedAt,
+			); err != nil {
+				return err
+			}
+			ineligibilities = append(ineligibilities, i)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ineligibilities, totalItems, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+)
+
+// insertIneligibleObito creates an obito detected now and removes it, with its ineligibility records, after the test
+func insertIneligibleObito(t *testing.T, db *sql.DB, tenantID, hospitalID uuid.UUID) uuid.UUID {
+	t.Helper()
+
+	obitoID := uuid.New()
+	_, err := db.Exec(`
+		INSERT INTO obitos_simulados (id, hospital_id, tenant_id, nome_paciente, data_nascimento, data_obito, causa_mortis, processado)
+		VALUES ($1, $2, $3, 'Paciente Inelegivel', '1930-01-01', NOW(), 'Causa teste', true)
+	`, obitoID, hospitalID, tenantID)
+	if err != nil {
+		t.Fatalf("Failed to insert obito: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM obitos_simulados WHERE id = $1`, obitoID) })
+	return obitoID
+}
+
+// TestObitoIneligibilityCreateAndList tests that ineligibility records keep the engine output and are
+// listed per tenant with the reason and date filters
+func TestObitoIneligibilityCreateAndList(t *testing.T) {
+	db := openTestDB(t)
+
+	tenantID := insertTestTenant(t, db)
+	otherTenantID := insertTestTenant(t, db)
+	suffix := uuid.New().String()[:8]
+	hospitalID := insertTestHospital(t, db, tenantID, "INE-A-"+suffix)
+	otherHospitalID := insertTestHospital(t, db, otherTenantID, "INE-B-"+suffix)
+
+	repo := NewObitoIneligibilityRepository(db)
+	ctx := context.Background()
+	rules := []string{"Idade Maxima", "Janela de 6 horas"}
+
+	tooOld := insertIneligibleObito(t, db, tenantID, hospitalID)
+	record, err := repo.Create(ctx, tooOld, []string{"Idade acima do limite"}, rules, "Idade Maxima")
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if record.HospitalID != hospitalID || record.TenantID != tenantID {
+		t.Errorf("Expected the hospital and tenant of the obito, got %s and %s", record.HospitalID, record.TenantID)
+	}
+
+	outsideWindow := insertIneligibleObito(t, db, tenantID, hospitalID)
+	if _, err := repo.Create(ctx, outsideWindow, []string{"Fora da janela de captacao"}, rules, ""); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	otherTenant := insertIneligibleObito(t, db, otherTenantID, otherHospitalID)
+	if _, err := repo.Create(ctx, otherTenant, []string{"Idade acima do limite"}, rules, ""); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	if _, err := repo.Create(ctx, uuid.New(), []string{"Idade acima do limite"}, rules, ""); !errors.Is(err, ErrObitoNotFound) {
+		t.Errorf("Expected ErrObitoNotFound for an unknown obito, got %v", err)
+	}
+
+	tenantCtx := middleware.WithTenantContext(ctx, tenantID.String(), false)
+	defaults := models.ObitoIneligibilityFilters{Page: 1, PageSize: 20}
+
+	t.Run("tenant records", func(t *testing.T) {
+		records, total, err := repo.List(tenantCtx, defaults)
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		if total != 2 || len(records) != 2 {
+			t.Fatalf("Expected the 2 records of the tenant, got %d (%d)", len(records), total)
+		}
+	})
+
+	t.Run("reason filter", func(t *testing.T) {
+		filters := defaults
+		filters.Motivo = "idade"
+		records, total, err := repo.List(tenantCtx, filters)
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		if total != 1 || len(records) != 1 || records[0].ObitoID != tooOld {
+			t.Fatalf("Expected only the obito above the age limit, got %+v", records)
+		}
+		if !reflect.DeepEqual(records[0].Motivos, []string{"Idade acima do limite"}) || !reflect.DeepEqual(records[0].RegrasAvaliadas, rules) {
+			t.Errorf("Expected the stored reasons and rules, got %v and %v", records[0].Motivos, records[0].RegrasAvaliadas)
+		}
+		if records[0].RegraRejeicao == nil || *records[0].RegraRejeicao != "Idade Maxima" {
+			t.Errorf("Expected the rejecting rule to be stored, got %v", records[0].RegraRejeicao)
+		}
+	})
+
+	t.Run("reason filter wildcards are literal", func(t *testing.T) {
+		filters := defaults
+		filters.Motivo = "%"
+		_, total, err := repo.List(tenantCtx, filters)
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		if total != 0 {
+			t.Errorf("Expected no reason containing a literal %%, got %d", total)
+		}
+	})
+
+	t.Run("date filter", func(t *testing.T) {
+		tomorrow := time.Now().Add(24 * time.Hour)
+		filters := defaults
+		filters.DateFrom = &tomorrow
+		_, total, err := repo.List(tenantCtx, filters)
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		if total != 0 {
+			t.Errorf("Expected no record from tomorrow on, got %d", total)
+		}
+	})
+}
@@ -89,6 +89,76 @@ func (r *ObitoRepository) GetUnprocessed(ctx context.Context, since time.Time) (
 	return obitos, nil
 }
 
+// ListByDeathDateRange returns obitos (processed or not) whose date of death falls in [from, to)
+// Results are tenant-scoped and limited to the most recent limit records
+func (r *ObitoRepository) ListByDeathDateRange(ctx context.Context, from, to time.Time, limit int) ([]models.ObitoSimulado, error) {
+	tenantFilter := NewTenantFilter(ctx)
+
+	query := `
+		SELECT
+			o.id, o.hospital_id, o.nome_paciente, o.data_nascimento, o.data_obito,
+			o.causa_mortis, o.prontuario, o.setor, o.leito, o.identificacao_desconhecida,
+			o.processado, o.processado_em, o.created_at,
+			h.id, h.nome, h.codigo, h.endereco, h.ativo
+		FROM obitos_simulados o
+		LEFT JOIN hospitals h ON o.hospital_id = h.id
+		WHERE o.data_obito >= $1
+		AND o.data_obito < $2
+	` + tenantFilter.AndClauseWithAlias("o") + `
+		ORDER BY o.data_obito DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var obitos []models.ObitoSimulado
+	for rows.Next() {
+		var o models.ObitoSimulado
+		var h models.Hospital
+		var prontuario, setor, leito, hEndereco sql.NullString
+		var processadoEm sql.NullTime
+
+		err := rows.Scan(
+			&o.ID, &o.HospitalID, &o.NomePaciente, &o.DataNascimento, &o.DataObito,
+			&o.CausaMortis, &prontuario, &setor, &leito, &o.IdentificacaoDesconhecida,
+			&o.Processado, &processadoEm, &o.CreatedAt,
+			&h.ID, &h.Nome, &h.Codigo, &hEndereco, &h.Ativo,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if prontuario.Valid {
+			o.Prontuario = &prontuario.String
+		}
+		if setor.Valid {
+			o.Setor = &setor.String
+		}
+		if leito.Valid {
+			o.Leito = &leito.String
+		}
+		if processadoEm.Valid {
+			o.ProcessadoEm = &processadoEm.Time
+		}
+		if hEndereco.Valid {
+			h.Endereco = &hEndereco.String
+		}
+		o.Hospital = &h
+
+		obitos = append(obitos, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return obitos, nil
+}
+
 // GetByID retrieves an obito by ID
 func (r *ObitoRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ObitoSimulado, error) {
 	query := `
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/sidot/backend/internal/services"
+)
+
+// OccurrenceDataEncryptionAlgorithm identifies dados_completos values sealed by this package
+const OccurrenceDataEncryptionAlgorithm = "aes-256-gcm"
+
+var (
+	// ErrOccurrenceDataEncryptionUnavailable is returned when encryption is requested without an ENCRYPTION_KEY
+	ErrOccurrenceDataEncryptionUnavailable = errors.New("occurrence data encryption requires ENCRYPTION_KEY to be configured")
+
+	// ErrOccurrenceDataEncrypted is returned when an encrypted dados_completos is read without the encryption key
+	ErrOccurrenceDataEncrypted = errors.New("occurrence data is encrypted and no encryption key is configured")
+)
+
+var (
+	// occurrenceDataEncryption encrypts dados_completos for every OccurrenceRepository in the process
+	occurrenceDataEncryption        *services.EncryptionService
+	occurrenceDataEncryptionEnabled bool
+	occurrenceDataEncryptionMu      sync.RWMutex
+)
+
+// encryptedOccurrenceData is the JSON envelope stored in dados_completos when encryption is enabled
+// It keeps the column valid JSONB while the patient data itself is only stored as ciphertext
+type encryptedOccurrenceData struct {
+	Algorithm  string `json:"_enc"`
+	Ciphertext string `json:"data"`
+}
+
+// SetOccurrenceDataEncryptionService sets the service used to encrypt and decrypt dados_completos
+// Encrypted values can be read whenever the service is set, even if encryption of new writes is disabled
+func SetOccurrenceDataEncryptionService(svc *services.EncryptionService) {
+	occurrenceDataEncryptionMu.Lock()
+	defer occurrenceDataEncryptionMu.Unlock()
+	occurrenceDataEncryption = svc
+}
+
+// SetOccurrenceDataEncryptionEnabled toggles encryption of dados_completos on new writes
+// Existing plaintext rows stay readable, so deployments can migrate gradually
+func SetOccurrenceDataEncryptionEnabled(enabled bool) error {
+	occurrenceDataEncryptionMu.Lock()
+	defer occurrenceDataEncryptionMu.Unlock()
+
+	if enabled && occurrenceDataEncryption == nil {
+		return ErrOccurrenceDataEncryptionUnavailable
+	}
+	occurrenceDataEncryptionEnabled = enabled
+	return nil
+}
+
+// OccurrenceDataEncryptionAvailable reports whether an encryption service is configured
+func OccurrenceDataEncryptionAvailable() bool {
+	occurrenceDataEncryptionMu.RLock()
+	defer occurrenceDataEncryptionMu.RUnlock()
+	return occurrenceDataEncryption != nil
+}
+
+// OccurrenceDataEncryptionEnabled reports whether new dados_completos values are encrypted
+func OccurrenceDataEncryptionEnabled() bool {
+	occurrenceDataEncryptionMu.RLock()
+	defer occurrenceDataEncryptionMu.RUnlock()
+	return occurrenceDataEncryptionEnabled
+}
+
+// SealOccurrenceData encrypts dados_completos for storage when encryption is enabled
+// Plaintext is returned unchanged when encryption is disabled
+func SealOccurrenceData(plaintext json.RawMessage) (json.RawMessage, error) {
+	occurrenceDataEncryptionMu.RLock()
+	svc, enabled := occurrenceDataEncryption, occurrenceDataEncryptionEnabled
+	occurrenceDataEncryptionMu.RUnlock()
+
+	if !enabled {
+		return plaintext, nil
+	}
+
+	ciphertext, err := svc.EncryptValue(string(plaintext))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(encryptedOccurrenceData{
+		Algorithm:  OccurrenceDataEncryptionAlgorithm,
+		Ciphertext: ciphertext,
+	})
+}
+
+// OpenOccurrenceData returns the plaintext dados_completos for a stored value
+// Values stored before encryption was enabled are returned unchanged
+func OpenOccurrenceData(stored json.RawMessage) (json.RawMessage, error) {
+	var envelope encryptedOccurrenceData
+	if err := json.Unmarshal(stored, &envelope); err != nil || envelope.Algorithm != OccurrenceDataEncryptionAlgorithm {
+		return stored, nil
+	}
+
+	occurrenceDataEncryptionMu.RLock()
+	svc := occurrenceDataEncryption
+	occurrenceDataEncryptionMu.RUnlock()
+
+	if svc == nil {
+		return nil, ErrOccurrenceDataEncrypted
+	}
+
+	plaintext, err := svc.DecryptValue(envelope.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(plaintext), nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services"
+)
+
+// useOccurrenceDataEncryption installs an encryption service with the given key for the test
+func useOccurrenceDataEncryption(t *testing.T, key string, enabled bool) {
+	t.Helper()
+
+	svc, err := services.NewEncryptionServiceWithKey([]byte(key))
+	if err != nil {
+		t.Fatalf("Failed to create encryption service: %v", err)
+	}
+	SetOccurrenceDataEncryptionService(svc)
+	if err := SetOccurrenceDataEncryptionEnabled(enabled); err != nil {
+		t.Fatalf("Failed to enable encryption: %v", err)
+	}
+
+	t.Cleanup(func() {
+		SetOccurrenceDataEncryptionService(nil)
+		SetOccurrenceDataEncryptionEnabled(false)
+	})
+}
+
+func occurrenceTestData(t *testing.T) json.RawMessage {
+	data, err := json.Marshal(models.OccurrenceCompleteData{
+		NomePaciente: "Maria Aparecida Souza",
+		CausaMortis:  "Infarto agudo do miocardio",
+		Idade:        54,
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal complete data: %v", err)
+	}
+	return data
+}
+
+func TestOccurrenceDataEncryptionRoundTrip(t *testing.T) {
+	useOccurrenceDataEncryption(t, "0123456789abcdef0123456789abcdef", true)
+	plaintext := occurrenceTestData(t)
+
+	stored, err := SealOccurrenceData(plaintext)
+	if err != nil {
+		t.Fatalf("SealOccurrenceData returned error: %v", err)
+	}
+
+	if bytes.Contains(stored, []byte("Maria")) || bytes.Contains(stored, []byte("miocardio")) {
+		t.Errorf("Expected stored data not to contain plaintext, got %s", stored)
+	}
+	if !json.Valid(stored) {
+		t.Errorf("Expected stored data to remain valid JSON for the JSONB column, got %s", stored)
+	}
+
+	opened, err := OpenOccurrenceData(stored)
+	if err != nil {
+		t.Fatalf("OpenOccurrenceData returned error: %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("Expected %s after round trip, got %s", plaintext, opened)
+	}
+}
+
+func TestOccurrenceDataEncryptionDisabledKeepsPlaintext(t *testing.T) {
+	useOccurrenceDataEncryption(t, "0123456789abcdef0123456789abcdef", false)
+	plaintext := occurrenceTestData(t)
+
+	stored, err := SealOccurrenceData(plaintext)
+	if err != nil {
+		t.Fatalf("SealOccurrenceData returned error: %v", err)
+	}
+	if !bytes.Equal(stored, plaintext) {
+		t.Errorf("Expected plaintext to be stored while encryption is disabled, got %s", stored)
+	}
+
+	// Rows written before encryption was enabled stay readable afterwards
+	SetOccurrenceDataEncryptionEnabled(true)
+	opened, err := OpenOccurrenceData(plaintext)
+	if err != nil || !bytes.Equal(opened, plaintext) {
+		t.Errorf("Expected legacy plaintext row to be returned unchanged, got %s (err: %v)", opened, err)
+	}
+}
+
+func TestOccurrenceDataEncryptedUnreadableWithoutKey(t *testing.T) {
+	useOccurrenceDataEncryption(t, "0123456789abcdef0123456789abcdef", true)
+	stored, err := SealOccurrenceData(occurrenceTestData(t))
+	if err != nil {
+		t.Fatalf("SealOccurrenceData returned error: %v", err)
+	}
+
+	// No key configured
+	SetOccurrenceDataEncryptionService(nil)
+	if _, err := OpenOccurrenceData(stored); !errors.Is(err, ErrOccurrenceDataEncrypted) {
+		t.Errorf("Expected ErrOccurrenceDataEncrypted without a key, got %v", err)
+	}
+
+	// A different key
+	other, _ := services.NewEncryptionServiceWithKey([]byte("fedcba9876543210fedcba9876543210"))
+	SetOccurrenceDataEncryptionService(other)
+	if _, err := OpenOccurrenceData(stored); !errors.Is(err, services.ErrDecryptionFailed) {
+		t.Errorf("Expected ErrDecryptionFailed with the wrong key, got %v", err)
+	}
+}
+
+func TestSetOccurrenceDataEncryptionEnabledRequiresKey(t *testing.T) {
+	SetOccurrenceDataEncryptionService(nil)
+	if err := SetOccurrenceDataEncryptionEnabled(true); !errors.Is(err, ErrOccurrenceDataEncryptionUnavailable) {
+		t.Errorf("Expected ErrOccurrenceDataEncryptionUnavailable, got %v", err)
+	}
+	if OccurrenceDataEncryptionEnabled() {
+		t.Error("Expected encryption to stay disabled without a key")
+	}
+}
+
+// TestListPendingNearExpirySkipsAssignedAndUnreadableRows verifies that the SLA listing leaves out assigned
+// occurrences and skips a row that cannot be decrypted instead of failing the whole batch
+func TestListPendingNearExpirySkipsAssignedAndUnreadableRows(t *testing.T) {
+	db := openTestDB(t)
+	useOccurrenceDataEncryption(t, "0123456789abcdef0123456789abcdef", true)
+
+	tenantID := insertTestTenant(t, db)
+	hospitalID := insertTestHospital(t, db, tenantID, "SLA"+uuid.New().String()[:8])
+	userID := insertTestUser(t, db, tenantID, "Operador SLA")
+
+	// Windows expire 30 minutes from now
+	now := time.Now()
+	createdAt := now.Add(-5*time.Hour - 30*time.Minute)
+	readable := insertTestOccurrence(t, db, tenantID, hospitalID, models.StatusPendente, createdAt, false, false)
+	assigned := insertTestOccurrence(t, db, tenantID, hospitalID, models.StatusPendente, createdAt, false, false)
+	unreadable := insertTestOccurrence(t, db, tenantID, hospitalID, models.StatusPendente, createdAt, false, false)
+
+	if _, err := db.Exec(`UPDATE occurrences SET assigned_user_id = $1 WHERE id = $2`, userID, assigned); err != nil {
+		t.Fatalf("Failed to assign occurrence: %v", err)
+	}
+	sealed, err := SealOccurrenceData(occurrenceTestData(t))
+	if err != nil {
+		t.Fatalf("SealOccurrenceData returned error: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE occurrences SET dados_completos = $1 WHERE id = $2`, string(sealed), unreadable); err != nil {
+		t.Fatalf("Failed to store sealed data: %v", err)
+	}
+
+	// Data sealed with another key can no longer be decrypted
+	useOccurrenceDataEncryption(t, "fedcba9876543210fedcba9876543210", true)
+
+	occurrences, err := NewOccurrenceRepository(db).ListPendingNearExpiry(context.Background(), now, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListPendingNearExpiry returned error: %v", err)
+	}
+	found := make(map[uuid.UUID]bool)
+	for _, o := range occurrences {
+		found[o.ID] = true
+		if o.ID == readable && o.TenantID != tenantID {
+			t.Errorf("Expected the occurrence of tenant %s, got %s", tenantID, o.TenantID)
+		}
+	}
+	if !found[readable] {
+		t.Error("Expected the unassigned readable occurrence to be listed")
+	}
+	if found[assigned] {
+		t.Error("Expected the assigned occurrence to be left out")
+	}
+	if found[unreadable] {
+		t.Error("Expected the unreadable occurrence to be skipped")
+	}
+}
@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sidot/backend/internal/models"
 )
 
@@ -26,6 +29,28 @@ func NewOccurrenceRepository(db *sql.DB) *OccurrenceRepository {
 	return &OccurrenceRepository{db: db}
 }
 
+// occurrenceSortColumns are the columns the occurrence lists can be sorted by
+var occurrenceSortColumns = map[string]bool{
+	"created_at":        true,
+	"score_priorizacao": true,
+	"janela_expira_em":  true,
+	"data_obito":        true,
+}
+
+// occurrenceOrderBy builds the ORDER BY clause of an occurrence list. Columns outside the allowlist
+// fall back to score descending; ties are broken by creation date and id so pages are stable.
+func occurrenceOrderBy(sortBy, sortOrder string) string {
+	column := "score_priorizacao"
+	if occurrenceSortColumns[sortBy] {
+		column = sortBy
+	}
+	order := "DESC"
+	if sortOrder == "asc" {
+		order = "ASC"
+	}
+	return fmt.Sprintf("o.%s %s, o.created_at DESC, o.id", column, order)
+}
+
 // List returns occurrences with pagination and filters for the current tenant
 func (r *OccurrenceRepository) List(ctx context.Context, filters models.OccurrenceListFilters) ([]models.Occurrence, int, error) {
 	tf := NewTenantFilter(ctx)
@@ -59,6 +84,13 @@ func (r *OccurrenceRepository) List(ctx context.Context, filters models.Occurren
 		argIndex++
 	}
 
+	if filters.Search != nil && *filters.Search != "" {
+		clause, pattern := occurrenceSearchClause(*filters.Search, filters.SearchFullName, argIndex)
+		where += clause
+		args = append(args, pattern)
+		argIndex++
+	}
+
 	// Count total items
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM occurrences o %s", where)
 	var totalItems int
@@ -67,24 +99,6 @@ func (r *OccurrenceRepository) List(ctx context.Context, filters models.Occurren
 		return nil, 0, err
 	}
 
-	// Build ORDER BY clause
-	orderBy := "o.created_at DESC"
-	if filters.SortBy != "" {
-		validSortColumns := map[string]bool{
-			"created_at":       true,
-			"score_priorizacao": true,
-			"janela_expira_em": true,
-			"data_obito":       true,
-		}
-		if validSortColumns[filters.SortBy] {
-			order := "DESC"
-			if filters.SortOrder == "asc" {
-				order = "ASC"
-			}
-			orderBy = fmt.Sprintf("o.%s %s", filters.SortBy, order)
-		}
-	}
-
 	// Pagination
 	offset := (filters.Page - 1) * filters.PageSize
 	limit := filters.PageSize
@@ -95,13 +109,15 @@ func (r *OccurrenceRepository) List(ctx context.Context, filters models.Occurren
 			o.id, o.obito_id, o.hospital_id, o.status, o.score_priorizacao,
 			o.nome_paciente_mascarado, o.dados_completos, o.created_at, o.updated_at,
 			o.notificado_em, o.data_obito, o.janela_expira_em,
-			h.id, h.nome, h.codigo, h.endereco, h.ativo
+			h.id, h.nome, h.codigo, h.endereco, h.ativo,
+			o.assigned_user_id, au.nome, o.flags
 		FROM occurrences o
 		LEFT JOIN hospitals h ON o.hospital_id = h.id
+		LEFT JOIN users au ON o.assigned_user_id = au.id
 		%s
 		ORDER BY %s
 		LIMIT %d OFFSET %d
-	`, where, orderBy, limit, offset)
+	`, where, occurrenceOrderBy(filters.SortBy, filters.SortOrder), limit, offset)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -116,18 +132,23 @@ func (r *OccurrenceRepository) List(ctx context.Context, filters models.Occurren
 		var notificadoEm sql.NullTime
 		var dadosCompletos string
 		var hEndereco sql.NullString
+		var assignedUserID, assignedUserNome sql.NullString
 
 		err := rows.Scan(
 			&o.ID, &o.ObitoID, &o.HospitalID, &o.Status, &o.ScorePriorizacao,
 			&o.NomePacienteMascarado, &dadosCompletos, &o.CreatedAt, &o.UpdatedAt,
 			&notificadoEm, &o.DataObito, &o.JanelaExpiraEm,
 			&h.ID, &h.Nome, &h.Codigo, &hEndereco, &h.Ativo,
+			&assignedUserID, &assignedUserNome, pq.Array(&o.Flags),
 		)
 		if err != nil {
 			return nil, 0, err
 		}
 
-		o.DadosCompletos = json.RawMessage(dadosCompletos)
+		o.DadosCompletos, err = OpenOccurrenceData(json.RawMessage(dadosCompletos))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read dados_completos of occurrence %s: %w", o.ID, err)
+		}
 
 		if notificadoEm.Valid {
 			o.NotificadoEm = &notificadoEm.Time
@@ -137,6 +158,7 @@ func (r *OccurrenceRepository) List(ctx context.Context, filters models.Occurren
 			h.Endereco = &hEndereco.String
 		}
 		o.Hospital = &h
+		setOccurrenceAssignee(&o, assignedUserID, assignedUserNome)
 
 		occurrences = append(occurrences, o)
 	}
@@ -148,18 +170,50 @@ func (r *OccurrenceRepository) List(ctx context.Context, filters models.Occurren
 	return occurrences, totalItems, nil
 }
 
+// occurrenceSearchClause builds the name search condition of List, matching the search as a prefix of
+// any word of the masked name, and of the full name in dados_completos when fullName is set.
+// Limitation: the full name is only searchable in plaintext dados_completos. Rows written while
+// occurrence_data_encryption is enabled store the name as ciphertext, so they only match by the
+// prefix kept visible in the masked name; a blind index is not kept because it would have to be
+// rebuilt on every key rotation.
+func occurrenceSearchClause(search string, fullName bool, argIndex int) (string, string) {
+	pattern := EscapeLikePattern(strings.TrimSpace(search)) + "%"
+
+	columns := []string{"o.nome_paciente_mascarado"}
+	if fullName {
+		columns = append(columns, "o.dados_completos->>'nome_paciente'")
+	}
+
+	conditions := make([]string, 0, len(columns)*2)
+	for _, column := range columns {
+		conditions = append(conditions,
+			fmt.Sprintf("%s ILIKE $%d", column, argIndex),
+			fmt.Sprintf("%s ILIKE ('%% ' || $%d)", column, argIndex),
+		)
+	}
+
+	return " AND (" + strings.Join(conditions, " OR ") + ")", pattern
+}
+
+// EscapeLikePattern escapes the LIKE wildcards of a user-provided value
+func EscapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
 // GetByID retrieves an occurrence by ID with full data for the current tenant
 func (r *OccurrenceRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Occurrence, error) {
 	tf := NewTenantFilter(ctx)
 
 	query := `
 		SELECT
-			o.id, o.obito_id, o.hospital_id, o.status, o.score_priorizacao,
+			o.id, o.obito_id, o.hospital_id, o.tenant_id, o.status, o.score_priorizacao,
 			o.nome_paciente_mascarado, o.dados_completos, o.created_at, o.updated_at,
 			o.notificado_em, o.data_obito, o.janela_expira_em,
-			h.id, h.nome, h.codigo, h.endereco, h.ativo
+			h.id, h.nome, h.codigo, h.endereco, h.ativo,
+			o.assigned_user_id, au.nome, o.flags
 		FROM occurrences o
 		LEFT JOIN hospitals h ON o.hospital_id = h.id
+		LEFT JOIN users au ON o.assigned_user_id = au.id
 		WHERE o.id = $1` + tf.AndClauseWithAlias("o") + `
 	`
 
@@ -168,12 +222,14 @@ func (r *OccurrenceRepository) GetByID(ctx context.Context, id uuid.UUID) (*mode
 	var notificadoEm sql.NullTime
 	var dadosCompletos string
 	var hEndereco sql.NullString
+	var assignedUserID, assignedUserNome sql.NullString
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&o.ID, &o.ObitoID, &o.HospitalID, &o.Status, &o.ScorePriorizacao,
+		&o.ID, &o.ObitoID, &o.HospitalID, &o.TenantID, &o.Status, &o.ScorePriorizacao,
 		&o.NomePacienteMascarado, &dadosCompletos, &o.CreatedAt, &o.UpdatedAt,
 		&notificadoEm, &o.DataObito, &o.JanelaExpiraEm,
 		&h.ID, &h.Nome, &h.Codigo, &hEndereco, &h.Ativo,
+		&assignedUserID, &assignedUserNome, pq.Array(&o.Flags),
 	)
 
 	if err != nil {
@@ -183,7 +239,10 @@ func (r *OccurrenceRepository) GetByID(ctx context.Context, id uuid.UUID) (*mode
 		return nil, err
 	}
 
-	o.DadosCompletos = json.RawMessage(dadosCompletos)
+	o.DadosCompletos, err = OpenOccurrenceData(json.RawMessage(dadosCompletos))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dados_completos of occurrence %s: %w", o.ID, err)
+	}
 
 	if notificadoEm.Valid {
 		o.NotificadoEm = &notificadoEm.Time
@@ -193,10 +252,24 @@ func (r *OccurrenceRepository) GetByID(ctx context.Context, id uuid.UUID) (*mode
 		h.Endereco = &hEndereco.String
 	}
 	o.Hospital = &h
+	setOccurrenceAssignee(&o, assignedUserID, assignedUserNome)
 
 	return &o, nil
 }
 
+// setOccurrenceAssignee fills the assignee of an occurrence from the nullable assigned_user_id/users.nome columns
+func setOccurrenceAssignee(o *models.Occurrence, userID, nome sql.NullString) {
+	if !userID.Valid {
+		return
+	}
+	uid, err := uuid.Parse(userID.String)
+	if err != nil {
+		return
+	}
+	o.AssignedUserID = &uid
+	o.AssignedUser = &models.OccurrenceAssignee{ID: uid, Nome: nome.String}
+}
+
 // UpdateStatus updates the status of an occurrence for the current tenant
 func (r *OccurrenceRepository) UpdateStatus(ctx context.Context, id uuid.UUID, newStatus models.OccurrenceStatus) error {
 	tf := NewTenantFilter(ctx)
@@ -223,7 +296,34 @@ func (r *OccurrenceRepository) UpdateStatus(ctx context.Context, id uuid.UUID, n
 	return nil
 }
 
-// Create creates a new occurrence
+// Assign sets the operator responsible for an occurrence for the current tenant
+func (r *OccurrenceRepository) Assign(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	tf := NewTenantFilter(ctx)
+
+	query := `
+		UPDATE occurrences
+		SET assigned_user_id = $1, updated_at = $2
+		WHERE id = $3` + tf.AndClause() + `
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrOccurrenceNotFound
+	}
+
+	return nil
+}
+
+// Create creates a new occurrence together with its occurrence.created outbox event, in one transaction,
+// so the notification of the occurrence cannot be lost if the process stops right after the insert
 func (r *OccurrenceRepository) Create(ctx context.Context, input *models.CreateOccurrenceInput) (*models.Occurrence, error) {
 	occurrence := &models.Occurrence{
 		ID:                    uuid.New(),
@@ -235,36 +335,69 @@ func (r *OccurrenceRepository) Create(ctx context.Context, input *models.CreateO
 		DadosCompletos:        input.DadosCompletos,
 		DataObito:             input.DataObito,
 		JanelaExpiraEm:        input.DataObito.Add(6 * time.Hour),
+		Flags:                 input.Flags,
 		CreatedAt:             time.Now(),
 		UpdatedAt:             time.Now(),
 	}
 
+	// Patient data is encrypted at rest when occurrence_data_encryption is enabled
+	storedDados, err := SealOccurrenceData(occurrence.DadosCompletos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt dados_completos: %w", err)
+	}
+
+	// flags is NOT NULL, an occurrence without flag rules stores an empty array
+	flags := occurrence.Flags
+	if flags == nil {
+		flags = []string{}
+	}
+
 	query := `
 		INSERT INTO occurrences (
 			id, obito_id, hospital_id, status, score_priorizacao,
 			nome_paciente_mascarado, dados_completos, data_obito, janela_expira_em,
-			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			created_at, updated_at, flags
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	event, err := models.NewOccurrenceCreatedEvent(occurrence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build occurrence event: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query,
 		occurrence.ID,
 		occurrence.ObitoID,
 		occurrence.HospitalID,
 		occurrence.Status,
 		occurrence.ScorePriorizacao,
 		occurrence.NomePacienteMascarado,
-		string(occurrence.DadosCompletos),
+		string(storedDados),
 		occurrence.DataObito,
 		occurrence.JanelaExpiraEm,
 		occurrence.CreatedAt,
 		occurrence.UpdatedAt,
+		pq.Array(flags),
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit occurrence: %w", err)
+	}
+
 	return occurrence, nil
 }
 
@@ -335,6 +468,135 @@ func (r *OccurrenceRepository) GetAverageNotificationTime(ctx context.Context) (
 	return avgTime, err
 }
 
+// GetPeriodComparison returns the period-based dashboard metrics of the current period (currentStart to now)
+// and of the previous one over the same elapsed time (previousStart to previousStart + (now - currentStart))
+// for the current tenant
+func (r *OccurrenceRepository) GetPeriodComparison(ctx context.Context, currentStart, previousStart, now time.Time) (models.PeriodMetrics, models.PeriodMetrics, error) {
+	tf := NewTenantFilter(ctx)
+	previousEnd := previousStart.Add(now.Sub(currentStart))
+
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE created_at >= $1 AND created_at < $2),
+			COALESCE(AVG(EXTRACT(EPOCH FROM (notificado_em - created_at)))
+				FILTER (WHERE notificado_em IS NOT NULL AND created_at >= $1 AND created_at < $2), 0),
+			COUNT(*) FILTER (WHERE created_at >= $3 AND created_at < $4),
+			COALESCE(AVG(EXTRACT(EPOCH FROM (notificado_em - created_at)))
+				FILTER (WHERE notificado_em IS NOT NULL AND created_at >= $3 AND created_at < $4), 0)
+		FROM occurrences
+		WHERE created_at >= $3 AND created_at < $2` + tf.AndClause() + `
+	`
+
+	var current, previous models.PeriodMetrics
+	err := r.db.QueryRowContext(ctx, query, currentStart, now, previousStart, previousEnd).Scan(
+		&current.ObitosElegiveis, &current.TempoMedioNotificacao,
+		&previous.ObitosElegiveis, &previous.TempoMedioNotificacao,
+	)
+	if err != nil {
+		return current, previous, err
+	}
+
+	// Potential corneas are the eligible deaths * 2, as in the dashboard metrics
+	current.CorneasPotenciais = current.ObitosElegiveis * 2
+	previous.CorneasPotenciais = previous.ObitosElegiveis * 2
+
+	return current, previous, nil
+}
+
+// ListPendingNearExpiry returns unassigned PENDENTE occurrences whose capture window ends after now and no later
+// than deadline, soonest first (tenant-independent for the SLA escalation monitor). An occurrence assigned to an
+// operator already has an owner and is not escalated. Rows whose dados_completos cannot be decrypted are logged
+// and skipped, so one bad row does not stop the escalation of the others. Each occurrence carries its tenant,
+// which scopes its escalation event.
+func (r *OccurrenceRepository) ListPendingNearExpiry(ctx context.Context, now, deadline time.Time) ([]models.Occurrence, error) {
+	query := `
+		SELECT
+			o.id, o.hospital_id, o.tenant_id, o.status, o.score_priorizacao, o.dados_completos,
+			o.data_obito, o.janela_expira_em, o.assigned_user_id, h.nome, h.escalation_email
+		FROM occurrences o
+		LEFT JOIN hospitals h ON o.hospital_id = h.id
+		WHERE o.status = $1 AND o.janela_expira_em > $2 AND o.janela_expira_em <= $3
+			AND o.assigned_user_id IS NULL
+		ORDER BY o.janela_expira_em
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, models.StatusPendente, now, deadline)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var occurrences []models.Occurrence
+	for rows.Next() {
+		var o models.Occurrence
+		var dadosCompletos string
+		var assignedUserID, hospitalNome, escalationEmail sql.NullString
+
+		err := rows.Scan(
+			&o.ID, &o.HospitalID, &o.TenantID, &o.Status, &o.ScorePriorizacao, &dadosCompletos,
+			&o.DataObito, &o.JanelaExpiraEm, &assignedUserID, &hospitalNome, &escalationEmail,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		o.DadosCompletos, err = OpenOccurrenceData(json.RawMessage(dadosCompletos))
+		if err != nil {
+			log.Printf("[SLA] Skipping occurrence %s: failed to read dados_completos: %v", o.ID, err)
+			continue
+		}
+
+		if uid, err := uuid.Parse(assignedUserID.String); assignedUserID.Valid && err == nil {
+			o.AssignedUserID = &uid
+		}
+		o.Hospital = &models.Hospital{ID: o.HospitalID, Nome: hospitalNome.String}
+		if escalationEmail.Valid {
+			o.Hospital.EscalationEmail = &escalationEmail.String
+		}
+
+		occurrences = append(occurrences, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return occurrences, nil
+}
+
+// ExpireOverdue moves every PENDENTE occurrence whose capture window ended at or before now to EXPIRADA
+// and returns the expired IDs (tenant-independent for the expiry job). The status check is part of the
+// UPDATE, so an occurrence accepted concurrently is never expired.
+func (r *OccurrenceRepository) ExpireOverdue(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	query := `
+		UPDATE occurrences
+		SET status = $1, updated_at = $2
+		WHERE status = $3 AND janela_expira_em <= $2
+		RETURNING id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, models.StatusExpirada, now, models.StatusPendente)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
 // ExistsByObitoID checks if an occurrence already exists for a given obito (tenant-independent for triagem motor)
 func (r *OccurrenceRepository) ExistsByObitoID(ctx context.Context, obitoID uuid.UUID) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM occurrences WHERE obito_id = $1)`
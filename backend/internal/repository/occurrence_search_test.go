@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+)
+
+// TestOccurrenceSearchClauseMaskedOnly verifies that the operador scope only searches the masked name
+func TestOccurrenceSearchClauseMaskedOnly(t *testing.T) {
+	clause, pattern := occurrenceSearchClause("Ma", false, 3)
+
+	if !strings.Contains(clause, "o.nome_paciente_mascarado ILIKE $3") {
+		t.Errorf("Expected the masked name to be searched, got %q", clause)
+	}
+	if strings.Contains(clause, "dados_completos") {
+		t.Errorf("Expected no full name search for the masked scope, got %q", clause)
+	}
+	if pattern != "Ma%" {
+		t.Errorf("Expected prefix pattern %q, got %q", "Ma%", pattern)
+	}
+}
+
+// TestOccurrenceSearchClauseFullName verifies that the gestor/admin scope also searches the full name
+func TestOccurrenceSearchClauseFullName(t *testing.T) {
+	clause, _ := occurrenceSearchClause("Maria", true, 1)
+
+	if !strings.Contains(clause, "o.nome_paciente_mascarado ILIKE $1") {
+		t.Errorf("Expected the masked name to be searched, got %q", clause)
+	}
+	if !strings.Contains(clause, "o.dados_completos->>'nome_paciente' ILIKE $1") {
+		t.Errorf("Expected the full name to be searched, got %q", clause)
+	}
+	if !strings.HasPrefix(clause, " AND (") || !strings.HasSuffix(clause, ")") {
+		t.Errorf("Expected the conditions grouped in a single AND, got %q", clause)
+	}
+}
+
+// TestOccurrenceSearchClauseEscapesWildcards verifies that user input cannot inject LIKE wildcards
+func TestOccurrenceSearchClauseEscapesWildcards(t *testing.T) {
+	_, pattern := occurrenceSearchClause("  50%_a\\ ", false, 1)
+
+	if pattern != `50\%\_a\\%` {
+		t.Errorf("Expected trimmed and escaped pattern, got %q", pattern)
+	}
+}
+
+// TestOccurrenceListFullNameSearchOverEncryptedRows verifies that the full name search matches plaintext
+// dados_completos only, while encrypted rows are still found by their masked name
+func TestOccurrenceListFullNameSearchOverEncryptedRows(t *testing.T) {
+	db := openTestDB(t)
+	useOccurrenceDataEncryption(t, "0123456789abcdef0123456789abcdef", true)
+
+	tenantID := insertTestTenant(t, db)
+	hospitalID := insertTestHospital(t, db, tenantID, "BUSCA"+uuid.New().String()[:8])
+
+	seed := func(nome, mascarado string, encrypted bool) uuid.UUID {
+		id := insertTestOccurrence(t, db, tenantID, hospitalID, models.StatusPendente, time.Now(), false, false)
+		dados := []byte(`{"nome_paciente":"` + nome + `"}`)
+		if encrypted {
+			sealed, err := SealOccurrenceData(dados)
+			if err != nil {
+				t.Fatalf("SealOccurrenceData returned error: %v", err)
+			}
+			dados = sealed
+		}
+		if _, err := db.Exec(`UPDATE occurrences SET dados_completos = $1, nome_paciente_mascarado = $2 WHERE id = $3`, string(dados), mascarado, id); err != nil {
+			t.Fatalf("Failed to set patient name: %v", err)
+		}
+		return id
+	}
+	plaintextID := seed("Maria Aparecida Souza", "Ma*** Ap****** So***", false)
+	encryptedID := seed("Joao Aparecido Lima", "Jo** Ap******* Li**", true)
+
+	repo := NewOccurrenceRepository(db)
+	ctx := middleware.WithTenantContext(context.Background(), tenantID.String(), false)
+	search := func(q string) map[uuid.UUID]bool {
+		t.Helper()
+		filters := models.DefaultFilters()
+		filters.Search = &q
+		filters.SearchFullName = true
+		occurrences, _, err := repo.List(ctx, filters)
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		found := make(map[uuid.UUID]bool, len(occurrences))
+		for _, o := range occurrences {
+			found[o.ID] = true
+		}
+		return found
+	}
+
+	if found := search("Aparecid"); !found[plaintextID] || found[encryptedID] {
+		t.Errorf("Expected the full name search to match the plaintext row only, got %v", found)
+	}
+	if found := search("Ap"); !found[plaintextID] || !found[encryptedID] {
+		t.Errorf("Expected the masked name prefix to match both rows, got %v", found)
+	}
+}
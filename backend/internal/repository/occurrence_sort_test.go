@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+)
+
+// TestOccurrenceOrderByAllowlist tests that only allowlisted columns reach the ORDER BY clause
+func TestOccurrenceOrderByAllowlist(t *testing.T) {
+	tests := []struct {
+		name      string
+		sortBy    string
+		sortOrder string
+		expected  string
+	}{
+		{"default", "", "", "o.score_priorizacao DESC, o.created_at DESC, o.id"},
+		{"ascending", "janela_expira_em", "asc", "o.janela_expira_em ASC, o.created_at DESC, o.id"},
+		{"unknown order", "data_obito", "sideways", "o.data_obito DESC, o.created_at DESC, o.id"},
+		{"injection", "created_at; DROP TABLE occurrences", "asc", "o.score_priorizacao ASC, o.created_at DESC, o.id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := occurrenceOrderBy(tt.sortBy, tt.sortOrder); got != tt.expected {
+				t.Errorf("occurrenceOrderBy(%q, %q) = %q, expected %q", tt.sortBy, tt.sortOrder, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestOccurrenceListSortOptions tests that each sort option of the occurrence list returns the expected order
+func TestOccurrenceListSortOptions(t *testing.T) {
+	db := openTestDB(t)
+
+	tenantID := insertTestTenant(t, db)
+	hospitalID := insertTestHospital(t, db, tenantID, "SORT-"+uuid.New().String()[:8])
+
+	var now time.Time
+	if err := db.QueryRow(`SELECT NOW()`).Scan(&now); err != nil {
+		t.Fatalf("Failed to read database clock: %v", err)
+	}
+
+	// Each column orders the occurrences differently
+	fixtures := []struct {
+		score     int
+		createdAt time.Duration
+		dataObito time.Duration
+		janela    time.Duration
+	}{
+		{90, -3 * time.Hour, -1 * time.Hour, 3 * time.Hour},
+		{50, -1 * time.Hour, -2 * time.Hour, 5 * time.Hour},
+		{70, -2 * time.Hour, -3 * time.Hour, 1 * time.Hour},
+	}
+	ids := make([]uuid.UUID, len(fixtures))
+	for i, f := range fixtures {
+		ids[i] = insertTestOccurrence(t, db, tenantID, hospitalID, models.StatusPendente, now.Add(f.createdAt), false, false)
+		_, err := db.Exec(`
+			UPDATE occurrences SET score_priorizacao = $2, data_obito = $3, janela_expira_em = $4 WHERE id = $1
+		`, ids[i], f.score, now.Add(f.dataObito), now.Add(f.janela))
+		if err != nil {
+			t.Fatalf("Failed to update occurrence: %v", err)
+		}
+	}
+
+	repo := NewOccurrenceRepository(db)
+	ctx := middleware.WithTenantContext(context.Background(), tenantID.String(), false)
+	hospital := hospitalID.String()
+
+	tests := []struct {
+		sort     string
+		expected []int // Fixtures in descending order
+	}{
+		{"score", []int{0, 2, 1}},
+		{"created_at", []int{1, 2, 0}},
+		{"data_obito", []int{0, 1, 2}},
+		{"tempo_restante", []int{1, 0, 2}},
+	}
+
+	for _, tt := range tests {
+		for _, order := range []string{"desc", "asc"} {
+			t.Run(tt.sort+" "+order, func(t *testing.T) {
+				filters := models.DefaultFilters()
+				filters.HospitalID = &hospital
+				filters.SortBy = models.OccurrenceSortFields[tt.sort]
+				filters.SortOrder = order
+
+				occurrences, _, err := repo.List(ctx, filters)
+				if err != nil {
+					t.Fatalf("List returned error: %v", err)
+				}
+				if len(occurrences) != len(ids) {
+					t.Fatalf("Expected %d occurrences, got %d", len(ids), len(occurrences))
+				}
+
+				for i := range occurrences {
+					position := i
+					if order == "asc" {
+						position = len(ids) - 1 - i
+					}
+					if expected := ids[tt.expected[position]]; occurrences[i].ID != expected {
+						t.Errorf("Position %d: expected occurrence %s, got %s", i, expected, occurrences[i].ID)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "database/sql"
+
+// ReportingDB returns the handle of the reporting and metrics read queries: the read replica
+// when one is configured, the primary otherwise. Writes always go to the primary.
+func ReportingDB(primary, replica *sql.DB) *sql.DB {
+	if replica != nil {
+		return replica
+	}
+	return primary
+}
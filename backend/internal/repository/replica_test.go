@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+)
+
+// TestReportingDBUsesReplica tests that the reporting repositories get the replica handle when one is configured
+func TestReportingDBUsesReplica(t *testing.T) {
+	primary, replica := &sql.DB{}, &sql.DB{}
+
+	if repo := NewIndicatorsRepository(ReportingDB(primary, replica)); repo.db != replica {
+		t.Error("Expected the indicators repository to use the replica")
+	}
+	if repo := NewIndicatorsRepository(ReportingDB(primary, nil)); repo.db != primary {
+		t.Error("Expected the indicators repository to fall back to the primary")
+	}
+}
@@ -37,6 +37,10 @@ func (r *ShiftRepository) Create(ctx context.Context, input *models.CreateShiftI
 		UpdatedAt:  time.Now(),
 	}
 
+	if err := r.checkOverlap(ctx, shift); err != nil {
+		return nil, err
+	}
+
 	query := `
 		INSERT INTO shifts (id, hospital_id, user_id, day_of_week, start_time, end_time, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
@@ -122,6 +126,10 @@ func (r *ShiftRepository) Update(ctx context.Context, id uuid.UUID, input *model
 	}
 	shift.UpdatedAt = time.Now()
 
+	if err := r.checkOverlap(ctx, shift); err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE shifts
 		SET user_id = $1, day_of_week = $2, start_time = $3, end_time = $4, updated_at = $5
@@ -218,8 +226,17 @@ func (r *ShiftRepository) ListByHospitalID(ctx context.Context, hospitalID uuid.
 	return shifts, nil
 }
 
+// shiftQueryer runs shift queries on the database or inside a transaction
+type shiftQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
 // GetShiftsByUserID retrieves all shifts for a user
 func (r *ShiftRepository) GetShiftsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Shift, error) {
+	return getShiftsByUserID(ctx, r.db, userID)
+}
+
+func getShiftsByUserID(ctx context.Context, q shiftQueryer, userID uuid.UUID) ([]models.Shift, error) {
 	query := `
 		SELECT
 			s.id, s.hospital_id, s.user_id, s.day_of_week,
@@ -229,7 +246,7 @@ func (r *ShiftRepository) GetShiftsByUserID(ctx context.Context, userID uuid.UUI
 		ORDER BY s.day_of_week, s.start_time
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	rows, err := q.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -255,7 +272,172 @@ func (r *ShiftRepository) GetShiftsByUserID(ctx context.Context, userID uuid.UUI
 	return shifts, nil
 }
 
+// CreateBatch creates shifts in one transaction, skipping each shift that is invalid or overlaps
+// another shift of its operator, either existing or earlier in the batch. The returned slices are
+// parallel to inputs: the created shift, or the error it was skipped for.
+func (r *ShiftRepository) CreateBatch(ctx context.Context, inputs []models.CreateShiftInput) ([]*models.Shift, []error, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	created, rowErrs, err := createShiftsTx(ctx, tx, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	return created, rowErrs, nil
+}
+
+// CreateAll creates every shift in one transaction, or none when any of them is invalid or
+// overlaps another shift of its operator; the error of the first such shift is returned
+func (r *ShiftRepository) CreateAll(ctx context.Context, inputs []models.CreateShiftInput) ([]*models.Shift, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	created, rowErrs, err := createShiftsTx(ctx, tx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	for _, rowErr := range rowErrs {
+		if rowErr != nil {
+			return nil, rowErr
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// createShiftsTx inserts the shifts that are valid and do not overlap, returning per-input results
+func createShiftsTx(ctx context.Context, tx *sql.Tx, inputs []models.CreateShiftInput) ([]*models.Shift, []error, error) {
+	created := make([]*models.Shift, len(inputs))
+	rowErrs := make([]error, len(inputs))
+	var err error
+
+	// Shifts of each operator, including the ones created by this batch
+	byUser := make(map[uuid.UUID][]models.Shift)
+
+	for i := range inputs {
+		input := &inputs[i]
+		if err := input.Validate(); err != nil {
+			rowErrs[i] = err
+			continue
+		}
+
+		existing, loaded := byUser[input.UserID]
+		if !loaded {
+			existing, err = getShiftsByUserID(ctx, tx, input.UserID)
+			if err != nil {
+				return nil, nil, err
+			}
+			byUser[input.UserID] = existing
+		}
+
+		now := time.Now()
+		shift := &models.Shift{
+			ID:         uuid.New(),
+			HospitalID: input.HospitalID,
+			UserID:     input.UserID,
+			DayOfWeek:  input.DayOfWeek,
+			StartTime:  input.StartTime,
+			EndTime:    input.EndTime,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if conflict := findOverlappingShift(shift, existing); conflict != nil {
+			rowErrs[i] = &models.ShiftOverlapError{Existing: *conflict}
+			continue
+		}
+
+		// A failed insert only rolls back to the savepoint, keeping the rest of the batch
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT shift_batch`); err != nil {
+			return nil, nil, err
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO shifts (id, hospital_id, user_id, day_of_week, start_time, end_time, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, shift.ID, shift.HospitalID, shift.UserID, shift.DayOfWeek, shift.StartTime, shift.EndTime, shift.CreatedAt, shift.UpdatedAt)
+		if err != nil {
+			if _, rollbackErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT shift_batch`); rollbackErr != nil {
+				return nil, nil, rollbackErr
+			}
+			if isUniqueViolation(err) {
+				err = models.ErrShiftExists
+			}
+			rowErrs[i] = err
+			continue
+		}
+
+		byUser[input.UserID] = append(existing, *shift)
+		created[i] = shift
+	}
+
+	return created, rowErrs, nil
+}
+
+// checkOverlap rejects a shift that overlaps another shift of the same operator
+// Returns a *models.ShiftOverlapError describing the conflicting shift
+func (r *ShiftRepository) checkOverlap(ctx context.Context, shift *models.Shift) error {
+	existing, err := r.GetShiftsByUserID(ctx, shift.UserID)
+	if err != nil {
+		return err
+	}
+
+	if conflict := findOverlappingShift(shift, existing); conflict != nil {
+		return &models.ShiftOverlapError{Existing: *conflict}
+	}
+
+	return nil
+}
+
+// findOverlappingShift returns the first existing shift (other than the shift itself) that overlaps it
+func findOverlappingShift(shift *models.Shift, existing []models.Shift) *models.Shift {
+	for i := range existing {
+		if existing[i].ID == shift.ID {
+			continue
+		}
+		if shift.Overlaps(&existing[i]) {
+			return &existing[i]
+		}
+	}
+	return nil
+}
+
+// HospitalLocation returns the timezone the shifts of the hospital are expressed in
+func (r *ShiftRepository) HospitalLocation(ctx context.Context, hospitalID uuid.UUID) (*time.Location, error) {
+	var timezone sql.NullString
+	err := r.db.QueryRowContext(ctx, `SELECT timezone FROM hospitals WHERE id = $1`, hospitalID).Scan(&timezone)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if !timezone.Valid {
+		return models.DefaultLocation(), nil
+	}
+	return models.HospitalLocation(&timezone.String), nil
+}
+
+// GetActiveShiftsAt retrieves the operators on duty at an instant, evaluated in the hospital's timezone
+func (r *ShiftRepository) GetActiveShiftsAt(ctx context.Context, hospitalID uuid.UUID, at time.Time) ([]models.Shift, error) {
+	loc, err := r.HospitalLocation(ctx, hospitalID)
+	if err != nil {
+		return nil, err
+	}
+	local := at.In(loc)
+	return r.GetActiveShifts(ctx, hospitalID, int(local.Weekday()), local)
+}
+
 // GetActiveShifts retrieves operators currently on duty based on hospital, day of week, and current time
+// The day of week and time are wall-clock values in the hospital's timezone (see GetActiveShiftsAt)
 // This handles night shifts that cross midnight correctly
 func (r *ShiftRepository) GetActiveShifts(ctx context.Context, hospitalID uuid.UUID, dayOfWeek int, currentTime time.Time) ([]models.Shift, error) {
 	// For night shifts, we need to check both the current day and the previous day
@@ -324,8 +506,13 @@ func (r *ShiftRepository) GetActiveShifts(ctx context.Context, hospitalID uuid.U
 }
 
 // GetTodayShifts retrieves all shifts scheduled for today for a hospital
+// "Today" is the current day in the hospital's timezone
 func (r *ShiftRepository) GetTodayShifts(ctx context.Context, hospitalID uuid.UUID) ([]models.TodayShift, error) {
-	now := time.Now()
+	loc, err := r.HospitalLocation(ctx, hospitalID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().In(loc)
 	dayOfWeek := int(now.Weekday())
 	previousDay := dayOfWeek - 1
 	if previousDay < 0 {
@@ -435,6 +622,16 @@ func (r *ShiftRepository) GetCoverageGaps(ctx context.Context, hospitalID uuid.U
 	return analysis, nil
 }
 
+// GetWeeklySchedule lays out the shifts of a hospital on a 7-day grid of slotMinutes slots
+func (r *ShiftRepository) GetWeeklySchedule(ctx context.Context, hospitalID uuid.UUID, slotMinutes int) (*models.WeeklySchedule, error) {
+	shifts, err := r.ListByHospitalID(ctx, hospitalID)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.BuildWeeklySchedule(hospitalID, shifts, slotMinutes)
+}
+
 // filterShiftsByDay returns shifts that cover the specified day
 func filterShiftsByDay(shifts []models.Shift, day models.DayOfWeek) []models.Shift {
 	var result []models.Shift
@@ -454,6 +651,12 @@ func filterShiftsByDay(shifts []models.Shift, day models.DayOfWeek) []models.Shi
 	return result
 }
 
+// coverageSlotMinutes is the resolution of the coverage bitmap
+const coverageSlotMinutes = 15
+
+// coverageSlotsPerDay is the number of coverage slots in a day (96 at 15-minute resolution)
+const coverageSlotsPerDay = 24 * 60 / coverageSlotMinutes
+
 // findGapsForDay finds gaps in coverage for a single day
 func findGapsForDay(shifts []models.Shift) []models.CoverageGap {
 	if len(shifts) == 0 {
@@ -463,8 +666,8 @@ func findGapsForDay(shifts []models.Shift) []models.CoverageGap {
 		}}
 	}
 
-	// Create a 24-hour coverage bitmap (resolution: 1 hour)
-	covered := make([]bool, 24)
+	// Create a coverage bitmap (resolution: 15 minutes)
+	covered := make([]bool, coverageSlotsPerDay)
 
 	for _, s := range shifts {
 		markCoverage(covered, s)
@@ -475,17 +678,17 @@ func findGapsForDay(shifts []models.Shift) []models.CoverageGap {
 	inGap := false
 	gapStart := 0
 
-	for hour := 0; hour < 24; hour++ {
-		if !covered[hour] {
+	for slot := 0; slot < coverageSlotsPerDay; slot++ {
+		if !covered[slot] {
 			if !inGap {
 				inGap = true
-				gapStart = hour
+				gapStart = slot
 			}
 		} else {
 			if inGap {
 				gaps = append(gaps, models.CoverageGap{
-					StartTime: formatHour(gapStart),
-					EndTime:   formatHour(hour),
+					StartTime: formatSlot(gapStart),
+					EndTime:   formatSlot(slot),
 				})
 				inGap = false
 			}
@@ -495,7 +698,7 @@ func findGapsForDay(shifts []models.Shift) []models.CoverageGap {
 	// Handle gap that extends to end of day
 	if inGap {
 		gaps = append(gaps, models.CoverageGap{
-			StartTime: formatHour(gapStart),
+			StartTime: formatSlot(gapStart),
 			EndTime:   "23:59",
 		})
 	}
@@ -503,33 +706,43 @@ func findGapsForDay(shifts []models.Shift) []models.CoverageGap {
 	return gaps
 }
 
-// markCoverage marks hours covered by a shift
+// markCoverage marks slots fully covered by a shift
+// The start is rounded up and the end rounded down to the slot boundary, so a partially covered slot stays a gap.
+// An end at 23:59 stands for the end of the day and covers the last slot
 func markCoverage(covered []bool, s models.Shift) {
-	startHour := s.StartTime.Hour()
-	endHour := s.EndTime.Hour()
+	startMinutes := s.StartTime.Hour()*60 + s.StartTime.Minute()
+	endMinutes := s.EndTime.Hour()*60 + s.EndTime.Minute()
+	if endMinutes == 24*60-1 {
+		endMinutes = 24 * 60
+	}
+	startSlot := (startMinutes + coverageSlotMinutes - 1) / coverageSlotMinutes
+	endSlot := endMinutes / coverageSlotMinutes
 
 	if s.IsNightShift() {
 		// Night shift: cover from start to midnight, then midnight to end
-		for h := startHour; h < 24; h++ {
-			covered[h] = true
+		for slot := startSlot; slot < coverageSlotsPerDay; slot++ {
+			covered[slot] = true
 		}
-		for h := 0; h < endHour; h++ {
-			covered[h] = true
+		for slot := 0; slot < endSlot; slot++ {
+			covered[slot] = true
 		}
 	} else {
 		// Day shift: cover from start to end
-		for h := startHour; h < endHour; h++ {
-			covered[h] = true
+		for slot := startSlot; slot < endSlot; slot++ {
+			covered[slot] = true
 		}
 	}
 }
 
-// formatHour formats an hour as HH:00
-func formatHour(hour int) string {
-	if hour < 10 {
-		return "0" + string(rune('0'+hour)) + ":00"
-	}
-	return string(rune('0'+hour/10)) + string(rune('0'+hour%10)) + ":00"
+// formatSlot formats the start boundary of a coverage slot as HH:MM
+func formatSlot(slot int) string {
+	minutes := slot * coverageSlotMinutes
+	return twoDigits(minutes/60) + ":" + twoDigits(minutes%60)
+}
+
+// twoDigits formats a number between 0 and 99 with a leading zero
+func twoDigits(n int) string {
+	return string(rune('0'+n/10)) + string(rune('0'+n%10))
 }
 
 // isUniqueViolation checks if the error is a unique constraint violation
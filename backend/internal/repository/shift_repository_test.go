@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -183,6 +184,70 @@ func TestCoverageGapDetection(t *testing.T) {
 			t.Errorf("Expected 3 gaps, got %d", len(gaps))
 		}
 	})
+
+	// Test case: Half-hour shift leaves the rest of the day uncovered from 07:30
+	t.Run("Shift ending on the half hour", func(t *testing.T) {
+		shifts := []models.Shift{
+			{StartTime: "00:00", EndTime: "07:00"},
+			{StartTime: "07:00", EndTime: "07:30"},
+		}
+		gaps := findGapsForDay(shifts)
+		if len(gaps) != 1 {
+			t.Fatalf("Expected 1 gap, got %d", len(gaps))
+		}
+		if gaps[0].StartTime != "07:30" || gaps[0].EndTime != "23:59" {
+			t.Errorf("Expected gap 07:30-23:59, got %s-%s", gaps[0].StartTime, gaps[0].EndTime)
+		}
+	})
+
+	// Test case: Half-hour shifts close coverage exactly, and a 30-minute gap is reported with minute boundaries
+	t.Run("Sub-hour gap between shifts", func(t *testing.T) {
+		shifts := []models.Shift{
+			{StartTime: "07:00", EndTime: "19:30"},
+			{StartTime: "19:00", EndTime: "07:00"},
+			{StartTime: "06:30", EndTime: "07:00"},
+		}
+		if gaps := findGapsForDay(shifts); len(gaps) != 0 {
+			t.Errorf("Expected no gaps, got %d", len(gaps))
+		}
+
+		shifts = []models.Shift{
+			{StartTime: "07:30", EndTime: "19:00"},
+			{StartTime: "19:00", EndTime: "07:00"},
+		}
+		gaps := findGapsForDay(shifts)
+		if len(gaps) != 1 {
+			t.Fatalf("Expected 1 gap, got %d", len(gaps))
+		}
+		if gaps[0].StartTime != "07:00" || gaps[0].EndTime != "07:30" {
+			t.Errorf("Expected gap 07:00-07:30, got %s-%s", gaps[0].StartTime, gaps[0].EndTime)
+		}
+	})
+
+	// Test case: Slots only partially covered by a shift are still reported as gaps
+	t.Run("Partially covered slots", func(t *testing.T) {
+		shifts := []models.Shift{
+			{StartTime: "07:10", EndTime: "19:00"},
+			{StartTime: "19:00", EndTime: "06:50"},
+		}
+		gaps := findGapsForDay(shifts)
+		if len(gaps) != 1 {
+			t.Fatalf("Expected 1 gap, got %d: %v", len(gaps), gaps)
+		}
+		if gaps[0].StartTime != "06:45" || gaps[0].EndTime != "07:15" {
+			t.Errorf("Expected gap 06:45-07:15, got %s-%s", gaps[0].StartTime, gaps[0].EndTime)
+		}
+	})
+
+	// Test case: Shift ending at 23:59 covers the last slot of the day
+	t.Run("Shift ending at end of day", func(t *testing.T) {
+		shifts := []models.Shift{
+			{StartTime: "00:00", EndTime: "23:59"},
+		}
+		if gaps := findGapsForDay(shifts); len(gaps) != 0 {
+			t.Errorf("Expected no gaps, got %d: %v", len(gaps), gaps)
+		}
+	})
 }
 
 // TestFilterShiftsByDay tests the filtering logic for shifts by day
@@ -218,24 +283,74 @@ func TestFilterShiftsByDay(t *testing.T) {
 	}
 }
 
-// TestFormatHour tests the hour formatting utility
-func TestFormatHour(t *testing.T) {
+// TestFormatSlot tests the coverage slot formatting utility
+func TestFormatSlot(t *testing.T) {
 	tests := []struct {
-		hour     int
+		slot     int
 		expected string
 	}{
 		{0, "00:00"},
-		{7, "07:00"},
-		{12, "12:00"},
-		{19, "19:00"},
-		{23, "23:00"},
+		{1, "00:15"},
+		{28, "07:00"},
+		{30, "07:30"},
+		{48, "12:00"},
+		{76, "19:00"},
+		{95, "23:45"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.expected, func(t *testing.T) {
-			if got := formatHour(tt.hour); got != tt.expected {
-				t.Errorf("formatHour(%d) = %s, expected %s", tt.hour, got, tt.expected)
+			if got := formatSlot(tt.slot); got != tt.expected {
+				t.Errorf("formatSlot(%d) = %s, expected %s", tt.slot, got, tt.expected)
 			}
 		})
 	}
 }
+
+// TestFindOverlappingShift tests the overlap check used on create and update
+func TestFindOverlappingShift(t *testing.T) {
+	userID := uuid.New()
+	existing := []models.Shift{
+		{ID: uuid.New(), UserID: userID, DayOfWeek: models.Monday, StartTime: "07:00", EndTime: "19:00"},
+		{ID: uuid.New(), UserID: userID, DayOfWeek: models.Wednesday, StartTime: "19:00", EndTime: "07:00"},
+	}
+
+	t.Run("Day shift overlap", func(t *testing.T) {
+		candidate := &models.Shift{ID: uuid.New(), UserID: userID, DayOfWeek: models.Monday, StartTime: "18:00", EndTime: "22:00"}
+		conflict := findOverlappingShift(candidate, existing)
+		if conflict == nil || conflict.ID != existing[0].ID {
+			t.Fatalf("Expected overlap with Monday day shift, got %v", conflict)
+		}
+
+		err := &models.ShiftOverlapError{Existing: *conflict}
+		if !errors.Is(err, models.ErrShiftOverlap) {
+			t.Error("Expected ShiftOverlapError to match ErrShiftOverlap")
+		}
+		if err.Error() != "shift overlaps existing shift on Segunda-feira from 07:00 to 19:00" {
+			t.Errorf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Night shift overlapping next day morning", func(t *testing.T) {
+		candidate := &models.Shift{ID: uuid.New(), UserID: userID, DayOfWeek: models.Thursday, StartTime: "06:00", EndTime: "10:00"}
+		conflict := findOverlappingShift(candidate, existing)
+		if conflict == nil || conflict.ID != existing[1].ID {
+			t.Fatalf("Expected overlap with Wednesday night shift, got %v", conflict)
+		}
+	})
+
+	t.Run("Updating a shift does not conflict with itself", func(t *testing.T) {
+		updated := existing[0]
+		updated.EndTime = "18:00"
+		if conflict := findOverlappingShift(&updated, existing); conflict != nil {
+			t.Errorf("Expected no overlap, got %v", conflict)
+		}
+	})
+
+	t.Run("No overlap", func(t *testing.T) {
+		candidate := &models.Shift{ID: uuid.New(), UserID: userID, DayOfWeek: models.Thursday, StartTime: "07:00", EndTime: "19:00"}
+		if conflict := findOverlappingShift(candidate, existing); conflict != nil {
+			t.Errorf("Expected no overlap, got %v", conflict)
+		}
+	})
+}
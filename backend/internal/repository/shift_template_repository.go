@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// ShiftTemplateRepository handles shift template data access
+type ShiftTemplateRepository struct {
+	db *sql.DB
+}
+
+// NewShiftTemplateRepository creates a new shift template repository
+func NewShiftTemplateRepository(db *sql.DB) *ShiftTemplateRepository {
+	return &ShiftTemplateRepository{db: db}
+}
+
+// shiftTemplateVisibility restricts the templates to the global ones and those of the tenant in context
+func shiftTemplateVisibility(ctx context.Context) string {
+	tf := NewTenantFilter(ctx)
+	if !tf.ShouldFilter() {
+		return ""
+	}
+	return fmt.Sprintf(" AND (tenant_id IS NULL OR tenant_id = '%s')", tf.TenantID)
+}
+
+// Create creates a shift template owned by the tenant in context
+func (r *ShiftTemplateRepository) Create(ctx context.Context, input *models.CreateShiftTemplateInput) (*models.ShiftTemplate, error) {
+	slots, err := json.Marshal(input.Slots)
+	if err != nil {
+		return nil, err
+	}
+
+	var tenantID *uuid.UUID
+	if value := GetTenantIDOrNil(ctx); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		tenantID = &id
+	}
+
+	template := &models.ShiftTemplate{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Nome:      input.Nome,
+		Descricao: input.Descricao,
+		Slots:     input.Slots,
+	}
+
+	query := `
+		INSERT INTO shift_templates (id, tenant_id, nome, descricao, slots, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+	err = r.db.QueryRowContext(ctx, query,
+		template.ID, template.TenantID, template.Nome, template.Descricao, slots,
+	).Scan(&template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// GetByID retrieves a shift template visible to the tenant in context
+func (r *ShiftTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ShiftTemplate, error) {
+	query := `
+		SELECT id, tenant_id, nome, descricao, slots, created_at, updated_at
+		FROM shift_templates
+		WHERE id = $1` + shiftTemplateVisibility(ctx)
+
+	template, err := scanShiftTemplate(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrShiftTemplateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// List returns the shift templates visible to the tenant in context, ordered by name
+func (r *ShiftTemplateRepository) List(ctx context.Context) ([]models.ShiftTemplate, error) {
+	query := `
+		SELECT id, tenant_id, nome, descricao, slots, created_at, updated_at
+		FROM shift_templates
+		WHERE 1=1` + shiftTemplateVisibility(ctx) + `
+		ORDER BY nome ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []models.ShiftTemplate{}
+	for rows.Next() {
+		template, err := scanShiftTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// shiftTemplateScanner is implemented by *sql.Row and *sql.Rows
+type shiftTemplateScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanShiftTemplate scans a shift template row
+func scanShiftTemplate(row shiftTemplateScanner) (*models.ShiftTemplate, error) {
+	var template models.ShiftTemplate
+	var tenantID, descricao sql.NullString
+	var slots []byte
+
+	if err := row.Scan(
+		&template.ID,
+		&tenantID,
+		&template.Nome,
+		&descricao,
+		&slots,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if tenantID.Valid {
+		id, err := uuid.Parse(tenantID.String)
+		if err != nil {
+			return nil, err
+		}
+		template.TenantID = &id
+	}
+	if descricao.Valid {
+		template.Descricao = &descricao.String
+	}
+	if err := json.Unmarshal(slots, &template.Slots); err != nil {
+		return nil, fmt.Errorf("invalid shift template slots: %w", err)
+	}
+
+	return &template, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DefaultStatementTimeout is the statement timeout of read queries when none is configured
+const DefaultStatementTimeout = 30 * time.Second
+
+// pqQueryCanceled is the PostgreSQL error code of a statement cancelled by statement_timeout
+const pqQueryCanceled = "57014"
+
+// ErrStatementTimeout is returned when a read query is aborted by the statement timeout
+var ErrStatementTimeout = errors.New("query exceeded the statement timeout")
+
+// statementTimeout is the statement timeout of read queries in nanoseconds (0 disables it)
+var statementTimeout atomic.Int64
+
+func init() {
+	statementTimeout.Store(int64(DefaultStatementTimeout))
+}
+
+// SetStatementTimeout sets the statement timeout applied to the read queries run with
+// withStatementTimeout (0 disables it)
+func SetStatementTimeout(timeout time.Duration) {
+	statementTimeout.Store(int64(timeout))
+}
+
+// StatementTimeout returns the statement timeout of read queries
+func StatementTimeout() time.Duration {
+	return time.Duration(statementTimeout.Load())
+}
+
+// readQueryer runs read queries on the database or inside a transaction
+type readQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// withStatementTimeout runs fn in a read-only transaction whose statements are aborted by
+// PostgreSQL after the statement timeout, so a runaway query does not hold a pooled connection.
+// fn must consume its rows before returning. A statement aborted by the timeout is reported
+// as ErrStatementTimeout.
+func withStatementTimeout(ctx context.Context, db *sql.DB, fn func(q readQueryer) error) error {
+	timeout := StatementTimeout()
+	if timeout <= 0 {
+		return fn(db)
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// SET does not take bind parameters; the value is an integer number of milliseconds, where 0 would disable it
+	ms := timeout.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", ms)); err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		// A query cancelled along with ctx is reported by its context error instead
+		if ctx.Err() == nil && isStatementTimeout(err) {
+			return fmt.Errorf("%w: %v", ErrStatementTimeout, err)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// isStatementTimeout checks if the error is a statement cancelled by statement_timeout
+func isStatementTimeout(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqQueryCanceled
+}
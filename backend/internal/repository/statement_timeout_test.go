@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// slowQueryDriver is a database/sql driver that records the statements it runs. Queries
+// containing pg_sleep fail like PostgreSQL aborting them when a statement_timeout was set
+// in the transaction, and succeed with no rows otherwise.
+type slowQueryDriver struct {
+	mu         sync.Mutex
+	statements []string
+	rollbacks  int
+}
+
+func (d *slowQueryDriver) record(statement string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, statement)
+}
+
+func (d *slowQueryDriver) Open(name string) (driver.Conn, error) {
+	return &slowQueryConn{driver: d}, nil
+}
+
+type slowQueryConn struct {
+	driver  *slowQueryDriver
+	timeout bool
+}
+
+func (c *slowQueryConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (c *slowQueryConn) Close() error              { return nil }
+func (c *slowQueryConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (c *slowQueryConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *slowQueryConn) Commit() error {
+	c.timeout = false
+	return nil
+}
+
+func (c *slowQueryConn) Rollback() error {
+	c.timeout = false
+	c.driver.mu.Lock()
+	c.driver.rollbacks++
+	c.driver.mu.Unlock()
+	return nil
+}
+
+func (c *slowQueryConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.driver.record(query)
+	if strings.HasPrefix(query, "SET LOCAL statement_timeout") {
+		c.timeout = true
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *slowQueryConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.driver.record(query)
+	if strings.Contains(query, "pg_sleep") && c.timeout {
+		return nil, &pq.Error{Code: pqQueryCanceled, Message: "canceling statement due to statement timeout"}
+	}
+	return emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return []string{"result"} }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+// openSlowQueryDB opens a database backed by a new slowQueryDriver
+func openSlowQueryDB(t *testing.T) (*sql.DB, *slowQueryDriver) {
+	t.Helper()
+
+	d := &slowQueryDriver{}
+	db := sql.OpenDB(slowQueryConnector{d})
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+type slowQueryConnector struct {
+	driver *slowQueryDriver
+}
+
+func (c slowQueryConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open("")
+}
+func (c slowQueryConnector) Driver() driver.Driver { return c.driver }
+
+// setTestStatementTimeout sets the statement timeout until the test ends
+func setTestStatementTimeout(t *testing.T, timeout time.Duration) {
+	t.Helper()
+
+	previous := StatementTimeout()
+	SetStatementTimeout(timeout)
+	t.Cleanup(func() { SetStatementTimeout(previous) })
+}
+
+// runSlowQuery runs SELECT pg_sleep(seconds) with withStatementTimeout
+func runSlowQuery(ctx context.Context, db *sql.DB, seconds string) error {
+	return withStatementTimeout(ctx, db, func(q readQueryer) error {
+		rows, err := q.QueryContext(ctx, "SELECT pg_sleep("+seconds+")")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+		}
+		return rows.Err()
+	})
+}
+
+// TestWithStatementTimeoutAbortsSlowQuery tests that the timeout is set in the transaction and an aborted statement is reported
+func TestWithStatementTimeoutAbortsSlowQuery(t *testing.T) {
+	setTestStatementTimeout(t, 100*time.Millisecond)
+	db, d := openSlowQueryDB(t)
+
+	err := runSlowQuery(context.Background(), db, "5")
+
+	if !errors.Is(err, ErrStatementTimeout) {
+		t.Fatalf("Expected ErrStatementTimeout, got %v", err)
+	}
+	if len(d.statements) != 2 || d.statements[0] != "SET LOCAL statement_timeout = 100" {
+		t.Errorf("Expected the timeout to be set before the query, got %v", d.statements)
+	}
+	if d.rollbacks != 1 {
+		t.Errorf("Expected the transaction to be rolled back, got %d rollbacks", d.rollbacks)
+	}
+}
+
+// TestWithStatementTimeoutDisabled tests that a zero timeout runs the query without a transaction
+func TestWithStatementTimeoutDisabled(t *testing.T) {
+	setTestStatementTimeout(t, 0)
+	db, d := openSlowQueryDB(t)
+
+	if err := runSlowQuery(context.Background(), db, "5"); err != nil {
+		t.Fatalf("Expected the query to run without a timeout, got %v", err)
+	}
+	if len(d.statements) != 1 || strings.HasPrefix(d.statements[0], "SET") {
+		t.Errorf("Expected only the query to run, got %v", d.statements)
+	}
+}
+
+// TestWithStatementTimeoutPostgres tests that PostgreSQL aborts a slow query after the timeout
+func TestWithStatementTimeoutPostgres(t *testing.T) {
+	db := openTestDB(t)
+	setTestStatementTimeout(t, 200*time.Millisecond)
+	ctx := context.Background()
+
+	started := time.Now()
+	err := runSlowQuery(ctx, db, "5")
+	if !errors.Is(err, ErrStatementTimeout) {
+		t.Fatalf("Expected ErrStatementTimeout, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("Expected the query to be aborted after 200ms, took %s", elapsed)
+	}
+
+	// Queries within the timeout are not affected, and the timeout does not leak to the pooled connection
+	if err := runSlowQuery(ctx, db, "0.05"); err != nil {
+		t.Errorf("Expected a query within the timeout to succeed, got %v", err)
+	}
+	var setting string
+	if err := db.QueryRowContext(ctx, "SHOW statement_timeout").Scan(&setting); err != nil {
+		t.Fatalf("Failed to read statement_timeout: %v", err)
+	}
+	if setting == "200ms" {
+		t.Error("Expected SET LOCAL to only apply to the transaction")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sidot/backend/internal/models"
 )
 
@@ -162,6 +163,26 @@ func (r *TenantRepository) GetBySlug(ctx context.Context, slug string) (*models.
 	return &tenant, nil
 }
 
+// GetAllowedOriginsBySlug returns the CORS origins of an active tenant by slug
+func (r *TenantRepository) GetAllowedOriginsBySlug(ctx context.Context, slug string) ([]string, error) {
+	query := `
+		SELECT allowed_origins
+		FROM tenants
+		WHERE slug = $1 AND COALESCE(is_active, true)
+	`
+
+	var origins []string
+	err := r.db.QueryRowContext(ctx, query, slug).Scan(pq.Array(&origins))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrTenantNotFound
+		}
+		return nil, err
+	}
+
+	return origins, nil
+}
+
 // List returns all tenants ordered by name
 func (r *TenantRepository) List(ctx context.Context) ([]models.Tenant, error) {
 	query := `
@@ -19,6 +19,10 @@ var (
 const (
 	triagemRulesCacheKey = "triagem_rules:all"
 	triagemRulesCacheTTL = 5 * time.Minute
+
+	// TriagemRulesChangedChannel is the Redis Pub/Sub channel notified whenever triagem rules change,
+	// so every instance can drop its in-memory copy of the rules
+	TriagemRulesChangedChannel = "triagem_rules:changed"
 )
 
 // TriagemRuleRepository handles triagem rule data access
@@ -194,6 +198,10 @@ func (r *TriagemRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 
 // Create creates a new triagem rule
 func (r *TriagemRuleRepository) Create(ctx context.Context, input *models.CreateTriagemRuleInput) (*models.TriagemRule, error) {
+	if _, err := models.ValidateRuleConfig(input.Regras); err != nil {
+		return nil, err
+	}
+
 	rule := &models.TriagemRule{
 		ID:         uuid.New(),
 		Nome:       input.Nome,
@@ -240,6 +248,12 @@ func (r *TriagemRuleRepository) Create(ctx context.Context, input *models.Create
 
 // Update updates a triagem rule
 func (r *TriagemRuleRepository) Update(ctx context.Context, id uuid.UUID, input *models.UpdateTriagemRuleInput) (*models.TriagemRule, error) {
+	if input.Regras != nil {
+		if _, err := models.ValidateRuleConfig(input.Regras); err != nil {
+			return nil, err
+		}
+	}
+
 	// Get existing rule
 	rule, err := r.GetByID(ctx, id)
 	if err != nil {
@@ -326,8 +340,10 @@ func (r *TriagemRuleRepository) SoftDelete(ctx context.Context, id uuid.UUID) er
 }
 
 // InvalidateCache invalidates the triagem rules cache (exported for use by handlers)
+// and publishes on TriagemRulesChangedChannel so other instances invalidate theirs
 func (r *TriagemRuleRepository) InvalidateCache(ctx context.Context) {
 	if r.redis != nil {
 		r.redis.Del(ctx, triagemRulesCacheKey)
+		r.redis.Publish(ctx, TriagemRulesChangedChannel, time.Now().UTC().Format(time.RFC3339Nano))
 	}
 }
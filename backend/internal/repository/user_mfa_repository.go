@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/services"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+// UserMFARepository stores the TOTP secrets of the users, encrypted at rest
+type UserMFARepository struct {
+	db                *sql.DB
+	encryptionService *services.EncryptionService
+}
+
+// NewUserMFARepository creates a new user MFA repository
+func NewUserMFARepository(db *sql.DB, encSvc *services.EncryptionService) *UserMFARepository {
+	return &UserMFARepository{
+		db:                db,
+		encryptionService: encSvc,
+	}
+}
+
+// GetMFA returns the MFA state of the user, or nil when the user never enrolled
+func (r *UserMFARepository) GetMFA(ctx context.Context, userID uuid.UUID) (*auth.MFAState, error) {
+	var secret sql.NullString
+	var enabled bool
+
+	err := r.db.QueryRowContext(ctx, `SELECT mfa_secret, mfa_enabled FROM users WHERE id = $1`, userID).Scan(&secret, &enabled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, auth.ErrUserNotFound
+		}
+		return nil, err
+	}
+	if !secret.Valid {
+		return nil, nil
+	}
+
+	plaintext, err := r.encryptionService.DecryptValue(secret.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt mfa secret: %w", err)
+	}
+
+	return &auth.MFAState{Secret: plaintext, Enabled: enabled}, nil
+}
+
+// SaveMFASecret encrypts and stores a new secret for the user, pending confirmation
+func (r *UserMFARepository) SaveMFASecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	encrypted, err := r.encryptionService.EncryptValue(secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt mfa secret: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET mfa_secret = $1, mfa_enabled = false, updated_at = NOW()
+		WHERE id = $2
+	`, encrypted, userID)
+	if err != nil {
+		return err
+	}
+
+	return requireUserAffected(result)
+}
+
+// EnableMFA confirms the enrollment of the stored secret of the user
+func (r *UserMFARepository) EnableMFA(ctx context.Context, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET mfa_enabled = true, updated_at = NOW()
+		WHERE id = $1 AND mfa_secret IS NOT NULL
+	`, userID)
+	if err != nil {
+		return err
+	}
+
+	return requireUserAffected(result)
+}
+
+// requireUserAffected returns auth.ErrUserNotFound when the update matched no user
+func requireUserAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return auth.ErrUserNotFound
+	}
+	return nil
+}
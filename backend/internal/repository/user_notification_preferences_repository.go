@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sidot/backend/internal/models"
 )
 
@@ -14,6 +15,54 @@ var (
 	ErrPreferencesNotFound = errors.New("notification preferences not found")
 )
 
+// notificationPreferencesColumns lists the selected columns in the order expected by scanNotificationPreferences
+const notificationPreferencesColumns = `id, user_id, sms_enabled, email_enabled, push_enabled, dashboard_enabled,
+		to_char(quiet_hours_start, 'HH24:MI'), to_char(quiet_hours_end, 'HH24:MI'), created_at, updated_at`
+
+// preferencesScanner is implemented by *sql.Row and *sql.Rows
+type preferencesScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanNotificationPreferences scans a row selected with notificationPreferencesColumns
+func scanNotificationPreferences(row preferencesScanner) (*models.UserNotificationPreferences, error) {
+	prefs := &models.UserNotificationPreferences{}
+	var quietStart, quietEnd sql.NullString
+
+	err := row.Scan(
+		&prefs.ID,
+		&prefs.UserID,
+		&prefs.SMSEnabled,
+		&prefs.EmailEnabled,
+		&prefs.PushEnabled,
+		&prefs.DashboardEnabled,
+		&quietStart,
+		&quietEnd,
+		&prefs.CreatedAt,
+		&prefs.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if quietStart.Valid && quietEnd.Valid {
+		start := models.ShiftTime(quietStart.String)
+		end := models.ShiftTime(quietEnd.String)
+		prefs.QuietHoursStart = &start
+		prefs.QuietHoursEnd = &end
+	}
+
+	return prefs, nil
+}
+
+// nullableShiftTime converts an optional time of day to a query parameter
+func nullableShiftTime(t *models.ShiftTime) interface{} {
+	if t == nil || *t == "" {
+		return nil
+	}
+	return string(*t)
+}
+
 // UserNotificationPreferencesRepository handles database operations for user notification preferences
 type UserNotificationPreferencesRepository struct {
 	db *sql.DB
@@ -31,6 +80,7 @@ func (r *UserNotificationPreferencesRepository) Create(ctx context.Context, inpu
 		UserID:           input.UserID,
 		SMSEnabled:       true,  // Default
 		EmailEnabled:     true,  // Default
+		PushEnabled:      true,  // Default
 		DashboardEnabled: true,  // Always true
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
@@ -46,55 +96,31 @@ func (r *UserNotificationPreferencesRepository) Create(ctx context.Context, inpu
 	// DashboardEnabled is always true, ignore input
 
 	query := `
-		INSERT INTO user_notification_preferences (id, user_id, sms_enabled, email_enabled, dashboard_enabled, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, user_id, sms_enabled, email_enabled, dashboard_enabled, created_at, updated_at
-	`
+		INSERT INTO user_notification_preferences (id, user_id, sms_enabled, email_enabled, push_enabled, dashboard_enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + notificationPreferencesColumns
 
-	err := r.db.QueryRowContext(ctx, query,
+	return scanNotificationPreferences(r.db.QueryRowContext(ctx, query,
 		prefs.ID,
 		prefs.UserID,
 		prefs.SMSEnabled,
 		prefs.EmailEnabled,
+		prefs.PushEnabled,
 		prefs.DashboardEnabled,
 		prefs.CreatedAt,
 		prefs.UpdatedAt,
-	).Scan(
-		&prefs.ID,
-		&prefs.UserID,
-		&prefs.SMSEnabled,
-		&prefs.EmailEnabled,
-		&prefs.DashboardEnabled,
-		&prefs.CreatedAt,
-		&prefs.UpdatedAt,
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return prefs, nil
+	))
 }
 
 // GetByUserID retrieves notification preferences by user ID
 func (r *UserNotificationPreferencesRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.UserNotificationPreferences, error) {
 	query := `
-		SELECT id, user_id, sms_enabled, email_enabled, dashboard_enabled, created_at, updated_at
+		SELECT ` + notificationPreferencesColumns + `
 		FROM user_notification_preferences
 		WHERE user_id = $1
 	`
 
-	prefs := &models.UserNotificationPreferences{}
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
-		&prefs.ID,
-		&prefs.UserID,
-		&prefs.SMSEnabled,
-		&prefs.EmailEnabled,
-		&prefs.DashboardEnabled,
-		&prefs.CreatedAt,
-		&prefs.UpdatedAt,
-	)
-
+	prefs, err := scanNotificationPreferences(r.db.QueryRowContext(ctx, query, userID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrPreferencesNotFound
@@ -120,37 +146,69 @@ func (r *UserNotificationPreferencesRepository) Update(ctx context.Context, user
 	if input.EmailEnabled != nil {
 		prefs.EmailEnabled = *input.EmailEnabled
 	}
+	if input.PushEnabled != nil {
+		prefs.PushEnabled = *input.PushEnabled
+	}
+	if input.QuietHoursStart != nil && input.QuietHoursEnd != nil {
+		prefs.QuietHoursStart = input.QuietHoursStart
+		prefs.QuietHoursEnd = input.QuietHoursEnd
+	}
 	// DashboardEnabled is always true, never update
 
 	prefs.UpdatedAt = time.Now()
 
 	query := `
 		UPDATE user_notification_preferences
-		SET sms_enabled = $2, email_enabled = $3, updated_at = $4
+		SET sms_enabled = $2, email_enabled = $3, push_enabled = $4,
+			quiet_hours_start = $5, quiet_hours_end = $6, updated_at = $7
 		WHERE user_id = $1
-		RETURNING id, user_id, sms_enabled, email_enabled, dashboard_enabled, created_at, updated_at
-	`
+		RETURNING ` + notificationPreferencesColumns
 
-	err = r.db.QueryRowContext(ctx, query,
+	return scanNotificationPreferences(r.db.QueryRowContext(ctx, query,
 		userID,
 		prefs.SMSEnabled,
 		prefs.EmailEnabled,
+		prefs.PushEnabled,
+		nullableShiftTime(prefs.QuietHoursStart),
+		nullableShiftTime(prefs.QuietHoursEnd),
 		prefs.UpdatedAt,
-	).Scan(
-		&prefs.ID,
-		&prefs.UserID,
-		&prefs.SMSEnabled,
-		&prefs.EmailEnabled,
-		&prefs.DashboardEnabled,
-		&prefs.CreatedAt,
-		&prefs.UpdatedAt,
-	)
+	))
+}
+
+// GetByUserIDs retrieves notification preferences for several users, keyed by user ID
+// Users without a preferences record are absent from the map
+func (r *UserNotificationPreferencesRepository) GetByUserIDs(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]*models.UserNotificationPreferences, error) {
+	result := make(map[uuid.UUID]*models.UserNotificationPreferences)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT ` + notificationPreferencesColumns + `
+		FROM user_notification_preferences
+		WHERE user_id = ANY($1::uuid[])
+	`
+
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
 
+	rows, err := r.db.QueryContext(ctx, query, pq.StringArray(ids))
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return prefs, nil
+	for rows.Next() {
+		prefs, err := scanNotificationPreferences(rows)
+		if err != nil {
+			return nil, err
+		}
+		result[prefs.UserID] = prefs
+	}
+
+	return result, rows.Err()
 }
 
 // EnsureExists creates preferences with defaults if they don't exist, or returns existing ones
@@ -28,35 +28,68 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// userEmailScope restricts an email lookup to the accounts visible from the tenant in context: those of
+// the tenant and the global ones (no tenant or super-admin). Without a tenant in context every account is
+// visible. The condition uses $2 for the tenant ID.
+func userEmailScope(ctx context.Context) (string, []interface{}) {
+	tenantID := GetTenantIDOrNil(ctx)
+	if tenantID == "" {
+		return "", nil
+	}
+	return " AND (tenant_id = $2 OR tenant_id IS NULL OR is_super_admin = true)", []interface{}{tenantID}
+}
+
 // GetByEmail retrieves a user by email for authentication
+// Emails are unique per tenant: outside of a tenant context, an email registered in several tenants
+// returns auth.ErrTenantRequired
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*auth.User, error) {
+	scope, scopeArgs := userEmailScope(ctx)
 	query := `
-		SELECT id, email, password_hash, nome, role, tenant_id, is_super_admin, ativo
+		SELECT id, email, password_hash, nome, role, tenant_id, is_super_admin, ativo, email_verified, created_at
 		FROM users
-		WHERE email = $1
+		WHERE email = $1 AND deleted_at IS NULL` + scope + `
+		LIMIT 2
 	`
 
+	rows, err := r.db.QueryContext(ctx, query, append([]interface{}{email}, scopeArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
 	var user auth.User
 	var tenantID sql.NullString
 	var isSuperAdmin sql.NullBool
+	found := 0
 
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.Nome,
-		&user.Role,
-		&tenantID,
-		&isSuperAdmin,
-		&user.Ativo,
-	)
-
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, auth.ErrUserNotFound
+	for rows.Next() {
+		found++
+		if found > 1 {
+			return nil, auth.ErrTenantRequired
+		}
+		err := rows.Scan(
+			&user.ID,
+			&user.Email,
+			&user.PasswordHash,
+			&user.Nome,
+			&user.Role,
+			&tenantID,
+			&isSuperAdmin,
+			&user.Ativo,
+			&user.EmailVerified,
+			&user.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
 		}
+	}
+
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	if found == 0 {
+		return nil, auth.ErrUserNotFound
+	}
 
 	// Set tenant ID if present
 	if tenantID.Valid {
@@ -80,10 +113,37 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*auth.Us
 	return &user, nil
 }
 
+// ListPasswordHashesByEmail returns the password hashes of the accounts with the email, in every tenant
+// allowed by the context
+func (r *UserRepository) ListPasswordHashesByEmail(ctx context.Context, email string) ([]string, error) {
+	scope, scopeArgs := userEmailScope(ctx)
+	query := `
+		SELECT password_hash
+		FROM users
+		WHERE email = $1 AND deleted_at IS NULL` + scope
+
+	rows, err := r.db.QueryContext(ctx, query, append([]interface{}{email}, scopeArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, rows.Err()
+}
+
 // GetByID retrieves a user by ID for authentication
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*auth.User, error) {
 	query := `
-		SELECT id, email, password_hash, nome, role, tenant_id, is_super_admin, ativo
+		SELECT id, email, password_hash, nome, role, tenant_id, is_super_admin, ativo, email_verified, created_at
 		FROM users
 		WHERE id = $1
 	`
@@ -101,6 +161,8 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*auth.User,
 		&tenantID,
 		&isSuperAdmin,
 		&user.Ativo,
+		&user.EmailVerified,
+		&user.CreatedAt,
 	)
 
 	if err != nil {
@@ -200,12 +262,29 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, p
 	return nil
 }
 
-// ExistsByEmail checks if a user with the given email exists
+// MarkEmailVerified records that the user confirmed its email
+// The user is identified by its verification token, so the tenant in context is not required
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users
+		SET email_verified = true, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`, userID)
+	if err != nil {
+		return err
+	}
+
+	return requireUserAffected(result)
+}
+
+// ExistsByEmail checks if a user with the given email exists in the tenant in context or as a global
+// account. Without a tenant in context (global accounts) every tenant is checked, so global emails stay unique.
 func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
+	scope, scopeArgs := userEmailScope(ctx)
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1` + scope + `)`
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, email).Scan(&exists)
+	err := r.db.QueryRowContext(ctx, query, append([]interface{}{email}, scopeArgs...)...).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
@@ -316,8 +395,8 @@ func (r *UserRepository) ListWithPagination(ctx context.Context, params *models.
 		params.Status = "all"
 	}
 
-	// Build WHERE clause
-	var conditions []string
+	// Build WHERE clause, excluding soft-deleted users
+	conditions := []string{"u.deleted_at IS NULL"}
 	var args []interface{}
 	argIndex := 1
 
@@ -346,10 +425,7 @@ func (r *UserRepository) ListWithPagination(ctx context.Context, params *models.
 		argIndex++
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
-	}
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
 
 	// Count total
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM users u %s`, whereClause)
@@ -365,7 +441,7 @@ func (r *UserRepository) ListWithPagination(ctx context.Context, params *models.
 
 	// Get users
 	query := fmt.Sprintf(`
-		SELECT u.id, u.email, u.nome, u.role, u.tenant_id, u.is_super_admin, u.mobile_phone, u.email_notifications, u.ativo, u.created_at, u.updated_at
+		SELECT u.id, u.email, u.nome, u.role, u.tenant_id, u.is_super_admin, u.mobile_phone, u.email_notifications, u.locale, u.ativo, u.email_verified, u.created_at, u.updated_at, u.last_login_at
 		FROM users u
 		%s
 		ORDER BY u.nome ASC
@@ -387,7 +463,7 @@ func (r *UserRepository) ListWithPagination(ctx context.Context, params *models.
 		var isSuperAdmin sql.NullBool
 
 		err := rows.Scan(
-			&u.ID, &u.Email, &u.Nome, &u.Role, &tenantID, &isSuperAdmin, &mobilePhone, &u.EmailNotifications, &u.Ativo, &u.CreatedAt, &u.UpdatedAt,
+			&u.ID, &u.Email, &u.Nome, &u.Role, &tenantID, &isSuperAdmin, &mobilePhone, &u.EmailNotifications, &u.Locale, &u.Ativo, &u.EmailVerified, &u.CreatedAt, &u.UpdatedAt, &u.LastLoginAt,
 		)
 		if err != nil {
 			return nil, err
@@ -447,9 +523,9 @@ func (r *UserRepository) ListByRole(ctx context.Context, role string) ([]models.
 	tenantFilter := NewTenantFilter(ctx)
 
 	query := `
-		SELECT u.id, u.email, u.nome, u.role, u.tenant_id, u.is_super_admin, u.mobile_phone, u.email_notifications, u.ativo, u.created_at, u.updated_at
+		SELECT u.id, u.email, u.nome, u.role, u.tenant_id, u.is_super_admin, u.mobile_phone, u.email_notifications, u.locale, u.ativo, u.created_at, u.updated_at
 		FROM users u
-		WHERE u.role = $1 AND u.ativo = true
+		WHERE u.role = $1 AND u.ativo = true AND u.deleted_at IS NULL
 	`
 
 	var args []interface{}
@@ -475,7 +551,7 @@ func (r *UserRepository) ListByRole(ctx context.Context, role string) ([]models.
 		var isSuperAdmin sql.NullBool
 
 		err := rows.Scan(
-			&u.ID, &u.Email, &u.Nome, &u.Role, &tenantID, &isSuperAdmin, &mobilePhone, &u.EmailNotifications, &u.Ativo, &u.CreatedAt, &u.UpdatedAt,
+			&u.ID, &u.Email, &u.Nome, &u.Role, &tenantID, &isSuperAdmin, &mobilePhone, &u.EmailNotifications, &u.Locale, &u.Ativo, &u.CreatedAt, &u.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -516,10 +592,10 @@ func (r *UserRepository) ListByRoleAndHospital(ctx context.Context, role string,
 	tenantFilter := NewTenantFilter(ctx)
 
 	query := `
-		SELECT u.id, u.email, u.nome, u.role, u.tenant_id, u.is_super_admin, u.mobile_phone, u.email_notifications, u.ativo, u.created_at, u.updated_at
+		SELECT u.id, u.email, u.nome, u.role, u.tenant_id, u.is_super_admin, u.mobile_phone, u.email_notifications, u.locale, u.ativo, u.created_at, u.updated_at
 		FROM users u
 		INNER JOIN user_hospitals uh ON u.id = uh.user_id
-		WHERE u.role = $1 AND u.ativo = true AND uh.hospital_id = $2
+		WHERE u.role = $1 AND u.ativo = true AND u.deleted_at IS NULL AND uh.hospital_id = $2
 	`
 
 	var args []interface{}
@@ -545,7 +621,7 @@ func (r *UserRepository) ListByRoleAndHospital(ctx context.Context, role string,
 		var isSuperAdmin sql.NullBool
 
 		err := rows.Scan(
-			&u.ID, &u.Email, &u.Nome, &u.Role, &tenantID, &isSuperAdmin, &mobilePhone, &u.EmailNotifications, &u.Ativo, &u.CreatedAt, &u.UpdatedAt,
+			&u.ID, &u.Email, &u.Nome, &u.Role, &tenantID, &isSuperAdmin, &mobilePhone, &u.EmailNotifications, &u.Locale, &u.Ativo, &u.CreatedAt, &u.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -586,9 +662,9 @@ func (r *UserRepository) ListByRoleWithEmailNotifications(ctx context.Context, r
 	tenantFilter := NewTenantFilter(ctx)
 
 	query := `
-		SELECT u.id, u.email, u.nome, u.role, u.tenant_id, u.is_super_admin, u.mobile_phone, u.email_notifications, u.ativo, u.created_at, u.updated_at
+		SELECT u.id, u.email, u.nome, u.role, u.tenant_id, u.is_super_admin, u.mobile_phone, u.email_notifications, u.locale, u.ativo, u.created_at, u.updated_at
 		FROM users u
-		WHERE u.role = $1 AND u.ativo = true AND u.email_notifications = true
+		WHERE u.role = $1 AND u.ativo = true AND u.deleted_at IS NULL AND u.email_notifications = true
 	`
 
 	var args []interface{}
@@ -614,7 +690,7 @@ func (r *UserRepository) ListByRoleWithEmailNotifications(ctx context.Context, r
 		var isSuperAdmin sql.NullBool
 
 		err := rows.Scan(
-			&u.ID, &u.Email, &u.Nome, &u.Role, &tenantID, &isSuperAdmin, &mobilePhone, &u.EmailNotifications, &u.Ativo, &u.CreatedAt, &u.UpdatedAt,
+			&u.ID, &u.Email, &u.Nome, &u.Role, &tenantID, &isSuperAdmin, &mobilePhone, &u.EmailNotifications, &u.Locale, &u.Ativo, &u.CreatedAt, &u.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -657,10 +733,21 @@ func (r *UserRepository) ListOperatorsByHospital(ctx context.Context, hospitalID
 
 // GetModelByID retrieves a user by ID with hospital data
 func (r *UserRepository) GetModelByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return r.getModelByID(ctx, id, "")
+}
+
+// GetTenantModelByID retrieves a user by ID with hospital data in the tenant of the context;
+// a user of another tenant is reported as ErrUserNotFound
+func (r *UserRepository) GetTenantModelByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return r.getModelByID(ctx, id, NewTenantFilter(ctx).AndClauseWithAlias("u"))
+}
+
+// getModelByID retrieves a user by ID with hospital data, restricted by the given tenant clause
+func (r *UserRepository) getModelByID(ctx context.Context, id uuid.UUID, tenantClause string) (*models.User, error) {
 	query := `
-		SELECT u.id, u.email, u.password_hash, u.nome, u.role, u.tenant_id, u.is_super_admin, u.mobile_phone, u.email_notifications, u.ativo, u.created_at, u.updated_at
+		SELECT u.id, u.email, u.password_hash, u.nome, u.role, u.tenant_id, u.is_super_admin, u.mobile_phone, u.email_notifications, u.locale, u.ativo, u.email_verified, u.created_at, u.updated_at, u.deleted_at, u.last_login_at
 		FROM users u
-		WHERE u.id = $1
+		WHERE u.id = $1` + tenantClause + `
 	`
 
 	var u models.User
@@ -668,7 +755,7 @@ func (r *UserRepository) GetModelByID(ctx context.Context, id uuid.UUID) (*model
 	var isSuperAdmin sql.NullBool
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&u.ID, &u.Email, &u.PasswordHash, &u.Nome, &u.Role, &tenantID, &isSuperAdmin, &mobilePhone, &u.EmailNotifications, &u.Ativo, &u.CreatedAt, &u.UpdatedAt,
+		&u.ID, &u.Email, &u.PasswordHash, &u.Nome, &u.Role, &tenantID, &isSuperAdmin, &mobilePhone, &u.EmailNotifications, &u.Locale, &u.Ativo, &u.EmailVerified, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt, &u.LastLoginAt,
 	)
 
 	if err != nil {
@@ -732,6 +819,12 @@ func (r *UserRepository) CreateUser(ctx context.Context, input *models.CreateUse
 		emailNotifications = *input.EmailNotifications
 	}
 
+	// Default locale to pt-BR
+	locale := models.DefaultLocale
+	if input.Locale != nil {
+		locale = models.NormalizeLocale(*input.Locale)
+	}
+
 	user := &models.User{
 		ID:                 uuid.New(),
 		TenantID:           tenantUUID,
@@ -741,7 +834,9 @@ func (r *UserRepository) CreateUser(ctx context.Context, input *models.CreateUse
 		Role:               input.Role,
 		MobilePhone:        input.MobilePhone,
 		EmailNotifications: emailNotifications,
+		Locale:             locale,
 		Ativo:              true,
+		EmailVerified:      !input.RequireEmailVerification,
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
 	}
@@ -753,8 +848,8 @@ func (r *UserRepository) CreateUser(ctx context.Context, input *models.CreateUse
 	defer tx.Rollback()
 
 	query := `
-		INSERT INTO users (id, email, password_hash, nome, role, tenant_id, is_super_admin, mobile_phone, email_notifications, ativo, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		INSERT INTO users (id, email, password_hash, nome, role, tenant_id, is_super_admin, mobile_phone, email_notifications, locale, ativo, email_verified, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
 	_, err = tx.ExecContext(ctx, query,
@@ -767,12 +862,18 @@ func (r *UserRepository) CreateUser(ctx context.Context, input *models.CreateUse
 		false, // is_super_admin defaults to false
 		user.MobilePhone,
 		user.EmailNotifications,
+		user.Locale,
 		user.Ativo,
+		user.EmailVerified,
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
 
 	if err != nil {
+		// Created concurrently with the same email in the tenant
+		if isUniqueViolation(err) {
+			return nil, ErrUserExists
+		}
 		return nil, err
 	}
 
@@ -831,6 +932,9 @@ func (r *UserRepository) UpdateUser(ctx context.Context, id uuid.UUID, input *mo
 	if input.EmailNotifications != nil {
 		user.EmailNotifications = *input.EmailNotifications
 	}
+	if input.Locale != nil {
+		user.Locale = models.NormalizeLocale(*input.Locale)
+	}
 	if input.Ativo != nil {
 		user.Ativo = *input.Ativo
 	}
@@ -848,8 +952,8 @@ func (r *UserRepository) UpdateUser(ctx context.Context, id uuid.UUID, input *mo
 
 	query := `
 		UPDATE users
-		SET nome = $1, role = $2, mobile_phone = $3, email_notifications = $4, ativo = $5, password_hash = $6, updated_at = $7
-		WHERE id = $8
+		SET nome = $1, role = $2, mobile_phone = $3, email_notifications = $4, locale = $5, ativo = $6, password_hash = $7, updated_at = $8
+		WHERE id = $9
 	`
 
 	result, err := tx.ExecContext(ctx, query,
@@ -857,6 +961,7 @@ func (r *UserRepository) UpdateUser(ctx context.Context, id uuid.UUID, input *mo
 		user.Role,
 		user.MobilePhone,
 		user.EmailNotifications,
+		user.Locale,
 		user.Ativo,
 		user.PasswordHash,
 		user.UpdatedAt,
@@ -927,7 +1032,7 @@ func (r *UserRepository) UpdateUser(ctx context.Context, id uuid.UUID, input *mo
 	return user, nil
 }
 
-// UpdateProfile updates a user's own profile (name and password only)
+// UpdateProfile updates a user's own profile (name, locale and password)
 func (r *UserRepository) UpdateProfile(ctx context.Context, id uuid.UUID, input *models.UpdateProfileInput, newPasswordHash *string) (*models.User, error) {
 	// Get existing user
 	user, err := r.GetModelByID(ctx, id)
@@ -938,6 +1043,9 @@ func (r *UserRepository) UpdateProfile(ctx context.Context, id uuid.UUID, input
 	if input.Nome != nil {
 		user.Nome = *input.Nome
 	}
+	if input.Locale != nil {
+		user.Locale = models.NormalizeLocale(*input.Locale)
+	}
 	if newPasswordHash != nil {
 		user.PasswordHash = *newPasswordHash
 	}
@@ -946,12 +1054,13 @@ func (r *UserRepository) UpdateProfile(ctx context.Context, id uuid.UUID, input
 
 	query := `
 		UPDATE users
-		SET nome = $1, password_hash = $2, updated_at = $3
-		WHERE id = $4
+		SET nome = $1, locale = $2, password_hash = $3, updated_at = $4
+		WHERE id = $5
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
 		user.Nome,
+		user.Locale,
 		user.PasswordHash,
 		user.UpdatedAt,
 		id,
@@ -995,15 +1104,70 @@ func (r *UserRepository) DeactivateUser(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+// SoftDelete marks a user of the current tenant as deleted and deactivates it. Deleted users are left
+// out of every listing and cannot sign in, but stay resolvable by ID so audit logs and occurrences
+// that reference them keep their names.
+func (r *UserRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
+	tf := NewTenantFilter(ctx)
+
+	query := `
+		UPDATE users
+		SET deleted_at = $1, ativo = false, updated_at = $1
+		WHERE id = $2 AND deleted_at IS NULL` + tf.AndClause() + `
+	`
+
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return auth.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Restore clears the deletion of a soft-deleted user of the current tenant and reactivates it
+func (r *UserRepository) Restore(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	tf := NewTenantFilter(ctx)
+
+	query := `
+		UPDATE users
+		SET deleted_at = NULL, ativo = true, updated_at = $1
+		WHERE id = $2 AND deleted_at IS NOT NULL` + tf.AndClause() + `
+	`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, auth.ErrUserNotFound
+	}
+
+	return r.GetModelByID(ctx, id)
+}
+
 // GetUsersWithSMSEnabled returns all active users with SMS enabled and mobile phone set (tenant-scoped)
 func (r *UserRepository) GetUsersWithSMSEnabled(ctx context.Context) ([]models.User, error) {
 	tenantFilter := NewTenantFilter(ctx)
 
 	query := `
-		SELECT u.id, u.email, u.nome, u.role, u.tenant_id, u.is_super_admin, u.mobile_phone, u.email_notifications, u.ativo, u.created_at, u.updated_at
+		SELECT u.id, u.email, u.nome, u.role, u.tenant_id, u.is_super_admin, u.mobile_phone, u.email_notifications, u.locale, u.ativo, u.created_at, u.updated_at
 		FROM users u
 		LEFT JOIN user_notification_preferences p ON u.id = p.user_id
-		WHERE u.ativo = true
+		WHERE u.ativo = true AND u.deleted_at IS NULL
 		AND u.mobile_phone IS NOT NULL
 		AND u.mobile_phone != ''
 		AND (p.sms_enabled IS NULL OR p.sms_enabled = true)
@@ -1031,7 +1195,7 @@ func (r *UserRepository) GetUsersWithSMSEnabled(ctx context.Context) ([]models.U
 		var isSuperAdmin sql.NullBool
 
 		err := rows.Scan(
-			&u.ID, &u.Email, &u.Nome, &u.Role, &tenantID, &isSuperAdmin, &mobilePhone, &u.EmailNotifications, &u.Ativo, &u.CreatedAt, &u.UpdatedAt,
+			&u.ID, &u.Email, &u.Nome, &u.Role, &tenantID, &isSuperAdmin, &mobilePhone, &u.EmailNotifications, &u.Locale, &u.Ativo, &u.CreatedAt, &u.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
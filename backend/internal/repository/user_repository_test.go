@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/middleware"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services/auth"
+)
+
+// insertTestUser creates an active operator of tenantID and removes it after the test
+func insertTestUser(t *testing.T, db *sql.DB, tenantID uuid.UUID, nome string) uuid.UUID {
+	t.Helper()
+
+	id := uuid.New()
+	_, err := db.Exec(`
+		INSERT INTO users (id, tenant_id, email, password_hash, nome, role, ativo)
+		VALUES ($1, $2, $3, 'hash', $4, 'operador', true)
+	`, id, tenantID, "usuario-"+id.String()[:8]+"@sidot.gov.br", nome)
+	if err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM users WHERE id = $1`, id) })
+	return id
+}
+
+// listedUserIDs returns the IDs of the users of the tenant listed by ListWithPagination
+func listedUserIDs(t *testing.T, repo *UserRepository, ctx context.Context) map[uuid.UUID]bool {
+	t.Helper()
+
+	result, err := repo.ListWithPagination(ctx, &models.UserListParams{Page: 1, PerPage: 100})
+	if err != nil {
+		t.Fatalf("ListWithPagination returned error: %v", err)
+	}
+	ids := make(map[uuid.UUID]bool, len(result.Users))
+	for _, u := range result.Users {
+		ids[u.ID] = true
+	}
+	return ids
+}
+
+// TestUserSoftDeleteAndRestore tests that a soft-deleted user leaves the listings but stays resolvable by ID until restored
+func TestUserSoftDeleteAndRestore(t *testing.T) {
+	db := openTestDB(t)
+
+	tenantID := insertTestTenant(t, db)
+	deletedID := insertTestUser(t, db, tenantID, "Operador Excluido")
+	keptID := insertTestUser(t, db, tenantID, "Operador Ativo")
+
+	repo := NewUserRepository(db)
+	ctx := middleware.WithTenantContext(context.Background(), tenantID.String(), false)
+
+	if err := repo.SoftDelete(ctx, deletedID); err != nil {
+		t.Fatalf("SoftDelete returned error: %v", err)
+	}
+
+	listed := listedUserIDs(t, repo, ctx)
+	if listed[deletedID] || !listed[keptID] {
+		t.Errorf("Expected only the active user in the listing, got %v", listed)
+	}
+	operators, err := repo.ListByRole(ctx, string(models.RoleOperador))
+	if err != nil {
+		t.Fatalf("ListByRole returned error: %v", err)
+	}
+	for _, u := range operators {
+		if u.ID == deletedID {
+			t.Error("Expected the deleted user to be excluded from ListByRole")
+		}
+	}
+
+	// Audit logs and occurrences still show who the user was
+	user, err := repo.GetModelByID(ctx, deletedID)
+	if err != nil {
+		t.Fatalf("Expected the deleted user to be resolvable by ID, got %v", err)
+	}
+	if user.Nome != "Operador Excluido" || user.DeletedAt == nil || user.Ativo {
+		t.Errorf("Expected the deleted and inactive user, got %+v", user)
+	}
+
+	if _, err := repo.GetByEmail(ctx, user.Email); !errors.Is(err, auth.ErrUserNotFound) {
+		t.Errorf("Expected a deleted user not to sign in, got %v", err)
+	}
+	if err := repo.SoftDelete(ctx, deletedID); !errors.Is(err, auth.ErrUserNotFound) {
+		t.Errorf("Expected deleting twice to return ErrUserNotFound, got %v", err)
+	}
+
+	restored, err := repo.Restore(ctx, deletedID)
+	if err != nil {
+		t.Fatalf("Restore returned error: %v", err)
+	}
+	if restored.DeletedAt != nil || !restored.Ativo {
+		t.Errorf("Expected the restored user to be active, got %+v", restored)
+	}
+	if !listedUserIDs(t, repo, ctx)[deletedID] {
+		t.Error("Expected the restored user back in the listing")
+	}
+	if _, err := repo.Restore(ctx, keptID); !errors.Is(err, auth.ErrUserNotFound) {
+		t.Errorf("Expected restoring a user that is not deleted to return ErrUserNotFound, got %v", err)
+	}
+}
+
+// TestUserEmailScope tests that email lookups in a tenant only see the tenant and global accounts
+func TestUserEmailScope(t *testing.T) {
+	if scope, args := userEmailScope(context.Background()); scope != "" || args != nil {
+		t.Errorf("Expected no scope without a tenant, got %q %v", scope, args)
+	}
+
+	tenantID := uuid.New().String()
+	scope, args := userEmailScope(middleware.WithTenantContext(context.Background(), tenantID, false))
+	if scope != " AND (tenant_id = $2 OR tenant_id IS NULL OR is_super_admin = true)" {
+		t.Errorf("Unexpected scope %q", scope)
+	}
+	if len(args) != 1 || args[0] != tenantID {
+		t.Errorf("Expected the tenant ID as argument, got %v", args)
+	}
+}
+
+// TestCreateUserEmailUniquePerTenant tests that an email can be used once in each tenant and global emails stay unique
+func TestCreateUserEmailUniquePerTenant(t *testing.T) {
+	db := openTestDB(t)
+
+	tenantA := insertTestTenant(t, db)
+	tenantB := insertTestTenant(t, db)
+	ctxA := middleware.WithTenantContext(context.Background(), tenantA.String(), false)
+	ctxB := middleware.WithTenantContext(context.Background(), tenantB.String(), false)
+
+	repo := NewUserRepository(db)
+	email := "terceirizado-" + uuid.New().String()[:8] + "@sidot.gov.br"
+	create := func(ctx context.Context) (*models.User, error) {
+		user, err := repo.CreateUser(ctx, &models.CreateUserInput{Email: email, Nome: "Terceirizado", Role: models.RoleOperador}, "hash")
+		if err == nil {
+			t.Cleanup(func() { db.Exec(`DELETE FROM users WHERE id = $1`, user.ID) })
+		}
+		return user, err
+	}
+
+	userA, err := create(ctxA)
+	if err != nil {
+		t.Fatalf("CreateUser in tenant A returned error: %v", err)
+	}
+	userB, err := create(ctxB)
+	if err != nil {
+		t.Fatalf("Expected the same email to be accepted in tenant B, got %v", err)
+	}
+	if _, err := create(ctxA); !errors.Is(err, ErrUserExists) {
+		t.Errorf("Expected ErrUserExists for the email twice in tenant A, got %v", err)
+	}
+	if _, err := create(context.Background()); !errors.Is(err, ErrUserExists) {
+		t.Errorf("Expected ErrUserExists for a global account with a tenant email, got %v", err)
+	}
+
+	// Sign-in needs the tenant to pick the account
+	if _, err := repo.GetByEmail(context.Background(), email); !errors.Is(err, auth.ErrTenantRequired) {
+		t.Errorf("Expected ErrTenantRequired without a tenant, got %v", err)
+	}
+	for ctx, expected := range map[context.Context]uuid.UUID{ctxA: userA.ID, ctxB: userB.ID} {
+		user, err := repo.GetByEmail(ctx, email)
+		if err != nil || user.ID != expected {
+			t.Errorf("Expected account %s in its tenant, got %v (%v)", expected, user, err)
+		}
+	}
+}
+
+// TestCreateUserWithEmailVerification tests that a user created with email verification signs in as verified once marked
+func TestCreateUserWithEmailVerification(t *testing.T) {
+	db := openTestDB(t)
+
+	tenantID := insertTestTenant(t, db)
+	ctx := middleware.WithTenantContext(context.Background(), tenantID.String(), false)
+	repo := NewUserRepository(db)
+
+	input := &models.CreateUserInput{
+		Email:                    "verificar-" + uuid.New().String()[:8] + "@sidot.gov.br",
+		Nome:                     "Usuario Novo",
+		Role:                     models.RoleOperador,
+		RequireEmailVerification: true,
+	}
+	user, err := repo.CreateUser(ctx, input, "hash")
+	if err != nil {
+		t.Fatalf("CreateUser returned error: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM users WHERE id = $1`, user.ID) })
+
+	if user.EmailVerified {
+		t.Error("Expected the created user to have an unverified email")
+	}
+	authUser, err := repo.GetByEmail(ctx, input.Email)
+	if err != nil {
+		t.Fatalf("GetByEmail returned error: %v", err)
+	}
+	if authUser.EmailVerified || authUser.CreatedAt.IsZero() {
+		t.Errorf("Expected the unverified email and creation date for sign-in, got %+v", authUser)
+	}
+
+	if err := repo.MarkEmailVerified(context.Background(), user.ID); err != nil {
+		t.Fatalf("MarkEmailVerified returned error: %v", err)
+	}
+	authUser, err = repo.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if !authUser.EmailVerified {
+		t.Error("Expected the email to be verified")
+	}
+
+	if err := repo.MarkEmailVerified(context.Background(), uuid.New()); !errors.Is(err, auth.ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound for an unknown user, got %v", err)
+	}
+}
+
+// TestGetTenantModelByID tests that the tenant-scoped user lookup does not see the users of another tenant
+func TestGetTenantModelByID(t *testing.T) {
+	db := openTestDB(t)
+
+	tenantA := insertTestTenant(t, db)
+	tenantB := insertTestTenant(t, db)
+	userB := insertTestUser(t, db, tenantB, "Operador Outro Tenant")
+
+	repo := NewUserRepository(db)
+	ctxA := middleware.WithTenantContext(context.Background(), tenantA.String(), false)
+	ctxB := middleware.WithTenantContext(context.Background(), tenantB.String(), false)
+
+	if _, err := repo.GetTenantModelByID(ctxA, userB); !errors.Is(err, auth.ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound for a user of another tenant, got %v", err)
+	}
+	if user, err := repo.GetTenantModelByID(ctxB, userB); err != nil || user.ID != userB {
+		t.Errorf("Expected the user of the tenant, got %v (%v)", user, err)
+	}
+
+	superAdminCtx := middleware.WithTenantContext(context.Background(), "", true)
+	if _, err := repo.GetTenantModelByID(superAdminCtx, userB); err != nil {
+		t.Errorf("Expected a super admin without tenant to see every user, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services/auth"
+	"github.com/sidot/backend/internal/services/notification"
+)
+
+// DefaultAlertCooldown is the minimum interval between alerts of the same action
+const DefaultAlertCooldown = 5 * time.Minute
+
+// AlertRule matches audit events of an action ("*" for any action) with at least MinSeverity
+type AlertRule struct {
+	Action      string
+	MinSeverity models.Severity
+}
+
+// DefaultAlertRules alert on super admin impersonation, user bans and triagem rule deletion
+var DefaultAlertRules = []AlertRule{
+	{Action: auth.ActionUserImpersonate, MinSeverity: models.SeverityWarn},
+	{Action: auth.ActionUserBan, MinSeverity: models.SeverityWarn},
+	{Action: models.ActionRegraDelete, MinSeverity: models.SeverityWarn},
+}
+
+// AlertConfig configures the email alerts sent for audit events
+type AlertConfig struct {
+	Recipients   []string
+	Rules        []AlertRule
+	Cooldown     time.Duration
+	DashboardURL string
+}
+
+// alertSender sends audit alert emails (implemented by notification.EmailService)
+type alertSender interface {
+	IsConfigured() bool
+	SendAuditAlert(ctx context.Context, to string, data *notification.AuditAlertData) error
+}
+
+// auditAlerts emails the recipients when a recorded event matches a rule, at most once per
+// action within the cooldown
+type auditAlerts struct {
+	sender alertSender
+	config AlertConfig
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+
+	now      func() time.Time
+	dispatch func(func()) // runs the sending off the request path
+}
+
+// ParseAlertRules parses "action" or "action:SEVERITY" entries; the severity defaults to WARN
+func ParseAlertRules(entries []string) ([]AlertRule, error) {
+	var rules []AlertRule
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		action, severity, found := strings.Cut(entry, ":")
+		rule := AlertRule{Action: strings.TrimSpace(action), MinSeverity: models.SeverityWarn}
+		if found {
+			rule.MinSeverity = models.Severity(strings.ToUpper(strings.TrimSpace(severity)))
+		}
+		if rule.Action == "" || !rule.MinSeverity.IsValid() {
+			return nil, fmt.Errorf("invalid audit alert rule %q: expected action or action:INFO|WARN|CRITICAL", entry)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// severityRank orders severities from INFO to CRITICAL
+func severityRank(severity models.Severity) int {
+	switch severity {
+	case models.SeverityCritical:
+		return 2
+	case models.SeverityWarn:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// matches reports whether the event matches a rule
+func (a *auditAlerts) matches(entry *models.AuditLog) bool {
+	for _, rule := range a.config.Rules {
+		if (rule.Action == "*" || rule.Action == entry.Acao) && severityRank(entry.Severity) >= severityRank(rule.MinSeverity) {
+			return true
+		}
+	}
+	return false
+}
+
+// reserve claims the alert of an action, returning false during its cooldown
+func (a *auditAlerts) reserve(action string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	if last, ok := a.lastSent[action]; ok && now.Sub(last) < a.config.Cooldown {
+		return false
+	}
+	a.lastSent[action] = now
+	return true
+}
+
+// notify alerts the recipients about a recorded event that matches a rule
+func (a *auditAlerts) notify(entry *models.AuditLog) {
+	if !a.matches(entry) || !a.sender.IsConfigured() {
+		return
+	}
+	if !a.reserve(entry.Acao) {
+		log.Printf("[Audit] Alert cooldown active, skipping alert for %s", entry.Acao)
+		return
+	}
+
+	data := notification.AuditAlertData{
+		Acao:         entry.Acao,
+		Severity:     string(entry.Severity),
+		ActorName:    entry.ActorName,
+		EntidadeTipo: entry.EntidadeTipo,
+		EntidadeID:   entry.EntidadeID,
+		Timestamp:    entry.Timestamp,
+	}
+	if entry.IPAddress != nil {
+		data.IPAddress = *entry.IPAddress
+	}
+	if a.config.DashboardURL != "" {
+		data.DashboardURL = a.config.DashboardURL + "/admin/logs"
+	}
+
+	a.dispatch(func() {
+		for _, to := range a.config.Recipients {
+			recipientData := data
+			if err := a.sender.SendAuditAlert(context.Background(), to, &recipientData); err != nil {
+				log.Printf("[Audit] Failed to send alert for %s to %s: %v", entry.Acao, to, err)
+			}
+		}
+	})
+}
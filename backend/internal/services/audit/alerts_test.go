@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services/auth"
+	"github.com/sidot/backend/internal/services/notification"
+)
+
+// fakeAuditLogCreator returns the entries it is given, like the repository INSERT
+type fakeAuditLogCreator struct {
+	entries []models.AuditLog
+}
+
+func (f *fakeAuditLogCreator) Create(ctx context.Context, input *models.CreateAuditLogInput) (*models.AuditLog, error) {
+	entry := models.AuditLog{
+		ID:           uuid.New(),
+		Timestamp:    time.Now(),
+		UsuarioID:    input.UsuarioID,
+		ActorName:    input.ActorName,
+		Acao:         input.Acao,
+		EntidadeTipo: input.EntidadeTipo,
+		EntidadeID:   input.EntidadeID,
+		Severity:     input.Severity,
+		IPAddress:    input.IPAddress,
+	}
+	f.entries = append(f.entries, entry)
+	return &entry, nil
+}
+
+// recordingAlertSender records the alerts sent
+type recordingAlertSender struct {
+	alerts []notification.AuditAlertData
+	to     []string
+}
+
+func (s *recordingAlertSender) IsConfigured() bool { return true }
+
+func (s *recordingAlertSender) SendAuditAlert(ctx context.Context, to string, data *notification.AuditAlertData) error {
+	s.to = append(s.to, to)
+	s.alerts = append(s.alerts, *data)
+	return nil
+}
+
+// newTestAlertingService returns an audit service whose alerts are sent synchronously at now
+func newTestAlertingService(now *time.Time) (*AuditService, *recordingAlertSender) {
+	sender := &recordingAlertSender{}
+	service := &AuditService{repo: &fakeAuditLogCreator{}}
+	service.SetAlerts(sender, AlertConfig{
+		Recipients:   []string{"admin@sidot.gov.br"},
+		Rules:        DefaultAlertRules,
+		Cooldown:     10 * time.Minute,
+		DashboardURL: "https://sidot.example.com",
+	})
+	service.alerts.now = func() time.Time { return *now }
+	service.alerts.dispatch = func(send func()) { send() }
+	return service, sender
+}
+
+func logTestEvent(t *testing.T, service *AuditService, acao string, severity models.Severity) {
+	t.Helper()
+	ip := "10.0.0.7"
+	err := service.LogEventWithUser(context.Background(), nil, "admin@sidot.gov.br", acao, models.EntityTypeUser,
+		uuid.NewString(), nil, severity, nil, &ip, nil)
+	if err != nil {
+		t.Fatalf("Failed to log event: %v", err)
+	}
+}
+
+// TestAuditAlertOnWarnBan verifies that a WARN ban event triggers an alert while an INFO event does not
+func TestAuditAlertOnWarnBan(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	service, sender := newTestAlertingService(&now)
+
+	logTestEvent(t, service, models.ActionUsuarioUpdate, models.SeverityInfo)
+	logTestEvent(t, service, auth.ActionUserBan, models.SeverityInfo)
+	if len(sender.alerts) != 0 {
+		t.Fatalf("Expected no alert for INFO events, got %d", len(sender.alerts))
+	}
+
+	logTestEvent(t, service, auth.ActionUserBan, models.SeverityWarn)
+	if len(sender.alerts) != 1 {
+		t.Fatalf("Expected 1 alert for the WARN ban, got %d", len(sender.alerts))
+	}
+	alert := sender.alerts[0]
+	if sender.to[0] != "admin@sidot.gov.br" || alert.Acao != auth.ActionUserBan || alert.Severity != "WARN" {
+		t.Errorf("Unexpected alert %+v to %s", alert, sender.to[0])
+	}
+	if alert.IPAddress != "10.0.0.7" || alert.DashboardURL != "https://sidot.example.com/admin/logs" {
+		t.Errorf("Unexpected alert details %+v", alert)
+	}
+}
+
+// TestAuditAlertCooldown verifies that repeated events of an action alert once per cooldown
+func TestAuditAlertCooldown(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	service, sender := newTestAlertingService(&now)
+
+	logTestEvent(t, service, auth.ActionUserBan, models.SeverityWarn)
+	logTestEvent(t, service, auth.ActionUserBan, models.SeverityWarn)
+	logTestEvent(t, service, models.ActionRegraDelete, models.SeverityWarn)
+	if len(sender.alerts) != 2 {
+		t.Fatalf("Expected 1 alert per action within the cooldown, got %d", len(sender.alerts))
+	}
+
+	now = now.Add(11 * time.Minute)
+	logTestEvent(t, service, auth.ActionUserBan, models.SeverityWarn)
+	if len(sender.alerts) != 3 {
+		t.Errorf("Expected a new alert after the cooldown, got %d", len(sender.alerts))
+	}
+}
+
+// TestParseAlertRules tests the AUDIT_ALERT_RULES format
+func TestParseAlertRules(t *testing.T) {
+	rules, err := ParseAlertRules([]string{"admin.user.ban", " regra.delete:critical ", "*:CRITICAL", ""})
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+	expected := []AlertRule{
+		{Action: "admin.user.ban", MinSeverity: models.SeverityWarn},
+		{Action: "regra.delete", MinSeverity: models.SeverityCritical},
+		{Action: "*", MinSeverity: models.SeverityCritical},
+	}
+	if len(rules) != len(expected) {
+		t.Fatalf("Expected %d rules, got %v", len(expected), rules)
+	}
+	for i := range expected {
+		if rules[i] != expected[i] {
+			t.Errorf("Rule %d: expected %+v, got %+v", i, expected[i], rules[i])
+		}
+	}
+
+	for _, invalid := range []string{"admin.user.ban:HIGH", ":WARN"} {
+		if _, err := ParseAlertRules([]string{invalid}); err == nil {
+			t.Errorf("Expected %q to be rejected", invalid)
+		}
+	}
+}
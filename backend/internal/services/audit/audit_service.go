@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -12,9 +13,15 @@ import (
 	"github.com/sidot/backend/internal/repository"
 )
 
+// auditLogCreator stores audit log entries (implemented by repository.AuditLogRepository)
+type auditLogCreator interface {
+	Create(ctx context.Context, input *models.CreateAuditLogInput) (*models.AuditLog, error)
+}
+
 // AuditService provides audit logging functionality
 type AuditService struct {
-	repo *repository.AuditLogRepository
+	repo   auditLogCreator
+	alerts *auditAlerts // nil when alerts are disabled
 }
 
 // NewAuditService creates a new audit service
@@ -22,6 +29,33 @@ func NewAuditService(repo *repository.AuditLogRepository) *AuditService {
 	return &AuditService{repo: repo}
 }
 
+// SetAlerts emails the configured recipients when a recorded event matches an alert rule
+func (s *AuditService) SetAlerts(sender alertSender, config AlertConfig) {
+	if config.Cooldown <= 0 {
+		config.Cooldown = DefaultAlertCooldown
+	}
+	s.alerts = &auditAlerts{
+		sender:   sender,
+		config:   config,
+		lastSent: make(map[string]time.Time),
+		now:      time.Now,
+		dispatch: func(send func()) { go send() },
+	}
+}
+
+// Create stores an audit log entry and sends the alerts it matches
+func (s *AuditService) Create(ctx context.Context, input *models.CreateAuditLogInput) (*models.AuditLog, error) {
+	entry, err := s.repo.Create(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.alerts != nil {
+		s.alerts.notify(entry)
+	}
+	return entry, nil
+}
+
 // LogEvent logs an audit event to the database
 func (s *AuditService) LogEvent(
 	ctx context.Context,
@@ -86,7 +120,7 @@ func (s *AuditService) LogEvent(
 		UserAgent:    userAgent,
 	}
 
-	_, err := s.repo.Create(ctx, input)
+	_, err := s.Create(ctx, input)
 	if err != nil {
 		log.Printf("Warning: Failed to create audit log: %v", err)
 		return err
@@ -131,7 +165,7 @@ func (s *AuditService) LogEventWithUser(
 		UserAgent:    userAgent,
 	}
 
-	_, err := s.repo.Create(ctx, input)
+	_, err := s.Create(ctx, input)
 	if err != nil {
 		log.Printf("Warning: Failed to create audit log: %v", err)
 		return err
@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// RedactedValue replaces the values of sensitive fields in a diff
+const RedactedValue = "[REDACTED]"
+
+// diffSensitiveFields are recorded as changed without their values
+var diffSensitiveFields = map[string]bool{
+	"password_hash":  true,
+	"config_conexao": true, // PEP connection credentials
+}
+
+// diffIgnoredFields change on every update and are left out of diffs
+var diffIgnoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// FieldChange is the previous and new value of a changed field
+type FieldChange struct {
+	Anterior interface{} `json:"anterior"`
+	Novo     interface{} `json:"novo"`
+}
+
+// Changes maps the name (db column) of each changed field to its change
+type Changes map[string]FieldChange
+
+// Diff compares two values of the same struct type (or pointers to it) field by field and
+// returns the changed fields, for the detalhes of update audit events. Fields are named by
+// their db tag (json tag when absent); relations (db:"-") and timestamps are skipped, and
+// sensitive fields appear with RedactedValue instead of their values.
+func Diff(before, after interface{}) Changes {
+	changes := Changes{}
+
+	b := reflect.Indirect(reflect.ValueOf(before))
+	a := reflect.Indirect(reflect.ValueOf(after))
+	if !b.IsValid() || !a.IsValid() || b.Type() != a.Type() || b.Kind() != reflect.Struct {
+		return changes
+	}
+
+	diffStruct(b, a, changes)
+	return changes
+}
+
+// diffStruct adds the changed fields of two values of the same struct type
+func diffStruct(before, after reflect.Value, changes Changes) {
+	for i := 0; i < before.NumField(); i++ {
+		field := before.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			diffStruct(before.Field(i), after.Field(i), changes)
+			continue
+		}
+
+		name := diffFieldName(field)
+		if name == "" || diffIgnoredFields[name] {
+			continue
+		}
+
+		previous := diffValue(before.Field(i))
+		current := diffValue(after.Field(i))
+		if diffEqual(previous, current) {
+			continue
+		}
+
+		if diffSensitiveFields[name] {
+			changes[name] = FieldChange{Anterior: RedactedValue, Novo: RedactedValue}
+			continue
+		}
+		changes[name] = FieldChange{Anterior: previous, Novo: current}
+	}
+}
+
+// diffFieldName returns the db (or json) name of a field, empty when it is not persisted
+func diffFieldName(field reflect.StructField) string {
+	tag, ok := field.Tag.Lookup("db")
+	if !ok {
+		tag = field.Tag.Get("json")
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// diffValue dereferences pointers, nil for a nil pointer
+func diffValue(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+// diffEqual compares field values; JSON is compared by content and times by instant
+func diffEqual(previous, current interface{}) bool {
+	switch p := previous.(type) {
+	case json.RawMessage:
+		c, ok := current.(json.RawMessage)
+		return ok && jsonEqual(p, c)
+	case time.Time:
+		c, ok := current.(time.Time)
+		return ok && p.Equal(c)
+	}
+	return reflect.DeepEqual(previous, current)
+}
+
+// jsonEqual compares two JSON documents ignoring formatting and key order
+func jsonEqual(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
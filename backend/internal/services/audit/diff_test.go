@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// TestDiffOmitsUnchangedFields verifies that only changed fields are recorded, by db column name
+func TestDiffOmitsUnchangedFields(t *testing.T) {
+	phone := "+5562999999999"
+	before := &models.User{
+		ID:          uuid.New(),
+		Email:       "operador@sidot.gov.br",
+		Nome:        "Maria",
+		Role:        models.RoleOperador,
+		MobilePhone: &phone,
+		Ativo:       true,
+		UpdatedAt:   time.Now().Add(-time.Hour),
+		Hospitals:   []models.Hospital{{ID: uuid.New()}},
+	}
+	after := *before
+	after.Nome = "Maria Souza"
+	after.Role = models.RoleGestor
+	samePhone := phone
+	after.MobilePhone = &samePhone
+	after.UpdatedAt = time.Now()
+	after.Hospitals = nil
+
+	changes := Diff(before, &after)
+
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changed fields, got %v", changes)
+	}
+	if changes["nome"].Anterior != "Maria" || changes["nome"].Novo != "Maria Souza" {
+		t.Errorf("Unexpected nome change %+v", changes["nome"])
+	}
+	if changes["role"].Anterior != models.RoleOperador || changes["role"].Novo != models.RoleGestor {
+		t.Errorf("Unexpected role change %+v", changes["role"])
+	}
+	for _, field := range []string{"mobile_phone", "updated_at", "hospitals", "email"} {
+		if _, ok := changes[field]; ok {
+			t.Errorf("Expected %s to be left out of the diff", field)
+		}
+	}
+}
+
+// TestDiffMasksSensitiveFields verifies that sensitive fields are recorded without their values
+func TestDiffMasksSensitiveFields(t *testing.T) {
+	before := models.User{ID: uuid.New(), Nome: "Maria", PasswordHash: "$2a$10$old"}
+	after := before
+	after.PasswordHash = "$2a$10$new"
+
+	changes := Diff(before, after)
+
+	change, ok := changes["password_hash"]
+	if !ok {
+		t.Fatalf("Expected the password change to be recorded, got %v", changes)
+	}
+	if change.Anterior != RedactedValue || change.Novo != RedactedValue {
+		t.Errorf("Expected redacted values, got %+v", change)
+	}
+
+	data, _ := json.Marshal(changes)
+	if string(data) != `{"password_hash":{"anterior":"[REDACTED]","novo":"[REDACTED]"}}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+}
+
+// TestDiffComparesJSONByContent verifies that reformatted JSON is not a change and embedded structs are compared
+func TestDiffComparesJSONByContent(t *testing.T) {
+	before := models.TenantWithMetrics{Tenant: models.Tenant{
+		Name:        "SES GO",
+		ThemeConfig: json.RawMessage(`{"primary": "#000", "logo": null}`),
+	}}
+	after := before
+	after.ThemeConfig = json.RawMessage(`{"logo":null,"primary":"#000"}`)
+	after.IsActive = true
+
+	changes := Diff(&before.Tenant, &after.Tenant)
+
+	if len(changes) != 1 || changes["is_active"].Novo != true {
+		t.Errorf("Expected only is_active to change, got %v", changes)
+	}
+}
+
+// TestDiffDifferentTypes verifies that values of different types produce no changes
+func TestDiffDifferentTypes(t *testing.T) {
+	if changes := Diff(&models.User{Nome: "a"}, &models.Hospital{Nome: "b"}); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+	if changes := Diff(nil, &models.User{}); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
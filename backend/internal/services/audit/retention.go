@@ -0,0 +1,234 @@
+package audit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+	"github.com/sidot/backend/internal/services/storage"
+)
+
+const (
+	// DefaultRetention keeps audit log entries for 5 years (LGPD/CFM)
+	DefaultRetention = 5 * 365 * 24 * time.Hour
+
+	// DefaultArchiveInterval is the interval between archival runs
+	DefaultArchiveInterval = 24 * time.Hour
+
+	// DefaultArchiveBatchSize is the number of entries exported per archive file
+	DefaultArchiveBatchSize = 5000
+
+	// DefaultArchivePrefix is the storage key prefix of the archive files
+	DefaultArchivePrefix = "audit-logs"
+)
+
+// auditArchiveStore reads entries past retention and deletes archived ones
+// (implemented by repository.AuditLogArchiveRepository)
+type auditArchiveStore interface {
+	ListBefore(ctx context.Context, cutoff time.Time, limit int) ([]models.AuditLog, error)
+	DeleteArchived(ctx context.Context, archive *models.AuditLogArchive, ids []uuid.UUID, cutoff time.Time) error
+}
+
+// RetentionJob archives audit log entries older than the retention period to gzip JSON files in
+// the configured storage, then deletes them from audit_logs and records the archive location
+type RetentionJob struct {
+	store   auditArchiveStore
+	storage storage.Storage
+
+	retention     time.Duration
+	checkInterval time.Duration
+	batchSize     int
+	prefix        string
+
+	totalArchived int64
+
+	running int32
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	logger *log.Logger
+}
+
+// NewRetentionJob creates a new audit log retention job writing archives to archiveStorage
+func NewRetentionJob(db *sql.DB, archiveStorage storage.Storage) *RetentionJob {
+	return &RetentionJob{
+		store:         repository.NewAuditLogArchiveRepository(db),
+		storage:       archiveStorage,
+		retention:     DefaultRetention,
+		checkInterval: DefaultArchiveInterval,
+		batchSize:     DefaultArchiveBatchSize,
+		prefix:        DefaultArchivePrefix,
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+		logger:        log.Default(),
+	}
+}
+
+// SetRetention sets how long audit log entries are kept in the table
+func (j *RetentionJob) SetRetention(retention time.Duration) {
+	if retention > 0 {
+		j.retention = retention
+	}
+}
+
+// SetCheckInterval sets the interval between archival runs
+func (j *RetentionJob) SetCheckInterval(interval time.Duration) {
+	if interval > 0 {
+		j.checkInterval = interval
+	}
+}
+
+// SetPrefix sets the storage key prefix of the archive files
+func (j *RetentionJob) SetPrefix(prefix string) {
+	if prefix != "" {
+		j.prefix = prefix
+	}
+}
+
+// Start begins the archival loop
+func (j *RetentionJob) Start(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&j.running, 0, 1) {
+		return nil // Already running
+	}
+
+	j.logger.Printf("[AuditRetention] Starting audit log retention job (retention %s, interval %s)", j.retention, j.checkInterval)
+
+	go j.archiveLoop(ctx)
+
+	return nil
+}
+
+// Stop stops the archival loop
+func (j *RetentionJob) Stop() {
+	if atomic.CompareAndSwapInt32(&j.running, 1, 0) {
+		close(j.stopCh)
+		<-j.doneCh
+		j.logger.Println("[AuditRetention] Audit log retention job stopped")
+	}
+}
+
+// IsRunning returns true if the job is running
+func (j *RetentionJob) IsRunning() bool {
+	return atomic.LoadInt32(&j.running) == 1
+}
+
+// GetStats returns the current statistics of the job
+func (j *RetentionJob) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"running":        j.IsRunning(),
+		"total_archived": atomic.LoadInt64(&j.totalArchived),
+	}
+}
+
+// archiveLoop is the main archival loop
+func (j *RetentionJob) archiveLoop(ctx context.Context) {
+	defer close(j.doneCh)
+
+	ticker := time.NewTicker(j.checkInterval)
+	defer ticker.Stop()
+
+	// Initial run
+	j.ArchiveExpired(ctx, time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-j.stopCh:
+			return
+		case <-ticker.C:
+			j.ArchiveExpired(ctx, time.Now())
+		}
+	}
+}
+
+// ArchiveExpired archives and deletes, one batch per archive file, every entry older than the
+// retention period at now. It returns the number of entries archived.
+func (j *RetentionJob) ArchiveExpired(ctx context.Context, now time.Time) int {
+	cutoff := now.Add(-j.retention)
+
+	archived := 0
+	for {
+		entries, err := j.store.ListBefore(ctx, cutoff, j.batchSize)
+		if err != nil {
+			j.logger.Printf("[AuditRetention] Error listing audit logs past retention: %v", err)
+			break
+		}
+		if len(entries) == 0 {
+			break
+		}
+
+		archive, err := j.archiveBatch(ctx, entries, cutoff, now)
+		if err != nil {
+			// The entries stay in the table and are archived again on the next run
+			j.logger.Printf("[AuditRetention] Error archiving %d audit logs: %v", len(entries), err)
+			break
+		}
+
+		archived += archive.Entries
+		j.logger.Printf("[AuditRetention] %d audit log(s) archived to %s", archive.Entries, archive.Location)
+		if len(entries) < j.batchSize {
+			break
+		}
+	}
+
+	if archived > 0 {
+		atomic.AddInt64(&j.totalArchived, int64(archived))
+	}
+	return archived
+}
+
+// archiveBatch uploads the entries as one gzip JSON file, then deletes them with the archive record.
+// The delete is limited to entries older than cutoff.
+func (j *RetentionJob) archiveBatch(ctx context.Context, entries []models.AuditLog, cutoff, now time.Time) (*models.AuditLogArchive, error) {
+	data, err := encodeArchive(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &models.AuditLogArchive{
+		ID:            uuid.New(),
+		Entries:       len(entries),
+		FromTimestamp: entries[0].Timestamp,
+		ToTimestamp:   entries[len(entries)-1].Timestamp,
+	}
+	key := path.Join(j.prefix, now.UTC().Format("2006/01"), fmt.Sprintf("audit-logs-%s-%s.json.gz",
+		archive.ToTimestamp.UTC().Format("20060102T150405Z"), archive.ID))
+
+	archive.Location, err = j.storage.Put(ctx, key, data, "application/gzip")
+	if err != nil {
+		return nil, fmt.Errorf("failed to store archive %s: %w", key, err)
+	}
+
+	ids := make([]uuid.UUID, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+	if err := j.store.DeleteArchived(ctx, archive, ids, cutoff); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// encodeArchive returns the entries as a gzip-compressed JSON array
+func encodeArchive(entries []models.AuditLog) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(entries); err != nil {
+		return nil, fmt.Errorf("failed to encode audit logs: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress audit logs: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,187 @@
+package audit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// fakeArchiveStore keeps audit logs in memory, deleting like the repository transaction does
+type fakeArchiveStore struct {
+	logs     map[uuid.UUID]models.AuditLog
+	archives []models.AuditLogArchive
+	failOn   error // returned by DeleteArchived when set
+}
+
+func (f *fakeArchiveStore) ListBefore(ctx context.Context, cutoff time.Time, limit int) ([]models.AuditLog, error) {
+	var logs []models.AuditLog
+	for _, entry := range f.logs {
+		if entry.Timestamp.Before(cutoff) {
+			logs = append(logs, entry)
+		}
+	}
+	sort.Slice(logs, func(i, k int) bool { return logs[i].Timestamp.Before(logs[k].Timestamp) })
+	if len(logs) > limit {
+		logs = logs[:limit]
+	}
+	return logs, nil
+}
+
+func (f *fakeArchiveStore) DeleteArchived(ctx context.Context, archive *models.AuditLogArchive, ids []uuid.UUID, cutoff time.Time) error {
+	if f.failOn != nil {
+		return f.failOn
+	}
+	for _, id := range ids {
+		if f.logs[id].Timestamp.Before(cutoff) {
+			delete(f.logs, id)
+		}
+	}
+	f.archives = append(f.archives, *archive)
+	return nil
+}
+
+// fakeArchiveStorage records the files written to it
+type fakeArchiveStorage struct {
+	files map[string][]byte
+}
+
+func (s *fakeArchiveStorage) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	s.files[key] = data
+	return "archive://" + key, nil
+}
+
+func newTestRetentionJob(logs ...models.AuditLog) (*RetentionJob, *fakeArchiveStore, *fakeArchiveStorage) {
+	store := &fakeArchiveStore{logs: make(map[uuid.UUID]models.AuditLog)}
+	for _, entry := range logs {
+		store.logs[entry.ID] = entry
+	}
+	files := &fakeArchiveStorage{files: make(map[string][]byte)}
+
+	job := NewRetentionJob(nil, files)
+	job.store = store
+	job.logger = log.New(io.Discard, "", 0)
+	return job, store, files
+}
+
+func testAuditLog(timestamp time.Time) models.AuditLog {
+	return models.AuditLog{
+		ID:           uuid.New(),
+		Timestamp:    timestamp,
+		ActorName:    models.SIDOTBotActor,
+		Acao:         "auth.login",
+		EntidadeTipo: "Usuario",
+		EntidadeID:   uuid.NewString(),
+		Severity:     models.SeverityInfo,
+	}
+}
+
+// decodeArchive decompresses an archive file
+func decodeArchive(t *testing.T, data []byte) []models.AuditLog {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Archive is not gzip: %v", err)
+	}
+	var logs []models.AuditLog
+	if err := json.NewDecoder(gz).Decode(&logs); err != nil {
+		t.Fatalf("Archive is not a JSON array of audit logs: %v", err)
+	}
+	return logs
+}
+
+// TestArchiveExpiredArchivesAndDeletesOldEntries verifies that entries past retention are archived
+// and removed while newer ones remain
+func TestArchiveExpiredArchivesAndDeletesOldEntries(t *testing.T) {
+	now := time.Date(2026, 10, 14, 3, 0, 0, 0, time.UTC)
+	oldest := testAuditLog(now.Add(-400 * 24 * time.Hour))
+	old := testAuditLog(now.Add(-370 * 24 * time.Hour))
+	recent := testAuditLog(now.Add(-30 * 24 * time.Hour))
+
+	job, store, files := newTestRetentionJob(oldest, old, recent)
+	job.SetRetention(365 * 24 * time.Hour)
+
+	if n := job.ArchiveExpired(context.Background(), now); n != 2 {
+		t.Fatalf("Expected 2 archived entries, got %d", n)
+	}
+
+	if len(store.logs) != 1 {
+		t.Fatalf("Expected 1 entry left in the table, got %d", len(store.logs))
+	}
+	if _, ok := store.logs[recent.ID]; !ok {
+		t.Error("Expected the entry inside the retention period to remain")
+	}
+
+	if len(store.archives) != 1 || len(files.files) != 1 {
+		t.Fatalf("Expected 1 archive recorded and stored, got %d and %d", len(store.archives), len(files.files))
+	}
+	archive := store.archives[0]
+	if archive.Entries != 2 || !archive.FromTimestamp.Equal(oldest.Timestamp) || !archive.ToTimestamp.Equal(old.Timestamp) {
+		t.Errorf("Unexpected archive record %+v", archive)
+	}
+
+	for key, data := range files.files {
+		if archive.Location != "archive://"+key {
+			t.Errorf("Expected archive location of %s, got %s", key, archive.Location)
+		}
+		archived := decodeArchive(t, data)
+		if len(archived) != 2 || archived[0].ID != oldest.ID || archived[1].ID != old.ID {
+			t.Errorf("Expected the 2 old entries in the archive, got %+v", archived)
+		}
+	}
+
+	// A later run has nothing left to archive
+	if n := job.ArchiveExpired(context.Background(), now); n != 0 {
+		t.Errorf("Expected no further archival, got %d", n)
+	}
+}
+
+// TestArchiveExpiredBatches verifies that large backlogs are split into one archive per batch
+func TestArchiveExpiredBatches(t *testing.T) {
+	now := time.Date(2026, 10, 14, 3, 0, 0, 0, time.UTC)
+	var logs []models.AuditLog
+	for i := 0; i < 5; i++ {
+		logs = append(logs, testAuditLog(now.Add(-DefaultRetention-time.Duration(i+1)*time.Hour)))
+	}
+
+	job, store, files := newTestRetentionJob(logs...)
+	job.batchSize = 2
+
+	if n := job.ArchiveExpired(context.Background(), now); n != 5 {
+		t.Fatalf("Expected 5 archived entries, got %d", n)
+	}
+	if len(store.logs) != 0 {
+		t.Errorf("Expected every entry to be deleted, got %d left", len(store.logs))
+	}
+	if len(store.archives) != 3 || len(files.files) != 3 {
+		t.Errorf("Expected 3 archives, got %d recorded and %d stored", len(store.archives), len(files.files))
+	}
+}
+
+// TestArchiveExpiredKeepsEntriesWhenDeleteFails verifies that entries are not lost when the transaction fails
+func TestArchiveExpiredKeepsEntriesWhenDeleteFails(t *testing.T) {
+	now := time.Date(2026, 10, 14, 3, 0, 0, 0, time.UTC)
+	old := testAuditLog(now.Add(-DefaultRetention - time.Hour))
+
+	job, store, _ := newTestRetentionJob(old)
+	store.failOn = errors.New("connection reset")
+
+	if n := job.ArchiveExpired(context.Background(), now); n != 0 {
+		t.Fatalf("Expected no archived entries, got %d", n)
+	}
+	if _, ok := store.logs[old.ID]; !ok {
+		t.Error("Expected the entry to stay in the table")
+	}
+	if len(store.archives) != 0 {
+		t.Errorf("Expected no archive record, got %d", len(store.archives))
+	}
+}
@@ -2,10 +2,12 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
 )
 
 // Test 1: Testar login com credenciais validas
@@ -227,23 +229,107 @@ func TestPasswordValidation(t *testing.T) {
 	}
 }
 
+// Testar politica de senha mais restritiva configurada via system settings
+func TestPasswordPolicyStricterRejectsDefaultAccepted(t *testing.T) {
+	strict := models.PasswordPolicyConfig{
+		MinLength:     12,
+		RequireUpper:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	tests := []struct {
+		name        string
+		password    string
+		expectError error
+	}{
+		{"too short for strict policy", "Valid-12!", ErrPasswordTooShort},
+		{"missing uppercase", "lowercase123!", ErrPasswordMissingUpper},
+		{"missing digit", "NoDigitsHere!!", ErrPasswordMissingDigit},
+		{"missing symbol", "NoSymbolHere123", ErrPasswordMissingSymbol},
+		{"meets strict policy", "Valid-Pass-123", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every case is accepted by the default policy
+			if err := ValidatePasswordWithPolicy(tt.password, models.DefaultPasswordPolicyConfig()); err != nil {
+				t.Fatalf("Default policy should accept %q, got %v", tt.password, err)
+			}
+
+			err := ValidatePasswordWithPolicy(tt.password, strict)
+			if tt.expectError == nil {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("Expected error %v, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+// Testar que ValidatePasswordStrength aplica a politica ativa do servico
+func TestAuthServiceSetPasswordPolicy(t *testing.T) {
+	defer SetActivePasswordPolicy(models.DefaultPasswordPolicyConfig())
+
+	service := NewAuthService(nil, NewMockUserRepository(), nil)
+	service.SetPasswordPolicy(models.PasswordPolicyConfig{MinLength: 10, RequireDigit: true})
+
+	if err := ValidatePasswordStrength("abcdefghij"); !errors.Is(err, ErrPasswordMissingDigit) {
+		t.Errorf("Expected ErrPasswordMissingDigit, got %v", err)
+	}
+	if err := ValidatePasswordStrength("abc12345"); err == nil || err.Error() != "password must be at least 10 characters" {
+		t.Errorf("Expected min length error for 10 characters, got %v", err)
+	}
+	if err := ValidatePasswordStrength("abcdefgh12"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// Testar leitura da politica de senha a partir do system setting
+func TestPasswordPolicySettingDefaults(t *testing.T) {
+	setting := &models.SystemSetting{Value: []byte(`{"require_digit": true}`)}
+	policy, err := setting.GetPasswordPolicyConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if policy.MinLength != MinPasswordLength || !policy.RequireDigit {
+		t.Errorf("Expected default min length with digit requirement, got %+v", policy)
+	}
+
+	invalid := &models.SystemSetting{Value: []byte(`{"min_length": 4}`)}
+	if _, err := invalid.GetPasswordPolicyConfig(); err == nil {
+		t.Error("Expected error for min_length below the floor")
+	}
+}
+
 // MockUserRepository for testing
 type MockUserRepository struct {
 	users map[string]*User
+	// accounts holds every account of an email, which is in several tenants when there is more than one
+	accounts map[string][]*User
 }
 
 func NewMockUserRepository() *MockUserRepository {
 	return &MockUserRepository{
-		users: make(map[string]*User),
+		users:    make(map[string]*User),
+		accounts: make(map[string][]*User),
 	}
 }
 
 func (r *MockUserRepository) AddUser(user *User) {
 	r.users[user.Email] = user
 	r.users[user.ID.String()] = user
+	r.accounts[user.Email] = append(r.accounts[user.Email], user)
 }
 
 func (r *MockUserRepository) GetByEmail(ctx context.Context, email string) (*User, error) {
+	if len(r.accounts[email]) > 1 {
+		return nil, ErrTenantRequired
+	}
 	user, ok := r.users[email]
 	if !ok {
 		return nil, ErrUserNotFound
@@ -251,6 +337,14 @@ func (r *MockUserRepository) GetByEmail(ctx context.Context, email string) (*Use
 	return user, nil
 }
 
+func (r *MockUserRepository) ListPasswordHashesByEmail(ctx context.Context, email string) ([]string, error) {
+	var hashes []string
+	for _, user := range r.accounts[email] {
+		hashes = append(hashes, user.PasswordHash)
+	}
+	return hashes, nil
+}
+
 func (r *MockUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*User, error) {
 	user, ok := r.users[id.String()]
 	if !ok {
@@ -337,6 +431,29 @@ func TestAuthServiceLogin(t *testing.T) {
 	}
 }
 
+// TestAuthServiceLoginTenantRequiredAfterPassword verifies that an email registered in several tenants is only
+// reported as needing a tenant once the password matches one of its accounts
+func TestAuthServiceLoginTenantRequiredAfterPassword(t *testing.T) {
+	userRepo := NewMockUserRepository()
+	for _, password := range []string{"senha-tenant-a", "senha-tenant-b"} {
+		hash, err := HashPassword(password)
+		if err != nil {
+			t.Fatalf("HashPassword returned error: %v", err)
+		}
+		tenantID := uuid.New()
+		userRepo.AddUser(&User{ID: uuid.New(), Email: "duplicado@sidot.gov.br", PasswordHash: hash, Role: "operador", TenantID: &tenantID, Ativo: true})
+	}
+	authService := NewAuthService(nil, userRepo, nil)
+	ctx := context.Background()
+
+	if _, err := authService.Login(ctx, "duplicado@sidot.gov.br", "senha-errada"); err != ErrInvalidCredentials {
+		t.Errorf("Expected ErrInvalidCredentials for a wrong password, got: %v", err)
+	}
+	if _, err := authService.Login(ctx, "duplicado@sidot.gov.br", "senha-tenant-b"); err != ErrTenantRequired {
+		t.Errorf("Expected ErrTenantRequired once the password matches, got: %v", err)
+	}
+}
+
 // Benchmark para hash de senha
 func BenchmarkHashPassword(b *testing.B) {
 	password := "benchmarkPassword123"
@@ -366,3 +483,176 @@ func BenchmarkValidateAccessToken(b *testing.B) {
 		_, _ = jwtService.ValidateAccessToken(token)
 	}
 }
+
+// memoryRefreshTokenStore is an in-memory RefreshTokenStore for testing
+type memoryRefreshTokenStore struct {
+	active  map[string]bool
+	revoked map[string]bool
+}
+
+func newMemoryRefreshTokenStore() *memoryRefreshTokenStore {
+	return &memoryRefreshTokenStore{
+		active:  make(map[string]bool),
+		revoked: make(map[string]bool),
+	}
+}
+
+func (m *memoryRefreshTokenStore) Register(ctx context.Context, tokenID string, ttl time.Duration) error {
+	m.active[tokenID] = true
+	return nil
+}
+
+func (m *memoryRefreshTokenStore) IsActive(ctx context.Context, tokenID string) (bool, error) {
+	return m.active[tokenID], nil
+}
+
+func (m *memoryRefreshTokenStore) Consume(ctx context.Context, tokenID string) (bool, error) {
+	wasActive := m.active[tokenID]
+	delete(m.active, tokenID)
+	return wasActive, nil
+}
+
+func (m *memoryRefreshTokenStore) Revoke(ctx context.Context, tokenID string, ttl time.Duration) error {
+	m.revoked[tokenID] = true
+	return nil
+}
+
+func (m *memoryRefreshTokenStore) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return m.revoked[tokenID], nil
+}
+
+// newRotationTestService creates an auth service with an in-memory token store and a test user
+func newRotationTestService(t *testing.T) (*AuthService, *JWTService, *User) {
+	t.Helper()
+
+	jwtService, err := NewJWTService(
+		"test-access-secret-key-32-chars!",
+		"test-refresh-secret-key-32chars!",
+		15*time.Minute,
+		7*24*time.Hour,
+	)
+	if err != nil {
+		t.Fatalf("Failed to create JWT service: %v", err)
+	}
+
+	userRepo := NewMockUserRepository()
+	user := &User{
+		ID:    uuid.New(),
+		Email: "gestor@sidot.gov.br",
+		Nome:  "Gestor Teste",
+		Role:  "gestor",
+		Ativo: true,
+	}
+	userRepo.AddUser(user)
+
+	service := NewAuthService(jwtService, userRepo, nil)
+	service.SetRefreshTokenStore(newMemoryRefreshTokenStore())
+	return service, jwtService, user
+}
+
+// Testar que um refresh token antigo nao pode ser reutilizado apos rotacao
+func TestRefreshTokenRotationRejectsReuse(t *testing.T) {
+	service, jwtService, user := newRotationTestService(t)
+	ctx := context.Background()
+
+	_, oldRefresh, err := jwtService.GenerateTokenPairWithTenant(user.ID.String(), user.Email, user.Role, "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to generate tokens: %v", err)
+	}
+	if err := service.registerRefreshToken(ctx, oldRefresh, LoginSource{}, nil); err != nil {
+		t.Fatalf("Failed to register refresh token: %v", err)
+	}
+
+	result, err := service.Refresh(ctx, oldRefresh)
+	if err != nil {
+		t.Fatalf("First refresh failed: %v", err)
+	}
+	if result.RefreshToken == oldRefresh {
+		t.Fatal("Refresh should issue a new refresh token")
+	}
+
+	// Reusing the rotated token must fail
+	if _, err := service.Refresh(ctx, oldRefresh); err != ErrTokenRevoked {
+		t.Errorf("Expected ErrTokenRevoked on reuse, got %v", err)
+	}
+	if _, err := service.ValidateRefreshToken(ctx, oldRefresh); err != ErrTokenRevoked {
+		t.Errorf("Expected ValidateRefreshToken to reject rotated token, got %v", err)
+	}
+
+	// The newly issued token keeps working
+	if _, err := service.Refresh(ctx, result.RefreshToken); err != nil {
+		t.Errorf("Refresh with rotated token failed: %v", err)
+	}
+}
+
+// Testar que logout revoga o refresh token
+func TestLogoutRevokesRefreshToken(t *testing.T) {
+	service, jwtService, user := newRotationTestService(t)
+	ctx := context.Background()
+
+	_, refresh, _ := jwtService.GenerateTokenPairWithTenant(user.ID.String(), user.Email, user.Role, "", "", false)
+	if err := service.registerRefreshToken(ctx, refresh, LoginSource{}, nil); err != nil {
+		t.Fatalf("Failed to register refresh token: %v", err)
+	}
+
+	if err := service.Logout(ctx, refresh); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+
+	if _, err := service.ValidateRefreshToken(ctx, refresh); err != ErrTokenRevoked {
+		t.Errorf("Expected ErrTokenRevoked after logout, got %v", err)
+	}
+	if _, err := service.Refresh(ctx, refresh); err != ErrTokenRevoked {
+		t.Errorf("Expected refresh to fail after logout, got %v", err)
+	}
+}
+
+// recordingLoginRecorder keeps recorded logins in memory
+type recordingLoginRecorder struct {
+	userIDs []uuid.UUID
+	sources []LoginSource
+	err     error
+}
+
+func (r *recordingLoginRecorder) RecordLogin(ctx context.Context, userID uuid.UUID, source LoginSource) error {
+	r.userIDs = append(r.userIDs, userID)
+	r.sources = append(r.sources, source)
+	return r.err
+}
+
+// Testar que o login bem-sucedido e registrado com IP e user agent e que falhas nao sao registradas
+func TestAuthServiceLoginRecordsLogin(t *testing.T) {
+	service, _, user := newRotationTestService(t)
+	passwordHash, err := HashPassword("SenhaSegura123!")
+	if err != nil {
+		t.Fatalf("HashPassword failed: %v", err)
+	}
+	user.PasswordHash = passwordHash
+	recorder := &recordingLoginRecorder{}
+	service.SetLoginRecorder(recorder)
+	ctx := context.Background()
+
+	source := LoginSource{IPAddress: "10.0.0.7", UserAgent: "Mozilla/5.0"}
+	if _, err := service.LoginFrom(ctx, user.Email, "SenhaSegura123!", source); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if len(recorder.userIDs) != 1 || recorder.userIDs[0] != user.ID || recorder.sources[0] != source {
+		t.Fatalf("Expected the login of %s from %+v to be recorded, got %v %v", user.ID, source, recorder.userIDs, recorder.sources)
+	}
+
+	if _, err := service.LoginFrom(ctx, user.Email, "wrongpassword", source); err != ErrInvalidCredentials {
+		t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+	}
+	if len(recorder.userIDs) != 1 {
+		t.Errorf("Expected a failed login not to be recorded, got %d logins", len(recorder.userIDs))
+	}
+
+	// A failure to record the login does not fail it
+	recorder.err = errors.New("database unavailable")
+	if _, err := service.Login(ctx, user.Email, "SenhaSegura123!"); err != nil {
+		t.Errorf("Expected the login to succeed when recording fails, got %v", err)
+	}
+	if len(recorder.userIDs) != 2 {
+		t.Errorf("Expected the second login to be recorded, got %d logins", len(recorder.userIDs))
+	}
+}
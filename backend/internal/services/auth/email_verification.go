@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sidot/backend/internal/models"
+)
+
+// EmailVerificationTokenDuration is how long the link of a verification email stays valid
+const EmailVerificationTokenDuration = 48 * time.Hour
+
+var (
+	// ErrEmailNotVerified is returned when an unverified user signs in after the grace period
+	ErrEmailNotVerified = errors.New("email has not been verified")
+
+	// ErrInvalidVerificationToken is returned when the verification token is unknown, used or expired
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+
+	// ErrEmailVerificationUnavailable is returned when verification emails cannot be sent (no Redis or SMTP)
+	ErrEmailVerificationUnavailable = errors.New("email verification is not available")
+)
+
+var (
+	// activeEmailVerificationPolicy controls whether new users must verify their email
+	activeEmailVerificationPolicy   models.EmailVerificationConfig
+	activeEmailVerificationPolicyMu sync.RWMutex
+)
+
+// SetActiveEmailVerificationPolicy replaces the policy applied to new users and logins
+func SetActiveEmailVerificationPolicy(policy models.EmailVerificationConfig) {
+	activeEmailVerificationPolicyMu.Lock()
+	defer activeEmailVerificationPolicyMu.Unlock()
+	activeEmailVerificationPolicy = policy
+}
+
+// ActiveEmailVerificationPolicy returns the policy currently applied to new users and logins
+func ActiveEmailVerificationPolicy() models.EmailVerificationConfig {
+	activeEmailVerificationPolicyMu.RLock()
+	defer activeEmailVerificationPolicyMu.RUnlock()
+	return activeEmailVerificationPolicy
+}
+
+// VerificationTokenStore stores the pending email verification tokens
+type VerificationTokenStore interface {
+	// Save stores the token of the user until ttl elapses
+	Save(ctx context.Context, token, userID string, ttl time.Duration) error
+
+	// Consume removes the token and returns its user, or ErrInvalidVerificationToken
+	Consume(ctx context.Context, token string) (string, error)
+}
+
+// EmailVerifier records the verified emails (implemented by repository.UserRepository)
+type EmailVerifier interface {
+	MarkEmailVerified(ctx context.Context, userID uuid.UUID) error
+}
+
+// VerificationMailer sends the verification link to the user (implemented by notification.EmailService)
+type VerificationMailer interface {
+	IsConfigured() bool
+	SendEmailVerification(ctx context.Context, to, nome, locale, verificationURL string, expiresAt time.Time) error
+}
+
+// RedisVerificationTokenStore implements VerificationTokenStore with one expiring Redis key per token
+type RedisVerificationTokenStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisVerificationTokenStore creates a Redis-backed verification token store
+func NewRedisVerificationTokenStore(client *redis.Client) *RedisVerificationTokenStore {
+	return &RedisVerificationTokenStore{
+		client:    client,
+		keyPrefix: "email_verification",
+	}
+}
+
+func (s *RedisVerificationTokenStore) key(token string) string {
+	return fmt.Sprintf("%s:%s", s.keyPrefix, token)
+}
+
+// Save stores the token of the user until ttl elapses
+func (s *RedisVerificationTokenStore) Save(ctx context.Context, token, userID string, ttl time.Duration) error {
+	return s.client.Set(ctx, s.key(token), userID, ttl).Err()
+}
+
+// Consume reads and deletes the token in one transaction, so a link verifies the email only once
+func (s *RedisVerificationTokenStore) Consume(ctx context.Context, token string) (string, error) {
+	key := s.key(token)
+	pipe := s.client.TxPipeline()
+	get := pipe.Get(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return "", err
+	}
+
+	userID, err := get.Result()
+	if err == redis.Nil {
+		return "", ErrInvalidVerificationToken
+	}
+	return userID, err
+}
+
+// SetEmailVerification sets where verified emails are recorded and how the links are sent
+// The links point to verifyURL with the token in the "token" query parameter
+func (s *AuthService) SetEmailVerification(verifier EmailVerifier, mailer VerificationMailer, verifyURL string) {
+	s.emailVerifier = verifier
+	s.verificationMailer = mailer
+	s.verifyURL = verifyURL
+}
+
+// SetVerificationTokenStore replaces the store of the email verification tokens
+func (s *AuthService) SetVerificationTokenStore(store VerificationTokenStore) {
+	s.verificationTokens = store
+}
+
+// EmailVerificationAvailable reports whether verification emails can be sent and confirmed
+func (s *AuthService) EmailVerificationAvailable() bool {
+	return s.verificationTokens != nil && s.emailVerifier != nil &&
+		s.verificationMailer != nil && s.verificationMailer.IsConfigured()
+}
+
+// SendEmailVerification emails a new verification link to the user
+func (s *AuthService) SendEmailVerification(ctx context.Context, user *models.User) error {
+	if !s.EmailVerificationAvailable() {
+		return ErrEmailVerificationUnavailable
+	}
+
+	token, err := generateVerificationToken()
+	if err != nil {
+		return err
+	}
+	if err := s.verificationTokens.Save(ctx, token, user.ID.String(), EmailVerificationTokenDuration); err != nil {
+		return err
+	}
+
+	link := strings.TrimRight(s.verifyURL, "/") + "?token=" + url.QueryEscape(token)
+	expiresAt := time.Now().Add(EmailVerificationTokenDuration)
+	return s.verificationMailer.SendEmailVerification(ctx, user.Email, user.Nome, user.PreferredLocale(), link, expiresAt)
+}
+
+// VerifyEmail consumes a verification token and marks the email of its user as verified
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	if s.verificationTokens == nil || s.emailVerifier == nil {
+		return ErrEmailVerificationUnavailable
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrInvalidVerificationToken
+	}
+
+	userID, err := s.verificationTokens.Consume(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	return s.emailVerifier.MarkEmailVerified(ctx, id)
+}
+
+// emailVerificationExpired reports whether the user must verify its email before signing in
+// Unverified users sign in during the grace period after their creation; disabling the policy lets them in
+func emailVerificationExpired(user *User) bool {
+	policy := ActiveEmailVerificationPolicy()
+	if !policy.Enabled || user.EmailVerified {
+		return false
+	}
+	grace := time.Duration(policy.GraceHours) * time.Hour
+	return time.Since(user.CreatedAt) >= grace
+}
+
+// generateVerificationToken generates a random hex token for the verification link
+func generateVerificationToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// memoryVerificationTokenStore keeps the verification tokens in memory
+type memoryVerificationTokenStore struct {
+	tokens map[string]string
+}
+
+func (s *memoryVerificationTokenStore) Save(ctx context.Context, token, userID string, ttl time.Duration) error {
+	s.tokens[token] = userID
+	return nil
+}
+
+func (s *memoryVerificationTokenStore) Consume(ctx context.Context, token string) (string, error) {
+	userID, ok := s.tokens[token]
+	if !ok {
+		return "", ErrInvalidVerificationToken
+	}
+	delete(s.tokens, token)
+	return userID, nil
+}
+
+// userEmailVerifier marks the email of the test user as verified
+type userEmailVerifier struct {
+	user *User
+}
+
+func (v *userEmailVerifier) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	if userID != v.user.ID {
+		return ErrUserNotFound
+	}
+	v.user.EmailVerified = true
+	return nil
+}
+
+// recordingMailer records the verification links sent
+type recordingMailer struct {
+	links []string
+}
+
+func (m *recordingMailer) IsConfigured() bool {
+	return true
+}
+
+func (m *recordingMailer) SendEmailVerification(ctx context.Context, to, nome, locale, verificationURL string, expiresAt time.Time) error {
+	m.links = append(m.links, verificationURL)
+	return nil
+}
+
+// newEmailVerificationTestService creates an auth service with email verification and an unverified user created now
+func newEmailVerificationTestService(t *testing.T) (*AuthService, *recordingMailer, *User) {
+	t.Helper()
+
+	service, user := newSessionTestService(t)
+	user.EmailVerified = false
+	user.CreatedAt = time.Now()
+
+	mailer := &recordingMailer{}
+	service.SetVerificationTokenStore(&memoryVerificationTokenStore{tokens: make(map[string]string)})
+	service.SetEmailVerification(&userEmailVerifier{user: user}, mailer, "https://sidot.example/verify-email")
+
+	SetActiveEmailVerificationPolicy(models.EmailVerificationConfig{Enabled: true})
+	t.Cleanup(func() { SetActiveEmailVerificationPolicy(models.EmailVerificationConfig{}) })
+	return service, mailer, user
+}
+
+// Testar que o login de usuario com email nao verificado e rejeitado apos o periodo de carencia
+func TestLoginRejectsUnverifiedEmail(t *testing.T) {
+	service, _, user := newEmailVerificationTestService(t)
+	ctx := context.Background()
+
+	if _, err := service.Login(ctx, user.Email, "SenhaSegura123!"); err != ErrEmailNotVerified {
+		t.Errorf("Expected ErrEmailNotVerified, got %v", err)
+	}
+
+	// A wrong password is still reported as invalid credentials
+	if _, err := service.Login(ctx, user.Email, "SenhaErrada123!"); err != ErrInvalidCredentials {
+		t.Errorf("Expected ErrInvalidCredentials for a wrong password, got %v", err)
+	}
+
+	// During the grace period the user signs in, and the refresh stops once it ends
+	SetActiveEmailVerificationPolicy(models.EmailVerificationConfig{Enabled: true, GraceHours: 24})
+	result, err := service.Login(ctx, user.Email, "SenhaSegura123!")
+	if err != nil {
+		t.Fatalf("Expected the login during the grace period, got %v", err)
+	}
+
+	user.CreatedAt = time.Now().Add(-25 * time.Hour)
+	if _, err := service.Refresh(ctx, result.RefreshToken); err != ErrEmailNotVerified {
+		t.Errorf("Expected the refresh to fail after the grace period, got %v", err)
+	}
+
+	// Disabling the verification lets unverified users in
+	SetActiveEmailVerificationPolicy(models.EmailVerificationConfig{})
+	if _, err := service.Login(ctx, user.Email, "SenhaSegura123!"); err != nil {
+		t.Errorf("Expected the login without email verification, got %v", err)
+	}
+}
+
+// Testar que o link de verificacao confirma o email uma unica vez e libera o login
+func TestVerifyEmail(t *testing.T) {
+	service, mailer, user := newEmailVerificationTestService(t)
+	ctx := context.Background()
+
+	if err := service.SendEmailVerification(ctx, &models.User{ID: user.ID, Email: user.Email, Nome: user.Nome}); err != nil {
+		t.Fatalf("SendEmailVerification failed: %v", err)
+	}
+	if len(mailer.links) != 1 {
+		t.Fatalf("Expected 1 verification email, got %d", len(mailer.links))
+	}
+
+	link, err := url.Parse(mailer.links[0])
+	if err != nil {
+		t.Fatalf("Invalid verification link %q: %v", mailer.links[0], err)
+	}
+	token := link.Query().Get("token")
+	if link.Host != "sidot.example" || link.Path != "/verify-email" || token == "" {
+		t.Fatalf("Unexpected verification link %q", mailer.links[0])
+	}
+
+	if err := service.VerifyEmail(ctx, "token-desconhecido"); err != ErrInvalidVerificationToken {
+		t.Errorf("Expected ErrInvalidVerificationToken for an unknown token, got %v", err)
+	}
+
+	if err := service.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("VerifyEmail failed: %v", err)
+	}
+	if !user.EmailVerified {
+		t.Fatal("Expected the email to be verified")
+	}
+	if err := service.VerifyEmail(ctx, token); err != ErrInvalidVerificationToken {
+		t.Errorf("Expected the token to be used only once, got %v", err)
+	}
+
+	if _, err := service.Login(ctx, user.Email, "SenhaSegura123!"); err != nil {
+		t.Errorf("Expected the login after the verification, got %v", err)
+	}
+}
+
+// Testar leitura da verificacao de email a partir do system setting
+func TestEmailVerificationSetting(t *testing.T) {
+	setting := &models.SystemSetting{Value: []byte(`{"enabled": true, "grace_hours": 24}`)}
+	config, err := setting.GetEmailVerificationConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !config.Enabled || config.GraceHours != 24 {
+		t.Errorf("Unexpected config %+v", config)
+	}
+
+	invalid := &models.SystemSetting{Value: []byte(`{"enabled": true, "grace_hours": -1}`)}
+	if _, err := invalid.GetEmailVerificationConfig(); err == nil {
+		t.Error("Expected error for a negative grace period")
+	}
+}
@@ -3,6 +3,10 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,10 +14,53 @@ import (
 )
 
 const (
-	// ImpersonationTokenDuration is the duration for impersonation tokens (1 hour)
-	ImpersonationTokenDuration = 1 * time.Hour
+	// ImpersonationTokenDuration is the default duration for impersonation tokens (1 hour)
+	ImpersonationTokenDuration = models.ImpersonationDefaultDurationMinutes * time.Minute
+
+	// MaxImpersonationTokenDuration is the hard limit for impersonation tokens (4 hours)
+	// Longer durations, whether configured or requested, are clamped to it
+	MaxImpersonationTokenDuration = models.ImpersonationMaxDurationMinutes * time.Minute
 )
 
+// ErrImpersonationReasonRequired is returned when an impersonation is requested without a reason
+var ErrImpersonationReasonRequired = errors.New("impersonation reason is required")
+
+var (
+	// activeImpersonationPolicy sets the duration of impersonation tokens
+	activeImpersonationPolicy   = models.DefaultImpersonationPolicyConfig()
+	activeImpersonationPolicyMu sync.RWMutex
+)
+
+// SetActiveImpersonationPolicy replaces the policy applied to new impersonation tokens
+func SetActiveImpersonationPolicy(policy models.ImpersonationPolicyConfig) {
+	activeImpersonationPolicyMu.Lock()
+	defer activeImpersonationPolicyMu.Unlock()
+	activeImpersonationPolicy = policy
+}
+
+// ActiveImpersonationPolicy returns the policy currently applied to new impersonation tokens
+func ActiveImpersonationPolicy() models.ImpersonationPolicyConfig {
+	activeImpersonationPolicyMu.RLock()
+	defer activeImpersonationPolicyMu.RUnlock()
+	return activeImpersonationPolicy
+}
+
+// ImpersonationDuration returns the duration of a new impersonation token
+// A zero requested duration uses the active policy; the result never exceeds MaxImpersonationTokenDuration
+func ImpersonationDuration(requested time.Duration) time.Duration {
+	duration := requested
+	if duration <= 0 {
+		duration = time.Duration(ActiveImpersonationPolicy().DurationMinutes) * time.Minute
+	}
+	if duration <= 0 {
+		duration = ImpersonationTokenDuration
+	}
+	if duration > MaxImpersonationTokenDuration {
+		duration = MaxImpersonationTokenDuration
+	}
+	return duration
+}
+
 // ImpersonationClaims extends standard claims with impersonation info
 type ImpersonationClaims struct {
 	OriginalAdminID string `json:"original_admin_id"`
@@ -30,6 +77,7 @@ type ImpersonationService struct {
 	jwtService   *JWTService
 	userRepo     UserRepository
 	auditLogRepo AuditLogger
+	sessions     ImpersonationSessionStore
 }
 
 // NewImpersonationService creates a new impersonation service
@@ -41,23 +89,38 @@ func NewImpersonationService(jwtService *JWTService, userRepo UserRepository, au
 	}
 }
 
+// SetSessionStore sets the store used to list and revoke active impersonation sessions
+// Without a store, impersonation tokens cannot be revoked before they expire
+func (s *ImpersonationService) SetSessionStore(store ImpersonationSessionStore) {
+	s.sessions = store
+}
+
 // ImpersonationResult contains the result of an impersonation request
 type ImpersonationResult struct {
 	AccessToken string    `json:"access_token"`
+	TokenID     string    `json:"jti"`
 	ExpiresAt   time.Time `json:"expires_at"`
 	ExpiresIn   int       `json:"expires_in"` // seconds
 	User        *User     `json:"user"`
 }
 
 // GenerateImpersonationToken creates a short-lived JWT for impersonating a user
-// The token includes the original admin ID in the claims for audit purposes
+// The token includes the original admin ID in the claims for audit purposes.
+// requestedDuration is clamped by ImpersonationDuration; zero uses the configured duration.
 func (s *ImpersonationService) GenerateImpersonationToken(
 	ctx context.Context,
 	adminUserID uuid.UUID,
 	targetUserID uuid.UUID,
+	reason string,
+	requestedDuration time.Duration,
 	ipAddress *string,
 	userAgent *string,
 ) (*ImpersonationResult, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrImpersonationReasonRequired
+	}
+
 	// Get the target user
 	targetUser, err := s.userRepo.GetByID(ctx, targetUserID)
 	if err != nil {
@@ -82,25 +145,45 @@ func (s *ImpersonationService) GenerateImpersonationToken(
 		tenantID = targetUser.TenantID.String()
 	}
 
-	// Generate a short-lived access token for the target user
-	// We use the standard token generation but with a shorter duration
-	now := time.Now()
-	expiresAt := now.Add(ImpersonationTokenDuration)
+	duration := ImpersonationDuration(requestedDuration)
 
-	accessToken, err := s.jwtService.GenerateAccessTokenWithTenant(
+	// Generate a short-lived access token for the target user
+	// The token carries the original admin ID and its jti identifies the impersonation session
+	accessToken, tokenID, expiresAt, err := s.jwtService.GenerateImpersonationToken(
 		targetUser.ID.String(),
 		targetUser.Email,
 		targetUser.Role,
 		hospitalID,
 		tenantID,
 		targetUser.IsSuperAdmin,
+		adminUser.ID.String(),
+		duration,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if s.sessions != nil {
+		session := &ImpersonationSession{
+			TokenID:         tokenID,
+			AdminID:         adminUser.ID.String(),
+			AdminEmail:      adminUser.Email,
+			TargetUserID:    targetUser.ID.String(),
+			TargetUserEmail: targetUser.Email,
+			TargetUserRole:  targetUser.Role,
+			TargetTenantID:  tenantID,
+			Reason:          reason,
+			StartedAt:       time.Now(),
+			ExpiresAt:       expiresAt,
+		}
+		// An untracked session could not be revoked, so the token is not handed out
+		if err := s.sessions.Track(ctx, session); err != nil {
+			return nil, fmt.Errorf("failed to track impersonation session: %w", err)
+		}
+	}
+
 	// Log the impersonation action to audit logs
-	err = s.logImpersonationAction(ctx, adminUser, targetUser, ipAddress, userAgent)
+	err = s.logImpersonationAction(ctx, adminUser, targetUser, tokenID, reason, duration, ipAddress, userAgent)
 	if err != nil {
 		// Log error but don't fail the impersonation
 		// The impersonation is still valid even if audit logging fails
@@ -108,17 +191,121 @@ func (s *ImpersonationService) GenerateImpersonationToken(
 
 	return &ImpersonationResult{
 		AccessToken: accessToken,
+		TokenID:     tokenID,
 		ExpiresAt:   expiresAt,
-		ExpiresIn:   int(ImpersonationTokenDuration.Seconds()),
+		ExpiresIn:   int(duration.Seconds()),
 		User:        targetUser,
 	}, nil
 }
 
+// ListActiveSessions returns the impersonation sessions that have not expired or been revoked
+func (s *ImpersonationService) ListActiveSessions(ctx context.Context) ([]ImpersonationSession, error) {
+	if s.sessions == nil {
+		return []ImpersonationSession{}, nil
+	}
+	return s.sessions.List(ctx)
+}
+
+// RevokeSession ends an impersonation session immediately
+// The token ID stays in the revocation set until the token would have expired
+func (s *ImpersonationService) RevokeSession(
+	ctx context.Context,
+	adminUserID uuid.UUID,
+	tokenID string,
+	ipAddress *string,
+	userAgent *string,
+) (*ImpersonationSession, error) {
+	if s.sessions == nil {
+		return nil, ErrImpersonationSessionNotFound
+	}
+
+	session, err := s.sessions.Get(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Until(session.ExpiresAt)
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	if err := s.sessions.Revoke(ctx, tokenID, ttl); err != nil {
+		return nil, err
+	}
+
+	if err := s.logRevocation(ctx, adminUserID, session, ipAddress, userAgent); err != nil {
+		// The session is already revoked; a failed audit entry doesn't undo it
+	}
+
+	return session, nil
+}
+
+// IsImpersonationRevoked reports whether an impersonation token was revoked
+func (s *ImpersonationService) IsImpersonationRevoked(ctx context.Context, tokenID string) (bool, error) {
+	if s.sessions == nil {
+		return false, nil
+	}
+	return s.sessions.IsRevoked(ctx, tokenID)
+}
+
+// logRevocation logs the termination of an impersonation session to audit logs
+func (s *ImpersonationService) logRevocation(
+	ctx context.Context,
+	adminUserID uuid.UUID,
+	session *ImpersonationSession,
+	ipAddress *string,
+	userAgent *string,
+) error {
+	if s.auditLogRepo == nil {
+		return nil
+	}
+
+	actorName := adminUserID.String()
+	if adminUser, err := s.userRepo.GetByID(ctx, adminUserID); err == nil {
+		actorName = adminUser.Email
+	}
+
+	detalhes := map[string]any{
+		"jti":               session.TokenID,
+		"admin_id":          session.AdminID,
+		"admin_email":       session.AdminEmail,
+		"target_user_id":    session.TargetUserID,
+		"target_user_email": session.TargetUserEmail,
+		"reason":            session.Reason,
+		"started_at":        session.StartedAt,
+		"expires_at":        session.ExpiresAt,
+		"revoked_by":        adminUserID.String(),
+	}
+	if session.TargetTenantID != "" {
+		detalhes["target_tenant_id"] = session.TargetTenantID
+	}
+
+	detalhesJSON, err := json.Marshal(detalhes)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.auditLogRepo.Create(ctx, &models.CreateAuditLogInput{
+		UsuarioID:    &adminUserID,
+		ActorName:    actorName,
+		Acao:         ActionImpersonationRevoke,
+		EntidadeTipo: "ImpersonationSession",
+		EntidadeID:   session.TokenID,
+		Severity:     models.SeverityWarn,
+		Detalhes:     detalhesJSON,
+		IPAddress:    ipAddress,
+		UserAgent:    userAgent,
+	})
+	return err
+}
+
 // logImpersonationAction logs the impersonation event to audit logs
 func (s *ImpersonationService) logImpersonationAction(
 	ctx context.Context,
 	adminUser *User,
 	targetUser *User,
+	tokenID string,
+	reason string,
+	duration time.Duration,
 	ipAddress *string,
 	userAgent *string,
 ) error {
@@ -133,6 +320,9 @@ func (s *ImpersonationService) logImpersonationAction(
 		"target_user_id":    targetUser.ID.String(),
 		"target_user_role":  targetUser.Role,
 		"impersonation":     true,
+		"jti":               tokenID,
+		"reason":            reason,
+		"duration_minutes":  int(duration.Minutes()),
 	}
 
 	if targetUser.TenantID != nil {
@@ -163,10 +353,13 @@ func (s *ImpersonationService) logImpersonationAction(
 
 // Action constants for impersonation
 const (
-	ActionUserImpersonate  = "admin.user.impersonate"
-	ActionUserBan          = "admin.user.ban"
-	ActionUserUnban        = "admin.user.unban"
-	ActionUserRoleChange   = "admin.user.role_change"
-	ActionUserResetPwd     = "admin.user.reset_password"
-	ActionHospitalReassign = "admin.hospital.reassign"
+	ActionUserImpersonate     = "admin.user.impersonate"
+	ActionImpersonationRevoke = "admin.impersonation.revoke"
+	ActionUserBan             = "admin.user.ban"
+	ActionUserUnban           = "admin.user.unban"
+	ActionUserRoleChange      = "admin.user.role_change"
+	ActionUserResetPwd        = "admin.user.reset_password"
+	ActionHospitalReassign    = "admin.hospital.reassign"
+	ActionHospitalDelete      = "admin.hospital.delete"
+	ActionHospitalRestore     = "admin.hospital.restore"
 )
@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// memoryImpersonationSessionStore is an in-memory ImpersonationSessionStore for testing
+type memoryImpersonationSessionStore struct {
+	sessions map[string]ImpersonationSession
+	revoked  map[string]bool
+}
+
+func newMemoryImpersonationSessionStore() *memoryImpersonationSessionStore {
+	return &memoryImpersonationSessionStore{
+		sessions: make(map[string]ImpersonationSession),
+		revoked:  make(map[string]bool),
+	}
+}
+
+func (m *memoryImpersonationSessionStore) Track(ctx context.Context, session *ImpersonationSession) error {
+	m.sessions[session.TokenID] = *session
+	return nil
+}
+
+func (m *memoryImpersonationSessionStore) List(ctx context.Context) ([]ImpersonationSession, error) {
+	sessions := make([]ImpersonationSession, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (m *memoryImpersonationSessionStore) Get(ctx context.Context, tokenID string) (*ImpersonationSession, error) {
+	session, ok := m.sessions[tokenID]
+	if !ok {
+		return nil, ErrImpersonationSessionNotFound
+	}
+	return &session, nil
+}
+
+func (m *memoryImpersonationSessionStore) Revoke(ctx context.Context, tokenID string, ttl time.Duration) error {
+	m.revoked[tokenID] = true
+	delete(m.sessions, tokenID)
+	return nil
+}
+
+func (m *memoryImpersonationSessionStore) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return m.revoked[tokenID], nil
+}
+
+// recordingAuditLogger keeps created audit log entries in memory
+type recordingAuditLogger struct {
+	entries []*models.CreateAuditLogInput
+}
+
+func (r *recordingAuditLogger) Create(ctx context.Context, input *models.CreateAuditLogInput) (*models.AuditLog, error) {
+	r.entries = append(r.entries, input)
+	return &models.AuditLog{ID: uuid.New()}, nil
+}
+
+// newImpersonationTestService creates an impersonation service with an admin and a target user
+func newImpersonationTestService(t *testing.T) (*ImpersonationService, *JWTService, *memoryImpersonationSessionStore, *recordingAuditLogger, *User, *User) {
+	t.Helper()
+
+	jwtService, err := NewJWTService("test-access-secret-key-32-chars!", "test-refresh-secret-key-32chars!", 15*time.Minute, 7*24*time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create JWT service: %v", err)
+	}
+
+	userRepo := NewMockUserRepository()
+	admin := &User{ID: uuid.New(), Email: "superadmin@sidot.gov.br", Role: "admin", IsSuperAdmin: true, Ativo: true}
+	target := &User{ID: uuid.New(), Email: "operador@sidot.gov.br", Role: "operador", Ativo: true}
+	userRepo.AddUser(admin)
+	userRepo.AddUser(target)
+
+	store := newMemoryImpersonationSessionStore()
+	auditLog := &recordingAuditLogger{}
+	service := NewImpersonationService(jwtService, userRepo, auditLog)
+	service.SetSessionStore(store)
+	return service, jwtService, store, auditLog, admin, target
+}
+
+// Testar que o token de impersonacao carrega o admin original e e rastreado como sessao ativa
+func TestImpersonationTokenIsTracked(t *testing.T) {
+	service, jwtService, _, _, admin, target := newImpersonationTestService(t)
+	ctx := context.Background()
+
+	result, err := service.GenerateImpersonationToken(ctx, admin.ID, target.ID, "Suporte ao chamado #123", 0, nil, nil)
+	if err != nil {
+		t.Fatalf("GenerateImpersonationToken returned error: %v", err)
+	}
+
+	claims, err := jwtService.ValidateAccessToken(result.AccessToken)
+	if err != nil {
+		t.Fatalf("Impersonation token should be a valid access token: %v", err)
+	}
+	if !claims.IsImpersonation || claims.OriginalAdminID != admin.ID.String() || claims.UserID != target.ID.String() {
+		t.Errorf("Unexpected impersonation claims: %+v", claims)
+	}
+	if claims.ID != result.TokenID {
+		t.Errorf("Expected jti %s, got %s", result.TokenID, claims.ID)
+	}
+	if remaining := time.Until(claims.ExpiresAt.Time); remaining > ImpersonationTokenDuration || remaining < ImpersonationTokenDuration-time.Minute {
+		t.Errorf("Expected token to expire after %v, got %v", ImpersonationTokenDuration, remaining)
+	}
+
+	sessions, err := service.ListActiveSessions(ctx)
+	if err != nil {
+		t.Fatalf("ListActiveSessions returned error: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].TokenID != result.TokenID || sessions[0].AdminID != admin.ID.String() || sessions[0].TargetUserID != target.ID.String() {
+		t.Errorf("Expected the impersonation session to be listed, got %+v", sessions)
+	}
+}
+
+// Testar que uma sessao revogada e marcada como revogada, deixa de ser listada e gera auditoria
+func TestRevokeImpersonationSession(t *testing.T) {
+	service, _, _, auditLog, admin, target := newImpersonationTestService(t)
+	ctx := context.Background()
+
+	result, err := service.GenerateImpersonationToken(ctx, admin.ID, target.ID, "Suporte ao chamado #123", 0, nil, nil)
+	if err != nil {
+		t.Fatalf("GenerateImpersonationToken returned error: %v", err)
+	}
+
+	session, err := service.RevokeSession(ctx, admin.ID, result.TokenID, nil, nil)
+	if err != nil {
+		t.Fatalf("RevokeSession returned error: %v", err)
+	}
+	if session.TargetUserID != target.ID.String() {
+		t.Errorf("Expected revoked session for target %s, got %s", target.ID, session.TargetUserID)
+	}
+
+	revoked, err := service.IsImpersonationRevoked(ctx, result.TokenID)
+	if err != nil || !revoked {
+		t.Errorf("Expected token to be revoked, got %v (%v)", revoked, err)
+	}
+	if sessions, _ := service.ListActiveSessions(ctx); len(sessions) != 0 {
+		t.Errorf("Expected no active sessions after revocation, got %d", len(sessions))
+	}
+
+	last := auditLog.entries[len(auditLog.entries)-1]
+	if last.Acao != ActionImpersonationRevoke || last.EntidadeID != result.TokenID || last.Severity != models.SeverityWarn {
+		t.Errorf("Unexpected revocation audit entry: %+v", last)
+	}
+	var detalhes map[string]any
+	json.Unmarshal(last.Detalhes, &detalhes)
+	if detalhes["target_user_id"] != target.ID.String() || detalhes["revoked_by"] != admin.ID.String() {
+		t.Errorf("Unexpected revocation audit detalhes: %v", detalhes)
+	}
+
+	// Revoking again reports the session as gone
+	if _, err := service.RevokeSession(ctx, admin.ID, result.TokenID, nil, nil); !errors.Is(err, ErrImpersonationSessionNotFound) {
+		t.Errorf("Expected ErrImpersonationSessionNotFound, got %v", err)
+	}
+}
+
+// Testar que a impersonacao sem motivo e rejeitada e que o motivo e registrado na auditoria
+func TestImpersonationRequiresReason(t *testing.T) {
+	service, _, store, auditLog, admin, target := newImpersonationTestService(t)
+	ctx := context.Background()
+
+	for _, reason := range []string{"", "   "} {
+		if _, err := service.GenerateImpersonationToken(ctx, admin.ID, target.ID, reason, 0, nil, nil); !errors.Is(err, ErrImpersonationReasonRequired) {
+			t.Errorf("Expected ErrImpersonationReasonRequired for reason %q, got %v", reason, err)
+		}
+	}
+	if len(store.sessions) != 0 || len(auditLog.entries) != 0 {
+		t.Fatal("Expected no session or audit entry without a reason")
+	}
+
+	result, err := service.GenerateImpersonationToken(ctx, admin.ID, target.ID, "  Suporte ao chamado #123 ", 0, nil, nil)
+	if err != nil {
+		t.Fatalf("GenerateImpersonationToken returned error: %v", err)
+	}
+
+	var detalhes map[string]any
+	json.Unmarshal(auditLog.entries[0].Detalhes, &detalhes)
+	if detalhes["reason"] != "Suporte ao chamado #123" {
+		t.Errorf("Expected reason in audit detalhes, got %v", detalhes["reason"])
+	}
+	if store.sessions[result.TokenID].Reason != "Suporte ao chamado #123" {
+		t.Errorf("Expected reason on the tracked session, got %q", store.sessions[result.TokenID].Reason)
+	}
+}
+
+// Testar que a duracao configurada e usada por padrao e que duracoes longas sao limitadas ao maximo
+func TestImpersonationDurationIsClamped(t *testing.T) {
+	service, jwtService, _, _, admin, target := newImpersonationTestService(t)
+	ctx := context.Background()
+
+	SetActiveImpersonationPolicy(models.ImpersonationPolicyConfig{DurationMinutes: 30})
+	t.Cleanup(func() { SetActiveImpersonationPolicy(models.DefaultImpersonationPolicyConfig()) })
+
+	tests := []struct {
+		name      string
+		requested time.Duration
+		expected  time.Duration
+	}{
+		{"configured duration by default", 0, 30 * time.Minute},
+		{"shorter requested duration", 10 * time.Minute, 10 * time.Minute},
+		{"over-long requested duration", 24 * time.Hour, MaxImpersonationTokenDuration},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := service.GenerateImpersonationToken(ctx, admin.ID, target.ID, "Auditoria", tc.requested, nil, nil)
+			if err != nil {
+				t.Fatalf("GenerateImpersonationToken returned error: %v", err)
+			}
+			if result.ExpiresIn != int(tc.expected.Seconds()) {
+				t.Errorf("Expected expires_in %v, got %ds", tc.expected, result.ExpiresIn)
+			}
+
+			claims, err := jwtService.ValidateAccessToken(result.AccessToken)
+			if err != nil {
+				t.Fatalf("Failed to validate token: %v", err)
+			}
+			if lifetime := claims.ExpiresAt.Sub(claims.IssuedAt.Time); lifetime != tc.expected {
+				t.Errorf("Expected token lifetime %v, got %v", tc.expected, lifetime)
+			}
+		})
+	}
+
+	// A configured duration above the maximum is clamped as well
+	SetActiveImpersonationPolicy(models.ImpersonationPolicyConfig{DurationMinutes: 48 * 60})
+	if duration := ImpersonationDuration(0); duration != MaxImpersonationTokenDuration {
+		t.Errorf("Expected configured duration to be clamped to %v, got %v", MaxImpersonationTokenDuration, duration)
+	}
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrImpersonationSessionNotFound is returned when an impersonation session is not active
+var ErrImpersonationSessionNotFound = errors.New("impersonation session not found")
+
+// ImpersonationSession describes an issued impersonation token
+type ImpersonationSession struct {
+	TokenID         string    `json:"jti"`
+	AdminID         string    `json:"admin_id"`
+	AdminEmail      string    `json:"admin_email"`
+	TargetUserID    string    `json:"target_user_id"`
+	TargetUserEmail string    `json:"target_user_email"`
+	TargetUserRole  string    `json:"target_user_role"`
+	TargetTenantID  string    `json:"target_tenant_id,omitempty"`
+	Reason          string    `json:"reason"`
+	StartedAt       time.Time `json:"started_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+// ImpersonationSessionStore tracks active impersonation sessions by token ID (jti)
+type ImpersonationSessionStore interface {
+	// Track records a newly issued impersonation session
+	Track(ctx context.Context, session *ImpersonationSession) error
+
+	// List returns the sessions that have not expired or been revoked
+	List(ctx context.Context) ([]ImpersonationSession, error)
+
+	// Get returns an active session, or ErrImpersonationSessionNotFound
+	Get(ctx context.Context, tokenID string) (*ImpersonationSession, error)
+
+	// Revoke removes a session and adds its token ID to the revocation set until ttl passes
+	Revoke(ctx context.Context, tokenID string, ttl time.Duration) error
+
+	// IsRevoked checks if a token ID is in the revocation set
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// RedisImpersonationSessionStore implements ImpersonationSessionStore with a Redis hash of
+// sessions keyed by jti and one revocation key with TTL per revoked token
+type RedisImpersonationSessionStore struct {
+	client           *redis.Client
+	sessionsKey      string
+	revokedKeyPrefix string
+}
+
+// NewRedisImpersonationSessionStore creates a Redis-backed impersonation session store
+func NewRedisImpersonationSessionStore(client *redis.Client) *RedisImpersonationSessionStore {
+	return &RedisImpersonationSessionStore{
+		client:           client,
+		sessionsKey:      "impersonation_sessions",
+		revokedKeyPrefix: "revoked_impersonation",
+	}
+}
+
+// Track records a newly issued impersonation session
+func (s *RedisImpersonationSessionStore) Track(ctx context.Context, session *ImpersonationSession) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return s.client.HSet(ctx, s.sessionsKey, session.TokenID, data).Err()
+}
+
+// List returns active sessions, newest first, pruning the expired ones from the hash
+func (s *RedisImpersonationSessionStore) List(ctx context.Context) ([]ImpersonationSession, error) {
+	entries, err := s.client.HGetAll(ctx, s.sessionsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]ImpersonationSession, 0, len(entries))
+	var expired []string
+	for tokenID, data := range entries {
+		var session ImpersonationSession
+		if err := json.Unmarshal([]byte(data), &session); err != nil || !session.ExpiresAt.After(now) {
+			expired = append(expired, tokenID)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	if len(expired) > 0 {
+		s.client.HDel(ctx, s.sessionsKey, expired...)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.After(sessions[j].StartedAt)
+	})
+	return sessions, nil
+}
+
+// Get returns an active session, or ErrImpersonationSessionNotFound
+func (s *RedisImpersonationSessionStore) Get(ctx context.Context, tokenID string) (*ImpersonationSession, error) {
+	data, err := s.client.HGet(ctx, s.sessionsKey, tokenID).Result()
+	if err == redis.Nil {
+		return nil, ErrImpersonationSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session ImpersonationSession
+	if err := json.Unmarshal([]byte(data), &session); err != nil || !session.ExpiresAt.After(time.Now()) {
+		return nil, ErrImpersonationSessionNotFound
+	}
+	return &session, nil
+}
+
+// Revoke removes a session and adds its token ID to the revocation set
+func (s *RedisImpersonationSessionStore) Revoke(ctx context.Context, tokenID string, ttl time.Duration) error {
+	key := fmt.Sprintf("%s:%s", s.revokedKeyPrefix, tokenID)
+	if err := s.client.Set(ctx, key, "revoked", ttl).Err(); err != nil {
+		return err
+	}
+	return s.client.HDel(ctx, s.sessionsKey, tokenID).Err()
+}
+
+// IsRevoked checks if a token ID is in the revocation set
+func (s *RedisImpersonationSessionStore) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	key := fmt.Sprintf("%s:%s", s.revokedKeyPrefix, tokenID)
+	result, err := s.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return result > 0, nil
+}
@@ -2,10 +2,12 @@ package auth
 
 import (
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
 )
 
 const (
@@ -36,6 +38,8 @@ type TokenType string
 const (
 	AccessToken  TokenType = "access"
 	RefreshToken TokenType = "refresh"
+	// MFAToken is issued after the password step of a login and is exchanged, with a TOTP code, for the token pair
+	MFAToken TokenType = "mfa"
 )
 
 // Claims represents the JWT claims for authentication
@@ -47,6 +51,11 @@ type Claims struct {
 	TenantID     string `json:"tenant_id,omitempty"`
 	IsSuperAdmin bool   `json:"is_super_admin,omitempty"`
 	TokenType    string `json:"token_type"`
+
+	// Set only on impersonation tokens
+	OriginalAdminID string `json:"original_admin_id,omitempty"`
+	IsImpersonation bool   `json:"is_impersonation,omitempty"`
+
 	jwt.RegisteredClaims
 }
 
@@ -57,6 +66,10 @@ type JWTService struct {
 	accessDuration  time.Duration
 	refreshDuration time.Duration
 	issuer          string
+
+	// roleDurations overrides the durations by role (see SetRoleDurations)
+	roleDurations   models.TokenDurationsConfig
+	roleDurationsMu sync.RWMutex
 }
 
 // NewJWTService creates a new JWT service instance
@@ -84,6 +97,33 @@ func NewJWTService(accessSecret, refreshSecret string, accessDuration, refreshDu
 	}, nil
 }
 
+// SetRoleDurations replaces the token duration overrides by role; roles without override use the global durations
+func (s *JWTService) SetRoleDurations(config models.TokenDurationsConfig) {
+	s.roleDurationsMu.Lock()
+	defer s.roleDurationsMu.Unlock()
+	s.roleDurations = config
+}
+
+// AccessTokenDuration returns the duration of the access tokens of the role
+func (s *JWTService) AccessTokenDuration(role string) time.Duration {
+	s.roleDurationsMu.RLock()
+	defer s.roleDurationsMu.RUnlock()
+	if minutes := s.roleDurations.Roles[models.UserRole(role)].AccessMinutes; minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return s.accessDuration
+}
+
+// RefreshTokenDuration returns the duration of the refresh tokens of the role
+func (s *JWTService) RefreshTokenDuration(role string) time.Duration {
+	s.roleDurationsMu.RLock()
+	defer s.roleDurationsMu.RUnlock()
+	if hours := s.roleDurations.Roles[models.UserRole(role)].RefreshHours; hours > 0 {
+		return time.Duration(hours) * time.Hour
+	}
+	return s.refreshDuration
+}
+
 // GenerateTokenPair generates both access and refresh tokens
 // Deprecated: Use GenerateTokenPairWithTenant for multi-tenant support
 func (s *JWTService) GenerateTokenPair(userID, email, role, hospitalID string) (accessToken, refreshToken string, err error) {
@@ -92,6 +132,7 @@ func (s *JWTService) GenerateTokenPair(userID, email, role, hospitalID string) (
 }
 
 // GenerateTokenPairWithTenant generates both access and refresh tokens with tenant context
+// The durations follow the overrides of the role, if any
 func (s *JWTService) GenerateTokenPairWithTenant(userID, email, role, hospitalID, tenantID string, isSuperAdmin bool) (accessToken, refreshToken string, err error) {
 	accessToken, err = s.GenerateAccessTokenWithTenant(userID, email, role, hospitalID, tenantID, isSuperAdmin)
 	if err != nil {
@@ -112,7 +153,7 @@ func (s *JWTService) GenerateAccessToken(userID, email, role, hospitalID string)
 	return s.GenerateAccessTokenWithTenant(userID, email, role, hospitalID, "", false)
 }
 
-// GenerateAccessTokenWithTenant generates a new access token with tenant context (15 minutes expiration)
+// GenerateAccessTokenWithTenant generates a new access token with tenant context (15 minutes expiration unless the role overrides it)
 func (s *JWTService) GenerateAccessTokenWithTenant(userID, email, role, hospitalID, tenantID string, isSuperAdmin bool) (string, error) {
 	now := time.Now()
 	claims := Claims{
@@ -124,7 +165,7 @@ func (s *JWTService) GenerateAccessTokenWithTenant(userID, email, role, hospital
 		IsSuperAdmin: isSuperAdmin,
 		TokenType:    string(AccessToken),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessDuration)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.AccessTokenDuration(role))),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    s.issuer,
@@ -137,13 +178,48 @@ func (s *JWTService) GenerateAccessTokenWithTenant(userID, email, role, hospital
 	return token.SignedString(s.accessSecret)
 }
 
+// GenerateImpersonationToken generates an access token for targetUserID on behalf of originalAdminID
+// The token expires after duration instead of the regular access token duration and its ID (jti)
+// is returned so the impersonation session can be tracked and revoked.
+func (s *JWTService) GenerateImpersonationToken(userID, email, role, hospitalID, tenantID string, isSuperAdmin bool, originalAdminID string, duration time.Duration) (token, tokenID string, expiresAt time.Time, err error) {
+	now := time.Now()
+	expiresAt = now.Add(duration)
+	tokenID = uuid.New().String()
+
+	claims := Claims{
+		UserID:          userID,
+		Email:           email,
+		Role:            role,
+		HospitalID:      hospitalID,
+		TenantID:        tenantID,
+		IsSuperAdmin:    isSuperAdmin,
+		TokenType:       string(AccessToken),
+		OriginalAdminID: originalAdminID,
+		IsImpersonation: true,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    s.issuer,
+			Subject:   userID,
+			ID:        tokenID,
+		},
+	}
+
+	token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.accessSecret)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	return token, tokenID, expiresAt, nil
+}
+
 // GenerateRefreshToken generates a new refresh token (7 days expiration)
 // Deprecated: Use GenerateRefreshTokenWithTenant for multi-tenant support
 func (s *JWTService) GenerateRefreshToken(userID, email, role, hospitalID string) (string, error) {
 	return s.GenerateRefreshTokenWithTenant(userID, email, role, hospitalID, "", false)
 }
 
-// GenerateRefreshTokenWithTenant generates a new refresh token with tenant context (7 days expiration)
+// GenerateRefreshTokenWithTenant generates a new refresh token with tenant context (7 days expiration unless the role overrides it)
 func (s *JWTService) GenerateRefreshTokenWithTenant(userID, email, role, hospitalID, tenantID string, isSuperAdmin bool) (string, error) {
 	now := time.Now()
 	claims := Claims{
@@ -155,7 +231,7 @@ func (s *JWTService) GenerateRefreshTokenWithTenant(userID, email, role, hospita
 		IsSuperAdmin: isSuperAdmin,
 		TokenType:    string(RefreshToken),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(now.Add(s.refreshDuration)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.RefreshTokenDuration(role))),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    s.issuer,
@@ -168,6 +244,26 @@ func (s *JWTService) GenerateRefreshTokenWithTenant(userID, email, role, hospita
 	return token.SignedString(s.refreshSecret)
 }
 
+// GenerateMFAToken generates the token that identifies a login waiting for its TOTP code
+func (s *JWTService) GenerateMFAToken(userID string, duration time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		UserID:    userID,
+		TokenType: string(MFAToken),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    s.issuer,
+			Subject:   userID,
+			ID:        uuid.New().String(),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.accessSecret)
+}
+
 // ValidateAccessToken validates an access token and returns the claims
 func (s *JWTService) ValidateAccessToken(tokenString string) (*Claims, error) {
 	return s.validateToken(tokenString, s.accessSecret, AccessToken)
@@ -178,6 +274,11 @@ func (s *JWTService) ValidateRefreshToken(tokenString string) (*Claims, error) {
 	return s.validateToken(tokenString, s.refreshSecret, RefreshToken)
 }
 
+// ValidateMFAToken validates an MFA token and returns the claims
+func (s *JWTService) ValidateMFAToken(tokenString string) (*Claims, error) {
+	return s.validateToken(tokenString, s.accessSecret, MFAToken)
+}
+
 // validateToken validates a token with the given secret and expected type
 func (s *JWTService) validateToken(tokenString string, secret []byte, expectedType TokenType) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
@@ -213,12 +314,12 @@ func (s *JWTService) validateToken(tokenString string, secret []byte, expectedTy
 	return claims, nil
 }
 
-// GetAccessTokenDuration returns the access token duration
+// GetAccessTokenDuration returns the global access token duration
 func (s *JWTService) GetAccessTokenDuration() time.Duration {
 	return s.accessDuration
 }
 
-// GetRefreshTokenDuration returns the refresh token duration
+// GetRefreshTokenDuration returns the global refresh token duration
 func (s *JWTService) GetRefreshTokenDuration() time.Duration {
 	return s.refreshDuration
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -147,3 +148,72 @@ func TestJWTService_TokenValidation(t *testing.T) {
 		assert.Equal(t, ErrInvalidToken, err)
 	})
 }
+
+func TestJWTService_RoleDurations(t *testing.T) {
+	service, err := NewJWTService("test-access-secret-32chars!!", "test-refresh-secret-32chars!", 15*time.Minute, 7*24*time.Hour)
+	require.NoError(t, err)
+
+	service.SetRoleDurations(models.TokenDurationsConfig{
+		Roles: map[models.UserRole]models.RoleTokenDurations{
+			models.RoleAdmin: {AccessMinutes: 5, RefreshHours: 8},
+		},
+	})
+
+	generate := func(role string) (*Claims, *Claims) {
+		accessToken, refreshToken, err := service.GenerateTokenPairWithTenant(
+			uuid.New().String(), role+"@example.com", role, "", uuid.New().String(), false,
+		)
+		require.NoError(t, err)
+
+		accessClaims, err := service.ValidateAccessToken(accessToken)
+		require.NoError(t, err)
+		refreshClaims, err := service.ValidateRefreshToken(refreshToken)
+		require.NoError(t, err)
+		return accessClaims, refreshClaims
+	}
+
+	t.Run("should expire admin tokens sooner than operador tokens", func(t *testing.T) {
+		adminAccess, adminRefresh := generate("admin")
+		operadorAccess, operadorRefresh := generate("operador")
+
+		assert.True(t, adminAccess.ExpiresAt.Before(operadorAccess.ExpiresAt.Time))
+		assert.True(t, adminRefresh.ExpiresAt.Before(operadorRefresh.ExpiresAt.Time))
+
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), adminAccess.ExpiresAt.Time, 5*time.Second)
+		assert.WithinDuration(t, time.Now().Add(8*time.Hour), adminRefresh.ExpiresAt.Time, 5*time.Second)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), operadorAccess.ExpiresAt.Time, 5*time.Second)
+		assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), operadorRefresh.ExpiresAt.Time, 5*time.Second)
+	})
+
+	t.Run("should report the durations of each role", func(t *testing.T) {
+		assert.Equal(t, 5*time.Minute, service.AccessTokenDuration("admin"))
+		assert.Equal(t, 15*time.Minute, service.AccessTokenDuration("operador"))
+		assert.Equal(t, 8*time.Hour, service.RefreshTokenDuration("admin"))
+		assert.Equal(t, 7*24*time.Hour, service.RefreshTokenDuration("gestor"))
+	})
+
+	t.Run("should restore the global durations when overrides are cleared", func(t *testing.T) {
+		service.SetRoleDurations(models.TokenDurationsConfig{})
+
+		adminAccess, _ := generate("admin")
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), adminAccess.ExpiresAt.Time, 5*time.Second)
+	})
+}
+
+func TestTokenDurationsSetting(t *testing.T) {
+	setting := &models.SystemSetting{Value: []byte(`{"roles": {"admin": {"access_minutes": 5}, "operador": {"refresh_hours": 720}}}`)}
+	config, err := setting.GetTokenDurationsConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 5, config.Roles[models.RoleAdmin].AccessMinutes)
+	assert.Equal(t, 720, config.Roles[models.RoleOperador].RefreshHours)
+
+	for _, value := range []string{
+		`{"roles": {"diretor": {"access_minutes": 5}}}`,
+		`{"roles": {"admin": {"access_minutes": -1}}}`,
+		`{"roles": {"admin": {"refresh_hours": 10000}}}`,
+	} {
+		invalid := &models.SystemSetting{Value: []byte(value)}
+		_, err := invalid.GetTokenDurationsConfig()
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,371 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+const (
+	// MFAIssuer is the issuer shown by authenticator apps
+	MFAIssuer = "SIDOT"
+
+	// MFAChallengeDuration is how long the MFA token of a login stays valid
+	MFAChallengeDuration = 5 * time.Minute
+
+	// MFAMaxAttempts is how many codes a user can check in MFAAttemptWindow
+	MFAMaxAttempts = 5
+
+	// MFAAttemptWindow is the window of the MFA attempt limit, started by the first attempt
+	MFAAttemptWindow = 15 * time.Minute
+
+	// TOTP parameters (RFC 6238 defaults, supported by every authenticator app)
+	totpPeriod       = 30
+	totpDigits       = 6
+	totpModulus      = 1000000
+	totpSkewSteps    = 1
+	totpSecretLength = 20
+
+	// totpStepTTL keeps the last accepted step while a code of it can still be valid
+	totpStepTTL = (2*totpSkewSteps + 1) * totpPeriod * time.Second
+)
+
+var (
+	// ErrMFAUnavailable is returned when MFA secrets cannot be stored (no encryption key configured)
+	ErrMFAUnavailable = errors.New("mfa is not available: encryption key not configured")
+
+	// ErrMFAAlreadyEnabled is returned when enrolling a user that already has MFA enabled
+	ErrMFAAlreadyEnabled = errors.New("mfa is already enabled")
+
+	// ErrMFANotEnrolled is returned when a code is checked for a user without an MFA secret
+	ErrMFANotEnrolled = errors.New("mfa enrollment has not been started")
+
+	// ErrInvalidMFACode is returned when the TOTP code does not match
+	ErrInvalidMFACode = errors.New("invalid mfa code")
+
+	// ErrMFAChallengeUsed is returned when the MFA token of a login that was already completed is used again
+	ErrMFAChallengeUsed = errors.New("mfa token has already been used")
+
+	// ErrTooManyMFAAttempts is returned when the user checked too many codes in the attempt window
+	ErrTooManyMFAAttempts = errors.New("too many mfa attempts")
+)
+
+var (
+	// activeMFAPolicy lists the roles that must sign in with a TOTP code
+	activeMFAPolicy   models.MFAPolicyConfig
+	activeMFAPolicyMu sync.RWMutex
+)
+
+// SetActiveMFAPolicy replaces the policy applied to new logins
+func SetActiveMFAPolicy(policy models.MFAPolicyConfig) {
+	activeMFAPolicyMu.Lock()
+	defer activeMFAPolicyMu.Unlock()
+	activeMFAPolicy = policy
+}
+
+// ActiveMFAPolicy returns the policy currently applied to new logins
+func ActiveMFAPolicy() models.MFAPolicyConfig {
+	activeMFAPolicyMu.RLock()
+	defer activeMFAPolicyMu.RUnlock()
+	return activeMFAPolicy
+}
+
+// MFAState is the TOTP secret of a user and whether its enrollment was confirmed
+type MFAState struct {
+	Secret  string
+	Enabled bool
+}
+
+// MFAStore stores the TOTP secrets of the users (implemented by repository.UserMFARepository)
+type MFAStore interface {
+	// GetMFA returns the MFA state of the user, or nil when the user never enrolled
+	GetMFA(ctx context.Context, userID uuid.UUID) (*MFAState, error)
+
+	// SaveMFASecret stores a new secret for the user, pending confirmation
+	SaveMFASecret(ctx context.Context, userID uuid.UUID, secret string) error
+
+	// EnableMFA confirms the enrollment of the stored secret
+	EnableMFA(ctx context.Context, userID uuid.UUID) error
+}
+
+// MFAEnrollment is the secret of a new enrollment, to be added to an authenticator app
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// GenerateTOTPSecret generates a random base32 TOTP secret
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret), nil
+}
+
+// TOTPCode returns the TOTP code of the secret at time t
+func TOTPCode(secret string, t time.Time) (string, error) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("invalid totp secret: %w", err)
+	}
+	return totpCodeAt(key, t.Unix()/totpPeriod), nil
+}
+
+// ValidateTOTPCode reports whether the code matches the secret at time t, one period apart at most
+func ValidateTOTPCode(secret, code string, t time.Time) bool {
+	_, ok := matchTOTPStep(secret, code, t)
+	return ok
+}
+
+// matchTOTPStep returns the time step whose code matches, one period apart from t at most
+func matchTOTPStep(secret, code string, t time.Time) (int64, bool) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	step := t.Unix() / totpPeriod
+	for skew := int64(-totpSkewSteps); skew <= totpSkewSteps; skew++ {
+		if subtle.ConstantTimeCompare([]byte(totpCodeAt(key, step+skew)), []byte(code)) == 1 {
+			return step + skew, true
+		}
+	}
+	return 0, false
+}
+
+// TOTPProvisioningURI returns the otpauth:// URI of the secret, shown as a QR code to authenticator apps
+func TOTPProvisioningURI(account, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", MFAIssuer)
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(totpPeriod))
+	label := url.PathEscape(MFAIssuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// totpCodeAt computes the HOTP code of the key for the time step (RFC 4226 dynamic truncation)
+func totpCodeAt(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%totpModulus)
+}
+
+// SetMFAStore sets the store of the TOTP secrets; MFA is unavailable without it
+func (s *AuthService) SetMFAStore(store MFAStore) {
+	s.mfaStore = store
+}
+
+// SetMFAChallengeStore sets the store that makes MFA tokens and codes single-use and limits the attempts
+// Without it the MFA step relies on the token expiration only
+func (s *AuthService) SetMFAChallengeStore(store MFAChallengeStore) {
+	s.mfaChallenges = store
+}
+
+// MFAAvailable reports whether MFA secrets can be stored
+func (s *AuthService) MFAAvailable() bool {
+	return s.mfaStore != nil
+}
+
+// EnrollMFA starts the MFA enrollment of the user, replacing a pending one
+// The enrollment is enabled once ConfirmMFAEnrollment receives a valid code
+func (s *AuthService) EnrollMFA(ctx context.Context, userID uuid.UUID) (*MFAEnrollment, error) {
+	if s.mfaStore == nil {
+		return nil, ErrMFAUnavailable
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.mfaStore.GetMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil && state.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	return s.startMFAEnrollment(ctx, user)
+}
+
+// ConfirmMFAEnrollment enables MFA for the user when the code matches the pending secret
+func (s *AuthService) ConfirmMFAEnrollment(ctx context.Context, userID uuid.UUID, code string) error {
+	if s.mfaStore == nil {
+		return ErrMFAUnavailable
+	}
+
+	state, err := s.checkMFACode(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+	if state.Enabled {
+		return ErrMFAAlreadyEnabled
+	}
+
+	return s.mfaStore.EnableMFA(ctx, userID)
+}
+
+// VerifyMFA completes a login waiting for its TOTP code and issues the token pair
+// A pending enrollment started by the login is enabled by the first valid code, and the MFA token
+// cannot complete another login
+func (s *AuthService) VerifyMFA(ctx context.Context, mfaToken, code string, source LoginSource) (*LoginResult, error) {
+	if s.mfaStore == nil {
+		return nil, ErrMFAUnavailable
+	}
+
+	claims, err := s.jwtService.ValidateMFAToken(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, ErrInvalidClaims
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Ativo {
+		return nil, ErrUserInactive
+	}
+
+	state, err := s.checkMFACode(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+	if s.mfaChallenges != nil {
+		consumed, err := s.mfaChallenges.ConsumeChallenge(ctx, claims.ID, MFAChallengeDuration)
+		if err != nil {
+			return nil, err
+		}
+		if !consumed {
+			return nil, ErrMFAChallengeUsed
+		}
+	}
+	if !state.Enabled {
+		if err := s.mfaStore.EnableMFA(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.completeLogin(ctx, user, source)
+}
+
+// mfaChallenge returns the MFA step of the login of the user, or nil when the user signs in with the password only
+// Users whose role requires MFA and that never enrolled get a new enrollment with the challenge
+func (s *AuthService) mfaChallenge(ctx context.Context, user *User) (*LoginResult, error) {
+	if s.mfaStore == nil {
+		return nil, nil
+	}
+
+	state, err := s.mfaStore.GetMFA(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := state != nil && state.Enabled
+	if !enabled && !ActiveMFAPolicy().RequiresRole(user.Role) {
+		return nil, nil
+	}
+
+	token, err := s.jwtService.GenerateMFAToken(user.ID.String(), MFAChallengeDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LoginResult{
+		MFARequired: true,
+		MFAToken:    token,
+		ExpiresIn:   int64(MFAChallengeDuration.Seconds()),
+	}
+	if !enabled {
+		result.MFAEnrollment, err = s.startMFAEnrollment(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// startMFAEnrollment stores a new pending secret for the user
+func (s *AuthService) startMFAEnrollment(ctx context.Context, user *User) (*MFAEnrollment, error) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaStore.SaveMFASecret(ctx, user.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: TOTPProvisioningURI(user.Email, secret),
+	}, nil
+}
+
+// checkMFACode returns the MFA state of the user when the code matches its secret
+// With a challenge store, the attempts are limited and a code is rejected when its time step
+// is not after the last accepted one, so a code cannot be replayed
+func (s *AuthService) checkMFACode(ctx context.Context, userID uuid.UUID, code string) (*MFAState, error) {
+	state, err := s.mfaStore.GetMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, ErrMFANotEnrolled
+	}
+
+	if s.mfaChallenges != nil {
+		attempts, err := s.mfaChallenges.RecordAttempt(ctx, userID.String(), MFAAttemptWindow)
+		if err != nil {
+			return nil, err
+		}
+		if attempts > MFAMaxAttempts {
+			return nil, ErrTooManyMFAAttempts
+		}
+	}
+
+	step, ok := matchTOTPStep(state.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	if s.mfaChallenges != nil {
+		accepted, err := s.mfaChallenges.AcceptTimeStep(ctx, userID.String(), step, totpStepTTL)
+		if err != nil {
+			return nil, err
+		}
+		if !accepted {
+			return nil, ErrInvalidMFACode
+		}
+		if err := s.mfaChallenges.ResetAttempts(ctx, userID.String()); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MFAChallengeStore guards the MFA step against replays and guessing
+type MFAChallengeStore interface {
+	// ConsumeChallenge marks the MFA token (jti) as used, returning false if it was already used
+	ConsumeChallenge(ctx context.Context, tokenID string, ttl time.Duration) (bool, error)
+
+	// AcceptTimeStep records the TOTP time step of an accepted code, returning false when the
+	// step is not after the last step accepted for the user
+	AcceptTimeStep(ctx context.Context, userID string, step int64, ttl time.Duration) (bool, error)
+
+	// RecordAttempt counts a code checked for the user and returns the attempts in the window
+	RecordAttempt(ctx context.Context, userID string, window time.Duration) (int64, error)
+
+	// ResetAttempts clears the attempts of the user after a valid code
+	ResetAttempts(ctx context.Context, userID string) error
+}
+
+// acceptTimeStepScript stores the step only when it is after the stored one, so two
+// requests with the same code cannot both be accepted
+var acceptTimeStepScript = redis.NewScript(`
+local last = redis.call('GET', KEYS[1])
+if last and tonumber(last) >= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
+// RedisMFAChallengeStore implements MFAChallengeStore with expiring Redis keys
+type RedisMFAChallengeStore struct {
+	client           *redis.Client
+	usedKeyPrefix    string
+	stepKeyPrefix    string
+	attemptKeyPrefix string
+}
+
+// NewRedisMFAChallengeStore creates a Redis-backed MFA challenge store
+func NewRedisMFAChallengeStore(client *redis.Client) *RedisMFAChallengeStore {
+	return &RedisMFAChallengeStore{
+		client:           client,
+		usedKeyPrefix:    "mfa_challenge_used",
+		stepKeyPrefix:    "mfa_totp_step",
+		attemptKeyPrefix: "mfa_attempts",
+	}
+}
+
+// ConsumeChallenge sets the key of the token unless it is already set (SETNX)
+func (s *RedisMFAChallengeStore) ConsumeChallenge(ctx context.Context, tokenID string, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s:%s", s.usedKeyPrefix, tokenID)
+	return s.client.SetNX(ctx, key, time.Now().Unix(), ttl).Result()
+}
+
+// AcceptTimeStep stores the step of the user when it is after the last accepted one
+func (s *RedisMFAChallengeStore) AcceptTimeStep(ctx context.Context, userID string, step int64, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s:%s", s.stepKeyPrefix, userID)
+	accepted, err := acceptTimeStepScript.Run(ctx, s.client, []string{key}, step, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return accepted == 1, nil
+}
+
+// RecordAttempt increments the attempt counter of the user, starting its window on the first attempt
+func (s *RedisMFAChallengeStore) RecordAttempt(ctx context.Context, userID string, window time.Duration) (int64, error) {
+	key := fmt.Sprintf("%s:%s", s.attemptKeyPrefix, userID)
+	attempts, err := s.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if attempts == 1 {
+		if err := s.client.Expire(ctx, key, window).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return attempts, nil
+}
+
+// ResetAttempts deletes the attempt counter of the user
+func (s *RedisMFAChallengeStore) ResetAttempts(ctx context.Context, userID string) error {
+	return s.client.Del(ctx, fmt.Sprintf("%s:%s", s.attemptKeyPrefix, userID)).Err()
+}
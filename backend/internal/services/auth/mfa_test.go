@@ -0,0 +1,342 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// memoryMFAStore keeps the MFA state of the users in memory
+type memoryMFAStore struct {
+	states map[uuid.UUID]*MFAState
+}
+
+func newMemoryMFAStore() *memoryMFAStore {
+	return &memoryMFAStore{states: make(map[uuid.UUID]*MFAState)}
+}
+
+func (s *memoryMFAStore) GetMFA(ctx context.Context, userID uuid.UUID) (*MFAState, error) {
+	state, ok := s.states[userID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *state
+	return &copied, nil
+}
+
+func (s *memoryMFAStore) SaveMFASecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	s.states[userID] = &MFAState{Secret: secret}
+	return nil
+}
+
+func (s *memoryMFAStore) EnableMFA(ctx context.Context, userID uuid.UUID) error {
+	state, ok := s.states[userID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	state.Enabled = true
+	return nil
+}
+
+// memoryMFAChallengeStore keeps the used MFA tokens, accepted steps and attempts in memory
+type memoryMFAChallengeStore struct {
+	used     map[string]bool
+	steps    map[string]int64
+	attempts map[string]int64
+}
+
+func newMemoryMFAChallengeStore() *memoryMFAChallengeStore {
+	return &memoryMFAChallengeStore{
+		used:     make(map[string]bool),
+		steps:    make(map[string]int64),
+		attempts: make(map[string]int64),
+	}
+}
+
+func (s *memoryMFAChallengeStore) ConsumeChallenge(ctx context.Context, tokenID string, ttl time.Duration) (bool, error) {
+	if s.used[tokenID] {
+		return false, nil
+	}
+	s.used[tokenID] = true
+	return true, nil
+}
+
+func (s *memoryMFAChallengeStore) AcceptTimeStep(ctx context.Context, userID string, step int64, ttl time.Duration) (bool, error) {
+	if last, ok := s.steps[userID]; ok && last >= step {
+		return false, nil
+	}
+	s.steps[userID] = step
+	return true, nil
+}
+
+func (s *memoryMFAChallengeStore) RecordAttempt(ctx context.Context, userID string, window time.Duration) (int64, error) {
+	s.attempts[userID]++
+	return s.attempts[userID], nil
+}
+
+func (s *memoryMFAChallengeStore) ResetAttempts(ctx context.Context, userID string) error {
+	delete(s.attempts, userID)
+	return nil
+}
+
+// newMFATestService creates an auth service with an in-memory MFA store and a gestor that signs in with password
+func newMFATestService(t *testing.T) (*AuthService, *JWTService, *memoryMFAStore, *User) {
+	t.Helper()
+
+	service, jwtService, user := newRotationTestService(t)
+	passwordHash, err := HashPassword("SenhaSegura123!")
+	if err != nil {
+		t.Fatalf("HashPassword failed: %v", err)
+	}
+	user.PasswordHash = passwordHash
+
+	store := newMemoryMFAStore()
+	service.SetMFAStore(store)
+	return service, jwtService, store, user
+}
+
+// currentCode returns the TOTP code of the secret now
+func currentCode(t *testing.T, secret string) string {
+	t.Helper()
+
+	code, err := TOTPCode(secret, time.Now())
+	if err != nil {
+		t.Fatalf("TOTPCode failed: %v", err)
+	}
+	return code
+}
+
+// wrongCode returns a well-formed code different from the given one
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+// Testar os codigos TOTP com os vetores SHA1 da RFC 6238 (6 digitos)
+func TestTOTPCodeRFC6238Vectors(t *testing.T) {
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" // base32 of "12345678901234567890"
+
+	for unix, expected := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		code, err := TOTPCode(secret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatalf("TOTPCode failed: %v", err)
+		}
+		if code != expected {
+			t.Errorf("At %d: expected %s, got %s", unix, expected, code)
+		}
+	}
+
+	// One period of clock drift is accepted, two are not
+	at := time.Unix(1111111109, 0)
+	if !ValidateTOTPCode(secret, "081804", at.Add(30*time.Second)) {
+		t.Error("Expected the code of the previous period to be accepted")
+	}
+	if ValidateTOTPCode(secret, "081804", at.Add(90*time.Second)) {
+		t.Error("Expected the code of two periods ago to be rejected")
+	}
+	if ValidateTOTPCode(secret, "81804", at) {
+		t.Error("Expected a code with missing digits to be rejected")
+	}
+}
+
+// Testar que o cadastro de MFA so e ativado com um codigo valido do segredo gerado
+func TestMFAEnrollment(t *testing.T) {
+	service, _, store, user := newMFATestService(t)
+	ctx := context.Background()
+
+	if err := service.ConfirmMFAEnrollment(ctx, user.ID, "123456"); err != ErrMFANotEnrolled {
+		t.Errorf("Expected ErrMFANotEnrolled before enrolling, got %v", err)
+	}
+
+	enrollment, err := service.EnrollMFA(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("EnrollMFA failed: %v", err)
+	}
+	if enrollment.Secret == "" || !strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/SIDOT:") ||
+		!strings.Contains(enrollment.ProvisioningURI, "secret="+enrollment.Secret) {
+		t.Fatalf("Unexpected enrollment %+v", enrollment)
+	}
+
+	code := currentCode(t, enrollment.Secret)
+	if err := service.ConfirmMFAEnrollment(ctx, user.ID, wrongCode(code)); err != ErrInvalidMFACode {
+		t.Errorf("Expected ErrInvalidMFACode, got %v", err)
+	}
+	if store.states[user.ID].Enabled {
+		t.Fatal("Expected MFA to stay disabled after an invalid code")
+	}
+
+	if err := service.ConfirmMFAEnrollment(ctx, user.ID, code); err != nil {
+		t.Fatalf("ConfirmMFAEnrollment failed: %v", err)
+	}
+	if !store.states[user.ID].Enabled {
+		t.Error("Expected MFA to be enabled after a valid code")
+	}
+
+	if _, err := service.EnrollMFA(ctx, user.ID); err != ErrMFAAlreadyEnabled {
+		t.Errorf("Expected ErrMFAAlreadyEnabled when enrolling again, got %v", err)
+	}
+}
+
+// Testar o login em duas etapas: a senha gera apenas o token de MFA e o codigo gera o par de tokens
+func TestLoginWithMFARequiresCode(t *testing.T) {
+	service, jwtService, store, user := newMFATestService(t)
+	recorder := &recordingLoginRecorder{}
+	service.SetLoginRecorder(recorder)
+	ctx := context.Background()
+
+	secret, _ := GenerateTOTPSecret()
+	store.states[user.ID] = &MFAState{Secret: secret, Enabled: true}
+
+	challenge, err := service.Login(ctx, user.Email, "SenhaSegura123!")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if !challenge.MFARequired || challenge.MFAToken == "" {
+		t.Fatalf("Expected an MFA challenge, got %+v", challenge)
+	}
+	if challenge.AccessToken != "" || challenge.RefreshToken != "" || challenge.User != nil || challenge.MFAEnrollment != nil {
+		t.Errorf("Expected only the MFA token before the code, got %+v", challenge)
+	}
+	if len(recorder.userIDs) != 0 {
+		t.Error("Expected the login not to be recorded before the code")
+	}
+	if _, err := jwtService.ValidateAccessToken(challenge.MFAToken); err != ErrInvalidToken {
+		t.Errorf("Expected the MFA token to be rejected as access token, got %v", err)
+	}
+
+	code := currentCode(t, secret)
+	source := LoginSource{IPAddress: "10.0.0.7"}
+	if _, err := service.VerifyMFA(ctx, challenge.MFAToken, wrongCode(code), source); err != ErrInvalidMFACode {
+		t.Errorf("Expected ErrInvalidMFACode, got %v", err)
+	}
+
+	result, err := service.VerifyMFA(ctx, challenge.MFAToken, code, source)
+	if err != nil {
+		t.Fatalf("VerifyMFA failed: %v", err)
+	}
+	if result.MFARequired || result.AccessToken == "" || result.RefreshToken == "" || result.User.ID != user.ID {
+		t.Errorf("Expected the token pair of the user, got %+v", result)
+	}
+	if len(recorder.userIDs) != 1 || recorder.sources[0] != source {
+		t.Errorf("Expected the verified login to be recorded, got %v", recorder.sources)
+	}
+
+	// The refresh token of the pair cannot complete the MFA step
+	if _, err := service.VerifyMFA(ctx, result.RefreshToken, code, source); err != ErrInvalidToken {
+		t.Errorf("Expected a refresh token to be rejected as MFA token, got %v", err)
+	}
+}
+
+// Testar que o token de MFA e o codigo so completam um login e que as tentativas sao limitadas
+func TestVerifyMFARejectsReplays(t *testing.T) {
+	service, _, store, user := newMFATestService(t)
+	challenges := newMemoryMFAChallengeStore()
+	service.SetMFAChallengeStore(challenges)
+	ctx := context.Background()
+
+	secret, _ := GenerateTOTPSecret()
+	store.states[user.ID] = &MFAState{Secret: secret, Enabled: true}
+
+	first, err := service.Login(ctx, user.Email, "SenhaSegura123!")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	code := currentCode(t, secret)
+	if _, err := service.VerifyMFA(ctx, first.MFAToken, code, LoginSource{}); err != nil {
+		t.Fatalf("VerifyMFA failed: %v", err)
+	}
+
+	// The accepted code cannot complete another login
+	second, err := service.Login(ctx, user.Email, "SenhaSegura123!")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if _, err := service.VerifyMFA(ctx, second.MFAToken, code, LoginSource{}); err != ErrInvalidMFACode {
+		t.Errorf("Expected a replayed code to be rejected, got %v", err)
+	}
+
+	// The MFA token cannot be used again, even with the code of a later step
+	delete(challenges.steps, user.ID.String())
+	if _, err := service.VerifyMFA(ctx, first.MFAToken, code, LoginSource{}); err != ErrMFAChallengeUsed {
+		t.Errorf("Expected ErrMFAChallengeUsed for a used MFA token, got %v", err)
+	}
+
+	// The codes checked are limited per user, valid or not
+	delete(challenges.steps, user.ID.String())
+	delete(challenges.attempts, user.ID.String())
+	for i := 0; i < MFAMaxAttempts; i++ {
+		if _, err := service.VerifyMFA(ctx, second.MFAToken, wrongCode(code), LoginSource{}); err != ErrInvalidMFACode {
+			t.Fatalf("Expected ErrInvalidMFACode on attempt %d, got %v", i+1, err)
+		}
+	}
+	if _, err := service.VerifyMFA(ctx, second.MFAToken, code, LoginSource{}); err != ErrTooManyMFAAttempts {
+		t.Errorf("Expected ErrTooManyMFAAttempts after %d attempts, got %v", MFAMaxAttempts, err)
+	}
+}
+
+// Testar que a politica exige o cadastro de MFA no login das funcoes configuradas
+func TestMFAPolicyRequiresEnrollmentOnLogin(t *testing.T) {
+	service, _, store, user := newMFATestService(t)
+	ctx := context.Background()
+
+	SetActiveMFAPolicy(models.MFAPolicyConfig{RequiredRoles: []models.UserRole{models.RoleAdmin, models.RoleGestor}})
+	t.Cleanup(func() { SetActiveMFAPolicy(models.MFAPolicyConfig{}) })
+
+	challenge, err := service.Login(ctx, user.Email, "SenhaSegura123!")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if !challenge.MFARequired || challenge.MFAEnrollment == nil || challenge.AccessToken != "" {
+		t.Fatalf("Expected an MFA challenge with a new enrollment, got %+v", challenge)
+	}
+
+	result, err := service.VerifyMFA(ctx, challenge.MFAToken, currentCode(t, challenge.MFAEnrollment.Secret), LoginSource{})
+	if err != nil {
+		t.Fatalf("VerifyMFA failed: %v", err)
+	}
+	if result.AccessToken == "" {
+		t.Error("Expected the token pair after the first code")
+	}
+	if !store.states[user.ID].Enabled {
+		t.Error("Expected the first valid code to enable MFA")
+	}
+
+	// Roles out of the policy keep signing in with the password only
+	SetActiveMFAPolicy(models.MFAPolicyConfig{RequiredRoles: []models.UserRole{models.RoleAdmin}})
+	delete(store.states, user.ID)
+	result, err = service.Login(ctx, user.Email, "SenhaSegura123!")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if result.MFARequired || result.AccessToken == "" {
+		t.Errorf("Expected a gestor out of the policy to sign in with the password, got %+v", result)
+	}
+}
+
+// Testar leitura da politica de MFA a partir do system setting
+func TestMFAPolicySetting(t *testing.T) {
+	setting := &models.SystemSetting{Value: []byte(`{"required_roles": ["admin", "gestor"]}`)}
+	policy, err := setting.GetMFAPolicyConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !policy.RequiresRole("admin") || !policy.RequiresRole("gestor") || policy.RequiresRole("operador") {
+		t.Errorf("Expected MFA required for admin and gestor only, got %+v", policy)
+	}
+
+	invalid := &models.SystemSetting{Value: []byte(`{"required_roles": ["diretor"]}`)}
+	if _, err := invalid.GetMFAPolicyConfig(); err == nil {
+		t.Error("Expected error for an unknown role")
+	}
+}
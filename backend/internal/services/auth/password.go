@@ -1,8 +1,14 @@
 package auth
 
 import (
+	"crypto/rand"
 	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"unicode"
 
+	"github.com/sidot/backend/internal/models"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -26,8 +32,37 @@ var (
 
 	// ErrInvalidPassword is returned when password verification fails
 	ErrInvalidPassword = errors.New("invalid password")
+
+	// ErrPasswordMissingUpper is returned when the policy requires an uppercase letter
+	ErrPasswordMissingUpper = errors.New("password must contain at least one uppercase letter")
+
+	// ErrPasswordMissingDigit is returned when the policy requires a digit
+	ErrPasswordMissingDigit = errors.New("password must contain at least one digit")
+
+	// ErrPasswordMissingSymbol is returned when the policy requires a symbol
+	ErrPasswordMissingSymbol = errors.New("password must contain at least one symbol")
+)
+
+var (
+	// activePasswordPolicy is the policy applied by ValidatePasswordStrength
+	activePasswordPolicy   = models.DefaultPasswordPolicyConfig()
+	activePasswordPolicyMu sync.RWMutex
 )
 
+// SetActivePasswordPolicy replaces the policy applied by ValidatePasswordStrength
+func SetActivePasswordPolicy(policy models.PasswordPolicyConfig) {
+	activePasswordPolicyMu.Lock()
+	defer activePasswordPolicyMu.Unlock()
+	activePasswordPolicy = policy
+}
+
+// ActivePasswordPolicy returns the policy currently applied by ValidatePasswordStrength
+func ActivePasswordPolicy() models.PasswordPolicyConfig {
+	activePasswordPolicyMu.RLock()
+	defer activePasswordPolicyMu.RUnlock()
+	return activePasswordPolicy
+}
+
 // HashPassword generates a bcrypt hash of the password with cost factor 12
 func HashPassword(password string) (string, error) {
 	if len(password) < MinPasswordLength {
@@ -57,13 +92,112 @@ func CheckPasswordHash(password, hash string) error {
 	return nil
 }
 
-// ValidatePasswordStrength validates password meets minimum requirements
+// ValidatePasswordStrength validates password meets the active password policy
 func ValidatePasswordStrength(password string) error {
-	if len(password) < MinPasswordLength {
+	return ValidatePasswordWithPolicy(password, ActivePasswordPolicy())
+}
+
+// ValidatePasswordWithPolicy validates password against the given policy
+func ValidatePasswordWithPolicy(password string, policy models.PasswordPolicyConfig) error {
+	if len(password) < policy.MinLength || len(password) < MinPasswordLength {
+		if policy.MinLength > MinPasswordLength {
+			return &passwordLengthError{minLength: policy.MinLength}
+		}
 		return ErrPasswordTooShort
 	}
 	if len(password) > MaxPasswordLength {
 		return ErrPasswordTooLong
 	}
+
+	var hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if policy.RequireUpper && !hasUpper {
+		return ErrPasswordMissingUpper
+	}
+	if policy.RequireDigit && !hasDigit {
+		return ErrPasswordMissingDigit
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		return ErrPasswordMissingSymbol
+	}
+
 	return nil
 }
+
+// Character classes of the temporary passwords (ambiguous characters such as 0/O and 1/l are left out)
+const (
+	temporaryPasswordUpper  = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	temporaryPasswordLower  = "abcdefghijkmnpqrstuvwxyz"
+	temporaryPasswordDigits = "23456789"
+	temporaryPasswordSymbol = "!@#$%&*?"
+
+	// temporaryPasswordLength is the length of temporary passwords unless the policy requires more
+	temporaryPasswordLength = 16
+)
+
+// GenerateTemporaryPassword generates a random password that meets the active password policy
+func GenerateTemporaryPassword() (string, error) {
+	length := temporaryPasswordLength
+	if policy := ActivePasswordPolicy(); policy.MinLength > length {
+		length = policy.MinLength
+	}
+
+	// One character of each class, so any policy is met, then any class
+	classes := []string{temporaryPasswordUpper, temporaryPasswordLower, temporaryPasswordDigits, temporaryPasswordSymbol}
+	all := temporaryPasswordUpper + temporaryPasswordLower + temporaryPasswordDigits + temporaryPasswordSymbol
+	password := make([]byte, 0, length)
+	for i := 0; i < length; i++ {
+		alphabet := all
+		if i < len(classes) {
+			alphabet = classes[i]
+		}
+		c, err := randomChar(alphabet)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// Shuffle so the classes are not always in the same positions
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+// randomChar returns a random character of the alphabet
+func randomChar(alphabet string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+	if err != nil {
+		return 0, err
+	}
+	return alphabet[n.Int64()], nil
+}
+
+// passwordLengthError reports a minimum length above the default; it matches ErrPasswordTooShort
+type passwordLengthError struct {
+	minLength int
+}
+
+func (e *passwordLengthError) Error() string {
+	return fmt.Sprintf("password must be at least %d characters", e.minLength)
+}
+
+func (e *passwordLengthError) Unwrap() error {
+	return ErrPasswordTooShort
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RefreshTokenStore tracks issued refresh token IDs (jti) for rotation and revocation
+type RefreshTokenStore interface {
+	// Register marks a newly issued refresh token as active
+	Register(ctx context.Context, tokenID string, ttl time.Duration) error
+
+	// IsActive reports whether the refresh token is still the current one in its chain
+	IsActive(ctx context.Context, tokenID string) (bool, error)
+
+	// Consume removes an active refresh token, returning false if it was not active
+	// Only one caller can consume a given token, which guards concurrent refreshes
+	Consume(ctx context.Context, tokenID string) (bool, error)
+
+	// Revoke adds a token ID to the revocation set
+	Revoke(ctx context.Context, tokenID string, ttl time.Duration) error
+
+	// IsRevoked checks if a token ID is in the revocation set
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// RedisRefreshTokenStore implements RefreshTokenStore using Redis keys with TTL
+type RedisRefreshTokenStore struct {
+	client           *redis.Client
+	activeKeyPrefix  string
+	revokedKeyPrefix string
+}
+
+// NewRedisRefreshTokenStore creates a Redis-backed refresh token store
+func NewRedisRefreshTokenStore(client *redis.Client) *RedisRefreshTokenStore {
+	return &RedisRefreshTokenStore{
+		client:           client,
+		activeKeyPrefix:  "refresh_token",
+		revokedKeyPrefix: "revoked_token",
+	}
+}
+
+// Register marks a newly issued refresh token as active
+func (s *RedisRefreshTokenStore) Register(ctx context.Context, tokenID string, ttl time.Duration) error {
+	key := fmt.Sprintf("%s:%s", s.activeKeyPrefix, tokenID)
+	return s.client.Set(ctx, key, "active", ttl).Err()
+}
+
+// IsActive reports whether the refresh token is still active
+func (s *RedisRefreshTokenStore) IsActive(ctx context.Context, tokenID string) (bool, error) {
+	key := fmt.Sprintf("%s:%s", s.activeKeyPrefix, tokenID)
+	result, err := s.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return result > 0, nil
+}
+
+// Consume removes an active refresh token atomically (DEL returns the number of keys removed)
+func (s *RedisRefreshTokenStore) Consume(ctx context.Context, tokenID string) (bool, error) {
+	key := fmt.Sprintf("%s:%s", s.activeKeyPrefix, tokenID)
+	removed, err := s.client.Del(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return removed > 0, nil
+}
+
+// Revoke adds a token ID to the revocation set
+func (s *RedisRefreshTokenStore) Revoke(ctx context.Context, tokenID string, ttl time.Duration) error {
+	key := fmt.Sprintf("%s:%s", s.revokedKeyPrefix, tokenID)
+	return s.client.Set(ctx, key, "revoked", ttl).Err()
+}
+
+// IsRevoked checks if a token ID is in the revocation set
+func (s *RedisRefreshTokenStore) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	key := fmt.Sprintf("%s:%s", s.revokedKeyPrefix, tokenID)
+	result, err := s.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return result > 0, nil
+}
@@ -3,11 +3,12 @@ package auth
 import (
 	"context"
 	"errors"
-	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"github.com/sidot/backend/internal/models"
 )
 
 var (
@@ -20,8 +21,14 @@ var (
 	// ErrInvalidCredentials is returned for invalid email/password
 	ErrInvalidCredentials = errors.New("invalid email or password")
 
+	// ErrTenantRequired is returned when an email is registered in several tenants and no tenant was given
+	ErrTenantRequired = errors.New("email is registered in more than one tenant")
+
 	// ErrTokenRevoked is returned when token has been revoked
 	ErrTokenRevoked = errors.New("token has been revoked")
+
+	// ErrSessionsUnavailable is returned when sessions are not tracked (no Redis configured)
+	ErrSessionsUnavailable = errors.New("sessions are not available")
 )
 
 // User represents the user data needed for authentication
@@ -35,39 +42,107 @@ type User struct {
 	TenantID     *uuid.UUID
 	IsSuperAdmin bool
 	Ativo        bool
+
+	// EmailVerified is false until a user created with email verification confirms its email
+	EmailVerified bool
+	CreatedAt     time.Time
 }
 
 // UserRepository defines the interface for user data access
 type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
+	// ListPasswordHashesByEmail returns the password hashes of every account with the email, for logins
+	// of an email registered in several tenants
+	ListPasswordHashesByEmail(ctx context.Context, email string) ([]string, error)
+}
+
+// LoginSource identifies where a login comes from
+type LoginSource struct {
+	IPAddress string
+	UserAgent string
+}
+
+// LoginRecorder records the successful logins of the users (implemented by repository.LoginHistoryRepository)
+type LoginRecorder interface {
+	RecordLogin(ctx context.Context, userID uuid.UUID, source LoginSource) error
 }
 
 // AuthService handles authentication operations
 type AuthService struct {
-	jwtService       *JWTService
-	userRepo         UserRepository
-	redisClient      *redis.Client
-	revokedKeyPrefix string
+	jwtService    *JWTService
+	userRepo      UserRepository
+	tokenStore    RefreshTokenStore
+	sessionStore  SessionStore
+	loginRecorder LoginRecorder
+	mfaStore      MFAStore
+	mfaChallenges MFAChallengeStore
+
+	verificationTokens VerificationTokenStore
+	emailVerifier      EmailVerifier
+	verificationMailer VerificationMailer
+	verifyURL          string
+
+	passwordUpdater PasswordUpdater
+	welcomeMailer   WelcomeMailer
+	resendThrottle  ResendThrottle
+	loginURL        string
 }
 
 // NewAuthService creates a new authentication service
+// Refresh token rotation and revocation are disabled when redisClient is nil
 func NewAuthService(jwtService *JWTService, userRepo UserRepository, redisClient *redis.Client) *AuthService {
-	return &AuthService{
-		jwtService:       jwtService,
-		userRepo:         userRepo,
-		redisClient:      redisClient,
-		revokedKeyPrefix: "revoked_token",
+	service := &AuthService{
+		jwtService: jwtService,
+		userRepo:   userRepo,
+	}
+	if redisClient != nil {
+		service.tokenStore = NewRedisRefreshTokenStore(redisClient)
+		service.sessionStore = NewRedisSessionStore(redisClient)
+		service.verificationTokens = NewRedisVerificationTokenStore(redisClient)
+		service.resendThrottle = NewRedisResendThrottle(redisClient)
+		service.mfaChallenges = NewRedisMFAChallengeStore(redisClient)
 	}
+	return service
+}
+
+// SetRefreshTokenStore replaces the store used for refresh token rotation and revocation
+func (s *AuthService) SetRefreshTokenStore(store RefreshTokenStore) {
+	s.tokenStore = store
+}
+
+// SetSessionStore replaces the store used to list and revoke the sessions of the users
+func (s *AuthService) SetSessionStore(store SessionStore) {
+	s.sessionStore = store
+}
+
+// SetLoginRecorder sets the recorder of the last login and login history of the users
+func (s *AuthService) SetLoginRecorder(recorder LoginRecorder) {
+	s.loginRecorder = recorder
+}
+
+// SetPasswordPolicy sets the password policy enforced for this deployment
+func (s *AuthService) SetPasswordPolicy(policy models.PasswordPolicyConfig) {
+	SetActivePasswordPolicy(policy)
+}
+
+// SetTokenDurations sets the access and refresh token durations by role, falling back to the global durations
+func (s *AuthService) SetTokenDurations(config models.TokenDurationsConfig) {
+	s.jwtService.SetRoleDurations(config)
 }
 
 // LoginResult contains the result of a successful login
+// When MFARequired is set the login waits for its TOTP code: only MFAToken is issued,
+// valid for ExpiresIn seconds, and MFAEnrollment holds the secret of a user still to enroll
 type LoginResult struct {
-	AccessToken  string    `json:"access_token"`
-	RefreshToken string    `json:"refresh_token"`
-	TokenType    string    `json:"token_type"`
-	ExpiresIn    int64     `json:"expires_in"`
-	User         *UserInfo `json:"user"`
+	AccessToken   string         `json:"access_token,omitempty"`
+	RefreshToken  string         `json:"refresh_token,omitempty"`
+	TokenType     string         `json:"token_type,omitempty"`
+	ExpiresIn     int64          `json:"expires_in"`
+	User          *UserInfo      `json:"user,omitempty"`
+	MFARequired   bool           `json:"mfa_required,omitempty"`
+	MFAToken      string         `json:"mfa_token,omitempty"`
+	MFAEnrollment *MFAEnrollment `json:"mfa_enrollment,omitempty"`
 }
 
 // UserInfo contains user information returned after login
@@ -83,12 +158,21 @@ type UserInfo struct {
 
 // Login authenticates a user with email and password
 func (s *AuthService) Login(ctx context.Context, email, password string) (*LoginResult, error) {
+	return s.LoginFrom(ctx, email, password, LoginSource{})
+}
+
+// LoginFrom authenticates a user with email and password and records the login with its source
+// Users with MFA get an MFA challenge instead of the token pair (see VerifyMFA)
+func (s *AuthService) LoginFrom(ctx context.Context, email, password string, source LoginSource) (*LoginResult, error) {
 	// Fetch user by email
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, ErrUserNotFound) {
 			return nil, ErrInvalidCredentials
 		}
+		if errors.Is(err, ErrTenantRequired) {
+			return nil, s.tenantRequiredError(ctx, email, password)
+		}
 		return nil, err
 	}
 
@@ -102,6 +186,36 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*Login
 		return nil, ErrInvalidCredentials
 	}
 
+	if emailVerificationExpired(user) {
+		return nil, ErrEmailNotVerified
+	}
+
+	challenge, err := s.mfaChallenge(ctx, user)
+	if err != nil || challenge != nil {
+		return challenge, err
+	}
+
+	return s.completeLogin(ctx, user, source)
+}
+
+// tenantRequiredError returns ErrTenantRequired only when the password matches one of the accounts with
+// the email, so callers without a valid password cannot learn that the email is in several tenants
+func (s *AuthService) tenantRequiredError(ctx context.Context, email, password string) error {
+	hashes, err := s.userRepo.ListPasswordHashesByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		if CheckPasswordHash(password, hash) == nil {
+			return ErrTenantRequired
+		}
+	}
+	return ErrInvalidCredentials
+}
+
+// completeLogin issues the token pair of an authenticated user and records the login
+// A failure to record the login is logged and does not fail the login
+func (s *AuthService) completeLogin(ctx context.Context, user *User, source LoginSource) (*LoginResult, error) {
 	// Generate tokens with tenant context
 	hospitalID := ""
 	if user.HospitalID != nil {
@@ -125,11 +239,21 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*Login
 		return nil, err
 	}
 
+	if err := s.registerRefreshToken(ctx, refreshToken, source, nil); err != nil {
+		return nil, err
+	}
+
+	if s.loginRecorder != nil {
+		if err := s.loginRecorder.RecordLogin(ctx, user.ID, source); err != nil {
+			log.Printf("[Auth] Failed to record login of user %s: %v", user.ID, err)
+		}
+	}
+
 	return &LoginResult{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		TokenType:    "Bearer",
-		ExpiresIn:    int64(s.jwtService.GetAccessTokenDuration().Seconds()),
+		ExpiresIn:    int64(s.jwtService.AccessTokenDuration(user.Role).Seconds()),
 		User: &UserInfo{
 			ID:           user.ID,
 			Email:        user.Email,
@@ -151,22 +275,14 @@ type RefreshResult struct {
 }
 
 // Refresh generates new tokens using a valid refresh token
+// The presented refresh token is rotated: it is consumed and revoked, and a new one is issued
 func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*RefreshResult, error) {
-	// Validate refresh token
-	claims, err := s.jwtService.ValidateRefreshToken(refreshToken)
+	// Validate refresh token (signature, expiry, revocation and rotation state)
+	claims, err := s.ValidateRefreshToken(ctx, refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if token is revoked
-	isRevoked, err := s.isTokenRevoked(ctx, claims.ID)
-	if err != nil {
-		return nil, err
-	}
-	if isRevoked {
-		return nil, ErrTokenRevoked
-	}
-
 	// Verify user still exists and is active
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
@@ -182,10 +298,29 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*Refres
 		return nil, ErrUserInactive
 	}
 
-	// Revoke old refresh token
-	if err := s.revokeToken(ctx, claims.ID, s.jwtService.GetRefreshTokenDuration()); err != nil {
-		// Log error but continue - token rotation is best effort
-		_ = err
+	// Sessions started during the grace period end with it
+	if emailVerificationExpired(user) {
+		return nil, ErrEmailNotVerified
+	}
+
+	// Consume the old refresh token; losing a concurrent race means it was already rotated
+	if s.tokenStore != nil {
+		consumed, err := s.tokenStore.Consume(ctx, claims.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !consumed {
+			return nil, ErrTokenRevoked
+		}
+		if err := s.tokenStore.Revoke(ctx, claims.ID, time.Until(claims.ExpiresAt.Time)); err != nil {
+			return nil, err
+		}
+	}
+
+	// The session moves to the new refresh token
+	session, err := s.takeSession(ctx, claims)
+	if err != nil {
+		return nil, err
 	}
 
 	// Generate new tokens with tenant context
@@ -211,14 +346,49 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*Refres
 		return nil, err
 	}
 
+	if err := s.registerRefreshToken(ctx, newRefreshToken, LoginSource{}, session); err != nil {
+		return nil, err
+	}
+
 	return &RefreshResult{
 		AccessToken:  newAccessToken,
 		RefreshToken: newRefreshToken,
 		TokenType:    "Bearer",
-		ExpiresIn:    int64(s.jwtService.GetAccessTokenDuration().Seconds()),
+		ExpiresIn:    int64(s.jwtService.AccessTokenDuration(user.Role).Seconds()),
 	}, nil
 }
 
+// ValidateRefreshToken validates a refresh token and rejects revoked or already rotated tokens
+func (s *AuthService) ValidateRefreshToken(ctx context.Context, refreshToken string) (*Claims, error) {
+	claims, err := s.jwtService.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.tokenStore == nil {
+		return claims, nil
+	}
+
+	revoked, err := s.tokenStore.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	// Tokens no longer active were rotated out or removed on logout
+	active, err := s.tokenStore.IsActive(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
 // Logout invalidates a refresh token
 func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
 	// Validate refresh token to get its ID
@@ -229,33 +399,125 @@ func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
 		return nil
 	}
 
-	// Revoke the token
-	return s.revokeToken(ctx, claims.ID, s.jwtService.GetRefreshTokenDuration())
+	if s.tokenStore == nil {
+		return nil
+	}
+
+	if err := s.revokeRefreshToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+	_, err = s.takeSession(ctx, claims)
+	return err
 }
 
-// revokeToken adds a token ID to the revoked tokens set
-func (s *AuthService) revokeToken(ctx context.Context, tokenID string, ttl time.Duration) error {
-	if s.redisClient == nil {
+// ListSessions returns the active sessions of the user, newest first
+func (s *AuthService) ListSessions(ctx context.Context, userID string) ([]Session, error) {
+	if s.tokenStore == nil || s.sessionStore == nil {
+		return nil, ErrSessionsUnavailable
+	}
+	return s.sessionStore.List(ctx, userID)
+}
+
+// RevokeSession revokes a session of the user by the jti of its current refresh token
+// The access tokens already issued to the session stay valid until they expire
+func (s *AuthService) RevokeSession(ctx context.Context, userID, tokenID string) error {
+	if s.tokenStore == nil || s.sessionStore == nil {
+		return ErrSessionsUnavailable
+	}
+
+	// Only sessions of the user can be revoked
+	session, err := s.sessionStore.Get(ctx, userID, tokenID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.revokeRefreshToken(ctx, tokenID, session.ExpiresAt); err != nil {
+		return err
+	}
+	_, err = s.sessionStore.Remove(ctx, userID, tokenID)
+	return err
+}
+
+// RevokeAllSessions revokes every session of the user ("logout everywhere") and returns how many were revoked
+func (s *AuthService) RevokeAllSessions(ctx context.Context, userID string) (int, error) {
+	sessions, err := s.ListSessions(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, session := range sessions {
+		if err := s.RevokeSession(ctx, userID, session.TokenID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return 0, err
+		}
+	}
+	return len(sessions), nil
+}
+
+// revokeRefreshToken removes a refresh token from the active set and revokes it until it expires
+func (s *AuthService) revokeRefreshToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	if _, err := s.tokenStore.Consume(ctx, tokenID); err != nil {
+		return err
+	}
+	return s.tokenStore.Revoke(ctx, tokenID, time.Until(expiresAt))
+}
+
+// registerRefreshToken records a newly issued refresh token as the active one and tracks its session
+// A rotated token continues the previous session, keeping where it was created; otherwise a new session starts from source
+func (s *AuthService) registerRefreshToken(ctx context.Context, refreshToken string, source LoginSource, previous *Session) error {
+	if s.tokenStore == nil {
 		return nil
 	}
 
-	key := fmt.Sprintf("%s:%s", s.revokedKeyPrefix, tokenID)
-	return s.redisClient.Set(ctx, key, "revoked", ttl).Err()
+	claims, err := s.jwtService.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+
+	// The duration of the token depends on the role, so the active entry follows its own expiration
+	if err := s.tokenStore.Register(ctx, claims.ID, time.Until(claims.ExpiresAt.Time)); err != nil {
+		return err
+	}
+
+	if s.sessionStore == nil {
+		return nil
+	}
+
+	session := &Session{
+		TokenID:   claims.ID,
+		UserID:    claims.UserID,
+		IPAddress: source.IPAddress,
+		UserAgent: source.UserAgent,
+		CreatedAt: claims.IssuedAt.Time,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if previous != nil {
+		refreshedAt := claims.IssuedAt.Time
+		session.IPAddress = previous.IPAddress
+		session.UserAgent = previous.UserAgent
+		session.CreatedAt = previous.CreatedAt
+		session.RefreshedAt = &refreshedAt
+	}
+	return s.sessionStore.Track(ctx, session)
 }
 
-// isTokenRevoked checks if a token has been revoked
-func (s *AuthService) isTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
-	if s.redisClient == nil {
-		return false, nil
+// takeSession removes the session of a refresh token and returns it, or nil when it is not tracked
+func (s *AuthService) takeSession(ctx context.Context, claims *Claims) (*Session, error) {
+	if s.sessionStore == nil {
+		return nil, nil
 	}
 
-	key := fmt.Sprintf("%s:%s", s.revokedKeyPrefix, tokenID)
-	result, err := s.redisClient.Exists(ctx, key).Result()
+	session, err := s.sessionStore.Get(ctx, claims.UserID, claims.ID)
+	if errors.Is(err, ErrSessionNotFound) {
+		return nil, nil
+	}
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	return result > 0, nil
+	if _, err := s.sessionStore.Remove(ctx, claims.UserID, claims.ID); err != nil {
+		return nil, err
+	}
+	return session, nil
 }
 
 // GetCurrentUser retrieves the current user from their ID
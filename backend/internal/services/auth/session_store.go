@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrSessionNotFound is returned when a session is not active
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session describes the refresh token chain of a login
+// The session follows the rotations of its refresh token: TokenID is the jti of the current one
+type Session struct {
+	TokenID     string     `json:"jti"`
+	UserID      string     `json:"user_id"`
+	IPAddress   string     `json:"ip_address,omitempty"`
+	UserAgent   string     `json:"user_agent,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RefreshedAt *time.Time `json:"refreshed_at,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+}
+
+// SessionStore tracks the sessions of each user by the jti of their current refresh token
+type SessionStore interface {
+	// Track records a session under its token ID
+	Track(ctx context.Context, session *Session) error
+
+	// List returns the sessions of the user that have not expired, newest first
+	List(ctx context.Context, userID string) ([]Session, error)
+
+	// Get returns an active session of the user, or ErrSessionNotFound
+	Get(ctx context.Context, userID, tokenID string) (*Session, error)
+
+	// Remove removes a session of the user, returning false if it was not tracked
+	Remove(ctx context.Context, userID, tokenID string) (bool, error)
+}
+
+// RedisSessionStore implements SessionStore with one Redis hash of sessions per user, keyed by jti
+type RedisSessionStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisSessionStore creates a Redis-backed session store
+func NewRedisSessionStore(client *redis.Client) *RedisSessionStore {
+	return &RedisSessionStore{
+		client:    client,
+		keyPrefix: "user_sessions",
+	}
+}
+
+func (s *RedisSessionStore) key(userID string) string {
+	return fmt.Sprintf("%s:%s", s.keyPrefix, userID)
+}
+
+// Track records a session and keeps the hash of the user until its last session expires
+// Token durations can change by role, so the expiration of the hash is only ever extended
+func (s *RedisSessionStore) Track(ctx context.Context, session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	key := s.key(session.UserID)
+	ttl := time.Until(session.ExpiresAt)
+	if current, err := s.client.TTL(ctx, key).Result(); err == nil && current > ttl {
+		ttl = current
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key, session.TokenID, data)
+	pipe.Expire(ctx, key, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// List returns active sessions, newest first, pruning the expired ones from the hash
+func (s *RedisSessionStore) List(ctx context.Context, userID string) ([]Session, error) {
+	key := s.key(userID)
+	entries, err := s.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]Session, 0, len(entries))
+	var expired []string
+	for tokenID, data := range entries {
+		var session Session
+		if err := json.Unmarshal([]byte(data), &session); err != nil || !session.ExpiresAt.After(now) {
+			expired = append(expired, tokenID)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	if len(expired) > 0 {
+		s.client.HDel(ctx, key, expired...)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+// Get returns an active session of the user, or ErrSessionNotFound
+func (s *RedisSessionStore) Get(ctx context.Context, userID, tokenID string) (*Session, error) {
+	data, err := s.client.HGet(ctx, s.key(userID), tokenID).Result()
+	if err == redis.Nil {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session Session
+	if err := json.Unmarshal([]byte(data), &session); err != nil || !session.ExpiresAt.After(time.Now()) {
+		return nil, ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+// Remove removes a session of the user (HDEL returns the number of fields removed)
+func (s *RedisSessionStore) Remove(ctx context.Context, userID, tokenID string) (bool, error) {
+	removed, err := s.client.HDel(ctx, s.key(userID), tokenID).Result()
+	if err != nil {
+		return false, err
+	}
+	return removed > 0, nil
+}
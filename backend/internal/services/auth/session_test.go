@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+)
+
+// memorySessionStore keeps the sessions of the users in memory
+type memorySessionStore struct {
+	sessions map[string]map[string]Session
+}
+
+func newMemorySessionStore() *memorySessionStore {
+	return &memorySessionStore{sessions: make(map[string]map[string]Session)}
+}
+
+func (m *memorySessionStore) Track(ctx context.Context, session *Session) error {
+	if m.sessions[session.UserID] == nil {
+		m.sessions[session.UserID] = make(map[string]Session)
+	}
+	m.sessions[session.UserID][session.TokenID] = *session
+	return nil
+}
+
+func (m *memorySessionStore) List(ctx context.Context, userID string) ([]Session, error) {
+	sessions := []Session{}
+	for _, session := range m.sessions[userID] {
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+func (m *memorySessionStore) Get(ctx context.Context, userID, tokenID string) (*Session, error) {
+	session, ok := m.sessions[userID][tokenID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+func (m *memorySessionStore) Remove(ctx context.Context, userID, tokenID string) (bool, error) {
+	_, ok := m.sessions[userID][tokenID]
+	delete(m.sessions[userID], tokenID)
+	return ok, nil
+}
+
+// newSessionTestService creates an auth service with in-memory token and session stores and a test user
+func newSessionTestService(t *testing.T) (*AuthService, *User) {
+	t.Helper()
+
+	service, _, user := newRotationTestService(t)
+	passwordHash, err := HashPassword("SenhaSegura123!")
+	if err != nil {
+		t.Fatalf("HashPassword failed: %v", err)
+	}
+	user.PasswordHash = passwordHash
+	service.SetSessionStore(newMemorySessionStore())
+	return service, user
+}
+
+// loginFrom signs the test user in from the source and returns the refresh token
+func loginFrom(t *testing.T, service *AuthService, user *User, source LoginSource) string {
+	t.Helper()
+
+	result, err := service.LoginFrom(context.Background(), user.Email, "SenhaSegura123!", source)
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	return result.RefreshToken
+}
+
+// sessionTokenID returns the jti of a refresh token
+func sessionTokenID(t *testing.T, service *AuthService, refreshToken string) string {
+	t.Helper()
+
+	claims, err := service.jwtService.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		t.Fatalf("Failed to validate refresh token: %v", err)
+	}
+	return claims.ID
+}
+
+// Testar que a sessao revogada nao renova o token e as demais sessoes continuam
+func TestRevokedSessionRefreshFails(t *testing.T) {
+	service, user := newSessionTestService(t)
+	ctx := context.Background()
+
+	laptop := loginFrom(t, service, user, LoginSource{IPAddress: "10.0.0.7", UserAgent: "Firefox"})
+	phone := loginFrom(t, service, user, LoginSource{IPAddress: "10.0.0.8", UserAgent: "SIDOT Android"})
+
+	sessions, err := service.ListSessions(ctx, user.ID.String())
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	for _, session := range sessions {
+		if session.UserAgent == "" || session.IPAddress == "" || session.CreatedAt.IsZero() || !session.ExpiresAt.After(time.Now()) {
+			t.Errorf("Expected the device info and dates of the session, got %+v", session)
+		}
+	}
+
+	// Sessions of other users cannot be revoked
+	if err := service.RevokeSession(ctx, "outro-usuario", sessionTokenID(t, service, laptop)); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound for the session of another user, got %v", err)
+	}
+
+	if err := service.RevokeSession(ctx, user.ID.String(), sessionTokenID(t, service, laptop)); err != nil {
+		t.Fatalf("RevokeSession failed: %v", err)
+	}
+	if _, err := service.ValidateRefreshToken(ctx, laptop); err != ErrTokenRevoked {
+		t.Errorf("Expected ValidateRefreshToken to reject the revoked session, got %v", err)
+	}
+	if _, err := service.Refresh(ctx, laptop); err != ErrTokenRevoked {
+		t.Errorf("Expected the refresh of the revoked session to fail, got %v", err)
+	}
+
+	refreshed, err := service.Refresh(ctx, phone)
+	if err != nil {
+		t.Fatalf("Expected the other session to keep refreshing, got %v", err)
+	}
+
+	// The session follows the rotated refresh token and keeps its device info
+	sessions, _ = service.ListSessions(ctx, user.ID.String())
+	if len(sessions) != 1 {
+		t.Fatalf("Expected 1 session after the revocation, got %d", len(sessions))
+	}
+	if sessions[0].TokenID != sessionTokenID(t, service, refreshed.RefreshToken) || sessions[0].UserAgent != "SIDOT Android" || sessions[0].RefreshedAt == nil {
+		t.Errorf("Expected the session to move to the new refresh token, got %+v", sessions[0])
+	}
+}
+
+// Testar que o logout em todos os dispositivos revoga todas as sessoes
+func TestRevokeAllSessions(t *testing.T) {
+	service, user := newSessionTestService(t)
+	ctx := context.Background()
+
+	tokens := []string{
+		loginFrom(t, service, user, LoginSource{UserAgent: "Firefox"}),
+		loginFrom(t, service, user, LoginSource{UserAgent: "Chrome"}),
+	}
+
+	revoked, err := service.RevokeAllSessions(ctx, user.ID.String())
+	if err != nil {
+		t.Fatalf("RevokeAllSessions failed: %v", err)
+	}
+	if revoked != 2 {
+		t.Errorf("Expected 2 sessions revoked, got %d", revoked)
+	}
+
+	for _, token := range tokens {
+		if _, err := service.Refresh(ctx, token); err != ErrTokenRevoked {
+			t.Errorf("Expected the refresh of a revoked session to fail, got %v", err)
+		}
+	}
+	if sessions, _ := service.ListSessions(ctx, user.ID.String()); len(sessions) != 0 {
+		t.Errorf("Expected no sessions left, got %d", len(sessions))
+	}
+
+	// Logout ends the session of its refresh token
+	token := loginFrom(t, service, user, LoginSource{UserAgent: "Safari"})
+	if err := service.Logout(ctx, token); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if sessions, _ := service.ListSessions(ctx, user.ID.String()); len(sessions) != 0 {
+		t.Errorf("Expected logout to remove the session, got %d", len(sessions))
+	}
+}
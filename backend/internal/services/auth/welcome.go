@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sidot/backend/internal/models"
+)
+
+// WelcomeResendInterval is how long a user waits between two resent welcome emails
+const WelcomeResendInterval = 10 * time.Minute
+
+// Kinds of welcome email
+const (
+	// WelcomeKindVerification is the verification link of a user that has not verified its email
+	WelcomeKindVerification = "verification"
+
+	// WelcomeKindTemporaryPassword is a new temporary password of a verified user
+	WelcomeKindTemporaryPassword = "temporary_password"
+)
+
+var (
+	// ErrWelcomeUnavailable is returned when welcome emails cannot be sent (no Redis or SMTP)
+	ErrWelcomeUnavailable = errors.New("welcome email is not available")
+
+	// ErrWelcomeRateLimited is matched by the error returned when the welcome email was resent recently
+	ErrWelcomeRateLimited = errors.New("welcome email was resent recently")
+)
+
+// WelcomeRateLimitError reports how long to wait before resending the welcome email; it matches ErrWelcomeRateLimited
+type WelcomeRateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *WelcomeRateLimitError) Error() string {
+	return fmt.Sprintf("welcome email was resent recently, try again in %d seconds", int(e.RetryAfter.Seconds()))
+}
+
+func (e *WelcomeRateLimitError) Unwrap() error {
+	return ErrWelcomeRateLimited
+}
+
+// ResendThrottle allows one resend per key within an interval
+type ResendThrottle interface {
+	// Acquire reports whether the key can be resent now, or how long until it can
+	Acquire(ctx context.Context, key string, interval time.Duration) (bool, time.Duration, error)
+
+	// Release frees the key again, for a resend that could not be delivered
+	Release(ctx context.Context, key string) error
+}
+
+// PasswordUpdater stores new password hashes (implemented by repository.UserRepository)
+type PasswordUpdater interface {
+	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+}
+
+// WelcomeMailer sends the welcome email with the temporary password (implemented by notification.EmailService)
+type WelcomeMailer interface {
+	IsConfigured() bool
+	SendWelcomeEmail(ctx context.Context, to, nome, locale, loginURL, temporaryPassword string) error
+}
+
+// WelcomeResend describes the welcome email that was resent
+type WelcomeResend struct {
+	Kind string `json:"kind"`
+}
+
+// RedisResendThrottle implements ResendThrottle with one expiring Redis key per resend
+type RedisResendThrottle struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+// NewRedisResendThrottle creates a Redis-backed resend throttle
+func NewRedisResendThrottle(client *redis.Client) *RedisResendThrottle {
+	return &RedisResendThrottle{
+		client:    client,
+		keyPrefix: "welcome_resend",
+	}
+}
+
+// Acquire sets the key of the resend unless it is still set (SETNX), returning its TTL otherwise
+func (t *RedisResendThrottle) Acquire(ctx context.Context, key string, interval time.Duration) (bool, time.Duration, error) {
+	fullKey := fmt.Sprintf("%s:%s", t.keyPrefix, key)
+	acquired, err := t.client.SetNX(ctx, fullKey, time.Now().Unix(), interval).Result()
+	if err != nil || acquired {
+		return acquired, 0, err
+	}
+
+	ttl, err := t.client.TTL(ctx, fullKey).Result()
+	if err != nil {
+		return false, 0, err
+	}
+	return false, ttl, nil
+}
+
+// Release deletes the key of the resend
+func (t *RedisResendThrottle) Release(ctx context.Context, key string) error {
+	return t.client.Del(ctx, fmt.Sprintf("%s:%s", t.keyPrefix, key)).Err()
+}
+
+// SetWelcomeEmail sets where temporary passwords are stored and how the welcome emails are sent
+// The welcome emails link to loginURL
+func (s *AuthService) SetWelcomeEmail(passwords PasswordUpdater, mailer WelcomeMailer, loginURL string) {
+	s.passwordUpdater = passwords
+	s.welcomeMailer = mailer
+	s.loginURL = loginURL
+}
+
+// SetResendThrottle replaces the throttle of the resent welcome emails
+func (s *AuthService) SetResendThrottle(throttle ResendThrottle) {
+	s.resendThrottle = throttle
+}
+
+// ResendWelcome resends the onboarding email of a user, at most once per WelcomeResendInterval
+// Users that have not verified their email get a new verification link; the others get a new temporary password.
+// When the email cannot be sent the previous password is restored and the resend can be retried right away.
+func (s *AuthService) ResendWelcome(ctx context.Context, user *models.User) (resend *WelcomeResend, err error) {
+	if !user.Ativo || user.DeletedAt != nil {
+		return nil, ErrUserInactive
+	}
+
+	kind := WelcomeKindTemporaryPassword
+	if !user.EmailVerified && s.EmailVerificationAvailable() {
+		kind = WelcomeKindVerification
+	}
+	if kind == WelcomeKindTemporaryPassword &&
+		(s.passwordUpdater == nil || s.welcomeMailer == nil || !s.welcomeMailer.IsConfigured()) {
+		return nil, ErrWelcomeUnavailable
+	}
+	if s.resendThrottle == nil {
+		return nil, ErrWelcomeUnavailable
+	}
+
+	allowed, retryAfter, err := s.resendThrottle.Acquire(ctx, user.ID.String(), WelcomeResendInterval)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, &WelcomeRateLimitError{RetryAfter: retryAfter}
+	}
+	defer func() {
+		if err != nil {
+			if releaseErr := s.resendThrottle.Release(ctx, user.ID.String()); releaseErr != nil {
+				log.Printf("[Auth] Warning: could not release the welcome resend of user %s: %v", user.ID, releaseErr)
+			}
+		}
+	}()
+
+	if kind == WelcomeKindVerification {
+		if err := s.SendEmailVerification(ctx, user); err != nil {
+			return nil, err
+		}
+		return &WelcomeResend{Kind: kind}, nil
+	}
+
+	password, err := GenerateTemporaryPassword()
+	if err != nil {
+		return nil, err
+	}
+	passwordHash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.passwordUpdater.UpdatePassword(ctx, user.ID, passwordHash); err != nil {
+		return nil, err
+	}
+
+	if err := s.welcomeMailer.SendWelcomeEmail(ctx, user.Email, user.Nome, user.PreferredLocale(), s.loginURL, password); err != nil {
+		// The new password was never delivered, keep the user able to sign in with the previous one
+		if restoreErr := s.passwordUpdater.UpdatePassword(ctx, user.ID, user.PasswordHash); restoreErr != nil {
+			log.Printf("[Auth] Warning: could not restore the password of user %s: %v", user.ID, restoreErr)
+		}
+		return nil, err
+	}
+	return &WelcomeResend{Kind: kind}, nil
+}
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// memoryResendThrottle allows one resend per key within the interval
+type memoryResendThrottle struct {
+	until map[string]time.Time
+}
+
+func (t *memoryResendThrottle) Acquire(ctx context.Context, key string, interval time.Duration) (bool, time.Duration, error) {
+	if until, ok := t.until[key]; ok && time.Now().Before(until) {
+		return false, time.Until(until), nil
+	}
+	t.until[key] = time.Now().Add(interval)
+	return true, 0, nil
+}
+
+func (t *memoryResendThrottle) Release(ctx context.Context, key string) error {
+	delete(t.until, key)
+	return nil
+}
+
+// memoryPasswordUpdater keeps the password hashes in memory
+type memoryPasswordUpdater struct {
+	hashes map[uuid.UUID]string
+}
+
+func (u *memoryPasswordUpdater) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	u.hashes[userID] = passwordHash
+	return nil
+}
+
+// recordingWelcomeMailer records the temporary passwords sent, failing with err when set
+type recordingWelcomeMailer struct {
+	passwords []string
+	err       error
+}
+
+func (m *recordingWelcomeMailer) IsConfigured() bool {
+	return true
+}
+
+func (m *recordingWelcomeMailer) SendWelcomeEmail(ctx context.Context, to, nome, locale, loginURL, temporaryPassword string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.passwords = append(m.passwords, temporaryPassword)
+	return nil
+}
+
+// newWelcomeTestService creates an auth service that resends welcome emails and a verified user
+func newWelcomeTestService(t *testing.T) (*AuthService, *memoryPasswordUpdater, *recordingWelcomeMailer, *models.User) {
+	t.Helper()
+
+	service, _, _ := newRotationTestService(t)
+	passwords := &memoryPasswordUpdater{hashes: make(map[uuid.UUID]string)}
+	mailer := &recordingWelcomeMailer{}
+	service.SetWelcomeEmail(passwords, mailer, "https://sidot.example/login")
+	service.SetResendThrottle(&memoryResendThrottle{until: make(map[string]time.Time)})
+
+	user := &models.User{
+		ID:            uuid.New(),
+		Email:         "operador@sidot.gov.br",
+		Nome:          "Operador Novo",
+		Role:          models.RoleOperador,
+		Ativo:         true,
+		EmailVerified: true,
+	}
+	return service, passwords, mailer, user
+}
+
+// Testar que o reenvio gera uma nova senha temporaria e bloqueia reenvios seguidos
+func TestResendWelcomeRateLimited(t *testing.T) {
+	service, passwords, mailer, user := newWelcomeTestService(t)
+	ctx := context.Background()
+
+	resend, err := service.ResendWelcome(ctx, user)
+	if err != nil {
+		t.Fatalf("ResendWelcome failed: %v", err)
+	}
+	if resend.Kind != WelcomeKindTemporaryPassword {
+		t.Errorf("Expected a temporary password, got %s", resend.Kind)
+	}
+	if len(mailer.passwords) != 1 {
+		t.Fatalf("Expected 1 welcome email, got %d", len(mailer.passwords))
+	}
+	if err := CheckPasswordHash(mailer.passwords[0], passwords.hashes[user.ID]); err != nil {
+		t.Errorf("Expected the stored hash to match the emailed password, got %v", err)
+	}
+
+	_, err = service.ResendWelcome(ctx, user)
+	var rateLimited *WelcomeRateLimitError
+	if !errors.Is(err, ErrWelcomeRateLimited) || !errors.As(err, &rateLimited) || rateLimited.RetryAfter <= 0 {
+		t.Fatalf("Expected the repeated resend to be rate limited, got %v", err)
+	}
+	if len(mailer.passwords) != 1 {
+		t.Errorf("Expected no email for the rate limited resend, got %d", len(mailer.passwords))
+	}
+
+	// The limit is per user
+	other := *user
+	other.ID = uuid.New()
+	if _, err := service.ResendWelcome(ctx, &other); err != nil {
+		t.Errorf("Expected the resend of another user, got %v", err)
+	}
+}
+
+// Testar que uma falha de envio mantem a senha anterior e permite reenviar imediatamente
+func TestResendWelcomeSendFailureKeepsPassword(t *testing.T) {
+	service, passwords, mailer, user := newWelcomeTestService(t)
+	ctx := context.Background()
+
+	previousHash, err := HashPassword("SenhaAnterior#2024")
+	if err != nil {
+		t.Fatalf("HashPassword failed: %v", err)
+	}
+	user.PasswordHash = previousHash
+	passwords.hashes[user.ID] = previousHash
+
+	smtpErr := errors.New("smtp unavailable")
+	mailer.err = smtpErr
+	if _, err := service.ResendWelcome(ctx, user); !errors.Is(err, smtpErr) {
+		t.Fatalf("Expected the send error, got %v", err)
+	}
+	if passwords.hashes[user.ID] != previousHash {
+		t.Error("Expected the previous password to be restored after the failed send")
+	}
+
+	mailer.err = nil
+	if _, err := service.ResendWelcome(ctx, user); err != nil {
+		t.Fatalf("Expected the retry not to be rate limited, got %v", err)
+	}
+	if len(mailer.passwords) != 1 {
+		t.Errorf("Expected the retry to send the email, got %d", len(mailer.passwords))
+	}
+}
+
+// Testar que o usuario sem email verificado recebe um novo link de verificacao
+func TestResendWelcomeSendsVerificationLink(t *testing.T) {
+	service, passwords, welcomeMailer, user := newWelcomeTestService(t)
+	user.EmailVerified = false
+
+	verificationMailer := &recordingMailer{}
+	service.SetVerificationTokenStore(&memoryVerificationTokenStore{tokens: make(map[string]string)})
+	service.SetEmailVerification(&userEmailVerifier{user: &User{ID: user.ID}}, verificationMailer, "https://sidot.example/verify-email")
+
+	resend, err := service.ResendWelcome(context.Background(), user)
+	if err != nil {
+		t.Fatalf("ResendWelcome failed: %v", err)
+	}
+	if resend.Kind != WelcomeKindVerification || len(verificationMailer.links) != 1 {
+		t.Errorf("Expected a new verification link, got %s (%d links)", resend.Kind, len(verificationMailer.links))
+	}
+	if len(welcomeMailer.passwords) != 0 || len(passwords.hashes) != 0 {
+		t.Error("Expected the password to be kept for an unverified user")
+	}
+}
+
+// Testar que usuarios inativos nao recebem o email de boas-vindas
+func TestResendWelcomeInactiveUser(t *testing.T) {
+	service, _, mailer, user := newWelcomeTestService(t)
+	user.Ativo = false
+
+	if _, err := service.ResendWelcome(context.Background(), user); err != ErrUserInactive {
+		t.Errorf("Expected ErrUserInactive, got %v", err)
+	}
+	if len(mailer.passwords) != 0 {
+		t.Error("Expected no welcome email for an inactive user")
+	}
+}
+
+// Testar que a senha temporaria atende a politica de senha ativa
+func TestGenerateTemporaryPasswordMeetsPolicy(t *testing.T) {
+	policy := models.PasswordPolicyConfig{MinLength: 20, RequireUpper: true, RequireDigit: true, RequireSymbol: true}
+	SetActivePasswordPolicy(policy)
+	t.Cleanup(func() { SetActivePasswordPolicy(models.DefaultPasswordPolicyConfig()) })
+
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		password, err := GenerateTemporaryPassword()
+		if err != nil {
+			t.Fatalf("GenerateTemporaryPassword failed: %v", err)
+		}
+		if err := ValidatePasswordWithPolicy(password, policy); err != nil {
+			t.Errorf("Expected %q to meet the policy, got %v", password, err)
+		}
+		seen[password] = true
+	}
+	if len(seen) != 20 {
+		t.Errorf("Expected distinct temporary passwords, got %d", len(seen))
+	}
+}
@@ -6,10 +6,17 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
 )
 
+// LegacyKeyVersion is the key version assumed for ciphertext without a version prefix
+// (values encrypted before key versions were introduced)
+const LegacyKeyVersion = 1
+
 var (
 	// ErrEncryptionKeyNotSet is returned when the encryption key environment variable is not set
 	ErrEncryptionKeyNotSet = errors.New("encryption key not set: ENCRYPTION_KEY environment variable is required")
@@ -22,20 +29,65 @@ var (
 
 	// ErrCiphertextTooShort is returned when the ciphertext is too short
 	ErrCiphertextTooShort = errors.New("ciphertext too short")
+
+	// ErrUnknownKeyVersion is returned when a ciphertext was encrypted with a key version the service does not have
+	ErrUnknownKeyVersion = errors.New("unknown encryption key version")
+
+	// ErrInvalidKeyVersion is returned when a key version is not a positive integer
+	ErrInvalidKeyVersion = errors.New("invalid encryption key version: must be a positive integer")
 )
 
 // EncryptionService provides AES-256-GCM encryption and decryption
+// Values are encrypted with the current key and prefixed with its version ("v2:<base64>"), so
+// values written with previous keys can still be decrypted while a key rotation is rolled out
 type EncryptionService struct {
-	key []byte
+	key     []byte
+	version int
+
+	// previousKeys holds retired keys by version, used only for decryption
+	previousKeys map[int][]byte
 }
 
 // NewEncryptionService creates a new encryption service using the ENCRYPTION_KEY environment variable
+// ENCRYPTION_KEY_VERSION sets the version of that key (default 1) and ENCRYPTION_PREVIOUS_KEYS
+// lists retired keys still accepted for decryption, as comma-separated "version:key" pairs
 func NewEncryptionService() (*EncryptionService, error) {
 	keyStr := os.Getenv("ENCRYPTION_KEY")
 	if keyStr == "" {
 		return nil, ErrEncryptionKeyNotSet
 	}
 
+	key, err := ParseEncryptionKey(keyStr)
+	if err != nil {
+		return nil, err
+	}
+
+	version := LegacyKeyVersion
+	if versionStr := os.Getenv("ENCRYPTION_KEY_VERSION"); versionStr != "" {
+		version, err = strconv.Atoi(versionStr)
+		if err != nil || version < 1 {
+			return nil, ErrInvalidKeyVersion
+		}
+	}
+
+	svc, err := NewEncryptionServiceWithVersion(version, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if previous := os.Getenv("ENCRYPTION_PREVIOUS_KEYS"); previous != "" {
+		for _, entry := range strings.Split(previous, ",") {
+			if err := svc.AddPreviousKeyEntry(entry); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return svc, nil
+}
+
+// ParseEncryptionKey decodes a key given as base64 (32 random bytes) or as a raw 32-byte string
+func ParseEncryptionKey(keyStr string) ([]byte, error) {
 	// Decode the key from base64 (allows for 32 random bytes encoded as base64)
 	key, err := base64.StdEncoding.DecodeString(keyStr)
 	if err != nil {
@@ -48,20 +100,101 @@ func NewEncryptionService() (*EncryptionService, error) {
 		return nil, ErrInvalidKeyLength
 	}
 
-	return &EncryptionService{key: key}, nil
+	return key, nil
 }
 
 // NewEncryptionServiceWithKey creates a new encryption service with a provided key
-// The key must be exactly 32 bytes for AES-256
+// The key must be exactly 32 bytes for AES-256 and is used as version 1
 func NewEncryptionServiceWithKey(key []byte) (*EncryptionService, error) {
+	return NewEncryptionServiceWithVersion(LegacyKeyVersion, key)
+}
+
+// NewEncryptionServiceWithVersion creates a new encryption service whose current key has the given version
+func NewEncryptionServiceWithVersion(version int, key []byte) (*EncryptionService, error) {
 	if len(key) != 32 {
 		return nil, ErrInvalidKeyLength
 	}
+	if version < 1 {
+		return nil, ErrInvalidKeyVersion
+	}
+
+	return &EncryptionService{
+		key:          key,
+		version:      version,
+		previousKeys: map[int][]byte{},
+	}, nil
+}
+
+// AddPreviousKey registers a retired key so values encrypted with it can still be decrypted
+func (s *EncryptionService) AddPreviousKey(version int, key []byte) error {
+	if len(key) != 32 {
+		return ErrInvalidKeyLength
+	}
+	if version < 1 {
+		return ErrInvalidKeyVersion
+	}
+	if version == s.version {
+		return fmt.Errorf("key version %d is already the current key", version)
+	}
+
+	s.previousKeys[version] = key
+	return nil
+}
+
+// AddPreviousKeyEntry registers a retired key given as "version:key"
+func (s *EncryptionService) AddPreviousKeyEntry(entry string) error {
+	versionStr, keyStr, found := strings.Cut(strings.TrimSpace(entry), ":")
+	if !found {
+		return errors.New("invalid previous key entry: expected version:key")
+	}
+
+	version, err := strconv.Atoi(versionStr)
+	if err != nil {
+		return ErrInvalidKeyVersion
+	}
+	key, err := ParseEncryptionKey(keyStr)
+	if err != nil {
+		return err
+	}
 
-	return &EncryptionService{key: key}, nil
+	return s.AddPreviousKey(version, key)
+}
+
+// KeyVersion returns the version of the key used for encryption
+func (s *EncryptionService) KeyVersion() int {
+	return s.version
+}
+
+// CiphertextKeyVersion returns the key version a value from EncryptValue was encrypted with
+func CiphertextKeyVersion(encodedCiphertext string) (int, error) {
+	prefix, _, found := strings.Cut(encodedCiphertext, ":")
+	if !found {
+		return LegacyKeyVersion, nil
+	}
+
+	if !strings.HasPrefix(prefix, "v") {
+		return 0, ErrUnknownKeyVersion
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(prefix, "v"))
+	if err != nil || version < 1 {
+		return 0, ErrUnknownKeyVersion
+	}
+	return version, nil
+}
+
+// keyForVersion returns the key registered for a version
+func (s *EncryptionService) keyForVersion(version int) ([]byte, error) {
+	if version == s.version {
+		return s.key, nil
+	}
+	if key, ok := s.previousKeys[version]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: v%d", ErrUnknownKeyVersion, version)
 }
 
 // EncryptValue encrypts a plaintext string using AES-256-GCM and returns a base64-encoded ciphertext
+// prefixed with the current key version
 func (s *EncryptionService) EncryptValue(plaintext string) (string, error) {
 	block, err := aes.NewCipher(s.key)
 	if err != nil {
@@ -82,19 +215,32 @@ func (s *EncryptionService) EncryptValue(plaintext string) (string, error) {
 	// Encrypt the plaintext and prepend the nonce
 	ciphertext := aesGCM.Seal(nonce, nonce, []byte(plaintext), nil)
 
-	// Return as base64-encoded string
-	return base64.StdEncoding.EncodeToString(ciphertext), nil
+	// Return as base64-encoded string tagged with the key version
+	return fmt.Sprintf("v%d:%s", s.version, base64.StdEncoding.EncodeToString(ciphertext)), nil
 }
 
-// DecryptValue decrypts a base64-encoded ciphertext using AES-256-GCM and returns the plaintext
+// DecryptValue decrypts a ciphertext from EncryptValue using AES-256-GCM and returns the plaintext
+// The key is selected by the version prefix; values without a prefix use LegacyKeyVersion
 func (s *EncryptionService) DecryptValue(encodedCiphertext string) (string, error) {
+	version, err := CiphertextKeyVersion(encodedCiphertext)
+	if err != nil {
+		return "", err
+	}
+	key, err := s.keyForVersion(version)
+	if err != nil {
+		return "", err
+	}
+
 	// Decode from base64
+	if _, encoded, found := strings.Cut(encodedCiphertext, ":"); found {
+		encodedCiphertext = encoded
+	}
 	ciphertext, err := base64.StdEncoding.DecodeString(encodedCiphertext)
 	if err != nil {
 		return "", err
 	}
 
-	block, err := aes.NewCipher(s.key)
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return "", err
 	}
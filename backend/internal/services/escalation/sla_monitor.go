@@ -0,0 +1,308 @@
+package escalation
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+	"github.com/sidot/backend/internal/services/notification"
+)
+
+const (
+	// DefaultCheckInterval is the interval between checks for occurrences close to expiry
+	DefaultCheckInterval = time.Minute
+
+	// DefaultThreshold is the time remaining in the capture window below which a pending occurrence escalates
+	DefaultThreshold = 60 * time.Minute
+
+	// EscalationKeyPrefix is the prefix for Redis dedupe keys (one escalation per occurrence)
+	EscalationKeyPrefix = "occurrence:sla_escalation:"
+)
+
+// occurrenceSource lists pending occurrences close to expiry (implemented by repository.OccurrenceRepository)
+type occurrenceSource interface {
+	ListPendingNearExpiry(ctx context.Context, now, deadline time.Time) ([]models.Occurrence, error)
+}
+
+// gestorSource lists the gestores of a hospital (implemented by repository.UserRepository)
+type gestorSource interface {
+	ListByRoleAndHospital(ctx context.Context, role string, hospitalID uuid.UUID) ([]models.User, error)
+}
+
+// escalationMailer sends escalation emails (implemented by notification.EmailService)
+type escalationMailer interface {
+	IsConfigured() bool
+	SendEscalationAlert(ctx context.Context, to string, data *notification.EscalationAlertData) error
+}
+
+// eventPublisher publishes dashboard events (implemented by notification.SSEHub)
+type eventPublisher interface {
+	PublishEvent(ctx context.Context, event *models.SSEEvent) error
+}
+
+// SLAMonitor escalates PENDENTE occurrences whose capture window is about to expire,
+// emailing the hospital gestores and escalation contact and publishing an SSE escalation event, once per occurrence
+type SLAMonitor struct {
+	redis       *redis.Client
+	occurrences occurrenceSource
+	gestors     gestorSource
+	mailer      escalationMailer
+	publisher   eventPublisher
+
+	checkInterval time.Duration
+	threshold     time.Duration
+	dashboardURL  string
+
+	totalEscalations int64
+
+	// In-memory dedupe (occurrence ID -> window expiry), used when Redis is not configured
+	escalated   map[uuid.UUID]time.Time
+	escalatedMu sync.Mutex
+
+	running int32
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	logger *log.Logger
+}
+
+// NewSLAMonitor creates a new SLA escalation monitor
+func NewSLAMonitor(db *sql.DB, redisClient *redis.Client, emailService *notification.EmailService, sseHub *notification.SSEHub) *SLAMonitor {
+	m := &SLAMonitor{
+		redis:         redisClient,
+		occurrences:   repository.NewOccurrenceRepository(db),
+		gestors:       repository.NewUserRepository(db),
+		checkInterval: DefaultCheckInterval,
+		threshold:     DefaultThreshold,
+		escalated:     make(map[uuid.UUID]time.Time),
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+		logger:        log.Default(),
+	}
+	// Assigned only when set, so the interfaces do not hold typed nil pointers
+	if emailService != nil {
+		m.mailer = emailService
+	}
+	if sseHub != nil {
+		m.publisher = sseHub
+	}
+	return m
+}
+
+// SetCheckInterval sets the interval between checks
+func (m *SLAMonitor) SetCheckInterval(interval time.Duration) {
+	m.checkInterval = interval
+}
+
+// SetThreshold sets the time remaining below which a pending occurrence escalates
+func (m *SLAMonitor) SetThreshold(threshold time.Duration) {
+	m.threshold = threshold
+}
+
+// SetDashboardURL sets the base dashboard URL used in escalation emails
+func (m *SLAMonitor) SetDashboardURL(url string) {
+	m.dashboardURL = url
+}
+
+// Start begins the check loop
+func (m *SLAMonitor) Start(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&m.running, 0, 1) {
+		return nil // Already running
+	}
+
+	m.logger.Printf("[SLA] Starting SLA escalation monitor (threshold %s)", m.threshold)
+
+	go m.checkLoop(ctx)
+
+	return nil
+}
+
+// Stop stops the check loop
+func (m *SLAMonitor) Stop() {
+	if atomic.CompareAndSwapInt32(&m.running, 1, 0) {
+		close(m.stopCh)
+		<-m.doneCh
+		m.logger.Println("[SLA] SLA escalation monitor stopped")
+	}
+}
+
+// IsRunning returns true if the monitor is running
+func (m *SLAMonitor) IsRunning() bool {
+	return atomic.LoadInt32(&m.running) == 1
+}
+
+// GetStats returns the current statistics of the monitor
+func (m *SLAMonitor) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"running":           m.IsRunning(),
+		"threshold_minutes": m.threshold.Minutes(),
+		"total_escalations": atomic.LoadInt64(&m.totalEscalations),
+	}
+}
+
+// checkLoop is the main check loop
+func (m *SLAMonitor) checkLoop(ctx context.Context) {
+	defer close(m.doneCh)
+
+	ticker := time.NewTicker(m.checkInterval)
+	defer ticker.Stop()
+
+	// Initial check
+	m.CheckOccurrences(ctx, time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.CheckOccurrences(ctx, time.Now())
+		}
+	}
+}
+
+// CheckOccurrences escalates every unassigned pending occurrence whose window expires within the threshold,
+// skipping those already escalated. It returns the number of occurrences escalated.
+func (m *SLAMonitor) CheckOccurrences(ctx context.Context, now time.Time) int {
+	occurrences, err := m.occurrences.ListPendingNearExpiry(ctx, now, now.Add(m.threshold))
+	if err != nil {
+		m.logger.Printf("[SLA] Error listing occurrences near expiry: %v", err)
+		return 0
+	}
+
+	m.pruneEscalated(now)
+
+	escalated := 0
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		if !m.claimEscalation(ctx, occurrence, now) {
+			continue
+		}
+		m.escalate(ctx, occurrence)
+		escalated++
+	}
+
+	if escalated > 0 {
+		atomic.AddInt64(&m.totalEscalations, int64(escalated))
+	}
+	return escalated
+}
+
+// escalate publishes the SSE escalation event and emails the contacts of the occurrence hospital
+func (m *SLAMonitor) escalate(ctx context.Context, occurrence *models.Occurrence) {
+	hospitalNome := "Hospital Desconhecido"
+	if occurrence.Hospital != nil && occurrence.Hospital.Nome != "" {
+		hospitalNome = occurrence.Hospital.Nome
+	}
+
+	event := models.NewEscalationSSEEvent(occurrence, hospitalNome)
+	if m.publisher != nil {
+		if err := m.publisher.PublishEvent(ctx, &event); err != nil {
+			m.logger.Printf("[SLA] Error publishing escalation of occurrence %s: %v", occurrence.ID, err)
+		}
+	}
+
+	sent := m.emailGestors(ctx, occurrence, hospitalNome, event.Setor)
+
+	m.logger.Printf("[SLA] Occurrence %s escalated (%s remaining, %d contact(s) emailed)",
+		occurrence.ID, event.TempoRestante, sent)
+}
+
+// emailGestors emails the escalation to every gestor of the hospital that accepts email notifications
+// and to the escalation contact of the hospital, once per address
+func (m *SLAMonitor) emailGestors(ctx context.Context, occurrence *models.Occurrence, hospitalNome, setor string) int {
+	if m.mailer == nil || !m.mailer.IsConfigured() {
+		return 0
+	}
+
+	gestors, err := m.gestors.ListByRoleAndHospital(ctx, string(models.RoleGestor), occurrence.HospitalID)
+	if err != nil {
+		// The escalation contact of the hospital is still emailed
+		m.logger.Printf("[SLA] Error listing gestors for hospital %s: %v", occurrence.HospitalID, err)
+	}
+
+	sent := 0
+	emailed := make(map[string]bool)
+	send := func(to, locale string) {
+		key := strings.ToLower(strings.TrimSpace(to))
+		if key == "" || emailed[key] {
+			return
+		}
+		emailed[key] = true
+
+		data := &notification.EscalationAlertData{
+			OccurrenceID:   occurrence.ID.String(),
+			HospitalNome:   hospitalNome,
+			Setor:          setor,
+			TempoRestante:  occurrence.FormatTimeRemaining(),
+			JanelaExpiraEm: occurrence.JanelaExpiraEm,
+			Locale:         locale,
+		}
+		if m.dashboardURL != "" {
+			data.DashboardURL = fmt.Sprintf("%s/dashboard/occurrences?id=%s", m.dashboardURL, occurrence.ID)
+		}
+
+		if err := m.mailer.SendEscalationAlert(ctx, to, data); err != nil {
+			m.logger.Printf("[SLA] Error sending escalation to %s: %v", to, err)
+			return
+		}
+		sent++
+	}
+
+	for _, gestor := range gestors {
+		if !gestor.CanReceiveEmailNotifications() {
+			continue
+		}
+		send(gestor.Email, gestor.PreferredLocale())
+	}
+
+	if occurrence.Hospital != nil && occurrence.Hospital.EscalationEmail != nil {
+		send(*occurrence.Hospital.EscalationEmail, models.DefaultLocale)
+	}
+	return sent
+}
+
+// claimEscalation atomically marks an occurrence as escalated, returning false if it already was.
+// The mark lasts until the capture window expires, after which the occurrence no longer qualifies.
+func (m *SLAMonitor) claimEscalation(ctx context.Context, occurrence *models.Occurrence, now time.Time) bool {
+	ttl := occurrence.JanelaExpiraEm.Sub(now) + time.Hour
+
+	if m.redis != nil {
+		claimed, err := m.redis.SetNX(ctx, EscalationKeyPrefix+occurrence.ID.String(), now.Unix(), ttl).Result()
+		if err == nil {
+			return claimed
+		}
+		m.logger.Printf("[SLA] Error checking escalation in Redis, using local dedupe: %v", err)
+	}
+
+	m.escalatedMu.Lock()
+	defer m.escalatedMu.Unlock()
+
+	if _, exists := m.escalated[occurrence.ID]; exists {
+		return false
+	}
+	m.escalated[occurrence.ID] = now.Add(ttl)
+	return true
+}
+
+// pruneEscalated drops local dedupe entries of occurrences whose window already expired
+func (m *SLAMonitor) pruneEscalated(now time.Time) {
+	m.escalatedMu.Lock()
+	defer m.escalatedMu.Unlock()
+
+	for id, expiresAt := range m.escalated {
+		if now.After(expiresAt) {
+			delete(m.escalated, id)
+		}
+	}
+}
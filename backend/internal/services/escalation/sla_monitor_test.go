@@ -0,0 +1,209 @@
+package escalation
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services/notification"
+)
+
+// fakeOccurrences returns the stored occurrences whose window ends inside the requested range
+type fakeOccurrences []models.Occurrence
+
+func (f fakeOccurrences) ListPendingNearExpiry(ctx context.Context, now, deadline time.Time) ([]models.Occurrence, error) {
+	var result []models.Occurrence
+	for _, o := range f {
+		if o.Status == models.StatusPendente && o.AssignedUserID == nil && o.JanelaExpiraEm.After(now) && !o.JanelaExpiraEm.After(deadline) {
+			result = append(result, o)
+		}
+	}
+	return result, nil
+}
+
+type fakeGestors map[uuid.UUID][]models.User
+
+func (f fakeGestors) ListByRoleAndHospital(ctx context.Context, role string, hospitalID uuid.UUID) ([]models.User, error) {
+	return f[hospitalID], nil
+}
+
+type fakeMailer struct {
+	sent []string
+}
+
+func (f *fakeMailer) IsConfigured() bool { return true }
+
+func (f *fakeMailer) SendEscalationAlert(ctx context.Context, to string, data *notification.EscalationAlertData) error {
+	f.sent = append(f.sent, to)
+	return nil
+}
+
+type fakePublisher struct {
+	events []models.SSEEvent
+}
+
+func (f *fakePublisher) PublishEvent(ctx context.Context, event *models.SSEEvent) error {
+	f.events = append(f.events, *event)
+	return nil
+}
+
+func newTestMonitor(occurrences fakeOccurrences, gestors fakeGestors) (*SLAMonitor, *fakeMailer, *fakePublisher) {
+	mailer := &fakeMailer{}
+	publisher := &fakePublisher{}
+
+	monitor := NewSLAMonitor(nil, nil, nil, nil)
+	monitor.occurrences = occurrences
+	monitor.gestors = gestors
+	monitor.mailer = mailer
+	monitor.publisher = publisher
+	monitor.logger = log.New(io.Discard, "", 0)
+
+	return monitor, mailer, publisher
+}
+
+// TestCheckOccurrencesEscalatesOncePerOccurrence verifies that an occurrence near expiry is escalated on the
+// first check and not again on later checks
+func TestCheckOccurrencesEscalatesOncePerOccurrence(t *testing.T) {
+	now := time.Now()
+	hospitalID := uuid.New()
+	nearExpiry := models.Occurrence{
+		ID:             uuid.New(),
+		HospitalID:     hospitalID,
+		TenantID:       uuid.New(),
+		Status:         models.StatusPendente,
+		JanelaExpiraEm: now.Add(30 * time.Minute),
+		DadosCompletos: []byte(`{"setor": "UTI"}`),
+		Hospital:       &models.Hospital{ID: hospitalID, Nome: "Hospital Teste"},
+	}
+	gestors := fakeGestors{hospitalID: {
+		{Email: "gestor@hospital.test", Ativo: true, EmailNotifications: true},
+		{Email: "sem-email@hospital.test", Ativo: true, EmailNotifications: false},
+	}}
+
+	monitor, mailer, publisher := newTestMonitor(fakeOccurrences{nearExpiry}, gestors)
+	ctx := context.Background()
+
+	if n := monitor.CheckOccurrences(ctx, now); n != 1 {
+		t.Fatalf("Expected 1 escalation, got %d", n)
+	}
+	if len(publisher.events) != 1 {
+		t.Fatalf("Expected 1 SSE event, got %d", len(publisher.events))
+	}
+	event := publisher.events[0]
+	if event.Type != models.SSEEventTypeEscalation || event.OccurrenceID != nearExpiry.ID {
+		t.Errorf("Unexpected escalation event: %+v", event)
+	}
+	if event.HospitalNome != "Hospital Teste" || event.Setor != "UTI" {
+		t.Errorf("Expected hospital and setor in the event, got %q %q", event.HospitalNome, event.Setor)
+	}
+	if event.TenantID != nearExpiry.TenantID.String() {
+		t.Errorf("Expected the event of tenant %s, got %q", nearExpiry.TenantID, event.TenantID)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0] != "gestor@hospital.test" {
+		t.Errorf("Expected one email to the gestor accepting emails, got %v", mailer.sent)
+	}
+
+	// Later checks while the occurrence is still pending do not escalate again
+	for i := 1; i <= 3; i++ {
+		if n := monitor.CheckOccurrences(ctx, now.Add(time.Duration(i)*time.Minute)); n != 0 {
+			t.Errorf("Expected no repeated escalation on check %d, got %d", i, n)
+		}
+	}
+	if len(publisher.events) != 1 || len(mailer.sent) != 1 {
+		t.Errorf("Expected a single escalation, got %d events and %d emails", len(publisher.events), len(mailer.sent))
+	}
+}
+
+// TestCheckOccurrencesRespectsThreshold verifies that only occurrences within the threshold escalate
+func TestCheckOccurrencesRespectsThreshold(t *testing.T) {
+	now := time.Now()
+	farFromExpiry := models.Occurrence{
+		ID:             uuid.New(),
+		HospitalID:     uuid.New(),
+		Status:         models.StatusPendente,
+		JanelaExpiraEm: now.Add(3 * time.Hour),
+	}
+
+	monitor, _, publisher := newTestMonitor(fakeOccurrences{farFromExpiry}, fakeGestors{})
+	ctx := context.Background()
+
+	if n := monitor.CheckOccurrences(ctx, now); n != 0 {
+		t.Fatalf("Expected no escalation with the default threshold, got %d", n)
+	}
+
+	monitor.SetThreshold(4 * time.Hour)
+	if n := monitor.CheckOccurrences(ctx, now); n != 1 {
+		t.Fatalf("Expected the occurrence to escalate with a wider threshold, got %d", n)
+	}
+	if len(publisher.events) != 1 {
+		t.Errorf("Expected 1 SSE event, got %d", len(publisher.events))
+	}
+}
+
+// TestCheckOccurrencesEscalatesEachOccurrence verifies that dedupe is per occurrence
+func TestCheckOccurrencesEscalatesEachOccurrence(t *testing.T) {
+	now := time.Now()
+	first := models.Occurrence{ID: uuid.New(), Status: models.StatusPendente, JanelaExpiraEm: now.Add(50 * time.Minute)}
+	occurrences := fakeOccurrences{first}
+
+	monitor, _, publisher := newTestMonitor(occurrences, fakeGestors{})
+	ctx := context.Background()
+
+	monitor.CheckOccurrences(ctx, now)
+
+	second := models.Occurrence{ID: uuid.New(), Status: models.StatusPendente, JanelaExpiraEm: now.Add(55 * time.Minute)}
+	monitor.occurrences = fakeOccurrences{first, second}
+
+	if n := monitor.CheckOccurrences(ctx, now.Add(time.Minute)); n != 1 {
+		t.Fatalf("Expected only the new occurrence to escalate, got %d", n)
+	}
+	if len(publisher.events) != 2 || publisher.events[1].OccurrenceID != second.ID {
+		t.Errorf("Expected escalations for both occurrences, got %+v", publisher.events)
+	}
+}
+
+// TestCheckOccurrencesEmailsHospitalEscalationContact verifies that the escalation contact of the hospital
+// is emailed along with its gestores, once even when it is also a gestor
+func TestCheckOccurrencesEmailsHospitalEscalationContact(t *testing.T) {
+	now := time.Now()
+	hospitalID := uuid.New()
+	escalationEmail := "plantao@hospital.test"
+	occurrence := models.Occurrence{
+		ID:             uuid.New(),
+		HospitalID:     hospitalID,
+		Status:         models.StatusPendente,
+		JanelaExpiraEm: now.Add(30 * time.Minute),
+		Hospital:       &models.Hospital{ID: hospitalID, Nome: "Hospital Teste", EscalationEmail: &escalationEmail},
+	}
+	gestors := fakeGestors{hospitalID: {
+		{Email: "gestor@hospital.test", Ativo: true, EmailNotifications: true},
+		{Email: "Plantao@hospital.test", Ativo: true, EmailNotifications: true},
+	}}
+
+	monitor, mailer, _ := newTestMonitor(fakeOccurrences{occurrence}, gestors)
+
+	if n := monitor.CheckOccurrences(context.Background(), now); n != 1 {
+		t.Fatalf("Expected 1 escalation, got %d", n)
+	}
+	if len(mailer.sent) != 2 || mailer.sent[0] != "gestor@hospital.test" || mailer.sent[1] != "Plantao@hospital.test" {
+		t.Errorf("Expected one email per address, got %v", mailer.sent)
+	}
+
+	// Hospitals without gestores are still reached through their escalation contact
+	other := occurrence
+	other.ID = uuid.New()
+	other.HospitalID = uuid.New()
+	monitor.occurrences = fakeOccurrences{other}
+	mailer.sent = nil
+
+	if n := monitor.CheckOccurrences(context.Background(), now); n != 1 {
+		t.Fatalf("Expected 1 escalation, got %d", n)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0] != escalationEmail {
+		t.Errorf("Expected the escalation contact to be emailed, got %v", mailer.sent)
+	}
+}
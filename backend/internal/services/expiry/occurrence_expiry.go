@@ -0,0 +1,167 @@
+package expiry
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// DefaultCheckInterval is the interval between expiry runs
+const DefaultCheckInterval = time.Minute
+
+// occurrenceExpirer expires overdue pending occurrences (implemented by repository.OccurrenceRepository)
+type occurrenceExpirer interface {
+	ExpireOverdue(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+}
+
+// historyWriter records occurrence history entries (implemented by repository.OccurrenceHistoryRepository)
+type historyWriter interface {
+	Create(ctx context.Context, input *models.CreateHistoryInput) (*models.OccurrenceHistory, error)
+}
+
+// metricsInvalidator drops cached dashboard metrics (implemented by metricscache.Cache)
+type metricsInvalidator interface {
+	Invalidate(ctx context.Context)
+}
+
+// OccurrenceExpiryJob moves PENDENTE occurrences whose capture window ended without being acted upon
+// to the terminal EXPIRADA status, recording a system history entry for each one
+type OccurrenceExpiryJob struct {
+	occurrences occurrenceExpirer
+	history     historyWriter
+	metrics     metricsInvalidator
+
+	checkInterval time.Duration
+
+	totalExpired int64
+
+	running int32
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	logger *log.Logger
+}
+
+// NewOccurrenceExpiryJob creates a new occurrence expiry job
+func NewOccurrenceExpiryJob(db *sql.DB) *OccurrenceExpiryJob {
+	return &OccurrenceExpiryJob{
+		occurrences:   repository.NewOccurrenceRepository(db),
+		history:       repository.NewOccurrenceHistoryRepository(db),
+		checkInterval: DefaultCheckInterval,
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+		logger:        log.Default(),
+	}
+}
+
+// SetCheckInterval sets the interval between expiry runs
+func (j *OccurrenceExpiryJob) SetCheckInterval(interval time.Duration) {
+	j.checkInterval = interval
+}
+
+// SetMetricsCache sets the cache of dashboard metrics invalidated when occurrences expire
+func (j *OccurrenceExpiryJob) SetMetricsCache(cache metricsInvalidator) {
+	j.metrics = cache
+}
+
+// Start begins the expiry loop
+func (j *OccurrenceExpiryJob) Start(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&j.running, 0, 1) {
+		return nil // Already running
+	}
+
+	j.logger.Printf("[Expiry] Starting occurrence expiry job (interval %s)", j.checkInterval)
+
+	go j.expiryLoop(ctx)
+
+	return nil
+}
+
+// Stop stops the expiry loop
+func (j *OccurrenceExpiryJob) Stop() {
+	if atomic.CompareAndSwapInt32(&j.running, 1, 0) {
+		close(j.stopCh)
+		<-j.doneCh
+		j.logger.Println("[Expiry] Occurrence expiry job stopped")
+	}
+}
+
+// IsRunning returns true if the job is running
+func (j *OccurrenceExpiryJob) IsRunning() bool {
+	return atomic.LoadInt32(&j.running) == 1
+}
+
+// GetStats returns the current statistics of the job
+func (j *OccurrenceExpiryJob) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"running":       j.IsRunning(),
+		"total_expired": atomic.LoadInt64(&j.totalExpired),
+	}
+}
+
+// expiryLoop is the main expiry loop
+func (j *OccurrenceExpiryJob) expiryLoop(ctx context.Context) {
+	defer close(j.doneCh)
+
+	ticker := time.NewTicker(j.checkInterval)
+	defer ticker.Stop()
+
+	// Initial run
+	j.ExpireOccurrences(ctx, time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-j.stopCh:
+			return
+		case <-ticker.C:
+			j.ExpireOccurrences(ctx, time.Now())
+		}
+	}
+}
+
+// ExpireOccurrences expires every pending occurrence whose window ended at or before now and
+// records the transition in its history. It returns the number of occurrences expired.
+func (j *OccurrenceExpiryJob) ExpireOccurrences(ctx context.Context, now time.Time) int {
+	ids, err := j.occurrences.ExpireOverdue(ctx, now)
+	if err != nil {
+		j.logger.Printf("[Expiry] Error expiring occurrences: %v", err)
+		return 0
+	}
+
+	statusAnterior := models.StatusPendente
+	statusNovo := models.StatusExpirada
+	observacoes := fmt.Sprintf("Janela de captacao expirada sem atendimento (verificado em %s)", now.Format(time.RFC3339))
+
+	for _, id := range ids {
+		// UserID is nil: the transition is made by the system
+		_, err := j.history.Create(ctx, &models.CreateHistoryInput{
+			OccurrenceID:   id,
+			Acao:           models.ActionOccurrenceExpired,
+			StatusAnterior: &statusAnterior,
+			StatusNovo:     &statusNovo,
+			Observacoes:    &observacoes,
+		})
+		if err != nil {
+			j.logger.Printf("[Expiry] Error recording history of occurrence %s: %v", id, err)
+		}
+	}
+
+	if len(ids) > 0 {
+		// Cached dashboard metrics count occurrences by status
+		if j.metrics != nil {
+			j.metrics.Invalidate(ctx)
+		}
+		atomic.AddInt64(&j.totalExpired, int64(len(ids)))
+		j.logger.Printf("[Expiry] %d occurrence(s) expired", len(ids))
+	}
+	return len(ids)
+}
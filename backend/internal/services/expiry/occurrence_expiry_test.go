@@ -0,0 +1,148 @@
+package expiry
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// fakeStore keeps occurrences and history in memory, expiring like the repository UPDATE does
+type fakeStore struct {
+	occurrences map[uuid.UUID]*models.Occurrence
+	history     []models.CreateHistoryInput
+}
+
+func (f *fakeStore) ExpireOverdue(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for id, o := range f.occurrences {
+		if o.Status == models.StatusPendente && !o.JanelaExpiraEm.After(now) {
+			o.Status = models.StatusExpirada
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeStore) Create(ctx context.Context, input *models.CreateHistoryInput) (*models.OccurrenceHistory, error) {
+	f.history = append(f.history, *input)
+	return &models.OccurrenceHistory{ID: uuid.New(), OccurrenceID: input.OccurrenceID, Acao: input.Acao}, nil
+}
+
+func newTestJob(occurrences ...*models.Occurrence) (*OccurrenceExpiryJob, *fakeStore) {
+	store := &fakeStore{occurrences: make(map[uuid.UUID]*models.Occurrence)}
+	for _, o := range occurrences {
+		store.occurrences[o.ID] = o
+	}
+
+	job := NewOccurrenceExpiryJob(nil)
+	job.occurrences = store
+	job.history = store
+	job.logger = log.New(io.Discard, "", 0)
+
+	return job, store
+}
+
+// TestExpireOccurrencesExpiresPendingPastWindow verifies that a pending occurrence past its window
+// becomes EXPIRADA with a system history entry
+func TestExpireOccurrencesExpiresPendingPastWindow(t *testing.T) {
+	now := time.Now()
+	overdue := &models.Occurrence{ID: uuid.New(), Status: models.StatusPendente, JanelaExpiraEm: now.Add(-time.Minute)}
+	inWindow := &models.Occurrence{ID: uuid.New(), Status: models.StatusPendente, JanelaExpiraEm: now.Add(time.Hour)}
+
+	job, store := newTestJob(overdue, inWindow)
+
+	if n := job.ExpireOccurrences(context.Background(), now); n != 1 {
+		t.Fatalf("Expected 1 expired occurrence, got %d", n)
+	}
+	if overdue.Status != models.StatusExpirada {
+		t.Errorf("Expected overdue occurrence to be EXPIRADA, got %s", overdue.Status)
+	}
+	if inWindow.Status != models.StatusPendente {
+		t.Errorf("Expected occurrence inside its window to stay PENDENTE, got %s", inWindow.Status)
+	}
+
+	if len(store.history) != 1 {
+		t.Fatalf("Expected 1 history entry, got %d", len(store.history))
+	}
+	entry := store.history[0]
+	if entry.OccurrenceID != overdue.ID || entry.Acao != models.ActionOccurrenceExpired {
+		t.Errorf("Unexpected history entry: %+v", entry)
+	}
+	if entry.UserID != nil {
+		t.Error("Expected a system history entry without user")
+	}
+	if entry.StatusAnterior == nil || *entry.StatusAnterior != models.StatusPendente ||
+		entry.StatusNovo == nil || *entry.StatusNovo != models.StatusExpirada {
+		t.Errorf("Expected PENDENTE -> EXPIRADA in history, got %v -> %v", entry.StatusAnterior, entry.StatusNovo)
+	}
+
+	// A later run does not expire the same occurrence again
+	if n := job.ExpireOccurrences(context.Background(), now.Add(time.Minute)); n != 0 {
+		t.Errorf("Expected no further expirations, got %d", n)
+	}
+}
+
+// TestExpireOccurrencesSkipsAcceptedOccurrence verifies that an occurrence acted upon is not expired
+func TestExpireOccurrencesSkipsAcceptedOccurrence(t *testing.T) {
+	now := time.Now()
+	accepted := &models.Occurrence{ID: uuid.New(), Status: models.StatusAceita, JanelaExpiraEm: now.Add(-time.Hour)}
+
+	job, store := newTestJob(accepted)
+
+	if n := job.ExpireOccurrences(context.Background(), now); n != 0 {
+		t.Fatalf("Expected no expired occurrence, got %d", n)
+	}
+	if accepted.Status != models.StatusAceita {
+		t.Errorf("Expected accepted occurrence to stay ACEITA, got %s", accepted.Status)
+	}
+	if len(store.history) != 0 {
+		t.Errorf("Expected no history entry, got %d", len(store.history))
+	}
+}
+
+// TestExpiradaIsTerminal verifies that EXPIRADA admits no further transitions
+func TestExpiradaIsTerminal(t *testing.T) {
+	if !models.StatusExpirada.IsTerminal() {
+		t.Error("Expected EXPIRADA to be terminal")
+	}
+	for _, target := range models.ValidStatuses {
+		if models.StatusExpirada.CanTransitionTo(target) {
+			t.Errorf("Expected no transition from EXPIRADA to %s", target)
+		}
+	}
+}
+
+// countingInvalidator counts cache invalidations
+type countingInvalidator struct {
+	calls int
+}
+
+func (c *countingInvalidator) Invalidate(ctx context.Context) {
+	c.calls++
+}
+
+// TestExpireOccurrencesInvalidatesMetricsCache verifies that the dashboard cache is dropped only when
+// a run expires occurrences
+func TestExpireOccurrencesInvalidatesMetricsCache(t *testing.T) {
+	now := time.Now()
+	overdue := &models.Occurrence{ID: uuid.New(), Status: models.StatusPendente, JanelaExpiraEm: now.Add(-time.Minute)}
+
+	job, _ := newTestJob(overdue)
+	cache := &countingInvalidator{}
+	job.SetMetricsCache(cache)
+
+	job.ExpireOccurrences(context.Background(), now)
+	if cache.calls != 1 {
+		t.Errorf("Expected 1 invalidation after expiring an occurrence, got %d", cache.calls)
+	}
+
+	job.ExpireOccurrences(context.Background(), now)
+	if cache.calls != 1 {
+		t.Errorf("Expected no invalidation when nothing expired, got %d", cache.calls)
+	}
+}
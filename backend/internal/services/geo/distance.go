@@ -0,0 +1,53 @@
+// Package geo provides geographic helpers for the map, such as straight-line distances
+// between coordinates and travel time estimates.
+package geo
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// EarthRadiusKm is the mean radius of the Earth used by the haversine formula
+	EarthRadiusKm = 6371.0
+
+	// DefaultAverageSpeedKmh is the average ground transport speed used to estimate travel times
+	// from straight-line distances, until a routing service is integrated
+	DefaultAverageSpeedKmh = 60.0
+)
+
+// Point is a coordinate in decimal degrees
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// IsValid checks if the point is within the latitude and longitude ranges
+func (p Point) IsValid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+// DistanceKm returns the straight-line (great-circle) distance between two points in kilometers,
+// computed with the haversine formula
+func DistanceKm(a, b Point) float64 {
+	lat1 := toRadians(a.Lat)
+	lat2 := toRadians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := toRadians(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// EstimateTravelTime estimates the time to travel a distance at an average speed in km/h
+func EstimateTravelTime(distanceKm, speedKmh float64) time.Duration {
+	if speedKmh <= 0 {
+		speedKmh = DefaultAverageSpeedKmh
+	}
+	return time.Duration(distanceKm / speedKmh * float64(time.Hour))
+}
+
+// toRadians converts decimal degrees to radians
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,61 @@
+package geo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestDistanceKmKnownPairs tests the haversine distance of known coordinate pairs
+func TestDistanceKmKnownPairs(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      Point
+		expected  float64
+		tolerance float64
+	}{
+		{"same point", Point{-16.6868, -49.2648}, Point{-16.6868, -49.2648}, 0, 1e-9},
+		{"Goiania to Brasilia", Point{-16.6869, -49.2648}, Point{-15.7939, -47.8828}, 178, 2},
+		{"Sao Paulo to Rio de Janeiro", Point{-23.5505, -46.6333}, Point{-22.9068, -43.1729}, 361, 3},
+		{"one degree of longitude at the equator", Point{0, 0}, Point{0, 1}, 111.19, 0.01},
+		{"antipodes", Point{0, 0}, Point{0, 180}, math.Pi * EarthRadiusKm, 0.01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistanceKm(tt.a, tt.b)
+			if math.Abs(got-tt.expected) > tt.tolerance {
+				t.Errorf("Expected %.2f km (±%.2f), got %.2f km", tt.expected, tt.tolerance, got)
+			}
+			if reverse := DistanceKm(tt.b, tt.a); math.Abs(reverse-got) > 1e-9 {
+				t.Errorf("Expected symmetric distance, got %.6f and %.6f", got, reverse)
+			}
+		})
+	}
+}
+
+// TestEstimateTravelTime tests the travel time estimate from the average speed
+func TestEstimateTravelTime(t *testing.T) {
+	if got := EstimateTravelTime(90, 60); got != 90*time.Minute {
+		t.Errorf("Expected 90m, got %s", got)
+	}
+	if got := EstimateTravelTime(30, 0); got != 30*time.Minute {
+		t.Errorf("Expected the default speed for a non-positive speed, got %s", got)
+	}
+}
+
+// TestPointIsValid tests the coordinate ranges
+func TestPointIsValid(t *testing.T) {
+	valid := []Point{{0, 0}, {-90, -180}, {90, 180}, {-16.6868, -49.2648}}
+	for _, p := range valid {
+		if !p.IsValid() {
+			t.Errorf("Expected %+v to be valid", p)
+		}
+	}
+	invalid := []Point{{-90.1, 0}, {90.1, 0}, {0, -180.1}, {0, 180.1}}
+	for _, p := range invalid {
+		if p.IsValid() {
+			t.Errorf("Expected %+v to be invalid", p)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package geocoding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultTimeout for geocoding API requests
+	DefaultTimeout = 5 * time.Second
+
+	// CacheTTL is how long resolved coordinates are cached
+	CacheTTL = 30 * 24 * time.Hour
+
+	// NotFoundCacheTTL is how long an unresolvable address is remembered
+	NotFoundCacheTTL = 24 * time.Hour
+
+	// CacheKeyPrefix is the prefix for Redis keys
+	CacheKeyPrefix = "geocode:"
+
+	// notFoundMarker is cached for addresses the API could not resolve
+	notFoundMarker = "not_found"
+)
+
+var (
+	ErrAddressNotFound = errors.New("address not found")
+	ErrGeocodingFailed = errors.New("geocoding request failed")
+)
+
+// Coordinates represents a resolved latitude/longitude pair
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Geocoder resolves a postal address into coordinates
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (*Coordinates, error)
+}
+
+// HTTPGeocoderConfig holds configuration for the HTTP geocoder
+type HTTPGeocoderConfig struct {
+	BaseURL   string
+	APIKey    string
+	UserAgent string
+	Timeout   time.Duration
+}
+
+// HTTPGeocoder queries a Nominatim-compatible search API
+// (GET <BaseURL>?q=<address>&format=json&limit=1, returning [{"lat": "...", "lon": "..."}])
+type HTTPGeocoder struct {
+	config     *HTTPGeocoderConfig
+	httpClient *http.Client
+}
+
+// NewHTTPGeocoder creates a new HTTP geocoder
+func NewHTTPGeocoder(config *HTTPGeocoderConfig) *HTTPGeocoder {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &HTTPGeocoder{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// nominatimResult represents a single search result
+type nominatimResult struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+// Geocode resolves an address using the configured API
+func (g *HTTPGeocoder) Geocode(ctx context.Context, address string) (*Coordinates, error) {
+	params := url.Values{}
+	params.Set("q", address)
+	params.Set("format", "json")
+	params.Set("limit", "1")
+	if g.config.APIKey != "" {
+		params.Set("key", g.config.APIKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.config.BaseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGeocodingFailed, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if g.config.UserAgent != "" {
+		req.Header.Set("User-Agent", g.config.UserAgent)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGeocodingFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrGeocodingFailed, resp.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGeocodingFailed, err)
+	}
+
+	if len(results) == 0 {
+		return nil, ErrAddressNotFound
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid latitude %q", ErrGeocodingFailed, results[0].Lat)
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid longitude %q", ErrGeocodingFailed, results[0].Lon)
+	}
+
+	return &Coordinates{Latitude: lat, Longitude: lng}, nil
+}
+
+// CachedGeocoder wraps a Geocoder with a Redis cache keyed by the normalized address
+// Addresses that cannot be resolved are cached for a shorter period to avoid repeated lookups
+type CachedGeocoder struct {
+	geocoder Geocoder
+	redis    *redis.Client
+}
+
+// NewCachedGeocoder creates a new cached geocoder
+func NewCachedGeocoder(geocoder Geocoder, redisClient *redis.Client) *CachedGeocoder {
+	return &CachedGeocoder{
+		geocoder: geocoder,
+		redis:    redisClient,
+	}
+}
+
+// Geocode resolves an address, consulting the cache first
+func (g *CachedGeocoder) Geocode(ctx context.Context, address string) (*Coordinates, error) {
+	if g.redis == nil {
+		return g.geocoder.Geocode(ctx, address)
+	}
+
+	key := cacheKey(address)
+	data, err := g.redis.Get(ctx, key).Result()
+	if err == nil {
+		if data == notFoundMarker {
+			return nil, ErrAddressNotFound
+		}
+		var coords Coordinates
+		if err := json.Unmarshal([]byte(data), &coords); err == nil {
+			return &coords, nil
+		}
+	}
+
+	coords, err := g.geocoder.Geocode(ctx, address)
+	if err != nil {
+		if errors.Is(err, ErrAddressNotFound) {
+			g.redis.Set(ctx, key, notFoundMarker, NotFoundCacheTTL)
+		}
+		return nil, err
+	}
+
+	if encoded, err := json.Marshal(coords); err == nil {
+		g.redis.Set(ctx, key, encoded, CacheTTL)
+	}
+
+	return coords, nil
+}
+
+// cacheKey generates the Redis cache key for an address
+func cacheKey(address string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(address), " "))
+	sum := sha256.Sum256([]byte(normalized))
+	return CacheKeyPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,150 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// HospitalCoordinateStore reads hospitals missing coordinates and persists resolved ones
+// (implemented by repository.HospitalRepository)
+type HospitalCoordinateStore interface {
+	GetActiveHospitalsMissingCoordinates(ctx context.Context) ([]models.Hospital, error)
+	UpdateCoordinates(ctx context.Context, id uuid.UUID, latitude, longitude float64) error
+}
+
+const (
+	// DefaultResolveInterval is the interval between geocoding runs
+	DefaultResolveInterval = 10 * time.Minute
+
+	// DefaultMaxLookupsPerRun caps the geocoding API calls of a single run
+	DefaultMaxLookupsPerRun = 20
+)
+
+// CoordinateResolver fills in missing hospital coordinates from their address.
+// It runs as a background job so map requests never wait on the geocoding API.
+type CoordinateResolver struct {
+	store    HospitalCoordinateStore
+	geocoder Geocoder
+
+	checkInterval time.Duration
+	maxLookups    int
+
+	running int32
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	logger *log.Logger
+}
+
+// NewCoordinateResolver creates a new coordinate resolver
+func NewCoordinateResolver(store HospitalCoordinateStore, geocoder Geocoder) *CoordinateResolver {
+	return &CoordinateResolver{
+		store:         store,
+		geocoder:      geocoder,
+		checkInterval: DefaultResolveInterval,
+		maxLookups:    DefaultMaxLookupsPerRun,
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+		logger:        log.Default(),
+	}
+}
+
+// SetCheckInterval sets the interval between geocoding runs
+func (r *CoordinateResolver) SetCheckInterval(interval time.Duration) {
+	r.checkInterval = interval
+}
+
+// SetMaxLookupsPerRun sets how many addresses a single run may geocode
+func (r *CoordinateResolver) SetMaxLookupsPerRun(max int) {
+	r.maxLookups = max
+}
+
+// Start begins the geocoding loop
+func (r *CoordinateResolver) Start(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&r.running, 0, 1) {
+		return nil // Already running
+	}
+
+	r.logger.Printf("[Geocoding] Starting hospital coordinate resolver (interval %s)", r.checkInterval)
+
+	go r.resolveLoop(ctx)
+
+	return nil
+}
+
+// Stop stops the geocoding loop
+func (r *CoordinateResolver) Stop() {
+	if atomic.CompareAndSwapInt32(&r.running, 1, 0) {
+		close(r.stopCh)
+		<-r.doneCh
+		r.logger.Println("[Geocoding] Hospital coordinate resolver stopped")
+	}
+}
+
+// resolveLoop is the main geocoding loop
+func (r *CoordinateResolver) resolveLoop(ctx context.Context) {
+	defer close(r.doneCh)
+
+	ticker := time.NewTicker(r.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		// First run on start, then on every tick
+		if _, err := r.ResolveMissing(ctx); err != nil {
+			r.logger.Printf("[Geocoding] Failed to resolve missing hospital coordinates: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ResolveMissing geocodes active hospitals that have an address but no coordinates
+// and stores the result, returning how many hospitals were updated
+// At most maxLookups addresses are geocoded per run; the rest wait for the next run.
+// Failures for individual hospitals are logged and skipped
+func (r *CoordinateResolver) ResolveMissing(ctx context.Context) (int, error) {
+	hospitals, err := r.store.GetActiveHospitalsMissingCoordinates(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	updated, lookups := 0, 0
+	for _, hospital := range hospitals {
+		if hospital.Endereco == nil || *hospital.Endereco == "" {
+			continue
+		}
+		if r.maxLookups > 0 && lookups >= r.maxLookups {
+			break
+		}
+		lookups++
+
+		coords, err := r.geocoder.Geocode(ctx, *hospital.Endereco)
+		if err != nil {
+			if !errors.Is(err, ErrAddressNotFound) {
+				log.Printf("[Geocoding] Failed to geocode hospital %s: %v", hospital.ID, err)
+			}
+			continue
+		}
+
+		if err := r.store.UpdateCoordinates(ctx, hospital.ID, coords.Latitude, coords.Longitude); err != nil {
+			log.Printf("[Geocoding] Failed to store coordinates for hospital %s: %v", hospital.ID, err)
+			continue
+		}
+
+		updated++
+	}
+
+	return updated, nil
+}
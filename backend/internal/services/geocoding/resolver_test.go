@@ -0,0 +1,191 @@
+package geocoding
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// mockHospitalStore keeps hospitals in memory, mirroring the repository queries used by the map
+type mockHospitalStore struct {
+	hospitals []models.Hospital
+}
+
+func (s *mockHospitalStore) GetActiveHospitalsMissingCoordinates(ctx context.Context) ([]models.Hospital, error) {
+	var result []models.Hospital
+	for _, h := range s.hospitals {
+		if h.Ativo && (h.Latitude == nil || h.Longitude == nil) && h.Endereco != nil && *h.Endereco != "" {
+			result = append(result, h)
+		}
+	}
+	return result, nil
+}
+
+func (s *mockHospitalStore) UpdateCoordinates(ctx context.Context, id uuid.UUID, latitude, longitude float64) error {
+	for i := range s.hospitals {
+		if s.hospitals[i].ID == id {
+			lat, lng := latitude, longitude
+			s.hospitals[i].Latitude = &lat
+			s.hospitals[i].Longitude = &lng
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *mockHospitalStore) GetActiveHospitalsWithCoordinates(ctx context.Context) ([]models.Hospital, error) {
+	var result []models.Hospital
+	for _, h := range s.hospitals {
+		if h.Ativo && h.Latitude != nil && h.Longitude != nil {
+			result = append(result, h)
+		}
+	}
+	return result, nil
+}
+
+// mockGeocoder resolves addresses from a fixed table and counts lookups
+type mockGeocoder struct {
+	results map[string]Coordinates
+	calls   int
+}
+
+func (g *mockGeocoder) Geocode(ctx context.Context, address string) (*Coordinates, error) {
+	g.calls++
+	coords, ok := g.results[address]
+	if !ok {
+		return nil, ErrAddressNotFound
+	}
+	return &coords, nil
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// TestResolveMissingStoresCoordinates tests that geocoded coordinates are stored and the hospital appears on the map
+func TestResolveMissingStoresCoordinates(t *testing.T) {
+	lat, lng := -16.6868, -49.2648
+	withCoords := models.Hospital{ID: uuid.New(), Nome: "HGG", Ativo: true, Latitude: &lat, Longitude: &lng}
+	withAddress := models.Hospital{ID: uuid.New(), Nome: "HUGO", Ativo: true, Endereco: strPtr("Av. 31 de Marco, Goiania - GO")}
+	unknownAddress := models.Hospital{ID: uuid.New(), Nome: "Hospital Sem Geo", Ativo: true, Endereco: strPtr("Endereco inexistente")}
+	noAddress := models.Hospital{ID: uuid.New(), Nome: "Hospital Sem Endereco", Ativo: true}
+
+	store := &mockHospitalStore{hospitals: []models.Hospital{withCoords, withAddress, unknownAddress, noAddress}}
+	geocoder := &mockGeocoder{results: map[string]Coordinates{
+		"Av. 31 de Marco, Goiania - GO": {Latitude: -16.7200, Longitude: -49.3000},
+	}}
+
+	before, _ := store.GetActiveHospitalsWithCoordinates(context.Background())
+	if len(before) != 1 {
+		t.Fatalf("Expected 1 hospital on the map before geocoding, got %d", len(before))
+	}
+
+	resolver := NewCoordinateResolver(store, geocoder)
+	updated, err := resolver.ResolveMissing(context.Background())
+	if err != nil {
+		t.Fatalf("ResolveMissing returned error: %v", err)
+	}
+	if updated != 1 {
+		t.Errorf("Expected 1 hospital updated, got %d", updated)
+	}
+	if geocoder.calls != 2 {
+		t.Errorf("Expected 2 geocoding lookups (hospitals with address only), got %d", geocoder.calls)
+	}
+
+	after, _ := store.GetActiveHospitalsWithCoordinates(context.Background())
+	if len(after) != 2 {
+		t.Fatalf("Expected 2 hospitals on the map after geocoding, got %d", len(after))
+	}
+
+	var found *models.Hospital
+	for i := range after {
+		if after[i].ID == withAddress.ID {
+			found = &after[i]
+		}
+	}
+	if found == nil {
+		t.Fatal("Expected geocoded hospital to appear on the map")
+	}
+	if *found.Latitude != -16.7200 || *found.Longitude != -49.3000 {
+		t.Errorf("Unexpected stored coordinates: %f, %f", *found.Latitude, *found.Longitude)
+	}
+
+	// A second pass only retries the unresolvable address
+	geocoder.calls = 0
+	if updated, _ := resolver.ResolveMissing(context.Background()); updated != 0 {
+		t.Errorf("Expected no updates on second pass, got %d", updated)
+	}
+	if geocoder.calls != 1 {
+		t.Errorf("Expected 1 lookup on second pass, got %d", geocoder.calls)
+	}
+}
+
+// TestHTTPGeocoderParsesResponse tests the default geocoder against a Nominatim-style API
+func TestHTTPGeocoderParsesResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") == "" || r.URL.Query().Get("format") != "json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("q") == "nowhere" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"lat": "-16.6868", "lon": "-49.2648"}]`))
+	}))
+	defer server.Close()
+
+	geocoder := NewHTTPGeocoder(&HTTPGeocoderConfig{BaseURL: server.URL})
+
+	coords, err := geocoder.Geocode(context.Background(), "Goiania")
+	if err != nil {
+		t.Fatalf("Geocode returned error: %v", err)
+	}
+	if coords.Latitude != -16.6868 || coords.Longitude != -49.2648 {
+		t.Errorf("Unexpected coordinates: %f, %f", coords.Latitude, coords.Longitude)
+	}
+
+	if _, err := geocoder.Geocode(context.Background(), "nowhere"); err != ErrAddressNotFound {
+		t.Errorf("Expected ErrAddressNotFound, got %v", err)
+	}
+}
+
+// TestCacheKeyNormalizesAddress tests that equivalent addresses share a cache entry
+func TestCacheKeyNormalizesAddress(t *testing.T) {
+	if cacheKey("Av. Brasil,  100") != cacheKey("av. brasil, 100") {
+		t.Error("Expected normalized addresses to share the same cache key")
+	}
+	if cacheKey("Av. Brasil, 100") == cacheKey("Av. Brasil, 200") {
+		t.Error("Expected different addresses to have different cache keys")
+	}
+}
+
+// TestResolveMissingCapsLookupsPerRun tests that a run geocodes at most the configured number of addresses
+func TestResolveMissingCapsLookupsPerRun(t *testing.T) {
+	store := &mockHospitalStore{}
+	results := map[string]Coordinates{}
+	for _, address := range []string{"Rua 1, Goiania - GO", "Rua 2, Goiania - GO", "Rua 3, Goiania - GO"} {
+		store.hospitals = append(store.hospitals, models.Hospital{ID: uuid.New(), Nome: address, Ativo: true, Endereco: strPtr(address)})
+		results[address] = Coordinates{Latitude: -16.7, Longitude: -49.3}
+	}
+	geocoder := &mockGeocoder{results: results}
+
+	resolver := NewCoordinateResolver(store, geocoder)
+	resolver.SetMaxLookupsPerRun(2)
+
+	if updated, err := resolver.ResolveMissing(context.Background()); err != nil || updated != 2 {
+		t.Fatalf("Expected 2 hospitals updated on the first run, got %d (err %v)", updated, err)
+	}
+	if geocoder.calls != 2 {
+		t.Errorf("Expected 2 lookups on the first run, got %d", geocoder.calls)
+	}
+
+	// The next run picks up the remaining hospital
+	if updated, _ := resolver.ResolveMissing(context.Background()); updated != 1 {
+		t.Errorf("Expected 1 hospital updated on the second run, got %d", updated)
+	}
+}
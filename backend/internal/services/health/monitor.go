@@ -4,12 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"log"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/sidot/backend/internal/models"
 	"github.com/sidot/backend/internal/services/listener"
 	"github.com/sidot/backend/internal/services/notification"
 	"github.com/sidot/backend/internal/services/triagem"
@@ -32,6 +34,9 @@ const (
 	// Latency thresholds in milliseconds
 	LatencyThresholdOK       = 500   // < 500ms = up
 	LatencyThresholdDegraded = 2000  // 500-2000ms = degraded
+
+	// PoolUsageThresholdDegraded is the percentage of MaxOpenConns in use at which the database is degraded
+	PoolUsageThresholdDegraded = 80
 )
 
 // ServiceStatus represents the status of a single service
@@ -50,6 +55,19 @@ type ComponentStatus struct {
 	LatencyMs int64         `json:"latency_ms"`
 	LastCheck time.Time     `json:"last_check"`
 	Message   string        `json:"message,omitempty"`
+
+	// Pool is set for the database component
+	Pool *DBPoolStats `json:"pool,omitempty"`
+}
+
+// DBPoolStats are the database connection pool statistics (from sql.DB.Stats)
+type DBPoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
 }
 
 // HealthSummary represents the overall health status
@@ -67,11 +85,48 @@ type StateTransition struct {
 	Timestamp     time.Time     `json:"timestamp"`
 }
 
+// alertSender sends infrastructure alert emails (implemented by notification.EmailService)
+type alertSender interface {
+	IsConfigured() bool
+	SendInfrastructureAlert(ctx context.Context, to string, data *notification.InfrastructureAlertData) error
+	SendInfrastructureRecovery(ctx context.Context, to string, data *notification.InfrastructureAlertData) error
+}
+
+// criticalComponent describes a component that alerts the admin when it goes down
+type criticalComponent struct {
+	Name         string
+	AlertMessage string
+}
+
+// criticalComponents are the components whose down transitions send an infrastructure alert, by component key
+var criticalComponents = map[string]criticalComponent{
+	"listener": {
+		Name:         "Obito Listener",
+		AlertMessage: "O servico Obito Listener parou de responder. Verifique imediatamente para evitar perda de notificacoes de doacao.",
+	},
+	"database": {
+		Name:         "Database (PostgreSQL)",
+		AlertMessage: "O banco de dados PostgreSQL nao esta respondendo. Ocorrencias e notificacoes nao podem ser registradas.",
+	},
+	"redis": {
+		Name:         "Redis",
+		AlertMessage: "O Redis nao esta respondendo. A fila de obitos, as filas de notificacao e o cache estao indisponiveis.",
+	},
+	"triagem_motor": {
+		Name:         "Triagem Motor",
+		AlertMessage: "O motor de triagem parou. Novos obitos nao estao sendo avaliados para doacao.",
+	},
+	"sse_hub": {
+		Name:         "SSE Hub",
+		AlertMessage: "O hub de notificacoes em tempo real parou. Os operadores nao estao recebendo novas ocorrencias no painel.",
+	},
+}
+
 // HealthMonitorService monitors the health of all system components
 type HealthMonitorService struct {
 	db           *sql.DB
 	redis        *redis.Client
-	emailService *notification.EmailService
+	emailService alertSender
 	sseHub       *notification.SSEHub
 	listener     *listener.ObitoListener
 	triagemMotor *triagem.TriagemMotor
@@ -86,9 +141,17 @@ type HealthMonitorService struct {
 	cooldownMu     sync.RWMutex
 	cooldownPeriod time.Duration
 
+	// Components with a down alert awaiting a recovery notice, with when they went down
+	activeAlerts   map[string]time.Time
+	activeAlertsMu sync.Mutex
+
 	// Check interval
 	checkInterval time.Duration
 
+	// Per-component latency thresholds (LatencyThresholdOK/LatencyThresholdDegraded when absent)
+	latencyThresholds   models.HealthLatencyThresholdsConfig
+	latencyThresholdsMu sync.RWMutex
+
 	// Status tracking
 	running int32
 
@@ -111,19 +174,26 @@ func NewHealthMonitorService(
 	emailService *notification.EmailService,
 	adminEmail string,
 ) *HealthMonitorService {
-	return &HealthMonitorService{
+	m := &HealthMonitorService{
 		db:              db,
 		redis:           redisClient,
-		emailService:    emailService,
 		adminEmail:      adminEmail,
 		lastKnownStates: make(map[string]ServiceStatus),
 		alertCooldowns:  make(map[string]time.Time),
+		activeAlerts:    make(map[string]time.Time),
 		cooldownPeriod:  DefaultAlertCooldown,
 		checkInterval:   DefaultCheckInterval,
 		stopCh:          make(chan struct{}),
 		doneCh:          make(chan struct{}),
 		logger:          log.Default(),
 	}
+
+	// Leave the interface nil when no email service is given so alerts are skipped instead of panicking
+	if emailService != nil {
+		m.emailService = emailService
+	}
+
+	return m
 }
 
 // SetSSEHub sets the SSE hub for publishing events
@@ -151,6 +221,13 @@ func (m *HealthMonitorService) SetCooldownPeriod(period time.Duration) {
 	m.cooldownPeriod = period
 }
 
+// SetLatencyThresholds sets per-component latency thresholds; nil restores the defaults for every component
+func (m *HealthMonitorService) SetLatencyThresholds(thresholds models.HealthLatencyThresholdsConfig) {
+	m.latencyThresholdsMu.Lock()
+	defer m.latencyThresholdsMu.Unlock()
+	m.latencyThresholds = thresholds
+}
+
 // SetLogger sets the logger
 func (m *HealthMonitorService) SetLogger(logger *log.Logger) {
 	m.logger = logger
@@ -208,8 +285,13 @@ func (m *HealthMonitorService) monitorLoop(ctx context.Context) {
 	}
 }
 
+// CheckNow runs all health checks immediately, handling state transitions and refreshing the cached summary
+func (m *HealthMonitorService) CheckNow(ctx context.Context) *HealthSummary {
+	return m.performHealthCheck(ctx)
+}
+
 // performHealthCheck performs all health checks and handles state transitions
-func (m *HealthMonitorService) performHealthCheck(ctx context.Context) {
+func (m *HealthMonitorService) performHealthCheck(ctx context.Context) *HealthSummary {
 	summary := m.GetHealthSummary(ctx)
 
 	// Cache the summary
@@ -224,23 +306,23 @@ func (m *HealthMonitorService) performHealthCheck(ctx context.Context) {
 
 	// Save states to Redis
 	m.saveLastStates(ctx)
+
+	return summary
 }
 
-// GetHealthSummary returns the current health summary
-func (m *HealthMonitorService) GetHealthSummary(ctx context.Context) *HealthSummary {
-	summary := &HealthSummary{
-		Timestamp:  time.Now(),
-		Components: make(map[string]ComponentStatus),
-	}
+// ReadinessComponents are the components that must not be down for the API to serve traffic:
+// its dependencies and the background services
+var ReadinessComponents = []string{"database", "redis", "listener", "triagem_motor", "sse_hub"}
 
-	// Check all components in parallel
-	var wg sync.WaitGroup
-	var mu sync.Mutex
+// componentCheck is a named component health check
+type componentCheck struct {
+	name  string
+	check func(context.Context) ComponentStatus
+}
 
-	checks := []struct {
-		name  string
-		check func(context.Context) ComponentStatus
-	}{
+// componentChecks returns the checks of all components
+func (m *HealthMonitorService) componentChecks() []componentCheck {
+	return []componentCheck{
 		{"database", m.checkDatabase},
 		{"redis", m.checkRedis},
 		{"listener", m.checkListener},
@@ -248,6 +330,37 @@ func (m *HealthMonitorService) GetHealthSummary(ctx context.Context) *HealthSumm
 		{"sse_hub", m.checkSSEHub},
 		{"api", m.checkAPI},
 	}
+}
+
+// GetHealthSummary returns the current health summary
+func (m *HealthMonitorService) GetHealthSummary(ctx context.Context) *HealthSummary {
+	return m.runChecks(ctx, m.componentChecks())
+}
+
+// CheckReadiness runs the checks of the readiness components only, without caching the summary
+// or handling state transitions. A degraded component still counts as ready.
+func (m *HealthMonitorService) CheckReadiness(ctx context.Context) *HealthSummary {
+	var checks []componentCheck
+	for _, c := range m.componentChecks() {
+		for _, name := range ReadinessComponents {
+			if c.name == name {
+				checks = append(checks, c)
+				break
+			}
+		}
+	}
+	return m.runChecks(ctx, checks)
+}
+
+// runChecks runs the given checks in parallel and aggregates them in a summary
+func (m *HealthMonitorService) runChecks(ctx context.Context, checks []componentCheck) *HealthSummary {
+	summary := &HealthSummary{
+		Timestamp:  time.Now(),
+		Components: make(map[string]ComponentStatus),
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
 
 	for _, c := range checks {
 		wg.Add(1)
@@ -303,8 +416,11 @@ func (m *HealthMonitorService) calculateOverallStatus(components map[string]Comp
 	return StatusUp
 }
 
-// checkDatabase checks PostgreSQL health
+// checkDatabase checks PostgreSQL health and connection pool usage
 func (m *HealthMonitorService) checkDatabase(ctx context.Context) ComponentStatus {
+	// Read the pool before pinging so the ping's own connection is not counted
+	pool := dbPoolStats(m.db.Stats())
+
 	start := time.Now()
 
 	err := m.db.PingContext(ctx)
@@ -314,6 +430,7 @@ func (m *HealthMonitorService) checkDatabase(ctx context.Context) ComponentStatu
 		Name:      "Database (PostgreSQL)",
 		LatencyMs: latencyMs,
 		LastCheck: time.Now(),
+		Pool:      &pool,
 	}
 
 	if err != nil {
@@ -322,10 +439,35 @@ func (m *HealthMonitorService) checkDatabase(ctx context.Context) ComponentStatu
 		return status
 	}
 
-	status.Status = m.latencyToStatus(latencyMs)
+	status.Status = m.latencyToStatus("database", latencyMs)
+	if status.Status == StatusUp && pool.nearExhaustion() {
+		status.Status = StatusDegraded
+		status.Message = fmt.Sprintf("connection pool near exhaustion: %d of %d connections in use", pool.InUse, pool.MaxOpenConnections)
+	}
 	return status
 }
 
+// dbPoolStats converts sql.DBStats to DBPoolStats
+func dbPoolStats(stats sql.DBStats) DBPoolStats {
+	return DBPoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+	}
+}
+
+// nearExhaustion reports whether the connections in use reached PoolUsageThresholdDegraded percent of the limit
+// An unlimited pool (MaxOpenConnections 0) is never near exhaustion
+func (p DBPoolStats) nearExhaustion() bool {
+	if p.MaxOpenConnections <= 0 {
+		return false
+	}
+	return p.InUse*100 >= p.MaxOpenConnections*PoolUsageThresholdDegraded
+}
+
 // checkRedis checks Redis health
 func (m *HealthMonitorService) checkRedis(ctx context.Context) ComponentStatus {
 	start := time.Now()
@@ -345,7 +487,7 @@ func (m *HealthMonitorService) checkRedis(ctx context.Context) ComponentStatus {
 		return status
 	}
 
-	status.Status = m.latencyToStatus(latencyMs)
+	status.Status = m.latencyToStatus("redis", latencyMs)
 	return status
 }
 
@@ -361,7 +503,7 @@ func (m *HealthMonitorService) checkListener(ctx context.Context) ComponentStatu
 
 	switch listenerStatus {
 	case "up":
-		status.Status = m.latencyToStatus(latencyMs)
+		status.Status = m.latencyToStatus("listener", latencyMs)
 	default:
 		status.Status = StatusDown
 		status.Message = "No heartbeat detected"
@@ -438,12 +580,20 @@ func (m *HealthMonitorService) checkAPI(ctx context.Context) ComponentStatus {
 	return status
 }
 
-// latencyToStatus converts latency to status
-func (m *HealthMonitorService) latencyToStatus(latencyMs int64) ServiceStatus {
-	if latencyMs < LatencyThresholdOK {
+// latencyToStatus converts a component's latency to status using its configured thresholds
+func (m *HealthMonitorService) latencyToStatus(component string, latencyMs int64) ServiceStatus {
+	okMs, degradedMs := int64(LatencyThresholdOK), int64(LatencyThresholdDegraded)
+
+	m.latencyThresholdsMu.RLock()
+	if threshold, ok := m.latencyThresholds[component]; ok {
+		okMs, degradedMs = threshold.OKMs, threshold.DegradedMs
+	}
+	m.latencyThresholdsMu.RUnlock()
+
+	if latencyMs < okMs {
 		return StatusUp
 	}
-	if latencyMs < LatencyThresholdDegraded {
+	if latencyMs < degradedMs {
 		return StatusDegraded
 	}
 	return StatusDown
@@ -476,17 +626,23 @@ func (m *HealthMonitorService) checkStateTransition(ctx context.Context, service
 	m.logger.Printf("[HealthMonitor] State transition detected: %s %s -> %s",
 		service, previousState, newState)
 
-	// Send alert if listener goes down
-	if service == "listener" && previousState == StatusUp && newState == StatusDown {
-		m.sendListenerDownAlert(ctx, transition)
+	// Send alert if a critical component goes down, and a recovery notice when an alerted component is back up
+	if component, ok := criticalComponents[service]; ok {
+		switch newState {
+		case StatusDown:
+			m.sendComponentDownAlert(ctx, component, transition)
+		case StatusUp:
+			m.sendComponentRecovery(ctx, component, transition)
+		}
 	}
 
 	// Publish SSE event for status change (optional feature)
 	m.publishStatusChangeEvent(ctx, transition)
 }
 
-// sendListenerDownAlert sends an email alert when the listener goes down
-func (m *HealthMonitorService) sendListenerDownAlert(ctx context.Context, transition StateTransition) {
+// sendComponentDownAlert sends an email alert when a critical component goes down
+// Each component has its own cooldown, keyed by the component key
+func (m *HealthMonitorService) sendComponentDownAlert(ctx context.Context, component criticalComponent, transition StateTransition) {
 	if m.emailService == nil || !m.emailService.IsConfigured() {
 		m.logger.Println("[HealthMonitor] Email service not configured, skipping alert")
 		return
@@ -498,27 +654,60 @@ func (m *HealthMonitorService) sendListenerDownAlert(ctx context.Context, transi
 	}
 
 	// Check cooldown
-	if !m.canSendAlert("listener") {
-		m.logger.Println("[HealthMonitor] Alert cooldown active, skipping listener down alert")
+	if !m.canSendAlert(transition.Service) {
+		m.logger.Printf("[HealthMonitor] Alert cooldown active, skipping %s down alert", transition.Service)
 		return
 	}
 
 	// Mark alert as sent
-	m.markAlertSent("listener")
+	m.markAlertSent(transition.Service)
 
 	// Send the alert
 	err := m.emailService.SendInfrastructureAlert(ctx, m.adminEmail, &notification.InfrastructureAlertData{
-		ServiceName:    "Obito Listener",
+		ServiceName:    component.Name,
 		Status:         "DOWN",
 		PreviousStatus: string(transition.PreviousState),
 		Timestamp:      transition.Timestamp,
-		Message:        "O servico Obito Listener parou de responder. Verifique imediatamente para evitar perda de notificacoes de doacao.",
+		Message:        component.AlertMessage,
+	})
+
+	if err != nil {
+		m.logger.Printf("[HealthMonitor] Error sending %s down alert: %v", transition.Service, err)
+		return
+	}
+
+	m.activeAlertsMu.Lock()
+	m.activeAlerts[transition.Service] = transition.Timestamp
+	m.activeAlertsMu.Unlock()
+
+	m.logger.Printf("[HealthMonitor] %s down alert sent to admin", component.Name)
+}
+
+// sendComponentRecovery sends a recovery email when a component the admin was alerted about is back up
+// Components that went down without an alert (e.g. during a cooldown) recover silently
+func (m *HealthMonitorService) sendComponentRecovery(ctx context.Context, component criticalComponent, transition StateTransition) {
+	m.activeAlertsMu.Lock()
+	downSince, alerted := m.activeAlerts[transition.Service]
+	delete(m.activeAlerts, transition.Service)
+	m.activeAlertsMu.Unlock()
+
+	if !alerted || m.emailService == nil || !m.emailService.IsConfigured() || m.adminEmail == "" {
+		return
+	}
+
+	err := m.emailService.SendInfrastructureRecovery(ctx, m.adminEmail, &notification.InfrastructureAlertData{
+		ServiceName:    component.Name,
+		Status:         "UP",
+		PreviousStatus: string(transition.PreviousState),
+		Timestamp:      transition.Timestamp,
+		DownSince:      downSince,
+		Message:        fmt.Sprintf("O servico %s voltou a responder normalmente.", component.Name),
 	})
 
 	if err != nil {
-		m.logger.Printf("[HealthMonitor] Error sending listener down alert: %v", err)
+		m.logger.Printf("[HealthMonitor] Error sending %s recovery notice: %v", transition.Service, err)
 	} else {
-		m.logger.Println("[HealthMonitor] Listener down alert sent to admin")
+		m.logger.Printf("[HealthMonitor] %s recovery notice sent to admin", component.Name)
 	}
 }
 
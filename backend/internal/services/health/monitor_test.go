@@ -0,0 +1,355 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/services/notification"
+)
+
+// pingDriver is a database/sql driver whose connections only answer pings,
+// so pool behaviour can be tested without PostgreSQL
+// The data source name is an optional ping delay (e.g. "50ms")
+type pingDriver struct{}
+
+func (pingDriver) Open(name string) (driver.Conn, error) {
+	var delay time.Duration
+	if name != "" {
+		d, err := time.ParseDuration(name)
+		if err != nil {
+			return nil, err
+		}
+		delay = d
+	}
+	return pingConn{delay: delay}, nil
+}
+
+type pingConn struct {
+	delay time.Duration
+}
+
+func (pingConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (pingConn) Close() error                              { return nil }
+func (pingConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (c pingConn) Ping(ctx context.Context) error {
+	time.Sleep(c.delay)
+	return nil
+}
+
+func init() {
+	sql.Register("health-ping", pingDriver{})
+}
+
+// openPoolDB opens a database with a pool of at most maxOpen connections
+func openPoolDB(t *testing.T, maxOpen int) *sql.DB {
+	t.Helper()
+	return openPingDB(t, maxOpen, "")
+}
+
+// openPingDB opens a database whose pings take the given delay
+func openPingDB(t *testing.T, maxOpen int, delay string) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("health-ping", delay)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(maxOpen)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// holdConns checks out n connections from the pool until the test ends
+func holdConns(t *testing.T, db *sql.DB, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		conn, err := db.Conn(context.Background())
+		if err != nil {
+			t.Fatalf("Failed to check out connection: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+	}
+}
+
+// TestCheckDatabaseReportsPoolStats tests that pool statistics are reported and a lightly used pool is up
+func TestCheckDatabaseReportsPoolStats(t *testing.T) {
+	db := openPoolDB(t, 5)
+	holdConns(t, db, 1)
+	monitor := NewHealthMonitorService(db, nil, nil, "")
+
+	status := monitor.checkDatabase(context.Background())
+
+	if status.Status != StatusUp {
+		t.Errorf("Expected database to be up, got %s (%s)", status.Status, status.Message)
+	}
+	if status.Pool == nil {
+		t.Fatal("Expected pool statistics")
+	}
+	if status.Pool.MaxOpenConnections != 5 || status.Pool.InUse != 1 {
+		t.Errorf("Expected 1 of 5 connections in use, got %+v", *status.Pool)
+	}
+}
+
+// TestCheckDatabaseDegradedNearPoolExhaustion tests that the database is degraded when the pool is almost fully in use
+func TestCheckDatabaseDegradedNearPoolExhaustion(t *testing.T) {
+	db := openPoolDB(t, 5)
+	holdConns(t, db, 4)
+	monitor := NewHealthMonitorService(db, nil, nil, "")
+
+	status := monitor.checkDatabase(context.Background())
+
+	if status.Status != StatusDegraded {
+		t.Fatalf("Expected database to be degraded, got %s", status.Status)
+	}
+	if status.Message != "connection pool near exhaustion: 4 of 5 connections in use" {
+		t.Errorf("Unexpected message %q", status.Message)
+	}
+	if status.Pool == nil || status.Pool.InUse != 4 {
+		t.Errorf("Expected 4 connections in use, got %+v", status.Pool)
+	}
+}
+
+// TestDBPoolStatsNearExhaustion tests the pool usage threshold
+func TestDBPoolStatsNearExhaustion(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxOpen int
+		inUse   int
+		want    bool
+	}{
+		{"unlimited pool", 0, 100, false},
+		{"idle pool", 25, 0, false},
+		{"below threshold", 25, 19, false},
+		{"at threshold", 25, 20, true},
+		{"exhausted", 25, 25, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := DBPoolStats{MaxOpenConnections: tt.maxOpen, InUse: tt.inUse}
+			if got := pool.nearExhaustion(); got != tt.want {
+				t.Errorf("nearExhaustion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCheckDatabaseUsesComponentLatencyThresholds tests that a database degraded under strict thresholds is up under relaxed ones
+func TestCheckDatabaseUsesComponentLatencyThresholds(t *testing.T) {
+	db := openPingDB(t, 5, "30ms")
+	monitor := NewHealthMonitorService(db, nil, nil, "")
+
+	monitor.SetLatencyThresholds(models.HealthLatencyThresholdsConfig{
+		"database": {OKMs: 10, DegradedMs: 1000},
+	})
+	if status := monitor.checkDatabase(context.Background()); status.Status != StatusDegraded {
+		t.Errorf("Expected database to be degraded under strict thresholds, got %s (latency %dms)", status.Status, status.LatencyMs)
+	}
+
+	monitor.SetLatencyThresholds(models.HealthLatencyThresholdsConfig{
+		"database": {OKMs: 1000, DegradedMs: 5000},
+	})
+	if status := monitor.checkDatabase(context.Background()); status.Status != StatusUp {
+		t.Errorf("Expected database to be up under relaxed thresholds, got %s (latency %dms)", status.Status, status.LatencyMs)
+	}
+}
+
+// TestLatencyToStatusPerComponent tests that thresholds apply only to their component and others keep the defaults
+func TestLatencyToStatusPerComponent(t *testing.T) {
+	monitor := NewHealthMonitorService(nil, nil, nil, "")
+	monitor.SetLatencyThresholds(models.HealthLatencyThresholdsConfig{
+		"redis": {OKMs: 50, DegradedMs: 200},
+	})
+
+	tests := []struct {
+		component string
+		latencyMs int64
+		want      ServiceStatus
+	}{
+		{"redis", 20, StatusUp},
+		{"redis", 100, StatusDegraded},
+		{"redis", 300, StatusDown},
+		{"database", 300, StatusUp},
+		{"database", LatencyThresholdOK, StatusDegraded},
+		{"database", LatencyThresholdDegraded, StatusDown},
+	}
+
+	for _, tt := range tests {
+		if got := monitor.latencyToStatus(tt.component, tt.latencyMs); got != tt.want {
+			t.Errorf("latencyToStatus(%q, %d) = %s, want %s", tt.component, tt.latencyMs, got, tt.want)
+		}
+	}
+
+	// Clearing the thresholds restores the defaults
+	monitor.SetLatencyThresholds(nil)
+	if got := monitor.latencyToStatus("redis", 300); got != StatusUp {
+		t.Errorf("Expected default thresholds after reset, got %s", got)
+	}
+}
+
+// recordingAlertSender records infrastructure alerts and recovery notices instead of emailing them
+type recordingAlertSender struct {
+	alerts     []notification.InfrastructureAlertData
+	recoveries []notification.InfrastructureAlertData
+}
+
+func (s *recordingAlertSender) IsConfigured() bool { return true }
+
+func (s *recordingAlertSender) SendInfrastructureAlert(ctx context.Context, to string, data *notification.InfrastructureAlertData) error {
+	s.alerts = append(s.alerts, *data)
+	return nil
+}
+
+func (s *recordingAlertSender) SendInfrastructureRecovery(ctx context.Context, to string, data *notification.InfrastructureAlertData) error {
+	s.recoveries = append(s.recoveries, *data)
+	return nil
+}
+
+// newAlertingMonitor creates a monitor that records alerts sent to the admin
+func newAlertingMonitor() (*HealthMonitorService, *recordingAlertSender) {
+	sender := &recordingAlertSender{}
+	monitor := NewHealthMonitorService(nil, nil, nil, "ops@example.com")
+	monitor.emailService = sender
+	monitor.SetLogger(log.New(io.Discard, "", 0))
+	return monitor, sender
+}
+
+// TestComponentDownTriggersAlert tests that critical components other than the listener alert when they go down
+func TestComponentDownTriggersAlert(t *testing.T) {
+	monitor, sender := newAlertingMonitor()
+	ctx := context.Background()
+
+	monitor.checkStateTransition(ctx, "redis", StatusUp)
+	monitor.checkStateTransition(ctx, "redis", StatusDown)
+
+	if len(sender.alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(sender.alerts))
+	}
+	alert := sender.alerts[0]
+	if alert.ServiceName != "Redis" || alert.Status != "DOWN" || alert.PreviousStatus != string(StatusUp) {
+		t.Errorf("Unexpected alert %+v", alert)
+	}
+}
+
+// TestComponentDownAlertCooldown tests that a second down transition within the cooldown is suppressed per component
+func TestComponentDownAlertCooldown(t *testing.T) {
+	monitor, sender := newAlertingMonitor()
+	ctx := context.Background()
+
+	for _, state := range []ServiceStatus{StatusUp, StatusDown, StatusUp, StatusDown} {
+		monitor.checkStateTransition(ctx, "redis", state)
+	}
+	if len(sender.alerts) != 1 {
+		t.Fatalf("Expected the second redis alert to be suppressed, got %d alerts", len(sender.alerts))
+	}
+
+	// Another component has its own cooldown
+	monitor.checkStateTransition(ctx, "database", StatusUp)
+	monitor.checkStateTransition(ctx, "database", StatusDown)
+	if len(sender.alerts) != 2 || sender.alerts[1].ServiceName != "Database (PostgreSQL)" {
+		t.Fatalf("Expected a database alert despite the redis cooldown, got %+v", sender.alerts)
+	}
+
+	// After the cooldown the component alerts again
+	monitor.SetCooldownPeriod(0)
+	monitor.checkStateTransition(ctx, "redis", StatusUp)
+	monitor.checkStateTransition(ctx, "redis", StatusDown)
+	if len(sender.alerts) != 3 {
+		t.Errorf("Expected a redis alert after the cooldown, got %d alerts", len(sender.alerts))
+	}
+}
+
+// TestNonCriticalComponentDownDoesNotAlert tests that components outside the critical list never alert
+func TestNonCriticalComponentDownDoesNotAlert(t *testing.T) {
+	monitor, sender := newAlertingMonitor()
+	ctx := context.Background()
+
+	monitor.checkStateTransition(ctx, "api", StatusUp)
+	monitor.checkStateTransition(ctx, "api", StatusDown)
+	monitor.checkStateTransition(ctx, "triagem_motor", StatusUp)
+	monitor.checkStateTransition(ctx, "triagem_motor", StatusDegraded)
+
+	if len(sender.alerts) != 0 {
+		t.Errorf("Expected no alerts, got %+v", sender.alerts)
+	}
+}
+
+// TestComponentRecoveryAfterAlert tests that a down-then-up sequence sends exactly one alert and one recovery notice
+func TestComponentRecoveryAfterAlert(t *testing.T) {
+	monitor, sender := newAlertingMonitor()
+	ctx := context.Background()
+
+	for _, state := range []ServiceStatus{StatusUp, StatusDown, StatusDown, StatusUp, StatusUp} {
+		monitor.checkStateTransition(ctx, "database", state)
+	}
+
+	if len(sender.alerts) != 1 || len(sender.recoveries) != 1 {
+		t.Fatalf("Expected 1 alert and 1 recovery, got %d alerts and %d recoveries", len(sender.alerts), len(sender.recoveries))
+	}
+	recovery := sender.recoveries[0]
+	if recovery.ServiceName != "Database (PostgreSQL)" || recovery.Status != "UP" || recovery.PreviousStatus != string(StatusDown) {
+		t.Errorf("Unexpected recovery %+v", recovery)
+	}
+	if !recovery.DownSince.Equal(sender.alerts[0].Timestamp) {
+		t.Errorf("Expected recovery to report the alert time %v as down since, got %v", sender.alerts[0].Timestamp, recovery.DownSince)
+	}
+}
+
+// TestComponentRecoveryWithoutAlert tests that no recovery notice is sent for an outage that was never alerted
+func TestComponentRecoveryWithoutAlert(t *testing.T) {
+	monitor, sender := newAlertingMonitor()
+	ctx := context.Background()
+
+	// The first outage alerts and recovers; the second falls within the cooldown and is not alerted
+	for _, state := range []ServiceStatus{StatusUp, StatusDown, StatusUp, StatusDown, StatusUp} {
+		monitor.checkStateTransition(ctx, "redis", state)
+	}
+	if len(sender.alerts) != 1 || len(sender.recoveries) != 1 {
+		t.Fatalf("Expected 1 alert and 1 recovery, got %d alerts and %d recoveries", len(sender.alerts), len(sender.recoveries))
+	}
+
+	// Degraded to up was never down, so there is nothing to recover from
+	monitor.checkStateTransition(ctx, "sse_hub", StatusUp)
+	monitor.checkStateTransition(ctx, "sse_hub", StatusDegraded)
+	monitor.checkStateTransition(ctx, "sse_hub", StatusUp)
+	if len(sender.recoveries) != 1 {
+		t.Errorf("Expected no recovery for a component that was not alerted, got %d", len(sender.recoveries))
+	}
+}
+
+// TestCheckReadinessReportsUnreachableRedis tests that readiness only runs its components and is down without Redis
+func TestCheckReadinessReportsUnreachableRedis(t *testing.T) {
+	db := openPoolDB(t, 5)
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond, MaxRetries: -1})
+	t.Cleanup(func() { redisClient.Close() })
+	monitor := NewHealthMonitorService(db, redisClient, nil, "")
+	monitor.SetLogger(log.New(io.Discard, "", 0))
+
+	summary := monitor.CheckReadiness(context.Background())
+
+	if summary.Status != StatusDown {
+		t.Errorf("Expected readiness to be down, got %s", summary.Status)
+	}
+	if len(summary.Components) != len(ReadinessComponents) {
+		t.Errorf("Expected %d components, got %d", len(ReadinessComponents), len(summary.Components))
+	}
+	if _, ok := summary.Components["api"]; ok {
+		t.Error("Expected the API self-check to be left out of readiness")
+	}
+	if summary.Components["database"].Status != StatusUp || summary.Components["redis"].Status != StatusDown {
+		t.Errorf("Expected database up and redis down, got %+v", summary.Components)
+	}
+	if monitor.GetLastSummary() != nil {
+		t.Error("Expected readiness checks not to replace the cached summary")
+	}
+}
@@ -0,0 +1,193 @@
+package metricscache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sidot/backend/internal/middleware"
+)
+
+const (
+	// DefaultTTL is how long a cached metrics result is served
+	DefaultTTL = 30 * time.Second
+
+	// KeyPrefix is the prefix for Redis keys of cached metrics results
+	KeyPrefix = "metrics:cache:"
+
+	// VersionKey holds the cache version; bumping it invalidates every cached result
+	VersionKey = "metrics:cache_version"
+
+	// allTenantsKey identifies results computed without a tenant (super admin across tenants)
+	allTenantsKey = "all"
+)
+
+// errCacheMiss is returned by a store when the key does not exist
+var errCacheMiss = errors.New("cache miss")
+
+// store is the key-value storage of the cache (implemented by redisStore)
+type store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	Incr(ctx context.Context, key string) error
+}
+
+// redisStore adapts a Redis client to the cache store
+type redisStore struct {
+	client *redis.Client
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) (string, error) {
+	value, err := s.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", errCacheMiss
+	}
+	return value, err
+}
+
+func (s *redisStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *redisStore) Incr(ctx context.Context, key string) error {
+	return s.client.Incr(ctx, key).Err()
+}
+
+// Cache caches dashboard metrics results keyed by tenant and filter params.
+// A nil Cache is valid and disables caching, so it can be used when Redis is not configured.
+type Cache struct {
+	store  store
+	ttl    time.Duration
+	hits   int64
+	misses int64
+	logger *log.Logger
+}
+
+// NewCache creates a metrics cache backed by Redis, or returns nil (caching disabled) without a client
+func NewCache(redisClient *redis.Client) *Cache {
+	if redisClient == nil {
+		return nil
+	}
+	return &Cache{
+		store:  &redisStore{client: redisClient},
+		ttl:    DefaultTTL,
+		logger: log.Default(),
+	}
+}
+
+// SetTTL sets how long a cached result is served
+func (c *Cache) SetTTL(ttl time.Duration) {
+	if c != nil {
+		c.ttl = ttl
+	}
+}
+
+// GetStats returns the hit and miss counters of the cache
+func (c *Cache) GetStats() map[string]interface{} {
+	if c == nil {
+		return map[string]interface{}{"enabled": false}
+	}
+	return map[string]interface{}{
+		"enabled": true,
+		"hits":    atomic.LoadInt64(&c.hits),
+		"misses":  atomic.LoadInt64(&c.misses),
+	}
+}
+
+// Fetch loads into dest the cached result of the named metric for the tenant of ctx and params,
+// or computes, caches and loads it on a miss. It returns whether the result came from the cache.
+// Cache errors are logged and the result is computed, so a Redis failure never fails the request.
+func (c *Cache) Fetch(ctx context.Context, name, params string, dest interface{}, compute func() (interface{}, error)) (bool, error) {
+	if c == nil {
+		return false, load(compute, dest)
+	}
+
+	tenant := tenantKey(ctx)
+	key := c.key(ctx, tenant, name, params)
+
+	if key != "" {
+		cached, err := c.store.Get(ctx, key)
+		if err == nil {
+			if err := json.Unmarshal([]byte(cached), dest); err == nil {
+				atomic.AddInt64(&c.hits, 1)
+				c.logger.Printf("[MetricsCache] hit %s (tenant %s)", name, tenant)
+				return true, nil
+			}
+		} else if !errors.Is(err, errCacheMiss) {
+			c.logger.Printf("[MetricsCache] Error reading %s: %v", name, err)
+		}
+	}
+
+	atomic.AddInt64(&c.misses, 1)
+	c.logger.Printf("[MetricsCache] miss %s (tenant %s)", name, tenant)
+
+	value, err := compute()
+	if err != nil {
+		return false, err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	if key != "" {
+		if err := c.store.Set(ctx, key, string(data), c.ttl); err != nil {
+			c.logger.Printf("[MetricsCache] Error caching %s: %v", name, err)
+		}
+	}
+
+	return false, json.Unmarshal(data, dest)
+}
+
+// Invalidate drops every cached result. It is called when an occurrence is created, changes status
+// (including expiry and AI-confirmed actions) or is assigned;
+// the tenant of a new occurrence is not known when it is relayed, so all tenants are invalidated.
+func (c *Cache) Invalidate(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	if err := c.store.Incr(ctx, VersionKey); err != nil {
+		c.logger.Printf("[MetricsCache] Error invalidating cache: %v", err)
+		return
+	}
+	c.logger.Println("[MetricsCache] Cache invalidated")
+}
+
+// key builds the cache key of a result for the current cache version, or "" if the version is unavailable
+func (c *Cache) key(ctx context.Context, tenant, name, params string) string {
+	version, err := c.store.Get(ctx, VersionKey)
+	if errors.Is(err, errCacheMiss) {
+		version = "0"
+	} else if err != nil {
+		c.logger.Printf("[MetricsCache] Error reading cache version, skipping cache: %v", err)
+		return ""
+	}
+	return KeyPrefix + version + ":" + tenant + ":" + name + ":" + params
+}
+
+// tenantKey returns the tenant of ctx, or allTenantsKey when there is none
+func tenantKey(ctx context.Context) string {
+	tenantID, err := middleware.GetTenantIDFromContext(ctx)
+	if err != nil || tenantID == "" {
+		return allTenantsKey
+	}
+	return tenantID
+}
+
+// load computes the result and loads it into dest without caching
+func load(compute func() (interface{}, error), dest interface{}) error {
+	value, err := compute()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
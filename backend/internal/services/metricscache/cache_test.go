@@ -0,0 +1,165 @@
+package metricscache
+
+import (
+	"context"
+	"io"
+	"log"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sidot/backend/internal/middleware"
+)
+
+// memoryStore is an in-memory cache store for tests
+type memoryStore struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{values: make(map[string]string)}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	if !ok {
+		return "", errCacheMiss
+	}
+	return value, nil
+}
+
+func (s *memoryStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	return nil
+}
+
+func (s *memoryStore) Incr(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, _ := strconv.Atoi(s.values[key])
+	s.values[key] = strconv.Itoa(current + 1)
+	return nil
+}
+
+type testResult struct {
+	Total int `json:"total"`
+}
+
+func newTestCache() *Cache {
+	return &Cache{
+		store:  newMemoryStore(),
+		ttl:    DefaultTTL,
+		logger: log.New(io.Discard, "", 0),
+	}
+}
+
+// countingCompute returns a compute function that counts its calls
+func countingCompute(calls *int, total int) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		*calls++
+		return testResult{Total: total}, nil
+	}
+}
+
+// TestFetchHitsCache tests that a repeated fetch is served from the cache
+func TestFetchHitsCache(t *testing.T) {
+	cache := newTestCache()
+	ctx := context.Background()
+	calls := 0
+
+	var first testResult
+	hit, err := cache.Fetch(ctx, "dashboard", "", &first, countingCompute(&calls, 7))
+	if err != nil || hit {
+		t.Fatalf("Expected a miss on the first fetch, got hit=%v err=%v", hit, err)
+	}
+
+	var second testResult
+	hit, err = cache.Fetch(ctx, "dashboard", "", &second, countingCompute(&calls, 99))
+	if err != nil || !hit {
+		t.Fatalf("Expected a hit on the second fetch, got hit=%v err=%v", hit, err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected compute to run once, ran %d times", calls)
+	}
+	if second.Total != 7 {
+		t.Errorf("Expected cached total 7, got %d", second.Total)
+	}
+
+	stats := cache.GetStats()
+	if stats["hits"] != int64(1) || stats["misses"] != int64(1) {
+		t.Errorf("Unexpected stats: %v", stats)
+	}
+}
+
+// TestInvalidateBustsCache tests that invalidating (as on occurrence creation) recomputes the result
+func TestInvalidateBustsCache(t *testing.T) {
+	cache := newTestCache()
+	ctx := context.Background()
+	calls := 0
+
+	var result testResult
+	cache.Fetch(ctx, "dashboard", "", &result, countingCompute(&calls, 1))
+
+	cache.Invalidate(ctx)
+
+	hit, err := cache.Fetch(ctx, "dashboard", "", &result, countingCompute(&calls, 2))
+	if err != nil || hit {
+		t.Fatalf("Expected a miss after invalidation, got hit=%v err=%v", hit, err)
+	}
+	if calls != 2 || result.Total != 2 {
+		t.Errorf("Expected a recomputed total 2 after 2 calls, got %d after %d calls", result.Total, calls)
+	}
+}
+
+// TestFetchKeysByTenantAndParams tests that tenants and params do not share cached results
+func TestFetchKeysByTenantAndParams(t *testing.T) {
+	cache := newTestCache()
+	tenantA := middleware.WithTenantContext(context.Background(), "tenant-a", false)
+	tenantB := middleware.WithTenantContext(context.Background(), "tenant-b", false)
+	calls := 0
+
+	var result testResult
+	cache.Fetch(tenantA, "indicators", "group_by=day", &result, countingCompute(&calls, 1))
+
+	if hit, _ := cache.Fetch(tenantB, "indicators", "group_by=day", &result, countingCompute(&calls, 2)); hit {
+		t.Error("Expected another tenant to miss the cache")
+	}
+	if hit, _ := cache.Fetch(tenantA, "indicators", "group_by=week", &result, countingCompute(&calls, 3)); hit {
+		t.Error("Expected other params to miss the cache")
+	}
+	if hit, _ := cache.Fetch(tenantA, "indicators", "group_by=day", &result, countingCompute(&calls, 4)); !hit || result.Total != 1 {
+		t.Errorf("Expected the original result from the cache, got hit=%v total=%d", hit, result.Total)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 computations, got %d", calls)
+	}
+}
+
+// TestNilCacheComputes tests that a disabled cache always computes the result
+func TestNilCacheComputes(t *testing.T) {
+	var cache *Cache
+	ctx := context.Background()
+	calls := 0
+
+	var result testResult
+	for i := 0; i < 2; i++ {
+		hit, err := cache.Fetch(ctx, "dashboard", "", &result, countingCompute(&calls, 5))
+		if err != nil || hit {
+			t.Fatalf("Expected a computed result, got hit=%v err=%v", hit, err)
+		}
+	}
+	if calls != 2 || result.Total != 5 {
+		t.Errorf("Expected 2 computations of total 5, got %d of %d", calls, result.Total)
+	}
+
+	cache.Invalidate(ctx)
+	if cache.GetStats()["enabled"] != false {
+		t.Error("Expected a nil cache to report disabled")
+	}
+}
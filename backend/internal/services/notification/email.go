@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"html/template"
+	"net"
 	"net/smtp"
+	"os"
 	"strings"
 	"time"
 )
@@ -16,8 +19,12 @@ var (
 	ErrSMTPNotConfigured = errors.New("SMTP not configured")
 	ErrInvalidRecipient  = errors.New("invalid recipient email")
 	ErrSendFailed        = errors.New("failed to send email")
+	ErrSTARTTLSRequired  = errors.New("SMTP server does not offer STARTTLS, refusing to send in cleartext")
 )
 
+// smtpDialTimeout bounds the connection to the SMTP server
+const smtpDialTimeout = 30 * time.Second
+
 // EmailConfig holds the configuration for email sending
 type EmailConfig struct {
 	SMTPHost     string
@@ -26,6 +33,21 @@ type EmailConfig struct {
 	SMTPPassword string
 	SMTPFrom     string
 	UseTLS       bool
+
+	// RequireSTARTTLS fails the send when the server does not offer STARTTLS,
+	// instead of delivering the email in cleartext (ignored with UseTLS)
+	RequireSTARTTLS bool
+	// RootCAs verifies the server certificate instead of the system roots
+	RootCAs *x509.CertPool
+	// InsecureSkipVerify disables certificate verification; development only
+	InsecureSkipVerify bool
+
+	// PoolSize caps the SMTP sessions kept open and reused across emails (0 dials one per email)
+	PoolSize int
+	// PoolIdleTimeout closes a pooled session unused for longer (default 30s)
+	PoolIdleTimeout time.Duration
+	// PoolMaxMessages recycles a pooled session after this many emails (0 is unlimited)
+	PoolMaxMessages int
 }
 
 // ObitoNotificationData represents the data for an obito notification email
@@ -37,6 +59,7 @@ type ObitoNotificationData struct {
 	OccurrenceID  string
 	Prioridade    int
 	DashboardURL  string
+	Locale        string // recipient locale, pt-BR when empty
 }
 
 // InfrastructureAlertData represents the data for an infrastructure alert email
@@ -47,18 +70,79 @@ type InfrastructureAlertData struct {
 	Timestamp      time.Time
 	Message        string
 	DashboardURL   string
+	Locale         string // recipient locale, pt-BR when empty
+
+	// DownSince is when the service went down (recovery emails only)
+	DownSince time.Time
+}
+
+// CoverageGapAlertData represents the data for an upcoming shift coverage gap email
+type CoverageGapAlertData struct {
+	HospitalNome string
+	DayName      string
+	StartTime    string
+	EndTime      string
+	StartsAt     time.Time
+	DashboardURL string
+	Locale       string // recipient locale, pt-BR when empty
+}
+
+// EscalationAlertData represents the data for an SLA escalation email about a pending occurrence
+type EscalationAlertData struct {
+	OccurrenceID   string
+	HospitalNome   string
+	Setor          string
+	TempoRestante  string
+	JanelaExpiraEm time.Time
+	DashboardURL   string
+	Locale         string // recipient locale, pt-BR when empty
+}
+
+// AuditAlertData represents the data for an email about a critical audit event
+type AuditAlertData struct {
+	Acao         string
+	Severity     string
+	ActorName    string
+	EntidadeTipo string
+	EntidadeID   string
+	IPAddress    string
+	Timestamp    time.Time
+	DashboardURL string
+	Locale       string // recipient locale, pt-BR when empty
+}
+
+// EmailVerificationData represents the data for an email with the link that verifies the email of a new user
+type EmailVerificationData struct {
+	Nome            string
+	VerificationURL string
+	ExpiresAt       time.Time
+	Locale          string // recipient locale, pt-BR when empty
+}
+
+// WelcomeEmailData represents the data for the onboarding email with the temporary password of a user
+type WelcomeEmailData struct {
+	Nome              string
+	Email             string
+	TemporaryPassword string
+	LoginURL          string
+	Locale            string // recipient locale, pt-BR when empty
 }
 
 // EmailService handles sending emails
 type EmailService struct {
 	config *EmailConfig
+	pool   *smtpPool // nil when sessions are not pooled
 }
 
 // NewEmailService creates a new EmailService
 func NewEmailService(config *EmailConfig) *EmailService {
-	return &EmailService{
+	s := &EmailService{
 		config: config,
 	}
+	if config != nil && config.PoolSize > 0 {
+		s.pool = newSMTPPool(config.PoolSize, config.PoolIdleTimeout, config.PoolMaxMessages, s.dialSMTP)
+	}
+	return s
 }
 
 // IsConfigured returns true if SMTP is properly configured
@@ -79,7 +163,7 @@ func (s *EmailService) SendObitoNotification(ctx context.Context, to string, dat
 		return ErrInvalidRecipient
 	}
 
-	subject := fmt.Sprintf("[URGENTE] Nova Ocorrencia Elegivel - %s", data.HospitalNome)
+	subject := obitoNotificationSubject(data)
 	body, err := s.renderObitoTemplate(data)
 	if err != nil {
 		return fmt.Errorf("failed to render template: %w", err)
@@ -103,7 +187,7 @@ func (s *EmailService) SendInfrastructureAlert(ctx context.Context, to string, d
 		data.DashboardURL = "http://localhost:3000/dashboard/status"
 	}
 
-	subject := fmt.Sprintf("[ALERTA] %s - %s", data.ServiceName, data.Status)
+	subject := fmt.Sprintf(infrastructureAlertSubjects.get(data.Locale), data.ServiceName, data.Status)
 	body, err := s.renderInfrastructureAlertTemplate(data)
 	if err != nil {
 		return fmt.Errorf("failed to render template: %w", err)
@@ -112,24 +196,196 @@ func (s *EmailService) SendInfrastructureAlert(ctx context.Context, to string, d
 	return s.sendEmail(ctx, to, subject, body)
 }
 
-// renderObitoTemplate renders the HTML template for obito notification
-func (s *EmailService) renderObitoTemplate(data *ObitoNotificationData) (string, error) {
-	tmpl, err := template.New("obito_notification").Parse(obitoNotificationTemplate)
+// SendInfrastructureRecovery sends an email telling the admin that a service that was alerted as down is back up
+func (s *EmailService) SendInfrastructureRecovery(ctx context.Context, to string, data *InfrastructureAlertData) error {
+	if !s.IsConfigured() {
+		return ErrSMTPNotConfigured
+	}
+
+	if to == "" || !strings.Contains(to, "@") {
+		return ErrInvalidRecipient
+	}
+
+	// Set default dashboard URL
+	if data.DashboardURL == "" {
+		data.DashboardURL = "http://localhost:3000/dashboard/status"
+	}
+
+	subject := fmt.Sprintf(infrastructureRecoverySubjects.get(data.Locale), data.ServiceName, data.Status)
+	body, err := s.renderInfrastructureRecoveryTemplate(data)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("failed to render template: %w", err)
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", err
+	return s.sendEmail(ctx, to, subject, body)
+}
+
+// SendCoverageGapAlert sends an email alert to a gestor about an upcoming shift coverage gap
+func (s *EmailService) SendCoverageGapAlert(ctx context.Context, to string, data *CoverageGapAlertData) error {
+	if !s.IsConfigured() {
+		return ErrSMTPNotConfigured
 	}
 
-	return buf.String(), nil
+	if to == "" || !strings.Contains(to, "@") {
+		return ErrInvalidRecipient
+	}
+
+	// Set default dashboard URL
+	if data.DashboardURL == "" {
+		data.DashboardURL = "http://localhost:3000/dashboard/shifts"
+	}
+
+	subject := fmt.Sprintf(coverageGapAlertSubjects.get(data.Locale), data.HospitalNome, data.DayName, data.StartTime)
+	body, err := s.renderCoverageGapAlertTemplate(data)
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return s.sendEmail(ctx, to, subject, body)
+}
+
+// SendEscalationAlert sends an email to a gestor about a pending occurrence close to window expiry
+func (s *EmailService) SendEscalationAlert(ctx context.Context, to string, data *EscalationAlertData) error {
+	if !s.IsConfigured() {
+		return ErrSMTPNotConfigured
+	}
+
+	if to == "" || !strings.Contains(to, "@") {
+		return ErrInvalidRecipient
+	}
+
+	// Set default dashboard URL
+	if data.DashboardURL == "" {
+		data.DashboardURL = fmt.Sprintf("http://localhost:3000/dashboard/occurrences?id=%s", data.OccurrenceID)
+	}
+
+	subject := fmt.Sprintf(escalationAlertSubjects.get(data.Locale), data.HospitalNome, data.TempoRestante)
+	body, err := s.renderEscalationAlertTemplate(data)
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return s.sendEmail(ctx, to, subject, body)
+}
+
+// renderObitoTemplate renders the HTML template for obito notification in the recipient locale
+func (s *EmailService) renderObitoTemplate(data *ObitoNotificationData) (string, error) {
+	return renderLocalizedTemplate("obito_notification", obitoNotificationTemplates.get(data.Locale), data)
 }
 
-// renderInfrastructureAlertTemplate renders the HTML template for infrastructure alert
+// renderInfrastructureAlertTemplate renders the HTML template for infrastructure alert in the recipient locale
 func (s *EmailService) renderInfrastructureAlertTemplate(data *InfrastructureAlertData) (string, error) {
-	tmpl, err := template.New("infrastructure_alert").Parse(infrastructureAlertTemplate)
+	return renderLocalizedTemplate("infrastructure_alert", infrastructureAlertTemplates.get(data.Locale), data)
+}
+
+// renderInfrastructureRecoveryTemplate renders the HTML template for infrastructure recovery in the recipient locale
+func (s *EmailService) renderInfrastructureRecoveryTemplate(data *InfrastructureAlertData) (string, error) {
+	return renderLocalizedTemplate("infrastructure_recovery", infrastructureRecoveryTemplates.get(data.Locale), data)
+}
+
+// renderCoverageGapAlertTemplate renders the HTML template for coverage gap alert in the recipient locale
+func (s *EmailService) renderCoverageGapAlertTemplate(data *CoverageGapAlertData) (string, error) {
+	return renderLocalizedTemplate("coverage_gap_alert", coverageGapAlertTemplates.get(data.Locale), data)
+}
+
+// SendAuditAlert sends an email to an admin about a critical audit event
+func (s *EmailService) SendAuditAlert(ctx context.Context, to string, data *AuditAlertData) error {
+	if !s.IsConfigured() {
+		return ErrSMTPNotConfigured
+	}
+
+	if to == "" || !strings.Contains(to, "@") {
+		return ErrInvalidRecipient
+	}
+
+	// Set default dashboard URL
+	if data.DashboardURL == "" {
+		data.DashboardURL = "http://localhost:3000/admin/logs"
+	}
+
+	subject := fmt.Sprintf(auditAlertSubjects.get(data.Locale), data.Severity, data.Acao)
+	body, err := s.renderAuditAlertTemplate(data)
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return s.sendEmail(ctx, to, subject, body)
+}
+
+// SendEmailVerification sends the verification link to a newly created user
+func (s *EmailService) SendEmailVerification(ctx context.Context, to, nome, locale, verificationURL string, expiresAt time.Time) error {
+	if !s.IsConfigured() {
+		return ErrSMTPNotConfigured
+	}
+
+	if to == "" || !strings.Contains(to, "@") {
+		return ErrInvalidRecipient
+	}
+
+	data := &EmailVerificationData{
+		Nome:            nome,
+		VerificationURL: verificationURL,
+		ExpiresAt:       expiresAt,
+		Locale:          locale,
+	}
+
+	body, err := s.renderEmailVerificationTemplate(data)
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return s.sendEmail(ctx, to, emailVerificationSubjects.get(data.Locale), body)
+}
+
+// SendWelcomeEmail sends the onboarding email with a temporary password to a user
+func (s *EmailService) SendWelcomeEmail(ctx context.Context, to, nome, locale, loginURL, temporaryPassword string) error {
+	if !s.IsConfigured() {
+		return ErrSMTPNotConfigured
+	}
+
+	if to == "" || !strings.Contains(to, "@") {
+		return ErrInvalidRecipient
+	}
+
+	data := &WelcomeEmailData{
+		Nome:              nome,
+		Email:             to,
+		TemporaryPassword: temporaryPassword,
+		LoginURL:          loginURL,
+		Locale:            locale,
+	}
+
+	body, err := s.renderWelcomeEmailTemplate(data)
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return s.sendEmail(ctx, to, welcomeEmailSubjects.get(data.Locale), body)
+}
+
+// renderEscalationAlertTemplate renders the HTML template for SLA escalation alert in the recipient locale
+func (s *EmailService) renderEscalationAlertTemplate(data *EscalationAlertData) (string, error) {
+	return renderLocalizedTemplate("escalation_alert", escalationAlertTemplates.get(data.Locale), data)
+}
+
+// renderAuditAlertTemplate renders the HTML template for critical audit event alerts in the recipient locale
+func (s *EmailService) renderAuditAlertTemplate(data *AuditAlertData) (string, error) {
+	return renderLocalizedTemplate("audit_alert", auditAlertTemplates.get(data.Locale), data)
+}
+
+// renderEmailVerificationTemplate renders the HTML template for email verification in the recipient locale
+func (s *EmailService) renderEmailVerificationTemplate(data *EmailVerificationData) (string, error) {
+	return renderLocalizedTemplate("email_verification", emailVerificationTemplates.get(data.Locale), data)
+}
+
+// renderWelcomeEmailTemplate renders the HTML template for welcome emails in the recipient locale
+func (s *EmailService) renderWelcomeEmailTemplate(data *WelcomeEmailData) (string, error) {
+	return renderLocalizedTemplate("welcome_email", welcomeEmailTemplates.get(data.Locale), data)
+}
+
+// renderLocalizedTemplate parses and renders the HTML template text of one locale
+func renderLocalizedTemplate(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
 	if err != nil {
 		return "", err
 	}
@@ -158,51 +414,93 @@ func (s *EmailService) sendEmail(ctx context.Context, to, subject, body string)
 	message.WriteString("\r\n")
 	message.WriteString(body)
 
-	addr := fmt.Sprintf("%s:%d", s.config.SMTPHost, s.config.SMTPPort)
-
-	var auth smtp.Auth
-	if s.config.SMTPUser != "" && s.config.SMTPPassword != "" {
-		auth = smtp.PlainAuth("", s.config.SMTPUser, s.config.SMTPPassword, s.config.SMTPHost)
-	}
-
-	// Use TLS if configured
-	if s.config.UseTLS || s.config.SMTPPort == 465 {
-		return s.sendEmailTLS(addr, auth, to, message.Bytes())
+	// Bursts reuse the pooled sessions
+	if s.pool != nil {
+		session, err := s.pool.get(ctx)
+		if err != nil {
+			return err
+		}
+		err = s.deliver(session.client, to, message.Bytes())
+		s.pool.put(session, err)
+		return err
 	}
 
-	// Standard SMTP (with STARTTLS if supported)
-	err := smtp.SendMail(addr, auth, s.config.SMTPFrom, []string{to}, message.Bytes())
+	client, err := s.dialSMTP(ctx)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrSendFailed, err)
+		return err
 	}
+	defer client.Close()
 
-	return nil
+	if err := s.deliver(client, to, message.Bytes()); err != nil {
+		return err
+	}
+	return client.Quit()
 }
 
-// sendEmailTLS sends email using TLS connection
-func (s *EmailService) sendEmailTLS(addr string, auth smtp.Auth, to string, message []byte) error {
-	tlsConfig := &tls.Config{
-		ServerName: s.config.SMTPHost,
+// tlsConfig returns the TLS configuration used to verify the SMTP server
+func (s *EmailService) tlsConfig() *tls.Config {
+	return &tls.Config{
+		ServerName:         s.config.SMTPHost,
+		RootCAs:            s.config.RootCAs,
+		InsecureSkipVerify: s.config.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
 	}
+}
+
+// dialSMTP opens an authenticated SMTP session. It uses implicit TLS when configured (always on
+// port 465); otherwise the plain connection is upgraded with STARTTLS when the server offers it,
+// and without STARTTLS the session stays in cleartext unless RequireSTARTTLS is set.
+func (s *EmailService) dialSMTP(ctx context.Context) (*smtp.Client, error) {
+	addr := fmt.Sprintf("%s:%d", s.config.SMTPHost, s.config.SMTPPort)
+	implicitTLS := s.config.UseTLS || s.config.SMTPPort == 465
 
-	conn, err := tls.Dial("tcp", addr, tlsConfig)
+	var conn net.Conn
+	var err error
+	if implicitTLS {
+		dialer := &tls.Dialer{
+			NetDialer: &net.Dialer{Timeout: smtpDialTimeout},
+			Config:    s.tlsConfig(),
+		}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		dialer := &net.Dialer{Timeout: smtpDialTimeout}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrSendFailed, err)
+		return nil, fmt.Errorf("%w: %v", ErrSendFailed, err)
 	}
-	defer conn.Close()
 
 	client, err := smtp.NewClient(conn, s.config.SMTPHost)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrSendFailed, err)
+		conn.Close()
+		return nil, fmt.Errorf("%w: %v", ErrSendFailed, err)
 	}
-	defer client.Close()
 
-	if auth != nil {
+	if !implicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(s.tlsConfig()); err != nil {
+				client.Close()
+				return nil, fmt.Errorf("%w: STARTTLS failed: %v", ErrSendFailed, err)
+			}
+		} else if s.config.RequireSTARTTLS {
+			client.Close()
+			return nil, fmt.Errorf("%w: %w", ErrSendFailed, ErrSTARTTLSRequired)
+		}
+	}
+
+	if s.config.SMTPUser != "" && s.config.SMTPPassword != "" {
+		auth := smtp.PlainAuth("", s.config.SMTPUser, s.config.SMTPPassword, s.config.SMTPHost)
 		if err := client.Auth(auth); err != nil {
-			return fmt.Errorf("%w: authentication failed: %v", ErrSendFailed, err)
+			client.Close()
+			return nil, fmt.Errorf("%w: authentication failed: %v", ErrSendFailed, err)
 		}
 	}
 
+	return client, nil
+}
+
+// deliver sends one message over an established SMTP session, leaving the session open
+func (s *EmailService) deliver(client *smtp.Client, to string, message []byte) error {
 	if err := client.Mail(s.config.SMTPFrom); err != nil {
 		return fmt.Errorf("%w: %v", ErrSendFailed, err)
 	}
@@ -226,7 +524,28 @@ func (s *EmailService) sendEmailTLS(addr string, auth smtp.Auth, to string, mess
 		return fmt.Errorf("%w: %v", ErrSendFailed, err)
 	}
 
-	return client.Quit()
+	return nil
+}
+
+// Close ends the pooled SMTP sessions
+func (s *EmailService) Close() {
+	if s.pool != nil {
+		s.pool.close()
+	}
+}
+
+// LoadCACertPool reads a PEM file of CA certificates
+func LoadCACertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
 }
 
 // FormatTimeRemaining formats the remaining time for display
@@ -249,7 +568,7 @@ func FormatTimeRemaining(expiresAt time.Time) string {
 	return fmt.Sprintf("%dmin", minutes)
 }
 
-// obitoNotificationTemplate is the HTML template for obito notification emails
+// obitoNotificationTemplate is the pt-BR HTML template for obito notification emails
 const obitoNotificationTemplate = `<!DOCTYPE html>
 <html lang="pt-BR">
 <head>
@@ -359,7 +678,7 @@ const obitoNotificationTemplate = `<!DOCTYPE html>
 </body>
 </html>`
 
-// infrastructureAlertTemplate is the HTML template for infrastructure alert emails
+// infrastructureAlertTemplate is the pt-BR HTML template for infrastructure alert emails
 const infrastructureAlertTemplate = `<!DOCTYPE html>
 <html lang="pt-BR">
 <head>
@@ -484,3 +803,524 @@ const infrastructureAlertTemplate = `<!DOCTYPE html>
     </table>
 </body>
 </html>`
+
+// infrastructureRecoveryTemplate is the pt-BR HTML template for infrastructure recovery emails
+const infrastructureRecoveryTemplate = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - Servico Restabelecido</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #9ca3af; margin: 5px 0 0 0; font-size: 14px;">Alerta de Infraestrutura</p>
+            </td>
+        </tr>
+
+        <!-- Recovery Banner -->
+        <tr>
+            <td style="background-color: #16A34A; padding: 20px; text-align: center;">
+                <span style="color: #ffffff; font-size: 24px; font-weight: bold;">
+                    SERVICO RESTABELECIDO
+                </span>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <h2 style="color: #1f2937; margin: 0 0 20px 0; font-size: 20px;">
+                    Detalhes da Recuperacao
+                </h2>
+
+                <table width="100%" cellpadding="12" cellspacing="0" style="background-color: #f0fdf4; border: 2px solid #bbf7d0; border-radius: 8px; margin-bottom: 20px;">
+                    <tr>
+                        <td style="border-bottom: 1px solid #bbf7d0; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Servico:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #bbf7d0; color: #166534; font-size: 14px; font-weight: bold;">
+                            {{.ServiceName}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #bbf7d0; color: #6b7280; font-size: 14px;">
+                            <strong>Status Atual:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #bbf7d0; color: #16A34A; font-size: 14px; font-weight: bold;">
+                            {{.Status}}
+                        </td>
+                    </tr>
+                    {{if not .DownSince.IsZero}}
+                    <tr>
+                        <td style="border-bottom: 1px solid #bbf7d0; color: #6b7280; font-size: 14px;">
+                            <strong>Fora do ar desde:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #bbf7d0; color: #1f2937; font-size: 14px;">
+                            {{.DownSince.Format "02/01/2006 15:04:05"}}
+                        </td>
+                    </tr>
+                    {{end}}
+                    <tr>
+                        <td style="color: #6b7280; font-size: 14px;">
+                            <strong>Restabelecido em:</strong>
+                        </td>
+                        <td style="color: #1f2937; font-size: 14px;">
+                            {{.Timestamp.Format "02/01/2006 15:04:05"}}
+                        </td>
+                    </tr>
+                </table>
+
+                {{if .Message}}
+                <div style="background-color: #f0fdf4; border: 1px solid #86efac; border-radius: 8px; padding: 15px; margin-bottom: 20px;">
+                    <p style="color: #166534; font-size: 14px; margin: 0;">
+                        <strong>Mensagem:</strong> {{.Message}}
+                    </p>
+                </div>
+                {{end}}
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.DashboardURL}}" style="display: inline-block; background-color: #1f2937; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        Ver Status do Sistema
+                    </a>
+                </div>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Sistema de Gestao de Doacao de Corneas
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    Alerta automatico de monitoramento de infraestrutura
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+// coverageGapAlertTemplate is the pt-BR HTML template for shift coverage gap alert emails
+const coverageGapAlertTemplate = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - Lacuna de Cobertura</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #9ca3af; margin: 5px 0 0 0; font-size: 14px;">Alerta de Escala</p>
+            </td>
+        </tr>
+
+        <!-- Alert Banner -->
+        <tr>
+            <td style="background-color: #D97706; padding: 20px; text-align: center;">
+                <span style="color: #ffffff; font-size: 24px; font-weight: bold;">
+                    PLANTAO SEM COBERTURA
+                </span>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <h2 style="color: #1f2937; margin: 0 0 20px 0; font-size: 20px;">
+                    {{.HospitalNome}}
+                </h2>
+
+                <table width="100%" cellpadding="12" cellspacing="0" style="background-color: #fffbeb; border: 2px solid #fde68a; border-radius: 8px; margin-bottom: 20px;">
+                    <tr>
+                        <td style="border-bottom: 1px solid #fde68a; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Dia:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fde68a; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.DayName}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #fde68a; color: #6b7280; font-size: 14px;">
+                            <strong>Horario sem operador:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fde68a; color: #92400e; font-size: 14px; font-weight: bold;">
+                            {{.StartTime}} - {{.EndTime}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="color: #6b7280; font-size: 14px;">
+                            <strong>Inicio da lacuna:</strong>
+                        </td>
+                        <td style="color: #1f2937; font-size: 14px;">
+                            {{.StartsAt.Format "02/01/2006 15:04"}}
+                        </td>
+                    </tr>
+                </table>
+
+                <p style="color: #4b5563; font-size: 14px; margin: 0 0 20px 0;">
+                    Nenhum operador esta escalado para este horario. Notificacoes de obitos elegiveis serao encaminhadas aos gestores ate que o plantao seja preenchido.
+                </p>
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.DashboardURL}}" style="display: inline-block; background-color: #1f2937; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        Gerenciar Escalas
+                    </a>
+                </div>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Sistema de Gestao de Doacao de Corneas
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    Alerta automatico de cobertura de escalas
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+// escalationAlertTemplate is the pt-BR HTML template for SLA escalation emails
+const escalationAlertTemplate = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - Ocorrencia Proxima da Expiracao</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #9ca3af; margin: 5px 0 0 0; font-size: 14px;">Escalonamento de Ocorrencia</p>
+            </td>
+        </tr>
+
+        <!-- Alert Banner -->
+        <tr>
+            <td style="background-color: #DC2626; padding: 20px; text-align: center;">
+                <span style="color: #ffffff; font-size: 24px; font-weight: bold;">
+                    OCORRENCIA PENDENTE PROXIMA DA EXPIRACAO
+                </span>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <h2 style="color: #1f2937; margin: 0 0 20px 0; font-size: 20px;">
+                    {{.HospitalNome}}
+                </h2>
+
+                <table width="100%" cellpadding="12" cellspacing="0" style="background-color: #fef2f2; border: 2px solid #fecaca; border-radius: 8px; margin-bottom: 20px;">
+                    {{if .Setor}}
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Setor:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.Setor}}
+                        </td>
+                    </tr>
+                    {{end}}
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Tempo restante:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #991b1b; font-size: 14px; font-weight: bold;">
+                            {{.TempoRestante}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="color: #6b7280; font-size: 14px;">
+                            <strong>Janela expira em:</strong>
+                        </td>
+                        <td style="color: #1f2937; font-size: 14px;">
+                            {{.JanelaExpiraEm.Format "02/01/2006 15:04"}}
+                        </td>
+                    </tr>
+                </table>
+
+                <p style="color: #4b5563; font-size: 14px; margin: 0 0 20px 0;">
+                    Esta ocorrencia ainda esta PENDENTE e a janela de captacao esta terminando. Atribua um operador ou assuma a ocorrencia para nao perder a captacao.
+                </p>
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.DashboardURL}}" style="display: inline-block; background-color: #DC2626; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        Ver Ocorrencia
+                    </a>
+                </div>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Sistema de Gestao de Doacao de Corneas
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    Alerta automatico de escalonamento de SLA
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+// auditAlertTemplate is the pt-BR HTML template for critical audit event emails
+const auditAlertTemplate = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - Evento Critico de Auditoria</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #9ca3af; margin: 5px 0 0 0; font-size: 14px;">Auditoria</p>
+            </td>
+        </tr>
+
+        <!-- Alert Banner -->
+        <tr>
+            <td style="background-color: #DC2626; padding: 20px; text-align: center;">
+                <span style="color: #ffffff; font-size: 24px; font-weight: bold;">
+                    EVENTO CRITICO DE AUDITORIA
+                </span>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <table width="100%" cellpadding="12" cellspacing="0" style="background-color: #fef2f2; border: 2px solid #fecaca; border-radius: 8px; margin-bottom: 20px;">
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Acao:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.Acao}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Severidade:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.Severity}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Realizado por:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.ActorName}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Entidade:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.EntidadeTipo}} {{.EntidadeID}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Data/hora:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.Timestamp.Format "02/01/2006 15:04:05"}}
+                        </td>
+                    </tr>
+                    {{if .IPAddress}}
+                    <tr>
+                        <td style="color: #6b7280; font-size: 14px;">
+                            <strong>Endereco IP:</strong>
+                        </td>
+                        <td style="color: #1f2937; font-size: 14px;">
+                            {{.IPAddress}}
+                        </td>
+                    </tr>
+                    {{end}}
+                </table>
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.DashboardURL}}" style="display: inline-block; background-color: #DC2626; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        Ver Logs de Auditoria
+                    </a>
+                </div>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Sistema de Gestao de Doacao de Corneas
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    Alerta automatico de auditoria
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+// emailVerificationTemplate is the pt-BR HTML template for email verification emails
+const emailVerificationTemplate = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - Confirme seu Email</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #0EA5E9; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #e0f2fe; margin: 5px 0 0 0; font-size: 14px;">Confirmacao de Email</p>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <p style="color: #1f2937; font-size: 16px; margin: 0 0 15px 0;">
+                    Ola, {{.Nome}}.
+                </p>
+                <p style="color: #4b5563; font-size: 14px; margin: 0 0 25px 0;">
+                    Sua conta no SIDOT foi criada. Confirme seu email para acessar o sistema.
+                </p>
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.VerificationURL}}" style="display: inline-block; background-color: #0EA5E9; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        Confirmar Email
+                    </a>
+                </div>
+
+                <p style="color: #6b7280; font-size: 12px; margin: 25px 0 0 0; text-align: center;">
+                    O link expira em {{.ExpiresAt.Format "02/01/2006 15:04"}}.
+                </p>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Sistema de Gestao de Doacao de Corneas
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    Se voce nao esperava este email, ignore-o
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+// welcomeEmailTemplate is the pt-BR HTML template for welcome emails
+const welcomeEmailTemplate = `<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - Acesso ao Sistema</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #0EA5E9; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #e0f2fe; margin: 5px 0 0 0; font-size: 14px;">Acesso ao Sistema</p>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <p style="color: #1f2937; font-size: 16px; margin: 0 0 15px 0;">
+                    Ola, {{.Nome}}.
+                </p>
+                <p style="color: #4b5563; font-size: 14px; margin: 0 0 20px 0;">
+                    Seu acesso ao SIDOT esta pronto. Use os dados abaixo para entrar no sistema.
+                </p>
+
+                <table width="100%" cellpadding="12" cellspacing="0" style="background-color: #f0f9ff; border: 2px solid #bae6fd; border-radius: 8px; margin-bottom: 20px;">
+                    <tr>
+                        <td style="border-bottom: 1px solid #bae6fd; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Email:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #bae6fd; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.Email}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Senha temporaria:</strong>
+                        </td>
+                        <td style="color: #1f2937; font-size: 16px; font-weight: bold; font-family: monospace;">
+                            {{.TemporaryPassword}}
+                        </td>
+                    </tr>
+                </table>
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.LoginURL}}" style="display: inline-block; background-color: #0EA5E9; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        Acessar o SIDOT
+                    </a>
+                </div>
+
+                <p style="color: #6b7280; font-size: 12px; margin: 25px 0 0 0; text-align: center;">
+                    Altere a senha temporaria no primeiro acesso.
+                </p>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Sistema de Gestao de Doacao de Corneas
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    Se voce nao esperava este email, avise o administrador do sistema
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
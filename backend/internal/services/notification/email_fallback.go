@@ -0,0 +1,97 @@
+package notification
+
+import (
+	"sync"
+)
+
+// DefaultEmailFallbackSize is the number of emails held in memory while Redis is unreachable
+const DefaultEmailFallbackSize = 500
+
+// emailFallbackBuffer holds emails in arrival order while the Redis queue is unreachable.
+// An email for an occurrence and recipient already buffered is dropped as a duplicate.
+type emailFallbackBuffer struct {
+	mu       sync.Mutex
+	items    []*EmailQueueItem
+	keys     map[string]struct{}
+	inFlight int // emails taken by Drain and not yet pushed, still counted as buffered
+	draining bool
+	max      int
+}
+
+func newEmailFallbackBuffer(max int) *emailFallbackBuffer {
+	return &emailFallbackBuffer{keys: make(map[string]struct{}), max: max}
+}
+
+// emailDedupKey identifies an email by occurrence and recipient
+func emailDedupKey(item *EmailQueueItem) string {
+	return item.OccurrenceID + "|" + item.To
+}
+
+// Add buffers an email, returning false when it is a duplicate, or ErrQueueFull
+func (b *emailFallbackBuffer) Add(item *EmailQueueItem) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := emailDedupKey(item)
+	if _, exists := b.keys[key]; exists {
+		return false, nil
+	}
+	if len(b.items)+b.inFlight >= b.max {
+		return false, ErrQueueFull
+	}
+
+	b.items = append(b.items, item)
+	b.keys[key] = struct{}{}
+	return true, nil
+}
+
+// Drain passes the buffered emails in order to push, stopping at its first error;
+// the emails not pushed are put back ahead of those buffered meanwhile. It returns the number of emails pushed.
+// The buffer is unlocked while pushing, so Add does not wait on Redis; emails added meanwhile are pushed
+// by the same Drain, and a concurrent Drain returns right away so the order is kept.
+func (b *emailFallbackBuffer) Drain(push func(*EmailQueueItem) error) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.draining {
+		return 0, nil
+	}
+	b.draining = true
+	defer func() { b.draining = false }()
+
+	pushed := 0
+	for len(b.items) > 0 {
+		items := b.items
+		b.items = nil
+		b.inFlight = len(items)
+		b.mu.Unlock()
+
+		n := 0
+		var err error
+		for _, item := range items {
+			if err = push(item); err != nil {
+				break
+			}
+			n++
+		}
+
+		b.mu.Lock()
+		b.inFlight = 0
+		for _, item := range items[:n] {
+			delete(b.keys, emailDedupKey(item))
+		}
+		pushed += n
+		if err != nil {
+			b.items = append(items[n:len(items):len(items)], b.items...)
+			return pushed, err
+		}
+	}
+	return pushed, nil
+}
+
+// Len returns the number of buffered emails, including those being pushed by Drain
+func (b *emailFallbackBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.items) + b.inFlight
+}
@@ -0,0 +1,849 @@
+package notification
+
+import (
+	"fmt"
+
+	"github.com/sidot/backend/internal/models"
+)
+
+// localizedText holds a text in each supported locale
+type localizedText map[string]string
+
+// get returns the text in locale, falling back to the default locale for unsupported ones
+func (t localizedText) get(locale string) string {
+	return t[models.NormalizeLocale(locale)]
+}
+
+// Email subjects, formatted with the same arguments in every locale
+var (
+	obitoNotificationSubjects = localizedText{
+		models.LocalePtBR: "[URGENTE] Nova Ocorrencia Elegivel - %s",
+		models.LocaleEn:   "[URGENT] New Eligible Occurrence - %s",
+	}
+	infrastructureAlertSubjects = localizedText{
+		models.LocalePtBR: "[ALERTA] %s - %s",
+		models.LocaleEn:   "[ALERT] %s - %s",
+	}
+	infrastructureRecoverySubjects = localizedText{
+		models.LocalePtBR: "[RESTABELECIDO] %s - %s",
+		models.LocaleEn:   "[RESTORED] %s - %s",
+	}
+	coverageGapAlertSubjects = localizedText{
+		models.LocalePtBR: "[ESCALA] Lacuna de cobertura - %s - %s %s",
+		models.LocaleEn:   "[SHIFTS] Coverage gap - %s - %s %s",
+	}
+	escalationAlertSubjects = localizedText{
+		models.LocalePtBR: "[ESCALONAMENTO] Ocorrencia pendente - %s - expira em %s",
+		models.LocaleEn:   "[ESCALATION] Pending occurrence - %s - expires in %s",
+	}
+	auditAlertSubjects = localizedText{
+		models.LocalePtBR: "[AUDITORIA] Evento %s - %s",
+		models.LocaleEn:   "[AUDIT] %s event - %s",
+	}
+	emailVerificationSubjects = localizedText{
+		models.LocalePtBR: "SIDOT - Confirme seu email",
+		models.LocaleEn:   "SIDOT - Confirm your email",
+	}
+	welcomeEmailSubjects = localizedText{
+		models.LocalePtBR: "SIDOT - Seu acesso ao sistema",
+		models.LocaleEn:   "SIDOT - Your system access",
+	}
+)
+
+// Email HTML templates of each supported locale
+var (
+	obitoNotificationTemplates = localizedText{
+		models.LocalePtBR: obitoNotificationTemplate,
+		models.LocaleEn:   obitoNotificationTemplateEn,
+	}
+	infrastructureAlertTemplates = localizedText{
+		models.LocalePtBR: infrastructureAlertTemplate,
+		models.LocaleEn:   infrastructureAlertTemplateEn,
+	}
+	infrastructureRecoveryTemplates = localizedText{
+		models.LocalePtBR: infrastructureRecoveryTemplate,
+		models.LocaleEn:   infrastructureRecoveryTemplateEn,
+	}
+	coverageGapAlertTemplates = localizedText{
+		models.LocalePtBR: coverageGapAlertTemplate,
+		models.LocaleEn:   coverageGapAlertTemplateEn,
+	}
+	escalationAlertTemplates = localizedText{
+		models.LocalePtBR: escalationAlertTemplate,
+		models.LocaleEn:   escalationAlertTemplateEn,
+	}
+	auditAlertTemplates = localizedText{
+		models.LocalePtBR: auditAlertTemplate,
+		models.LocaleEn:   auditAlertTemplateEn,
+	}
+	emailVerificationTemplates = localizedText{
+		models.LocalePtBR: emailVerificationTemplate,
+		models.LocaleEn:   emailVerificationTemplateEn,
+	}
+	welcomeEmailTemplates = localizedText{
+		models.LocalePtBR: welcomeEmailTemplate,
+		models.LocaleEn:   welcomeEmailTemplateEn,
+	}
+)
+
+// obitoNotificationSubject returns the subject of an obito notification email in the recipient locale
+func obitoNotificationSubject(data *ObitoNotificationData) string {
+	return fmt.Sprintf(obitoNotificationSubjects.get(data.Locale), data.HospitalNome)
+}
+
+// obitoNotificationTemplateEn is the en HTML template for obito notification emails
+const obitoNotificationTemplateEn = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - New Occurrence</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #0EA5E9; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #e0f2fe; margin: 5px 0 0 0; font-size: 14px;">Death Notification System</p>
+            </td>
+        </tr>
+
+        <!-- Alert Banner -->
+        <tr>
+            <td style="background-color: #EF4444; padding: 15px; text-align: center;">
+                <span style="color: #ffffff; font-size: 18px; font-weight: bold;">
+                    NEW ELIGIBLE OCCURRENCE
+                </span>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <h2 style="color: #1f2937; margin: 0 0 20px 0; font-size: 20px;">
+                    Occurrence Details
+                </h2>
+
+                <table width="100%" cellpadding="10" cellspacing="0" style="background-color: #f9fafb; border-radius: 8px; margin-bottom: 20px;">
+                    <tr>
+                        <td style="border-bottom: 1px solid #e5e7eb; color: #6b7280; font-size: 14px;">
+                            <strong>Hospital:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #e5e7eb; color: #1f2937; font-size: 14px;">
+                            {{.HospitalNome}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #e5e7eb; color: #6b7280; font-size: 14px;">
+                            <strong>Ward:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #e5e7eb; color: #1f2937; font-size: 14px;">
+                            {{.Setor}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #e5e7eb; color: #6b7280; font-size: 14px;">
+                            <strong>Time of Death:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #e5e7eb; color: #1f2937; font-size: 14px;">
+                            {{.HoraObito.Format "Jan 02, 2006 15:04"}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="color: #6b7280; font-size: 14px;">
+                            <strong>Time Remaining:</strong>
+                        </td>
+                        <td style="color: #EF4444; font-size: 14px; font-weight: bold;">
+                            {{.TempoRestante}}
+                        </td>
+                    </tr>
+                </table>
+
+                <!-- Priority Badge -->
+                <div style="text-align: center; margin-bottom: 20px;">
+                    <span style="background-color: {{if ge .Prioridade 80}}#EF4444{{else if ge .Prioridade 60}}#F59E0B{{else}}#10B981{{end}}; color: #ffffff; padding: 8px 16px; border-radius: 20px; font-size: 12px; font-weight: bold;">
+                        PRIORITY: {{.Prioridade}}
+                    </span>
+                </div>
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.DashboardURL}}" style="display: inline-block; background-color: #0EA5E9; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        Open Dashboard
+                    </a>
+                </div>
+            </td>
+        </tr>
+
+        <!-- Warning -->
+        <tr>
+            <td style="padding: 20px 30px; background-color: #fef3c7;">
+                <p style="color: #92400e; font-size: 13px; margin: 0; text-align: center;">
+                    <strong>ATTENTION:</strong> The cornea retrieval window is 6 hours after death.
+                    Immediate action is required.
+                </p>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #f3f4f6; padding: 20px; text-align: center;">
+                <p style="color: #6b7280; font-size: 12px; margin: 0;">
+                    SIDOT - Cornea Donation Management System
+                </p>
+                <p style="color: #9ca3af; font-size: 11px; margin: 10px 0 0 0;">
+                    This is an automated message. Please do not reply directly.
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+// infrastructureAlertTemplateEn is the en HTML template for infrastructure alert emails
+const infrastructureAlertTemplateEn = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - Infrastructure Alert</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #9ca3af; margin: 5px 0 0 0; font-size: 14px;">Infrastructure Alert</p>
+            </td>
+        </tr>
+
+        <!-- Alert Banner -->
+        <tr>
+            <td style="background-color: #DC2626; padding: 20px; text-align: center;">
+                <span style="color: #ffffff; font-size: 24px; font-weight: bold;">
+                    SERVICE DOWN
+                </span>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <h2 style="color: #1f2937; margin: 0 0 20px 0; font-size: 20px;">
+                    Alert Details
+                </h2>
+
+                <table width="100%" cellpadding="12" cellspacing="0" style="background-color: #fef2f2; border: 2px solid #fecaca; border-radius: 8px; margin-bottom: 20px;">
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Service:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #991b1b; font-size: 14px; font-weight: bold;">
+                            {{.ServiceName}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px;">
+                            <strong>Current Status:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #DC2626; font-size: 14px; font-weight: bold;">
+                            {{.Status}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px;">
+                            <strong>Previous Status:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px;">
+                            {{.PreviousStatus}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="color: #6b7280; font-size: 14px;">
+                            <strong>Detected at:</strong>
+                        </td>
+                        <td style="color: #1f2937; font-size: 14px;">
+                            {{.Timestamp.Format "Jan 02, 2006 15:04:05"}}
+                        </td>
+                    </tr>
+                </table>
+
+                {{if .Message}}
+                <div style="background-color: #fef3c7; border: 1px solid #fcd34d; border-radius: 8px; padding: 15px; margin-bottom: 20px;">
+                    <p style="color: #92400e; font-size: 14px; margin: 0;">
+                        <strong>Message:</strong> {{.Message}}
+                    </p>
+                </div>
+                {{end}}
+
+                <!-- Action Required -->
+                <div style="background-color: #fee2e2; border-radius: 8px; padding: 20px; margin-bottom: 20px; text-align: center;">
+                    <p style="color: #991b1b; font-size: 16px; font-weight: bold; margin: 0 0 10px 0;">
+                        IMMEDIATE ACTION REQUIRED
+                    </p>
+                    <p style="color: #7f1d1d; font-size: 14px; margin: 0;">
+                        Check the service and take the corrective actions needed to restore normal operation.
+                    </p>
+                </div>
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.DashboardURL}}" style="display: inline-block; background-color: #1f2937; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        View System Status
+                    </a>
+                </div>
+            </td>
+        </tr>
+
+        <!-- Recommended Actions -->
+        <tr>
+            <td style="padding: 0 30px 30px 30px;">
+                <h3 style="color: #1f2937; margin: 0 0 15px 0; font-size: 16px;">
+                    Recommended Actions:
+                </h3>
+                <ol style="color: #4b5563; font-size: 14px; margin: 0; padding-left: 20px;">
+                    <li style="margin-bottom: 8px;">Check the service logs on the server</li>
+                    <li style="margin-bottom: 8px;">Confirm network and database connectivity</li>
+                    <li style="margin-bottom: 8px;">Restart the service if needed</li>
+                    <li style="margin-bottom: 8px;">Monitor the recovery on the status dashboard</li>
+                </ol>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Cornea Donation Management System
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    Automatic infrastructure monitoring alert
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+// infrastructureRecoveryTemplateEn is the en HTML template for infrastructure recovery emails
+const infrastructureRecoveryTemplateEn = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - Service Restored</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #9ca3af; margin: 5px 0 0 0; font-size: 14px;">Infrastructure Alert</p>
+            </td>
+        </tr>
+
+        <!-- Recovery Banner -->
+        <tr>
+            <td style="background-color: #16A34A; padding: 20px; text-align: center;">
+                <span style="color: #ffffff; font-size: 24px; font-weight: bold;">
+                    SERVICE RESTORED
+                </span>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <h2 style="color: #1f2937; margin: 0 0 20px 0; font-size: 20px;">
+                    Recovery Details
+                </h2>
+
+                <table width="100%" cellpadding="12" cellspacing="0" style="background-color: #f0fdf4; border: 2px solid #bbf7d0; border-radius: 8px; margin-bottom: 20px;">
+                    <tr>
+                        <td style="border-bottom: 1px solid #bbf7d0; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Service:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #bbf7d0; color: #166534; font-size: 14px; font-weight: bold;">
+                            {{.ServiceName}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #bbf7d0; color: #6b7280; font-size: 14px;">
+                            <strong>Current Status:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #bbf7d0; color: #16A34A; font-size: 14px; font-weight: bold;">
+                            {{.Status}}
+                        </td>
+                    </tr>
+                    {{if not .DownSince.IsZero}}
+                    <tr>
+                        <td style="border-bottom: 1px solid #bbf7d0; color: #6b7280; font-size: 14px;">
+                            <strong>Down since:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #bbf7d0; color: #1f2937; font-size: 14px;">
+                            {{.DownSince.Format "Jan 02, 2006 15:04:05"}}
+                        </td>
+                    </tr>
+                    {{end}}
+                    <tr>
+                        <td style="color: #6b7280; font-size: 14px;">
+                            <strong>Restored at:</strong>
+                        </td>
+                        <td style="color: #1f2937; font-size: 14px;">
+                            {{.Timestamp.Format "Jan 02, 2006 15:04:05"}}
+                        </td>
+                    </tr>
+                </table>
+
+                {{if .Message}}
+                <div style="background-color: #f0fdf4; border: 1px solid #86efac; border-radius: 8px; padding: 15px; margin-bottom: 20px;">
+                    <p style="color: #166534; font-size: 14px; margin: 0;">
+                        <strong>Message:</strong> {{.Message}}
+                    </p>
+                </div>
+                {{end}}
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.DashboardURL}}" style="display: inline-block; background-color: #1f2937; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        View System Status
+                    </a>
+                </div>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Cornea Donation Management System
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    Automatic infrastructure monitoring alert
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+// coverageGapAlertTemplateEn is the en HTML template for shift coverage gap alert emails
+const coverageGapAlertTemplateEn = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - Coverage Gap</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #9ca3af; margin: 5px 0 0 0; font-size: 14px;">Shift Alert</p>
+            </td>
+        </tr>
+
+        <!-- Alert Banner -->
+        <tr>
+            <td style="background-color: #D97706; padding: 20px; text-align: center;">
+                <span style="color: #ffffff; font-size: 24px; font-weight: bold;">
+                    UNCOVERED SHIFT
+                </span>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <h2 style="color: #1f2937; margin: 0 0 20px 0; font-size: 20px;">
+                    {{.HospitalNome}}
+                </h2>
+
+                <table width="100%" cellpadding="12" cellspacing="0" style="background-color: #fffbeb; border: 2px solid #fde68a; border-radius: 8px; margin-bottom: 20px;">
+                    <tr>
+                        <td style="border-bottom: 1px solid #fde68a; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Day:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fde68a; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.DayName}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #fde68a; color: #6b7280; font-size: 14px;">
+                            <strong>Hours without an operator:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fde68a; color: #92400e; font-size: 14px; font-weight: bold;">
+                            {{.StartTime}} - {{.EndTime}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="color: #6b7280; font-size: 14px;">
+                            <strong>Gap starts at:</strong>
+                        </td>
+                        <td style="color: #1f2937; font-size: 14px;">
+                            {{.StartsAt.Format "Jan 02, 2006 15:04"}}
+                        </td>
+                    </tr>
+                </table>
+
+                <p style="color: #4b5563; font-size: 14px; margin: 0 0 20px 0;">
+                    No operator is scheduled for these hours. Notifications of eligible deaths will be forwarded to the managers until the shift is filled.
+                </p>
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.DashboardURL}}" style="display: inline-block; background-color: #1f2937; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        Manage Shifts
+                    </a>
+                </div>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Cornea Donation Management System
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    Automatic shift coverage alert
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+// escalationAlertTemplateEn is the en HTML template for SLA escalation emails
+const escalationAlertTemplateEn = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - Occurrence Close to Expiry</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #9ca3af; margin: 5px 0 0 0; font-size: 14px;">Occurrence Escalation</p>
+            </td>
+        </tr>
+
+        <!-- Alert Banner -->
+        <tr>
+            <td style="background-color: #DC2626; padding: 20px; text-align: center;">
+                <span style="color: #ffffff; font-size: 24px; font-weight: bold;">
+                    PENDING OCCURRENCE CLOSE TO EXPIRY
+                </span>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <h2 style="color: #1f2937; margin: 0 0 20px 0; font-size: 20px;">
+                    {{.HospitalNome}}
+                </h2>
+
+                <table width="100%" cellpadding="12" cellspacing="0" style="background-color: #fef2f2; border: 2px solid #fecaca; border-radius: 8px; margin-bottom: 20px;">
+                    {{if .Setor}}
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Ward:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.Setor}}
+                        </td>
+                    </tr>
+                    {{end}}
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Time remaining:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #991b1b; font-size: 14px; font-weight: bold;">
+                            {{.TempoRestante}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="color: #6b7280; font-size: 14px;">
+                            <strong>Window expires at:</strong>
+                        </td>
+                        <td style="color: #1f2937; font-size: 14px;">
+                            {{.JanelaExpiraEm.Format "Jan 02, 2006 15:04"}}
+                        </td>
+                    </tr>
+                </table>
+
+                <p style="color: #4b5563; font-size: 14px; margin: 0 0 20px 0;">
+                    This occurrence is still PENDING and its retrieval window is closing. Assign an operator or take the occurrence so the retrieval is not lost.
+                </p>
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.DashboardURL}}" style="display: inline-block; background-color: #DC2626; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        View Occurrence
+                    </a>
+                </div>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Cornea Donation Management System
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    Automatic SLA escalation alert
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+// auditAlertTemplateEn is the en HTML template for critical audit event emails
+const auditAlertTemplateEn = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - Critical Audit Event</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #9ca3af; margin: 5px 0 0 0; font-size: 14px;">Audit</p>
+            </td>
+        </tr>
+
+        <!-- Alert Banner -->
+        <tr>
+            <td style="background-color: #DC2626; padding: 20px; text-align: center;">
+                <span style="color: #ffffff; font-size: 24px; font-weight: bold;">
+                    CRITICAL AUDIT EVENT
+                </span>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <table width="100%" cellpadding="12" cellspacing="0" style="background-color: #fef2f2; border: 2px solid #fecaca; border-radius: 8px; margin-bottom: 20px;">
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Action:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.Acao}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Severity:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.Severity}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Performed by:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.ActorName}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Entity:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.EntidadeTipo}} {{.EntidadeID}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="border-bottom: 1px solid #fecaca; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Date/time:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #fecaca; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.Timestamp.Format "02/01/2006 15:04:05"}}
+                        </td>
+                    </tr>
+                    {{if .IPAddress}}
+                    <tr>
+                        <td style="color: #6b7280; font-size: 14px;">
+                            <strong>IP address:</strong>
+                        </td>
+                        <td style="color: #1f2937; font-size: 14px;">
+                            {{.IPAddress}}
+                        </td>
+                    </tr>
+                    {{end}}
+                </table>
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.DashboardURL}}" style="display: inline-block; background-color: #DC2626; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        View Audit Logs
+                    </a>
+                </div>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Cornea Donation Management System
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    Automatic audit alert
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+// emailVerificationTemplateEn is the en HTML template for email verification emails
+const emailVerificationTemplateEn = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - Confirm your Email</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #0EA5E9; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #e0f2fe; margin: 5px 0 0 0; font-size: 14px;">Email Confirmation</p>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <p style="color: #1f2937; font-size: 16px; margin: 0 0 15px 0;">
+                    Hello, {{.Nome}}.
+                </p>
+                <p style="color: #4b5563; font-size: 14px; margin: 0 0 25px 0;">
+                    Your SIDOT account was created. Confirm your email to access the system.
+                </p>
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.VerificationURL}}" style="display: inline-block; background-color: #0EA5E9; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        Confirm Email
+                    </a>
+                </div>
+
+                <p style="color: #6b7280; font-size: 12px; margin: 25px 0 0 0; text-align: center;">
+                    The link expires on {{.ExpiresAt.Format "01/02/2006 15:04"}}.
+                </p>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Cornea Donation Management System
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    If you did not expect this email, ignore it
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+// welcomeEmailTemplateEn is the en HTML template for welcome emails
+const welcomeEmailTemplateEn = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>SIDOT - System Access</title>
+</head>
+<body style="font-family: 'Segoe UI', Arial, sans-serif; margin: 0; padding: 0; background-color: #f3f4f6;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; margin: 0 auto; background-color: #ffffff;">
+        <!-- Header -->
+        <tr>
+            <td style="background-color: #0EA5E9; padding: 20px; text-align: center;">
+                <h1 style="color: #ffffff; margin: 0; font-size: 24px;">SIDOT</h1>
+                <p style="color: #e0f2fe; margin: 5px 0 0 0; font-size: 14px;">System Access</p>
+            </td>
+        </tr>
+
+        <!-- Content -->
+        <tr>
+            <td style="padding: 30px;">
+                <p style="color: #1f2937; font-size: 16px; margin: 0 0 15px 0;">
+                    Hello, {{.Nome}}.
+                </p>
+                <p style="color: #4b5563; font-size: 14px; margin: 0 0 20px 0;">
+                    Your SIDOT access is ready. Use the details below to sign in.
+                </p>
+
+                <table width="100%" cellpadding="12" cellspacing="0" style="background-color: #f0f9ff; border: 2px solid #bae6fd; border-radius: 8px; margin-bottom: 20px;">
+                    <tr>
+                        <td style="border-bottom: 1px solid #bae6fd; color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Email:</strong>
+                        </td>
+                        <td style="border-bottom: 1px solid #bae6fd; color: #1f2937; font-size: 14px; font-weight: bold;">
+                            {{.Email}}
+                        </td>
+                    </tr>
+                    <tr>
+                        <td style="color: #6b7280; font-size: 14px; width: 40%;">
+                            <strong>Temporary password:</strong>
+                        </td>
+                        <td style="color: #1f2937; font-size: 16px; font-weight: bold; font-family: monospace;">
+                            {{.TemporaryPassword}}
+                        </td>
+                    </tr>
+                </table>
+
+                <!-- CTA Button -->
+                <div style="text-align: center;">
+                    <a href="{{.LoginURL}}" style="display: inline-block; background-color: #0EA5E9; color: #ffffff; text-decoration: none; padding: 14px 28px; border-radius: 8px; font-size: 16px; font-weight: bold;">
+                        Open SIDOT
+                    </a>
+                </div>
+
+                <p style="color: #6b7280; font-size: 12px; margin: 25px 0 0 0; text-align: center;">
+                    Change the temporary password when you first sign in.
+                </p>
+            </td>
+        </tr>
+
+        <!-- Footer -->
+        <tr>
+            <td style="background-color: #1f2937; padding: 20px; text-align: center;">
+                <p style="color: #9ca3af; font-size: 12px; margin: 0;">
+                    SIDOT - Cornea Donation Management System
+                </p>
+                <p style="color: #6b7280; font-size: 11px; margin: 10px 0 0 0;">
+                    If you did not expect this email, let the system administrator know
+                </p>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>`
@@ -0,0 +1,157 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sidot/backend/internal/models"
+)
+
+// assertContainsAll fails for every expected string missing from body
+func assertContainsAll(t *testing.T, body string, expected ...string) {
+	t.Helper()
+	for _, want := range expected {
+		if !strings.Contains(body, want) {
+			t.Errorf("Email body should contain %q", want)
+		}
+	}
+}
+
+// assertContainsNone fails for every unexpected string found in body
+func assertContainsNone(t *testing.T, body string, unexpected ...string) {
+	t.Helper()
+	for _, unwanted := range unexpected {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("Email body should not contain %q", unwanted)
+		}
+	}
+}
+
+// TestObitoTemplateLocales tests that the obito notification renders in the recipient locale
+func TestObitoTemplateLocales(t *testing.T) {
+	service := NewEmailService(&EmailConfig{})
+	data := &ObitoNotificationData{
+		HospitalNome:  "Hospital de Urgencias de Goias",
+		Setor:         "UTI",
+		HoraObito:     time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC),
+		TempoRestante: "5h 30min",
+		Prioridade:    90,
+		DashboardURL:  "http://localhost:3000/dashboard",
+	}
+
+	body, err := service.renderObitoTemplate(data)
+	if err != nil {
+		t.Fatalf("Failed to render pt-BR template: %v", err)
+	}
+	assertContainsAll(t, body, `lang="pt-BR"`, "NOVA OCORRENCIA ELEGIVEL", "Hora do Obito", "14/10/2026 09:30", data.HospitalNome)
+	if subject := obitoNotificationSubject(data); subject != "[URGENTE] Nova Ocorrencia Elegivel - "+data.HospitalNome {
+		t.Errorf("Unexpected pt-BR subject %q", subject)
+	}
+
+	data.Locale = models.LocaleEn
+	body, err = service.renderObitoTemplate(data)
+	if err != nil {
+		t.Fatalf("Failed to render en template: %v", err)
+	}
+	assertContainsAll(t, body, `lang="en"`, "NEW ELIGIBLE OCCURRENCE", "Time of Death", "Oct 14, 2026 09:30", "PRIORITY: 90", data.HospitalNome, data.TempoRestante)
+	assertContainsNone(t, body, "NOVA OCORRENCIA ELEGIVEL", "Acessar Dashboard")
+	if subject := obitoNotificationSubject(data); subject != "[URGENT] New Eligible Occurrence - "+data.HospitalNome {
+		t.Errorf("Unexpected en subject %q", subject)
+	}
+}
+
+// TestAlertTemplateLocales tests that every alert email renders in both locales
+func TestAlertTemplateLocales(t *testing.T) {
+	service := NewEmailService(&EmailConfig{})
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		render func(locale string) (string, error)
+		ptBR   string
+		en     string
+	}{
+		{
+			name: "infrastructure alert",
+			render: func(locale string) (string, error) {
+				return service.renderInfrastructureAlertTemplate(&InfrastructureAlertData{ServiceName: "Redis", Status: "DOWN", Timestamp: now, Locale: locale})
+			},
+			ptBR: "SERVICO FORA DO AR",
+			en:   "SERVICE DOWN",
+		},
+		{
+			name: "infrastructure recovery",
+			render: func(locale string) (string, error) {
+				return service.renderInfrastructureRecoveryTemplate(&InfrastructureAlertData{ServiceName: "Redis", Status: "UP", Timestamp: now, Locale: locale})
+			},
+			ptBR: "SERVICO RESTABELECIDO",
+			en:   "SERVICE RESTORED",
+		},
+		{
+			name: "coverage gap alert",
+			render: func(locale string) (string, error) {
+				return service.renderCoverageGapAlertTemplate(&CoverageGapAlertData{HospitalNome: "HUGO", StartsAt: now, Locale: locale})
+			},
+			ptBR: "PLANTAO SEM COBERTURA",
+			en:   "UNCOVERED SHIFT",
+		},
+		{
+			name: "escalation alert",
+			render: func(locale string) (string, error) {
+				return service.renderEscalationAlertTemplate(&EscalationAlertData{HospitalNome: "HUGO", JanelaExpiraEm: now, Locale: locale})
+			},
+			ptBR: "OCORRENCIA PENDENTE PROXIMA DA EXPIRACAO",
+			en:   "PENDING OCCURRENCE CLOSE TO EXPIRY",
+		},
+		{
+			name: "audit alert",
+			render: func(locale string) (string, error) {
+				return service.renderAuditAlertTemplate(&AuditAlertData{Acao: "admin.user.ban", Severity: "WARN", Timestamp: now, Locale: locale})
+			},
+			ptBR: "EVENTO CRITICO DE AUDITORIA",
+			en:   "CRITICAL AUDIT EVENT",
+		},
+		{
+			name: "welcome email",
+			render: func(locale string) (string, error) {
+				return service.renderWelcomeEmailTemplate(&WelcomeEmailData{Nome: "Maria", Email: "maria@sidot.gov.br", TemporaryPassword: "Tmp#Senha2026", Locale: locale})
+			},
+			ptBR: "Senha temporaria",
+			en:   "Temporary password",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := tt.render(models.LocalePtBR)
+			if err != nil {
+				t.Fatalf("Failed to render pt-BR template: %v", err)
+			}
+			assertContainsAll(t, body, `lang="pt-BR"`, tt.ptBR, "Sistema de Gestao de Doacao de Corneas")
+
+			body, err = tt.render(models.LocaleEn)
+			if err != nil {
+				t.Fatalf("Failed to render en template: %v", err)
+			}
+			assertContainsAll(t, body, `lang="en"`, tt.en, "Cornea Donation Management System")
+			assertContainsNone(t, body, tt.ptBR)
+		})
+	}
+}
+
+// TestUnsupportedLocaleFallsBack tests that an unsupported locale renders the pt-BR template
+func TestUnsupportedLocaleFallsBack(t *testing.T) {
+	service := NewEmailService(&EmailConfig{})
+
+	body, err := service.renderCoverageGapAlertTemplate(&CoverageGapAlertData{HospitalNome: "HUGO", Locale: "fr"})
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+	assertContainsAll(t, body, `lang="pt-BR"`, "PLANTAO SEM COBERTURA")
+
+	if subject := fmt.Sprintf(escalationAlertSubjects.get("en-US"), "HUGO", "30min"); subject != "[ESCALATION] Pending occurrence - HUGO - expires in 30min" {
+		t.Errorf("Unexpected subject %q", subject)
+	}
+}
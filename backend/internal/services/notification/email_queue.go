@@ -7,6 +7,7 @@ import (
 	"errors"
 	"log"
 	"math"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,11 +30,32 @@ const (
 
 	// BaseBackoffDelay is the base delay for exponential backoff
 	BaseBackoffDelay = 1 * time.Second
+
+	// EmailDedupKeyPrefix prefixes the Redis keys that suppress duplicate emails
+	EmailDedupKeyPrefix = "sidot:email_dedup:"
+
+	// DefaultEmailDedupWindow is how long an occurrence email is not sent again to the same recipient
+	DefaultEmailDedupWindow = 10 * time.Minute
+
+	// DefaultEmailDrainTimeout bounds how long Stop waits for the email being sent and the
+	// fallback buffer to be moved to Redis
+	DefaultEmailDrainTimeout = 10 * time.Second
+
+	// emailDrainRetryInterval is the delay between attempts to move the fallback buffer to Redis on Stop
+	emailDrainRetryInterval = 200 * time.Millisecond
 )
 
 var (
 	ErrQueueFull      = errors.New("email queue is full")
 	ErrInvalidPayload = errors.New("invalid email payload")
+
+	// ErrEmailSuppressed is returned by EnqueueEmail when the recipient was already sent
+	// this occurrence's notification within the dedup window
+	ErrEmailSuppressed = errors.New("duplicate email suppressed")
+
+	// ErrEmailWorkerStopping is returned by EnqueueEmail when Redis is unreachable while the worker
+	// is stopping, since an email buffered in memory then would be lost on exit
+	ErrEmailWorkerStopping = errors.New("email queue worker is stopping")
 )
 
 // EmailQueueItem represents an item in the email queue
@@ -50,11 +72,34 @@ type EmailQueueItem struct {
 	Error         string                 `json:"error,omitempty"`
 }
 
+// emailQueueStore holds the Redis email queue and dedup keys (implemented by *redis.Client)
+type emailQueueStore interface {
+	LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+	LRem(ctx context.Context, key string, count int64, value interface{}) *redis.IntCmd
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+// emailNotificationRecorder records sent and failed emails as notifications
+// (implemented by repository.NotificationRepository)
+type emailNotificationRecorder interface {
+	CreateNotificationFromEmail(ctx context.Context, occurrenceID uuid.UUID, userID *uuid.UUID, metadata *models.NotificationMetadata, status models.NotificationStatus, errorMsg *string) (*models.Notification, error)
+}
+
+// emailDeliveryRecorder records the delivery status of queued emails
+// (implemented by repository.EmailDeliveryRepository)
+type emailDeliveryRecorder interface {
+	Save(ctx context.Context, delivery *models.EmailDelivery) error
+}
+
 // EmailQueueWorker processes emails from the queue
 type EmailQueueWorker struct {
 	redis            *redis.Client
+	queue            emailQueueStore
+	fallback         *emailFallbackBuffer
 	emailService     *EmailService
-	notificationRepo *repository.NotificationRepository
+	notificationRepo emailNotificationRecorder
+	deliveries       emailDeliveryRecorder
 
 	// Status tracking
 	running         int32
@@ -68,25 +113,37 @@ type EmailQueueWorker struct {
 	doneCh chan struct{}
 	wg     sync.WaitGroup
 
+	// Draining: stopping rejects new emails that would only be buffered in memory, cancelWork
+	// aborts the email being sent after drainTimeout
+	stopping     int32
+	cancelWork   context.CancelFunc
+	drainTimeout time.Duration
+
 	// Logger
 	logger *log.Logger
 
 	// Configuration
 	pollInterval time.Duration
 	batchSize    int
+	dedupWindow  time.Duration // 0 disables the suppression of duplicate emails
 }
 
 // NewEmailQueueWorker creates a new EmailQueueWorker
 func NewEmailQueueWorker(redisClient *redis.Client, emailService *EmailService, db *sql.DB) *EmailQueueWorker {
 	return &EmailQueueWorker{
 		redis:            redisClient,
+		queue:            redisClient,
+		fallback:         newEmailFallbackBuffer(DefaultEmailFallbackSize),
 		emailService:     emailService,
 		notificationRepo: repository.NewNotificationRepository(db),
+		deliveries:       repository.NewEmailDeliveryRepository(db),
 		stopCh:           make(chan struct{}),
 		doneCh:           make(chan struct{}),
 		logger:           log.Default(),
 		pollInterval:     5 * time.Second,
 		batchSize:        10,
+		dedupWindow:      DefaultEmailDedupWindow,
+		drainTimeout:     DefaultEmailDrainTimeout,
 	}
 }
 
@@ -98,16 +155,39 @@ func (w *EmailQueueWorker) Start(ctx context.Context) error {
 
 	w.logger.Println("[EmailQueue] Starting email queue worker")
 
-	go w.processLoop(ctx)
+	// The email being sent is not aborted by cancelling ctx; Stop bounds it instead
+	workCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	w.cancelWork = cancel
+
+	go w.processLoop(ctx, workCtx)
 
 	return nil
 }
 
-// Stop stops the worker loop
+// Stop stops taking emails from the queue, then waits up to the drain timeout for the email
+// being sent and for the fallback buffer to be moved to Redis. An email whose send is aborted
+// by the timeout is kept in the Redis processing list rather than dropped.
 func (w *EmailQueueWorker) Stop() {
 	if atomic.CompareAndSwapInt32(&w.running, 1, 0) {
+		atomic.StoreInt32(&w.stopping, 1)
 		close(w.stopCh)
-		<-w.doneCh
+
+		ctx, cancel := context.WithTimeout(context.Background(), w.drainTimeout)
+		defer cancel()
+
+		select {
+		case <-w.doneCh:
+		case <-ctx.Done():
+			w.logger.Printf("[EmailQueue] Warning: email in flight not sent within %s, aborting it", w.drainTimeout)
+			w.cancelWork()
+			<-w.doneCh
+		}
+		w.cancelWork()
+
+		w.drainFallbackUntil(ctx)
+		if buffered := w.fallback.Len(); buffered > 0 {
+			w.logger.Printf("[EmailQueue] Warning: %d buffered email(s) not delivered to Redis before shutdown", buffered)
+		}
 		w.logger.Println("[EmailQueue] Email queue worker stopped")
 	}
 }
@@ -117,7 +197,9 @@ func (w *EmailQueueWorker) IsRunning() bool {
 	return atomic.LoadInt32(&w.running) == 1
 }
 
-// EnqueueEmail adds an email to the queue
+// EnqueueEmail adds an email to the queue. It returns ErrEmailSuppressed, without queueing,
+// when the same occurrence was already queued for the recipient within the dedup window
+// (e.g. after a retriage).
 func (w *EmailQueueWorker) EnqueueEmail(ctx context.Context, occurrenceID uuid.UUID, to string, userID *uuid.UUID, data *ObitoNotificationData) error {
 	item := &EmailQueueItem{
 		ID:           uuid.New().String(),
@@ -133,16 +215,133 @@ func (w *EmailQueueWorker) EnqueueEmail(ctx context.Context, occurrenceID uuid.U
 		item.UserID = &userIDStr
 	}
 
+	if w.claimDedup(ctx, item) {
+		return ErrEmailSuppressed
+	}
+
+	if err := w.enqueue(ctx, item); err != nil {
+		w.releaseDedup(ctx, item)
+		return err
+	}
+	w.recordDelivery(ctx, item, models.EmailDeliveryQueued)
+	return nil
+}
+
+// enqueue pushes an item to Redis, or to the fallback buffer while Redis is unreachable
+func (w *EmailQueueWorker) enqueue(ctx context.Context, item *EmailQueueItem) error {
+	// Emails buffered during a Redis outage go first, so the queue keeps the arrival order
+	if w.fallback.Len() > 0 {
+		if err := w.bufferEmail(item); err != nil {
+			return err
+		}
+		w.drainFallback(ctx)
+		return nil
+	}
+
+	if err := w.pushToQueue(ctx, item); err != nil {
+		w.logger.Printf("[EmailQueue] Redis unavailable, buffering email in memory: %v", err)
+		return w.bufferEmail(item)
+	}
+	return nil
+}
+
+// emailDedupRedisKey is the Redis key that marks an occurrence email sent to a recipient
+func emailDedupRedisKey(item *EmailQueueItem) string {
+	return EmailDedupKeyPrefix + item.OccurrenceID + ":" + strings.ToLower(item.To)
+}
+
+// claimDedup marks the email as sent for the dedup window, reporting whether it was already
+// marked. Without Redis the email is not suppressed; the fallback buffer drops duplicates.
+func (w *EmailQueueWorker) claimDedup(ctx context.Context, item *EmailQueueItem) bool {
+	if w.dedupWindow <= 0 {
+		return false
+	}
+
+	claimed, err := w.queue.SetNX(ctx, emailDedupRedisKey(item), item.ID, w.dedupWindow).Result()
+	if err != nil {
+		return false
+	}
+	if !claimed {
+		w.logger.Printf("[EmailQueue] Suppressed duplicate email to %s for occurrence %s", item.To, item.OccurrenceID)
+	}
+	return !claimed
+}
+
+// releaseDedup removes the mark of an email that could not be queued, so it can be retried
+func (w *EmailQueueWorker) releaseDedup(ctx context.Context, item *EmailQueueItem) {
+	if w.dedupWindow > 0 {
+		w.queue.Del(ctx, emailDedupRedisKey(item))
+	}
+}
+
+// pushToQueue adds an item to the Redis queue
+func (w *EmailQueueWorker) pushToQueue(ctx context.Context, item *EmailQueueItem) error {
 	payload, err := json.Marshal(item)
 	if err != nil {
 		return err
 	}
+	return w.queue.LPush(ctx, EmailQueueKey, payload).Err()
+}
 
-	return w.redis.LPush(ctx, EmailQueueKey, payload).Err()
+// bufferEmail holds an email in memory until Redis is reachable again
+func (w *EmailQueueWorker) bufferEmail(item *EmailQueueItem) error {
+	if atomic.LoadInt32(&w.stopping) == 1 {
+		return ErrEmailWorkerStopping
+	}
+
+	added, err := w.fallback.Add(item)
+	if errors.Is(err, ErrQueueFull) {
+		w.logger.Printf("[EmailQueue] Fallback buffer full (%d emails), dropping email to %s for occurrence %s",
+			w.fallback.Len(), item.To, item.OccurrenceID)
+		atomic.AddInt64(&w.errors, 1)
+		return err
+	}
+	if !added {
+		w.logger.Printf("[EmailQueue] Email to %s for occurrence %s already buffered, skipping duplicate", item.To, item.OccurrenceID)
+	}
+	return nil
+}
+
+// drainFallback moves the buffered emails to the Redis queue, in order,
+// keeping in memory the ones not pushed while Redis is still unreachable
+func (w *EmailQueueWorker) drainFallback(ctx context.Context) {
+	if w.fallback.Len() == 0 {
+		return
+	}
+
+	pushed, err := w.fallback.Drain(func(item *EmailQueueItem) error {
+		return w.pushToQueue(ctx, item)
+	})
+	if pushed > 0 {
+		w.logger.Printf("[EmailQueue] Moved %d buffered email(s) to the Redis queue", pushed)
+	}
+	if err != nil && pushed == 0 {
+		return // still unreachable, retried on the next tick
+	}
+	if err != nil {
+		w.logger.Printf("[EmailQueue] Redis unavailable again, %d email(s) still buffered: %v", w.fallback.Len(), err)
+	}
 }
 
-// processLoop is the main processing loop
-func (w *EmailQueueWorker) processLoop(ctx context.Context) {
+// drainFallbackUntil retries moving the fallback buffer to Redis until it is empty or ctx is done
+func (w *EmailQueueWorker) drainFallbackUntil(ctx context.Context) {
+	for {
+		w.drainFallback(ctx)
+		if w.fallback.Len() == 0 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(emailDrainRetryInterval):
+		}
+	}
+}
+
+// processLoop is the main processing loop. It stops taking emails when ctx is done or the worker
+// is stopped; the emails taken are sent with workCtx.
+func (w *EmailQueueWorker) processLoop(ctx, workCtx context.Context) {
 	defer close(w.doneCh)
 
 	ticker := time.NewTicker(w.pollInterval)
@@ -155,13 +354,15 @@ func (w *EmailQueueWorker) processLoop(ctx context.Context) {
 		case <-w.stopCh:
 			return
 		case <-ticker.C:
-			w.processQueue(ctx)
+			w.drainFallback(workCtx)
+			w.processQueue(ctx, workCtx)
 		}
 	}
 }
 
-// processQueue processes items from the queue
-func (w *EmailQueueWorker) processQueue(ctx context.Context) {
+// processQueue processes items from the queue, one at a time so that stopping between
+// two items leaves the rest of the batch in the queue
+func (w *EmailQueueWorker) processQueue(ctx, workCtx context.Context) {
 	for i := 0; i < w.batchSize; i++ {
 		select {
 		case <-ctx.Done():
@@ -172,7 +373,7 @@ func (w *EmailQueueWorker) processQueue(ctx context.Context) {
 		}
 
 		// Get an item from the queue
-		result, err := w.redis.RPopLPush(ctx, EmailQueueKey, EmailProcessingKey).Result()
+		result, err := w.redis.RPopLPush(workCtx, EmailQueueKey, EmailProcessingKey).Result()
 		if err != nil {
 			if err == redis.Nil {
 				return // Queue is empty
@@ -186,7 +387,7 @@ func (w *EmailQueueWorker) processQueue(ctx context.Context) {
 		var item EmailQueueItem
 		if err := json.Unmarshal([]byte(result), &item); err != nil {
 			w.logger.Printf("[EmailQueue] Error unmarshalling queue item: %v", err)
-			w.removeFromProcessing(ctx, result)
+			w.removeFromProcessing(workCtx, result)
 			atomic.AddInt64(&w.errors, 1)
 			continue
 		}
@@ -194,12 +395,12 @@ func (w *EmailQueueWorker) processQueue(ctx context.Context) {
 		// Check if we should retry (exponential backoff)
 		if item.NextRetryAt != nil && time.Now().Before(*item.NextRetryAt) {
 			// Put back in queue for later
-			w.requeue(ctx, &item, result)
+			w.requeue(workCtx, &item, result)
 			continue
 		}
 
 		// Process the email
-		w.processEmail(ctx, &item, result)
+		w.processEmail(workCtx, &item, result)
 	}
 }
 
@@ -222,7 +423,7 @@ func (w *EmailQueueWorker) processEmail(ctx context.Context, item *EmailQueueIte
 
 	metadata := &models.NotificationMetadata{
 		EmailTo:       item.To,
-		EmailSubject:  "[URGENTE] Nova Ocorrencia Elegivel - " + item.Data.HospitalNome,
+		EmailSubject:  obitoNotificationSubject(item.Data),
 		HospitalNome:  item.Data.HospitalNome,
 		Setor:         item.Data.Setor,
 		TempoRestante: item.Data.TempoRestante,
@@ -242,9 +443,12 @@ func (w *EmailQueueWorker) processEmail(ctx context.Context, item *EmailQueueIte
 			item.NextRetryAt = &nextRetry
 
 			// Requeue for retry
+			w.recordDelivery(ctx, item, models.EmailDeliveryFailed)
 			w.requeue(ctx, item, rawPayload)
 		} else {
 			// Max retries reached, record failure
+			item.NextRetryAt = nil
+			w.recordDelivery(ctx, item, models.EmailDeliveryFailed)
 			atomic.AddInt64(&w.totalFailed, 1)
 			errMsg := err.Error()
 			_, _ = w.notificationRepo.CreateNotificationFromEmail(ctx, occurrenceID, userID, metadata, models.NotificationStatusFalha, &errMsg)
@@ -256,6 +460,8 @@ func (w *EmailQueueWorker) processEmail(ctx context.Context, item *EmailQueueIte
 	// Success
 	atomic.AddInt64(&w.totalSuccessful, 1)
 	w.logger.Printf("[EmailQueue] Successfully sent email to %s for occurrence %s", item.To, item.OccurrenceID)
+	item.NextRetryAt = nil
+	w.recordDelivery(ctx, item, models.EmailDeliverySent)
 
 	// Record successful notification
 	_, _ = w.notificationRepo.CreateNotificationFromEmail(ctx, occurrenceID, userID, metadata, models.NotificationStatusEnviado, nil)
@@ -272,23 +478,65 @@ func (w *EmailQueueWorker) requeue(ctx context.Context, item *EmailQueueItem, ra
 	}
 
 	// Remove from processing and add back to queue
-	w.redis.LRem(ctx, EmailProcessingKey, 1, rawPayload)
-	w.redis.LPush(ctx, EmailQueueKey, payload)
+	w.queue.LRem(ctx, EmailProcessingKey, 1, rawPayload)
+	w.queue.LPush(ctx, EmailQueueKey, payload)
 }
 
 // removeFromProcessing removes an item from the processing list
 func (w *EmailQueueWorker) removeFromProcessing(ctx context.Context, rawPayload string) {
-	w.redis.LRem(ctx, EmailProcessingKey, 1, rawPayload)
+	w.queue.LRem(ctx, EmailProcessingKey, 1, rawPayload)
+}
+
+// recordDelivery saves the delivery status of an email for auditing; a failure to record
+// is logged and never affects the delivery itself
+func (w *EmailQueueWorker) recordDelivery(ctx context.Context, item *EmailQueueItem, status models.EmailDeliveryStatus) {
+	if w.deliveries == nil {
+		return
+	}
+
+	id, err := uuid.Parse(item.ID)
+	if err != nil {
+		return
+	}
+	attempts := item.Retries // Retries counts the failed attempts only
+	if status == models.EmailDeliverySent {
+		attempts++
+	}
+	delivery := &models.EmailDelivery{
+		ID:            id,
+		Recipient:     item.To,
+		Status:        status,
+		Attempts:      attempts,
+		LastAttemptAt: item.LastAttemptAt,
+		NextRetryAt:   item.NextRetryAt,
+	}
+	if occurrenceID, err := uuid.Parse(item.OccurrenceID); err == nil {
+		delivery.OccurrenceID = &occurrenceID
+	}
+	if item.UserID != nil {
+		if userID, err := uuid.Parse(*item.UserID); err == nil {
+			delivery.UserID = &userID
+		}
+	}
+	if item.Error != "" {
+		lastError := item.Error
+		delivery.LastError = &lastError
+	}
+
+	if err := w.deliveries.Save(ctx, delivery); err != nil {
+		w.logger.Printf("[EmailQueue] Error recording delivery of email %s: %v", item.ID, err)
+	}
 }
 
 // GetStats returns statistics about the queue worker
 func (w *EmailQueueWorker) GetStats() map[string]interface{} {
 	return map[string]interface{}{
-		"running":          w.IsRunning(),
-		"total_processed":  atomic.LoadInt64(&w.totalProcessed),
-		"total_successful": atomic.LoadInt64(&w.totalSuccessful),
-		"total_failed":     atomic.LoadInt64(&w.totalFailed),
-		"errors":           atomic.LoadInt64(&w.errors),
+		"running":           w.IsRunning(),
+		"total_processed":   atomic.LoadInt64(&w.totalProcessed),
+		"total_successful":  atomic.LoadInt64(&w.totalSuccessful),
+		"total_failed":      atomic.LoadInt64(&w.totalFailed),
+		"errors":            atomic.LoadInt64(&w.errors),
+		"fallback_buffered": w.fallback.Len(),
 	}
 }
 
@@ -297,6 +545,16 @@ func (w *EmailQueueWorker) GetQueueLength(ctx context.Context) (int64, error) {
 	return w.redis.LLen(ctx, EmailQueueKey).Result()
 }
 
+// SetDedupWindow sets how long an occurrence email is suppressed for a recipient (0 disables it)
+func (w *EmailQueueWorker) SetDedupWindow(window time.Duration) {
+	w.dedupWindow = window
+}
+
+// SetDrainTimeout sets how long Stop waits for the email being sent and the fallback buffer
+func (w *EmailQueueWorker) SetDrainTimeout(timeout time.Duration) {
+	w.drainTimeout = timeout
+}
+
 // SetLogger sets a custom logger
 func (w *EmailQueueWorker) SetLogger(logger *log.Logger) {
 	w.logger = logger
@@ -0,0 +1,477 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sidot/backend/internal/models"
+)
+
+// fakeEmailQueue records pushed payloads and dedup keys, failing while down to simulate a
+// Redis outage; dedup keys expire against the fake clock now
+type fakeEmailQueue struct {
+	down     bool
+	payloads []string
+	keys     map[string]time.Time
+	now      time.Time
+}
+
+func (q *fakeEmailQueue) LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx)
+	if q.down {
+		cmd.SetErr(errors.New("dial tcp: connection refused"))
+		return cmd
+	}
+	for _, value := range values {
+		q.payloads = append(q.payloads, string(value.([]byte)))
+	}
+	cmd.SetVal(int64(len(q.payloads)))
+	return cmd
+}
+
+func (q *fakeEmailQueue) LRem(ctx context.Context, key string, count int64, value interface{}) *redis.IntCmd {
+	return redis.NewIntCmd(ctx)
+}
+
+func (q *fakeEmailQueue) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	cmd := redis.NewBoolCmd(ctx)
+	if q.down {
+		cmd.SetErr(errors.New("dial tcp: connection refused"))
+		return cmd
+	}
+	if q.keys == nil {
+		q.keys = make(map[string]time.Time)
+	}
+	if expiresAt, exists := q.keys[key]; exists && q.now.Before(expiresAt) {
+		cmd.SetVal(false)
+		return cmd
+	}
+	q.keys[key] = q.now.Add(expiration)
+	cmd.SetVal(true)
+	return cmd
+}
+
+func (q *fakeEmailQueue) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx)
+	for _, key := range keys {
+		delete(q.keys, key)
+	}
+	return cmd
+}
+
+// recipients returns the recipients of the queued emails in the order they are consumed
+func (q *fakeEmailQueue) recipients(t *testing.T) []string {
+	t.Helper()
+
+	recipients := []string{}
+	for _, payload := range q.payloads {
+		var item EmailQueueItem
+		if err := json.Unmarshal([]byte(payload), &item); err != nil {
+			t.Fatalf("Failed to unmarshal queued email: %v", err)
+		}
+		recipients = append(recipients, item.To)
+	}
+	return recipients
+}
+
+// fakeEmailDeliveries records every saved delivery status
+type fakeEmailDeliveries struct {
+	saved []models.EmailDelivery
+}
+
+func (d *fakeEmailDeliveries) Save(ctx context.Context, delivery *models.EmailDelivery) error {
+	d.saved = append(d.saved, *delivery)
+	return nil
+}
+
+// last returns the most recently saved delivery status
+func (d *fakeEmailDeliveries) last(t *testing.T) models.EmailDelivery {
+	t.Helper()
+	if len(d.saved) == 0 {
+		t.Fatal("Expected a recorded delivery")
+	}
+	return d.saved[len(d.saved)-1]
+}
+
+// fakeEmailNotifications records the status of the notifications created for emails
+type fakeEmailNotifications struct {
+	statuses []models.NotificationStatus
+}
+
+func (n *fakeEmailNotifications) CreateNotificationFromEmail(ctx context.Context, occurrenceID uuid.UUID, userID *uuid.UUID, metadata *models.NotificationMetadata, status models.NotificationStatus, errorMsg *string) (*models.Notification, error) {
+	n.statuses = append(n.statuses, status)
+	return &models.Notification{}, nil
+}
+
+func newTestEmailQueueWorker(queue *fakeEmailQueue, fallbackSize int) *EmailQueueWorker {
+	w := NewEmailQueueWorker(nil, nil, nil)
+	w.queue = queue
+	w.fallback = newEmailFallbackBuffer(fallbackSize)
+	w.deliveries = &fakeEmailDeliveries{}
+	w.notificationRepo = &fakeEmailNotifications{}
+	w.SetLogger(log.New(io.Discard, "", 0))
+	return w
+}
+
+func assertRecipients(t *testing.T, got, want []string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("Expected queued recipients %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected queued recipients %v, got %v", want, got)
+		}
+	}
+}
+
+func TestEmailQueueFallbackDuringRedisOutage(t *testing.T) {
+	ctx := context.Background()
+	queue := &fakeEmailQueue{}
+	w := newTestEmailQueueWorker(queue, 10)
+	data := &ObitoNotificationData{HospitalNome: "HGG"}
+	occurrenceA, occurrenceB := uuid.New(), uuid.New()
+
+	// Redis up: straight to the queue
+	if err := w.EnqueueEmail(ctx, occurrenceA, "first@example.com", nil, data); err != nil {
+		t.Fatalf("EnqueueEmail failed: %v", err)
+	}
+
+	// Redis down: buffered, duplicates of occurrence+recipient dropped
+	queue.down = true
+	for _, email := range []struct {
+		occurrence uuid.UUID
+		to         string
+	}{
+		{occurrenceA, "second@example.com"},
+		{occurrenceB, "second@example.com"},
+		{occurrenceA, "second@example.com"},
+	} {
+		if err := w.EnqueueEmail(ctx, email.occurrence, email.to, nil, data); err != nil {
+			t.Fatalf("EnqueueEmail during outage should buffer, got: %v", err)
+		}
+	}
+	if buffered := w.fallback.Len(); buffered != 2 {
+		t.Errorf("Expected 2 buffered emails, got %d", buffered)
+	}
+
+	w.drainFallback(ctx)
+	if buffered := w.fallback.Len(); buffered != 2 {
+		t.Errorf("Expected emails to stay buffered while Redis is down, got %d", buffered)
+	}
+
+	// Redis back: a new email is queued after the buffered ones
+	queue.down = false
+	if err := w.EnqueueEmail(ctx, occurrenceB, "third@example.com", nil, data); err != nil {
+		t.Fatalf("EnqueueEmail after recovery failed: %v", err)
+	}
+
+	if buffered := w.fallback.Len(); buffered != 0 {
+		t.Errorf("Expected buffer drained after recovery, got %d", buffered)
+	}
+	assertRecipients(t, queue.recipients(t), []string{
+		"first@example.com", "second@example.com", "second@example.com", "third@example.com",
+	})
+	if w.GetStats()["fallback_buffered"] != 0 {
+		t.Errorf("Expected fallback_buffered 0, got %v", w.GetStats()["fallback_buffered"])
+	}
+}
+
+func TestEmailQueueFallbackDrainedByWorkerTick(t *testing.T) {
+	ctx := context.Background()
+	queue := &fakeEmailQueue{down: true}
+	w := newTestEmailQueueWorker(queue, 10)
+	data := &ObitoNotificationData{HospitalNome: "HGG"}
+
+	for _, to := range []string{"a@example.com", "b@example.com"} {
+		if err := w.EnqueueEmail(ctx, uuid.New(), to, nil, data); err != nil {
+			t.Fatalf("EnqueueEmail failed: %v", err)
+		}
+	}
+
+	queue.down = false
+	w.drainFallback(ctx)
+
+	assertRecipients(t, queue.recipients(t), []string{"a@example.com", "b@example.com"})
+}
+
+func TestEmailQueueFallbackFull(t *testing.T) {
+	ctx := context.Background()
+	w := newTestEmailQueueWorker(&fakeEmailQueue{down: true}, 1)
+	data := &ObitoNotificationData{HospitalNome: "HGG"}
+
+	if err := w.EnqueueEmail(ctx, uuid.New(), "a@example.com", nil, data); err != nil {
+		t.Fatalf("EnqueueEmail failed: %v", err)
+	}
+	if err := w.EnqueueEmail(ctx, uuid.New(), "b@example.com", nil, data); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull when the buffer is full, got %v", err)
+	}
+}
+
+func TestEmailFallbackDrainDoesNotHoldBufferWhilePushing(t *testing.T) {
+	buffer := newEmailFallbackBuffer(3)
+	first := &EmailQueueItem{OccurrenceID: "a", To: "first@example.com"}
+	second := &EmailQueueItem{OccurrenceID: "b", To: "second@example.com"}
+	if _, err := buffer.Add(first); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	pushing := make(chan struct{})
+	release := make(chan struct{})
+	var pushedTo []string
+	pushErr := errors.New("redis unavailable")
+	failOn := "second@example.com"
+	done := make(chan error, 1)
+	go func() {
+		_, err := buffer.Drain(func(item *EmailQueueItem) error {
+			if item == first {
+				close(pushing)
+				<-release
+			}
+			if item.To == failOn {
+				return pushErr
+			}
+			pushedTo = append(pushedTo, item.To)
+			return nil
+		})
+		done <- err
+	}()
+	<-pushing
+
+	// Add does not wait for the push; the email being pushed still counts as buffered
+	if added, err := buffer.Add(&EmailQueueItem{OccurrenceID: "a", To: "first@example.com"}); added || err != nil {
+		t.Errorf("Expected the email being pushed to dedup, got added=%v err=%v", added, err)
+	}
+	if added, err := buffer.Add(second); !added || err != nil {
+		t.Fatalf("Expected Add during a drain to buffer, got added=%v err=%v", added, err)
+	}
+	if n := buffer.Len(); n != 2 {
+		t.Errorf("Expected 2 buffered emails, got %d", n)
+	}
+	if n, err := buffer.Drain(func(*EmailQueueItem) error { return nil }); n != 0 || err != nil {
+		t.Errorf("Expected a concurrent drain to do nothing, got %d (%v)", n, err)
+	}
+
+	// The email added meanwhile is pushed by the same drain; it fails and is put back
+	close(release)
+	if err := <-done; !errors.Is(err, pushErr) {
+		t.Fatalf("Expected the push error, got %v", err)
+	}
+	assertRecipients(t, pushedTo, []string{"first@example.com"})
+	if n := buffer.Len(); n != 1 {
+		t.Fatalf("Expected the failed email to stay buffered, got %d", n)
+	}
+
+	failOn = ""
+	if n, err := buffer.Drain(func(item *EmailQueueItem) error {
+		pushedTo = append(pushedTo, item.To)
+		return nil
+	}); n != 1 || err != nil {
+		t.Fatalf("Expected the failed email to be pushed on the next drain, got %d (%v)", n, err)
+	}
+	assertRecipients(t, pushedTo, []string{"first@example.com", "second@example.com"})
+}
+
+func TestEmailQueueDedupWindow(t *testing.T) {
+	ctx := context.Background()
+	queue := &fakeEmailQueue{now: time.Now()}
+	w := newTestEmailQueueWorker(queue, 10)
+	w.SetDedupWindow(10 * time.Minute)
+	data := &ObitoNotificationData{HospitalNome: "HGG"}
+	occurrenceID := uuid.New()
+
+	if err := w.EnqueueEmail(ctx, occurrenceID, "operador@example.com", nil, data); err != nil {
+		t.Fatalf("First EnqueueEmail failed: %v", err)
+	}
+
+	// Retriage within the window: same occurrence and recipient (case-insensitive) suppressed
+	queue.now = queue.now.Add(5 * time.Minute)
+	if err := w.EnqueueEmail(ctx, occurrenceID, "Operador@example.com", nil, data); !errors.Is(err, ErrEmailSuppressed) {
+		t.Errorf("Expected ErrEmailSuppressed within the window, got %v", err)
+	}
+
+	// Other recipients and occurrences are not affected
+	if err := w.EnqueueEmail(ctx, occurrenceID, "outro@example.com", nil, data); err != nil {
+		t.Errorf("Expected other recipient to be queued, got %v", err)
+	}
+	if err := w.EnqueueEmail(ctx, uuid.New(), "operador@example.com", nil, data); err != nil {
+		t.Errorf("Expected other occurrence to be queued, got %v", err)
+	}
+
+	// After the window the notification is sent again
+	queue.now = queue.now.Add(6 * time.Minute)
+	if err := w.EnqueueEmail(ctx, occurrenceID, "operador@example.com", nil, data); err != nil {
+		t.Errorf("Expected email after the window to be queued, got %v", err)
+	}
+
+	assertRecipients(t, queue.recipients(t), []string{
+		"operador@example.com", "outro@example.com", "operador@example.com", "operador@example.com",
+	})
+}
+
+func TestEmailQueueDedupReleasedWhenNotQueued(t *testing.T) {
+	ctx := context.Background()
+	queue := &fakeEmailQueue{now: time.Now()}
+	w := newTestEmailQueueWorker(queue, 0) // no room to buffer
+	data := &ObitoNotificationData{HospitalNome: "HGG"}
+	occurrenceID := uuid.New()
+
+	// Redis accepts the dedup key but the push fails and the buffer is full
+	w.queue = &failingPushQueue{fakeEmailQueue: queue}
+	if err := w.EnqueueEmail(ctx, occurrenceID, "operador@example.com", nil, data); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Expected ErrQueueFull, got %v", err)
+	}
+
+	w.queue = queue
+	if err := w.EnqueueEmail(ctx, occurrenceID, "operador@example.com", nil, data); err != nil {
+		t.Errorf("Expected retry of an email that was not queued, got %v", err)
+	}
+}
+
+// failingPushQueue fails every push while keeping the dedup keys working
+type failingPushQueue struct {
+	*fakeEmailQueue
+}
+
+func (q *failingPushQueue) LPush(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetErr(errors.New("OOM command not allowed"))
+	return cmd
+}
+
+// lastQueuedItem returns the most recently pushed queue item and its payload
+func lastQueuedItem(t *testing.T, queue *fakeEmailQueue) (*EmailQueueItem, string) {
+	t.Helper()
+	if len(queue.payloads) == 0 {
+		t.Fatal("Expected a queued email")
+	}
+	payload := queue.payloads[len(queue.payloads)-1]
+	var item EmailQueueItem
+	if err := json.Unmarshal([]byte(payload), &item); err != nil {
+		t.Fatalf("Failed to unmarshal queued email: %v", err)
+	}
+	return &item, payload
+}
+
+func TestEmailQueueRecordsDeliveryStatus(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeSMTPServer(t, nil)
+	server.setReject(true)
+
+	queue := &fakeEmailQueue{now: time.Now()}
+	w := newTestEmailQueueWorker(queue, 10)
+	w.emailService = NewEmailService(server.config())
+	deliveries := w.deliveries.(*fakeEmailDeliveries)
+
+	occurrenceID, userID := uuid.New(), uuid.New()
+	if err := w.EnqueueEmail(ctx, occurrenceID, "operador@example.com", &userID, &ObitoNotificationData{HospitalNome: "HGG"}); err != nil {
+		t.Fatalf("Failed to enqueue email: %v", err)
+	}
+	queued := deliveries.last(t)
+	if queued.Status != models.EmailDeliveryQueued || queued.Attempts != 0 || queued.Recipient != "operador@example.com" {
+		t.Errorf("Expected a queued delivery without attempts, got %+v", queued)
+	}
+	if queued.OccurrenceID == nil || *queued.OccurrenceID != occurrenceID || queued.UserID == nil || *queued.UserID != userID {
+		t.Errorf("Expected the delivery to reference the occurrence and user, got %+v", queued)
+	}
+
+	// The SMTP server rejects the recipient: recorded as failed and requeued for retry
+	item, payload := lastQueuedItem(t, queue)
+	w.processEmail(ctx, item, payload)
+
+	failed := deliveries.last(t)
+	if failed.Status != models.EmailDeliveryFailed || failed.Attempts != 1 {
+		t.Errorf("Expected a failed delivery after 1 attempt, got %+v", failed)
+	}
+	if failed.LastError == nil || !strings.Contains(*failed.LastError, "550") {
+		t.Errorf("Expected the SMTP error to be recorded, got %v", failed.LastError)
+	}
+	if failed.NextRetryAt == nil || failed.LastAttemptAt == nil {
+		t.Errorf("Expected the attempt and retry times to be recorded, got %+v", failed)
+	}
+	if failed.ID != queued.ID {
+		t.Errorf("Expected the same delivery record to be updated, got %s and %s", queued.ID, failed.ID)
+	}
+
+	retry, retryPayload := lastQueuedItem(t, queue)
+	if len(queue.payloads) != 2 || retry.ID != item.ID || retry.Retries != 1 || retry.NextRetryAt == nil {
+		t.Fatalf("Expected the email to be requeued for retry, got %d payloads and %+v", len(queue.payloads), retry)
+	}
+
+	// The retry is accepted
+	server.setReject(false)
+	w.processEmail(ctx, retry, retryPayload)
+
+	sent := deliveries.last(t)
+	if sent.Status != models.EmailDeliverySent || sent.Attempts != 2 || sent.NextRetryAt != nil {
+		t.Errorf("Expected a sent delivery after 2 attempts, got %+v", sent)
+	}
+	if messages, _, _ := server.delivered(); len(messages) != 1 {
+		t.Errorf("Expected one delivered message, got %d", len(messages))
+	}
+}
+
+func TestEmailQueueStopDrainsFallbackBuffer(t *testing.T) {
+	ctx := context.Background()
+	queue := &fakeEmailQueue{down: true}
+	w := newTestEmailQueueWorker(queue, 10)
+	w.pollInterval = time.Hour // the buffer is only drained by Stop
+	data := &ObitoNotificationData{HospitalNome: "HGG"}
+
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	for _, to := range []string{"a@example.com", "b@example.com"} {
+		if err := w.EnqueueEmail(ctx, uuid.New(), to, nil, data); err != nil {
+			t.Fatalf("EnqueueEmail failed: %v", err)
+		}
+	}
+
+	queue.down = false
+	w.Stop()
+
+	if buffered := w.fallback.Len(); buffered != 0 {
+		t.Errorf("Expected the buffer drained on stop, got %d buffered", buffered)
+	}
+	assertRecipients(t, queue.recipients(t), []string{"a@example.com", "b@example.com"})
+}
+
+func TestEmailQueueStopDrainTimeout(t *testing.T) {
+	ctx := context.Background()
+	queue := &fakeEmailQueue{down: true}
+	w := newTestEmailQueueWorker(queue, 10)
+	w.pollInterval = time.Hour
+	w.SetDrainTimeout(300 * time.Millisecond)
+	data := &ObitoNotificationData{HospitalNome: "HGG"}
+
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := w.EnqueueEmail(ctx, uuid.New(), "a@example.com", nil, data); err != nil {
+		t.Fatalf("EnqueueEmail failed: %v", err)
+	}
+
+	// Redis stays down: Stop gives up after the drain timeout
+	started := time.Now()
+	w.Stop()
+	if elapsed := time.Since(started); elapsed < 300*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("Expected Stop to return after the 300ms drain timeout, took %s", elapsed)
+	}
+	if buffered := w.fallback.Len(); buffered != 1 {
+		t.Errorf("Expected the undelivered email to stay buffered, got %d", buffered)
+	}
+
+	// Emails that could only be buffered in memory are refused once stopping
+	if err := w.EnqueueEmail(ctx, uuid.New(), "b@example.com", nil, data); !errors.Is(err, ErrEmailWorkerStopping) {
+		t.Errorf("Expected ErrEmailWorkerStopping after stop, got %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSMTPServer is a minimal SMTP server that records the messages delivered to it
+type fakeSMTPServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config // STARTTLS is offered when set
+
+	mu       sync.Mutex
+	reject   bool       // reject every recipient with a permanent error
+	conns    []net.Conn // accepted connections
+	mails    int        // MAIL commands received
+	messages []string   // delivered messages
+	secure   []bool     // whether each message was delivered over TLS
+}
+
+func newFakeSMTPServer(t *testing.T, tlsConfig *tls.Config) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	server := &fakeSMTPServer{listener: listener, tlsConfig: tlsConfig}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.mu.Lock()
+			server.conns = append(server.conns, conn)
+			server.mu.Unlock()
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+// config returns an email config pointing at the server
+func (f *fakeSMTPServer) config() *EmailConfig {
+	host, port, _ := net.SplitHostPort(f.listener.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+	return &EmailConfig{SMTPHost: host, SMTPPort: portNum, SMTPFrom: "noreply@sidot.gov.br"}
+}
+
+func (f *fakeSMTPServer) serve(conn net.Conn) {
+	defer func() { conn.Close() }()
+
+	secure := false
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.Fields(line + " ")[0])
+
+		switch command {
+		case "EHLO", "HELO":
+			if f.tlsConfig != nil && !secure {
+				text.PrintfLine("250-fake\r\n250-STARTTLS\r\n250 8BITMIME")
+			} else {
+				text.PrintfLine("250-fake\r\n250 8BITMIME")
+			}
+		case "STARTTLS":
+			if f.tlsConfig == nil || secure {
+				text.PrintfLine("502 not supported")
+				continue
+			}
+			text.PrintfLine("220 ready to start TLS")
+			tlsConn := tls.Server(conn, f.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			text = textproto.NewConn(conn)
+			secure = true
+		case "MAIL":
+			f.mu.Lock()
+			f.mails++
+			f.mu.Unlock()
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			f.mu.Lock()
+			reject := f.reject
+			f.mu.Unlock()
+			if reject {
+				text.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 end with .")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			f.mu.Lock()
+			f.messages = append(f.messages, string(data))
+			f.secure = append(f.secure, secure)
+			f.mu.Unlock()
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("250 ok")
+		}
+	}
+}
+
+// setReject makes the server reject (or accept again) every recipient
+func (f *fakeSMTPServer) setReject(reject bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reject = reject
+}
+
+// connections returns the number of connections accepted
+func (f *fakeSMTPServer) connections() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.conns)
+}
+
+// dropConnections closes every connection, as a server timing out idle sessions does
+func (f *fakeSMTPServer) dropConnections() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, conn := range f.conns {
+		conn.Close()
+	}
+}
+
+func (f *fakeSMTPServer) delivered() ([]string, []bool, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.messages, f.secure, f.mails
+}
+
+// testServerTLS returns the TLS config and certificate of an httptest server,
+// valid for 127.0.0.1
+func testServerTLS(t *testing.T) (*tls.Config, *x509.Certificate) {
+	t.Helper()
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(ts.Close)
+	return &tls.Config{Certificates: ts.TLS.Certificates}, ts.Certificate()
+}
+
+// TestSendEmailRequireSTARTTLS tests that the client refuses to send in cleartext when STARTTLS is required
+func TestSendEmailRequireSTARTTLS(t *testing.T) {
+	server := newFakeSMTPServer(t, nil)
+	config := server.config()
+	config.RequireSTARTTLS = true
+
+	err := NewEmailService(config).sendEmail(context.Background(), "operador@sidot.gov.br", "Teste", "<p>corpo</p>")
+	if !errors.Is(err, ErrSTARTTLSRequired) {
+		t.Fatalf("Expected ErrSTARTTLSRequired, got %v", err)
+	}
+	if !errors.Is(err, ErrSendFailed) {
+		t.Errorf("Expected the error to wrap ErrSendFailed, got %v", err)
+	}
+
+	messages, _, mails := server.delivered()
+	if mails != 0 || len(messages) != 0 {
+		t.Errorf("Expected no mail transaction, got %d MAIL and %d messages", mails, len(messages))
+	}
+}
+
+// TestSendEmailCleartextWhenSTARTTLSOptional tests that STARTTLS stays opportunistic by default
+func TestSendEmailCleartextWhenSTARTTLSOptional(t *testing.T) {
+	server := newFakeSMTPServer(t, nil)
+
+	err := NewEmailService(server.config()).sendEmail(context.Background(), "operador@sidot.gov.br", "Teste", "<p>corpo</p>")
+	if err != nil {
+		t.Fatalf("Failed to send email: %v", err)
+	}
+
+	messages, secure, _ := server.delivered()
+	if len(messages) != 1 || secure[0] {
+		t.Fatalf("Expected one cleartext message, got %d (secure %v)", len(messages), secure)
+	}
+	if !strings.Contains(messages[0], "Subject: Teste") || !strings.Contains(messages[0], "<p>corpo</p>") {
+		t.Errorf("Unexpected message %q", messages[0])
+	}
+}
+
+// TestSendEmailSTARTTLSVerifiesCertificate tests the upgrade with a trusted and an untrusted certificate
+func TestSendEmailSTARTTLSVerifiesCertificate(t *testing.T) {
+	tlsConfig, cert := testServerTLS(t)
+
+	t.Run("trusted certificate", func(t *testing.T) {
+		server := newFakeSMTPServer(t, tlsConfig)
+		config := server.config()
+		config.RequireSTARTTLS = true
+		config.RootCAs = x509.NewCertPool()
+		config.RootCAs.AddCert(cert)
+
+		if err := NewEmailService(config).sendEmail(context.Background(), "operador@sidot.gov.br", "Teste", "<p>corpo</p>"); err != nil {
+			t.Fatalf("Failed to send email: %v", err)
+		}
+		messages, secure, _ := server.delivered()
+		if len(messages) != 1 || !secure[0] {
+			t.Fatalf("Expected one message over TLS, got %d (secure %v)", len(messages), secure)
+		}
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		server := newFakeSMTPServer(t, tlsConfig)
+
+		err := NewEmailService(server.config()).sendEmail(context.Background(), "operador@sidot.gov.br", "Teste", "<p>corpo</p>")
+		if err == nil || !strings.Contains(err.Error(), "certificate") {
+			t.Fatalf("Expected a certificate verification error, got %v", err)
+		}
+		messages, _, mails := server.delivered()
+		if mails != 0 || len(messages) != 0 {
+			t.Errorf("Expected no mail transaction, got %d MAIL and %d messages", mails, len(messages))
+		}
+	})
+}
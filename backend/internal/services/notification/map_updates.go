@@ -0,0 +1,315 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// DefaultMapUpdateInterval is the interval between checks for urgency and on-duty operator changes
+const DefaultMapUpdateInterval = time.Minute
+
+// mapEventPublisher publishes hub events (implemented by SSEHub)
+type mapEventPublisher interface {
+	PublishEvent(ctx context.Context, event *models.SSEEvent) error
+}
+
+// mapHospitalSource loads the hospitals shown on the map (implemented by repository.HospitalRepository)
+type mapHospitalSource interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Hospital, error)
+	GetActiveHospitalsWithCoordinates(ctx context.Context) ([]models.Hospital, error)
+}
+
+// mapOccurrenceSource lists the occurrences of a hospital (implemented by repository.OccurrenceRepository)
+type mapOccurrenceSource interface {
+	List(ctx context.Context, filters models.OccurrenceListFilters) ([]models.Occurrence, int, error)
+}
+
+// mapShiftSource finds the operators on duty (implemented by repository.ShiftRepository)
+type mapShiftSource interface {
+	GetActiveShiftsAt(ctx context.Context, hospitalID uuid.UUID, at time.Time) ([]models.Shift, error)
+}
+
+// mapMarker is the last published state of a hospital marker
+type mapMarker struct {
+	urgency    models.UrgencyLevel
+	count      int
+	operatorID uuid.UUID
+}
+
+// MapUpdatePublisher publishes map_update events on the map topic of the hub when a hospital
+// marker changes: occurrences created or resolved, max urgency changes and on-duty operator changes.
+// Occurrence and shift flows publish right away; a periodic check catches the changes caused by
+// the passing of time (windows getting closer to expiry, shifts starting and ending).
+type MapUpdatePublisher struct {
+	publisher   mapEventPublisher
+	hospitals   mapHospitalSource
+	occurrences mapOccurrenceSource
+	shifts      mapShiftSource
+	thresholds  UrgencyThresholdsSource
+
+	// Last published marker of each hospital
+	markers   map[uuid.UUID]mapMarker
+	markersMu sync.Mutex
+
+	checkInterval time.Duration
+	now           func() time.Time
+
+	running int32
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	logger *log.Logger
+}
+
+// NewMapUpdatePublisher creates a new map update publisher
+func NewMapUpdatePublisher(db *sql.DB, hub *SSEHub) *MapUpdatePublisher {
+	return newMapUpdatePublisher(hub, repository.NewHospitalRepository(db), repository.NewOccurrenceRepository(db), repository.NewShiftRepository(db))
+}
+
+// newMapUpdatePublisher creates a map update publisher from its dependencies
+func newMapUpdatePublisher(publisher mapEventPublisher, hospitals mapHospitalSource, occurrences mapOccurrenceSource, shifts mapShiftSource) *MapUpdatePublisher {
+	return &MapUpdatePublisher{
+		publisher:     publisher,
+		hospitals:     hospitals,
+		occurrences:   occurrences,
+		shifts:        shifts,
+		markers:       make(map[uuid.UUID]mapMarker),
+		checkInterval: DefaultMapUpdateInterval,
+		now:           time.Now,
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+		logger:        log.Default(),
+	}
+}
+
+// SetCheckInterval sets the interval between urgency and operator checks
+func (p *MapUpdatePublisher) SetCheckInterval(interval time.Duration) {
+	p.checkInterval = interval
+}
+
+// SetUrgencyThresholds sets the tenant urgency bands of the markers
+func (p *MapUpdatePublisher) SetUrgencyThresholds(source UrgencyThresholdsSource) {
+	p.thresholds = source
+}
+
+// SetLogger sets a custom logger
+func (p *MapUpdatePublisher) SetLogger(logger *log.Logger) {
+	p.logger = logger
+}
+
+// Start begins the periodic check loop
+func (p *MapUpdatePublisher) Start(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&p.running, 0, 1) {
+		return nil // Already running
+	}
+
+	p.logger.Println("[MapUpdates] Starting map update publisher")
+
+	go p.checkLoop(ctx)
+
+	return nil
+}
+
+// Stop stops the periodic check loop
+func (p *MapUpdatePublisher) Stop() {
+	if atomic.CompareAndSwapInt32(&p.running, 1, 0) {
+		close(p.stopCh)
+		<-p.doneCh
+		p.logger.Println("[MapUpdates] Map update publisher stopped")
+	}
+}
+
+// checkLoop is the main check loop
+func (p *MapUpdatePublisher) checkLoop(ctx context.Context) {
+	defer close(p.doneCh)
+
+	ticker := time.NewTicker(p.checkInterval)
+	defer ticker.Stop()
+
+	// Initial check records the current markers
+	p.CheckChanges(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.stopCh:
+			return
+		case <-ticker.C:
+			p.CheckChanges(ctx)
+		}
+	}
+}
+
+// PublishOccurrenceCreated publishes the marker of the hospital of a new occurrence
+func (p *MapUpdatePublisher) PublishOccurrenceCreated(ctx context.Context, occurrence *models.Occurrence) error {
+	return p.publishOccurrence(ctx, models.MapChangeOccurrenceCreated, occurrence)
+}
+
+// PublishOccurrenceResolved publishes the marker of the hospital of an occurrence that left the active statuses
+func (p *MapUpdatePublisher) PublishOccurrenceResolved(ctx context.Context, occurrence *models.Occurrence) error {
+	return p.publishOccurrence(ctx, models.MapChangeOccurrenceResolved, occurrence)
+}
+
+// publishOccurrence publishes the marker of the hospital of an occurrence, with the occurrence attached
+func (p *MapUpdatePublisher) publishOccurrence(ctx context.Context, change models.MapUpdateChange, occurrence *models.Occurrence) error {
+	hospital, err := p.hospitals.GetByID(ctx, occurrence.HospitalID)
+	if err != nil {
+		return err
+	}
+	if !isMapped(hospital) {
+		return nil
+	}
+
+	thresholds := LoadUrgencyThresholds(ctx, p.thresholds, p.logger).For(hospital.TenantID)
+	update, marker, err := p.snapshot(ctx, hospital, thresholds)
+	if err != nil {
+		return err
+	}
+	update.Change = change
+	mapOccurrence := occurrence.ToMapOccurrenceResponse(thresholds)
+	update.Ocorrencia = &mapOccurrence
+
+	return p.publish(ctx, hospital, update, marker)
+}
+
+// PublishShiftChange publishes the marker of a hospital whose shifts changed when its on-duty operator changed
+func (p *MapUpdatePublisher) PublishShiftChange(ctx context.Context, hospitalID uuid.UUID) error {
+	hospital, err := p.hospitals.GetByID(ctx, hospitalID)
+	if err != nil {
+		return err
+	}
+	if !isMapped(hospital) {
+		return nil
+	}
+
+	thresholds := LoadUrgencyThresholds(ctx, p.thresholds, p.logger).For(hospital.TenantID)
+	update, marker, err := p.snapshot(ctx, hospital, thresholds)
+	if err != nil {
+		return err
+	}
+
+	p.markersMu.Lock()
+	last, known := p.markers[hospital.ID]
+	p.markersMu.Unlock()
+	if known && last.operatorID == marker.operatorID {
+		return nil
+	}
+
+	update.Change = models.MapChangeOperator
+	return p.publish(ctx, hospital, update, marker)
+}
+
+// CheckChanges compares the markers of the mapped hospitals with the last published ones and
+// publishes those that changed. Hospitals seen for the first time are only recorded.
+func (p *MapUpdatePublisher) CheckChanges(ctx context.Context) {
+	hospitals, err := p.hospitals.GetActiveHospitalsWithCoordinates(ctx)
+	if err != nil {
+		p.logger.Printf("[MapUpdates] Failed to list hospitals: %v", err)
+		return
+	}
+
+	thresholds := LoadUrgencyThresholds(ctx, p.thresholds, p.logger)
+	for i := range hospitals {
+		hospital := &hospitals[i]
+		update, marker, err := p.snapshot(ctx, hospital, thresholds.For(hospital.TenantID))
+		if err != nil {
+			p.logger.Printf("[MapUpdates] Failed to load marker of hospital %s: %v", hospital.ID, err)
+			continue
+		}
+
+		p.markersMu.Lock()
+		last, known := p.markers[hospital.ID]
+		if !known {
+			p.markers[hospital.ID] = marker
+		}
+		p.markersMu.Unlock()
+		if !known {
+			continue
+		}
+
+		// A pending occurrence that expired leaves the active statuses without going through the occurrence flow
+		switch {
+		case marker.count < last.count:
+			update.Change = models.MapChangeOccurrenceResolved
+		case marker.count > last.count:
+			update.Change = models.MapChangeOccurrenceCreated
+		case marker.urgency != last.urgency:
+			update.Change = models.MapChangeUrgency
+		case marker.operatorID != last.operatorID:
+			update.Change = models.MapChangeOperator
+		default:
+			continue
+		}
+
+		if err := p.publish(ctx, hospital, update, marker); err != nil {
+			p.logger.Printf("[MapUpdates] Failed to publish marker of hospital %s: %v", hospital.ID, err)
+		}
+	}
+}
+
+// publish publishes a marker update and records it as the last published marker of the hospital
+func (p *MapUpdatePublisher) publish(ctx context.Context, hospital *models.Hospital, update *models.MapUpdate, marker mapMarker) error {
+	event := models.NewMapUpdateSSEEvent(update, hospital.Nome, hospital.TenantID)
+	if err := p.publisher.PublishEvent(ctx, &event); err != nil {
+		return err
+	}
+
+	p.markersMu.Lock()
+	p.markers[hospital.ID] = marker
+	p.markersMu.Unlock()
+	return nil
+}
+
+// snapshot loads the current marker of a hospital: active occurrences, max urgency and operator on duty
+func (p *MapUpdatePublisher) snapshot(ctx context.Context, hospital *models.Hospital, thresholds models.UrgencyThresholds) (*models.MapUpdate, mapMarker, error) {
+	hospitalID := hospital.ID.String()
+
+	var active []models.Occurrence
+	for _, status := range []models.OccurrenceStatus{models.StatusPendente, models.StatusEmAndamento} {
+		status := status
+		occurrences, _, err := p.occurrences.List(ctx, models.OccurrenceListFilters{
+			Status:     &status,
+			HospitalID: &hospitalID,
+			Page:       1,
+			PageSize:   100, // Same cap as the map endpoint
+		})
+		if err != nil {
+			return nil, mapMarker{}, err
+		}
+		active = append(active, occurrences...)
+	}
+
+	update := &models.MapUpdate{
+		HospitalID:       hospital.ID,
+		UrgenciaMaxima:   models.CalculateMaxUrgency(active, thresholds),
+		OcorrenciasCount: len(active),
+	}
+	marker := mapMarker{urgency: update.UrgenciaMaxima, count: update.OcorrenciasCount}
+
+	// Shifts are wall-clock times in the hospital's timezone, resolved by the shift source
+	shifts, err := p.shifts.GetActiveShiftsAt(ctx, hospital.ID, p.now())
+	if err == nil && len(shifts) > 0 && shifts[0].User != nil {
+		update.OperadorPlantao = &models.MapOperatorResponse{
+			ID:     shifts[0].User.ID,
+			Nome:   shifts[0].User.Nome,
+			UserID: shifts[0].UserID,
+		}
+		marker.operatorID = shifts[0].UserID
+	}
+
+	return update, marker, nil
+}
+
+// isMapped checks if the hospital is shown on the map
+func isMapped(hospital *models.Hospital) bool {
+	return hospital.Ativo && hospital.DeletedAt == nil && hospital.Latitude != nil && hospital.Longitude != nil
+}
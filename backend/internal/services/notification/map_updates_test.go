@@ -0,0 +1,295 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// fakeMapEventPublisher records the published events
+type fakeMapEventPublisher struct {
+	events []models.SSEEvent
+}
+
+func (f *fakeMapEventPublisher) PublishEvent(ctx context.Context, event *models.SSEEvent) error {
+	f.events = append(f.events, *event)
+	return nil
+}
+
+// fakeMapSources simulates the hospital, occurrence and shift repositories
+type fakeMapSources struct {
+	hospitals   []models.Hospital
+	occurrences []models.Occurrence
+	shifts      []models.Shift
+}
+
+func (f *fakeMapSources) GetByID(ctx context.Context, id uuid.UUID) (*models.Hospital, error) {
+	for i := range f.hospitals {
+		if f.hospitals[i].ID == id {
+			return &f.hospitals[i], nil
+		}
+	}
+	return nil, errors.New("hospital not found")
+}
+
+func (f *fakeMapSources) GetActiveHospitalsWithCoordinates(ctx context.Context) ([]models.Hospital, error) {
+	var result []models.Hospital
+	for _, h := range f.hospitals {
+		if isMapped(&h) {
+			result = append(result, h)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeMapSources) List(ctx context.Context, filters models.OccurrenceListFilters) ([]models.Occurrence, int, error) {
+	var result []models.Occurrence
+	for _, o := range f.occurrences {
+		if o.HospitalID.String() == *filters.HospitalID && o.Status == *filters.Status {
+			result = append(result, o)
+		}
+	}
+	return result, len(result), nil
+}
+
+func (f *fakeMapSources) GetActiveShiftsAt(ctx context.Context, hospitalID uuid.UUID, at time.Time) ([]models.Shift, error) {
+	var result []models.Shift
+	for _, s := range f.shifts {
+		if s.HospitalID == hospitalID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func newTestMapUpdatePublisher() (*MapUpdatePublisher, *fakeMapEventPublisher, *fakeMapSources) {
+	events := &fakeMapEventPublisher{}
+	sources := &fakeMapSources{}
+	publisher := newMapUpdatePublisher(events, sources, sources, sources)
+	publisher.SetLogger(log.New(io.Discard, "", 0))
+	return publisher, events, sources
+}
+
+func mapTestHospital(nome string, mapped bool) models.Hospital {
+	hospital := models.Hospital{ID: uuid.New(), TenantID: uuid.New(), Nome: nome, Ativo: true}
+	if mapped {
+		lat, lng := -16.6868, -49.2648
+		hospital.Latitude = &lat
+		hospital.Longitude = &lng
+	}
+	return hospital
+}
+
+func mapTestOccurrence(hospitalID uuid.UUID, remaining time.Duration) models.Occurrence {
+	return models.Occurrence{
+		ID:             uuid.New(),
+		HospitalID:     hospitalID,
+		Status:         models.StatusPendente,
+		JanelaExpiraEm: time.Now().Add(remaining),
+	}
+}
+
+// TestMapUpdateNewOccurrenceAtMappedHospital tests that a new occurrence at a mapped hospital emits a map update
+func TestMapUpdateNewOccurrenceAtMappedHospital(t *testing.T) {
+	publisher, events, sources := newTestMapUpdatePublisher()
+
+	hospital := mapTestHospital("Hospital Geral", true)
+	operatorID := uuid.New()
+	sources.hospitals = append(sources.hospitals, hospital)
+	sources.shifts = append(sources.shifts, models.Shift{
+		ID: uuid.New(), HospitalID: hospital.ID, UserID: operatorID,
+		User: &models.User{ID: operatorID, Nome: "Maria Operadora"},
+	})
+	occurrence := mapTestOccurrence(hospital.ID, 90*time.Minute)
+	sources.occurrences = append(sources.occurrences, occurrence)
+
+	if err := publisher.PublishOccurrenceCreated(context.Background(), &occurrence); err != nil {
+		t.Fatalf("PublishOccurrenceCreated returned error: %v", err)
+	}
+
+	if len(events.events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events.events))
+	}
+	event := events.events[0]
+	if event.Type != models.SSEEventTypeMapUpdate || event.Topic != models.SSETopicMap {
+		t.Errorf("Expected map_update event on the map topic, got %s/%q", event.Type, event.Topic)
+	}
+	if event.TenantID != hospital.TenantID.String() {
+		t.Errorf("Expected the event of tenant %s, got %q", hospital.TenantID, event.TenantID)
+	}
+	if event.OccurrenceID != occurrence.ID || event.HospitalNome != hospital.Nome {
+		t.Errorf("Unexpected occurrence or hospital in event: %+v", event)
+	}
+	if event.Map == nil {
+		t.Fatal("Expected map update payload")
+	}
+	update := event.Map
+	if update.Change != models.MapChangeOccurrenceCreated || update.HospitalID != hospital.ID {
+		t.Errorf("Expected occurrence_created for hospital %s, got %s for %s", hospital.ID, update.Change, update.HospitalID)
+	}
+	if update.OcorrenciasCount != 1 || update.UrgenciaMaxima != models.UrgencyRed {
+		t.Errorf("Expected 1 red occurrence, got %d %s", update.OcorrenciasCount, update.UrgenciaMaxima)
+	}
+	if update.OperadorPlantao == nil || update.OperadorPlantao.Nome != "Maria Operadora" {
+		t.Errorf("Expected operator on duty in update, got %+v", update.OperadorPlantao)
+	}
+	if update.Ocorrencia == nil || update.Ocorrencia.ID != occurrence.ID {
+		t.Errorf("Expected occurrence in update, got %+v", update.Ocorrencia)
+	}
+
+	// Hospitals without coordinates are not on the map
+	unmapped := mapTestHospital("Hospital Sem Coordenadas", false)
+	sources.hospitals = append(sources.hospitals, unmapped)
+	other := mapTestOccurrence(unmapped.ID, 90*time.Minute)
+	if err := publisher.PublishOccurrenceCreated(context.Background(), &other); err != nil {
+		t.Fatalf("PublishOccurrenceCreated returned error: %v", err)
+	}
+	if len(events.events) != 1 {
+		t.Errorf("Expected no event for an unmapped hospital, got %d events", len(events.events))
+	}
+}
+
+// TestMapUpdateCheckChanges tests that the periodic check publishes urgency and operator changes only
+func TestMapUpdateCheckChanges(t *testing.T) {
+	publisher, events, sources := newTestMapUpdatePublisher()
+	ctx := context.Background()
+
+	hospital := mapTestHospital("Hospital Geral", true)
+	sources.hospitals = append(sources.hospitals, hospital)
+	sources.occurrences = append(sources.occurrences, mapTestOccurrence(hospital.ID, 3*time.Hour))
+
+	// First check only records the markers
+	publisher.CheckChanges(ctx)
+	publisher.CheckChanges(ctx)
+	if len(events.events) != 0 {
+		t.Fatalf("Expected no event without changes, got %d", len(events.events))
+	}
+
+	// The capture window got closer to expiry
+	sources.occurrences[0].JanelaExpiraEm = time.Now().Add(time.Hour)
+	publisher.CheckChanges(ctx)
+	if len(events.events) != 1 || events.events[0].Map.Change != models.MapChangeUrgency {
+		t.Fatalf("Expected urgency_changed event, got %+v", events.events)
+	}
+	if events.events[0].Map.UrgenciaMaxima != models.UrgencyRed {
+		t.Errorf("Expected red urgency, got %s", events.events[0].Map.UrgenciaMaxima)
+	}
+
+	// A shift started
+	operatorID := uuid.New()
+	sources.shifts = append(sources.shifts, models.Shift{
+		ID: uuid.New(), HospitalID: hospital.ID, UserID: operatorID,
+		User: &models.User{ID: operatorID, Nome: "Joao Operador"},
+	})
+	publisher.CheckChanges(ctx)
+	if len(events.events) != 2 || events.events[1].Map.Change != models.MapChangeOperator {
+		t.Fatalf("Expected operator_changed event, got %+v", events.events)
+	}
+
+	// Editing the shifts without changing the operator on duty publishes nothing
+	if err := publisher.PublishShiftChange(ctx, hospital.ID); err != nil {
+		t.Fatalf("PublishShiftChange returned error: %v", err)
+	}
+	if len(events.events) != 2 {
+		t.Errorf("Expected no event when the operator on duty is unchanged, got %d events", len(events.events))
+	}
+}
+
+// TestSSEHubRoutesEventsByTopic tests that topic events only reach the clients of the topic
+func TestSSEHubRoutesEventsByTopic(t *testing.T) {
+	hub := NewSSEHub(nil, nil)
+	hub.SetLogger(log.New(io.Discard, "", 0))
+
+	notifications := NewSSEClient("user-1", "operador")
+	mapClient := NewTopicSSEClient("user-2", "operador", models.SSETopicMap)
+	mapClient.SetTenant("", true)
+	hub.RegisterClient(notifications)
+	hub.RegisterClient(mapClient)
+
+	mapEvent := models.NewMapUpdateSSEEvent(&models.MapUpdate{Change: models.MapChangeOperator, HospitalID: uuid.New()}, "Hospital Geral", uuid.New())
+	if err := hub.PublishEvent(context.Background(), &mapEvent); err != nil {
+		t.Fatalf("PublishEvent returned error: %v", err)
+	}
+	newOccurrence := models.SSEEvent{Type: "new_occurrence", OccurrenceID: uuid.New()}
+	if err := hub.PublishEvent(context.Background(), &newOccurrence); err != nil {
+		t.Fatalf("PublishEvent returned error: %v", err)
+	}
+
+	if len(mapClient.Channel) != 1 || (<-mapClient.Channel).Type != models.SSEEventTypeMapUpdate {
+		t.Error("Expected the map client to receive only the map update")
+	}
+	if len(notifications.Channel) != 1 || (<-notifications.Channel).Type != "new_occurrence" {
+		t.Error("Expected the notification client to receive only the new occurrence")
+	}
+}
+
+// TestSSEHubRoutesMapEventsByTenant tests that map updates only reach the map clients of the hospital's tenant and super admins
+func TestSSEHubRoutesMapEventsByTenant(t *testing.T) {
+	hub := NewSSEHub(nil, nil)
+	hub.SetLogger(log.New(io.Discard, "", 0))
+
+	tenantID := uuid.New()
+	sameTenant := NewTopicSSEClient("user-1", "operador", models.SSETopicMap)
+	sameTenant.SetTenant(tenantID.String(), false)
+	otherTenant := NewTopicSSEClient("user-2", "operador", models.SSETopicMap)
+	otherTenant.SetTenant(uuid.New().String(), false)
+	superAdmin := NewTopicSSEClient("user-3", "admin", models.SSETopicMap)
+	superAdmin.SetTenant(uuid.New().String(), true)
+	for _, client := range []*SSEClient{sameTenant, otherTenant, superAdmin} {
+		hub.RegisterClient(client)
+	}
+
+	mapEvent := models.NewMapUpdateSSEEvent(&models.MapUpdate{Change: models.MapChangeOperator, HospitalID: uuid.New()}, "Hospital Geral", tenantID)
+	if err := hub.PublishEvent(context.Background(), &mapEvent); err != nil {
+		t.Fatalf("PublishEvent returned error: %v", err)
+	}
+
+	if len(sameTenant.Channel) != 1 {
+		t.Errorf("Expected the client of the tenant to receive the update, got %d events", len(sameTenant.Channel))
+	}
+	if len(otherTenant.Channel) != 0 {
+		t.Errorf("Expected the client of another tenant to receive nothing, got %d events", len(otherTenant.Channel))
+	}
+	if len(superAdmin.Channel) != 1 {
+		t.Errorf("Expected the super admin to receive the update, got %d events", len(superAdmin.Channel))
+	}
+}
+
+// TestSSEHubRoutesEscalationEventsByTenant tests that an escalation only reaches the clients of the occurrence's tenant and super admins
+func TestSSEHubRoutesEscalationEventsByTenant(t *testing.T) {
+	hub := NewSSEHub(nil, nil)
+	hub.SetLogger(log.New(io.Discard, "", 0))
+
+	tenantID := uuid.New()
+	sameTenant := NewSSEClient("user-1", "operador")
+	sameTenant.SetTenant(tenantID.String(), false)
+	otherTenant := NewSSEClient("user-2", "operador")
+	otherTenant.SetTenant(uuid.New().String(), false)
+	superAdmin := NewSSEClient("user-3", "admin")
+	superAdmin.SetTenant(uuid.New().String(), true)
+	for _, client := range []*SSEClient{sameTenant, otherTenant, superAdmin} {
+		hub.RegisterClient(client)
+	}
+
+	occurrence := &models.Occurrence{ID: uuid.New(), HospitalID: uuid.New(), TenantID: tenantID, Status: models.StatusPendente}
+	event := models.NewEscalationSSEEvent(occurrence, "Hospital Geral")
+	if err := hub.PublishEvent(context.Background(), &event); err != nil {
+		t.Fatalf("PublishEvent returned error: %v", err)
+	}
+
+	if len(sameTenant.Channel) != 1 {
+		t.Errorf("Expected the client of the tenant to receive the escalation, got %d events", len(sameTenant.Channel))
+	}
+	if len(otherTenant.Channel) != 0 {
+		t.Errorf("Expected the client of another tenant to receive nothing, got %d events", len(otherTenant.Channel))
+	}
+	if len(superAdmin.Channel) != 1 {
+		t.Errorf("Expected the super admin to receive the escalation, got %d events", len(superAdmin.Channel))
+	}
+}
@@ -84,6 +84,32 @@ func TestSSEEventPublish(t *testing.T) {
 	}
 }
 
+// TestInfrastructureRecoveryTemplate tests recovery email rendering
+func TestInfrastructureRecoveryTemplate(t *testing.T) {
+	service := NewEmailService(&EmailConfig{})
+
+	downSince := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	data := &InfrastructureAlertData{
+		ServiceName:  "Redis",
+		Status:       "UP",
+		Timestamp:    downSince.Add(12 * time.Minute),
+		DownSince:    downSince,
+		Message:      "O servico Redis voltou a responder normalmente.",
+		DashboardURL: "http://localhost:3000/dashboard/status",
+	}
+
+	body, err := service.renderInfrastructureRecoveryTemplate(data)
+	if err != nil {
+		t.Fatalf("Failed to render recovery template: %v", err)
+	}
+
+	for _, expected := range []string{"SERVICO RESTABELECIDO", data.ServiceName, "14/10/2026 09:00:00", "14/10/2026 09:12:00", data.Message, data.DashboardURL} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Recovery email body should contain %q", expected)
+		}
+	}
+}
+
 // TestEmailTemplateFormat tests email template rendering
 func TestEmailTemplateFormat(t *testing.T) {
 	// Create email service (without SMTP config)
@@ -0,0 +1,256 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// OccurrenceRecipientSource lists the users of a hospital notified about new occurrences
+// (implemented by repository.UserRepository)
+type OccurrenceRecipientSource interface {
+	ListByRoleAndHospital(ctx context.Context, role string, hospitalID uuid.UUID) ([]models.User, error)
+}
+
+// NotificationPreferencesSource loads per-user channel preferences
+// (implemented by repository.UserNotificationPreferencesRepository)
+type NotificationPreferencesSource interface {
+	GetByUserIDs(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]*models.UserNotificationPreferences, error)
+}
+
+// EmailEnqueuer queues occurrence emails (implemented by EmailQueueWorker)
+type EmailEnqueuer interface {
+	EnqueueEmail(ctx context.Context, occurrenceID uuid.UUID, to string, userID *uuid.UUID, data *ObitoNotificationData) error
+}
+
+// SMSEnqueuer queues occurrence SMS messages (implemented by SMSQueueWorker)
+type SMSEnqueuer interface {
+	EnqueueSMS(ctx context.Context, occurrenceID uuid.UUID, phoneNumber string, userID *uuid.UUID, message string) error
+}
+
+// PushSubscriptionSource lists the devices registered by a user
+// (implemented by repository.PushSubscriptionRepository)
+type PushSubscriptionSource interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]models.PushSubscription, error)
+}
+
+// PushSender delivers push notifications to a user's devices (implemented by PushService)
+type PushSender interface {
+	SendToUser(ctx context.Context, subscriptions []models.PushSubscription, payload *PushPayload) (*PushSendSummary, error)
+}
+
+// UrgencyThresholdsSource loads the urgency bands of the tenants
+// (implemented by repository.AdminSettingsRepository)
+type UrgencyThresholdsSource interface {
+	GetUrgencyThresholdsConfig(ctx context.Context) (*models.UrgencyThresholdsConfig, error)
+}
+
+// LoadUrgencyThresholds loads the urgency bands of the tenants
+// A nil config (no source or a failed load) applies the built-in bands
+func LoadUrgencyThresholds(ctx context.Context, source UrgencyThresholdsSource, logger *log.Logger) *models.UrgencyThresholdsConfig {
+	if source == nil {
+		return nil
+	}
+	config, err := source.GetUrgencyThresholdsConfig(ctx)
+	if err != nil {
+		logger.Printf("Warning: Failed to load urgency thresholds, using the defaults: %v", err)
+		return nil
+	}
+	return config
+}
+
+// OccurrenceNotifier sends email, SMS and push notifications for new occurrences
+// honoring each user's notification preferences and quiet hours
+type OccurrenceNotifier struct {
+	users         OccurrenceRecipientSource
+	preferences   NotificationPreferencesSource
+	email         EmailEnqueuer
+	sms           SMSEnqueuer
+	push          PushSender
+	subscriptions PushSubscriptionSource
+	thresholds    UrgencyThresholdsSource
+	dashboardURL  string
+	now           func() time.Time
+}
+
+// NewOccurrenceNotifier creates a new occurrence notifier
+// Channels are enabled by calling SetEmailQueue, SetSMSQueue and SetPush
+func NewOccurrenceNotifier(users OccurrenceRecipientSource, preferences NotificationPreferencesSource) *OccurrenceNotifier {
+	return &OccurrenceNotifier{
+		users:        users,
+		preferences:  preferences,
+		dashboardURL: "http://localhost:3000",
+		now:          time.Now,
+	}
+}
+
+// SetUrgencyThresholds sets the tenant urgency bands that mark an occurrence as critical
+func (n *OccurrenceNotifier) SetUrgencyThresholds(source UrgencyThresholdsSource) {
+	n.thresholds = source
+}
+
+// SetEmailQueue enables the email channel
+func (n *OccurrenceNotifier) SetEmailQueue(queue EmailEnqueuer) {
+	n.email = queue
+}
+
+// SetSMSQueue enables the SMS channel
+func (n *OccurrenceNotifier) SetSMSQueue(queue SMSEnqueuer) {
+	n.sms = queue
+}
+
+// SetPush enables the push channel
+func (n *OccurrenceNotifier) SetPush(sender PushSender, subscriptions PushSubscriptionSource) {
+	n.push = sender
+	n.subscriptions = subscriptions
+}
+
+// SetDashboardURL sets the dashboard base URL used in notification links
+func (n *OccurrenceNotifier) SetDashboardURL(url string) {
+	if url != "" {
+		n.dashboardURL = url
+	}
+}
+
+// NotifyNewOccurrence notifies the operators of the occurrence's hospital on every enabled channel,
+// or its gestores when no operator covers the hospital
+// Occurrences with less than 2 hours of window left are critical and bypass quiet hours
+func (n *OccurrenceNotifier) NotifyNewOccurrence(ctx context.Context, occurrence *models.Occurrence, hospitalNome string) {
+	if n.email == nil && n.sms == nil && n.push == nil {
+		return
+	}
+
+	operators, err := n.recipients(ctx, occurrence.HospitalID)
+	if err != nil {
+		log.Printf("Warning: Failed to get operators for occurrence notification: %v", err)
+		return
+	}
+	if len(operators) == 0 {
+		log.Printf("Warning: No operator or gestor linked to hospital %s for occurrence %s", occurrence.HospitalID, occurrence.ID)
+		return
+	}
+
+	userIDs := make([]uuid.UUID, len(operators))
+	for i, operator := range operators {
+		userIDs[i] = operator.ID
+	}
+
+	prefs, err := n.preferences.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		// Fall back to default preferences rather than dropping the notification
+		log.Printf("Warning: Failed to load notification preferences: %v", err)
+	}
+
+	var completeData models.OccurrenceCompleteData
+	if err := json.Unmarshal(occurrence.DadosCompletos, &completeData); err != nil {
+		log.Printf("Warning: Failed to parse occurrence data for notification: %v", err)
+		return
+	}
+
+	now := n.now()
+	remainingMinutes := int(occurrence.JanelaExpiraEm.Sub(now).Minutes())
+	thresholds := LoadUrgencyThresholds(ctx, n.thresholds, log.Default()).For(occurrence.TenantID)
+	critical := models.CalculateUrgencyLevel(remainingMinutes, thresholds) == models.UrgencyRed
+
+	if n.email != nil {
+		emailData := &ObitoNotificationData{
+			HospitalNome:  hospitalNome,
+			Setor:         completeData.Setor,
+			HoraObito:     occurrence.DataObito,
+			TempoRestante: occurrence.FormatTimeRemaining(),
+			OccurrenceID:  occurrence.ID.String(),
+			Prioridade:    occurrence.ScorePriorizacao,
+			DashboardURL:  n.dashboardURL + "/dashboard",
+		}
+
+		for _, operator := range FilterRecipients(operators, prefs, models.ChannelEmail, critical, now) {
+			userID := operator.ID
+			recipientData := *emailData
+			recipientData.Locale = operator.PreferredLocale()
+			err := n.email.EnqueueEmail(ctx, occurrence.ID, operator.Email, &userID, &recipientData)
+			if err != nil && !errors.Is(err, ErrEmailSuppressed) {
+				log.Printf("Warning: Failed to queue email for %s: %v", operator.Email, err)
+			}
+		}
+	}
+
+	if n.sms != nil {
+		message := BuildSMSMessage(&SMSNotificationData{
+			HospitalNome:  hospitalNome,
+			Idade:         completeData.Idade,
+			HorasRestante: remainingMinutes / 60,
+			OccurrenceID:  occurrence.ID,
+			BaseURL:       n.dashboardURL,
+		})
+
+		for _, operator := range FilterRecipients(operators, prefs, models.ChannelSMS, critical, now) {
+			userID := operator.ID
+			if err := n.sms.EnqueueSMS(ctx, occurrence.ID, *operator.MobilePhone, &userID, message); err != nil {
+				log.Printf("Warning: Failed to queue SMS for %s: %v", MaskPhoneForLog(*operator.MobilePhone), err)
+			}
+		}
+	}
+
+	if n.push != nil && n.subscriptions != nil {
+		payload := NewOccurrenceNotificationPayload(hospitalNome, completeData.Setor, remainingMinutes, occurrence.ID.String(), n.dashboardURL)
+
+		for _, operator := range FilterRecipients(operators, prefs, models.ChannelPush, critical, now) {
+			subscriptions, err := n.subscriptions.GetByUserID(ctx, operator.ID)
+			if err != nil || len(subscriptions) == 0 {
+				continue
+			}
+			if _, err := n.push.SendToUser(ctx, subscriptions, payload); err != nil {
+				log.Printf("Warning: Failed to send push to user %s: %v", operator.ID, err)
+			}
+		}
+	}
+}
+
+// recipients returns the operators linked to the hospital, falling back to its gestores
+func (n *OccurrenceNotifier) recipients(ctx context.Context, hospitalID uuid.UUID) ([]models.User, error) {
+	operators, err := n.users.ListByRoleAndHospital(ctx, string(models.RoleOperador), hospitalID)
+	if err != nil {
+		return nil, err
+	}
+	if len(operators) > 0 {
+		return operators, nil
+	}
+	return n.users.ListByRoleAndHospital(ctx, string(models.RoleGestor), hospitalID)
+}
+
+// FilterRecipients returns the users that accept a notification on the channel at time now
+// Users without stored preferences get the defaults (SMS only when a mobile phone is set)
+func FilterRecipients(users []models.User, prefs map[uuid.UUID]*models.UserNotificationPreferences, channel models.NotificationChannel, critical bool, now time.Time) []models.User {
+	var recipients []models.User
+	for _, user := range users {
+		switch channel {
+		case models.ChannelEmail:
+			if !user.CanReceiveEmailNotifications() {
+				continue
+			}
+		case models.ChannelSMS:
+			if !user.CanReceiveSMSNotifications() {
+				continue
+			}
+		default:
+			if !user.Ativo {
+				continue
+			}
+		}
+
+		userPrefs, ok := prefs[user.ID]
+		if !ok {
+			userPrefs = models.DefaultPreferences(user.ID, user.CanReceiveSMSNotifications())
+		}
+
+		if userPrefs.AllowsNotification(channel, critical, now) {
+			recipients = append(recipients, user)
+		}
+	}
+	return recipients
+}
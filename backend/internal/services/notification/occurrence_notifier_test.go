@@ -0,0 +1,265 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// notifierTestHospitalID is the hospital of the test occurrences and operators
+var notifierTestHospitalID = uuid.MustParse("7c9e6679-7425-40de-944b-e07fc1f90ae7")
+
+// mockRecipientSource returns the users with the role linked to the hospital
+type mockRecipientSource struct {
+	users []models.User
+}
+
+func (s *mockRecipientSource) ListByRoleAndHospital(ctx context.Context, role string, hospitalID uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	for _, user := range s.users {
+		if string(user.Role) != role {
+			continue
+		}
+		for _, hospital := range user.Hospitals {
+			if hospital.ID == hospitalID {
+				users = append(users, user)
+				break
+			}
+		}
+	}
+	return users, nil
+}
+
+// mockPreferencesSource returns stored preferences keyed by user ID
+type mockPreferencesSource struct {
+	prefs map[uuid.UUID]*models.UserNotificationPreferences
+}
+
+func (s *mockPreferencesSource) GetByUserIDs(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]*models.UserNotificationPreferences, error) {
+	return s.prefs, nil
+}
+
+// recordingQueue records the recipients of queued emails and SMS messages
+type recordingQueue struct {
+	emails  []uuid.UUID
+	sms     []uuid.UUID
+	locales map[uuid.UUID]string
+}
+
+func (q *recordingQueue) EnqueueEmail(ctx context.Context, occurrenceID uuid.UUID, to string, userID *uuid.UUID, data *ObitoNotificationData) error {
+	q.emails = append(q.emails, *userID)
+	if q.locales == nil {
+		q.locales = make(map[uuid.UUID]string)
+	}
+	q.locales[*userID] = data.Locale
+	return nil
+}
+
+func (q *recordingQueue) EnqueueSMS(ctx context.Context, occurrenceID uuid.UUID, phoneNumber string, userID *uuid.UUID, message string) error {
+	q.sms = append(q.sms, *userID)
+	return nil
+}
+
+// notifierTestOperator creates an active operator with email and mobile phone
+func notifierTestOperator(nome string) models.User {
+	phone := "+5562999999999"
+	return models.User{
+		ID:                 uuid.New(),
+		Nome:               nome,
+		Email:              nome + "@sidot.gov.br",
+		Role:               models.RoleOperador,
+		MobilePhone:        &phone,
+		EmailNotifications: true,
+		Ativo:              true,
+		Hospitals:          []models.Hospital{{ID: notifierTestHospitalID}},
+	}
+}
+
+// notifierTestOccurrence creates an occurrence with the given window remaining at now
+func notifierTestOccurrence(now time.Time, remaining time.Duration) *models.Occurrence {
+	data, _ := json.Marshal(models.OccurrenceCompleteData{Setor: "UTI", Idade: 45})
+	return &models.Occurrence{
+		ID:             uuid.New(),
+		HospitalID:     notifierTestHospitalID,
+		DataObito:      now.Add(-time.Hour),
+		JanelaExpiraEm: now.Add(remaining),
+		DadosCompletos: data,
+	}
+}
+
+func containsUser(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// TestNotifyNewOccurrenceExcludesSMSDisabled tests that a user with SMS disabled receives no SMS
+func TestNotifyNewOccurrenceExcludesSMSDisabled(t *testing.T) {
+	smsOn := notifierTestOperator("sms_on")
+	smsOff := notifierTestOperator("sms_off")
+	noPrefs := notifierTestOperator("no_prefs")
+
+	prefs := map[uuid.UUID]*models.UserNotificationPreferences{
+		smsOn.ID:  models.DefaultPreferences(smsOn.ID, true),
+		smsOff.ID: models.DefaultPreferences(smsOff.ID, true),
+	}
+	prefs[smsOff.ID].SMSEnabled = false
+
+	queue := &recordingQueue{}
+	notifier := NewOccurrenceNotifier(
+		&mockRecipientSource{users: []models.User{smsOn, smsOff, noPrefs}},
+		&mockPreferencesSource{prefs: prefs},
+	)
+	notifier.SetEmailQueue(queue)
+	notifier.SetSMSQueue(queue)
+
+	now := time.Date(2026, 10, 14, 14, 0, 0, 0, time.Local)
+	notifier.now = func() time.Time { return now }
+	notifier.NotifyNewOccurrence(context.Background(), notifierTestOccurrence(now, 5*time.Hour), "Hospital Teste")
+
+	if len(queue.sms) != 2 || containsUser(queue.sms, smsOff.ID) {
+		t.Errorf("Expected SMS only for sms_on and no_prefs, got %v", queue.sms)
+	}
+	if !containsUser(queue.sms, noPrefs.ID) {
+		t.Error("Expected user without stored preferences to get the SMS default")
+	}
+	if len(queue.emails) != 3 {
+		t.Errorf("Expected email for all 3 operators, got %d", len(queue.emails))
+	}
+}
+
+// TestNotifyNewOccurrenceQuietHours tests that quiet hours suppress non-critical notifications only
+func TestNotifyNewOccurrenceQuietHours(t *testing.T) {
+	quiet := notifierTestOperator("quiet")
+	awake := notifierTestOperator("awake")
+
+	start, end := models.ShiftTime("22:00"), models.ShiftTime("07:00")
+	quietPrefs := models.DefaultPreferences(quiet.ID, true)
+	quietPrefs.QuietHoursStart = &start
+	quietPrefs.QuietHoursEnd = &end
+
+	prefs := map[uuid.UUID]*models.UserNotificationPreferences{quiet.ID: quietPrefs}
+	night := time.Date(2026, 10, 14, 23, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name          string
+		remaining     time.Duration
+		expectedQuiet bool
+	}{
+		{"non-critical suppressed", 5 * time.Hour, false},
+		{"critical bypasses quiet hours", 90 * time.Minute, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			queue := &recordingQueue{}
+			notifier := NewOccurrenceNotifier(
+				&mockRecipientSource{users: []models.User{quiet, awake}},
+				&mockPreferencesSource{prefs: prefs},
+			)
+			notifier.SetEmailQueue(queue)
+			notifier.SetSMSQueue(queue)
+			notifier.now = func() time.Time { return night }
+
+			notifier.NotifyNewOccurrence(context.Background(), notifierTestOccurrence(night, tc.remaining), "Hospital Teste")
+
+			if got := containsUser(queue.emails, quiet.ID); got != tc.expectedQuiet {
+				t.Errorf("Email to user in quiet hours: got %v, expected %v", got, tc.expectedQuiet)
+			}
+			if got := containsUser(queue.sms, quiet.ID); got != tc.expectedQuiet {
+				t.Errorf("SMS to user in quiet hours: got %v, expected %v", got, tc.expectedQuiet)
+			}
+			if !containsUser(queue.emails, awake.ID) || !containsUser(queue.sms, awake.ID) {
+				t.Error("Expected user without quiet hours to be notified")
+			}
+		})
+	}
+
+	// Outside quiet hours the same user is notified
+	morning := time.Date(2026, 10, 15, 9, 0, 0, 0, time.Local)
+	if recipients := FilterRecipients([]models.User{quiet}, prefs, models.ChannelEmail, false, morning); len(recipients) != 1 {
+		t.Errorf("Expected user to be notified outside quiet hours, got %d recipients", len(recipients))
+	}
+}
+
+// TestNotifyNewOccurrenceEmailLocale tests that each email is queued in its recipient locale
+func TestNotifyNewOccurrenceEmailLocale(t *testing.T) {
+	english := notifierTestOperator("english")
+	english.Locale = models.LocaleEn
+	unset := notifierTestOperator("unset")
+
+	queue := &recordingQueue{}
+	notifier := NewOccurrenceNotifier(
+		&mockRecipientSource{users: []models.User{english, unset}},
+		&mockPreferencesSource{},
+	)
+	notifier.SetEmailQueue(queue)
+
+	now := time.Date(2026, 10, 14, 14, 0, 0, 0, time.Local)
+	notifier.now = func() time.Time { return now }
+	notifier.NotifyNewOccurrence(context.Background(), notifierTestOccurrence(now, 5*time.Hour), "Hospital Teste")
+
+	if queue.locales[english.ID] != models.LocaleEn {
+		t.Errorf("Expected en email for english, got %q", queue.locales[english.ID])
+	}
+	if queue.locales[unset.ID] != models.LocalePtBR {
+		t.Errorf("Expected pt-BR email for user without locale, got %q", queue.locales[unset.ID])
+	}
+}
+
+// TestNotifyNewOccurrenceHospitalScope tests that only the operators of the occurrence's hospital are notified
+func TestNotifyNewOccurrenceHospitalScope(t *testing.T) {
+	local := notifierTestOperator("local")
+	other := notifierTestOperator("other")
+	other.Hospitals = []models.Hospital{{ID: uuid.New()}}
+	gestor := notifierTestOperator("gestor")
+	gestor.Role = models.RoleGestor
+
+	queue := &recordingQueue{}
+	notifier := NewOccurrenceNotifier(
+		&mockRecipientSource{users: []models.User{local, other, gestor}},
+		&mockPreferencesSource{},
+	)
+	notifier.SetEmailQueue(queue)
+
+	now := time.Date(2026, 10, 14, 14, 0, 0, 0, time.Local)
+	notifier.now = func() time.Time { return now }
+	notifier.NotifyNewOccurrence(context.Background(), notifierTestOccurrence(now, 5*time.Hour), "Hospital Teste")
+
+	if len(queue.emails) != 1 || !containsUser(queue.emails, local.ID) {
+		t.Errorf("Expected email only for the hospital operator, got %v", queue.emails)
+	}
+	if containsUser(queue.emails, other.ID) {
+		t.Error("Expected no email for the operator of another hospital")
+	}
+}
+
+// TestNotifyNewOccurrenceFallsBackToGestores tests that gestores are notified when no operator covers the hospital
+func TestNotifyNewOccurrenceFallsBackToGestores(t *testing.T) {
+	other := notifierTestOperator("other")
+	other.Hospitals = []models.Hospital{{ID: uuid.New()}}
+	gestor := notifierTestOperator("gestor")
+	gestor.Role = models.RoleGestor
+
+	queue := &recordingQueue{}
+	notifier := NewOccurrenceNotifier(
+		&mockRecipientSource{users: []models.User{other, gestor}},
+		&mockPreferencesSource{},
+	)
+	notifier.SetEmailQueue(queue)
+
+	now := time.Date(2026, 10, 14, 14, 0, 0, 0, time.Local)
+	notifier.now = func() time.Time { return now }
+	notifier.NotifyNewOccurrence(context.Background(), notifierTestOccurrence(now, 5*time.Hour), "Hospital Teste")
+
+	if len(queue.emails) != 1 || !containsUser(queue.emails, gestor.ID) {
+		t.Errorf("Expected email only for the hospital gestor, got %v", queue.emails)
+	}
+}
@@ -4,14 +4,31 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"time"
 
 	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
 )
 
+// FCM result errors meaning the registration token will never be valid again
+const (
+	FCMErrorNotRegistered       = "NotRegistered"
+	FCMErrorInvalidRegistration = "InvalidRegistration"
+)
+
+// ErrInvalidFCMToken is returned when FCM reports a token as unregistered or invalid
+var ErrInvalidFCMToken = errors.New("FCM token is no longer valid")
+
+// PushSubscriptionPruner removes subscriptions whose tokens FCM rejected
+// (implemented by repository.PushSubscriptionRepository)
+type PushSubscriptionPruner interface {
+	Delete(ctx context.Context, token string) error
+}
+
 // PushConfig holds Firebase Cloud Messaging configuration
 type PushConfig struct {
 	// FCM Server Key (Legacy) or Service Account JSON path
@@ -29,6 +46,14 @@ type PushConfig struct {
 type PushService struct {
 	config     *PushConfig
 	httpClient *http.Client
+	pruner     PushSubscriptionPruner
+}
+
+// PushSendSummary summarizes a send to all devices of a user
+type PushSendSummary struct {
+	Sent         int      `json:"sent"`
+	Failed       int      `json:"failed"`
+	PrunedTokens []string `json:"pruned_tokens,omitempty"`
 }
 
 // PushPayload represents the notification payload
@@ -100,6 +125,11 @@ func NewPushService(config *PushConfig) *PushService {
 	}
 }
 
+// SetSubscriptionPruner sets the store used to delete subscriptions with invalid tokens
+func (s *PushService) SetSubscriptionPruner(pruner PushSubscriptionPruner) {
+	s.pruner = pruner
+}
+
 // IsConfigured returns true if the push service is properly configured
 func (s *PushService) IsConfigured() bool {
 	return s.config != nil && s.config.ServerKey != ""
@@ -131,30 +161,59 @@ func (s *PushService) SendToToken(ctx context.Context, token string, payload *Pu
 }
 
 // SendToUser sends a push notification to all devices of a user
-func (s *PushService) SendToUser(ctx context.Context, subscriptions []models.PushSubscription, payload *PushPayload) error {
+// Subscriptions whose tokens FCM reports as NotRegistered/InvalidRegistration are deleted
+// and listed in the returned summary
+func (s *PushService) SendToUser(ctx context.Context, subscriptions []models.PushSubscription, payload *PushPayload) (*PushSendSummary, error) {
 	if !s.IsConfigured() {
-		return fmt.Errorf("push service not configured")
+		return nil, fmt.Errorf("push service not configured")
 	}
 
+	summary := &PushSendSummary{}
 	var lastErr error
-	successCount := 0
 
 	for _, sub := range subscriptions {
 		err := s.SendToToken(ctx, sub.Token, payload)
-		if err != nil {
-			log.Printf("[PushService] Failed to send to token %s: %v", sub.Token[:20]+"...", err)
-			lastErr = err
-		} else {
-			successCount++
+		if err == nil {
+			summary.Sent++
+			continue
+		}
+
+		log.Printf("[PushService] Failed to send to token %s: %v", tokenPreview(sub.Token), err)
+		summary.Failed++
+		lastErr = err
+
+		if errors.Is(err, ErrInvalidFCMToken) && s.pruner != nil {
+			if err := s.pruner.Delete(ctx, sub.Token); err != nil && !errors.Is(err, repository.ErrSubscriptionNotFound) {
+				log.Printf("[PushService] Failed to prune token %s: %v", tokenPreview(sub.Token), err)
+				continue
+			}
+			summary.PrunedTokens = append(summary.PrunedTokens, sub.Token)
 		}
 	}
 
-	if successCount == 0 && lastErr != nil {
-		return fmt.Errorf("failed to send to any device: %w", lastErr)
+	if len(summary.PrunedTokens) > 0 {
+		log.Printf("[PushService] Pruned %d invalid push subscriptions", len(summary.PrunedTokens))
 	}
 
-	log.Printf("[PushService] Sent notification to %d/%d devices", successCount, len(subscriptions))
-	return nil
+	if summary.Sent == 0 && lastErr != nil {
+		return summary, fmt.Errorf("failed to send to any device: %w", lastErr)
+	}
+
+	log.Printf("[PushService] Sent notification to %d/%d devices", summary.Sent, len(subscriptions))
+	return summary, nil
+}
+
+// tokenPreview shortens a token for logging
+func tokenPreview(token string) string {
+	if len(token) > 20 {
+		return token[:20] + "..."
+	}
+	return token
+}
+
+// isInvalidTokenError returns true for FCM errors that mean the token should be discarded
+func isInvalidTokenError(code string) bool {
+	return code == FCMErrorNotRegistered || code == FCMErrorInvalidRegistration
 }
 
 // sendFCMMessage sends the actual HTTP request to FCM
@@ -189,6 +248,9 @@ func (s *PushService) sendFCMMessage(ctx context.Context, message *FCMMessage) e
 
 	if fcmResp.Failure > 0 && len(fcmResp.Results) > 0 {
 		for _, result := range fcmResp.Results {
+			if isInvalidTokenError(result.Error) {
+				return fmt.Errorf("%w: %s", ErrInvalidFCMToken, result.Error)
+			}
 			if result.Error != "" {
 				return fmt.Errorf("FCM error: %s", result.Error)
 			}
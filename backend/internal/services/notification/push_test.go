@@ -0,0 +1,115 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// mockSubscriptionStore keeps push subscriptions in memory, keyed by token
+type mockSubscriptionStore struct {
+	subscriptions map[string]models.PushSubscription
+}
+
+func (s *mockSubscriptionStore) Delete(ctx context.Context, token string) error {
+	if _, ok := s.subscriptions[token]; !ok {
+		return repository.ErrSubscriptionNotFound
+	}
+	delete(s.subscriptions, token)
+	return nil
+}
+
+// newMockFCMServer answers like the legacy FCM API, rejecting the given tokens with errorCode
+func newMockFCMServer(t *testing.T, invalidTokens map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message FCMMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("Invalid FCM request body: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		response := FCMResponse{Success: 1, Results: []FCMResult{{MessageID: "msg-" + message.To}}}
+		if code, invalid := invalidTokens[message.To]; invalid {
+			response = FCMResponse{Failure: 1, Results: []FCMResult{{Error: code}}}
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+}
+
+// TestSendToUserPrunesInvalidTokens tests that only the subscription FCM rejects is removed
+func TestSendToUserPrunesInvalidTokens(t *testing.T) {
+	userID := uuid.New()
+	store := &mockSubscriptionStore{subscriptions: map[string]models.PushSubscription{}}
+	for _, token := range []string{"token-valid-a", "token-expired", "token-valid-b"} {
+		store.subscriptions[token] = models.PushSubscription{ID: uuid.New(), UserID: userID, Token: token}
+	}
+	subscriptions := []models.PushSubscription{
+		store.subscriptions["token-valid-a"],
+		store.subscriptions["token-expired"],
+		store.subscriptions["token-valid-b"],
+	}
+
+	server := newMockFCMServer(t, map[string]string{"token-expired": FCMErrorNotRegistered})
+	defer server.Close()
+
+	service := NewPushService(&PushConfig{ServerKey: "test-key", FCMURL: server.URL})
+	service.SetSubscriptionPruner(store)
+
+	summary, err := service.SendToUser(context.Background(), subscriptions, &PushPayload{Title: "Teste", Body: "Teste"})
+	if err != nil {
+		t.Fatalf("SendToUser returned error: %v", err)
+	}
+
+	if summary.Sent != 2 || summary.Failed != 1 {
+		t.Errorf("Expected 2 sent and 1 failed, got %d sent and %d failed", summary.Sent, summary.Failed)
+	}
+	if len(summary.PrunedTokens) != 1 || summary.PrunedTokens[0] != "token-expired" {
+		t.Errorf("Expected only token-expired to be pruned, got %v", summary.PrunedTokens)
+	}
+
+	if _, exists := store.subscriptions["token-expired"]; exists {
+		t.Error("Expected invalid subscription to be deleted")
+	}
+	if len(store.subscriptions) != 2 {
+		t.Errorf("Expected 2 remaining subscriptions, got %d", len(store.subscriptions))
+	}
+}
+
+// TestSendToUserKeepsTokensOnTransientErrors tests that other FCM errors do not delete subscriptions
+func TestSendToUserKeepsTokensOnTransientErrors(t *testing.T) {
+	store := &mockSubscriptionStore{subscriptions: map[string]models.PushSubscription{
+		"token-unavailable": {ID: uuid.New(), Token: "token-unavailable"},
+		"token-invalid":     {ID: uuid.New(), Token: "token-invalid"},
+	}}
+
+	server := newMockFCMServer(t, map[string]string{
+		"token-unavailable": "Unavailable",
+		"token-invalid":     FCMErrorInvalidRegistration,
+	})
+	defer server.Close()
+
+	service := NewPushService(&PushConfig{ServerKey: "test-key", FCMURL: server.URL})
+	service.SetSubscriptionPruner(store)
+
+	summary, err := service.SendToUser(context.Background(), []models.PushSubscription{
+		store.subscriptions["token-unavailable"],
+		store.subscriptions["token-invalid"],
+	}, &PushPayload{Title: "Teste", Body: "Teste"})
+	if err == nil {
+		t.Error("Expected error when no device received the notification")
+	}
+
+	if summary == nil || len(summary.PrunedTokens) != 1 || summary.PrunedTokens[0] != "token-invalid" {
+		t.Fatalf("Expected only token-invalid to be pruned, got %+v", summary)
+	}
+	if _, exists := store.subscriptions["token-unavailable"]; !exists {
+		t.Error("Expected subscription with transient error to be kept")
+	}
+}
@@ -0,0 +1,134 @@
+package notification
+
+import (
+	"context"
+	"net/smtp"
+	"sync"
+	"time"
+)
+
+// DefaultSMTPPoolIdleTimeout closes pooled SMTP sessions unused for longer, before most
+// servers drop them on their own
+const DefaultSMTPPoolIdleTimeout = 30 * time.Second
+
+// smtpSession is an authenticated SMTP session held by the pool
+type smtpSession struct {
+	client   *smtp.Client
+	lastUsed time.Time
+	messages int // emails sent over the session
+}
+
+// smtpPool keeps SMTP sessions open so bursts of emails (e.g. notifying every operator of a
+// hospital) reuse them instead of dialing and negotiating TLS per email. At most size sessions
+// are open at once; idle sessions are checked with NOOP before reuse and recycled after
+// idleTimeout or maxMessages emails, and broken ones are replaced by a new dial.
+type smtpPool struct {
+	dial        func(ctx context.Context) (*smtp.Client, error)
+	slots       chan struct{} // one per session in use
+	idleTimeout time.Duration
+	maxMessages int // 0 is unlimited
+	now         func() time.Time
+
+	mu     sync.Mutex
+	idle   []*smtpSession
+	closed bool
+}
+
+func newSMTPPool(size int, idleTimeout time.Duration, maxMessages int, dial func(ctx context.Context) (*smtp.Client, error)) *smtpPool {
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultSMTPPoolIdleTimeout
+	}
+	return &smtpPool{
+		dial:        dial,
+		slots:       make(chan struct{}, size),
+		idleTimeout: idleTimeout,
+		maxMessages: maxMessages,
+		now:         time.Now,
+	}
+}
+
+// get returns a healthy idle session, or dials a new one, waiting while size sessions are in use.
+// Every session returned must be given back with put.
+func (p *smtpPool) get(ctx context.Context) (*smtpSession, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	for session := p.popIdle(); session != nil; session = p.popIdle() {
+		if p.now().Sub(session.lastUsed) > p.idleTimeout {
+			session.client.Quit()
+			session.client.Close()
+			continue
+		}
+		if err := session.client.Noop(); err != nil {
+			session.client.Close() // dropped by the server
+			continue
+		}
+		return session, nil
+	}
+
+	client, err := p.dial(ctx)
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+	return &smtpSession{client: client}, nil
+}
+
+// put returns a session after a send; sessions whose transaction cannot be reset, or that
+// reached maxMessages, are closed instead of kept
+func (p *smtpPool) put(session *smtpSession, sendErr error) {
+	defer func() { <-p.slots }()
+
+	session.messages++
+	session.lastUsed = p.now()
+
+	if sendErr != nil && session.client.Reset() != nil {
+		session.client.Close()
+		return
+	}
+	if p.maxMessages > 0 && session.messages >= p.maxMessages {
+		session.client.Quit()
+		session.client.Close()
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		session.client.Quit()
+		session.client.Close()
+		return
+	}
+	p.idle = append(p.idle, session)
+}
+
+// popIdle removes the most recently used idle session, nil when there is none
+func (p *smtpPool) popIdle() *smtpSession {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := len(p.idle)
+	if n == 0 {
+		return nil
+	}
+	session := p.idle[n-1]
+	p.idle = p.idle[:n-1]
+	return session
+}
+
+// close ends the idle sessions; sessions in use are closed when given back
+func (p *smtpPool) close() {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.closed = true
+	p.mu.Unlock()
+
+	for _, session := range idle {
+		session.client.Quit()
+		session.client.Close()
+	}
+}
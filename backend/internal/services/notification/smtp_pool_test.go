@@ -0,0 +1,129 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// sendTestEmails sends n emails, failing the test on the first error
+func sendTestEmails(t *testing.T, service *EmailService, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		to := fmt.Sprintf("operador%d@sidot.gov.br", i)
+		if err := service.sendEmail(context.Background(), to, "Teste", "<p>corpo</p>"); err != nil {
+			t.Fatalf("Failed to send email %d: %v", i, err)
+		}
+	}
+}
+
+// TestSMTPPoolReusesConnection tests that consecutive emails share one pooled connection
+func TestSMTPPoolReusesConnection(t *testing.T) {
+	server := newFakeSMTPServer(t, nil)
+	config := server.config()
+	config.PoolSize = 1
+	service := NewEmailService(config)
+	defer service.Close()
+
+	sendTestEmails(t, service, 3)
+
+	messages, _, _ := server.delivered()
+	if len(messages) != 3 {
+		t.Fatalf("Expected 3 messages, got %d", len(messages))
+	}
+	if conns := server.connections(); conns != 1 {
+		t.Errorf("Expected 1 connection, got %d", conns)
+	}
+}
+
+// TestSMTPWithoutPoolDialsPerEmail tests that each email dials when pooling is disabled
+func TestSMTPWithoutPoolDialsPerEmail(t *testing.T) {
+	server := newFakeSMTPServer(t, nil)
+	service := NewEmailService(server.config())
+
+	sendTestEmails(t, service, 2)
+
+	if conns := server.connections(); conns != 2 {
+		t.Errorf("Expected 2 connections, got %d", conns)
+	}
+}
+
+// TestSMTPPoolReconnectsDroppedConnection tests that a connection closed by the server is replaced
+func TestSMTPPoolReconnectsDroppedConnection(t *testing.T) {
+	server := newFakeSMTPServer(t, nil)
+	config := server.config()
+	config.PoolSize = 1
+	service := NewEmailService(config)
+	defer service.Close()
+
+	sendTestEmails(t, service, 1)
+	server.dropConnections()
+	sendTestEmails(t, service, 1)
+
+	messages, _, _ := server.delivered()
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(messages))
+	}
+	if conns := server.connections(); conns != 2 {
+		t.Errorf("Expected 2 connections, got %d", conns)
+	}
+}
+
+// TestSMTPPoolRecyclesConnections tests the idle timeout and the message limit
+func TestSMTPPoolRecyclesConnections(t *testing.T) {
+	t.Run("idle timeout", func(t *testing.T) {
+		server := newFakeSMTPServer(t, nil)
+		config := server.config()
+		config.PoolSize = 1
+		config.PoolIdleTimeout = time.Minute
+		service := NewEmailService(config)
+		defer service.Close()
+
+		now := time.Now()
+		service.pool.now = func() time.Time { return now }
+
+		sendTestEmails(t, service, 2)
+		now = now.Add(2 * time.Minute)
+		sendTestEmails(t, service, 1)
+
+		if conns := server.connections(); conns != 2 {
+			t.Errorf("Expected 2 connections, got %d", conns)
+		}
+	})
+
+	t.Run("max messages", func(t *testing.T) {
+		server := newFakeSMTPServer(t, nil)
+		config := server.config()
+		config.PoolSize = 1
+		config.PoolMaxMessages = 2
+		service := NewEmailService(config)
+		defer service.Close()
+
+		sendTestEmails(t, service, 5)
+
+		if conns := server.connections(); conns != 3 {
+			t.Errorf("Expected 3 connections, got %d", conns)
+		}
+	})
+}
+
+// TestSMTPPoolKeepsConnectionAfterRejection tests that a rejected recipient does not discard the connection
+func TestSMTPPoolKeepsConnectionAfterRejection(t *testing.T) {
+	server := newFakeSMTPServer(t, nil)
+	config := server.config()
+	config.PoolSize = 1
+	service := NewEmailService(config)
+	defer service.Close()
+
+	server.setReject(true)
+	if err := service.sendEmail(context.Background(), "operador@sidot.gov.br", "Teste", "<p>corpo</p>"); err == nil {
+		t.Fatal("Expected the rejected recipient to fail")
+	}
+	server.setReject(false)
+	sendTestEmails(t, service, 1)
+
+	if conns := server.connections(); conns != 1 {
+		t.Errorf("Expected 1 connection, got %d", conns)
+	}
+}
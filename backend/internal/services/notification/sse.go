@@ -26,11 +26,25 @@ const (
 	ClientTimeout = 60 * time.Second
 )
 
-// SSEClient represents a connected SSE client
+// Client transports supported by the hub
+const (
+	TransportSSE       = "sse"
+	TransportWebSocket = "websocket"
+)
+
+// SSEClient represents a connected real-time client (SSE stream or WebSocket)
 type SSEClient struct {
 	ID        string
 	UserID    string
 	Role      string
+	Transport string
+	// Topic selects the events delivered to the client; empty receives the notification events
+	Topic string
+
+	// TenantID and IsSuperAdmin select the tenant events delivered to the client; super admins receive every tenant
+	TenantID     string
+	IsSuperAdmin bool
+
 	Channel   chan *models.SSEEvent
 	Done      chan struct{}
 	CreatedAt time.Time
@@ -38,16 +52,48 @@ type SSEClient struct {
 
 // NewSSEClient creates a new SSE client
 func NewSSEClient(userID, role string) *SSEClient {
+	return newHubClient(userID, role, TransportSSE)
+}
+
+// NewTopicSSEClient creates an SSE client that only receives the events of a topic (e.g., models.SSETopicMap)
+func NewTopicSSEClient(userID, role, topic string) *SSEClient {
+	client := newHubClient(userID, role, TransportSSE)
+	client.Topic = topic
+	return client
+}
+
+// NewWebSocketClient creates a new WebSocket client fed by the same hub events as SSE clients
+func NewWebSocketClient(userID, role string) *SSEClient {
+	return newHubClient(userID, role, TransportWebSocket)
+}
+
+// newHubClient creates a client for the given transport
+func newHubClient(userID, role, transport string) *SSEClient {
 	return &SSEClient{
 		ID:        uuid.New().String(),
 		UserID:    userID,
 		Role:      role,
+		Transport: transport,
 		Channel:   make(chan *models.SSEEvent, 100),
 		Done:      make(chan struct{}),
 		CreatedAt: time.Now(),
 	}
 }
 
+// SetTenant sets the tenant of the client, so it only receives the tenant events of its own tenant
+func (c *SSEClient) SetTenant(tenantID string, isSuperAdmin bool) {
+	c.TenantID = tenantID
+	c.IsSuperAdmin = isSuperAdmin
+}
+
+// receives checks if the event is delivered to the client: same topic and, for tenant events, same tenant
+func (c *SSEClient) receives(event *models.SSEEvent) bool {
+	if c.Topic != event.Topic {
+		return false
+	}
+	return event.TenantID == "" || c.IsSuperAdmin || c.TenantID == event.TenantID
+}
+
 // Close closes the client connection
 func (c *SSEClient) Close() {
 	select {
@@ -138,7 +184,7 @@ func (h *SSEHub) RegisterClient(client *SSEClient) {
 	h.clients[client.ID] = client
 	atomic.AddInt64(&h.totalConnections, 1)
 
-	h.logger.Printf("[SSE] Client registered: %s (user: %s, role: %s)", client.ID, client.UserID, client.Role)
+	h.logger.Printf("[SSE] Client registered: %s (user: %s, role: %s, transport: %s, topic: %q)", client.ID, client.UserID, client.Role, client.Transport, client.Topic)
 }
 
 // UnregisterClient removes an SSE client
@@ -161,7 +207,14 @@ func (h *SSEHub) GetClientCount() int {
 }
 
 // PublishEvent publishes an event to all connected clients via Redis Pub/Sub
+// Without Redis the event is delivered only to clients connected to this instance
 func (h *SSEHub) PublishEvent(ctx context.Context, event *models.SSEEvent) error {
+	if h.redis == nil {
+		h.broadcastToClients(event)
+		atomic.AddInt64(&h.totalEventsPublished, 1)
+		return nil
+	}
+
 	data, err := json.Marshal(event)
 	if err != nil {
 		return err
@@ -235,7 +288,7 @@ func (h *SSEHub) subscribeLoop(ctx context.Context) {
 	}
 }
 
-// broadcastToClients sends an event to all connected clients
+// broadcastToClients sends an event to the connected clients subscribed to its topic and tenant
 func (h *SSEHub) broadcastToClients(event *models.SSEEvent) {
 	h.clientsMu.RLock()
 	defer h.clientsMu.RUnlock()
@@ -243,6 +296,9 @@ func (h *SSEHub) broadcastToClients(event *models.SSEEvent) {
 	atomic.AddInt64(&h.totalBroadcasts, 1)
 
 	for _, client := range h.clients {
+		if !client.receives(event) {
+			continue
+		}
 		select {
 		case client.Channel <- event:
 			// Event sent successfully
@@ -289,11 +345,27 @@ func (h *SSEHub) sendHeartbeat() {
 	}
 }
 
+// GetClientCountByTransport returns the number of connected clients per transport
+func (h *SSEHub) GetClientCountByTransport() map[string]int {
+	h.clientsMu.RLock()
+	defer h.clientsMu.RUnlock()
+
+	counts := map[string]int{
+		TransportSSE:       0,
+		TransportWebSocket: 0,
+	}
+	for _, client := range h.clients {
+		counts[client.Transport]++
+	}
+	return counts
+}
+
 // GetStats returns statistics about the SSE hub
 func (h *SSEHub) GetStats() map[string]interface{} {
 	return map[string]interface{}{
 		"running":               h.IsRunning(),
 		"connected_clients":     h.GetClientCount(),
+		"clients_by_transport":  h.GetClientCountByTransport(),
 		"total_connections":     atomic.LoadInt64(&h.totalConnections),
 		"total_broadcasts":      atomic.LoadInt64(&h.totalBroadcasts),
 		"total_events_published": atomic.LoadInt64(&h.totalEventsPublished),
@@ -0,0 +1,306 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+const (
+	// DefaultPollInterval is the interval between scans for pending events
+	DefaultPollInterval = 2 * time.Second
+
+	// DefaultBatchSize is the maximum number of events claimed per scan
+	DefaultBatchSize = 50
+
+	// DefaultClaimLease is how long a claimed event stays invisible to other relays.
+	// An event not marked sent or failed within the lease (e.g. the process crashed) is claimed again.
+	DefaultClaimLease = time.Minute
+
+	// MaxRetryDelay caps the backoff between delivery attempts
+	MaxRetryDelay = 10 * time.Minute
+
+	// DefaultMaxAttempts is the number of delivery attempts before an event is moved to dead-letter
+	DefaultMaxAttempts = 10
+
+	// DefaultSentRetention is how long sent events are kept before the relay deletes them
+	DefaultSentRetention = 7 * 24 * time.Hour
+
+	// SentCleanupInterval is the interval between deletions of expired sent events
+	SentCleanupInterval = time.Hour
+)
+
+// Handler delivers an event; returning an error schedules a retry
+type Handler func(ctx context.Context, event *models.OutboxEvent) error
+
+// Store is the outbox persistence used by the relay (implemented by repository.EventsOutboxRepository)
+type Store interface {
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error)
+	MarkSent(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, deliveryErr string, retryAt time.Time) error
+	MarkDead(ctx context.Context, id uuid.UUID, deliveryErr string) error
+	DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// Relay publishes outbox events to their handlers and marks them sent, with at-least-once semantics:
+// handlers may see an event more than once (after a crash or a failed MarkSent) but never miss one.
+// An event still failing after maxAttempts is moved to dead-letter and left for an operator;
+// sent events are deleted once older than sentRetention.
+type Relay struct {
+	store    Store
+	handlers map[string]Handler
+	mu       sync.RWMutex
+
+	pollInterval time.Duration
+	batchSize    int
+	claimLease   time.Duration
+	maxAttempts  int
+
+	sentRetention time.Duration
+	lastCleanup   time.Time
+
+	totalSent   int64
+	totalFailed int64
+	totalDead   int64
+	totalPurged int64
+
+	running int32
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	now    func() time.Time
+	logger *log.Logger
+}
+
+// NewRelay creates a relay reading from the events_outbox table
+func NewRelay(db *sql.DB) *Relay {
+	return NewRelayWithStore(repository.NewEventsOutboxRepository(db))
+}
+
+// NewRelayWithStore creates a relay reading from the given store
+func NewRelayWithStore(store Store) *Relay {
+	return &Relay{
+		store:         store,
+		handlers:      make(map[string]Handler),
+		pollInterval:  DefaultPollInterval,
+		batchSize:     DefaultBatchSize,
+		claimLease:    DefaultClaimLease,
+		maxAttempts:   DefaultMaxAttempts,
+		sentRetention: DefaultSentRetention,
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+		now:           time.Now,
+		logger:        log.Default(),
+	}
+}
+
+// Handle registers the handler for an event type
+func (r *Relay) Handle(eventType string, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[eventType] = handler
+}
+
+// SetPollInterval sets the interval between scans for pending events
+func (r *Relay) SetPollInterval(interval time.Duration) {
+	r.pollInterval = interval
+}
+
+// SetClaimLease sets how long a claimed event stays invisible to other relays
+func (r *Relay) SetClaimLease(lease time.Duration) {
+	r.claimLease = lease
+}
+
+// SetMaxAttempts sets the number of delivery attempts before an event is moved to dead-letter
+func (r *Relay) SetMaxAttempts(attempts int) {
+	r.maxAttempts = attempts
+}
+
+// SetSentRetention sets how long sent events are kept (0 keeps them forever)
+func (r *Relay) SetSentRetention(retention time.Duration) {
+	r.sentRetention = retention
+}
+
+// SetLogger sets a custom logger for the relay
+func (r *Relay) SetLogger(logger *log.Logger) {
+	r.logger = logger
+}
+
+// Start begins the relay loop; events left unsent by a previous run are picked up on the first scan
+func (r *Relay) Start(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&r.running, 0, 1) {
+		return nil // Already running
+	}
+
+	r.logger.Println("[Outbox] Starting outbox relay")
+
+	go r.relayLoop(ctx)
+
+	return nil
+}
+
+// Stop stops the relay loop
+func (r *Relay) Stop() {
+	if atomic.CompareAndSwapInt32(&r.running, 1, 0) {
+		close(r.stopCh)
+		<-r.doneCh
+		r.logger.Println("[Outbox] Outbox relay stopped")
+	}
+}
+
+// IsRunning returns true if the relay is running
+func (r *Relay) IsRunning() bool {
+	return atomic.LoadInt32(&r.running) == 1
+}
+
+// GetStats returns the current statistics of the relay
+func (r *Relay) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"running":      r.IsRunning(),
+		"total_sent":   atomic.LoadInt64(&r.totalSent),
+		"total_failed": atomic.LoadInt64(&r.totalFailed),
+		"total_dead":   atomic.LoadInt64(&r.totalDead),
+		"total_purged": atomic.LoadInt64(&r.totalPurged),
+	}
+}
+
+// relayLoop is the main relay loop
+func (r *Relay) relayLoop(ctx context.Context) {
+	defer close(r.doneCh)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain full batches right away instead of waiting a tick per batch
+		for r.RelayPending(ctx) == r.batchSize {
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		if r.now().Sub(r.lastCleanup) >= SentCleanupInterval {
+			r.CleanupSent(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending claims due events and delivers them, returning how many were claimed
+func (r *Relay) RelayPending(ctx context.Context) int {
+	events, err := r.store.ClaimPending(ctx, r.batchSize, r.claimLease)
+	if err != nil {
+		r.logger.Printf("[Outbox] Error claiming events: %v", err)
+		return 0
+	}
+
+	for i := range events {
+		r.deliver(ctx, &events[i])
+	}
+
+	return len(events)
+}
+
+// deliver runs the handler of an event and records the outcome
+func (r *Relay) deliver(ctx context.Context, event *models.OutboxEvent) {
+	r.mu.RLock()
+	handler, ok := r.handlers[event.EventType]
+	r.mu.RUnlock()
+
+	// Claimed more often than allowed without a recorded outcome (e.g. the handler crashes the process)
+	if event.Attempts > r.maxAttempts {
+		r.markDead(ctx, event, fmt.Sprintf("exceeded %d delivery attempts", r.maxAttempts))
+		return
+	}
+
+	var err error
+	if !ok {
+		// Kept for retry: an instance running a newer version may know the event type
+		err = fmt.Errorf("no handler for event type %s", event.EventType)
+	} else {
+		err = handler(ctx, event)
+	}
+
+	if err != nil {
+		atomic.AddInt64(&r.totalFailed, 1)
+		if event.Attempts >= r.maxAttempts {
+			r.markDead(ctx, event, err.Error())
+			return
+		}
+		retryAt := r.now().Add(RetryDelay(event.Attempts))
+		r.logger.Printf("[Outbox] Delivery of %s event %s failed (attempt %d, retry at %s): %v",
+			event.EventType, event.ID, event.Attempts, retryAt.Format(time.RFC3339), err)
+		if markErr := r.store.MarkFailed(ctx, event.ID, err.Error(), retryAt); markErr != nil {
+			r.logger.Printf("[Outbox] Error recording failure of event %s: %v", event.ID, markErr)
+		}
+		return
+	}
+
+	// If this fails the event is delivered again once its lease expires
+	if err := r.store.MarkSent(ctx, event.ID); err != nil {
+		r.logger.Printf("[Outbox] Error marking event %s as sent: %v", event.ID, err)
+		return
+	}
+	atomic.AddInt64(&r.totalSent, 1)
+}
+
+// markDead moves an event to dead-letter
+func (r *Relay) markDead(ctx context.Context, event *models.OutboxEvent, reason string) {
+	r.logger.Printf("[Outbox] Moving %s event %s to dead-letter after %d attempts: %s",
+		event.EventType, event.ID, event.Attempts, reason)
+	if err := r.store.MarkDead(ctx, event.ID, reason); err != nil {
+		r.logger.Printf("[Outbox] Error moving event %s to dead-letter: %v", event.ID, err)
+		return
+	}
+	atomic.AddInt64(&r.totalDead, 1)
+}
+
+// CleanupSent deletes sent events older than the retention, returning how many were deleted.
+// Dead-letter and pending events are never deleted.
+func (r *Relay) CleanupSent(ctx context.Context) int64 {
+	if r.sentRetention <= 0 {
+		return 0
+	}
+	r.lastCleanup = r.now()
+
+	deleted, err := r.store.DeleteSentBefore(ctx, r.lastCleanup.Add(-r.sentRetention))
+	if err != nil {
+		r.logger.Printf("[Outbox] Error deleting sent events: %v", err)
+		return 0
+	}
+	if deleted > 0 {
+		r.logger.Printf("[Outbox] Deleted %d sent events older than %s", deleted, r.sentRetention)
+	}
+	atomic.AddInt64(&r.totalPurged, deleted)
+	return deleted
+}
+
+// RetryDelay returns the backoff before the next delivery attempt: 5s doubling per attempt, capped at MaxRetryDelay
+func RetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := 5 * time.Second
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= MaxRetryDelay {
+			return MaxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,357 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// memoryStore is an in-memory outbox with the same lease semantics as the events_outbox table
+type memoryStore struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	events []*models.OutboxEvent
+}
+
+func newMemoryStore(now func() time.Time) *memoryStore {
+	return &memoryStore{now: now}
+}
+
+func (s *memoryStore) add(eventType string) *models.OutboxEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event := &models.OutboxEvent{
+		ID:          uuid.New(),
+		EventType:   eventType,
+		AggregateID: uuid.New(),
+		Payload:     []byte(`{}`),
+		AvailableAt: s.now(),
+		CreatedAt:   s.now(),
+	}
+	s.events = append(s.events, event)
+	return event
+}
+
+func (s *memoryStore) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var claimed []models.OutboxEvent
+	for _, e := range s.events {
+		if len(claimed) == limit {
+			break
+		}
+		if e.SentAt == nil && e.DeadAt == nil && !e.AvailableAt.After(s.now()) {
+			e.AvailableAt = s.now().Add(lease)
+			e.Attempts++
+			claimed = append(claimed, *e)
+		}
+	}
+	return claimed, nil
+}
+
+func (s *memoryStore) MarkSent(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.events {
+		if e.ID == id {
+			sentAt := s.now()
+			e.SentAt = &sentAt
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) MarkFailed(ctx context.Context, id uuid.UUID, deliveryErr string, retryAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.events {
+		if e.ID == id && e.SentAt == nil {
+			e.LastError = &deliveryErr
+			e.AvailableAt = retryAt
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) MarkDead(ctx context.Context, id uuid.UUID, deliveryErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.events {
+		if e.ID == id && e.SentAt == nil {
+			deadAt := s.now()
+			e.LastError = &deliveryErr
+			e.DeadAt = &deadAt
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.events[:0]
+	var deleted int64
+	for _, e := range s.events {
+		if e.SentAt != nil && e.SentAt.Before(cutoff) {
+			deleted++
+			continue
+		}
+		kept = append(kept, e)
+	}
+	s.events = kept
+	return deleted, nil
+}
+
+// fakeClock is a manually advanced clock shared by the store and the relay
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func newTestRelay(store Store, clock *fakeClock) *Relay {
+	relay := NewRelayWithStore(store)
+	relay.now = clock.Now
+	relay.SetLogger(log.New(io.Discard, "", 0))
+	return relay
+}
+
+func TestRelayPendingMarksDeliveredEventsSent(t *testing.T) {
+	clock := &fakeClock{t: time.Now()}
+	store := newMemoryStore(clock.Now)
+	event := store.add(models.OutboxEventOccurrenceCreated)
+
+	relay := newTestRelay(store, clock)
+	var delivered []uuid.UUID
+	relay.Handle(models.OutboxEventOccurrenceCreated, func(ctx context.Context, e *models.OutboxEvent) error {
+		delivered = append(delivered, e.ID)
+		return nil
+	})
+
+	if n := relay.RelayPending(context.Background()); n != 1 {
+		t.Fatalf("Expected 1 event claimed, got %d", n)
+	}
+	if len(delivered) != 1 || delivered[0] != event.ID {
+		t.Fatalf("Expected event %s to be delivered, got %v", event.ID, delivered)
+	}
+	if event.SentAt == nil {
+		t.Error("Expected delivered event to be marked sent")
+	}
+
+	if n := relay.RelayPending(context.Background()); n != 0 {
+		t.Errorf("Expected sent event not to be claimed again, got %d", n)
+	}
+}
+
+func TestRelayPendingRetriesFailedDeliveryWithBackoff(t *testing.T) {
+	clock := &fakeClock{t: time.Now()}
+	store := newMemoryStore(clock.Now)
+	event := store.add(models.OutboxEventOccurrenceCreated)
+
+	relay := newTestRelay(store, clock)
+	calls := 0
+	relay.Handle(models.OutboxEventOccurrenceCreated, func(ctx context.Context, e *models.OutboxEvent) error {
+		calls++
+		if calls == 1 {
+			return errors.New("redis unavailable")
+		}
+		return nil
+	})
+
+	relay.RelayPending(context.Background())
+	if event.SentAt != nil || event.LastError == nil {
+		t.Fatal("Expected failed event to stay pending with its error recorded")
+	}
+
+	// Not due before the backoff elapses
+	clock.Advance(RetryDelay(1) - time.Second)
+	if n := relay.RelayPending(context.Background()); n != 0 {
+		t.Fatalf("Expected no retry before the backoff, got %d claimed", n)
+	}
+
+	clock.Advance(time.Second)
+	relay.RelayPending(context.Background())
+	if calls != 2 || event.SentAt == nil {
+		t.Errorf("Expected event to be delivered on retry, calls=%d sent=%v", calls, event.SentAt != nil)
+	}
+}
+
+func TestUnsentEventIsRelayedAfterRestart(t *testing.T) {
+	clock := &fakeClock{t: time.Now()}
+	store := newMemoryStore(clock.Now)
+	event := store.add(models.OutboxEventOccurrenceCreated)
+
+	// First instance claims the event and crashes before marking it sent
+	crashed, err := store.ClaimPending(context.Background(), DefaultBatchSize, DefaultClaimLease)
+	if err != nil || len(crashed) != 1 {
+		t.Fatalf("Expected the crashed instance to claim the event, got %d (%v)", len(crashed), err)
+	}
+
+	// Restarted instance
+	relay := newTestRelay(store, clock)
+	relay.SetPollInterval(10 * time.Millisecond)
+	delivered := make(chan uuid.UUID, 1)
+	relay.Handle(models.OutboxEventOccurrenceCreated, func(ctx context.Context, e *models.OutboxEvent) error {
+		delivered <- e.ID
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := relay.Start(ctx); err != nil {
+		t.Fatalf("Failed to start relay: %v", err)
+	}
+	defer relay.Stop()
+
+	select {
+	case <-delivered:
+		t.Fatal("Expected the event to stay leased to the crashed instance")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	clock.Advance(DefaultClaimLease)
+
+	select {
+	case id := <-delivered:
+		if id != event.ID {
+			t.Errorf("Expected event %s, got %s", event.ID, id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the unsent event to be relayed after the lease expired")
+	}
+
+	relay.Stop()
+	if event.SentAt == nil {
+		t.Error("Expected relayed event to be marked sent")
+	}
+	if event.Attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", event.Attempts)
+	}
+}
+
+func TestRelayPendingMovesExhaustedEventToDeadLetter(t *testing.T) {
+	clock := &fakeClock{t: time.Now()}
+	store := newMemoryStore(clock.Now)
+	event := store.add(models.OutboxEventOccurrenceCreated)
+
+	relay := newTestRelay(store, clock)
+	relay.SetMaxAttempts(3)
+	calls := 0
+	relay.Handle(models.OutboxEventOccurrenceCreated, func(ctx context.Context, e *models.OutboxEvent) error {
+		calls++
+		return errors.New("redis unavailable")
+	})
+
+	for i := 0; i < 5; i++ {
+		relay.RelayPending(context.Background())
+		clock.Advance(MaxRetryDelay)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 3 delivery attempts, got %d", calls)
+	}
+	if event.DeadAt == nil || event.SentAt != nil {
+		t.Fatal("Expected the exhausted event to be moved to dead-letter")
+	}
+	if event.LastError == nil || *event.LastError != "redis unavailable" {
+		t.Errorf("Expected the last delivery error to be kept, got %v", event.LastError)
+	}
+	if stats := relay.GetStats(); stats["total_dead"] != int64(1) {
+		t.Errorf("Expected 1 dead event in the stats, got %v", stats["total_dead"])
+	}
+}
+
+func TestRelayPendingDeadLettersEventClaimedPastMaxAttempts(t *testing.T) {
+	clock := &fakeClock{t: time.Now()}
+	store := newMemoryStore(clock.Now)
+	event := store.add(models.OutboxEventOccurrenceCreated)
+	event.Attempts = DefaultMaxAttempts // every previous claim ended in a crash
+
+	relay := newTestRelay(store, clock)
+	relay.Handle(models.OutboxEventOccurrenceCreated, func(ctx context.Context, e *models.OutboxEvent) error {
+		t.Error("Expected the handler not to run for an event past its attempts")
+		return nil
+	})
+
+	relay.RelayPending(context.Background())
+	if event.DeadAt == nil {
+		t.Error("Expected the event to be moved to dead-letter")
+	}
+}
+
+func TestCleanupSentDeletesOnlyExpiredSentEvents(t *testing.T) {
+	clock := &fakeClock{t: time.Now()}
+	store := newMemoryStore(clock.Now)
+	oldSent := store.add(models.OutboxEventOccurrenceCreated)
+	dead := store.add(models.OutboxEventOccurrenceCreated)
+	pending := store.add(models.OutboxEventOccurrenceCreated)
+
+	relay := newTestRelay(store, clock)
+	relay.SetSentRetention(24 * time.Hour)
+	store.MarkSent(context.Background(), oldSent.ID)
+	store.MarkDead(context.Background(), dead.ID, "redis unavailable")
+	pending.AvailableAt = clock.Now().Add(48 * time.Hour)
+
+	clock.Advance(25 * time.Hour)
+	recentSent := store.add(models.OutboxEventOccurrenceCreated)
+	store.MarkSent(context.Background(), recentSent.ID)
+
+	if n := relay.CleanupSent(context.Background()); n != 1 {
+		t.Fatalf("Expected 1 sent event deleted, got %d", n)
+	}
+	remaining := map[uuid.UUID]bool{}
+	for _, e := range store.events {
+		remaining[e.ID] = true
+	}
+	if remaining[oldSent.ID] {
+		t.Error("Expected the expired sent event to be deleted")
+	}
+	for name, e := range map[string]*models.OutboxEvent{"dead": dead, "pending": pending, "recent sent": recentSent} {
+		if !remaining[e.ID] {
+			t.Errorf("Expected the %s event to be kept", name)
+		}
+	}
+
+	relay.SetSentRetention(0)
+	clock.Advance(48 * time.Hour)
+	if n := relay.CleanupSent(context.Background()); n != 0 {
+		t.Errorf("Expected no deletion with retention disabled, got %d", n)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 5 * time.Second},
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{4, 40 * time.Second},
+		{20, MaxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		if got := RetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("RetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// causaMortisGroup counts the triaged obitos sharing a raw cause of death, eligibility and exclusion reasons
+type causaMortisGroup struct {
+	CausaMortis string
+	Elegivel    bool
+	Motivos     []string
+	Obitos      int
+}
+
+// CausaMortisReport returns the eligibility of the triaged obitos per normalized cause of death.
+// The period and hospital filters apply to the obitos (data_obito); the desfecho filter does not apply.
+func (s *ReportService) CausaMortisReport(ctx context.Context, filters models.ReportFilters) (*models.CausaMortisReport, error) {
+	where := "WHERE ob.processado = true" + repository.NewTenantFilter(ctx).AndClauseWithAlias("ob")
+	args := []interface{}{}
+
+	if filters.DateFrom != nil {
+		args = append(args, *filters.DateFrom)
+		where += fmt.Sprintf(" AND ob.data_obito >= $%d", len(args))
+	}
+	if filters.DateTo != nil {
+		args = append(args, *filters.DateTo)
+		where += fmt.Sprintf(" AND ob.data_obito <= $%d", len(args))
+	}
+	if filters.HospitalID != nil && *filters.HospitalID != "" {
+		args = append(args, *filters.HospitalID)
+		where += fmt.Sprintf(" AND ob.hospital_id = $%d", len(args))
+	}
+
+	// An obito is eligible once the triagem created its occurrence; the reasons come from its latest ineligibility record
+	query := fmt.Sprintf(`
+		SELECT ob.causa_mortis, o.id IS NOT NULL as elegivel, oi.motivos, COUNT(*) as obitos
+		FROM obitos_simulados ob
+		LEFT JOIN occurrences o ON o.obito_id = ob.id
+		LEFT JOIN LATERAL (
+			SELECT motivos
+			FROM obito_ineligibilities
+			WHERE obito_id = ob.id
+			ORDER BY created_at DESC
+			LIMIT 1
+		) oi ON true
+		%s
+		GROUP BY ob.causa_mortis, elegivel, oi.motivos
+	`, where)
+
+	rows, err := s.readDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query causa mortis counts: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []causaMortisGroup
+	for rows.Next() {
+		var group causaMortisGroup
+		if err := rows.Scan(&group.CausaMortis, &group.Elegivel, pq.Array(&group.Motivos), &group.Obitos); err != nil {
+			return nil, fmt.Errorf("failed to scan causa mortis row: %w", err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return &models.CausaMortisReport{
+		Causas:        aggregateCausaMortis(groups),
+		PeriodoInicio: filters.DateFrom,
+		PeriodoFim:    filters.DateTo,
+	}, nil
+}
+
+// normalizeCausaMortis normalizes the free text cause of death so spelling variants are grouped together
+func normalizeCausaMortis(causa string) string {
+	return strings.Join(strings.Fields(strings.ToLower(causa)), " ")
+}
+
+// aggregateCausaMortis merges the groups by normalized cause of death, ordered by most ineligible obitos
+func aggregateCausaMortis(groups []causaMortisGroup) []models.CausaMortisStats {
+	byCausa := make(map[string]*models.CausaMortisStats)
+	motivosByCausa := make(map[string]map[string]int)
+
+	for _, group := range groups {
+		causa := normalizeCausaMortis(group.CausaMortis)
+		stats, ok := byCausa[causa]
+		if !ok {
+			stats = &models.CausaMortisStats{CausaMortis: causa}
+			byCausa[causa] = stats
+			motivosByCausa[causa] = make(map[string]int)
+		}
+
+		stats.TotalObitos += group.Obitos
+		if group.Elegivel {
+			stats.Elegiveis += group.Obitos
+			continue
+		}
+		stats.Inelegiveis += group.Obitos
+		for _, motivo := range group.Motivos {
+			motivosByCausa[causa][motivo] += group.Obitos
+		}
+	}
+
+	result := make([]models.CausaMortisStats, 0, len(byCausa))
+	for causa, stats := range byCausa {
+		if stats.TotalObitos > 0 {
+			stats.TaxaElegibilidade = float64(stats.Elegiveis) / float64(stats.TotalObitos) * 100
+		}
+		stats.MotivoExclusaoFrequente = mostCommonMotivo(motivosByCausa[causa])
+		result = append(result, *stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Inelegiveis != result[j].Inelegiveis {
+			return result[i].Inelegiveis > result[j].Inelegiveis
+		}
+		if result[i].TotalObitos != result[j].TotalObitos {
+			return result[i].TotalObitos > result[j].TotalObitos
+		}
+		return result[i].CausaMortis < result[j].CausaMortis
+	})
+
+	return result
+}
+
+// mostCommonMotivo returns the most counted exclusion reason, the first alphabetically on ties, or nil when there is none
+func mostCommonMotivo(counts map[string]int) *string {
+	var best string
+	bestCount := 0
+	for motivo, count := range counts {
+		if count > bestCount || (count == bestCount && motivo < best) {
+			best, bestCount = motivo, count
+		}
+	}
+	if bestCount == 0 {
+		return nil
+	}
+	return &best
+}
@@ -20,29 +20,6 @@ func NewPDFGenerator() *PDFGenerator {
 
 // GenerateReport generates a PDF report
 func (g *PDFGenerator) GenerateReport(filters models.ReportFilters, metrics *models.ReportMetrics, rows []models.ReportOccurrenceRow) ([]byte, error) {
-	var buf bytes.Buffer
-
-	// PDF Header
-	buf.WriteString("%PDF-1.4\n")
-	buf.WriteString("1 0 obj\n")
-	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\n")
-	buf.WriteString("endobj\n")
-
-	// Pages object
-	buf.WriteString("2 0 obj\n")
-	buf.WriteString("<< /Type /Pages /Kids [3 0 R] /Count 1 >>\n")
-	buf.WriteString("endobj\n")
-
-	// Page object
-	buf.WriteString("3 0 obj\n")
-	buf.WriteString("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>\n")
-	buf.WriteString("endobj\n")
-
-	// Font object
-	buf.WriteString("5 0 obj\n")
-	buf.WriteString("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>\n")
-	buf.WriteString("endobj\n")
-
 	// Build content stream
 	var content bytes.Buffer
 	content.WriteString("BT\n")
@@ -153,8 +130,148 @@ func (g *PDFGenerator) GenerateReport(filters models.ReportFilters, metrics *mod
 	content.WriteString(fmt.Sprintf("500 30 Td\n(Pagina 1) Tj\n"))
 	content.WriteString("ET\n")
 
+	return buildDocument(content.Bytes()), nil
+}
+
+// GenerateCaseSheet generates a one-page PDF handoff sheet for a single occurrence
+func (g *PDFGenerator) GenerateCaseSheet(sheet *models.OccurrenceCaseSheet) ([]byte, error) {
+	if sheet == nil || sheet.Occurrence == nil {
+		return nil, fmt.Errorf("case sheet requires an occurrence")
+	}
+	occ := sheet.Occurrence
+
+	var content bytes.Buffer
+	content.WriteString("BT\n")
+
+	// Header - same branding as the aggregated report
+	yPos := 750
+	content.WriteString(fmt.Sprintf("/F1 16 Tf\n50 %d Td\n(SIDOT) Tj\n", yPos))
+	content.WriteString(fmt.Sprintf("/F1 10 Tf\n350 %d Td\n(Governo do Estado de Goias - SES) Tj\n", yPos))
+
+	// Title and identification
+	yPos -= 40
+	content.WriteString(fmt.Sprintf("/F1 14 Tf\n50 %d Td\n(Ficha da Ocorrencia) Tj\n", yPos))
+
+	yPos -= 20
+	content.WriteString(fmt.Sprintf("/F1 9 Tf\n50 %d Td\n(ID: %s) Tj\n", yPos, occ.ID.String()))
+
+	hospitalNome := ""
+	if occ.Hospital != nil {
+		hospitalNome = occ.Hospital.Nome
+	}
+	yPos -= 15
+	content.WriteString(fmt.Sprintf("/F1 10 Tf\n50 %d Td\n(Hospital: %s) Tj\n", yPos, escapeString(hospitalNome)))
+
+	yPos -= 15
+	content.WriteString(fmt.Sprintf("/F1 10 Tf\n50 %d Td\n(Status: %s) Tj\n", yPos, escapeString(occ.Status.String())))
+
+	yPos -= 15
+	content.WriteString(fmt.Sprintf("/F1 10 Tf\n50 %d Td\n(Data/Hora Obito: %s - Janela expira em: %s) Tj\n",
+		yPos, occ.DataObito.Format("02/01/2006 15:04"), occ.JanelaExpiraEm.Format("02/01/2006 15:04")))
+
+	// Patient data (unmasked - caller must be authorized)
+	yPos -= 30
+	content.WriteString(fmt.Sprintf("/F1 12 Tf\n50 %d Td\n(Dados do Paciente) Tj\n", yPos))
+
+	if dados := sheet.DadosCompletos; dados != nil {
+		lines := []string{
+			"Nome: " + dados.NomePaciente,
+			"Data de Nascimento: " + dados.DataNascimento.Format("02/01/2006"),
+			fmt.Sprintf("Idade: %d", dados.Idade),
+			"Causa Mortis: " + dados.CausaMortis,
+			"Prontuario: " + dados.Prontuario,
+			"Setor: " + dados.Setor + " - Leito: " + dados.Leito,
+		}
+		for _, line := range lines {
+			yPos -= 15
+			content.WriteString(fmt.Sprintf("/F1 10 Tf\n60 %d Td\n(%s) Tj\n", yPos, escapeString(line)))
+		}
+	} else {
+		yPos -= 15
+		content.WriteString(fmt.Sprintf("/F1 10 Tf\n60 %d Td\n(Dados completos indisponiveis) Tj\n", yPos))
+	}
+
+	// Score breakdown
+	yPos -= 30
+	content.WriteString(fmt.Sprintf("/F1 12 Tf\n50 %d Td\n(Pontuacao de Priorizacao: %d) Tj\n", yPos, sheet.Score.Total))
+
+	yPos -= 15
+	content.WriteString(fmt.Sprintf("/F1 10 Tf\n60 %d Td\n(- Setor %s: %d) Tj\n", yPos, escapeString(sheet.Score.Setor), sheet.Score.PontuacaoSetor))
+
+	yPos -= 15
+	content.WriteString(fmt.Sprintf("/F1 10 Tf\n60 %d Td\n(- Bonus de urgencia: %d) Tj\n", yPos, sheet.Score.BonusUrgencia))
+
+	// Outcome
+	yPos -= 30
+	desfechoText := "Desfecho: nao registrado"
+	if sheet.Outcome != nil && sheet.Outcome.Desfecho != nil {
+		desfechoText = "Desfecho: " + sheet.Outcome.Desfecho.DisplayName() + " em " + sheet.Outcome.CreatedAt.Format("02/01/2006 15:04")
+		if sheet.Outcome.Observacoes != nil && *sheet.Outcome.Observacoes != "" {
+			desfechoText += " - " + truncateString(*sheet.Outcome.Observacoes, 60)
+		}
+	}
+	content.WriteString(fmt.Sprintf("/F1 12 Tf\n50 %d Td\n(%s) Tj\n", yPos, escapeString(desfechoText)))
+
+	// History timeline (oldest first)
+	yPos -= 30
+	content.WriteString(fmt.Sprintf("/F1 12 Tf\n50 %d Td\n(Historico) Tj\n", yPos))
+
+	yPos -= 15
+	for i := len(sheet.History) - 1; i >= 0; i-- {
+		if yPos < 60 {
+			// Single page handoff sheet - remaining entries are omitted
+			content.WriteString(fmt.Sprintf("/F1 8 Tf\n60 %d Td\n(... %d entradas omitidas) Tj\n", yPos, i+1))
+			break
+		}
+
+		h := sheet.History[i]
+		entry := h.CreatedAt.Format("02/01/06 15:04") + " - " + h.Acao
+		if h.StatusNovo != nil {
+			entry += " [" + h.StatusNovo.String() + "]"
+		}
+		if h.User != nil && h.User.Nome != "" {
+			entry += " por " + truncateString(h.User.Nome, 25)
+		}
+
+		content.WriteString(fmt.Sprintf("/F1 8 Tf\n60 %d Td\n(%s) Tj\n", yPos, escapeString(entry)))
+		yPos -= 12
+	}
+
+	// Footer
+	content.WriteString("ET\n")
+	content.WriteString("BT\n")
+	content.WriteString(fmt.Sprintf("/F1 8 Tf\n50 30 Td\n(Gerado automaticamente por SIDOT em %s) Tj\n", time.Now().Format("02/01/2006 15:04")))
+	content.WriteString("ET\n")
+
+	return buildDocument(content.Bytes()), nil
+}
+
+// buildDocument wraps a content stream in a single-page PDF document
+func buildDocument(contentBytes []byte) []byte {
+	var buf bytes.Buffer
+
+	// PDF Header
+	buf.WriteString("%PDF-1.4\n")
+	buf.WriteString("1 0 obj\n")
+	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\n")
+	buf.WriteString("endobj\n")
+
+	// Pages object
+	buf.WriteString("2 0 obj\n")
+	buf.WriteString("<< /Type /Pages /Kids [3 0 R] /Count 1 >>\n")
+	buf.WriteString("endobj\n")
+
+	// Page object
+	buf.WriteString("3 0 obj\n")
+	buf.WriteString("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>\n")
+	buf.WriteString("endobj\n")
+
+	// Font object
+	buf.WriteString("5 0 obj\n")
+	buf.WriteString("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>\n")
+	buf.WriteString("endobj\n")
+
 	// Content stream object
-	contentBytes := content.Bytes()
 	buf.WriteString("4 0 obj\n")
 	buf.WriteString(fmt.Sprintf("<< /Length %d >>\n", len(contentBytes)))
 	buf.WriteString("stream\n")
@@ -180,7 +297,7 @@ func (g *PDFGenerator) GenerateReport(filters models.ReportFilters, metrics *mod
 	buf.WriteString(fmt.Sprintf("%d\n", xrefOffset))
 	buf.WriteString("%%EOF\n")
 
-	return buf.Bytes(), nil
+	return buf.Bytes()
 }
 
 // escapeString escapes special characters for PDF strings
@@ -11,16 +11,25 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
 )
 
 // ReportService handles report generation
+// Report queries run on readDB, a read replica when configured; export logs are written to db.
 type ReportService struct {
-	db *sql.DB
+	db     *sql.DB
+	readDB *sql.DB
 }
 
-// NewReportService creates a new report service
+// NewReportService creates a new report service running every query on db
 func NewReportService(db *sql.DB) *ReportService {
-	return &ReportService{db: db}
+	return &ReportService{db: db, readDB: db}
+}
+
+// NewReportServiceWithReplica creates a new report service whose report queries run on the
+// replica, or on the primary when replica is nil
+func NewReportServiceWithReplica(primary, replica *sql.DB) *ReportService {
+	return &ReportService{db: primary, readDB: repository.ReportingDB(primary, replica)}
 }
 
 // GenerateCSV generates a CSV report with streaming
@@ -116,6 +125,35 @@ func (s *ReportService) GeneratePDF(ctx context.Context, filters models.ReportFi
 	return pdfBytes, nil
 }
 
+// GenerateOccurrencePDF generates a single-occurrence case sheet PDF
+// History is expected newest-first, as returned by the history repository
+func (s *ReportService) GenerateOccurrencePDF(occurrence *models.Occurrence, history []models.OccurrenceHistory) ([]byte, error) {
+	sheet := &models.OccurrenceCaseSheet{
+		Occurrence: occurrence,
+		History:    history,
+	}
+
+	var dados models.OccurrenceCompleteData
+	if err := json.Unmarshal(occurrence.DadosCompletos, &dados); err == nil {
+		sheet.DadosCompletos = &dados
+	}
+	sheet.Score = models.NewScoreBreakdown(dados.Setor, occurrence.ScorePriorizacao)
+
+	for i := range history {
+		if history[i].Desfecho != nil {
+			sheet.Outcome = &history[i]
+			break
+		}
+	}
+
+	pdfBytes, err := NewPDFGenerator().GenerateCaseSheet(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate case sheet: %w", err)
+	}
+
+	return pdfBytes, nil
+}
+
 // CalculateMetrics calculates aggregated metrics for the report
 func (s *ReportService) CalculateMetrics(ctx context.Context, filters models.ReportFilters) (*models.ReportMetrics, error) {
 	metrics := &models.ReportMetrics{
@@ -134,7 +172,7 @@ func (s *ReportService) CalculateMetrics(ctx context.Context, filters models.Rep
 		LEFT JOIN hospitals h ON o.hospital_id = h.id
 		%s
 	`, where)
-	if err := s.db.QueryRowContext(ctx, totalQuery, args...).Scan(&metrics.TotalOcorrencias); err != nil {
+	if err := s.readDB.QueryRowContext(ctx, totalQuery, args...).Scan(&metrics.TotalOcorrencias); err != nil {
 		return nil, fmt.Errorf("failed to count total: %w", err)
 	}
 
@@ -152,7 +190,7 @@ func (s *ReportService) CalculateMetrics(ctx context.Context, filters models.Rep
 		%s
 		GROUP BY COALESCE(oh.desfecho::text, 'Pendente')
 	`, where)
-	desfechoRows, err := s.db.QueryContext(ctx, desfechoQuery, args...)
+	desfechoRows, err := s.readDB.QueryContext(ctx, desfechoQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query desfecho counts: %w", err)
 	}
@@ -172,17 +210,18 @@ func (s *ReportService) CalculateMetrics(ctx context.Context, filters models.Rep
 		metrics.OcorrenciasPorDesfecho[displayName] = count
 	}
 
-	// Taxa de Perda Operacional: % of notifications that expired after 6h without action (status CANCELADA)
+	// Taxa de Perda Operacional: % of notifications that expired after 6h without action
+	// (EXPIRADA, or CANCELADA after the window for occurrences closed before the expiry job existed)
 	perdaQuery := fmt.Sprintf(`
 		SELECT
-			COUNT(*) FILTER (WHERE o.status = 'CANCELADA' AND o.janela_expira_em < NOW()) as expirados,
+			COUNT(*) FILTER (WHERE o.status = 'EXPIRADA' OR (o.status = 'CANCELADA' AND o.janela_expira_em < NOW())) as expirados,
 			COUNT(*) as total
 		FROM occurrences o
 		LEFT JOIN hospitals h ON o.hospital_id = h.id
 		%s
 	`, where)
 	var expirados, total int
-	if err := s.db.QueryRowContext(ctx, perdaQuery, args...).Scan(&expirados, &total); err != nil {
+	if err := s.readDB.QueryRowContext(ctx, perdaQuery, args...).Scan(&expirados, &total); err != nil {
 		return nil, fmt.Errorf("failed to calculate loss rate: %w", err)
 	}
 	if total > 0 {
@@ -205,7 +244,7 @@ func (s *ReportService) CalculateMetrics(ctx context.Context, filters models.Rep
 		%s
 		AND first_action.first_change IS NOT NULL
 	`, where)
-	if err := s.db.QueryRowContext(ctx, tempoQuery, args...).Scan(&metrics.TempoMedioReacaoMin); err != nil {
+	if err := s.readDB.QueryRowContext(ctx, tempoQuery, args...).Scan(&metrics.TempoMedioReacaoMin); err != nil {
 		return nil, fmt.Errorf("failed to calculate avg reaction time: %w", err)
 	}
 
@@ -331,7 +370,7 @@ func (s *ReportService) queryReportData(ctx context.Context, filters models.Repo
 		ORDER BY o.created_at DESC
 	`, where)
 
-	return s.db.QueryContext(ctx, query, args...)
+	return s.readDB.QueryContext(ctx, query, args...)
 }
 
 // scanReportRow scans a single row from the report query
@@ -360,8 +399,10 @@ func (s *ReportService) scanReportRow(rows *sql.Rows) (*models.ReportOccurrenceR
 	// Parse dados_completos to get age
 	var dados models.OccurrenceCompleteData
 	idade := 0
-	if err := json.Unmarshal([]byte(dadosCompletos), &dados); err == nil {
-		idade = dados.Idade
+	if plain, err := repository.OpenOccurrenceData(json.RawMessage(dadosCompletos)); err == nil {
+		if err := json.Unmarshal(plain, &dados); err == nil {
+			idade = dados.Idade
+		}
 	}
 
 	row := &models.ReportOccurrenceRow{
@@ -3,9 +3,12 @@ package report
 import (
 	"bytes"
 	"context"
+	"database/sql"
+	"encoding/json"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sidot/backend/internal/models"
 )
 
@@ -184,6 +187,79 @@ func TestPDFGenerator_ContainsRequiredElements(t *testing.T) {
 	})
 }
 
+// TestGenerateOccurrencePDF_ContainsOccurrenceData tests the single-occurrence case sheet
+func TestGenerateOccurrencePDF_ContainsOccurrenceData(t *testing.T) {
+	t.Run("Case sheet contains occurrence ID, history and outcome", func(t *testing.T) {
+		now := time.Now()
+		dados, _ := json.Marshal(models.OccurrenceCompleteData{
+			NomePaciente: "Joao da Silva",
+			CausaMortis:  "Infarto agudo do miocardio",
+			Idade:        65,
+			Setor:        "UTI",
+		})
+		occurrence := &models.Occurrence{
+			ID:                    uuid.New(),
+			HospitalID:            uuid.New(),
+			Status:                models.StatusAceita,
+			ScorePriorizacao:      95,
+			NomePacienteMascarado: "J** d* S****",
+			DadosCompletos:        dados,
+			DataObito:             now.Add(-2 * time.Hour),
+			JanelaExpiraEm:        now.Add(4 * time.Hour),
+			Hospital:              &models.Hospital{Nome: "Hospital Teste"},
+		}
+
+		desfecho := models.OutcomeSucessoCaptacao
+		status := models.StatusAceita
+		history := []models.OccurrenceHistory{
+			{Acao: models.ActionOutcomeRegistered, Desfecho: &desfecho, CreatedAt: now},
+			{Acao: models.ActionOccurrenceAccepted, StatusNovo: &status, CreatedAt: now.Add(-time.Hour)},
+		}
+
+		service := &ReportService{}
+		pdfBytes, err := service.GenerateOccurrencePDF(occurrence, history)
+		if err != nil {
+			t.Fatalf("Failed to generate occurrence PDF: %v", err)
+		}
+
+		if !bytes.HasPrefix(pdfBytes, []byte("%PDF-1.4")) {
+			t.Error("PDF should start with %PDF-1.4 header")
+		}
+		if !bytes.Contains(pdfBytes, []byte(occurrence.ID.String())) {
+			t.Error("PDF should contain the occurrence ID")
+		}
+		if !bytes.Contains(pdfBytes, []byte("Joao da Silva")) {
+			t.Error("PDF should contain the unmasked patient name")
+		}
+		if !bytes.Contains(pdfBytes, []byte(models.ActionOccurrenceAccepted)) {
+			t.Error("PDF should contain the history timeline")
+		}
+		if !bytes.Contains(pdfBytes, []byte(desfecho.DisplayName())) {
+			t.Error("PDF should contain the registered outcome")
+		}
+	})
+
+	t.Run("Nil occurrence returns error", func(t *testing.T) {
+		gen := NewPDFGenerator()
+		if _, err := gen.GenerateCaseSheet(&models.OccurrenceCaseSheet{}); err == nil {
+			t.Error("Expected error for case sheet without occurrence")
+		}
+	})
+}
+
+// TestNewScoreBreakdown tests splitting a priority score into components
+func TestNewScoreBreakdown(t *testing.T) {
+	breakdown := models.NewScoreBreakdown("UTI", 100)
+	if breakdown.PontuacaoSetor+breakdown.BonusUrgencia != 100 {
+		t.Errorf("Components should sum to total, got %d + %d", breakdown.PontuacaoSetor, breakdown.BonusUrgencia)
+	}
+
+	unknown := models.NewScoreBreakdown("", 50)
+	if unknown.PontuacaoSetor != 50 || unknown.BonusUrgencia != 0 {
+		t.Errorf("Unknown sector should use default base 50, got %+v", unknown)
+	}
+}
+
 // TestDesfechoDisplayNames tests the desfecho display name mappings
 func TestDesfechoDisplayNames(t *testing.T) {
 	t.Run("All outcomes have display names", func(t *testing.T) {
@@ -232,6 +308,97 @@ func TestBuildWhereClause(t *testing.T) {
 	})
 }
 
+// TestNewReportServiceWithReplica tests that report queries use the replica while export logs stay on the primary
+func TestNewReportServiceWithReplica(t *testing.T) {
+	primary, replica := &sql.DB{}, &sql.DB{}
+
+	t.Run("Replica configured", func(t *testing.T) {
+		service := NewReportServiceWithReplica(primary, replica)
+		if service.readDB != replica {
+			t.Error("Expected report queries to use the replica")
+		}
+		if service.db != primary {
+			t.Error("Expected export logs to be written to the primary")
+		}
+	})
+
+	t.Run("No replica falls back to the primary", func(t *testing.T) {
+		service := NewReportServiceWithReplica(primary, nil)
+		if service.readDB != primary || service.db != primary {
+			t.Error("Expected every query to use the primary")
+		}
+	})
+}
+
+// TestAggregateCausaMortis tests that obitos are grouped by normalized cause of death with their eligibility
+func TestAggregateCausaMortis(t *testing.T) {
+	groups := []causaMortisGroup{
+		{CausaMortis: "Sepse", Elegivel: true, Obitos: 1},
+		{CausaMortis: "  SEPSE ", Elegivel: false, Motivos: []string{"Idade acima do limite"}, Obitos: 2},
+		{CausaMortis: "sepse", Elegivel: false, Motivos: []string{"Idade acima do limite", "Fora da janela"}, Obitos: 1},
+		{CausaMortis: "Infarto  Agudo do Miocardio", Elegivel: true, Obitos: 3},
+		{CausaMortis: "infarto agudo do miocardio", Elegivel: false, Motivos: []string{"Fora da janela"}, Obitos: 1},
+		{CausaMortis: "Neoplasia", Elegivel: false, Obitos: 1},
+	}
+
+	stats := aggregateCausaMortis(groups)
+	if len(stats) != 3 {
+		t.Fatalf("Expected 3 normalized causes, got %+v", stats)
+	}
+
+	t.Run("Grouped by normalized cause, most ineligible first", func(t *testing.T) {
+		expected := []struct {
+			causa                         string
+			total, elegiveis, inelegiveis int
+		}{
+			{"sepse", 4, 1, 3},
+			{"infarto agudo do miocardio", 4, 3, 1},
+			{"neoplasia", 1, 0, 1},
+		}
+		for i, e := range expected {
+			s := stats[i]
+			if s.CausaMortis != e.causa || s.TotalObitos != e.total || s.Elegiveis != e.elegiveis || s.Inelegiveis != e.inelegiveis {
+				t.Errorf("Position %d: expected %s %d/%d/%d, got %s %d/%d/%d", i,
+					e.causa, e.total, e.elegiveis, e.inelegiveis,
+					s.CausaMortis, s.TotalObitos, s.Elegiveis, s.Inelegiveis)
+			}
+		}
+	})
+
+	t.Run("Eligibility rate", func(t *testing.T) {
+		if stats[0].TaxaElegibilidade != 25 || stats[1].TaxaElegibilidade != 75 || stats[2].TaxaElegibilidade != 0 {
+			t.Errorf("Unexpected eligibility rates %v, %v, %v",
+				stats[0].TaxaElegibilidade, stats[1].TaxaElegibilidade, stats[2].TaxaElegibilidade)
+		}
+	})
+
+	t.Run("Most common exclusion reason", func(t *testing.T) {
+		if stats[0].MotivoExclusaoFrequente == nil || *stats[0].MotivoExclusaoFrequente != "Idade acima do limite" {
+			t.Errorf("Expected 'Idade acima do limite' for sepse, got %v", stats[0].MotivoExclusaoFrequente)
+		}
+		if stats[1].MotivoExclusaoFrequente == nil || *stats[1].MotivoExclusaoFrequente != "Fora da janela" {
+			t.Errorf("Expected 'Fora da janela' for infarto, got %v", stats[1].MotivoExclusaoFrequente)
+		}
+		if stats[2].MotivoExclusaoFrequente != nil {
+			t.Errorf("Expected no exclusion reason without recorded reasons, got %s", *stats[2].MotivoExclusaoFrequente)
+		}
+	})
+}
+
+// TestNormalizeCausaMortis tests the normalization of free text causes of death
+func TestNormalizeCausaMortis(t *testing.T) {
+	tests := map[string]string{
+		"Sepse":                      "sepse",
+		"  AVC   Hemorragico \t":     "avc hemorragico",
+		"insuficiencia respiratoria": "insuficiencia respiratoria",
+	}
+	for input, expected := range tests {
+		if got := normalizeCausaMortis(input); got != expected {
+			t.Errorf("normalizeCausaMortis(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
 // Helper function to create a test context with timeout
 func testContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), 5*time.Second)
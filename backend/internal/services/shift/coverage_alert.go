@@ -0,0 +1,306 @@
+package shift
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+	"github.com/sidot/backend/internal/services/notification"
+)
+
+const (
+	// DefaultCoverageCheckInterval is the interval between coverage gap checks
+	DefaultCoverageCheckInterval = 15 * time.Minute
+
+	// DefaultCoverageLookahead is how far ahead a gap must begin to trigger an alert
+	DefaultCoverageLookahead = 2 * time.Hour
+
+	// CoverageAlertCooldown suppresses repeated alerts for the same gap on the same day
+	CoverageAlertCooldown = 24 * time.Hour
+
+	// CoverageAlertKeyPrefix is the prefix for Redis cooldown keys
+	CoverageAlertKeyPrefix = "shift:coverage_alert:"
+)
+
+// CoverageGapAlert represents an upcoming coverage gap for a hospital
+type CoverageGapAlert struct {
+	HospitalID   uuid.UUID
+	HospitalNome string
+	Gap          models.CoverageGap
+	StartsAt     time.Time
+}
+
+// CooldownKey returns the dedupe key for this gap occurrence (one per gap per day)
+func (a CoverageGapAlert) CooldownKey() string {
+	return fmt.Sprintf("%s%s:%s:%s", CoverageAlertKeyPrefix, a.HospitalID.String(), a.StartsAt.Format("2006-01-02"), a.Gap.StartTime)
+}
+
+// CoverageAlertService periodically checks shift coverage and emails gestores about upcoming gaps
+type CoverageAlertService struct {
+	redis        *redis.Client
+	emailService *notification.EmailService
+	shiftRepo    *repository.ShiftRepository
+	userRepo     *repository.UserRepository
+	hospitalRepo *repository.HospitalRepository
+
+	checkInterval time.Duration
+	lookahead     time.Duration
+	dashboardURL  string
+
+	// In-memory cooldowns, used when Redis is not configured
+	alertCooldowns map[string]time.Time
+	cooldownMu     sync.Mutex
+
+	running int32
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	logger *log.Logger
+}
+
+// NewCoverageAlertService creates a new coverage alert service
+func NewCoverageAlertService(db *sql.DB, redisClient *redis.Client, emailService *notification.EmailService) *CoverageAlertService {
+	return &CoverageAlertService{
+		redis:          redisClient,
+		emailService:   emailService,
+		shiftRepo:      repository.NewShiftRepository(db),
+		userRepo:       repository.NewUserRepository(db),
+		hospitalRepo:   repository.NewHospitalRepository(db),
+		checkInterval:  DefaultCoverageCheckInterval,
+		lookahead:      DefaultCoverageLookahead,
+		alertCooldowns: make(map[string]time.Time),
+		stopCh:         make(chan struct{}),
+		doneCh:         make(chan struct{}),
+		logger:         log.Default(),
+	}
+}
+
+// SetCheckInterval sets the interval between coverage checks
+func (s *CoverageAlertService) SetCheckInterval(interval time.Duration) {
+	s.checkInterval = interval
+}
+
+// SetLookahead sets how far ahead a gap must begin to be alerted
+func (s *CoverageAlertService) SetLookahead(lookahead time.Duration) {
+	s.lookahead = lookahead
+}
+
+// SetDashboardURL sets the base dashboard URL used in alert emails
+func (s *CoverageAlertService) SetDashboardURL(url string) {
+	s.dashboardURL = url
+}
+
+// Start begins the coverage check loop
+func (s *CoverageAlertService) Start(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&s.running, 0, 1) {
+		return nil // Already running
+	}
+
+	s.logger.Println("[CoverageAlert] Starting coverage alert service")
+
+	go s.checkLoop(ctx)
+
+	return nil
+}
+
+// Stop stops the coverage check loop
+func (s *CoverageAlertService) Stop() {
+	if atomic.CompareAndSwapInt32(&s.running, 1, 0) {
+		close(s.stopCh)
+		<-s.doneCh
+		s.logger.Println("[CoverageAlert] Coverage alert service stopped")
+	}
+}
+
+// IsRunning returns true if the service is running
+func (s *CoverageAlertService) IsRunning() bool {
+	return atomic.LoadInt32(&s.running) == 1
+}
+
+// checkLoop is the main check loop
+func (s *CoverageAlertService) checkLoop(ctx context.Context) {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	// Initial check
+	s.CheckCoverage(ctx, time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+			s.CheckCoverage(ctx, time.Now())
+		}
+	}
+}
+
+// CheckCoverage checks every active hospital for gaps beginning within the lookahead and alerts gestores
+func (s *CoverageAlertService) CheckCoverage(ctx context.Context, now time.Time) {
+	if s.emailService == nil || !s.emailService.IsConfigured() {
+		return
+	}
+
+	hospitals, err := s.hospitalRepo.GetActiveHospitals(ctx)
+	if err != nil {
+		s.logger.Printf("[CoverageAlert] Error listing hospitals: %v", err)
+		return
+	}
+
+	for _, hospital := range hospitals {
+		analysis, err := s.shiftRepo.GetCoverageGaps(ctx, hospital.ID)
+		if err != nil {
+			s.logger.Printf("[CoverageAlert] Error analyzing coverage for hospital %s: %v", hospital.ID, err)
+			continue
+		}
+
+		// Gaps are wall-clock times of the hospital's timezone
+		alerts := UpcomingGapAlerts(analysis, hospital.Nome, now.In(hospital.Location()), s.lookahead)
+		alerts = s.filterCooldown(ctx, alerts)
+		if len(alerts) == 0 {
+			continue
+		}
+
+		gestors, err := s.userRepo.ListByRoleAndHospital(ctx, string(models.RoleGestor), hospital.ID)
+		if err != nil {
+			s.logger.Printf("[CoverageAlert] Error listing gestors for hospital %s: %v", hospital.ID, err)
+			continue
+		}
+
+		for _, alert := range alerts {
+			s.sendAlert(ctx, alert, gestors)
+		}
+	}
+}
+
+// sendAlert emails a coverage gap alert to every gestor that accepts email notifications
+func (s *CoverageAlertService) sendAlert(ctx context.Context, alert CoverageGapAlert, gestors []models.User) {
+	sent := 0
+	for _, gestor := range gestors {
+		if !gestor.CanReceiveEmailNotifications() {
+			continue
+		}
+
+		data := &notification.CoverageGapAlertData{
+			HospitalNome: alert.HospitalNome,
+			DayName:      alert.Gap.DayName,
+			StartTime:    alert.Gap.StartTime,
+			EndTime:      alert.Gap.EndTime,
+			StartsAt:     alert.StartsAt,
+			Locale:       gestor.PreferredLocale(),
+		}
+		if s.dashboardURL != "" {
+			data.DashboardURL = s.dashboardURL + "/dashboard/shifts"
+		}
+
+		if err := s.emailService.SendCoverageGapAlert(ctx, gestor.Email, data); err != nil {
+			s.logger.Printf("[CoverageAlert] Error sending alert to %s: %v", gestor.Email, err)
+			continue
+		}
+		sent++
+	}
+
+	if sent == 0 {
+		s.logger.Printf("[CoverageAlert] No gestor notified for gap at hospital %s (%s %s)",
+			alert.HospitalID, alert.Gap.DayName, alert.Gap.StartTime)
+		return
+	}
+
+	s.logger.Printf("[CoverageAlert] Coverage gap alert sent to %d gestor(s) for hospital %s (%s %s-%s)",
+		sent, alert.HospitalID, alert.Gap.DayName, alert.Gap.StartTime, alert.Gap.EndTime)
+}
+
+// UpcomingGapAlerts converts a coverage analysis into alerts for gaps beginning within the lookahead window
+// Gaps that already started are not reported; they were alerted when they were still upcoming
+func UpcomingGapAlerts(analysis *models.CoverageAnalysis, hospitalNome string, now time.Time, lookahead time.Duration) []CoverageGapAlert {
+	alerts := []CoverageGapAlert{}
+	if analysis == nil || !analysis.HasGaps {
+		return alerts
+	}
+
+	deadline := now.Add(lookahead)
+	for _, gap := range analysis.Gaps {
+		startsAt, ok := nextGapStart(gap, now)
+		if !ok || startsAt.After(deadline) {
+			continue
+		}
+
+		alerts = append(alerts, CoverageGapAlert{
+			HospitalID:   analysis.HospitalID,
+			HospitalNome: hospitalNome,
+			Gap:          gap,
+			StartsAt:     startsAt,
+		})
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].StartsAt.Before(alerts[j].StartsAt)
+	})
+
+	return alerts
+}
+
+// nextGapStart returns the next time (at or after now) the gap begins
+func nextGapStart(gap models.CoverageGap, now time.Time) (time.Time, bool) {
+	start := models.ShiftTime(gap.StartTime)
+	if !start.IsValid() || !gap.DayOfWeek.IsValid() {
+		return time.Time{}, false
+	}
+
+	daysAhead := (int(gap.DayOfWeek) - int(now.Weekday()) + 7) % 7
+	day := now.AddDate(0, 0, daysAhead)
+	startsAt, err := start.ToTime(day)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	if startsAt.Before(now) {
+		startsAt = startsAt.AddDate(0, 0, 7)
+	}
+
+	return startsAt, true
+}
+
+// filterCooldown drops alerts already sent for the same gap on the same day and marks the remaining ones
+func (s *CoverageAlertService) filterCooldown(ctx context.Context, alerts []CoverageGapAlert) []CoverageGapAlert {
+	pending := make([]CoverageGapAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		if s.claimCooldown(ctx, alert.CooldownKey()) {
+			pending = append(pending, alert)
+		}
+	}
+	return pending
+}
+
+// claimCooldown atomically marks an alert key as sent, returning false if it is still in cooldown
+func (s *CoverageAlertService) claimCooldown(ctx context.Context, key string) bool {
+	if s.redis != nil {
+		claimed, err := s.redis.SetNX(ctx, key, time.Now().Unix(), CoverageAlertCooldown).Result()
+		if err == nil {
+			return claimed
+		}
+		s.logger.Printf("[CoverageAlert] Error checking cooldown in Redis, using local cooldown: %v", err)
+	}
+
+	s.cooldownMu.Lock()
+	defer s.cooldownMu.Unlock()
+
+	if lastAlert, exists := s.alertCooldowns[key]; exists && time.Since(lastAlert) < CoverageAlertCooldown {
+		return false
+	}
+	s.alertCooldowns[key] = time.Now()
+	return true
+}
@@ -0,0 +1,102 @@
+package shift
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// TestUpcomingGapAlertsWithinLookahead tests that only gaps beginning inside the lookahead become alerts
+func TestUpcomingGapAlertsWithinLookahead(t *testing.T) {
+	hospitalID := uuid.New()
+	// Wednesday 2026-10-14 17:30 local time
+	now := time.Date(2026, 10, 14, 17, 30, 0, 0, time.Local)
+
+	analysis := &models.CoverageAnalysis{
+		HospitalID: hospitalID,
+		HasGaps:    true,
+		Gaps: []models.CoverageGap{
+			{DayOfWeek: models.Wednesday, DayName: models.Wednesday.String(), StartTime: "19:00", EndTime: "23:59"},
+			{DayOfWeek: models.Wednesday, DayName: models.Wednesday.String(), StartTime: "08:00", EndTime: "10:00"},
+			{DayOfWeek: models.Thursday, DayName: models.Thursday.String(), StartTime: "00:00", EndTime: "06:00"},
+		},
+	}
+
+	alerts := UpcomingGapAlerts(analysis, "Hospital Teste", now, 2*time.Hour)
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(alerts))
+	}
+
+	alert := alerts[0]
+	if alert.HospitalID != hospitalID || alert.HospitalNome != "Hospital Teste" {
+		t.Errorf("Unexpected hospital in alert: %s %s", alert.HospitalID, alert.HospitalNome)
+	}
+	expected := time.Date(2026, 10, 14, 19, 0, 0, 0, time.Local)
+	if !alert.StartsAt.Equal(expected) {
+		t.Errorf("Expected gap to start at %v, got %v", expected, alert.StartsAt)
+	}
+
+	// A wider lookahead also picks up the gap after midnight, ordered by start time
+	alerts = UpcomingGapAlerts(analysis, "Hospital Teste", now, 8*time.Hour)
+	if len(alerts) != 2 {
+		t.Fatalf("Expected 2 alerts, got %d", len(alerts))
+	}
+	if alerts[1].Gap.DayOfWeek != models.Thursday {
+		t.Errorf("Expected second alert for Thursday, got %s", alerts[1].Gap.DayName)
+	}
+
+	// The morning gap already passed today and next occurs in a week
+	for _, a := range alerts {
+		if a.Gap.StartTime == "08:00" {
+			t.Error("Expected past gap not to be alerted")
+		}
+	}
+}
+
+// TestUpcomingGapAlertsNoGaps tests that full coverage produces no alerts
+func TestUpcomingGapAlertsNoGaps(t *testing.T) {
+	analysis := &models.CoverageAnalysis{HospitalID: uuid.New(), Gaps: []models.CoverageGap{}}
+
+	alerts := UpcomingGapAlerts(analysis, "Hospital Teste", time.Now(), 24*time.Hour)
+	if len(alerts) != 0 {
+		t.Errorf("Expected no alerts, got %d", len(alerts))
+	}
+
+	if alerts := UpcomingGapAlerts(nil, "", time.Now(), time.Hour); len(alerts) != 0 {
+		t.Errorf("Expected no alerts for nil analysis, got %d", len(alerts))
+	}
+}
+
+// TestCoverageAlertCooldownSuppression tests that the same gap is only alerted once per day
+func TestCoverageAlertCooldownSuppression(t *testing.T) {
+	service := NewCoverageAlertService(nil, nil, nil)
+	ctx := context.Background()
+
+	now := time.Date(2026, 10, 14, 17, 30, 0, 0, time.Local)
+	analysis := &models.CoverageAnalysis{
+		HospitalID: uuid.New(),
+		HasGaps:    true,
+		Gaps: []models.CoverageGap{
+			{DayOfWeek: models.Wednesday, DayName: models.Wednesday.String(), StartTime: "19:00", EndTime: "23:59"},
+		},
+	}
+
+	first := service.filterCooldown(ctx, UpcomingGapAlerts(analysis, "Hospital Teste", now, 2*time.Hour))
+	if len(first) != 1 {
+		t.Fatalf("Expected first check to produce 1 alert, got %d", len(first))
+	}
+
+	second := service.filterCooldown(ctx, UpcomingGapAlerts(analysis, "Hospital Teste", now.Add(15*time.Minute), 2*time.Hour))
+	if len(second) != 0 {
+		t.Errorf("Expected repeated alert to be suppressed, got %d", len(second))
+	}
+
+	// The same gap a week later is a different day and must alert again
+	nextWeek := service.filterCooldown(ctx, UpcomingGapAlerts(analysis, "Hospital Teste", now.AddDate(0, 0, 7), 2*time.Hour))
+	if len(nextWeek) != 1 {
+		t.Errorf("Expected gap on a different day to alert, got %d", len(nextWeek))
+	}
+}
@@ -62,9 +62,8 @@ func (s *ShiftRoutingService) GetOnDutyOperators(ctx context.Context, hospitalID
 		return operators, nil
 	}
 
-	// Cache miss - query database
-	dayOfWeek := int(eventTime.Weekday())
-	shifts, err := s.shiftRepo.GetActiveShifts(ctx, hospitalID, dayOfWeek, eventTime)
+	// Cache miss - query database, in the hospital's timezone
+	shifts, err := s.shiftRepo.GetActiveShiftsAt(ctx, hospitalID, eventTime)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active shifts: %w", err)
 	}
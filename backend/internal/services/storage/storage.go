@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DriverLocal stores files on the local filesystem
+	DriverLocal = "local"
+
+	// DriverS3 stores files in an S3-compatible bucket
+	DriverS3 = "s3"
+)
+
+var (
+	// ErrInvalidKey is returned when an object key is empty or escapes the storage root
+	ErrInvalidKey = errors.New("invalid storage key")
+
+	// ErrUnknownDriver is returned when the configured storage driver is not supported
+	ErrUnknownDriver = errors.New("unknown storage driver")
+)
+
+// Storage stores uploaded files and returns the public URL they are served from
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) (string, error)
+}
+
+// Config selects and configures a Storage implementation
+type Config struct {
+	Driver string
+
+	// Local driver
+	LocalDir       string
+	LocalPublicURL string
+
+	// S3 driver
+	S3 S3Config
+}
+
+// New creates the Storage selected by config.Driver (local when empty)
+func New(config *Config) (Storage, error) {
+	switch config.Driver {
+	case "", DriverLocal:
+		return NewLocalStorage(config.LocalDir, config.LocalPublicURL), nil
+	case DriverS3:
+		return NewS3Storage(&config.S3)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDriver, config.Driver)
+	}
+}
+
+// cleanKey normalizes an object key and rejects keys that would escape the storage root
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+// LocalStorage writes files under a directory that is served at PublicURL
+type LocalStorage struct {
+	dir       string
+	publicURL string
+}
+
+// NewLocalStorage creates a filesystem storage rooted at dir (default "uploads"),
+// whose files are served at publicURL (default "/uploads")
+func NewLocalStorage(dir, publicURL string) *LocalStorage {
+	if dir == "" {
+		dir = "uploads"
+	}
+	if publicURL == "" {
+		publicURL = "/uploads"
+	}
+	return &LocalStorage{
+		dir:       dir,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// Put writes data to <dir>/<key> and returns <publicURL>/<key>
+func (s *LocalStorage) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", err
+	}
+
+	return s.publicURL + "/" + key, nil
+}
@@ -0,0 +1,108 @@
+package triagem
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// autoAssignPolicySource reads the auto-assignment policy (implemented by repository.AdminSettingsRepository)
+type autoAssignPolicySource interface {
+	GetSettingByKey(ctx context.Context, key string) (*models.SystemSetting, error)
+}
+
+// activeShiftSource lists the shifts on duty (implemented by repository.ShiftRepository)
+type activeShiftSource interface {
+	GetActiveShiftsAt(ctx context.Context, hospitalID uuid.UUID, at time.Time) ([]models.Shift, error)
+}
+
+// occurrenceAssigner sets the operator of an occurrence (implemented by repository.OccurrenceRepository)
+type occurrenceAssigner interface {
+	Assign(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+}
+
+// assignmentHistoryWriter records the assignment (implemented by repository.OccurrenceHistoryRepository)
+type assignmentHistoryWriter interface {
+	Create(ctx context.Context, input *models.CreateHistoryInput) (*models.OccurrenceHistory, error)
+}
+
+// AutoAssigner assigns new occurrences to the operator on duty at their hospital,
+// for the hospitals and tenants enabled by the occurrence_auto_assign setting
+type AutoAssigner struct {
+	policy      autoAssignPolicySource
+	shifts      activeShiftSource
+	occurrences occurrenceAssigner
+	history     assignmentHistoryWriter
+	now         func() time.Time
+	logger      *log.Logger
+}
+
+// NewAutoAssigner creates an auto assigner reading its policy from the system settings
+func NewAutoAssigner(db *sql.DB, settings *repository.AdminSettingsRepository) *AutoAssigner {
+	return &AutoAssigner{
+		policy:      settings,
+		shifts:      repository.NewShiftRepository(db),
+		occurrences: repository.NewOccurrenceRepository(db),
+		history:     repository.NewOccurrenceHistoryRepository(db),
+		now:         time.Now,
+		logger:      log.Default(),
+	}
+}
+
+// Assign assigns the occurrence to the first operator on duty when the policy applies to its hospital.
+// It returns the assigned operator, or nil when the policy is off or no one is on duty.
+func (a *AutoAssigner) Assign(ctx context.Context, occurrence *models.Occurrence) (*models.User, error) {
+	setting, err := a.policy.GetSettingByKey(ctx, models.SettingKeyOccurrenceAutoAssign)
+	if err != nil {
+		if errors.Is(err, repository.ErrAdminSettingNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get auto-assignment policy: %w", err)
+	}
+	policy, err := setting.GetOccurrenceAutoAssignConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid auto-assignment policy: %w", err)
+	}
+	if !policy.AppliesTo(occurrence.TenantID, occurrence.HospitalID) {
+		return nil, nil
+	}
+
+	shifts, err := a.shifts.GetActiveShiftsAt(ctx, occurrence.HospitalID, a.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active shifts: %w", err)
+	}
+
+	var operator *models.User
+	for _, shift := range shifts {
+		if shift.User != nil && shift.User.Ativo && shift.User.Role == models.RoleOperador {
+			operator = shift.User
+			break
+		}
+	}
+	if operator == nil {
+		return nil, nil
+	}
+
+	if err := a.occurrences.Assign(ctx, occurrence.ID, operator.ID); err != nil {
+		return nil, fmt.Errorf("failed to assign occurrence: %w", err)
+	}
+	occurrence.AssignedUserID = &operator.ID
+
+	observacoes := fmt.Sprintf("Atribuida automaticamente a %s (plantao)", operator.Nome)
+	if _, err := a.history.Create(ctx, &models.CreateHistoryInput{
+		OccurrenceID: occurrence.ID,
+		Acao:         models.ActionOccurrenceAssigned,
+		Observacoes:  &observacoes,
+	}); err != nil {
+		a.logger.Printf("[Triagem] Warning: Could not create assignment history entry: %v", err)
+	}
+
+	return operator, nil
+}
@@ -0,0 +1,174 @@
+package triagem
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+	"github.com/sidot/backend/internal/repository"
+)
+
+type fakeAutoAssignPolicy struct {
+	setting *models.SystemSetting
+}
+
+func (f *fakeAutoAssignPolicy) GetSettingByKey(ctx context.Context, key string) (*models.SystemSetting, error) {
+	if f.setting == nil || key != models.SettingKeyOccurrenceAutoAssign {
+		return nil, repository.ErrAdminSettingNotFound
+	}
+	return f.setting, nil
+}
+
+type fakeActiveShifts struct {
+	shifts     []models.Shift
+	hospitalID uuid.UUID
+	at         time.Time
+}
+
+func (f *fakeActiveShifts) GetActiveShiftsAt(ctx context.Context, hospitalID uuid.UUID, at time.Time) ([]models.Shift, error) {
+	f.hospitalID = hospitalID
+	f.at = at
+	return f.shifts, nil
+}
+
+type fakeOccurrenceAssigner struct {
+	assigned map[uuid.UUID]uuid.UUID
+}
+
+func (f *fakeOccurrenceAssigner) Assign(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	f.assigned[id] = userID
+	return nil
+}
+
+type fakeAssignmentHistory struct {
+	entries []*models.CreateHistoryInput
+}
+
+func (f *fakeAssignmentHistory) Create(ctx context.Context, input *models.CreateHistoryInput) (*models.OccurrenceHistory, error) {
+	f.entries = append(f.entries, input)
+	return &models.OccurrenceHistory{ID: uuid.New(), OccurrenceID: input.OccurrenceID, Acao: input.Acao}, nil
+}
+
+// newTestAutoAssigner returns an auto assigner with fakes, at a Wednesday
+func newTestAutoAssigner(t *testing.T, policy *models.OccurrenceAutoAssignConfig, shifts []models.Shift) (*AutoAssigner, *fakeActiveShifts, *fakeOccurrenceAssigner, *fakeAssignmentHistory) {
+	t.Helper()
+
+	source := &fakeAutoAssignPolicy{}
+	if policy != nil {
+		value, err := json.Marshal(policy)
+		if err != nil {
+			t.Fatalf("Failed to marshal policy: %v", err)
+		}
+		source.setting = &models.SystemSetting{Key: models.SettingKeyOccurrenceAutoAssign, Value: value}
+	}
+
+	activeShifts := &fakeActiveShifts{shifts: shifts}
+	occurrences := &fakeOccurrenceAssigner{assigned: make(map[uuid.UUID]uuid.UUID)}
+	history := &fakeAssignmentHistory{}
+	assigner := &AutoAssigner{
+		policy:      source,
+		shifts:      activeShifts,
+		occurrences: occurrences,
+		history:     history,
+		now:         func() time.Time { return time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC) },
+		logger:      log.New(io.Discard, "", 0),
+	}
+	return assigner, activeShifts, occurrences, history
+}
+
+func onDutyShift(hospitalID uuid.UUID, role models.UserRole) models.Shift {
+	user := &models.User{ID: uuid.New(), Nome: "Plantonista", Role: role, Ativo: true}
+	return models.Shift{ID: uuid.New(), HospitalID: hospitalID, UserID: user.ID, User: user}
+}
+
+// TestAutoAssignOnDutyOperator tests that an occurrence is assigned to the operator on duty
+func TestAutoAssignOnDutyOperator(t *testing.T) {
+	hospitalID := uuid.New()
+	gestor := onDutyShift(hospitalID, models.RoleGestor)
+	operador := onDutyShift(hospitalID, models.RoleOperador)
+	assigner, activeShifts, occurrences, history := newTestAutoAssigner(t,
+		&models.OccurrenceAutoAssignConfig{HospitalIDs: []uuid.UUID{hospitalID}},
+		[]models.Shift{gestor, operador})
+
+	occurrence := &models.Occurrence{ID: uuid.New(), TenantID: uuid.New(), HospitalID: hospitalID}
+	assigned, err := assigner.Assign(context.Background(), occurrence)
+	if err != nil {
+		t.Fatalf("Failed to auto-assign: %v", err)
+	}
+
+	if assigned == nil || assigned.ID != operador.UserID {
+		t.Fatalf("Expected the operator on duty to be assigned, got %v", assigned)
+	}
+	if occurrences.assigned[occurrence.ID] != operador.UserID {
+		t.Errorf("Expected the assignment to be stored, got %v", occurrences.assigned)
+	}
+	if occurrence.AssignedUserID == nil || *occurrence.AssignedUserID != operador.UserID {
+		t.Errorf("Expected the occurrence to carry the assignee, got %v", occurrence.AssignedUserID)
+	}
+	if activeShifts.hospitalID != hospitalID || !activeShifts.at.Equal(assigner.now()) {
+		t.Errorf("Expected the shifts of the hospital at %s, got %s at %s", assigner.now(), activeShifts.hospitalID, activeShifts.at)
+	}
+	if len(history.entries) != 1 {
+		t.Fatalf("Expected one history entry, got %d", len(history.entries))
+	}
+	if history.entries[0].Acao != models.ActionOccurrenceAssigned || history.entries[0].OccurrenceID != occurrence.ID {
+		t.Errorf("Unexpected history entry %+v", history.entries[0])
+	}
+}
+
+// TestAutoAssignByTenant tests that a tenant-wide policy covers every hospital of the tenant
+func TestAutoAssignByTenant(t *testing.T) {
+	tenantID, hospitalID := uuid.New(), uuid.New()
+	operador := onDutyShift(hospitalID, models.RoleOperador)
+	assigner, _, occurrences, _ := newTestAutoAssigner(t,
+		&models.OccurrenceAutoAssignConfig{TenantIDs: []uuid.UUID{tenantID}},
+		[]models.Shift{operador})
+
+	occurrence := &models.Occurrence{ID: uuid.New(), TenantID: tenantID, HospitalID: hospitalID}
+	if _, err := assigner.Assign(context.Background(), occurrence); err != nil {
+		t.Fatalf("Failed to auto-assign: %v", err)
+	}
+	if occurrences.assigned[occurrence.ID] != operador.UserID {
+		t.Errorf("Expected the occurrence to be assigned, got %v", occurrences.assigned)
+	}
+}
+
+// TestAutoAssignSkipped tests that no assignment is made when no one is on duty or the policy is off
+func TestAutoAssignSkipped(t *testing.T) {
+	hospitalID := uuid.New()
+	enabled := &models.OccurrenceAutoAssignConfig{HospitalIDs: []uuid.UUID{hospitalID}}
+
+	tests := []struct {
+		name   string
+		policy *models.OccurrenceAutoAssignConfig
+		shifts []models.Shift
+	}{
+		{"no one on duty", enabled, nil},
+		{"only a gestor on duty", enabled, []models.Shift{onDutyShift(hospitalID, models.RoleGestor)}},
+		{"no policy", nil, []models.Shift{onDutyShift(hospitalID, models.RoleOperador)}},
+		{"other hospital", &models.OccurrenceAutoAssignConfig{HospitalIDs: []uuid.UUID{uuid.New()}}, []models.Shift{onDutyShift(hospitalID, models.RoleOperador)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assigner, _, occurrences, history := newTestAutoAssigner(t, tt.policy, tt.shifts)
+
+			occurrence := &models.Occurrence{ID: uuid.New(), TenantID: uuid.New(), HospitalID: hospitalID}
+			assigned, err := assigner.Assign(context.Background(), occurrence)
+			if err != nil {
+				t.Fatalf("Failed to auto-assign: %v", err)
+			}
+			if assigned != nil || len(occurrences.assigned) != 0 || len(history.entries) != 0 {
+				t.Errorf("Expected no assignment, got %v with %d history entries", occurrences.assigned, len(history.entries))
+			}
+			if occurrence.AssignedUserID != nil {
+				t.Errorf("Expected the occurrence to stay unassigned")
+			}
+		})
+	}
+}
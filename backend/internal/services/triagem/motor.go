@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"log"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -30,6 +31,9 @@ const (
 
 	// Default rules cache TTL
 	DefaultRulesCacheTTL = 5 * time.Minute
+
+	// DefaultDrainTimeout bounds how long Stop waits for the messages already read to be processed
+	DefaultDrainTimeout = 10 * time.Second
 )
 
 var (
@@ -45,6 +49,13 @@ type TriagemResult struct {
 	Score        int      `json:"score"`
 	Motivos      []string `json:"motivos,omitempty"`
 	RulesApplied []string `json:"rules_applied,omitempty"`
+	RejectedBy   string   `json:"rejected_by,omitempty"` // Name of the first rule that found the obito ineligible
+	Flags        []string `json:"flags,omitempty"`       // Warnings of the failing "flag" rules, attached to the occurrence
+}
+
+// ineligibilityRecorder persists the ineligible obitos (implemented by repository.ObitoIneligibilityRepository)
+type ineligibilityRecorder interface {
+	Create(ctx context.Context, obitoID uuid.UUID, motivos, regrasAvaliadas []string, regraRejeicao string) (*models.ObitoIneligibility, error)
 }
 
 // TriagemMotor consumes obito events and applies triagem rules
@@ -57,6 +68,9 @@ type TriagemMotor struct {
 	ruleRepo     *repository.TriagemRuleRepository
 	hospitalRepo *repository.HospitalRepository
 
+	// Records of the ineligible obitos
+	ineligibilities ineligibilityRecorder
+
 	// Cached rules
 	cachedRules    []models.TriagemRule
 	rulesCacheTime time.Time
@@ -72,12 +86,23 @@ type TriagemMotor struct {
 	startedAt        time.Time
 
 	// Control channels
-	stopCh chan struct{}
-	doneCh chan struct{}
-
-	// Callback for new occurrences (used for SSE notifications)
+	stopCh      chan struct{}
+	doneCh      chan struct{}
+	rulesDoneCh chan struct{}
+
+	// Draining: cancelRead interrupts the blocking stream read on Stop, cancelWork
+	// aborts the processing of the messages already read after drainTimeout
+	cancelRead   context.CancelFunc
+	cancelWork   context.CancelFunc
+	drainTimeout time.Duration
+
+	// Optional callback for new occurrences. Best effort only: SSE and notifications are
+	// delivered from the events outbox, written in the same transaction as the occurrence.
 	onOccurrenceCreated OccurrenceCreatedCallback
 
+	// Optional auto-assignment of new occurrences to the operator on duty
+	autoAssigner *AutoAssigner
+
 	// Logger
 	logger *log.Logger
 }
@@ -85,17 +110,19 @@ type TriagemMotor struct {
 // NewTriagemMotor creates a new TriagemMotor
 func NewTriagemMotor(db *sql.DB, redisClient *redis.Client) *TriagemMotor {
 	return &TriagemMotor{
-		db:            db,
-		redis:         redisClient,
-		obitoRepo:     repository.NewObitoRepository(db),
-		occRepo:       repository.NewOccurrenceRepository(db),
-		historyRepo:   repository.NewOccurrenceHistoryRepository(db),
-		ruleRepo:      repository.NewTriagemRuleRepository(db, redisClient),
-		hospitalRepo:  repository.NewHospitalRepository(db),
-		rulesCacheTTL: DefaultRulesCacheTTL,
-		stopCh:        make(chan struct{}),
-		doneCh:        make(chan struct{}),
-		logger:        log.Default(),
+		db:              db,
+		redis:           redisClient,
+		obitoRepo:       repository.NewObitoRepository(db),
+		occRepo:         repository.NewOccurrenceRepository(db),
+		historyRepo:     repository.NewOccurrenceHistoryRepository(db),
+		ruleRepo:        repository.NewTriagemRuleRepository(db, redisClient),
+		hospitalRepo:    repository.NewHospitalRepository(db),
+		ineligibilities: repository.NewObitoIneligibilityRepository(db),
+		rulesCacheTTL:   DefaultRulesCacheTTL,
+		drainTimeout:    DefaultDrainTimeout,
+		stopCh:          make(chan struct{}),
+		doneCh:          make(chan struct{}),
+		logger:          log.Default(),
 	}
 }
 
@@ -104,6 +131,16 @@ func (m *TriagemMotor) SetOnOccurrenceCreated(callback OccurrenceCreatedCallback
 	m.onOccurrenceCreated = callback
 }
 
+// SetAutoAssigner enables the auto-assignment of new occurrences to the operator on duty
+func (m *TriagemMotor) SetAutoAssigner(assigner *AutoAssigner) {
+	m.autoAssigner = assigner
+}
+
+// SetDrainTimeout sets how long Stop waits for the messages already read to be processed
+func (m *TriagemMotor) SetDrainTimeout(timeout time.Duration) {
+	m.drainTimeout = timeout
+}
+
 // Start begins the consumer loop
 func (m *TriagemMotor) Start(ctx context.Context) error {
 	if !atomic.CompareAndSwapInt32(&m.running, 0, 1) {
@@ -120,16 +157,45 @@ func (m *TriagemMotor) Start(ctx context.Context) error {
 		// Continue anyway - group might already exist
 	}
 
-	go m.consumeLoop(ctx)
+	// Without the subscription the rules cache still expires after rulesCacheTTL
+	if err := m.subscribeRulesChanges(ctx); err != nil {
+		m.logger.Printf("[Triagem] Warning: Could not subscribe to rule changes: %v", err)
+	}
+
+	// Messages already read are processed under a context that outlives ctx, so cancelling the
+	// background services does not leave them half processed; Stop bounds their processing instead
+	readCtx, cancelRead := context.WithCancel(ctx)
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	m.cancelRead = cancelRead
+	m.cancelWork = cancelWork
+
+	go m.consumeLoop(readCtx, workCtx)
 
 	return nil
 }
 
-// Stop stops the consumer loop
+// Stop stops reading new messages, then waits up to the drain timeout for the messages already
+// read to be processed. Messages still unprocessed after it are left unacknowledged, and read
+// again from the pending entries on the next start.
 func (m *TriagemMotor) Stop() {
 	if atomic.CompareAndSwapInt32(&m.running, 1, 0) {
 		close(m.stopCh)
-		<-m.doneCh
+		m.cancelRead()
+
+		timer := time.NewTimer(m.drainTimeout)
+		select {
+		case <-m.doneCh:
+			timer.Stop()
+		case <-timer.C:
+			m.logger.Printf("[Triagem] Warning: in-flight messages not processed within %s, leaving them pending", m.drainTimeout)
+			m.cancelWork()
+			<-m.doneCh
+		}
+		m.cancelWork()
+
+		if m.rulesDoneCh != nil {
+			<-m.rulesDoneCh
+		}
 		m.logger.Println("[Triagem] Triagem motor stopped")
 	}
 }
@@ -153,26 +219,64 @@ func (m *TriagemMotor) createConsumerGroup(ctx context.Context) error {
 	return nil
 }
 
-// consumeLoop is the main consumer loop
-func (m *TriagemMotor) consumeLoop(ctx context.Context) {
+// consumeLoop is the main consumer loop. Messages are read with readCtx and processed with workCtx.
+func (m *TriagemMotor) consumeLoop(readCtx, workCtx context.Context) {
 	defer close(m.doneCh)
 
+	// Messages read but not acknowledged before the last shutdown come first
+	m.consumePending(readCtx, workCtx)
+
 	for {
 		select {
-		case <-ctx.Done():
+		case <-readCtx.Done():
 			return
 		case <-m.stopCh:
 			return
 		default:
-			m.consumeMessages(ctx)
+			m.consumeMessages(readCtx, workCtx)
+		}
+	}
+}
+
+// consumePending processes the pending entries of this consumer: messages delivered to it
+// that were never acknowledged, e.g. because the drain timeout expired on shutdown
+func (m *TriagemMotor) consumePending(readCtx, workCtx context.Context) {
+	lastID := "0"
+	for readCtx.Err() == nil {
+		streams, err := m.redis.XReadGroup(readCtx, &redis.XReadGroupArgs{
+			Group:    ConsumerGroupName,
+			Consumer: ConsumerName,
+			Streams:  []string{listener.ObitosStreamName, lastID},
+			Count:    10,
+			Block:    -1, // Pending entries are returned right away
+		}).Result()
+		if err != nil {
+			if err != redis.Nil && !strings.Contains(err.Error(), "context") {
+				m.logger.Printf("[Triagem] Error reading pending messages: %v", err)
+				atomic.AddInt64(&m.errors, 1)
+			}
+			return
+		}
+
+		read := 0
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				m.processMessage(workCtx, message)
+				lastID = message.ID
+				read++
+			}
+		}
+		if read == 0 {
+			return
 		}
 	}
 }
 
 // consumeMessages reads and processes messages from the stream
-func (m *TriagemMotor) consumeMessages(ctx context.Context) {
+// The whole batch read is processed, even when Stop is called meanwhile
+func (m *TriagemMotor) consumeMessages(readCtx, workCtx context.Context) {
 	// Read messages using XREADGROUP
-	streams, err := m.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+	streams, err := m.redis.XReadGroup(readCtx, &redis.XReadGroupArgs{
 		Group:    ConsumerGroupName,
 		Consumer: ConsumerName,
 		Streams:  []string{listener.ObitosStreamName, ">"},
@@ -192,7 +296,7 @@ func (m *TriagemMotor) consumeMessages(ctx context.Context) {
 
 	for _, stream := range streams {
 		for _, message := range stream.Messages {
-			m.processMessage(ctx, message)
+			m.processMessage(workCtx, message)
 		}
 	}
 }
@@ -269,6 +373,16 @@ func (m *TriagemMotor) processMessage(ctx context.Context, message redis.XMessag
 			atomic.AddInt64(&m.totalElegiveis, 1)
 			m.logger.Printf("[Triagem] Obito %s is ELIGIBLE - Occurrence created with score %d", obitoID, result.Score)
 
+			// Assign to the operator on duty when the hospital has the policy enabled
+			if m.autoAssigner != nil && occurrence != nil {
+				operator, err := m.autoAssigner.Assign(ctx, occurrence)
+				if err != nil {
+					m.logger.Printf("[Triagem] Warning: Could not auto-assign occurrence %s: %v", occurrence.ID, err)
+				} else if operator != nil {
+					m.logger.Printf("[Triagem] Occurrence %s auto-assigned to %s", occurrence.ID, operator.ID)
+				}
+			}
+
 			// Trigger notification callback if set
 			if m.onOccurrenceCreated != nil && occurrence != nil {
 				hospitalNome := m.getHospitalName(ctx, obito.HospitalID)
@@ -276,14 +390,29 @@ func (m *TriagemMotor) processMessage(ctx context.Context, message redis.XMessag
 			}
 		}
 	} else {
-		atomic.AddInt64(&m.totalInelegiveis, 1)
-		m.logger.Printf("[Triagem] Obito %s is INELIGIBLE - Reasons: %s", obitoID, strings.Join(result.Motivos, ", "))
+		m.handleIneligible(ctx, obitoID, result)
+	}
+
+	// Processing aborted by the drain timeout: leave the message pending so it is processed again
+	if ctx.Err() != nil {
+		m.logger.Printf("[Triagem] Processing of obito %s aborted, message %s left pending", obitoID, message.ID)
+		return
 	}
 
 	// Acknowledge the message
 	m.ackMessage(ctx, message.ID)
 }
 
+// handleIneligible records why the obito is ineligible, so gestores can audit the rejections and tune the rules
+func (m *TriagemMotor) handleIneligible(ctx context.Context, obitoID uuid.UUID, result *TriagemResult) {
+	atomic.AddInt64(&m.totalInelegiveis, 1)
+	m.logger.Printf("[Triagem] Obito %s is INELIGIBLE by rule %s - Reasons: %s", obitoID, result.RejectedBy, strings.Join(result.Motivos, ", "))
+
+	if _, err := m.ineligibilities.Create(ctx, obitoID, result.Motivos, result.RulesApplied, result.RejectedBy); err != nil {
+		m.logger.Printf("[Triagem] Warning: Could not record ineligibility of obito %s: %v", obitoID, err)
+	}
+}
+
 // getHospitalName retrieves the hospital name for notifications
 func (m *TriagemMotor) getHospitalName(ctx context.Context, hospitalID uuid.UUID) string {
 	hospital, err := m.hospitalRepo.GetByID(ctx, hospitalID)
@@ -303,13 +432,6 @@ func (m *TriagemMotor) ackMessage(ctx context.Context, messageID string) {
 
 // ApplyRules applies all active triagem rules to an obito
 func (m *TriagemMotor) ApplyRules(ctx context.Context, obito *models.ObitoSimulado) (*TriagemResult, error) {
-	result := &TriagemResult{
-		Elegivel:     true,
-		Score:        0,
-		Motivos:      []string{},
-		RulesApplied: []string{},
-	}
-
 	// Get cached rules
 	rules, err := m.getCachedRules(ctx)
 	if err != nil {
@@ -318,29 +440,63 @@ func (m *TriagemMotor) ApplyRules(ctx context.Context, obito *models.ObitoSimula
 		rules = m.getDefaultRules()
 	}
 
-	// Apply each rule
-	for _, rule := range rules {
+	return m.evaluateRules(obito, rules, time.Now()), nil
+}
+
+// evaluateRules applies the given rules to an obito as of the given moment
+// Time-dependent rules (janela_horas) and the priority score are evaluated relative to at
+func (m *TriagemMotor) evaluateRules(obito *models.ObitoSimulado, rules []models.TriagemRule, at time.Time) *TriagemResult {
+	result := &TriagemResult{
+		Elegivel:     true,
+		Score:        0,
+		Motivos:      []string{},
+		RulesApplied: []string{},
+	}
+
+	// Apply each rule, highest priority first; a rejecting rule with the "rejeitar" action ends the evaluation,
+	// a failing "flag" rule keeps the obito eligible and only records its warning
+	for _, rule := range sortRulesByPriority(rules) {
 		if !rule.Ativo {
 			continue
 		}
 
-		ruleResult := m.applyRule(obito, &rule)
+		ruleResult, acao := m.applyRule(obito, &rule, at)
 		result.RulesApplied = append(result.RulesApplied, rule.Nome)
+		result.Score += ruleResult.Score
+
+		if !ruleResult.Elegivel && acao == models.RuleActionFlag {
+			result.Flags = append(result.Flags, ruleResult.Motivos...)
+			continue
+		}
 
 		if !ruleResult.Elegivel {
 			result.Elegivel = false
 			result.Motivos = append(result.Motivos, ruleResult.Motivos...)
+			if result.RejectedBy == "" {
+				result.RejectedBy = rule.Nome
+			}
+			if acao == models.RuleActionRejeitar {
+				break
+			}
 		}
-
-		result.Score += ruleResult.Score
 	}
 
 	// Calculate final score based on sector if eligible
 	if result.Elegivel {
-		result.Score = m.calculatePriorityScore(obito)
+		result.Score = m.calculatePriorityScore(obito, at)
 	}
 
-	return result, nil
+	return result
+}
+
+// sortRulesByPriority returns a copy of the rules ordered by prioridade descending, keeping the order of equal priorities
+func sortRulesByPriority(rules []models.TriagemRule) []models.TriagemRule {
+	sorted := make([]models.TriagemRule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Prioridade > sorted[j].Prioridade
+	})
+	return sorted
 }
 
 // getCachedRules gets rules from cache or database
@@ -405,8 +561,8 @@ func (m *TriagemMotor) getDefaultRules() []models.TriagemRule {
 	}
 }
 
-// applyRule applies a single rule to an obito
-func (m *TriagemMotor) applyRule(obito *models.ObitoSimulado, rule *models.TriagemRule) *TriagemResult {
+// applyRule applies a single rule to an obito, returning the result and the action of the rule
+func (m *TriagemMotor) applyRule(obito *models.ObitoSimulado, rule *models.TriagemRule, at time.Time) (*TriagemResult, models.RuleAction) {
 	result := &TriagemResult{
 		Elegivel: true,
 		Score:    0,
@@ -416,28 +572,32 @@ func (m *TriagemMotor) applyRule(obito *models.ObitoSimulado, rule *models.Triag
 	var config models.RuleConfig
 	if err := json.Unmarshal(rule.Regras, &config); err != nil {
 		m.logger.Printf("[Triagem] Error parsing rule config: %v", err)
-		return result
+		return result, ""
+	}
+	if err := config.Validate(); err != nil {
+		m.logger.Printf("[Triagem] Skipping rule %s: %v", rule.Nome, err)
+		return result, ""
 	}
 
 	switch config.Tipo {
 	case models.RuleTypeIdadeMaxima:
-		return m.applyIdadeMaximaRule(obito, config.Valor)
+		return m.applyIdadeMaximaRule(obito, config.Valor), config.Acao
 
 	case models.RuleTypeJanelaHoras:
-		return m.applyJanelaHorasRule(obito, config.Valor)
+		return m.applyJanelaHorasRule(obito, config.Valor, at), config.Acao
 
 	case models.RuleTypeIdentificacaoDesconhecida:
-		return m.applyIdentificacaoDesconhecidaRule(obito, config.Valor)
+		return m.applyIdentificacaoDesconhecidaRule(obito, config.Valor), config.Acao
 
 	case models.RuleTypeCausasExcludentes:
-		return m.applyCausasExcludentesRule(obito, config.Valor)
+		return m.applyCausasExcludentesRule(obito, config.Valor), config.Acao
 
 	case models.RuleTypeSetorPriorizacao:
 		// This rule only affects score, not eligibility
-		return result
+		return result, config.Acao
 	}
 
-	return result
+	return result, config.Acao
 }
 
 // applyIdadeMaximaRule applies the maximum age rule
@@ -459,7 +619,7 @@ func (m *TriagemMotor) applyIdadeMaximaRule(obito *models.ObitoSimulado, valor i
 }
 
 // applyJanelaHorasRule applies the time window rule
-func (m *TriagemMotor) applyJanelaHorasRule(obito *models.ObitoSimulado, valor interface{}) *TriagemResult {
+func (m *TriagemMotor) applyJanelaHorasRule(obito *models.ObitoSimulado, valor interface{}, at time.Time) *TriagemResult {
 	result := &TriagemResult{Elegivel: true, Motivos: []string{}}
 
 	windowHours, ok := valor.(float64)
@@ -467,7 +627,7 @@ func (m *TriagemMotor) applyJanelaHorasRule(obito *models.ObitoSimulado, valor i
 		return result
 	}
 
-	if !obito.IsWithinWindow(int(windowHours)) {
+	if !obito.IsWithinWindowAt(int(windowHours), at) {
 		result.Elegivel = false
 		result.Motivos = append(result.Motivos, "Fora da janela de captacao")
 	}
@@ -521,7 +681,7 @@ func (m *TriagemMotor) applyCausasExcludentesRule(obito *models.ObitoSimulado, v
 }
 
 // calculatePriorityScore calculates the priority score based on sector and time remaining
-func (m *TriagemMotor) calculatePriorityScore(obito *models.ObitoSimulado) int {
+func (m *TriagemMotor) calculatePriorityScore(obito *models.ObitoSimulado, at time.Time) int {
 	baseScore := 50 // Default score
 
 	// Get sector score
@@ -530,7 +690,7 @@ func (m *TriagemMotor) calculatePriorityScore(obito *models.ObitoSimulado) int {
 	}
 
 	// Adjust by time remaining (more urgent = higher score)
-	remaining := obito.TimeRemaining(6) // 6 hour window
+	remaining := obito.TimeRemainingAt(6, at) // 6 hour window
 	if remaining > 0 {
 		hoursRemaining := remaining.Hours()
 		if hoursRemaining <= 1 {
@@ -550,8 +710,8 @@ func (m *TriagemMotor) calculatePriorityScore(obito *models.ObitoSimulado) int {
 	return baseScore
 }
 
-// createOccurrence creates a new occurrence for an eligible obito
-func (m *TriagemMotor) createOccurrence(ctx context.Context, obito *models.ObitoSimulado, result *TriagemResult) (*models.Occurrence, error) {
+// newOccurrenceInput builds the occurrence of an eligible obito with the score and flags of its triagem
+func newOccurrenceInput(obito *models.ObitoSimulado, result *TriagemResult) (*models.CreateOccurrenceInput, error) {
 	// Prepare complete data
 	completeData := obito.ToOccurrenceData()
 	completeDataJSON, err := json.Marshal(completeData)
@@ -559,14 +719,22 @@ func (m *TriagemMotor) createOccurrence(ctx context.Context, obito *models.Obito
 		return nil, err
 	}
 
-	// Create occurrence input
-	input := &models.CreateOccurrenceInput{
+	return &models.CreateOccurrenceInput{
 		ObitoID:               obito.ID,
 		HospitalID:            obito.HospitalID,
 		ScorePriorizacao:      result.Score,
 		NomePacienteMascarado: models.MaskName(obito.NomePaciente),
 		DadosCompletos:        completeDataJSON,
 		DataObito:             obito.DataObito,
+		Flags:                 result.Flags,
+	}, nil
+}
+
+// createOccurrence creates a new occurrence for an eligible obito
+func (m *TriagemMotor) createOccurrence(ctx context.Context, obito *models.ObitoSimulado, result *TriagemResult) (*models.Occurrence, error) {
+	input, err := newOccurrenceInput(obito, result)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create the occurrence
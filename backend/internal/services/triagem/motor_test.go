@@ -1,7 +1,11 @@
 package triagem
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"log"
+	"reflect"
 	"testing"
 	"time"
 
@@ -9,6 +13,183 @@ import (
 	"github.com/sidot/backend/internal/models"
 )
 
+// recordingIneligibilities records the ineligible obitos in memory
+type recordingIneligibilities struct {
+	records []models.ObitoIneligibility
+}
+
+func (r *recordingIneligibilities) Create(ctx context.Context, obitoID uuid.UUID, motivos, regrasAvaliadas []string, regraRejeicao string) (*models.ObitoIneligibility, error) {
+	record := models.ObitoIneligibility{ID: uuid.New(), ObitoID: obitoID, Motivos: motivos, RegrasAvaliadas: regrasAvaliadas, RegraRejeicao: &regraRejeicao, CreatedAt: time.Now()}
+	r.records = append(r.records, record)
+	return &record, nil
+}
+
+// TestIneligibleObitoWritesReasonRecord tests that an obito rejected by the rules is recorded
+// with the reasons and evaluated rules returned by the engine
+func TestIneligibleObitoWritesReasonRecord(t *testing.T) {
+	motor := NewTriagemMotor(nil, nil)
+	recorder := &recordingIneligibilities{}
+	motor.ineligibilities = recorder
+	motor.cachedRules = motor.getDefaultRules()
+	motor.rulesCacheTime = time.Now()
+
+	obitos := simulationTestObitos(time.Now())
+	tooOld := obitos[2]
+
+	result, err := motor.ApplyRules(context.Background(), &tooOld)
+	if err != nil {
+		t.Fatalf("ApplyRules failed: %v", err)
+	}
+	if result.Elegivel || len(result.Motivos) == 0 {
+		t.Fatalf("Expected the obito to be ineligible with reasons, got %+v", result)
+	}
+
+	motor.handleIneligible(context.Background(), tooOld.ID, result)
+
+	if len(recorder.records) != 1 {
+		t.Fatalf("Expected 1 ineligibility record, got %d", len(recorder.records))
+	}
+	record := recorder.records[0]
+	if record.ObitoID != tooOld.ID {
+		t.Errorf("Expected the record of obito %s, got %s", tooOld.ID, record.ObitoID)
+	}
+	if !reflect.DeepEqual(record.Motivos, result.Motivos) {
+		t.Errorf("Expected reasons %v, got %v", result.Motivos, record.Motivos)
+	}
+	if !reflect.DeepEqual(record.RegrasAvaliadas, result.RulesApplied) {
+		t.Errorf("Expected evaluated rules %v, got %v", result.RulesApplied, record.RegrasAvaliadas)
+	}
+	if *record.RegraRejeicao != "Idade Maxima" || result.RejectedBy != "Idade Maxima" {
+		t.Errorf("Expected the age rule to be recorded as the rejecting rule, got %s", *record.RegraRejeicao)
+	}
+	if stats := motor.GetStats(); stats["total_inelegiveis"].(int64) != 1 {
+		t.Errorf("Expected 1 ineligible obito in the stats, got %v", stats["total_inelegiveis"])
+	}
+}
+
+// TestEvaluateRulesFollowsPriority tests that rules are evaluated by prioridade descending, whatever their order,
+// and that the first rejecting rule with the "rejeitar" action ends the evaluation
+func TestEvaluateRulesFollowsPriority(t *testing.T) {
+	motor := NewTriagemMotor(nil, nil)
+	motor.logger = log.New(io.Discard, "", 0)
+
+	rule := func(nome string, prioridade int, regras string) models.TriagemRule {
+		return models.TriagemRule{ID: uuid.New(), Nome: nome, Ativo: true, Prioridade: prioridade, Regras: json.RawMessage(regras)}
+	}
+	idade := rule("Idade Maxima", 90, `{"tipo": "idade_maxima", "valor": 80, "acao": "rejeitar"}`)
+	janela := rule("Janela 6 Horas", 100, `{"tipo": "janela_horas", "valor": 6, "acao": "rejeitar"}`)
+	desconhecida := rule("Identificacao Desconhecida", 50, `{"tipo": "identificacao_desconhecida", "valor": true, "acao": "alertar"}`)
+	setor := rule("Setor", 10, `{"tipo": "setor_priorizacao", "valor": {"UTI": 100}, "acao": "priorizar"}`)
+	rules := []models.TriagemRule{setor, desconhecida, idade, janela}
+
+	obitos := simulationTestObitos(time.Now())
+	at := time.Now()
+
+	t.Run("Eligible obito evaluates every rule by priority", func(t *testing.T) {
+		result := motor.evaluateRules(&obitos[0], rules, at)
+		expected := []string{"Janela 6 Horas", "Idade Maxima", "Identificacao Desconhecida", "Setor"}
+		if !result.Elegivel || !reflect.DeepEqual(result.RulesApplied, expected) {
+			t.Errorf("Expected an eligible obito evaluated in order %v, got %+v", expected, result)
+		}
+		if result.RejectedBy != "" {
+			t.Errorf("Expected no rejecting rule, got %s", result.RejectedBy)
+		}
+	})
+
+	t.Run("Rejecting rule stops the evaluation", func(t *testing.T) {
+		tooOld := obitos[2]
+		tooOld.IdentificacaoDesconhecida = true
+		result := motor.evaluateRules(&tooOld, rules, at)
+
+		expected := []string{"Janela 6 Horas", "Idade Maxima"}
+		if result.Elegivel || !reflect.DeepEqual(result.RulesApplied, expected) {
+			t.Errorf("Expected the evaluation to stop at the age rule, got %+v", result)
+		}
+		if result.RejectedBy != "Idade Maxima" || !reflect.DeepEqual(result.Motivos, []string{"Idade acima do limite"}) {
+			t.Errorf("Expected the age rule to reject the obito, got %s %v", result.RejectedBy, result.Motivos)
+		}
+	})
+
+	t.Run("Higher priority rejection wins", func(t *testing.T) {
+		oldOutsideWindow := obitos[2]
+		oldOutsideWindow.DataObito = at.Add(-7 * time.Hour)
+		result := motor.evaluateRules(&oldOutsideWindow, rules, at)
+
+		if result.RejectedBy != "Janela 6 Horas" || !reflect.DeepEqual(result.RulesApplied, []string{"Janela 6 Horas"}) {
+			t.Errorf("Expected the window rule (priority 100) to reject first, got %+v", result)
+		}
+	})
+
+	t.Run("Non rejecting action does not stop the evaluation", func(t *testing.T) {
+		unknown := obitos[4]
+		result := motor.evaluateRules(&unknown, rules, at)
+
+		if result.Elegivel || result.RejectedBy != "Identificacao Desconhecida" || len(result.RulesApplied) != 4 {
+			t.Errorf("Expected every rule evaluated after an alert, got %+v", result)
+		}
+	})
+
+	t.Run("Rule order is not changed", func(t *testing.T) {
+		if rules[0].Nome != "Setor" || rules[3].Nome != "Janela 6 Horas" {
+			t.Error("Expected evaluateRules not to reorder the given rules")
+		}
+	})
+}
+
+// TestFlagRuleKeepsObitoEligible tests that a failing rule with the "flag" action does not reject the obito
+// and that its warning is carried to the occurrence created for it
+func TestFlagRuleKeepsObitoEligible(t *testing.T) {
+	motor := NewTriagemMotor(nil, nil)
+	motor.logger = log.New(io.Discard, "", 0)
+
+	rules := []models.TriagemRule{
+		{ID: uuid.New(), Nome: "Idade Limitrofe", Ativo: true, Prioridade: 90, Regras: json.RawMessage(`{"tipo": "idade_maxima", "valor": 80, "acao": "flag"}`)},
+		{ID: uuid.New(), Nome: "Janela 6 Horas", Ativo: true, Prioridade: 100, Regras: json.RawMessage(`{"tipo": "janela_horas", "valor": 6, "acao": "rejeitar"}`)},
+	}
+
+	obitos := simulationTestObitos(time.Now())
+	at := time.Now()
+
+	t.Run("Flagged obito stays eligible", func(t *testing.T) {
+		tooOld := obitos[2]
+		result := motor.evaluateRules(&tooOld, rules, at)
+
+		if !result.Elegivel || result.RejectedBy != "" || len(result.Motivos) != 0 {
+			t.Fatalf("Expected an eligible obito without rejection, got %+v", result)
+		}
+		if !reflect.DeepEqual(result.Flags, []string{"Idade acima do limite"}) {
+			t.Errorf("Expected the age warning as flag, got %v", result.Flags)
+		}
+		if result.Score == 0 {
+			t.Error("Expected the priority score of an eligible obito")
+		}
+
+		input, err := newOccurrenceInput(&tooOld, result)
+		if err != nil {
+			t.Fatalf("newOccurrenceInput failed: %v", err)
+		}
+		if !reflect.DeepEqual(input.Flags, result.Flags) {
+			t.Errorf("Expected the occurrence to carry flags %v, got %v", result.Flags, input.Flags)
+		}
+	})
+
+	t.Run("Passing flag rule adds no flag", func(t *testing.T) {
+		result := motor.evaluateRules(&obitos[0], rules, at)
+		if !result.Elegivel || len(result.Flags) != 0 {
+			t.Errorf("Expected an eligible obito without flags, got %+v", result)
+		}
+	})
+
+	t.Run("Rejecting rule still rejects a flagged obito", func(t *testing.T) {
+		oldOutsideWindow := obitos[2]
+		oldOutsideWindow.DataObito = at.Add(-7 * time.Hour)
+		result := motor.evaluateRules(&oldOutsideWindow, rules, at)
+		if result.Elegivel || result.RejectedBy != "Janela 6 Horas" {
+			t.Errorf("Expected the window rule to reject the obito, got %+v", result)
+		}
+	})
+}
+
 // TestTriagemResultStructure tests the TriagemResult structure
 func TestTriagemResultStructure(t *testing.T) {
 	result := &TriagemResult{
@@ -0,0 +1,57 @@
+package triagem
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sidot/backend/internal/repository"
+)
+
+// subscribeRulesChanges subscribes to rule change notifications published by any instance
+// (see repository.TriagemRuleRepository.InvalidateCache) and invalidates the local rules cache on each one
+func (m *TriagemMotor) subscribeRulesChanges(ctx context.Context) error {
+	if m.redis == nil {
+		return nil
+	}
+
+	pubsub := m.redis.Subscribe(ctx, repository.TriagemRulesChangedChannel)
+
+	// Wait for the subscription to be confirmed so changes published after Start are not missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return err
+	}
+
+	m.rulesDoneCh = make(chan struct{})
+	go m.rulesChangesLoop(ctx, pubsub)
+
+	return nil
+}
+
+// rulesChangesLoop invalidates the rules cache whenever a change notification is received
+// The Pub/Sub connection is re-established by the Redis client if it drops
+func (m *TriagemMotor) rulesChangesLoop(ctx context.Context, pubsub *redis.PubSub) {
+	defer close(m.rulesDoneCh)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.stopCh:
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if msg == nil {
+				continue
+			}
+
+			m.InvalidateRulesCache()
+			m.logger.Printf("[Triagem] Rules cache invalidated (rules changed at %s)", msg.Payload)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package triagem
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sidot/backend/internal/models"
+)
+
+// fakePubSubServer is a minimal RESP2 server implementing the commands used for rule change notifications
+// (SUBSCRIBE, PUBLISH, DEL, PING), so instances can be tested without a real Redis
+type fakePubSubServer struct {
+	listener    net.Listener
+	mu          sync.Mutex
+	subscribers map[string][]*fakeRedisConn
+}
+
+type fakeRedisConn struct {
+	conn       net.Conn
+	mu         sync.Mutex
+	subscribed bool
+}
+
+func (c *fakeRedisConn) write(s string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	io.WriteString(c.conn, s)
+}
+
+func bulkString(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+// startFakePubSubServer starts the server and returns the address clients should connect to
+func startFakePubSubServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &fakePubSubServer{listener: listener, subscribers: make(map[string][]*fakeRedisConn)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(&fakeRedisConn{conn: conn})
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func (s *fakePubSubServer) serve(c *fakeRedisConn) {
+	defer c.conn.Close()
+	reader := bufio.NewReader(c.conn)
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		switch strings.ToUpper(args[0]) {
+		case "SUBSCRIBE":
+			s.mu.Lock()
+			for i, channel := range args[1:] {
+				s.subscribers[channel] = append(s.subscribers[channel], c)
+				c.subscribed = true
+				c.write("*3\r\n" + bulkString("subscribe") + bulkString(channel) + ":" + strconv.Itoa(i+1) + "\r\n")
+			}
+			s.mu.Unlock()
+		case "PUBLISH":
+			s.mu.Lock()
+			subscribers := s.subscribers[args[1]]
+			s.mu.Unlock()
+			for _, sub := range subscribers {
+				sub.write("*3\r\n" + bulkString("message") + bulkString(args[1]) + bulkString(args[2]))
+			}
+			c.write(":" + strconv.Itoa(len(subscribers)) + "\r\n")
+		case "DEL":
+			c.write(":1\r\n")
+		case "PING":
+			if c.subscribed {
+				c.write("*2\r\n" + bulkString("pong") + bulkString(""))
+			} else {
+				c.write("+PONG\r\n")
+			}
+		case "CLIENT":
+			c.write("+OK\r\n")
+		default:
+			c.write("-ERR unknown command '" + args[0] + "'\r\n")
+		}
+	}
+}
+
+// readCommand reads one RESP array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || count < 1 {
+		return nil, fmt.Errorf("unexpected command line %q", line)
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, fmt.Errorf("unexpected argument line %q", line)
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+// rulesCached reports whether the motor currently holds cached rules
+func rulesCached(m *TriagemMotor) bool {
+	m.rulesMu.RLock()
+	defer m.rulesMu.RUnlock()
+	return len(m.cachedRules) > 0
+}
+
+// TestRulesChangeInvalidatesOtherInstances tests that a rule change published by one instance
+// invalidates the rules cache of another instance
+func TestRulesChangeInvalidatesOtherInstances(t *testing.T) {
+	addr := startFakePubSubServer(t)
+
+	newInstance := func() *TriagemMotor {
+		client := redis.NewClient(&redis.Options{Addr: addr})
+		t.Cleanup(func() { client.Close() })
+		return NewTriagemMotor(nil, client)
+	}
+	first, second := newInstance(), newInstance()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, m := range []*TriagemMotor{first, second} {
+		if err := m.subscribeRulesChanges(ctx); err != nil {
+			t.Fatalf("subscribeRulesChanges() error = %v", err)
+		}
+		m.rulesMu.Lock()
+		m.cachedRules = []models.TriagemRule{{Nome: "Idade Maxima", Ativo: true}}
+		m.rulesCacheTime = time.Now()
+		m.rulesMu.Unlock()
+	}
+
+	// Editing a rule on the first instance goes through the repository, which publishes the change
+	first.ruleRepo.InvalidateCache(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for rulesCached(second) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if rulesCached(second) {
+		t.Fatal("Expected the second instance to invalidate its rules cache after the first published a change")
+	}
+
+	cancel()
+	<-first.rulesDoneCh
+	<-second.rulesDoneCh
+}
+
+// TestSubscribeRulesChangesWithoutRedis tests that a motor without Redis falls back to TTL expiry only
+func TestSubscribeRulesChangesWithoutRedis(t *testing.T) {
+	m := NewTriagemMotor(nil, nil)
+
+	if err := m.subscribeRulesChanges(context.Background()); err != nil {
+		t.Errorf("Expected no error without Redis, got %v", err)
+	}
+	if m.rulesDoneCh != nil {
+		t.Error("Expected no subscription loop without Redis")
+	}
+}
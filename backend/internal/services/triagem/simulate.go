@@ -0,0 +1,88 @@
+package triagem
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// SimulationObitoResult is the outcome of a candidate rule set for a single obito
+type SimulationObitoResult struct {
+	ObitoID      uuid.UUID `json:"obito_id"`
+	HospitalID   uuid.UUID `json:"hospital_id"`
+	HospitalNome string    `json:"hospital_nome,omitempty"`
+	DataObito    time.Time `json:"data_obito"`
+	AvaliadoEm   time.Time `json:"avaliado_em"`
+	Elegivel     bool      `json:"elegivel"`
+	Score        int       `json:"score"`
+	Motivos      []string  `json:"motivos"`
+	RulesApplied []string  `json:"rules_applied"`
+	RejectedBy   string    `json:"rejected_by,omitempty"`
+	Flags        []string  `json:"flags,omitempty"`
+}
+
+// SimulationSummary aggregates the outcome of a simulation
+type SimulationSummary struct {
+	Total       int            `json:"total"`
+	Elegiveis   int            `json:"elegiveis"`
+	Inelegiveis int            `json:"inelegiveis"`
+	Motivos     map[string]int `json:"motivos"`
+}
+
+// SimulationResult is the response of a triagem dry-run
+type SimulationResult struct {
+	Results []SimulationObitoResult `json:"results"`
+	Summary SimulationSummary       `json:"summary"`
+}
+
+// Simulate evaluates a candidate rule set against obitos without touching
+// the cached active rules, creating occurrences or updating motor stats.
+// Each obito is evaluated as of when it was registered (created_at), which is
+// when the motor would have processed it.
+func (m *TriagemMotor) Simulate(obitos []models.ObitoSimulado, rules []models.TriagemRule) *SimulationResult {
+	result := &SimulationResult{
+		Results: make([]SimulationObitoResult, 0, len(obitos)),
+		Summary: SimulationSummary{Motivos: map[string]int{}},
+	}
+
+	for i := range obitos {
+		obito := &obitos[i]
+
+		evaluatedAt := obito.CreatedAt
+		if evaluatedAt.IsZero() {
+			evaluatedAt = obito.DataObito
+		}
+
+		triagemResult := m.evaluateRules(obito, rules, evaluatedAt)
+
+		entry := SimulationObitoResult{
+			ObitoID:      obito.ID,
+			HospitalID:   obito.HospitalID,
+			DataObito:    obito.DataObito,
+			AvaliadoEm:   evaluatedAt,
+			Elegivel:     triagemResult.Elegivel,
+			Score:        triagemResult.Score,
+			Motivos:      triagemResult.Motivos,
+			RulesApplied: triagemResult.RulesApplied,
+			RejectedBy:   triagemResult.RejectedBy,
+			Flags:        triagemResult.Flags,
+		}
+		if obito.Hospital != nil {
+			entry.HospitalNome = obito.Hospital.Nome
+		}
+		result.Results = append(result.Results, entry)
+
+		result.Summary.Total++
+		if triagemResult.Elegivel {
+			result.Summary.Elegiveis++
+		} else {
+			result.Summary.Inelegiveis++
+			for _, motivo := range triagemResult.Motivos {
+				result.Summary.Motivos[motivo]++
+			}
+		}
+	}
+
+	return result
+}
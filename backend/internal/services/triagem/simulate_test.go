@@ -0,0 +1,154 @@
+package triagem
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sidot/backend/internal/models"
+)
+
+// simulationTestObitos returns obitos covering each default rule, registered at createdAt
+func simulationTestObitos(createdAt time.Time) []models.ObitoSimulado {
+	uti := "UTI"
+	enfermaria := "Enfermaria"
+	hospital := &models.Hospital{ID: uuid.New(), Nome: "Hospital Teste"}
+
+	base := func(idade int, mortoHa time.Duration) models.ObitoSimulado {
+		dataObito := createdAt.Add(-mortoHa)
+		return models.ObitoSimulado{
+			ID:             uuid.New(),
+			HospitalID:     hospital.ID,
+			NomePaciente:   "Paciente Teste",
+			DataNascimento: dataObito.AddDate(-idade, 0, -1),
+			DataObito:      dataObito,
+			CausaMortis:    "Parada cardiaca",
+			Setor:          &uti,
+			CreatedAt:      createdAt,
+			Hospital:       hospital,
+		}
+	}
+
+	eligible := base(45, 30*time.Minute)
+	urgent := base(60, 4*time.Hour+30*time.Minute)
+	urgent.Setor = &enfermaria
+	tooOld := base(85, 30*time.Minute)
+	outsideWindow := base(40, 7*time.Hour)
+	unknown := base(50, 30*time.Minute)
+	unknown.IdentificacaoDesconhecida = true
+
+	return []models.ObitoSimulado{eligible, urgent, tooOld, outsideWindow, unknown}
+}
+
+// TestSimulateMatchesApplyRulesForDefaultRules tests that a dry-run gives the same results as the real engine
+func TestSimulateMatchesApplyRulesForDefaultRules(t *testing.T) {
+	motor := NewTriagemMotor(nil, nil)
+	defaultRules := motor.getDefaultRules()
+
+	// Serve the default rules from the cache so ApplyRules does not hit the database
+	motor.cachedRules = defaultRules
+	motor.rulesCacheTime = time.Now()
+
+	obitos := simulationTestObitos(time.Now())
+	simulation := motor.Simulate(obitos, defaultRules)
+
+	if len(simulation.Results) != len(obitos) {
+		t.Fatalf("Expected %d results, got %d", len(obitos), len(simulation.Results))
+	}
+
+	for i := range obitos {
+		expected, err := motor.ApplyRules(context.Background(), &obitos[i])
+		if err != nil {
+			t.Fatalf("ApplyRules returned error: %v", err)
+		}
+
+		got := simulation.Results[i]
+		if got.ObitoID != obitos[i].ID {
+			t.Errorf("Result %d: expected obito %s, got %s", i, obitos[i].ID, got.ObitoID)
+		}
+		if got.Elegivel != expected.Elegivel || got.Score != expected.Score {
+			t.Errorf("Result %d: expected elegivel=%v score=%d, got elegivel=%v score=%d",
+				i, expected.Elegivel, expected.Score, got.Elegivel, got.Score)
+		}
+		if !reflect.DeepEqual(got.Motivos, expected.Motivos) {
+			t.Errorf("Result %d: expected motivos %v, got %v", i, expected.Motivos, got.Motivos)
+		}
+		if !reflect.DeepEqual(got.RulesApplied, expected.RulesApplied) {
+			t.Errorf("Result %d: expected rules %v, got %v", i, expected.RulesApplied, got.RulesApplied)
+		}
+	}
+
+	summary := simulation.Summary
+	if summary.Total != 5 || summary.Elegiveis != 2 || summary.Inelegiveis != 3 {
+		t.Errorf("Unexpected summary counts: %+v", summary)
+	}
+	if summary.Motivos["Idade acima do limite"] != 1 || summary.Motivos["Fora da janela de captacao"] != 1 {
+		t.Errorf("Unexpected motivos summary: %v", summary.Motivos)
+	}
+}
+
+// TestSimulateDoesNotPersist tests that a dry-run leaves the motor state untouched
+// The motor has no database, so any attempt to create occurrences or history would fail
+func TestSimulateDoesNotPersist(t *testing.T) {
+	motor := NewTriagemMotor(nil, nil)
+	activeRules := motor.getDefaultRules()
+	motor.cachedRules = activeRules
+	cacheTime := time.Now()
+	motor.rulesCacheTime = cacheTime
+
+	// Candidate rule stricter than the active set
+	candidate := []models.TriagemRule{{
+		ID:     uuid.New(),
+		Nome:   "Idade Maxima 50",
+		Ativo:  true,
+		Regras: json.RawMessage(`{"tipo": "idade_maxima", "valor": 50, "acao": "rejeitar"}`),
+	}}
+
+	// Obitos registered ten days ago are evaluated as of registration, not now
+	obitos := simulationTestObitos(time.Now().AddDate(0, 0, -10))
+	simulation := motor.Simulate(obitos, candidate)
+
+	if simulation.Summary.Elegiveis != 3 || simulation.Summary.Inelegiveis != 2 {
+		t.Errorf("Expected 3 eligible and 2 ineligible with the candidate rule, got %+v", simulation.Summary)
+	}
+
+	stats := motor.GetStats()
+	for _, key := range []string{"total_processados", "total_elegiveis", "total_inelegiveis", "errors"} {
+		if stats[key].(int64) != 0 {
+			t.Errorf("Expected %s to remain 0 after simulation, got %v", key, stats[key])
+		}
+	}
+
+	if !reflect.DeepEqual(motor.cachedRules, activeRules) || !motor.rulesCacheTime.Equal(cacheTime) {
+		t.Error("Expected simulation not to replace the cached active rules")
+	}
+	for _, obito := range obitos {
+		if obito.Processado {
+			t.Error("Expected obitos not to be marked as processed")
+		}
+	}
+}
+
+// TestSimulateTriagemRulesInputToRules tests defaults and ordering of candidate rules
+func TestSimulateTriagemRulesInputToRules(t *testing.T) {
+	inactive := false
+	high := 90
+	input := models.SimulateTriagemRulesInput{
+		Rules: []models.CreateTriagemRuleInput{
+			{Nome: "B", Regras: json.RawMessage(`{}`)},
+			{Nome: "A", Regras: json.RawMessage(`{}`), Ativo: &inactive},
+			{Nome: "C", Regras: json.RawMessage(`{}`), Prioridade: &high},
+		},
+	}
+
+	rules := input.ToRules()
+	if rules[0].Nome != "C" || rules[1].Nome != "A" || rules[2].Nome != "B" {
+		t.Errorf("Expected order C, A, B, got %s, %s, %s", rules[0].Nome, rules[1].Nome, rules[2].Nome)
+	}
+	if rules[1].Ativo || !rules[2].Ativo || rules[2].Prioridade != 50 {
+		t.Errorf("Unexpected defaults: %+v", rules)
+	}
+}
@@ -0,0 +1,18 @@
+-- Migration: 031_add_notification_preferences_channels
+-- Description: Add push channel and quiet hours to user notification preferences
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE user_notification_preferences ADD COLUMN IF NOT EXISTS push_enabled BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE user_notification_preferences ADD COLUMN IF NOT EXISTS quiet_hours_start TIME;
+ALTER TABLE user_notification_preferences ADD COLUMN IF NOT EXISTS quiet_hours_end TIME;
+
+-- Comments
+COMMENT ON COLUMN user_notification_preferences.push_enabled IS 'Enable push notifications (default: true)';
+COMMENT ON COLUMN user_notification_preferences.quiet_hours_start IS 'Start of quiet hours (local time) - non-critical notifications are suppressed';
+COMMENT ON COLUMN user_notification_preferences.quiet_hours_end IS 'End of quiet hours (local time), may be earlier than start to span midnight';
+
+-- DOWN (for rollback)
+-- ALTER TABLE user_notification_preferences DROP COLUMN IF EXISTS quiet_hours_end;
+-- ALTER TABLE user_notification_preferences DROP COLUMN IF EXISTS quiet_hours_start;
+-- ALTER TABLE user_notification_preferences DROP COLUMN IF EXISTS push_enabled;
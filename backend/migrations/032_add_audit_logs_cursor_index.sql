@@ -0,0 +1,11 @@
+-- Migration: 032_add_audit_logs_cursor_index
+-- Description: Add (timestamp, id) index for cursor pagination of audit logs
+-- Created: 2026-10-14
+
+-- UP
+-- Keyset pagination orders by (timestamp DESC, id DESC) and seeks with (timestamp, id) < cursor
+CREATE INDEX IF NOT EXISTS idx_audit_logs_timestamp_id
+    ON audit_logs(timestamp DESC, id DESC);
+
+-- DOWN (for rollback)
+-- DROP INDEX IF EXISTS idx_audit_logs_timestamp_id;
@@ -0,0 +1,22 @@
+-- Migration: 033_create_tenant_theme_versions
+-- Description: Keep prior tenant theme configs so theme changes can be rolled back
+-- Created: 2026-10-14
+
+-- UP
+CREATE TABLE IF NOT EXISTS tenant_theme_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    theme_config JSONB NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_tenant_theme_versions_version UNIQUE (tenant_id, version)
+);
+
+-- Comments
+COMMENT ON TABLE tenant_theme_versions IS 'Prior theme_config values of each tenant, archived when the theme is changed';
+COMMENT ON COLUMN tenant_theme_versions.version IS 'Sequential version per tenant (1 = oldest archived config)';
+COMMENT ON COLUMN tenant_theme_versions.created_by IS 'User whose change archived this config';
+
+-- DOWN (for rollback)
+-- DROP TABLE IF EXISTS tenant_theme_versions;
@@ -0,0 +1,36 @@
+-- Migration: 034_add_source_template_to_triagem_rules
+-- Description: Track the template a triagem rule was cloned from, to report template usage and local changes
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE triagem_rules ADD COLUMN IF NOT EXISTS source_template_id UUID;
+ALTER TABLE triagem_rules ADD COLUMN IF NOT EXISTS source_template_checksum VARCHAR(64);
+
+ALTER TABLE triagem_rules
+    DROP CONSTRAINT IF EXISTS fk_triagem_rules_source_template_id;
+ALTER TABLE triagem_rules
+    ADD CONSTRAINT fk_triagem_rules_source_template_id
+    FOREIGN KEY (source_template_id) REFERENCES triagem_rule_templates(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_triagem_rules_source_template_id
+    ON triagem_rules(source_template_id)
+    WHERE source_template_id IS NOT NULL;
+
+-- Rules cloned before this migration were matched to templates by name; keep that link when the name is unambiguous
+-- (their checksum stays NULL, so whether they were modified is unknown)
+UPDATE triagem_rules tr
+SET source_template_id = t.id
+FROM triagem_rule_templates t
+WHERE tr.source_template_id IS NULL
+  AND tr.nome = t.nome
+  AND (SELECT COUNT(*) FROM triagem_rule_templates t2 WHERE t2.nome = t.nome) = 1;
+
+-- Comments
+COMMENT ON COLUMN triagem_rules.source_template_id IS 'Template this rule was cloned from (NULL for rules created directly)';
+COMMENT ON COLUMN triagem_rules.source_template_checksum IS 'SHA-256 of the rule fields at clone time, used to detect local modifications';
+
+-- DOWN (for rollback)
+-- DROP INDEX IF EXISTS idx_triagem_rules_source_template_id;
+-- ALTER TABLE triagem_rules DROP CONSTRAINT IF EXISTS fk_triagem_rules_source_template_id;
+-- ALTER TABLE triagem_rules DROP COLUMN IF EXISTS source_template_checksum;
+-- ALTER TABLE triagem_rules DROP COLUMN IF EXISTS source_template_id;
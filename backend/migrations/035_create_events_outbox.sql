@@ -0,0 +1,37 @@
+-- Migration: 035_create_events_outbox
+-- Description: Outbox of domain events written in the same transaction as the change, relayed to SSE/notifications at least once
+-- Created: 2026-10-14
+
+-- UP
+CREATE TABLE IF NOT EXISTS events_outbox (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_type VARCHAR(100) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    available_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP WITH TIME ZONE
+);
+
+-- The relay only scans unsent events that are due
+CREATE INDEX IF NOT EXISTS idx_events_outbox_pending
+    ON events_outbox(available_at, created_at)
+    WHERE sent_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_outbox_aggregate_id
+    ON events_outbox(aggregate_id);
+
+-- Comments
+COMMENT ON TABLE events_outbox IS 'Domain events awaiting delivery by the outbox relay (at-least-once)';
+COMMENT ON COLUMN events_outbox.event_type IS 'Event type, e.g. occurrence.created';
+COMMENT ON COLUMN events_outbox.aggregate_id IS 'ID of the entity the event is about';
+COMMENT ON COLUMN events_outbox.attempts IS 'Number of times the relay claimed the event';
+COMMENT ON COLUMN events_outbox.available_at IS 'When the event may be claimed: creation, end of a claim lease or next retry';
+COMMENT ON COLUMN events_outbox.sent_at IS 'When the event was delivered (NULL while pending)';
+
+-- DOWN (for rollback)
+-- DROP INDEX IF EXISTS idx_events_outbox_aggregate_id;
+-- DROP INDEX IF EXISTS idx_events_outbox_pending;
+-- DROP TABLE IF EXISTS events_outbox;
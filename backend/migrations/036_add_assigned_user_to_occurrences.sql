@@ -0,0 +1,24 @@
+-- Migration: 036_add_assigned_user_to_occurrences
+-- Description: Operator an occurrence is assigned to
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE occurrences ADD COLUMN IF NOT EXISTS assigned_user_id UUID;
+
+ALTER TABLE occurrences
+    DROP CONSTRAINT IF EXISTS fk_occurrences_assigned_user_id;
+ALTER TABLE occurrences
+    ADD CONSTRAINT fk_occurrences_assigned_user_id
+    FOREIGN KEY (assigned_user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_occurrences_assigned_user_id
+    ON occurrences(assigned_user_id)
+    WHERE assigned_user_id IS NOT NULL;
+
+-- Comments
+COMMENT ON COLUMN occurrences.assigned_user_id IS 'Operator responsible for the occurrence (NULL while unassigned)';
+
+-- DOWN (for rollback)
+-- DROP INDEX IF EXISTS idx_occurrences_assigned_user_id;
+-- ALTER TABLE occurrences DROP CONSTRAINT IF EXISTS fk_occurrences_assigned_user_id;
+-- ALTER TABLE occurrences DROP COLUMN IF EXISTS assigned_user_id;
@@ -0,0 +1,13 @@
+-- Migration: 037_add_expirada_occurrence_status
+-- Description: Terminal status for pending occurrences whose capture window expired
+-- Created: 2026-10-14
+
+-- UP
+ALTER TYPE occurrence_status ADD VALUE IF NOT EXISTS 'EXPIRADA';
+
+-- Comments
+COMMENT ON TYPE occurrence_status IS 'Occurrence status; EXPIRADA is set by the expiry job when a PENDENTE window ends';
+
+-- DOWN (for rollback)
+-- Postgres cannot drop enum values; move EXPIRADA rows back before recreating the type if needed:
+-- UPDATE occurrences SET status = 'CANCELADA' WHERE status = 'EXPIRADA';
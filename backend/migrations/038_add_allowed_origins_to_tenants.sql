@@ -0,0 +1,12 @@
+-- Migration: 038_add_allowed_origins_to_tenants
+-- Description: CORS origins of each tenant dashboard domain, allowed besides the global CORS_ORIGINS
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS allowed_origins TEXT[] NOT NULL DEFAULT '{}';
+
+-- Comments
+COMMENT ON COLUMN tenants.allowed_origins IS 'CORS origins (scheme://host[:port]) allowed for requests to the tenant slug subdomain';
+
+-- DOWN (for rollback)
+-- ALTER TABLE tenants DROP COLUMN IF EXISTS allowed_origins;
@@ -0,0 +1,17 @@
+-- Migration: 039_add_locale_to_users
+-- Description: Locale of the messages (notification emails) sent to each user
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS locale VARCHAR(10) NOT NULL DEFAULT 'pt-BR';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_locale_check;
+ALTER TABLE users ADD CONSTRAINT users_locale_check CHECK (locale IN ('pt-BR', 'en'));
+
+-- Comments
+COMMENT ON COLUMN users.locale IS 'Idioma das notificacoes por email (pt-BR ou en)';
+
+-- DOWN (for rollback)
+-- ALTER TABLE users DROP CONSTRAINT IF EXISTS users_locale_check;
+-- ALTER TABLE users DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,34 @@
+-- Migration: 040_create_email_deliveries
+-- Description: Delivery status of each queued email (queued, sent, failed), updated by the email queue worker
+-- Created: 2026-10-14
+
+-- UP
+CREATE TABLE IF NOT EXISTS email_deliveries (
+    id UUID PRIMARY KEY,
+    occurrence_id UUID REFERENCES occurrences(id) ON DELETE SET NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    recipient VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    next_retry_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Admins list the most recent deliveries, usually filtered by status
+CREATE INDEX IF NOT EXISTS idx_email_deliveries_status_updated_at
+    ON email_deliveries(status, updated_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_email_deliveries_occurrence_id
+    ON email_deliveries(occurrence_id);
+
+-- Comments
+COMMENT ON TABLE email_deliveries IS 'Audit of queued emails: one row per email, updated on every send attempt';
+COMMENT ON COLUMN email_deliveries.id IS 'ID of the email queue item';
+COMMENT ON COLUMN email_deliveries.status IS 'queued (waiting), sent, or failed (last attempt failed; retried while next_retry_at is set)';
+COMMENT ON COLUMN email_deliveries.attempts IS 'Number of send attempts';
+
+-- DOWN (for rollback)
+-- DROP TABLE IF EXISTS email_deliveries;
@@ -0,0 +1,43 @@
+-- Migration: 041_create_audit_log_archives
+-- Description: Archives of audit log entries past retention, and deletion of archived entries
+-- Created: 2026-10-14
+
+-- UP
+CREATE TABLE IF NOT EXISTS audit_log_archives (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    location TEXT NOT NULL,
+    entries INTEGER NOT NULL,
+    from_timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    to_timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_archives_created_at ON audit_log_archives(created_at DESC);
+
+-- audit_logs stays immutable: only a transaction that set sidot.audit_archive (the retention
+-- job, after exporting the entries) may delete from it
+CREATE OR REPLACE FUNCTION prevent_audit_log_delete()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF current_setting('sidot.audit_archive', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'DELETE operations are not allowed on audit_logs table';
+END;
+$$ LANGUAGE plpgsql;
+
+-- Comments
+COMMENT ON TABLE audit_log_archives IS 'Exportacoes (JSON gzip) dos logs de auditoria removidos apos o periodo de retencao';
+COMMENT ON COLUMN audit_log_archives.location IS 'Local do arquivo exportado no storage configurado';
+COMMENT ON COLUMN audit_log_archives.entries IS 'Quantidade de registros exportados';
+COMMENT ON COLUMN audit_log_archives.from_timestamp IS 'Timestamp do registro mais antigo exportado';
+COMMENT ON COLUMN audit_log_archives.to_timestamp IS 'Timestamp do registro mais recente exportado';
+
+-- DOWN (for rollback)
+-- CREATE OR REPLACE FUNCTION prevent_audit_log_delete()
+-- RETURNS TRIGGER AS $$
+-- BEGIN
+--     RAISE EXCEPTION 'DELETE operations are not allowed on audit_logs table';
+-- END;
+-- $$ LANGUAGE plpgsql;
+-- DROP TABLE IF EXISTS audit_log_archives;
@@ -0,0 +1,24 @@
+-- Migration: 042_create_shift_templates
+-- Description: Reusable weekly shift rotations that expand into the shifts of a hospital
+-- Created: 2026-10-14
+
+-- UP
+CREATE TABLE IF NOT EXISTS shift_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID REFERENCES tenants(id) ON DELETE CASCADE,
+    nome VARCHAR(255) NOT NULL,
+    descricao TEXT,
+    slots JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_shift_templates_tenant ON shift_templates(tenant_id);
+
+-- Comments
+COMMENT ON TABLE shift_templates IS 'Modelos de escala semanal (ex.: plantao 12h diurno/noturno) aplicados aos hospitais';
+COMMENT ON COLUMN shift_templates.tenant_id IS 'Tenant dono do modelo; NULL para modelos disponiveis a todos os tenants';
+COMMENT ON COLUMN shift_templates.slots IS 'Turnos do modelo: [{start_time, end_time, days}], sem days o turno se repete nos sete dias';
+
+-- DOWN (for rollback)
+-- DROP TABLE IF EXISTS shift_templates;
@@ -0,0 +1,12 @@
+-- Migration: 043_add_timezone_to_hospitals
+-- Description: Timezone of each hospital, used to evaluate its shifts in local time
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE hospitals ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
+
+-- Comments
+COMMENT ON COLUMN hospitals.timezone IS 'Fuso horario IANA do hospital (ex.: America/Manaus); NULL usa SYSTEM_TIMEZONE';
+
+-- DOWN (for rollback)
+-- ALTER TABLE hospitals DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,23 @@
+-- Migration: 044_create_user_list_presets
+-- Description: Named filter and sort presets of the lists, saved by each user
+-- Created: 2026-10-14
+
+-- UP
+CREATE TABLE IF NOT EXISTS user_list_presets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    list VARCHAR(50) NOT NULL,
+    nome VARCHAR(100) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT user_list_presets_unique_name UNIQUE (user_id, list, nome)
+);
+
+-- Comments
+COMMENT ON TABLE user_list_presets IS 'Filtros e ordenacao salvos por usuario para as listagens (ex.: ocorrencias)';
+COMMENT ON COLUMN user_list_presets.list IS 'Listagem do preset (occurrences)';
+COMMENT ON COLUMN user_list_presets.params IS 'Parametros de consulta da listagem: {status, hospital_id, sort, ...}';
+
+-- DOWN (for rollback)
+-- DROP TABLE IF EXISTS user_list_presets;
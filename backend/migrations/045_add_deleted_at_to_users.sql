@@ -0,0 +1,15 @@
+-- Migration: 045_add_deleted_at_to_users
+-- Description: Soft delete of users, keeping the rows referenced by audit logs and occurrences
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_users_tenant_not_deleted ON users(tenant_id) WHERE deleted_at IS NULL;
+
+-- Comments
+COMMENT ON COLUMN users.deleted_at IS 'Soft delete - data de exclusao logica; o usuario sai das listagens mas continua nas referencias de auditoria e ocorrencias';
+
+-- DOWN (for rollback)
+-- DROP INDEX IF EXISTS idx_users_tenant_not_deleted;
+-- ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,21 @@
+-- Migration: 046_scope_user_email_per_tenant
+-- Description: Email uniqueness per tenant, so the same person can have an account in several tenants
+-- Created: 2026-10-14
+
+-- UP
+DROP INDEX IF EXISTS idx_users_email;
+
+-- Accounts of a tenant: one per email in the tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tenant_email ON users(tenant_id, email) WHERE tenant_id IS NOT NULL;
+
+-- Global accounts (no tenant): one per email; the application also keeps them apart from tenant accounts
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_global_email ON users(email) WHERE tenant_id IS NULL;
+
+-- Lookups by email at login
+CREATE INDEX IF NOT EXISTS idx_users_email_lookup ON users(email);
+
+-- DOWN (for rollback)
+-- DROP INDEX IF EXISTS idx_users_email_lookup;
+-- DROP INDEX IF EXISTS idx_users_global_email;
+-- DROP INDEX IF EXISTS idx_users_tenant_email;
+-- CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(email);
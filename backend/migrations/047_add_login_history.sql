@@ -0,0 +1,24 @@
+-- Migration: 047_add_login_history
+-- Description: Last login of the users and history of the successful logins, for security review
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS login_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    logged_in_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_history_user ON login_history (user_id, logged_in_at DESC);
+
+-- Comments
+COMMENT ON COLUMN users.last_login_at IS 'Data e hora do ultimo login bem-sucedido';
+COMMENT ON TABLE login_history IS 'Historico de logins bem-sucedidos dos usuarios (IP e user agent)';
+
+-- DOWN (for rollback)
+-- DROP TABLE IF EXISTS login_history;
+-- ALTER TABLE users DROP COLUMN IF EXISTS last_login_at;
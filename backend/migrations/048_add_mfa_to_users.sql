@@ -0,0 +1,15 @@
+-- Migration: 048_add_mfa_to_users
+-- Description: TOTP secret (encrypted) and MFA state of the users
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_secret TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_enabled BOOLEAN NOT NULL DEFAULT false;
+
+-- Comments
+COMMENT ON COLUMN users.mfa_secret IS 'Segredo TOTP criptografado com a chave de criptografia (EncryptionService); NULL sem cadastro de MFA';
+COMMENT ON COLUMN users.mfa_enabled IS 'MFA confirmado com um codigo valido; o login exige o codigo TOTP';
+
+-- DOWN (for rollback)
+-- ALTER TABLE users DROP COLUMN IF EXISTS mfa_enabled;
+-- ALTER TABLE users DROP COLUMN IF EXISTS mfa_secret;
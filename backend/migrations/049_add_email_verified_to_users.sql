@@ -0,0 +1,13 @@
+-- Migration: 049_add_email_verified_to_users
+-- Description: Email verification state of the users
+-- Created: 2026-10-14
+
+-- UP
+-- Users created before the verification flow are considered verified
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT true;
+
+-- Comments
+COMMENT ON COLUMN users.email_verified IS 'Email confirmado pelo link de verificacao; false bloqueia o login apos o periodo de carencia quando o setting email_verification esta ativo';
+
+-- DOWN (for rollback)
+-- ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
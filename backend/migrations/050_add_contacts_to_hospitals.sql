@@ -0,0 +1,15 @@
+-- Migration: 050_add_contacts_to_hospitals
+-- Description: Contact and escalation emails of the hospitals
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE hospitals ADD COLUMN IF NOT EXISTS email VARCHAR(255);
+ALTER TABLE hospitals ADD COLUMN IF NOT EXISTS escalation_email VARCHAR(255);
+
+-- Comments
+COMMENT ON COLUMN hospitals.email IS 'Email de contato do hospital';
+COMMENT ON COLUMN hospitals.escalation_email IS 'Email que recebe as escalacoes de SLA das ocorrencias do hospital, junto com os gestores';
+
+-- DOWN (for rollback)
+-- ALTER TABLE hospitals DROP COLUMN IF EXISTS escalation_email;
+-- ALTER TABLE hospitals DROP COLUMN IF EXISTS email;
@@ -0,0 +1,25 @@
+-- Migration: 051_create_obito_ineligibilities
+-- Description: Ineligibility records of the triagem (reasons and evaluated rules), for audit and the cause of death report
+-- Created: 2026-10-14
+
+-- UP
+CREATE TABLE IF NOT EXISTS obito_ineligibilities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    obito_id UUID NOT NULL REFERENCES obitos_simulados(id) ON DELETE CASCADE,
+    hospital_id UUID NOT NULL REFERENCES hospitals(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    motivos TEXT[] NOT NULL,
+    regras_avaliadas TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_obito_ineligibilities_tenant ON obito_ineligibilities (tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_obito_ineligibilities_obito ON obito_ineligibilities (obito_id, created_at DESC);
+
+-- Comments
+COMMENT ON TABLE obito_ineligibilities IS 'Obitos considerados inelegiveis pela triagem, para auditoria e ajuste das regras';
+COMMENT ON COLUMN obito_ineligibilities.motivos IS 'Motivos de inelegibilidade retornados pelas regras';
+COMMENT ON COLUMN obito_ineligibilities.regras_avaliadas IS 'Nomes das regras ativas avaliadas na triagem';
+
+-- DOWN (for rollback)
+-- DROP TABLE IF EXISTS obito_ineligibilities;
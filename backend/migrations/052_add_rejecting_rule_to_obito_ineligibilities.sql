@@ -0,0 +1,12 @@
+-- Migration: 052_add_rejecting_rule_to_obito_ineligibilities
+-- Description: Rule that rejected each ineligible obito, now that the rules are evaluated by priority
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE obito_ineligibilities ADD COLUMN IF NOT EXISTS regra_rejeicao VARCHAR(255);
+
+-- Comments
+COMMENT ON COLUMN obito_ineligibilities.regra_rejeicao IS 'Primeira regra (por prioridade) que considerou o obito inelegivel';
+
+-- DOWN (for rollback)
+-- ALTER TABLE obito_ineligibilities DROP COLUMN IF EXISTS regra_rejeicao;
@@ -0,0 +1,12 @@
+-- Migration: 053_add_flags_to_occurrences
+-- Description: Warnings attached to an occurrence by the triagem rules with the "flag" action
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE occurrences ADD COLUMN IF NOT EXISTS flags TEXT[] NOT NULL DEFAULT '{}';
+
+-- Comments
+COMMENT ON COLUMN occurrences.flags IS 'Alertas das regras de triagem com acao flag (ex: idade limitrofe), exibidos aos operadores';
+
+-- DOWN (for rollback)
+-- ALTER TABLE occurrences DROP COLUMN IF EXISTS flags;
@@ -0,0 +1,112 @@
+-- Migration: 054_restrict_audit_log_deletion
+-- Description: Audit log deletion only through SECURITY DEFINER functions owned by a dedicated role
+-- Created: 2026-10-14
+
+-- UP
+-- The sidot.audit_archive setting of 041 could be set by any session, so it is replaced by a NOLOGIN
+-- role: the delete trigger lets through only statements running as that role, which happens inside
+-- the functions below and nowhere else
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'sidot_audit_archiver') THEN
+        CREATE ROLE sidot_audit_archiver NOLOGIN;
+    END IF;
+END
+$$;
+
+-- The migration role is a member of the archiver role only while it hands the functions over to it;
+-- the membership is revoked at the end, so no login role can SET ROLE to the archiver afterwards
+GRANT sidot_audit_archiver TO CURRENT_USER;
+GRANT USAGE, CREATE ON SCHEMA public TO sidot_audit_archiver;
+
+GRANT SELECT, DELETE ON audit_logs TO sidot_audit_archiver;
+GRANT SELECT (id, is_active) ON tenants TO sidot_audit_archiver;
+GRANT SELECT (id, tenant_id, is_super_admin) ON users TO sidot_audit_archiver;
+GRANT SELECT (id, tenant_id) ON hospitals TO sidot_audit_archiver;
+
+CREATE OR REPLACE FUNCTION prevent_audit_log_delete()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF current_user = 'sidot_audit_archiver' THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'DELETE operations are not allowed on audit_logs table';
+END;
+$$ LANGUAGE plpgsql;
+
+-- Deletes the audit log entries exported by the retention job that are older than the retention cutoff,
+-- returning how many were deleted. A cutoff in the future is refused, so recent entries cannot be deleted.
+CREATE OR REPLACE FUNCTION archive_audit_logs(log_ids UUID[], cutoff TIMESTAMPTZ)
+RETURNS BIGINT
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public, pg_temp
+AS $$
+DECLARE
+    deleted BIGINT;
+BEGIN
+    IF cutoff > now() THEN
+        RAISE EXCEPTION 'audit logs can only be archived before a cutoff in the past';
+    END IF;
+
+    DELETE FROM audit_logs WHERE id = ANY(log_ids) AND timestamp < cutoff;
+    GET DIAGNOSTICS deleted = ROW_COUNT;
+    RETURN deleted;
+END;
+$$;
+
+-- Deletes the audit log entries of an inactive tenant, and those referencing its users (super admins
+-- excluded) or hospitals, for the tenant purge. Active tenants are refused.
+CREATE OR REPLACE FUNCTION purge_tenant_audit_logs(purged_tenant_id UUID)
+RETURNS BIGINT
+LANGUAGE plpgsql
+SECURITY DEFINER
+SET search_path = public, pg_temp
+AS $$
+DECLARE
+    deleted BIGINT;
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM tenants WHERE id = purged_tenant_id AND is_active = false) THEN
+        RAISE EXCEPTION 'audit logs can only be purged for an inactive tenant';
+    END IF;
+
+    DELETE FROM audit_logs
+    WHERE tenant_id = purged_tenant_id
+       OR usuario_id IN (SELECT id FROM users WHERE tenant_id = purged_tenant_id AND is_super_admin = false)
+       OR hospital_id IN (SELECT id FROM hospitals WHERE tenant_id = purged_tenant_id);
+    GET DIAGNOSTICS deleted = ROW_COUNT;
+    RETURN deleted;
+END;
+$$;
+
+ALTER FUNCTION archive_audit_logs(UUID[], TIMESTAMPTZ) OWNER TO sidot_audit_archiver;
+ALTER FUNCTION purge_tenant_audit_logs(UUID) OWNER TO sidot_audit_archiver;
+REVOKE ALL ON FUNCTION archive_audit_logs(UUID[], TIMESTAMPTZ) FROM PUBLIC;
+REVOKE ALL ON FUNCTION purge_tenant_audit_logs(UUID) FROM PUBLIC;
+GRANT EXECUTE ON FUNCTION archive_audit_logs(UUID[], TIMESTAMPTZ) TO CURRENT_USER;
+GRANT EXECUTE ON FUNCTION purge_tenant_audit_logs(UUID) TO CURRENT_USER;
+
+-- The functions stay owned by the archiver role; its membership and schema CREATE were only needed for the hand-over
+REVOKE CREATE ON SCHEMA public FROM sidot_audit_archiver;
+REVOKE sidot_audit_archiver FROM CURRENT_USER;
+
+-- Comments
+COMMENT ON FUNCTION archive_audit_logs(UUID[], TIMESTAMPTZ) IS 'Remove os logs de auditoria ja exportados pelo job de retencao e anteriores ao corte informado';
+COMMENT ON FUNCTION purge_tenant_audit_logs(UUID) IS 'Remove os logs de auditoria de um tenant inativo na remocao definitiva dos seus dados';
+
+-- DOWN (for rollback)
+-- GRANT sidot_audit_archiver TO CURRENT_USER;
+-- DROP FUNCTION IF EXISTS purge_tenant_audit_logs(UUID);
+-- DROP FUNCTION IF EXISTS archive_audit_logs(UUID[], TIMESTAMPTZ);
+-- CREATE OR REPLACE FUNCTION prevent_audit_log_delete()
+-- RETURNS TRIGGER AS $$
+-- BEGIN
+--     IF current_setting('sidot.audit_archive', true) = 'on' THEN
+--         RETURN OLD;
+--     END IF;
+--     RAISE EXCEPTION 'DELETE operations are not allowed on audit_logs table';
+-- END;
+-- $$ LANGUAGE plpgsql;
+-- REVOKE ALL ON audit_logs, tenants, users, hospitals FROM sidot_audit_archiver;
+-- REVOKE ALL ON SCHEMA public FROM sidot_audit_archiver;
+-- DROP ROLE IF EXISTS sidot_audit_archiver;
@@ -0,0 +1,25 @@
+-- Migration: 055_add_dead_letter_to_events_outbox
+-- Description: Dead-letter state for outbox events that exhausted their delivery attempts
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE events_outbox ADD COLUMN IF NOT EXISTS dead_at TIMESTAMP WITH TIME ZONE;
+
+-- Dead events are no longer scanned by the relay
+DROP INDEX IF EXISTS idx_events_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_events_outbox_pending
+    ON events_outbox(available_at, created_at)
+    WHERE sent_at IS NULL AND dead_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_outbox_dead_at
+    ON events_outbox(dead_at)
+    WHERE dead_at IS NOT NULL;
+
+-- Comments
+COMMENT ON COLUMN events_outbox.dead_at IS 'When the event was moved to dead-letter after exhausting its attempts (NULL while deliverable)';
+
+-- DOWN (for rollback)
+-- DROP INDEX IF EXISTS idx_events_outbox_dead_at;
+-- DROP INDEX IF EXISTS idx_events_outbox_pending;
+-- CREATE INDEX IF NOT EXISTS idx_events_outbox_pending ON events_outbox(available_at, created_at) WHERE sent_at IS NULL;
+-- ALTER TABLE events_outbox DROP COLUMN IF EXISTS dead_at;
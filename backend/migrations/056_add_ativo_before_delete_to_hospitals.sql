@@ -0,0 +1,12 @@
+-- Migration: 056_add_ativo_before_delete_to_hospitals
+-- Description: Keep the ativo flag a hospital had before it was soft-deleted so a restore brings it back
+-- Created: 2026-10-14
+
+-- UP
+ALTER TABLE hospitals ADD COLUMN IF NOT EXISTS ativo_before_delete BOOLEAN;
+
+-- Comments
+COMMENT ON COLUMN hospitals.ativo_before_delete IS 'Value of ativo when the hospital was soft-deleted (NULL while not deleted)';
+
+-- DOWN (for rollback)
+-- ALTER TABLE hospitals DROP COLUMN IF EXISTS ativo_before_delete;
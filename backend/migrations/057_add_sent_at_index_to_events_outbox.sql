@@ -0,0 +1,11 @@
+-- Migration: 057_add_sent_at_index_to_events_outbox
+-- Description: Index the sent events so the relay can delete the ones past OUTBOX_SENT_RETENTION
+-- Created: 2026-10-14
+
+-- UP
+CREATE INDEX IF NOT EXISTS idx_events_outbox_sent_at
+    ON events_outbox(sent_at)
+    WHERE sent_at IS NOT NULL;
+
+-- DOWN (for rollback)
+-- DROP INDEX IF EXISTS idx_events_outbox_sent_at;
@@ -0,0 +1,250 @@
+// Package pepmapping defines the field mapping of PEP agents (the "mapping" section of
+// mapping.yaml) and validates it against the rules of the SIDOT central server.
+// It is shared by the backend and the pep-agent module, so both apply the same rules.
+package pepmapping
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WatermarkPlaceholder is replaced by the last processed value in a custom query
+const WatermarkPlaceholder = "{{WATERMARK}}"
+
+// CPF masking modes
+const (
+	MaskPartial = "partial" // keep the last 2 digits visible (default)
+	MaskFull    = "full"    // mask every digit
+)
+
+// Column types that can be declared in field_types
+const (
+	TypeText      = "text"
+	TypeInteger   = "integer"
+	TypeDate      = "date"
+	TypeTimestamp = "timestamp"
+)
+
+// Mapping defines the field mapping from PEP to SIDOT
+type Mapping struct {
+	SourceTable  string       `yaml:"source_table" json:"source_table"` // e.g., "TASY.TB_PACIENTE_OBITO"
+	Fields       FieldMapping `yaml:"fields" json:"fields"`
+	FilterColumn string       `yaml:"filter_column" json:"filter_column"` // column for watermark filtering
+	CustomQuery  string       `yaml:"custom_query" json:"custom_query"`   // optional: override auto-generated query
+
+	// FieldTypes optionally declares the column type of SIDOT fields (text, integer, date, timestamp)
+	FieldTypes map[string]string `yaml:"field_types" json:"field_types,omitempty"`
+	Masking    MaskingConfig     `yaml:"masking" json:"masking"`
+}
+
+// FieldMapping maps PEP database columns to SIDOT standard fields
+type FieldMapping struct {
+	// Required fields
+	ID             string `yaml:"id" json:"id"` // unique identifier in source
+	NomePaciente   string `yaml:"nome_paciente" json:"nome_paciente"`
+	DataObito      string `yaml:"data_obito" json:"data_obito"`
+	CausaMortis    string `yaml:"causa_mortis" json:"causa_mortis"`
+	DataNascimento string `yaml:"data_nascimento" json:"data_nascimento"` // or use idade
+	Idade          string `yaml:"idade" json:"idade"`                     // alternative to data_nascimento
+
+	// Patient identification (at least one required)
+	CNS string `yaml:"cns" json:"cns"` // Cartao Nacional de Saude
+	CPF string `yaml:"cpf" json:"cpf"` // CPF (will be masked)
+
+	// Optional fields
+	Setor                     string `yaml:"setor" json:"setor"`
+	Leito                     string `yaml:"leito" json:"leito"`
+	Prontuario                string `yaml:"prontuario" json:"prontuario"`
+	IdentificacaoDesconhecida string `yaml:"identificacao_desconhecida" json:"identificacao_desconhecida"` // 'S' or 'N'
+}
+
+// MaskingConfig defines how sensitive fields are masked before leaving the hospital
+type MaskingConfig struct {
+	CPF string `yaml:"cpf" json:"cpf"` // partial (default) or full
+}
+
+// columns returns the mapped column of each SIDOT field, keyed by its YAML name
+func (f FieldMapping) columns() map[string]string {
+	return map[string]string{
+		"id":                         f.ID,
+		"nome_paciente":              f.NomePaciente,
+		"data_obito":                 f.DataObito,
+		"causa_mortis":               f.CausaMortis,
+		"data_nascimento":            f.DataNascimento,
+		"idade":                      f.Idade,
+		"cns":                        f.CNS,
+		"cpf":                        f.CPF,
+		"setor":                      f.Setor,
+		"leito":                      f.Leito,
+		"prontuario":                 f.Prontuario,
+		"identificacao_desconhecida": f.IdentificacaoDesconhecida,
+	}
+}
+
+// allowedTypes lists the column types the agent can scan into each SIDOT field
+var allowedTypes = map[string][]string{
+	"id":                         {TypeText, TypeInteger},
+	"nome_paciente":              {TypeText},
+	"data_obito":                 {TypeTimestamp, TypeDate},
+	"causa_mortis":               {TypeText},
+	"data_nascimento":            {TypeDate, TypeTimestamp},
+	"idade":                      {TypeInteger},
+	"cns":                        {TypeText, TypeInteger},
+	"cpf":                        {TypeText, TypeInteger},
+	"setor":                      {TypeText, TypeInteger},
+	"leito":                      {TypeText, TypeInteger},
+	"prontuario":                 {TypeText, TypeInteger},
+	"identificacao_desconhecida": {TypeText},
+}
+
+// Severity of a validation issue
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // the agent refuses to start
+	SeverityWarning Severity = "warning" // the agent starts, but the mapping should be reviewed
+)
+
+// Issue is a problem found in a mapping
+type Issue struct {
+	Field    string   `json:"field"` // path inside the mapping section, e.g. "fields.data_obito"
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	return i.Field + ": " + i.Message
+}
+
+// Parse decodes a mapping from YAML or JSON (JSON is valid YAML). It accepts either a full
+// mapping.yaml, reading its "mapping" section, or the mapping section alone.
+func Parse(data []byte) (*Mapping, error) {
+	var file struct {
+		Mapping *Mapping `yaml:"mapping"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping: %w", err)
+	}
+	if file.Mapping != nil {
+		return file.Mapping, nil
+	}
+
+	var mapping Mapping
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping: %w", err)
+	}
+	return &mapping, nil
+}
+
+// Validate checks a mapping against the server rules and returns every problem found,
+// errors first. An empty result means the mapping is valid.
+func Validate(m Mapping) []Issue {
+	var errs, warnings []Issue
+	addError := func(field, format string, args ...interface{}) {
+		errs = append(errs, Issue{Field: field, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+	}
+	addWarning := func(field, format string, args ...interface{}) {
+		warnings = append(warnings, Issue{Field: field, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Source
+	if m.SourceTable == "" && m.CustomQuery == "" {
+		addError("source_table", "source_table or custom_query is required")
+	}
+	if m.CustomQuery != "" && !strings.Contains(m.CustomQuery, WatermarkPlaceholder) {
+		addWarning("custom_query", "custom_query has no %s placeholder, every poll reads all records again", WatermarkPlaceholder)
+	}
+
+	// Required target fields
+	if m.Fields.ID == "" {
+		addError("fields.id", "id is required (unique identifier of the record)")
+	}
+	if m.Fields.NomePaciente == "" {
+		addError("fields.nome_paciente", "nome_paciente is required (patient name)")
+	}
+	if m.Fields.DataObito == "" {
+		addError("fields.data_obito", "data_obito is required (date and time of death)")
+	}
+	if m.Fields.CausaMortis == "" {
+		addError("fields.causa_mortis", "causa_mortis is required (cause of death)")
+	}
+	if m.Fields.DataNascimento == "" && m.Fields.Idade == "" {
+		addError("fields.data_nascimento", "data_nascimento or idade is required")
+	}
+	if m.Fields.CNS == "" && m.Fields.CPF == "" {
+		addWarning("fields.cns", "neither cns nor cpf is mapped, the Central de Transplantes cannot identify the patient")
+	}
+	if m.FilterColumn != "" && m.Fields.DataObito != "" && m.FilterColumn != m.Fields.DataObito {
+		addWarning("filter_column", "filter_column differs from data_obito, records are ordered by the death date")
+	}
+
+	// Field types
+	columns := m.Fields.columns()
+	for _, field := range sortedKeys(m.FieldTypes) {
+		path := "field_types." + field
+		typ := strings.ToLower(strings.TrimSpace(m.FieldTypes[field]))
+		allowed, known := allowedTypes[field]
+		switch {
+		case !known:
+			addError(path, "unknown field %q", field)
+		case !contains([]string{TypeText, TypeInteger, TypeDate, TypeTimestamp}, typ):
+			addError(path, "unknown type %q (use text, integer, date or timestamp)", m.FieldTypes[field])
+		case !contains(allowed, typ):
+			addError(path, "%s cannot be %s (use %s)", field, typ, strings.Join(allowed, " or "))
+		case columns[field] == "":
+			addWarning(path, "type declared for %s, which is not mapped", field)
+		}
+	}
+
+	// Masking
+	switch strings.ToLower(strings.TrimSpace(m.Masking.CPF)) {
+	case "", MaskPartial, MaskFull:
+	case "none", "disabled", "off", "false":
+		addError("masking.cpf", "cpf masking cannot be disabled, the CPF must be masked before leaving the hospital (LGPD)")
+	default:
+		addError("masking.cpf", "unknown cpf masking %q (use partial or full)", m.Masking.CPF)
+	}
+
+	return append(errs, warnings...)
+}
+
+// Errors returns the issues with error severity
+func Errors(issues []Issue) []Issue {
+	return filter(issues, SeverityError)
+}
+
+// Warnings returns the issues with warning severity
+func Warnings(issues []Issue) []Issue {
+	return filter(issues, SeverityWarning)
+}
+
+func filter(issues []Issue, severity Severity) []Issue {
+	filtered := []Issue{}
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package pepmapping
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validMappingYAML = `
+mapping:
+  source_table: TASY.TB_PACIENTE_OBITO
+  filter_column: DT_OBITO
+  fields:
+    id: CD_PACIENTE_OBITO
+    nome_paciente: NM_PACIENTE
+    data_obito: DT_OBITO
+    causa_mortis: DS_CAUSA_MORTIS
+    data_nascimento: DT_NASCIMENTO
+    cns: NR_CNS
+    cpf: NR_CPF
+  field_types:
+    data_obito: timestamp
+    data_nascimento: date
+  masking:
+    cpf: partial
+`
+
+func fields(issues []Issue) []string {
+	names := []string{}
+	for _, issue := range issues {
+		names = append(names, issue.Field)
+	}
+	return names
+}
+
+func TestParse(t *testing.T) {
+	t.Run("full mapping.yaml", func(t *testing.T) {
+		mapping, err := Parse([]byte(validMappingYAML))
+		require.NoError(t, err)
+		assert.Equal(t, "TASY.TB_PACIENTE_OBITO", mapping.SourceTable)
+		assert.Equal(t, "DT_OBITO", mapping.Fields.DataObito)
+		assert.Equal(t, "timestamp", mapping.FieldTypes["data_obito"])
+	})
+
+	t.Run("mapping section as JSON", func(t *testing.T) {
+		mapping, err := Parse([]byte(`{"source_table":"OBITOS","fields":{"id":"ID","data_obito":"DT"}}`))
+		require.NoError(t, err)
+		assert.Equal(t, "OBITOS", mapping.SourceTable)
+		assert.Equal(t, "DT", mapping.Fields.DataObito)
+	})
+
+	t.Run("invalid YAML", func(t *testing.T) {
+		_, err := Parse([]byte("mapping: [unclosed"))
+		assert.Error(t, err)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	valid, err := Parse([]byte(validMappingYAML))
+	require.NoError(t, err)
+
+	t.Run("valid mapping has no issues", func(t *testing.T) {
+		assert.Empty(t, Validate(*valid))
+	})
+
+	t.Run("missing death date is an error", func(t *testing.T) {
+		mapping := *valid
+		mapping.Fields.DataObito = ""
+		mapping.FilterColumn = ""
+		mapping.FieldTypes = nil
+
+		issues := Validate(mapping)
+		errs := Errors(issues)
+		require.Len(t, errs, 1)
+		assert.Equal(t, "fields.data_obito", errs[0].Field)
+		assert.Equal(t, SeverityError, errs[0].Severity)
+		assert.Contains(t, errs[0].Message, "data_obito is required")
+	})
+
+	t.Run("reports every problem", func(t *testing.T) {
+		issues := Validate(Mapping{Masking: MaskingConfig{CPF: "none"}})
+
+		assert.Equal(t, []string{
+			"source_table", "fields.id", "fields.nome_paciente", "fields.data_obito",
+			"fields.causa_mortis", "fields.data_nascimento", "masking.cpf", "fields.cns",
+		}, fields(issues), "errors come before warnings")
+		assert.Equal(t, []string{"fields.cns"}, fields(Warnings(issues)))
+	})
+
+	t.Run("field types", func(t *testing.T) {
+		mapping := *valid
+		mapping.FieldTypes = map[string]string{
+			"data_obito": "text",
+			"idade":      "integer",
+			"leito":      "blob",
+			"peso":       "integer",
+		}
+
+		issues := Validate(mapping)
+		assert.Equal(t, []string{"field_types.data_obito", "field_types.leito", "field_types.peso"}, fields(Errors(issues)))
+		assert.Equal(t, []string{"field_types.idade"}, fields(Warnings(issues)), "type of an unmapped field")
+	})
+
+	t.Run("masking", func(t *testing.T) {
+		mapping := *valid
+		mapping.Masking.CPF = MaskFull
+		assert.Empty(t, Validate(mapping))
+
+		mapping.Masking.CPF = "hash"
+		assert.Equal(t, []string{"masking.cpf"}, fields(Errors(Validate(mapping))))
+	})
+
+	t.Run("custom query without watermark is a warning", func(t *testing.T) {
+		mapping := *valid
+		mapping.CustomQuery = "SELECT * FROM OBITOS"
+
+		issues := Validate(mapping)
+		assert.Empty(t, Errors(issues))
+		assert.Equal(t, []string{"custom_query"}, fields(Warnings(issues)))
+	})
+}
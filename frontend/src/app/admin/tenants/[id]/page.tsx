@@ -98,6 +98,7 @@ export default function TenantEditorPage() {
   // Form state for details tab
   const [formName, setFormName] = useState('');
   const [formSlug, setFormSlug] = useState('');
+  const [formOrigins, setFormOrigins] = useState('');
 
   // File input refs for asset uploads
   const logoInputRef = useRef<HTMLInputElement>(null);
@@ -110,6 +111,7 @@ export default function TenantEditorPage() {
       setTenant(data);
       setFormName(data.name);
       setFormSlug(data.slug);
+      setFormOrigins((data.allowed_origins ?? []).join('\n'));
     } catch (err) {
       console.error('Failed to fetch tenant:', err);
       toast.error('Falha ao carregar tenant');
@@ -119,6 +121,7 @@ export default function TenantEditorPage() {
         name: 'Tenant de Exemplo',
         slug: 'tenant-exemplo',
         is_active: true,
+        allowed_origins: [],
         theme_config: {},
         created_at: new Date().toISOString(),
         updated_at: new Date().toISOString(),
@@ -149,6 +152,10 @@ export default function TenantEditorPage() {
       await updateAdminTenant(tenantId, {
         name: formName.trim(),
         slug: formSlug.trim(),
+        allowed_origins: formOrigins
+          .split('\n')
+          .map((origin) => origin.trim())
+          .filter(Boolean),
       });
       toast.success('Tenant atualizado com sucesso');
       loadTenant();
@@ -386,6 +393,22 @@ export default function TenantEditorPage() {
                   hifens.
                 </p>
               </div>
+              <div className="space-y-2">
+                <label className="text-sm font-medium text-slate-300">
+                  Origens permitidas (CORS)
+                </label>
+                <textarea
+                  value={formOrigins}
+                  onChange={(e) => setFormOrigins(e.target.value)}
+                  placeholder="https://painel.exemplo.gov.br"
+                  rows={3}
+                  className="w-full rounded-md border bg-slate-900 border-slate-700 px-3 py-2 text-sm text-white font-mono placeholder:text-slate-500"
+                />
+                <p className="text-xs text-slate-500">
+                  Uma origem por linha (ex.: https://painel.exemplo.gov.br). Aceitas nas
+                  requisicoes ao subdominio do slug, alem das origens globais.
+                </p>
+              </div>
               <div className="space-y-2">
                 <label className="text-sm font-medium text-slate-300">ID</label>
                 <Input
@@ -55,6 +55,7 @@ import {
   fetchTriagemTemplateUsage,
   type AdminTriagemTemplate,
   type AdminTenant,
+  type TriagemTemplateTenantUsage,
   type PaginatedAdminResponse,
 } from '@/lib/api/admin';
 import { CloneTemplateDialog } from '@/components/admin/CloneTemplateDialog';
@@ -293,7 +294,7 @@ interface ViewTemplateDialogProps {
   open: boolean;
   onClose: () => void;
   template: AdminTriagemTemplate | null;
-  tenantsUsing: { id: string; name: string }[];
+  tenantsUsing: TriagemTemplateTenantUsage[];
   isLoadingUsage?: boolean;
 }
 
@@ -367,15 +368,34 @@ function ViewTemplateDialog({
                 Nenhum tenant esta usando este template
               </p>
             ) : (
-              <div className="flex flex-wrap gap-2">
+              <div className="space-y-2">
                 {tenantsUsing.map((tenant) => (
-                  <Badge
-                    key={tenant.id}
-                    variant="outline"
-                    className="bg-slate-700/50 border-slate-600 text-slate-300"
-                  >
-                    {tenant.name}
-                  </Badge>
+                  <div key={tenant.tenant_id} className="p-3 bg-slate-900 rounded-lg space-y-2">
+                    <div className="flex items-center justify-between">
+                      <span className="text-sm text-slate-200">{tenant.tenant_name}</span>
+                      <span className="text-xs text-slate-500">
+                        {tenant.rule_count} regra(s)
+                        {tenant.modified_count > 0 && ` - ${tenant.modified_count} modificada(s)`}
+                      </span>
+                    </div>
+                    <div className="flex flex-wrap gap-2">
+                      {tenant.rules.map((rule) => (
+                        <Badge
+                          key={rule.rule_id}
+                          variant="outline"
+                          className={
+                            rule.modified
+                              ? 'bg-amber-400/10 text-amber-400 border-amber-400/20'
+                              : 'bg-slate-700/50 border-slate-600 text-slate-300'
+                          }
+                        >
+                          {rule.nome}
+                          {rule.modified === true && ' (modificada)'}
+                          {rule.modified === null && ' (origem sem rastreio)'}
+                        </Badge>
+                      ))}
+                    </div>
+                  </div>
                 ))}
               </div>
             )}
@@ -407,7 +427,7 @@ export default function TriagemTemplatesPage() {
   const [viewDialogOpen, setViewDialogOpen] = useState(false);
   const [cloneDialogOpen, setCloneDialogOpen] = useState(false);
   const [selectedTemplate, setSelectedTemplate] = useState<AdminTriagemTemplate | null>(null);
-  const [tenantsUsing, setTenantsUsing] = useState<{ id: string; name: string }[]>([]);
+  const [tenantsUsing, setTenantsUsing] = useState<TriagemTemplateTenantUsage[]>([]);
   const [isActionLoading, setIsActionLoading] = useState(false);
   const [isLoadingUsage, setIsLoadingUsage] = useState(false);
 
@@ -530,7 +550,21 @@ export default function TriagemTemplatesPage() {
     try {
       setIsActionLoading(true);
       const result = await cloneTriagemTemplateToTenants(templateId, tenantIds);
-      toast.success(`Template clonado para ${result.cloned_count} tenant(s)`);
+      if (result.failure_count === 0) {
+        toast.success(`Template clonado para ${result.success_count} tenant(s)`);
+      } else {
+        const tenantNames = new Map(tenants.map((t) => [t.id, t.name]));
+        const failures = result.results
+          .filter((r) => !r.success)
+          .map((r) => `${tenantNames.get(r.tenant_id) ?? r.tenant_id}: ${r.error}`)
+          .join('; ');
+        const summary = `Clonado para ${result.success_count} tenant(s), falhou em ${result.failure_count}`;
+        if (result.success_count > 0) {
+          toast.warning(summary, { description: failures });
+        } else {
+          toast.error('Falha ao clonar template', { description: failures });
+        }
+      }
       setCloneDialogOpen(false);
     } catch (err) {
       console.error('Failed to clone template:', err);
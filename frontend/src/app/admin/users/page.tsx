@@ -419,10 +419,10 @@ export default function UsersListPage() {
     setViewDialogOpen(true);
   };
 
-  const handleImpersonate = async (userId: string) => {
+  const handleImpersonate = async (userId: string, reason: string) => {
     try {
       setIsActionLoading(true);
-      const result = await impersonateUser(userId);
+      const result = await impersonateUser(userId, { reason });
 
       // Open new tab with impersonation token
       const url = `/dashboard?impersonate_token=${result.access_token}`;
@@ -31,11 +31,30 @@ import { Label } from '@/components/ui/label';
 import { Textarea } from '@/components/ui/textarea';
 import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@/components/ui/select';
 import { useTriagemRules, useCreateTriagemRule, useUpdateTriagemRule, useToggleTriagemRule, useDeleteTriagemRule } from '@/hooks';
-import type { TriagemRule, CreateTriagemRuleInput, UpdateTriagemRuleInput, TriagemRuleConfig } from '@/types';
+import type { TriagemRule, CreateTriagemRuleInput, UpdateTriagemRuleInput, TriagemRuleConfig, TriagemRuleAction } from '@/types';
 
 type RuleType = 'idade_maxima' | 'janela_horas' | 'causas_excludentes' | 'identificacao';
 
+// Tipo da regra no formato do backend ({tipo, valor, acao})
+const RULE_TYPE_TIPO: Record<RuleType, string> = {
+  idade_maxima: 'idade_maxima',
+  janela_horas: 'janela_horas',
+  causas_excludentes: 'causas_excludentes',
+  identificacao: 'identificacao_desconhecida',
+};
+
+// Acoes oferecidas no editor; priorizar e exclusiva das regras de setor
+const RULE_ACTIONS: { value: TriagemRuleAction; label: string; description: string }[] = [
+  { value: 'rejeitar', label: 'Rejeitar', description: 'O obito e inelegivel e a avaliacao termina nesta regra' },
+  { value: 'alertar', label: 'Alertar', description: 'O obito e inelegivel e as demais regras continuam sendo avaliadas' },
+  { value: 'flag', label: 'Sinalizar', description: 'O obito continua elegivel e a ocorrencia recebe um aviso' },
+];
+
 function getRuleType(config: TriagemRuleConfig): RuleType | null {
+  if (config.tipo) {
+    const entry = Object.entries(RULE_TYPE_TIPO).find(([, tipo]) => tipo === config.tipo);
+    return entry ? (entry[0] as RuleType) : null;
+  }
   if (config.idade_maxima !== undefined) return 'idade_maxima';
   if (config.janela_horas !== undefined) return 'janela_horas';
   if (config.causas_excludentes !== undefined && config.causas_excludentes.length > 0) return 'causas_excludentes';
@@ -43,13 +62,30 @@ function getRuleType(config: TriagemRuleConfig): RuleType | null {
   return null;
 }
 
+// getRuleValue returns the value of the rule in either the backend or the legacy format
+function getRuleValue(config: TriagemRuleConfig): unknown {
+  if (config.tipo) return config.valor;
+  return config.idade_maxima ?? config.janela_horas ?? config.causas_excludentes ?? config.identificacao_desconhecida_inelegivel;
+}
+
+function getRuleActionLabel(config: TriagemRuleConfig): string | null {
+  return RULE_ACTIONS.find((a) => a.value === config.acao)?.label ?? null;
+}
+
 function getRuleDisplayValue(config: TriagemRuleConfig): string {
-  if (config.idade_maxima !== undefined) return `Idade maxima: ${config.idade_maxima} anos`;
-  if (config.janela_horas !== undefined) return `Janela: ${config.janela_horas} horas`;
-  if (config.causas_excludentes !== undefined && config.causas_excludentes.length > 0) {
-    return `CIDs: ${config.causas_excludentes.slice(0, 3).join(', ')}${config.causas_excludentes.length > 3 ? '...' : ''}`;
+  const value = getRuleValue(config);
+  switch (getRuleType(config)) {
+    case 'idade_maxima':
+      return `Idade maxima: ${value} anos`;
+    case 'janela_horas':
+      return `Janela: ${value} horas`;
+    case 'causas_excludentes': {
+      const cids = Array.isArray(value) ? (value as string[]) : [];
+      return `CIDs: ${cids.slice(0, 3).join(', ')}${cids.length > 3 ? '...' : ''}`;
+    }
+    case 'identificacao':
+      return value ? 'Identificacao desconhecida inelegivel' : 'Configuracao vazia';
   }
-  if (config.identificacao_desconhecida_inelegivel) return 'Identificacao desconhecida inelegivel';
   return 'Configuracao vazia';
 }
 
@@ -86,6 +122,7 @@ export default function RulesPage() {
   const [formIdadeMaxima, setFormIdadeMaxima] = useState('70');
   const [formJanelaHoras, setFormJanelaHoras] = useState('6');
   const [formCausasExcludentes, setFormCausasExcludentes] = useState('');
+  const [formAction, setFormAction] = useState<TriagemRuleAction>('rejeitar');
 
   const rules = data?.data ?? [];
 
@@ -116,6 +153,7 @@ export default function RulesPage() {
     setFormIdadeMaxima('70');
     setFormJanelaHoras('6');
     setFormCausasExcludentes('');
+    setFormAction('rejeitar');
     setIsFormOpen(true);
   };
 
@@ -126,29 +164,31 @@ export default function RulesPage() {
     setFormPriority(String(rule.prioridade));
 
     const type = getRuleType(rule.regras);
+    const value = getRuleValue(rule.regras);
     setFormRuleType(type || 'idade_maxima');
-    setFormIdadeMaxima(String(rule.regras.idade_maxima ?? 70));
-    setFormJanelaHoras(String(rule.regras.janela_horas ?? 6));
-    setFormCausasExcludentes(rule.regras.causas_excludentes?.join(', ') ?? '');
+    setFormIdadeMaxima(String(type === 'idade_maxima' ? value : 70));
+    setFormJanelaHoras(String(type === 'janela_horas' ? value : 6));
+    setFormCausasExcludentes(type === 'causas_excludentes' && Array.isArray(value) ? value.join(', ') : '');
+    setFormAction(rule.regras.acao && rule.regras.acao !== 'priorizar' ? rule.regras.acao : 'rejeitar');
 
     setIsFormOpen(true);
   };
 
   const handleSubmit = async () => {
-    const regras: TriagemRuleConfig = {};
+    const regras: TriagemRuleConfig = { tipo: RULE_TYPE_TIPO[formRuleType], acao: formAction };
 
     switch (formRuleType) {
       case 'idade_maxima':
-        regras.idade_maxima = parseInt(formIdadeMaxima, 10);
+        regras.valor = parseInt(formIdadeMaxima, 10);
         break;
       case 'janela_horas':
-        regras.janela_horas = parseInt(formJanelaHoras, 10);
+        regras.valor = parseInt(formJanelaHoras, 10);
         break;
       case 'causas_excludentes':
-        regras.causas_excludentes = formCausasExcludentes.split(',').map(s => s.trim()).filter(Boolean);
+        regras.valor = formCausasExcludentes.split(',').map(s => s.trim()).filter(Boolean);
         break;
       case 'identificacao':
-        regras.identificacao_desconhecida_inelegivel = true;
+        regras.valor = true;
         break;
     }
 
@@ -257,6 +297,9 @@ export default function RulesPage() {
                     <TableCell>{getRuleTypeBadge(rule.regras)}</TableCell>
                     <TableCell className="text-muted-foreground">
                       {getRuleDisplayValue(rule.regras)}
+                      {getRuleActionLabel(rule.regras) && (
+                        <Badge variant="outline" className="ml-2">{getRuleActionLabel(rule.regras)}</Badge>
+                      )}
                     </TableCell>
                     <TableCell>
                       <Badge variant="outline">{rule.prioridade}</Badge>
@@ -375,6 +418,23 @@ export default function RulesPage() {
               </div>
             </div>
 
+            <div className="grid gap-2">
+              <Label htmlFor="action">Acao</Label>
+              <Select value={formAction} onValueChange={(v) => setFormAction(v as TriagemRuleAction)}>
+                <SelectTrigger id="action">
+                  <SelectValue />
+                </SelectTrigger>
+                <SelectContent>
+                  {RULE_ACTIONS.map((action) => (
+                    <SelectItem key={action.value} value={action.value}>{action.label}</SelectItem>
+                  ))}
+                </SelectContent>
+              </Select>
+              <p className="text-xs text-muted-foreground">
+                {RULE_ACTIONS.find((a) => a.value === formAction)?.description}
+              </p>
+            </div>
+
             {/* Dynamic fields based on rule type */}
             {formRuleType === 'idade_maxima' && (
               <div className="grid gap-2">
@@ -431,8 +491,12 @@ export default function RulesPage() {
             {formRuleType === 'identificacao' && (
               <div className="p-4 bg-muted rounded-lg">
                 <p className="text-sm">
-                  Esta regra marca automaticamente pacientes com identificacao desconhecida
-                  como <strong>inelegiveis</strong> para doacao.
+                  {formAction === 'flag' ? (
+                    <>Esta regra adiciona um <strong>aviso</strong> as ocorrencias de pacientes com identificacao desconhecida.</>
+                  ) : (
+                    <>Esta regra marca automaticamente pacientes com identificacao desconhecida
+                    como <strong>inelegiveis</strong> para doacao.</>
+                  )}
                 </p>
               </div>
             )}
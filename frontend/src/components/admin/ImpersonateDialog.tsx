@@ -12,13 +12,15 @@ import {
   DialogTitle,
 } from '@/components/ui/dialog';
 import { Badge } from '@/components/ui/badge';
+import { Label } from '@/components/ui/label';
+import { Textarea } from '@/components/ui/textarea';
 import type { AdminUser } from '@/lib/api/admin';
 
 interface ImpersonateDialogProps {
   open: boolean;
   onClose: () => void;
   user: AdminUser | null;
-  onImpersonate: (userId: string) => void;
+  onImpersonate: (userId: string, reason: string) => void;
   isLoading?: boolean;
 }
 
@@ -29,14 +31,24 @@ export function ImpersonateDialog({
   onImpersonate,
   isLoading = false,
 }: ImpersonateDialogProps) {
+  const [reason, setReason] = useState('');
+
   if (!user) return null;
 
+  const trimmedReason = reason.trim();
+
   const handleConfirm = () => {
-    onImpersonate(user.id);
+    if (!trimmedReason) return;
+    onImpersonate(user.id, trimmedReason);
+  };
+
+  const handleClose = () => {
+    setReason('');
+    onClose();
   };
 
   return (
-    <Dialog open={open} onOpenChange={(o) => !o && onClose()}>
+    <Dialog open={open} onOpenChange={(o) => !o && handleClose()}>
       <DialogContent className="bg-slate-800 border-slate-700 text-white sm:max-w-md">
         <DialogHeader>
           <DialogTitle className="flex items-center gap-2">
@@ -87,6 +99,25 @@ export function ImpersonateDialog({
             </div>
           </div>
 
+          {/* Reason */}
+          <div className="space-y-2">
+            <Label htmlFor="impersonate-reason" className="text-slate-300">
+              Motivo
+            </Label>
+            <Textarea
+              id="impersonate-reason"
+              value={reason}
+              onChange={(e) => setReason(e.target.value)}
+              placeholder="Ex.: suporte ao chamado #123"
+              maxLength={500}
+              disabled={isLoading}
+              className="bg-slate-900 border-slate-700 text-white"
+            />
+            <p className="text-xs text-slate-500">
+              Obrigatorio. O motivo sera registrado no log de auditoria.
+            </p>
+          </div>
+
           {/* Info */}
           <div className="space-y-2 text-sm text-slate-400">
             <p className="flex items-center gap-2">
@@ -94,7 +125,8 @@ export function ImpersonateDialog({
               Uma nova aba sera aberta com a sessao do usuario
             </p>
             <p>
-              A sessao de impersonalizacao tem duracao limitada de 1 hora.
+              A sessao de impersonalizacao tem duracao limitada e pode ser encerrada
+              por um super admin a qualquer momento.
             </p>
           </div>
         </div>
@@ -103,7 +135,7 @@ export function ImpersonateDialog({
           <Button
             type="button"
             variant="ghost"
-            onClick={onClose}
+            onClick={handleClose}
             disabled={isLoading}
             className="text-slate-400 hover:bg-slate-700 hover:text-slate-200"
           >
@@ -112,7 +144,7 @@ export function ImpersonateDialog({
           <Button
             type="button"
             onClick={handleConfirm}
-            disabled={isLoading}
+            disabled={isLoading || !trimmedReason}
             className="bg-violet-600 hover:bg-violet-700"
           >
             {isLoading ? (
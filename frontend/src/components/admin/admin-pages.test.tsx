@@ -262,11 +262,14 @@ describe('Task Group 8: Remaining Admin Pages Tests', () => {
         { wrapper: createWrapper() }
       );
 
-      // Click confirm button
+      // Confirm is disabled until a reason is given
       const confirmButton = screen.getByRole('button', { name: /Confirmar Impersonalizacao/i });
+      expect(confirmButton).toBeDisabled();
+
+      await user.type(screen.getByLabelText(/Motivo/i), 'Suporte ao chamado #123');
       await user.click(confirmButton);
 
-      expect(mockOnImpersonate).toHaveBeenCalledWith('user-1');
+      expect(mockOnImpersonate).toHaveBeenCalledWith('user-1', 'Suporte ao chamado #123');
     });
 
     it('generates impersonation token when API is called', async () => {
@@ -19,6 +19,7 @@ const statusLabels: Record<string, string> = {
   RECUSADA: 'Recusada',
   CANCELADA: 'Cancelada',
   CONCLUIDA: 'Concluida',
+  EXPIRADA: 'Expirada',
 };
 
 const urgencyColors: Record<string, { bg: string; text: string; border: string }> = {
@@ -46,6 +47,7 @@ const statusBadgeVariants: Record<string, 'default' | 'secondary' | 'destructive
   RECUSADA: 'destructive',
   CANCELADA: 'secondary',
   CONCLUIDA: 'default',
+  EXPIRADA: 'secondary',
 };
 
 export function OccurrenceCard({ data, onClick, className }: OccurrenceCardProps) {
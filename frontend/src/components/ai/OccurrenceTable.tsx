@@ -36,6 +36,7 @@ const statusLabels: Record<string, string> = {
   RECUSADA: 'Recusada',
   CANCELADA: 'Cancelada',
   CONCLUIDA: 'Concluida',
+  EXPIRADA: 'Expirada',
 };
 
 const statusBadgeVariants: Record<string, 'default' | 'secondary' | 'destructive' | 'outline'> = {
@@ -45,6 +46,7 @@ const statusBadgeVariants: Record<string, 'default' | 'secondary' | 'destructive
   RECUSADA: 'destructive',
   CANCELADA: 'secondary',
   CONCLUIDA: 'default',
+  EXPIRADA: 'secondary',
 };
 
 const urgencyColors: Record<string, string> = {
@@ -2,7 +2,7 @@
 
 import { format, parseISO } from 'date-fns';
 import { ptBR } from 'date-fns/locale';
-import { Play, Check, X, Ban, Flag, Clock, User, Building2, Activity } from 'lucide-react';
+import { Play, Check, X, Ban, Flag, Clock, User, Building2, Activity, AlertTriangle } from 'lucide-react';
 import {
   Dialog,
   DialogContent,
@@ -116,6 +116,21 @@ export function OccurrenceDetailModal({
           </div>
         ) : occurrence?.dados_completos ? (
           <div className="space-y-6">
+            {/* Triagem Flags */}
+            {occurrence.flags && occurrence.flags.length > 0 && (
+              <div className="rounded-lg border border-yellow-500/50 bg-yellow-500/10 p-4">
+                <h3 className="mb-2 flex items-center gap-2 font-semibold">
+                  <AlertTriangle className="h-4 w-4 text-yellow-600" />
+                  Avisos da Triagem
+                </h3>
+                <ul className="list-disc space-y-1 pl-5 text-sm">
+                  {occurrence.flags.map((flag) => (
+                    <li key={flag}>{flag}</li>
+                  ))}
+                </ul>
+              </div>
+            )}
+
             {/* Patient Info */}
             <div className="rounded-lg border p-4">
               <h3 className="mb-3 flex items-center gap-2 font-semibold">
@@ -29,6 +29,7 @@ const statusOptions: { value: OccurrenceStatus | 'all'; label: string }[] = [
   { value: 'RECUSADA', label: 'Recusada' },
   { value: 'CANCELADA', label: 'Cancelada' },
   { value: 'CONCLUIDA', label: 'Concluida' },
+  { value: 'EXPIRADA', label: 'Expirada' },
 ];
 
 const sortOptions: { value: SortField; label: string }[] = [
@@ -74,6 +75,13 @@ export function OccurrenceFilters({
     });
   };
 
+  const handleSearchChange = (e: React.ChangeEvent<HTMLInputElement>) => {
+    onFiltersChange({
+      ...filters,
+      q: e.target.value || undefined,
+    });
+  };
+
   const handleSortFieldChange = (value: string) => {
     onSortChange(value as SortField, sortOrder);
   };
@@ -91,11 +99,25 @@ export function OccurrenceFilters({
     filters.status ||
     filters.hospital_id ||
     filters.date_from ||
-    filters.date_to;
+    filters.date_to ||
+    filters.q;
 
   return (
     <div className="space-y-4">
       <div className="flex flex-wrap gap-3">
+        {/* Patient Name Search */}
+        <div className="w-full sm:w-auto">
+          <Input
+            type="search"
+            value={filters.q || ''}
+            onChange={handleSearchChange}
+            placeholder="Buscar paciente"
+            maxLength={100}
+            className="w-full sm:w-[200px]"
+            aria-label="Buscar por nome do paciente"
+          />
+        </div>
+
         {/* Status Filter */}
         <div className="w-full sm:w-auto">
           <Select value={filters.status || 'all'} onValueChange={handleStatusChange}>
@@ -120,6 +120,22 @@ describe('OccurrencesTable', () => {
     expect(onViewDetails).toHaveBeenCalledWith('1');
   });
 
+  it('shows the triagem flags of an occurrence', () => {
+    const flagged: Occurrence = { ...mockOccurrences[0], flags: ['Idade acima do limite'] };
+
+    render(
+      <OccurrencesTable
+        occurrences={[flagged, mockOccurrences[1]]}
+        onViewDetails={vi.fn()}
+        onStatusChange={vi.fn()}
+        onComplete={vi.fn()}
+      />
+    );
+
+    expect(screen.getByLabelText('Avisos: Idade acima do limite')).toBeInTheDocument();
+    expect(screen.getAllByLabelText(/^Avisos:/)).toHaveLength(1);
+  });
+
   it('shows empty state when no occurrences', () => {
     const onViewDetails = vi.fn();
     const onStatusChange = vi.fn();
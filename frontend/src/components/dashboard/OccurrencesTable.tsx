@@ -1,7 +1,7 @@
 'use client';
 
 import { useState } from 'react';
-import { Eye, Play, Check, X, Ban, Flag } from 'lucide-react';
+import { Eye, Play, Check, X, Ban, Flag, AlertTriangle } from 'lucide-react';
 import {
   Table,
   TableBody,
@@ -166,7 +166,20 @@ export function OccurrencesTable({
                 <TableCell>
                   {occurrence.dados_completos?.setor || 'N/A'}
                 </TableCell>
-                <TableCell>{occurrence.nome_paciente_mascarado}</TableCell>
+                <TableCell>
+                  <div className="flex items-center gap-2">
+                    {occurrence.nome_paciente_mascarado}
+                    {occurrence.flags && occurrence.flags.length > 0 && (
+                      <span
+                        className="inline-flex items-center text-yellow-600"
+                        title={occurrence.flags.join('\n')}
+                        aria-label={`Avisos: ${occurrence.flags.join(', ')}`}
+                      >
+                        <AlertTriangle className="h-4 w-4" />
+                      </span>
+                    )}
+                  </div>
+                </TableCell>
                 <TableCell>
                   <StatusBadge status={occurrence.status} />
                 </TableCell>
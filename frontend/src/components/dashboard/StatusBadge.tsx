@@ -47,6 +47,12 @@ export function getStatusConfig(status: OccurrenceStatus): StatusConfig {
         variant: 'secondary',
         className: 'bg-sky-100 text-sky-800 border-sky-200',
       };
+    case 'EXPIRADA':
+      return {
+        label: 'Expirada',
+        variant: 'secondary',
+        className: 'bg-red-100 text-red-800 border-red-200',
+      };
     default:
       return {
         label: status,
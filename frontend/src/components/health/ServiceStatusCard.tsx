@@ -139,6 +139,15 @@ export function ServiceStatusCard({
           </div>
         </div>
 
+        {/* Connection Pool */}
+        {component.pool && (
+          <p className="mt-2 text-xs text-gray-500">
+            Conexoes: {component.pool.in_use} em uso / {component.pool.idle} ociosas
+            {component.pool.max_open_connections > 0 && ` (max ${component.pool.max_open_connections})`}
+            {component.pool.wait_count > 0 && ` - ${component.pool.wait_count} esperas`}
+          </p>
+        )}
+
         {/* Error Message */}
         {component.message && component.status !== 'up' && (
           <div className="mt-3 flex items-start gap-2 p-2 bg-white/50 rounded-md">
@@ -37,6 +37,17 @@ function DashboardLayoutInner({ children }: DashboardLayoutProps) {
         },
       });
     }
+
+    if (event.type === 'escalation') {
+      toast.error('Ocorrencia Pendente Proxima da Expiracao', {
+        description: `${event.hospital_nome ?? event.hospital} - ${event.setor}. Tempo restante: ${event.tempo_restante}`,
+        duration: 15000,
+        action: {
+          label: 'Ver',
+          onClick: () => router.push(`/dashboard/occurrences?id=${event.occurrence_id}`),
+        },
+      });
+    }
   };
 
   useSSE({
@@ -2,13 +2,21 @@
 
 import { useQuery } from '@tanstack/react-query';
 import { api } from '@/lib/api';
-import type { DashboardMetrics } from '@/types';
+import type { ComparisonPeriod, DashboardMetrics } from '@/types';
+
+interface UseMetricsOptions {
+  // Includes the change of each metric vs the previous period
+  compareWith?: ComparisonPeriod;
+}
+
+export function useMetrics(options: UseMetricsOptions = {}) {
+  const { compareWith } = options;
 
-export function useMetrics() {
   return useQuery({
-    queryKey: ['metrics'],
+    queryKey: ['metrics', compareWith],
     queryFn: async () => {
-      const response = await api.get<DashboardMetrics>('/metrics/dashboard');
+      const params = compareWith ? { compare: 'previous', period: compareWith } : undefined;
+      const response = await api.get<DashboardMetrics>('/metrics/dashboard', { params });
       return response.data;
     },
     refetchInterval: 30 * 1000, // Refresh every 30 seconds
@@ -2,6 +2,7 @@
 
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import { api } from '@/lib/api';
+import { useDebounce } from './useDebounce';
 import type {
   Occurrence,
   OccurrenceDetail,
@@ -32,11 +33,19 @@ interface OutcomeRequest {
   observacoes?: string;
 }
 
+interface AssignRequest {
+  id: string;
+  userId: string;
+  observacoes?: string;
+}
+
 export function useOccurrences(options: UseOccurrencesOptions = {}) {
   const { page = 1, perPage = 10, filters, sortBy, sortOrder } = options;
+  // Debounced so typing in the name search does not issue a request per keystroke
+  const search = useDebounce(filters?.q);
 
   return useQuery({
-    queryKey: ['occurrences', page, perPage, filters, sortBy, sortOrder],
+    queryKey: ['occurrences', page, perPage, { ...filters, q: search }, sortBy, sortOrder],
     queryFn: async () => {
       const params = new URLSearchParams();
       params.append('page', String(page));
@@ -54,6 +63,9 @@ export function useOccurrences(options: UseOccurrencesOptions = {}) {
       if (filters?.date_to) {
         params.append('date_to', filters.date_to);
       }
+      if (search) {
+        params.append('q', search);
+      }
       if (sortBy) {
         params.append('sort_by', sortBy);
       }
@@ -148,3 +160,22 @@ export function useRegisterOutcome() {
     },
   });
 }
+
+export function useAssignOccurrence() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: async ({ id, userId, observacoes }: AssignRequest) => {
+      const response = await api.post(`/occurrences/${id}/assign`, {
+        user_id: userId,
+        observacoes,
+      });
+      return response.data;
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['occurrences'] });
+      queryClient.invalidateQueries({ queryKey: ['occurrence'] });
+      queryClient.invalidateQueries({ queryKey: ['occurrence-history'] });
+    },
+  });
+}
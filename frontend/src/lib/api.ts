@@ -175,10 +175,17 @@ export interface AIConfirmResponse {
 
 export interface AIConversation {
   session_id: string;
-  title?: string;
-  last_message?: string;
-  created_at: string;
-  updated_at: string;
+  last_message_at: string;
+  message_count: number;
+}
+
+export interface AIConversationList {
+  data: AIConversation[];
+  meta: {
+    page: number;
+    per_page: number;
+    has_more: boolean;
+  };
 }
 
 export interface AIConversationHistory {
@@ -215,10 +222,23 @@ export const aiApi = {
   },
 
   /**
-   * List all user conversations
+   * List user conversations, most recent first
+   */
+  listConversations: async (page = 1, perPage = 20): Promise<AIConversationList> => {
+    const response = await api.get<AIConversationList>('/ai/conversations', {
+      params: { page, per_page: perPage },
+    });
+    return response.data;
+  },
+
+  /**
+   * Download a conversation as Markdown or JSON
    */
-  listConversations: async (): Promise<AIConversation[]> => {
-    const response = await api.get<AIConversation[]>('/ai/conversations');
+  exportConversation: async (sessionId: string, format: 'markdown' | 'json' = 'markdown'): Promise<Blob> => {
+    const response = await api.get(`/ai/conversations/${sessionId}/export`, {
+      params: { format },
+      responseType: 'blob',
+    });
     return response.data;
   },
 
@@ -27,6 +27,7 @@ export interface AdminTenant {
   is_active: boolean;
   logo_url?: string;
   favicon_url?: string;
+  allowed_origins: string[];
   theme_config: ThemeConfig;
   created_at: string;
   updated_at: string;
@@ -189,7 +190,7 @@ export async function createAdminTenant(input: {
 
 export async function updateAdminTenant(
   id: string,
-  input: Partial<{ name: string; slug: string }>
+  input: Partial<{ name: string; slug: string; allowed_origins: string[] }>
 ): Promise<AdminTenant> {
   const { data } = await api.put<AdminTenant>(`${ADMIN_BASE}/tenants/${id}`, input);
   return data;
@@ -253,14 +254,17 @@ export async function fetchAdminUser(id: string): Promise<AdminUser> {
   return data;
 }
 
-export async function impersonateUser(id: string): Promise<{
+export async function impersonateUser(
+  id: string,
+  input: { reason: string; duration_minutes?: number }
+): Promise<{
   access_token: string;
   expires_at: string;
 }> {
   const { data } = await api.post<{
     access_token: string;
     expires_at: string;
-  }>(`${ADMIN_BASE}/users/${id}/impersonate`);
+  }>(`${ADMIN_BASE}/users/${id}/impersonate`, input);
   return data;
 }
 
@@ -403,21 +407,55 @@ export async function updateAdminTriagemTemplate(
   return data;
 }
 
+export interface CloneTenantResult {
+  tenant_id: string;
+  success: boolean;
+  rule_id?: string;
+  error?: string;
+}
+
+export interface CloneTriagemTemplateResult {
+  message: string;
+  template_id: string;
+  success_count: number;
+  failure_count: number;
+  results: CloneTenantResult[];
+}
+
 export async function cloneTriagemTemplateToTenants(
   id: string,
   tenantIds: string[]
-): Promise<{ cloned_count: number }> {
-  const { data } = await api.post<{ cloned_count: number }>(
+): Promise<CloneTriagemTemplateResult> {
+  const { data } = await api.post<CloneTriagemTemplateResult>(
     `${ADMIN_BASE}/triagem-templates/${id}/clone`,
     { tenant_ids: tenantIds }
   );
   return data;
 }
 
+export interface TriagemTemplateDerivedRule {
+  rule_id: string;
+  nome: string;
+  ativo: boolean;
+  /** null when the rule predates clone tracking */
+  modified: boolean | null;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface TriagemTemplateTenantUsage {
+  tenant_id: string;
+  tenant_name: string;
+  tenant_slug: string;
+  rule_count: number;
+  modified_count: number;
+  rules: TriagemTemplateDerivedRule[];
+}
+
 export async function fetchTriagemTemplateUsage(
   id: string
-): Promise<{ tenants: { id: string; name: string }[] }> {
-  const { data } = await api.get<{ tenants: { id: string; name: string }[] }>(
+): Promise<{ template_id: string; total_tenants: number; tenants: TriagemTemplateTenantUsage[] }> {
+  const { data } = await api.get<{ template_id: string; total_tenants: number; tenants: TriagemTemplateTenantUsage[] }>(
     `${ADMIN_BASE}/triagem-templates/${id}/usage`
   );
   return data;
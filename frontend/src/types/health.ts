@@ -8,6 +8,17 @@ export interface ComponentStatus {
   latency_ms: number;
   last_check: string;
   message?: string;
+  /** Connection pool usage (database component only) */
+  pool?: DBPoolStats;
+}
+
+export interface DBPoolStats {
+  max_open_connections: number;
+  open_connections: number;
+  in_use: number;
+  idle: number;
+  wait_count: number;
+  wait_duration_ms: number;
 }
 
 export interface HealthSummary {
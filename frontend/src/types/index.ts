@@ -94,7 +94,8 @@ export type OccurrenceStatus =
   | 'ACEITA'
   | 'RECUSADA'
   | 'CANCELADA'
-  | 'CONCLUIDA';
+  | 'CONCLUIDA'
+  | 'EXPIRADA';
 
 export type OutcomeType =
   | 'sucesso_captacao'
@@ -113,10 +114,17 @@ export interface Occurrence {
   nome_paciente_mascarado: string;
   dados_completos?: ObitoData;
   notificado_em?: string;
+  assigned_user?: OccurrenceAssignee;
+  flags?: string[]; // Avisos das regras de triagem com acao "flag"
   created_at: string;
   updated_at: string;
 }
 
+export interface OccurrenceAssignee {
+  id: string;
+  nome: string;
+}
+
 export interface OccurrenceDetail extends Occurrence {
   dados_completos: ObitoData;
   history: OccurrenceHistoryItem[];
@@ -162,7 +170,13 @@ export interface TriagemRule {
   updated_at: string;
 }
 
+export type TriagemRuleAction = 'rejeitar' | 'priorizar' | 'alertar' | 'flag';
+
 export interface TriagemRuleConfig {
+  tipo?: string;
+  valor?: unknown;
+  acao?: TriagemRuleAction;
+  // Formato legado, uma chave por tipo de regra
   idade_maxima?: number;
   causas_excludentes?: string[];
   janela_horas?: number;
@@ -193,6 +207,24 @@ export interface DashboardMetrics {
   obitos_elegiveis_hoje: number;
   tempo_medio_notificacao_segundos: number;
   corneas_potenciais: number;
+  comparacao?: MetricsComparison;
+}
+
+export type ComparisonPeriod = 'day' | 'week';
+
+export interface MetricDelta {
+  atual: number;
+  anterior: number;
+  variacao_percentual: number | null;
+}
+
+export interface MetricsComparison {
+  periodo: ComparisonPeriod;
+  inicio_atual: string;
+  inicio_anterior: string;
+  obitos_elegiveis: MetricDelta;
+  tempo_medio_notificacao: MetricDelta;
+  corneas_potenciais: MetricDelta;
 }
 
 // =============================================================================
@@ -220,11 +252,13 @@ export interface ApiError {
 // =============================================================================
 
 export interface SSENotificationEvent {
-  type: 'new_occurrence' | 'status_update' | 'map_update';
+  type: 'new_occurrence' | 'status_update' | 'map_update' | 'escalation';
   occurrence_id: string;
   hospital: string;
+  hospital_nome?: string;
   hospital_id?: string;
   setor: string;
+  tempo_restante?: string;
   tempo_restante_minutos: number;
   timestamp: string;
 }
@@ -238,6 +272,7 @@ export interface OccurrenceFilters {
   hospital_id?: string;
   date_from?: string;
   date_to?: string;
+  q?: string;
 }
 
 export type SortField = 'created_at' | 'score_priorizacao' | 'tempo_restante';
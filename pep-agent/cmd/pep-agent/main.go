@@ -19,15 +19,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/sidot/pep-agent/internal/config"
+	"github.com/sidot/pep-agent/internal/models"
 	"github.com/sidot/pep-agent/internal/poller"
+	"github.com/sidot/pep-agent/internal/pusher"
 )
 
 const (
@@ -68,6 +72,9 @@ func main() {
 	logger.Printf("Source table: %s", cfg.Mapping.SourceTable)
 	logger.Printf("Central server: %s", cfg.Central.URL)
 	logger.Printf("Hospital ID: %s", cfg.Agent.HospitalID)
+	for _, warning := range cfg.MappingWarnings() {
+		logger.Printf("Mapping warning: mapping.%s", warning)
+	}
 
 	// Validate only mode
 	if *validateOnly {
@@ -79,6 +86,28 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Verify the API key and hospital before polling; an unreachable server is not fatal,
+	// the poller retries, but a rejected key or another hospital is a misconfiguration
+	pingCtx, pingCancel := context.WithTimeout(ctx, cfg.GetTimeout())
+	ping, err := pusher.NewPusher(cfg).Ping(pingCtx)
+	pingCancel()
+	switch {
+	case errors.Is(err, pusher.ErrNotAuthorized):
+		logger.Fatalf("Central server rejected the configuration: %v (check central.api_key and agent.hospital_id)", err)
+	case err != nil:
+		logger.Printf("Warning: could not reach the central server: %v", err)
+	case !strings.EqualFold(ping.HospitalID, cfg.Agent.HospitalID):
+		logger.Fatalf("API key belongs to hospital %s (%s), but agent.hospital_id is %s", ping.HospitalID, ping.HospitalNome, cfg.Agent.HospitalID)
+	default:
+		logger.Printf("Central server recognized hospital: %s (tenant %s)", ping.HospitalNome, ping.TenantSlug)
+		if models.EventSchemaVersion > ping.EventSchemaVersion || models.EventSchemaVersion < ping.MinEventSchemaVersion {
+			logger.Fatalf("Central server accepts event schema v%d to v%d, agent sends v%d", ping.MinEventSchemaVersion, ping.EventSchemaVersion, models.EventSchemaVersion)
+		}
+		if models.EventSchemaVersion < ping.EventSchemaVersion {
+			logger.Printf("Warning: server expects event schema v%d, agent sends v%d; update the agent", ping.EventSchemaVersion, models.EventSchemaVersion)
+		}
+	}
+
 	// Create and start poller
 	p := poller.NewPoller(cfg)
 	p.SetLogger(logger)
@@ -5,5 +5,8 @@ go 1.21
 require (
 	github.com/go-sql-driver/mysql v1.7.1
 	github.com/lib/pq v1.10.9
+	github.com/sidot/backend v0.0.0
 	gopkg.in/yaml.v3 v3.0.1
 )
+
+replace github.com/sidot/backend => ../backend
@@ -3,12 +3,15 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"os"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/sidot/backend/pkg/pepmapping"
 	"gopkg.in/yaml.v3"
 )
 
@@ -31,34 +34,12 @@ type DatabaseConfig struct {
 	SSLMode  string `yaml:"ssl_mode"` // disable, require, verify-full
 }
 
-// MappingConfig defines the field mapping from PEP to SIDOT
-type MappingConfig struct {
-	SourceTable  string       `yaml:"source_table"`  // e.g., "TASY.TB_PACIENTE_OBITO"
-	Fields       FieldMapping `yaml:"fields"`
-	FilterColumn string       `yaml:"filter_column"` // column for watermark filtering
-	CustomQuery  string       `yaml:"custom_query"`  // optional: override auto-generated query
-}
+// MappingConfig defines the field mapping from PEP to SIDOT. The type and its validation
+// are shared with the central server, which checks uploaded mappings with the same rules.
+type MappingConfig = pepmapping.Mapping
 
 // FieldMapping maps PEP database columns to SIDOT standard fields
-type FieldMapping struct {
-	// Required fields
-	ID             string `yaml:"id"`              // unique identifier in source
-	NomePaciente   string `yaml:"nome_paciente"`
-	DataObito      string `yaml:"data_obito"`
-	CausaMortis    string `yaml:"causa_mortis"`
-	DataNascimento string `yaml:"data_nascimento"` // or use idade
-	Idade          string `yaml:"idade"`           // alternative to data_nascimento
-
-	// Patient identification (at least one required)
-	CNS string `yaml:"cns"` // Cartao Nacional de Saude
-	CPF string `yaml:"cpf"` // CPF (will be masked)
-
-	// Optional fields
-	Setor                     string `yaml:"setor"`
-	Leito                     string `yaml:"leito"`
-	Prontuario                string `yaml:"prontuario"`
-	IdentificacaoDesconhecida string `yaml:"identificacao_desconhecida"` // 'S' or 'N'
-}
+type FieldMapping = pepmapping.FieldMapping
 
 // CentralConfig defines the connection to the SIDOT central server
 type CentralConfig struct {
@@ -66,6 +47,43 @@ type CentralConfig struct {
 	APIKey   string `yaml:"api_key"`  // supports ${ENV_VAR} syntax
 	Insecure bool   `yaml:"insecure"` // skip TLS verification (dev only)
 	Timeout  string `yaml:"timeout"`  // request timeout (default: 30s)
+
+	// Mutual TLS (optional): client certificate presented to the central server,
+	// registered there by its SHA-256 fingerprint
+	ClientCert string `yaml:"client_cert"` // path to the PEM certificate
+	ClientKey  string `yaml:"client_key"`  // path to the PEM private key
+	CACert     string `yaml:"ca_cert"`     // optional: CA bundle to verify the server
+}
+
+// TLSConfig builds the TLS settings of the HTTPS client, loading the client certificate
+// and CA bundle when configured
+func (c *CentralConfig) TLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.Insecure}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, fmt.Errorf("central.client_cert and central.client_key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.CACert != "" {
+		pem, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read central.ca_cert: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("central.ca_cert has no PEM certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
 }
 
 // AgentSettings defines operational parameters for the agent
@@ -75,6 +93,11 @@ type AgentSettings struct {
 	StateFile      string `yaml:"state_file"`      // path to watermark state file
 	LogLevel       string `yaml:"log_level"`       // debug, info, warn, error
 	AlertThreshold string `yaml:"alert_threshold"` // offline duration to trigger alert (default: 10m)
+
+	// Adaptive polling: each empty poll multiplies the interval by poll_backoff_factor,
+	// up to max_poll_interval; finding records resets it to poll_interval
+	MaxPollInterval   string  `yaml:"max_poll_interval"`   // default: 30s (set to poll_interval to disable)
+	PollBackoffFactor float64 `yaml:"poll_backoff_factor"` // default: 1.5
 }
 
 // Load reads and parses a YAML configuration file
@@ -135,34 +158,20 @@ func (c *AgentConfig) Validate() error {
 		return fmt.Errorf("database.user is required")
 	}
 
-	// Mapping validation
-	if c.Mapping.SourceTable == "" && c.Mapping.CustomQuery == "" {
-		return fmt.Errorf("mapping.source_table or mapping.custom_query is required")
-	}
-
-	// Field mapping validation (required fields)
-	if c.Mapping.Fields.ID == "" {
-		return fmt.Errorf("mapping.fields.id is required")
-	}
-	if c.Mapping.Fields.NomePaciente == "" {
-		return fmt.Errorf("mapping.fields.nome_paciente is required")
-	}
-	if c.Mapping.Fields.DataObito == "" {
-		return fmt.Errorf("mapping.fields.data_obito is required")
-	}
-	if c.Mapping.Fields.CausaMortis == "" {
-		return fmt.Errorf("mapping.fields.causa_mortis is required")
-	}
-	if c.Mapping.Fields.DataNascimento == "" && c.Mapping.Fields.Idade == "" {
-		return fmt.Errorf("mapping.fields.data_nascimento or mapping.fields.idade is required")
+	// Mapping validation (the first error; warnings are reported by MappingWarnings)
+	if errs := pepmapping.Errors(pepmapping.Validate(c.Mapping)); len(errs) > 0 {
+		return fmt.Errorf("mapping.%s", errs[0])
 	}
 
 	// Central validation
 	if c.Central.URL == "" {
 		return fmt.Errorf("central.url is required")
 	}
-	if c.Central.APIKey == "" {
-		return fmt.Errorf("central.api_key is required")
+	if c.Central.APIKey == "" && c.Central.ClientCert == "" {
+		return fmt.Errorf("central.api_key (or central.client_cert) is required")
+	}
+	if _, err := c.Central.TLSConfig(); err != nil {
+		return err
 	}
 
 	// Agent validation
@@ -173,6 +182,11 @@ func (c *AgentConfig) Validate() error {
 	return nil
 }
 
+// MappingWarnings returns the mapping issues that do not prevent the agent from starting
+func (c *AgentConfig) MappingWarnings() []pepmapping.Issue {
+	return pepmapping.Warnings(pepmapping.Validate(c.Mapping))
+}
+
 // SetDefaults sets default values for optional configuration fields
 func (c *AgentConfig) SetDefaults() {
 	if c.Database.Port == 0 {
@@ -191,6 +205,12 @@ func (c *AgentConfig) SetDefaults() {
 	if c.Agent.PollInterval == "" {
 		c.Agent.PollInterval = "3s"
 	}
+	if c.Agent.MaxPollInterval == "" {
+		c.Agent.MaxPollInterval = "30s"
+	}
+	if c.Agent.PollBackoffFactor == 0 {
+		c.Agent.PollBackoffFactor = 1.5
+	}
 	if c.Agent.StateFile == "" {
 		c.Agent.StateFile = "/var/lib/pep-agent/state.json"
 	}
@@ -203,6 +223,9 @@ func (c *AgentConfig) SetDefaults() {
 	if c.Central.Timeout == "" {
 		c.Central.Timeout = "30s"
 	}
+	if c.Mapping.Masking.CPF == "" {
+		c.Mapping.Masking.CPF = pepmapping.MaskPartial
+	}
 	if c.Mapping.FilterColumn == "" {
 		c.Mapping.FilterColumn = c.Mapping.Fields.DataObito
 	}
@@ -217,6 +240,25 @@ func (c *AgentConfig) GetPollInterval() time.Duration {
 	return d
 }
 
+// GetMaxPollInterval returns the longest poll interval of adaptive polling,
+// never shorter than the poll interval
+func (c *AgentConfig) GetMaxPollInterval() time.Duration {
+	d, err := time.ParseDuration(c.Agent.MaxPollInterval)
+	if err != nil || d < c.GetPollInterval() {
+		return c.GetPollInterval()
+	}
+	return d
+}
+
+// GetPollBackoffFactor returns the growth factor of the poll interval after an empty poll
+// (1 keeps the interval fixed)
+func (c *AgentConfig) GetPollBackoffFactor() float64 {
+	if c.Agent.PollBackoffFactor < 1 {
+		return 1
+	}
+	return c.Agent.PollBackoffFactor
+}
+
 // GetAlertThreshold returns the alert threshold as a time.Duration
 func (c *AgentConfig) GetAlertThreshold() time.Duration {
 	d, err := time.ParseDuration(c.Agent.AlertThreshold)
@@ -235,6 +277,12 @@ func (c *AgentConfig) GetTimeout() time.Duration {
 	return d
 }
 
+// GetPingURL returns the ping endpoint next to the events URL
+// e.g. "https://sidot.example.com/api/v1/pep/eventos" -> ".../api/v1/pep/ping"
+func (c *AgentConfig) GetPingURL() string {
+	return strings.TrimSuffix(strings.TrimRight(c.Central.URL, "/"), "/eventos") + "/ping"
+}
+
 // GetDSN returns the database connection string
 func (c *AgentConfig) GetDSN() string {
 	switch c.Database.Driver {
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCertificate writes a self-signed client certificate and its key as PEM files
+func writeTestCertificate(t *testing.T) (certPath, keyPath string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "pep-agent"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certPath = filepath.Join(dir, "agent.crt")
+	keyPath = filepath.Join(dir, "agent.key")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	return certPath, keyPath
+}
+
+func TestCentralConfigTLSConfig(t *testing.T) {
+	certPath, keyPath := writeTestCertificate(t)
+
+	t.Run("loads the client certificate and CA", func(t *testing.T) {
+		central := CentralConfig{ClientCert: certPath, ClientKey: keyPath, CACert: certPath}
+
+		tlsConfig, err := central.TLSConfig()
+		if err != nil {
+			t.Fatalf("TLSConfig() error = %v", err)
+		}
+		if len(tlsConfig.Certificates) != 1 {
+			t.Errorf("Certificates = %d; want 1", len(tlsConfig.Certificates))
+		}
+		if tlsConfig.RootCAs == nil {
+			t.Error("RootCAs not set from ca_cert")
+		}
+	})
+
+	t.Run("API key only has no client certificate", func(t *testing.T) {
+		tlsConfig, err := (&CentralConfig{Insecure: true}).TLSConfig()
+		if err != nil {
+			t.Fatalf("TLSConfig() error = %v", err)
+		}
+		if len(tlsConfig.Certificates) != 0 || !tlsConfig.InsecureSkipVerify {
+			t.Errorf("TLSConfig() = %+v; want insecure without certificates", tlsConfig)
+		}
+	})
+
+	t.Run("rejects invalid settings", func(t *testing.T) {
+		tests := map[string]CentralConfig{
+			"certificate without key":  {ClientCert: certPath},
+			"missing certificate":      {ClientCert: filepath.Join(t.TempDir(), "missing.crt"), ClientKey: keyPath},
+			"key is not a certificate": {ClientCert: keyPath, ClientKey: keyPath},
+			"CA without certificates":  {CACert: keyPath},
+		}
+		for name, central := range tests {
+			if _, err := central.TLSConfig(); err == nil {
+				t.Errorf("%s: TLSConfig() error = nil; want an error", name)
+			}
+		}
+	})
+}
+
+func TestValidateAcceptsClientCertificateWithoutAPIKey(t *testing.T) {
+	certPath, keyPath := writeTestCertificate(t)
+
+	cfg := AgentConfig{
+		Database: DatabaseConfig{Driver: "postgres", Host: "localhost", Database: "pep", User: "reader"},
+		Mapping: MappingConfig{
+			SourceTable: "OBITOS",
+			Fields:      FieldMapping{ID: "ID", NomePaciente: "NOME", DataObito: "DT", CausaMortis: "CAUSA", Idade: "IDADE"},
+		},
+		Central: CentralConfig{URL: "https://sidot.example.com/api/v1/pep/eventos", ClientCert: certPath, ClientKey: keyPath},
+		Agent:   AgentSettings{HospitalID: "123e4567-e89b-12d3-a456-426614174000"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	cfg.Central.ClientCert, cfg.Central.ClientKey = "", ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() without api_key and client_cert = nil; want an error")
+	}
+}
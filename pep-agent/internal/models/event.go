@@ -7,9 +7,15 @@ import (
 	"unicode"
 )
 
+// EventSchemaVersion is the version of ObitoEvent, checked against the central server on startup
+const EventSchemaVersion = 2
+
 // ObitoEvent represents a death event to be sent to SIDOT central server
 // This structure mirrors the ObitoEvent in the main backend for compatibility
 type ObitoEvent struct {
+	// Version of this payload, used by the server to upconvert older agents
+	SchemaVersion int `json:"schema_version"`
+
 	// Unique identifier from the source PEP system
 	HospitalIDOrigem string `json:"hospital_id_origem"`
 
@@ -62,6 +68,7 @@ type PEPRecord struct {
 // ToObitoEvent converts a PEPRecord to ObitoEvent with LGPD masking
 func (r *PEPRecord) ToObitoEvent(hospitalID string) *ObitoEvent {
 	event := &ObitoEvent{
+		SchemaVersion:     EventSchemaVersion,
 		HospitalIDOrigem:  r.ID,
 		HospitalID:        hospitalID,
 		TimestampDeteccao: time.Now().Format(time.RFC3339),
@@ -142,6 +149,15 @@ func MaskCPF(cpf string) string {
 	return "***.***.***-" + cleaned[9:]
 }
 
+// MaskCPFFull masks every digit of a CPF number (masking.cpf: full)
+// Example: "123.456.789-10" -> "***.***.***-**"
+func MaskCPFFull(cpf string) string {
+	if cpf == "" {
+		return ""
+	}
+	return "***.***.***-**"
+}
+
 // MaskName masks a name for LGPD compliance (for logging purposes)
 // Example: "Joao Silva" -> "Jo** Si***"
 func MaskName(name string) string {
@@ -0,0 +1,44 @@
+package poller
+
+import "time"
+
+// pollBackoff adapts the poll interval to the activity of the PEP source: every empty poll
+// multiplies the interval by the growth factor, up to the max, and a poll that finds records
+// goes back to the base interval
+type pollBackoff struct {
+	base    time.Duration
+	max     time.Duration
+	factor  float64
+	current time.Duration
+}
+
+// newPollBackoff creates a backoff starting at the base interval. A max below the base
+// or a factor up to 1 keeps the interval fixed.
+func newPollBackoff(base, max time.Duration, factor float64) *pollBackoff {
+	if max < base {
+		max = base
+	}
+	return &pollBackoff{base: base, max: max, factor: factor, current: base}
+}
+
+// Next records the result of a poll and returns the delay until the next one
+func (b *pollBackoff) Next(found bool) time.Duration {
+	if found {
+		b.current = b.base
+		return b.current
+	}
+
+	if b.factor > 1 {
+		next := time.Duration(float64(b.current) * b.factor)
+		if next > b.max || next < b.current {
+			next = b.max
+		}
+		b.current = next
+	}
+	return b.current
+}
+
+// Current returns the delay until the next poll
+func (b *pollBackoff) Current() time.Duration {
+	return b.current
+}
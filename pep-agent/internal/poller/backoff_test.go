@@ -0,0 +1,63 @@
+package poller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPollBackoff_EmptyCyclesIncreaseDelay(t *testing.T) {
+	b := newPollBackoff(3*time.Second, 30*time.Second, 2)
+
+	expected := []time.Duration{
+		6 * time.Second,
+		12 * time.Second,
+		24 * time.Second,
+		30 * time.Second, // capped at max
+		30 * time.Second,
+	}
+	for i, want := range expected {
+		if got := b.Next(false); got != want {
+			t.Errorf("empty cycle %d: delay = %v; want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestPollBackoff_RecordsResetDelay(t *testing.T) {
+	b := newPollBackoff(3*time.Second, 30*time.Second, 1.5)
+
+	b.Next(false)
+	b.Next(false)
+	if b.Current() <= 3*time.Second {
+		t.Fatalf("delay after empty cycles = %v; want more than the base", b.Current())
+	}
+
+	if got := b.Next(true); got != 3*time.Second {
+		t.Errorf("delay after records found = %v; want %v", got, 3*time.Second)
+	}
+	if got := b.Next(false); got != 4500*time.Millisecond {
+		t.Errorf("delay after reset and empty cycle = %v; want %v", got, 4500*time.Millisecond)
+	}
+}
+
+func TestPollBackoff_Disabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		max    time.Duration
+		factor float64
+	}{
+		{"factor 1", 30 * time.Second, 1},
+		{"max equal to base", 3 * time.Second, 2},
+		{"max below base", time.Second, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newPollBackoff(3*time.Second, tt.max, tt.factor)
+			for i := 0; i < 3; i++ {
+				if got := b.Next(false); got != 3*time.Second {
+					t.Errorf("delay = %v; want fixed %v", got, 3*time.Second)
+				}
+			}
+		})
+	}
+}
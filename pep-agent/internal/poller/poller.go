@@ -12,6 +12,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/sidot/backend/pkg/pepmapping"
 	"github.com/sidot/pep-agent/internal/config"
 	"github.com/sidot/pep-agent/internal/database"
 	"github.com/sidot/pep-agent/internal/models"
@@ -86,7 +87,7 @@ func (p *Poller) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	p.logger.Printf("Starting poller with interval: %v", p.config.GetPollInterval())
+	p.logger.Printf("Starting poller with interval: %v (up to %v when idle)", p.config.GetPollInterval(), p.config.GetMaxPollInterval())
 	p.logger.Printf("Database: %s@%s:%d/%s",
 		p.config.Database.User,
 		p.config.Database.Host,
@@ -152,15 +153,16 @@ func (p *Poller) GetStatus() *PollerStatus {
 func (p *Poller) pollLoop(ctx context.Context) {
 	defer close(p.doneCh)
 
-	ticker := time.NewTicker(p.config.GetPollInterval())
-	defer ticker.Stop()
+	backoff := newPollBackoff(p.config.GetPollInterval(), p.config.GetMaxPollInterval(), p.config.GetPollBackoffFactor())
+	timer := time.NewTimer(backoff.Current())
+	defer timer.Stop()
 
 	// State save ticker (every minute)
 	saveTicker := time.NewTicker(1 * time.Minute)
 	defer saveTicker.Stop()
 
 	// Initial poll
-	p.poll(ctx)
+	timer.Reset(backoff.Next(p.poll(ctx)))
 
 	for {
 		select {
@@ -168,8 +170,8 @@ func (p *Poller) pollLoop(ctx context.Context) {
 			return
 		case <-p.stopCh:
 			return
-		case <-ticker.C:
-			p.poll(ctx)
+		case <-timer.C:
+			timer.Reset(backoff.Next(p.poll(ctx)))
 		case <-saveTicker.C:
 			if p.stateChanged {
 				if err := p.saveState(); err != nil {
@@ -181,8 +183,9 @@ func (p *Poller) pollLoop(ctx context.Context) {
 	}
 }
 
-// poll performs a single poll cycle
-func (p *Poller) poll(ctx context.Context) {
+// poll performs a single poll cycle and reports whether new records were found
+// (a failed cycle counts as empty)
+func (p *Poller) poll(ctx context.Context) bool {
 	p.lastPollTime = time.Now()
 
 	// Check database connection
@@ -191,7 +194,7 @@ func (p *Poller) poll(ctx context.Context) {
 			p.logger.Printf("Database connection failed: %v", err)
 			atomic.AddInt64(&p.totalErrors, 1)
 			p.checkOfflineAlert()
-			return
+			return false
 		}
 	}
 
@@ -206,11 +209,11 @@ func (p *Poller) poll(ctx context.Context) {
 	if err != nil {
 		p.logger.Printf("Error fetching records: %v", err)
 		atomic.AddInt64(&p.totalErrors, 1)
-		return
+		return false
 	}
 
 	if len(records) == 0 {
-		return
+		return false
 	}
 
 	p.logger.Printf("Detected %d new record(s)", len(records))
@@ -219,17 +222,21 @@ func (p *Poller) poll(ctx context.Context) {
 	for _, record := range records {
 		select {
 		case <-ctx.Done():
-			return
+			return true
 		default:
 			p.processRecord(ctx, record)
 		}
 	}
+	return true
 }
 
 // processRecord processes a single PEP record
 func (p *Poller) processRecord(ctx context.Context, record *models.PEPRecord) {
 	// Convert to event (with LGPD masking)
 	event := record.ToObitoEvent(p.config.Agent.HospitalID)
+	if p.config.Mapping.Masking.CPF == pepmapping.MaskFull && record.CPF != nil {
+		event.CPFMasked = models.MaskCPFFull(*record.CPF)
+	}
 
 	// Log without sensitive data
 	p.logger.Printf("Processing: ID=%s, Patient=%s, Time=%s",
@@ -4,8 +4,8 @@ package pusher
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -29,6 +29,20 @@ type PushResult struct {
 	EventID    string
 }
 
+// PingResult is the hospital the central server resolved from the API key
+type PingResult struct {
+	HospitalID            string `json:"hospital_id"`
+	HospitalNome          string `json:"hospital_nome"`
+	TenantSlug            string `json:"tenant_slug"`
+	ServerTime            string `json:"server_time"`
+	EventSchemaVersion    int    `json:"event_schema_version"`
+	MinEventSchemaVersion int    `json:"min_event_schema_version"`
+}
+
+// ErrNotAuthorized is returned by Ping when the server rejects the API key
+// or does not recognize its hospital
+var ErrNotAuthorized = errors.New("API key rejected by the central server")
+
 // RetryConfig defines the backoff strategy for retries
 type RetryConfig struct {
 	MaxRetries int
@@ -59,9 +73,10 @@ func NewPusher(cfg *config.AgentConfig) *Pusher {
 		MaxConnsPerHost:     5,
 	}
 
-	// Allow insecure TLS for development (controlled by config)
-	if cfg.Central.Insecure {
-		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	// TLS settings: insecure mode for development, client certificate for mTLS
+	// (already validated by config.Load)
+	if tlsConfig, err := cfg.Central.TLSConfig(); err == nil {
+		transport.TLSClientConfig = tlsConfig
 	}
 
 	client := &http.Client{
@@ -184,6 +199,38 @@ func (p *Pusher) PushBatch(ctx context.Context, events []*models.ObitoEvent, ret
 	return results, lastErr
 }
 
+// Ping verifies connectivity and the API key, returning the hospital resolved by the server
+func (p *Pusher) Ping(ctx context.Context) (*PingResult, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.config.GetPingURL(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ping request: %w", err)
+	}
+
+	req.Header.Set("X-API-Key", p.config.Central.APIKey)
+	req.Header.Set("User-Agent", "SIDOT-PEP-Agent/1.0")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ping failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w (%d: %s)", ErrNotAuthorized, resp.StatusCode, string(body))
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("ping returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result PingResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode ping response: %w", err)
+	}
+	return &result, nil
+}
+
 // HealthCheck verifies connectivity to the central server
 func (p *Pusher) HealthCheck(ctx context.Context) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", p.config.Central.URL, nil)